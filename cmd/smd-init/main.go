@@ -30,16 +30,20 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/internal/pgmigrate"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 )
 
 const APP_VERSION = "1"
-const SCHEMA_VERSION = 20
-const SCHEMA_STEPS = 22
+const SCHEMA_VERSION = 37
+const SCHEMA_STEPS = 39
+
+// First step with room for encrypted RedfishEndpoint passwords
+const REENCRYPT_MIN_STEP = 31
 
 var dbName string
 var dbUser string
@@ -52,6 +56,7 @@ var forceStep int
 var migrationsDir string
 var migrateStep uint
 var fresh *bool
+var reencrypt *bool
 var versionFlag *bool
 
 // Parse command line options.
@@ -62,9 +67,11 @@ func parseCmdLine() {
 	flag.StringVar(&dbPortStr, "dbport", "", "Database port")
 	flag.StringVar(&dbOpts, "dbopts", "", "Database options string")
 	flag.IntVar(&forceStep, "f", -1, "Force migration to step X")
-	flag.StringVar(&migrationsDir, "migrationsdir", "/persistent_migrations", "Directory with migrations v4 files")
+	flag.StringVar(&migrationsDir, "migrationsdir", "/persistent_migrations", "Directory with migrations v4 files, empty for the built-in ones")
 	fresh = flag.Bool("fresh", false,
 		"Revert all schemas before installing (drops all data)")
+	reencrypt = flag.Bool("reencrypt", false,
+		"Re-encrypt RedfishEndpoint passwords with the current key after migrating")
 	versionFlag = flag.Bool("v", false, "Print the version number.")
 
 	flag.Parse()
@@ -154,6 +161,16 @@ func parseCmdLine() {
 			*fresh = true
 		}
 	}
+	envvar = "SMD_REENCRYPT"
+	if !*reencrypt {
+		if val := os.Getenv(envvar); val != "" {
+			*reencrypt = true
+		}
+	}
+	if *reencrypt && migrateStep < REENCRYPT_MIN_STEP {
+		lg.Printf("Can't re-encrypt passwords below step %d", REENCRYPT_MIN_STEP)
+		os.Exit(1)
+	}
 
 	// Env var only
 	envvar = "SMD_DBPASS"
@@ -217,9 +234,12 @@ func main() {
 	}
 	lg.Printf("Creating postgres driver succeeded")
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrationsDir,
-		"postgres", driver)
+	src, err := pgmigrate.Source(migrationsDir)
+	if err != nil {
+		lg.Printf("Reading migrations failed: '%s'", err)
+		os.Exit(1)
+	}
+	m, err := migrate.NewWithInstance("smd", src, "postgres", driver)
 	if err != nil {
 		lg.Printf("Creating migration failed: '%s'", err)
 		os.Exit(1)
@@ -300,6 +320,9 @@ func main() {
 		}
 	} else {
 		lg.Printf("Migration: Already at expected step.  Nothing to do.")
+		if *reencrypt {
+			reencryptPasswords(dbDSN)
+		}
 		os.Exit(0)
 	}
 	version2, dirty2, err := m.Version()
@@ -311,4 +334,36 @@ func main() {
 	} else {
 		lg.Printf("Migration: At step version %d, dirty: %t", version2, dirty2)
 	}
+	if *reencrypt {
+		reencryptPasswords(dbDSN)
+	}
+}
+
+// Re-encrypt RedfishEndpoint passwords that are plaintext, or encrypted with
+// an older key, with the key configured by SMD_DB_ENCRYPT_KEY_FILE or
+// SMD_DB_ENCRYPT_VAULT_KEY.
+func reencryptPasswords(dsn string) {
+	env, err := envcrypt.FromEnv()
+	if err != nil {
+		lg.Printf("Re-encrypt: Can't set up database encryption: '%s'", err)
+		os.Exit(1)
+	}
+	if env == nil {
+		lg.Printf("Re-encrypt: No key, set %s or %s",
+			envcrypt.EnvKeyFile, envcrypt.EnvVaultKey)
+		os.Exit(1)
+	}
+	hmsdb := hmsds.NewHMSDB_PG(dsn, lg)
+	if err := hmsdb.Open(); err != nil {
+		lg.Printf("Re-encrypt: Open failed: '%s'", err)
+		os.Exit(1)
+	}
+	hmsdb.SetFieldEncryption(env)
+	num, err := hmsdb.ReencryptRFEndpointPasswords()
+	hmsdb.Close()
+	if err != nil {
+		lg.Printf("Re-encrypt: Failed: '%s'", err)
+		os.Exit(1)
+	}
+	lg.Printf("Re-encrypt: Re-encrypted %d RedfishEndpoint passwords", num)
 }
@@ -98,6 +98,17 @@ func (s *SmD) VerifyScope(testScopes []string, r *http.Request) (bool, error) {
 	return true, nil
 }
 
+// Get who is making a request, for the histories that record this.  This is
+// the subject of the JWT if there is one, otherwise the remote address.
+func (s *SmD) requestActor(r *http.Request) string {
+	if _, claims, err := jwtauth.FromContext(r.Context()); err == nil {
+		if sub, ok := claims["sub"].(string); ok && sub != "" {
+			return sub
+		}
+	}
+	return r.RemoteAddr
+}
+
 type statusCheckTransport struct {
 	http.RoundTripper
 }
@@ -0,0 +1,352 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// BMC liveness probing
+//
+// With SMD_BMC_PROBE_INTERVAL_SECS set, the leader checks every enabled
+// Redfish RedfishEndpoint that often, so dead BMCs show up without running
+// a discovery.  SMD_BMC_PROBE_MODE picks the check:
+//
+//     https   HEAD of the Redfish ServiceRoot (default).  Any HTTP response
+//             counts, even an error status, as the BMC is answering.
+//     tcp     Connecting to the BMC's HTTPS port.
+//
+// Each check has SMD_BMC_PROBE_TIMEOUT_SECS (default 5) to finish.  After
+// SMD_BMC_PROBE_FAILURES checks in a row fail (default 3), the endpoint is
+// Unreachable and its BMC component is flagged Alert, sending an SCN.  The
+// flag goes back to OK, with another SCN, once the BMC answers again,
+// unless something else has changed it in the meantime.
+//
+// When each endpoint was last reached and whether it is Unreachable are
+// kept in the DB and returned as Liveness with RedfishEndpoints, and by
+//
+//     GET /Inventory/RedfishEndpoints/Liveness[?id=<xname>...][&unreachable=true]
+///////////////////////////////////////////////////////////////////////////////
+
+// Values for SMD_BMC_PROBE_MODE
+const (
+	BMCProbeHTTPS = "https"
+	BMCProbeTCP   = "tcp"
+)
+
+// Defaults for SMD_BMC_PROBE_TIMEOUT_SECS and SMD_BMC_PROBE_FAILURES
+const (
+	DefaultBMCProbeTimeout  = 5 * time.Second
+	DefaultBMCProbeFailures = 3
+)
+
+// How many endpoints are probed at once.
+const bmcProbeFanout = 64
+
+type BMCProber struct {
+	Interval time.Duration // 0 is off
+	Timeout  time.Duration
+	Failures int // Failed probes in a row before Unreachable
+	Mode     string
+
+	// Probes one endpoint, if not the one for Mode, e.g. for tests.
+	probe func(ep *sm.RedfishEndpoint) error
+}
+
+// Check once whether ep is answering.
+func (p *BMCProber) probeOne(ep *sm.RedfishEndpoint) error {
+	if p.probe != nil {
+		return p.probe(ep)
+	}
+	if ep.FQDN == "" {
+		return errors.New("no FQDN")
+	}
+	addr := bmcProbeAddr(ep.FQDN)
+	if p.Mode == BMCProbeTCP {
+		conn, err := net.DialTimeout("tcp", addr, p.Timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	// The deadline covers the client's retries and TLS failover too.
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	// The '%' before an IPv6 zone ID must be escaped in URLs.
+	url := "https://" + strings.Replace(addr, "%", "%25", 1) + "/redfish/v1/"
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	client := rf.RfDefaultClient()
+	if client == nil {
+		return errors.New("no HTTP client")
+	}
+	rsp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	base.DrainAndCloseResponseBody(rsp)
+	return nil
+}
+
+// host:port to probe for an FQDN, which may already have a port.
+func bmcProbeAddr(fqdn string) string {
+	if _, _, err := net.SplitHostPort(fqdn); err == nil {
+		return fqdn
+	}
+	host := strings.TrimSuffix(strings.TrimPrefix(fqdn, "["), "]")
+	return net.JoinHostPort(host, "443")
+}
+
+// The liveness of RedfishEndpoint id after a probe at now that failed with
+// err, or succeeded if err is nil, given prev, its liveness before (nil if
+// it was never probed).  It is Unreachable once failures probes in a row
+// have failed.
+func nextRFEndpointLiveness(
+	prev *sm.RedfishEndpointLiveness,
+	id string,
+	err error,
+	now time.Time,
+	failures int,
+) *sm.RedfishEndpointLiveness {
+	l := &sm.RedfishEndpointLiveness{
+		RedfishEndpointID: id,
+		LastProbe:         now.UTC().Format(time.RFC3339),
+	}
+	if prev != nil {
+		l.LastContact = prev.LastContact
+		l.Failures = prev.Failures
+		l.Unreachable = prev.Unreachable
+	}
+	if err == nil {
+		l.LastContact = l.LastProbe
+		l.Failures = 0
+		l.Unreachable = false
+		return l
+	}
+	l.Failures++
+	l.LastError = err.Error()
+	if l.Failures >= failures {
+		l.Unreachable = true
+	}
+	return l
+}
+
+// Spin off a thread to probe the BMCs every s.bmcProbe.Interval while the
+// leader.
+func (s *SmD) StartBMCProber() {
+	if s.bmcProbe.Interval <= 0 {
+		return
+	}
+	go func() {
+		for {
+			time.Sleep(s.bmcProbe.Interval)
+			if s.cluster.isLeader() {
+				s.probeBMCs()
+			}
+		}
+	}()
+}
+
+// Probe every enabled Redfish RedfishEndpoint once, record the results and
+// flag the BMCs that became unreachable, or reachable again.
+func (s *SmD) probeBMCs() {
+	eps, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		s.LogAlways("probeBMCs(): Lookup failure: %s", err)
+		return
+	}
+	targets := make([]*sm.RedfishEndpoint, 0, len(eps))
+	for _, ep := range eps {
+		// Other protocols have no Redfish service to probe.
+		if ep.Enabled && (ep.Protocol == "" ||
+			strings.EqualFold(ep.Protocol, rf.ProtocolRedfish)) {
+			targets = append(targets, ep)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	lvs, err := s.db.GetRFEndpointLiveness(nil)
+	if err != nil {
+		s.LogAlways("probeBMCs(): Liveness lookup failure: %s", err)
+		return
+	}
+	prev := make(map[string]*sm.RedfishEndpointLiveness, len(lvs))
+	for _, l := range lvs {
+		prev[l.RedfishEndpointID] = l
+	}
+
+	errs := make([]error, len(targets))
+	sem := make(chan struct{}, bmcProbeFanout)
+	var wg sync.WaitGroup
+	for i, ep := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ep *sm.RedfishEndpoint) {
+			defer func() { <-sem; wg.Done() }()
+			errs[i] = s.bmcProbe.probeOne(ep)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	for i, ep := range targets {
+		was := prev[ep.ID] != nil && prev[ep.ID].Unreachable
+		l := nextRFEndpointLiveness(prev[ep.ID], ep.ID, errs[i], now,
+			s.bmcProbe.Failures)
+		if err := s.db.UpsertRFEndpointLiveness(l); err != nil {
+			// e.g. the endpoint was deleted while being probed
+			s.LogAlways("probeBMCs(): Can't record liveness of %s: %s",
+				ep.ID, err)
+			continue
+		}
+		if l.Unreachable && !was {
+			s.LogAlways("Warning: BMC %s is unreachable after %d probes: %s",
+				ep.ID, l.Failures, l.LastError)
+			s.setBMCUnreachableFlag(ep.ID, true)
+		} else if !l.Unreachable && was {
+			s.LogAlways("BMC %s is reachable again", ep.ID)
+			s.setBMCUnreachableFlag(ep.ID, false)
+		}
+	}
+}
+
+// Set the Flag of a BMC component to Alert when it becomes unreachable, or
+// back to OK once it is reachable, if it is still Alert.
+func (s *SmD) setBMCUnreachableFlag(id string, unreachable bool) {
+	comp, err := s.db.GetComponentByID(id)
+	if err != nil {
+		s.LogAlways("setBMCUnreachableFlag(%s): %s", id, err)
+		return
+	} else if comp == nil {
+		return
+	}
+	newFlag := base.FlagOK.String()
+	if unreachable {
+		newFlag = base.FlagAlert.String()
+	}
+	if comp.Flag == newFlag ||
+		(!unreachable && comp.Flag != base.FlagAlert.String()) {
+		return
+	}
+	scnIDs, err := s.dbUpdateCompFlagOnly(
+		s.db.WithActor(CompHistActorBMCProbe), []string{id}, newFlag,
+		new(hmsds.PartInfo))
+	if err != nil {
+		s.LogAlways("setBMCUnreachableFlag(%s): %s", id, err)
+		return
+	}
+	if len(scnIDs) != 0 {
+		scn := NewJobSCN(scnIDs, base.Component{
+			State: comp.State,
+			Flag:  newFlag,
+		}, s)
+		s.wp.Queue(scn)
+	}
+}
+
+// Fill in the Liveness of the given RedfishEndpoints, if the prober is on.
+// Failing to is logged but not an error, as it is only extra information.
+func (s *SmD) addRFEndpointLiveness(eps []*sm.RedfishEndpoint) {
+	if s.bmcProbe.Interval <= 0 || len(eps) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(eps))
+	for _, ep := range eps {
+		ids = append(ids, ep.ID)
+	}
+	lvs, err := s.db.GetRFEndpointLiveness(ids)
+	if err != nil {
+		s.LogAlways("addRFEndpointLiveness(): Lookup failure: %s", err)
+		return
+	}
+	byID := make(map[string]*sm.RedfishEndpointLiveness, len(lvs))
+	for _, l := range lvs {
+		byID[l.RedfishEndpointID] = l
+	}
+	for _, ep := range eps {
+		ep.Liveness = byID[ep.ID]
+	}
+}
+
+// Get the liveness of RedfishEndpoints, optionally only ?id=... and/or
+// only those that are ?unreachable=true (or false).
+func (s *SmD) doRFEndpointLivenessGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doRFEndpointLivenessGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	ids := []string{}
+	for _, id := range r.Form["id"] {
+		normID := xnametypes.VerifyNormalizeCompID(id)
+		if normID == "" {
+			sendJsonError(w, http.StatusBadRequest,
+				"invalid xname ID '"+id+"'")
+			return
+		}
+		ids = append(ids, normID)
+	}
+	unreachable := ""
+	if val := r.Form.Get("unreachable"); val != "" {
+		unreachable = strings.ToLower(val)
+		if unreachable != "true" && unreachable != "false" {
+			sendJsonError(w, http.StatusBadRequest,
+				"bad query param: unreachable must be true or false")
+			return
+		}
+	}
+	lvs, err := s.db.GetRFEndpointLiveness(ids)
+	if err != nil {
+		s.reqLog(r).LogAlways("doRFEndpointLivenessGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	out := sm.RedfishEndpointLivenessArray{
+		Liveness: make([]*sm.RedfishEndpointLiveness, 0, len(lvs)),
+	}
+	for _, l := range lvs {
+		if unreachable == "" || (unreachable == "true") == l.Unreachable {
+			out.Liveness = append(out.Liveness, l)
+		}
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
@@ -0,0 +1,328 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func resetBMCProbeResults() {
+	results.GetRFEndpointsAll.Return.entries = nil
+	results.GetRFEndpointsAll.Return.err = nil
+	results.GetRFEndpointLiveness.Input.ids = nil
+	results.GetRFEndpointLiveness.Return.lvs = nil
+	results.GetRFEndpointLiveness.Return.err = nil
+	results.UpsertRFEndpointLiveness.Input.lvs = nil
+	results.UpsertRFEndpointLiveness.Return.err = nil
+	results.GetComponentByID.Input.id = ""
+	results.GetComponentByID.Return.id = nil
+	results.GetComponentByID.Return.err = nil
+	results.UpdateCompFlagOnly.Input.id = ""
+	results.UpdateCompFlagOnly.Input.flag = ""
+	results.UpdateCompFlagOnly.Return.rowsAffected = 0
+	results.UpdateCompFlagOnly.Return.err = nil
+	results.WithActor.Input.actor = ""
+}
+
+func TestBMCProbeAddr(t *testing.T) {
+	tests := []struct {
+		fqdn     string
+		expected string
+	}{
+		{"x0c0s0b0", "x0c0s0b0:443"},
+		{"x0c0s0b0.local:8443", "x0c0s0b0.local:8443"},
+		{"10.1.1.1", "10.1.1.1:443"},
+		{"fd00::1", "[fd00::1]:443"},
+		{"[fd00::1]", "[fd00::1]:443"},
+		{"[fd00::1]:8443", "[fd00::1]:8443"},
+	}
+	for i, test := range tests {
+		if out := bmcProbeAddr(test.fqdn); out != test.expected {
+			t.Errorf("Test %d: expected %s, got %s", i, test.expected, out)
+		}
+	}
+}
+
+func TestNextRFEndpointLiveness(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	nowStr := "2026-10-18T12:00:00Z"
+	before := "2026-10-18T11:59:00Z"
+	failed := errors.New("connection refused")
+	tests := []struct {
+		prev     *sm.RedfishEndpointLiveness
+		err      error
+		expected sm.RedfishEndpointLiveness
+	}{{
+		// Never probed before
+		prev: nil,
+		err:  nil,
+		expected: sm.RedfishEndpointLiveness{
+			RedfishEndpointID: "x0c0s0b0",
+			LastContact:       nowStr,
+			LastProbe:         nowStr,
+		},
+	}, {
+		// First failure
+		prev: &sm.RedfishEndpointLiveness{
+			LastContact: before,
+			LastProbe:   before,
+		},
+		err: failed,
+		expected: sm.RedfishEndpointLiveness{
+			RedfishEndpointID: "x0c0s0b0",
+			LastContact:       before,
+			LastProbe:         nowStr,
+			Failures:          1,
+			LastError:         "connection refused",
+		},
+	}, {
+		// Enough failures in a row
+		prev: &sm.RedfishEndpointLiveness{
+			LastContact: before,
+			LastProbe:   before,
+			Failures:    2,
+			LastError:   "timeout",
+		},
+		err: failed,
+		expected: sm.RedfishEndpointLiveness{
+			RedfishEndpointID: "x0c0s0b0",
+			LastContact:       before,
+			LastProbe:         nowStr,
+			Failures:          3,
+			Unreachable:       true,
+			LastError:         "connection refused",
+		},
+	}, {
+		// Back again
+		prev: &sm.RedfishEndpointLiveness{
+			LastContact: before,
+			LastProbe:   before,
+			Failures:    5,
+			Unreachable: true,
+			LastError:   "timeout",
+		},
+		err: nil,
+		expected: sm.RedfishEndpointLiveness{
+			RedfishEndpointID: "x0c0s0b0",
+			LastContact:       nowStr,
+			LastProbe:         nowStr,
+		},
+	}}
+	for i, test := range tests {
+		out := nextRFEndpointLiveness(test.prev, "x0c0s0b0", test.err, now, 3)
+		if !reflect.DeepEqual(test.expected, *out) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, *out)
+		}
+	}
+}
+
+func TestProbeBMCs(t *testing.T) {
+	defer resetBMCProbeResults()
+	saved := s.bmcProbe
+	defer func() { s.bmcProbe = saved }()
+
+	down := map[string]bool{"x0c0s1b0": true, "x0c0s2b0": true}
+	s.bmcProbe = BMCProber{
+		Interval: time.Minute,
+		Failures: 2,
+		probe: func(ep *sm.RedfishEndpoint) error {
+			if down[ep.ID] {
+				return errors.New("no route to host")
+			}
+			return nil
+		},
+	}
+
+	tests := []struct {
+		eps          []*sm.RedfishEndpoint
+		prev         []*sm.RedfishEndpointLiveness
+		comp         *base.Component
+		expectedIDs  []string
+		expectedUnr  []bool
+		expectedFlag string
+	}{{
+		// Disabled and non-Redfish endpoints are skipped; one failure
+		// is not enough.
+		eps: []*sm.RedfishEndpoint{
+			{RedfishEPDescription: rfEPDesc("x0c0s0b0", true, "")},
+			{RedfishEPDescription: rfEPDesc("x0c0s1b0", true, "Redfish")},
+			{RedfishEPDescription: rfEPDesc("x0c0s2b0", false, "")},
+			{RedfishEPDescription: rfEPDesc("x0c0s3b0", true, "IPMI")},
+		},
+		expectedIDs: []string{"x0c0s0b0", "x0c0s1b0"},
+		expectedUnr: []bool{false, false},
+	}, {
+		// Becomes unreachable
+		eps: []*sm.RedfishEndpoint{
+			{RedfishEPDescription: rfEPDesc("x0c0s1b0", true, "")},
+		},
+		prev: []*sm.RedfishEndpointLiveness{
+			{RedfishEndpointID: "x0c0s1b0", Failures: 1},
+		},
+		comp:         &base.Component{ID: "x0c0s1b0", State: "Ready", Flag: "OK"},
+		expectedIDs:  []string{"x0c0s1b0"},
+		expectedUnr:  []bool{true},
+		expectedFlag: "Alert",
+	}, {
+		// Reachable again
+		eps: []*sm.RedfishEndpoint{
+			{RedfishEPDescription: rfEPDesc("x0c0s0b0", true, "")},
+		},
+		prev: []*sm.RedfishEndpointLiveness{
+			{RedfishEndpointID: "x0c0s0b0", Failures: 4, Unreachable: true},
+		},
+		comp:         &base.Component{ID: "x0c0s0b0", State: "Ready", Flag: "Alert"},
+		expectedIDs:  []string{"x0c0s0b0"},
+		expectedUnr:  []bool{false},
+		expectedFlag: "OK",
+	}, {
+		// Reachable again, but someone else changed the flag since
+		eps: []*sm.RedfishEndpoint{
+			{RedfishEPDescription: rfEPDesc("x0c0s0b0", true, "")},
+		},
+		prev: []*sm.RedfishEndpointLiveness{
+			{RedfishEndpointID: "x0c0s0b0", Failures: 4, Unreachable: true},
+		},
+		comp:        &base.Component{ID: "x0c0s0b0", State: "Ready", Flag: "Warning"},
+		expectedIDs: []string{"x0c0s0b0"},
+		expectedUnr: []bool{false},
+	}}
+	for i, test := range tests {
+		resetBMCProbeResults()
+		results.GetRFEndpointsAll.Return.entries = test.eps
+		results.GetRFEndpointLiveness.Return.lvs = test.prev
+		results.GetComponentByID.Return.id = test.comp
+		results.UpdateCompFlagOnly.Return.rowsAffected = 1
+		s.probeBMCs()
+
+		lvs := results.UpsertRFEndpointLiveness.Input.lvs
+		if len(lvs) != len(test.expectedIDs) {
+			t.Errorf("Test %d: expected %d upserts, got %+v", i,
+				len(test.expectedIDs), lvs)
+			continue
+		}
+		for j, l := range lvs {
+			if l.RedfishEndpointID != test.expectedIDs[j] ||
+				l.Unreachable != test.expectedUnr[j] {
+				t.Errorf("Test %d: expected %s unreachable=%t, got %+v", i,
+					test.expectedIDs[j], test.expectedUnr[j], l)
+			}
+		}
+		if results.UpdateCompFlagOnly.Input.flag != test.expectedFlag {
+			t.Errorf("Test %d: expected flag '%s', got '%s'", i,
+				test.expectedFlag, results.UpdateCompFlagOnly.Input.flag)
+		}
+		if test.expectedFlag != "" &&
+			results.WithActor.Input.actor != CompHistActorBMCProbe {
+			t.Errorf("Test %d: expected actor %s, got '%s'", i,
+				CompHistActorBMCProbe, results.WithActor.Input.actor)
+		}
+	}
+}
+
+func rfEPDesc(id string, enabled bool, protocol string) rf.RedfishEPDescription {
+	return rf.RedfishEPDescription{
+		ID:       id,
+		FQDN:     id,
+		Enabled:  enabled,
+		Protocol: protocol,
+	}
+}
+
+func TestDoRFEndpointLivenessGet(t *testing.T) {
+	defer resetBMCProbeResults()
+	lvs := []*sm.RedfishEndpointLiveness{{
+		RedfishEndpointID: "x0c0s0b0",
+		LastContact:       "2026-10-18T12:00:00Z",
+		LastProbe:         "2026-10-18T12:00:00Z",
+	}, {
+		RedfishEndpointID: "x0c0s1b0",
+		LastProbe:         "2026-10-18T12:00:00Z",
+		Failures:          3,
+		Unreachable:       true,
+		LastError:         "i/o timeout",
+	}}
+	tests := []struct {
+		url         string
+		code        int
+		expectedIDs []string
+		expected    []*sm.RedfishEndpointLiveness
+	}{{
+		url:         "https://localhost/hsm/v2/Inventory/RedfishEndpoints/Liveness",
+		code:        http.StatusOK,
+		expectedIDs: []string{},
+		expected:    lvs,
+	}, {
+		url:         "https://localhost/hsm/v2/Inventory/RedfishEndpoints/Liveness?id=X0C0S01B0&id=x0c0s0b0&unreachable=True",
+		code:        http.StatusOK,
+		expectedIDs: []string{"x0c0s1b0", "x0c0s0b0"},
+		expected:    lvs[1:],
+	}, {
+		url:         "https://localhost/hsm/v2/Inventory/RedfishEndpoints/Liveness?unreachable=false",
+		code:        http.StatusOK,
+		expectedIDs: []string{},
+		expected:    lvs[:1],
+	}, {
+		url:  "https://localhost/hsm/v2/Inventory/RedfishEndpoints/Liveness?id=foo",
+		code: http.StatusBadRequest,
+	}, {
+		url:  "https://localhost/hsm/v2/Inventory/RedfishEndpoints/Liveness?unreachable=maybe",
+		code: http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		resetBMCProbeResults()
+		results.GetRFEndpointLiveness.Return.lvs = lvs
+		req, _ := http.NewRequest("GET", test.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+		if !reflect.DeepEqual(test.expectedIDs, results.GetRFEndpointLiveness.Input.ids) {
+			t.Errorf("Test %d: expected ids %v, got %v", i, test.expectedIDs,
+				results.GetRFEndpointLiveness.Input.ids)
+		}
+		var out sm.RedfishEndpointLivenessArray
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, out.Liveness) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected,
+				out.Liveness)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Boot configuration
+//
+// Discovery keeps the Boot settings of each ComputerSystem (boot order,
+// boot source override and whether it is for UEFI or Legacy boot) in the
+// RedfishSystemInfo of its ComponentEndpoint.
+//
+//     GET /Inventory/BootConfig/{xname}
+//     GET /Inventory/BootConfig[?id=<xname>...]
+//
+// return them so that boot and image services can check how nodes will
+// boot without talking to their BMCs.  They are as of the last discovery.
+///////////////////////////////////////////////////////////////////////////////
+
+// Boot settings of a node, as last discovered.
+type BootConfig struct {
+	ID                           string   `json:"ID"`
+	RedfishEndpointID            string   `json:"RedfishEndpointID"`
+	RedfishURL                   string   `json:"RedfishURL"`
+	BootSourceOverrideEnabled    string   `json:"BootSourceOverrideEnabled,omitempty"`
+	BootSourceOverrideTarget     string   `json:"BootSourceOverrideTarget,omitempty"`
+	BootSourceOverrideMode       string   `json:"BootSourceOverrideMode,omitempty"`
+	UefiTargetBootSourceOverride string   `json:"UefiTargetBootSourceOverride,omitempty"`
+	BootNext                     string   `json:"BootNext,omitempty"`
+	BootOrder                    []string `json:"BootOrder"`
+	AllowableTargets             []string `json:"BootSourceOverrideTargetAllowableValues,omitempty"`
+	AllowableModes               []string `json:"BootSourceOverrideModeAllowableValues,omitempty"`
+}
+
+// Output of GET /Inventory/BootConfig
+type BootConfigArray struct {
+	BootConfig []*BootConfig `json:"BootConfig"`
+}
+
+// Flatten the Boot settings of a ComputerSystem ComponentEndpoint, nil if
+// none were discovered.
+func newBootConfig(cep *sm.ComponentEndpoint) *BootConfig {
+	if cep.RedfishSystemInfo == nil || cep.RedfishSystemInfo.Boot == nil {
+		return nil
+	}
+	boot := cep.RedfishSystemInfo.Boot
+	bc := &BootConfig{
+		ID:                           cep.ID,
+		RedfishEndpointID:            cep.RfEndpointID,
+		RedfishURL:                   cep.OdataID,
+		BootSourceOverrideEnabled:    boot.BootSourceOverrideEnabled,
+		BootSourceOverrideTarget:     boot.BootSourceOverrideTarget,
+		BootSourceOverrideMode:       boot.BootSourceOverrideMode,
+		UefiTargetBootSourceOverride: boot.UefiTargetBootSourceOverride,
+		BootNext:                     boot.BootNext,
+		BootOrder:                    boot.BootOrder,
+		AllowableTargets:             boot.AllowableValues,
+		AllowableModes:               boot.ModeAllowableValues,
+	}
+	if bc.BootOrder == nil {
+		bc.BootOrder = []string{}
+	}
+	return bc
+}
+
+// Get the boot configuration of a single node
+func (s *SmD) doBootConfigGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	cep, err := s.db.GetCompEndpointByID(xname)
+	if err != nil {
+		s.lg.Printf("doBootConfigGet(): Lookup failure: %s %s", xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if cep == nil {
+		sendJsonError(w, http.StatusNotFound, "no such component endpoint")
+		return
+	}
+	if cep.RedfishType != rf.ComputerSystemType {
+		sendJsonError(w, http.StatusBadRequest,
+			"component endpoint is not a ComputerSystem")
+		return
+	}
+	bc := newBootConfig(cep)
+	if bc == nil {
+		sendJsonError(w, http.StatusNotFound,
+			"no boot settings were discovered for this component")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, bc)
+}
+
+// Get the boot configuration of all or some nodes.  Those without any
+// discovered Boot settings are left out.
+func (s *SmD) doBootConfigGetAll(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	ids := []string{}
+	for _, id := range r.URL.Query()["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		ID:          ids,
+		RedfishType: []string{rf.ComputerSystemType},
+	})
+	if err != nil {
+		s.lg.Printf("doBootConfigGetAll(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	bcs := BootConfigArray{BootConfig: make([]*BootConfig, 0, len(ceps))}
+	for _, cep := range ceps {
+		if bc := newBootConfig(cep); bc != nil {
+			bcs.BootConfig = append(bcs.BootConfig, bc)
+		}
+	}
+	sort.Slice(bcs.BootConfig, func(i, j int) bool {
+		return bcs.BootConfig[i].ID < bcs.BootConfig[j].ID
+	})
+	sendJsonObject(w, http.StatusOK, bcs)
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestBootConfigGet(t *testing.T) {
+	defer func() {
+		results.GetCompEndpointByID.Return.entry = nil
+		results.GetCompEndpointByID.Return.err = nil
+		results.GetCompEndpointsFilter.Return.entries = nil
+		results.GetCompEndpointsFilter.Return.err = nil
+	}()
+	node := &sm.ComponentEndpoint{
+		ComponentDescription: rf.ComponentDescription{
+			ID:           "x1000c0s0b0n0",
+			RedfishType:  rf.ComputerSystemType,
+			OdataID:      "/redfish/v1/Systems/Node0",
+			RfEndpointID: "x1000c0s0b0",
+		},
+		RedfishSystemInfo: &rf.ComponentSystemInfo{
+			Boot: &rf.ComputerSystemBoot{
+				BootSourceOverrideEnabled: "Once",
+				BootSourceOverrideTarget:  "Pxe",
+				AllowableValues:           []string{"None", "Pxe", "Hdd"},
+				BootSourceOverrideMode:    "UEFI",
+				ModeAllowableValues:       []string{"Legacy", "UEFI"},
+				BootOrder:                 []string{"Boot0002", "Boot0001"},
+			},
+		},
+	}
+	// Discovered before boot settings were kept
+	oldNode := &sm.ComponentEndpoint{
+		ComponentDescription: rf.ComponentDescription{
+			ID:           "x1000c0s0b0n1",
+			RedfishType:  rf.ComputerSystemType,
+			OdataID:      "/redfish/v1/Systems/Node1",
+			RfEndpointID: "x1000c0s0b0",
+		},
+		RedfishSystemInfo: &rf.ComponentSystemInfo{},
+	}
+	bmc := &sm.ComponentEndpoint{
+		ComponentDescription: rf.ComponentDescription{
+			ID:          "x1000c0s0b0",
+			RedfishType: rf.ManagerType,
+		},
+	}
+	expBc := &BootConfig{
+		ID:                        "x1000c0s0b0n0",
+		RedfishEndpointID:         "x1000c0s0b0",
+		RedfishURL:                "/redfish/v1/Systems/Node0",
+		BootSourceOverrideEnabled: "Once",
+		BootSourceOverrideTarget:  "Pxe",
+		BootSourceOverrideMode:    "UEFI",
+		BootOrder:                 []string{"Boot0002", "Boot0001"},
+		AllowableTargets:          []string{"None", "Pxe", "Hdd"},
+		AllowableModes:            []string{"Legacy", "UEFI"},
+	}
+
+	tests := []struct {
+		cep     *sm.ComponentEndpoint
+		expCode int
+	}{
+		{node, http.StatusOK},
+		{oldNode, http.StatusNotFound},
+		{bmc, http.StatusBadRequest},
+		{nil, http.StatusNotFound},
+	}
+	for i, test := range tests {
+		results.GetCompEndpointByID.Return.entry = test.cep
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Inventory/BootConfig/X1000c0s0b0n0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if results.GetCompEndpointByID.Input.id != "x1000c0s0b0n0" {
+			t.Errorf("Test %d: Looked up wrong ComponentEndpoint: %s", i,
+				results.GetCompEndpointByID.Input.id)
+		}
+		if test.expCode != http.StatusOK {
+			continue
+		}
+		var bc BootConfig
+		json.Unmarshal(w.Body.Bytes(), &bc)
+		if !reflect.DeepEqual(&bc, expBc) {
+			t.Errorf("Test %d: Expected %+v, got %s", i, expBc, w.Body.String())
+		}
+	}
+
+	// Collection, sorted, leaving out the node without boot settings
+	results.GetCompEndpointsFilter.Return.entries = []*sm.ComponentEndpoint{
+		oldNode, node}
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/BootConfig?id=x1000c0s0b0n0&id=x1000c0s0b0n1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var bcs BootConfigArray
+	json.Unmarshal(w.Body.Bytes(), &bcs)
+	if w.Code != http.StatusOK || len(bcs.BootConfig) != 1 ||
+		!reflect.DeepEqual(bcs.BootConfig[0], expBc) {
+		t.Errorf("Collection: Unexpected response %d %s", w.Code,
+			w.Body.String())
+	}
+	f := results.GetCompEndpointsFilter.Input.f
+	if !reflect.DeepEqual(f.RedfishType, []string{rf.ComputerSystemType}) ||
+		!reflect.DeepEqual(f.ID, []string{"x1000c0s0b0n0", "x1000c0s0b0n1"}) {
+		t.Errorf("Collection: Unexpected filter %+v", f)
+	}
+}
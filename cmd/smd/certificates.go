@@ -0,0 +1,600 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// BMC certificate rotation
+//
+// Discovery keeps the action targets of each RedfishEndpoint's
+// CertificateService, and where its certificates are, in the ServiceInfo of
+// its CertificateService ServiceEndpoint.  These are used to
+//
+//     POST /Inventory/Certificates/Actions/GenerateCSR
+//     POST /Inventory/Certificates/Actions/ReplaceCertificate
+//
+// on a list of RedfishEndpoints and/or the members of groups, so rotating
+// the HTTPS certificates of many BMCs can be driven through HSM.  Either
+// upload a certificate and key directly, or have each BMC generate a CSR,
+// have it signed, and upload the signed certificate.  Each request returns
+// the result for each endpoint, and the latest result for each endpoint is
+// kept in memory for GET /Inventory/Certificates/Status.
+//
+// With SMD_RF_DISCOVER_CERTIFICATES=true discovery also keeps the subject,
+// issuer and validity of each HTTPS certificate, and
+//
+//     GET /Inventory/Certificates/Expiry?days=<n>
+//
+// lists those that expire within n days, so they can be rotated before
+// discovery and other connections to the BMCs start failing.
+///////////////////////////////////////////////////////////////////////////////
+
+// Certificate actions
+const (
+	CertActionGenerateCSR        = "GenerateCSR"
+	CertActionReplaceCertificate = "ReplaceCertificate"
+)
+
+// Result of a certificate action on an endpoint
+const (
+	CertActionSucceeded = "Succeeded"
+	CertActionFailed    = "Failed"
+)
+
+// How many endpoints are worked on at once.
+const certActionFanout = 64
+
+var errCertNoEndpoint = errors.New("no such RedfishEndpoint")
+var errCertNoService = errors.New(
+	"no CertificateService discovered for this RedfishEndpoint")
+var errCertNoHTTPSCert = errors.New(
+	"no HTTPS certificate location discovered for this RedfishEndpoint")
+
+// Which RedfishEndpoints an action, e.g. on certificates, is for.  Groups
+// are expanded to their members that are RedfishEndpoints.
+type RFEndpointTargets struct {
+	Targets []string `json:"Targets"`
+	Groups  []string `json:"Groups"`
+}
+
+// A certificate, with its private key for BMCs that need it uploaded with
+// the certificate rather than generating their own with GenerateCSR.
+type CertificateIn struct {
+	Certificate string `json:"Certificate"`
+	PrivateKey  string `json:"PrivateKey,omitempty"`
+}
+
+// Input of POST /Inventory/Certificates/Actions/ReplaceCertificate.
+// Certificate (if given) is installed on all of the targets, e.g. for a
+// wildcard certificate.  Endpoints listed in Certificates get their own, and
+// are added to the targets.
+type CertReplaceIn struct {
+	RFEndpointTargets
+	CertificateIn
+	CertificateType string                   `json:"CertificateType"`
+	Certificates    map[string]CertificateIn `json:"Certificates"`
+}
+
+// Input of POST /Inventory/Certificates/Actions/GenerateCSR.  CommonName
+// defaults to the FQDN of each endpoint, and AlternativeNames to its FQDN
+// and hostname.
+type CertCSRIn struct {
+	RFEndpointTargets
+	CommonName         string   `json:"CommonName"`
+	AlternativeNames   []string `json:"AlternativeNames"`
+	Organization       string   `json:"Organization"`
+	OrganizationalUnit string   `json:"OrganizationalUnit"`
+	City               string   `json:"City"`
+	State              string   `json:"State"`
+	Country            string   `json:"Country"`
+	Email              string   `json:"Email"`
+	KeyPairAlgorithm   string   `json:"KeyPairAlgorithm"`
+	KeyBitLength       int      `json:"KeyBitLength"`
+}
+
+// Result of a certificate action on one RedfishEndpoint
+type CertActionResult struct {
+	ID        string `json:"ID"`
+	Action    string `json:"Action"`
+	Status    string `json:"Status"`
+	Error     string `json:"Error,omitempty"`
+	CSRString string `json:"CSRString,omitempty"`
+	Timestamp string `json:"Timestamp"`
+}
+
+type CertActionCounts struct {
+	Total     int `json:"Total"`
+	Succeeded int `json:"Succeeded"`
+	Failed    int `json:"Failed"`
+}
+
+// Output of the certificate actions and of GET /Inventory/Certificates/Status
+type CertActionResults struct {
+	Counts  CertActionCounts    `json:"Counts"`
+	Results []*CertActionResult `json:"Results"`
+}
+
+// An HTTPS certificate of a RedfishEndpoint and when it expires, as of the
+// last discovery.  DaysLeft is negative once it has expired, and absent if
+// the BMC didn't say.
+type CertExpiry struct {
+	ID             string `json:"ID"`
+	CertificateURI string `json:"CertificateURI"`
+	Subject        string `json:"Subject"`
+	Issuer         string `json:"Issuer"`
+	ValidNotBefore string `json:"ValidNotBefore,omitempty"`
+	ValidNotAfter  string `json:"ValidNotAfter,omitempty"`
+	DaysLeft       *int   `json:"DaysLeft,omitempty"`
+
+	expiry time.Time
+}
+
+// Output of GET /Inventory/Certificates/Expiry
+type CertExpiryArray struct {
+	Certificates []*CertExpiry `json:"Certificates"`
+}
+
+// Latest certificate action result for each RedfishEndpoint
+type CertStatusStore struct {
+	lock    sync.Mutex
+	results map[string]*CertActionResult
+}
+
+func (cs *CertStatusStore) set(res *CertActionResult) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if cs.results == nil {
+		cs.results = make(map[string]*CertActionResult)
+	}
+	cs.results[res.ID] = res
+}
+
+// Latest results for ids, or for every endpoint if there are none.
+func (cs *CertStatusStore) get(ids []string) []*CertActionResult {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	results := []*CertActionResult{}
+	if len(ids) == 0 {
+		for _, res := range cs.results {
+			results = append(results, res)
+		}
+	} else {
+		for _, id := range ids {
+			if res, ok := cs.results[id]; ok {
+				results = append(results, res)
+			}
+		}
+	}
+	return results
+}
+
+func newCertActionResults(results []*CertActionResult) *CertActionResults {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	out := &CertActionResults{Results: results}
+	for _, res := range results {
+		out.Counts.Total++
+		if res.Status == CertActionSucceeded {
+			out.Counts.Succeeded++
+		} else {
+			out.Counts.Failed++
+		}
+	}
+	return out
+}
+
+// Get the normalized, de-duplicated xnames of the targets, plus extra,
+// expanding groups.  Returns a message for the client on error.
+func (s *SmD) rfEndpointTargetIDs(t *RFEndpointTargets, extra []string) ([]string, int, string) {
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, id := range append(append([]string{}, t.Targets...), extra...) {
+		xname := xnametypes.NormalizeHMSCompID(id)
+		if !xnametypes.IsHMSCompIDValid(xname) {
+			return nil, http.StatusBadRequest, "invalid xname: " + id
+		}
+		if !seen[xname] {
+			seen[xname] = true
+			ids = append(ids, xname)
+		}
+	}
+	var members []string
+	for _, label := range t.Groups {
+		group, err := s.db.GetGroup(label, "")
+		if err != nil {
+			s.LogAlways("rfEndpointTargetIDs(): GetGroup(%s): %s", label, err)
+			return nil, http.StatusInternalServerError, "failed to query DB."
+		} else if group == nil {
+			return nil, http.StatusNotFound, "no such group: " + label
+		}
+		members = append(members, group.Members.IDs...)
+	}
+	if len(members) > 0 {
+		// Only the members that are RedfishEndpoints, e.g. not the nodes
+		eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{ID: members})
+		if err != nil {
+			s.LogAlways("rfEndpointTargetIDs(): GetRFEndpointsFilter: %s", err)
+			return nil, http.StatusInternalServerError, "failed to query DB."
+		}
+		for _, ep := range eps {
+			if !seen[ep.ID] {
+				seen[ep.ID] = true
+				ids = append(ids, ep.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, http.StatusBadRequest, "no target RedfishEndpoints"
+	}
+	return ids, 0, ""
+}
+
+// Run action on each of the RedfishEndpoints ids, with the stored info
+// of their CertificateService, and record the results.
+func (s *SmD) doCertAction(
+	ids []string,
+	action string,
+	do func(rfEP *rf.RedfishEP, info *rf.CertificateServiceInfo, res *CertActionResult) error,
+) (*CertActionResults, error) {
+	eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{ID: ids})
+	if err != nil {
+		return nil, err
+	}
+	seps, err := s.db.GetServiceEndpointsFilter(&hmsds.ServiceEPFilter{
+		Service:      []string{rf.CertificateServiceType},
+		RfEndpointID: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	epMap := make(map[string]*sm.RedfishEndpoint, len(eps))
+	for _, ep := range eps {
+		epMap[ep.ID] = ep
+	}
+	sepMap := make(map[string]*sm.ServiceEndpoint, len(seps))
+	for _, sep := range seps {
+		sepMap[sep.RfEndpointID] = sep
+	}
+
+	results := make([]*CertActionResult, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, certActionFanout)
+	for i, id := range ids {
+		res := &CertActionResult{ID: id, Action: action}
+		results[i] = res
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			err := s.certActionOne(epMap[id], sepMap[id], res, do)
+			res.Timestamp = time.Now().UTC().Format(time.RFC3339)
+			if err != nil {
+				res.Status = CertActionFailed
+				res.Error = err.Error()
+				s.LogAlways("Certificate %s failed for %s: %s", action, id, err)
+			} else {
+				res.Status = CertActionSucceeded
+			}
+			s.certStatus.set(res)
+		}()
+	}
+	wg.Wait()
+	return newCertActionResults(results), nil
+}
+
+// Set up the connection to one endpoint and do the action on it.
+func (s *SmD) certActionOne(
+	ep *sm.RedfishEndpoint,
+	sep *sm.ServiceEndpoint,
+	res *CertActionResult,
+	do func(rfEP *rf.RedfishEP, info *rf.CertificateServiceInfo, res *CertActionResult) error,
+) error {
+	if ep == nil {
+		return errCertNoEndpoint
+	}
+	if sep == nil {
+		return errCertNoService
+	}
+	info := new(rf.CertificateServiceInfo)
+	if err := json.Unmarshal(sep.ServiceInfo, info); err != nil {
+		return err
+	}
+	rfEP, err := s.connectRedfishEP(ep)
+	if err != nil {
+		return err
+	}
+	return do(rfEP, info, res)
+}
+
+// Set up a connection to a RedfishEndpoint outside of discovery, with its
+// vendor profile and its credentials from secure storage if they are kept
+// there.
+func (s *SmD) connectRedfishEP(ep *sm.RedfishEndpoint) (*rf.RedfishEP, error) {
+	rfEP, err := rf.NewRedfishEp(&ep.RedfishEPDescription)
+	if err != nil {
+		return nil, err
+	}
+	s.setDiscoveryVendorProfile(rfEP)
+	if s.readVault {
+		cred, err := s.ccs.GetCompCred(rfEP.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(cred.Password) > 0 {
+			rfEP.User = cred.Username
+			rfEP.Password = cred.Password
+		}
+	}
+	return rfEP, nil
+}
+
+// Decode the POST body into in.  Sends an error and returns false if it
+// can't be.
+func (s *SmD) certDecodeBody(w http.ResponseWriter, r *http.Request, in interface{}) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body")
+		return false
+	}
+	if err := json.Unmarshal(body, in); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return false
+	}
+	return true
+}
+
+// Have RedfishEndpoints generate new keys and CSRs for their HTTPS
+// certificates.  The CSRs are returned in the results.
+func (s *SmD) doCertGenerateCSRPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var in CertCSRIn
+	if !s.certDecodeBody(w, r, &in) {
+		return
+	}
+	ids, code, msg := s.rfEndpointTargetIDs(&in.RFEndpointTargets, nil)
+	if code != 0 {
+		sendJsonError(w, code, msg)
+		return
+	}
+	results, err := s.doCertAction(ids, CertActionGenerateCSR,
+		func(rfEP *rf.RedfishEP, info *rf.CertificateServiceInfo, res *CertActionResult) error {
+			certs := info.HTTPSCertificates()
+			if len(certs) == 0 {
+				return errCertNoHTTPSCert
+			}
+			var target string
+			if info.Actions != nil && info.Actions.GenerateCSR != nil {
+				target = info.Actions.GenerateCSR.Target
+			}
+			req := &rf.GenerateCSRRequest{
+				CertificateCollection: rf.ResourceID{
+					Oid: certs[0][:strings.LastIndex(certs[0], "/")],
+				},
+				CommonName:         in.CommonName,
+				AlternativeNames:   in.AlternativeNames,
+				Organization:       in.Organization,
+				OrganizationalUnit: in.OrganizationalUnit,
+				City:               in.City,
+				State:              in.State,
+				Country:            in.Country,
+				Email:              in.Email,
+				KeyPairAlgorithm:   in.KeyPairAlgorithm,
+				KeyBitLength:       in.KeyBitLength,
+			}
+			if req.CommonName == "" {
+				req.CommonName = rfEP.FQDN
+			}
+			if len(req.AlternativeNames) == 0 {
+				req.AlternativeNames = []string{rfEP.FQDN}
+				if rfEP.Hostname != "" && rfEP.Hostname != rfEP.FQDN {
+					req.AlternativeNames = append(req.AlternativeNames,
+						rfEP.Hostname)
+				}
+			}
+			rsp, err := rfEP.GenerateCSR(target, req)
+			if err != nil {
+				return err
+			}
+			res.CSRString = rsp.CSRString
+			return nil
+		})
+	if err != nil {
+		s.LogAlways("doCertGenerateCSRPost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, results)
+}
+
+// Install new HTTPS certificates on RedfishEndpoints.
+func (s *SmD) doCertReplacePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var in CertReplaceIn
+	if !s.certDecodeBody(w, r, &in) {
+		return
+	}
+	// Normalize the keys of the per-endpoint certificates.
+	certs := make(map[string]CertificateIn, len(in.Certificates))
+	extra := make([]string, 0, len(in.Certificates))
+	for id, cert := range in.Certificates {
+		if cert.Certificate == "" {
+			sendJsonError(w, http.StatusBadRequest, "empty Certificate for "+id)
+			return
+		}
+		certs[xnametypes.NormalizeHMSCompID(id)] = cert
+		extra = append(extra, id)
+	}
+	sort.Strings(extra)
+	if in.Certificate == "" &&
+		(len(in.Targets) > 0 || len(in.Groups) > 0 || len(certs) == 0) {
+		sendJsonError(w, http.StatusBadRequest,
+			"Certificate is needed for Targets and Groups")
+		return
+	}
+	if in.CertificateType == "" {
+		in.CertificateType = "PEM"
+	}
+	ids, code, msg := s.rfEndpointTargetIDs(&in.RFEndpointTargets, extra)
+	if code != 0 {
+		sendJsonError(w, code, msg)
+		return
+	}
+	results, err := s.doCertAction(ids, CertActionReplaceCertificate,
+		func(rfEP *rf.RedfishEP, info *rf.CertificateServiceInfo, res *CertActionResult) error {
+			uris := info.HTTPSCertificates()
+			if len(uris) == 0 {
+				return errCertNoHTTPSCert
+			}
+			var target string
+			if info.Actions != nil && info.Actions.ReplaceCertificate != nil {
+				target = info.Actions.ReplaceCertificate.Target
+			}
+			cert, ok := certs[rfEP.ID]
+			if !ok {
+				cert = in.CertificateIn
+			}
+			certStr := cert.Certificate
+			if cert.PrivateKey != "" {
+				certStr = strings.TrimRight(certStr, "\n") + "\n" + cert.PrivateKey
+			}
+			return rfEP.ReplaceCertificate(target, &rf.ReplaceCertificateRequest{
+				CertificateString: certStr,
+				CertificateType:   in.CertificateType,
+				CertificateUri:    rf.ResourceID{Oid: uris[0]},
+			})
+		})
+	if err != nil {
+		s.LogAlways("doCertReplacePost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, results)
+}
+
+// Get the latest certificate action result for some or all endpoints
+func (s *SmD) doCertStatusGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	ids := []string{}
+	for _, id := range r.URL.Query()["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	sendJsonObject(w, http.StatusOK,
+		newCertActionResults(s.certStatus.get(ids)))
+}
+
+// Get the discovered HTTPS certificates of all or some RedfishEndpoints,
+// soonest to expire first, optionally only those expiring within some days.
+func (s *SmD) doCertExpiryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	query := r.URL.Query()
+	days := -1
+	if val := query.Get("days"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			sendJsonError(w, http.StatusBadRequest,
+				"days must be a non-negative integer")
+			return
+		}
+		days = n
+	}
+	ids := []string{}
+	for _, id := range query["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	seps, err := s.db.GetServiceEndpointsFilter(&hmsds.ServiceEPFilter{
+		Service:      []string{rf.CertificateServiceType},
+		RfEndpointID: ids,
+	})
+	if err != nil {
+		s.lg.Printf("doCertExpiryGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	now := time.Now()
+	deadline := now.Add(time.Duration(days) * 24 * time.Hour)
+	out := CertExpiryArray{Certificates: []*CertExpiry{}}
+	for _, sep := range seps {
+		info := new(rf.CertificateServiceInfo)
+		if err := json.Unmarshal(sep.ServiceInfo, info); err != nil {
+			s.lg.Printf("doCertExpiryGet(): Bad ServiceInfo: %s %s",
+				sep.RfEndpointID, err)
+			continue
+		}
+		for _, cert := range info.HTTPSCertificateDetails {
+			ce := &CertExpiry{
+				ID:             sep.RfEndpointID,
+				CertificateURI: cert.Oid,
+				Subject:        cert.Subject.CommonName,
+				Issuer:         cert.Issuer.CommonName,
+				ValidNotBefore: cert.ValidNotBefore,
+				ValidNotAfter:  cert.ValidNotAfter,
+			}
+			if expiry, ok := cert.Expiry(); ok {
+				left := int(math.Floor(expiry.Sub(now).Hours() / 24))
+				ce.DaysLeft = &left
+				ce.expiry = expiry
+			}
+			if days >= 0 && (ce.DaysLeft == nil || !ce.expiry.Before(deadline)) {
+				continue
+			}
+			out.Certificates = append(out.Certificates, ce)
+		}
+	}
+	// Unknown expiry last
+	sort.SliceStable(out.Certificates, func(i, j int) bool {
+		a, b := out.Certificates[i], out.Certificates[j]
+		if a.DaysLeft == nil || b.DaysLeft == nil {
+			return b.DaysLeft == nil && a.DaysLeft != nil
+		}
+		if !a.expiry.Equal(b.expiry) {
+			return a.expiry.Before(b.expiry)
+		}
+		return a.ID < b.ID
+	})
+	sendJsonObject(w, http.StatusOK, out)
+}
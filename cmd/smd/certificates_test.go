@@ -0,0 +1,192 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestCertReplace(t *testing.T) {
+	defer func() {
+		results.GetRFEndpointsFilter.Return.entries = nil
+		results.GetRFEndpointsFilter.Return.err = nil
+		results.GetServiceEndpointsFilter.Return.entries = nil
+		results.GetServiceEndpointsFilter.Return.err = nil
+		s.certStatus = CertStatusStore{}
+	}()
+	results.GetRFEndpointsFilter.Return.entries = []*sm.RedfishEndpoint{
+		{RedfishEPDescription: rf.RedfishEPDescription{
+			ID: "x0c0s27b0", FQDN: "x0c0s27b0", Enabled: true}},
+	}
+	results.GetRFEndpointsFilter.Return.err = nil
+	results.GetServiceEndpointsFilter.Return.entries = nil
+	results.GetServiceEndpointsFilter.Return.err = nil
+
+	tests := []struct {
+		body    string
+		expCode int
+	}{{
+		`{"Targets": ["x0c0s27b0"]}`,
+		http.StatusBadRequest,
+	}, {
+		`{"Targets": ["x0c0s27b0"], "Certificate": "-----BEGIN CERTIFICATE-----"}`,
+		http.StatusOK,
+	}, {
+		`{"Certificates": {"x0c0s27b0": {"Certificate": ""}}}`,
+		http.StatusBadRequest,
+	}, {
+		`{"Certificates": {"X0C0S27B0": {"Certificate": "-----BEGIN CERTIFICATE-----"}}}`,
+		http.StatusOK,
+	}, {
+		`{"Targets": ["foo"], "Certificate": "-----BEGIN CERTIFICATE-----"}`,
+		http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Inventory/Certificates/Actions/ReplaceCertificate",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		// No CertificateService was discovered for the endpoint.
+		var out CertActionResults
+		json.Unmarshal(w.Body.Bytes(), &out)
+		if out.Counts.Total != 1 || out.Counts.Failed != 1 ||
+			out.Results[0].ID != "x0c0s27b0" ||
+			out.Results[0].Error != errCertNoService.Error() {
+			t.Errorf("Test %d: Unexpected results: %s", i, w.Body.String())
+		}
+	}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/Certificates/Status?id=x0c0s27b0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var out CertActionResults
+	json.Unmarshal(w.Body.Bytes(), &out)
+	if w.Code != http.StatusOK || out.Counts.Total != 1 ||
+		out.Results[0].Action != CertActionReplaceCertificate ||
+		out.Results[0].Status != CertActionFailed {
+		t.Errorf("Unexpected status: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCertExpiryGet(t *testing.T) {
+	defer func() {
+		results.GetServiceEndpointsFilter.Return.entries = nil
+		results.GetServiceEndpointsFilter.Return.err = nil
+	}()
+	now := time.Now().UTC()
+	day := 24 * time.Hour
+	newSep := func(id string, certs ...rf.Certificate) *sm.ServiceEndpoint {
+		info, _ := json.Marshal(&rf.CertificateServiceInfo{
+			HTTPSCertificateDetails: certs})
+		return &sm.ServiceEndpoint{
+			ServiceDescription: rf.ServiceDescription{
+				RfEndpointID: id,
+				RedfishType:  rf.CertificateServiceType,
+			},
+			ServiceInfo: json.RawMessage(info),
+		}
+	}
+	newCert := func(cn string, notAfter string) rf.Certificate {
+		return rf.Certificate{
+			Oid:           "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1",
+			Subject:       rf.CertificateIdentifier{CommonName: cn},
+			Issuer:        rf.CertificateIdentifier{CommonName: "CA"},
+			ValidNotAfter: notAfter,
+		}
+	}
+	results.GetServiceEndpointsFilter.Return.entries = []*sm.ServiceEndpoint{
+		newSep("x0c0s1b0", newCert("s1", now.Add(100*day).Format(time.RFC3339))),
+		newSep("x0c0s2b0", newCert("s2", now.Add(-2*day-time.Hour).Format(time.RFC3339))),
+		newSep("x0c0s3b0", newCert("s3", "")),
+		newSep("x0c0s4b0", newCert("s4", now.Add(10*day+time.Hour).Format(time.RFC3339))),
+		newSep("x0c0s5b0"), // Discovered without certificate details
+	}
+
+	tests := []struct {
+		query    string
+		expCode  int
+		expCNs   []string
+		expDays  []int
+		expIDArg []string
+	}{
+		{"", http.StatusOK, []string{"s2", "s4", "s1", "s3"}, []int{-3, 10, 99}, []string{}},
+		{"?days=30&id=X0C0S4B0", http.StatusOK, []string{"s2", "s4"}, []int{-3, 10}, []string{"x0c0s4b0"}},
+		{"?days=0", http.StatusOK, []string{"s2"}, []int{-3}, []string{}},
+		{"?days=-1", http.StatusBadRequest, nil, nil, nil},
+		{"?days=soon", http.StatusBadRequest, nil, nil, nil},
+	}
+	for i, test := range tests {
+		results.GetServiceEndpointsFilter.Input.f = nil
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Inventory/Certificates/Expiry"+test.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		f := results.GetServiceEndpointsFilter.Input.f
+		if !reflect.DeepEqual(f.RfEndpointID, test.expIDArg) {
+			t.Errorf("Test %d: Expected id filter %v, got %v", i,
+				test.expIDArg, f.RfEndpointID)
+		}
+		var out CertExpiryArray
+		json.Unmarshal(w.Body.Bytes(), &out)
+		cns := []string{}
+		days := []int{}
+		for _, ce := range out.Certificates {
+			cns = append(cns, ce.Subject)
+			if ce.DaysLeft != nil {
+				days = append(days, *ce.DaysLeft)
+			}
+		}
+		if !reflect.DeepEqual(cns, test.expCNs) ||
+			!reflect.DeepEqual(days, test.expDays) {
+			t.Errorf("Test %d: Expected %v %v, got %v %v", i, test.expCNs,
+				test.expDays, cns, days)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Coordination between SMD instances
+//
+// With SMD_CLUSTER_LEASE_SECS set, SMD instances sharing a database
+// coordinate through leases kept in it, each renewed every third of that
+// time:
+//
+//     member/<id>   held by each running instance, so they know each other
+//     leader        held by one instance at a time
+//
+// where <id> is SMD_CLUSTER_ID, the host name by default, which must be
+// unique to each instance.  An instance that stops renewing, e.g. because
+// it died or lost the database, drops out when its leases expire and
+// another instance takes over as leader.  All instances should use the
+// same SMD_CLUSTER_LEASE_SECS.
+//
+// Work that only needs doing once for the whole system is done by the
+// leader alone:
+//
+//     releasing expired reservations and warning of expiring ones
+//     picking up jobs orphaned by instances that died
+//     inventory consistency checks
+//     removing component cache SCN subscriptions of instances that are
+//     gone, see comp-cache.go
+//
+// Work on RedfishEndpoints is sharded: each endpoint belongs to one of the
+// running instances, chosen by rendezvous hashing of the endpoint ID over
+// the member IDs, so only the endpoints of an instance that comes or goes
+// change hands.  Orphaned discoveries are picked up by their endpoint's
+// owner, rather than by whichever instance looks first.
+//
+// SCNs are already partitioned, as each instance sends them for the
+// changes it makes.
+//
+//     GET /service/cluster   this instance's ID, whether it is the leader,
+//                            and the members
+//
+// Without SMD_CLUSTER_LEASE_SECS, each instance acts as leader and owns
+// every endpoint, as if it were the only one.
+///////////////////////////////////////////////////////////////////////////////
+
+// Lease names
+const (
+	clusterLeaderLease  = "leader"
+	clusterMemberPrefix = "member/"
+)
+
+// This instance's view of the cluster, as returned by GET /service/cluster
+type ClusterStatus struct {
+	ID      string   `json:"ID"`
+	Enabled bool     `json:"Enabled"`
+	Leader  bool     `json:"Leader"`
+	Members []string `json:"Members"`
+}
+
+type Cluster struct {
+	ID       string        // Unique to this instance
+	LeaseTTL time.Duration // 0 if not coordinating with other instances
+
+	now func() time.Time
+
+	lock        sync.Mutex
+	leaderUntil time.Time // When leadership lapses unless renewed
+	members     []string  // IDs of running instances, sorted
+}
+
+// Is coordination with other instances on?
+func (c *Cluster) enabled() bool {
+	return c.LeaseTTL > 0
+}
+
+// Should this instance do the work that is only done once?
+func (c *Cluster) isLeader() bool {
+	if !c.enabled() {
+		return true
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now().Before(c.leaderUntil)
+}
+
+// Weight of member for id, the highest of which owns it.
+func clusterWeight(member, id string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(member))
+	h.Write([]byte{0})
+	h.Write([]byte(id))
+	return h.Sum64()
+}
+
+// The member that owns the RedfishEndpoint id.
+func (c *Cluster) owner(id string) string {
+	c.lock.Lock()
+	members := c.members
+	c.lock.Unlock()
+	owner := c.ID
+	best := clusterWeight(c.ID, id)
+	for _, m := range members {
+		if w := clusterWeight(m, id); w > best || (w == best && m < owner) {
+			owner, best = m, w
+		}
+	}
+	return owner
+}
+
+// Should this instance do the work for the RedfishEndpoint id?
+func (c *Cluster) ownsEndpoint(id string) bool {
+	if !c.enabled() {
+		return true
+	}
+	return c.owner(id) == c.ID
+}
+
+func (c *Cluster) status() ClusterStatus {
+	st := ClusterStatus{
+		ID:      c.ID,
+		Enabled: c.enabled(),
+		Leader:  c.isLeader(),
+	}
+	c.lock.Lock()
+	st.Members = append([]string{}, c.members...)
+	c.lock.Unlock()
+	if len(st.Members) == 0 {
+		st.Members = []string{c.ID}
+	}
+	return st
+}
+
+// Spin off a thread to keep this instance's leases, if coordinating with
+// other instances.
+func (s *SmD) StartCluster() {
+	if !s.cluster.enabled() {
+		return
+	}
+	if s.cluster.now == nil {
+		s.cluster.now = time.Now
+	}
+	go func() {
+		for {
+			s.clusterRenew()
+			time.Sleep(s.cluster.LeaseTTL / 3)
+		}
+	}()
+}
+
+// Renew this instance's leases, try to become leader, and look up the
+// other members.
+func (s *SmD) clusterRenew() {
+	c := &s.cluster
+	start := c.now()
+	_, err := s.db.AcquireLease(clusterMemberPrefix+c.ID, c.ID, c.LeaseTTL)
+	if err != nil {
+		s.LogAlways("clusterRenew(): Can't renew membership: %s", err)
+	}
+	leader, err := s.db.AcquireLease(clusterLeaderLease, c.ID, c.LeaseTTL)
+	if err != nil {
+		// Stays leader until the lease would have expired.
+		s.LogAlways("clusterRenew(): Can't renew leadership: %s", err)
+	} else {
+		c.lock.Lock()
+		was := c.now().Before(c.leaderUntil)
+		if leader {
+			c.leaderUntil = start.Add(c.LeaseTTL)
+		} else {
+			c.leaderUntil = time.Time{}
+		}
+		c.lock.Unlock()
+		if leader && !was {
+			s.LogAlways("This instance (%s) is now the leader", c.ID)
+		} else if !leader && was {
+			s.LogAlways("This instance (%s) is no longer the leader", c.ID)
+		}
+	}
+	leases, err := s.db.GetLeases(clusterMemberPrefix)
+	if err != nil {
+		s.LogAlways("clusterRenew(): Can't look up members: %s", err)
+		return
+	}
+	members := []string{c.ID}
+	for _, l := range leases {
+		if l.Holder != c.ID {
+			members = append(members, l.Holder)
+		}
+	}
+	sort.Strings(members)
+	c.lock.Lock()
+	changed := strings.Join(members, ",") != strings.Join(c.members, ",")
+	c.members = members
+	c.lock.Unlock()
+	if changed {
+		s.LogAlways("Cluster members: %v", members)
+	}
+
+	if s.compCache != nil && s.compCachePolicy.SCNURL != "" {
+		// In case it was removed while this instance couldn't renew.
+		if !s.compCacheSubscribed() {
+			if err := s.compCacheSubscribe(); err != nil {
+				s.LogAlways("clusterRenew(): Can't subscribe component cache: %s", err)
+			}
+		}
+	}
+	if c.isLeader() {
+		s.clusterPruneCacheSubs(members)
+	}
+}
+
+// Remove the component cache SCN subscriptions of instances that aren't
+// members any more, as nothing is listening at their URLs.
+func (s *SmD) clusterPruneCacheSubs(members []string) {
+	isMember := make(map[string]bool, len(members))
+	for _, m := range members {
+		isMember[m] = true
+	}
+	s.scnSubLock.Lock()
+	subs := append(s.scnSubs.SubscriptionList[:0:0], s.scnSubs.SubscriptionList...)
+	s.scnSubLock.Unlock()
+	for _, sub := range subs {
+		if !strings.HasPrefix(sub.Subscriber, compCacheSubscriber) ||
+			isMember[strings.TrimPrefix(sub.Subscriber, compCacheSubscriber)] {
+			continue
+		}
+		didDelete, err := s.db.DeleteSCNSubscription(sub.ID)
+		if err != nil {
+			s.LogAlways("clusterPruneCacheSubs(): Can't delete subscription %d: %s",
+				sub.ID, err)
+		} else if didDelete {
+			s.LogAlways("Removed SCN subscription %d of %s, which is gone",
+				sub.ID, sub.Subscriber)
+		}
+	}
+}
+
+// Get this instance's view of the cluster
+func (s *SmD) doClusterGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, s.cluster.status())
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+func TestClusterOwnsEndpoint(t *testing.T) {
+	members := []string{"smd-a", "smd-b", "smd-c"}
+	views := map[string]*Cluster{}
+	for _, m := range members {
+		views[m] = &Cluster{ID: m, LeaseTTL: time.Minute, members: members}
+	}
+	owners := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 300; i++ {
+		id := fmt.Sprintf("x%dc0s0b0", i)
+		n := 0
+		for m, c := range views {
+			if c.ownsEndpoint(id) {
+				owners[id] = m
+				n++
+			}
+		}
+		if n != 1 {
+			t.Fatalf("Expected 1 owner of %s, got %d", id, n)
+		}
+		counts[owners[id]]++
+	}
+	for _, m := range members {
+		if counts[m] < 50 {
+			t.Errorf("Expected endpoints spread out, got %v", counts)
+		}
+	}
+
+	// Only smd-c's endpoints move when it goes.
+	left := &Cluster{ID: "smd-a", LeaseTTL: time.Minute,
+		members: []string{"smd-a", "smd-b"}}
+	for id, owner := range owners {
+		if owner == "smd-a" && !left.ownsEndpoint(id) {
+			t.Errorf("Expected smd-a to keep %s", id)
+		}
+		if owner == "smd-b" && left.ownsEndpoint(id) {
+			t.Errorf("Expected smd-b to keep %s", id)
+		}
+	}
+
+	// Without coordination, everything is owned.
+	alone := &Cluster{ID: "smd-a"}
+	if !alone.isLeader() || !alone.ownsEndpoint("x1c0s0b0") {
+		t.Errorf("Expected a lone instance to be leader and own everything")
+	}
+}
+
+func TestClusterRenew(t *testing.T) {
+	defer func() {
+		s.cluster.ID = ""
+		s.cluster.LeaseTTL = 0
+		s.cluster.leaderUntil = time.Time{}
+		s.cluster.members = nil
+		results.AcquireLease.Return.acquired = false
+		results.GetLeases.Return.leases = nil
+	}()
+	now := time.Now()
+	s.cluster.ID = "smd-b"
+	s.cluster.LeaseTTL = 15 * time.Second
+	s.cluster.now = func() time.Time { return now }
+
+	results.AcquireLease.Return.acquired = true
+	results.AcquireLease.Return.err = nil
+	results.GetLeases.Return.leases = []*hmsds.Lease{
+		{Name: "member/smd-c", Holder: "smd-c"},
+		{Name: "member/smd-a", Holder: "smd-a"},
+	}
+	results.GetLeases.Return.err = nil
+	s.clusterRenew()
+	if results.AcquireLease.Input.name != clusterLeaderLease ||
+		results.AcquireLease.Input.holder != "smd-b" ||
+		results.AcquireLease.Input.ttl != 15*time.Second {
+		t.Errorf("Unexpected lease request %+v", results.AcquireLease.Input)
+	}
+	if results.GetLeases.Input.prefix != clusterMemberPrefix {
+		t.Errorf("Expected members looked up, got prefix %s",
+			results.GetLeases.Input.prefix)
+	}
+	expected := ClusterStatus{ID: "smd-b", Enabled: true, Leader: true,
+		Members: []string{"smd-a", "smd-b", "smd-c"}}
+	if st := s.cluster.status(); !reflect.DeepEqual(st, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, st)
+	}
+
+	// Leadership lapses if it can't be renewed in time.
+	results.AcquireLease.Return.err = fmt.Errorf("connection refused")
+	now = now.Add(10 * time.Second)
+	s.clusterRenew()
+	if !s.cluster.isLeader() {
+		t.Errorf("Expected to stay leader until the lease expires")
+	}
+	now = now.Add(10 * time.Second)
+	if s.cluster.isLeader() {
+		t.Errorf("Expected leadership to lapse")
+	}
+	results.AcquireLease.Return.err = nil
+
+	// Another instance took over.
+	results.AcquireLease.Return.acquired = false
+	s.clusterRenew()
+	if s.cluster.isLeader() {
+		t.Errorf("Expected not to be leader")
+	}
+
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/service/cluster", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var st ClusterStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil ||
+		w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	expected.Leader = false
+	if !reflect.DeepEqual(st, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, st)
+	}
+}
@@ -0,0 +1,640 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component cache
+//
+// If SMD_COMP_CACHE_TTL_MS is set, components read by xname, and the results
+// of component queries (GET and POST /State/Components, .../Query), are
+// kept in memory for that long, so hot paths like BOS and PCS polling
+// component state don't go to the database every time.
+//
+// Every write this instance makes that can change a component, its group
+// or partition membership, or its locks, drops the affected entries as it
+// completes, so this instance always reads its own writes.  A component's
+// entry is dropped, and all query results, since any of them may include
+// it.
+//
+// Changes made by other SMD instances are seen through their SCNs.  With
+// SMD_COMP_CACHE_SCN_URL set to where the other instances can reach this
+// one, e.g.
+//
+//     http://<pod IP>:27779/hsm/v2/State/Components/Cache/Invalidate
+//
+// an SCN subscription for every State, Role, SubRole and Enabled change is
+// added for it at startup, and each SCN received drops the components in
+// it.  Each instance adds a random token=... query parameter to its URL
+// when it starts, and drops SCNs that don't carry it, as the route has no
+// other authentication.  The subscription is named
+// smd-cache@<SMD_CLUSTER_ID>, and is removed
+// once that instance is gone if SMD_CLUSTER_LEASE_SECS is set, see
+// cluster.go.  Changes that have no SCN, e.g. flags, NIDs and group membership,
+// are only seen by other instances once entries expire, so the TTL should
+// be kept short, a few seconds, when running more than one instance.
+//
+// At most SMD_COMP_CACHE_MAX_QUERIES (default 1000) query results are kept;
+// when there are more they are all dropped.
+//
+// Lookups are counted as smd_component_cache_lookups_total{cache,result} in
+// /metrics, where cache is "id" or "query" and result is "hit" or "miss".
+///////////////////////////////////////////////////////////////////////////////
+
+// Policy controlling the component cache.
+type CompCachePolicy struct {
+	TTL        time.Duration // How long entries are used, 0 for no cache
+	MaxQueries int           // Query results kept before they are all dropped
+	SCNURL     string        // Where other instances send SCNs, if set
+}
+
+// Default policy, used for any values not overridden by env vars.
+var DefaultCompCachePolicy = CompCachePolicy{
+	TTL:        0,
+	MaxQueries: 1000,
+}
+
+// Subscriber name for the cache's SCN subscription, followed by the
+// instance's SMD_CLUSTER_ID.
+const compCacheSubscriber = "smd-cache@"
+
+// Cache names and results, for metrics.
+const (
+	CompCacheByID  = "id"
+	CompCacheQuery = "query"
+)
+
+type compCacheEntry struct {
+	comps   []*base.Component
+	expires time.Time
+}
+
+type CompCache struct {
+	policy CompCachePolicy
+	now    func() time.Time
+
+	// Called for each lookup with the cache and whether it was a hit.
+	lookup func(cache string, hit bool)
+
+	// Required on SCNs from other instances, see compCacheSCNURL().
+	scnToken string
+
+	lock    sync.Mutex
+	gen     uint64 // Changed by every invalidation
+	byID    map[string]compCacheEntry
+	queries map[string]compCacheEntry
+}
+
+func NewCompCache(p CompCachePolicy) *CompCache {
+	return &CompCache{
+		policy:  p,
+		now:     time.Now,
+		lookup:  func(string, bool) {},
+		byID:    make(map[string]compCacheEntry),
+		queries: make(map[string]compCacheEntry),
+	}
+}
+
+// Copies of the components, so callers can't change what's cached.
+func copyComps(comps []*base.Component) []*base.Component {
+	if comps == nil {
+		return nil
+	}
+	cps := make([]*base.Component, len(comps))
+	for i, comp := range comps {
+		cp := *comp
+		cps[i] = &cp
+	}
+	return cps
+}
+
+// Look up key in one of the caches.  Returns the components if found and
+// not expired, and the generation to pass to put() if not.
+func (c *CompCache) get(cache string, entries map[string]compCacheEntry, key string) ([]*base.Component, bool, uint64) {
+	c.lock.Lock()
+	e, ok := entries[key]
+	if ok && !c.now().Before(e.expires) {
+		delete(entries, key)
+		ok = false
+	}
+	gen := c.gen
+	c.lock.Unlock()
+	c.lookup(cache, ok)
+	if !ok {
+		return nil, false, gen
+	}
+	return copyComps(e.comps), true, gen
+}
+
+// Keep comps, read from the database, unless something was invalidated
+// since the lookup that returned gen, as they may be from before that.
+func (c *CompCache) put(entries map[string]compCacheEntry, key string, comps []*base.Component, gen uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.gen != gen {
+		return
+	}
+	e := compCacheEntry{
+		comps:   copyComps(comps),
+		expires: c.now().Add(c.policy.TTL),
+	}
+	entries[key] = e
+}
+
+func (c *CompCache) getID(id string) (*base.Component, bool, uint64) {
+	comps, ok, gen := c.get(CompCacheByID, c.byID, id)
+	if !ok {
+		return nil, false, gen
+	}
+	return comps[0], true, gen
+}
+
+func (c *CompCache) putID(id string, comp *base.Component, gen uint64) {
+	c.put(c.byID, id, []*base.Component{comp}, gen)
+}
+
+func (c *CompCache) getQuery(key string) ([]*base.Component, bool, uint64) {
+	return c.get(CompCacheQuery, c.queries, key)
+}
+
+func (c *CompCache) putQuery(key string, comps []*base.Component, gen uint64) {
+	c.lock.Lock()
+	if len(c.queries) >= c.policy.MaxQueries {
+		c.queries = make(map[string]compCacheEntry)
+	}
+	c.lock.Unlock()
+	c.put(c.queries, key, comps, gen)
+}
+
+// Drop the components with the given xnames, and all query results.
+func (c *CompCache) Invalidate(ids []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	for _, id := range ids {
+		delete(c.byID, xnametypes.NormalizeHMSCompID(id))
+	}
+	c.queries = make(map[string]compCacheEntry)
+}
+
+// Drop everything.
+func (c *CompCache) InvalidateAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.gen++
+	c.byID = make(map[string]compCacheEntry)
+	c.queries = make(map[string]compCacheEntry)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Database handle that reads components through the cache.  All other
+// calls go straight to the database, with those that write components
+// invalidating them once done.
+///////////////////////////////////////////////////////////////////////////////
+
+type compCacheDB struct {
+	hmsds.HMSDB
+	c *CompCache
+}
+
+func newCompCacheDB(db hmsds.HMSDB, c *CompCache) hmsds.HMSDB {
+	return &compCacheDB{HMSDB: db, c: c}
+}
+
+// Transactions can write anything, so the whole cache is dropped when one
+// commits.
+type compCacheTx struct {
+	hmsds.HMSDBTx
+	c *CompCache
+}
+
+func (t *compCacheTx) Commit() error {
+	defer t.c.InvalidateAll()
+	return t.HMSDBTx.Commit()
+}
+
+func (d *compCacheDB) Begin() (hmsds.HMSDBTx, error) {
+	tx, err := d.HMSDB.Begin()
+	if err != nil || tx == nil {
+		return tx, err
+	}
+	return &compCacheTx{HMSDBTx: tx, c: d.c}, nil
+}
+
+func (d *compCacheDB) WithActor(actor string) hmsds.HMSDB {
+	return &compCacheDB{HMSDB: d.HMSDB.WithActor(actor), c: d.c}
+}
+
+// Key for a component query.
+func compCacheKey(f *hmsds.ComponentFilter, fieldFltr hmsds.FieldFilter, ids []string) (string, bool) {
+	key, err := json.Marshal(struct {
+		F     *hmsds.ComponentFilter
+		Field hmsds.FieldFilter
+		IDs   []string
+	}{f, fieldFltr, ids})
+	if err != nil {
+		return "", false
+	}
+	return string(key), true
+}
+
+func (d *compCacheDB) GetComponentByID(id string) (*base.Component, error) {
+	id = xnametypes.NormalizeHMSCompID(id)
+	comp, ok, gen := d.c.getID(id)
+	if ok {
+		return comp, nil
+	}
+	comp, err := d.HMSDB.GetComponentByID(id)
+	if err == nil && comp != nil {
+		d.c.putID(id, comp, gen)
+	}
+	return comp, err
+}
+
+func (d *compCacheDB) GetComponentsFilter(f *hmsds.ComponentFilter, fieldFltr hmsds.FieldFilter) ([]*base.Component, error) {
+	key, ok := compCacheKey(f, fieldFltr, nil)
+	if !ok {
+		return d.HMSDB.GetComponentsFilter(f, fieldFltr)
+	}
+	comps, ok, gen := d.c.getQuery("filter" + key)
+	if ok {
+		return comps, nil
+	}
+	comps, err := d.HMSDB.GetComponentsFilter(f, fieldFltr)
+	if err == nil {
+		d.c.putQuery("filter"+key, comps, gen)
+	}
+	return comps, err
+}
+
+func (d *compCacheDB) GetComponentsQuery(f *hmsds.ComponentFilter, fieldFltr hmsds.FieldFilter, ids []string) ([]*base.Component, error) {
+	key, ok := compCacheKey(f, fieldFltr, ids)
+	if !ok {
+		return d.HMSDB.GetComponentsQuery(f, fieldFltr, ids)
+	}
+	comps, ok, gen := d.c.getQuery("query" + key)
+	if ok {
+		return comps, nil
+	}
+	comps, err := d.HMSDB.GetComponentsQuery(f, fieldFltr, ids)
+	if err == nil {
+		d.c.putQuery("query"+key, comps, gen)
+	}
+	return comps, err
+}
+
+//
+// Component writes
+//
+
+func (d *compCacheDB) InsertComponent(c *base.Component) (int64, error) {
+	defer d.c.Invalidate([]string{c.ID})
+	return d.HMSDB.InsertComponent(c)
+}
+
+func (d *compCacheDB) InsertComponents(comps *base.ComponentArray) ([]string, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.InsertComponents(comps)
+}
+
+func (d *compCacheDB) UpsertComponents(comps []*base.Component, force bool) (map[string]map[string]bool, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.UpsertComponents(comps, force)
+}
+
+func (d *compCacheDB) UpdateComponentIfUnchanged(prev, c *base.Component) (bool, error) {
+	defer d.c.Invalidate([]string{c.ID})
+	return d.HMSDB.UpdateComponentIfUnchanged(prev, c)
+}
+
+func (d *compCacheDB) BulkPatchComponents(f *hmsds.ComponentFilter, p *hmsds.CompBulkPatch) (map[string]map[string]bool, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.BulkPatchComponents(f, p)
+}
+
+func (d *compCacheDB) UpdateCompStates(ids []string, state string, flag string, force bool, pi *hmsds.PartInfo) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.UpdateCompStates(ids, state, flag, force, pi)
+}
+
+func (d *compCacheDB) UpdateCompFlagOnly(id string, flag string) (int64, error) {
+	defer d.c.Invalidate([]string{id})
+	return d.HMSDB.UpdateCompFlagOnly(id, flag)
+}
+
+func (d *compCacheDB) BulkUpdateCompFlagOnly(ids []string, flag string) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.BulkUpdateCompFlagOnly(ids, flag)
+}
+
+func (d *compCacheDB) UpdateCompEnabled(id string, enabled bool) (int64, error) {
+	defer d.c.Invalidate([]string{id})
+	return d.HMSDB.UpdateCompEnabled(id, enabled)
+}
+
+func (d *compCacheDB) BulkUpdateCompEnabled(ids []string, enabled bool) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.BulkUpdateCompEnabled(ids, enabled)
+}
+
+func (d *compCacheDB) UpdateCompSwStatus(id string, swStatus string) (int64, error) {
+	defer d.c.Invalidate([]string{id})
+	return d.HMSDB.UpdateCompSwStatus(id, swStatus)
+}
+
+func (d *compCacheDB) BulkUpdateCompSwStatus(ids []string, swstatus string) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.BulkUpdateCompSwStatus(ids, swstatus)
+}
+
+func (d *compCacheDB) UpdateCompRole(id string, role, subRole string) (int64, error) {
+	defer d.c.Invalidate([]string{id})
+	return d.HMSDB.UpdateCompRole(id, role, subRole)
+}
+
+func (d *compCacheDB) BulkUpdateCompRole(ids []string, role, subRole string) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.BulkUpdateCompRole(ids, role, subRole)
+}
+
+func (d *compCacheDB) BulkUpdateCompClass(ids []string, class string) ([]string, error) {
+	defer d.c.Invalidate(ids)
+	return d.HMSDB.BulkUpdateCompClass(ids, class)
+}
+
+func (d *compCacheDB) UpdateCompNID(c *base.Component) error {
+	defer d.c.Invalidate([]string{c.ID})
+	return d.HMSDB.UpdateCompNID(c)
+}
+
+func (d *compCacheDB) BulkUpdateCompNID(comps *[]base.Component) error {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.BulkUpdateCompNID(comps)
+}
+
+func (d *compCacheDB) DeleteComponentByID(id string) (bool, error) {
+	defer d.c.Invalidate([]string{id})
+	return d.HMSDB.DeleteComponentByID(id)
+}
+
+func (d *compCacheDB) DeleteComponentsAll() (int64, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.DeleteComponentsAll()
+}
+
+func (d *compCacheDB) UpdateCompLocksV2(f sm.CompLockV2Filter, action string) (sm.CompLockV2UpdateResult, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.UpdateCompLocksV2(f, action)
+}
+
+//
+// Endpoint writes, which also set components
+//
+
+func (d *compCacheDB) DeleteRFEndpointByIDSetEmpty(id string) (bool, []string, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.DeleteRFEndpointByIDSetEmpty(id)
+}
+
+func (d *compCacheDB) DeleteRFEndpointsAllSetEmpty() (int64, []string, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.DeleteRFEndpointsAllSetEmpty()
+}
+
+func (d *compCacheDB) DeleteCompEndpointByIDSetEmpty(id string) (bool, []string, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.DeleteCompEndpointByIDSetEmpty(id)
+}
+
+func (d *compCacheDB) DeleteCompEndpointsAllSetEmpty() (int64, []string, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.DeleteCompEndpointsAllSetEmpty()
+}
+
+func (d *compCacheDB) UpdateAllForRFEndpoint(
+	ep *sm.RedfishEndpoint,
+	ceps *sm.ComponentEndpointArray,
+	hls []*sm.HWInvByLoc,
+	comps *base.ComponentArray,
+	seps *sm.ServiceEndpointArray,
+	ceis []*sm.CompEthInterfaceV2,
+) (*[]base.Component, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.UpdateAllForRFEndpoint(ep, ceps, hls, comps, seps, ceis)
+}
+
+func (d *compCacheDB) UpdateAllForRFEndpointBatches(
+	ep *sm.RedfishEndpoint,
+	next func() (*hmsds.RFEndpointBatch, error),
+) (*[]base.Component, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.UpdateAllForRFEndpointBatches(ep, next)
+}
+
+func (d *compCacheDB) RestoreTombstone(kind, id string) (bool, error) {
+	defer d.c.InvalidateAll()
+	return d.HMSDB.RestoreTombstone(kind, id)
+}
+
+//
+// Group and partition writes, which change the results of queries by group
+// or partition.
+//
+
+func (d *compCacheDB) InsertGroup(g *sm.Group) (string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.InsertGroup(g)
+}
+
+func (d *compCacheDB) UpdateGroup(label string, gp *sm.GroupPatch) error {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.UpdateGroup(label, gp)
+}
+
+func (d *compCacheDB) DeleteGroup(label string) (bool, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.DeleteGroup(label)
+}
+
+func (d *compCacheDB) AddGroupMember(label, id string) (string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.AddGroupMember(label, id)
+}
+
+func (d *compCacheDB) DeleteGroupMember(label, id string) (bool, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.DeleteGroupMember(label, id)
+}
+
+func (d *compCacheDB) SetGroupMembers(label string, ids []string) ([]string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.SetGroupMembers(label, ids)
+}
+
+func (d *compCacheDB) InsertPartition(p *sm.Partition) (string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.InsertPartition(p)
+}
+
+func (d *compCacheDB) UpdatePartition(pname string, pp *sm.PartitionPatch) error {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.UpdatePartition(pname, pp)
+}
+
+func (d *compCacheDB) DeletePartition(pname string) (bool, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.DeletePartition(pname)
+}
+
+func (d *compCacheDB) AddPartitionMember(pname, id string) (string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.AddPartitionMember(pname, id)
+}
+
+func (d *compCacheDB) DeletePartitionMember(pname, id string) (bool, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.DeletePartitionMember(pname, id)
+}
+
+func (d *compCacheDB) MovePartitionMembers(from, to string, ids []string) ([]string, error) {
+	defer d.c.Invalidate(nil)
+	return d.HMSDB.MovePartitionMembers(from, to, ids)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Invalidation by other SMD instances
+///////////////////////////////////////////////////////////////////////////////
+
+// A new random token for SCNs from other instances.
+func newCompCacheSCNToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// The URL other instances send SCNs to: SCNURL with this instance's token.
+func (s *SmD) compCacheSCNURL() string {
+	url := s.compCachePolicy.SCNURL
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+	return url + sep + "token=" + s.compCache.scnToken
+}
+
+// Is this instance's SCN subscription in the subscriptions last read?
+func (s *SmD) compCacheSubscribed() bool {
+	subscriber := compCacheSubscriber + s.cluster.ID
+	url := s.compCacheSCNURL()
+	s.scnSubLock.Lock()
+	defer s.scnSubLock.Unlock()
+	for _, sub := range s.scnSubs.SubscriptionList {
+		if sub.Subscriber == subscriber && sub.Url == url {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribe to the SCNs of all SMD instances, at SCNURL, unless already
+// subscribed.  The subscriber is named for the instance's SMD_CLUSTER_ID,
+// see cluster.go.  Its subscriptions with an old token, e.g. from before a
+// restart, are removed.
+func (s *SmD) compCacheSubscribe() error {
+	url := s.compCacheSCNURL()
+	subscriber := compCacheSubscriber + s.cluster.ID
+	subs, err := s.db.GetSCNSubscriptionsAll()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, sub := range subs.SubscriptionList {
+		if sub.Subscriber != subscriber {
+			continue
+		} else if sub.Url == url {
+			found = true
+		} else if _, err := s.db.DeleteSCNSubscription(sub.ID); err != nil {
+			return err
+		}
+	}
+	if found {
+		return nil
+	}
+	enabled := true
+	_, err = s.db.InsertSCNSubscription(sm.SCNPostSubscription{
+		Subscriber: subscriber,
+		Enabled:    &enabled,
+		Roles:      base.GetHMSRoleList(),
+		SubRoles:   base.GetHMSSubRoleList(),
+		States:     base.GetHMSStateList(),
+		Url:        url,
+	})
+	return err
+}
+
+// Drop the components in an SCN sent by another instance.  Always
+// succeeds when there's no cache, so SCNs aren't retried, and otherwise
+// needs the token in this instance's subscription URL.
+func (s *SmD) doCompCacheInvalidatePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if s.compCache != nil {
+		token := r.URL.Query().Get("token")
+		if s.compCache.scnToken == "" || subtle.ConstantTimeCompare(
+			[]byte(token), []byte(s.compCache.scnToken)) != 1 {
+			sendJsonError(w, http.StatusForbidden, "bad token")
+			return
+		}
+	}
+	var scn sm.SCNPayload
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &scn)
+	}
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, "error decoding JSON "+err.Error())
+		return
+	}
+	if s.compCache != nil {
+		s.compCache.Invalidate(scn.Components)
+	}
+	sendJsonError(w, http.StatusOK, "invalidated")
+}
@@ -0,0 +1,219 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestCompCacheByID(t *testing.T) {
+	c := NewCompCache(CompCachePolicy{TTL: time.Minute, MaxQueries: 10})
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	lookups := map[string]int{}
+	c.lookup = func(cache string, hit bool) {
+		if hit {
+			lookups[cache+" hit"]++
+		} else {
+			lookups[cache+" miss"]++
+		}
+	}
+	db := newCompCacheDB(s.db, c)
+	results.GetComponentByID.Return.id = &base.Component{ID: "x0c0s0b0n0", State: "On"}
+	results.GetComponentByID.Return.err = nil
+	defer func() {
+		results.GetComponentByID.Return.id = nil
+		results.UpdateCompStates.Return.affectedIds = nil
+	}()
+
+	// read reads x0c0s0b0n0 and returns its state and whether the DB was
+	// read.
+	read := func() (string, bool) {
+		results.GetComponentByID.Input.id = ""
+		comp, err := db.GetComponentByID("x0c0s0b0n0")
+		if err != nil || comp == nil {
+			t.Fatalf("Unexpected result %v, %v", comp, err)
+		}
+		return comp.State, results.GetComponentByID.Input.id != ""
+	}
+	if _, fromDB := read(); !fromDB {
+		t.Errorf("Expected first read from DB")
+	}
+	state, fromDB := read()
+	if fromDB || state != "On" {
+		t.Errorf("Expected cached On, got %s (from DB %t)", state, fromDB)
+	}
+
+	// Callers can't change what's cached.
+	comp, _ := db.GetComponentByID("x0c0s0b0n0")
+	comp.State = "Bogus"
+	if state, _ := read(); state != "On" {
+		t.Errorf("Expected cached component unchanged, got %s", state)
+	}
+
+	// Writes through the cache drop the component.
+	results.GetComponentByID.Return.id = &base.Component{ID: "x0c0s0b0n0", State: "Off"}
+	db.UpdateCompStates([]string{"x0c0s0b0n0"}, "Off", "OK", false, nil)
+	if state, fromDB := read(); !fromDB || state != "Off" {
+		t.Errorf("Expected Off from DB after write, got %s (from DB %t)", state, fromDB)
+	}
+
+	// So do SCNs from other instances, when the cache is on, if they have
+	// its token.
+	s.compCache = c
+	c.scnToken = "f00d"
+	defer func() { s.compCache = nil }()
+	results.GetComponentByID.Return.id = &base.Component{ID: "x0c0s0b0n0", State: "Ready"}
+	for _, token := range []string{"", "bad"} {
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/State/Components/Cache/Invalidate?token="+token,
+			bytes.NewBufferString(`{"Components":["x0c0s0b0n0"],"State":"Ready"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Token '%s': response code was %v; want 403: %s", token,
+				w.Code, w.Body.String())
+		}
+	}
+	if state, fromDB := read(); fromDB || state != "Off" {
+		t.Errorf("Expected cached Off after SCNs without the token, got %s (from DB %t)",
+			state, fromDB)
+	}
+	req, _ := http.NewRequest("POST",
+		"https://localhost/hsm/v2/State/Components/Cache/Invalidate?token=f00d",
+		bytes.NewBufferString(`{"Components":["x0c0s0b0n0"],"State":"Ready"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Response code was %v; want 200: %s", w.Code, w.Body.String())
+	}
+	if state, fromDB := read(); !fromDB || state != "Ready" {
+		t.Errorf("Expected Ready from DB after SCN, got %s (from DB %t)", state, fromDB)
+	}
+
+	// And entries expire.
+	now = now.Add(time.Minute)
+	if _, fromDB := read(); !fromDB {
+		t.Errorf("Expected expired entry read from DB")
+	}
+	if lookups["id hit"] != 4 || lookups["id miss"] != 4 {
+		t.Errorf("Unexpected lookups %v", lookups)
+	}
+}
+
+func TestCompCacheSubscribe(t *testing.T) {
+	savedCache, savedURL, savedID := s.compCache, s.compCachePolicy.SCNURL, s.cluster.ID
+	defer func() {
+		s.compCache, s.compCachePolicy.SCNURL, s.cluster.ID = savedCache, savedURL, savedID
+		results.GetSCNSubscriptionsAll.Return.subs = nil
+		results.InsertSCNSubscription.Input.sub = sm.SCNPostSubscription{}
+		results.DeleteSCNSubscription.Input.id = 0
+	}()
+	s.compCache = NewCompCache(CompCachePolicy{TTL: time.Minute})
+	s.compCache.scnToken = "f00d"
+	s.compCachePolicy.SCNURL = "http://10.0.0.1:27779/hsm/v2/State/Components/Cache/Invalidate"
+	s.cluster.ID = "smd-0"
+	url := s.compCachePolicy.SCNURL + "?token=f00d"
+	if s.compCacheSCNURL() != url {
+		t.Errorf("Expected URL %s, got %s", url, s.compCacheSCNURL())
+	}
+
+	// One from before a restart, with an old token
+	results.GetSCNSubscriptionsAll.Return.subs = &sm.SCNSubscriptionArray{
+		SubscriptionList: []sm.SCNSubscription{{
+			ID:         3,
+			Subscriber: "smd-cache@smd-1",
+			Url:        "http://10.0.0.2:27779/hsm/v2/State/Components/Cache/Invalidate?token=beef",
+		}, {
+			ID:         4,
+			Subscriber: "smd-cache@smd-0",
+			Url:        s.compCachePolicy.SCNURL + "?token=beef",
+		}},
+	}
+	results.GetSCNSubscriptionsAll.Return.err = nil
+	results.InsertSCNSubscription.Return.err = nil
+	results.DeleteSCNSubscription.Return.err = nil
+	if err := s.compCacheSubscribe(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if results.DeleteSCNSubscription.Input.id != 4 {
+		t.Errorf("Expected old subscription 4 deleted, got %d",
+			results.DeleteSCNSubscription.Input.id)
+	}
+	sub := results.InsertSCNSubscription.Input.sub
+	if sub.Subscriber != "smd-cache@smd-0" || sub.Url != url {
+		t.Errorf("Unexpected subscription %+v", sub)
+	}
+}
+
+func TestCompCacheQuery(t *testing.T) {
+	c := NewCompCache(CompCachePolicy{TTL: time.Minute, MaxQueries: 2})
+	db := newCompCacheDB(s.db, c)
+	results.GetComponentsFilter.Return.ids = []*base.Component{
+		{ID: "x0c0s0b0n0", State: "On"},
+	}
+	results.GetComponentsFilter.Return.err = nil
+	defer func() { results.GetComponentsFilter.Return.ids = nil }()
+
+	// read queries by state and returns the number of components found and
+	// whether the DB was read.
+	read := func(state string) (int, bool) {
+		results.GetComponentsFilter.Input.fieldFilter = -1
+		comps, err := db.GetComponentsFilter(
+			&hmsds.ComponentFilter{State: []string{state}}, hmsds.FLTR_DEFAULT)
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		return len(comps), results.GetComponentsFilter.Input.fieldFilter != -1
+	}
+	if _, fromDB := read("On"); !fromDB {
+		t.Errorf("Expected first query from DB")
+	}
+	if n, fromDB := read("On"); fromDB || n != 1 {
+		t.Errorf("Expected 1 cached component, got %d (from DB %t)", n, fromDB)
+	}
+	if _, fromDB := read("Off"); !fromDB {
+		t.Errorf("Expected different query from DB")
+	}
+
+	// Group membership changes query results.
+	db.AddGroupMember("blue", "x0c0s0b0n0")
+	if _, fromDB := read("On"); !fromDB {
+		t.Errorf("Expected query from DB after group change")
+	}
+
+	// Past MaxQueries, results are dropped.
+	read("Off")
+	read("Ready")
+	if _, fromDB := read("On"); !fromDB {
+		t.Errorf("Expected query from DB after too many queries")
+	}
+}
@@ -0,0 +1,174 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component state history
+//
+// Every change to a component is kept in the database by a trigger, along
+// with who made it: the API caller, or one of the CompHistActor* names
+// below for changes HSM makes itself.  GET /State/Components/{xname}/History
+// returns its State and Flag changes, optionally only those between
+// starttime and endtime, and with aggregate=state or aggregate=flag, how
+// long it spent in each State or Flag over that time, e.g. for availability
+// reports.  Without a starttime, aggregates cover the week before endtime
+// (default now).
+///////////////////////////////////////////////////////////////////////////////
+
+// Who changed a component, when it was HSM itself
+const (
+	CompHistActorDiscovery    = "smd:discovery"
+	CompHistActorRedfishEvent = "smd:redfish-event"
+	CompHistActorStatePoll    = "smd:state-poll"
+	CompHistActorCoolingFault = "smd:cooling-fault"
+	CompHistActorMACConflict  = "smd:mac-conflict"
+	CompHistActorHeartbeat    = "smd:heartbeat"
+	CompHistActorBMCProbe     = "smd:bmc-probe"
+)
+
+// Default time range for aggregates without a starttime
+const CompHistDefaultRange = 7 * 24 * time.Hour
+
+// Time spent in each State (by "state") or Flag (by "flag") between start
+// and end, given the history from the change in effect at start on.
+func compStateTimes(hist []*sm.CompStateHistEntry, by string, start, end time.Time) (map[string]float64, error) {
+	secs := make(map[string]float64)
+	for i, e := range hist {
+		from, err := time.Parse(time.RFC3339Nano, e.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		to := end
+		if i+1 < len(hist) {
+			if to, err = time.Parse(time.RFC3339Nano, hist[i+1].Timestamp); err != nil {
+				return nil, err
+			}
+			if to.After(end) {
+				to = end
+			}
+		}
+		if from.Before(start) {
+			from = start
+		}
+		if e.Deleted || !to.After(from) {
+			continue
+		}
+		key := e.State
+		if by == "flag" {
+			key = e.Flag
+		}
+		secs[key] += to.Sub(from).Seconds()
+	}
+	return secs, nil
+}
+
+// Get the State and Flag changes of a component, and optionally the time
+// it spent in each.
+func (s *SmD) doCompHistoryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !xnametypes.IsHMSCompIDValid(xname) {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname.")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doCompHistoryGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	f := &hmsds.CompStateHistFilter{
+		StartTime: r.Form.Get("starttime"),
+		EndTime:   r.Form.Get("endtime"),
+	}
+	by := strings.ToLower(r.Form.Get("aggregate"))
+	var start, end time.Time
+	if by != "" {
+		if by != "state" && by != "flag" {
+			sendJsonError(w, http.StatusBadRequest,
+				"aggregate must be state or flag")
+			return
+		}
+		var err error
+		end = time.Now()
+		if f.EndTime != "" {
+			if end, err = time.Parse(time.RFC3339, f.EndTime); err != nil {
+				sendJsonError(w, http.StatusBadRequest,
+					"bad endtime: "+err.Error())
+				return
+			}
+		}
+		start = end.Add(-CompHistDefaultRange)
+		if f.StartTime != "" {
+			if start, err = time.Parse(time.RFC3339, f.StartTime); err != nil {
+				sendJsonError(w, http.StatusBadRequest,
+					"bad starttime: "+err.Error())
+				return
+			}
+		}
+		if !end.After(start) {
+			sendJsonError(w, http.StatusBadRequest,
+				"endtime must be after starttime")
+			return
+		}
+		f.StartTime = start.UTC().Format(time.RFC3339)
+		f.EndTime = end.UTC().Format(time.RFC3339)
+	}
+
+	hist, err := s.db.GetCompStateHist(xname, f)
+	if err != nil {
+		s.reqLog(r).LogAlways("doCompHistoryGet(): Lookup failure: (%s) %s", xname, err)
+		sendJsonDBError(w, "bad query param: ", "DB query failed.", err)
+		return
+	}
+	ch := &sm.CompStateHistory{ID: xname, History: hist}
+	if by != "" {
+		secs, err := compStateTimes(hist, by, start, end)
+		if err != nil {
+			s.reqLog(r).LogAlways("doCompHistoryGet(): bad history: (%s) %s", xname, err)
+			sendJsonError(w, http.StatusInternalServerError,
+				"bad timestamp in history.")
+			return
+		}
+		ch.Times = &sm.CompStateTimes{
+			By:        by,
+			StartTime: f.StartTime,
+			EndTime:   f.EndTime,
+			Seconds:   secs,
+		}
+	}
+	sendJsonObject(w, http.StatusOK, ch)
+}
@@ -0,0 +1,190 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestCompStateTimes(t *testing.T) {
+	day := func(d int) string {
+		return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC).Format(time.RFC3339Nano)
+	}
+	hist := []*sm.CompStateHistEntry{
+		{State: "On", Flag: "OK", Timestamp: day(1)},
+		{State: "Ready", Flag: "OK", Timestamp: day(3)},
+		{State: "Ready", Flag: "Warning", Timestamp: day(4)},
+		{State: "Ready", Flag: "Warning", Deleted: true, Timestamp: day(6)},
+		{State: "Off", Flag: "OK", Timestamp: day(7)},
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	d := (24 * time.Hour).Seconds()
+	tests := []struct {
+		by       string
+		start    time.Time
+		end      time.Time
+		expected map[string]float64
+	}{{
+		by:       "state",
+		start:    start,
+		end:      end,
+		expected: map[string]float64{"On": d, "Ready": 3 * d, "Off": d},
+	}, {
+		by:       "flag",
+		start:    start,
+		end:      end,
+		expected: map[string]float64{"OK": 3 * d, "Warning": 2 * d},
+	}, {
+		// Window ending before the last change
+		by:       "state",
+		start:    start,
+		end:      time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC),
+		expected: map[string]float64{"On": d, "Ready": d / 2},
+	}}
+	for i, test := range tests {
+		secs, err := compStateTimes(hist, test.by, test.start, test.end)
+		if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, secs) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, secs)
+		}
+	}
+}
+
+func TestDoCompHistoryGet(t *testing.T) {
+	defer func() {
+		results.GetCompStateHist.Input.id = ""
+		results.GetCompStateHist.Input.f = nil
+		results.GetCompStateHist.Return.hist = nil
+		results.GetCompStateHist.Return.err = nil
+	}()
+	results.GetCompStateHist.Return.err = nil
+	results.GetCompStateHist.Return.hist = []*sm.CompStateHistEntry{
+		{State: "On", Flag: "OK", Actor: CompHistActorDiscovery,
+			Timestamp: "2026-03-01T00:00:00Z"},
+		{State: "Ready", Flag: "OK", Actor: CompHistActorRedfishEvent,
+			Timestamp: "2026-03-02T00:00:00Z"},
+	}
+	tests := []struct {
+		url      string
+		code     int
+		filter   *hmsds.CompStateHistFilter
+		expected map[string]float64
+	}{{
+		url:    "https://localhost/hsm/v2/State/Components/X0C0S0B0N0/History",
+		code:   http.StatusOK,
+		filter: &hmsds.CompStateHistFilter{},
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/x0c0s0b0n0/History?starttime=2026-03-01T12:00:00Z&endtime=2026-03-03T00:00:00Z&aggregate=State",
+		code: http.StatusOK,
+		filter: &hmsds.CompStateHistFilter{StartTime: "2026-03-01T12:00:00Z",
+			EndTime: "2026-03-03T00:00:00Z"},
+		expected: map[string]float64{"On": 12 * 3600, "Ready": 24 * 3600},
+	}, {
+		// Default range is the week before endtime
+		url:  "https://localhost/hsm/v2/State/Components/x0c0s0b0n0/History?endtime=2026-03-03T00:00:00Z&aggregate=flag",
+		code: http.StatusOK,
+		filter: &hmsds.CompStateHistFilter{StartTime: "2026-02-24T00:00:00Z",
+			EndTime: "2026-03-03T00:00:00Z"},
+		expected: map[string]float64{"OK": 2 * 24 * 3600},
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/x0c0s0b0n0/History?aggregate=role",
+		code: http.StatusBadRequest,
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/x0c0s0b0n0/History?aggregate=state&starttime=yesterday",
+		code: http.StatusBadRequest,
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/x0c0s0b0n0/History?aggregate=state&starttime=2026-03-03T00:00:00Z&endtime=2026-03-01T00:00:00Z",
+		code: http.StatusBadRequest,
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/foo/History",
+		code: http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		results.GetCompStateHist.Input.f = nil
+		req, _ := http.NewRequest("GET", test.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+		if results.GetCompStateHist.Input.id != "x0c0s0b0n0" ||
+			!reflect.DeepEqual(test.filter, results.GetCompStateHist.Input.f) {
+			t.Errorf("Test %d: expected lookup of x0c0s0b0n0 %+v, got %s %+v", i,
+				test.filter, results.GetCompStateHist.Input.id,
+				results.GetCompStateHist.Input.f)
+		}
+		var ch sm.CompStateHistory
+		if err := json.Unmarshal(w.Body.Bytes(), &ch); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+			continue
+		}
+		if ch.ID != "x0c0s0b0n0" || len(ch.History) != 2 {
+			t.Errorf("Test %d: bad history: %+v", i, ch)
+		}
+		if test.expected == nil {
+			if ch.Times != nil {
+				t.Errorf("Test %d: unexpected Times: %+v", i, ch.Times)
+			}
+		} else if ch.Times == nil ||
+			!reflect.DeepEqual(test.expected, ch.Times.Seconds) {
+			t.Errorf("Test %d: expected Times %v, got %+v", i, test.expected,
+				ch.Times)
+		}
+	}
+}
+
+func TestDoCompUpdateActor(t *testing.T) {
+	defer func() {
+		results.WithActor.Input.actor = ""
+		results.UpdateCompStates.Return.affectedIds = nil
+	}()
+	results.UpdateCompStates.Return.err = nil
+	update := &CompUpdate{
+		ComponentIDs: []string{"x0c0s0b0n0"},
+		UpdateType:   StateDataUpdate.String(),
+		State:        "Ready",
+		actor:        CompHistActorRedfishEvent,
+	}
+	if err := s.doCompUpdate(update, "TestDoCompUpdateActor"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if results.WithActor.Input.actor != CompHistActorRedfishEvent {
+		t.Errorf("Expected actor %s, got '%s'", CompHistActorRedfishEvent,
+			results.WithActor.Input.actor)
+	}
+}
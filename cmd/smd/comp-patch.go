@@ -0,0 +1,382 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Partial component updates
+//
+// PATCH /State/Components/{xname} takes a JSON merge patch (RFC 7386) of the
+// component, e.g. {"Role":"Application","SubRole":"UAN"}, and changes only
+// the fields it has.  null clears a field that may be empty.  ID and Type
+// can't be changed, nor Locked and ReservationDisabled, which belong to the
+// locking API.  The body can be sent as application/json or
+// application/merge-patch+json.
+//
+// GET /State/Components/{xname} and the PATCH response have an ETag for the
+// component's current contents.  Sending it back in If-Match makes the PATCH
+// fail with 412 Precondition Failed if the component has changed since, so
+// concurrent writers don't silently overwrite each other.  If-Match: * only
+// requires that the component exists.
+//
+// The component is compared again under a row lock when the change is
+// written, so a write that lands between the check and the update is caught
+// as well.  Without If-Match the patch is simply re-applied to the newer
+// component, a few times at most.
+//
+// PATCH /State/Components with the same filter parameters as the GET, e.g.
+// ?role=Compute&state=Off, sets Flag, Enabled and/or SoftwareStatus on every
+// matching component in one transaction and returns the IDs of those that
+// changed.  At least one filter parameter is required, so a typo can't turn
+// into an update of the whole system.
+///////////////////////////////////////////////////////////////////////////////
+
+const MergePatchMediaType = "application/merge-patch+json"
+
+// Attempts at a PATCH without If-Match before giving up with 409.
+const compPatchRetries = 3
+
+var errCompPatchObject = errors.New("patch must be a JSON object")
+var errCompPatchReadOnly = errors.New("ID, Type, Locked and ReservationDisabled cannot be patched")
+var errCompPatchNID = errors.New("NID must be an integer")
+var errCompBulkPatchNoFilter = errors.New("at least one filter parameter is required")
+var errCompBulkPatchNoFields = errors.New("at least one of Flag, Enabled or SoftwareStatus is required")
+var errCompPatchMediaType = errors.New("Content-Type must be " +
+	"application/json or " + MergePatchMediaType)
+
+// Entity tag for the current contents of a component.
+func compETag(c *base.Component) string {
+	raw, _ := json.Marshal(c)
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// True if the If-Match header value allows writing a component whose tag
+// is etag.  Weak tags never match, as for any If-Match.
+func ifMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply an RFC 7386 merge patch to target.
+func mergePatch(target map[string]interface{}, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			tsub, ok := target[k].(map[string]interface{})
+			if !ok {
+				tsub = make(map[string]interface{})
+			}
+			mergePatch(tsub, sub)
+			target[k] = tsub
+			continue
+		}
+		target[k] = v
+	}
+}
+
+// Return a copy of cur with patch applied, checked and normalized the way
+// a PUT would be.  cur is not modified.
+func patchComponent(cur *base.Component, patch map[string]interface{}) (*base.Component, error) {
+	raw, err := json.Marshal(cur)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	mergePatch(doc, patch)
+	if raw, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
+	compIn := sm.ComponentPut{}
+	dec = json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&compIn.Component); err != nil {
+		return nil, err
+	}
+	c := &compIn.Component
+	if xnametypes.NormalizeHMSCompID(c.ID) != cur.ID || c.Type != cur.Type ||
+		c.Locked != cur.Locked ||
+		c.ReservationDisabled != cur.ReservationDisabled {
+		return nil, errCompPatchReadOnly
+	}
+	if err := compIn.VerifyNormalize(); err != nil {
+		return nil, err
+	}
+	// Match what the database will hand back, so the ETag in the response
+	// is the one a GET would return.
+	if c.Enabled == nil {
+		enabled := true
+		c.Enabled = &enabled
+	}
+	if len(c.NID) != 0 {
+		nid, err := c.NID.Int64()
+		if err != nil {
+			return nil, errCompPatchNID
+		} else if nid < 0 {
+			c.NID = ""
+		}
+	}
+	return c, nil
+}
+
+// Update a single component with a JSON merge patch, optionally only if
+// it still has the ETag given in If-Match.
+func (s *SmD) doComponentPatch(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, _ := mime.ParseMediaType(ct)
+		if mt != "application/json" && mt != MergePatchMediaType {
+			sendJsonError(w, http.StatusUnsupportedMediaType,
+				errCompPatchMediaType.Error())
+			return
+		}
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body")
+		return
+	}
+	var patch map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&patch); err != nil || patch == nil {
+		sendJsonError(w, http.StatusBadRequest, errCompPatchObject.Error())
+		return
+	}
+	ifMatch := r.Header.Get("If-Match")
+
+	for try := 1; ; try++ {
+		cur, err := s.db.GetComponentByID(xname)
+		if err != nil {
+			s.LogAlways("doComponentPatch(): Lookup failure: (%s) %s", xname, err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		if cur == nil {
+			if ifMatch != "" {
+				sendJsonError(w, http.StatusPreconditionFailed,
+					"no such xname.")
+			} else {
+				sendJsonError(w, http.StatusNotFound, "no such xname.")
+			}
+			return
+		}
+		if ifMatch != "" && !ifMatches(ifMatch, compETag(cur)) {
+			w.Header().Set("ETag", compETag(cur))
+			sendJsonError(w, http.StatusPreconditionFailed,
+				"component has been modified.")
+			return
+		}
+		comp, err := patchComponent(cur, patch)
+		if err != nil {
+			s.lg.Printf("doComponentPatch(): Couldn't patch component: %s", err)
+			sendJsonError(w, http.StatusBadRequest,
+				"couldn't patch component: "+err.Error())
+			return
+		}
+		ok, err := s.db.UpdateComponentIfUnchanged(cur, comp)
+		if err != nil {
+			sendJsonDBError(w, "operation 'PATCH' failed: ", "", err)
+			s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+			return
+		}
+		if !ok {
+			if ifMatch != "" {
+				sendJsonError(w, http.StatusPreconditionFailed,
+					"component has been modified.")
+				return
+			} else if try >= compPatchRetries {
+				sendJsonError(w, http.StatusConflict,
+					"component is being modified, try again.")
+				return
+			}
+			continue
+		}
+		if comp.NID != cur.NID {
+			s.warnNIDsInUse(w, []*base.Component{comp})
+		}
+		s.queueCompPatchSCNs(cur, comp)
+		w.Header().Set("ETag", compETag(comp))
+		sendJsonCompRsp(w, comp)
+		return
+	}
+}
+
+// IDs of the components changed by a bulk PATCH.
+type CompBulkPatchOut struct {
+	ComponentIDs []string `json:"ComponentIDs"`
+}
+
+// Update Flag, Enabled and/or SoftwareStatus on all components matching the
+// query parameters.
+func (s *SmD) doComponentsPatch(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.lg.Printf("doComponentsPatch(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	// Unknown parameters would otherwise just be ignored, widening the
+	// update.
+	if len(r.Form) == 0 {
+		sendJsonError(w, http.StatusBadRequest, errCompBulkPatchNoFilter.Error())
+		return
+	}
+	params := make(map[string]string)
+	jsonFieldNames(reflect.TypeOf(hmsds.ComponentFilter{}), params)
+	for p := range r.Form {
+		if _, ok := params[strings.ToLower(p)]; !ok {
+			sendJsonError(w, http.StatusBadRequest,
+				"unknown filter parameter: "+p)
+			return
+		}
+	}
+	formJSON, err := json.Marshal(r.Form)
+	if err != nil {
+		s.lg.Printf("doComponentsPatch(): Marshall form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	compFilter := new(hmsds.ComponentFilter)
+	if err = json.Unmarshal(formJSON, compFilter); err != nil {
+		s.lg.Printf("doComponentsPatch(): Unmarshall form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+
+	patch := new(hmsds.CompBulkPatch)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(patch); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if patch.Flag == nil && patch.Enabled == nil && patch.SwStatus == nil {
+		sendJsonError(w, http.StatusBadRequest, errCompBulkPatchNoFields.Error())
+		return
+	}
+	changes, err := s.db.BulkPatchComponents(compFilter, patch)
+	if err != nil {
+		s.LogAlways("doComponentsPatch(): Update failure: %s", err)
+		sendJsonDBError(w, "bad query param: ", "", err)
+		return
+	}
+
+	out := CompBulkPatchOut{ComponentIDs: make([]string, 0, len(changes))}
+	flagIDs := []string{}
+	enabledIDs := []string{}
+	swStatusIDs := []string{}
+	for id, fields := range changes {
+		out.ComponentIDs = append(out.ComponentIDs, id)
+		if fields["flag"] {
+			flagIDs = append(flagIDs, id)
+		}
+		if fields["enabled"] {
+			enabledIDs = append(enabledIDs, id)
+		}
+		if fields["swStatus"] {
+			swStatusIDs = append(swStatusIDs, id)
+		}
+	}
+	sort.Strings(out.ComponentIDs)
+	sort.Strings(flagIDs)
+	sort.Strings(enabledIDs)
+	sort.Strings(swStatusIDs)
+	// No SCNs for flag changes, as for FlagOnly updates, but they are
+	// streamed.
+	if len(flagIDs) != 0 {
+		s.compChanged(sm.SCNPayload{
+			Components: flagIDs,
+			Flag:       base.VerifyNormalizeFlag(*patch.Flag),
+		})
+	}
+	if len(enabledIDs) != 0 {
+		s.wp.Queue(NewJobSCN(enabledIDs, base.Component{Enabled: patch.Enabled}, s))
+	}
+	if len(swStatusIDs) != 0 {
+		s.wp.Queue(NewJobSCN(swStatusIDs, base.Component{SwStatus: *patch.SwStatus}, s))
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
+
+// Send SCNs for the fields a PATCH changed, the same as for a PUT.
+func (s *SmD) queueCompPatchSCNs(prev, c *base.Component) {
+	scnIds := []string{c.ID}
+	if c.State != prev.State {
+		s.wp.Queue(NewJobSCN(scnIds, base.Component{State: c.State}, s))
+	} else if c.Flag != prev.Flag {
+		// No SCN, but streamed like other flag-only changes.
+		s.compChanged(sm.SCNPayload{Components: scnIds, Flag: c.Flag})
+	}
+	if *c.Enabled != (prev.Enabled == nil || *prev.Enabled) {
+		s.wp.Queue(NewJobSCN(scnIds, base.Component{Enabled: c.Enabled}, s))
+	}
+	if c.SwStatus != prev.SwStatus {
+		s.wp.Queue(NewJobSCN(scnIds, base.Component{SwStatus: c.SwStatus}, s))
+	}
+	if c.Role != prev.Role || c.SubRole != prev.SubRole {
+		s.wp.Queue(NewJobSCN(scnIds,
+			base.Component{Role: c.Role, SubRole: c.SubRole}, s))
+	}
+}
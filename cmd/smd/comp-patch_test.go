@@ -0,0 +1,349 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+func TestIfMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		expect bool
+	}{
+		{`"abc"`, `"abc"`, true},
+		{`"xyz", "abc"`, `"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, false},
+		{`"xyz"`, `"abc"`, false},
+	}
+	for i, test := range tests {
+		if ifMatches(test.header, test.etag) != test.expect {
+			t.Errorf("Test %v Failed: ifMatches(%s, %s) expected %v",
+				i, test.header, test.etag, test.expect)
+		}
+	}
+}
+
+func TestPatchComponent(t *testing.T) {
+	enabledFlg := true
+	cur := &base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Compute", "", "864", "", "Sling", "X86", "", false, false}
+
+	tests := []struct {
+		patch       string
+		expected    *base.Component
+		expectedErr string
+	}{{
+		`{"Role":"application","SubRole":"Worker"}`,
+		&base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Application", "Worker", "864", "", "Sling", "X86", "", false, false},
+		"",
+	}, {
+		`{"ID":"X0C0S27B0N0","NID":-1,"Enabled":null,"Arch":null}`,
+		&base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Compute", "", "", "", "Sling", "", "", false, false},
+		"",
+	}, {
+		`{"State":"Bogus"}`, nil, "state 'Bogus' is invalid",
+	}, {
+		`{"State":null}`, nil, "state '' is invalid",
+	}, {
+		`{"Type":"NodeBMC"}`, nil, errCompPatchReadOnly.Error(),
+	}, {
+		`{"ID":"x0c0s27b0n1"}`, nil, errCompPatchReadOnly.Error(),
+	}, {
+		`{"Locked":true}`, nil, errCompPatchReadOnly.Error(),
+	}, {
+		`{"NID":1.5}`, nil, errCompPatchNID.Error(),
+	}, {
+		`{"Color":"Blue"}`, nil, `json: unknown field "Color"`,
+	}}
+	for i, test := range tests {
+		var patch map[string]interface{}
+		dec := json.NewDecoder(strings.NewReader(test.patch))
+		dec.UseNumber()
+		if err := dec.Decode(&patch); err != nil {
+			t.Fatalf("Test %v: bad patch: %s", i, err)
+		}
+		comp, err := patchComponent(cur, patch)
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("Test %v Failed: Expected error '%s'; Received '%v'",
+					i, test.expectedErr, err)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+		} else if compETag(comp) != compETag(test.expected) {
+			t.Errorf("Test %v Failed: Expected '%+v'; Received '%+v'",
+				i, test.expected, comp)
+		}
+	}
+	if cur.Role != "Compute" || cur.NID != "864" {
+		t.Errorf("Current component was modified: %+v", cur)
+	}
+}
+
+func TestDoComponentPatch(t *testing.T) {
+	enabledFlg := true
+	cur := &base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Compute", "", "864", "", "Sling", "X86", "", false, false}
+	patched := &base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Application", "Worker", "864", "", "Sling", "X86", "", false, false}
+	curTag := compETag(cur)
+
+	tests := []struct {
+		ifMatch      string
+		contentType  string
+		body         string
+		hmsdsComp    *base.Component
+		hmsdsOK      bool
+		hmsdsErr     error
+		expectUpdate bool
+		expectedCode int
+		expectedETag string
+		expectedResp string
+	}{{
+		ifMatch:      curTag,
+		contentType:  MergePatchMediaType,
+		body:         `{"Role":"Application","SubRole":"Worker"}`,
+		hmsdsComp:    cur,
+		hmsdsOK:      true,
+		expectUpdate: true,
+		expectedCode: http.StatusOK,
+		expectedETag: compETag(patched),
+		expectedResp: `{"ID":"x0c0s27b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Application","SubRole":"Worker","NID":864,"NetType":"Sling","Arch":"X86"}` + "\n",
+	}, {
+		ifMatch:      `"stale"`,
+		body:         `{"Role":"Application"}`,
+		hmsdsComp:    cur,
+		expectedCode: http.StatusPreconditionFailed,
+		expectedETag: curTag,
+		expectedResp: `{"type":"about:blank","title":"Precondition Failed","detail":"component has been modified.","status":412}` + "\n",
+	}, {
+		// Changed between the check and the write
+		ifMatch:      "*",
+		body:         `{"Role":"Application","SubRole":"Worker"}`,
+		hmsdsComp:    cur,
+		hmsdsOK:      false,
+		expectUpdate: true,
+		expectedCode: http.StatusPreconditionFailed,
+		expectedResp: `{"type":"about:blank","title":"Precondition Failed","detail":"component has been modified.","status":412}` + "\n",
+	}, {
+		// Keeps changing without If-Match
+		body:         `{"Role":"Application","SubRole":"Worker"}`,
+		hmsdsComp:    cur,
+		hmsdsOK:      false,
+		expectUpdate: true,
+		expectedCode: http.StatusConflict,
+		expectedResp: `{"type":"about:blank","title":"Conflict","detail":"component is being modified, try again.","status":409}` + "\n",
+	}, {
+		body:         `{"Role":"Application"}`,
+		expectedCode: http.StatusNotFound,
+		expectedResp: `{"type":"about:blank","title":"Not Found","detail":"no such xname.","status":404}` + "\n",
+	}, {
+		contentType:  "text/plain",
+		body:         `{"Role":"Application"}`,
+		hmsdsComp:    cur,
+		expectedCode: http.StatusUnsupportedMediaType,
+		expectedResp: `{"type":"about:blank","title":"Unsupported Media Type","detail":"Content-Type must be application/json or application/merge-patch+json","status":415}` + "\n",
+	}, {
+		body:         `["Role"]`,
+		hmsdsComp:    cur,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"patch must be a JSON object","status":400}` + "\n",
+	}, {
+		body:         `{"Type":"NodeBMC"}`,
+		hmsdsComp:    cur,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"couldn't patch component: ID, Type, Locked and ReservationDisabled cannot be patched","status":400}` + "\n",
+	}, {
+		body:         `{"Role":"Application"}`,
+		hmsdsComp:    cur,
+		hmsdsErr:     errors.New("unexpected DB error"),
+		expectUpdate: true,
+		expectedCode: http.StatusInternalServerError,
+		expectedResp: `{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}` + "\n",
+	}}
+	defer func() {
+		results.GetComponentByID.Return.id = nil
+		results.UpdateComponentIfUnchanged.Input.prev = nil
+		results.UpdateComponentIfUnchanged.Input.c = nil
+		results.UpdateComponentIfUnchanged.Return.ok = false
+		results.UpdateComponentIfUnchanged.Return.err = nil
+	}()
+
+	for i, test := range tests {
+		results.GetComponentByID.Return.id = test.hmsdsComp
+		results.GetComponentByID.Return.err = nil
+		results.UpdateComponentIfUnchanged.Input.prev = nil
+		results.UpdateComponentIfUnchanged.Input.c = nil
+		results.UpdateComponentIfUnchanged.Return.ok = test.hmsdsOK
+		results.UpdateComponentIfUnchanged.Return.err = test.hmsdsErr
+
+		req, err := http.NewRequest("PATCH",
+			"https://localhost/hsm/v2/State/Components/x0c0s27b0n0",
+			bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		if test.ifMatch != "" {
+			req.Header.Set("If-Match", test.ifMatch)
+		}
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if w.Header().Get("ETag") != test.expectedETag {
+			t.Errorf("Test %v Failed: ETag was '%s'; want '%s'",
+				i, w.Header().Get("ETag"), test.expectedETag)
+		}
+		if w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, test.expectedResp, w.Body)
+		}
+		if prev := results.UpdateComponentIfUnchanged.Input.prev; (prev != nil) != test.expectUpdate {
+			t.Errorf("Test %v Failed: Expected update %v; Received %+v",
+				i, test.expectUpdate, prev)
+		} else if prev != nil && prev != test.hmsdsComp {
+			t.Errorf("Test %v Failed: Update was not conditional on the component read", i)
+		}
+	}
+
+	// GET returns the same tag
+	results.GetComponentByID.Return.id = cur
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/State/Components/x0c0s27b0n0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Header().Get("ETag") != curTag {
+		t.Errorf("GET ETag was '%s'; want '%s'", w.Header().Get("ETag"), curTag)
+	}
+}
+
+func TestDoComponentsPatch(t *testing.T) {
+	flag := "Warning"
+	enabled := false
+	tests := []struct {
+		reqURI         string
+		body           string
+		hmsdsChanges   map[string]map[string]bool
+		hmsdsErr       error
+		expectedFilter *hmsds.ComponentFilter
+		expectedPatch  *hmsds.CompBulkPatch
+		expectedCode   int
+		expectedResp   string
+	}{{
+		reqURI: "https://localhost/hsm/v2/State/Components?role=Compute&state=Off",
+		body:   `{"Flag":"Warning","Enabled":false}`,
+		hmsdsChanges: map[string]map[string]bool{
+			"x0c0s27b0n0": {"flag": true},
+			"x0c0s26b0n0": {"flag": true, "enabled": true},
+		},
+		expectedFilter: &hmsds.ComponentFilter{Role: []string{"Compute"}, State: []string{"Off"}},
+		expectedPatch:  &hmsds.CompBulkPatch{Flag: &flag, Enabled: &enabled},
+		expectedCode:   http.StatusOK,
+		expectedResp:   `{"ComponentIDs":["x0c0s26b0n0","x0c0s27b0n0"]}` + "\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/State/Components?group=grp1",
+		body:           `{"Flag":"Warning"}`,
+		hmsdsChanges:   map[string]map[string]bool{},
+		expectedFilter: &hmsds.ComponentFilter{Group: []string{"grp1"}},
+		expectedPatch:  &hmsds.CompBulkPatch{Flag: &flag},
+		expectedCode:   http.StatusOK,
+		expectedResp:   `{"ComponentIDs":[]}` + "\n",
+	}, {
+		reqURI:       "https://localhost/hsm/v2/State/Components",
+		body:         `{"Flag":"Warning"}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"at least one filter parameter is required","status":400}` + "\n",
+	}, {
+		reqURI:       "https://localhost/hsm/v2/State/Components?rol=Compute",
+		body:         `{"Flag":"Warning"}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"unknown filter parameter: rol","status":400}` + "\n",
+	}, {
+		reqURI:       "https://localhost/hsm/v2/State/Components?role=Compute",
+		body:         `{}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"at least one of Flag, Enabled or SoftwareStatus is required","status":400}` + "\n",
+	}, {
+		reqURI:       "https://localhost/hsm/v2/State/Components?role=Compute",
+		body:         `{"State":"Off"}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: `{"type":"about:blank","title":"Bad Request","detail":"error decoding JSON json: unknown field \"State\"","status":400}` + "\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/State/Components?role=Compute",
+		body:           `{"Flag":"Warning"}`,
+		hmsdsErr:       hmsds.ErrHMSDSArgBadFlag,
+		expectedFilter: &hmsds.ComponentFilter{Role: []string{"Compute"}},
+		expectedPatch:  &hmsds.CompBulkPatch{Flag: &flag},
+		expectedCode:   http.StatusBadRequest,
+		expectedResp:   `{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not a valid HMS Flag","status":400}` + "\n",
+	}}
+	defer func() {
+		results.BulkPatchComponents.Input.f = nil
+		results.BulkPatchComponents.Input.p = nil
+		results.BulkPatchComponents.Return.changes = nil
+		results.BulkPatchComponents.Return.err = nil
+	}()
+
+	for i, test := range tests {
+		results.BulkPatchComponents.Input.f = nil
+		results.BulkPatchComponents.Input.p = nil
+		results.BulkPatchComponents.Return.changes = test.hmsdsChanges
+		results.BulkPatchComponents.Return.err = test.hmsdsErr
+
+		req, err := http.NewRequest("PATCH", test.reqURI, bytes.NewBufferString(test.body))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, test.expectedResp, w.Body)
+		}
+		if f := results.BulkPatchComponents.Input.f; (f == nil) != (test.expectedFilter == nil) ||
+			(f != nil && !compareFilter(*test.expectedFilter, *f)) {
+			t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, f)
+		}
+		if p := results.BulkPatchComponents.Input.p; !reflect.DeepEqual(p, test.expectedPatch) {
+			t.Errorf("Test %v Failed: Expected patch is '%+v'; Received '%+v'", i, test.expectedPatch, p)
+		}
+	}
+}
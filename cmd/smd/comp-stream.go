@@ -0,0 +1,298 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component change stream
+//
+//     GET /State/Components/Stream
+//
+// is a Server-Sent Events stream of component changes, for dashboards and
+// CLIs that want to follow live state without polling or running a listener
+// for SCN subscriptions.  Each change is sent as
+//
+//     id: <stream>-<seq>
+//     event: scn
+//     data: {"Components":["x0c0s0b0n0"],"State":"Ready","Flag":"OK"}
+//
+// where data is the same payload an SCN subscriber would get.  Flag-only
+// changes, which have no SCN, are streamed too.
+//
+// The last SMD_COMP_STREAM_BACKLOG (default 1000) events are kept, so a
+// client that reconnects with Last-Event-ID, as EventSource does, gets what
+// it missed.  If those events are gone, or the ID is from before SMD
+// restarted, a "resync" event is sent first, meaning the client should
+// re-read the components it cares about.  A client that can't keep up is
+// disconnected and can resume the same way.
+//
+// A stream ends just before the server's request timeout, and the client
+// reconnects and resumes after the retry delay it is given.
+//
+// Each SMD instance streams the changes it made itself, like SCNs.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	CompStreamEventSCN    = "scn"
+	CompStreamEventResync = "resync"
+
+	CompStreamMediaType = "text/event-stream"
+)
+
+// Events kept for clients that reconnect, unless overridden.
+const CompStreamBacklogDefault = 1000
+
+// Events queued for one client before it is considered too slow.
+const compStreamClientQueue = 256
+
+// Interval between keepalive comments, so idle connections aren't dropped by
+// proxies.
+const compStreamKeepalive = 30 * time.Second
+
+// How long before the request deadline the stream is ended, so it finishes
+// cleanly rather than timing out.
+const compStreamDeadlineMargin = time.Second
+
+// Reconnect delay given to clients, in milliseconds.
+const compStreamRetryMS = 1000
+
+// One streamed change.
+type CompStreamEvent struct {
+	seq     uint64
+	payload sm.SCNPayload
+}
+
+type CompStream struct {
+	lock     sync.Mutex
+	streamID string // Distinguishes this run of SMD in event IDs
+	backlog  int
+	seq      uint64
+	events   []CompStreamEvent // Most recent last
+	clients  map[chan CompStreamEvent]bool
+}
+
+func (cs *CompStream) init() {
+	if cs.streamID == "" {
+		cs.streamID = strconv.FormatInt(time.Now().UnixNano(), 36)
+		cs.clients = make(map[chan CompStreamEvent]bool)
+	}
+	if cs.backlog <= 0 {
+		cs.backlog = CompStreamBacklogDefault
+	}
+}
+
+// Set how many events are kept for clients that reconnect.
+func (cs *CompStream) setBacklog(n int) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.backlog = n
+}
+
+// Send a change to all clients.  Clients whose queue is full are dropped.
+func (cs *CompStream) publish(payload sm.SCNPayload) {
+	if len(payload.Components) == 0 {
+		return
+	}
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.init()
+
+	cs.seq++
+	ev := CompStreamEvent{seq: cs.seq, payload: payload}
+	cs.events = append(cs.events, ev)
+	if len(cs.events) > cs.backlog {
+		cs.events = append([]CompStreamEvent(nil),
+			cs.events[len(cs.events)-cs.backlog:]...)
+	}
+	for ch := range cs.clients {
+		select {
+		case ch <- ev:
+		default:
+			delete(cs.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// Add a client that has seen events up to lastID ("" for a new client).
+// Returns the events it missed, the ID for a resync event if some of them are
+// no longer available (else ""), and the channel for new events, which is
+// closed if the client falls behind.
+func (cs *CompStream) subscribe(lastID string) ([]CompStreamEvent, string, chan CompStreamEvent) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	cs.init()
+
+	missed := []CompStreamEvent{}
+	resync := false
+	if lastID != "" {
+		seq, ok := cs.parseID(lastID)
+		switch {
+		case !ok:
+			resync = true
+		case len(cs.events) > 0 && seq+1 < cs.events[0].seq:
+			// Some were dropped from the backlog, send what's left.
+			resync = true
+			missed = append(missed, cs.events...)
+		case seq < cs.seq:
+			for _, ev := range cs.events {
+				if ev.seq > seq {
+					missed = append(missed, ev)
+				}
+			}
+		case seq > cs.seq:
+			resync = true
+		}
+	}
+	resyncID := ""
+	if resync {
+		// Resumes from just before the events being sent.
+		seq := cs.seq
+		if len(missed) > 0 {
+			seq = missed[0].seq - 1
+		}
+		resyncID = cs.eventID(CompStreamEvent{seq: seq})
+	}
+	ch := make(chan CompStreamEvent, compStreamClientQueue)
+	cs.clients[ch] = true
+	return missed, resyncID, ch
+}
+
+// Remove a client, if it wasn't already dropped.
+func (cs *CompStream) unsubscribe(ch chan CompStreamEvent) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if cs.clients[ch] {
+		delete(cs.clients, ch)
+		close(ch)
+	}
+}
+
+func (cs *CompStream) eventID(ev CompStreamEvent) string {
+	return cs.streamID + "-" + strconv.FormatUint(ev.seq, 10)
+}
+
+// Sequence number of an event ID from this stream.
+func (cs *CompStream) parseID(id string) (uint64, bool) {
+	i := strings.LastIndexByte(id, '-')
+	if i < 0 || id[:i] != cs.streamID {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(id[i+1:], 10, 64)
+	return seq, err == nil
+}
+
+// Write one event in SSE format.
+func writeCompStreamEvent(w http.ResponseWriter, id, event string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, raw)
+	return err
+}
+
+// Stream component changes to the client until it goes away.
+func (s *SmD) doComponentsStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		sendJsonError(w, http.StatusInternalServerError,
+			"streaming is not supported.")
+		return
+	}
+	lastID := r.Header.Get("Last-Event-ID")
+	missed, resyncID, ch := s.compStream.subscribe(lastID)
+	defer s.compStream.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", CompStreamMediaType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", compStreamRetryMS); err != nil {
+		return
+	}
+
+	send := func(ev CompStreamEvent) bool {
+		err := writeCompStreamEvent(w, s.compStream.eventID(ev),
+			CompStreamEventSCN, ev.payload)
+		return err == nil
+	}
+	if resyncID != "" {
+		if writeCompStreamEvent(w, resyncID, CompStreamEventResync, struct{}{}) != nil {
+			return
+		}
+	}
+	for _, ev := range missed {
+		if !send(ev) {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(compStreamKeepalive)
+	defer keepalive.Stop()
+	var deadline <-chan time.Time
+	if d, ok := r.Context().Deadline(); ok {
+		end := time.NewTimer(time.Until(d) - compStreamDeadlineMargin)
+		defer end.Stop()
+		deadline = end.C
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				s.lg.Printf("doComponentsStream(): Dropped slow client %s",
+					r.RemoteAddr)
+				return
+			}
+			if !send(ev) {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		case <-deadline:
+			return
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
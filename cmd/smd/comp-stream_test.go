@@ -0,0 +1,144 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestCompStreamSubscribe(t *testing.T) {
+	cs := CompStream{}
+	cs.setBacklog(3)
+	_, _, ch := cs.subscribe("")
+	for _, state := range []string{"On", "Ready", "Off", "On"} {
+		cs.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, State: state})
+	}
+	if len(ch) != 4 {
+		t.Errorf("Expected 4 events queued, got %v", len(ch))
+	}
+	cs.unsubscribe(ch)
+	if _, ok := <-ch; !ok {
+		t.Errorf("Expected queued events after unsubscribe")
+	}
+
+	tests := []struct {
+		lastID       string
+		expectSeqs   []uint64
+		expectResync string
+	}{
+		{"", []uint64{}, ""},
+		{cs.streamID + "-4", []uint64{}, ""},
+		{cs.streamID + "-2", []uint64{3, 4}, ""},
+		{cs.streamID + "-1", []uint64{2, 3, 4}, ""},
+		{cs.streamID + "-0", []uint64{2, 3, 4}, cs.streamID + "-1"},
+		{cs.streamID + "-9", []uint64{}, cs.streamID + "-4"},
+		{"old-3", []uint64{}, cs.streamID + "-4"},
+		{"garbage", []uint64{}, cs.streamID + "-4"},
+	}
+	for i, test := range tests {
+		missed, resyncID, ch := cs.subscribe(test.lastID)
+		seqs := []uint64{}
+		for _, ev := range missed {
+			seqs = append(seqs, ev.seq)
+		}
+		if len(seqs) != len(test.expectSeqs) ||
+			(len(seqs) != 0 && seqs[0] != test.expectSeqs[0]) {
+			t.Errorf("Test %v Failed: Expected missed %v, got %v",
+				i, test.expectSeqs, seqs)
+		}
+		if resyncID != test.expectResync {
+			t.Errorf("Test %v Failed: Expected resync ID '%s', got '%s'",
+				i, test.expectResync, resyncID)
+		}
+		cs.unsubscribe(ch)
+	}
+
+	// A client that falls behind is dropped.
+	_, _, ch = cs.subscribe("")
+	for i := 0; i <= compStreamClientQueue; i++ {
+		cs.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, Flag: "OK"})
+	}
+	for range ch {
+	}
+	if len(cs.clients) != 0 {
+		t.Errorf("Expected slow client to be dropped")
+	}
+	cs.unsubscribe(ch)
+}
+
+func TestDoComponentsStream(t *testing.T) {
+	defer func() {
+		s.compStream = CompStream{}
+	}()
+	s.compStream = CompStream{}
+	s.compStream.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, State: "Ready", Flag: "OK"})
+	s.compStream.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, Flag: "Alert"})
+	id1 := s.compStream.streamID + "-1"
+	id2 := s.compStream.streamID + "-2"
+
+	tests := []struct {
+		lastID       string
+		expectedResp string
+	}{{
+		lastID:       "",
+		expectedResp: "retry: 1000\n\n",
+	}, {
+		lastID: id1,
+		expectedResp: "retry: 1000\n\n" +
+			"id: " + id2 + "\nevent: scn\ndata: {\"Components\":[\"x0c0s0b0n0\"],\"Flag\":\"Alert\"}\n\n",
+	}, {
+		lastID: "old-7",
+		expectedResp: "retry: 1000\n\n" +
+			"id: " + id2 + "\nevent: resync\ndata: {}\n\n",
+	}}
+	for i, test := range tests {
+		// The stream ends at the deadline, less the margin, so right away.
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		req, _ := http.NewRequestWithContext(ctx, "GET", "http://localhost/hsm/v2/State/Components/Stream", nil)
+		if test.lastID != "" {
+			req.Header.Set("Last-Event-ID", test.lastID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		cancel()
+		if w.Code != http.StatusOK {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v",
+				i, http.StatusOK, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, CompStreamMediaType) {
+			t.Errorf("Test %v Failed: Expected Content-Type %s, got '%s'",
+				i, CompStreamMediaType, ct)
+		}
+		if w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Compact component encoding
+//
+// Clients that ask for it with
+//
+//     Accept: application/vnd.smd.compact+json
+//
+// get component collections with Type, State, Flag, Role, SubRole, NetType,
+// Arch and Class as small integer codes instead of strings.  Each code is
+// an index into the matching list in the response's Dictionary, which only
+// holds the values used in that response, in the order they first appear.
+// Fields that are empty are left out as usual.  For dumps of many thousands
+// of components this is a fraction of the size of the regular encoding.
+///////////////////////////////////////////////////////////////////////////////
+
+const CompactMediaType = "application/vnd.smd.compact+json"
+
+// Dictionary of the values behind the codes in a CompactComponentArray,
+// keyed by field name.
+type CompactDictionary map[string][]string
+
+type CompactComponent struct {
+	ID                  string      `json:"ID"`
+	Type                *int        `json:"Type,omitempty"`
+	State               *int        `json:"State,omitempty"`
+	Flag                *int        `json:"Flag,omitempty"`
+	Enabled             *bool       `json:"Enabled,omitempty"`
+	SwStatus            string      `json:"SoftwareStatus,omitempty"`
+	Role                *int        `json:"Role,omitempty"`
+	SubRole             *int        `json:"SubRole,omitempty"`
+	NID                 json.Number `json:"NID,omitempty"`
+	Subtype             string      `json:"Subtype,omitempty"`
+	NetType             *int        `json:"NetType,omitempty"`
+	Arch                *int        `json:"Arch,omitempty"`
+	Class               *int        `json:"Class,omitempty"`
+	ReservationDisabled bool        `json:"ReservationDisabled,omitempty"`
+	Locked              bool        `json:"Locked,omitempty"`
+}
+
+type CompactComponentArray struct {
+	Dictionary CompactDictionary   `json:"Dictionary"`
+	Components []*CompactComponent `json:"Components"`
+}
+
+// True if the client will take the compact encoding.
+func acceptsCompact(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mt, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mt != CompactMediaType {
+				continue
+			}
+			// q=0 means "not acceptable"
+			if q, ok := params["q"]; ok && strings.Trim(q, "0.") == "" {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Builds the codes for one field.
+type compactCoder struct {
+	values []string
+	codes  map[string]int
+}
+
+func (c *compactCoder) code(val string) *int {
+	if val == "" {
+		return nil
+	}
+	code, ok := c.codes[val]
+	if !ok {
+		if c.codes == nil {
+			c.codes = make(map[string]int)
+		}
+		code = len(c.values)
+		c.codes[val] = code
+		c.values = append(c.values, val)
+	}
+	return &code
+}
+
+// Convert a ComponentArray to its compact encoding.
+func NewCompactComponentArray(comps *base.ComponentArray) *CompactComponentArray {
+	var typ, state, flag, role, subRole, netType, arch, class compactCoder
+
+	cca := &CompactComponentArray{
+		Components: make([]*CompactComponent, 0, len(comps.Components)),
+	}
+	for _, comp := range comps.Components {
+		if comp == nil {
+			continue
+		}
+		cca.Components = append(cca.Components, &CompactComponent{
+			ID:                  comp.ID,
+			Type:                typ.code(comp.Type),
+			State:               state.code(comp.State),
+			Flag:                flag.code(comp.Flag),
+			Enabled:             comp.Enabled,
+			SwStatus:            comp.SwStatus,
+			Role:                role.code(comp.Role),
+			SubRole:             subRole.code(comp.SubRole),
+			NID:                 comp.NID,
+			Subtype:             comp.Subtype,
+			NetType:             netType.code(comp.NetType),
+			Arch:                arch.code(comp.Arch),
+			Class:               class.code(comp.Class),
+			ReservationDisabled: comp.ReservationDisabled,
+			Locked:              comp.Locked,
+		})
+	}
+	cca.Dictionary = make(CompactDictionary)
+	for field, c := range map[string]*compactCoder{
+		"Type":    &typ,
+		"State":   &state,
+		"Flag":    &flag,
+		"Role":    &role,
+		"SubRole": &subRole,
+		"NetType": &netType,
+		"Arch":    &arch,
+		"Class":   &class,
+	} {
+		if len(c.values) > 0 {
+			cca.Dictionary[field] = c.values
+		}
+	}
+	return cca
+}
+
+// Send a ComponentArray in its compact encoding.
+func sendCompactCompArray(w http.ResponseWriter, cca *CompactComponentArray) {
+	w.Header().Set("Content-Type", CompactMediaType)
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(cca); err != nil {
+		fmt.Printf("Couldn't encode JSON: %s\n", err)
+	}
+}
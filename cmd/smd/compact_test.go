@@ -0,0 +1,104 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+func TestAcceptsCompact(t *testing.T) {
+	tests := []struct {
+		accept string
+		exp    bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{CompactMediaType, true},
+		{"application/json;q=0.5, " + CompactMediaType, true},
+		{CompactMediaType + ";q=0.1", true},
+		{CompactMediaType + ";q=0", false},
+		{CompactMediaType + "; q=0.000", false},
+	}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET", "https://localhost/", nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		if got := acceptsCompact(req); got != test.exp {
+			t.Errorf("Test %v (%s): Expected %t, got %t", i, test.accept,
+				test.exp, got)
+		}
+	}
+}
+
+func TestCompactComponents(t *testing.T) {
+	enabled := true
+	results.GetComponentsFilter.Return.ids = []*base.Component{
+		{ID: "x0c0s14b0n0", Type: "Node", State: "On", Flag: "OK", Enabled: &enabled, Role: "Compute", NID: "448", Arch: "X86"},
+		{ID: "x0c0s15b0n0", Type: "Node", State: "Off", Flag: "OK", Role: "Compute", NID: "480", Arch: "X86"},
+		{ID: "x0c0s14b0", Type: "NodeBMC", State: "Ready", Flag: "Warning"},
+	}
+	results.GetComponentsFilter.Return.err = nil
+	defer func() { results.GetComponentsFilter.Return.ids = nil }()
+
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/State/Components", nil)
+	req.Header.Set("Accept", CompactMediaType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Response code was %v; want 200: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != CompactMediaType {
+		t.Errorf("Expected Content-Type %s, got %s", CompactMediaType, ct)
+	}
+	expected := `{"Dictionary":{"Arch":["X86"],"Flag":["OK","Warning"],` +
+		`"Role":["Compute"],"State":["On","Off","Ready"],"Type":["Node","NodeBMC"]},` +
+		`"Components":[` +
+		`{"ID":"x0c0s14b0n0","Type":0,"State":0,"Flag":0,"Enabled":true,"Role":0,"NID":448,"Arch":0},` +
+		`{"ID":"x0c0s15b0n0","Type":0,"State":1,"Flag":0,"Role":0,"NID":480,"Arch":0},` +
+		`{"ID":"x0c0s14b0","Type":1,"State":2,"Flag":1}]}`
+	var got, exp interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Bad response: %s", w.Body.String())
+	}
+	json.Unmarshal([]byte(expected), &exp)
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("Expected %s, got %s", expected, w.Body.String())
+	}
+
+	// Regular clients are unaffected
+	req.Header.Del("Accept")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var comps base.ComponentArray
+	if err := json.Unmarshal(w.Body.Bytes(), &comps); err != nil ||
+		len(comps.Components) != 3 || comps.Components[2].State != "Ready" {
+		t.Errorf("Unexpected regular response: %s", w.Body.String())
+	}
+}
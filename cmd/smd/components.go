@@ -73,6 +73,18 @@ func (s *SmD) DiscoverComponentArray(rfEP *rf.RedfishEP) (*base.ComponentArray,
 			}
 		}
 	}
+	for _, asicEP := range rfEP.HSNAsics.OIDs {
+		comp := s.DiscoverComponentHSNAsic(asicEP)
+		if comp != nil {
+			comps.Components = append(comps.Components, comp)
+			for _, portEP := range asicEP.Ports.OIDs {
+				cport := s.DiscoverComponentHSNLink(portEP)
+				if cport != nil {
+					comps.Components = append(comps.Components, cport)
+				}
+			}
+		}
+	}
 	return comps, nil
 }
 
@@ -336,7 +348,60 @@ func (s *SmD) DiscoverComponentOutlet(outEP *rf.EpOutlet) *base.Component {
 	return comp
 }
 
-// Get default NID and Role from SLS or the uploaded NodeMaps in priority order.
+// Use discovered data on a Redfish (not HMS) Switch or FabricAdapter type to
+// create an HMS HSNAsic Component representation.
+func (s *SmD) DiscoverComponentHSNAsic(asicEP *rf.EpHSNAsic) *base.Component {
+	if asicEP.LastStatus == rf.RedfishSubtypeNoSupport {
+		s.LogAlways("DiscoverComponentHSNAsic: EP: %s RF Subtype %s "+
+			"not supported.", asicEP.RfEndpointID, asicEP.RedfishSubtype)
+		return nil
+	} else if asicEP.LastStatus != rf.DiscoverOK {
+		s.LogAlways("DiscoverComponentHSNAsic: Saw EP with bad status: %s",
+			asicEP.LastStatus)
+		return nil
+	}
+	comp := new(base.Component)
+
+	comp.ID = asicEP.ID
+	comp.Type = asicEP.Type
+	comp.State = asicEP.State
+	comp.Flag = asicEP.Flag
+	comp.Subtype = asicEP.Subtype
+	comp.Arch = asicEP.Arch
+	comp.NetType = asicEP.NetType
+	comp.Class = asicEP.DefaultClass
+
+	return comp
+}
+
+// Use discovered data on a Redfish (not HMS) Port type to create
+// an HMS HSNLink Component representation.
+func (s *SmD) DiscoverComponentHSNLink(portEP *rf.EpHSNPort) *base.Component {
+	if portEP.LastStatus == rf.RedfishSubtypeNoSupport {
+		s.LogAlways("DiscoverComponentHSNLink: EP: %s RF Subtype %s "+
+			"not supported.", portEP.RfEndpointID, portEP.RedfishSubtype)
+		return nil
+	} else if portEP.LastStatus != rf.DiscoverOK {
+		s.LogAlways("DiscoverComponentHSNLink: Saw EP with bad status: %s",
+			portEP.LastStatus)
+		return nil
+	}
+	comp := new(base.Component)
+
+	comp.ID = portEP.ID
+	comp.Type = portEP.Type
+	comp.State = portEP.State
+	comp.Flag = portEP.Flag
+	comp.Subtype = portEP.Subtype
+	comp.Arch = portEP.Arch
+	comp.NetType = portEP.NetType
+	comp.Class = portEP.DefaultClass
+
+	return comp
+}
+
+// Get default NID and Role from SLS or the uploaded NodeMaps in priority order,
+// with the NID from the NID policies otherwise.
 func (s *SmD) GetCompDefaults(xname, defaultRole, defaultSubRole, defaultClass string) (uint64, string, string, string) {
 	var (
 		nid     uint64
@@ -373,7 +438,7 @@ func (s *SmD) GetCompDefaults(xname, defaultRole, defaultSubRole, defaultClass s
 		}
 	}
 	if nid == 0 {
-		nid = GetBogusNID(xname)
+		nid = s.policyNID(xname)
 	}
 	if len(role) == 0 {
 		role = defaultRole
@@ -0,0 +1,242 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+	"github.com/go-chi/chi/v5"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component-scoped tokens
+//
+// With SMD_COMPONENT_TOKEN_KEY set, a token for a single node or BMC can be
+// issued with POST /State/Components/{xname}/Token.  The token only lets
+// that component report on itself through the constrained
+// PATCH /State/Components/{xname}/SelfReport, e.g. so a node can mark
+// itself Ready once booted without being given write access to the rest
+// of the API.  Tokens are HS256 JWTs signed with the key and expire after
+// SMD_COMPONENT_TOKEN_TTL_SECS (default one day).  No other endpoint
+// accepts them, as those only take tokens from the JWKS issuer.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	compTokenIssuer   = "smd"
+	compTokenAudience = "smd-component-self-report"
+
+	// Shortest SMD_COMPONENT_TOKEN_KEY accepted, in bytes.
+	CompTokenKeyMinLen = 32
+
+	DefaultCompTokenTTL = 24 * time.Hour
+)
+
+// States a component may report for itself.  Anything else, e.g. Off or
+// Empty, must come from discovery or an administrator.
+var compSelfReportStates = map[string]bool{
+	base.StateReady.String(): true,
+	base.StateOn.String():    true,
+}
+
+// Flags a component may report for itself.  Locked, Alert and so on are
+// only set by HSM or an administrator.
+var compSelfReportFlags = map[string]bool{
+	base.FlagOK.String():      true,
+	base.FlagWarning.String(): true,
+}
+
+// Output of POST /State/Components/{xname}/Token
+type CompToken struct {
+	ID        string `json:"ID"`
+	Token     string `json:"Token"`
+	ExpiresAt string `json:"ExpiresAt"`
+}
+
+// Input of PATCH /State/Components/{xname}/SelfReport
+type CompSelfReportIn struct {
+	State          string  `json:"State,omitempty"`
+	Flag           string  `json:"Flag,omitempty"`
+	SoftwareStatus *string `json:"SoftwareStatus,omitempty"`
+}
+
+// Set up signing and verification of component tokens with the given key.
+func newCompTokenAuth(key []byte) (*jwtauth.JWTAuth, error) {
+	if len(key) < CompTokenKeyMinLen {
+		return nil, fmt.Errorf("key must be at least %d bytes",
+			CompTokenKeyMinLen)
+	}
+	return jwtauth.New("HS256", key, nil,
+		jwt.WithIssuer(compTokenIssuer),
+		jwt.WithAudience(compTokenAudience)), nil
+}
+
+// Issue a token that lets the given component report its own state.
+func (s *SmD) doCompTokenPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if s.compTokenAuth == nil {
+		sendJsonError(w, http.StatusServiceUnavailable,
+			"component tokens are not enabled")
+		return
+	}
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	hmsType := xnametypes.GetHMSType(xname)
+	if hmsType != xnametypes.Node && !xnametypes.IsHMSTypeController(hmsType) {
+		sendJsonError(w, http.StatusBadRequest,
+			"tokens can only be issued for nodes and BMCs")
+		return
+	}
+	comp, err := s.db.GetComponentByID(xname)
+	if err != nil {
+		s.LogAlways("doCompTokenPost(): Lookup failure: (%s) %s", xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if comp == nil {
+		sendJsonError(w, http.StatusNotFound, "no such xname.")
+		return
+	}
+	now := time.Now()
+	expires := now.Add(s.compTokenTTL)
+	claims := map[string]interface{}{
+		jwt.SubjectKey:  xname,
+		jwt.IssuerKey:   compTokenIssuer,
+		jwt.AudienceKey: compTokenAudience,
+	}
+	jwtauth.SetIssuedAt(claims, now)
+	jwtauth.SetExpiry(claims, expires)
+	_, token, err := s.compTokenAuth.Encode(claims)
+	if err != nil {
+		s.LogAlways("doCompTokenPost(): Can't sign token for %s: %s",
+			xname, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to sign token")
+		return
+	}
+	s.LogAlways("Issued component token for %s, expires %s", xname,
+		expires.UTC().Format(time.RFC3339))
+	sendJsonObject(w, http.StatusOK, CompToken{
+		ID:        xname,
+		Token:     token,
+		ExpiresAt: expires.UTC().Format(time.RFC3339),
+	})
+}
+
+// Let a component update its own State, Flag and SoftwareStatus using a
+// token issued for it by doCompTokenPost.
+func (s *SmD) doCompSelfReportPatch(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if s.compTokenAuth == nil {
+		sendJsonError(w, http.StatusServiceUnavailable,
+			"component tokens are not enabled")
+		return
+	}
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	token, err := jwtauth.VerifyToken(s.compTokenAuth,
+		jwtauth.TokenFromHeader(r))
+	if err != nil {
+		sendJsonError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if token.Subject() != xname {
+		sendJsonError(w, http.StatusForbidden,
+			"token was not issued for this xname")
+		return
+	}
+
+	var in CompSelfReportIn
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &in)
+	}
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if in.Flag != "" {
+		in.Flag = base.VerifyNormalizeFlag(in.Flag)
+		if !compSelfReportFlags[in.Flag] {
+			sendJsonError(w, http.StatusBadRequest,
+				"components may only report Flag OK or Warning")
+			return
+		}
+	}
+	var updates []*CompUpdate
+	if in.State != "" {
+		state := base.VerifyNormalizeState(in.State)
+		if !compSelfReportStates[state] {
+			sendJsonError(w, http.StatusBadRequest,
+				"components may only report State Ready or On")
+			return
+		}
+		updates = append(updates, &CompUpdate{
+			State:      state,
+			Flag:       in.Flag,
+			UpdateType: StateDataUpdate.String(),
+		})
+	} else if in.Flag != "" {
+		updates = append(updates, &CompUpdate{
+			Flag:       in.Flag,
+			UpdateType: FlagOnlyUpdate.String(),
+		})
+	}
+	if in.SoftwareStatus != nil {
+		updates = append(updates, &CompUpdate{
+			SwStatus:   in.SoftwareStatus,
+			UpdateType: SwStatusUpdate.String(),
+		})
+	}
+	if len(updates) == 0 {
+		sendJsonError(w, http.StatusBadRequest,
+			"no State, Flag or SoftwareStatus given")
+		return
+	}
+	for _, u := range updates {
+		u.ComponentIDs = []string{xname}
+		u.actor = xname
+		if err := s.doCompUpdate(u, "doCompSelfReportPatch"); err != nil {
+			if base.IsHMSError(err) {
+				sendJsonError(w, http.StatusBadRequest, err.Error())
+			} else {
+				sendJsonError(w, http.StatusBadRequest,
+					"operation 'SelfReport' failed for "+xname)
+			}
+			s.Log(LOG_INFO, "doCompSelfReportPatch(%s) failed: %s %s, Err: %s",
+				u.UpdateType, r.RemoteAddr, string(body), err)
+			return
+		}
+	}
+	s.Log(LOG_DEBUG, "doCompSelfReportPatch() succeeded: %s %s %s",
+		xname, r.RemoteAddr, string(body))
+	sendJsonError(w, http.StatusNoContent, "")
+}
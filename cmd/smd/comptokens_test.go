@@ -0,0 +1,138 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+func TestCompTokens(t *testing.T) {
+	if _, err := newCompTokenAuth([]byte("too-short")); err == nil {
+		t.Errorf("Expected an error for a short key")
+	}
+	ta, err := newCompTokenAuth([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer func() {
+		s.compTokenAuth = nil
+		results.GetComponentByID.Return.id = nil
+	}()
+	s.compTokenAuth = ta
+	s.compTokenTTL = DefaultCompTokenTTL
+	results.GetComponentByID.Return.err = nil
+	results.GetComponentByID.Return.id = &base.Component{
+		ID: "x0c0s27b0n0", State: base.StateOn.String()}
+
+	do := func(method, uri, token string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, uri, bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	const compURI = "https://localhost/hsm/v2/State/Components/"
+
+	// Issue
+	if w := do("POST", compURI+"x0c0/Token", "", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Chassis token: Response code was %v; want 400", w.Code)
+	}
+	w := do("POST", compURI+"x0c0s27b0n0/Token", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Issue: Response code was %v; want 200: %s", w.Code,
+			w.Body.String())
+	}
+	var ct CompToken
+	if err := json.Unmarshal(w.Body.Bytes(), &ct); err != nil || ct.Token == "" {
+		t.Fatalf("Bad token response: %s", w.Body.String())
+	}
+
+	// Tokens that must be refused
+	expired := map[string]interface{}{
+		jwt.SubjectKey:  "x0c0s27b0n0",
+		jwt.IssuerKey:   compTokenIssuer,
+		jwt.AudienceKey: compTokenAudience,
+	}
+	jwtauth.SetExpiry(expired, time.Now().Add(-time.Minute))
+	_, expiredToken, _ := ta.Encode(expired)
+	otherAud := map[string]interface{}{
+		jwt.SubjectKey:  "x0c0s27b0n0",
+		jwt.IssuerKey:   compTokenIssuer,
+		jwt.AudienceKey: "smd",
+	}
+	jwtauth.SetExpiryIn(otherAud, time.Hour)
+	_, otherAudToken, _ := ta.Encode(otherAud)
+
+	tests := []struct {
+		uri      string
+		token    string
+		body     string
+		expCode  int
+		expState string
+	}{
+		{compURI + "x0c0s27b0n0/SelfReport", "", `{"State":"Ready"}`, http.StatusUnauthorized, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", "garbage", `{"State":"Ready"}`, http.StatusUnauthorized, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", expiredToken, `{"State":"Ready"}`, http.StatusUnauthorized, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", otherAudToken, `{"State":"Ready"}`, http.StatusUnauthorized, ""},
+		{compURI + "x0c0s27b0n1/SelfReport", ct.Token, `{"State":"Ready"}`, http.StatusForbidden, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", ct.Token, `{"State":"Off"}`, http.StatusBadRequest, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", ct.Token, `{}`, http.StatusBadRequest, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", ct.Token, `{"State":"Ready","Flag":"Locked"}`, http.StatusBadRequest, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", ct.Token, `{"Flag":"Alert"}`, http.StatusBadRequest, ""},
+		{compURI + "x0c0s27b0n0/SelfReport", ct.Token, `{"State":"ready"}`, http.StatusNoContent, "Ready"},
+	}
+	for i, test := range tests {
+		results.UpdateCompStates.Input.ids = []string{}
+		results.UpdateCompStates.Input.state = ""
+		results.UpdateCompStates.Return.affectedIds = []string{"x0c0s27b0n0"}
+		results.UpdateCompStates.Return.err = nil
+
+		w := do("PATCH", test.uri, test.token, test.body)
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v: %s",
+				i, w.Code, test.expCode, w.Body.String())
+		}
+		if results.UpdateCompStates.Input.state != test.expState {
+			t.Errorf("Test %v Failed: Expected state '%s'; Received '%s'",
+				i, test.expState, results.UpdateCompStates.Input.state)
+		}
+	}
+
+	// Not enabled
+	s.compTokenAuth = nil
+	if w := do("PATCH", compURI+"x0c0s27b0n0/SelfReport", ct.Token,
+		`{"State":"Ready"}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Disabled: Response code was %v; want 503", w.Code)
+	}
+}
@@ -0,0 +1,249 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Inventory consistency checking
+//
+// Looks for drift between RedfishEndpoints, the ComponentEndpoints that
+// discovery created for them, and the Components table:
+//
+//   - ComponentEndpoints whose RedfishEndpoint no longer exists.
+//   - RedfishEndpoints that were discovered OK but have no
+//     ComponentEndpoints.
+//   - ComponentEndpoints whose Type doesn't match their xname, or the
+//     Component with the same xname.
+//
+// Checks can be run on demand via GET /Inventory/Consistency or
+// periodically (SMD_CONSISTENCY_CHECK_INTERVAL_SECS), which logs an alert
+// when problems are found.  Counters are kept across checks so that they
+// can be scraped for alerting.
+///////////////////////////////////////////////////////////////////////////////
+
+// One detected inconsistency.
+type ConsistencyIssue struct {
+	ID                string `json:"ID"`
+	RedfishEndpointID string `json:"RedfishEndpointID,omitempty"`
+	Detail            string `json:"Detail"`
+}
+
+// Running totals and the results of the most recent check.
+type ConsistencyCounters struct {
+	ChecksRun       uint64 `json:"ChecksRun"`
+	ChecksFailed    uint64 `json:"ChecksFailed"`
+	ChecksWithDrift uint64 `json:"ChecksWithDrift"`
+	LastCheckTime   string `json:"LastCheckTime,omitempty"`
+
+	OrphanedComponentEndpoints int `json:"OrphanedComponentEndpoints"`
+	EmptyRedfishEndpoints      int `json:"EmptyRedfishEndpoints"`
+	TypeMismatches             int `json:"TypeMismatches"`
+}
+
+// Output of GET /Inventory/Consistency
+type ConsistencyReport struct {
+	Timestamp                  string              `json:"Timestamp"`
+	Consistent                 bool                `json:"Consistent"`
+	OrphanedComponentEndpoints []ConsistencyIssue  `json:"OrphanedComponentEndpoints"`
+	EmptyRedfishEndpoints      []ConsistencyIssue  `json:"EmptyRedfishEndpoints"`
+	TypeMismatches             []ConsistencyIssue  `json:"TypeMismatches"`
+	Counters                   ConsistencyCounters `json:"Counters"`
+}
+
+type ConsistencyChecker struct {
+	lock     sync.Mutex
+	counters ConsistencyCounters
+}
+
+// Build a report from the current RedfishEndpoints, ComponentEndpoints and
+// Components.  Counters are not filled in.
+func NewConsistencyReport(
+	rfEPs []*sm.RedfishEndpoint,
+	ceps []*sm.ComponentEndpoint,
+	comps []*base.Component,
+	now time.Time,
+) *ConsistencyReport {
+	rpt := &ConsistencyReport{
+		Timestamp:                  now.UTC().Format(time.RFC3339),
+		OrphanedComponentEndpoints: []ConsistencyIssue{},
+		EmptyRedfishEndpoints:      []ConsistencyIssue{},
+		TypeMismatches:             []ConsistencyIssue{},
+	}
+	rfEPMap := make(map[string]*sm.RedfishEndpoint, len(rfEPs))
+	for _, ep := range rfEPs {
+		rfEPMap[ep.ID] = ep
+	}
+	compMap := make(map[string]*base.Component, len(comps))
+	for _, comp := range comps {
+		compMap[comp.ID] = comp
+	}
+	cepCounts := make(map[string]int, len(rfEPs))
+	for _, cep := range ceps {
+		if _, ok := rfEPMap[cep.RfEndpointID]; !ok {
+			rpt.OrphanedComponentEndpoints = append(rpt.OrphanedComponentEndpoints,
+				ConsistencyIssue{
+					ID:                cep.ID,
+					RedfishEndpointID: cep.RfEndpointID,
+					Detail:            "RedfishEndpoint does not exist",
+				})
+		} else {
+			cepCounts[cep.RfEndpointID]++
+		}
+		xnameType := xnametypes.GetHMSTypeString(cep.ID)
+		if xnameType != cep.Type {
+			rpt.TypeMismatches = append(rpt.TypeMismatches,
+				ConsistencyIssue{
+					ID:                cep.ID,
+					RedfishEndpointID: cep.RfEndpointID,
+					Detail: "ComponentEndpoint type " + cep.Type +
+						" does not match xname type " + xnameType,
+				})
+		} else if comp, ok := compMap[cep.ID]; ok && comp.Type != cep.Type {
+			rpt.TypeMismatches = append(rpt.TypeMismatches,
+				ConsistencyIssue{
+					ID:                cep.ID,
+					RedfishEndpointID: cep.RfEndpointID,
+					Detail: "ComponentEndpoint type " + cep.Type +
+						" does not match Component type " + comp.Type,
+				})
+		}
+	}
+	for _, ep := range rfEPs {
+		if ep.DiscInfo.LastStatus == rf.DiscoverOK && cepCounts[ep.ID] == 0 {
+			rpt.EmptyRedfishEndpoints = append(rpt.EmptyRedfishEndpoints,
+				ConsistencyIssue{
+					ID:     ep.ID,
+					Detail: "Discovered OK but has no ComponentEndpoints",
+				})
+		}
+	}
+	for _, issues := range [][]ConsistencyIssue{
+		rpt.OrphanedComponentEndpoints,
+		rpt.EmptyRedfishEndpoints,
+		rpt.TypeMismatches,
+	} {
+		sort.Slice(issues, func(i, j int) bool {
+			return issues[i].ID < issues[j].ID
+		})
+	}
+	rpt.Consistent = len(rpt.OrphanedComponentEndpoints) == 0 &&
+		len(rpt.EmptyRedfishEndpoints) == 0 &&
+		len(rpt.TypeMismatches) == 0
+	return rpt
+}
+
+// Record the outcome of a check.  rpt is nil if the check failed.
+func (c *ConsistencyChecker) record(rpt *ConsistencyReport, now time.Time) ConsistencyCounters {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.counters.ChecksRun++
+	c.counters.LastCheckTime = now.UTC().Format(time.RFC3339)
+	if rpt == nil {
+		c.counters.ChecksFailed++
+		return c.counters
+	}
+	if !rpt.Consistent {
+		c.counters.ChecksWithDrift++
+	}
+	c.counters.OrphanedComponentEndpoints = len(rpt.OrphanedComponentEndpoints)
+	c.counters.EmptyRedfishEndpoints = len(rpt.EmptyRedfishEndpoints)
+	c.counters.TypeMismatches = len(rpt.TypeMismatches)
+	return c.counters
+}
+
+// Run a consistency check against the database, update the counters and
+// log an alert if anything is amiss.
+func (s *SmD) checkConsistency() (*ConsistencyReport, error) {
+	rfEPs, ceps, comps, err := s.getConsistencyData()
+	if err != nil {
+		s.consistency.record(nil, time.Now())
+		return nil, err
+	}
+	now := time.Now()
+	rpt := NewConsistencyReport(rfEPs, ceps, comps, now)
+	rpt.Counters = s.consistency.record(rpt, now)
+	if !rpt.Consistent {
+		s.LogAlways("ALERT: Inventory inconsistent: %d orphaned "+
+			"ComponentEndpoints, %d empty RedfishEndpoints, %d type mismatches",
+			len(rpt.OrphanedComponentEndpoints),
+			len(rpt.EmptyRedfishEndpoints),
+			len(rpt.TypeMismatches))
+	}
+	return rpt, nil
+}
+
+func (s *SmD) getConsistencyData() ([]*sm.RedfishEndpoint, []*sm.ComponentEndpoint, []*base.Component, error) {
+	rfEPs, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ceps, err := s.db.GetCompEndpointsAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	comps, err := s.db.GetComponentsAll()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return rfEPs, ceps, comps, nil
+}
+
+// Run checkConsistency() every interval, forever, while the leader.
+func (s *SmD) StartConsistencyChecker(interval time.Duration) {
+	go func() {
+		for {
+			time.Sleep(interval)
+			if !s.cluster.isLeader() {
+				continue
+			}
+			if _, err := s.checkConsistency(); err != nil {
+				s.LogAlways("Consistency check failed: %s", err)
+			}
+		}
+	}()
+}
+
+// Get a consistency report for RedfishEndpoints and ComponentEndpoints
+func (s *SmD) doConsistencyGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	rpt, err := s.checkConsistency()
+	if err != nil {
+		s.lg.Printf("doConsistencyGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, rpt)
+}
@@ -0,0 +1,225 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func consistencyTestRFEP(id, status string) *sm.RedfishEndpoint {
+	ep := new(sm.RedfishEndpoint)
+	ep.ID = id
+	ep.Type = "NodeBMC"
+	ep.DiscInfo.LastStatus = status
+	return ep
+}
+
+func consistencyTestCompEP(id, cType, rfEPID string) *sm.ComponentEndpoint {
+	cep := new(sm.ComponentEndpoint)
+	cep.ID = id
+	cep.Type = cType
+	cep.RfEndpointID = rfEPID
+	return cep
+}
+
+func TestNewConsistencyReport(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		rfEPs       []*sm.RedfishEndpoint
+		ceps        []*sm.ComponentEndpoint
+		comps       []*base.Component
+		expOrphaned []ConsistencyIssue
+		expEmpty    []ConsistencyIssue
+		expMismatch []ConsistencyIssue
+	}{{
+		// Everything consistent
+		rfEPs: []*sm.RedfishEndpoint{
+			consistencyTestRFEP("x0c0s0b0", rf.DiscoverOK),
+		},
+		ceps: []*sm.ComponentEndpoint{
+			consistencyTestCompEP("x0c0s0b0", "NodeBMC", "x0c0s0b0"),
+			consistencyTestCompEP("x0c0s0b0n0", "Node", "x0c0s0b0"),
+		},
+		comps: []*base.Component{
+			{ID: "x0c0s0b0n0", Type: "Node"},
+		},
+		expOrphaned: []ConsistencyIssue{},
+		expEmpty:    []ConsistencyIssue{},
+		expMismatch: []ConsistencyIssue{},
+	}, {
+		// One of each problem.  x0c0s2b0 hasn't been discovered OK so
+		// having no ComponentEndpoints is expected.
+		rfEPs: []*sm.RedfishEndpoint{
+			consistencyTestRFEP("x0c0s0b0", rf.DiscoverOK),
+			consistencyTestRFEP("x0c0s1b0", rf.DiscoverOK),
+			consistencyTestRFEP("x0c0s2b0", rf.HTTPsGetFailed),
+		},
+		ceps: []*sm.ComponentEndpoint{
+			consistencyTestCompEP("x0c0s0b0", "NodeBMC", "x0c0s0b0"),
+			consistencyTestCompEP("x0c0s0b0n0", "Node", "x0c0s0b0"),
+			consistencyTestCompEP("x0c0s0b0n1", "NodeBMC", "x0c0s0b0"),
+			consistencyTestCompEP("x0c0s3b0n0", "Node", "x0c0s3b0"),
+		},
+		comps: []*base.Component{
+			{ID: "x0c0s0b0n0", Type: "NodeEnclosure"},
+		},
+		expOrphaned: []ConsistencyIssue{{
+			ID:                "x0c0s3b0n0",
+			RedfishEndpointID: "x0c0s3b0",
+			Detail:            "RedfishEndpoint does not exist",
+		}},
+		expEmpty: []ConsistencyIssue{{
+			ID:     "x0c0s1b0",
+			Detail: "Discovered OK but has no ComponentEndpoints",
+		}},
+		expMismatch: []ConsistencyIssue{{
+			ID:                "x0c0s0b0n0",
+			RedfishEndpointID: "x0c0s0b0",
+			Detail:            "ComponentEndpoint type Node does not match Component type NodeEnclosure",
+		}, {
+			ID:                "x0c0s0b0n1",
+			RedfishEndpointID: "x0c0s0b0",
+			Detail:            "ComponentEndpoint type NodeBMC does not match xname type Node",
+		}},
+	}}
+
+	for i, test := range tests {
+		rpt := NewConsistencyReport(test.rfEPs, test.ceps, test.comps, now)
+		if rpt.Timestamp != "2025-01-02T03:04:05Z" {
+			t.Errorf("Test %v Failed: Bad Timestamp '%s'", i, rpt.Timestamp)
+		}
+		if !reflect.DeepEqual(test.expOrphaned, rpt.OrphanedComponentEndpoints) {
+			t.Errorf("Test %v Failed: Expected orphaned '%v'; Received '%v'",
+				i, test.expOrphaned, rpt.OrphanedComponentEndpoints)
+		}
+		if !reflect.DeepEqual(test.expEmpty, rpt.EmptyRedfishEndpoints) {
+			t.Errorf("Test %v Failed: Expected empty '%v'; Received '%v'",
+				i, test.expEmpty, rpt.EmptyRedfishEndpoints)
+		}
+		if !reflect.DeepEqual(test.expMismatch, rpt.TypeMismatches) {
+			t.Errorf("Test %v Failed: Expected mismatches '%v'; Received '%v'",
+				i, test.expMismatch, rpt.TypeMismatches)
+		}
+		expConsistent := len(test.expOrphaned)+len(test.expEmpty)+len(test.expMismatch) == 0
+		if rpt.Consistent != expConsistent {
+			t.Errorf("Test %v Failed: Expected Consistent %v", i, expConsistent)
+		}
+	}
+}
+
+func TestDoConsistencyGet(t *testing.T) {
+	defer func() {
+		results.GetRFEndpointsAll.Return.entries = nil
+		results.GetRFEndpointsAll.Return.err = nil
+		results.GetCompEndpointsAll.Return.entries = nil
+		results.GetCompEndpointsAll.Return.err = nil
+		results.GetComponentsAll.Return.ids = nil
+		results.GetComponentsAll.Return.err = nil
+		s.consistency = ConsistencyChecker{}
+	}()
+	s.consistency = ConsistencyChecker{}
+
+	tests := []struct {
+		rfEPs       []*sm.RedfishEndpoint
+		ceps        []*sm.ComponentEndpoint
+		hmsdsErr    error
+		expCode     int
+		expCounters ConsistencyCounters
+	}{{
+		rfEPs: []*sm.RedfishEndpoint{
+			consistencyTestRFEP("x0c0s0b0", rf.DiscoverOK),
+		},
+		ceps: []*sm.ComponentEndpoint{
+			consistencyTestCompEP("x0c0s0b0n0", "Node", "x0c0s0b0"),
+		},
+		hmsdsErr: nil,
+		expCode:  http.StatusOK,
+		expCounters: ConsistencyCounters{
+			ChecksRun: 1,
+		},
+	}, {
+		rfEPs: []*sm.RedfishEndpoint{
+			consistencyTestRFEP("x0c0s0b0", rf.DiscoverOK),
+		},
+		ceps:     []*sm.ComponentEndpoint{},
+		hmsdsErr: nil,
+		expCode:  http.StatusOK,
+		expCounters: ConsistencyCounters{
+			ChecksRun:             2,
+			ChecksWithDrift:       1,
+			EmptyRedfishEndpoints: 1,
+		},
+	}, {
+		rfEPs:    nil,
+		ceps:     nil,
+		hmsdsErr: errors.New("DB is down"),
+		expCode:  http.StatusInternalServerError,
+	}}
+
+	for i, test := range tests {
+		results.GetRFEndpointsAll.Return.entries = test.rfEPs
+		results.GetRFEndpointsAll.Return.err = nil
+		results.GetCompEndpointsAll.Return.entries = test.ceps
+		results.GetCompEndpointsAll.Return.err = test.hmsdsErr
+		results.GetComponentsAll.Return.ids = []*base.Component{}
+		results.GetComponentsAll.Return.err = nil
+
+		req, err := http.NewRequest("GET", "https://localhost/hsm/v2/Inventory/Consistency", nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expCode)
+			continue
+		}
+		if test.expCode != http.StatusOK {
+			continue
+		}
+		var rpt ConsistencyReport
+		if err := json.Unmarshal(w.Body.Bytes(), &rpt); err != nil {
+			t.Errorf("Test %v Failed: Bad response body: %s", i, err)
+			continue
+		}
+		rpt.Counters.LastCheckTime = ""
+		if !reflect.DeepEqual(test.expCounters, rpt.Counters) {
+			t.Errorf("Test %v Failed: Expected counters '%+v'; Received '%+v'",
+				i, test.expCounters, rpt.Counters)
+		}
+	}
+	if s.consistency.counters.ChecksFailed != 1 {
+		t.Errorf("Expected 1 failed check, got %d", s.consistency.counters.ChecksFailed)
+	}
+}
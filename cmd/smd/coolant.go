@@ -0,0 +1,358 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Liquid-cooling faults
+//
+// EX node and chassis controllers report coolant leaks and coolant faults
+// (pump, flow, pressure) either as CrayAlerts events or as leak/coolant
+// sensor readings in their MetricReports.  Each active fault is tracked in
+// memory per component and sensor, and is reflected in the component's Flag:
+// Alert while any leak is active, Warning while only coolant faults are, and
+// back to OK once the last fault on the component clears.  Flag changes are
+// sent as SCNs together with the component's current State, since a Flag
+// alone is not an SCN trigger.
+//
+// GET /State/CoolingFaults[/{xname}] reports the active faults.
+///////////////////////////////////////////////////////////////////////////////
+
+// Kinds of cooling fault
+const (
+	CoolingFaultLeak    = "Leak"
+	CoolingFaultCoolant = "Coolant"
+)
+
+// Where a cooling fault was reported
+const (
+	CoolingFaultSourceEvent     = "Event"
+	CoolingFaultSourceTelemetry = "Telemetry"
+)
+
+// One active cooling fault on a component.
+type CoolingFault struct {
+	ID                string `json:"ID"`
+	Kind              string `json:"Kind"`
+	Flag              string `json:"Flag"`
+	Sensor            string `json:"Sensor"`
+	Source            string `json:"Source"`
+	Detail            string `json:"Detail,omitempty"`
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+	Since             string `json:"Since"`
+}
+
+// Output of GET /State/CoolingFaults[/{xname}]
+type CoolingFaultArray struct {
+	CoolingFaults []CoolingFault `json:"CoolingFaults"`
+}
+
+type CoolingFaultTracker struct {
+	lock   sync.Mutex
+	faults map[string]map[string]*CoolingFault // by xname, sensor
+}
+
+// Flag a component should have for a kind of cooling fault
+func coolingFaultFlag(kind string) string {
+	if kind == CoolingFaultLeak {
+		return base.FlagAlert.String()
+	}
+	return base.FlagWarning.String()
+}
+
+// Flag for the active faults of a component, or "" if there are none.
+func coolingFaultsFlag(faults map[string]*CoolingFault) string {
+	flag := ""
+	for _, f := range faults {
+		if f.Kind == CoolingFaultLeak {
+			return base.FlagAlert.String()
+		}
+		flag = base.FlagWarning.String()
+	}
+	return flag
+}
+
+// Record a fault (or its clearing if active is false).  Returns the flag the
+// component had before and should have after, "" meaning no cooling fault.
+func (ct *CoolingFaultTracker) update(f CoolingFault, active bool, now time.Time) (oldFlag, newFlag string) {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+
+	if ct.faults == nil {
+		ct.faults = make(map[string]map[string]*CoolingFault)
+	}
+	faults := ct.faults[f.ID]
+	oldFlag = coolingFaultsFlag(faults)
+	if active {
+		if faults == nil {
+			faults = make(map[string]*CoolingFault)
+			ct.faults[f.ID] = faults
+		}
+		if old, ok := faults[f.Sensor]; ok && old.Kind == f.Kind {
+			f.Since = old.Since
+		} else {
+			f.Since = now.UTC().Format(time.RFC3339)
+		}
+		f.Flag = coolingFaultFlag(f.Kind)
+		faults[f.Sensor] = &f
+	} else if faults != nil {
+		delete(faults, f.Sensor)
+		if len(faults) == 0 {
+			delete(ct.faults, f.ID)
+		}
+	}
+	return oldFlag, coolingFaultsFlag(ct.faults[f.ID])
+}
+
+// Active faults, for one xname only if xname is not "", sorted by xname and
+// sensor.
+func (ct *CoolingFaultTracker) active(xname string) []CoolingFault {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+
+	faults := []CoolingFault{}
+	for id, byID := range ct.faults {
+		if xname != "" && id != xname {
+			continue
+		}
+		for _, f := range byID {
+			faults = append(faults, *f)
+		}
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		if faults[i].ID != faults[j].ID {
+			return faults[i].ID < faults[j].ID
+		}
+		return faults[i].Sensor < faults[j].Sensor
+	})
+	return faults
+}
+
+// Flag for the active faults of xname, or "" if there are none.
+func (ct *CoolingFaultTracker) flag(xname string) string {
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+
+	return coolingFaultsFlag(ct.faults[xname])
+}
+
+// Record that a cooling fault was raised or cleared and update the Flag of
+// the component if needed.  Hardware faults (see hwevents.go) on the same
+// component count towards the Flag too.
+func (s *SmD) setCoolingFault(f CoolingFault, active bool) error {
+	oldFlag, newFlag := s.coolingFaults.update(f, active, time.Now())
+	other := s.hwFaults.flag(f.ID)
+	oldFlag = worseFaultFlag(oldFlag, other)
+	newFlag = worseFaultFlag(newFlag, other)
+	if oldFlag == newFlag {
+		return nil
+	}
+	if active {
+		s.LogAlways("Cooling fault on %s: %s %s (%s)", f.ID, f.Kind, f.Sensor,
+			f.Detail)
+	} else {
+		s.LogAlways("Cooling fault cleared on %s: %s %s", f.ID, f.Kind, f.Sensor)
+	}
+	return s.setFaultFlag(f.ID, newFlag)
+}
+
+// Set the Flag of a component for its faults, "" meaning it has none, and
+// send an SCN.
+func (s *SmD) setFaultFlag(xname, flag string) error {
+	if flag == "" {
+		flag = base.FlagOK.String()
+	}
+	comp, err := s.db.GetComponentByID(xname)
+	if err != nil {
+		return err
+	} else if comp == nil {
+		return ErrSMDBadID
+	}
+	scnIDs, err := s.dbUpdateCompFlagOnly(
+		s.db.WithActor(CompHistActorCoolingFault), []string{xname}, flag,
+		new(hmsds.PartInfo))
+	if err != nil {
+		return err
+	}
+	if len(scnIDs) != 0 {
+		scn := NewJobSCN(scnIDs, base.Component{
+			State: comp.State,
+			Flag:  flag,
+		}, s)
+		s.wp.Queue(scn)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Events
+/////////////////////////////////////////////////////////////////////////////
+
+// EventActionParser - CrayAlerts leak and coolant fault events.  The
+//
+//	sensor is in the first MessageArg if there is one, and the
+//	component in OriginOfCondition.  The Flag is set here, so no
+//	CompUpdate is returned.
+func CoolingFaultParser(s *SmD, pe *processedRFEvent) (*CompUpdate, error) {
+	kind := CoolingFaultCoolant
+	id := strings.ToLower(pe.MessageId)
+	if strings.HasPrefix(id, "leak") {
+		kind = CoolingFaultLeak
+	}
+	active := !strings.HasSuffix(id, "cleared")
+
+	xname := ""
+	if pe.Origin != "" {
+		var err error
+		xname, err = s.getIDForURI(pe.RfEndppointID, pe.Origin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if xname == "" {
+		xname = pe.RfEndppointID
+	}
+	sensor := pe.Origin
+	if len(pe.MessageArgs) > 0 && pe.MessageArgs[0] != "" {
+		sensor = pe.MessageArgs[0]
+	}
+	err := s.setCoolingFault(CoolingFault{
+		ID:                xname,
+		Kind:              kind,
+		Sensor:            sensor,
+		Source:            CoolingFaultSourceEvent,
+		Detail:            pe.Message,
+		RedfishEndpointID: pe.RfEndppointID,
+	}, active)
+	return nil, err
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Telemetry
+/////////////////////////////////////////////////////////////////////////////
+
+// Readings of leak/coolant status sensors that mean there is no fault.
+var coolingSensorOK = map[string]bool{
+	"":        true,
+	"0":       true,
+	"false":   true,
+	"ok":      true,
+	"normal":  true,
+	"absent":  true,
+	"none":    true,
+	"cleared": true,
+}
+
+// Kind of cooling fault a metric reports, or "" if it is not a leak or
+// coolant status sensor.  Coolant readings (temperatures, flow rates) are
+// not faults in themselves and are left alone.
+func coolingSensorKind(mv *rf.MetricValue) string {
+	str := strings.ToLower(mv.MetricProperty + " " + mv.MetricId)
+	if strings.Contains(str, "leak") {
+		return CoolingFaultLeak
+	}
+	if strings.Contains(str, "coolant") {
+		for _, kw := range []string{"fault", "alarm", "status"} {
+			if strings.Contains(str, kw) {
+				return CoolingFaultCoolant
+			}
+		}
+	}
+	return ""
+}
+
+// Raise or clear cooling faults for the leak and coolant sensors in a
+// MetricReport from the given RedfishEndpoint.
+func (s *SmD) coolingFaultsFromReport(epID string, targets []telemetryTarget, rpt *rf.MetricReport) {
+	for i := range rpt.MetricValues {
+		mv := &rpt.MetricValues[i]
+		kind := coolingSensorKind(mv)
+		if kind == "" {
+			continue
+		}
+		xname := telemetryXName(targets, mv.MetricProperty)
+		if xname == "" {
+			continue
+		}
+		sensor := mv.MetricProperty
+		if sensor == "" {
+			sensor = mv.MetricId
+		}
+		value := strings.ToLower(strings.TrimSpace(mv.MetricValue))
+		err := s.setCoolingFault(CoolingFault{
+			ID:                xname,
+			Kind:              kind,
+			Sensor:            sensor,
+			Source:            CoolingFaultSourceTelemetry,
+			Detail:            mv.MetricId + "=" + mv.MetricValue,
+			RedfishEndpointID: epID,
+		}, !coolingSensorOK[value])
+		if err != nil {
+			s.LogAlways("Failed to update cooling fault flag for %s: %s",
+				xname, err)
+		}
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// API
+/////////////////////////////////////////////////////////////////////////////
+
+// Get all active cooling faults
+func (s *SmD) doCoolingFaultsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, CoolingFaultArray{
+		CoolingFaults: s.coolingFaults.active(""),
+	})
+}
+
+// Get the active cooling faults of one component
+func (s *SmD) doCoolingFaultGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !xnametypes.IsHMSCompIDValid(xname) {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname")
+		return
+	}
+	faults := s.coolingFaults.active(xname)
+	if len(faults) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no cooling faults for this xname")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, CoolingFaultArray{CoolingFaults: faults})
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+const testPayloadLeakReport = `{
+	"Id": "CoolingMetrics",
+	"Context": "secret",
+	"Timestamp": "2026-10-17T10:00:00Z",
+	"MetricValues": [{
+		"MetricId": "LeakDetector0",
+		"MetricProperty": "/redfish/v1/Chassis/Enclosure/LeakDetection/LeakDetectors/0#/DetectorState",
+		"MetricValue": "%s"
+	}, {
+		"MetricId": "CoolantTemp",
+		"MetricProperty": "/redfish/v1/Chassis/Enclosure/Coolant#/TemperatureCelsius",
+		"MetricValue": "31"
+	}]
+}`
+
+func TestCoolingFaults(t *testing.T) {
+	defer func() {
+		results.GetCompEndpointsFilter.Return.entries = nil
+		results.GetComponentByID.Return.id = nil
+		results.UpdateCompFlagOnly.Return.rowsAffected = 0
+		s.telemetryCtx = ""
+		s.telemetry = TelemetryStore{}
+		s.coolingFaults = CoolingFaultTracker{}
+	}()
+	s.telemetryCtx = "secret"
+	results.GetCompEndpointsFilter.Return.entries = []*sm.ComponentEndpoint{
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x1000c0s3e0", OdataID: "/redfish/v1/Chassis/Enclosure"}},
+	}
+	results.GetComponentByID.Return.id = &base.Component{
+		ID: "x1000c0s3e0", State: base.StateOn.String()}
+	results.UpdateCompFlagOnly.Return.rowsAffected = 1
+
+	post := func(value string) {
+		results.UpdateCompFlagOnly.Input.flag = ""
+		body := bytes.NewBufferString(
+			fmt.Sprintf(testPayloadLeakReport, value))
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Telemetry/MetricReports/x1000c0s3b0", body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("POST failed: %d %s", w.Code, w.Body.String())
+		}
+	}
+	get := func(uri string, expCode int) []CoolingFault {
+		req, _ := http.NewRequest("GET", uri, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != expCode {
+			t.Fatalf("GET %s: Response code was %v; want %v",
+				uri, w.Code, expCode)
+		}
+		var faults CoolingFaultArray
+		json.Unmarshal(w.Body.Bytes(), &faults)
+		return faults.CoolingFaults
+	}
+
+	// Healthy reading - nothing happens
+	post("OK")
+	if results.UpdateCompFlagOnly.Input.flag != "" {
+		t.Errorf("Unexpected flag update: %s",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+	if faults := get("https://localhost/hsm/v2/State/CoolingFaults", http.StatusOK); len(faults) != 0 {
+		t.Errorf("Unexpected cooling faults: %v", faults)
+	}
+
+	// Leak - Alert
+	post("Critical")
+	if results.UpdateCompFlagOnly.Input.id != "x1000c0s3e0" ||
+		results.UpdateCompFlagOnly.Input.flag != base.FlagAlert.String() {
+		t.Errorf("Expected Alert on x1000c0s3e0, got %s on %s",
+			results.UpdateCompFlagOnly.Input.flag,
+			results.UpdateCompFlagOnly.Input.id)
+	}
+	faults := get("https://localhost/hsm/v2/State/CoolingFaults/x1000c0s3e0", http.StatusOK)
+	if len(faults) != 1 || faults[0].Kind != CoolingFaultLeak ||
+		faults[0].Source != CoolingFaultSourceTelemetry ||
+		faults[0].RedfishEndpointID != "x1000c0s3b0" {
+		t.Errorf("Unexpected cooling faults: %v", faults)
+	}
+
+	// Still leaking - no new update
+	post("Critical")
+	if results.UpdateCompFlagOnly.Input.flag != "" {
+		t.Errorf("Unexpected flag update: %s",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+
+	// Coolant fault event on the same component - leak still wins
+	results.UpdateCompFlagOnly.Input.flag = ""
+	pe := &processedRFEvent{
+		MessageId:     "CoolantFault",
+		Registry:      "CrayAlerts",
+		RfEndppointID: "x1000c0s3e0",
+		Message:       "Coolant pump 1 failed",
+		MessageArgs:   []string{"Pump1"},
+	}
+	if parser := s.GetEventActionParser(pe); parser == nil {
+		t.Fatalf("No parser for CrayAlerts CoolantFault")
+	} else if u, err := parser(s, pe); u != nil || err != nil {
+		t.Fatalf("Unexpected parser result: %v %v", u, err)
+	}
+	if results.UpdateCompFlagOnly.Input.flag != "" {
+		t.Errorf("Unexpected flag update: %s",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+
+	// Leak clears - Warning for the coolant fault
+	post("0")
+	if results.UpdateCompFlagOnly.Input.flag != base.FlagWarning.String() {
+		t.Errorf("Expected Warning, got '%s'",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+
+	// Coolant fault clears - OK
+	pe.MessageId = "CoolantFaultCleared"
+	if parser := s.GetEventActionParser(pe); parser == nil {
+		t.Fatalf("No parser for CrayAlerts CoolantFaultCleared")
+	} else if _, err := parser(s, pe); err != nil {
+		t.Fatalf("Unexpected parser error: %v", err)
+	}
+	if results.UpdateCompFlagOnly.Input.flag != base.FlagOK.String() {
+		t.Errorf("Expected OK, got '%s'", results.UpdateCompFlagOnly.Input.flag)
+	}
+	get("https://localhost/hsm/v2/State/CoolingFaults/x1000c0s3e0", http.StatusNotFound)
+
+	// Only CrayAlerts is trusted for these
+	pe.Registry = "ResourceEvent"
+	if parser := s.GetEventActionParser(pe); parser != nil {
+		t.Errorf("Unexpected parser for ResourceEvent CoolantFaultCleared")
+	}
+}
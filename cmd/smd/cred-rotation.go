@@ -0,0 +1,508 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// BMC credential rotation
+//
+//     POST /Inventory/Credentials/Actions/Rotate
+//
+// changes the password of the account HSM uses on a list of
+// RedfishEndpoints, the members of groups and/or every RedfishEndpoint of
+// some types.  The new password is given, for all of them or per endpoint,
+// or else a random one is generated for each.  For each endpoint:
+//
+//     1. The account with the endpoint's username is found in its
+//        AccountService.
+//     2. Its password is PATCHed.
+//     3. The BMC is logged in to with the new password.
+//     4. The new password is stored in the credential store, for the
+//        endpoint and for each of its discovered components.
+//
+// If 3 or 4 fails, the old password is put back on the BMC and in the
+// store, so the endpoint is left as it was (Status RolledBack).  If that
+// fails too (Status RollbackFailed) the BMC may be left with a password
+// that is not stored and needs attention.  Only the results are returned,
+// never the passwords.  The latest result for each endpoint is kept in
+// memory for GET /Inventory/Credentials/Status.
+//
+// This needs the credentials to be kept in a credential store (SMD_WVAULT).
+///////////////////////////////////////////////////////////////////////////////
+
+// Result of a credential rotation on an endpoint
+const (
+	CredRotateSucceeded      = "Succeeded"
+	CredRotateFailed         = "Failed"
+	CredRotateRolledBack     = "RolledBack"
+	CredRotateRollbackFailed = "RollbackFailed"
+)
+
+// Steps of a credential rotation, as given for one that failed
+const (
+	CredRotateStepConnect     = "Connect"
+	CredRotateStepFindAccount = "FindAccount"
+	CredRotateStepSetPassword = "SetPassword"
+	CredRotateStepVerifyLogin = "VerifyLogin"
+	CredRotateStepStore       = "Store"
+)
+
+// Lengths of generated passwords
+const (
+	credRotatePasswordLenDefault = 16
+	credRotatePasswordLenMin     = 8
+	credRotatePasswordLenMax     = 64
+)
+
+// Characters of generated passwords.  No symbols and nothing that is easily
+// mistaken for something else, since BMCs differ in what they accept.
+const credRotatePasswordChars = "ABCDEFGHJKLMNPQRSTUVWXYZ" +
+	"abcdefghijkmnopqrstuvwxyz" + "23456789"
+
+// How many endpoints are worked on at once.
+const credRotateFanout = 64
+
+var errCredRotateNoStore = errors.New(
+	"BMC credentials are not kept in a credential store (SMD_WVAULT)")
+var errCredRotateNoCreds = errors.New(
+	"no current credentials for this RedfishEndpoint")
+var errCredRotateBusy = errors.New(
+	"a credential rotation is already in progress for this RedfishEndpoint")
+
+// Input of POST /Inventory/Credentials/Actions/Rotate.  Endpoints listed
+// in Passwords get their own, and are added to the targets.  Password (if
+// given) is used for the rest, otherwise each gets a random password of
+// PasswordLength characters.
+type CredRotateIn struct {
+	RFEndpointTargets
+	Types          []string          `json:"Types"`
+	Password       string            `json:"Password,omitempty"`
+	Passwords      map[string]string `json:"Passwords,omitempty"`
+	PasswordLength int               `json:"PasswordLength,omitempty"`
+}
+
+// Result of a credential rotation on one RedfishEndpoint.  Step is where
+// it failed, if it did.
+type CredRotateResult struct {
+	ID        string `json:"ID"`
+	Username  string `json:"Username,omitempty"`
+	Status    string `json:"Status"`
+	Step      string `json:"Step,omitempty"`
+	Error     string `json:"Error,omitempty"`
+	Timestamp string `json:"Timestamp"`
+}
+
+type CredRotateCounts struct {
+	Total          int `json:"Total"`
+	Succeeded      int `json:"Succeeded"`
+	Failed         int `json:"Failed"`
+	RolledBack     int `json:"RolledBack"`
+	RollbackFailed int `json:"RollbackFailed"`
+}
+
+// Output of the rotation and of GET /Inventory/Credentials/Status
+type CredRotateResults struct {
+	Counts  CredRotateCounts    `json:"Counts"`
+	Results []*CredRotateResult `json:"Results"`
+}
+
+// Latest credential rotation result for each RedfishEndpoint, and those
+// being rotated.
+type CredRotateStatusStore struct {
+	lock    sync.Mutex
+	results map[string]*CredRotateResult
+	busy    map[string]bool
+}
+
+// Mark id as being rotated, unless it already is.
+func (cs *CredRotateStatusStore) start(id string) bool {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if cs.busy == nil {
+		cs.busy = make(map[string]bool)
+	}
+	if cs.busy[id] {
+		return false
+	}
+	cs.busy[id] = true
+	return true
+}
+
+// Record the result of a rotation started with start().
+func (cs *CredRotateStatusStore) done(res *CredRotateResult) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	if cs.results == nil {
+		cs.results = make(map[string]*CredRotateResult)
+	}
+	cs.results[res.ID] = res
+	delete(cs.busy, res.ID)
+}
+
+// Latest results for ids, or for every endpoint if there are none.
+func (cs *CredRotateStatusStore) get(ids []string) []*CredRotateResult {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+	results := []*CredRotateResult{}
+	if len(ids) == 0 {
+		for _, res := range cs.results {
+			results = append(results, res)
+		}
+	} else {
+		for _, id := range ids {
+			if res, ok := cs.results[id]; ok {
+				results = append(results, res)
+			}
+		}
+	}
+	return results
+}
+
+func newCredRotateResults(results []*CredRotateResult) *CredRotateResults {
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	out := &CredRotateResults{Results: results}
+	for _, res := range results {
+		out.Counts.Total++
+		switch res.Status {
+		case CredRotateSucceeded:
+			out.Counts.Succeeded++
+		case CredRotateRolledBack:
+			out.Counts.RolledBack++
+		case CredRotateRollbackFailed:
+			out.Counts.RollbackFailed++
+		default:
+			out.Counts.Failed++
+		}
+	}
+	return out
+}
+
+// Generate a random password of n characters, with upper and lower case
+// letters and digits, which most BMCs insist on.
+func generateBMCPassword(n int) (string, error) {
+	max := big.NewInt(int64(len(credRotatePasswordChars)))
+	pw := make([]byte, n)
+	for {
+		for i := range pw {
+			j, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			pw[i] = credRotatePasswordChars[j.Int64()]
+		}
+		str := string(pw)
+		if strings.ContainsAny(str, "ABCDEFGHJKLMNPQRSTUVWXYZ") &&
+			strings.ContainsAny(str, "abcdefghijkmnopqrstuvwxyz") &&
+			strings.ContainsAny(str, "23456789") {
+			return str, nil
+		}
+	}
+}
+
+// Rotate the credentials of the RedfishEndpoints ids to those from
+// password, and record the results.
+func (s *SmD) doCredRotate(ids []string, password func(id string) (string, error)) (*CredRotateResults, error) {
+	eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{ID: ids})
+	if err != nil {
+		return nil, err
+	}
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{RfEndpointID: ids})
+	if err != nil {
+		return nil, err
+	}
+	epMap := make(map[string]*sm.RedfishEndpoint, len(eps))
+	for _, ep := range eps {
+		epMap[ep.ID] = ep
+	}
+	cepMap := make(map[string][]*sm.ComponentEndpoint)
+	for _, cep := range ceps {
+		cepMap[cep.RfEndpointID] = append(cepMap[cep.RfEndpointID], cep)
+	}
+
+	results := make([]*CredRotateResult, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, credRotateFanout)
+	for i, id := range ids {
+		res := &CredRotateResult{ID: id}
+		results[i] = res
+		if !s.credRotateStatus.start(id) {
+			res.Status = CredRotateFailed
+			res.Step = CredRotateStepConnect
+			res.Error = errCredRotateBusy.Error()
+			res.Timestamp = time.Now().UTC().Format(time.RFC3339)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			s.credRotateOne(epMap[id], cepMap[id], password, res)
+			res.Timestamp = time.Now().UTC().Format(time.RFC3339)
+			if res.Status != CredRotateSucceeded {
+				s.LogAlways("Credential rotation %s for %s at %s: %s",
+					res.Status, id, res.Step, res.Error)
+			} else {
+				s.LogAlways("Credential rotation succeeded for %s (%s)",
+					id, res.Username)
+			}
+			s.credRotateStatus.done(res)
+		}()
+	}
+	wg.Wait()
+	return newCredRotateResults(results), nil
+}
+
+// Rotate the credentials of one endpoint, and those stored for its
+// components, filling in res.
+func (s *SmD) credRotateOne(
+	ep *sm.RedfishEndpoint,
+	ceps []*sm.ComponentEndpoint,
+	password func(id string) (string, error),
+	res *CredRotateResult,
+) {
+	fail := func(step string, err error) {
+		res.Status = CredRotateFailed
+		res.Step = step
+		res.Error = err.Error()
+	}
+	if ep == nil {
+		fail(CredRotateStepConnect, errCertNoEndpoint)
+		return
+	}
+	rfEP, err := s.connectRedfishEP(ep)
+	if err != nil {
+		fail(CredRotateStepConnect, err)
+		return
+	}
+	if rfEP.User == "" || rfEP.Password == "" {
+		fail(CredRotateStepConnect, errCredRotateNoCreds)
+		return
+	}
+	res.Username = rfEP.User
+	oldPass := rfEP.Password
+	newPass, err := password(ep.ID)
+	if err != nil {
+		fail(CredRotateStepConnect, err)
+		return
+	}
+
+	// What is stored now, to put back if the rotation fails
+	oldCreds := make([]compcreds.CompCredentials, 0, 1+len(ceps))
+	cred, err := s.ccs.GetCompCred(ep.ID)
+	if err != nil {
+		fail(CredRotateStepConnect, err)
+		return
+	}
+	cred.Xname = ep.ID
+	if cred.URL == "" {
+		cred.URL = ep.FQDN + "/redfish/v1"
+	}
+	if cred.Username == "" || cred.Password == "" {
+		cred.Username = rfEP.User
+		cred.Password = oldPass
+	}
+	oldCreds = append(oldCreds, cred)
+	for _, cep := range ceps {
+		cred, err := s.ccs.GetCompCred(cep.ID)
+		if err != nil {
+			fail(CredRotateStepConnect, err)
+			return
+		}
+		// Only those stored with the endpoint's credentials by discovery
+		if cred.Username != rfEP.User || cred.Password != oldPass {
+			continue
+		}
+		cred.Xname = cep.ID
+		oldCreds = append(oldCreds, cred)
+	}
+
+	uri, err := rfEP.FindAccount(rfEP.User)
+	if err != nil {
+		fail(CredRotateStepFindAccount, err)
+		return
+	}
+	if err = rfEP.SetAccountPassword(uri, newPass); err != nil {
+		fail(CredRotateStepSetPassword, err)
+		return
+	}
+
+	// The BMC has the new password now, so anything else that fails is
+	// rolled back.
+	step := CredRotateStepVerifyLogin
+	err = rfEP.CheckLogin(uri, rfEP.User, newPass)
+	stored := 0
+	if err == nil {
+		step = CredRotateStepStore
+		for _, cred := range oldCreds {
+			cred.Username = rfEP.User
+			cred.Password = newPass
+			if err = s.ccs.StoreCompCred(cred); err != nil {
+				break
+			}
+			stored++
+		}
+	}
+	if err == nil {
+		res.Status = CredRotateSucceeded
+		return
+	}
+	fail(step, err)
+	var rerrs []string
+	for _, cred := range oldCreds[:stored] {
+		if err := s.ccs.StoreCompCred(cred); err != nil {
+			rerrs = append(rerrs, fmt.Sprintf("restore %s: %s", cred.Xname, err))
+		}
+	}
+	if err := credRotateRollback(rfEP, uri, oldPass, newPass); err != nil {
+		rerrs = append(rerrs, "restore BMC password: "+err.Error())
+	}
+	if len(rerrs) > 0 {
+		res.Status = CredRotateRollbackFailed
+		res.Error += "; rollback failed: " + strings.Join(rerrs, "; ")
+	} else {
+		res.Status = CredRotateRolledBack
+	}
+}
+
+// Put the old password back on the BMC after a failed rotation, logging in
+// with the new one (or the old one, if the change didn't take), and check
+// that it works.
+func credRotateRollback(rfEP *rf.RedfishEP, uri, oldPass, newPass string) error {
+	rfEP.Password = newPass
+	if err := rfEP.SetAccountPassword(uri, oldPass); err != nil {
+		rfEP.Password = oldPass
+		if rfEP.CheckLogin(uri, rfEP.User, oldPass) != nil {
+			return err
+		}
+	}
+	rfEP.Password = oldPass
+	return rfEP.CheckLogin(uri, rfEP.User, oldPass)
+}
+
+// Rotate the BMC credentials of RedfishEndpoints.
+func (s *SmD) doCredRotatePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if !s.writeVault || s.ccs == nil {
+		sendJsonError(w, http.StatusBadRequest, errCredRotateNoStore.Error())
+		return
+	}
+	var in CredRotateIn
+	if !s.certDecodeBody(w, r, &in) {
+		return
+	}
+	if in.PasswordLength == 0 {
+		in.PasswordLength = credRotatePasswordLenDefault
+	} else if in.PasswordLength < credRotatePasswordLenMin ||
+		in.PasswordLength > credRotatePasswordLenMax {
+		sendJsonError(w, http.StatusBadRequest, fmt.Sprintf(
+			"PasswordLength must be from %d to %d",
+			credRotatePasswordLenMin, credRotatePasswordLenMax))
+		return
+	}
+	// Normalize the keys of the per-endpoint passwords.
+	passwords := make(map[string]string, len(in.Passwords))
+	extra := make([]string, 0, len(in.Passwords))
+	for id, pw := range in.Passwords {
+		if pw == "" {
+			sendJsonError(w, http.StatusBadRequest, "empty password for "+id)
+			return
+		}
+		passwords[xnametypes.NormalizeHMSCompID(id)] = pw
+		extra = append(extra, id)
+	}
+	sort.Strings(extra)
+	if len(in.Types) > 0 {
+		types := make([]string, 0, len(in.Types))
+		for _, t := range in.Types {
+			nt := xnametypes.VerifyNormalizeType(t)
+			if nt == "" {
+				sendJsonError(w, http.StatusBadRequest, "invalid type: "+t)
+				return
+			}
+			types = append(types, nt)
+		}
+		eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{Type: types})
+		if err != nil {
+			s.LogAlways("doCredRotatePost(): Lookup failure: %s", err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		for _, ep := range eps {
+			extra = append(extra, ep.ID)
+		}
+	}
+	ids, code, msg := s.rfEndpointTargetIDs(&in.RFEndpointTargets, extra)
+	if code != 0 {
+		sendJsonError(w, code, msg)
+		return
+	}
+	s.LogAlways("Credential rotation of %d RedfishEndpoint(s) requested by %s",
+		len(ids), s.requestActor(r))
+	results, err := s.doCredRotate(ids, func(id string) (string, error) {
+		if pw, ok := passwords[id]; ok {
+			return pw, nil
+		} else if in.Password != "" {
+			return in.Password, nil
+		}
+		return generateBMCPassword(in.PasswordLength)
+	})
+	if err != nil {
+		s.LogAlways("doCredRotatePost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, results)
+}
+
+// Get the latest credential rotation result for some or all endpoints
+func (s *SmD) doCredRotateStatusGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	ids := []string{}
+	for _, id := range r.URL.Query()["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	sendJsonObject(w, http.StatusOK,
+		newCredRotateResults(s.credRotateStatus.get(ids)))
+}
@@ -0,0 +1,257 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+// BMC with one account, root, that checks its password.
+type testAccountService struct {
+	sync.Mutex
+	password    string
+	ignorePatch bool // Say the password was changed, but don't
+	patches     int
+}
+
+func (as *testAccountService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	as.Lock()
+	defer as.Unlock()
+	if user, pw, _ := r.BasicAuth(); user != "root" || pw != as.password {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.URL.Path {
+	case "/redfish/v1":
+		w.Write([]byte(`{"AccountService": {"@odata.id": "/redfish/v1/AccountService"}}`))
+	case "/redfish/v1/AccountService":
+		w.Write([]byte(`{"Accounts": {"@odata.id": "/redfish/v1/AccountService/Accounts"}}`))
+	case "/redfish/v1/AccountService/Accounts":
+		w.Write([]byte(`{"Members": [{"@odata.id": "/redfish/v1/AccountService/Accounts/1"}]}`))
+	case "/redfish/v1/AccountService/Accounts/1":
+		if r.Method == http.MethodPatch {
+			var patch rf.ManagerAccount
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &patch)
+			as.patches++
+			if !as.ignorePatch {
+				as.password = patch.Password
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"@odata.id": "/redfish/v1/AccountService/Accounts/1", "UserName": "root"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Credential store that fails to store for one key.
+type testFailingCredStore struct {
+	CredentialStore
+	failKey string
+}
+
+func (cs *testFailingCredStore) StoreCompCred(cred compcreds.CompCredentials) error {
+	if cred.Xname == cs.failKey {
+		return errors.New("store is down")
+	}
+	return cs.CredentialStore.StoreCompCred(cred)
+}
+
+func TestDoCredRotate(t *testing.T) {
+	as := &testAccountService{password: "initial0"}
+	server := httptest.NewTLSServer(as)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	readVault := s.readVault
+	writeVault := s.writeVault
+	ccs := s.ccs
+	defer func() {
+		s.readVault = readVault
+		s.writeVault = writeVault
+		s.ccs = ccs
+		s.credRotateStatus = CredRotateStatusStore{}
+		results.GetRFEndpointsFilter.Return.entries = nil
+		results.GetCompEndpointsFilter.Return.entries = nil
+	}()
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	fcs, err := NewFileCredStore(filepath.Join(t.TempDir(), "creds.enc"), key)
+	if err != nil {
+		t.Fatalf("NewFileCredStore: %s", err)
+	}
+	for _, id := range []string{"x3000c0s9b0", "x3000c0s9b0n0"} {
+		fcs.StoreCompCred(compcreds.CompCredentials{Xname: id,
+			URL: u.Host + "/redfish/v1", Username: "root", Password: "initial0"})
+	}
+	// Stored with other credentials, e.g. by hand
+	fcs.StoreCompCred(compcreds.CompCredentials{Xname: "x3000c0s9b0n1",
+		Username: "admin", Password: "other"})
+
+	results.GetRFEndpointsFilter.Return.entries = []*sm.RedfishEndpoint{{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID: "x3000c0s9b0", Type: "NodeBMC", FQDN: u.Host, Enabled: true}}}
+	results.GetCompEndpointsFilter.Return.entries = []*sm.ComponentEndpoint{{
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x3000c0s9b0n0", RfEndpointID: "x3000c0s9b0"}}, {
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x3000c0s9b0n1", RfEndpointID: "x3000c0s9b0"}}}
+
+	tests := []struct {
+		writeVault  bool
+		failKey     string
+		ignorePatch bool
+		body        string
+		expCode     int
+		expStatus   string
+		expStep     string
+		expPassword string // On the BMC and in the store after; "" to skip
+	}{{
+		false, "", false,
+		`{"Targets": ["x3000c0s9b0"]}`,
+		http.StatusBadRequest, "", "", "",
+	}, {
+		true, "", false,
+		`{"Targets": ["x3000c0s9b0"], "PasswordLength": 4}`,
+		http.StatusBadRequest, "", "", "",
+	}, {
+		true, "", false,
+		`{"Types": ["NodeBMCs"]}`,
+		http.StatusBadRequest, "", "", "",
+	}, {
+		true, "", false,
+		`{"Passwords": {"X3000C0S9B0": "changed1"}}`,
+		http.StatusOK, CredRotateSucceeded, "", "changed1",
+	}, {
+		// Fails storing for the node, so the endpoint's credentials are
+		// put back, as well as the BMC's password.
+		true, "x3000c0s9b0n0", false,
+		`{"Targets": ["x3000c0s9b0"], "Password": "changed2"}`,
+		http.StatusOK, CredRotateRolledBack, CredRotateStepStore, "changed1",
+	}, {
+		true, "", true,
+		`{"Groups": [], "Types": ["NodeBMC"], "Password": "changed3"}`,
+		http.StatusOK, CredRotateRolledBack, CredRotateStepVerifyLogin, "changed1",
+	}, {
+		true, "", false,
+		`{"Targets": ["x3000c0s9b0"]}`,
+		http.StatusOK, CredRotateSucceeded, "", "",
+	}}
+	for i, test := range tests {
+		s.readVault = test.writeVault
+		s.writeVault = test.writeVault
+		s.ccs = &testFailingCredStore{fcs, test.failKey}
+		as.ignorePatch = test.ignorePatch
+
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Inventory/Credentials/Actions/Rotate",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		var out CredRotateResults
+		json.Unmarshal(w.Body.Bytes(), &out)
+		if out.Counts.Total != 1 || out.Results[0].ID != "x3000c0s9b0" ||
+			out.Results[0].Username != "root" ||
+			out.Results[0].Status != test.expStatus ||
+			out.Results[0].Step != test.expStep {
+			t.Errorf("Test %d: Unexpected results: %s", i, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), "changed") {
+			t.Errorf("Test %d: Password in the results: %s", i, w.Body.String())
+		}
+
+		bmcPass := as.password
+		epCred, _ := fcs.GetCompCred("x3000c0s9b0")
+		nodeCred, _ := fcs.GetCompCred("x3000c0s9b0n0")
+		otherCred, _ := fcs.GetCompCred("x3000c0s9b0n1")
+		if test.expPassword != "" && bmcPass != test.expPassword {
+			t.Errorf("Test %d: Expected BMC password '%s', got '%s'",
+				i, test.expPassword, bmcPass)
+		} else if test.expPassword == "" && len(bmcPass) != credRotatePasswordLenDefault {
+			t.Errorf("Test %d: Expected a generated password, got '%s'",
+				i, bmcPass)
+		}
+		if epCred.Password != bmcPass || nodeCred.Password != bmcPass ||
+			epCred.URL != u.Host+"/redfish/v1" {
+			t.Errorf("Test %d: Expected stored password '%s', got %+v %+v",
+				i, bmcPass, epCred, nodeCred)
+		}
+		if otherCred.Password != "other" {
+			t.Errorf("Test %d: Unrelated credentials changed: %+v", i, otherCred)
+		}
+	}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/Credentials/Status?id=X3000C0S9B0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var out CredRotateResults
+	json.Unmarshal(w.Body.Bytes(), &out)
+	if w.Code != http.StatusOK || out.Counts.Total != 1 ||
+		out.Counts.Succeeded != 1 || out.Results[0].Timestamp == "" {
+		t.Errorf("Unexpected status: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGenerateBMCPassword(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		pw, err := generateBMCPassword(credRotatePasswordLenMin)
+		if err != nil {
+			t.Fatalf("generateBMCPassword: %s", err)
+		}
+		if len(pw) != credRotatePasswordLenMin ||
+			strings.Trim(pw, credRotatePasswordChars) != "" ||
+			!strings.ContainsAny(pw, "0123456789") {
+			t.Errorf("Bad password '%s'", pw)
+		}
+		if seen[pw] {
+			t.Errorf("Repeated password '%s'", pw)
+		}
+		seen[pw] = true
+	}
+}
@@ -0,0 +1,256 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Service account files mounted into every pod
+const (
+	k8sSADir           = "/var/run/secrets/kubernetes.io/serviceaccount"
+	k8sSATokenFile     = k8sSADir + "/token"
+	k8sSACAFile        = k8sSADir + "/ca.crt"
+	k8sSANamespaceFile = k8sSADir + "/namespace"
+)
+
+// Default prefix of the Secret names
+const k8sCredPrefixDefault = "hms-creds-"
+
+// Keys in the Secret data.  username and password are those of the
+// kubernetes.io/basic-auth type.
+const (
+	k8sCredUsername = "username"
+	k8sCredPassword = "password"
+	k8sCredURL      = "url"
+	k8sCredSNMPAuth = "snmp-auth-pass"
+	k8sCredSNMPPriv = "snmp-priv-pass"
+)
+
+// Credential store using a Kubernetes Secret per component.
+type K8sCredStore struct {
+	apiURL    string // e.g. https://10.96.0.1:443
+	namespace string
+	prefix    string
+	tokenFile string // Re-read for every request, it is rotated
+	client    *http.Client
+}
+
+// Secret, as much of it as is used here.
+type k8sSecret struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   k8sSecretMeta     `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	Data       map[string][]byte `json:"data"`
+}
+
+type k8sSecretMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Create a credential store for the Kubernetes cluster HSM is running in,
+// using its service account.  An empty namespace is that of the pod and an
+// empty prefix is hms-creds-.
+func NewK8sCredStoreInCluster(namespace, prefix string) (*K8sCredStore, error) {
+	host := os.Getenv("KUBERNETES_SERVICE_HOST")
+	port := os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in Kubernetes, " +
+			"KUBERNETES_SERVICE_HOST/PORT not set")
+	}
+	if namespace == "" {
+		ns, err := os.ReadFile(k8sSANamespaceFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't read the pod namespace: %s", err)
+		}
+		namespace = strings.TrimSpace(string(ns))
+	}
+	ca, err := os.ReadFile(k8sSACAFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the cluster CA: %s", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates in %s", k8sSACAFile)
+	}
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+	return NewK8sCredStore("https://"+net.JoinHostPort(host, port),
+		namespace, prefix, k8sSATokenFile, client), nil
+}
+
+// Create a credential store using the Kubernetes API at apiURL, with the
+// bearer token in tokenFile (none if empty).
+func NewK8sCredStore(apiURL, namespace, prefix, tokenFile string, client *http.Client) *K8sCredStore {
+	if prefix == "" {
+		prefix = k8sCredPrefixDefault
+	}
+	return &K8sCredStore{
+		apiURL:    strings.TrimSuffix(apiURL, "/"),
+		namespace: namespace,
+		prefix:    prefix,
+		tokenFile: tokenFile,
+		client:    client,
+	}
+}
+
+var k8sNameBadChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// Get the name of the Secret for the key.  Names must be DNS subdomains,
+// so anything else in the key is replaced with '-'.
+func (kcs *K8sCredStore) secretName(key string) string {
+	name := kcs.prefix + k8sNameBadChars.ReplaceAllString(strings.ToLower(key), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-.")
+}
+
+// Do a Kubernetes API request on the secret with the given name, or on all
+// the secrets in the namespace if empty.  Returns the status code and body.
+func (kcs *K8sCredStore) do(method, name string, in interface{}) (int, []byte, error) {
+	url := kcs.apiURL + "/api/v1/namespaces/" + kcs.namespace + "/secrets"
+	if name != "" {
+		url += "/" + name
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return 0, nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if kcs.tokenFile != "" {
+		token, err := os.ReadFile(kcs.tokenFile)
+		if err != nil {
+			return 0, nil, fmt.Errorf("can't read service account token: %s", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	rsp, err := kcs.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rsp.Body.Close()
+	out, err := io.ReadAll(rsp.Body)
+	return rsp.StatusCode, out, err
+}
+
+// Get the credentials stored under key, or empty credentials if none are.
+func (kcs *K8sCredStore) GetCompCred(key string) (compcreds.CompCredentials, error) {
+	cred := compcreds.CompCredentials{Xname: key}
+	name := kcs.secretName(key)
+	code, body, err := kcs.do(http.MethodGet, name, nil)
+	if err != nil {
+		return cred, err
+	}
+	if code == http.StatusNotFound {
+		return cred, nil
+	} else if code != http.StatusOK {
+		return cred, fmt.Errorf("get secret %s: %d %s", name, code, body)
+	}
+	var secret k8sSecret
+	if err = json.Unmarshal(body, &secret); err != nil {
+		return cred, fmt.Errorf("get secret %s: %s", name, err)
+	}
+	cred.Username = string(secret.Data[k8sCredUsername])
+	cred.Password = string(secret.Data[k8sCredPassword])
+	cred.URL = string(secret.Data[k8sCredURL])
+	cred.SNMPAuthPass = string(secret.Data[k8sCredSNMPAuth])
+	cred.SNMPPrivPass = string(secret.Data[k8sCredSNMPPriv])
+	return cred, nil
+}
+
+// Store credentials under their Xname, replacing the Secret or creating it
+// if it doesn't exist yet.
+func (kcs *K8sCredStore) StoreCompCred(cred compcreds.CompCredentials) error {
+	name := kcs.secretName(cred.Xname)
+	secret := k8sSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: k8sSecretMeta{
+			Name:        name,
+			Namespace:   kcs.namespace,
+			Labels:      map[string]string{"app.kubernetes.io/managed-by": "smd"},
+			Annotations: map[string]string{"smd.openchami.org/key": cred.Xname},
+		},
+		Type: "kubernetes.io/basic-auth",
+		Data: map[string][]byte{
+			k8sCredUsername: []byte(cred.Username),
+			k8sCredPassword: []byte(cred.Password),
+		},
+	}
+	if cred.URL != "" {
+		secret.Data[k8sCredURL] = []byte(cred.URL)
+	}
+	if cred.SNMPAuthPass != "" {
+		secret.Data[k8sCredSNMPAuth] = []byte(cred.SNMPAuthPass)
+	}
+	if cred.SNMPPrivPass != "" {
+		secret.Data[k8sCredSNMPPriv] = []byte(cred.SNMPPrivPass)
+	}
+	code, body, err := kcs.do(http.MethodPut, name, secret)
+	if err != nil {
+		return err
+	}
+	if code == http.StatusNotFound {
+		code, body, err = kcs.do(http.MethodPost, "", secret)
+		if err != nil {
+			return err
+		}
+	}
+	if code != http.StatusOK && code != http.StatusCreated {
+		return fmt.Errorf("store secret %s: %d %s", name, code, body)
+	}
+	return nil
+}
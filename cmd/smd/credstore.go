@@ -0,0 +1,183 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Credential stores
+//
+// HSM reads the BMC credentials it discovers with, and stores those of the
+// components it discovers, in a CredentialStore, when SMD_RVAULT or
+// SMD_WVAULT is set.  SMD_CRED_STORE picks the backend:
+//
+//     vault       Vault, with the secrets under VAULT_KEYPATH (the default)
+//     file        a local file, encrypted with AES-256-GCM, named by
+//                 SMD_CRED_FILE with the base64 32-byte key in
+//                 SMD_CRED_FILE_KEY
+//     kubernetes  a basic-auth Secret per component, named SMD_CRED_K8S_PREFIX
+//                 (default "hms-creds-") plus the xname, in the namespace
+//                 SMD_CRED_K8S_NAMESPACE (default that of the pod)
+//
+// The file is read once at startup and rewritten on every store, so it
+// can't be shared by more than one HSM instance.  The other backends can.
+// Looking up credentials that were never stored returns empty credentials,
+// which are never used.
+///////////////////////////////////////////////////////////////////////////////
+
+// Where component credentials are kept.  *compcreds.CompCredStore (Vault)
+// is one of these.
+type CredentialStore interface {
+	// Get the credentials stored under key, normally an xname.
+	GetCompCred(key string) (compcreds.CompCredentials, error)
+
+	// Store credentials under their Xname.
+	StoreCompCred(cred compcreds.CompCredentials) error
+}
+
+// SMD_CRED_STORE values
+const (
+	credStoreVault = "vault"
+	credStoreFile  = "file"
+	credStoreK8s   = "kubernetes"
+)
+
+// Credential store in an encrypted local file.
+type FileCredStore struct {
+	path  string
+	aead  cipher.AEAD
+	lock  sync.Mutex
+	creds map[string]compcreds.CompCredentials
+}
+
+// Open the credential store in the file at path, encrypted with the base64
+// 32-byte key.  The file is created by the first store if it doesn't exist.
+func NewFileCredStore(path, key string) (*FileCredStore, error) {
+	k, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("key is not base64: %s", err)
+	}
+	if len(k) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, not %d", len(k))
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	fcs := &FileCredStore{
+		path:  path,
+		aead:  aead,
+		creds: make(map[string]compcreds.CompCredentials),
+	}
+	if err = fcs.load(); err != nil {
+		return nil, err
+	}
+	return fcs, nil
+}
+
+// Read and decrypt the file, if it exists.
+func (fcs *FileCredStore) load() error {
+	data, err := os.ReadFile(fcs.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	nsize := fcs.aead.NonceSize()
+	if len(data) < nsize {
+		return fmt.Errorf("%s is truncated", fcs.path)
+	}
+	plain, err := fcs.aead.Open(nil, data[:nsize], data[nsize:], nil)
+	if err != nil {
+		return fmt.Errorf("can't decrypt %s, wrong key?", fcs.path)
+	}
+	return json.Unmarshal(plain, &fcs.creds)
+}
+
+// Encrypt and write the file, replacing it only once it is complete.
+func (fcs *FileCredStore) save() error {
+	plain, err := json.Marshal(fcs.creds)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, fcs.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := fcs.aead.Seal(nonce, nonce, plain, nil)
+
+	tmp, err := os.CreateTemp(filepath.Dir(fcs.path), filepath.Base(fcs.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fcs.path)
+}
+
+// Get the credentials stored under key, or empty credentials if none are.
+func (fcs *FileCredStore) GetCompCred(key string) (compcreds.CompCredentials, error) {
+	fcs.lock.Lock()
+	defer fcs.lock.Unlock()
+	return fcs.creds[key], nil
+}
+
+// Store credentials under their Xname and rewrite the file.
+func (fcs *FileCredStore) StoreCompCred(cred compcreds.CompCredentials) error {
+	fcs.lock.Lock()
+	defer fcs.lock.Unlock()
+	old, existed := fcs.creds[cred.Xname]
+	fcs.creds[cred.Xname] = cred
+	if err := fcs.save(); err != nil {
+		if existed {
+			fcs.creds[cred.Xname] = old
+		} else {
+			delete(fcs.creds, cred.Xname)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+)
+
+// Vault is one of the backends.
+var _ CredentialStore = (*compcreds.CompCredStore)(nil)
+
+func TestFileCredStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "creds.enc")
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+
+	if _, err := NewFileCredStore(path, "not base64!"); err == nil {
+		t.Errorf("Expected an error for a bad key")
+	}
+	if _, err := NewFileCredStore(path,
+		base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Errorf("Expected an error for a short key")
+	}
+
+	fcs, err := NewFileCredStore(path, key)
+	if err != nil {
+		t.Fatalf("NewFileCredStore: %s", err)
+	}
+	cred, err := fcs.GetCompCred("x0c0s0b0")
+	if err != nil || cred.Password != "" {
+		t.Errorf("Expected empty credentials, got %v, %v", cred, err)
+	}
+	cred = compcreds.CompCredentials{
+		Xname:    "x0c0s0b0",
+		URL:      "x0c0s0b0/redfish/v1",
+		Username: "root",
+		Password: "initial0",
+	}
+	if err = fcs.StoreCompCred(cred); err != nil {
+		t.Fatalf("StoreCompCred: %s", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %s", err)
+	}
+	if strings.Contains(string(data), "initial0") {
+		t.Errorf("Expected the password to be encrypted")
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", fi.Mode().Perm())
+	}
+
+	// Reopened, e.g. after a restart
+	fcs, err = NewFileCredStore(path, key)
+	if err != nil {
+		t.Fatalf("NewFileCredStore: %s", err)
+	}
+	if got, err := fcs.GetCompCred("x0c0s0b0"); err != nil || got != cred {
+		t.Errorf("Expected %v, got %v, %v", cred, got, err)
+	}
+
+	otherKey := base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	if _, err = NewFileCredStore(path, otherKey); err == nil ||
+		!strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("Expected a wrong key error, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the store file, got %v", entries)
+	}
+}
+
+// Just enough of the Kubernetes Secrets API for K8sCredStore.
+type fakeK8sSecrets struct {
+	lock    sync.Mutex
+	secrets map[string]k8sSecret
+	tokens  []string
+}
+
+func (f *fakeK8sSecrets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+
+	const base = "/api/v1/namespaces/hms/secrets"
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, base), "/")
+	if !strings.HasPrefix(r.URL.Path, base) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var secret k8sSecret
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &secret); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s, ok := f.secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(s)
+	case http.MethodPut:
+		if _, ok := f.secrets[name]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.secrets[name] = secret
+		json.NewEncoder(w).Encode(secret)
+	case http.MethodPost:
+		f.secrets[secret.Metadata.Name] = secret
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(secret)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestK8sCredStore(t *testing.T) {
+	fake := &fakeK8sSecrets{secrets: map[string]k8sSecret{}}
+	ts := httptest.NewServer(fake)
+	defer ts.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	os.WriteFile(tokenFile, []byte("sa-token\n"), 0600)
+
+	kcs := NewK8sCredStore(ts.URL+"/", "hms", "", tokenFile, ts.Client())
+	cred, err := kcs.GetCompCred("x0c0s0b0")
+	if err != nil || cred.Password != "" || cred.Xname != "x0c0s0b0" {
+		t.Errorf("Expected empty credentials, got %v, %v", cred, err)
+	}
+
+	cred = compcreds.CompCredentials{
+		Xname:    "x0c0s0b0",
+		URL:      "x0c0s0b0/redfish/v1",
+		Username: "root",
+		Password: "initial0",
+	}
+	if err = kcs.StoreCompCred(cred); err != nil {
+		t.Fatalf("StoreCompCred (create): %s", err)
+	}
+	secret, ok := fake.secrets["hms-creds-x0c0s0b0"]
+	if !ok || secret.Type != "kubernetes.io/basic-auth" ||
+		string(secret.Data["password"]) != "initial0" {
+		t.Errorf("Expected a basic-auth secret, got %+v", secret)
+	}
+	cred.Password = "changed1"
+	if err = kcs.StoreCompCred(cred); err != nil {
+		t.Fatalf("StoreCompCred (replace): %s", err)
+	}
+	if got, err := kcs.GetCompCred("x0c0s0b0"); err != nil || got != cred {
+		t.Errorf("Expected %v, got %v, %v", cred, got, err)
+	}
+	if fake.tokens[0] != "Bearer sa-token" {
+		t.Errorf("Expected the service account token, got '%s'", fake.tokens[0])
+	}
+
+	// Vendor profile secrets needn't be xnames
+	if name := kcs.secretName("HPE/Default Creds"); name != "hms-creds-hpe-default-creds" {
+		t.Errorf("Expected a DNS name, got '%s'", name)
+	}
+
+	kcs = NewK8sCredStore(ts.URL, "other", "bmc-", "", ts.Client())
+	if _, err = kcs.GetCompCred("x0c0s0b0"); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if err = kcs.StoreCompCred(cred); err == nil {
+		t.Errorf("Expected an error storing to a missing namespace")
+	}
+}
@@ -0,0 +1,148 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Database health
+//
+// GET /service/health/db reports how busy the DB connection pool is, how
+// many transactions have been slow, and the pool settings, to help tell
+// whether API latency is coming from the database.  It returns 503 if the
+// database can't be reached.  The pool is set up with:
+//
+//     SMD_DB_MAX_OPEN_CONNS           most open connections (70), -1 no limit
+//     SMD_DB_MAX_IDLE_CONNS           most idle connections kept (2), -1 none
+//     SMD_DB_CONN_MAX_LIFETIME_SECS   close connections once this old
+//     SMD_DB_CONN_MAX_IDLE_SECS       close connections idle this long
+//     SMD_DB_STATEMENT_TIMEOUT_SECS   cancel statements running this long
+//     SMD_DB_SLOW_TX_MS               count transactions this long as slow
+//
+// Transactions are timed from begin to commit or rollback, which for most
+// requests covers all of their queries.  Slow ones are also logged.  The
+// same counts are in /metrics as smd_db_*.
+///////////////////////////////////////////////////////////////////////////////
+
+// Transactions taking this long or longer are counted as slow by default.
+const DefaultDBSlowTxThreshold = time.Second
+
+// Database health status
+const (
+	DBHealthOK          = "OK"
+	DBHealthSaturated   = "Saturated"   // Every connection in use
+	DBHealthUnavailable = "Unavailable" // Can't reach the database
+)
+
+type DBHealth struct {
+	Status string
+	Error  string `json:",omitempty"`
+
+	MaxOpenConnections  int
+	OpenConnections     int
+	InUse               int
+	Idle                int
+	Saturation          float64 // InUse / MaxOpenConnections, 0 if no limit
+	WaitCount           int64
+	WaitDurationSeconds float64
+	MaxIdleClosed       int64
+	MaxIdleTimeClosed   int64
+	MaxLifetimeClosed   int64
+
+	Transactions           uint64
+	SlowTransactions       uint64
+	SlowTxThresholdSeconds float64
+	MaxTxDurationSeconds   float64
+
+	Config DBHealthConfig
+}
+
+// Connection pool settings, 0 for no limit.
+type DBHealthConfig struct {
+	MaxOpenConnections      int
+	MaxIdleConnections      int
+	ConnMaxLifetimeSeconds  float64
+	ConnMaxIdleTimeSeconds  float64
+	StatementTimeoutSeconds float64
+}
+
+// Get the current health of the database connection pool.
+func (s *SmD) getDBHealth() *DBHealth {
+	st := s.db.DBStats()
+	tx := s.db.TxStats()
+	cfg := s.db.PoolConfig()
+	h := &DBHealth{
+		Status:              DBHealthOK,
+		MaxOpenConnections:  st.MaxOpenConnections,
+		OpenConnections:     st.OpenConnections,
+		InUse:               st.InUse,
+		Idle:                st.Idle,
+		WaitCount:           st.WaitCount,
+		WaitDurationSeconds: st.WaitDuration.Seconds(),
+		MaxIdleClosed:       st.MaxIdleClosed,
+		MaxIdleTimeClosed:   st.MaxIdleTimeClosed,
+		MaxLifetimeClosed:   st.MaxLifetimeClosed,
+
+		Transactions:           tx.Transactions,
+		SlowTransactions:       tx.SlowTransactions,
+		SlowTxThresholdSeconds: tx.SlowTxThreshold.Seconds(),
+		MaxTxDurationSeconds:   tx.MaxTxDuration.Seconds(),
+
+		Config: DBHealthConfig{
+			MaxOpenConnections:      cfg.MaxOpenConns,
+			MaxIdleConnections:      cfg.MaxIdleConns,
+			ConnMaxLifetimeSeconds:  cfg.ConnMaxLifetime.Seconds(),
+			ConnMaxIdleTimeSeconds:  cfg.ConnMaxIdleTime.Seconds(),
+			StatementTimeoutSeconds: cfg.StatementTimeout.Seconds(),
+		},
+	}
+	if st.MaxOpenConnections > 0 {
+		h.Saturation = float64(st.InUse) / float64(st.MaxOpenConnections)
+		if st.InUse >= st.MaxOpenConnections {
+			h.Status = DBHealthSaturated
+		}
+	}
+	if err := s.db.TestConnection(); err != nil {
+		h.Status = DBHealthUnavailable
+		h.Error = err.Error()
+	}
+	return h
+}
+
+// GET /service/health/db
+func (s *SmD) doDBHealthGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	h := s.getDBHealth()
+	if h.Status == DBHealthUnavailable {
+		s.reqLog(r).LogAlways("doDBHealthGet(): Database failed health check: %s", h.Error)
+		sendJsonObject(w, http.StatusServiceUnavailable, h)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, h)
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+func TestDoDBHealthGet(t *testing.T) {
+	defer func() {
+		results.DBStats.Return.stats = sql.DBStats{}
+		results.TxStats.Return.stats = hmsds.DBTxStats{}
+		results.PoolConfig.Return.cfg = hmsds.DBPoolConfig{}
+		results.TestConnection.Return.err = nil
+	}()
+	results.DBStats.Return.stats = sql.DBStats{MaxOpenConnections: 4,
+		OpenConnections: 3, InUse: 2, Idle: 1, WaitCount: 5,
+		WaitDuration: 2 * time.Second}
+	results.TxStats.Return.stats = hmsds.DBTxStats{Transactions: 10,
+		SlowTransactions: 1, SlowTxThreshold: time.Second,
+		MaxTxDuration: 1500 * time.Millisecond}
+	results.PoolConfig.Return.cfg = hmsds.DBPoolConfig{MaxOpenConns: 4,
+		MaxIdleConns: 2, StatementTimeout: 30 * time.Second}
+
+	get := func() (int, *DBHealth) {
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/service/health/db", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var h DBHealth
+		if err := json.Unmarshal(w.Body.Bytes(), &h); err != nil {
+			t.Fatalf("Bad response %d: %s", w.Code, w.Body.String())
+		}
+		return w.Code, &h
+	}
+
+	code, h := get()
+	expected := &DBHealth{
+		Status:                 DBHealthOK,
+		MaxOpenConnections:     4,
+		OpenConnections:        3,
+		InUse:                  2,
+		Idle:                   1,
+		Saturation:             0.5,
+		WaitCount:              5,
+		WaitDurationSeconds:    2,
+		Transactions:           10,
+		SlowTransactions:       1,
+		SlowTxThresholdSeconds: 1,
+		MaxTxDurationSeconds:   1.5,
+		Config: DBHealthConfig{
+			MaxOpenConnections:      4,
+			MaxIdleConnections:      2,
+			StatementTimeoutSeconds: 30,
+		},
+	}
+	if code != http.StatusOK || !reflect.DeepEqual(expected, h) {
+		t.Errorf("Expected %d %+v, got %d %+v", http.StatusOK, expected, code, h)
+	}
+
+	// Every connection in use
+	results.DBStats.Return.stats.InUse = 4
+	code, h = get()
+	if code != http.StatusOK || h.Status != DBHealthSaturated ||
+		h.Saturation != 1 {
+		t.Errorf("Expected saturated, got %d %+v", code, h)
+	}
+
+	// Can't reach the database
+	results.TestConnection.Return.err = errors.New("connection refused")
+	code, h = get()
+	if code != http.StatusServiceUnavailable ||
+		h.Status != DBHealthUnavailable || h.Error != "connection refused" {
+		t.Errorf("Expected unavailable, got %d %+v", code, h)
+	}
+}
@@ -27,10 +27,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
 	compcreds "github.com/Cray-HPE/hms-compcredentials"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 )
@@ -46,33 +48,56 @@ import (
 //	eps is a set of RedfishEndpoints retrieved from the database.
 //	id is the id of the DiscoveryStatus object to write status to.
 func (s *SmD) discoverFromEndpoints(eps []*sm.RedfishEndpoint, id uint, update, force bool) {
+	s.discoverFromEndpointsJob(eps, id, update, force, nil)
+}
+
+// As above, but recording the progress of each endpoint in job, which may
+// be nil.  If the job is cancelled, endpoints that haven't started are
+// left as they were.
+func (s *SmD) discoverFromEndpointsJob(eps []*sm.RedfishEndpoint, id uint, update, force bool, job *discJob) {
+	dlog := s.logWith(LogSubsysDiscovery)
+	defer job.finish()
 	idsFiltered := make([]string, 0, len(eps))
+	epsByID := make(map[string]*sm.RedfishEndpoint, len(eps))
 	for _, ep := range eps {
 		if update && !ep.RediscOnUpdate {
-			s.LogAlways("Skipping discovery for %s since !RediscoverOnUpdate",
-				ep.ID)
+			dlog.With("endpoint_id", ep.ID).LogAlways(
+				"Skipping discovery for %s since !RediscoverOnUpdate", ep.ID)
+			job.skip(ep.ID, DiscJobSkipped, "!RediscoverOnUpdate")
 			continue
 		}
 		if !ep.Enabled {
-			s.LogAlways("Skipping discovery for %s since !Enabled",
-				ep.ID)
+			dlog.With("endpoint_id", ep.ID).LogAlways(
+				"Skipping discovery for %s since !Enabled", ep.ID)
+			job.skip(ep.ID, DiscJobSkipped, "!Enabled")
+			continue
+		}
+		if !s.discoveryAllowed(ep.ID, force) {
+			job.skip(ep.ID, DiscJobSkipped, "deferred after repeated failures")
 			continue
 		}
 		idsFiltered = append(idsFiltered, xnametypes.VerifyNormalizeCompID(ep.ID))
+		epsByID[ep.ID] = ep
 	}
 	// This should not fail in practice unless eps have not been inserted yet
 	// This will "lock" the LastStatus to in-progress so it can't be started
 	// twice.
 	discEPs, err := s.db.UpdateRFEndpointForDiscover(idsFiltered, force)
 	if err != nil {
-		s.lg.Printf("Discovery: UpdateRFEndpointForDiscover() returned %s", err)
+		dlog.LogAlways("Discovery: UpdateRFEndpointForDiscover() returned %s", err)
 		return
 	}
 	if len(discEPs) != len(eps) {
-		s.LogAlways("%d/%d endpoints are already being discovered and will "+
+		dlog.LogAlways("%d/%d endpoints are already being discovered and will "+
 			"be skipped (not forced)",
 			len(eps)-len(discEPs), len(eps))
 	}
+	for _, ep := range discEPs {
+		delete(epsByID, ep.ID)
+	}
+	for epID := range epsByID {
+		job.skip(epID, DiscJobSkipped, "already being discovered")
+	}
 
 	// Create RedfishEPDescription array from the raw data
 	rfEpds := new(rf.RedfishEPDescriptions)
@@ -92,7 +117,7 @@ func (s *SmD) discoverFromEndpoints(eps []*sm.RedfishEndpoint, id uint, update,
 	if err != nil {
 		// This shouldn't happen as it means an entry was not created
 		// correctly.
-		s.LogAlways("%d/%d endpoints are invalid and will be skipped: %s",
+		dlog.LogAlways("%d/%d endpoints are invalid and will be skipped: %s",
 			(numEPs - rfEps.Num), numEPs, err)
 	}
 
@@ -102,26 +127,51 @@ func (s *SmD) discoverFromEndpoints(eps []*sm.RedfishEndpoint, id uint, update,
 	stat.ID = id
 	err = s.db.UpsertDiscoveryStatus(stat)
 	if err != nil {
-		s.lg.Printf("UpsertDiscoveryStatus start: %s", err)
+		dlog.LogAlways("UpsertDiscoveryStatus start: %s", err)
+	}
+
+	for _, ep := range discEPs {
+		if _, ok := rfEps.IDs[ep.ID]; !ok {
+			job.skip(ep.ID, DiscJobFailed, rf.EndpointInvalid)
+		}
 	}
 
 	var wGrp sync.WaitGroup
-	for _, rfEp := range rfEps.IDs {
+	var limit chan struct{}
+	if s.discConcurrency > 0 {
+		limit = make(chan struct{}, s.discConcurrency)
+	}
+	discIDs := make([]string, 0, len(rfEps.IDs))
+	for id, rfEp := range rfEps.IDs {
+		discIDs = append(discIDs, id)
 		wGrp.Add(1)
 		// Start each endpoint as a separate thread
 		go func(e *rf.RedfishEP) {
 			defer wGrp.Done()
-			s.doDiscovery(e)
+			if limit != nil {
+				limit <- struct{}{}
+				defer func() { <-limit }()
+			}
+			if job.isCancelled() {
+				s.discoveryCancel(e.ID, eps)
+				job.skip(e.ID, DiscJobCancelled, "job cancelled")
+				return
+			}
+			job.start(e.ID)
+			err := s.doDiscovery(e)
+			job.result(e, err)
 		}(rfEp)
 	}
 	wGrp.Wait()
 
 	// Write discovery status - we're done.
 	stat.Status = sm.DiscComplete
+	stat.Details = s.discoveryChangeDetails(discIDs)
 	err = s.db.UpsertDiscoveryStatus(stat)
 	if err != nil {
-		s.lg.Printf("UpsertDiscoveryStatus end: %s", err)
+		dlog.LogAlways("UpsertDiscoveryStatus end: %s", err)
 	}
+	s.publishDiscoveryComplete(stat, discIDs)
 }
 
 // Single-endpoint version of the above.
@@ -131,29 +181,33 @@ func (s *SmD) discoverFromEndpoints(eps []*sm.RedfishEndpoint, id uint, update,
 //	ep is a single RedfishEndpoint retrieved from the database.
 //	id is the id of the DiscoveryStatus object to write status to.
 func (s *SmD) discoverFromEndpoint(ep *sm.RedfishEndpoint, id uint, force bool) {
+	dlog := s.logWith(LogSubsysDiscovery).With("endpoint_id", ep.ID)
 	if !ep.RediscOnUpdate {
-		s.LogAlways("Skipping discovery for %s: !RediscoverOnUpdate", ep.ID)
+		dlog.LogAlways("Skipping discovery for %s: !RediscoverOnUpdate", ep.ID)
 		return
 	}
 	if !ep.Enabled {
-		s.LogAlways("Skipping discovery for %s since !Enabled", ep.ID)
+		dlog.LogAlways("Skipping discovery for %s since !Enabled", ep.ID)
+		return
+	}
+	if !s.discoveryAllowed(ep.ID, force) {
 		return
 	}
 	// This will "lock" the LastStatus to in-progress so it can't be started
 	// twice.
 	discEPs, err := s.db.UpdateRFEndpointForDiscover([]string{ep.ID}, force)
 	if err != nil {
-		s.lg.Printf("Discovery: UpdateRFEndpointForDiscover() returned %s", err)
+		dlog.LogAlways("Discovery: UpdateRFEndpointForDiscover() returned %s", err)
 		return
 	} else if len(discEPs) == 0 {
-		s.lg.Printf("Discovery: already in progress for %s", ep.ID)
+		dlog.LogAlways("Discovery: already in progress for %s", ep.ID)
 		return
 	}
 	rfEP, err := rf.NewRedfishEp(&discEPs[0].RedfishEPDescription)
 	if err != nil {
 		// This shouldn't happen as it means an entry was not created
 		// correctly.
-		s.LogAlways("Endpoint is invalid and will be skipped")
+		dlog.LogAlways("Endpoint is invalid and will be skipped")
 	}
 
 	// Write that discovery has started.
@@ -162,29 +216,52 @@ func (s *SmD) discoverFromEndpoint(ep *sm.RedfishEndpoint, id uint, force bool)
 	stat.ID = id
 	err = s.db.UpsertDiscoveryStatus(stat)
 	if err != nil {
-		s.lg.Printf("UpsertDiscoveryStatus start: %s", err)
+		dlog.LogAlways("UpsertDiscoveryStatus start: %s", err)
 	}
 
 	s.doDiscovery(rfEP)
 
 	// Write discovery status - we're done.
 	stat.Status = sm.DiscComplete
+	stat.Details = s.discoveryChangeDetails([]string{ep.ID})
 	err = s.db.UpsertDiscoveryStatus(stat)
 	if err != nil {
-		s.lg.Printf("UpsertDiscoveryStatus end: %s", err)
+		dlog.LogAlways("UpsertDiscoveryStatus end: %s", err)
+	}
+	s.publishDiscoveryComplete(stat, []string{ep.ID})
+}
+
+// Put back the RedfishEndpoint id as it was in eps, before it was marked as
+// being discovered, as its discovery was cancelled before it started.
+func (s *SmD) discoveryCancel(id string, eps []*sm.RedfishEndpoint) {
+	for _, ep := range eps {
+		if ep.ID != id {
+			continue
+		}
+		if _, err := s.db.UpdateRFEndpoint(ep); err != nil {
+			s.logWith(LogSubsysDiscovery).With("endpoint_id", id).LogAlways(
+				"Can't reset status of %s after cancelling: %s", id, err)
+		}
+		return
 	}
 }
 
-func (s *SmD) doDiscovery(rfEP *rf.RedfishEP) {
+// Discover and store one endpoint, returning any error storing it.
+func (s *SmD) doDiscovery(rfEP *rf.RedfishEP) error {
+	dlog := s.logWith(LogSubsysDiscovery).With("endpoint_id", rfEP.ID)
+	start := time.Now()
 
 	// Add the xname to the list of discovery jobs for this HSM instance to periodically update.
 	s.discoveryMapAdd(rfEP.ID)
+	// Use the port, auth style, quirks and HTTP options from the endpoint's
+	// vendor profile
+	s.setDiscoveryVendorProfile(rfEP)
 	// Get redfish endpoint credentials from Vault
 	if s.readVault {
 		cred, err := s.ccs.GetCompCred(rfEP.ID)
 		if err != nil {
 			// Ignore we'll let it naturally fail without credentials later.
-			s.LogAlways("Warning: Failed to get credentials from Vault for %s - %s", rfEP.ID, err)
+			dlog.LogAlways("Warning: Failed to get credentials from the credential store for %s - %s", rfEP.ID, err)
 		} else {
 			// Don't read empty credentials
 			if len(cred.Password) > 0 {
@@ -194,12 +271,33 @@ func (s *SmD) doDiscovery(rfEP *rf.RedfishEP) {
 		}
 	}
 
+	// Credentials to try if the endpoint rejects its own
+	s.setDiscoveryFallbackCredentials(rfEP)
+
 	// Do the actual discovery, including contacting the remote endpoint.
 	rfEP.GetRootInfo()
+	s.recordDiscovery(rfEP)
+	if rfEP.DiscInfo.CredentialSource != "" {
+		dlog.LogAlways("Warning: %s is using fallback credentials %s",
+			rfEP.ID, rfEP.DiscInfo.CredentialSource)
+	}
+	if n := len(rfEP.DiscInfo.Errors); n > 0 &&
+		rfEP.DiscInfo.LastStatus == rf.DiscoverOK {
+		dlog.LogAlways("Warning: %s was only partially discovered, %d "+
+			"resource(s) failed", rfEP.ID, n)
+	}
 
 	// Create/update HMS-level components from the retrieved discovery data
 	// from Redfish.  This also inserts the data into the database.
-	s.updateFromRfEndpoint(rfEP)
+	err := s.updateFromRfEndpoint(rfEP)
+	s.metrics.observeDiscovery(rfEP, time.Since(start))
+
+	// Have the endpoint push its power and thermal MetricReports to us.
+	s.telemetrySubscribe(rfEP)
+
+	// Keep the endpoint's event subscription to the collector, if it has one.
+	s.eventSubscribe(rfEP)
+	return err
 }
 
 // Back end that writes one RedfishEndpoint's worth of structs to the DB
@@ -212,6 +310,7 @@ func (s *SmD) doDiscovery(rfEP *rf.RedfishEP) {
 //	       and then queried via gets to the specified destination.
 func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 	ep := sm.NewRedfishEndpoint(&rfEP.RedfishEPDescription)
+	dlog := s.logWith(LogSubsysDiscovery).With("endpoint_id", ep.ID)
 	var savedErr error = nil
 	var savedPw string
 	var savedUn string
@@ -222,7 +321,7 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 		//
 		// Update endpoint only to reflect being skipped.
 		//
-		s.LogAlways("Discover of RedfishEndpoint %s skipped: %s",
+		dlog.LogAlways("Discover of RedfishEndpoint %s skipped: %s",
 			ep.ID, ep.DiscInfo.LastStatus)
 		if s.readVault {
 			ep.Password = ""
@@ -231,7 +330,7 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 		_, err := s.db.UpdateRFEndpoint(ep)
 		return err
 	} else if ep.DiscInfo.LastStatus != rf.DiscoverOK {
-		s.LogAlways("Discover of RedfishEndpoint %s failed: %s",
+		dlog.LogAlways("Discover of RedfishEndpoint %s failed: %s",
 			ep.ID, ep.DiscInfo.LastStatus)
 		if s.readVault {
 			ep.Password = ""
@@ -241,70 +340,6 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 		_, err := s.db.UpdateRFEndpoint(ep)
 		return err
 	}
-	// Add/update component endpoints
-	ceps, err := s.DiscoverComponentEndpointArray(rfEP)
-	if err != nil {
-		// These error types shouldn't happen, but may fail every time
-		// so better to skip them and store the remaining, valid components.
-		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
-			s.LogAlways("DiscoverComponentEndpointArray(%s): One or more: %s",
-				rfEP.ID, err)
-		} else {
-			s.LogAlways("DiscoverComponentEndpointArray(%s): Fatal storing: %s",
-				rfEP.ID, err)
-			ep.DiscInfo.LastStatus = rf.UnexpectedErrorPreStore
-			savedErr = err
-		}
-	}
-	//Add/update component ethernet interface
-	ceis := s.DiscoverCompEthInterfaceArray(ep, ceps)
-	// Add/update service endpoints
-	seps := s.DiscoverServiceEndpointArray(rfEP)
-	// Add/update Hardware Inventory (FRU info, etc.) entries
-	hwlocs, err := s.DiscoverHWInvByLocArray(rfEP)
-	if err != nil {
-		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
-			// Non-fatal, one or more components wasn't supported.  Likely to
-			// recur if discovery re-run.
-			s.LogAlways("DiscoverHWInvByLocArray(%s): One or more: %s",
-				rfEP.ID, err)
-		} else {
-			s.LogAlways("DiscoverHWInvByLocArray(%s): Fatal error storing: %s",
-				rfEP.ID, err)
-			ep.DiscInfo.LastStatus = rf.UnexpectedErrorPreStore
-			savedErr = err
-		}
-	}
-	// Add HMS component entries (NID, state, role, etc.)
-	comps, err := s.DiscoverComponentArray(rfEP)
-	if err != nil {
-		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
-			// Non-fatal, one or more components wasn't supported.  Likely to
-			// recur if discovery re-run.
-			s.LogAlways("DiscoverComponentArray(%s): One or more: %s",
-				rfEP.ID, err)
-		} else {
-			s.LogAlways("DiscoverComponentArray(%s): Fatal error storing: %s",
-				rfEP.ID, err)
-			ep.DiscInfo.LastStatus = rf.UnexpectedErrorPreStore
-			savedErr = err
-		}
-	}
-	// Unrecoverable error - just save errored state for endpoint.
-	if ep.DiscInfo.LastStatus != rf.DiscoverOK {
-		if s.readVault {
-			ep.Password = ""
-		}
-		s.discoveryMapRemove(ep.ID)
-		_, err = s.db.UpdateAllForRFEndpoint(ep, nil, nil, nil, nil, nil)
-		if err == nil {
-			// Return initial reason for failure.
-			return savedErr
-		} else {
-			return err
-		}
-	}
-
 	if s.readVault || s.writeVault {
 		savedPw = ep.Password
 		savedUn = ep.User
@@ -314,47 +349,103 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 		}
 	}
 
-	// Get hartbeating status from HBTD to make sure nodes previously set to 'Ready'
-	// go back to 'Ready' if they are still heartbeating.
-	if s.hbtd != nil && comps != nil && len(comps.Components) > 0 {
-		compMap := make(map[string]*base.Component)
-		compList := make([]string, 0, 1)
-		for _, comp := range comps.Components {
-			if comp.Type == xnametypes.Node.String() && comp.State == base.StateOn.String() {
-				compMap[comp.ID] = comp
-				compList = append(compList, comp.ID)
-			}
+	// Create the HMS-level structures from the discovered data and store
+	// them one part of the endpoint at a time, so that a controller with
+	// many components doesn't need all of them in memory at once.  Each
+	// part is committed on its own and the endpoint is only updated once
+	// all of them are, so nothing that follows (SCNs, Vault) should happen
+	// unless this succeeds.
+	parts := rfEP.Parts()
+	creds := make([]compcreds.CompCredentials, 0, 1)
+	var preStoreErr error
+	// MACs discovered, and which other components had them beforehand, to
+	// look for duplicates once the endpoint is stored.
+	var ceis, macOwners []*sm.CompEthInterfaceV2
+	macOwnerEPs := make(map[string]string)
+	// Components found, to compare with earlier discoveries before the
+	// endpoint's existing ones are replaced.  As parts are committed as
+	// they are stored, this has to be checked before storing any of them.
+	numComps := 0
+	if ceps, _ := s.DiscoverComponentEndpointArray(rfEP); ceps != nil {
+		numComps = len(ceps.ComponentEndpoints)
+	}
+	if err := s.checkCompCount(ep.ID, numComps); err != nil {
+		// Keep the existing inventory, only record why.
+		ep.DiscInfo.LastStatus = rf.DiscoveryHeld
+		s.discoveryMapRemove(ep.ID)
+		if _, err := s.db.UpdateRFEndpoint(ep); err != nil {
+			return err
+		}
+		return errCompCountHeld
+	}
+	stored, err := s.storedDiscoverySnapshot(ep.ID)
+	if err != nil {
+		dlog.LogAlways("storedDiscoverySnapshot(%s): %s", ep.ID, err)
+	}
+	discovered := newDiscoverySnapshot()
+	next := func() (*hmsds.RFEndpointBatch, error) {
+		if len(parts) == 0 {
+			return nil, nil
 		}
-		results, err := s.hbtd.GetHeartbeatStatus(compList)
+		batch, err := s.discoverRFEndpointBatch(ep, parts[0])
+		parts = parts[1:]
 		if err != nil {
-			s.LogAlways("GetHeartbeatStatus(): Could not retrieve heartbeat status: %s", err)
-		} else {
-			for _, stat := range results {
-				comp, ok := compMap[stat.XName]
-				if ok && stat.Heartbeating {
-					comp.State = base.StateReady.String()
-				}
+			preStoreErr = err
+			return nil, err
+		}
+		discovered.addHWInvByLocs(batch.HWInvByLocs)
+		if batch.CompEndpoints != nil {
+			discovered.addCompEndpoints(batch.CompEndpoints.ComponentEndpoints)
+			for _, cep := range batch.CompEndpoints.ComponentEndpoints {
+				creds = append(creds, compcreds.CompCredentials{
+					Xname:    cep.ID,
+					URL:      cep.URL,
+					Username: savedUn,
+					Password: savedPw,
+				})
+			}
+		}
+		if len(batch.CompEthInterfaces) > 0 {
+			owners, ownerEPs, err := s.getMACOwners(batch.CompEthInterfaces)
+			if err != nil {
+				dlog.LogAlways("getMACOwners(%s): %s", ep.ID, err)
+			}
+			ceis = append(ceis, batch.CompEthInterfaces...)
+			macOwners = append(macOwners, owners...)
+			for id, epID := range ownerEPs {
+				macOwnerEPs[id] = epID
 			}
 		}
+		return batch, nil
 	}
-
 	s.discoveryMapRemove(ep.ID)
-	// Data looks good - store it
-	discoveredComps, err := s.db.UpdateAllForRFEndpoint(ep, ceps, hwlocs, comps, seps, ceis)
-	if err != nil {
+	db := s.db.WithActor(CompHistActorDiscovery)
+	discoveredComps, err := db.UpdateAllForRFEndpointBatches(ep, next)
+	if preStoreErr != nil {
+		// Unrecoverable error - just save errored state for endpoint.
+		ep.DiscInfo.LastStatus = rf.UnexpectedErrorPreStore
+		_, err = db.UpdateAllForRFEndpoint(ep, nil, nil, nil, nil, nil)
+		if err == nil {
+			// Return initial reason for failure.
+			return preStoreErr
+		} else {
+			return err
+		}
+	} else if err != nil {
 		// Unexpected error storing endpoint's data.
-		s.LogAlways("UpdateAllForRFEndpoint(%s): Fatal error storing: %s",
+		dlog.LogAlways("UpdateAllForRFEndpoint(%s): Fatal error storing: %s",
 			rfEP.ID, err)
 		// Try to update just the endpoint to store this failed status.
 		ep.DiscInfo.LastStatus = rf.StoreFailed
 		savedErr = err
 		_, err = s.db.UpdateRFEndpoint(ep)
 		if err != nil {
-			s.LogAlways("UpdateRFEndpoint(%s): Second fatal error storing: %s",
+			dlog.LogAlways("UpdateRFEndpoint(%s): Second fatal error storing: %s",
 				rfEP.ID, err)
 		}
 		return savedErr
 	}
+	s.recordDiscoveryChanges(ep.ID, diffDiscoveries(ep.ID, stored, discovered))
 	if discoveredComps != nil {
 		scnMap := make(map[string][]string)
 		// Send a SCN for each state for all of the new components and components that have updated states.
@@ -371,6 +462,10 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 			s.wp.Queue(scn)
 		}
 	}
+	// Flag components that now share a MAC with another, after the SCNs
+	// for their new states.
+	s.updateMACConflicts(ep.ID, ceis, macOwners, macOwnerEPs)
+
 	// Store Credentials in Vault for the discovered componentEndpoints. This
 	// is done if either readVault or writeVault is true because HSM is the one
 	// discovering these components and thus, if Vault is being used, HSM must
@@ -378,37 +473,109 @@ func (s *SmD) updateFromRfEndpoint(rfEP *rf.RedfishEP) error {
 	if s.readVault || s.writeVault {
 		// Don't store empty credentials
 		if len(savedPw) > 0 {
-			for _, cep := range ceps.ComponentEndpoints {
-				cred := compcreds.CompCredentials{
-					Xname:    cep.ID,
-					URL:      cep.URL,
-					Username: savedUn,
-					Password: savedPw,
-				}
+			for _, cred := range creds {
 				err := s.ccs.StoreCompCred(cred)
 				if err != nil {
 					// If we fail to store credentials in vault, we'll lose the
 					// credentials and the component endpoints associated with
 					// them will still be successfully in the database.
-					s.LogAlways("Failed to store credentials for %s in Vault - %s", cep.ID, err)
+					dlog.With("xname", cred.Xname).LogAlways("Failed to store credentials for %s in the credential store - %s", cred.Xname, err)
 					savedErr = err
 				}
 			}
 		}
 	}
 
-	// Generate HWInv History Entries
-	err = s.GenerateHWInvHist(hwlocs)
+	// Return "main" error as far as whether discovered info could be written.
+	return savedErr
+}
+
+// Create the HMS-level structures for one part of a discovered endpoint
+// (see rf.RedfishEP.Parts()).  Returns an error only if it is fatal, i.e.
+// nothing should be stored for the endpoint.
+func (s *SmD) discoverRFEndpointBatch(ep *sm.RedfishEndpoint, rfEP *rf.RedfishEP) (*hmsds.RFEndpointBatch, error) {
+	dlog := s.logWith(LogSubsysDiscovery).With("endpoint_id", rfEP.ID)
+	var err error
+	b := new(hmsds.RFEndpointBatch)
+
+	// Add/update component endpoints
+	b.CompEndpoints, err = s.DiscoverComponentEndpointArray(rfEP)
 	if err != nil {
-		// Unexpected error storing HWInv history entries.
-		s.LogAlways("GenerateHWInvHist(): Fatal error storing: %s", err)
-		if savedErr == nil {
-			return err
+		// These error types shouldn't happen, but may fail every time
+		// so better to skip them and store the remaining, valid components.
+		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
+			dlog.LogAlways("DiscoverComponentEndpointArray(%s): One or more: %s",
+				rfEP.ID, err)
+		} else {
+			dlog.LogAlways("DiscoverComponentEndpointArray(%s): Fatal storing: %s",
+				rfEP.ID, err)
+			return nil, err
+		}
+	}
+	//Add/update component ethernet interface
+	b.CompEthInterfaces = s.DiscoverCompEthInterfaceArray(ep, b.CompEndpoints)
+	// Add/update service endpoints
+	b.ServiceEndpoints = s.DiscoverServiceEndpointArray(rfEP)
+	// Add/update Hardware Inventory (FRU info, etc.) entries
+	b.HWInvByLocs, err = s.DiscoverHWInvByLocArray(rfEP)
+	if err != nil {
+		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
+			// Non-fatal, one or more components wasn't supported.  Likely to
+			// recur if discovery re-run.
+			dlog.LogAlways("DiscoverHWInvByLocArray(%s): One or more: %s",
+				rfEP.ID, err)
+		} else {
+			dlog.LogAlways("DiscoverHWInvByLocArray(%s): Fatal error storing: %s",
+				rfEP.ID, err)
+			return nil, err
+		}
+	}
+	// Add HMS component entries (NID, state, role, etc.)
+	comps, err := s.DiscoverComponentArray(rfEP)
+	if err != nil {
+		if err == base.ErrHMSTypeInvalid || err == base.ErrHMSTypeUnsupported {
+			// Non-fatal, one or more components wasn't supported.  Likely to
+			// recur if discovery re-run.
+			dlog.LogAlways("DiscoverComponentArray(%s): One or more: %s",
+				rfEP.ID, err)
+		} else {
+			dlog.LogAlways("DiscoverComponentArray(%s): Fatal error storing: %s",
+				rfEP.ID, err)
+			return nil, err
 		}
 	}
+	b.Components = comps
 
-	// Return "main" error as far as whether discovered info could be written.
-	return savedErr
+	// Get hartbeating status from HBTD to make sure nodes previously set to 'Ready'
+	// go back to 'Ready' if they are still heartbeating.  Without HBTD, use
+	// the heartbeats reported to HSM itself, see heartbeats.go.
+	if comps != nil && len(comps.Components) > 0 {
+		compMap := make(map[string]*base.Component)
+		compList := make([]string, 0, 1)
+		for _, comp := range comps.Components {
+			if comp.Type == xnametypes.Node.String() && comp.State == base.StateOn.String() {
+				compMap[comp.ID] = comp
+				compList = append(compList, comp.ID)
+			}
+		}
+		if s.hbtd != nil {
+			results, err := s.hbtd.GetHeartbeatStatus(compList)
+			if err != nil {
+				dlog.LogAlways("GetHeartbeatStatus(): Could not retrieve heartbeat status: %s", err)
+			} else {
+				for _, stat := range results {
+					comp, ok := compMap[stat.XName]
+					if ok && stat.Heartbeating {
+						comp.State = base.StateReady.String()
+					}
+				}
+			}
+		} else if len(compList) > 0 {
+			s.setReadyIfHeartbeating(compMap, compList)
+		}
+	}
+
+	return b, nil
 }
 
 ////////////////////////////////////////////////////////////////////////////
@@ -861,11 +1028,9 @@ func (s *SmD) DiscoverHWInvByLocArray(rfEP *rf.RedfishEP) ([]*sm.HWInvByLoc, err
 //     adding to the new location. (Removal event wasn't generated)
 //
 // If a eventType is specified, all generated entries will be forced to that
-// event type.
+// event type.  The FRU sightings for hwlocs are recorded as well.
 func (s *SmD) GenerateHWInvHist(hwlocs []*sm.HWInvByLoc) error {
-	hwhists := make([]*sm.HWInvHist, 0, 1)
 	locIDs := make([]string, 0, len(hwlocs))
-	lhsMap := make(map[string]*sm.HWInvHist, 0)
 
 	// Get a list of the LocIDs
 	for _, hwloc := range hwlocs {
@@ -879,33 +1044,16 @@ func (s *SmD) GenerateHWInvHist(hwlocs []*sm.HWInvByLoc) error {
 	if err != nil {
 		return err
 	}
-	// Create a map linking the locIDs to the last recorded history entry
-	for _, lh := range lhs {
-		lhsMap[lh.ID] = lh
-	}
-	for _, hwloc := range hwlocs {
-		// Skip hwlocs that have no FRU
-		if hwloc == nil || hwloc.PopulatedFRU == nil {
-			continue
-		}
-		newHist := sm.HWInvHist{
-			ID:        hwloc.ID,
-			FruId:     hwloc.PopulatedFRU.FRUID,
-			EventType: sm.HWInvHistEventTypeDetected,
-		}
-		// Only create a new 'detected' event if the previous event for that location
-		// is not a Location+FRUID+EventType duplicate.
-		if lastHist, ok := lhsMap[hwloc.ID]; !ok ||
-		   lastHist.FruId != hwloc.PopulatedFRU.FRUID ||
-		   lastHist.EventType != sm.HWInvHistEventTypeDetected {
-			hwhists = append(hwhists, &newHist)
-		}
-	}
+	hwhists := sm.NewHWInvHistsDetected(hwlocs, lhs)
 	if len(hwhists) > 0 {
 		// Insert the history events into the database
 		err = s.db.InsertHWInvHists(hwhists)
+		if err != nil {
+			return err
+		}
 	}
-	return err
+	// Track where each FRU has been seen.
+	return s.db.RecordHWInvSightings(hwlocs)
 }
 
 // Most components above nodes except controllers/BMCs are
@@ -1872,7 +2020,8 @@ func (s *SmD) DiscoverServiceEndpointArray(rfEP *rf.RedfishEP) *sm.ServiceEndpoi
 		sep.ServiceDescription = rfEP.UpdateService.ServiceDescription
 		sep.RfEndpointFQDN = rfEP.UpdateService.RootFQDN
 		sep.URL = rfEP.UpdateService.UpdateServiceURL
-		infoJSON, err := json.Marshal(rfEP.UpdateService.UpdateServiceRF)
+		// Keeps the firmware inventory, for querying firmware versions
+		infoJSON, err := json.Marshal(rfEP.UpdateService.Info())
 		if err != nil {
 			// This should never fail
 			s.LogAlways("DiscoverServiceEndpointArray: decode UpdateServiceInfo: %s", err)
@@ -1881,5 +2030,36 @@ func (s *SmD) DiscoverServiceEndpointArray(rfEP *rf.RedfishEP) *sm.ServiceEndpoi
 			seps.ServiceEndpoints = append(seps.ServiceEndpoints, sep)
 		}
 	}
+	if rfEP.TelemetryService != nil {
+		sep := new(sm.ServiceEndpoint)
+
+		sep.ServiceDescription = rfEP.TelemetryService.ServiceDescription
+		sep.RfEndpointFQDN = rfEP.TelemetryService.RootFQDN
+		sep.URL = rfEP.TelemetryService.TelemetryServiceURL
+		infoJSON, err := json.Marshal(rfEP.TelemetryService.TelemetryServiceRF)
+		if err != nil {
+			// This should never fail
+			s.LogAlways("DiscoverServiceEndpointArray: decode TelemetryServiceInfo: %s", err)
+		} else {
+			sep.ServiceInfo = json.RawMessage(infoJSON)
+			seps.ServiceEndpoints = append(seps.ServiceEndpoints, sep)
+		}
+	}
+	if rfEP.CertificateService != nil {
+		sep := new(sm.ServiceEndpoint)
+
+		sep.ServiceDescription = rfEP.CertificateService.ServiceDescription
+		sep.RfEndpointFQDN = rfEP.CertificateService.RootFQDN
+		sep.URL = rfEP.CertificateService.CertificateServiceURL
+		// Keeps the action targets, for driving certificate replacement
+		infoJSON, err := json.Marshal(rfEP.CertificateService.Info())
+		if err != nil {
+			// This should never fail
+			s.LogAlways("DiscoverServiceEndpointArray: decode CertificateServiceInfo: %s", err)
+		} else {
+			sep.ServiceInfo = json.RawMessage(infoJSON)
+			seps.ServiceEndpoints = append(seps.ServiceEndpoints, sep)
+		}
+	}
 	return seps
 }
@@ -0,0 +1,76 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+func TestUpdateFromRfEndpointBatches(t *testing.T) {
+	rfEP := &rf.RedfishEP{}
+	rfEP.ID = "x1000c0b0"
+	rfEP.Type = "ChassisBMC"
+	rfEP.DiscInfo.LastStatus = rf.DiscoverOK
+	rfEP.AccountService = &rf.EpAccountService{}
+	rfEP.Chassis.OIDs = make(map[string]*rf.EpChassis)
+	for _, id := range []string{"x1000c0s0", "x1000c0s1"} {
+		chEP := &rf.EpChassis{}
+		chEP.ID = id
+		chEP.Type = "ComputeModule"
+		chEP.Status = "Empty"
+		chEP.DefaultClass = "Mountain"
+		chEP.LastStatus = rf.DiscoverOK
+		rfEP.Chassis.OIDs["Blade"+id[len(id)-1:]] = chEP
+	}
+	results.UpdateAllForRFEndpointBatches.Return.discoveredIds = nil
+	results.UpdateAllForRFEndpointBatches.Return.err = nil
+
+	if err := s.updateFromRfEndpoint(rfEP); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	batches := results.UpdateAllForRFEndpointBatches.Input.batches
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(batches))
+	}
+	for i, b := range batches {
+		expID := []string{"x1000c0s0", "x1000c0s1"}[i]
+		if b.Components == nil || len(b.Components.Components) != 1 ||
+			b.Components.Components[0].ID != expID {
+			t.Errorf("Batch %d: Expected component %s, got %v", i, expID,
+				b.Components)
+		}
+		if len(b.HWInvByLocs) != 1 || b.HWInvByLocs[0].ID != expID {
+			t.Errorf("Batch %d: Expected HWInv location %s", i, expID)
+		}
+		if numSvcs := len(b.ServiceEndpoints.ServiceEndpoints); numSvcs != 1-i {
+			t.Errorf("Batch %d: Expected %d service endpoints, got %d",
+				i, 1-i, numSvcs)
+		}
+	}
+	if ep := results.UpdateAllForRFEndpointBatches.Input.ep; ep == nil ||
+		ep.ID != rfEP.ID {
+		t.Errorf("Expected RedfishEndpoint %s to be stored", rfEP.ID)
+	}
+}
@@ -0,0 +1,175 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Discovery circuit breaker
+//
+// A BMC that keeps failing discovery, e.g. a CMM under load that times out
+// or answers 503 even after the Redfish-level retries, is left alone for a
+// while rather than being rediscovered on every trigger.  After Threshold
+// failed discoveries in a row the endpoint's circuit opens for Cooldown,
+// doubled each time it opens again up to MaxCooldown.  A discovery asked
+// for while the circuit is open is deferred until the cooldown is over,
+// when a single attempt is let through; success closes the circuit again.
+// Forced discoveries always go through.
+///////////////////////////////////////////////////////////////////////////////
+
+type DiscoveryBreakerPolicy struct {
+	Threshold   int           // Failures in a row to open, 0 to disable
+	Cooldown    time.Duration // How long it first stays open
+	MaxCooldown time.Duration // Longest it stays open after doubling
+}
+
+var DefaultDiscoveryBreakerPolicy = DiscoveryBreakerPolicy{
+	Threshold:   3,
+	Cooldown:    5 * time.Minute,
+	MaxCooldown: time.Hour,
+}
+
+type discBreakerState struct {
+	failures  int
+	cooldown  time.Duration // Used the last time it opened
+	openUntil time.Time
+	deferred  bool // A deferred discovery is scheduled
+}
+
+// Per-RedfishEndpoint circuit breakers.  A nil *DiscoveryBreaker allows
+// everything.
+type DiscoveryBreaker struct {
+	lock   sync.Mutex
+	policy DiscoveryBreakerPolicy
+	eps    map[string]*discBreakerState
+}
+
+func NewDiscoveryBreaker(policy DiscoveryBreakerPolicy) *DiscoveryBreaker {
+	return &DiscoveryBreaker{
+		policy: policy,
+		eps:    make(map[string]*discBreakerState),
+	}
+}
+
+// Check whether an endpoint may be discovered now.  If not, returns how
+// long until it may be, and whether the caller should schedule a deferred
+// discovery for then, which only the first caller is told to do.
+func (b *DiscoveryBreaker) Allow(id string, now time.Time) (ok bool, wait time.Duration, schedule bool) {
+	if b == nil || b.policy.Threshold <= 0 {
+		return true, 0, false
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	st, found := b.eps[id]
+	if !found || !now.Before(st.openUntil) {
+		if found {
+			st.deferred = false
+		}
+		return true, 0, false
+	}
+	schedule = !st.deferred
+	st.deferred = true
+	return false, st.openUntil.Sub(now), schedule
+}
+
+// Record the outcome of a discovery.  Returns how long the circuit is now
+// open for, or 0 if it is closed.
+func (b *DiscoveryBreaker) Record(id string, failed bool, now time.Time) time.Duration {
+	if b == nil || b.policy.Threshold <= 0 {
+		return 0
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if !failed {
+		delete(b.eps, id)
+		return 0
+	}
+	st, ok := b.eps[id]
+	if !ok {
+		st = new(discBreakerState)
+		b.eps[id] = st
+	}
+	st.failures++
+	if st.failures < b.policy.Threshold {
+		return 0
+	}
+	if st.cooldown == 0 {
+		st.cooldown = b.policy.Cooldown
+	} else {
+		st.cooldown *= 2
+	}
+	if b.policy.MaxCooldown > 0 && st.cooldown > b.policy.MaxCooldown {
+		st.cooldown = b.policy.MaxCooldown
+	}
+	st.openUntil = now.Add(st.cooldown)
+	return st.cooldown
+}
+
+// True if the discovery of an endpoint should go ahead.  If its circuit is
+// open the discovery is deferred until it half-opens, unless force is set.
+func (s *SmD) discoveryAllowed(id string, force bool) bool {
+	if force {
+		return true
+	}
+	ok, wait, schedule := s.discBreaker.Allow(id, time.Now())
+	if ok {
+		return true
+	}
+	s.logWith(LogSubsysDiscovery).With("endpoint_id", id).LogAlways(
+		"Deferring discovery of %s for %s after repeated failures",
+		id, wait.Round(time.Second))
+	if schedule {
+		time.AfterFunc(wait, func() { s.deferredDiscovery(id) })
+	}
+	return false
+}
+
+// Run a discovery that was deferred by the circuit breaker.
+func (s *SmD) deferredDiscovery(id string) {
+	ep, err := s.db.GetRFEndpointByID(id)
+	if err != nil {
+		s.logWith(LogSubsysDiscovery).With("endpoint_id", id).LogAlways(
+			"Deferred discovery of %s: Lookup failure: %s", id, err)
+		return
+	} else if ep == nil {
+		return
+	}
+	s.discoverFromEndpoint(ep, 0, false)
+}
+
+// Feed the outcome of contacting an endpoint to its circuit breaker.
+func (s *SmD) recordDiscovery(rfEP *rf.RedfishEP) {
+	failed := rfEP.DiscInfo.LastStatus == rf.HTTPsGetFailed
+	if cooldown := s.discBreaker.Record(rfEP.ID, failed, time.Now()); cooldown > 0 {
+		s.logWith(LogSubsysDiscovery).With("endpoint_id", rfEP.ID).LogAlways(
+			"Warning: %s keeps failing discovery, not rediscovering "+
+				"it for %s", rfEP.ID, cooldown)
+	}
+}
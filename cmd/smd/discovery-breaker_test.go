@@ -0,0 +1,91 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiscoveryBreaker(t *testing.T) {
+	b := NewDiscoveryBreaker(DiscoveryBreakerPolicy{
+		Threshold:   2,
+		Cooldown:    time.Minute,
+		MaxCooldown: 3 * time.Minute,
+	})
+	now := time.Now()
+	const id = "x1000c0b0"
+
+	allow := func(step string, expOK, expSchedule bool) {
+		t.Helper()
+		ok, _, schedule := b.Allow(id, now)
+		if ok != expOK || schedule != expSchedule {
+			t.Errorf("%s: Expected allow %t schedule %t, got %t %t",
+				step, expOK, expSchedule, ok, schedule)
+		}
+	}
+	record := func(step string, failed bool, expCooldown time.Duration) {
+		t.Helper()
+		if cooldown := b.Record(id, failed, now); cooldown != expCooldown {
+			t.Errorf("%s: Expected cooldown %s, got %s",
+				step, expCooldown, cooldown)
+		}
+	}
+
+	record("first failure", true, 0)
+	allow("below threshold", true, false)
+	record("second failure", true, time.Minute)
+	allow("open", false, true)
+	allow("open, already deferred", false, false)
+	if ok, _, _ := b.Allow("x1000c0b1", now); !ok {
+		t.Errorf("Other endpoints should not be affected")
+	}
+
+	// Half-open after the cooldown; failing again doubles it, up to the max
+	now = now.Add(time.Minute)
+	allow("half-open", true, false)
+	record("half-open failure", true, 2*time.Minute)
+	allow("re-opened", false, true)
+	now = now.Add(2 * time.Minute)
+	record("half-open failure 2", true, 3*time.Minute)
+
+	// Success closes it
+	now = now.Add(3 * time.Minute)
+	allow("half-open 2", true, false)
+	record("success", false, 0)
+	record("new first failure", true, 0)
+	allow("closed", true, false)
+
+	// Disabled and nil breakers allow everything
+	var nilBreaker *DiscoveryBreaker
+	if ok, _, _ := nilBreaker.Allow(id, now); !ok || nilBreaker.Record(id, true, now) != 0 {
+		t.Errorf("nil breaker should allow everything")
+	}
+	off := NewDiscoveryBreaker(DiscoveryBreakerPolicy{Threshold: 0})
+	for i := 0; i < 5; i++ {
+		off.Record(id, true, now)
+	}
+	if ok, _, _ := off.Allow(id, now); !ok {
+		t.Errorf("disabled breaker should allow everything")
+	}
+}
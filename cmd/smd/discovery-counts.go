@@ -0,0 +1,335 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component count anomalies
+//
+// Storing a discovery replaces everything previously discovered through the
+// endpoint, so a chassis that suddenly reports zero blades, e.g. because its
+// controller is half booted, would silently delete all of them.  The number
+// of components each discovery finds is kept for every endpoint, and one
+// that drops by more than DropPct percent from the last accepted count, or
+// that goes over the soft Quota, is an anomaly.  Depending on Action, an
+// anomaly is just logged (warn), or the discovery is thrown away and the
+// endpoint's existing inventory kept (hold) until an operator confirms the
+// new counts with POST /Inventory/DiscoveryHolds/{xname}/Actions/Confirm,
+// which rediscovers it and accepts the result.  Counts and holds are kept in
+// memory; the first count for an endpoint after a restart is compared with
+// what is in the database.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	CompCountActionOff  = "off"
+	CompCountActionWarn = "warn"
+	CompCountActionHold = "hold"
+)
+
+type CompCountPolicy struct {
+	Action  string // One of the CompCountAction* values
+	DropPct int    // Drop from the last accepted count that is an anomaly
+	Quota   int    // Soft limit on components per endpoint, 0 for none
+	History int    // Counts kept for each endpoint
+}
+
+var DefaultCompCountPolicy = CompCountPolicy{
+	Action:  CompCountActionWarn,
+	DropPct: 50,
+	History: 10,
+}
+
+// Returned by the discovery's batch function to throw away a held discovery.
+var errCompCountHeld = errors.New("component count anomaly, discovery held")
+
+// One discovery's number of components.
+type CompCountSample struct {
+	Count int    `json:"Count"`
+	Time  string `json:"Time"`
+}
+
+// A discovery held for confirmation, as shown by GET /Inventory/DiscoveryHolds
+type CompCountHold struct {
+	ID              string            `json:"ID"`
+	Reason          string            `json:"Reason"`
+	PreviousCount   int               `json:"PreviousCount"`
+	DiscoveredCount int               `json:"DiscoveredCount"`
+	Since           string            `json:"Since"`
+	History         []CompCountSample `json:"History"`
+}
+
+type CompCountHoldArray struct {
+	DiscoveryHolds []CompCountHold `json:"DiscoveryHolds"`
+}
+
+// Per-RedfishEndpoint component counts.  A nil *CompCountTracker accepts
+// everything.
+type CompCountTracker struct {
+	lock      sync.Mutex
+	policy    CompCountPolicy
+	history   map[string][]CompCountSample
+	holds     map[string]*CompCountHold
+	confirmed map[string]bool // Accept the next discovery whatever it finds
+}
+
+func NewCompCountTracker(policy CompCountPolicy) *CompCountTracker {
+	return &CompCountTracker{
+		policy:    policy,
+		history:   make(map[string][]CompCountSample),
+		holds:     make(map[string]*CompCountHold),
+		confirmed: make(map[string]bool),
+	}
+}
+
+// True if the endpoint has no counts yet, so the one in the database
+// should be passed to Check().
+func (ct *CompCountTracker) NeedsBaseline(id string) bool {
+	if ct == nil || ct.policy.Action == CompCountActionOff {
+		return false
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	return len(ct.history[id]) == 0
+}
+
+// Check the number of components a discovery found against the endpoint's
+// last accepted count, or dbCount if it has none.  Returns why the count is
+// an anomaly, or "" if it isn't, and whether the discovery should be held.
+// Counts that aren't held become the new last accepted count.
+func (ct *CompCountTracker) Check(id string, count, dbCount int, now time.Time) (anomaly string, hold bool) {
+	if ct == nil || ct.policy.Action == CompCountActionOff {
+		return "", false
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+
+	prev := dbCount
+	if h := ct.history[id]; len(h) > 0 {
+		prev = h[len(h)-1].Count
+	}
+	if ct.policy.DropPct > 0 && prev > 0 &&
+		count*100 < prev*(100-ct.policy.DropPct) {
+		anomaly = fmt.Sprintf("component count dropped from %d to %d",
+			prev, count)
+	} else if ct.policy.Quota > 0 && count > ct.policy.Quota && count > prev {
+		anomaly = fmt.Sprintf("component count %d is over the quota of %d",
+			count, ct.policy.Quota)
+	}
+	if anomaly != "" && ct.policy.Action == CompCountActionHold &&
+		!ct.confirmed[id] {
+		hd, ok := ct.holds[id]
+		if !ok {
+			hd = &CompCountHold{ID: id, Since: now.UTC().Format(time.RFC3339)}
+			ct.holds[id] = hd
+		}
+		hd.Reason = anomaly
+		hd.PreviousCount = prev
+		hd.DiscoveredCount = count
+		return anomaly, true
+	}
+	delete(ct.confirmed, id)
+	delete(ct.holds, id)
+	h := append(ct.history[id], CompCountSample{
+		Count: count,
+		Time:  now.UTC().Format(time.RFC3339),
+	})
+	if ct.policy.History > 0 && len(h) > ct.policy.History {
+		h = h[len(h)-ct.policy.History:]
+	}
+	ct.history[id] = h
+	return anomaly, false
+}
+
+// Accept the next discovery of a held endpoint.  Returns false if it isn't
+// held.
+func (ct *CompCountTracker) Confirm(id string) bool {
+	if ct == nil {
+		return false
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	if _, ok := ct.holds[id]; !ok {
+		return false
+	}
+	delete(ct.holds, id)
+	ct.confirmed[id] = true
+	return true
+}
+
+// Drop the hold on an endpoint, keeping its existing inventory.  It is
+// checked again the next time it is discovered.  Returns false if it
+// isn't held.
+func (ct *CompCountTracker) Dismiss(id string) bool {
+	if ct == nil {
+		return false
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	if _, ok := ct.holds[id]; !ok {
+		return false
+	}
+	delete(ct.holds, id)
+	return true
+}
+
+// Forget an endpoint, e.g. when it is deleted.
+func (ct *CompCountTracker) Remove(id string) {
+	if ct == nil {
+		return
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	delete(ct.history, id)
+	delete(ct.holds, id)
+	delete(ct.confirmed, id)
+}
+
+// Forget all endpoints, e.g. when they are all deleted.
+func (ct *CompCountTracker) Reset() {
+	if ct == nil {
+		return
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	ct.history = make(map[string][]CompCountSample)
+	ct.holds = make(map[string]*CompCountHold)
+	ct.confirmed = make(map[string]bool)
+}
+
+// Held discoveries, sorted by ID.  If id is not empty, only that
+// endpoint's.
+func (ct *CompCountTracker) Holds(id string) []CompCountHold {
+	holds := []CompCountHold{}
+	if ct == nil {
+		return holds
+	}
+	ct.lock.Lock()
+	defer ct.lock.Unlock()
+	for hid, hd := range ct.holds {
+		if id != "" && hid != id {
+			continue
+		}
+		h := *hd
+		h.History = append([]CompCountSample{}, ct.history[hid]...)
+		holds = append(holds, h)
+	}
+	sort.Slice(holds, func(i, j int) bool {
+		return holds[i].ID < holds[j].ID
+	})
+	return holds
+}
+
+// Check the number of components discovered through an endpoint.  Returns
+// errCompCountHeld if the discovery should not be stored.
+func (s *SmD) checkCompCount(id string, count int) error {
+	dbCount := 0
+	if s.compCounts.NeedsBaseline(id) {
+		ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+			RfEndpointID: []string{id},
+		})
+		if err != nil {
+			s.LogAlways("checkCompCount(%s): Lookup failure: %s", id, err)
+		}
+		dbCount = len(ceps)
+	}
+	anomaly, hold := s.compCounts.Check(id, count, dbCount, time.Now())
+	if hold {
+		s.LogAlways("Warning: %s: %s, keeping existing inventory until "+
+			"confirmed", id, anomaly)
+		return errCompCountHeld
+	} else if anomaly != "" {
+		s.LogAlways("Warning: %s: %s", id, anomaly)
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// HTTP API
+/////////////////////////////////////////////////////////////////////////////
+
+// Get all discoveries held for confirmation
+func (s *SmD) doCompCountHoldsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, CompCountHoldArray{
+		DiscoveryHolds: s.compCounts.Holds(""),
+	})
+}
+
+// Get the held discovery of one endpoint
+func (s *SmD) doCompCountHoldGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	holds := s.compCounts.Holds(xname)
+	if len(holds) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no such held discovery.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, holds[0])
+}
+
+// Accept the component count of a held endpoint and rediscover it.
+func (s *SmD) doCompCountHoldConfirm(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	ep, err := s.db.GetRFEndpointByID(xname)
+	if err != nil {
+		s.LogAlways("doCompCountHoldConfirm(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	} else if ep == nil || !s.compCounts.Confirm(xname) {
+		sendJsonError(w, http.StatusNotFound, "no such held discovery.")
+		return
+	}
+	go s.discoverFromEndpoints([]*sm.RedfishEndpoint{ep}, 0, false, true)
+	sendJsonError(w, http.StatusAccepted,
+		"confirmed, rediscovering "+xname)
+}
+
+// Drop the hold on an endpoint, keeping its existing inventory.
+func (s *SmD) doCompCountHoldDelete(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !s.compCounts.Dismiss(xname) {
+		sendJsonError(w, http.StatusNotFound, "no such held discovery.")
+		return
+	}
+	sendJsonError(w, http.StatusOK, "deleted 1 entry")
+}
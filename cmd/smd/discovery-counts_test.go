@@ -0,0 +1,166 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+func TestCompCountTracker(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		policy    CompCountPolicy
+		counts    []int // Accepted in order before the check
+		dbCount   int
+		count     int
+		expAnomal bool
+		expHold   bool
+	}{
+		{"steady", DefaultCompCountPolicy, []int{8, 8}, 0, 8, false, false},
+		{"small drop", DefaultCompCountPolicy, []int{8}, 0, 5, false, false},
+		{"to zero", DefaultCompCountPolicy, []int{8}, 0, 0, true, false},
+		{"db baseline", DefaultCompCountPolicy, nil, 8, 2, true, false},
+		{"first discovery", DefaultCompCountPolicy, nil, 0, 8, false, false},
+		{"held", CompCountPolicy{Action: CompCountActionHold, DropPct: 50}, []int{8}, 0, 3, true, true},
+		{"off", CompCountPolicy{Action: CompCountActionOff, DropPct: 50}, []int{8}, 0, 0, false, false},
+		{"over quota", CompCountPolicy{Action: CompCountActionHold, Quota: 10}, []int{8}, 0, 12, true, true},
+		{"first over quota", CompCountPolicy{Action: CompCountActionHold, Quota: 10}, nil, 0, 12, true, true},
+		{"already over quota", CompCountPolicy{Action: CompCountActionHold, Quota: 10}, nil, 12, 12, false, false},
+	}
+	for _, test := range tests {
+		ct := NewCompCountTracker(test.policy)
+		for _, c := range test.counts {
+			ct.Check("x1000c0b0", c, 0, now)
+		}
+		anomaly, hold := ct.Check("x1000c0b0", test.count, test.dbCount, now)
+		if (anomaly != "") != test.expAnomal || hold != test.expHold {
+			t.Errorf("%s: FAIL: Expected anomaly %v hold %v, got '%s' %v",
+				test.name, test.expAnomal, test.expHold, anomaly, hold)
+		}
+		if holds := ct.Holds(""); (len(holds) == 1) != test.expHold {
+			t.Errorf("%s: FAIL: Unexpected holds %v", test.name, holds)
+		}
+	}
+
+	// A confirmed hold accepts the next count, which becomes the baseline
+	ct := NewCompCountTracker(CompCountPolicy{Action: CompCountActionHold, DropPct: 50})
+	ct.Check("x1000c0b0", 8, 0, now)
+	if _, hold := ct.Check("x1000c0b0", 2, 0, now); !hold {
+		t.Fatalf("Expected hold")
+	}
+	if ct.Confirm("x1000c0b1") || !ct.Confirm("x1000c0b0") {
+		t.Errorf("Confirm of wrong endpoint")
+	}
+	if _, hold := ct.Check("x1000c0b0", 2, 0, now); hold {
+		t.Errorf("Confirmed count held")
+	}
+	if anomaly, hold := ct.Check("x1000c0b0", 2, 0, now); anomaly != "" || hold {
+		t.Errorf("Confirmed count not the new baseline: '%s' %v", anomaly, hold)
+	}
+	// A dismissed hold is checked again
+	ct.Check("x1000c0b0", 0, 0, now)
+	if !ct.Dismiss("x1000c0b0") || len(ct.Holds("")) != 0 {
+		t.Errorf("Dismiss failed")
+	}
+	if _, hold := ct.Check("x1000c0b0", 0, 0, now); !hold {
+		t.Errorf("Dismissed count not held again")
+	}
+}
+
+func TestUpdateFromRfEndpointHeld(t *testing.T) {
+	defer func() {
+		s.compCounts = nil
+		results.UpdateRFEndpoint.Input.ep = nil
+	}()
+	s.compCounts = NewCompCountTracker(CompCountPolicy{
+		Action:  CompCountActionHold,
+		DropPct: 50,
+	})
+	s.compCounts.Check("x1000c0b0", 8, 0, time.Now())
+
+	rfEP := &rf.RedfishEP{}
+	rfEP.ID = "x1000c0b0"
+	rfEP.Type = "ChassisBMC"
+	rfEP.DiscInfo.LastStatus = rf.DiscoverOK
+	rfEP.AccountService = &rf.EpAccountService{}
+	rfEP.Chassis.OIDs = make(map[string]*rf.EpChassis)
+	chEP := &rf.EpChassis{}
+	chEP.ID = "x1000c0s0"
+	chEP.Type = "ComputeModule"
+	chEP.Status = "Empty"
+	chEP.DefaultClass = "Mountain"
+	chEP.LastStatus = rf.DiscoverOK
+	rfEP.Chassis.OIDs["Blade0"] = chEP
+	results.UpdateAllForRFEndpointBatches.Return.discoveredIds = nil
+	results.UpdateAllForRFEndpointBatches.Return.err = nil
+
+	if err := s.updateFromRfEndpoint(rfEP); err != errCompCountHeld {
+		t.Fatalf("Expected discovery to be held, got %v", err)
+	}
+	if ep := results.UpdateRFEndpoint.Input.ep; ep == nil ||
+		ep.DiscInfo.LastStatus != rf.DiscoveryHeld {
+		t.Errorf("Expected endpoint to be stored as %s", rf.DiscoveryHeld)
+	}
+
+	get := func(uri string, expCode int) []byte {
+		req, _ := http.NewRequest("GET", uri, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != expCode {
+			t.Fatalf("GET %s: Response code was %v; want %v",
+				uri, w.Code, expCode)
+		}
+		return w.Body.Bytes()
+	}
+	var holds CompCountHoldArray
+	json.Unmarshal(get("https://localhost/hsm/v2/Inventory/DiscoveryHolds", http.StatusOK), &holds)
+	if len(holds.DiscoveryHolds) != 1 ||
+		holds.DiscoveryHolds[0].ID != "x1000c0b0" ||
+		holds.DiscoveryHolds[0].PreviousCount != 8 ||
+		holds.DiscoveryHolds[0].DiscoveredCount != 1 {
+		t.Errorf("Unexpected holds: %+v", holds)
+	}
+	get("https://localhost/hsm/v2/Inventory/DiscoveryHolds/x1000c0b0", http.StatusOK)
+	get("https://localhost/hsm/v2/Inventory/DiscoveryHolds/x1000c1b0", http.StatusNotFound)
+
+	// Confirmed, so the next discovery is stored
+	s.compCounts.Confirm("x1000c0b0")
+	if err := s.updateFromRfEndpoint(rfEP); err != nil {
+		t.Errorf("Unexpected error after confirmation: %s", err)
+	}
+
+	req, _ := http.NewRequest("DELETE",
+		"https://localhost/hsm/v2/Inventory/DiscoveryHolds/x1000c0b0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("DELETE of confirmed hold: Response code was %v; want 404", w.Code)
+	}
+}
@@ -0,0 +1,334 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Discovery differences
+//
+// Storing a discovery replaces everything previously discovered through the
+// endpoint.  Before it is stored, what the discovery found is compared with
+// what was there: ComponentEndpoints added, removed or with different
+// Redfish fields, and FRUs added, removed or moved to another location.  A
+// FRU replaced by another shows up as one removed and one added at the same
+// location.  The first discovery of an endpoint has nothing to compare with
+// and reports no differences.
+//
+// The differences for the endpoints a discovery covered are put in the
+// Details of its DiscoveryStatus, and if SMD_HW_CHANGE_NOTIFY_URL is set
+// each one is also POSTed there as a hardware changed notification.
+///////////////////////////////////////////////////////////////////////////////
+
+// HWInventory types that never have ComponentEndpoints of their own and
+// belong to whichever component they are under.
+var discDiffSubcompTypes = map[xnametypes.HMSType]bool{
+	xnametypes.Processor:                true,
+	xnametypes.NodeAccel:                true,
+	xnametypes.Memory:                   true,
+	xnametypes.StorageGroup:             true,
+	xnametypes.Drive:                    true,
+	xnametypes.NodeHsnNic:               true,
+	xnametypes.NodeAccelRiser:           true,
+	xnametypes.CMMRectifier:             true,
+	xnametypes.NodeEnclosurePowerSupply: true,
+}
+
+// What is compared for each ComponentEndpoint
+type discDiffComp struct {
+	Type           string
+	RedfishType    string
+	RedfishSubtype string
+	MACAddr        string
+	UUID           string
+	OdataID        string
+}
+
+// Fields that differ between two ComponentEndpoints
+func (c discDiffComp) changedFields(o discDiffComp) []string {
+	fields := []string{}
+	for _, f := range []struct {
+		name     string
+		old, new string
+	}{
+		{"Type", o.Type, c.Type},
+		{"RedfishType", o.RedfishType, c.RedfishType},
+		{"RedfishSubtype", o.RedfishSubtype, c.RedfishSubtype},
+		{"MACAddr", o.MACAddr, c.MACAddr},
+		{"UUID", o.UUID, c.UUID},
+		{"OdataID", o.OdataID, c.OdataID},
+	} {
+		if f.old != f.new {
+			fields = append(fields, f.name)
+		}
+	}
+	return fields
+}
+
+// The components and FRUs of one endpoint, as stored or as discovered.
+type discoverySnapshot struct {
+	comps map[string]discDiffComp // by xname
+	frus  map[string]string       // location xname by FRUID
+}
+
+func newDiscoverySnapshot() *discoverySnapshot {
+	return &discoverySnapshot{
+		comps: make(map[string]discDiffComp),
+		frus:  make(map[string]string),
+	}
+}
+
+func (ds *discoverySnapshot) addCompEndpoints(ceps []*sm.ComponentEndpoint) {
+	for _, cep := range ceps {
+		ds.comps[cep.ID] = discDiffComp{
+			Type:           cep.Type,
+			RedfishType:    cep.RedfishType,
+			RedfishSubtype: cep.RedfishSubtype,
+			MACAddr:        cep.MACAddr,
+			UUID:           cep.UUID,
+			OdataID:        cep.OdataID,
+		}
+	}
+}
+
+func (ds *discoverySnapshot) addHWInvByLocs(hwlocs []*sm.HWInvByLoc) {
+	for _, hwloc := range hwlocs {
+		if hwloc.PopulatedFRU != nil && hwloc.PopulatedFRU.FRUID != "" {
+			ds.frus[hwloc.PopulatedFRU.FRUID] = hwloc.ID
+		}
+	}
+}
+
+// Drop the FRUs of locations that are not at or under one of the given
+// components, e.g. those of nodes under a blade that belong to another
+// endpoint.
+func (ds *discoverySnapshot) keepFRUsOf(comps map[string]bool) {
+	for fruID, loc := range ds.frus {
+		owner := loc
+		for discDiffSubcompTypes[xnametypes.GetHMSType(owner)] {
+			owner = xnametypes.GetHMSCompParent(owner)
+		}
+		if !comps[owner] {
+			delete(ds.frus, fruID)
+		}
+	}
+}
+
+// Differences going from an endpoint's stored snapshot to the one just
+// discovered, sorted by ID.
+func diffDiscoveries(epID string, old, new *discoverySnapshot) []sm.DiscoveryChange {
+	changes := []sm.DiscoveryChange{}
+	if len(old.comps) == 0 {
+		return changes
+	}
+	comps := make(map[string]bool)
+	for id := range old.comps {
+		comps[id] = true
+	}
+	for id := range new.comps {
+		comps[id] = true
+	}
+	old.keepFRUsOf(comps)
+	new.keepFRUsOf(comps)
+
+	change := func(kind, id, chg string) sm.DiscoveryChange {
+		return sm.DiscoveryChange{
+			RedfishEndpointID: epID,
+			Kind:              kind,
+			ID:                id,
+			Change:            chg,
+		}
+	}
+	for id, c := range new.comps {
+		o, ok := old.comps[id]
+		if !ok {
+			changes = append(changes,
+				change(sm.DiscChangeKindComponent, id, sm.DiscChangeAdded))
+		} else if fields := c.changedFields(o); len(fields) != 0 {
+			chg := change(sm.DiscChangeKindComponent, id, sm.DiscChangeChanged)
+			chg.Fields = fields
+			changes = append(changes, chg)
+		}
+	}
+	for id := range old.comps {
+		if _, ok := new.comps[id]; !ok {
+			changes = append(changes,
+				change(sm.DiscChangeKindComponent, id, sm.DiscChangeRemoved))
+		}
+	}
+	for fruID, loc := range new.frus {
+		oldLoc, ok := old.frus[fruID]
+		if ok && oldLoc == loc {
+			continue
+		}
+		chg := change(sm.DiscChangeKindFRU, loc, sm.DiscChangeAdded)
+		chg.FRUID = fruID
+		if ok {
+			chg.Change = sm.DiscChangeMoved
+			chg.From = oldLoc
+		}
+		changes = append(changes, chg)
+	}
+	for fruID, loc := range old.frus {
+		if _, ok := new.frus[fruID]; !ok {
+			chg := change(sm.DiscChangeKindFRU, loc, sm.DiscChangeRemoved)
+			chg.FRUID = fruID
+			changes = append(changes, chg)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].ID != changes[j].ID {
+			return changes[i].ID < changes[j].ID
+		}
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Change < changes[j].Change
+	})
+	return changes
+}
+
+// Differences found by the latest discovery of each endpoint, until they
+// are reported in a DiscoveryStatus.
+type DiscoveryChangeStore struct {
+	lock    sync.Mutex
+	changes map[string][]sm.DiscoveryChange
+}
+
+func (dc *DiscoveryChangeStore) set(epID string, changes []sm.DiscoveryChange) {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+	if dc.changes == nil {
+		dc.changes = make(map[string][]sm.DiscoveryChange)
+	}
+	dc.changes[epID] = changes
+}
+
+// Remove and return the differences found for the given endpoints.
+func (dc *DiscoveryChangeStore) take(epIDs []string) []sm.DiscoveryChange {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+	changes := []sm.DiscoveryChange{}
+	for _, id := range epIDs {
+		changes = append(changes, dc.changes[id]...)
+		delete(dc.changes, id)
+	}
+	return changes
+}
+
+// The stored components and FRUs of an endpoint.
+func (s *SmD) storedDiscoverySnapshot(epID string) (*discoverySnapshot, error) {
+	ds := newDiscoverySnapshot()
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		RfEndpointID: []string{epID},
+	})
+	if err != nil || len(ceps) == 0 {
+		return ds, err
+	}
+	ds.addCompEndpoints(ceps)
+	ids := make([]string, 0, len(ceps))
+	for _, cep := range ceps {
+		ids = append(ids, cep.ID)
+	}
+	hwlocs, err := s.db.GetHWInvByLocFilter(hmsds.HWInvLoc_IDs(ids),
+		hmsds.HWInvLoc_Child, hmsds.HWInvLoc_From("storedDiscoverySnapshot"))
+	if err != nil {
+		return ds, err
+	}
+	ds.addHWInvByLocs(hwlocs)
+	return ds, nil
+}
+
+// Record the differences a discovery found and send them to
+// SMD_HW_CHANGE_NOTIFY_URL, if set.
+func (s *SmD) recordDiscoveryChanges(epID string, changes []sm.DiscoveryChange) {
+	s.discChanges.set(epID, changes)
+	if len(changes) == 0 {
+		return
+	}
+	for _, chg := range changes {
+		s.publishBusEvent(sm.BusHWInvChange, chg)
+	}
+	s.LogAlways("Discovery of %s found %d hardware change(s)", epID,
+		len(changes))
+	if s.hwChangeURL != "" {
+		go s.notifyDiscoveryChanges(changes)
+	}
+}
+
+// POST a hardware changed notification for each difference.
+func (s *SmD) notifyDiscoveryChanges(changes []sm.DiscoveryChange) {
+	client := s.GetHTTPClient()
+	for _, chg := range changes {
+		payload, err := json.Marshal(chg)
+		if err != nil {
+			s.LogAlways("notifyDiscoveryChanges(): Marshal: %s", err)
+			continue
+		}
+		req, err := retryablehttp.NewRequest("POST", s.hwChangeURL,
+			bytes.NewReader(payload))
+		if err != nil {
+			s.LogAlways("notifyDiscoveryChanges(): %s", err)
+			return
+		}
+		base.SetHTTPUserAgent(req.Request, serviceName)
+		req.Header.Set("Content-Type", "application/json")
+		rsp, err := client.Do(req)
+		if err != nil {
+			s.LogAlways("WARNING: Hardware change notification to %s failed: %s",
+				s.hwChangeURL, err)
+			continue
+		}
+		base.DrainAndCloseResponseBody(rsp)
+		if rsp.StatusCode >= http.StatusBadRequest {
+			s.LogAlways("WARNING: Hardware change notification to %s: %s",
+				s.hwChangeURL, rsp.Status)
+		}
+	}
+}
+
+// DiscoveryStatus Details for the differences found for the given
+// endpoints.
+func (s *SmD) discoveryChangeDetails(epIDs []string) *json.RawMessage {
+	details, err := json.Marshal(sm.DiscoveryDetails{
+		Changes: s.discChanges.take(epIDs),
+	})
+	if err != nil {
+		s.LogAlways("discoveryChangeDetails(): Marshal: %s", err)
+		return nil
+	}
+	raw := json.RawMessage(details)
+	return &raw
+}
@@ -0,0 +1,165 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func testDiscSnapshot(ceps map[string]string, frus map[string]string) *discoverySnapshot {
+	ds := newDiscoverySnapshot()
+	for id, oid := range ceps {
+		ds.addCompEndpoints([]*sm.ComponentEndpoint{{
+			ComponentDescription: rf.ComponentDescription{ID: id, OdataID: oid}}})
+	}
+	for loc, fruID := range frus {
+		ds.addHWInvByLocs([]*sm.HWInvByLoc{{
+			ID: loc, PopulatedFRU: &sm.HWInvByFRU{FRUID: fruID}}})
+	}
+	return ds
+}
+
+func TestDiffDiscoveries(t *testing.T) {
+	old := testDiscSnapshot(map[string]string{
+		"x1000c0s0b0":   "/redfish/v1/Managers/BMC",
+		"x1000c0s0b0n0": "/redfish/v1/Systems/Node0",
+		"x1000c0s0b0n1": "/redfish/v1/Systems/Node1",
+	}, map[string]string{
+		"x1000c0s0b0n0":     "Node-A",
+		"x1000c0s0b0n0d0":   "DIMM-A",
+		"x1000c0s0b0n0d1":   "DIMM-B",
+		"x1000c0s0b0n1p0":   "CPU-A",
+		"x1000c0s0b0n0g1k0": "Drive-A",
+		// Another endpoint's node under the same slot
+		"x1000c0s0b1n0": "Node-Other",
+	})
+	new := testDiscSnapshot(map[string]string{
+		"x1000c0s0b0":   "/redfish/v1/Managers/BMC",
+		"x1000c0s0b0n0": "/redfish/v1/Systems/Node0",
+		"x1000c0s0b0n2": "/redfish/v1/Systems/Node2",
+	}, map[string]string{
+		"x1000c0s0b0n0":     "Node-A",
+		"x1000c0s0b0n0d0":   "DIMM-B",
+		"x1000c0s0b0n0d1":   "DIMM-C",
+		"x1000c0s0b0n0g1k0": "Drive-A",
+	})
+	new.comps["x1000c0s0b0n0"] = discDiffComp{
+		OdataID: "/redfish/v1/Systems/Node0", UUID: "new-uuid"}
+
+	exp := []sm.DiscoveryChange{
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindComponent,
+			ID: "x1000c0s0b0n0", Change: sm.DiscChangeChanged,
+			Fields: []string{"UUID"}},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindFRU,
+			ID: "x1000c0s0b0n0d0", Change: sm.DiscChangeMoved,
+			FRUID: "DIMM-B", From: "x1000c0s0b0n0d1"},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindFRU,
+			ID: "x1000c0s0b0n0d0", Change: sm.DiscChangeRemoved,
+			FRUID: "DIMM-A"},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindFRU,
+			ID: "x1000c0s0b0n0d1", Change: sm.DiscChangeAdded,
+			FRUID: "DIMM-C"},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindComponent,
+			ID: "x1000c0s0b0n1", Change: sm.DiscChangeRemoved},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindFRU,
+			ID: "x1000c0s0b0n1p0", Change: sm.DiscChangeRemoved,
+			FRUID: "CPU-A"},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindComponent,
+			ID: "x1000c0s0b0n2", Change: sm.DiscChangeAdded},
+	}
+	changes := diffDiscoveries("x1000c0s0b0", old, new)
+	if !reflect.DeepEqual(changes, exp) {
+		t.Errorf("Expected:\n%+v\ngot:\n%+v", exp, changes)
+	}
+
+	// First discovery - nothing to compare with
+	if changes := diffDiscoveries("x1000c0s0b0", newDiscoverySnapshot(),
+		new); len(changes) != 0 {
+		t.Errorf("Unexpected changes on first discovery: %+v", changes)
+	}
+}
+
+func TestDiscoveryChangeNotify(t *testing.T) {
+	var lock sync.Mutex
+	got := []sm.DiscoveryChange{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			var chg sm.DiscoveryChange
+			json.Unmarshal(body, &chg)
+			lock.Lock()
+			got = append(got, chg)
+			lock.Unlock()
+		}))
+	defer server.Close()
+	defer func() {
+		s.hwChangeURL = ""
+		s.discChanges = DiscoveryChangeStore{}
+	}()
+	s.hwChangeURL = server.URL
+
+	changes := []sm.DiscoveryChange{
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindFRU,
+			ID: "x1000c0s0b0n0d0", Change: sm.DiscChangeAdded, FRUID: "DIMM-C"},
+		{RedfishEndpointID: "x1000c0s0b0", Kind: sm.DiscChangeKindComponent,
+			ID: "x1000c0s0b0n2", Change: sm.DiscChangeAdded},
+	}
+	s.recordDiscoveryChanges("x1000c0s0b0", changes)
+	s.recordDiscoveryChanges("x1000c0s1b0", []sm.DiscoveryChange{})
+	for i := 0; i < 50; i++ {
+		lock.Lock()
+		n := len(got)
+		lock.Unlock()
+		if n == len(changes) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	lock.Lock()
+	if !reflect.DeepEqual(got, changes) {
+		t.Errorf("Expected notifications %+v, got %+v", changes, got)
+	}
+	lock.Unlock()
+
+	var details sm.DiscoveryDetails
+	raw := s.discoveryChangeDetails([]string{"x1000c0s0b0", "x1000c0s1b0"})
+	if raw == nil || json.Unmarshal(*raw, &details) != nil ||
+		!reflect.DeepEqual(details.Changes, changes) {
+		t.Errorf("Unexpected DiscoveryStatus Details: %v", raw)
+	}
+	// Only reported once
+	raw = s.discoveryChangeDetails([]string{"x1000c0s0b0"})
+	if raw == nil || string(*raw) != `{"Changes":[]}` {
+		t.Errorf("Unexpected DiscoveryStatus Details: %v", raw)
+	}
+}
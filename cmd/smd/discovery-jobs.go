@@ -0,0 +1,400 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Discovery jobs
+//
+// Each POST /Inventory/Discover creates a job that tracks the discovery of
+// each RedfishEndpoint it covers:
+//
+//     Pending      waiting to start
+//     InProgress   being discovered
+//     Complete     discovered and stored
+//     Failed       discovery or storing failed, see LastDiscoveryStatus
+//     Skipped      not discovered, e.g. !Enabled or already in progress
+//     Cancelled    the job was cancelled before it started
+//
+// with counts of each, the number of Redfish resources that could not be
+// read, and an ETA assuming the rest take as long on average as those
+// done so far.  Cancelling a job stops endpoints that haven't started, and
+// leaves their status as it was; those already being discovered finish.
+// With SMD_DISCOVERY_CONCURRENCY set, at most that many endpoints of a
+// discovery are worked on at once, otherwise they all start together.
+//
+//     GET  /Inventory/DiscoveryJobs                       all jobs
+//     GET  /Inventory/DiscoveryJobs/{id}                  one job
+//     POST /Inventory/DiscoveryJobs/{id}/Actions/Cancel   cancel it
+//
+// Jobs are kept in memory by the instance running them, the last
+// SMD_DISCOVERY_JOB_KEEP finished ones along with those still running.
+///////////////////////////////////////////////////////////////////////////////
+
+// Status of a job and of each of its endpoints
+const (
+	DiscJobPending    = "Pending"
+	DiscJobInProgress = "InProgress"
+	DiscJobComplete   = "Complete"
+	DiscJobFailed     = "Failed"
+	DiscJobSkipped    = "Skipped"
+	DiscJobCancelled  = "Cancelled"
+)
+
+const DefaultDiscoveryJobKeep = 100
+
+type DiscoveryJobEndpoint struct {
+	ID                  string `json:"ID"`
+	Status              string `json:"Status"`
+	LastDiscoveryStatus string `json:"LastDiscoveryStatus,omitempty"`
+	Errors              int    `json:"Errors,omitempty"`
+	Reason              string `json:"Reason,omitempty"`
+	Started             string `json:"Started,omitempty"`
+	Finished            string `json:"Finished,omitempty"`
+}
+
+type DiscoveryJob struct {
+	ID         string                  `json:"ID"`
+	Status     string                  `json:"Status"`
+	Created    string                  `json:"Created"`
+	Finished   string                  `json:"Finished,omitempty"`
+	ETA        string                  `json:"ETA,omitempty"`
+	Total      int                     `json:"Total"`
+	Pending    int                     `json:"Pending"`
+	InProgress int                     `json:"InProgress"`
+	Complete   int                     `json:"Complete"`
+	Failed     int                     `json:"Failed"`
+	Skipped    int                     `json:"Skipped"`
+	Cancelled  int                     `json:"Cancelled"`
+	Errors     int                     `json:"Errors"`
+	Endpoints  []*DiscoveryJobEndpoint `json:"Endpoints,omitempty"`
+}
+
+type DiscoveryJobArray struct {
+	DiscoveryJobs []*DiscoveryJob `json:"DiscoveryJobs"`
+}
+
+// The discovery jobs of this instance.
+type DiscoveryJobs struct {
+	lock sync.Mutex
+	keep int
+	jobs map[string]*discJob
+	done []string // Finished jobs, oldest first
+	now  func() time.Time
+}
+
+type discJob struct {
+	jobs      *DiscoveryJobs
+	id        string
+	created   time.Time
+	finished  time.Time
+	cancelled bool
+	eps       []*DiscoveryJobEndpoint // By ID
+	byID      map[string]*DiscoveryJobEndpoint
+}
+
+func NewDiscoveryJobs(keep int) *DiscoveryJobs {
+	return &DiscoveryJobs{
+		keep: keep,
+		jobs: make(map[string]*discJob),
+		now:  time.Now,
+	}
+}
+
+func discJobTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Start tracking a job for the RedfishEndpoints ids.  A nil *DiscoveryJobs
+// tracks nothing and returns a nil job, which is fine to use.
+func (j *DiscoveryJobs) New(ids []string) *discJob {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	job := &discJob{
+		jobs:    j,
+		id:      uuid.New().String(),
+		created: j.now(),
+		eps:     make([]*DiscoveryJobEndpoint, 0, len(ids)),
+		byID:    make(map[string]*DiscoveryJobEndpoint, len(ids)),
+	}
+	for _, id := range ids {
+		if _, ok := job.byID[id]; ok {
+			continue
+		}
+		ep := &DiscoveryJobEndpoint{ID: id, Status: DiscJobPending}
+		job.eps = append(job.eps, ep)
+		job.byID[id] = ep
+	}
+	sort.Slice(job.eps, func(a, b int) bool { return job.eps[a].ID < job.eps[b].ID })
+	j.jobs[job.id] = job
+	return job
+}
+
+// The job's ID, empty for a nil job.
+func (dj *discJob) ID() string {
+	if dj == nil {
+		return ""
+	}
+	return dj.id
+}
+
+// Should endpoints that haven't started be left alone?
+func (dj *discJob) isCancelled() bool {
+	if dj == nil {
+		return false
+	}
+	dj.jobs.lock.Lock()
+	defer dj.jobs.lock.Unlock()
+	return dj.cancelled
+}
+
+// Record that the endpoint id isn't being discovered, for reason.
+func (dj *discJob) skip(id, status, reason string) {
+	if dj == nil {
+		return
+	}
+	dj.jobs.lock.Lock()
+	defer dj.jobs.lock.Unlock()
+	if ep, ok := dj.byID[id]; ok && ep.Status == DiscJobPending {
+		ep.Status = status
+		ep.Reason = reason
+		ep.Finished = discJobTime(dj.jobs.now())
+	}
+}
+
+// Record that discovery of the endpoint id has begun.
+func (dj *discJob) start(id string) {
+	if dj == nil {
+		return
+	}
+	dj.jobs.lock.Lock()
+	defer dj.jobs.lock.Unlock()
+	if ep, ok := dj.byID[id]; ok {
+		ep.Status = DiscJobInProgress
+		ep.Started = discJobTime(dj.jobs.now())
+	}
+}
+
+// Record the outcome of discovering rfEP, err being from storing it.
+func (dj *discJob) result(rfEP *rf.RedfishEP, err error) {
+	if dj == nil {
+		return
+	}
+	dj.jobs.lock.Lock()
+	defer dj.jobs.lock.Unlock()
+	ep, ok := dj.byID[rfEP.ID]
+	if !ok {
+		return
+	}
+	ep.LastDiscoveryStatus = rfEP.DiscInfo.LastStatus
+	ep.Errors = len(rfEP.DiscInfo.Errors)
+	ep.Finished = discJobTime(dj.jobs.now())
+	if rfEP.DiscInfo.LastStatus != rf.DiscoverOK {
+		ep.Status = DiscJobFailed
+	} else if err != nil {
+		ep.Status = DiscJobFailed
+		ep.Reason = err.Error()
+	} else {
+		ep.Status = DiscJobComplete
+	}
+}
+
+// Record that the job is over.  Endpoints it never got to are skipped.
+func (dj *discJob) finish() {
+	if dj == nil {
+		return
+	}
+	j := dj.jobs
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if !dj.finished.IsZero() {
+		return
+	}
+	dj.finished = j.now()
+	for _, ep := range dj.eps {
+		if ep.Status == DiscJobPending {
+			ep.Status = DiscJobSkipped
+			ep.Finished = discJobTime(dj.finished)
+		}
+	}
+	j.done = append(j.done, dj.id)
+	for len(j.done) > j.keep {
+		delete(j.jobs, j.done[0])
+		j.done = j.done[1:]
+	}
+}
+
+// Snapshot of the job, with its counts and ETA worked out.  Must hold the
+// lock.
+func (dj *discJob) view(withEPs bool) *DiscoveryJob {
+	job := &DiscoveryJob{
+		ID:      dj.id,
+		Created: discJobTime(dj.created),
+		Total:   len(dj.eps),
+	}
+	for _, ep := range dj.eps {
+		switch ep.Status {
+		case DiscJobPending:
+			job.Pending++
+		case DiscJobInProgress:
+			job.InProgress++
+		case DiscJobComplete:
+			job.Complete++
+		case DiscJobFailed:
+			job.Failed++
+		case DiscJobSkipped:
+			job.Skipped++
+		case DiscJobCancelled:
+			job.Cancelled++
+		}
+		job.Errors += ep.Errors
+		if withEPs {
+			cp := *ep
+			job.Endpoints = append(job.Endpoints, &cp)
+		}
+	}
+	switch {
+	case !dj.finished.IsZero() && dj.cancelled:
+		job.Status = DiscJobCancelled
+	case !dj.finished.IsZero():
+		job.Status = DiscJobComplete
+	case job.Pending == job.Total:
+		job.Status = DiscJobPending
+	default:
+		job.Status = DiscJobInProgress
+	}
+	if !dj.finished.IsZero() {
+		job.Finished = discJobTime(dj.finished)
+	} else if done := job.Complete + job.Failed; done > 0 && !dj.cancelled {
+		now := dj.jobs.now()
+		left := job.Pending + job.InProgress
+		per := now.Sub(dj.created) / time.Duration(done)
+		job.ETA = discJobTime(now.Add(per * time.Duration(left)))
+	}
+	return job
+}
+
+// Get a job, nil if no such job.
+func (j *DiscoveryJobs) Get(id string) *DiscoveryJob {
+	if j == nil {
+		return nil
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	dj, ok := j.jobs[id]
+	if !ok {
+		return nil
+	}
+	return dj.view(true)
+}
+
+// Get all jobs, without their endpoints, oldest first.
+func (j *DiscoveryJobs) List() []*DiscoveryJob {
+	jobs := []*DiscoveryJob{}
+	if j == nil {
+		return jobs
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	for _, dj := range j.jobs {
+		jobs = append(jobs, dj.view(false))
+	}
+	sort.Slice(jobs, func(a, b int) bool {
+		if jobs[a].Created != jobs[b].Created {
+			return jobs[a].Created < jobs[b].Created
+		}
+		return jobs[a].ID < jobs[b].ID
+	})
+	return jobs
+}
+
+// Cancel a job.  Returns whether it exists, and whether it was still
+// running.
+func (j *DiscoveryJobs) Cancel(id string) (found, running bool) {
+	if j == nil {
+		return false, false
+	}
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	dj, ok := j.jobs[id]
+	if !ok {
+		return false, false
+	}
+	if !dj.finished.IsZero() {
+		return true, false
+	}
+	dj.cancelled = true
+	return true, true
+}
+
+// Get all discovery jobs
+func (s *SmD) doDiscJobsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, &DiscoveryJobArray{
+		DiscoveryJobs: s.discJobs.List(),
+	})
+}
+
+// Get one discovery job, with the progress of each endpoint
+func (s *SmD) doDiscJobGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	job := s.discJobs.Get(chi.URLParam(r, "id"))
+	if job == nil {
+		sendJsonError(w, http.StatusNotFound, "no such discovery job.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, job)
+}
+
+// Cancel a discovery job
+func (s *SmD) doDiscJobCancel(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	id := chi.URLParam(r, "id")
+	found, running := s.discJobs.Cancel(id)
+	if !found {
+		sendJsonError(w, http.StatusNotFound, "no such discovery job.")
+		return
+	} else if !running {
+		sendJsonError(w, http.StatusConflict, "discovery job already finished.")
+		return
+	}
+	sendJsonError(w, http.StatusAccepted, "cancelling discovery job "+id)
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+func TestDiscoveryJobs(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	jobs := NewDiscoveryJobs(1)
+	jobs.now = func() time.Time { return now }
+
+	job := jobs.New([]string{"x1c0s2b0", "x1c0s0b0", "x1c0s1b0", "x1c0s3b0", "x1c0s0b0"})
+	if v := jobs.Get(job.ID()); v == nil || v.Total != 4 || v.Pending != 4 ||
+		v.Status != DiscJobPending || v.Endpoints[0].ID != "x1c0s0b0" {
+		t.Fatalf("Unexpected new job %+v", v)
+	}
+
+	job.skip("x1c0s3b0", DiscJobSkipped, "!Enabled")
+	job.start("x1c0s0b0")
+	job.start("x1c0s1b0")
+	now = now.Add(time.Minute)
+	ok := &rf.RedfishEP{}
+	ok.ID = "x1c0s0b0"
+	ok.DiscInfo.LastStatus = rf.DiscoverOK
+	ok.DiscInfo.Errors = []rf.DiscoveryError{{}, {}}
+	job.result(ok, nil)
+	held := &rf.RedfishEP{}
+	held.ID = "x1c0s1b0"
+	held.DiscInfo.LastStatus = rf.DiscoverOK
+	job.result(held, errors.New("held"))
+
+	v := jobs.Get(job.ID())
+	if v.Status != DiscJobInProgress || v.Complete != 1 || v.Failed != 1 ||
+		v.Skipped != 1 || v.Pending != 1 || v.Errors != 2 {
+		t.Errorf("Unexpected job progress %+v", v)
+	}
+	// Two done in a minute, so one more should take 30 seconds.
+	if expected := discJobTime(now.Add(30 * time.Second)); v.ETA != expected {
+		t.Errorf("Expected ETA %s, got %s", expected, v.ETA)
+	}
+
+	if found, running := jobs.Cancel(job.ID()); !found || !running {
+		t.Errorf("Expected running job to be cancelled")
+	}
+	if !job.isCancelled() {
+		t.Errorf("Expected job to be cancelled")
+	}
+	job.skip("x1c0s2b0", DiscJobCancelled, "job cancelled")
+	job.finish()
+	v = jobs.Get(job.ID())
+	if v.Status != DiscJobCancelled || v.Cancelled != 1 || v.Finished == "" ||
+		v.ETA != "" {
+		t.Errorf("Unexpected cancelled job %+v", v)
+	}
+	if found, running := jobs.Cancel(job.ID()); !found || running {
+		t.Errorf("Expected finished job not to be running")
+	}
+
+	// Only the last finished job is kept.
+	next := jobs.New([]string{"x2c0s0b0"})
+	next.finish()
+	if jobs.Get(job.ID()) != nil || len(jobs.List()) != 1 {
+		t.Errorf("Expected oldest finished job to be dropped")
+	}
+	if v := jobs.Get(next.ID()); v.Skipped != 1 || v.Status != DiscJobComplete {
+		t.Errorf("Unexpected job %+v", v)
+	}
+
+	// A nil job tracks nothing.
+	var none *DiscoveryJobs
+	nj := none.New([]string{"x1c0s0b0"})
+	nj.start("x1c0s0b0")
+	nj.finish()
+	if nj.ID() != "" || nj.isCancelled() {
+		t.Errorf("Expected nil job to do nothing")
+	}
+}
+
+func TestDiscoveryJobsAPI(t *testing.T) {
+	defer func() { s.discJobs = nil }()
+	s.discJobs = NewDiscoveryJobs(DefaultDiscoveryJobKeep)
+	job := s.discJobs.New([]string{"x1c0s0b0"})
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DiscoveryJobs/"+job.ID(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var v DiscoveryJob
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil ||
+		w.Code != http.StatusOK || v.ID != job.ID() || len(v.Endpoints) != 1 {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DiscoveryJobs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var list DiscoveryJobArray
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil ||
+		w.Code != http.StatusOK || len(list.DiscoveryJobs) != 1 ||
+		list.DiscoveryJobs[0].Endpoints != nil {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+
+	// Running, then finished.
+	for _, code := range []int{http.StatusAccepted, http.StatusConflict} {
+		req, _ = http.NewRequest("POST", "https://localhost/hsm/v2/Inventory/"+
+			"DiscoveryJobs/"+job.ID()+"/Actions/Cancel", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != code {
+			t.Errorf("Expected %d, got %d", code, w.Code)
+		}
+		job.finish()
+	}
+	if v := s.discJobs.Get(job.ID()); v.Status != DiscJobCancelled {
+		t.Errorf("Expected job cancelled, got %s", v.Status)
+	}
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DiscoveryJobs/nope", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}
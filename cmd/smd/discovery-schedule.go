@@ -0,0 +1,365 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Scheduled rediscovery
+//
+// RedfishEndpoints can be rediscovered periodically rather than only on
+// demand.  A schedule is stored under the name of what it covers, and each
+// endpoint uses the first of these that exists:
+//
+//     x3000c0s1b0   the endpoint itself
+//     NodeBMC       the endpoint's type
+//     default       every endpoint
+//
+// An endpoint is due IntervalSeconds after its last discovery attempt, plus
+// a fixed per-endpoint jitter of up to JitterSeconds so that endpoints
+// discovered together don't all come due together.  Endpoints never
+// discovered are due straight away.  If the schedule has maintenance
+// windows, a due rediscovery waits for the next one to open.
+//
+// Once a minute, the due endpoints that are Enabled, RediscoverOnUpdate,
+// not already being discovered, and owned by this instance (see
+// cluster.go) are rediscovered, at most discSchedMaxPerTick at a time;
+// the rest are picked up on later ticks.
+//
+//     GET    /Inventory/DiscoverySchedules          all schedules
+//     GET    /Inventory/DiscoverySchedules/{name}   one schedule
+//     PUT    /Inventory/DiscoverySchedules/{name}   create or replace it
+//     DELETE /Inventory/DiscoverySchedules/{name}   remove it
+//     GET    /Inventory/DiscoveryPlan[?id=xname]    next planned run of
+//                                                   each endpoint
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	discSchedTick       = time.Minute
+	discSchedMaxPerTick = 100
+)
+
+// Normalize the name of a schedule, returning "" if it isn't an xname, a
+// component type or "default".
+func discSchedName(name string) string {
+	if strings.EqualFold(name, sm.DiscoveryScheduleDefault) {
+		return sm.DiscoveryScheduleDefault
+	}
+	if t := xnametypes.VerifyNormalizeType(name); t != "" {
+		return t
+	}
+	if id := xnametypes.NormalizeHMSCompID(name); xnametypes.IsHMSCompIDValid(id) {
+		return id
+	}
+	return ""
+}
+
+// Index schedules by name.
+func discSchedMap(scheds []*sm.DiscoverySchedule) map[string]*sm.DiscoverySchedule {
+	byName := make(map[string]*sm.DiscoverySchedule, len(scheds))
+	for _, ds := range scheds {
+		byName[ds.Name] = ds
+	}
+	return byName
+}
+
+// The schedule for ep, nil if none.
+func discSchedFor(byName map[string]*sm.DiscoverySchedule, ep *sm.RedfishEndpoint) *sm.DiscoverySchedule {
+	if ds, ok := byName[ep.ID]; ok {
+		return ds
+	}
+	if ds, ok := byName[ep.Type]; ok {
+		return ds
+	}
+	return byName[sm.DiscoveryScheduleDefault]
+}
+
+// The fixed jitter for the endpoint id, from 0 to jitter seconds.
+func discSchedJitter(id string, jitter int) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return time.Duration(h.Sum32()%uint32(jitter+1)) * time.Second
+}
+
+// When the endpoint id, last attempted at last (RFC3339, empty if never),
+// is next to be rediscovered, no earlier than now.  Zero if never.
+func discSchedNext(ds *sm.DiscoverySchedule, id, last string, now time.Time) time.Time {
+	if ds == nil || ds.IntervalSeconds <= 0 {
+		return time.Time{}
+	}
+	now = now.UTC()
+	due := now
+	if t, err := time.Parse(time.RFC3339Nano, last); err == nil {
+		due = t.UTC().Add(time.Duration(ds.IntervalSeconds)*time.Second +
+			discSchedJitter(id, ds.JitterSeconds))
+		if due.Before(now) {
+			due = now
+		}
+	}
+	if len(ds.Windows) == 0 {
+		return due
+	}
+	// The earliest opening at or after due.  Starting a day early catches
+	// a window that began yesterday and runs past midnight.
+	var next time.Time
+	midnight := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	for _, w := range ds.Windows {
+		start, err1 := sm.ParseDiscoveryWindowTime(w.Start)
+		end, err2 := sm.ParseDiscoveryWindowTime(w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if end <= start {
+			end += 24 * 60
+		}
+		for off := -1; off <= 7; off++ {
+			day := midnight.AddDate(0, 0, off)
+			if !discSchedOnDay(w.Days, day.Weekday()) {
+				continue
+			}
+			wStart := day.Add(time.Duration(start) * time.Minute)
+			wEnd := day.Add(time.Duration(end) * time.Minute)
+			if !due.Before(wEnd) {
+				continue
+			}
+			if wStart.Before(due) {
+				wStart = due
+			}
+			if next.IsZero() || wStart.Before(next) {
+				next = wStart
+			}
+			break
+		}
+	}
+	return next
+}
+
+// Is a window with days open on weekday?
+func discSchedOnDay(days []string, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if d == sm.DiscoveryWindowDays[weekday] {
+			return true
+		}
+	}
+	return false
+}
+
+// Spin off a thread to rediscover endpoints as their schedules come due.
+func (s *SmD) StartDiscoveryScheduler() {
+	go func() {
+		for {
+			time.Sleep(discSchedTick)
+			s.discoverySchedRun(time.Now())
+		}
+	}()
+}
+
+// Start rediscovery of the endpoints owned by this instance that are due
+// at now, returning how many were started.
+func (s *SmD) discoverySchedRun(now time.Time) int {
+	scheds, err := s.db.GetDiscoverySchedules()
+	if err != nil {
+		s.LogAlways("discoverySchedRun(): Can't get schedules: %s", err)
+		return 0
+	} else if len(scheds) == 0 {
+		return 0
+	}
+	byName := discSchedMap(scheds)
+	eps, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		s.LogAlways("discoverySchedRun(): Can't get endpoints: %s", err)
+		return 0
+	}
+	started := 0
+	for _, ep := range eps {
+		if started >= discSchedMaxPerTick {
+			break
+		}
+		if !ep.Enabled || !ep.RediscOnUpdate ||
+			ep.DiscInfo.LastStatus == rf.DiscoveryStarted ||
+			!s.cluster.ownsEndpoint(ep.ID) {
+			continue
+		}
+		next := discSchedNext(discSchedFor(byName, ep), ep.ID,
+			ep.DiscInfo.LastAttempt, now)
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+		s.LogAlways("Scheduled rediscovery of %s", ep.ID)
+		go s.discoverFromEndpoint(ep, 0, false)
+		started++
+	}
+	return started
+}
+
+// Get all rediscovery schedules
+func (s *SmD) doDiscSchedulesGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	scheds, err := s.db.GetDiscoverySchedules()
+	if err != nil {
+		s.LogAlways("doDiscSchedulesGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.DiscoveryScheduleArray{Schedules: scheds})
+}
+
+// Get one rediscovery schedule
+func (s *SmD) doDiscScheduleGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	name := discSchedName(chi.URLParam(r, "name"))
+	scheds, err := s.db.GetDiscoverySchedules()
+	if err != nil {
+		s.LogAlways("doDiscScheduleGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if ds, ok := discSchedMap(scheds)[name]; ok && name != "" {
+		sendJsonObject(w, http.StatusOK, ds)
+		return
+	}
+	sendJsonError(w, http.StatusNotFound, "no such schedule.")
+}
+
+// Create or replace a rediscovery schedule
+func (s *SmD) doDiscSchedulePut(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	name := discSchedName(chi.URLParam(r, "name"))
+	if name == "" {
+		sendJsonError(w, http.StatusBadRequest,
+			"schedule name must be an xname, a type or '"+
+				sm.DiscoveryScheduleDefault+"'")
+		return
+	}
+	ds := new(sm.DiscoverySchedule)
+	if !s.certDecodeBody(w, r, ds) {
+		return
+	}
+	ds.Name = name
+	if err := ds.Verify(); err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.db.UpsertDiscoverySchedule(ds); err != nil {
+		s.LogAlways("doDiscSchedulePut(): Store failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, ds)
+}
+
+// Remove a rediscovery schedule
+func (s *SmD) doDiscScheduleDelete(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	name := discSchedName(chi.URLParam(r, "name"))
+	didDelete := false
+	if name != "" {
+		var err error
+		didDelete, err = s.db.DeleteDiscoverySchedule(name)
+		if err != nil {
+			s.LogAlways("doDiscScheduleDelete(): Delete failure: %s", err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+	}
+	if !didDelete {
+		sendJsonError(w, http.StatusNotFound, "no such schedule.")
+		return
+	}
+	sendJsonError(w, http.StatusOK, "deleted 1 entry")
+}
+
+// Get the next planned rediscovery of each endpoint, or of those given by
+// id.
+func (s *SmD) doDiscPlanGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"failed to decode query parameters.")
+		return
+	}
+	var want map[string]bool
+	if ids := r.Form["id"]; len(ids) > 0 {
+		want = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			want[xnametypes.NormalizeHMSCompID(id)] = true
+		}
+	}
+	scheds, err := s.db.GetDiscoverySchedules()
+	if err != nil {
+		s.LogAlways("doDiscPlanGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	eps, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		s.LogAlways("doDiscPlanGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	byName := discSchedMap(scheds)
+	now := time.Now()
+	plan := &sm.DiscoveryPlan{Endpoints: []*sm.DiscoveryPlanEntry{}}
+	for _, ep := range eps {
+		if want != nil && !want[ep.ID] {
+			continue
+		}
+		entry := &sm.DiscoveryPlanEntry{
+			RedfishEndpointID: ep.ID,
+			LastAttempt:       ep.DiscInfo.LastAttempt,
+		}
+		if s.cluster.enabled() {
+			entry.Owner = s.cluster.owner(ep.ID)
+		}
+		if ds := discSchedFor(byName, ep); ds != nil {
+			entry.Schedule = ds.Name
+			if next := discSchedNext(ds, ep.ID, ep.DiscInfo.LastAttempt, now); !next.IsZero() {
+				entry.Next = next.Format(time.RFC3339)
+			}
+		}
+		plan.Endpoints = append(plan.Endpoints, entry)
+	}
+	sendJsonObject(w, http.StatusOK, plan)
+}
@@ -0,0 +1,160 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestDiscSchedNext(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	hourly := &sm.DiscoverySchedule{Name: "default", IntervalSeconds: 3600}
+	nightly := &sm.DiscoverySchedule{Name: "NodeBMC", IntervalSeconds: 3600,
+		Windows: []sm.DiscoveryWindow{{Start: "22:00", End: "02:00"}}}
+	weekend := &sm.DiscoverySchedule{Name: "x1c0s0b0", IntervalSeconds: 3600,
+		Windows: []sm.DiscoveryWindow{{Days: []string{"Sat", "Sun"},
+			Start: "08:00", End: "09:00"}}}
+	tests := []struct {
+		ds       *sm.DiscoverySchedule
+		last     string
+		expected time.Time
+	}{
+		{nil, "", time.Time{}},
+		{&sm.DiscoverySchedule{Name: "default"}, "", time.Time{}},
+		{hourly, "", now},
+		{hourly, "2026-10-14T11:30:00.000000Z", now.Add(30 * time.Minute)},
+		{hourly, "2026-10-13T11:30:00.000000Z", now},
+		{nightly, "", time.Date(2026, 10, 14, 22, 0, 0, 0, time.UTC)},
+		{weekend, "", time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)},
+	}
+	for i, test := range tests {
+		next := discSchedNext(test.ds, "x1c0s0b0", test.last, now)
+		if !next.Equal(test.expected) {
+			t.Errorf("Test %d: expected %s, got %s", i, test.expected, next)
+		}
+	}
+
+	// Inside a window that began the day before.
+	late := time.Date(2026, 10, 15, 1, 0, 0, 0, time.UTC)
+	if next := discSchedNext(nightly, "x1c0s0b0", "", late); !next.Equal(late) {
+		t.Errorf("Expected %s, got %s", late, next)
+	}
+
+	// Jitter is fixed per endpoint and within bounds.
+	jittery := &sm.DiscoverySchedule{IntervalSeconds: 3600, JitterSeconds: 600}
+	last := "2026-10-14T12:00:00.000000Z"
+	a := discSchedNext(jittery, "x1c0s0b0", last, now)
+	if !a.Equal(discSchedNext(jittery, "x1c0s0b0", last, now)) ||
+		a.Before(now.Add(time.Hour)) || a.After(now.Add(70*time.Minute)) {
+		t.Errorf("Unexpected jittered time %s", a)
+	}
+}
+
+func TestDiscSchedAPI(t *testing.T) {
+	defer func() {
+		results.UpsertDiscoverySchedule.Input.ds = nil
+		results.GetDiscoverySchedules.Return.scheds = nil
+		results.GetRFEndpointsAll.Return.entries = nil
+	}()
+
+	payload := []byte(`{"IntervalSeconds":3600,"JitterSeconds":60}`)
+	req, _ := http.NewRequest("PUT",
+		"https://localhost/hsm/v2/Inventory/DiscoverySchedules/nodebmc",
+		bytes.NewBuffer(payload))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	expected := &sm.DiscoverySchedule{Name: "NodeBMC", IntervalSeconds: 3600,
+		JitterSeconds: 60}
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	} else if !reflect.DeepEqual(results.UpsertDiscoverySchedule.Input.ds, expected) {
+		t.Errorf("Expected %+v stored, got %+v", expected,
+			results.UpsertDiscoverySchedule.Input.ds)
+	}
+
+	for _, bad := range []struct{ name, body string }{
+		{"foo", `{"IntervalSeconds":3600}`},
+		{"default", `{"IntervalSeconds":60,"JitterSeconds":120}`},
+		{"default", `{"IntervalSeconds":60,"Windows":[{"Start":"25:00","End":"01:00"}]}`},
+		{"default", `{"IntervalSeconds":60,"Windows":[{"Days":["Funday"],"Start":"00:00","End":"01:00"}]}`},
+	} {
+		req, _ = http.NewRequest("PUT",
+			"https://localhost/hsm/v2/Inventory/DiscoverySchedules/"+bad.name,
+			bytes.NewBufferString(bad.body))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s %s, got %d", bad.name, bad.body, w.Code)
+		}
+	}
+
+	results.GetDiscoverySchedules.Return.scheds = []*sm.DiscoverySchedule{
+		{Name: "default"},
+		{Name: "x1c0s0b0", IntervalSeconds: 3600},
+	}
+	results.GetDiscoverySchedules.Return.err = nil
+	ep1 := &sm.RedfishEndpoint{}
+	ep1.ID = "x1c0s0b0"
+	ep1.Type = "NodeBMC"
+	ep1.DiscInfo.LastAttempt = "2026-10-14T11:30:00.000000Z"
+	ep2 := &sm.RedfishEndpoint{}
+	ep2.ID = "x1c0s1b0"
+	ep2.Type = "NodeBMC"
+	results.GetRFEndpointsAll.Return.entries = []*sm.RedfishEndpoint{ep1, ep2}
+	results.GetRFEndpointsAll.Return.err = nil
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DiscoveryPlan?id=x1c0s1b0", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var plan sm.DiscoveryPlan
+	if err := json.Unmarshal(w.Body.Bytes(), &plan); err != nil ||
+		w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+	expectedPlan := sm.DiscoveryPlan{Endpoints: []*sm.DiscoveryPlanEntry{
+		{RedfishEndpointID: "x1c0s1b0", Schedule: "default"},
+	}}
+	if !reflect.DeepEqual(plan, expectedPlan) {
+		t.Errorf("Expected plan %+v, got %+v", expectedPlan, plan)
+	}
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DiscoverySchedules/X1C0S0B0", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var ds sm.DiscoverySchedule
+	if err := json.Unmarshal(w.Body.Bytes(), &ds); err != nil ||
+		w.Code != http.StatusOK || ds.Name != "x1c0s0b0" {
+		t.Errorf("Unexpected response %d: %s", w.Code, w.Body.String())
+	}
+}
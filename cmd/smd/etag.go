@@ -0,0 +1,174 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Conditional GETs
+//
+// The database keeps a change counter for each collection clients poll:
+// components, groups and partitions (one counter for both, with their
+// members), and hardware inventory.  Each one goes up when a transaction
+// that changed the collection commits, whichever HSM instance made it.
+//
+// GETs of those collections send a weak ETag made of the counters the
+// response depends on, e.g. W/"c1042.g17" for State/Components, which can
+// filter on groups and partitions.  A client that sends it back in
+// If-None-Match gets 304 Not Modified, with no body, until one of the
+// collections changes.  The tag only says that nothing has changed; it is
+// the same for every query of the route, so it goes with the URL the client
+// polls, and differs by Accept header, which can pick the compact format.
+//
+// GET /State/Components/{xname} keeps the tag of the component's contents
+// that PATCH takes in If-Match, and answers If-None-Match with it too.
+//
+// If the counters can't be read, e.g. the schema is too old to have them,
+// responses are sent without an ETag.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	etagComponents = "c"
+	etagGroups     = "g"
+	etagHWInv      = "h"
+)
+
+// Collections each conditional GET route depends on.
+var etagRoutes = map[string][]string{
+	"doComponentsGetV2":           {etagComponents, etagGroups},
+	"doComponentByNIDGetV2":       {etagComponents},
+	"doComponentsQueryGetV2":      {etagComponents, etagGroups},
+	"doGroupsGetV2":               {etagGroups},
+	"doGroupLabelsGetV2":          {etagGroups},
+	"doGroupGetV2":                {etagGroups},
+	"doGroupMembersGetV2":         {etagGroups},
+	"doPartitionsGetV2":           {etagGroups},
+	"doPartitionNamesGetV2":       {etagGroups},
+	"doPartitionGetV2":            {etagGroups},
+	"doPartitionMembersGetV2":     {etagGroups},
+	"doMembershipsGetV2":          {etagComponents, etagGroups},
+	"doMembershipGetV2":           {etagComponents, etagGroups},
+	"doHWInvByLocationGetV2":      {etagHWInv},
+	"doHWInvByLocationGetAllV2":   {etagHWInv, etagGroups},
+	"doHWInvByLocationQueryGetV2": {etagHWInv, etagGroups},
+	"doHWInvByFRUGetV2":           {etagHWInv},
+	"doHWInvByFRUGetAllV2":        {etagHWInv},
+}
+
+// Weak entity tag for the given collections at versions vers, for a request
+// with the given Accept header, which may pick another representation.
+func collectionETag(vers *hmsds.CollectionVersions, colls []string, accept string) string {
+	parts := make([]string, 0, len(colls))
+	for _, coll := range colls {
+		var v int64
+		switch coll {
+		case etagComponents:
+			v = vers.Components
+		case etagGroups:
+			v = vers.Groups
+		case etagHWInv:
+			v = vers.HWInv
+		}
+		parts = append(parts, coll+strconv.FormatInt(v, 10))
+	}
+	if accept != "" {
+		h := fnv.New32a()
+		h.Write([]byte(accept))
+		parts = append(parts, strconv.FormatUint(uint64(h.Sum32()), 16))
+	}
+	return `W/"` + strings.Join(parts, ".") + `"`
+}
+
+// True if the If-None-Match header value has a tag matching etag, comparing
+// weakly as If-None-Match does.
+func ifNoneMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// Drops the ETag from anything but a successful response, e.g. an error for
+// a bad query.
+type etagWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (ew *etagWriter) WriteHeader(code int) {
+	if !ew.wroteHeader && code != http.StatusOK {
+		ew.Header().Del("ETag")
+	}
+	ew.wroteHeader = true
+	ew.ResponseWriter.WriteHeader(code)
+}
+
+func (ew *etagWriter) Write(b []byte) (int, error) {
+	ew.wroteHeader = true
+	return ew.ResponseWriter.Write(b)
+}
+
+// For streamed responses, e.g. hardware inventory exports.
+func (ew *etagWriter) Flush() {
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Wrap the handler for a route so that it sends an ETag and answers a
+// matching If-None-Match with 304, if the route is a conditional GET.
+func (s *SmD) etagGuard(route Route, next http.Handler) http.Handler {
+	colls, ok := etagRoutes[route.Name]
+	if !ok || route.Method != http.MethodGet {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vers, err := s.db.GetCollectionVersions()
+		if err != nil || vers == nil {
+			s.Log(LOG_DEBUG, "etagGuard(): No collection versions: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		etag := collectionETag(vers, colls, r.Header.Get("Accept"))
+		w.Header().Set("ETag", etag)
+		w.Header().Add("Vary", "Accept")
+		if inm := r.Header.Get("If-None-Match"); inm != "" && ifNoneMatches(inm, etag) {
+			defer base.DrainAndCloseRequestBody(r)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next.ServeHTTP(&etagWriter{ResponseWriter: w}, r)
+	})
+}
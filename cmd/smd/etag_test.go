@@ -0,0 +1,154 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+func TestIfNoneMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		expect bool
+	}{
+		{`W/"c1.g2"`, `W/"c1.g2"`, true},
+		{`"c1.g2"`, `W/"c1.g2"`, true},
+		{`W/"c1.g1", W/"c1.g2"`, `W/"c1.g2"`, true},
+		{`*`, `W/"c1.g2"`, true},
+		{`W/"c1.g1"`, `W/"c1.g2"`, false},
+		{`W/"abc"`, `"abc"`, true},
+	}
+	for i, test := range tests {
+		if ifNoneMatches(test.header, test.etag) != test.expect {
+			t.Errorf("Test %v Failed: %s vs %s: expected %v",
+				i, test.header, test.etag, test.expect)
+		}
+	}
+}
+
+func TestETagGuard(t *testing.T) {
+	defer func() {
+		results.GetCollectionVersions.Return.vers = nil
+		results.GetCollectionVersions.Return.err = nil
+		results.GetComponentsFilter.Return.ids = nil
+		results.GetComponentsFilter.Return.err = nil
+	}()
+	results.GetComponentsFilter.Return.ids = []*base.Component{
+		{ID: "x0c0s0b0n0", Type: "Node", State: "Ready", Flag: "OK"},
+	}
+	get := func(url, inm, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		if inm != "" {
+			req.Header.Set("If-None-Match", inm)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	url := "https://localhost/hsm/v2/State/Components"
+
+	// No counters, no ETag
+	results.GetCollectionVersions.Return.err = errors.New("no such table")
+	w := get(url, "", "")
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != "" {
+		t.Errorf("Expected 200 with no ETag, got %v %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	results.GetCollectionVersions.Return.err = nil
+	results.GetCollectionVersions.Return.vers = &hmsds.CollectionVersions{
+		Components: 7, Groups: 2, HWInv: 5}
+	w = get(url, "", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag != `W/"c7.g2"` {
+		t.Fatalf("Expected 200 with ETag W/\"c7.g2\", got %v %q", w.Code, etag)
+	}
+	w = get(url, etag, "")
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 ||
+		w.Header().Get("ETag") != etag {
+		t.Errorf("Expected 304 with no body, got %v: %s", w.Code, w.Body.String())
+	}
+
+	// The compact format has its own tag.
+	w = get(url, etag, CompactMediaType)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with another ETag, got %v %q", w.Code,
+			w.Header().Get("ETag"))
+	}
+
+	// A change to a collection the route doesn't use keeps the tag.
+	results.GetCollectionVersions.Return.vers.HWInv = 6
+	if w = get(url, etag, ""); w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 after an HWInv change, got %v", w.Code)
+	}
+	results.GetCollectionVersions.Return.vers.Groups = 3
+	if w = get(url, etag, ""); w.Code != http.StatusOK ||
+		w.Header().Get("ETag") != `W/"c7.g3"` {
+		t.Errorf("Expected 200 after a group change, got %v %q", w.Code,
+			w.Header().Get("ETag"))
+	}
+
+	// Errors don't get an ETag.
+	results.GetComponentsFilter.Return.err = hmsds.ErrHMSDSArgBadID
+	if w = get(url, "", ""); w.Code != http.StatusBadRequest ||
+		w.Header().Get("ETag") != "" {
+		t.Errorf("Expected 400 with no ETag, got %v %q", w.Code,
+			w.Header().Get("ETag"))
+	}
+}
+
+func TestComponentGetIfNoneMatch(t *testing.T) {
+	defer func() {
+		results.GetComponentByID.Return.id = nil
+	}()
+	comp := &base.Component{ID: "x0c0s0b0n0", Type: "Node", State: "Ready", Flag: "OK"}
+	results.GetComponentByID.Return.id = comp
+	results.GetComponentByID.Return.err = nil
+	etag := compETag(comp)
+
+	req := httptest.NewRequest("GET",
+		"https://localhost/hsm/v2/State/Components/x0c0s0b0n0", nil)
+	req.Header.Set("If-None-Match", etag)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304, got %v: %s", w.Code, w.Body.String())
+	}
+
+	comp.State = "Off"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with a new ETag, got %v %q", w.Code,
+			w.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,157 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Publish-only NATS client for the event bus
+//
+// Only what publishing needs from the NATS text protocol: read the server's
+// INFO, send CONNECT, PUB each event, and answer the server's PINGs so the
+// connection stays up.  No TLS; credentials, if any, come from the URL.
+///////////////////////////////////////////////////////////////////////////////
+
+const natsDialTimeout = 10 * time.Second
+
+var errNATSClosed = errors.New("NATS connection closed")
+
+type natsConn struct {
+	lock    sync.Mutex // Serializes writes
+	conn    net.Conn
+	subject string
+	err     error // Set once the server closes or rejects the connection
+}
+
+// CONNECT options
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+// Connect to the NATS server at addr to publish on subject.
+func dialNATS(addr string, user *url.Userinfo, subject string) (busConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, natsDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	rd := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(natsDialTimeout))
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return nil, fmt.Errorf("not a NATS server: %q", strings.TrimSpace(line))
+	}
+	opts := natsConnect{Name: serviceName, Lang: "go", Version: "1"}
+	if user != nil {
+		opts.User = user.Username()
+		opts.Pass, _ = user.Password()
+	}
+	copts, _ := json.Marshal(opts)
+	// The PONG confirms the CONNECT was accepted.
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", copts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	line, err = rd.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if strings.TrimSpace(line) != "PONG" {
+		conn.Close()
+		return nil, fmt.Errorf("NATS connect failed: %s", strings.TrimSpace(line))
+	}
+	conn.SetDeadline(time.Time{})
+
+	nc := &natsConn{conn: conn, subject: subject}
+	go nc.readLoop(rd)
+	return nc, nil
+}
+
+// Answer PINGs and notice errors until the connection closes.
+func (nc *natsConn) readLoop(rd *bufio.Reader) {
+	for {
+		line, err := rd.ReadString('\n')
+		if err != nil {
+			nc.fail(errNATSClosed)
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			nc.lock.Lock()
+			_, err = nc.conn.Write([]byte("PONG\r\n"))
+			nc.lock.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			err = fmt.Errorf("NATS server: %s", line)
+		}
+		if err != nil {
+			nc.fail(err)
+			return
+		}
+	}
+}
+
+func (nc *natsConn) fail(err error) {
+	nc.lock.Lock()
+	defer nc.lock.Unlock()
+	if nc.err == nil {
+		nc.err = err
+	}
+	nc.conn.Close()
+}
+
+func (nc *natsConn) write(msg []byte) error {
+	nc.lock.Lock()
+	defer nc.lock.Unlock()
+	if nc.err != nil {
+		return nc.err
+	}
+	_, err := fmt.Fprintf(nc.conn, "PUB %s %d\r\n%s\r\n", nc.subject, len(msg), msg)
+	return err
+}
+
+func (nc *natsConn) close() error {
+	nc.fail(errNATSClosed)
+	return nil
+}
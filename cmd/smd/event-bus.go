@@ -0,0 +1,213 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Event bus publishing
+//
+// If SMD_EVENT_BUS_URL is set, changes are published to a message bus as well
+// as being sent to SCN subscribers:
+//
+//     nats://[user:pass@]host:port/subject
+//     kafka://host:port/topic    (csm builds only)
+//
+// Each message is an sm.BusEvent, a versioned envelope with the Type of
+// change and its Data:
+//
+//     ComponentChange      the SCN payload for a State, Flag, Enabled,
+//                          SoftwareStatus or Role change, flag-only
+//                          changes included
+//     DiscoveryComplete    the DiscoveryStatus ID and endpoints discovered
+//     HWInventoryChange    each hardware difference a discovery found
+//
+// Events are queued and sent in order by one goroutine, which reconnects
+// if the bus goes away.  If the queue fills while the bus is unreachable,
+// new events are dropped and logged rather than holding up SMD.
+///////////////////////////////////////////////////////////////////////////////
+
+// Events queued for the bus before new ones are dropped.
+const eventBusQueueLen = 10000
+
+// Wait between attempts to connect to the bus.
+const eventBusRetryDelay = 5 * time.Second
+
+// Source of the events, in every BusEvent.
+const eventBusSource = "smd"
+
+var errEventBusURL = errors.New("event bus URL must be nats:// or kafka://host:port/topic")
+
+// Connection to a message bus, for one topic.
+type busConn interface {
+	write(msg []byte) error
+	close() error
+}
+
+type EventBus struct {
+	lock     sync.Mutex
+	url      string // Redacted, for logging
+	dial     func() (busConn, error)
+	queue    chan []byte
+	dropping bool
+	dropped  uint64
+}
+
+// Split a bus URL into scheme, host:port and topic.
+func parseEventBusURL(busURL string) (*url.URL, string, error) {
+	u, err := url.Parse(busURL)
+	if err != nil {
+		return nil, "", err
+	}
+	topic := strings.TrimPrefix(u.Path, "/")
+	if u.Hostname() == "" || u.Port() == "" || topic == "" ||
+		strings.Contains(topic, "/") {
+		return nil, "", errEventBusURL
+	}
+	if _, err := strconv.Atoi(u.Port()); err != nil {
+		return nil, "", errEventBusURL
+	}
+	return u, topic, nil
+}
+
+// Start publishing events to the bus at busURL.
+func (s *SmD) StartEventBus(busURL string) error {
+	u, topic, err := parseEventBusURL(busURL)
+	if err != nil {
+		return err
+	}
+	var dial func() (busConn, error)
+	switch strings.ToLower(u.Scheme) {
+	case "nats":
+		dial = func() (busConn, error) {
+			return dialNATS(u.Host, u.User, topic)
+		}
+	case "kafka":
+		port, _ := strconv.Atoi(u.Port())
+		dial = func() (busConn, error) {
+			return dialKafkaBus(u.Hostname(), port, topic)
+		}
+	default:
+		return errEventBusURL
+	}
+	s.eventBus.lock.Lock()
+	s.eventBus.url = u.Redacted()
+	s.eventBus.dial = dial
+	s.eventBus.queue = make(chan []byte, eventBusQueueLen)
+	s.eventBus.lock.Unlock()
+	go s.eventBusSend()
+	return nil
+}
+
+// Send queued events, connecting and reconnecting as needed.
+func (s *SmD) eventBusSend() {
+	eb := &s.eventBus
+	var conn busConn
+	for msg := range eb.queue {
+		// One retry on a fresh connection if the old one has gone bad.
+		for try := 0; try < 2; try++ {
+			for conn == nil {
+				c, err := eb.dial()
+				if err != nil {
+					s.LogAlways("WARNING: Can't connect to event bus %s: %s",
+						eb.url, err)
+					time.Sleep(eventBusRetryDelay)
+					continue
+				}
+				s.LogAlways("Connected to event bus %s", eb.url)
+				conn = c
+			}
+			err := conn.write(msg)
+			if err == nil {
+				break
+			}
+			s.LogAlways("WARNING: Event bus %s write failed: %s", eb.url, err)
+			conn.close()
+			conn = nil
+		}
+	}
+}
+
+// Queue an event for the bus, if there is one.
+func (s *SmD) publishBusEvent(eventType string, data interface{}) {
+	eb := &s.eventBus
+	eb.lock.Lock()
+	defer eb.lock.Unlock()
+	if eb.queue == nil {
+		return
+	}
+	msg, err := json.Marshal(sm.BusEvent{
+		Version:   sm.BusEventVersion,
+		Type:      eventType,
+		Source:    eventBusSource,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Data:      data,
+	})
+	if err != nil {
+		s.LogAlways("publishBusEvent(): Marshal %s: %s", eventType, err)
+		return
+	}
+	select {
+	case eb.queue <- msg:
+		if eb.dropping {
+			s.LogAlways("Event bus %s caught up, %d events were dropped",
+				eb.url, eb.dropped)
+			eb.dropping = false
+			eb.dropped = 0
+		}
+	default:
+		if !eb.dropping {
+			s.LogAlways("WARNING: Event bus %s queue full, dropping events",
+				eb.url)
+			eb.dropping = true
+		}
+		eb.dropped++
+	}
+}
+
+// A component's State, Flag, Enabled, SoftwareStatus or Role changed.
+func (s *SmD) compChanged(scn sm.SCNPayload) {
+	s.compStream.publish(scn)
+	if len(scn.Components) != 0 {
+		s.publishBusEvent(sm.BusCompChange, scn)
+	}
+}
+
+// A discovery of the given endpoints finished.  Any differences it found
+// have already been published as HWInventoryChange events.
+func (s *SmD) publishDiscoveryComplete(stat *sm.DiscoveryStatus, epIDs []string) {
+	s.publishBusEvent(sm.BusDiscComplete, sm.BusDiscoveryComplete{
+		DiscoveryID:        stat.ID,
+		RedfishEndpointIDs: epIDs,
+	})
+}
@@ -0,0 +1,179 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestParseEventBusURL(t *testing.T) {
+	tests := []struct {
+		busURL      string
+		expectHost  string
+		expectTopic string
+		expectErr   bool
+	}{
+		{"nats://nats:4222/smd.events", "nats:4222", "smd.events", false},
+		{"nats://user:pw@10.0.0.1:4222/smd", "10.0.0.1:4222", "smd", false},
+		{"kafka://kafka:9092/smd-events", "kafka:9092", "smd-events", false},
+		{"nats://nats/smd", "", "", true},
+		{"nats://nats:4222/", "", "", true},
+		{"nats://nats:4222/a/b", "", "", true},
+		{"nats://nats:port/smd", "", "", true},
+	}
+	for i, test := range tests {
+		u, topic, err := parseEventBusURL(test.busURL)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error for '%s'",
+					i, test.busURL)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+		} else if u.Host != test.expectHost || topic != test.expectTopic {
+			t.Errorf("Test %v Failed: Expected %s %s, got %s %s",
+				i, test.expectHost, test.expectTopic, u.Host, topic)
+		}
+	}
+	if err := s.StartEventBus("amqp://rabbit:5672/smd"); err != errEventBusURL {
+		t.Errorf("Expected %s for an unsupported bus, got %v", errEventBusURL, err)
+	}
+}
+
+func TestPublishBusEvent(t *testing.T) {
+	defer func() {
+		s.eventBus = EventBus{}
+	}()
+	// Nothing is queued without a bus.
+	s.eventBus = EventBus{}
+	s.publishBusEvent(sm.BusCompChange, sm.SCNPayload{})
+
+	s.eventBus.queue = make(chan []byte, 1)
+	s.compChanged(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, State: "Ready"})
+	s.compChanged(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, State: "Off"})
+	if !s.eventBus.dropping || s.eventBus.dropped != 1 {
+		t.Errorf("Expected 1 event dropped, got %v", s.eventBus.dropped)
+	}
+	ev := sm.BusEvent{Data: &sm.SCNPayload{}}
+	if err := json.Unmarshal(<-s.eventBus.queue, &ev); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if ev.Version != sm.BusEventVersion || ev.Type != sm.BusCompChange ||
+		ev.Source != eventBusSource || ev.Timestamp == "" {
+		t.Errorf("Unexpected event %+v", ev)
+	}
+	if scn := ev.Data.(*sm.SCNPayload); scn.State != "Ready" {
+		t.Errorf("Expected State Ready, got %+v", scn)
+	}
+	s.publishDiscoveryComplete(&sm.DiscoveryStatus{ID: 3}, []string{"x0c0s0b0"})
+	if s.eventBus.dropping {
+		t.Errorf("Expected dropping to end once there is room")
+	}
+	<-s.eventBus.queue
+}
+
+// Just enough of a NATS server to check what the client sends.
+func fakeNATSServer(t *testing.T, lines chan<- string) net.Listener {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %s", err)
+	}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rd := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		for {
+			line, err := rd.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case line == "PING":
+				conn.Write([]byte("PONG\r\nPING\r\n"))
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := rd.ReadString('\n')
+				line += " " + strings.TrimSpace(payload)
+			}
+			lines <- line
+		}
+	}()
+	return ln
+}
+
+func TestDialNATS(t *testing.T) {
+	lines := make(chan string, 10)
+	ln := fakeNATSServer(t, lines)
+	defer ln.Close()
+
+	conn, err := dialNATS(ln.Addr().String(), url.UserPassword("smd", "pw"), "smd.events")
+	if err != nil {
+		t.Fatalf("dialNATS: %s", err)
+	}
+	if err := conn.write([]byte(`{"Type":"ComponentChange"}`)); err != nil {
+		t.Errorf("write: %s", err)
+	}
+
+	// The PONG for the server's PING may come before or after the PUB.
+	expected := []string{
+		`CONNECT {"verbose":false,"pedantic":false,"name":"` + serviceName + `","lang":"go","version":"1","user":"smd","pass":"pw"}`,
+		"PING",
+		"PONG",
+		`PUB smd.events 26 {"Type":"ComponentChange"}`,
+	}
+	got := []string{}
+	for range expected {
+		select {
+		case line := <-lines:
+			got = append(got, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out, got %q", got)
+		}
+	}
+	sort.Strings(expected[2:])
+	sort.Strings(got[2:])
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	conn.close()
+	if err := conn.write([]byte("{}")); err == nil {
+		t.Errorf("Expected write after close to fail")
+	}
+}
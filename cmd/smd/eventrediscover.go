@@ -0,0 +1,101 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Rediscovery on ResourceAdded/ResourceRemoved events
+//
+// A ResourceEvent ResourceAdded or ResourceRemoved event for a ComputerSystem
+// or Chassis (or their collections) means a blade or node was swapped behind
+// the BMC, so the RedfishEndpoint is rediscovered to pick up the new
+// components and inventory.  BMCs send a burst of these while hardware comes
+// and goes, so the rediscovery is started SMD_EVENT_REDISCOVER_DELAY_SECS
+// (default 15) after the first event and covers all of them.  As with
+// PATCHes, endpoints with RediscoverOnUpdate false are left alone.
+///////////////////////////////////////////////////////////////////////////////
+
+const DefaultEventRediscoverDelay = 15 * time.Second
+
+// Rediscoveries waiting for their delay to pass, by RedfishEndpoint.
+type EventRediscoverer struct {
+	lock    sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// Whether a Redfish URI is a ComputerSystem or Chassis, or their collection.
+func isSystemOrChassisURI(uri string) bool {
+	if i := strings.Index(uri, "#"); i >= 0 {
+		uri = uri[:i]
+	}
+	fields := strings.Split(strings.Trim(strings.ToLower(uri), "/"), "/")
+	if len(fields) < 3 || len(fields) > 4 ||
+		fields[0] != "redfish" || fields[1] != "v1" {
+		return false
+	}
+	return fields[2] == "systems" || fields[2] == "chassis"
+}
+
+// Schedule a rediscovery of a RedfishEndpoint unless one is already pending.
+func (s *SmD) eventRediscoverLater(epID, reason string) {
+	if s.disableDiscovery {
+		return
+	}
+	er := &s.eventRedisc
+	er.lock.Lock()
+	defer er.lock.Unlock()
+
+	if er.pending == nil {
+		er.pending = make(map[string]*time.Timer)
+	}
+	if _, ok := er.pending[epID]; ok {
+		return
+	}
+	s.LogAlways("Rediscovering %s in %s after %s", epID, s.eventRediscDelay,
+		reason)
+	er.pending[epID] = time.AfterFunc(s.eventRediscDelay, func() {
+		s.eventRediscover(epID)
+	})
+}
+
+// Rediscover a RedfishEndpoint once its delay has passed.
+func (s *SmD) eventRediscover(epID string) {
+	s.eventRedisc.lock.Lock()
+	delete(s.eventRedisc.pending, epID)
+	s.eventRedisc.lock.Unlock()
+
+	ep, err := s.db.GetRFEndpointByID(epID)
+	if err != nil {
+		s.LogAlways("eventRediscover(): Lookup failure on %s: %s", epID, err)
+		return
+	} else if ep == nil {
+		s.Log(LOG_INFO, "eventRediscover(): %s is not a RedfishEndpoint", epID)
+		return
+	}
+	s.discoverFromEndpoint(ep, 0, false)
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestIsSystemOrChassisURI(t *testing.T) {
+	tests := []struct {
+		uri string
+		exp bool
+	}{
+		{"/redfish/v1/Systems/Node0", true},
+		{"/redfish/v1/Systems/Node0/", true},
+		{"/redfish/v1/Chassis", true},
+		{"/redfish/v1/Chassis/Blade3#/Status", true},
+		{"/redfish/v1/Systems/Node0/Storage/1/Drives/0", false},
+		{"/redfish/v1/Managers/BMC", false},
+		{"", false},
+	}
+	for i, test := range tests {
+		if out := isSystemOrChassisURI(test.uri); out != test.exp {
+			t.Errorf("Test %d: %s: Expected %v, got %v", i, test.uri,
+				test.exp, out)
+		}
+	}
+}
+
+func TestEventRediscover(t *testing.T) {
+	delay := s.eventRediscDelay
+	defer func() {
+		s.eventRediscDelay = delay
+		s.eventRedisc = EventRediscoverer{}
+		results.GetRFEndpointByID.Return.entry = nil
+		results.UpdateRFEndpointForDiscover.Input.ids = nil
+	}()
+	// Long enough not to fire during the test
+	s.eventRediscDelay = time.Hour
+
+	for _, msgID := range []string{"ResourceRemoved", "ResourceAdded"} {
+		pe := &processedRFEvent{
+			MessageId:     msgID,
+			Registry:      "ResourceEvent",
+			RfEndppointID: "x1000c0s5b0",
+			Origin:        "/redfish/v1/Systems/Node1",
+		}
+		if parser := s.GetEventActionParser(pe); parser == nil {
+			t.Fatalf("No parser for ResourceEvent %s", msgID)
+		} else if u, err := parser(s, pe); u != nil || err != nil {
+			t.Fatalf("Unexpected parser result: %v %v", u, err)
+		}
+	}
+	s.eventRedisc.lock.Lock()
+	timer := s.eventRedisc.pending["x1000c0s5b0"]
+	num := len(s.eventRedisc.pending)
+	s.eventRedisc.lock.Unlock()
+	if timer == nil || num != 1 {
+		t.Fatalf("Expected one pending rediscovery, got %d", num)
+	}
+	timer.Stop()
+
+	for _, test := range []struct {
+		redisc bool
+		expIDs int
+	}{{false, 0}, {true, 1}} {
+		results.UpdateRFEndpointForDiscover.Input.ids = nil
+		results.GetRFEndpointByID.Return.entry = &sm.RedfishEndpoint{
+			RedfishEPDescription: rf.RedfishEPDescription{
+				ID: "x1000c0s5b0", Enabled: true,
+				RediscOnUpdate: test.redisc}}
+		s.eventRediscover("x1000c0s5b0")
+		if ids := results.UpdateRFEndpointForDiscover.Input.ids; len(ids) != test.expIDs {
+			t.Errorf("RediscOnUpdate %v: Expected %d endpoints rediscovered, got %v",
+				test.redisc, test.expIDs, ids)
+		}
+	}
+	if len(s.eventRedisc.pending) != 0 {
+		t.Errorf("Rediscovery still pending after it ran")
+	}
+}
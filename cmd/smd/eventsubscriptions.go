@@ -0,0 +1,416 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Redfish event subscriptions
+//
+// With SMD_EVENT_COLLECTOR_URL set, SMD can keep a Redfish event
+// subscription on each RedfishEndpoint pointing at the event collector, with
+// SMD_EVENT_CONTEXT (or else the endpoint's xname), followed by
+// ":<SMD_EVENT_SECRET>" if that is set, as its Context.  Setting
+// "EventSubscription": true when POSTing RedfishEndpoints has one created
+// when each endpoint is discovered, and again on rediscovery if it has gone
+// missing, e.g. after a BMC reset.
+//
+//     POST /Inventory/EventSubscriptions/Actions/Create
+//     POST /Inventory/EventSubscriptions/Actions/Verify
+//     POST /Inventory/EventSubscriptions/Actions/Repair
+//
+// do the same for a list of RedfishEndpoints and/or the members of groups,
+// check that their subscriptions are still there, or recreate them and
+// remove any others for the collector that don't match.  Verify and Repair
+// default to all endpoints with a subscription.  The state of each
+// subscription as of the last operation on it is kept in the DB:
+//
+//     GET /Inventory/EventSubscriptions[?id=<xname>...]
+//     GET /Inventory/EventSubscriptions/{xname}
+///////////////////////////////////////////////////////////////////////////////
+
+// Event subscription operations
+const (
+	EventSubOpCreate = "Create"
+	EventSubOpVerify = "Verify"
+	EventSubOpRepair = "Repair"
+)
+
+// How many endpoints are worked on at once.
+const eventSubFanout = 64
+
+var errEventSubNoCollector = errors.New(
+	"no event collector configured (SMD_EVENT_COLLECTOR_URL)")
+var errEventSubNoEndpoint = errors.New("no such RedfishEndpoint")
+var errEventSubNotKept = errors.New(
+	"no event subscription is kept for this RedfishEndpoint")
+var errEventSubNoService = errors.New(
+	"no EventService Subscriptions discovered for this RedfishEndpoint")
+
+// The Context to subscribe RedfishEndpoint id with.
+func (s *SmD) eventSubContext(id string) string {
+	ctx := id
+	if s.eventCtx != "" {
+		ctx = s.eventCtx
+	}
+	if s.eventSecret != "" {
+		ctx += ":" + s.eventSecret
+	}
+	return ctx
+}
+
+// A new event subscription for RedfishEndpoint id to the configured
+// collector, not yet created on the endpoint.
+func (s *SmD) newEventSubscription(id string) *sm.EventSubscription {
+	return &sm.EventSubscription{
+		RedfishEndpointID: id,
+		Destination:       s.eventCollector,
+		Context:           s.eventSubContext(id),
+		Status:            sm.EventSubPending,
+	}
+}
+
+// Keep an event subscription on the given, newly added, RedfishEndpoints.
+// It is created once they are discovered.
+func (s *SmD) addEventSubscriptions(eps []*sm.RedfishEndpoint) {
+	for _, ep := range eps {
+		if err := s.db.UpsertEventSubscription(s.newEventSubscription(ep.ID)); err != nil {
+			s.LogAlways("Failed to add event subscription for %s: %s",
+				ep.ID, err)
+		}
+	}
+}
+
+// Bring the event subscription sub on rfEP, in the Subscriptions
+// collection subPath, in line with op, and update sub with the result.
+func (s *SmD) eventSubSync(rfEP *rf.RedfishEP, subPath string, sub *sm.EventSubscription, op string) {
+	sub.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+	err := s.eventSubSyncOne(rfEP, subPath, sub, op)
+	if err != nil {
+		sub.Status = sm.EventSubFailed
+		sub.LastError = err.Error()
+	}
+}
+
+func (s *SmD) eventSubSyncOne(rfEP *rf.RedfishEP, subPath string, sub *sm.EventSubscription, op string) error {
+	if subPath == "" {
+		return errEventSubNoService
+	}
+	subs, err := rfEP.GetEventSubscriptions(subPath, sub.Destination)
+	if err != nil {
+		return err
+	}
+	uri := ""
+	stale := []string{}
+	for _, es := range subs {
+		if uri == "" && es.Context == sub.Context &&
+			es.EventFormatType != "MetricReport" {
+			uri = es.Oid
+		} else {
+			stale = append(stale, es.Oid)
+		}
+	}
+	if op == EventSubOpRepair {
+		for _, oid := range stale {
+			if err := rfEP.DeleteEventSubscription(oid); err != nil {
+				return err
+			}
+			s.LogAlways("Removed stale event subscription %s from %s",
+				oid, rfEP.ID)
+		}
+	}
+	if uri == "" && op != EventSubOpVerify {
+		uri, err = rfEP.CreateEventSubscription(subPath, sub.Destination,
+			sub.Context)
+		if err != nil {
+			return err
+		}
+		s.LogAlways("Created event subscription %s on %s", uri, rfEP.ID)
+	}
+	sub.SubscriptionURI = uri
+	sub.LastError = ""
+	if uri == "" {
+		sub.Status = sm.EventSubMissing
+	} else {
+		sub.Status = sm.EventSubActive
+	}
+	return nil
+}
+
+// Create the event subscription of a just discovered RedfishEndpoint if
+// one is to be kept on it and it isn't there.
+func (s *SmD) eventSubscribe(rfEP *rf.RedfishEP) {
+	if s.eventCollector == "" || rfEP.DiscInfo.LastStatus != rf.DiscoverOK {
+		return
+	}
+	subs, err := s.db.GetEventSubscriptions([]string{rfEP.ID})
+	if err != nil {
+		s.LogAlways("eventSubscribe(%s): GetEventSubscriptions: %s",
+			rfEP.ID, err)
+		return
+	} else if len(subs) == 0 {
+		return
+	}
+	sub := subs[0]
+	sub.Destination = s.eventCollector
+	sub.Context = s.eventSubContext(rfEP.ID)
+	subPath := ""
+	if rfEP.EventService != nil {
+		subPath = rfEP.EventService.EventServiceRF.Subscriptions.Oid
+	}
+	s.eventSubSync(rfEP, subPath, sub, EventSubOpCreate)
+	if sub.Status == sm.EventSubFailed {
+		s.LogAlways("Failed to subscribe to events from %s: %s",
+			rfEP.ID, sub.LastError)
+	}
+	if err := s.db.UpsertEventSubscription(sub); err != nil {
+		s.LogAlways("eventSubscribe(%s): UpsertEventSubscription: %s",
+			rfEP.ID, err)
+	}
+}
+
+// Get the event subscription kept on a single RedfishEndpoint
+func (s *SmD) doEventSubscriptionGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	subs, err := s.db.GetEventSubscriptions([]string{xname})
+	if err != nil {
+		s.lg.Printf("doEventSubscriptionGet(): Lookup failure: %s %s", xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if len(subs) == 0 {
+		sendJsonError(w, http.StatusNotFound,
+			"no event subscription is kept for this RedfishEndpoint")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, subs[0])
+}
+
+// Get the event subscriptions kept on all or some RedfishEndpoints
+func (s *SmD) doEventSubscriptionsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	ids := []string{}
+	for _, id := range r.URL.Query()["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	subs, err := s.db.GetEventSubscriptions(ids)
+	if err != nil {
+		s.lg.Printf("doEventSubscriptionsGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK,
+		sm.EventSubscriptionArray{EventSubscriptions: subs})
+}
+
+// Create, verify or repair the event subscriptions of RedfishEndpoints.
+// Returns the resulting state of each.
+func (s *SmD) doEventSubscriptionsActionPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	op := ""
+	for _, o := range []string{EventSubOpCreate, EventSubOpVerify, EventSubOpRepair} {
+		if strings.EqualFold(chi.URLParam(r, "operation"), o) {
+			op = o
+		}
+	}
+	if op == "" {
+		sendJsonError(w, http.StatusBadRequest,
+			"operation must be Create, Verify or Repair")
+		return
+	}
+	if op != EventSubOpVerify && s.eventCollector == "" {
+		sendJsonError(w, http.StatusBadRequest, errEventSubNoCollector.Error())
+		return
+	}
+	var in RFEndpointTargets
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body")
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &in); err != nil {
+			sendJsonError(w, http.StatusBadRequest,
+				"error decoding JSON "+err.Error())
+			return
+		}
+	}
+
+	// Verify and Repair default to every endpoint with a subscription.
+	var ids []string
+	if op != EventSubOpCreate && len(in.Targets) == 0 && len(in.Groups) == 0 {
+		subs, err := s.db.GetEventSubscriptions(nil)
+		if err != nil {
+			s.lg.Printf("doEventSubscriptionsActionPost(): Lookup failure: %s", err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		for _, sub := range subs {
+			ids = append(ids, sub.RedfishEndpointID)
+		}
+	} else {
+		var code int
+		var msg string
+		ids, code, msg = s.rfEndpointTargetIDs(&in, nil)
+		if code != 0 {
+			sendJsonError(w, code, msg)
+			return
+		}
+	}
+	subs, err := s.doEventSubAction(ids, op)
+	if err != nil {
+		s.lg.Printf("doEventSubscriptionsActionPost(): %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK,
+		sm.EventSubscriptionArray{EventSubscriptions: subs})
+}
+
+// Run op on the event subscriptions of the RedfishEndpoints ids, with the
+// stored info of their EventService, and store the results.
+func (s *SmD) doEventSubAction(ids []string, op string) ([]*sm.EventSubscription, error) {
+	subs := make([]*sm.EventSubscription, 0, len(ids))
+	if len(ids) == 0 {
+		return subs, nil
+	}
+	kept, err := s.db.GetEventSubscriptions(ids)
+	if err != nil {
+		return nil, err
+	}
+	eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{ID: ids})
+	if err != nil {
+		return nil, err
+	}
+	seps, err := s.db.GetServiceEndpointsFilter(&hmsds.ServiceEPFilter{
+		Service:      []string{rf.EventServiceType},
+		RfEndpointID: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+	subMap := make(map[string]*sm.EventSubscription, len(kept))
+	for _, sub := range kept {
+		subMap[sub.RedfishEndpointID] = sub
+	}
+	epMap := make(map[string]*sm.RedfishEndpoint, len(eps))
+	for _, ep := range eps {
+		epMap[ep.ID] = ep
+	}
+	sepMap := make(map[string]*sm.ServiceEndpoint, len(seps))
+	for _, sep := range seps {
+		sepMap[sep.RfEndpointID] = sep
+	}
+
+	// Which results are stored; not those for unknown endpoints, or those
+	// without a subscription that were only verified.
+	store := make([]bool, len(ids))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, eventSubFanout)
+	for i, id := range ids {
+		sub := subMap[id]
+		if sub == nil {
+			sub = s.newEventSubscription(id)
+		} else if op != EventSubOpVerify {
+			sub.Destination = s.eventCollector
+			sub.Context = s.eventSubContext(id)
+		}
+		subs = append(subs, sub)
+		ep := epMap[id]
+		if ep == nil {
+			sub.Status = sm.EventSubFailed
+			sub.LastError = errEventSubNoEndpoint.Error()
+			continue
+		} else if subMap[id] == nil && op == EventSubOpVerify {
+			sub.Status = sm.EventSubFailed
+			sub.LastError = errEventSubNotKept.Error()
+			continue
+		}
+		store[i] = true
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			s.eventSubActionOne(ep, sepMap[id], sub, op)
+		}()
+	}
+	wg.Wait()
+
+	for i, sub := range subs {
+		if !store[i] {
+			continue
+		}
+		if err := s.db.UpsertEventSubscription(sub); err != nil {
+			return nil, err
+		}
+	}
+	return subs, nil
+}
+
+// Set up the connection to one endpoint and run op on its subscription.
+func (s *SmD) eventSubActionOne(
+	ep *sm.RedfishEndpoint,
+	sep *sm.ServiceEndpoint,
+	sub *sm.EventSubscription,
+	op string,
+) {
+	subPath := ""
+	if sep != nil {
+		var info rf.EventService
+		if err := json.Unmarshal(sep.ServiceInfo, &info); err == nil {
+			subPath = info.Subscriptions.Oid
+		}
+	}
+	rfEP, err := s.connectRedfishEP(ep)
+	if err != nil {
+		sub.Status = sm.EventSubFailed
+		sub.LastError = err.Error()
+		sub.LastUpdated = time.Now().UTC().Format(time.RFC3339)
+		return
+	}
+	s.eventSubSync(rfEP, subPath, sub, op)
+	if sub.Status == sm.EventSubFailed {
+		s.LogAlways("Event subscription %s failed for %s: %s", op, ep.ID,
+			sub.LastError)
+	}
+}
@@ -0,0 +1,284 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+const testEventCollector = "https://collector.local/events"
+
+// BMC EventService keeping its subscriptions in memory.
+type testEventService struct {
+	sync.Mutex
+	subs map[string]rf.EventDestination
+	next int
+}
+
+func (es *testEventService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const coll = "/redfish/v1/EventService/Subscriptions"
+	es.Lock()
+	defer es.Unlock()
+	switch {
+	case r.Method == "GET" && r.URL.Path == coll:
+		members := []string{}
+		for oid := range es.subs {
+			members = append(members, `{"@odata.id": "`+oid+`"}`)
+		}
+		w.Write([]byte(`{"Members": [` + strings.Join(members, ",") + `]}`))
+	case r.Method == "POST" && r.URL.Path == coll:
+		var sub rf.EventDestination
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &sub)
+		es.next++
+		sub.Oid = fmt.Sprintf("%s/%d", coll, es.next)
+		es.subs[sub.Oid] = sub
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(sub)
+	case r.Method == "GET":
+		if sub, ok := es.subs[r.URL.Path]; ok {
+			json.NewEncoder(w).Encode(sub)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == "DELETE":
+		delete(es.subs, r.URL.Path)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// The Contexts of the subscriptions to dest, sorted.
+func (es *testEventService) contexts(dest string) []string {
+	es.Lock()
+	defer es.Unlock()
+	ctxs := []string{}
+	for _, sub := range es.subs {
+		if sub.Destination == dest {
+			ctxs = append(ctxs, sub.Context)
+		}
+	}
+	sort.Strings(ctxs)
+	return ctxs
+}
+
+func TestEventSubContext(t *testing.T) {
+	defer func() { s.eventCtx, s.eventSecret = "", "" }()
+	tests := []struct {
+		ctx, secret, expected string
+	}{
+		{"", "", "x3000c0s9b0"},
+		{"", "secret", "x3000c0s9b0:secret"},
+		{"mysite", "", "mysite"},
+		{"mysite", "secret", "mysite:secret"},
+	}
+	for i, test := range tests {
+		s.eventCtx, s.eventSecret = test.ctx, test.secret
+		if ctx := s.eventSubContext("x3000c0s9b0"); ctx != test.expected {
+			t.Errorf("Test %d: expected '%s', got '%s'", i, test.expected, ctx)
+		}
+	}
+}
+
+func TestEventSubscriptions(t *testing.T) {
+	es := &testEventService{subs: map[string]rf.EventDestination{
+		"/redfish/v1/EventService/Subscriptions/old": {
+			Oid:         "/redfish/v1/EventService/Subscriptions/old",
+			Destination: testEventCollector, Context: "stale"},
+		"/redfish/v1/EventService/Subscriptions/other": {
+			Oid:         "/redfish/v1/EventService/Subscriptions/other",
+			Destination: "https://elsewhere.local/events", Context: "other"},
+	}}
+	server := httptest.NewTLSServer(es)
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	readVault := s.readVault
+	writeVault := s.writeVault
+	disableDiscovery := s.disableDiscovery
+	defer func() {
+		s.readVault = readVault
+		s.writeVault = writeVault
+		s.disableDiscovery = disableDiscovery
+		s.eventCollector = ""
+		results.InsertRFEndpoints.Return.err = nil
+		results.GetEventSubscriptions.Return.subs = nil
+		results.UpsertEventSubscription.Input.subs = nil
+		results.GetRFEndpointsFilter.Return.entries = nil
+		results.GetServiceEndpointsFilter.Return.entries = nil
+	}()
+	s.readVault = false
+	s.writeVault = false
+	s.disableDiscovery = true
+	s.eventCollector = ""
+
+	// Registering with a subscription needs a collector
+	regBody := []byte(`{"EventSubscription": true, "RedfishEndpoints": [
+		{"ID": "x3000c0s9b0", "FQDN": "` + u.Host + `"}]}`)
+	post := func(path string, body []byte) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "https://localhost/hsm/v2"+path,
+			bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	if w := post("/Inventory/RedfishEndpoints", regBody); w.Code != http.StatusBadRequest {
+		t.Errorf("Registering without a collector: Expected 400, got %d %s",
+			w.Code, w.Body.String())
+	}
+	s.eventCollector = testEventCollector
+	results.UpsertEventSubscription.Input.subs = nil
+	if w := post("/Inventory/RedfishEndpoints", regBody); w.Code != http.StatusCreated {
+		t.Errorf("Registering: Expected 201, got %d %s", w.Code, w.Body.String())
+	}
+	expPending := []sm.EventSubscription{{RedfishEndpointID: "x3000c0s9b0",
+		Destination: testEventCollector, Context: "x3000c0s9b0",
+		Status: sm.EventSubPending}}
+	if subs := results.UpsertEventSubscription.Input.subs; len(subs) != 1 ||
+		subs[0] != expPending[0] {
+		t.Errorf("Registering: Expected %+v stored, got %+v", expPending, subs)
+	}
+
+	results.GetRFEndpointsFilter.Return.entries = []*sm.RedfishEndpoint{{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID: "x3000c0s9b0", Type: "NodeBMC", FQDN: u.Host, Enabled: true}}}
+	results.GetServiceEndpointsFilter.Return.entries = []*sm.ServiceEndpoint{{
+		ServiceDescription: rf.ServiceDescription{
+			RfEndpointID: "x3000c0s9b0", RedfishType: rf.EventServiceType},
+		ServiceInfo: json.RawMessage(`{"Subscriptions": {
+			"@odata.id": "/redfish/v1/EventService/Subscriptions"}}`),
+	}}
+	tests := []struct {
+		op          string
+		body        string
+		kept        bool
+		expCode     int
+		expStatus   string
+		expContexts []string // Subscriptions to the collector afterwards
+	}{
+		{"Create", `{"Targets": ["x3000c0s9b0"]}`, false, http.StatusOK,
+			sm.EventSubActive, []string{"stale", "x3000c0s9b0"}},
+		{"Verify", ``, true, http.StatusOK,
+			sm.EventSubActive, []string{"stale", "x3000c0s9b0"}},
+		{"repair", `{"Targets": ["x3000c0s9b0"]}`, true, http.StatusOK,
+			sm.EventSubActive, []string{"x3000c0s9b0"}},
+		// Not kept, so nothing to verify
+		{"Verify", `{"Targets": ["x3000c0s9b0"]}`, false, http.StatusOK,
+			sm.EventSubFailed, []string{"x3000c0s9b0"}},
+		{"Delete", ``, true, http.StatusBadRequest, "", nil},
+	}
+	var kept *sm.EventSubscription
+	for i, test := range tests {
+		results.GetEventSubscriptions.Return.subs = nil
+		if test.kept {
+			results.GetEventSubscriptions.Return.subs = []*sm.EventSubscription{kept}
+		}
+		results.UpsertEventSubscription.Input.subs = nil
+		w := post("/Inventory/EventSubscriptions/Actions/"+test.op,
+			[]byte(test.body))
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if test.expCode != http.StatusOK {
+			continue
+		}
+		var out sm.EventSubscriptionArray
+		json.Unmarshal(w.Body.Bytes(), &out)
+		if len(out.EventSubscriptions) != 1 ||
+			out.EventSubscriptions[0].Status != test.expStatus {
+			t.Errorf("Test %d: Expected status %s, got %s", i, test.expStatus,
+				w.Body.String())
+			continue
+		}
+		sub := out.EventSubscriptions[0]
+		if test.expStatus == sm.EventSubActive {
+			if !strings.HasPrefix(sub.SubscriptionURI,
+				"/redfish/v1/EventService/Subscriptions/") ||
+				(kept != nil && sub.SubscriptionURI != kept.SubscriptionURI) {
+				t.Errorf("Test %d: Unexpected SubscriptionURI %s", i,
+					sub.SubscriptionURI)
+			}
+			if stored := results.UpsertEventSubscription.Input.subs; len(stored) != 1 ||
+				stored[0] != *sub {
+				t.Errorf("Test %d: Expected %+v stored, got %+v", i, sub, stored)
+			}
+			kept = sub
+		} else if len(results.UpsertEventSubscription.Input.subs) != 0 {
+			t.Errorf("Test %d: Expected nothing stored", i)
+		}
+		ctxs := es.contexts(testEventCollector)
+		if strings.Join(ctxs, ",") != strings.Join(test.expContexts, ",") {
+			t.Errorf("Test %d: Expected subscriptions %v, got %v", i,
+				test.expContexts, ctxs)
+		}
+	}
+	if ctxs := es.contexts("https://elsewhere.local/events"); len(ctxs) != 1 {
+		t.Errorf("Subscription to another destination was changed: %v", ctxs)
+	}
+
+	// Gone after a BMC reset
+	es.Lock()
+	delete(es.subs, kept.SubscriptionURI)
+	es.Unlock()
+	results.GetEventSubscriptions.Return.subs = []*sm.EventSubscription{kept}
+	w := post("/Inventory/EventSubscriptions/Actions/Verify", nil)
+	if w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), `"Status":"Missing"`) {
+		t.Errorf("Verify after reset: Unexpected response %d %s", w.Code,
+			w.Body.String())
+	}
+
+	for _, test := range []struct {
+		subs    []*sm.EventSubscription
+		expCode int
+	}{{nil, http.StatusNotFound}, {[]*sm.EventSubscription{kept}, http.StatusOK}} {
+		results.GetEventSubscriptions.Return.subs = test.subs
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Inventory/EventSubscriptions/X3000c0s9b0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("GET: Expected code %d, got %d %s", test.expCode, w.Code,
+				w.Body.String())
+		}
+		if ids := results.GetEventSubscriptions.Input.ids; len(ids) != 1 ||
+			ids[0] != "x3000c0s9b0" {
+			t.Errorf("GET: Looked up wrong subscription: %v", ids)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Firmware inventory
+//
+// Discovery keeps the update targets of each RedfishEndpoint's
+// UpdateService, and the members of its FirmwareInventory collection, in the
+// ServiceInfo of its UpdateService ServiceEndpoint.
+//
+//     GET /Inventory/FirmwareInventory/{xname}
+//     GET /Inventory/FirmwareInventory[?id=<xname>...]
+//
+// return them in a flat form, so the firmware versions on a BMC (and where
+// to send an update) can be looked up without talking to the BMC.
+///////////////////////////////////////////////////////////////////////////////
+
+// Firmware versions of one RedfishEndpoint, as last discovered.
+type FirmwareInventory struct {
+	RedfishEndpointID    string                 `json:"RedfishEndpointID"`
+	UpdateServiceURI     string                 `json:"UpdateServiceURI"`
+	SimpleUpdateTarget   string                 `json:"SimpleUpdateTarget,omitempty"`
+	HttpPushUri          string                 `json:"HttpPushUri,omitempty"`
+	FirmwareInventoryURI string                 `json:"FirmwareInventoryURI,omitempty"`
+	Firmware             []rf.SoftwareInventory `json:"Firmware"`
+}
+
+// Output of GET /Inventory/FirmwareInventory
+type FirmwareInventoryArray struct {
+	FirmwareInventory []*FirmwareInventory `json:"FirmwareInventory"`
+}
+
+// Flatten the ServiceInfo of an UpdateService ServiceEndpoint.
+func newFirmwareInventory(sep *sm.ServiceEndpoint) (*FirmwareInventory, error) {
+	info := new(rf.UpdateServiceInfo)
+	if err := json.Unmarshal(sep.ServiceInfo, info); err != nil {
+		return nil, err
+	}
+	fwi := &FirmwareInventory{
+		RedfishEndpointID:  sep.RfEndpointID,
+		UpdateServiceURI:   sep.OdataID,
+		SimpleUpdateTarget: info.SimpleUpdateTarget(),
+		HttpPushUri:        info.HttpPushUri,
+		Firmware:           info.Firmware,
+	}
+	if info.FirmwareInventory != nil {
+		fwi.FirmwareInventoryURI = info.FirmwareInventory.Oid
+	}
+	if fwi.Firmware == nil {
+		fwi.Firmware = []rf.SoftwareInventory{}
+	}
+	return fwi, nil
+}
+
+// Get the firmware inventory of a single RedfishEndpoint
+func (s *SmD) doFirmwareInventoryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	sep, err := s.db.GetServiceEndpointByID(rf.UpdateServiceType, xname)
+	if err != nil {
+		s.lg.Printf("doFirmwareInventoryGet(): Lookup failure: %s %s",
+			xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if sep == nil {
+		sendJsonError(w, http.StatusNotFound,
+			"no UpdateService under this redfish endpoint")
+		return
+	}
+	fwi, err := newFirmwareInventory(sep)
+	if err != nil {
+		s.lg.Printf("doFirmwareInventoryGet(): Bad ServiceInfo: %s %s",
+			xname, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode UpdateService info")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, fwi)
+}
+
+// Get the firmware inventory of all or some RedfishEndpoints
+func (s *SmD) doFirmwareInventoryGetAll(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	ids := []string{}
+	for _, id := range r.URL.Query()["id"] {
+		ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+	}
+	seps, err := s.db.GetServiceEndpointsFilter(&hmsds.ServiceEPFilter{
+		Service:      []string{rf.UpdateServiceType},
+		RfEndpointID: ids,
+	})
+	if err != nil {
+		s.lg.Printf("doFirmwareInventoryGetAll(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	fwis := FirmwareInventoryArray{
+		FirmwareInventory: make([]*FirmwareInventory, 0, len(seps)),
+	}
+	for _, sep := range seps {
+		fwi, err := newFirmwareInventory(sep)
+		if err != nil {
+			// Skip it rather than fail the whole query.
+			s.lg.Printf("doFirmwareInventoryGetAll(): Bad ServiceInfo: %s %s",
+				sep.RfEndpointID, err)
+			continue
+		}
+		fwis.FirmwareInventory = append(fwis.FirmwareInventory, fwi)
+	}
+	sort.Slice(fwis.FirmwareInventory, func(i, j int) bool {
+		return fwis.FirmwareInventory[i].RedfishEndpointID <
+			fwis.FirmwareInventory[j].RedfishEndpointID
+	})
+	sendJsonObject(w, http.StatusOK, fwis)
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+const testUpdateServiceInfo = `{
+	"@odata.id": "/redfish/v1/UpdateService",
+	"Id": "UpdateService",
+	"FirmwareInventory": {"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory"},
+	"Actions": {
+		"#UpdateService.SimpleUpdate": {
+			"target": "/redfish/v1/UpdateService/Actions/SimpleUpdate"
+		}
+	},
+	"HttpPushUri": "/redfish/v1/UpdateService/update",
+	"Firmware": [{
+		"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BMC",
+		"Id": "BMC",
+		"Name": "BMC Firmware",
+		"Version": "1.45.2",
+		"Updateable": true
+	}, {
+		"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BIOS",
+		"Id": "BIOS",
+		"Name": "BIOS",
+		"Version": "2.18"
+	}]
+}`
+
+func TestFirmwareInventoryGet(t *testing.T) {
+	defer func() {
+		results.GetServiceEndpointByID.Return.entry = nil
+		results.GetServiceEndpointByID.Return.err = nil
+		results.GetServiceEndpointsFilter.Return.entries = nil
+		results.GetServiceEndpointsFilter.Return.err = nil
+	}()
+	sep := &sm.ServiceEndpoint{
+		ServiceDescription: rf.ServiceDescription{
+			RfEndpointID: "x1000c0s0b0",
+			RedfishType:  rf.UpdateServiceType,
+			OdataID:      "/redfish/v1/UpdateService",
+		},
+		ServiceInfo: json.RawMessage(testUpdateServiceInfo),
+	}
+	// Discovered before the firmware inventory was kept
+	oldSep := &sm.ServiceEndpoint{
+		ServiceDescription: rf.ServiceDescription{
+			RfEndpointID: "x1000c0s1b0",
+			RedfishType:  rf.UpdateServiceType,
+			OdataID:      "/redfish/v1/UpdateService",
+		},
+		ServiceInfo: json.RawMessage(`{"@odata.id": "/redfish/v1/UpdateService"}`),
+	}
+	updateable := true
+	expFwi := &FirmwareInventory{
+		RedfishEndpointID:    "x1000c0s0b0",
+		UpdateServiceURI:     "/redfish/v1/UpdateService",
+		SimpleUpdateTarget:   "/redfish/v1/UpdateService/Actions/SimpleUpdate",
+		HttpPushUri:          "/redfish/v1/UpdateService/update",
+		FirmwareInventoryURI: "/redfish/v1/UpdateService/FirmwareInventory",
+		Firmware: []rf.SoftwareInventory{{
+			Oid:        "/redfish/v1/UpdateService/FirmwareInventory/BMC",
+			Id:         "BMC",
+			Name:       "BMC Firmware",
+			Version:    "1.45.2",
+			Updateable: &updateable,
+		}, {
+			Oid:     "/redfish/v1/UpdateService/FirmwareInventory/BIOS",
+			Id:      "BIOS",
+			Name:    "BIOS",
+			Version: "2.18",
+		}},
+	}
+
+	// Single endpoint
+	results.GetServiceEndpointByID.Return.entry = sep
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/FirmwareInventory/X1000c0s0b0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var fwi FirmwareInventory
+	json.Unmarshal(w.Body.Bytes(), &fwi)
+	if w.Code != http.StatusOK || !reflect.DeepEqual(&fwi, expFwi) {
+		t.Errorf("Single: Expected %+v, got %d %s", expFwi, w.Code,
+			w.Body.String())
+	}
+	if results.GetServiceEndpointByID.Input.svc != rf.UpdateServiceType ||
+		results.GetServiceEndpointByID.Input.id != "x1000c0s0b0" {
+		t.Errorf("Single: Looked up wrong ServiceEndpoint: %s %s",
+			results.GetServiceEndpointByID.Input.svc,
+			results.GetServiceEndpointByID.Input.id)
+	}
+	results.GetServiceEndpointByID.Return.entry = nil
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Missing: Expected %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// Collection, sorted, with the old entry having no firmware
+	results.GetServiceEndpointsFilter.Return.entries = []*sm.ServiceEndpoint{
+		oldSep, sep}
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/FirmwareInventory?id=x1000c0s0b0&id=x1000c0s1b0", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var fwis FirmwareInventoryArray
+	json.Unmarshal(w.Body.Bytes(), &fwis)
+	if w.Code != http.StatusOK || len(fwis.FirmwareInventory) != 2 {
+		t.Fatalf("Collection: Unexpected response %d %s", w.Code,
+			w.Body.String())
+	}
+	if !reflect.DeepEqual(fwis.FirmwareInventory[0], expFwi) {
+		t.Errorf("Collection: Expected %+v, got %+v", expFwi,
+			fwis.FirmwareInventory[0])
+	}
+	if old := fwis.FirmwareInventory[1]; old.RedfishEndpointID != "x1000c0s1b0" ||
+		old.Firmware == nil || len(old.Firmware) != 0 {
+		t.Errorf("Collection: Unexpected old entry %+v", old)
+	}
+	f := results.GetServiceEndpointsFilter.Input.f
+	if !reflect.DeepEqual(f.Service, []string{rf.UpdateServiceType}) ||
+		!reflect.DeepEqual(f.RfEndpointID, []string{"x1000c0s0b0", "x1000c0s1b0"}) {
+		t.Errorf("Collection: Unexpected filter %+v", f)
+	}
+}
@@ -0,0 +1,455 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	smdv1 "github.com/OpenCHAMI/smd/v2/pkg/grpc/smd/v1"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// gRPC API
+//
+// If SMD_GRPC_LISTEN (or -grpc-listen) is set, e.g. to ":27780", the
+// smd.v1.SMD service in api/proto/smd/v1/smd.proto is served there, for
+// services like PCS that read components and inventory on hot paths and
+// don't want the JSON overhead.  It has the read side of:
+//
+//     GetComponent, ListComponents            /State/Components
+//     GetComponentEndpoint,
+//     ListComponentEndpoints                  /Inventory/ComponentEndpoints
+//     GetHWInvByLoc, ListHWInvByLoc           /Inventory/Hardware
+//     WatchComponents                         /State/Components/Stream
+//
+// WatchComponents streams the same component changes as the SSE stream, and
+// resumes the same way: a client that reconnects with resume_after set to
+// the last event_id it got is sent what it missed, or a resync change first
+// if that's no longer available.
+//
+// The server uses the same TLS cert and key as the HTTP server, if they
+// exist.  With JWT auth configured, each call needs an "authorization:
+// Bearer <token>" metadata entry, and with RBAC enabled each method is
+// allowed if GET of the matching REST route would be.
+///////////////////////////////////////////////////////////////////////////////
+
+// The REST route each method reads, for RBAC.
+var grpcMethodRoutes = map[string]string{
+	smdv1.SMD_GetComponent_FullMethodName:           "/State/Components/{xname}",
+	smdv1.SMD_ListComponents_FullMethodName:         "/State/Components",
+	smdv1.SMD_GetComponentEndpoint_FullMethodName:   "/Inventory/ComponentEndpoints/{xname}",
+	smdv1.SMD_ListComponentEndpoints_FullMethodName: "/Inventory/ComponentEndpoints",
+	smdv1.SMD_GetHWInvByLoc_FullMethodName:          "/Inventory/Hardware/{xname}",
+	smdv1.SMD_ListHWInvByLoc_FullMethodName:         "/Inventory/Hardware",
+	smdv1.SMD_WatchComponents_FullMethodName:        "/State/Components/Stream",
+}
+
+type grpcServer struct {
+	smdv1.UnimplementedSMDServer
+	s *SmD
+}
+
+// Serve the gRPC API until it fails.  useTLS is whether the HTTP server's
+// cert and key were found.
+func (s *SmD) serveGRPC(useTLS bool) {
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.grpcUnaryAuth),
+		grpc.StreamInterceptor(s.grpcStreamAuth),
+	}
+	if useTLS {
+		creds, err := credentials.NewServerTLSFromFile(s.tlsCert, s.tlsKey)
+		if err != nil {
+			s.LogAlways("gRPC server error: %s", err)
+			return
+		}
+		opts = append(opts, grpc.Creds(creds))
+	} else {
+		s.LogAlways("Warning: TLS cert or key file missing, gRPC is not using TLS")
+	}
+	lis, err := net.Listen("tcp", s.grpcListen)
+	if err != nil {
+		s.LogAlways("gRPC server error: %s", err)
+		return
+	}
+	srv := grpc.NewServer(opts...)
+	smdv1.RegisterSMDServer(srv, &grpcServer{s: s})
+	s.LogAlways("Listening for gRPC connections at: %v", s.grpcListen)
+	if err := srv.Serve(lis); err != nil {
+		s.LogAlways("gRPC server error: %s", err)
+	}
+}
+
+func (s *SmD) grpcUnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.grpcAuthorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *SmD) grpcStreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.grpcAuthorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// Check the call's bearer token the way the HTTP server checks protected
+// routes, and RBAC if it's enabled.
+func (s *SmD) grpcAuthorize(ctx context.Context, method string) error {
+	if !s.IsUsingAuthentication() {
+		return nil
+	}
+	tokenStr := ""
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, val := range md.Get("authorization") {
+		if len(val) > 7 && strings.EqualFold(val[:7], "bearer ") {
+			tokenStr = strings.TrimSpace(val[7:])
+		}
+	}
+	if tokenStr == "" {
+		return status.Error(codes.Unauthenticated, "no bearer token")
+	}
+	token, err := jwtauth.VerifyToken(s.tokenAuth, tokenStr)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	claims, err := token.AsMap(ctx)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	for _, claim := range []string{"sub", "iss", "aud"} {
+		if _, ok := claims[claim]; !ok {
+			return status.Error(codes.Unauthenticated,
+				"failed to verify claim(s) from token: "+claim)
+		}
+	}
+
+	p := s.rbac.Get()
+	if !p.Enabled {
+		return nil
+	}
+	roles := p.roles(claims)
+	if p.allowed(roles, "GET", grpcMethodRoutes[method]) {
+		return nil
+	}
+	actor, _ := claims["sub"].(string)
+	if len(roles) == 0 {
+		s.Log(LOG_INFO, "RBAC: %s has no role, denied %s", actor, method)
+		return status.Error(codes.PermissionDenied, "no HSM role for "+actor)
+	}
+	s.Log(LOG_INFO, "RBAC: %s (%s) denied %s", actor, strings.Join(roles, ","),
+		method)
+	return status.Errorf(codes.PermissionDenied, "role(s) %s may not call %s",
+		strings.Join(roles, ","), method)
+}
+
+// Like sendJsonDBError: bad arguments are the caller's fault, anything else
+// is ours.
+func grpcDBError(err error) error {
+	if base.IsHMSError(err) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, "failed to query DB.")
+}
+
+func (g *grpcServer) GetComponent(ctx context.Context, req *smdv1.GetComponentRequest) (*smdv1.Component, error) {
+	comp, err := g.s.db.GetComponentByID(req.Id)
+	if err != nil {
+		g.s.LogAlways("grpc GetComponent(): Lookup failure: (%s) %s", req.Id, err)
+		return nil, grpcDBError(err)
+	}
+	if comp == nil {
+		return nil, status.Error(codes.NotFound, "no such xname.")
+	}
+	return componentToPB(comp), nil
+}
+
+func (g *grpcServer) ListComponents(ctx context.Context, req *smdv1.ListComponentsRequest) (*smdv1.ListComponentsResponse, error) {
+	f := &hmsds.ComponentFilter{
+		ID:        req.Id,
+		Type:      req.Type,
+		State:     req.State,
+		Flag:      req.Flag,
+		Role:      req.Role,
+		SubRole:   req.SubRole,
+		Class:     req.Class,
+		Arch:      req.Arch,
+		Group:     req.Group,
+		Partition: req.Partition,
+		NID:       req.Nid,
+	}
+	fieldFltr := hmsds.FLTR_DEFAULT
+	if req.StateOnly {
+		fieldFltr = hmsds.FLTR_STATEONLY
+	}
+	comps, err := g.s.db.GetComponentsFilter(f, fieldFltr)
+	if err != nil {
+		g.s.LogAlways("grpc ListComponents(): Lookup failure: %s", err)
+		return nil, grpcDBError(err)
+	}
+	rsp := &smdv1.ListComponentsResponse{
+		Components: make([]*smdv1.Component, 0, len(comps)),
+	}
+	for _, comp := range comps {
+		rsp.Components = append(rsp.Components, componentToPB(comp))
+	}
+	return rsp, nil
+}
+
+func (g *grpcServer) GetComponentEndpoint(ctx context.Context, req *smdv1.GetComponentEndpointRequest) (*smdv1.ComponentEndpoint, error) {
+	cep, err := g.s.db.GetCompEndpointByID(req.Id)
+	if err != nil {
+		g.s.LogAlways("grpc GetComponentEndpoint(): Lookup failure: (%s) %s", req.Id, err)
+		return nil, grpcDBError(err)
+	}
+	if cep == nil {
+		return nil, status.Error(codes.NotFound, "no such xname.")
+	}
+	return compEndpointToPB(cep)
+}
+
+func (g *grpcServer) ListComponentEndpoints(ctx context.Context, req *smdv1.ListComponentEndpointsRequest) (*smdv1.ListComponentEndpointsResponse, error) {
+	ceps, err := g.s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		ID:           req.Id,
+		RfEndpointID: req.RedfishEp,
+		Type:         req.Type,
+		RedfishType:  req.RedfishType,
+	})
+	if err != nil {
+		g.s.LogAlways("grpc ListComponentEndpoints(): Lookup failure: %s", err)
+		return nil, grpcDBError(err)
+	}
+	rsp := &smdv1.ListComponentEndpointsResponse{
+		ComponentEndpoints: make([]*smdv1.ComponentEndpoint, 0, len(ceps)),
+	}
+	for _, cep := range ceps {
+		pb, err := compEndpointToPB(cep)
+		if err != nil {
+			return nil, err
+		}
+		rsp.ComponentEndpoints = append(rsp.ComponentEndpoints, pb)
+	}
+	return rsp, nil
+}
+
+func (g *grpcServer) GetHWInvByLoc(ctx context.Context, req *smdv1.GetHWInvByLocRequest) (*smdv1.HWInvByLoc, error) {
+	hwloc, err := g.s.db.GetHWInvByLocID(req.Id)
+	if err != nil {
+		g.s.LogAlways("grpc GetHWInvByLoc(): Lookup failure: (%s) %s", req.Id, err)
+		return nil, grpcDBError(err)
+	}
+	if hwloc == nil {
+		return nil, status.Error(codes.NotFound, "no such xname.")
+	}
+	return hwInvByLocToPB(hwloc)
+}
+
+func (g *grpcServer) ListHWInvByLoc(ctx context.Context, req *smdv1.ListHWInvByLocRequest) (*smdv1.ListHWInvByLocResponse, error) {
+	f_opts := []hmsds.HWInvLocFiltFunc{}
+	if len(req.Id) > 0 {
+		ids := make([]string, 0, len(req.Id))
+		for _, id := range req.Id {
+			normID := xnametypes.VerifyNormalizeCompID(id)
+			if normID == "" {
+				return nil, status.Error(codes.InvalidArgument, "Invalid xname")
+			}
+			ids = append(ids, normID)
+		}
+		f_opts = append(f_opts, hmsds.HWInvLoc_IDs(ids))
+	}
+	if len(req.Type) > 0 {
+		types := make([]string, 0, len(req.Type))
+		for _, cType := range req.Type {
+			normType := xnametypes.VerifyNormalizeType(cType)
+			if normType == "" {
+				return nil, status.Error(codes.InvalidArgument, "Invalid HMS type")
+			}
+			types = append(types, normType)
+		}
+		f_opts = append(f_opts, hmsds.HWInvLoc_Types(types))
+	}
+	if len(req.Manufacturer) > 0 {
+		f_opts = append(f_opts, hmsds.HWInvLoc_Manufacturers(req.Manufacturer))
+	}
+	if len(req.PartNumber) > 0 {
+		f_opts = append(f_opts, hmsds.HWInvLoc_PartNumbers(req.PartNumber))
+	}
+	if len(req.SerialNumber) > 0 {
+		f_opts = append(f_opts, hmsds.HWInvLoc_SerialNumbers(req.SerialNumber))
+	}
+	if len(req.FruId) > 0 {
+		f_opts = append(f_opts, hmsds.HWInvLoc_FruIDs(req.FruId))
+	}
+	hwlocs, err := g.s.db.GetHWInvByLocFilter(f_opts...)
+	if err != nil {
+		g.s.LogAlways("grpc ListHWInvByLoc(): Lookup failure: %s", err)
+		return nil, grpcDBError(err)
+	}
+	rsp := &smdv1.ListHWInvByLocResponse{
+		Locations: make([]*smdv1.HWInvByLoc, 0, len(hwlocs)),
+	}
+	for _, hwloc := range hwlocs {
+		pb, err := hwInvByLocToPB(hwloc)
+		if err != nil {
+			return nil, err
+		}
+		rsp.Locations = append(rsp.Locations, pb)
+	}
+	return rsp, nil
+}
+
+// Stream component changes until the client goes away or falls behind.
+func (g *grpcServer) WatchComponents(req *smdv1.WatchComponentsRequest, stream smdv1.SMD_WatchComponentsServer) error {
+	cs := &g.s.compStream
+	missed, resyncID, ch := cs.subscribe(req.ResumeAfter)
+	defer cs.unsubscribe(ch)
+
+	if resyncID != "" {
+		err := stream.Send(&smdv1.ComponentChange{EventId: resyncID, Resync: true})
+		if err != nil {
+			return err
+		}
+	}
+	for _, ev := range missed {
+		if err := stream.Send(compChangeToPB(cs.eventID(ev), ev.payload)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				g.s.lg.Printf("grpc WatchComponents(): Dropped slow client")
+				return status.Error(codes.ResourceExhausted,
+					"client fell behind, resume after the last event_id")
+			}
+			if err := stream.Send(compChangeToPB(cs.eventID(ev), ev.payload)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func componentToPB(comp *base.Component) *smdv1.Component {
+	pb := &smdv1.Component{
+		Id:                  comp.ID,
+		Type:                comp.Type,
+		State:               comp.State,
+		Flag:                comp.Flag,
+		Enabled:             comp.Enabled,
+		SoftwareStatus:      comp.SwStatus,
+		Role:                comp.Role,
+		SubRole:             comp.SubRole,
+		Subtype:             comp.Subtype,
+		NetType:             comp.NetType,
+		Arch:                comp.Arch,
+		Class:               comp.Class,
+		ReservationDisabled: comp.ReservationDisabled,
+		Locked:              comp.Locked,
+	}
+	if nid, err := comp.NID.Int64(); err == nil {
+		pb.Nid = &nid
+	}
+	return pb
+}
+
+func compEndpointToPB(cep *sm.ComponentEndpoint) (*smdv1.ComponentEndpoint, error) {
+	info, err := cep.EncodeComponentInfo()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &smdv1.ComponentEndpoint{
+		Id:                    cep.ID,
+		Type:                  cep.Type,
+		Domain:                cep.Domain,
+		Fqdn:                  cep.FQDN,
+		RedfishType:           cep.RedfishType,
+		RedfishSubtype:        cep.RedfishSubtype,
+		MacAddr:               cep.MACAddr,
+		Uuid:                  cep.UUID,
+		OdataId:               cep.OdataID,
+		RedfishEndpointId:     cep.RfEndpointID,
+		Enabled:               cep.Enabled,
+		RedfishEndpointFqdn:   cep.RfEndpointFQDN,
+		RedfishUrl:            cep.URL,
+		ComponentEndpointType: cep.ComponentEndpointType,
+		Info:                  info,
+	}, nil
+}
+
+func hwInvByLocToPB(hwloc *sm.HWInvByLoc) (*smdv1.HWInvByLoc, error) {
+	locInfo, err := hwloc.EncodeLocationInfo()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	pb := &smdv1.HWInvByLoc{
+		Id:                        hwloc.ID,
+		Type:                      hwloc.Type,
+		Ordinal:                   int32(hwloc.Ordinal),
+		Status:                    hwloc.Status,
+		HwInventoryByLocationType: hwloc.HWInventoryByLocationType,
+		LocationInfo:              locInfo,
+	}
+	if fru := hwloc.PopulatedFRU; fru != nil {
+		fruInfo, err := fru.EncodeFRUInfo()
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		pb.PopulatedFru = &smdv1.HWInvByFRU{
+			FruId:                fru.FRUID,
+			Type:                 fru.Type,
+			Subtype:              fru.Subtype,
+			HwInventoryByFruType: fru.HWInventoryByFRUType,
+			FruInfo:              fruInfo,
+		}
+	}
+	return pb, nil
+}
+
+func compChangeToPB(id string, p sm.SCNPayload) *smdv1.ComponentChange {
+	return &smdv1.ComponentChange{
+		EventId:        id,
+		Components:     p.Components,
+		Enabled:        p.Enabled,
+		Flag:           p.Flag,
+		Role:           p.Role,
+		SubRole:        p.SubRole,
+		SoftwareStatus: p.SoftwareStatus,
+		State:          p.State,
+		Reservation:    p.Reservation,
+	}
+}
@@ -0,0 +1,285 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	smdv1 "github.com/OpenCHAMI/smd/v2/pkg/grpc/smd/v1"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// A client for the gRPC API served over an in-memory connection.
+func newTestGRPCClient(t *testing.T) smdv1.SMDClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(s.grpcUnaryAuth),
+		grpc.StreamInterceptor(s.grpcStreamAuth),
+	)
+	smdv1.RegisterSMDServer(srv, &grpcServer{s: s})
+	go srv.Serve(lis)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+	return smdv1.NewSMDClient(conn)
+}
+
+func TestGRPCGetComponent(t *testing.T) {
+	defer func() {
+		results.GetComponentByID.Return.id = nil
+		results.GetComponentByID.Return.err = nil
+	}()
+	client := newTestGRPCClient(t)
+	enabled := true
+	tests := []struct {
+		comp    *base.Component
+		err     error
+		expCode codes.Code
+		expComp *smdv1.Component
+	}{{
+		&base.Component{ID: "x0c0s0b0n0", Type: "Node", State: "Ready",
+			Flag: "OK", Enabled: &enabled, NID: json.Number("1"), Role: "Compute"},
+		nil,
+		codes.OK,
+		&smdv1.Component{Id: "x0c0s0b0n0", Type: "Node", State: "Ready",
+			Flag: "OK", Enabled: &enabled, Nid: new(int64), Role: "Compute"},
+	}, {
+		nil,
+		nil,
+		codes.NotFound,
+		nil,
+	}, {
+		nil,
+		hmsds.ErrHMSDSArgBadID,
+		codes.InvalidArgument,
+		nil,
+	}}
+	*tests[0].expComp.Nid = 1
+	for i, test := range tests {
+		results.GetComponentByID.Return.id = test.comp
+		results.GetComponentByID.Return.err = test.err
+		comp, err := client.GetComponent(context.Background(),
+			&smdv1.GetComponentRequest{Id: "x0c0s0b0n0"})
+		if status.Code(err) != test.expCode {
+			t.Errorf("Test %v Failed: Expected code %v, got %v", i, test.expCode, err)
+			continue
+		}
+		if results.GetComponentByID.Input.id != "x0c0s0b0n0" {
+			t.Errorf("Test %v Failed: Expected id x0c0s0b0n0, got %s",
+				i, results.GetComponentByID.Input.id)
+		}
+		if test.expComp != nil && (comp.Id != test.expComp.Id ||
+			comp.State != test.expComp.State || comp.Flag != test.expComp.Flag ||
+			comp.Role != test.expComp.Role || comp.GetEnabled() != true ||
+			comp.Nid == nil || *comp.Nid != *test.expComp.Nid) {
+			t.Errorf("Test %v Failed: Expected %v, got %v", i, test.expComp, comp)
+		}
+	}
+}
+
+func TestGRPCListComponents(t *testing.T) {
+	defer func() {
+		results.GetComponentsFilter.Return.ids = nil
+	}()
+	client := newTestGRPCClient(t)
+	results.GetComponentsFilter.Return.ids = []*base.Component{
+		{ID: "x0c0s0b0n0", Type: "Node", State: "Ready", Flag: "OK"},
+		{ID: "x0c0s0b0n1", Type: "Node", State: "Off", Flag: "OK"},
+	}
+	results.GetComponentsFilter.Return.err = nil
+	rsp, err := client.ListComponents(context.Background(),
+		&smdv1.ListComponentsRequest{Type: []string{"Node"}, Role: []string{"Compute"},
+			StateOnly: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(rsp.Components) != 2 || rsp.Components[1].Id != "x0c0s0b0n1" ||
+		rsp.Components[1].Nid != nil {
+		t.Errorf("Unexpected components: %v", rsp.Components)
+	}
+	f := results.GetComponentsFilter.Input.compFilter
+	if !reflect.DeepEqual(f.Type, []string{"Node"}) ||
+		!reflect.DeepEqual(f.Role, []string{"Compute"}) {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+	if results.GetComponentsFilter.Input.fieldFilter != hmsds.FLTR_STATEONLY {
+		t.Errorf("Expected FLTR_STATEONLY, got %v",
+			results.GetComponentsFilter.Input.fieldFilter)
+	}
+}
+
+func TestGRPCListHWInvByLoc(t *testing.T) {
+	defer func() {
+		results.GetHWInvByLocFilter.Return.hwlocs = nil
+	}()
+	client := newTestGRPCClient(t)
+	results.GetHWInvByLocFilter.Return.hwlocs = []*sm.HWInvByLoc{{
+		ID:                        "x0c0s0b0n0",
+		Type:                      "Node",
+		Status:                    "Populated",
+		HWInventoryByLocationType: sm.HWInvByLocNode,
+		PopulatedFRU: &sm.HWInvByFRU{
+			FRUID:                "Node.Cray.123",
+			Type:                 "Node",
+			HWInventoryByFRUType: sm.HWInvByFRUNode,
+		},
+	}}
+	results.GetHWInvByLocFilter.Return.err = nil
+
+	_, err := client.ListHWInvByLoc(context.Background(),
+		&smdv1.ListHWInvByLocRequest{Type: []string{"NotAType"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a bad type, got %v", err)
+	}
+
+	rsp, err := client.ListHWInvByLoc(context.Background(),
+		&smdv1.ListHWInvByLocRequest{Id: []string{"x0c0s0b0n0"}, Type: []string{"node"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(rsp.Locations) != 1 || rsp.Locations[0].PopulatedFru == nil ||
+		rsp.Locations[0].PopulatedFru.FruId != "Node.Cray.123" {
+		t.Errorf("Unexpected locations: %v", rsp.Locations)
+	}
+	f := results.GetHWInvByLocFilter.Input.f
+	if !reflect.DeepEqual(f.ID, []string{"x0c0s0b0n0"}) ||
+		!reflect.DeepEqual(f.Type, []string{"Node"}) {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+}
+
+func TestGRPCWatchComponents(t *testing.T) {
+	defer func() {
+		s.compStream = CompStream{}
+	}()
+	s.compStream = CompStream{}
+	s.compStream.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, State: "Ready"})
+	id1 := s.compStream.streamID + "-1"
+
+	client := newTestGRPCClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Resuming from before the first event gets it again.
+	stream, err := client.WatchComponents(ctx,
+		&smdv1.WatchComponentsRequest{ResumeAfter: s.compStream.streamID + "-0"})
+	if err != nil {
+		t.Fatalf("WatchComponents: %s", err)
+	}
+	chg, err := stream.Recv()
+	if err != nil || chg.EventId != id1 || chg.State != "Ready" || chg.Resync {
+		t.Fatalf("Expected missed event %s, got %v (%v)", id1, chg, err)
+	}
+	// The stream subscribed before sending what was missed.
+	s.compStream.publish(sm.SCNPayload{Components: []string{"x0c0s0b0n0"}, Flag: "Alert"})
+	chg, err = stream.Recv()
+	if err != nil || chg.EventId != s.compStream.streamID+"-2" || chg.Flag != "Alert" {
+		t.Errorf("Expected new event, got %v (%v)", chg, err)
+	}
+
+	// An unknown ID gets a resync first.
+	stream, err = client.WatchComponents(ctx,
+		&smdv1.WatchComponentsRequest{ResumeAfter: "other-5"})
+	if err != nil {
+		t.Fatalf("WatchComponents: %s", err)
+	}
+	chg, err = stream.Recv()
+	if err != nil || !chg.Resync {
+		t.Errorf("Expected a resync, got %v (%v)", chg, err)
+	}
+}
+
+func TestGRPCAuthorize(t *testing.T) {
+	defer func() {
+		s.jwksURL = ""
+		s.tokenAuth = nil
+		s.rbac = RBAC{}
+	}()
+	ja := jwtauth.New("HS256", []byte("0123456789abcdef0123456789abcdef"), nil)
+	s.jwksURL = "https://localhost/.well-known/jwks.json"
+	s.tokenAuth = ja
+	token := func(claims map[string]interface{}) string {
+		_, tokenStr, err := ja.Encode(claims)
+		if err != nil {
+			t.Fatalf("Encode: %s", err)
+		}
+		return "Bearer " + tokenStr
+	}
+	full := map[string]interface{}{"sub": "pcs", "iss": "test", "aud": "smd",
+		"roles": "read-only"}
+
+	tests := []struct {
+		rbac    bool
+		auth    string
+		method  string
+		expCode codes.Code
+	}{
+		{false, "", smdv1.SMD_GetComponent_FullMethodName, codes.Unauthenticated},
+		{false, "Bearer garbage", smdv1.SMD_GetComponent_FullMethodName, codes.Unauthenticated},
+		{false, token(map[string]interface{}{"sub": "pcs"}),
+			smdv1.SMD_GetComponent_FullMethodName, codes.Unauthenticated},
+		{false, token(full), smdv1.SMD_WatchComponents_FullMethodName, codes.OK},
+		{true, token(full), smdv1.SMD_ListHWInvByLoc_FullMethodName, codes.OK},
+		{true, token(map[string]interface{}{"sub": "pcs", "iss": "test", "aud": "smd"}),
+			smdv1.SMD_ListComponents_FullMethodName, codes.PermissionDenied},
+	}
+	for i, test := range tests {
+		p := DefaultRBACPolicy()
+		p.Enabled = test.rbac
+		if err := s.rbac.Set(p); err != nil {
+			t.Fatalf("Set: %s", err)
+		}
+		ctx := context.Background()
+		if test.auth != "" {
+			ctx = metadata.NewIncomingContext(ctx,
+				metadata.Pairs("authorization", test.auth))
+		}
+		err := s.grpcAuthorize(ctx, test.method)
+		if status.Code(err) != test.expCode {
+			t.Errorf("Test %v Failed: Expected code %v, got %v", i, test.expCode, err)
+		}
+	}
+}
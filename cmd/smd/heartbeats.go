@@ -0,0 +1,284 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Component heartbeats
+//
+// Heartbeat daemons report the nodes they hear from in bulk with
+//
+//     POST /State/Components/Heartbeats
+//     {"Heartbeats":[{"ID":"x0c0s0b0n0"},{"ID":"x0c0s1b0n0","Status":"Halt"}]}
+//
+// and HSM works out the state changes itself, so the daemon doesn't have to
+// track each node's state and push every change:
+//
+//     heartbeat                        -> Ready/OK
+//     heartbeat with Status Halt       -> Halt, the OS shut down or panicked
+//     none for SMD_HEARTBEAT_WARN_SECS    -> Ready/Warning (default 10)
+//     none for SMD_HEARTBEAT_STANDBY_SECS -> Standby/Alert (default 30)
+//
+// Only components that have heartbeated at least once and are Ready are
+// checked for late heartbeats, by the leader, every heartbeatCheckInterval.
+// A threshold of 0 disables that transition.  The changes are made like any
+// other state update, so they get SCNs, only happen from valid starting
+// states and are in the component state history.  Going to Standby starts
+// the usual Redfish polling to see whether the node is still on.  Without
+// HBTD, rediscovery keeps nodes that are still heartbeating Ready.
+//
+// GET /State/Components/Heartbeats lists the last heartbeat of each
+// component that has reported any, optionally only those at least ?age=N
+// seconds old.
+///////////////////////////////////////////////////////////////////////////////
+
+// Default time without a heartbeat before a Ready component gets a Warning
+// flag, and before it goes to Standby.
+const (
+	DefaultHeartbeatWarn    = 10 * time.Second
+	DefaultHeartbeatStandby = 30 * time.Second
+)
+
+// How often the leader looks for late heartbeats.
+const heartbeatCheckInterval = 2 * time.Second
+
+// Spin off a thread to periodically change the state of components whose
+// heartbeats are late.
+func (s *SmD) StartHeartbeatChecker() {
+	if s.hbWarn <= 0 && s.hbStandby <= 0 {
+		return
+	}
+	go func() {
+		for {
+			time.Sleep(heartbeatCheckInterval)
+			if s.cluster.isLeader() {
+				s.checkHeartbeats()
+			}
+		}
+	}()
+}
+
+// Move Ready components whose last heartbeat is older than s.hbStandby to
+// Standby and flag those older than s.hbWarn with a Warning.
+func (s *SmD) checkHeartbeats() {
+	minAge := s.hbWarn
+	if minAge <= 0 || (s.hbStandby > 0 && s.hbStandby < minAge) {
+		minAge = s.hbStandby
+	}
+	hbs, err := s.db.GetCompHeartbeats(&hmsds.CompHeartbeatFilter{
+		State: []string{base.StateReady.String()},
+		Age:   minAge,
+	})
+	if err != nil {
+		s.LogAlways("checkHeartbeats(): Lookup failure: %s", err)
+		return
+	}
+	standbyIDs := []string{}
+	warnIDs := []string{}
+	for _, hb := range hbs {
+		age := time.Duration(hb.Age * float64(time.Second))
+		if s.hbStandby > 0 && age >= s.hbStandby {
+			standbyIDs = append(standbyIDs, hb.ID)
+		} else if s.hbWarn > 0 && age >= s.hbWarn &&
+			hb.Flag == base.FlagOK.String() {
+			warnIDs = append(warnIDs, hb.ID)
+		}
+	}
+	s.heartbeatStateUpdate(standbyIDs, base.StateStandby, base.FlagAlert,
+		CompHistActorHeartbeat)
+	s.heartbeatStateUpdate(warnIDs, base.StateReady, base.FlagWarning,
+		CompHistActorHeartbeat)
+}
+
+// Set the state and flag of the given components, if any, on behalf of
+// actor.
+func (s *SmD) heartbeatStateUpdate(ids []string, state base.HMSState, flag base.HMSFlag, actor string) {
+	if len(ids) == 0 {
+		return
+	}
+	update := &CompUpdate{
+		ComponentIDs: ids,
+		UpdateType:   StateDataUpdate.String(),
+		State:        state.String(),
+		Flag:         flag.String(),
+		actor:        actor,
+	}
+	if err := s.doCompUpdate(update, "heartbeatStateUpdate"); err != nil {
+		s.LogAlways("heartbeatStateUpdate(): Could not set %d components "+
+			"to %s/%s: %s", len(ids), state, flag, err)
+	} else {
+		s.Log(LOG_INFO, "Heartbeats: set %d components to %s/%s", len(ids),
+			state, flag)
+	}
+}
+
+// Set the discovered components in compMap that have heartbeated recently
+// to Ready, so rediscovering them doesn't take them out of Ready.  Recently
+// is before they would get a Warning, or be set to Standby if there are no
+// warnings.
+func (s *SmD) setReadyIfHeartbeating(compMap map[string]*base.Component, ids []string) {
+	fresh := s.hbWarn
+	if fresh <= 0 {
+		fresh = s.hbStandby
+	}
+	if fresh <= 0 {
+		return
+	}
+	hbs, err := s.db.GetCompHeartbeats(&hmsds.CompHeartbeatFilter{ID: ids})
+	if err != nil {
+		s.LogAlways("setReadyIfHeartbeating(): Lookup failure: %s", err)
+		return
+	}
+	for _, hb := range hbs {
+		comp, ok := compMap[hb.ID]
+		if ok && time.Duration(hb.Age*float64(time.Second)) < fresh {
+			comp.State = base.StateReady.String()
+		}
+	}
+}
+
+// Record heartbeats from many components and update their states.
+func (s *SmD) doCompHeartbeatsPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body: "+err.Error())
+		return
+	}
+	hba := new(sm.HeartbeatArray)
+	if err := json.Unmarshal(body, hba); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if len(hba.Heartbeats) == 0 {
+		sendJsonError(w, http.StatusBadRequest, "no Heartbeats given")
+		return
+	}
+	// The status of each component, the last one given if more than one.
+	status := make(map[string]string, len(hba.Heartbeats))
+	ids := make([]string, 0, len(hba.Heartbeats))
+	for _, hb := range hba.Heartbeats {
+		id := xnametypes.VerifyNormalizeCompID(hb.ID)
+		if id == "" {
+			sendJsonError(w, http.StatusBadRequest,
+				"invalid xname ID '"+hb.ID+"'")
+			return
+		}
+		switch strings.ToLower(hb.Status) {
+		case "", strings.ToLower(sm.HeartbeatOK):
+			hb.Status = sm.HeartbeatOK
+		case strings.ToLower(sm.HeartbeatHalt):
+			hb.Status = sm.HeartbeatHalt
+		default:
+			sendJsonError(w, http.StatusBadRequest,
+				"invalid Status '"+hb.Status+"' for "+id+
+					", must be "+sm.HeartbeatOK+" or "+sm.HeartbeatHalt)
+			return
+		}
+		if _, ok := status[id]; !ok {
+			ids = append(ids, id)
+		}
+		status[id] = hb.Status
+	}
+
+	hbs, err := s.db.UpdateCompHeartbeats(ids)
+	if err != nil {
+		s.reqLog(r).LogAlways("doCompHeartbeatsPost(): Update failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	readyIDs := []string{}
+	haltIDs := []string{}
+	known := make(map[string]bool, len(hbs))
+	for _, hb := range hbs {
+		known[hb.ID] = true
+		if status[hb.ID] == sm.HeartbeatHalt {
+			if hb.State != base.StateHalt.String() {
+				haltIDs = append(haltIDs, hb.ID)
+			}
+		} else if hb.State != base.StateReady.String() ||
+			hb.Flag != base.FlagOK.String() {
+			readyIDs = append(readyIDs, hb.ID)
+		}
+	}
+	actor := s.requestActor(r)
+	s.heartbeatStateUpdate(readyIDs, base.StateReady, base.FlagOK, actor)
+	s.heartbeatStateUpdate(haltIDs, base.StateHalt, base.FlagOK, actor)
+
+	result := sm.HeartbeatResult{Accepted: len(hbs), Unknown: []string{}}
+	for _, id := range ids {
+		if !known[id] {
+			result.Unknown = append(result.Unknown, id)
+		}
+	}
+	sendJsonObject(w, http.StatusOK, result)
+}
+
+// Get the last heartbeat of each component that has reported any, optionally
+// only for ?id=... or those at least ?age=N seconds old.
+func (s *SmD) doCompHeartbeatsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doCompHeartbeatsGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	f := new(hmsds.CompHeartbeatFilter)
+	for _, id := range r.Form["id"] {
+		f.ID = append(f.ID, xnametypes.NormalizeHMSCompID(id))
+	}
+	if val := r.Form.Get("age"); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			sendJsonError(w, http.StatusBadRequest,
+				"bad query param: age must be 0+ seconds")
+			return
+		}
+		f.Age = time.Duration(secs) * time.Second
+	}
+	hbs, err := s.db.GetCompHeartbeats(f)
+	if err != nil {
+		s.reqLog(r).LogAlways("doCompHeartbeatsGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, sm.CompHeartbeatArray{Heartbeats: hbs})
+}
@@ -0,0 +1,308 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func resetHeartbeatResults() {
+	results.UpdateCompHeartbeats.Input.ids = nil
+	results.UpdateCompHeartbeats.Return.hbs = nil
+	results.UpdateCompHeartbeats.Return.err = nil
+	results.GetCompHeartbeats.Input.f = nil
+	results.GetCompHeartbeats.Return.hbs = nil
+	results.GetCompHeartbeats.Return.err = nil
+	results.UpdateCompStates.Input.ids = nil
+	results.UpdateCompStates.Input.state = ""
+	results.UpdateCompStates.Input.flag = ""
+	results.UpdateCompStates.Return.affectedIds = nil
+	results.UpdateCompStates.Return.err = nil
+	results.WithActor.Input.actor = ""
+}
+
+func TestDoCompHeartbeatsPost(t *testing.T) {
+	defer resetHeartbeatResults()
+	tests := []struct {
+		reqBody       string
+		hbs           []*sm.CompHeartbeat
+		code          int
+		expectedIDs   []string
+		expectedState string
+		expectedFlag  string
+		expectedSet   []string
+		expectedRes   sm.HeartbeatResult
+	}{{
+		// Only components not already Ready/OK change
+		reqBody: `{"Heartbeats":[{"ID":"x0c0s0b0n0"},{"ID":"X0C0S1B0N0","Status":"ok"},{"ID":"x0c0s2b0n0"},{"ID":"x0c0s9b0n0"}]}`,
+		hbs: []*sm.CompHeartbeat{
+			{ID: "x0c0s0b0n0", State: "Ready", Flag: "OK"},
+			{ID: "x0c0s1b0n0", State: "Ready", Flag: "Warning"},
+			{ID: "x0c0s2b0n0", State: "Standby", Flag: "Alert"},
+		},
+		code:          http.StatusOK,
+		expectedIDs:   []string{"x0c0s0b0n0", "x0c0s1b0n0", "x0c0s2b0n0", "x0c0s9b0n0"},
+		expectedState: "Ready",
+		expectedFlag:  "OK",
+		expectedSet:   []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+		expectedRes: sm.HeartbeatResult{
+			Accepted: 3,
+			Unknown:  []string{"x0c0s9b0n0"},
+		},
+	}, {
+		// The last heartbeat for a component wins
+		reqBody: `{"Heartbeats":[{"ID":"x0c0s0b0n0"},{"ID":"x0c0s0b0n0","Status":"Halt"}]}`,
+		hbs: []*sm.CompHeartbeat{
+			{ID: "x0c0s0b0n0", State: "Ready", Flag: "OK"},
+		},
+		code:          http.StatusOK,
+		expectedIDs:   []string{"x0c0s0b0n0"},
+		expectedState: "Halt",
+		expectedFlag:  "OK",
+		expectedSet:   []string{"x0c0s0b0n0"},
+		expectedRes:   sm.HeartbeatResult{Accepted: 1, Unknown: []string{}},
+	}, {
+		reqBody: `{"Heartbeats":[]}`,
+		code:    http.StatusBadRequest,
+	}, {
+		reqBody: `{"Heartbeats":[{"ID":"foo"}]}`,
+		code:    http.StatusBadRequest,
+	}, {
+		reqBody: `{"Heartbeats":[{"ID":"x0c0s0b0n0","Status":"Panic"}]}`,
+		code:    http.StatusBadRequest,
+	}, {
+		reqBody: `{"Heartbeats":`,
+		code:    http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		resetHeartbeatResults()
+		results.UpdateCompHeartbeats.Return.hbs = test.hbs
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/State/Components/Heartbeats",
+			bytes.NewBufferString(test.reqBody))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			if results.UpdateCompHeartbeats.Input.ids != nil {
+				t.Errorf("Test %d: unexpected heartbeat update", i)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(test.expectedIDs, results.UpdateCompHeartbeats.Input.ids) {
+			t.Errorf("Test %d: expected heartbeats for %v, got %v", i,
+				test.expectedIDs, results.UpdateCompHeartbeats.Input.ids)
+		}
+		if !reflect.DeepEqual(test.expectedSet, results.UpdateCompStates.Input.ids) ||
+			results.UpdateCompStates.Input.state != test.expectedState ||
+			results.UpdateCompStates.Input.flag != test.expectedFlag {
+			t.Errorf("Test %d: expected %v set to %s/%s, got %v %s/%s", i,
+				test.expectedSet, test.expectedState, test.expectedFlag,
+				results.UpdateCompStates.Input.ids,
+				results.UpdateCompStates.Input.state,
+				results.UpdateCompStates.Input.flag)
+		}
+		var res sm.HeartbeatResult
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(test.expectedRes, res) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expectedRes, res)
+		}
+	}
+}
+
+func TestDoCompHeartbeatsGet(t *testing.T) {
+	defer resetHeartbeatResults()
+	resetHeartbeatResults()
+	results.GetCompHeartbeats.Return.hbs = []*sm.CompHeartbeat{{
+		ID:       "x0c0s0b0n0",
+		State:    "Ready",
+		Flag:     "OK",
+		LastSeen: "2026-10-17T12:00:00Z",
+		Age:      42,
+	}}
+	tests := []struct {
+		url      string
+		code     int
+		expected *hmsds.CompHeartbeatFilter
+	}{{
+		url:      "https://localhost/hsm/v2/State/Components/Heartbeats",
+		code:     http.StatusOK,
+		expected: &hmsds.CompHeartbeatFilter{},
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/Heartbeats?id=x0c0s0b0n0&id=X0C0S01B0N0&age=30",
+		code: http.StatusOK,
+		expected: &hmsds.CompHeartbeatFilter{
+			ID:  []string{"x0c0s0b0n0", "x0c0s1b0n0"},
+			Age: 30 * time.Second,
+		},
+	}, {
+		url:  "https://localhost/hsm/v2/State/Components/Heartbeats?age=-1",
+		code: http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		results.GetCompHeartbeats.Input.f = nil
+		req, _ := http.NewRequest("GET", test.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+		if !reflect.DeepEqual(test.expected, results.GetCompHeartbeats.Input.f) {
+			t.Errorf("Test %d: expected filter %+v, got %+v", i, test.expected,
+				results.GetCompHeartbeats.Input.f)
+		}
+		var hba sm.CompHeartbeatArray
+		if err := json.Unmarshal(w.Body.Bytes(), &hba); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(results.GetCompHeartbeats.Return.hbs, hba.Heartbeats) {
+			t.Errorf("Test %d: unexpected heartbeats %+v", i, hba.Heartbeats)
+		}
+	}
+}
+
+func TestCheckHeartbeats(t *testing.T) {
+	defer resetHeartbeatResults()
+	savedWarn, savedStandby := s.hbWarn, s.hbStandby
+	defer func() { s.hbWarn, s.hbStandby = savedWarn, savedStandby }()
+
+	tests := []struct {
+		warn          time.Duration
+		standby       time.Duration
+		hbs           []*sm.CompHeartbeat
+		expectedAge   time.Duration
+		expectedIDs   []string
+		expectedState string
+		expectedFlag  string
+	}{{
+		// Warnings are done last, the mock only keeps the last update
+		warn:    10 * time.Second,
+		standby: 30 * time.Second,
+		hbs: []*sm.CompHeartbeat{
+			{ID: "x0c0s0b0n0", State: "Ready", Flag: "OK", Age: 45},
+			{ID: "x0c0s1b0n0", State: "Ready", Flag: "OK", Age: 12.5},
+			{ID: "x0c0s2b0n0", State: "Ready", Flag: "Warning", Age: 20},
+		},
+		expectedAge:   10 * time.Second,
+		expectedIDs:   []string{"x0c0s1b0n0"},
+		expectedState: "Ready",
+		expectedFlag:  "Warning",
+	}, {
+		// No warnings
+		warn:    0,
+		standby: 30 * time.Second,
+		hbs: []*sm.CompHeartbeat{
+			{ID: "x0c0s0b0n0", State: "Ready", Flag: "OK", Age: 45},
+			{ID: "x0c0s2b0n0", State: "Ready", Flag: "Warning", Age: 30},
+		},
+		expectedAge:   30 * time.Second,
+		expectedIDs:   []string{"x0c0s0b0n0", "x0c0s2b0n0"},
+		expectedState: "Standby",
+		expectedFlag:  "Alert",
+	}, {
+		// Nothing late
+		warn:        10 * time.Second,
+		standby:     30 * time.Second,
+		expectedAge: 10 * time.Second,
+	}}
+	for i, test := range tests {
+		resetHeartbeatResults()
+		s.hbWarn, s.hbStandby = test.warn, test.standby
+		results.GetCompHeartbeats.Return.hbs = test.hbs
+		s.checkHeartbeats()
+
+		f := results.GetCompHeartbeats.Input.f
+		if f == nil || f.Age != test.expectedAge ||
+			!reflect.DeepEqual(f.State, []string{"Ready"}) {
+			t.Errorf("Test %d: expected Ready older than %s, got %+v", i,
+				test.expectedAge, f)
+		}
+		if !reflect.DeepEqual(test.expectedIDs, results.UpdateCompStates.Input.ids) ||
+			results.UpdateCompStates.Input.state != test.expectedState ||
+			results.UpdateCompStates.Input.flag != test.expectedFlag {
+			t.Errorf("Test %d: expected %v set to %s/%s, got %v %s/%s", i,
+				test.expectedIDs, test.expectedState, test.expectedFlag,
+				results.UpdateCompStates.Input.ids,
+				results.UpdateCompStates.Input.state,
+				results.UpdateCompStates.Input.flag)
+		}
+		if test.expectedIDs != nil &&
+			results.WithActor.Input.actor != CompHistActorHeartbeat {
+			t.Errorf("Test %d: expected actor %s, got '%s'", i,
+				CompHistActorHeartbeat, results.WithActor.Input.actor)
+		}
+	}
+}
+
+func TestSetReadyIfHeartbeating(t *testing.T) {
+	defer resetHeartbeatResults()
+	savedWarn, savedStandby := s.hbWarn, s.hbStandby
+	defer func() { s.hbWarn, s.hbStandby = savedWarn, savedStandby }()
+	s.hbWarn, s.hbStandby = 10*time.Second, 30*time.Second
+
+	resetHeartbeatResults()
+	results.GetCompHeartbeats.Return.hbs = []*sm.CompHeartbeat{
+		{ID: "x0c0s0b0n0", State: "Ready", Flag: "OK", Age: 3},
+		{ID: "x0c0s1b0n0", State: "Ready", Flag: "Warning", Age: 12},
+	}
+	compMap := map[string]*base.Component{
+		"x0c0s0b0n0": {ID: "x0c0s0b0n0", State: "On"},
+		"x0c0s1b0n0": {ID: "x0c0s1b0n0", State: "On"},
+		"x0c0s2b0n0": {ID: "x0c0s2b0n0", State: "On"},
+	}
+	ids := []string{"x0c0s0b0n0", "x0c0s1b0n0", "x0c0s2b0n0"}
+	s.setReadyIfHeartbeating(compMap, ids)
+
+	if f := results.GetCompHeartbeats.Input.f; f == nil || !reflect.DeepEqual(ids, f.ID) {
+		t.Errorf("Expected lookup of %v, got %+v", ids, f)
+	}
+	expected := map[string]string{
+		"x0c0s0b0n0": "Ready",
+		"x0c0s1b0n0": "On",
+		"x0c0s2b0n0": "On",
+	}
+	for id, state := range expected {
+		if compMap[id].State != state {
+			t.Errorf("Expected %s to be %s, got %s", id, state, compMap[id].State)
+		}
+	}
+}
@@ -23,8 +23,11 @@
 package main
 
 import (
+	"database/sql"
 	"log"
+	"time"
 
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 	base "github.com/Cray-HPE/hms-base/v2"
@@ -108,6 +111,39 @@ type TestResults struct {
 			err error
 		}
 	}
+	GetComponentsAsOf struct {
+		Input struct {
+			compFilter  hmsds.ComponentFilter
+			fieldFilter hmsds.FieldFilter
+			asOf        string
+		}
+		Return struct {
+			ids []*base.Component
+			err error
+		}
+	}
+	GetCompStateHist struct {
+		Input struct {
+			id string
+			f  *hmsds.CompStateHistFilter
+		}
+		Return struct {
+			hist []*sm.CompStateHistEntry
+			err  error
+		}
+	}
+	GetComponentCounts struct {
+		Return struct {
+			counts []*hmsds.ComponentCount
+			err    error
+		}
+	}
+	GetCollectionVersions struct {
+		Return struct {
+			vers *hmsds.CollectionVersions
+			err  error
+		}
+	}
 	GetComponentByNID struct {
 		Input struct {
 			nid string
@@ -145,6 +181,26 @@ type TestResults struct {
 			err       error
 		}
 	}
+	UpdateComponentIfUnchanged struct {
+		Input struct {
+			prev *base.Component
+			c    *base.Component
+		}
+		Return struct {
+			ok  bool
+			err error
+		}
+	}
+	BulkPatchComponents struct {
+		Input struct {
+			f *hmsds.ComponentFilter
+			p *hmsds.CompBulkPatch
+		}
+		Return struct {
+			changes map[string]map[string]bool
+			err     error
+		}
+	}
 	UpdateCompStates struct {
 		Input struct {
 			ids   []string
@@ -394,6 +450,15 @@ type TestResults struct {
 			err    error
 		}
 	}
+	ForEachHWInvByLocFilter struct {
+		Input struct {
+			f *hmsds.HWInvLocFilter
+		}
+		Return struct {
+			hwlocs []*sm.HWInvByLoc
+			err    error
+		}
+	}
 	GetHWInvByLocID struct {
 		Input struct {
 			id string
@@ -488,6 +553,15 @@ type TestResults struct {
 		}
 	}
 	// HWInv History
+	LocateHWInvByFRU struct {
+		Input struct {
+			f *hmsds.HWInvLocFilter
+		}
+		Return struct {
+			hwfrus []*sm.HWInvByFRULocation
+			err    error
+		}
+	}
 	GetHWInvHistFilter struct {
 		Input struct {
 			f *hmsds.HWInvHistFilter
@@ -522,6 +596,23 @@ type TestResults struct {
 			err error
 		}
 	}
+	GetHWInvSightingsFilter struct {
+		Input struct {
+			f *hmsds.HWInvHistFilter
+		}
+		Return struct {
+			sightings []*sm.HWInvSighting
+			err       error
+		}
+	}
+	RecordHWInvSightings struct {
+		Input struct {
+			hwlocs []*sm.HWInvByLoc
+		}
+		Return struct {
+			err error
+		}
+	}
 	DeleteHWInvHistByLocID struct {
 		Input struct {
 			id string
@@ -677,6 +768,144 @@ type TestResults struct {
 			err         error
 		}
 	}
+	GetTombstones struct {
+		Input struct {
+			kind string
+			ids  []string
+		}
+		Return struct {
+			tss []*sm.Tombstone
+			err error
+		}
+	}
+	RestoreTombstone struct {
+		Input struct {
+			kind string
+			id   string
+		}
+		Return struct {
+			restored bool
+			err      error
+		}
+	}
+	PurgeTombstones struct {
+		Input struct {
+			before time.Time
+		}
+		Return struct {
+			numRows int64
+			err     error
+		}
+	}
+	UpdateCompHeartbeats struct {
+		Input struct {
+			ids []string
+		}
+		Return struct {
+			hbs []*sm.CompHeartbeat
+			err error
+		}
+	}
+	GetCompHeartbeats struct {
+		Input struct {
+			f *hmsds.CompHeartbeatFilter
+		}
+		Return struct {
+			hbs []*sm.CompHeartbeat
+			err error
+		}
+	}
+	GetNIDPolicies struct {
+		Return struct {
+			policies []*sm.NIDPolicy
+			err      error
+		}
+	}
+	UpsertNIDPolicy struct {
+		Input struct {
+			p *sm.NIDPolicy
+		}
+		Return struct {
+			err error
+		}
+	}
+	DeleteNIDPolicy struct {
+		Input struct {
+			name string
+		}
+		Return struct {
+			didDelete bool
+			err       error
+		}
+	}
+	GetNIDAssignments struct {
+		Input struct {
+			ids []string
+		}
+		Return struct {
+			as  []*sm.NIDAssignment
+			err error
+		}
+	}
+	InsertNIDAssignment struct {
+		Input struct {
+			a *sm.NIDAssignment
+		}
+		Return struct {
+			didInsert bool
+			err       error
+		}
+	}
+	DeleteNIDAssignment struct {
+		Input struct {
+			id string
+		}
+		Return struct {
+			didDelete bool
+			err       error
+		}
+	}
+	GetNIDsInUse struct {
+		Input struct {
+			start int64
+			end   int64
+		}
+		Return struct {
+			inUse map[int64][]string
+			err   error
+		}
+	}
+	DBStats struct {
+		Return struct {
+			stats sql.DBStats
+		}
+	}
+	SetPoolConfig struct {
+		Input struct {
+			cfg hmsds.DBPoolConfig
+		}
+	}
+	PoolConfig struct {
+		Return struct {
+			cfg hmsds.DBPoolConfig
+		}
+	}
+	TxStats struct {
+		Return struct {
+			stats hmsds.DBTxStats
+		}
+	}
+	SetLogLevel struct {
+		Input struct {
+			lvl hmsds.LogLevel
+		}
+	}
+	ReencryptRFEndpointPasswords struct {
+		Return struct {
+			num int
+			err error
+		}
+	}
 	// Component Endpoints
 	GetCompEndpointByID struct {
 		Input struct {
@@ -808,6 +1037,41 @@ type TestResults struct {
 			err     error
 		}
 	}
+	// Event Subscriptions
+	GetEventSubscriptions struct {
+		Input struct {
+			ids []string
+		}
+		Return struct {
+			subs []*sm.EventSubscription
+			err  error
+		}
+	}
+	UpsertEventSubscription struct {
+		Input struct {
+			subs []sm.EventSubscription
+		}
+		Return struct {
+			err error
+		}
+	}
+	GetRFEndpointLiveness struct {
+		Input struct {
+			ids []string
+		}
+		Return struct {
+			lvs []*sm.RedfishEndpointLiveness
+			err error
+		}
+	}
+	UpsertRFEndpointLiveness struct {
+		Input struct {
+			lvs []sm.RedfishEndpointLiveness
+		}
+		Return struct {
+			err error
+		}
+	}
 	// Component Ethernet Interfaces
 	GetCompEthInterfaceFilter struct {
 		Input struct {
@@ -955,6 +1219,16 @@ type TestResults struct {
 			err           error
 		}
 	}
+	UpdateAllForRFEndpointBatches struct {
+		Input struct {
+			ep      *sm.RedfishEndpoint
+			batches []*hmsds.RFEndpointBatch
+		}
+		Return struct {
+			discoveredIds *[]base.Component
+			err           error
+		}
+	}
 	// SCN subscriptions operations
 	GetSCNSubscriptionsAll struct {
 		Return struct {
@@ -1041,8 +1315,9 @@ type TestResults struct {
 			filt_part string
 		}
 		Return struct {
-			group *sm.Group
-			err   error
+			group   *sm.Group
+			byLabel map[string]*sm.Group // If set, used instead of group
+			err     error
 		}
 	}
 	GetGroupLabels struct {
@@ -1051,6 +1326,21 @@ type TestResults struct {
 			err    error
 		}
 	}
+	WithActor struct {
+		Input struct {
+			actor string
+		}
+	}
+	GetGroupChildren struct {
+		Input struct {
+			label     string
+			recursive bool
+		}
+		Return struct {
+			children []string
+			err      error
+		}
+	}
 	DeleteGroup struct {
 		Input struct {
 			label string
@@ -1080,6 +1370,16 @@ type TestResults struct {
 			err       error
 		}
 	}
+	GetGroupHist struct {
+		Input struct {
+			label string
+			f     *hmsds.MemberHistFilter
+		}
+		Return struct {
+			hist []*sm.MemberHistEntry
+			err  error
+		}
+	}
 	// Partitions
 	InsertPartition struct {
 		Input struct {
@@ -1143,6 +1443,27 @@ type TestResults struct {
 			err       error
 		}
 	}
+	MovePartitionMembers struct {
+		Input struct {
+			from string
+			to   string
+			ids  []string
+		}
+		Return struct {
+			ids []string
+			err error
+		}
+	}
+	GetPartitionHist struct {
+		Input struct {
+			pname string
+			f     *hmsds.MemberHistFilter
+		}
+		Return struct {
+			hist []*sm.MemberHistEntry
+			err  error
+		}
+	}
 	// Memberships
 	GetMembership struct {
 		Input struct {
@@ -1196,6 +1517,15 @@ type TestResults struct {
 			err error
 		}
 	}
+	GetCompReservationsExpiring struct {
+		Input struct {
+			within time.Duration
+		}
+		Return struct {
+			res []sm.CompLockV2Expiring
+			err error
+		}
+	}
 	GetCompReservations struct {
 		Input struct {
 			dkeys []sm.CompLockV2Key
@@ -1233,6 +1563,15 @@ type TestResults struct {
 			err     error
 		}
 	}
+	GetCompLockAudit struct {
+		Input struct {
+			f *hmsds.CompLockAuditFilter
+		}
+		Return struct {
+			entries []*sm.CompLockV2AuditEntry
+			err     error
+		}
+	}
 	// Job Sync
 	InsertJob struct {
 		Input struct {
@@ -1280,6 +1619,59 @@ type TestResults struct {
 			err       error
 		}
 	}
+	AcquireLease struct {
+		Input struct {
+			name   string
+			holder string
+			ttl    time.Duration
+		}
+		Return struct {
+			acquired bool
+			err      error
+		}
+	}
+	ReleaseLease struct {
+		Input struct {
+			name   string
+			holder string
+		}
+		Return struct {
+			released bool
+			err      error
+		}
+	}
+	GetLeases struct {
+		Input struct {
+			prefix string
+		}
+		Return struct {
+			leases []*hmsds.Lease
+			err    error
+		}
+	}
+	GetDiscoverySchedules struct {
+		Return struct {
+			scheds []*sm.DiscoverySchedule
+			err    error
+		}
+	}
+	UpsertDiscoverySchedule struct {
+		Input struct {
+			ds *sm.DiscoverySchedule
+		}
+		Return struct {
+			err error
+		}
+	}
+	DeleteDiscoverySchedule struct {
+		Input struct {
+			name string
+		}
+		Return struct {
+			didDelete bool
+			err       error
+		}
+	}
 }
 
 type hmsdbtest struct {
@@ -1313,9 +1705,13 @@ func (d *hmsdbtest) ImplementationName() string {
 }
 
 func (d *hmsdbtest) SetLogLevel(lvl hmsds.LogLevel) error {
+	d.t.SetLogLevel.Input.lvl = lvl
 	return nil
 }
 
+func (d *hmsdbtest) SetFieldEncryption(env *envcrypt.Envelope) {
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // DB operations - Open, Close, Start Transaction
@@ -1345,11 +1741,32 @@ func (d *hmsdbtest) Begin() (hmsds.HMSDBTx, error) {
 	return nil, nil
 }
 
+func (d *hmsdbtest) WithActor(actor string) hmsds.HMSDB {
+	d.t.WithActor.Input.actor = actor
+	return d
+}
+
 // Test the database connection to make sure that it is healthy
 func (d *hmsdbtest) TestConnection() error {
 	return d.t.TestConnection.Return.err
 }
 
+func (d *hmsdbtest) DBStats() sql.DBStats {
+	return d.t.DBStats.Return.stats
+}
+
+func (d *hmsdbtest) SetPoolConfig(cfg hmsds.DBPoolConfig) {
+	d.t.SetPoolConfig.Input.cfg = cfg
+}
+
+func (d *hmsdbtest) PoolConfig() hmsds.DBPoolConfig {
+	return d.t.PoolConfig.Return.cfg
+}
+
+func (d *hmsdbtest) TxStats() hmsds.DBTxStats {
+	return d.t.TxStats.Return.stats
+}
+
 // Build filter query for Component IDs using filter functions and
 // then return the list of matching xname IDs as a string array, write
 // locking the rows if requested.
@@ -1421,6 +1838,30 @@ func (d *hmsdbtest) GetComponentsQuery(f *hmsds.ComponentFilter, fieldFltr hmsds
 	return d.t.GetComponentsQuery.Return.ids, d.t.GetComponentsQuery.Return.err
 }
 
+// Get some or all HMS Components as they were at time asOf, from the
+// component state history.
+func (d *hmsdbtest) GetComponentsAsOf(f *hmsds.ComponentFilter, fieldFltr hmsds.FieldFilter, asOf string) ([]*base.Component, error) {
+	d.t.GetComponentsAsOf.Input.compFilter = *f
+	d.t.GetComponentsAsOf.Input.fieldFilter = fieldFltr
+	d.t.GetComponentsAsOf.Input.asOf = asOf
+	return d.t.GetComponentsAsOf.Return.ids, d.t.GetComponentsAsOf.Return.err
+}
+
+func (d *hmsdbtest) GetCompStateHist(id string, f *hmsds.CompStateHistFilter) ([]*sm.CompStateHistEntry, error) {
+	d.t.GetCompStateHist.Input.id = id
+	d.t.GetCompStateHist.Input.f = f
+	return d.t.GetCompStateHist.Return.hist, d.t.GetCompStateHist.Return.err
+}
+
+// Count the HMS Components in system by type and state.
+func (d *hmsdbtest) GetComponentCounts() ([]*hmsds.ComponentCount, error) {
+	return d.t.GetComponentCounts.Return.counts, d.t.GetComponentCounts.Return.err
+}
+
+func (d *hmsdbtest) GetCollectionVersions() (*hmsds.CollectionVersions, error) {
+	return d.t.GetCollectionVersions.Return.vers, d.t.GetCollectionVersions.Return.err
+}
+
 // Get a single component by its NID, if one exists.
 func (d *hmsdbtest) GetComponentByNID(nid string) (*base.Component, error) {
 	d.t.GetComponentByNID.Input.nid = nid
@@ -1450,6 +1891,21 @@ func (d *hmsdbtest) UpsertComponents(comps []*base.Component, force bool) (map[s
 	return d.t.UpsertComponents.Return.changeMap, d.t.UpsertComponents.Return.err
 }
 
+// Overwrite the writable fields of existing component c.ID with those of
+// c, but only if the component still matches prev.
+func (d *hmsdbtest) UpdateComponentIfUnchanged(prev, c *base.Component) (bool, error) {
+	d.t.UpdateComponentIfUnchanged.Input.prev = prev
+	d.t.UpdateComponentIfUnchanged.Input.c = c
+	return d.t.UpdateComponentIfUnchanged.Return.ok, d.t.UpdateComponentIfUnchanged.Return.err
+}
+
+// Set the fields given in p on every component matching f.
+func (d *hmsdbtest) BulkPatchComponents(f *hmsds.ComponentFilter, p *hmsds.CompBulkPatch) (map[string]map[string]bool, error) {
+	d.t.BulkPatchComponents.Input.f = f
+	d.t.BulkPatchComponents.Input.p = p
+	return d.t.BulkPatchComponents.Return.changes, d.t.BulkPatchComponents.Return.err
+}
+
 // Update state and flag fields only in DB for the given IDs.  If
 // len(ids) is > 1 a locking read will be done to ensure the list o
 // components that was actually modified is always returned.
@@ -1692,6 +2148,22 @@ func (d *hmsdbtest) GetHWInvByLocFilter(f_opts ...hmsds.HWInvLocFiltFunc) ([]*sm
 	return d.t.GetHWInvByLocFilter.Return.hwlocs, d.t.GetHWInvByLocFilter.Return.err
 }
 
+// Same selection as GetHWInvByLocFilter, but instead of returning the
+// entries fn is called with each one, in xname order, as it is read.
+func (d *hmsdbtest) ForEachHWInvByLocFilter(fn func(*sm.HWInvByLoc) error, f_opts ...hmsds.HWInvLocFiltFunc) error {
+	f := new(hmsds.HWInvLocFilter)
+	for _, opts := range f_opts {
+		opts(f)
+	}
+	d.t.ForEachHWInvByLocFilter.Input.f = f
+	for _, hwloc := range d.t.ForEachHWInvByLocFilter.Return.hwlocs {
+		if err := fn(hwloc); err != nil {
+			return err
+		}
+	}
+	return d.t.ForEachHWInvByLocFilter.Return.err
+}
+
 // Get a single Hardware inventory entry by current xname
 // This struct includes the FRU info if the xname is currently populated.
 func (d *hmsdbtest) GetHWInvByLocID(id string) (*sm.HWInvByLoc, error) {
@@ -1794,6 +2266,16 @@ func (d *hmsdbtest) DeleteHWInvByFRUsAll() (int64, error) {
 //
 ////////////////////////////////////////////////////////////////////////////
 
+// Find HW-inventory-by-FRU entries and the locations they are currently at.
+func (d *hmsdbtest) LocateHWInvByFRU(f_opts ...hmsds.HWInvLocFiltFunc) ([]*sm.HWInvByFRULocation, error) {
+	f := new(hmsds.HWInvLocFilter)
+	for _, opts := range f_opts {
+		opts(f)
+	}
+	d.t.LocateHWInvByFRU.Input.f = f
+	return d.t.LocateHWInvByFRU.Return.hwfrus, d.t.LocateHWInvByFRU.Return.err
+}
+
 // Get hardware history for some or all Hardware Inventory entries with
 // filtering options to possibly narrow the returned values.
 // If no filter provided, just get everything.  Otherwise use it
@@ -1828,6 +2310,22 @@ func (d *hmsdbtest) InsertHWInvHists(hhs []*sm.HWInvHist) error {
 	return d.t.InsertHWInvHists.Return.err
 }
 
+// Get the FRU sightings matching the filter.
+func (d *hmsdbtest) GetHWInvSightingsFilter(f_opts ...hmsds.HWInvHistFiltFunc) ([]*sm.HWInvSighting, error) {
+	f := new(hmsds.HWInvHistFilter)
+	for _, opts := range f_opts {
+		opts(f)
+	}
+	d.t.GetHWInvSightingsFilter.Input.f = f
+	return d.t.GetHWInvSightingsFilter.Return.sightings, d.t.GetHWInvSightingsFilter.Return.err
+}
+
+// Record that the FRUs populating hwlocs were seen at those locations now.
+func (d *hmsdbtest) RecordHWInvSightings(hwlocs []*sm.HWInvByLoc) error {
+	d.t.RecordHWInvSightings.Input.hwlocs = hwlocs
+	return d.t.RecordHWInvSightings.Return.err
+}
+
 // Delete all HWInvHist entries with matching xname id from database, if it
 // exists.
 // Returns the number of deleted rows, if error is nil.
@@ -1996,6 +2494,75 @@ func (d *hmsdbtest) DeleteRFEndpointsAllSetEmpty() (int64, []string, error) {
 		d.t.DeleteRFEndpointsAllSetEmpty.Return.err
 }
 
+func (d *hmsdbtest) GetTombstones(kind, id string) ([]*sm.Tombstone, error) {
+	d.t.GetTombstones.Input.kind = kind
+	d.t.GetTombstones.Input.ids = append(d.t.GetTombstones.Input.ids, id)
+	return d.t.GetTombstones.Return.tss, d.t.GetTombstones.Return.err
+}
+
+func (d *hmsdbtest) RestoreTombstone(kind, id string) (bool, error) {
+	d.t.RestoreTombstone.Input.kind = kind
+	d.t.RestoreTombstone.Input.id = id
+	return d.t.RestoreTombstone.Return.restored, d.t.RestoreTombstone.Return.err
+}
+
+func (d *hmsdbtest) PurgeTombstones(before time.Time) (int64, error) {
+	d.t.PurgeTombstones.Input.before = before
+	return d.t.PurgeTombstones.Return.numRows, d.t.PurgeTombstones.Return.err
+}
+
+func (d *hmsdbtest) UpdateCompHeartbeats(ids []string) ([]*sm.CompHeartbeat, error) {
+	d.t.UpdateCompHeartbeats.Input.ids = ids
+	return d.t.UpdateCompHeartbeats.Return.hbs, d.t.UpdateCompHeartbeats.Return.err
+}
+
+func (d *hmsdbtest) GetCompHeartbeats(f *hmsds.CompHeartbeatFilter) ([]*sm.CompHeartbeat, error) {
+	d.t.GetCompHeartbeats.Input.f = f
+	return d.t.GetCompHeartbeats.Return.hbs, d.t.GetCompHeartbeats.Return.err
+}
+
+func (d *hmsdbtest) GetNIDPolicies() ([]*sm.NIDPolicy, error) {
+	return d.t.GetNIDPolicies.Return.policies, d.t.GetNIDPolicies.Return.err
+}
+
+func (d *hmsdbtest) UpsertNIDPolicy(p *sm.NIDPolicy) error {
+	d.t.UpsertNIDPolicy.Input.p = p
+	return d.t.UpsertNIDPolicy.Return.err
+}
+
+func (d *hmsdbtest) DeleteNIDPolicy(name string) (bool, error) {
+	d.t.DeleteNIDPolicy.Input.name = name
+	return d.t.DeleteNIDPolicy.Return.didDelete, d.t.DeleteNIDPolicy.Return.err
+}
+
+func (d *hmsdbtest) GetNIDAssignments(ids []string) ([]*sm.NIDAssignment, error) {
+	d.t.GetNIDAssignments.Input.ids = ids
+	return d.t.GetNIDAssignments.Return.as, d.t.GetNIDAssignments.Return.err
+}
+
+func (d *hmsdbtest) InsertNIDAssignment(a *sm.NIDAssignment) (bool, error) {
+	d.t.InsertNIDAssignment.Input.a = a
+	return d.t.InsertNIDAssignment.Return.didInsert,
+		d.t.InsertNIDAssignment.Return.err
+}
+
+func (d *hmsdbtest) DeleteNIDAssignment(id string) (bool, error) {
+	d.t.DeleteNIDAssignment.Input.id = id
+	return d.t.DeleteNIDAssignment.Return.didDelete,
+		d.t.DeleteNIDAssignment.Return.err
+}
+
+func (d *hmsdbtest) GetNIDsInUse(start, end int64) (map[int64][]string, error) {
+	d.t.GetNIDsInUse.Input.start = start
+	d.t.GetNIDsInUse.Input.end = end
+	return d.t.GetNIDsInUse.Return.inUse, d.t.GetNIDsInUse.Return.err
+}
+
+func (d *hmsdbtest) ReencryptRFEndpointPasswords() (int, error) {
+	return d.t.ReencryptRFEndpointPasswords.Return.num,
+		d.t.ReencryptRFEndpointPasswords.Return.err
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Component Endpoints - Component info discovered from parent RedfishEndpoint
@@ -2130,6 +2697,48 @@ func (d *hmsdbtest) DeleteServiceEndpointsAll() (int64, error) {
 	return d.t.DeleteServiceEndpointsAll.Return.numRows, d.t.DeleteServiceEndpointsAll.Return.err
 }
 
+////////////////////////////////////////////////////////////////////////////
+//
+// Event Subscriptions - Redfish event subscriptions kept on RedfishEndpoints
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get the event subscriptions kept for the given RedfishEndpoint ids,
+// or for all RedfishEndpoints if ids is empty.
+func (d *hmsdbtest) GetEventSubscriptions(ids []string) ([]*sm.EventSubscription, error) {
+	d.t.GetEventSubscriptions.Input.ids = ids
+	return d.t.GetEventSubscriptions.Return.subs, d.t.GetEventSubscriptions.Return.err
+}
+
+// Insert the event subscription of a RedfishEndpoint, updating it if it
+// exists.  Each one is recorded, in order.
+func (d *hmsdbtest) UpsertEventSubscription(sub *sm.EventSubscription) error {
+	d.t.UpsertEventSubscription.Input.subs = append(
+		d.t.UpsertEventSubscription.Input.subs, *sub)
+	return d.t.UpsertEventSubscription.Return.err
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// RedfishEndpoint Liveness - whether RedfishEndpoints answered the BMC prober
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get the liveness of the given RedfishEndpoint ids, or of all
+// RedfishEndpoints that have been probed if ids is empty.
+func (d *hmsdbtest) GetRFEndpointLiveness(ids []string) ([]*sm.RedfishEndpointLiveness, error) {
+	d.t.GetRFEndpointLiveness.Input.ids = ids
+	return d.t.GetRFEndpointLiveness.Return.lvs, d.t.GetRFEndpointLiveness.Return.err
+}
+
+// Insert the liveness of a RedfishEndpoint, replacing it if it exists.
+// Each one is recorded, in order.
+func (d *hmsdbtest) UpsertRFEndpointLiveness(l *sm.RedfishEndpointLiveness) error {
+	d.t.UpsertRFEndpointLiveness.Input.lvs = append(
+		d.t.UpsertRFEndpointLiveness.Input.lvs, *l)
+	return d.t.UpsertRFEndpointLiveness.Return.err
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Component Ethernet Interfaces - MAC address to IP address relations
@@ -2318,6 +2927,25 @@ func (d *hmsdbtest) UpdateAllForRFEndpoint(
 	return d.t.UpdateAllForRFEndpoint.Return.discoveredIds, d.t.UpdateAllForRFEndpoint.Return.err
 }
 
+func (d *hmsdbtest) UpdateAllForRFEndpointBatches(
+	ep *sm.RedfishEndpoint,
+	next func() (*hmsds.RFEndpointBatch, error),
+) (*[]base.Component, error) {
+	d.t.UpdateAllForRFEndpointBatches.Input.ep = ep
+	d.t.UpdateAllForRFEndpointBatches.Input.batches = nil
+	for {
+		b, err := next()
+		if err != nil {
+			return nil, err
+		} else if b == nil {
+			break
+		}
+		d.t.UpdateAllForRFEndpointBatches.Input.batches =
+			append(d.t.UpdateAllForRFEndpointBatches.Input.batches, b)
+	}
+	return d.t.UpdateAllForRFEndpointBatches.Return.discoveredIds, d.t.UpdateAllForRFEndpointBatches.Return.err
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // SCN subscription operations
@@ -2399,6 +3027,9 @@ func (d *hmsdbtest) UpdateGroup(label string, gp *sm.GroupPatch) error {
 func (d *hmsdbtest) GetGroup(label, filt_part string) (*sm.Group, error) {
 	d.t.GetGroup.Input.label = label
 	d.t.GetGroup.Input.filt_part = filt_part
+	if d.t.GetGroup.Return.byLabel != nil {
+		return d.t.GetGroup.Return.byLabel[label], d.t.GetGroup.Return.err
+	}
 	return d.t.GetGroup.Return.group, d.t.GetGroup.Return.err
 }
 
@@ -2407,6 +3038,13 @@ func (d *hmsdbtest) GetGroupLabels() ([]string, error) {
 	return d.t.GetGroupLabels.Return.labels, d.t.GetGroupLabels.Return.err
 }
 
+// Get the labels of the child groups of the group with the given label.
+func (d *hmsdbtest) GetGroupChildren(label string, recursive bool) ([]string, error) {
+	d.t.GetGroupChildren.Input.label = label
+	d.t.GetGroupChildren.Input.recursive = recursive
+	return d.t.GetGroupChildren.Return.children, d.t.GetGroupChildren.Return.err
+}
+
 // Delete entire group with the given label.  If no error, bool indicates
 // whether member was present to remove.
 func (d *hmsdbtest) DeleteGroup(label string) (bool, error) {
@@ -2434,6 +3072,12 @@ func (d *hmsdbtest) DeleteGroupMember(label, id string) (bool, error) {
 	return d.t.DeleteGroupMember.Return.didDelete, d.t.DeleteGroupMember.Return.err
 }
 
+func (d *hmsdbtest) GetGroupHist(label string, f *hmsds.MemberHistFilter) ([]*sm.MemberHistEntry, error) {
+	d.t.GetGroupHist.Input.label = label
+	d.t.GetGroupHist.Input.f = f
+	return d.t.GetGroupHist.Return.hist, d.t.GetGroupHist.Return.err
+}
+
 func (d *hmsdbtest) SetGroupMembers(label string, ids []string) ([]string, error) {
 	return nil, nil
 }
@@ -2495,6 +3139,19 @@ func (d *hmsdbtest) DeletePartitionMember(pname, id string) (bool, error) {
 	return d.t.DeletePartitionMember.Return.didDelete, d.t.DeletePartitionMember.Return.err
 }
 
+func (d *hmsdbtest) MovePartitionMembers(from, to string, ids []string) ([]string, error) {
+	d.t.MovePartitionMembers.Input.from = from
+	d.t.MovePartitionMembers.Input.to = to
+	d.t.MovePartitionMembers.Input.ids = ids
+	return d.t.MovePartitionMembers.Return.ids, d.t.MovePartitionMembers.Return.err
+}
+
+func (d *hmsdbtest) GetPartitionHist(pname string, f *hmsds.MemberHistFilter) ([]*sm.MemberHistEntry, error) {
+	d.t.GetPartitionHist.Input.pname = pname
+	d.t.GetPartitionHist.Input.f = f
+	return d.t.GetPartitionHist.Return.hist, d.t.GetPartitionHist.Return.err
+}
+
 //
 // Memberships
 //
@@ -2549,6 +3206,13 @@ func (d *hmsdbtest) DeleteCompReservationsExpired() ([]string, error) {
 	return d.t.DeleteCompReservationsExpired.Return.ids, d.t.DeleteCompReservationsExpired.Return.err
 }
 
+// Retrieve the reservations that expire within the given duration from now,
+// sorted by expiration time.
+func (d *hmsdbtest) GetCompReservationsExpiring(within time.Duration) ([]sm.CompLockV2Expiring, error) {
+	d.t.GetCompReservationsExpiring.Input.within = within
+	return d.t.GetCompReservationsExpiring.Return.res, d.t.GetCompReservationsExpiring.Return.err
+}
+
 // Retrieve the status of reservations. The public key and xname is
 // required to address the reservation.
 func (d *hmsdbtest) GetCompReservations(dkeys []sm.CompLockV2Key) (sm.CompLockV2ReservationResult, error) {
@@ -2583,6 +3247,12 @@ func (d *hmsdbtest) UpdateCompLocksV2(f sm.CompLockV2Filter, action string) (sm.
 	return d.t.UpdateCompLocksV2.Return.results, d.t.UpdateCompLocksV2.Return.err
 }
 
+// Get the lock audit log, oldest change first, optionally filtering.
+func (d *hmsdbtest) GetCompLockAudit(f *hmsds.CompLockAuditFilter) ([]*sm.CompLockV2AuditEntry, error) {
+	d.t.GetCompLockAudit.Input.f = f
+	return d.t.GetCompLockAudit.Return.entries, d.t.GetCompLockAudit.Return.err
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Job Sync Management
@@ -2630,3 +3300,47 @@ func (d *hmsdbtest) DeleteJob(jobId string) (bool, error) {
 	d.t.DeleteJob.Input.jobId = jobId
 	return d.t.DeleteJob.Return.didDelete, d.t.DeleteJob.Return.err
 }
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Leases
+//
+////////////////////////////////////////////////////////////////////////////
+
+func (d *hmsdbtest) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	d.t.AcquireLease.Input.name = name
+	d.t.AcquireLease.Input.holder = holder
+	d.t.AcquireLease.Input.ttl = ttl
+	return d.t.AcquireLease.Return.acquired, d.t.AcquireLease.Return.err
+}
+
+func (d *hmsdbtest) ReleaseLease(name, holder string) (bool, error) {
+	d.t.ReleaseLease.Input.name = name
+	d.t.ReleaseLease.Input.holder = holder
+	return d.t.ReleaseLease.Return.released, d.t.ReleaseLease.Return.err
+}
+
+func (d *hmsdbtest) GetLeases(prefix string) ([]*hmsds.Lease, error) {
+	d.t.GetLeases.Input.prefix = prefix
+	return d.t.GetLeases.Return.leases, d.t.GetLeases.Return.err
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Rediscovery Schedules
+//
+////////////////////////////////////////////////////////////////////////////
+
+func (d *hmsdbtest) GetDiscoverySchedules() ([]*sm.DiscoverySchedule, error) {
+	return d.t.GetDiscoverySchedules.Return.scheds, d.t.GetDiscoverySchedules.Return.err
+}
+
+func (d *hmsdbtest) UpsertDiscoverySchedule(ds *sm.DiscoverySchedule) error {
+	d.t.UpsertDiscoverySchedule.Input.ds = ds
+	return d.t.UpsertDiscoverySchedule.Return.err
+}
+
+func (d *hmsdbtest) DeleteDiscoverySchedule(name string) (bool, error) {
+	d.t.DeleteDiscoverySchedule.Input.name = name
+	return d.t.DeleteDiscoverySchedule.Return.didDelete, d.t.DeleteDiscoverySchedule.Return.err
+}
@@ -0,0 +1,396 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/subtle"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Redfish event receiver and hardware faults
+//
+// Redfish events can be POSTed directly to SMD at
+//
+//     POST /Events/Redfish
+//
+// e.g. by pointing SMD_EVENT_COLLECTOR_URL at it, so that they are acted on
+// without a message bus.  They are handled exactly like events read from the
+// bus: the RedfishEndpoint is taken from the event Context, which must hold
+// its xname (the default subscription Context, optionally followed by
+// ":<label>"), and power events change the State of the affected components.
+//
+// BMCs can't get tokens, so the receiver is a public route.  Instead
+// SMD_EVENT_SECRET is added to the Context of the subscriptions SMD keeps
+// (see eventsubscriptions.go) as a label, "<xname>:<secret>", and events
+// whose Context doesn't carry it are rejected.  Without it no events are
+// accepted here.
+//
+// Chassis intrusion, drive removal or failure and ResourceEvent health
+// changes raise hardware faults.  Each active fault is tracked in memory per
+// component, kind and Redfish resource, and sets the component's Flag:
+// Alert while any fault needs one, Warning while only lesser faults are
+// active, and back to OK once the last fault (including any cooling fault,
+// see coolant.go) on the component clears.  A resource that is not itself a
+// component is charged to the closest component above it, e.g. a drive to
+// its node, and failing that to the RedfishEndpoint.
+//
+// GET /State/HardwareFaults[/{xname}] reports the active faults.
+///////////////////////////////////////////////////////////////////////////////
+
+// Kinds of hardware fault
+const (
+	HWFaultIntrusion = "Intrusion"
+	HWFaultDrive     = "Drive"
+	HWFaultHealth    = "Health"
+)
+
+// One active hardware fault on a component.
+type HardwareFault struct {
+	ID                string `json:"ID"`
+	Kind              string `json:"Kind"`
+	Flag              string `json:"Flag"`
+	Resource          string `json:"Resource"`
+	MessageId         string `json:"MessageId"`
+	Detail            string `json:"Detail,omitempty"`
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+	Since             string `json:"Since"`
+}
+
+// Output of GET /State/HardwareFaults[/{xname}]
+type HardwareFaultArray struct {
+	HardwareFaults []HardwareFault `json:"HardwareFaults"`
+}
+
+type HardwareFaultTracker struct {
+	lock   sync.Mutex
+	faults map[string]map[string]*HardwareFault // by xname, kind:resource
+}
+
+// The worse of two flags, "" meaning no fault.
+func worseFaultFlag(a, b string) string {
+	if a == base.FlagAlert.String() || b == base.FlagAlert.String() {
+		return base.FlagAlert.String()
+	}
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+// Flag for the active faults of a component, or "" if there are none.
+func hwFaultsFlag(faults map[string]*HardwareFault) string {
+	flag := ""
+	for _, f := range faults {
+		flag = worseFaultFlag(flag, f.Flag)
+	}
+	return flag
+}
+
+// Record a fault (or its clearing if active is false).  Returns the flag the
+// component had before and should have after, "" meaning no hardware fault.
+func (ht *HardwareFaultTracker) update(f HardwareFault, active bool, now time.Time) (oldFlag, newFlag string) {
+	ht.lock.Lock()
+	defer ht.lock.Unlock()
+
+	if ht.faults == nil {
+		ht.faults = make(map[string]map[string]*HardwareFault)
+	}
+	key := f.Kind + ":" + f.Resource
+	faults := ht.faults[f.ID]
+	oldFlag = hwFaultsFlag(faults)
+	if active {
+		if faults == nil {
+			faults = make(map[string]*HardwareFault)
+			ht.faults[f.ID] = faults
+		}
+		if old, ok := faults[key]; ok {
+			f.Since = old.Since
+		} else {
+			f.Since = now.UTC().Format(time.RFC3339)
+		}
+		faults[key] = &f
+	} else if faults != nil {
+		delete(faults, key)
+		if len(faults) == 0 {
+			delete(ht.faults, f.ID)
+		}
+	}
+	return oldFlag, hwFaultsFlag(ht.faults[f.ID])
+}
+
+// Flag for the active faults of xname, or "" if there are none.
+func (ht *HardwareFaultTracker) flag(xname string) string {
+	ht.lock.Lock()
+	defer ht.lock.Unlock()
+
+	return hwFaultsFlag(ht.faults[xname])
+}
+
+// Active faults, for one xname only if xname is not "", sorted by xname,
+// kind and resource.
+func (ht *HardwareFaultTracker) active(xname string) []HardwareFault {
+	ht.lock.Lock()
+	defer ht.lock.Unlock()
+
+	faults := []HardwareFault{}
+	for id, byID := range ht.faults {
+		if xname != "" && id != xname {
+			continue
+		}
+		for _, f := range byID {
+			faults = append(faults, *f)
+		}
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		if faults[i].ID != faults[j].ID {
+			return faults[i].ID < faults[j].ID
+		}
+		if faults[i].Kind != faults[j].Kind {
+			return faults[i].Kind < faults[j].Kind
+		}
+		return faults[i].Resource < faults[j].Resource
+	})
+	return faults
+}
+
+// Record that a hardware fault was raised or cleared and update the Flag of
+// the component if needed.
+func (s *SmD) setHardwareFault(f HardwareFault, active bool) error {
+	oldFlag, newFlag := s.hwFaults.update(f, active, time.Now())
+	other := s.coolingFaults.flag(f.ID)
+	oldFlag = worseFaultFlag(oldFlag, other)
+	newFlag = worseFaultFlag(newFlag, other)
+	if oldFlag == newFlag {
+		return nil
+	}
+	if active {
+		s.LogAlways("Hardware fault on %s: %s %s (%s)", f.ID, f.Kind,
+			f.Resource, f.MessageId)
+	} else {
+		s.LogAlways("Hardware fault cleared on %s: %s %s (%s)", f.ID, f.Kind,
+			f.Resource, f.MessageId)
+	}
+	return s.setFaultFlag(f.ID, newFlag)
+}
+
+// Find the component a Redfish resource belongs to: the component at the
+// URI itself, or else the one at the closest URI above it.  Falls back to
+// the RedfishEndpoint itself.
+func (s *SmD) getIDForResource(epID, uri string) (string, error) {
+	if i := strings.Index(uri, "#"); i >= 0 {
+		uri = uri[:i]
+	}
+	uri = strings.TrimSuffix(uri, "/")
+	if uri == "" {
+		return epID, nil
+	}
+	// Only the full URI goes through getIDForURI, which resyncs the cache
+	// on a miss.  The URIs above it are looked up in the cache as it is.
+	xname, err := s.getIDForURI(epID, uri)
+	if err != nil {
+		return "", err
+	}
+	for xname == "" && strings.Count(uri, "/") > 3 {
+		uri = uri[:strings.LastIndex(uri, "/")]
+		xname, _ = s.smapCompEP.LookupKey(strings.ToLower(epID + ":" + uri))
+	}
+	if xname == "" {
+		xname = epID
+	}
+	return xname, nil
+}
+
+// Raise or clear a fault for the resource an event is about.
+func (s *SmD) hwFaultFromRFEvent(pe *processedRFEvent, kind, flag string, active bool) error {
+	xname, err := s.getIDForResource(pe.RfEndppointID, pe.Origin)
+	if err != nil {
+		return err
+	}
+	return s.setHardwareFault(HardwareFault{
+		ID:                xname,
+		Kind:              kind,
+		Flag:              flag,
+		Resource:          pe.Origin,
+		MessageId:         pe.MessageId,
+		Detail:            strings.Join(pe.MessageArgs, ", "),
+		RedfishEndpointID: pe.RfEndppointID,
+	}, active)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Events
+/////////////////////////////////////////////////////////////////////////////
+
+// EventActionParser - Chassis intrusion.  Vendor registries name these
+//
+//	differently, but all end the MessageId with Detected or Cleared.
+//	The Flag is set here, so no CompUpdate is returned.
+func IntrusionParser(s *SmD, pe *processedRFEvent) (*CompUpdate, error) {
+	active := !strings.HasSuffix(strings.ToLower(pe.MessageId), "cleared")
+	return nil, s.hwFaultFromRFEvent(pe, HWFaultIntrusion,
+		base.FlagWarning.String(), active)
+}
+
+// EventActionParser - StorageDevice registry drive events.  A failed or
+//
+//	offline drive is an Alert, a removed drive or predicted failure a
+//	Warning.  Inserted and the ...Cleared messages clear it.
+func DriveEventParser(s *SmD, pe *processedRFEvent) (*CompUpdate, error) {
+	id := strings.ToLower(pe.MessageId)
+	switch {
+	case id == "driveinserted" || strings.HasSuffix(id, "cleared"):
+		return nil, s.hwFaultFromRFEvent(pe, HWFaultDrive, "", false)
+	case id == "drivefailure" || id == "driveoffline":
+		return nil, s.hwFaultFromRFEvent(pe, HWFaultDrive,
+			base.FlagAlert.String(), true)
+	}
+	return nil, s.hwFaultFromRFEvent(pe, HWFaultDrive,
+		base.FlagWarning.String(), true)
+}
+
+// EventActionParser - ResourceEvent ResourceAdded/ResourceRemoved.  A
+//
+//	ComputerSystem or Chassis coming or going has the RedfishEndpoint
+//	rediscovered (see eventrediscover.go), a drive raises or clears a
+//	fault.  Other resources are left to discovery.
+func ResourceAddedRemovedParser(s *SmD, pe *processedRFEvent) (*CompUpdate, error) {
+	if isSystemOrChassisURI(pe.Origin) {
+		s.eventRediscoverLater(pe.RfEndppointID, pe.MessageId+" "+pe.Origin)
+		return nil, nil
+	}
+	if !strings.Contains(strings.ToLower(pe.Origin), "/drives/") {
+		return nil, nil
+	}
+	removed := strings.EqualFold(pe.MessageId, "ResourceRemoved")
+	return nil, s.hwFaultFromRFEvent(pe, HWFaultDrive,
+		base.FlagWarning.String(), removed)
+}
+
+// EventActionParser - ResourceEvent ResourceStatusChangedOK/Warning/
+//
+//	Critical.  Health of the resource in OriginOfCondition.
+func ResourceStatusChangedParser(s *SmD, pe *processedRFEvent) (*CompUpdate, error) {
+	switch strings.ToLower(pe.MessageId) {
+	case "resourcestatuschangedcritical":
+		return nil, s.hwFaultFromRFEvent(pe, HWFaultHealth,
+			base.FlagAlert.String(), true)
+	case "resourcestatuschangedwarning":
+		return nil, s.hwFaultFromRFEvent(pe, HWFaultHealth,
+			base.FlagWarning.String(), true)
+	}
+	return nil, s.hwFaultFromRFEvent(pe, HWFaultHealth, "", false)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// API
+/////////////////////////////////////////////////////////////////////////////
+
+// Whether secret is one of the labels of the Context each EventRecord is
+// identified by.
+func rfEventHasSecret(e *rf.Event, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	for _, erec := range e.Events {
+		_, labels := GetEventIDAndLabels(e.Context, erec.Context)
+		found := false
+		for _, label := range labels {
+			if subtle.ConstantTimeCompare([]byte(label), []byte(secret)) == 1 {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Receive a Redfish event pushed by a BMC (or forwarded by a collector) and
+// act on it as if it had come from the message bus.
+func (s *SmD) doRedfishEventPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body")
+		return
+	}
+	e, err := rf.EventDecode(body)
+	if err != nil {
+		s.lg.Printf("doRedfishEventPost(): %s", err)
+		sendJsonError(w, http.StatusBadRequest, "error decoding JSON Event")
+		return
+	} else if len(e.Events) == 0 {
+		sendJsonError(w, http.StatusBadRequest, "no Events in request")
+		return
+	}
+	if !rfEventHasSecret(e, s.eventSecret) {
+		sendJsonError(w, http.StatusForbidden, "bad Context")
+		return
+	}
+	if err := s.doHandleRFEvent(string(body)); err != nil {
+		s.LogAlways("doRedfishEventPost(): %s", err)
+		sendJsonError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sendJsonError(w, http.StatusNoContent, "")
+}
+
+// Get all active hardware faults
+func (s *SmD) doHardwareFaultsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, HardwareFaultArray{
+		HardwareFaults: s.hwFaults.active(""),
+	})
+}
+
+// Get the active hardware faults of one component
+func (s *SmD) doHardwareFaultGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !xnametypes.IsHMSCompIDValid(xname) {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname")
+		return
+	}
+	faults := s.hwFaults.active(xname)
+	if len(faults) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no hardware faults for this xname")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, HardwareFaultArray{HardwareFaults: faults})
+}
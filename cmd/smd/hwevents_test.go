@@ -0,0 +1,191 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+const testPayloadHWEvent = `{
+	"Context": "x1000c0s4b0:secret",
+	"Events": [{
+		"EventType": "Alert",
+		"MessageId": "%s",
+		"OriginOfCondition": {"@odata.id": "%s"}
+	}]
+}`
+
+func TestHardwareFaults(t *testing.T) {
+	ceps := []*sm.ComponentEndpoint{
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x1000c0s4b0", RfEndpointID: "x1000c0s4b0",
+			OdataID: "/redfish/v1/Managers/BMC"}},
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x1000c0s4b0n0", RfEndpointID: "x1000c0s4b0",
+			OdataID: "/redfish/v1/Systems/Node0"}},
+	}
+	getID := results.GetCompEndpointIDs.Funcs.getID
+	returnIDs := results.GetCompEndpointIDs.Funcs.returnIDs
+	defer func() {
+		results.GetCompEndpointsAll.Return.entries = nil
+		results.GetCompEndpointIDs.Funcs.getID = getID
+		results.GetCompEndpointIDs.Funcs.returnIDs = returnIDs
+		results.GetComponentByID.Return.id = nil
+		results.UpdateCompFlagOnly.Return.rowsAffected = 0
+		s.hwFaults = HardwareFaultTracker{}
+		s.eventSecret = ""
+	}()
+	s.eventSecret = "secret"
+	results.GetCompEndpointsAll.Return.entries = ceps
+	results.GetCompEndpointIDs.Funcs.getID = GetCompEpIDsGenGetID
+	results.GetCompEndpointIDs.Funcs.returnIDs = GetCompEpIDsGenReturnIDs(ceps)
+	results.GetComponentByID.Return.id = &base.Component{
+		ID: "x1000c0s4b0n0", State: base.StateOn.String()}
+	results.UpdateCompFlagOnly.Return.rowsAffected = 1
+
+	const drive = "/redfish/v1/Systems/Node0/Storage/1/Drives/0"
+	tests := []struct {
+		msgID   string
+		origin  string
+		expID   string
+		expFlag string // "" if the Flag should not change
+	}{
+		{"StorageDevice.1.1.DriveRemoved", drive, "x1000c0s4b0n0",
+			base.FlagWarning.String()},
+		{"StorageDevice.1.1.DriveRemoved", drive, "", ""},
+		{"ResourceEvent.1.0.ResourceStatusChangedCritical",
+			"/redfish/v1/Systems/Node0", "x1000c0s4b0n0", base.FlagAlert.String()},
+		{"ResourceEvent.1.0.ResourceStatusChangedOK",
+			"/redfish/v1/Systems/Node0", "x1000c0s4b0n0", base.FlagWarning.String()},
+		{"StorageDevice.1.1.DriveInserted", drive, "x1000c0s4b0n0",
+			base.FlagOK.String()},
+		// Not under any ComponentEndpoint - charged to the BMC
+		{"Vendor.1.0.ChassisIntrusionDetected", "/redfish/v1/Chassis/Enclosure",
+			"x1000c0s4b0", base.FlagWarning.String()},
+		// Only drives are tracked
+		{"ResourceEvent.1.0.ResourceRemoved",
+			"/redfish/v1/Systems/Node0/Processors/CPU0", "", ""},
+	}
+	for i, test := range tests {
+		results.UpdateCompFlagOnly.Input.id = ""
+		results.UpdateCompFlagOnly.Input.flag = ""
+		body := bytes.NewBufferString(
+			fmt.Sprintf(testPayloadHWEvent, test.msgID, test.origin))
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Events/Redfish", body)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Test %d: POST failed: %d %s", i, w.Code, w.Body.String())
+		}
+		if results.UpdateCompFlagOnly.Input.id != test.expID ||
+			results.UpdateCompFlagOnly.Input.flag != test.expFlag {
+			t.Errorf("Test %d: Expected '%s' on '%s', got '%s' on '%s'", i,
+				test.expFlag, test.expID, results.UpdateCompFlagOnly.Input.flag,
+				results.UpdateCompFlagOnly.Input.id)
+		}
+	}
+
+	get := func(uri string, expCode int) []HardwareFault {
+		req, _ := http.NewRequest("GET", uri, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != expCode {
+			t.Fatalf("GET %s: Response code was %v; want %v",
+				uri, w.Code, expCode)
+		}
+		var faults HardwareFaultArray
+		json.Unmarshal(w.Body.Bytes(), &faults)
+		return faults.HardwareFaults
+	}
+	get("https://localhost/hsm/v2/State/HardwareFaults/x1000c0s4b0n0",
+		http.StatusNotFound)
+	faults := get("https://localhost/hsm/v2/State/HardwareFaults", http.StatusOK)
+	if len(faults) != 1 || faults[0].ID != "x1000c0s4b0" ||
+		faults[0].Kind != HWFaultIntrusion ||
+		faults[0].Resource != "/redfish/v1/Chassis/Enclosure" ||
+		faults[0].RedfishEndpointID != "x1000c0s4b0" {
+		t.Errorf("Unexpected hardware faults: %v", faults)
+	}
+
+	// Not an Event
+	req, _ := http.NewRequest("POST", "https://localhost/hsm/v2/Events/Redfish",
+		bytes.NewBufferString(`{"Id": "MetricReport"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a body with no Events, got %d", w.Code)
+	}
+
+	// Without the secret nothing changes.
+	for _, ctx := range []string{"x1000c0s4b0", "x1000c0s4b0:wrong", ""} {
+		results.UpdateCompFlagOnly.Input.flag = ""
+		req, _ := http.NewRequest("POST", "https://localhost/hsm/v2/Events/Redfish",
+			bytes.NewBufferString(`{"Context": "`+ctx+`", "Events": [{
+				"EventType": "Alert",
+				"MessageId": "ResourceEvent.1.0.ResourceStatusChangedCritical",
+				"OriginOfCondition": {"@odata.id": "/redfish/v1/Systems/Node0"}
+			}]}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden || results.UpdateCompFlagOnly.Input.flag != "" {
+			t.Errorf("Context '%s': expected 403 and no flag change, got %d '%s'",
+				ctx, w.Code, results.UpdateCompFlagOnly.Input.flag)
+		}
+	}
+}
+
+func TestRFEventHasSecret(t *testing.T) {
+	tests := []struct {
+		ctx      string
+		recCtxs  []string
+		secret   string
+		expected bool
+	}{
+		{"x1000c0s4b0:secret", []string{""}, "secret", true},
+		{"x1000c0s4b0:label:secret", []string{"", ""}, "secret", true},
+		{"", []string{"x1000c0s4b0:secret", "x1000c0s4b0:secret"}, "secret", true},
+		{"", []string{"x1000c0s4b0:secret", "x1000c0s4b0"}, "secret", false},
+		{"x1000c0s4b0", []string{""}, "secret", false},
+		{"secret", []string{""}, "secret", false},
+		{"x1000c0s4b0:", []string{""}, "", false},
+	}
+	for i, test := range tests {
+		e := &rf.Event{Context: test.ctx}
+		for _, ctx := range test.recCtxs {
+			e.Events = append(e.Events, rf.EventRecord{Context: ctx})
+		}
+		if rfEventHasSecret(e, test.secret) != test.expected {
+			t.Errorf("Test %d: expected %t", i, test.expected)
+		}
+	}
+}
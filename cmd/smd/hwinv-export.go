@@ -0,0 +1,309 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Bulk hardware inventory export
+//
+// GET /Inventory/Hardware normally returns one JSON array of every matching
+// location with its full location and FRU info.  With format=csv or
+// format=ndjson it instead returns one flat row per location, with only the
+// fields asset management usually wants:
+//
+//     xname, type, fruid, manufacturer, model, serialnumber, partnumber,
+//     fwversion
+//
+// The columns parameter picks which ones and in what order, e.g.
+// columns=xname,serialnumber.  CSV output starts with a header row of the
+// column names.  NDJSON output is one JSON object per line, keyed by the
+// column names.
+//
+// Rows are written as they are read from the database and flushed to the
+// client every so often, so neither side has to hold the whole inventory of
+// a large system in memory.  fwversion is the location's firmware version
+// if it has one, else the FRU's firmware or BIOS version.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	HWInvExportCSV    = "csv"
+	HWInvExportNDJSON = "ndjson"
+	HWInvExportJSON   = "json" // The regular, non-streamed response
+
+	HWInvExportCSVMediaType    = "text/csv"
+	HWInvExportNDJSONMediaType = "application/x-ndjson"
+)
+
+// Rows written between flushes to the client.
+const hwInvExportFlushRows = 500
+
+// All export columns, in default order.
+var hwInvExportColumns = []string{
+	"xname",
+	"type",
+	"fruid",
+	"manufacturer",
+	"model",
+	"serialnumber",
+	"partnumber",
+	"fwversion",
+}
+
+var errHWInvExportFormat = errors.New("Invalid format")
+var errHWInvExportColumn = errors.New("Invalid column")
+
+// The fields of the location and FRU info used for export rows.  Every
+// location and FRU info type is decoded into this, keeping the fields it
+// has.
+type hwInvExportInfo struct {
+	Manufacturer    string `json:"Manufacturer"`
+	Model           string `json:"Model"`
+	SerialNumber    string `json:"SerialNumber"`
+	PartNumber      string `json:"PartNumber"`
+	FirmwareVersion string `json:"FirmwareVersion"`
+	BiosVersion     string `json:"BiosVersion"`
+}
+
+// Format and columns of a requested export.
+type hwInvExport struct {
+	format  string
+	columns []string
+}
+
+// Parse the format and columns query parameters.  Returns nil, nil for the
+// regular JSON response.  Columns may be given as a comma separated list,
+// repeated, or both.
+func newHWInvExport(formats, columns []string) (*hwInvExport, error) {
+	format := HWInvExportJSON
+	if len(formats) > 0 && formats[0] != "" {
+		format = strings.ToLower(formats[0])
+	}
+	switch format {
+	case HWInvExportJSON:
+		if len(columns) > 0 {
+			return nil, errHWInvExportColumn
+		}
+		return nil, nil
+	case HWInvExportCSV, HWInvExportNDJSON:
+	default:
+		return nil, errHWInvExportFormat
+	}
+	e := &hwInvExport{format: format}
+	for _, list := range columns {
+		for _, col := range strings.Split(list, ",") {
+			col = strings.ToLower(strings.TrimSpace(col))
+			if !hwInvExportColumnValid(col) {
+				return nil, errHWInvExportColumn
+			}
+			e.columns = append(e.columns, col)
+		}
+	}
+	if len(e.columns) == 0 {
+		e.columns = hwInvExportColumns
+	}
+	return e, nil
+}
+
+func hwInvExportColumnValid(col string) bool {
+	for _, c := range hwInvExportColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *hwInvExport) mediaType() string {
+	if e.format == HWInvExportCSV {
+		return HWInvExportCSVMediaType
+	}
+	return HWInvExportNDJSONMediaType
+}
+
+// Values of the export columns for one location.
+func (e *hwInvExport) values(hwloc *sm.HWInvByLoc) []string {
+	var loc, fru hwInvExportInfo
+	if locInfo, err := hwloc.EncodeLocationInfo(); err == nil {
+		json.Unmarshal(locInfo, &loc)
+	}
+	fruID := ""
+	if hwloc.PopulatedFRU != nil {
+		fruID = hwloc.PopulatedFRU.FRUID
+		if fruInfo, err := hwloc.PopulatedFRU.EncodeFRUInfo(); err == nil {
+			json.Unmarshal(fruInfo, &fru)
+		}
+	}
+	vals := make([]string, 0, len(e.columns))
+	for _, col := range e.columns {
+		val := ""
+		switch col {
+		case "xname":
+			val = hwloc.ID
+		case "type":
+			val = hwloc.Type
+		case "fruid":
+			val = fruID
+		case "manufacturer":
+			val = fru.Manufacturer
+		case "model":
+			val = fru.Model
+		case "serialnumber":
+			val = fru.SerialNumber
+		case "partnumber":
+			val = fru.PartNumber
+		case "fwversion":
+			val = loc.FirmwareVersion
+			if val == "" {
+				val = fru.FirmwareVersion
+			}
+			if val == "" {
+				val = fru.BiosVersion
+			}
+		}
+		vals = append(vals, val)
+	}
+	return vals
+}
+
+// Writes export rows in one of the export formats.
+type hwInvRowWriter interface {
+	writeRow(vals []string) error
+	flush() error
+}
+
+type hwInvCSVWriter struct {
+	cw *csv.Writer
+}
+
+func (cw *hwInvCSVWriter) writeRow(vals []string) error {
+	return cw.cw.Write(vals)
+}
+
+func (cw *hwInvCSVWriter) flush() error {
+	cw.cw.Flush()
+	return cw.cw.Error()
+}
+
+type hwInvNDJSONWriter struct {
+	bw      *bufio.Writer
+	columns []string
+}
+
+// Write the row as a JSON object with the fields in column order.
+func (nw *hwInvNDJSONWriter) writeRow(vals []string) error {
+	nw.bw.WriteByte('{')
+	for i, col := range nw.columns {
+		if i > 0 {
+			nw.bw.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		val, _ := json.Marshal(vals[i])
+		nw.bw.Write(key)
+		nw.bw.WriteByte(':')
+		nw.bw.Write(val)
+	}
+	nw.bw.WriteString("}\n")
+	return nil
+}
+
+func (nw *hwInvNDJSONWriter) flush() error {
+	return nw.bw.Flush()
+}
+
+// Start the response body.  CSV gets its header row.
+func (e *hwInvExport) newRowWriter(w io.Writer) (hwInvRowWriter, error) {
+	if e.format == HWInvExportCSV {
+		cw := &hwInvCSVWriter{cw: csv.NewWriter(w)}
+		return cw, cw.writeRow(e.columns)
+	}
+	return &hwInvNDJSONWriter{bw: bufio.NewWriter(w), columns: e.columns}, nil
+}
+
+// Stream the locations selected by f_opts to the client in the export's
+// format.  Nothing is written until the first row is read, so a failed
+// query still gets a regular error response.  Once rows have been sent a
+// failure can only end the response early.
+func (s *SmD) sendHWInvExport(w http.ResponseWriter, e *hwInvExport, f_opts []hmsds.HWInvLocFiltFunc) {
+	var rw hwInvRowWriter
+	flusher, _ := w.(http.Flusher)
+	start := func() error {
+		w.Header().Set("Content-Type", e.mediaType())
+		w.WriteHeader(http.StatusOK)
+		var err error
+		rw, err = e.newRowWriter(w)
+		return err
+	}
+
+	rows := 0
+	err := s.db.ForEachHWInvByLocFilter(func(hwloc *sm.HWInvByLoc) error {
+		if rw == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := rw.writeRow(e.values(hwloc)); err != nil {
+			return err
+		}
+		rows++
+		if rows%hwInvExportFlushRows == 0 {
+			if err := rw.flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	}, f_opts...)
+	if err != nil && rw == nil {
+		s.lg.Printf("sendHWInvExport(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "failed to query DB.", err)
+		return
+	} else if err != nil {
+		s.lg.Printf("sendHWInvExport(): Export ended after %d rows: %s",
+			rows, err)
+		return
+	}
+	if rw == nil {
+		// No matches, just the CSV header if any.
+		if err := start(); err != nil {
+			s.lg.Printf("sendHWInvExport(): Write failure: %s", err)
+			return
+		}
+	}
+	if err := rw.flush(); err != nil {
+		s.lg.Printf("sendHWInvExport(): Write failure: %s", err)
+	}
+}
@@ -0,0 +1,153 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	stest "github.com/OpenCHAMI/smd/v2/pkg/sharedtest"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestNewHWInvExport(t *testing.T) {
+	tests := []struct {
+		formats, columns []string
+		expected         *hwInvExport
+		expectedErr      error
+	}{
+		{nil, nil, nil, nil},
+		{[]string{"json"}, nil, nil, nil},
+		{[]string{"json"}, []string{"xname"}, nil, errHWInvExportColumn},
+		{[]string{"xml"}, nil, nil, errHWInvExportFormat},
+		{[]string{"CSV"}, nil,
+			&hwInvExport{format: HWInvExportCSV, columns: hwInvExportColumns}, nil},
+		{[]string{"ndjson"}, []string{"xname, SerialNumber", "fwversion"},
+			&hwInvExport{format: HWInvExportNDJSON,
+				columns: []string{"xname", "serialnumber", "fwversion"}}, nil},
+		{[]string{"csv"}, []string{"xname,serial"}, nil, errHWInvExportColumn},
+	}
+	for i, test := range tests {
+		e, err := newHWInvExport(test.formats, test.columns)
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Received '%v'",
+				i, test.expectedErr, err)
+		} else if !reflect.DeepEqual(e, test.expected) {
+			t.Errorf("Test %v Failed: Expected '%+v'; Received '%+v'",
+				i, test.expected, e)
+		}
+	}
+}
+
+func TestDoHWInvByLocationGetAllExport(t *testing.T) {
+	node := stest.NodeHWInvByLoc1
+	empty := &sm.HWInvByLoc{
+		ID:                        "x0c0s0b0n0d1",
+		Type:                      "Memory",
+		Status:                    "Empty",
+		HWInventoryByLocationType: sm.HWInvByLocMemory,
+	}
+	hwlocs := []*sm.HWInvByLoc{&node, empty}
+
+	tests := []struct {
+		reqURI         string
+		hmsdsRespErr   error
+		expectedCode   int
+		expectedType   string
+		expectedFilter *hmsds.HWInvLocFilter
+		expectedResp   string
+	}{{
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?format=csv",
+		expectedCode:   http.StatusOK,
+		expectedType:   HWInvExportCSVMediaType,
+		expectedFilter: &hmsds.HWInvLocFilter{},
+		expectedResp: "xname,type,fruid,manufacturer,model,serialnumber,partnumber,fwversion\n" +
+			"x0c0s0b0n0,Node,Dell-99999-1234-1234-2345,Dell,OKS0P2354,1234-1234-2345,99999,v1.0.2.9999\n" +
+			"x0c0s0b0n0d1,Memory,,,,,,\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?format=ndjson&columns=xname,serialnumber&type=node",
+		expectedCode:   http.StatusOK,
+		expectedType:   HWInvExportNDJSONMediaType,
+		expectedFilter: &hmsds.HWInvLocFilter{Type: []string{"Node"}},
+		expectedResp: `{"xname":"x0c0s0b0n0","serialnumber":"1234-1234-2345"}` + "\n" +
+			`{"xname":"x0c0s0b0n0d1","serialnumber":""}` + "\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?format=yaml",
+		expectedCode:   http.StatusBadRequest,
+		expectedFilter: nil,
+		expectedResp:   `{"type":"about:blank","title":"Bad Request","detail":"Invalid format","status":400}` + "\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?format=csv&columns=foo",
+		expectedCode:   http.StatusBadRequest,
+		expectedFilter: nil,
+		expectedResp:   `{"type":"about:blank","title":"Bad Request","detail":"Invalid column","status":400}` + "\n",
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?format=csv",
+		hmsdsRespErr:   sql.ErrConnDone,
+		expectedCode:   http.StatusInternalServerError,
+		expectedFilter: &hmsds.HWInvLocFilter{},
+		expectedResp:   `{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}` + "\n",
+	}}
+	defer func() {
+		results.ForEachHWInvByLocFilter.Input.f = nil
+		results.ForEachHWInvByLocFilter.Return.hwlocs = nil
+		results.ForEachHWInvByLocFilter.Return.err = nil
+	}()
+
+	for i, test := range tests {
+		results.ForEachHWInvByLocFilter.Input.f = nil
+		results.ForEachHWInvByLocFilter.Return.hwlocs = hwlocs
+		results.ForEachHWInvByLocFilter.Return.err = nil
+		if test.hmsdsRespErr != nil {
+			results.ForEachHWInvByLocFilter.Return.hwlocs = nil
+			results.ForEachHWInvByLocFilter.Return.err = test.hmsdsRespErr
+		}
+
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if test.expectedType != "" && w.Header().Get("Content-Type") != test.expectedType {
+			t.Errorf("Test %v Failed: Content-Type was '%v'; want '%v'",
+				i, w.Header().Get("Content-Type"), test.expectedType)
+		}
+		if f := results.ForEachHWInvByLocFilter.Input.f; (f == nil) != (test.expectedFilter == nil) ||
+			(f != nil && !compareHWInvLocFilter(*test.expectedFilter, *f)) {
+			t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, f)
+		}
+		if w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, test.expectedResp, w.Body)
+		}
+	}
+}
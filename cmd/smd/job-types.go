@@ -23,18 +23,11 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"strings"
-	"sync"
-	"time"
-
-	"github.com/hashicorp/go-retryablehttp"
 
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 	base "github.com/Cray-HPE/hms-base/v2"
@@ -122,7 +115,6 @@ func (j *JobSCN) Type() base.JobType {
 func (j *JobSCN) Run() {
 	var trigger string
 	var triggerType int
-	var waitGroup sync.WaitGroup
 	scn := sm.SCNPayload{
 		Components:     j.IDs,
 		Enabled:        j.Data.Enabled,
@@ -132,15 +124,16 @@ func (j *JobSCN) Run() {
 		SoftwareStatus: j.Data.SwStatus,
 		State:          j.Data.State,
 	}
+	j.s.compChanged(scn)
 	// j.s.LogAlways("Sending SCN: %v\n", scn)
 	payload, err := json.Marshal(scn)
 	if err != nil {
-		j.s.LogAlways("WARNING: SCN failed. Could not encode JSON: %v (%v)", err, scn)
+		j.s.logWith(LogSubsysSCN).LogAlways(
+			"WARNING: SCN failed. Could not encode JSON: %v (%v)", err, scn)
 		j.SetStatus(base.JSTAT_ERROR, err)
 		return
 	}
 	// j.s.LogAlways("Sending SCN Payload: %v\n", string(payload))
-	client := j.s.GetHTTPClient()
 
 	// Get a the state that triggered this SCN
 	if len(scn.State) != 0 {
@@ -159,61 +152,54 @@ func (j *JobSCN) Run() {
 		trigger = "enabled"
 		triggerType = SCNMAP_ENABLED
 	} else {
-		j.s.LogAlways("warning: Invalid SCN trigger %v", scn)
+		j.s.logWith(LogSubsysSCN).LogAlways("warning: Invalid SCN trigger %v", scn)
 		j.SetStatus(base.JSTAT_ERROR, errors.New("invalid SCN trigger"))
 		return
 	}
-	if j.s.scnSubMap[triggerType] == nil {
+	j.s.queueSCN(scn, payload, triggerType, trigger)
+}
+
+// Queue a SCN, already encoded as payload, for each subscriber to the
+// (lower case) trigger.
+func (s *SmD) queueSCN(scn sm.SCNPayload, payload []byte, triggerType int, trigger string) {
+	if s.scnSubMap[triggerType] == nil {
 		// No subscriptions for this trigger type
 		return
 	}
-	urlList, ok := j.s.scnSubMap[triggerType][trigger]
+	urlList, ok := s.scnSubMap[triggerType][trigger]
 	if !ok {
 		// No URLs to send to
 		return
 	}
+	// Each URL has its own queue, see scn-delivery.go, and SCNs may be
+	// coalesced first, see scn-coalesce.go
+	resolver := newSCNScopeResolver(s)
 	for _, url := range urlList {
-		waitGroup.Add(1)
-		go func(urlStr string) {
-			defer waitGroup.Done()
-			for retry := 0; retry < 3; retry++ {
-				var strbody []byte
-				req, rerr := http.NewRequest("POST", urlStr, bytes.NewReader(payload))
-				if err != nil {
-					j.s.LogAlways("WARNING: can't create an HTTP request: %v",
-						rerr)
-					time.Sleep(5 * time.Second)
-					continue
-				}
-				base.SetHTTPUserAgent(req, serviceName)
-				req.Header.Add("Content-Type", "application/json")
-				newRequest, rerr := retryablehttp.FromRequest(req)
-				if err != nil {
-					j.s.LogAlways("WARNING: can't create an HTTP request: %v",
-						rerr)
-					time.Sleep(5 * time.Second)
-					continue
-				}
-				rsp, err := client.Do(newRequest)
-				if err != nil {
-					base.DrainAndCloseResponseBody(rsp)
-					j.s.LogAlways("WARNING: SCN POST failed for %s: %v", urlStr, err)
-				} else {
-					if rsp.Body != nil {
-						strbody, _ = ioutil.ReadAll(rsp.Body)
-					}
-					base.DrainAndCloseResponseBody(rsp)
-					if rsp.StatusCode != 200 {
-						j.s.LogAlways("WARNING: An error occurred uploading SCN to %s: %s %s", urlStr, rsp.Status, string(strbody))
-					} else {
-						return
-					}
-				}
-				time.Sleep(5 * time.Second)
-			}
-		}(url.url)
+		subs := s.getSCNScopeSubs(url.url, triggerType, trigger)
+		if subs == nil {
+			// At least one subscription wants everything.
+			s.sendSCN(url.url, scn, payload)
+			continue
+		}
+		// Only send the components the subscriber asked for. See
+		// scn-scope.go
+		scoped := scn
+		scoped.Components = resolver.filter(subs, scn.Components)
+		if len(scoped.Components) == 0 {
+			continue
+		}
+		if len(scoped.Components) == len(scn.Components) {
+			s.sendSCN(url.url, scn, payload)
+			continue
+		}
+		scopedPayload, err := json.Marshal(scoped)
+		if err != nil {
+			s.logWith(LogSubsysSCN).LogAlways(
+				"WARNING: SCN failed. Could not encode JSON: %v (%v)", err, scoped)
+			continue
+		}
+		s.sendSCN(url.url, scoped, scopedPayload)
 	}
-	waitGroup.Wait()
 }
 
 // ///////////////////////////////////////////////////////////////////////////
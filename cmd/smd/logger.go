@@ -38,7 +38,7 @@ func (s *SmD) Logger(inner http.Handler, name string) http.Handler {
 
 		inner.ServeHTTP(w, r)
 
-		s.Log(LOG_NOTICE,
+		s.reqLog(r).Log(LOG_NOTICE,
 			"%s %s %s %s",
 			r.Method,
 			r.RequestURI,
@@ -0,0 +1,386 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Structured logging
+//
+// Log lines are text by default.  With -log-format json (or SMD_LOG_FORMAT)
+// each line is a JSON object with the time, caller and message instead,
+// plus the level, subsystem and fields such as request_id, xname and
+// endpoint_id where the code logging it knows them.  Lines from the
+// database layer have the subsystem "db".
+//
+// The -log level can be overridden per subsystem (api, discovery, db, scn)
+// at startup with SMD_LOG_LEVELS, e.g. "discovery=debug,db=info", or at
+// runtime with PUT /service/loglevel.  Overrides are not persisted and
+// apply only to the HSM instance that receives the request.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+const (
+	LogSubsysAPI       = "api"
+	LogSubsysDiscovery = "discovery"
+	LogSubsysDB        = "db"
+	LogSubsysSCN       = "scn"
+)
+
+var logSubsystems = map[string]bool{
+	LogSubsysAPI:       true,
+	LogSubsysDiscovery: true,
+	LogSubsysDB:        true,
+	LogSubsysSCN:       true,
+}
+
+// Names of the log levels, indexed by LogLevel.
+var logLevelNames = []string{"default", "notice", "info", "debug"}
+
+func (lvl LogLevel) String() string {
+	if lvl >= LOG_DEFAULT && lvl < LOG_LVL_MAX {
+		return logLevelNames[lvl]
+	}
+	return strconv.Itoa(int(lvl))
+}
+
+// Parse a level name or number, e.g. "debug" or "3".
+func parseLogLevel(val string) (LogLevel, error) {
+	val = strings.ToLower(strings.TrimSpace(val))
+	for i, name := range logLevelNames {
+		if val == name {
+			return LogLevel(i), nil
+		}
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < int(LOG_DEFAULT) || n >= int(LOG_LVL_MAX) {
+		return LOG_DEFAULT, fmt.Errorf("bad log level '%s', want one of %s",
+			val, strings.Join(logLevelNames, ", "))
+	}
+	return LogLevel(n), nil
+}
+
+// Parse per-subsystem levels, e.g. "discovery=debug,db=info".
+func parseLogLevels(val string) (map[string]LogLevel, error) {
+	levels := make(map[string]LogLevel)
+	for _, kv := range strings.Split(val, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+		sub, lvlStr, ok := strings.Cut(kv, "=")
+		sub = strings.ToLower(strings.TrimSpace(sub))
+		if !ok || !logSubsystems[sub] {
+			return nil, fmt.Errorf("bad subsystem level '%s'", kv)
+		}
+		lvl, err := parseLogLevel(lvlStr)
+		if err != nil {
+			return nil, err
+		}
+		levels[sub] = lvl
+	}
+	return levels, nil
+}
+
+// Map our log level to the equivalent for the database layer.
+func hmsdsLogLevel(lvl LogLevel) hmsds.LogLevel {
+	switch lvl {
+	case LOG_DEFAULT:
+		return hmsds.LOG_DEFAULT
+	case LOG_NOTICE:
+		return hmsds.LOG_NOTICE
+	case LOG_INFO:
+		return hmsds.LOG_INFO
+	default:
+		return hmsds.LOG_DEBUG
+	}
+}
+
+// Per-subsystem overrides of the -log level
+type LogLevelOverrides struct {
+	lock   sync.RWMutex
+	levels map[string]LogLevel
+}
+
+func (o *LogLevelOverrides) Get(sub string) (LogLevel, bool) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	lvl, ok := o.levels[sub]
+	return lvl, ok
+}
+
+func (o *LogLevelOverrides) Set(sub string, lvl LogLevel) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.levels == nil {
+		o.levels = make(map[string]LogLevel)
+	}
+	o.levels[sub] = lvl
+}
+
+func (o *LogLevelOverrides) Clear(sub string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	delete(o.levels, sub)
+}
+
+// The level in effect for a subsystem: its override, if any, else -log.
+func (s *SmD) subsysLogLevel(sub string) LogLevel {
+	if lvl, ok := s.logLevels.Get(sub); ok {
+		return lvl
+	}
+	return s.lgLvl
+}
+
+// Switch s.lg to JSON lines on w.  Anything logged with s.lg directly (or
+// by the database layer via dbLogger()) is given the caller and message
+// only, as its level isn't known.
+func (s *SmD) setJSONLogging(w io.Writer) {
+	zl := zerolog.New(w)
+	s.zlog = &zl
+	s.lg = log.New(&jsonLogWriter{zl: s.zlog}, "", log.Lshortfile)
+}
+
+// Logger for the database layer.
+func (s *SmD) dbLogger() *log.Logger {
+	if s.zlog == nil {
+		return s.lg
+	}
+	return log.New(&jsonLogWriter{zl: s.zlog, sub: LogSubsysDB}, "",
+		log.Lshortfile)
+}
+
+// Turns lines from a log.Logger with only log.Lshortfile set, i.e.
+// "file.go:123: message", into JSON.
+type jsonLogWriter struct {
+	zl  *zerolog.Logger
+	sub string
+}
+
+func (jw *jsonLogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	caller, msg, ok := strings.Cut(line, ": ")
+	if !ok {
+		caller, msg = "", line
+	}
+	ev := jw.zl.Log().Str("time", logTime())
+	if caller != "" {
+		ev = ev.Str("caller", caller)
+	}
+	if jw.sub != "" {
+		ev = ev.Str("subsystem", jw.sub)
+	}
+	ev.Msg(msg)
+	return len(p), nil
+}
+
+func logTime() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+type logField struct {
+	key string
+	val string
+}
+
+// Logs for a subsystem, with fields added to each line.  Made with
+// s.logWith() or s.reqLog().
+type SubsysLogger struct {
+	s      *SmD
+	sub    string
+	fields []logField
+}
+
+func (s *SmD) logWith(sub string) SubsysLogger {
+	return SubsysLogger{s: s, sub: sub}
+}
+
+// Logger for an API request, with its request ID.
+func (s *SmD) reqLog(r *http.Request) SubsysLogger {
+	l := s.logWith(LogSubsysAPI)
+	if id := middleware.GetReqID(r.Context()); id != "" {
+		l = l.With("request_id", id)
+	}
+	return l
+}
+
+// Copy of the logger with another field, e.g. With("xname", id).
+func (l SubsysLogger) With(key, val string) SubsysLogger {
+	fields := make([]logField, len(l.fields), len(l.fields)+1)
+	copy(fields, l.fields)
+	l.fields = append(fields, logField{key, val})
+	return l
+}
+
+// Log if lvl is at or below the subsystem's level.
+func (l SubsysLogger) Log(lvl LogLevel, format string, a ...interface{}) {
+	if lvl <= l.s.subsysLogLevel(l.sub) {
+		l.output(lvl, fmt.Sprintf(format, a...))
+	}
+}
+
+func (l SubsysLogger) LogAlways(format string, a ...interface{}) {
+	l.output(LOG_DEFAULT, fmt.Sprintf(format, a...))
+}
+
+// Depth 3 is the caller of Log() or LogAlways().
+func (l SubsysLogger) output(lvl LogLevel, msg string) {
+	if l.s.zlog == nil {
+		var sb strings.Builder
+		sb.WriteString(msg)
+		sb.WriteString(" subsystem=" + l.sub)
+		for _, f := range l.fields {
+			sb.WriteString(" " + f.key + "=" + f.val)
+		}
+		l.s.lg.Output(3, sb.String())
+		return
+	}
+	ev := l.s.zlog.Log().Str("time", logTime()).Str("level", lvl.String())
+	if _, file, line, ok := runtime.Caller(2); ok {
+		ev = ev.Str("caller", filepath.Base(file)+":"+strconv.Itoa(line))
+	}
+	ev = ev.Str("subsystem", l.sub)
+	for _, f := range l.fields {
+		ev = ev.Str(f.key, f.val)
+	}
+	ev.Msg(msg)
+}
+
+// Output of GET /service/loglevel and input for PUT /service/loglevel.
+// Default is the -log level, and Subsystems the level in effect for each
+// subsystem.  In a PUT, Default may not be changed and a level of "" goes
+// back to the default.
+type LogLevelsStatus struct {
+	Default    string            `json:"Default"`
+	Format     string            `json:"Format,omitempty"`
+	Subsystems map[string]string `json:"Subsystems"`
+}
+
+func (s *SmD) logLevelsStatus() LogLevelsStatus {
+	st := LogLevelsStatus{
+		Default:    s.lgLvl.String(),
+		Format:     LogFormatText,
+		Subsystems: make(map[string]string),
+	}
+	if s.zlog != nil {
+		st.Format = LogFormatJSON
+	}
+	for sub := range logSubsystems {
+		st.Subsystems[sub] = s.subsysLogLevel(sub).String()
+	}
+	return st
+}
+
+// Set or clear the override for a subsystem, passing the db level on to
+// the database layer.
+func (s *SmD) setSubsysLogLevel(sub string, lvl LogLevel, clear bool) {
+	if clear {
+		s.logLevels.Clear(sub)
+	} else {
+		s.logLevels.Set(sub, lvl)
+	}
+	if sub == LogSubsysDB && s.db != nil {
+		s.db.SetLogLevel(hmsdsLogLevel(s.subsysLogLevel(sub)))
+	}
+}
+
+// Get the log levels
+func (s *SmD) doLogLevelGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, s.logLevelsStatus())
+}
+
+// Change the log level of one or more subsystems
+func (s *SmD) doLogLevelPut(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var in LogLevelsStatus
+	body, err := ioutil.ReadAll(r.Body)
+	err = json.Unmarshal(body, &in)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if in.Default != "" && in.Default != s.lgLvl.String() {
+		sendJsonError(w, http.StatusBadRequest,
+			"Default can't be changed, set the level of each subsystem")
+		return
+	}
+	if len(in.Subsystems) == 0 {
+		sendJsonError(w, http.StatusBadRequest,
+			"Subsystems is required in PUT body")
+		return
+	}
+	// Check everything before changing anything.
+	subs := make([]string, 0, len(in.Subsystems))
+	levels := make(map[string]LogLevel)
+	for sub, lvlStr := range in.Subsystems {
+		if !logSubsystems[sub] {
+			sendJsonError(w, http.StatusBadRequest,
+				"unknown subsystem '"+sub+"'")
+			return
+		}
+		subs = append(subs, sub)
+		if lvlStr == "" {
+			continue
+		}
+		lvl, err := parseLogLevel(lvlStr)
+		if err != nil {
+			sendJsonError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		levels[sub] = lvl
+	}
+	sort.Strings(subs)
+	for _, sub := range subs {
+		lvl, ok := levels[sub]
+		s.setSubsysLogLevel(sub, lvl, !ok)
+		s.reqLog(r).LogAlways("Log level of %s set to %s (%s)", sub,
+			s.subsysLogLevel(sub), r.RemoteAddr)
+	}
+	sendJsonObject(w, http.StatusOK, s.logLevelsStatus())
+}
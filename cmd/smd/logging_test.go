@@ -0,0 +1,227 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestParseLogLevels(t *testing.T) {
+	tests := []struct {
+		in     string
+		exp    map[string]LogLevel
+		expErr bool
+	}{
+		{"", map[string]LogLevel{}, false},
+		{"discovery=debug, DB=1,scn=Info,", map[string]LogLevel{
+			LogSubsysDiscovery: LOG_DEBUG,
+			LogSubsysDB:        LOG_NOTICE,
+			LogSubsysSCN:       LOG_INFO,
+		}, false},
+		{"api=default", map[string]LogLevel{LogSubsysAPI: LOG_DEFAULT}, false},
+		{"discovery", nil, true},
+		{"hwinv=debug", nil, true},
+		{"db=loud", nil, true},
+		{"db=4", nil, true},
+	}
+	for i, test := range tests {
+		levels, err := parseLogLevels(test.in)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Test %d: Expected an error, got %v", i, levels)
+			}
+			continue
+		}
+		if err != nil || len(levels) != len(test.exp) {
+			t.Errorf("Test %d: Expected %v, got %v, %v", i, test.exp, levels, err)
+			continue
+		}
+		for sub, lvl := range test.exp {
+			if levels[sub] != lvl {
+				t.Errorf("Test %d: Expected %s=%s, got %s", i, sub, lvl,
+					levels[sub])
+			}
+		}
+	}
+}
+
+// Decode the JSON log lines in buf.
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]string {
+	var lines []map[string]string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		m := make(map[string]string)
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("Bad JSON log line '%s': %s", line, err)
+		}
+		lines = append(lines, m)
+	}
+	buf.Reset()
+	return lines
+}
+
+func TestStructuredLogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	ls := &SmD{lgLvl: LOG_NOTICE}
+	ls.setJSONLogging(&buf)
+
+	dlog := ls.logWith(LogSubsysDiscovery).With("endpoint_id", "x0c0s0b0")
+	dlog.Log(LOG_DEBUG, "not logged")
+	dlog.Log(LOG_NOTICE, "Discovering %s", "x0c0s0b0")
+	dlog.With("xname", "x0c0s0b0n0").LogAlways("Found %d", 1)
+	lines := logLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %v", lines)
+	}
+	if l := lines[0]; l["message"] != "Discovering x0c0s0b0" ||
+		l["level"] != "notice" || l["subsystem"] != LogSubsysDiscovery ||
+		l["endpoint_id"] != "x0c0s0b0" || l["xname"] != "" ||
+		!strings.HasPrefix(l["caller"], "logging_test.go:") || l["time"] == "" {
+		t.Errorf("Unexpected line %v", l)
+	}
+	if l := lines[1]; l["message"] != "Found 1" || l["level"] != "default" ||
+		l["endpoint_id"] != "x0c0s0b0" || l["xname"] != "x0c0s0b0n0" {
+		t.Errorf("Unexpected line %v", l)
+	}
+
+	// Only the discovery subsystem is turned up.
+	ls.setSubsysLogLevel(LogSubsysDiscovery, LOG_DEBUG, false)
+	dlog.Log(LOG_DEBUG, "logged")
+	ls.logWith(LogSubsysSCN).Log(LOG_DEBUG, "not logged")
+	if lines = logLines(t, &buf); len(lines) != 1 || lines[0]["message"] != "logged" {
+		t.Errorf("Expected only the discovery line, got %v", lines)
+	}
+	ls.setSubsysLogLevel(LogSubsysDiscovery, 0, true)
+	dlog.Log(LOG_DEBUG, "not logged")
+	if lines = logLines(t, &buf); len(lines) != 0 {
+		t.Errorf("Expected the default level again, got %v", lines)
+	}
+
+	// Request IDs from the middleware
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/service/ready", nil)
+	middleware.RequestID(ls.Logger(http.NotFoundHandler(), "test")).ServeHTTP(
+		httptest.NewRecorder(), req)
+	lines = logLines(t, &buf)
+	if len(lines) != 1 || lines[0]["subsystem"] != LogSubsysAPI ||
+		lines[0]["request_id"] == "" {
+		t.Errorf("Expected an api line with a request ID, got %v", lines)
+	}
+
+	// Plain log lines, e.g. from the database layer
+	ls.lg.Printf("Plain %s", "line")
+	ls.dbLogger().Printf("Query failed")
+	lines = logLines(t, &buf)
+	if len(lines) != 2 || lines[0]["message"] != "Plain line" ||
+		!strings.HasPrefix(lines[0]["caller"], "logging_test.go:") ||
+		lines[0]["subsystem"] != "" || lines[0]["level"] != "" ||
+		lines[1]["message"] != "Query failed" ||
+		lines[1]["subsystem"] != LogSubsysDB {
+		t.Errorf("Unexpected lines %v", lines)
+	}
+}
+
+func TestStructuredLogText(t *testing.T) {
+	var buf bytes.Buffer
+	ls := &SmD{lgLvl: LOG_DEFAULT, lg: log.New(&buf, "", 0)}
+	ls.logWith(LogSubsysSCN).With("url", "http://sub1").LogAlways("Failed")
+	if got := buf.String(); got != "Failed subsystem=scn url=http://sub1\n" {
+		t.Errorf("Unexpected line '%s'", got)
+	}
+	if ls.dbLogger() != ls.lg {
+		t.Errorf("Expected the database layer to share the text logger")
+	}
+}
+
+func TestDoLogLevel(t *testing.T) {
+	defer func() {
+		s.logLevels = LogLevelOverrides{}
+		results.SetLogLevel.Input.lvl = hmsds.LOG_DEFAULT
+	}()
+	s.logLevels = LogLevelOverrides{}
+
+	// Run in order, each builds on the levels set by the previous ones.
+	tests := []struct {
+		reqType  string
+		reqBody  string
+		expCode  int
+		expInRsp string
+	}{{
+		"GET", "",
+		http.StatusOK,
+		`"Subsystems":{"api":"default","db":"default","discovery":"default","scn":"default"}`,
+	}, {
+		"PUT", `{"Subsystems":{"discovery":"debug","db":"2"}}`,
+		http.StatusOK,
+		`"Subsystems":{"api":"default","db":"info","discovery":"debug","scn":"default"}`,
+	}, {
+		"PUT", `{}`,
+		http.StatusBadRequest,
+		"Subsystems is required",
+	}, {
+		"PUT", `{"Subsystems":{"scn":"debug","hwinv":"debug"}}`,
+		http.StatusBadRequest,
+		"unknown subsystem 'hwinv'",
+	}, {
+		"PUT", `{"Subsystems":{"scn":"loud"}}`,
+		http.StatusBadRequest,
+		"bad log level 'loud'",
+	}, {
+		"PUT", `{"Default":"debug","Subsystems":{"scn":"debug"}}`,
+		http.StatusBadRequest,
+		"Default can't be changed",
+	}, {
+		"PUT", `{"Default":"default","Subsystems":{"discovery":""}}`,
+		http.StatusOK,
+		`"Subsystems":{"api":"default","db":"info","discovery":"default","scn":"default"}`,
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest(test.reqType,
+			"https://localhost/hsm/v2/service/loglevel",
+			strings.NewReader(test.reqBody))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode ||
+			!strings.Contains(w.Body.String(), test.expInRsp) {
+			t.Errorf("Test %d: Expected %d '%s', got %d %s", i, test.expCode,
+				test.expInRsp, w.Code, w.Body.String())
+		}
+	}
+	if results.SetLogLevel.Input.lvl != hmsds.LOG_INFO {
+		t.Errorf("Expected the db level passed on, got %d",
+			results.SetLogLevel.Input.lvl)
+	}
+	if lvl := s.subsysLogLevel(LogSubsysSCN); lvl != LOG_DEFAULT {
+		t.Errorf("Expected failed PUTs to change nothing, got scn=%s", lvl)
+	}
+}
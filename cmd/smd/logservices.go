@@ -0,0 +1,198 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Redfish logs
+//
+//     GET /Inventory/LogServices/{xname}
+//     GET /Inventory/LogServices/{xname}/{logservice}/Entries[?top=<n>]
+//
+// list the LogServices (SEL, IML, Event log, ...) of a node or BMC, and
+// fetch the newest entries of one of them from the BMC, using the
+// credentials HSM already has.  Consumers then don't each need BMC
+// credentials and a Redfish client just to read a log.
+//
+// With SMD_RF_DISCOVER_LOG_SERVICES=true the LogServices are listed during
+// discovery and kept with the ComponentEndpoint.  Otherwise, or if none
+// were found, they are looked up on the BMC when asked for.
+///////////////////////////////////////////////////////////////////////////////
+
+// Entries returned if top isn't given
+const LogEntriesDefaultTop = 100
+
+// Output of GET /Inventory/LogServices/{xname}
+type LogServices struct {
+	ID                string               `json:"ID"`
+	RedfishEndpointID string               `json:"RedfishEndpointID"`
+	LogServices       []*rf.LogServiceInfo `json:"LogServices"`
+}
+
+// Output of GET /Inventory/LogServices/{xname}/{logservice}/Entries
+type LogEntries struct {
+	ID                string        `json:"ID"`
+	RedfishEndpointID string        `json:"RedfishEndpointID"`
+	LogService        string        `json:"LogService"`
+	Entries           []rf.LogEntry `json:"Entries"`
+}
+
+// Look up the ComponentEndpoint of a System or Manager and connect to its
+// RedfishEndpoint.  Sends an error and returns nils if it can't.
+func (s *SmD) logServiceEP(w http.ResponseWriter, xname string) (*sm.ComponentEndpoint, *rf.RedfishEP) {
+	cep, err := s.db.GetCompEndpointByID(xname)
+	if err != nil {
+		s.lg.Printf("logServiceEP(): Lookup failure: %s %s", xname, err)
+		sendJsonDBError(w, "", "", err)
+		return nil, nil
+	}
+	if cep == nil {
+		sendJsonError(w, http.StatusNotFound, "no such component endpoint")
+		return nil, nil
+	}
+	if cep.RedfishType != rf.ComputerSystemType && cep.RedfishType != rf.ManagerType {
+		sendJsonError(w, http.StatusBadRequest,
+			"component endpoint is not a ComputerSystem or Manager")
+		return nil, nil
+	}
+	ep, err := s.db.GetRFEndpointByID(cep.RfEndpointID)
+	if err != nil {
+		s.lg.Printf("logServiceEP(): Lookup failure: %s %s", cep.RfEndpointID,
+			err)
+		sendJsonDBError(w, "", "", err)
+		return nil, nil
+	}
+	if ep == nil {
+		sendJsonError(w, http.StatusNotFound, "no such redfish endpoint")
+		return nil, nil
+	}
+	rfEP, err := s.connectRedfishEP(ep)
+	if err != nil {
+		s.lg.Printf("logServiceEP(): %s: %s", ep.ID, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to set up connection to redfish endpoint")
+		return nil, nil
+	}
+	return cep, rfEP
+}
+
+// The LogServices kept from discovery, or else those on the BMC now.
+func (s *SmD) getLogServices(cep *sm.ComponentEndpoint, rfEP *rf.RedfishEP) ([]*rf.LogServiceInfo, error) {
+	var logs []*rf.LogServiceInfo
+	if cep.RedfishSystemInfo != nil {
+		logs = cep.RedfishSystemInfo.LogServices
+	} else if cep.RedfishManagerInfo != nil {
+		logs = cep.RedfishManagerInfo.LogServices
+	}
+	if len(logs) > 0 {
+		return logs, nil
+	}
+	return rfEP.GetLogServices(cep.OdataID)
+}
+
+// List the LogServices of a System or Manager
+func (s *SmD) doLogServicesGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	cep, rfEP := s.logServiceEP(w, xname)
+	if cep == nil {
+		return
+	}
+	logs, err := s.getLogServices(cep, rfEP)
+	if err != nil {
+		s.lg.Printf("doLogServicesGet(): %s: %s", xname, err)
+		sendJsonError(w, http.StatusBadGateway,
+			"failed to get LogServices from redfish endpoint: "+err.Error())
+		return
+	}
+	sendJsonObject(w, http.StatusOK, LogServices{
+		ID:                cep.ID,
+		RedfishEndpointID: cep.RfEndpointID,
+		LogServices:       logs,
+	})
+}
+
+// Fetch the newest entries of a LogService of a System or Manager
+func (s *SmD) doLogEntriesGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	name := chi.URLParam(r, "logservice")
+	top := LogEntriesDefaultTop
+	if val := r.URL.Query().Get("top"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			sendJsonError(w, http.StatusBadRequest,
+				"top must be a positive integer")
+			return
+		}
+		top = n
+	}
+	cep, rfEP := s.logServiceEP(w, xname)
+	if cep == nil {
+		return
+	}
+	logs, err := s.getLogServices(cep, rfEP)
+	if err != nil {
+		s.lg.Printf("doLogEntriesGet(): %s: %s", xname, err)
+		sendJsonError(w, http.StatusBadGateway,
+			"failed to get LogServices from redfish endpoint: "+err.Error())
+		return
+	}
+	var log *rf.LogServiceInfo
+	for _, ls := range logs {
+		if strings.EqualFold(ls.RedfishId, name) {
+			log = ls
+			break
+		}
+	}
+	if log == nil {
+		sendJsonError(w, http.StatusNotFound, "no such LogService: "+name)
+		return
+	}
+	entries, err := rfEP.GetLogEntries(log.EntriesURL, top)
+	if err != nil {
+		s.lg.Printf("doLogEntriesGet(): %s %s: %s", xname, log.EntriesURL, err)
+		sendJsonError(w, http.StatusBadGateway,
+			"failed to get log entries from redfish endpoint: "+err.Error())
+		return
+	}
+	sendJsonObject(w, http.StatusOK, LogEntries{
+		ID:                cep.ID,
+		RedfishEndpointID: cep.RfEndpointID,
+		LogService:        log.RedfishId,
+		Entries:           entries,
+	})
+}
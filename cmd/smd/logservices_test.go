@@ -0,0 +1,146 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+// BMC with a SEL on its node that is only found by asking it.
+func LogServicesHandler(w http.ResponseWriter, r *http.Request) {
+	payloads := map[string]string{
+		"/redfish/v1/Systems/Node0": `{"@odata.id": "/redfish/v1/Systems/Node0",
+			"LogServices": {"@odata.id": "/redfish/v1/Systems/Node0/LogServices"}}`,
+		"/redfish/v1/Systems/Node0/LogServices": `{"Members": [
+			{"@odata.id": "/redfish/v1/Systems/Node0/LogServices/SEL"}]}`,
+		"/redfish/v1/Systems/Node0/LogServices/SEL": `{"Id": "SEL",
+			"@odata.id": "/redfish/v1/Systems/Node0/LogServices/SEL",
+			"Entries": {"@odata.id": "/redfish/v1/Systems/Node0/LogServices/SEL/Entries"}}`,
+		"/redfish/v1/Systems/Node0/LogServices/SEL/Entries": `{"Members": [
+			{"Id": "1", "Created": "2026-10-01T00:00:00Z", "Message": "Power on"},
+			{"Id": "2", "Created": "2026-10-02T00:00:00Z", "Message": "Fan failed"}]}`,
+	}
+	if user, pw, _ := r.BasicAuth(); user != "root" || pw != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	payload, ok := payloads[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte(payload))
+}
+
+func TestLogServicesGet(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(LogServicesHandler))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+
+	readVault := s.readVault
+	defer func() {
+		s.readVault = readVault
+		results.GetCompEndpointByID.Return.entry = nil
+		results.GetRFEndpointByID.Return.entry = nil
+	}()
+	s.readVault = false
+	results.GetRFEndpointByID.Return.entry = &sm.RedfishEndpoint{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID: "x3000c0s9b0", Type: "NodeBMC", FQDN: u.Host, User: "root",
+			Password: "secret", Enabled: true}}
+	node := &sm.ComponentEndpoint{
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x3000c0s9b0n0", RedfishType: rf.ComputerSystemType,
+			OdataID: "/redfish/v1/Systems/Node0", RfEndpointID: "x3000c0s9b0"},
+		RedfishSystemInfo: &rf.ComponentSystemInfo{},
+	}
+	chassis := &sm.ComponentEndpoint{
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x3000c0s9e0", RedfishType: rf.ChassisType,
+			RfEndpointID: "x3000c0s9b0"},
+	}
+	// Already discovered, but pointing to a log that's gone
+	discovered := &sm.ComponentEndpoint{
+		ComponentDescription: node.ComponentDescription,
+		RedfishSystemInfo: &rf.ComponentSystemInfo{
+			LogServices: []*rf.LogServiceInfo{{RedfishId: "IML",
+				EntriesURL: "/redfish/v1/Systems/Node0/LogServices/IML/Entries"}}},
+	}
+
+	tests := []struct {
+		cep     *sm.ComponentEndpoint
+		path    string
+		expCode int
+		expBody interface{}
+	}{{
+		node, "/x3000c0s9b0n0", http.StatusOK,
+		&LogServices{ID: "x3000c0s9b0n0", RedfishEndpointID: "x3000c0s9b0",
+			LogServices: []*rf.LogServiceInfo{{RedfishId: "SEL",
+				URL:        "/redfish/v1/Systems/Node0/LogServices/SEL",
+				EntriesURL: "/redfish/v1/Systems/Node0/LogServices/SEL/Entries"}}},
+	}, {
+		node, "/x3000c0s9b0n0/sel/Entries?top=1", http.StatusOK,
+		&LogEntries{ID: "x3000c0s9b0n0", RedfishEndpointID: "x3000c0s9b0",
+			LogService: "SEL", Entries: []rf.LogEntry{{Id: "2",
+				Created: "2026-10-02T00:00:00Z", Message: "Fan failed"}}},
+	}, {
+		node, "/x3000c0s9b0n0/SEL/Entries?top=0", http.StatusBadRequest, nil,
+	}, {
+		node, "/x3000c0s9b0n0/Crash/Entries", http.StatusNotFound, nil,
+	}, {
+		chassis, "/x3000c0s9e0", http.StatusBadRequest, nil,
+	}, {
+		nil, "/x3000c0s8b0n0", http.StatusNotFound, nil,
+	}, {
+		discovered, "/x3000c0s9b0n0/IML/Entries", http.StatusBadGateway, nil,
+	}}
+	for i, test := range tests {
+		results.GetCompEndpointByID.Return.entry = test.cep
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Inventory/LogServices"+test.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %d: Expected code %d, got %d %s", i, test.expCode,
+				w.Code, w.Body.String())
+			continue
+		}
+		if test.expBody == nil {
+			continue
+		}
+		got := reflect.New(reflect.TypeOf(test.expBody).Elem()).Interface()
+		json.Unmarshal(w.Body.Bytes(), got)
+		if !reflect.DeepEqual(got, test.expBody) {
+			t.Errorf("Test %d: Expected %+v, got %s", i, test.expBody,
+				w.Body.String())
+		}
+	}
+}
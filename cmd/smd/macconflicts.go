@@ -0,0 +1,300 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Duplicate MAC addresses
+//
+// The same MAC address on more than one component is usually a sign of a
+// cloned image or a miswired interface.  Component ethernet interfaces are
+// keyed by MAC, so only one component can own a MAC in the database and the
+// last one discovered silently takes it over.  Instead, each discovery
+// records which of its components claim which MACs, along with the owner in
+// the database at the time, replacing the claims from the RedfishEndpoint's
+// last discovery.  MACs claimed by more than one component are reported by
+// GET /Inventory/EthernetInterfaces/Conflicts, and the components involved
+// get a Warning Flag, sent as an SCN, until the conflict goes away.  Claims
+// are kept in memory.
+///////////////////////////////////////////////////////////////////////////////
+
+// A component that was discovered with, or had in the database, a MAC.
+type MACClaim struct {
+	ComponentID       string `json:"ComponentID"`
+	RedfishEndpointID string `json:"RedfishEndpointID,omitempty"`
+	Description       string `json:"Description,omitempty"`
+	LastSeen          string `json:"LastSeen"`
+}
+
+// A MAC address claimed by more than one component.  ID is the MAC as used
+// for the component's EthernetInterface ID.
+type MACConflict struct {
+	ID         string     `json:"ID"`
+	MACAddress string     `json:"MACAddress"`
+	Components []MACClaim `json:"Components"`
+}
+
+// Output of GET /Inventory/EthernetInterfaces/Conflicts
+type MACConflictArray struct {
+	Conflicts []MACConflict `json:"Conflicts"`
+}
+
+type MACIndex struct {
+	lock   sync.Mutex
+	claims map[string]map[string]*MACClaim // by MAC ID, component
+	macs   map[string]string               // MACAddress for each MAC ID
+}
+
+// Components that currently share a MAC with another component.
+func (mi *MACIndex) conflicted() map[string]bool {
+	comps := make(map[string]bool)
+	for _, byComp := range mi.claims {
+		if len(byComp) > 1 {
+			for id := range byComp {
+				comps[id] = true
+			}
+		}
+	}
+	return comps
+}
+
+// Replace the claims of RedfishEndpoint epID with those from its latest
+// discovery, ceis.  owners are the database entries for those MACs from
+// before the discovery was stored, with the RedfishEndpoint of each owner
+// in ownerEPs.  Returns the components that are newly in conflict and
+// those that no longer are.
+func (mi *MACIndex) update(
+	epID string,
+	ceis, owners []*sm.CompEthInterfaceV2,
+	ownerEPs map[string]string,
+	now time.Time,
+) (added, cleared []string) {
+	mi.lock.Lock()
+	defer mi.lock.Unlock()
+
+	if mi.claims == nil {
+		mi.claims = make(map[string]map[string]*MACClaim)
+		mi.macs = make(map[string]string)
+	}
+	before := mi.conflicted()
+	for mac, byComp := range mi.claims {
+		for id, c := range byComp {
+			if c.RedfishEndpointID == epID {
+				delete(byComp, id)
+			}
+		}
+		if len(byComp) == 0 {
+			delete(mi.claims, mac)
+			delete(mi.macs, mac)
+		}
+	}
+	ts := now.UTC().Format(time.RFC3339)
+	add := func(cei *sm.CompEthInterfaceV2, epID string) {
+		byComp := mi.claims[cei.ID]
+		if byComp == nil {
+			byComp = make(map[string]*MACClaim)
+			mi.claims[cei.ID] = byComp
+			mi.macs[cei.ID] = cei.MACAddr
+		}
+		byComp[cei.CompID] = &MACClaim{
+			ComponentID:       cei.CompID,
+			RedfishEndpointID: epID,
+			Description:       cei.Desc,
+			LastSeen:          ts,
+		}
+	}
+	for _, cei := range ceis {
+		add(cei, epID)
+	}
+	// Owners from the endpoint itself are about to be overwritten.
+	for _, cei := range owners {
+		if ownerEP := ownerEPs[cei.CompID]; ownerEP != epID {
+			if _, ok := mi.claims[cei.ID][cei.CompID]; !ok {
+				add(cei, ownerEP)
+			}
+		}
+	}
+
+	after := mi.conflicted()
+	for id := range after {
+		if !before[id] {
+			added = append(added, id)
+		}
+	}
+	for id := range before {
+		if !after[id] {
+			cleared = append(cleared, id)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(cleared)
+	return added, cleared
+}
+
+// Forget everything, e.g. when all RedfishEndpoints are deleted.
+func (mi *MACIndex) reset() {
+	mi.lock.Lock()
+	defer mi.lock.Unlock()
+	mi.claims = nil
+	mi.macs = nil
+}
+
+// Current conflicts, sorted by MAC and component.
+func (mi *MACIndex) conflicts() []MACConflict {
+	mi.lock.Lock()
+	defer mi.lock.Unlock()
+
+	conflicts := []MACConflict{}
+	for mac, byComp := range mi.claims {
+		if len(byComp) < 2 {
+			continue
+		}
+		conflict := MACConflict{ID: mac, MACAddress: mi.macs[mac]}
+		for _, c := range byComp {
+			conflict.Components = append(conflict.Components, *c)
+		}
+		sort.Slice(conflict.Components, func(i, j int) bool {
+			return conflict.Components[i].ComponentID <
+				conflict.Components[j].ComponentID
+		})
+		conflicts = append(conflicts, conflict)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ID < conflicts[j].ID
+	})
+	return conflicts
+}
+
+// Get the current database owners of the MACs in ceis that belong to other
+// components, and the RedfishEndpoint of each of those components.  Must
+// be called before the discovered interfaces are stored.
+func (s *SmD) getMACOwners(ceis []*sm.CompEthInterfaceV2) ([]*sm.CompEthInterfaceV2, map[string]string, error) {
+	if len(ceis) == 0 {
+		return nil, nil, nil
+	}
+	ids := make([]string, 0, len(ceis))
+	claimed := make(map[string]string, len(ceis))
+	for _, cei := range ceis {
+		ids = append(ids, cei.ID)
+		claimed[cei.ID] = cei.CompID
+	}
+	dbCEIs, err := s.db.GetCompEthInterfaceFilter(hmsds.CEI_IDs(ids))
+	if err != nil {
+		return nil, nil, err
+	}
+	owners := []*sm.CompEthInterfaceV2{}
+	ownerIDs := []string{}
+	for _, cei := range dbCEIs {
+		if cei.CompID != "" && cei.CompID != claimed[cei.ID] {
+			owners = append(owners, cei)
+			ownerIDs = append(ownerIDs, cei.CompID)
+		}
+	}
+	ownerEPs := make(map[string]string)
+	if len(owners) == 0 {
+		return owners, ownerEPs, nil
+	}
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{ID: ownerIDs})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cep := range ceps {
+		ownerEPs[cep.ID] = cep.RfEndpointID
+	}
+	return owners, ownerEPs, nil
+}
+
+// Record the MACs discovered for RedfishEndpoint epID, and flag the
+// components whose MAC conflicts started or ended.
+func (s *SmD) updateMACConflicts(
+	epID string,
+	ceis, owners []*sm.CompEthInterfaceV2,
+	ownerEPs map[string]string,
+) {
+	added, cleared := s.macIndex.update(epID, ceis, owners, ownerEPs, time.Now())
+	if len(added) > 0 {
+		s.LogAlways("Warning: duplicate MAC addresses discovered for %v", added)
+	}
+	for _, id := range added {
+		s.setMACConflictFlag(id, true)
+	}
+	for _, id := range cleared {
+		s.setMACConflictFlag(id, false)
+	}
+}
+
+// Set the Flag of a component to Warning for a MAC conflict, or back to OK
+// once it is resolved.  Flags that are worse than Warning, or that are
+// Warning for another reason, e.g. a cooling fault, are left alone.
+func (s *SmD) setMACConflictFlag(id string, conflict bool) {
+	comp, err := s.db.GetComponentByID(id)
+	if err != nil {
+		s.LogAlways("setMACConflictFlag(%s): %s", id, err)
+		return
+	} else if comp == nil {
+		return
+	}
+	newFlag := base.FlagOK.String()
+	if conflict {
+		if comp.Flag != base.FlagOK.String() {
+			return
+		}
+		newFlag = base.FlagWarning.String()
+	} else if comp.Flag != base.FlagWarning.String() ||
+		len(s.coolingFaults.active(id)) > 0 {
+		return
+	}
+	scnIDs, err := s.dbUpdateCompFlagOnly(
+		s.db.WithActor(CompHistActorMACConflict), []string{id}, newFlag,
+		new(hmsds.PartInfo))
+	if err != nil {
+		s.LogAlways("setMACConflictFlag(%s): %s", id, err)
+		return
+	}
+	if len(scnIDs) != 0 {
+		scn := NewJobSCN(scnIDs, base.Component{
+			State: comp.State,
+			Flag:  newFlag,
+		}, s)
+		s.wp.Queue(scn)
+	}
+}
+
+// Get the MAC addresses discovered on more than one component
+func (s *SmD) doMACConflictsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, MACConflictArray{
+		Conflicts: s.macIndex.conflicts(),
+	})
+}
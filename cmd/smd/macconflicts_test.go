@@ -0,0 +1,154 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func testCEI(mac, comp string) *sm.CompEthInterfaceV2 {
+	cei, _ := sm.NewCompEthInterfaceV2("", mac, comp, nil)
+	return cei
+}
+
+func TestMACIndex(t *testing.T) {
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	var mi MACIndex
+
+	// No duplicates
+	added, cleared := mi.update("x0c0s0b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0"),
+	}, nil, nil, now)
+	if len(added) != 0 || len(cleared) != 0 || len(mi.conflicts()) != 0 {
+		t.Errorf("Unexpected conflict: %v %v %v", added, cleared, mi.conflicts())
+	}
+
+	// Same MAC discovered on another endpoint, which takes over the MAC in
+	// the database.
+	added, cleared = mi.update("x0c0s1b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s1b0n0"),
+	}, []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0"),
+	}, map[string]string{"x0c0s0b0n0": "x0c0s0b0"}, now)
+	if exp := []string{"x0c0s0b0n0", "x0c0s1b0n0"}; !reflect.DeepEqual(added, exp) ||
+		len(cleared) != 0 {
+		t.Errorf("Expected %v added, got %v %v", exp, added, cleared)
+	}
+	conflicts := mi.conflicts()
+	if len(conflicts) != 1 || conflicts[0].ID != "a4bf01000001" ||
+		conflicts[0].MACAddress != "a4:bf:01:00:00:01" ||
+		len(conflicts[0].Components) != 2 ||
+		conflicts[0].Components[0].RedfishEndpointID != "x0c0s0b0" ||
+		conflicts[0].Components[1].RedfishEndpointID != "x0c0s1b0" {
+		t.Errorf("Unexpected conflicts: %v", conflicts)
+	}
+
+	// Rediscovering either endpoint with the same MACs changes nothing
+	added, cleared = mi.update("x0c0s0b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0"),
+	}, []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s1b0n0"),
+	}, map[string]string{"x0c0s1b0n0": "x0c0s1b0"}, now)
+	if len(added) != 0 || len(cleared) != 0 || len(mi.conflicts()) != 1 {
+		t.Errorf("Unexpected change: %v %v %v", added, cleared, mi.conflicts())
+	}
+
+	// The first endpoint's MAC is fixed
+	added, cleared = mi.update("x0c0s0b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:02", "x0c0s0b0n0"),
+	}, nil, nil, now)
+	if exp := []string{"x0c0s0b0n0", "x0c0s1b0n0"}; !reflect.DeepEqual(cleared, exp) ||
+		len(added) != 0 || len(mi.conflicts()) != 0 {
+		t.Errorf("Expected %v cleared, got %v %v", exp, added, cleared)
+	}
+
+	// Owner from the same endpoint (e.g. MAC moved to another node) is
+	// not a conflict.
+	added, _ = mi.update("x0c0s0b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:02", "x0c0s0b0n1"),
+	}, []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:02", "x0c0s0b0n0"),
+	}, map[string]string{"x0c0s0b0n0": "x0c0s0b0"}, now)
+	if len(added) != 0 {
+		t.Errorf("Unexpected conflict: %v", added)
+	}
+}
+
+func TestMACConflictsGet(t *testing.T) {
+	defer func() {
+		results.GetComponentByID.Return.id = nil
+		results.UpdateCompFlagOnly.Return.rowsAffected = 0
+		s.macIndex = MACIndex{}
+	}()
+	results.GetComponentByID.Return.id = &base.Component{
+		ID: "x0c0s0b0n0", State: base.StateOn.String(),
+		Flag: base.FlagOK.String()}
+	results.UpdateCompFlagOnly.Return.rowsAffected = 1
+	results.UpdateCompFlagOnly.Input.flag = ""
+
+	s.updateMACConflicts("x0c0s1b0", []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s1b0n0"),
+	}, []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0"),
+	}, map[string]string{"x0c0s0b0n0": "x0c0s0b0"})
+	if results.UpdateCompFlagOnly.Input.flag != base.FlagWarning.String() {
+		t.Errorf("Expected Warning flag, got '%s'",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/EthernetInterfaces/Conflicts", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Response code was %v; want 200", w.Code)
+	}
+	var conflicts MACConflictArray
+	if err := json.Unmarshal(w.Body.Bytes(), &conflicts); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	if len(conflicts.Conflicts) != 1 ||
+		len(conflicts.Conflicts[0].Components) != 2 {
+		t.Errorf("Unexpected conflicts: %s", w.Body.String())
+	}
+
+	// Deleting the endpoint resolves it
+	results.GetComponentByID.Return.id.Flag = base.FlagWarning.String()
+	results.UpdateCompFlagOnly.Input.flag = ""
+	s.updateMACConflicts("x0c0s1b0", nil, nil, nil)
+	if results.UpdateCompFlagOnly.Input.flag != base.FlagOK.String() {
+		t.Errorf("Expected OK flag, got '%s'",
+			results.UpdateCompFlagOnly.Input.flag)
+	}
+	if c := s.macIndex.conflicts(); len(c) != 0 {
+		t.Errorf("Unexpected conflicts: %v", c)
+	}
+}
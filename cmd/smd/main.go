@@ -41,15 +41,17 @@ import (
 	"github.com/Cray-HPE/hms-certs/pkg/hms_certs"
 	compcreds "github.com/Cray-HPE/hms-compcredentials"
 	sstorage "github.com/Cray-HPE/hms-securestorage"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
 	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	"github.com/OpenCHAMI/smd/v2/internal/hbtdapi"
 	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
-	"github.com/OpenCHAMI/smd/v2/internal/pgmigrate"
 	"github.com/OpenCHAMI/smd/v2/internal/slsapi"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 	"github.com/go-chi/chi/v5"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
 )
 
@@ -72,7 +74,8 @@ const (
 	SCNMAP_SUBROLE  = 2
 	SCNMAP_SWSTATUS = 3
 	SCNMAP_STATE    = 4
-	SCNMAP_MAX      = 5
+	SCNMAP_RESERVED = 5
+	SCNMAP_MAX      = 6
 )
 
 type SCNUrl struct {
@@ -101,19 +104,87 @@ type SmD struct {
 	dbPortStr string
 	dbPort    int
 	dbOpts    string
+	dbPool    hmsds.DBPoolConfig
 
 	logDir           string
 	tlsCert          string
 	tlsKey           string
 	proxyURL         string
 	httpListen       string
+	grpcListen       string
 	msgbusListen     string
 	logLevelIn       int
 	msgbusConfig     MsgBusConfigWrapper
 	msgbusHandle     MsgbusHandleWrapper
 	hwInvHistAgeMax  int
+	tombstoneKeep    time.Duration
+	hbWarn           time.Duration
+	hbStandby        time.Duration
+	bmcProbe         BMCProber
+	rfMaxRespBytes   int64
+	rfMaxArrayLen    int
+	rfThermal        bool
+	rfCertificates   bool
+	rfLogServices    bool
+	rfSecureBoot     bool
+	rfSessionAuth    bool
+	rfRetryPolicy    rf.RetryPolicy
+	rfHTTPOptions    rf.HTTPOptions
+	rfSchemaCheck    bool
+	rfSchemaDir      string
+	rfSNMPCommunity  string
+	discBrkPolicy    DiscoveryBreakerPolicy
+	discBreaker      *DiscoveryBreaker
+	compCountPolicy  CompCountPolicy
+	compCounts       *CompCountTracker
+	discConcurrency  int
+	discJobKeep      int
+	discJobs         *DiscoveryJobs
+	rfepDeleteTokens RFEPDeleteTokens
+	scnStormPolicy   SCNStormPolicy
+	scnStorm         *SCNStormDetector
+	scnDelivPolicy   SCNDeliveryPolicy
+	scnDelivery      *SCNDelivery
+	scnCoalPolicy    SCNCoalescePolicy
+	scnCoalesce      *SCNCoalescer
+	compCachePolicy  CompCachePolicy
+	cluster          Cluster
+	compCache        *CompCache
+	resExpiry        ReservationExpiry
+	consistencyIntvl time.Duration
+	consistency      ConsistencyChecker
+	vendorProfPath   string
+	vendorProfiles   map[string]*rf.VendorProfile
+	readOnly         ReadOnlyMode
+	rbac             RBAC
+	selfTestOn       bool
+	selfTestID       string
+	selfTest         SelfTestState
+	telemetryURL     string
+	telemetryCtx     string
+	eventCollector   string
+	eventCtx         string
+	eventSecret      string
+	telemetry        TelemetryStore
+	certStatus       CertStatusStore
+	credRotateStatus CredRotateStatusStore
+	metrics          Metrics
+	logFormat        string
+	logLevels        LogLevelOverrides
+	zlog             *zerolog.Logger // Set if logging JSON
+	coolingFaults    CoolingFaultTracker
+	hwFaults         HardwareFaultTracker
+	compStream       CompStream
+	eventBusURL      string
+	eventBus         EventBus
+	eventRedisc      EventRediscoverer
+	eventRediscDelay time.Duration
+	discChanges      DiscoveryChangeStore
+	hwChangeURL      string
+	macIndex         MACIndex
 	smapCompEP       *SyncMap
 	genTestPayloads  string
+	genTestMinimize  bool
 	disableDiscovery bool
 	openchami        bool
 	zerolog          bool
@@ -124,16 +195,41 @@ type SmD struct {
 	valuesBaseV2        string
 	stateBaseV2         string
 	componentsBaseV2    string
+	deletedCompBaseV2   string
 	redfishEPBaseV2     string
+	deletedRFEPBaseV2   string
 	compEPBaseV2        string
 	serviceEPBaseV2     string
 	compEthIntBaseV2    string
 	hsnIntBaseV2        string
 	hwinvByLocBaseV2    string
 	hwinvByFRUBaseV2    string
+	sparePartsBaseV2    string
+	thermalBaseV2       string
 	invDiscoverBaseV2   string
 	invDiscStatusBaseV2 string
+	invDiscHoldBaseV2   string
+	invDiscSchedBaseV2  string
+	invDiscPlanBaseV2   string
+	invDiscJobBaseV2    string
+	invExportBaseV2     string
+	invConsistBaseV2    string
+	invReconcileBaseV2  string
+	snapshotBaseV2      string
+	vendorProfBaseV2    string
+	fallbackCredBaseV2  string
+	telemetryBaseV2     string
+	certBaseV2          string
+	credBaseV2          string
+	firmwareBaseV2      string
+	logServiceBaseV2    string
+	bootConfigBaseV2    string
+	eventSubBaseV2      string
+	eventsBaseV2        string
 	nodeMapBaseV2       string
+	nidPolicyBaseV2     string
+	nidAssignBaseV2     string
+	xnamesBaseV2        string
 	subscriptionBaseV2  string
 	groupsBaseV2        string
 	partitionsBaseV2    string
@@ -159,8 +255,9 @@ type SmD struct {
 	//       the one writing credentials to Vault.
 	writeVault bool
 	readVault  bool
+	credStore  string // SMD_CRED_STORE backend
 	ss         sstorage.SecureStorage
-	ccs        *compcreds.CompCredStore
+	ccs        CredentialStore
 
 	// Job Sync
 	jobLock     sync.Mutex
@@ -176,6 +273,10 @@ type SmD struct {
 	tokenAuth *jwtauth.JWTAuth
 	jwksURL   string
 
+	// Component-scoped tokens for self-reporting, nil if not enabled
+	compTokenAuth *jwtauth.JWTAuth
+	compTokenTTL  time.Duration
+
 	httpClient *retryablehttp.Client
 }
 
@@ -314,6 +415,16 @@ func addSCNMapSubscription(subMap *SCNSubMap, sub *sm.SCNSubscription) {
 		}
 		subMap[SCNMAP_STATE][state] = addSCNUrl(subMap[SCNMAP_STATE][state], sub.Url)
 	}
+	for _, rs := range sub.Reservations {
+		res := strings.ToLower(rs)
+		if subMap[SCNMAP_RESERVED] == nil {
+			subMap[SCNMAP_RESERVED] = make(map[string][]SCNUrl, 0)
+		}
+		if _, ok := subMap[SCNMAP_RESERVED][res]; !ok {
+			subMap[SCNMAP_RESERVED][res] = make([]SCNUrl, 0, 1)
+		}
+		subMap[SCNMAP_RESERVED][res] = addSCNUrl(subMap[SCNMAP_RESERVED][res], sub.Url)
+	}
 }
 
 // Remove a SCN subscription from the specified SCN subscription map
@@ -337,6 +448,10 @@ func removeSCNMapSubscription(subMap *SCNSubMap, sub *sm.SCNSubscription) {
 		state := strings.ToLower(st)
 		subMap[SCNMAP_STATE][state] = removeSCNUrl(subMap[SCNMAP_STATE][state], sub.Url)
 	}
+	for _, rs := range sub.Reservations {
+		res := strings.ToLower(rs)
+		subMap[SCNMAP_RESERVED][res] = removeSCNUrl(subMap[SCNMAP_RESERVED][res], sub.Url)
+	}
 }
 
 // Spin off a thread to periodically refresh the SCN subscription tables.
@@ -369,6 +484,10 @@ func (s *SmD) SCNSubscriptionRefresh() {
 func (s *SmD) CompReservationCleanup() {
 	go func() {
 		for {
+			if !s.cluster.isLeader() {
+				time.Sleep(30 * time.Second)
+				continue
+			}
 			xnames, err := s.db.DeleteCompReservationsExpired()
 			if err != nil {
 				s.LogAlways("CompReservationCleanup(): Lookup failure: %s", err)
@@ -376,6 +495,7 @@ func (s *SmD) CompReservationCleanup() {
 			} else {
 				if len(xnames) > 0 {
 					s.LogAlways("CompReservationCleanup(): Release %d expired component reservations for: %v", len(xnames), xnames)
+					s.reservationsExpired(xnames)
 				}
 				time.Sleep(30 * time.Second)
 			}
@@ -385,10 +505,14 @@ func (s *SmD) CompReservationCleanup() {
 
 // Jobs running locally in an intance of HSM can become orphaned if that
 // instance of HSM dies. This spins off a goroutine to periodically check for
-// orphaned jobs and picks them up.
+// orphaned jobs and picks them up.  Only the leader does, see cluster.go.
 func (s *SmD) JobSync() {
 	go func() {
 		for {
+			if !s.cluster.isLeader() {
+				time.Sleep(20 * time.Second)
+				continue
+			}
 			failed := false
 			numNewJobs := 0
 			// Take on orphaned Jobs
@@ -435,12 +559,12 @@ func (s *SmD) JobSync() {
 
 // Discovery jobs running locally in an intance of HSM can become orphaned if that
 // instance of HSM dies. This spins off a goroutine to periodically check for
-// orphaned discovery jobs and picks them up.
-// TODO: Close potential race condition between GetRFEndpointsFilter() and when
-//
-//	discoverFromEndpoint() updates the DiscInfo.LastAttempt where another HSM
-//	instance could run GetRFEndpointsFilter() to get the same list of orphaned
-//	EPs. For now, this should be rare.
+// orphaned discovery jobs and picks them up.  Each orphaned endpoint is only
+// picked up by the instance that owns it, see cluster.go.  Without
+// SMD_CLUSTER_LEASE_SECS, another HSM instance could run
+// GetRFEndpointsFilter() before discoverFromEndpoint() updates
+// DiscInfo.LastAttempt and pick up the same endpoints, though this should be
+// rare.
 func (s *SmD) DiscoverySync() {
 	go func() {
 		for {
@@ -456,7 +580,8 @@ func (s *SmD) DiscoverySync() {
 					lastAttempt, _ := time.Parse("2006-01-02T15:04:05.000000Z07:00", ep.DiscInfo.LastAttempt)
 					// Consider discovery jobs that have not updated
 					// in 30 minutes to have been orphaned.
-					if time.Since(lastAttempt) >= (time.Minute * 30) {
+					if time.Since(lastAttempt) >= (time.Minute*30) &&
+						s.cluster.ownsEndpoint(ep.ID) {
 						// Take on orphaned discovery job
 						go s.discoverFromEndpoint(ep, 0, true)
 						numNewJobs++
@@ -568,12 +693,16 @@ func (s *SmD) parseCmdLine(openchamiDefault, zeroLogDefault bool) {
 	flag.StringVar(&s.dbDSN, "db-dsn", "", "DSN to connect to database")
 	flag.StringVar(&s.httpListen, "http-listen", httpListenDefault,
 		"HTTP server IP + port binding")
+	flag.StringVar(&s.grpcListen, "grpc-listen", "",
+		"gRPC server IP + port binding, no gRPC server if unset (default)")
 	flag.StringVar(&s.tlsCert, "tls-cert", "/etc/cert.pem",
 		"TLS cert file")
 	flag.StringVar(&s.tlsKey, "tls-key", "/etc/key.pem",
 		"TLS key file")
 	flag.IntVar(&s.logLevelIn, "log", int(LOG_DEFAULT),
 		"Log level: 0 to 4")
+	flag.StringVar(&s.logFormat, "log-format", "",
+		"Log format: 'text' (default) or 'json'")
 	flag.StringVar(&s.dbType, "dbtype", "",
 		"Database type: 'mysql' (default) or 'postgres'")
 	flag.StringVar(&s.dbName, "dbname", "", "Database name (default 'hmsds'")
@@ -583,6 +712,9 @@ func (s *SmD) parseCmdLine(openchamiDefault, zeroLogDefault bool) {
 	flag.StringVar(&s.dbOpts, "dbopts", "", "Database options string")
 	flag.StringVar(&s.jwksURL, "jwks-url", "", "Set the JWKS URL to fetch public key for validation")
 	flag.BoolVar(&applyMigrations, "migrate", false, "Apply all database migrations before starting")
+	flag.IntVar(&migrateTo, "migrate-to", -1, "Migration to go up or down to with -migrate (default the last)")
+	flag.BoolVar(&migrateDryRun, "migrate-dry-run", false, "Print the DDL -migrate would run, and exit")
+	flag.StringVar(&migrationsDir, "migrations-dir", "", "Directory of migrations to use instead of the built-in ones")
 	flag.BoolVar(&s.disableDiscovery, "disable-discovery", false, "Disable discovery-related subroutines")
 	flag.BoolVar(&s.openchami, "openchami", openchamiDefault, "Enabled OpenCHAMI features")
 	flag.BoolVar(&s.zerolog, "zerolog", zeroLogDefault, "Enabled zerolog")
@@ -675,10 +807,75 @@ func (s *SmD) parseCmdLine(openchamiDefault, zeroLogDefault bool) {
 		s.genTestPayloads = val
 	}
 	// Env var only
+	envvar = "GEN_TEST_PAYLOADS_MINIMIZE"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env GEN_TEST_PAYLOADS_MINIMIZE - '%s'\n", val)
+		} else {
+			s.genTestMinimize = b
+		}
+	}
+	// Env var only
 	envvar = "SMD_DBPASS"
 	if val := os.Getenv(envvar); val != "" {
 		s.dbPass = val
 	}
+	envvar = "SMD_DB_MAX_OPEN_CONNS"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			fmt.Printf("Bad SMD_DB_MAX_OPEN_CONNS '%s': Must be a number of connections, -1 for no limit", val)
+		} else {
+			s.dbPool.MaxOpenConns = n
+		}
+	}
+	envvar = "SMD_DB_MAX_IDLE_CONNS"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			fmt.Printf("Bad SMD_DB_MAX_IDLE_CONNS '%s': Must be a number of connections, -1 for none", val)
+		} else {
+			s.dbPool.MaxIdleConns = n
+		}
+	}
+	envvar = "SMD_DB_CONN_MAX_LIFETIME_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_DB_CONN_MAX_LIFETIME_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.dbPool.ConnMaxLifetime = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_DB_CONN_MAX_IDLE_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_DB_CONN_MAX_IDLE_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.dbPool.ConnMaxIdleTime = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_DB_STATEMENT_TIMEOUT_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_DB_STATEMENT_TIMEOUT_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.dbPool.StatementTimeout = time.Duration(secs) * time.Second
+		}
+	}
+	s.dbPool.SlowTxThreshold = DefaultDBSlowTxThreshold
+	envvar = "SMD_DB_SLOW_TX_MS"
+	if val := os.Getenv(envvar); val != "" {
+		ms, err := strconv.Atoi(val)
+		if err != nil || ms < 0 {
+			fmt.Printf("Bad SMD_DB_SLOW_TX_MS '%s': Must be 0+ milliseconds", val)
+		} else {
+			s.dbPool.SlowTxThreshold = time.Duration(ms) * time.Millisecond
+		}
+	}
 
 	// Set dbName
 	if s.dbName == "" {
@@ -696,6 +893,24 @@ func (s *SmD) parseCmdLine(openchamiDefault, zeroLogDefault bool) {
 		os.Exit(1)
 	}
 
+	s.credStore = credStoreVault
+	envvar = "SMD_CRED_STORE"
+	if val := os.Getenv(envvar); val != "" {
+		switch store := strings.ToLower(val); store {
+		case credStoreVault:
+			s.credStore = store
+		case credStoreFile, credStoreK8s:
+			// Don't also need the Vault flags to use the store asked for.
+			// They can still turn reading or writing off.
+			s.credStore = store
+			s.readVault = true
+			s.writeVault = true
+		default:
+			fmt.Printf("Bad SMD_CRED_STORE '%s': Must be vault, file or kubernetes\n", val)
+			os.Exit(1)
+		}
+	}
+
 	envvar = "SMD_WVAULT"
 	if val := os.Getenv(envvar); val != "" {
 		b, err := strconv.ParseBool(val)
@@ -729,6 +944,662 @@ func (s *SmD) parseCmdLine(openchamiDefault, zeroLogDefault bool) {
 		}
 	}
 
+	s.tombstoneKeep = DefaultTombstoneKeep
+	envvar = "SMD_TOMBSTONE_RETENTION_DAYS"
+	if val := os.Getenv(envvar); val != "" {
+		days, err := strconv.Atoi(val)
+		if err != nil || days < 0 {
+			fmt.Printf("Bad SMD_TOMBSTONE_RETENTION_DAYS '%s': Must be 0+ days", val)
+		} else {
+			s.tombstoneKeep = time.Duration(days) * 24 * time.Hour
+		}
+	}
+
+	s.hbWarn = DefaultHeartbeatWarn
+	envvar = "SMD_HEARTBEAT_WARN_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_HEARTBEAT_WARN_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.hbWarn = time.Duration(secs) * time.Second
+		}
+	}
+
+	s.hbStandby = DefaultHeartbeatStandby
+	envvar = "SMD_HEARTBEAT_STANDBY_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_HEARTBEAT_STANDBY_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.hbStandby = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_BMC_PROBE_INTERVAL_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_BMC_PROBE_INTERVAL_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.bmcProbe.Interval = time.Duration(secs) * time.Second
+		}
+	}
+
+	s.bmcProbe.Timeout = DefaultBMCProbeTimeout
+	envvar = "SMD_BMC_PROBE_TIMEOUT_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_BMC_PROBE_TIMEOUT_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.bmcProbe.Timeout = time.Duration(secs) * time.Second
+		}
+	}
+
+	s.bmcProbe.Failures = DefaultBMCProbeFailures
+	envvar = "SMD_BMC_PROBE_FAILURES"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_BMC_PROBE_FAILURES '%s': Must be 1+ probes", val)
+		} else {
+			s.bmcProbe.Failures = n
+		}
+	}
+
+	s.bmcProbe.Mode = BMCProbeHTTPS
+	envvar = "SMD_BMC_PROBE_MODE"
+	if val := os.Getenv(envvar); val != "" {
+		switch strings.ToLower(val) {
+		case BMCProbeHTTPS, BMCProbeTCP:
+			s.bmcProbe.Mode = strings.ToLower(val)
+		default:
+			fmt.Printf("Bad SMD_BMC_PROBE_MODE '%s': Must be %s or %s", val,
+				BMCProbeHTTPS, BMCProbeTCP)
+		}
+	}
+
+	envvar = "SMD_RF_MAX_RESPONSE_BYTES"
+	if val := os.Getenv(envvar); val != "" {
+		maxBytes, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || maxBytes < 1 {
+			fmt.Printf("Bad SMD_RF_MAX_RESPONSE_BYTES '%s': Must be 1+ bytes", val)
+		} else {
+			s.rfMaxRespBytes = maxBytes
+		}
+	}
+
+	envvar = "SMD_RF_MAX_ARRAY_ENTRIES"
+	if val := os.Getenv(envvar); val != "" {
+		maxLen, err := strconv.Atoi(val)
+		if err != nil || maxLen < 1 {
+			fmt.Printf("Bad SMD_RF_MAX_ARRAY_ENTRIES '%s': Must be 1+ entries", val)
+		} else {
+			s.rfMaxArrayLen = maxLen
+		}
+	}
+
+	envvar = "SMD_RF_DISCOVER_THERMAL"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_DISCOVER_THERMAL - '%s'\n", val)
+		} else {
+			s.rfThermal = b
+		}
+	}
+	envvar = "SMD_RF_DISCOVER_CERTIFICATES"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_DISCOVER_CERTIFICATES - '%s'\n", val)
+		} else {
+			s.rfCertificates = b
+		}
+	}
+	envvar = "SMD_RF_DISCOVER_LOG_SERVICES"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_DISCOVER_LOG_SERVICES - '%s'\n", val)
+		} else {
+			s.rfLogServices = b
+		}
+	}
+	envvar = "SMD_RF_DISCOVER_SECURE_BOOT"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_DISCOVER_SECURE_BOOT - '%s'\n", val)
+		} else {
+			s.rfSecureBoot = b
+		}
+	}
+
+	s.rfSessionAuth = true
+	envvar = "SMD_RF_SESSION_AUTH"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_SESSION_AUTH - '%s'\n", val)
+		} else {
+			s.rfSessionAuth = b
+		}
+	}
+
+	s.rfRetryPolicy = rf.DefaultRetryPolicy
+	envvar = "SMD_RF_RETRIES"
+	if val := os.Getenv(envvar); val != "" {
+		retries, err := strconv.Atoi(val)
+		if err != nil || retries < 0 {
+			fmt.Printf("Bad SMD_RF_RETRIES '%s': Must be 0+ retries", val)
+		} else {
+			s.rfRetryPolicy.Retries = retries
+		}
+	}
+	envvar = "SMD_RF_RETRY_BACKOFF_MS"
+	if val := os.Getenv(envvar); val != "" {
+		ms, err := strconv.Atoi(val)
+		if err != nil || ms < 0 {
+			fmt.Printf("Bad SMD_RF_RETRY_BACKOFF_MS '%s': Must be 0+ milliseconds", val)
+		} else {
+			s.rfRetryPolicy.Backoff = time.Duration(ms) * time.Millisecond
+		}
+	}
+	envvar = "SMD_RF_RETRY_MAX_BACKOFF_MS"
+	if val := os.Getenv(envvar); val != "" {
+		ms, err := strconv.Atoi(val)
+		if err != nil || ms < 0 {
+			fmt.Printf("Bad SMD_RF_RETRY_MAX_BACKOFF_MS '%s': Must be 0+ milliseconds", val)
+		} else {
+			s.rfRetryPolicy.MaxBackoff = time.Duration(ms) * time.Millisecond
+		}
+	}
+	envvar = "SMD_RF_RETRY_JITTER"
+	if val := os.Getenv(envvar); val != "" {
+		jitter, err := strconv.ParseFloat(val, 64)
+		if err != nil || jitter < 0 || jitter > 1 {
+			fmt.Printf("Bad SMD_RF_RETRY_JITTER '%s': Must be 0.0-1.0", val)
+		} else {
+			s.rfRetryPolicy.Jitter = jitter
+		}
+	}
+
+	// Tuning of the HTTP clients used during discovery, in seconds or
+	// connections.  Vendor profiles can override these per endpoint.
+	for _, opt := range []struct {
+		envvar string
+		val    *int
+	}{
+		{"SMD_RF_HTTP_TIMEOUT_SECS", &s.rfHTTPOptions.Timeout},
+		{"SMD_RF_TLS_HANDSHAKE_TIMEOUT_SECS", &s.rfHTTPOptions.TLSHandshakeTimeout},
+		{"SMD_RF_IDLE_CONN_TIMEOUT_SECS", &s.rfHTTPOptions.IdleConnTimeout},
+		{"SMD_RF_MAX_IDLE_CONNS", &s.rfHTTPOptions.MaxIdleConns},
+		{"SMD_RF_MAX_IDLE_CONNS_PER_HOST", &s.rfHTTPOptions.MaxIdleConnsPerHost},
+		{"SMD_RF_MAX_CONNS_PER_HOST", &s.rfHTTPOptions.MaxConnsPerHost},
+	} {
+		if val := os.Getenv(opt.envvar); val != "" {
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				fmt.Printf("Bad %s '%s': Must be 0+\n", opt.envvar, val)
+			} else {
+				*opt.val = n
+			}
+		}
+	}
+	envvar = "SMD_RF_DISABLE_KEEPALIVES"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_DISABLE_KEEPALIVES - '%s'\n", val)
+		} else {
+			s.rfHTTPOptions.DisableKeepAlives = b
+		}
+	}
+	envvar = "SMD_RF_SCHEMA_VALIDATION"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_RF_SCHEMA_VALIDATION - '%s'\n", val)
+		} else {
+			s.rfSchemaCheck = b
+		}
+	}
+	envvar = "SMD_RF_SCHEMA_DIR"
+	if val := os.Getenv(envvar); val != "" {
+		s.rfSchemaDir = val
+	}
+	envvar = "SMD_SNMP_COMMUNITY"
+	if val := os.Getenv(envvar); val != "" {
+		s.rfSNMPCommunity = val
+	}
+
+	s.discBrkPolicy = DefaultDiscoveryBreakerPolicy
+	envvar = "SMD_DISCOVERY_BREAKER_THRESHOLD"
+	if val := os.Getenv(envvar); val != "" {
+		threshold, err := strconv.Atoi(val)
+		if err != nil || threshold < 0 {
+			fmt.Printf("Bad SMD_DISCOVERY_BREAKER_THRESHOLD '%s': Must be 0+ failures", val)
+		} else {
+			s.discBrkPolicy.Threshold = threshold
+		}
+	}
+	envvar = "SMD_DISCOVERY_BREAKER_COOLDOWN_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_DISCOVERY_BREAKER_COOLDOWN_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.discBrkPolicy.Cooldown = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_DISCOVERY_BREAKER_MAX_COOLDOWN_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_DISCOVERY_BREAKER_MAX_COOLDOWN_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.discBrkPolicy.MaxCooldown = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_DISCOVERY_CONCURRENCY"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			fmt.Printf("Bad SMD_DISCOVERY_CONCURRENCY '%s': Must be 0+ endpoints", val)
+		} else {
+			s.discConcurrency = n
+		}
+	}
+	s.discJobKeep = DefaultDiscoveryJobKeep
+	envvar = "SMD_DISCOVERY_JOB_KEEP"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			fmt.Printf("Bad SMD_DISCOVERY_JOB_KEEP '%s': Must be 0+ jobs", val)
+		} else {
+			s.discJobKeep = n
+		}
+	}
+
+	s.compCountPolicy = DefaultCompCountPolicy
+	envvar = "SMD_DISCOVERY_COUNT_ACTION"
+	if val := os.Getenv(envvar); val != "" {
+		switch action := strings.ToLower(val); action {
+		case CompCountActionOff, CompCountActionWarn, CompCountActionHold:
+			s.compCountPolicy.Action = action
+		default:
+			fmt.Printf("Bad SMD_DISCOVERY_COUNT_ACTION '%s': Must be off, warn or hold", val)
+		}
+	}
+	envvar = "SMD_DISCOVERY_COUNT_DROP_PCT"
+	if val := os.Getenv(envvar); val != "" {
+		pct, err := strconv.Atoi(val)
+		if err != nil || pct < 0 || pct > 100 {
+			fmt.Printf("Bad SMD_DISCOVERY_COUNT_DROP_PCT '%s': Must be 0-100 percent", val)
+		} else {
+			s.compCountPolicy.DropPct = pct
+		}
+	}
+	envvar = "SMD_DISCOVERY_COUNT_QUOTA"
+	if val := os.Getenv(envvar); val != "" {
+		quota, err := strconv.Atoi(val)
+		if err != nil || quota < 0 {
+			fmt.Printf("Bad SMD_DISCOVERY_COUNT_QUOTA '%s': Must be 0+ components", val)
+		} else {
+			s.compCountPolicy.Quota = quota
+		}
+	}
+
+	s.scnStormPolicy = DefaultSCNStormPolicy
+	envvar = "SMD_SCN_STORM_ENABLE"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_SCN_STORM_ENABLE - '%s'\n", val)
+		} else {
+			s.scnStormPolicy.Enabled = b
+		}
+	}
+	envvar = "SMD_SCN_STORM_REDISCOVER"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_SCN_STORM_REDISCOVER - '%s'\n", val)
+		} else {
+			s.scnStormPolicy.Rediscover = b
+		}
+	}
+	envvar = "SMD_SCN_STORM_WINDOW_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_SCN_STORM_WINDOW_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.scnStormPolicy.Window = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_SCN_STORM_THRESHOLD"
+	if val := os.Getenv(envvar); val != "" {
+		num, err := strconv.Atoi(val)
+		if err != nil || num < 1 {
+			fmt.Printf("Bad SMD_SCN_STORM_THRESHOLD '%s': Must be 1+ flaps", val)
+		} else {
+			s.scnStormPolicy.Threshold = num
+		}
+	}
+	envvar = "SMD_SCN_STORM_VERIFY_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_SCN_STORM_VERIFY_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.scnStormPolicy.VerifyWindow = time.Duration(secs) * time.Second
+		}
+	}
+
+	s.cluster.ID = os.Getenv("SMD_CLUSTER_ID")
+	if s.cluster.ID == "" {
+		s.cluster.ID, _ = os.Hostname()
+	}
+	envvar = "SMD_CLUSTER_LEASE_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_CLUSTER_LEASE_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.cluster.LeaseTTL = time.Duration(secs) * time.Second
+		}
+	}
+
+	s.compCachePolicy = DefaultCompCachePolicy
+	envvar = "SMD_COMP_CACHE_TTL_MS"
+	if val := os.Getenv(envvar); val != "" {
+		ms, err := strconv.Atoi(val)
+		if err != nil || ms < 0 {
+			fmt.Printf("Bad SMD_COMP_CACHE_TTL_MS '%s': Must be 0+ milliseconds", val)
+		} else {
+			s.compCachePolicy.TTL = time.Duration(ms) * time.Millisecond
+		}
+	}
+	envvar = "SMD_COMP_CACHE_MAX_QUERIES"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_COMP_CACHE_MAX_QUERIES '%s': Must be 1+ queries", val)
+		} else {
+			s.compCachePolicy.MaxQueries = n
+		}
+	}
+	s.compCachePolicy.SCNURL = os.Getenv("SMD_COMP_CACHE_SCN_URL")
+
+	s.scnCoalPolicy = DefaultSCNCoalescePolicy
+	envvar = "SMD_SCN_COALESCE_MS"
+	if val := os.Getenv(envvar); val != "" {
+		ms, err := strconv.Atoi(val)
+		if err != nil || ms < 0 {
+			fmt.Printf("Bad SMD_SCN_COALESCE_MS '%s': Must be 0+ milliseconds", val)
+		} else {
+			s.scnCoalPolicy.Window = time.Duration(ms) * time.Millisecond
+		}
+	}
+	envvar = "SMD_SCN_COALESCE_MAX_COMPONENTS"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_SCN_COALESCE_MAX_COMPONENTS '%s': Must be 1+ components", val)
+		} else {
+			s.scnCoalPolicy.MaxComponents = n
+		}
+	}
+
+	s.scnDelivPolicy = DefaultSCNDeliveryPolicy
+	envvar = "SMD_SCN_DELIVERY_ATTEMPTS"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_SCN_DELIVERY_ATTEMPTS '%s': Must be 1+ attempts", val)
+		} else {
+			s.scnDelivPolicy.Attempts = n
+		}
+	}
+	envvar = "SMD_SCN_DELIVERY_BACKOFF_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_SCN_DELIVERY_BACKOFF_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.scnDelivPolicy.Backoff = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_SCN_DELIVERY_MAX_BACKOFF_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 1 {
+			fmt.Printf("Bad SMD_SCN_DELIVERY_MAX_BACKOFF_SECS '%s': Must be 1+ seconds", val)
+		} else {
+			s.scnDelivPolicy.MaxBackoff = time.Duration(secs) * time.Second
+		}
+	}
+	envvar = "SMD_SCN_DELIVERY_QUEUE_LEN"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_SCN_DELIVERY_QUEUE_LEN '%s': Must be 1+ SCNs", val)
+		} else {
+			s.scnDelivPolicy.QueueLen = n
+		}
+	}
+	envvar = "SMD_SCN_DEAD_LETTER_LEN"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			fmt.Printf("Bad SMD_SCN_DEAD_LETTER_LEN '%s': Must be 0+ SCNs", val)
+		} else {
+			s.scnDelivPolicy.DeadLetterLen = n
+		}
+	}
+
+	s.resExpiry.Warn = resExpiryWarnDefault
+	envvar = "SMD_RESERVATION_EXPIRY_WARN_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_RESERVATION_EXPIRY_WARN_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.resExpiry.Warn = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_GRPC_LISTEN"
+	if val := os.Getenv(envvar); val != "" {
+		s.grpcListen = val
+	}
+
+	envvar = "SMD_COMP_STREAM_BACKLOG"
+	if val := os.Getenv(envvar); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			fmt.Printf("Bad SMD_COMP_STREAM_BACKLOG '%s': Must be 1+ events", val)
+		} else {
+			s.compStream.setBacklog(n)
+		}
+	}
+
+	envvar = "SMD_CONSISTENCY_CHECK_INTERVAL_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_CONSISTENCY_CHECK_INTERVAL_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.consistencyIntvl = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_VENDOR_PROFILES_FILE"
+	if val := os.Getenv(envvar); val != "" {
+		s.vendorProfPath = val
+	}
+
+	envvar = "SMD_TELEMETRY_RECEIVER_URL"
+	if val := os.Getenv(envvar); val != "" {
+		s.telemetryURL = val
+	}
+
+	envvar = "SMD_TELEMETRY_CONTEXT"
+	if val := os.Getenv(envvar); val != "" {
+		s.telemetryCtx = val
+	}
+	if s.telemetryURL != "" && s.telemetryCtx == "" {
+		// The receiver has no other authentication.
+		fmt.Printf("SMD_TELEMETRY_RECEIVER_URL needs SMD_TELEMETRY_CONTEXT\n")
+		os.Exit(1)
+	}
+
+	envvar = "SMD_EVENT_COLLECTOR_URL"
+	if val := os.Getenv(envvar); val != "" {
+		s.eventCollector = val
+	}
+
+	envvar = "SMD_EVENT_CONTEXT"
+	if val := os.Getenv(envvar); val != "" {
+		s.eventCtx = val
+	}
+
+	envvar = "SMD_EVENT_SECRET"
+	if val := os.Getenv(envvar); val != "" {
+		s.eventSecret = val
+	}
+
+	envvar = "SMD_HW_CHANGE_NOTIFY_URL"
+	if val := os.Getenv(envvar); val != "" {
+		s.hwChangeURL = val
+	}
+
+	envvar = "SMD_EVENT_BUS_URL"
+	if val := os.Getenv(envvar); val != "" {
+		s.eventBusURL = val
+	}
+
+	s.eventRediscDelay = DefaultEventRediscoverDelay
+	envvar = "SMD_EVENT_REDISCOVER_DELAY_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			fmt.Printf("Bad SMD_EVENT_REDISCOVER_DELAY_SECS '%s': Must be 0+ seconds", val)
+		} else {
+			s.eventRediscDelay = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_COMPONENT_TOKEN_KEY"
+	if val := os.Getenv(envvar); val != "" {
+		ta, err := newCompTokenAuth([]byte(val))
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_COMPONENT_TOKEN_KEY: %s, "+
+				"component tokens disabled\n", err)
+		} else {
+			s.compTokenAuth = ta
+		}
+	}
+
+	s.compTokenTTL = DefaultCompTokenTTL
+	envvar = "SMD_COMPONENT_TOKEN_TTL_SECS"
+	if val := os.Getenv(envvar); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs <= 0 {
+			fmt.Printf("Warning: Bad env SMD_COMPONENT_TOKEN_TTL_SECS - '%s'\n", val)
+		} else {
+			s.compTokenTTL = time.Duration(secs) * time.Second
+		}
+	}
+
+	envvar = "SMD_LOG_FORMAT"
+	if s.logFormat == "" {
+		s.logFormat = os.Getenv(envvar)
+	}
+	switch s.logFormat {
+	case "", LogFormatText:
+		s.logFormat = LogFormatText
+	case LogFormatJSON:
+	default:
+		fmt.Printf("Warning: Bad log format '%s', using text\n", s.logFormat)
+		s.logFormat = LogFormatText
+	}
+
+	envvar = "SMD_LOG_LEVELS"
+	if val := os.Getenv(envvar); val != "" {
+		levels, err := parseLogLevels(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_LOG_LEVELS: %s\n", err)
+		} else {
+			for sub, lvl := range levels {
+				s.logLevels.Set(sub, lvl)
+			}
+		}
+	}
+
+	envvar = "SMD_READ_ONLY"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_READ_ONLY - '%s'\n", val)
+		} else {
+			s.readOnly.Set(b, os.Getenv("SMD_READ_ONLY_REASON"), time.Now())
+		}
+	}
+
+	rbacPolicy := DefaultRBACPolicy()
+	envvar = "SMD_RBAC_POLICY_FILE"
+	if val := os.Getenv(envvar); val != "" {
+		// Don't start without the access control that was asked for.
+		p, err := loadRBACPolicy(val)
+		if err != nil {
+			fmt.Printf("Bad env SMD_RBAC_POLICY_FILE '%s': %s\n", val, err)
+			os.Exit(1)
+		}
+		rbacPolicy = p
+		rbacPolicy.Enabled = true
+	}
+	envvar = "SMD_RBAC"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Bad env SMD_RBAC - '%s'\n", val)
+			os.Exit(1)
+		}
+		rbacPolicy.Enabled = b
+	}
+	if err := s.rbac.Set(rbacPolicy); err != nil {
+		fmt.Printf("Bad RBAC policy: %s\n", err)
+		os.Exit(1)
+	} else if rbacPolicy.Enabled && s.jwksURL == "" {
+		fmt.Printf("Warning: RBAC needs SMD_JWKS_URL, it is not enforced\n")
+	}
+
+	envvar = "SMD_SELF_TEST"
+	if val := os.Getenv(envvar); val != "" {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			fmt.Printf("Warning: Bad env SMD_SELF_TEST - '%s'\n", val)
+		} else {
+			s.selfTestOn = b
+		}
+	}
+	envvar = "SMD_SELF_TEST_XNAME"
+	if val := os.Getenv(envvar); val != "" {
+		xname := xnametypes.NormalizeHMSCompID(val)
+		if xnametypes.GetHMSType(xname) != xnametypes.NodeBMC {
+			fmt.Printf("Warning: Bad env SMD_SELF_TEST_XNAME - '%s' is "+
+				"not a NodeBMC\n", val)
+		} else {
+			s.selfTestID = xname
+		}
+	}
+
 	s.hmsConfigPath = "/hms_config/hms_config.json"
 	envvar = "HMS_CONFIG_PATH"
 	if val := os.Getenv(envvar); val != "" {
@@ -779,16 +1650,41 @@ func main() {
 	s.valuesBaseV2 = s.serviceBaseV2 + "/values"
 	s.stateBaseV2 = s.apiRootV2 + "/State"
 	s.componentsBaseV2 = s.stateBaseV2 + "/Components"
+	s.deletedCompBaseV2 = s.stateBaseV2 + "/DeletedComponents"
 	s.redfishEPBaseV2 = s.apiRootV2 + "/Inventory/RedfishEndpoints"
+	s.deletedRFEPBaseV2 = s.apiRootV2 + "/Inventory/DeletedRedfishEndpoints"
 	s.nodeMapBaseV2 = s.apiRootV2 + "/Defaults/NodeMaps"
+	s.nidPolicyBaseV2 = s.apiRootV2 + "/Defaults/NIDPolicies"
+	s.nidAssignBaseV2 = s.apiRootV2 + "/Defaults/NIDAssignments"
+	s.xnamesBaseV2 = s.apiRootV2 + "/Xnames"
 	s.compEPBaseV2 = s.apiRootV2 + "/Inventory/ComponentEndpoints"
 	s.serviceEPBaseV2 = s.apiRootV2 + "/Inventory/ServiceEndpoints"
 	s.compEthIntBaseV2 = s.apiRootV2 + "/Inventory/EthernetInterfaces"
 	s.hsnIntBaseV2 = s.apiRootV2 + "/Inventory/HSNInterfaces"
 	s.hwinvByLocBaseV2 = s.apiRootV2 + "/Inventory/Hardware"
 	s.hwinvByFRUBaseV2 = s.apiRootV2 + "/Inventory/HardwareByFRU"
+	s.sparePartsBaseV2 = s.apiRootV2 + "/Inventory/SpareParts"
+	s.thermalBaseV2 = s.apiRootV2 + "/Inventory/ThermalSensors"
 	s.invDiscoverBaseV2 = s.apiRootV2 + "/Inventory/Discover"
 	s.invDiscStatusBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryStatus"
+	s.invDiscHoldBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryHolds"
+	s.invDiscSchedBaseV2 = s.apiRootV2 + "/Inventory/DiscoverySchedules"
+	s.invDiscPlanBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryPlan"
+	s.invDiscJobBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryJobs"
+	s.invExportBaseV2 = s.apiRootV2 + "/Inventory/Export"
+	s.invConsistBaseV2 = s.apiRootV2 + "/Inventory/Consistency"
+	s.invReconcileBaseV2 = s.apiRootV2 + "/Inventory/Reconcile"
+	s.snapshotBaseV2 = s.apiRootV2 + "/Admin/Snapshot"
+	s.vendorProfBaseV2 = s.apiRootV2 + "/Inventory/VendorProfiles"
+	s.fallbackCredBaseV2 = s.apiRootV2 + "/Inventory/FallbackCredentialEndpoints"
+	s.telemetryBaseV2 = s.apiRootV2 + "/Telemetry"
+	s.certBaseV2 = s.apiRootV2 + "/Inventory/Certificates"
+	s.credBaseV2 = s.apiRootV2 + "/Inventory/Credentials"
+	s.firmwareBaseV2 = s.apiRootV2 + "/Inventory/FirmwareInventory"
+	s.logServiceBaseV2 = s.apiRootV2 + "/Inventory/LogServices"
+	s.bootConfigBaseV2 = s.apiRootV2 + "/Inventory/BootConfig"
+	s.eventSubBaseV2 = s.apiRootV2 + "/Inventory/EventSubscriptions"
+	s.eventsBaseV2 = s.apiRootV2 + "/Events"
 	s.subscriptionBaseV2 = s.apiRootV2 + "/Subscriptions"
 	s.groupsBaseV2 = s.apiRootV2 + "/groups"
 	s.partitionsBaseV2 = s.apiRootV2 + "/partitions"
@@ -801,6 +1697,9 @@ func main() {
 
 	// Set up logging for State Manager
 	s.lg = log.New(os.Stdout, "", log.Lshortfile|log.LstdFlags|log.Lmicroseconds)
+	if s.logFormat == LogFormatJSON {
+		s.setJSONLogging(os.Stdout)
+	}
 	if err := s.SetLogLevel(LogLevel(s.logLevelIn)); err != nil {
 		os.Exit(1)
 	}
@@ -819,11 +1718,61 @@ func main() {
 	// Route logs for sm module to main smd log
 	sm.SetLogger(s.lg)
 
+	// Bound memory used for any one Redfish response during discovery
+	if s.rfMaxRespBytes > 0 {
+		rf.SetMaxResponseBytes(s.rfMaxRespBytes)
+	}
+	if s.rfMaxArrayLen > 0 {
+		rf.SetMaxArrayEntries(s.rfMaxArrayLen)
+	}
+	// Fans and temperature sensors cost an extra request per chassis
+	rf.SetDiscoverThermal(s.rfThermal)
+	// HTTPS certificate details cost an extra request per certificate
+	rf.SetDiscoverCertificates(s.rfCertificates)
+	// Listing LogServices costs extra requests per System and Manager
+	rf.SetDiscoverLogServices(s.rfLogServices)
+	// SecureBoot status costs an extra request per System
+	rf.SetDiscoverSecureBoot(s.rfSecureBoot)
+	// Log in once per discovery instead of basic auth on every request
+	rf.SetSessionAuth(s.rfSessionAuth)
+	// Timeouts and connection pools for talking to endpoints
+	rf.SetHTTPOptions(s.rfHTTPOptions)
+	// Check discovered resources against their DMTF schemas
+	rf.SetSchemaValidation(s.rfSchemaCheck)
+	if s.rfSchemaDir != "" {
+		if err := rf.LoadSchemaDir(s.rfSchemaDir); err != nil {
+			s.LogAlways("Error: Loading Redfish schemas: %s", err)
+		}
+	}
+	// For PDUs discovered with the SNMP fallback driver
+	if s.rfSNMPCommunity != "" {
+		rf.SetSNMPCommunity(s.rfSNMPCommunity)
+	}
+	// Retry busy BMCs, and stop rediscovering ones that keep failing
+	rf.SetRetryPolicy(s.rfRetryPolicy)
+	s.discBreaker = NewDiscoveryBreaker(s.discBrkPolicy)
+	// Don't let a discovery that lost most of its components replace them
+	s.compCounts = NewCompCountTracker(s.compCountPolicy)
+	// Track the progress of discoveries asked for through the API
+	s.discJobs = NewDiscoveryJobs(s.discJobKeep)
+
 	// Load HMS base configuration file
 	if err := base.InitTypes(s.hmsConfigPath); err != nil {
 		s.LogAlways("Error: %s\n", err)
 	}
 
+	// Load vendor profiles for RedfishEndpoint creation
+	if s.vendorProfPath != "" {
+		profiles, err := loadVendorProfiles(s.vendorProfPath)
+		if err != nil {
+			s.LogAlways("Error: Can't load vendor profiles: %s\n", err)
+		} else {
+			s.vendorProfiles = profiles
+			s.LogAlways("Loaded %d vendor profiles from %s",
+				len(profiles), s.vendorProfPath)
+		}
+	}
+
 	client := s.GetHTTPClient()
 
 	// Skip SLS if not given a URL.
@@ -847,45 +1796,34 @@ func main() {
 		if err := rf.EnableGenTestingPayloads(s.genTestPayloads); err != nil {
 			s.LogAlways("EnableGenTestingPayloads: Error '%s'", err)
 		}
+		rf.SetGenTestingPayloadsMinimize(s.genTestMinimize)
 	}
 
 	// Connect to database - DSN generated/checked during option parsing
 	// per dbType, so we should always be using a valid, supported type.
 	if s.dbType == dbTypePostgres {
-		hmsdsLgLvl := hmsds.LOG_DEFAULT
 		s.LogAlways("Connecting to data store (Postgres)...")
-		s.db = hmsds.NewHMSDB_PG(s.dbDSN, s.lg)
-		switch s.lgLvl {
-		case LOG_DEFAULT:
-			hmsdsLgLvl = hmsds.LOG_DEFAULT
-		case LOG_NOTICE:
-			hmsdsLgLvl = hmsds.LOG_NOTICE
-		case LOG_INFO:
-			hmsdsLgLvl = hmsds.LOG_INFO
-		case LOG_DEBUG:
-			hmsdsLgLvl = hmsds.LOG_DEBUG
-		default:
-			hmsdsLgLvl = hmsds.LOG_DEBUG
+		s.db = hmsds.NewHMSDB_PG(s.dbDSN, s.dbLogger())
+		s.db.SetLogLevel(hmsdsLogLevel(s.subsysLogLevel(LogSubsysDB)))
+		s.db.SetPoolConfig(s.dbPool)
+
+		// Encrypt RedfishEndpoint passwords, if a key is configured.
+		fieldEnv, err := envcrypt.FromEnv()
+		if err != nil {
+			s.LogAlways("Error: Can't set up database encryption: %s", err)
+			os.Exit(1)
+		}
+		if fieldEnv != nil {
+			s.LogAlways("Encrypting RedfishEndpoint passwords in the database")
 		}
-		s.db.SetLogLevel(hmsdsLgLvl)
+		s.db.SetFieldEncryption(fieldEnv)
+	}
+	if migrateDryRun {
+		s.migratePlan()
+		os.Exit(0)
 	}
 	if applyMigrations {
-		s.LogAlways("Applying all unapplied migrations")
-		for {
-			migrateConnection, err := pgmigrate.DBConnect(s.dbDSN)
-			if err != nil {
-				s.LogAlways("Error connecting to database: %s", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
-			err = pgmigrate.ApplyMigrations("/persistent_migrations", migrateConnection)
-			if err != nil {
-				s.LogAlways("Error applying migrations: %s", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
-			break
-		}
+		s.migrateApply()
 	}
 	for {
 		if err := s.db.Open(); err != nil {
@@ -895,8 +1833,33 @@ func main() {
 			break
 		}
 	}
+	s.schemaCheck()
 
-	if s.readVault || s.writeVault {
+	if s.credStore == credStoreFile {
+		fcs, err := NewFileCredStore(os.Getenv("SMD_CRED_FILE"),
+			os.Getenv("SMD_CRED_FILE_KEY"))
+		if err != nil {
+			s.LogAlways("Error: Can't open credential file store (SMD_CRED_FILE): %s", err)
+			os.Exit(1)
+		}
+		s.ccs = fcs
+		s.LogAlways("Using credential file store %s", os.Getenv("SMD_CRED_FILE"))
+	} else if s.credStore == credStoreK8s {
+		for {
+			kcs, err := NewK8sCredStoreInCluster(
+				os.Getenv("SMD_CRED_K8S_NAMESPACE"),
+				os.Getenv("SMD_CRED_K8S_PREFIX"))
+			if err != nil {
+				s.LogAlways("Error: Kubernetes credential store failed - %s", err)
+				time.Sleep(5 * time.Second)
+			} else {
+				s.ccs = kcs
+				s.LogAlways("Using Kubernetes Secrets in %s for credentials",
+					kcs.namespace)
+				break
+			}
+		}
+	} else if s.readVault || s.writeVault {
 		for {
 			var err error
 			s.LogAlways("Connecting to secure store (Vault)...")
@@ -940,6 +1903,58 @@ func main() {
 		s.LogAlways("CA_URI: '%s'.", vurl)
 	}
 
+	// Set up SCN delivery queues
+	s.scnDelivery = NewSCNDelivery(s.scnDelivPolicy, s.scnPost)
+	s.scnDelivery.delivered = s.metrics.observeSCNLag
+
+	// Set up SCN coalescing
+	if s.scnCoalPolicy.Window > 0 {
+		s.scnCoalesce = NewSCNCoalescer(s.scnCoalPolicy, func(url string, payload []byte) {
+			s.scnDelivery.Queue(url, payload)
+		})
+		s.LogAlways("SCN coalescing enabled: %+v", s.scnCoalPolicy)
+	}
+
+	// Set up SCN storm detection
+	s.scnStorm = NewSCNStormDetector(s.scnStormPolicy, s.scnStormRediscover, s.scnStormSend)
+	if s.scnStormPolicy.Enabled {
+		s.LogAlways("SCN storm detection enabled: %+v", s.scnStormPolicy)
+	}
+
+	// Publish changes to the event bus, if configured
+	if s.eventBusURL != "" {
+		if err := s.StartEventBus(s.eventBusURL); err != nil {
+			s.LogAlways("WARNING: Not publishing to event bus: %s", err)
+		} else {
+			s.LogAlways("Publishing events to %s", s.eventBus.url)
+		}
+	}
+
+	// Cache components, and hear about other instances' changes to them
+	if s.compCachePolicy.TTL > 0 {
+		s.compCache = NewCompCache(s.compCachePolicy)
+		s.compCache.lookup = s.metrics.observeCompCache
+		s.db = newCompCacheDB(s.db, s.compCache)
+		s.LogAlways("Component cache enabled: %+v", s.compCachePolicy)
+		if s.compCachePolicy.SCNURL != "" {
+			token, err := newCompCacheSCNToken()
+			if err == nil {
+				s.compCache.scnToken = token
+				err = s.compCacheSubscribe()
+			}
+			if err != nil {
+				s.LogAlways("WARNING: Component cache won't see other instances' changes: %s", err)
+			}
+		}
+	}
+
+	// Coordinate with other instances, see cluster.go
+	if s.cluster.enabled() {
+		s.StartCluster()
+		s.LogAlways("Coordinating with other instances as %s, lease %s",
+			s.cluster.ID, s.cluster.LeaseTTL)
+	}
+
 	//Initialize the SCN subscription list and map
 	s.scnSubs.SubscriptionList = []sm.SCNSubscription{}
 	s.SCNSubscriptionRefresh()
@@ -961,8 +1976,22 @@ func main() {
 		s.LogAlways("Started redfish event monitoring.")
 	}
 
-	// Start the component lock cleanup thread
+	// Start the component lock cleanup and expiry notification threads
 	s.CompReservationCleanup()
+	s.ReservationExpiryNotifier()
+	if s.tombstoneKeep > 0 {
+		s.StartTombstonePurger()
+	}
+
+	// Start looking for late heartbeats
+	s.StartHeartbeatChecker()
+
+	// Start probing BMCs, if configured
+	if s.bmcProbe.Interval > 0 {
+		s.StartBMCProber()
+		s.LogAlways("Probing BMCs (%s) every %s", s.bmcProbe.Mode,
+			s.bmcProbe.Interval)
+	}
 
 	// Start the Job Sync thread to pick up orphaned
 	// jobs from other HSM instances.
@@ -970,9 +1999,18 @@ func main() {
 	s.srfpJobList = make(map[string]*Job, 0)
 	s.discMap = make(map[string]int, 0)
 	s.JobSync()
+	if s.consistencyIntvl > 0 {
+		s.StartConsistencyChecker(s.consistencyIntvl)
+		s.LogAlways("Inventory consistency check every %s", s.consistencyIntvl)
+	}
+	if s.selfTestOn {
+		s.SelfTestInit()
+		go s.SelfTestRun()
+	}
 	if !s.disableDiscovery {
 		s.DiscoverySync()
 		s.DiscoveryUpdater()
+		s.StartDiscoveryScheduler()
 	}
 
 	// Initialize token authorization and load JWKS well-knowns from .well-known endpoint
@@ -1001,6 +2039,9 @@ func main() {
 	s.LogAlways("Registered SMD protected routes: %v", protectedRoutes)
 	s.LogAlways("Registered SMD public routes: %v", publicRoutes)
 	err = s.setupCerts(s.tlsCert, s.tlsKey)
+	if s.grpcListen != "" {
+		go s.serveGRPC(err == nil)
+	}
 	if err == nil {
 		err = http.ListenAndServeTLS(s.httpListen, s.tlsCert, s.tlsKey, router)
 	} else {
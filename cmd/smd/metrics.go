@@ -0,0 +1,407 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Prometheus metrics
+//
+// GET /metrics returns these in the Prometheus text exposition format:
+//
+//     smd_http_requests_total{route,method,code}          API requests
+//     smd_http_request_duration_seconds{route,method}     API latency
+//     smd_db_*                                            DB pool, transactions
+//     smd_discovery_duration_seconds                      all discoveries
+//     smd_discovery_last_duration_seconds{id}             per RedfishEndpoint
+//     smd_discovery_errors_total{vendor,status}           failed discoveries
+//     smd_scn_delivery_lag_seconds                        SCN queued to sent
+//     smd_components{type,state}                          State/Components
+//     smd_component_cache_lookups_total{cache,result}     see comp-cache.go
+//
+// The route is the route's pattern, e.g. /hsm/v2/State/Components/{xname},
+// so there is one series per route rather than per URL.  The vendor is the
+// Redfish service root's Vendor, or the endpoint's vendor profile if it
+// couldn't be read.  The DB and component metrics are read when scraped;
+// the rest are kept in memory by each SMD instance, from when it started.
+///////////////////////////////////////////////////////////////////////////////
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metric names
+const (
+	metricHTTPRequests     = "smd_http_requests_total"
+	metricHTTPDuration     = "smd_http_request_duration_seconds"
+	metricDBMaxOpen        = "smd_db_max_open_connections"
+	metricDBOpen           = "smd_db_open_connections"
+	metricDBInUse          = "smd_db_in_use_connections"
+	metricDBIdle           = "smd_db_idle_connections"
+	metricDBWaits          = "smd_db_wait_count_total"
+	metricDBWaitDuration   = "smd_db_wait_duration_seconds_total"
+	metricDBTxs            = "smd_db_transactions_total"
+	metricDBSlowTxs        = "smd_db_slow_transactions_total"
+	metricDiscDuration     = "smd_discovery_duration_seconds"
+	metricDiscLastDuration = "smd_discovery_last_duration_seconds"
+	metricDiscErrors       = "smd_discovery_errors_total"
+	metricSCNLag           = "smd_scn_delivery_lag_seconds"
+	metricComponents       = "smd_components"
+	metricReadOnly         = "smd_read_only"
+	metricCompCache        = "smd_component_cache_lookups_total"
+)
+
+// Histogram bucket upper bounds, in seconds.
+var (
+	metricsHTTPBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5,
+		5, 10, 30, 60}
+	metricsDiscBuckets = []float64{1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+	metricsSCNBuckets  = []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300}
+)
+
+// One metric and its series, by label values.
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string // counter, gauge or histogram
+	labels  []string
+	buckets []float64 // Histograms only
+	series  map[string]*metricSeries
+}
+
+type metricSeries struct {
+	labelVals []string
+	value     float64  // Sum, for histograms
+	count     uint64   // Histograms only
+	buckets   []uint64 // Histograms only, not cumulative
+}
+
+// Definitions of the metrics, in the order they are written.
+func metricDefs() []*metricFamily {
+	return []*metricFamily{
+		{name: metricHTTPRequests, typ: "counter",
+			help:   "API requests by route, method and status code.",
+			labels: []string{"route", "method", "code"}},
+		{name: metricHTTPDuration, typ: "histogram",
+			help:    "API request latency by route and method.",
+			labels:  []string{"route", "method"},
+			buckets: metricsHTTPBuckets},
+		{name: metricDBMaxOpen, typ: "gauge",
+			help: "Maximum open database connections, 0 for unlimited."},
+		{name: metricDBOpen, typ: "gauge",
+			help: "Open database connections, in use or idle."},
+		{name: metricDBInUse, typ: "gauge",
+			help: "Database connections in use."},
+		{name: metricDBIdle, typ: "gauge",
+			help: "Idle database connections."},
+		{name: metricDBWaits, typ: "counter",
+			help: "Waits for a free database connection."},
+		{name: metricDBWaitDuration, typ: "counter",
+			help: "Time spent waiting for a free database connection."},
+		{name: metricDBTxs, typ: "counter",
+			help: "Database transactions done."},
+		{name: metricDBSlowTxs, typ: "counter",
+			help: "Database transactions that took SMD_DB_SLOW_TX_MS or longer."},
+		{name: metricDiscDuration, typ: "histogram",
+			help:    "Time to discover a RedfishEndpoint.",
+			buckets: metricsDiscBuckets},
+		{name: metricDiscLastDuration, typ: "gauge",
+			help:   "Time the last discovery of each RedfishEndpoint took.",
+			labels: []string{"id"}},
+		{name: metricDiscErrors, typ: "counter",
+			help:   "Failed discoveries by vendor and discovery status.",
+			labels: []string{"vendor", "status"}},
+		{name: metricSCNLag, typ: "histogram",
+			help:    "Time from queueing an SCN to delivering it.",
+			buckets: metricsSCNBuckets},
+		{name: metricComponents, typ: "gauge",
+			help:   "State/Components by type and state.",
+			labels: []string{"type", "state"}},
+		{name: metricReadOnly, typ: "gauge",
+			help: "1 if the API is in read-only mode, 0 if not."},
+		{name: metricCompCache, typ: "counter",
+			help:   "Component cache lookups by cache and result.",
+			labels: []string{"cache", "result"}},
+	}
+}
+
+// Metrics kept by SMD.  The zero value is ready to use.
+type Metrics struct {
+	lock     sync.Mutex
+	families []*metricFamily
+	byName   map[string]*metricFamily
+}
+
+// Set up the families the first time.  Should be called with lock held.
+func (m *Metrics) init() {
+	if m.byName != nil {
+		return
+	}
+	m.families = metricDefs()
+	m.byName = make(map[string]*metricFamily, len(m.families))
+	for _, f := range m.families {
+		f.series = make(map[string]*metricSeries)
+		m.byName[f.name] = f
+	}
+}
+
+// Should be called with lock held.
+func (m *Metrics) family(name string) *metricFamily {
+	m.init()
+	return m.byName[name]
+}
+
+// Should be called with lock held.
+func (f *metricFamily) get(labelVals []string) *metricSeries {
+	key := strings.Join(labelVals, "\xff")
+	ser, ok := f.series[key]
+	if !ok {
+		ser = &metricSeries{labelVals: labelVals}
+		if f.typ == "histogram" {
+			ser.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = ser
+	}
+	return ser
+}
+
+// Add v to a counter.
+func (m *Metrics) add(name string, v float64, labelVals ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.family(name).get(labelVals).value += v
+}
+
+// Set a gauge, or a counter read from elsewhere.
+func (m *Metrics) set(name string, v float64, labelVals ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.family(name).get(labelVals).value = v
+}
+
+// Add an observation to a histogram.
+func (m *Metrics) observe(name string, v float64, labelVals ...string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	f := m.family(name)
+	ser := f.get(labelVals)
+	ser.value += v
+	ser.count++
+	for i, le := range f.buckets {
+		if v <= le {
+			ser.buckets[i]++
+			break
+		}
+	}
+}
+
+// Record an API request.
+func (m *Metrics) observeRequest(route, method string, code int, d time.Duration) {
+	m.add(metricHTTPRequests, 1, route, method, strconv.Itoa(code))
+	m.observe(metricHTTPDuration, d.Seconds(), route, method)
+}
+
+// Record a discovery of rfEP that took d.
+func (m *Metrics) observeDiscovery(rfEP *rf.RedfishEP, d time.Duration) {
+	m.observe(metricDiscDuration, d.Seconds())
+	m.set(metricDiscLastDuration, d.Seconds(), rfEP.ID)
+	if rfEP.DiscInfo.LastStatus == rf.DiscoverOK {
+		return
+	}
+	vendor := rfEP.ServiceRootRF.Vendor
+	if vendor == "" {
+		vendor = rfEP.TemplateID
+	}
+	if vendor == "" {
+		vendor = "unknown"
+	}
+	m.add(metricDiscErrors, 1, vendor, rfEP.DiscInfo.LastStatus)
+}
+
+// Record an SCN delivered d after it was queued.
+func (m *Metrics) observeSCNLag(d time.Duration) {
+	m.observe(metricSCNLag, d.Seconds())
+}
+
+// Update the DB connection pool metrics.
+func (m *Metrics) setDBStats(st sql.DBStats) {
+	m.set(metricDBMaxOpen, float64(st.MaxOpenConnections))
+	m.set(metricDBOpen, float64(st.OpenConnections))
+	m.set(metricDBInUse, float64(st.InUse))
+	m.set(metricDBIdle, float64(st.Idle))
+	m.set(metricDBWaits, float64(st.WaitCount))
+	m.set(metricDBWaitDuration, st.WaitDuration.Seconds())
+}
+
+// Update the DB transaction metrics.
+func (m *Metrics) setDBTxStats(st hmsds.DBTxStats) {
+	m.set(metricDBTxs, float64(st.Transactions))
+	m.set(metricDBSlowTxs, float64(st.SlowTransactions))
+}
+
+// Replace the component counts with counts.
+func (m *Metrics) setComponentCounts(counts []*hmsds.ComponentCount) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	f := m.family(metricComponents)
+	f.series = make(map[string]*metricSeries)
+	for _, c := range counts {
+		f.get([]string{c.Type, c.State}).value += float64(c.Count)
+	}
+}
+
+// Set the read-only gauge from the current mode.
+func (m *Metrics) setReadOnly(st ReadOnlyStatus) {
+	v := 0.0
+	if st.ReadOnly {
+		v = 1
+	}
+	m.set(metricReadOnly, v)
+}
+
+// Record a component cache lookup.
+func (m *Metrics) observeCompCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.add(metricCompCache, 1, cache, result)
+}
+
+// Escape a label value, or help text, which keeps double quotes, for the
+// text format.
+var (
+	metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	metricsHelpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func metricsFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// {a="1",b="2"} for the label names and values, plus le if not "".
+func metricsLabels(names, vals []string, le string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+metricsLabelEscaper.Replace(vals[i])+`"`)
+	}
+	if le != "" {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Write all metrics in the text exposition format.
+func (m *Metrics) write(w io.Writer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.init()
+	for _, f := range m.families {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name,
+			metricsHelpEscaper.Replace(f.help), f.name, f.typ)
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if len(keys) == 0 && len(f.labels) == 0 {
+			// Unlabelled metrics are always written, even if zero.
+			f.get(nil)
+			keys = append(keys, "")
+		}
+		for _, key := range keys {
+			ser := f.series[key]
+			if f.typ != "histogram" {
+				fmt.Fprintf(w, "%s%s %s\n", f.name,
+					metricsLabels(f.labels, ser.labelVals, ""),
+					metricsFloat(ser.value))
+				continue
+			}
+			var cum uint64
+			for i, le := range f.buckets {
+				cum += ser.buckets[i]
+				fmt.Fprintf(w, "%s_bucket%s %d\n", f.name,
+					metricsLabels(f.labels, ser.labelVals, metricsFloat(le)),
+					cum)
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name,
+				metricsLabels(f.labels, ser.labelVals, "+Inf"), ser.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", f.name,
+				metricsLabels(f.labels, ser.labelVals, ""),
+				metricsFloat(ser.value))
+			fmt.Fprintf(w, "%s_count%s %d\n", f.name,
+				metricsLabels(f.labels, ser.labelVals, ""), ser.count)
+		}
+	}
+}
+
+// Count requests to route, and how long they take.
+func (s *SmD) instrumentRoute(route Route, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		h.ServeHTTP(ww, r)
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+		s.metrics.observeRequest(route.Pattern, route.Method, code,
+			time.Since(start))
+	})
+}
+
+// GET /metrics
+func (s *SmD) doMetricsGet(w http.ResponseWriter, r *http.Request) {
+	s.metrics.setDBStats(s.db.DBStats())
+	s.metrics.setDBTxStats(s.db.TxStats())
+	s.metrics.setReadOnly(s.readOnly.Get())
+	counts, err := s.db.GetComponentCounts()
+	if err != nil {
+		// Leave them out rather than report stale counts.
+		s.LogAlways("doMetricsGet(): Lookup failure: %s", err)
+		counts = nil
+	}
+	s.metrics.setComponentCounts(counts)
+	w.Header().Set("Content-Type", metricsContentType)
+	w.WriteHeader(http.StatusOK)
+	s.metrics.write(w)
+}
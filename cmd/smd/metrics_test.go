@@ -0,0 +1,204 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+func TestMetricsWrite(t *testing.T) {
+	var m Metrics
+	m.observeRequest("/hsm/v2/State/Components/{xname}", "GET", 200,
+		30*time.Millisecond)
+	m.observeRequest("/hsm/v2/State/Components/{xname}", "GET", 200,
+		2*time.Second)
+	m.observeRequest("/hsm/v2/State/Components/{xname}", "GET", 404,
+		time.Millisecond)
+
+	ok := &rf.RedfishEP{}
+	ok.ID = "x0c0s0b0"
+	ok.DiscInfo.LastStatus = rf.DiscoverOK
+	ok.ServiceRootRF.Vendor = "HPE"
+	m.observeDiscovery(ok, 20*time.Second)
+	failed := &rf.RedfishEP{}
+	failed.ID = "x0c0s1b0"
+	failed.TemplateID = "Gigabyte \"G\""
+	failed.DiscInfo.LastStatus = rf.HTTPsGetFailed
+	m.observeDiscovery(failed, 3*time.Second)
+	unknown := &rf.RedfishEP{}
+	unknown.ID = "x0c0s2b0"
+	unknown.DiscInfo.LastStatus = rf.HTTPsGetFailed
+	m.observeDiscovery(unknown, time.Second)
+	escaped := &rf.RedfishEP{}
+	escaped.ID = "x0c0s3b0"
+	escaped.ServiceRootRF.Vendor = "Acme\\BMC\nv2"
+	escaped.DiscInfo.LastStatus = rf.HTTPsGetFailed
+	m.observeDiscovery(escaped, time.Second)
+
+	m.setDBStats(sql.DBStats{OpenConnections: 3, InUse: 1, Idle: 2,
+		WaitCount: 7, WaitDuration: 1500 * time.Millisecond})
+	m.setComponentCounts([]*hmsds.ComponentCount{
+		{Type: "Node", State: "Ready", Count: 2},
+		{Type: "Node", State: "Off", Count: 1},
+	})
+	m.setComponentCounts([]*hmsds.ComponentCount{
+		{Type: "Node", State: "Ready", Count: 1},
+		{Type: "NodeBMC", State: "Ready", Count: 1},
+	})
+	m.setReadOnly(ReadOnlyStatus{ReadOnly: true})
+
+	var buf bytes.Buffer
+	m.write(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE smd_http_requests_total counter",
+		`smd_http_requests_total{route="/hsm/v2/State/Components/{xname}",method="GET",code="200"} 2`,
+		`smd_http_requests_total{route="/hsm/v2/State/Components/{xname}",method="GET",code="404"} 1`,
+		"# TYPE smd_http_request_duration_seconds histogram",
+		`smd_http_request_duration_seconds_bucket{route="/hsm/v2/State/Components/{xname}",method="GET",le="0.005"} 1`,
+		`smd_http_request_duration_seconds_bucket{route="/hsm/v2/State/Components/{xname}",method="GET",le="0.05"} 2`,
+		`smd_http_request_duration_seconds_bucket{route="/hsm/v2/State/Components/{xname}",method="GET",le="2.5"} 3`,
+		`smd_http_request_duration_seconds_bucket{route="/hsm/v2/State/Components/{xname}",method="GET",le="+Inf"} 3`,
+		`smd_http_request_duration_seconds_count{route="/hsm/v2/State/Components/{xname}",method="GET"} 3`,
+		"smd_db_max_open_connections 0",
+		"smd_db_open_connections 3",
+		"smd_db_in_use_connections 1",
+		"smd_db_idle_connections 2",
+		"smd_db_wait_count_total 7",
+		"smd_db_wait_duration_seconds_total 1.5",
+		`smd_discovery_duration_seconds_bucket{le="1"} 2`,
+		`smd_discovery_duration_seconds_bucket{le="5"} 3`,
+		`smd_discovery_duration_seconds_bucket{le="30"} 4`,
+		"smd_discovery_duration_seconds_count 4",
+		`smd_discovery_last_duration_seconds{id="x0c0s0b0"} 20`,
+		`smd_discovery_last_duration_seconds{id="x0c0s1b0"} 3`,
+		`smd_discovery_errors_total{vendor="Gigabyte \"G\"",status="HTTPsGetFailed"} 1`,
+		`smd_discovery_errors_total{vendor="unknown",status="HTTPsGetFailed"} 1`,
+		`smd_discovery_errors_total{vendor="Acme\\BMC\nv2",status="HTTPsGetFailed"} 1`,
+		`smd_scn_delivery_lag_seconds_bucket{le="+Inf"} 0`,
+		"smd_scn_delivery_lag_seconds_count 0",
+		`smd_components{type="Node",state="Ready"} 1`,
+		`smd_components{type="NodeBMC",state="Ready"} 1`,
+		"# HELP smd_read_only 1 if the API is in read-only mode, 0 if not.",
+		"smd_read_only 1",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Missing '%s' in:\n%s", line, out)
+		}
+	}
+	if !strings.Contains(out, `smd_http_request_duration_seconds_sum{route="/hsm/v2/State/Components/{xname}",method="GET"} 2.03`) {
+		t.Errorf("Unexpected latency sum:\n%s", out)
+	}
+	if strings.Contains(out, `vendor="HPE"`) {
+		t.Errorf("Successful discovery counted as an error:\n%s", out)
+	}
+	if strings.Contains(out, `state="Off"`) {
+		t.Errorf("Old component counts not replaced:\n%s", out)
+	}
+}
+
+func TestSCNDeliveryLag(t *testing.T) {
+	d, _, _ := newTestSCNDelivery(DefaultSCNDeliveryPolicy)
+	now := time.Now()
+	d.now = func() time.Time {
+		now = now.Add(250 * time.Millisecond)
+		return now
+	}
+	var m Metrics
+	d.delivered = m.observeSCNLag
+	d.Queue("http://sub1", []byte(`{}`))
+	waitSCNDelivery(t, d)
+
+	var buf bytes.Buffer
+	m.write(&buf)
+	for _, line := range []string{
+		`smd_scn_delivery_lag_seconds_bucket{le="0.1"} 0`,
+		`smd_scn_delivery_lag_seconds_bucket{le="0.5"} 1`,
+		"smd_scn_delivery_lag_seconds_count 1",
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("Missing '%s' in:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestDoMetricsGet(t *testing.T) {
+	defer func() {
+		results.GetComponentCounts.Return.counts = nil
+		results.GetComponentCounts.Return.err = nil
+		results.DBStats.Return.stats = sql.DBStats{}
+		results.TxStats.Return.stats = hmsds.DBTxStats{}
+	}()
+	results.DBStats.Return.stats = sql.DBStats{MaxOpenConnections: 10,
+		OpenConnections: 4}
+	results.TxStats.Return.stats = hmsds.DBTxStats{Transactions: 12,
+		SlowTransactions: 2}
+	results.GetComponentCounts.Return.counts = []*hmsds.ComponentCount{
+		{Type: "Node", State: "On", Count: 3},
+	}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/service/liveness", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req, _ = http.NewRequest("GET", "https://localhost/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK ||
+		w.Header().Get("Content-Type") != metricsContentType {
+		t.Fatalf("Unexpected response: %d %v", w.Code, w.Header())
+	}
+	for _, line := range []string{
+		`smd_http_requests_total{route="/hsm/v2/service/liveness",method="GET",code="204"}`,
+		"smd_db_max_open_connections 10",
+		"smd_db_open_connections 4",
+		"smd_db_transactions_total 12",
+		"smd_db_slow_transactions_total 2",
+		`smd_components{type="Node",state="On"} 3`,
+		"smd_read_only 0",
+	} {
+		if !strings.Contains(w.Body.String(), line) {
+			t.Errorf("Missing '%s' in:\n%s", line, w.Body.String())
+		}
+	}
+
+	// Component counts are left out if they can't be read.
+	results.GetComponentCounts.Return.err = errors.New("db down")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK ||
+		strings.Contains(w.Body.String(), `smd_components{`) ||
+		!strings.Contains(w.Body.String(), `route="/metrics"`) {
+		t.Errorf("Unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,194 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/internal/pgmigrate"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Schema migrations
+//
+// The migrations under migrations/postgres are built in, so SMD can bring
+// its own schema up to date without smd-init or the files being installed:
+//
+//     -migrate                 apply them before starting
+//     -migrate -migrate-to N   go up or down to migration N instead of the
+//                              last one, e.g. before going back to an
+//                              older SMD
+//     -migrate-dry-run         print the DDL -migrate would run, and exit
+//     -migrations-dir DIR      use the migrations in DIR instead
+//
+// At startup, SMD checks the database is at the migration it expects, the
+// last one (or -migrate-to), and won't start if it is behind or a
+// migration failed part way through, as its queries would fail.  If the
+// database is ahead, e.g. after rolling SMD back, it only warns.
+///////////////////////////////////////////////////////////////////////////////
+
+var migrateTo int
+var migrateDryRun bool
+var migrationsDir string
+
+// Open the migrations and work out the one to migrate to.
+func migrateSource() (source.Driver, uint, error) {
+	src, err := pgmigrate.Source(migrationsDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	latest, err := pgmigrate.Latest(src)
+	if err != nil {
+		src.Close()
+		return nil, 0, err
+	}
+	if migrateTo < 0 {
+		return src, latest, nil
+	} else if uint(migrateTo) > latest {
+		src.Close()
+		return nil, 0, fmt.Errorf("-migrate-to %d is past the last migration, %d",
+			migrateTo, latest)
+	}
+	return src, uint(migrateTo), nil
+}
+
+// Print the DDL that -migrate would run, then exit.
+func (s *SmD) migratePlan() {
+	src, target, err := migrateSource()
+	if err != nil {
+		s.LogAlways("Error: Can't read migrations: %s", err)
+		os.Exit(1)
+	}
+	defer src.Close()
+	db, err := pgmigrate.DBConnect(s.dbDSN)
+	if err != nil {
+		s.LogAlways("Error connecting to database: %s", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	version, dirty, err := pgmigrate.CurrentVersion(db)
+	if err != nil {
+		s.LogAlways("Error: Can't get schema version: %s", err)
+		os.Exit(1)
+	}
+	steps, err := pgmigrate.Plan(src, version, target)
+	if err != nil {
+		s.LogAlways("Error: Can't plan migration from %d to %d: %s",
+			version, target, err)
+		os.Exit(1)
+	}
+	fmt.Printf("-- Database at migration %d (dirty: %t), migrating to %d\n",
+		version, dirty, target)
+	for _, step := range steps {
+		dir := "down"
+		if step.Up {
+			dir = "up"
+		}
+		fmt.Printf("\n-- Migration %d %s: %s\n%s\n",
+			step.Version, dir, step.Name, step.SQL)
+	}
+	if len(steps) == 0 {
+		fmt.Printf("-- Nothing to do\n")
+	}
+}
+
+// Apply migrations, up or down, retrying until the database can be
+// reached.
+func (s *SmD) migrateApply() {
+	for {
+		src, target, err := migrateSource()
+		if err != nil {
+			s.LogAlways("Error: Can't read migrations: %s", err)
+			os.Exit(1)
+		}
+		db, err := pgmigrate.DBConnect(s.dbDSN)
+		if err != nil {
+			src.Close()
+			s.LogAlways("Error connecting to database: %s", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		s.LogAlways("Applying migrations up or down to %d", target)
+		err = pgmigrate.MigrateTo(db, src, target)
+		db.Close()
+		if err != nil {
+			s.LogAlways("Error applying migrations: %s", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		break
+	}
+}
+
+// Make sure the schema is the one this SMD expects.
+func (s *SmD) schemaCheck() {
+	expected, err := latestMigration()
+	if err != nil {
+		s.LogAlways("Error: Can't read migrations: %s", err)
+		os.Exit(1)
+	}
+	if applyMigrations && migrateTo >= 0 {
+		expected = uint(migrateTo)
+	}
+	db, err := pgmigrate.DBConnect(s.dbDSN)
+	if err != nil {
+		s.LogAlways("Warning: Can't check schema version: %s", err)
+		return
+	}
+	defer db.Close()
+	version, dirty, err := pgmigrate.CurrentVersion(db)
+	if err != nil {
+		s.LogAlways("Warning: Can't check schema version: %s", err)
+		return
+	}
+	switch {
+	case dirty:
+		s.LogAlways("Error: Migration %d of the schema failed part way "+
+			"through.  Fix the schema and force the version with smd-init -f.",
+			version)
+		os.Exit(1)
+	case version < expected:
+		s.LogAlways("Error: Schema is at migration %d, this SMD needs %d.  "+
+			"Run smd-init, or start SMD with -migrate.", version, expected)
+		os.Exit(1)
+	case version > expected:
+		s.LogAlways("Warning: Schema is at migration %d, newer than the %d "+
+			"this SMD knows.  Roll it back with -migrate -migrate-to %d if "+
+			"anything fails.", version, expected, expected)
+	default:
+		s.LogAlways("Schema is at migration %d", version)
+	}
+}
+
+// The last migration.
+func latestMigration() (uint, error) {
+	src, err := pgmigrate.Source(migrationsDir)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+	return pgmigrate.Latest(src)
+}
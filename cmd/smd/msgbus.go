@@ -156,3 +156,33 @@ func (s *SmD) MsgBusReconnect() error {
 func (s *SmD) MsgBusReadNext() (string, error) {
 	return s.msgbusHandle.Handle.MessageRead()
 }
+
+// Kafka connection for publishing events, see event-bus.go
+type kafkaBusConn struct {
+	handle msgbus.MsgBusIO
+}
+
+// Connect to Kafka to publish events on topic.
+func dialKafkaBus(host string, port int, topic string) (busConn, error) {
+	handle, err := msgbus.Connect(msgbus.MsgBusConfig{
+		BusTech:        msgbus.BusTechKafka,
+		Blocking:       msgbus.NonBlocking,
+		Direction:      msgbus.BusWriter,
+		ConnectRetries: 10,
+		Host:           host,
+		Port:           port,
+		Topic:          topic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaBusConn{handle: handle}, nil
+}
+
+func (k *kafkaBusConn) write(msg []byte) error {
+	return k.handle.MessageWrite(string(msg))
+}
+
+func (k *kafkaBusConn) close() error {
+	return k.handle.Disconnect()
+}
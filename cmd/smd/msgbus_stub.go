@@ -27,8 +27,12 @@
 
 package main
 
+import "errors"
+
 const MSG_BUS_BUILD = false
 
+var errKafkaBusNotBuilt = errors.New("kafka event bus is only in csm builds")
+
 type MsgBusConfigWrapper struct {
 }
 
@@ -54,3 +58,7 @@ func (s *SmD) MsgBusReconnect() error {
 func (s *SmD) MsgBusReadNext() (string, error) {
 	return "", nil
 }
+
+func dialKafkaBus(host string, port int, topic string) (busConn, error) {
+	return nil, errKafkaBusNotBuilt
+}
@@ -0,0 +1,425 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// NID assignment policies
+//
+// A node's NID comes from SLS or its NodeMap if there is one.  Otherwise it
+// used to be derived from its xname (GetBogusNID()).  NID policies replace
+// that for new nodes:
+//
+//     Sequential  the lowest free NID from Start to End, e.g. one policy
+//                 per cabinet with its own range
+//     Geometry    derived from the xname like the default, offset by Start
+//                 and no higher than End, if set
+//     Reserved    Start to End are never assigned by the other policies
+//
+// Each policy applies to the nodes at or below its Scope, or to all nodes.
+// The policies are tried in order of Priority and the first that can
+// assign a NID does.  A NID is free if no component, NodeMap or earlier
+// assignment uses it.  Those that can't, e.g. because the range is full or
+// the derived NID is taken, are logged as conflicts and the next is tried,
+// falling back to the default.
+//
+// Assignments are recorded, so a node keeps its NID if it is deleted and
+// rediscovered, until the assignment is deleted.  The NID of an assignment
+// is unique in the database, so instances assigning NIDs at the same time
+// can't assign the same one.  Nodes that already exist keep their NIDs.
+//
+//     GET    /Defaults/NIDPolicies                        all policies
+//     GET    /Defaults/NIDPolicies/{name}                 one policy
+//     PUT    /Defaults/NIDPolicies/{name}                 create or replace
+//     DELETE /Defaults/NIDPolicies/{name}                 remove it
+//     POST   /Defaults/NIDPolicies/Actions/Preview        NIDs nodes would
+//                                                         get, assigning none
+//     GET    /Defaults/NIDAssignments[?id=xname]          assigned NIDs
+//     DELETE /Defaults/NIDAssignments/{xname}             release one
+///////////////////////////////////////////////////////////////////////////////
+
+// How many times to try to assign a NID that other instances may be
+// assigning at the same time.
+const nidAssignTries = 3
+
+// Assigns NIDs by policy.  Previews don't record the assignments and keep
+// track of the NIDs they would have assigned in pending instead.
+type nidAssigner struct {
+	s        *SmD
+	policies []*sm.NIDPolicy
+	reserved []*sm.NIDPolicy
+	record   bool
+	pending  map[int64]string
+}
+
+// Get the current policies.  If record, assignments are recorded.
+func (s *SmD) newNIDAssigner(record bool) (*nidAssigner, error) {
+	policies, err := s.db.GetNIDPolicies()
+	if err != nil {
+		return nil, err
+	}
+	na := &nidAssigner{
+		s:       s,
+		record:  record,
+		pending: make(map[int64]string),
+	}
+	for _, p := range policies {
+		if p.Type == sm.NIDPolicyReserved {
+			na.reserved = append(na.reserved, p)
+		} else {
+			na.policies = append(na.policies, p)
+		}
+	}
+	return na, nil
+}
+
+// Why nid can't be assigned to xname, or "" if it can.  inUse has the
+// xnames using each NID.
+func (na *nidAssigner) nidConflict(nid int64, xname string, inUse map[int64][]string) string {
+	for _, r := range na.reserved {
+		if nid >= r.Start && nid <= r.End {
+			return fmt.Sprintf("NID %d is reserved by %s", nid, r.Name)
+		}
+	}
+	for _, id := range inUse[nid] {
+		if id != xname {
+			return fmt.Sprintf("NID %d is used by %s", nid, id)
+		}
+	}
+	if id, ok := na.pending[nid]; ok && id != xname {
+		return fmt.Sprintf("NID %d would be assigned to %s", nid, id)
+	}
+	return ""
+}
+
+// The NID policy p would assign to xname, or a conflict if none.
+func (na *nidAssigner) candidate(p *sm.NIDPolicy, xname string) (int64, string, error) {
+	if p.Type == sm.NIDPolicyGeometry {
+		nid := p.Start + int64(GetBogusNID(xname))
+		if p.End != 0 && nid > p.End {
+			return 0, fmt.Sprintf("NID %d is above %d", nid, p.End), nil
+		}
+		inUse, err := na.s.db.GetNIDsInUse(nid, nid)
+		if err != nil {
+			return 0, "", err
+		}
+		if conflict := na.nidConflict(nid, xname, inUse); conflict != "" {
+			return 0, conflict, nil
+		}
+		return nid, "", nil
+	}
+	inUse, err := na.s.db.GetNIDsInUse(p.Start, p.End)
+	if err != nil {
+		return 0, "", err
+	}
+	for nid := p.Start; nid <= p.End; nid++ {
+		if na.nidConflict(nid, xname, inUse) == "" {
+			return nid, "", nil
+		}
+	}
+	return 0, fmt.Sprintf("no free NID from %d to %d", p.Start, p.End), nil
+}
+
+// The NID xname gets from the policies: the one already assigned to it, or
+// from the first policy that can assign one, or the default.  Conflicts
+// has why the policies that apply but were skipped could not assign one.
+func (na *nidAssigner) assign(xname string) (*sm.NIDPreview, error) {
+	as, err := na.s.db.GetNIDAssignments([]string{xname})
+	if err != nil {
+		return nil, err
+	}
+	if len(as) > 0 {
+		return &sm.NIDPreview{
+			ID:     xname,
+			NID:    as[0].NID,
+			Source: sm.NIDSourceAssignment,
+			Policy: as[0].Policy,
+		}, nil
+	}
+	pv := &sm.NIDPreview{ID: xname}
+	for _, p := range na.policies {
+		if p.Scope != "" && !inXnameScope(xname, p.Scope) {
+			continue
+		}
+		for try := 1; ; try++ {
+			nid, conflict, err := na.candidate(p, xname)
+			if err != nil {
+				return nil, err
+			}
+			if conflict != "" {
+				pv.Conflicts = append(pv.Conflicts, p.Name+": "+conflict)
+				break
+			}
+			pv.NID, pv.Source, pv.Policy = nid, sm.NIDSourcePolicy, p.Name
+			if !na.record {
+				na.pending[nid] = xname
+				return pv, nil
+			}
+			didInsert, err := na.s.db.InsertNIDAssignment(&sm.NIDAssignment{
+				ID:     xname,
+				NID:    nid,
+				Policy: p.Name,
+			})
+			if err != nil {
+				return nil, err
+			} else if didInsert {
+				return pv, nil
+			} else if try == nidAssignTries {
+				// Some other instance keeps getting there first.
+				pv.Conflicts = append(pv.Conflicts, p.Name+": "+
+					fmt.Sprintf("NID %d was assigned elsewhere", nid))
+				break
+			}
+		}
+	}
+	pv.NID, pv.Source, pv.Policy = int64(GetBogusNID(xname)), sm.NIDSourceDefault, ""
+	return pv, nil
+}
+
+// The NID for a node with none from SLS or its NodeMap: the one it already
+// has, or one from the NID policies, or the default.
+func (s *SmD) policyNID(xname string) uint64 {
+	htype := xnametypes.GetHMSType(xname)
+	if htype != xnametypes.Node && htype != xnametypes.VirtualNode {
+		return GetBogusNID(xname)
+	}
+	na, err := s.newNIDAssigner(true)
+	if err != nil {
+		s.LogAlways("policyNID(%s): Lookup failure: %s", xname, err)
+		return GetBogusNID(xname)
+	} else if len(na.policies) == 0 {
+		return GetBogusNID(xname)
+	}
+	comp, err := s.db.GetComponentByID(xname)
+	if err != nil {
+		s.LogAlways("policyNID(%s): Lookup failure: %s", xname, err)
+		return GetBogusNID(xname)
+	} else if comp != nil {
+		if nid, err := comp.NID.Int64(); err == nil && nid > 0 {
+			return uint64(nid)
+		}
+	}
+	pv, err := na.assign(xname)
+	if err != nil {
+		s.LogAlways("policyNID(%s): Assignment failure: %s", xname, err)
+		return GetBogusNID(xname)
+	}
+	if len(pv.Conflicts) > 0 {
+		s.LogAlways("WARNING: NID policy conflicts for %s, got NID %d (%s): %s",
+			xname, pv.NID, pv.Source, strings.Join(pv.Conflicts, "; "))
+	} else if pv.Source == sm.NIDSourcePolicy {
+		s.LogAlways("Assigned NID %d to %s by NID policy %s", pv.NID, xname,
+			pv.Policy)
+	}
+	return uint64(pv.NID)
+}
+
+// Get all NID policies
+func (s *SmD) doNIDPoliciesGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	policies, err := s.db.GetNIDPolicies()
+	if err != nil {
+		s.LogAlways("doNIDPoliciesGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.NIDPolicyArray{Policies: policies})
+}
+
+// Get one NID policy
+func (s *SmD) doNIDPolicyGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	name := chi.URLParam(r, "name")
+	policies, err := s.db.GetNIDPolicies()
+	if err != nil {
+		s.LogAlways("doNIDPolicyGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	for _, p := range policies {
+		if p.Name == name {
+			sendJsonObject(w, http.StatusOK, p)
+			return
+		}
+	}
+	sendJsonError(w, http.StatusNotFound, "no such policy.")
+}
+
+// Create or replace a NID policy
+func (s *SmD) doNIDPolicyPut(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	p := new(sm.NIDPolicy)
+	if !s.certDecodeBody(w, r, p) {
+		return
+	}
+	p.Name = chi.URLParam(r, "name")
+	if err := p.Verify(); err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.db.UpsertNIDPolicy(p); err != nil {
+		s.LogAlways("doNIDPolicyPut(): Store failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, p)
+}
+
+// Remove a NID policy.  The NIDs it assigned stay assigned.
+func (s *SmD) doNIDPolicyDelete(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	didDelete, err := s.db.DeleteNIDPolicy(chi.URLParam(r, "name"))
+	if err != nil {
+		s.LogAlways("doNIDPolicyDelete(): Delete failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if !didDelete {
+		sendJsonError(w, http.StatusNotFound, "no such policy.")
+		return
+	}
+	sendJsonError(w, http.StatusOK, "deleted 1 entry")
+}
+
+// Show the NIDs the given nodes have or would get, without assigning any.
+// Nodes earlier in the list are previewed as if they had been assigned
+// theirs, as discovering them together would.
+func (s *SmD) doNIDPolicyPreviewPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	in := new(sm.NIDPreviewIn)
+	if !s.certDecodeBody(w, r, in) {
+		return
+	}
+	if len(in.IDs) == 0 {
+		sendJsonError(w, http.StatusBadRequest, "no IDs given")
+		return
+	}
+	ids := make([]string, 0, len(in.IDs))
+	for _, id := range in.IDs {
+		xname := xnametypes.VerifyNormalizeCompID(id)
+		htype := xnametypes.GetHMSType(xname)
+		if htype != xnametypes.Node && htype != xnametypes.VirtualNode {
+			sendJsonError(w, http.StatusBadRequest,
+				"xname ID '"+id+"' is invalid or not a node")
+			return
+		}
+		ids = append(ids, xname)
+	}
+	na, err := s.newNIDAssigner(false)
+	if err != nil {
+		s.LogAlways("doNIDPolicyPreviewPost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	pva := sm.NIDPreviewArray{NIDs: make([]*sm.NIDPreview, 0, len(ids))}
+	for _, xname := range ids {
+		pv, err := s.previewNID(na, xname)
+		if err != nil {
+			s.LogAlways("doNIDPolicyPreviewPost(): Lookup failure: %s", err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		pva.NIDs = append(pva.NIDs, pv)
+	}
+	sendJsonObject(w, http.StatusOK, pva)
+}
+
+// The NID xname has or would get, in the same order as GetCompDefaults()
+// and policyNID() look for one.
+func (s *SmD) previewNID(na *nidAssigner, xname string) (*sm.NIDPreview, error) {
+	comp, err := s.db.GetComponentByID(xname)
+	if err != nil {
+		return nil, err
+	} else if comp != nil {
+		if nid, err := comp.NID.Int64(); err == nil && nid > 0 {
+			return &sm.NIDPreview{ID: xname, NID: nid,
+				Source: sm.NIDSourceComponent}, nil
+		}
+	}
+	if s.sls != nil {
+		nodeInfo, err := s.sls.GetNodeInfo(xname)
+		if err == nil && nodeInfo.NID > 0 {
+			return &sm.NIDPreview{ID: xname, NID: int64(nodeInfo.NID),
+				Source: sm.NIDSourceSLS}, nil
+		}
+	}
+	m, err := s.db.GetNodeMapByID(xname)
+	if err != nil {
+		return nil, err
+	} else if m != nil && m.NID > 0 {
+		return &sm.NIDPreview{ID: xname, NID: int64(m.NID),
+			Source: sm.NIDSourceNodeMap}, nil
+	}
+	return na.assign(xname)
+}
+
+// Get the NIDs assigned by policies, all or those for ?id=...
+func (s *SmD) doNIDAssignmentsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"failed to decode query parameters.")
+		return
+	}
+	as, err := s.db.GetNIDAssignments(r.Form["id"])
+	if err != nil {
+		s.LogAlways("doNIDAssignmentsGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.NIDAssignmentArray{Assignments: as})
+}
+
+// Release the NID assigned to a node, so it can be assigned again.  The
+// node itself keeps it if it exists.
+func (s *SmD) doNIDAssignmentDelete(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	didDelete, err := s.db.DeleteNIDAssignment(chi.URLParam(r, "xname"))
+	if err != nil {
+		s.LogAlways("doNIDAssignmentDelete(): Delete failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if !didDelete {
+		sendJsonError(w, http.StatusNotFound, "no such assignment.")
+		return
+	}
+	sendJsonError(w, http.StatusOK, "deleted 1 entry")
+}
@@ -0,0 +1,291 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func resetNIDPolicyResults() {
+	results.GetNIDPolicies.Return.policies = nil
+	results.GetNIDPolicies.Return.err = nil
+	results.UpsertNIDPolicy.Input.p = nil
+	results.UpsertNIDPolicy.Return.err = nil
+	results.GetNIDAssignments.Input.ids = nil
+	results.GetNIDAssignments.Return.as = nil
+	results.GetNIDAssignments.Return.err = nil
+	results.InsertNIDAssignment.Input.a = nil
+	results.InsertNIDAssignment.Return.didInsert = false
+	results.InsertNIDAssignment.Return.err = nil
+	results.GetNIDsInUse.Return.inUse = nil
+	results.GetNIDsInUse.Return.err = nil
+	results.GetComponentByID.Return.id = nil
+	results.GetComponentByID.Return.err = nil
+	results.GetNodeMapByID.Return.entry = nil
+	results.GetNodeMapByID.Return.err = nil
+}
+
+func TestNIDAssignerAssign(t *testing.T) {
+	defer resetNIDPolicyResults()
+
+	seq := &sm.NIDPolicy{Name: "x1000", Type: sm.NIDPolicySequential,
+		Scope: "x1000", Start: 1, End: 4}
+	geo := &sm.NIDPolicy{Name: "geo", Type: sm.NIDPolicyGeometry,
+		Start: 100000000}
+	geoMax := &sm.NIDPolicy{Name: "geomax", Type: sm.NIDPolicyGeometry,
+		End: 100}
+	rsv := &sm.NIDPolicy{Name: "rsv", Type: sm.NIDPolicyReserved,
+		Start: 1, End: 2}
+	tests := []struct {
+		xname     string
+		policies  []*sm.NIDPolicy
+		as        []*sm.NIDAssignment
+		inUse     map[int64][]string
+		didInsert bool
+		expected  *sm.NIDPreview
+	}{{
+		// Lowest free NID, skipping the reserved ones and those in use
+		xname:     "x1000c0s0b0n0",
+		policies:  []*sm.NIDPolicy{rsv, seq, geo},
+		inUse:     map[int64][]string{3: {"x1000c0s1b0n0"}},
+		didInsert: true,
+		expected: &sm.NIDPreview{ID: "x1000c0s0b0n0", NID: 4,
+			Source: sm.NIDSourcePolicy, Policy: "x1000"},
+	}, {
+		// A NID used by the node itself is free for it
+		xname:     "x1000c0s0b0n0",
+		policies:  []*sm.NIDPolicy{seq},
+		inUse:     map[int64][]string{1: {"x1000c0s0b0n0"}},
+		didInsert: true,
+		expected: &sm.NIDPreview{ID: "x1000c0s0b0n0", NID: 1,
+			Source: sm.NIDSourcePolicy, Policy: "x1000"},
+	}, {
+		// Out of scope, so by geometry
+		xname:     "x1001c0s0b0n0",
+		policies:  []*sm.NIDPolicy{seq, geo},
+		didInsert: true,
+		expected: &sm.NIDPreview{ID: "x1001c0s0b0n0", NID: 100000000 + 1002*16384,
+			Source: sm.NIDSourcePolicy, Policy: "geo"},
+	}, {
+		// Full range and geometry above End, so the default
+		xname:    "x1000c0s0b0n0",
+		policies: []*sm.NIDPolicy{rsv, seq, geoMax},
+		inUse: map[int64][]string{
+			3: {"x1000c0s1b0n0"},
+			4: {"x1000c0s2b0n0"},
+		},
+		expected: &sm.NIDPreview{ID: "x1000c0s0b0n0", NID: 1001 * 16384,
+			Source: sm.NIDSourceDefault, Conflicts: []string{
+				"x1000: no free NID from 1 to 4",
+				"geomax: NID 16400384 is above 100",
+			}},
+	}, {
+		// Another instance assigned every free NID first
+		xname:    "x1000c0s0b0n0",
+		policies: []*sm.NIDPolicy{seq},
+		expected: &sm.NIDPreview{ID: "x1000c0s0b0n0", NID: 1001 * 16384,
+			Source: sm.NIDSourceDefault, Conflicts: []string{
+				"x1000: NID 1 was assigned elsewhere",
+			}},
+	}, {
+		// Already assigned
+		xname:    "x1000c0s0b0n0",
+		policies: []*sm.NIDPolicy{seq},
+		as: []*sm.NIDAssignment{{ID: "x1000c0s0b0n0", NID: 7,
+			Policy: "old"}},
+		expected: &sm.NIDPreview{ID: "x1000c0s0b0n0", NID: 7,
+			Source: sm.NIDSourceAssignment, Policy: "old"},
+	}}
+	for i, test := range tests {
+		resetNIDPolicyResults()
+		results.GetNIDPolicies.Return.policies = test.policies
+		results.GetNIDAssignments.Return.as = test.as
+		results.GetNIDsInUse.Return.inUse = test.inUse
+		results.InsertNIDAssignment.Return.didInsert = test.didInsert
+		na, err := s.newNIDAssigner(true)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %s", i, err)
+		}
+		pv, err := na.assign(test.xname)
+		if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, pv) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, pv)
+		}
+		if test.didInsert {
+			a := results.InsertNIDAssignment.Input.a
+			if a == nil || a.ID != test.xname || a.NID != pv.NID ||
+				a.Policy != pv.Policy {
+				t.Errorf("Test %d: expected the assignment to be recorded, got %+v",
+					i, a)
+			}
+		}
+	}
+}
+
+func TestPolicyNID(t *testing.T) {
+	defer resetNIDPolicyResults()
+
+	// No policies, so the default
+	resetNIDPolicyResults()
+	if nid := s.policyNID("x1000c0s0b0n0"); nid != 1001*16384 {
+		t.Errorf("Expected the default NID, got %d", nid)
+	}
+
+	// Existing nodes keep their NIDs
+	results.GetNIDPolicies.Return.policies = []*sm.NIDPolicy{{Name: "seq",
+		Type: sm.NIDPolicySequential, Start: 1, End: 10}}
+	results.GetComponentByID.Return.id = &base.Component{ID: "x1000c0s0b0n0",
+		NID: json.Number("42")}
+	if nid := s.policyNID("x1000c0s0b0n0"); nid != 42 {
+		t.Errorf("Expected the existing NID, got %d", nid)
+	}
+	if results.InsertNIDAssignment.Input.a != nil {
+		t.Errorf("Expected no assignment, got %+v",
+			results.InsertNIDAssignment.Input.a)
+	}
+
+	results.GetComponentByID.Return.id = nil
+	results.InsertNIDAssignment.Return.didInsert = true
+	if nid := s.policyNID("x1000c0s0b0n0"); nid != 1 {
+		t.Errorf("Expected NID 1, got %d", nid)
+	}
+
+	// Only for nodes
+	results.InsertNIDAssignment.Input.a = nil
+	s.policyNID("x1000c0s0b0")
+	if results.InsertNIDAssignment.Input.a != nil {
+		t.Errorf("Expected no assignment, got %+v",
+			results.InsertNIDAssignment.Input.a)
+	}
+}
+
+func TestDoNIDPolicyPut(t *testing.T) {
+	defer resetNIDPolicyResults()
+
+	tests := []struct {
+		body     string
+		code     int
+		expected *sm.NIDPolicy
+	}{{
+		body: `{"Type":"sequential","Scope":"X1000","Priority":1,"Start":1,"End":1000}`,
+		code: http.StatusOK,
+		expected: &sm.NIDPolicy{Name: "p1", Type: sm.NIDPolicySequential,
+			Scope: "x1000", Priority: 1, Start: 1, End: 1000},
+	}, {
+		body:     `{"Type":"Geometry"}`,
+		code:     http.StatusOK,
+		expected: &sm.NIDPolicy{Name: "p1", Type: sm.NIDPolicyGeometry},
+	}, {
+		body: `{"Type":"Reserved","Start":10,"End":5}`,
+		code: http.StatusBadRequest,
+	}, {
+		body: `{"Type":"Sequential","Start":0,"End":5}`,
+		code: http.StatusBadRequest,
+	}, {
+		body: `{"Type":"Random","Start":1,"End":5}`,
+		code: http.StatusBadRequest,
+	}, {
+		body: `{"Type":"Geometry","Scope":"foo"}`,
+		code: http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		resetNIDPolicyResults()
+		req, _ := http.NewRequest("PUT",
+			"https://localhost/hsm/v2/Defaults/NIDPolicies/p1",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+		} else if !reflect.DeepEqual(test.expected,
+			results.UpsertNIDPolicy.Input.p) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected,
+				results.UpsertNIDPolicy.Input.p)
+		}
+	}
+}
+
+func TestDoNIDPolicyPreviewPost(t *testing.T) {
+	defer resetNIDPolicyResults()
+
+	resetNIDPolicyResults()
+	results.GetNIDPolicies.Return.policies = []*sm.NIDPolicy{{Name: "seq",
+		Type: sm.NIDPolicySequential, Start: 1, End: 2}}
+	req, _ := http.NewRequest("POST",
+		"https://localhost/hsm/v2/Defaults/NIDPolicies/Actions/Preview",
+		bytes.NewBufferString(`{"IDs":["x1000c0s0b0n0","x1000c0s0b0n1","x1000c0s1b0n0"]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var pva sm.NIDPreviewArray
+	if err := json.Unmarshal(w.Body.Bytes(), &pva); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	// Each node gets the next NID, until there are none left.
+	expected := sm.NIDPreviewArray{NIDs: []*sm.NIDPreview{
+		{ID: "x1000c0s0b0n0", NID: 1, Source: sm.NIDSourcePolicy, Policy: "seq"},
+		{ID: "x1000c0s0b0n1", NID: 2, Source: sm.NIDSourcePolicy, Policy: "seq"},
+		{ID: "x1000c0s1b0n0", NID: 1001*16384 + 32, Source: sm.NIDSourceDefault,
+			Conflicts: []string{"seq: no free NID from 1 to 2"}},
+	}}
+	if !reflect.DeepEqual(expected, pva) {
+		t.Errorf("Expected %+v, got %+v", expected, pva)
+	}
+	if results.InsertNIDAssignment.Input.a != nil {
+		t.Errorf("Expected no assignment, got %+v",
+			results.InsertNIDAssignment.Input.a)
+	}
+
+	// NodeMaps come first
+	results.GetNodeMapByID.Return.entry = &sm.NodeMap{ID: "x1000c0s0b0n0", NID: 9}
+	req, _ = http.NewRequest("POST",
+		"https://localhost/hsm/v2/Defaults/NIDPolicies/Actions/Preview",
+		bytes.NewBufferString(`{"IDs":["x1000c0s0b0n0"]}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"Source":"NodeMap"`)) {
+		t.Errorf("Expected the NodeMap NID, got %d: %s", w.Code,
+			w.Body.String())
+	}
+
+	req, _ = http.NewRequest("POST",
+		"https://localhost/hsm/v2/Defaults/NIDPolicies/Actions/Preview",
+		bytes.NewBufferString(`{"IDs":["x1000c0s0b0"]}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a non-node, got %d", w.Code)
+	}
+}
@@ -0,0 +1,331 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// NodeMaps in CSV
+//
+// GET /Defaults/NodeMaps?format=csv returns the NodeMaps as CSV with a
+// header row:
+//
+//     xname,nid,role,subrole
+//
+// POST /Defaults/NodeMaps/Import takes the same.  The header names the
+// columns, in any order; xname (or id) and nid are required and role and
+// subrole are optional.  Each row is checked on its own, and rows that are
+// invalid or conflict are skipped while the rest are applied, so one bad
+// line doesn't hold up the rest.  A row conflicts if its xname or NID is on
+// an earlier row, or its NID already belongs to another xname: another
+// node's NodeMap (unless that one is also being imported), a component, or
+// a NID policy assignment.
+//
+// The response has the result of each row.  With dryrun=true nothing is
+// stored and the results are what importing would do.
+///////////////////////////////////////////////////////////////////////////////
+
+const NodeMapsCSVMediaType = "text/csv"
+
+// Columns of NodeMaps in CSV, in export order.
+var nodeMapCSVColumns = []string{"xname", "nid", "role", "subrole"}
+
+var errNodeMapCSVNoHeader = errors.New("no CSV header")
+
+// A row being imported, with the NodeMap if it is valid.
+type nodeMapImportRow struct {
+	res *sm.NodeMapImportRow
+	m   *sm.NodeMap
+}
+
+// Write the NodeMaps as CSV, with the header row.
+func writeNodeMapsCSV(w io.Writer, maps []*sm.NodeMap) error {
+	cw := csv.NewWriter(w)
+	cw.Write(nodeMapCSVColumns)
+	for _, m := range maps {
+		cw.Write([]string{m.ID, strconv.Itoa(m.NID), m.Role, m.SubRole})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Read NodeMaps from CSV.  Errors are for the CSV as a whole; rows with
+// bad values are returned with an Invalid result instead.
+func readNodeMapsCSV(r io.Reader) ([]*nodeMapImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errNodeMapCSVNoHeader
+	} else if err != nil {
+		return nil, err
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "id" {
+			name = "xname"
+		}
+		if !nodeMapCSVColumnValid(name) {
+			return nil, fmt.Errorf("unknown column '%s'", name)
+		} else if _, ok := cols[name]; ok {
+			return nil, fmt.Errorf("column '%s' given twice", name)
+		}
+		cols[name] = i
+	}
+	if _, ok := cols["xname"]; !ok {
+		return nil, errors.New("no xname column")
+	} else if _, ok := cols["nid"]; !ok {
+		return nil, errors.New("no nid column")
+	}
+
+	rows := []*nodeMapImportRow{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+		line, _ := cr.FieldPos(0)
+		row := &nodeMapImportRow{res: &sm.NodeMapImportRow{
+			Row:     line,
+			ID:      field("xname"),
+			Role:    field("role"),
+			SubRole: field("subrole"),
+		}}
+		rows = append(rows, row)
+
+		nid, err := strconv.Atoi(field("nid"))
+		if err != nil {
+			row.res.Result = sm.NodeMapImportInvalid
+			row.res.Error = fmt.Sprintf("NID '%s' is not a number", field("nid"))
+			continue
+		}
+		row.res.NID = nid
+		m, err := sm.NewNodeMap(row.res.ID, row.res.Role, row.res.SubRole,
+			nid, nil)
+		if err != nil {
+			row.res.Result = sm.NodeMapImportInvalid
+			row.res.Error = err.Error()
+			continue
+		}
+		row.m = m
+		row.res.ID, row.res.Role, row.res.SubRole = m.ID, m.Role, m.SubRole
+	}
+	return rows, nil
+}
+
+func nodeMapCSVColumnValid(col string) bool {
+	for _, c := range nodeMapCSVColumns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark the valid rows that conflict with earlier rows or with NIDs in use,
+// and set the result of the rest: Created, Updated or Unchanged.
+func (s *SmD) checkNodeMapImport(rows []*nodeMapImportRow) error {
+	conflict := func(row *nodeMapImportRow, format string, a ...interface{}) {
+		row.m = nil
+		row.res.Result = sm.NodeMapImportConflict
+		row.res.Error = fmt.Sprintf(format, a...)
+	}
+
+	// Conflicts within the import
+	rowByID := map[string]int{}
+	rowByNID := map[int]int{}
+	for _, row := range rows {
+		if row.m == nil {
+			continue
+		}
+		if prev, ok := rowByID[row.m.ID]; ok {
+			conflict(row, "xname %s is also on row %d", row.m.ID, prev)
+		} else if prev, ok := rowByNID[row.m.NID]; ok {
+			conflict(row, "NID %d is also on row %d", row.m.NID, prev)
+		} else {
+			rowByID[row.m.ID] = row.res.Row
+			rowByNID[row.m.NID] = row.res.Row
+		}
+	}
+	if len(rowByID) == 0 {
+		return nil
+	}
+
+	// Conflicts with what is already there.  NodeMaps of xnames that are
+	// being imported are replaced, so their NIDs are free.
+	maps, err := s.db.GetNodeMapsAll()
+	if err != nil {
+		return err
+	}
+	mapByID := map[string]*sm.NodeMap{}
+	mapByNID := map[int]string{}
+	for _, m := range maps {
+		mapByID[m.ID] = m
+		if _, ok := rowByID[m.ID]; !ok {
+			mapByNID[m.NID] = m.ID
+		}
+	}
+	nids := make([]string, 0, len(rowByNID))
+	for nid := range rowByNID {
+		nids = append(nids, strconv.Itoa(nid))
+	}
+	comps, err := s.db.GetComponentsFilter(&hmsds.ComponentFilter{NID: nids},
+		hmsds.FLTR_NIDONLY)
+	if err != nil {
+		return err
+	}
+	compByNID := map[int]string{}
+	for _, comp := range comps {
+		if nid, err := comp.NID.Int64(); err == nil {
+			compByNID[int(nid)] = comp.ID
+		}
+	}
+	as, err := s.db.GetNIDAssignments(nil)
+	if err != nil {
+		return err
+	}
+	assignByNID := map[int]*sm.NIDAssignment{}
+	for _, a := range as {
+		assignByNID[int(a.NID)] = a
+	}
+
+	for _, row := range rows {
+		m := row.m
+		if m == nil {
+			continue
+		}
+		if id, ok := mapByNID[m.NID]; ok && id != m.ID {
+			conflict(row, "NID %d is mapped to %s", m.NID, id)
+		} else if id, ok := compByNID[m.NID]; ok && id != m.ID {
+			conflict(row, "NID %d is used by component %s", m.NID, id)
+		} else if a, ok := assignByNID[m.NID]; ok && a.ID != m.ID {
+			conflict(row, "NID %d is assigned to %s by NID policy %s",
+				m.NID, a.ID, a.Policy)
+		} else if old, ok := mapByID[m.ID]; !ok {
+			row.res.Result = sm.NodeMapImportCreated
+		} else if old.NID == m.NID && old.Role == m.Role &&
+			old.SubRole == m.SubRole {
+			row.res.Result = sm.NodeMapImportUnchanged
+		} else {
+			row.res.Result = sm.NodeMapImportUpdated
+		}
+	}
+	return nil
+}
+
+// Import NodeMaps from CSV, applying the rows that are valid and don't
+// conflict, unless dryrun=true.
+func (s *SmD) doNodeMapsImportPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	dryRun := false
+	if val := r.URL.Query().Get("dryrun"); val != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(val); err != nil {
+			sendJsonError(w, http.StatusBadRequest,
+				"dryrun must be true or false")
+			return
+		}
+	}
+	rows, err := readNodeMapsCSV(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding CSV: "+err.Error())
+		return
+	}
+	if err := s.checkNodeMapImport(rows); err != nil {
+		s.reqLog(r).LogAlways("doNodeMapsImportPost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+
+	code := http.StatusOK
+	if !dryRun {
+		nnms := new(sm.NodeMapArray)
+		for _, row := range rows {
+			if row.res.Result == sm.NodeMapImportCreated ||
+				row.res.Result == sm.NodeMapImportUpdated {
+				nnms.NodeMaps = append(nnms.NodeMaps, row.m)
+			}
+		}
+		if len(nnms.NodeMaps) > 0 {
+			err = s.db.InsertNodeMaps(nnms)
+		}
+		if err != nil {
+			s.reqLog(r).LogAlways("doNodeMapsImportPost(): Store failure: %s",
+				err)
+			msg := "failed during store"
+			code = http.StatusInternalServerError
+			if err == hmsds.ErrHMSDSDuplicateKey {
+				// Something else took a NID since it was checked.
+				msg = "would conflict with an existing xname ID that has " +
+					"the same NID"
+				code = http.StatusConflict
+			}
+			for _, row := range rows {
+				if row.res.Result == sm.NodeMapImportCreated ||
+					row.res.Result == sm.NodeMapImportUpdated {
+					row.res.Result = sm.NodeMapImportFailed
+					row.res.Error = msg
+				}
+			}
+		} else {
+			s.reqLog(r).LogAlways("doNodeMapsImportPost(): Stored %d NodeMaps",
+				len(nnms.NodeMaps))
+		}
+	}
+	res := &sm.NodeMapImportResult{
+		DryRun:  dryRun,
+		Summary: map[string]int{},
+		Rows:    make([]*sm.NodeMapImportRow, 0, len(rows)),
+	}
+	for _, row := range rows {
+		res.Summary[row.res.Result]++
+		res.Rows = append(res.Rows, row.res)
+	}
+	sendJsonObject(w, code, res)
+}
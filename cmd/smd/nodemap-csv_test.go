@@ -0,0 +1,260 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func resetNodeMapImportResults() {
+	results.GetNodeMapsAll.Return.entry = nil
+	results.GetNodeMapsAll.Return.err = nil
+	results.InsertNodeMaps.Input.nnms = nil
+	results.InsertNodeMaps.Return.err = nil
+	results.GetComponentsFilter.Return.ids = nil
+	results.GetComponentsFilter.Return.err = nil
+	results.GetNIDAssignments.Return.as = nil
+	results.GetNIDAssignments.Return.err = nil
+}
+
+func TestDoNodeMapsGetCSV(t *testing.T) {
+	defer resetNodeMapImportResults()
+
+	resetNodeMapImportResults()
+	results.GetNodeMapsAll.Return.entry = []*sm.NodeMap{
+		{ID: "x0c0s0b0n0", NID: 1, Role: "Compute"},
+		{ID: "x0c0s0b0n1", NID: 2, Role: "Application", SubRole: "UAN"},
+	}
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Defaults/NodeMaps?format=csv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	expected := "xname,nid,role,subrole\n" +
+		"x0c0s0b0n0,1,Compute,\n" +
+		"x0c0s0b0n1,2,Application,UAN\n"
+	if w.Code != http.StatusOK || w.Body.String() != expected {
+		t.Errorf("Expected %q, got %d: %q", expected, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != NodeMapsCSVMediaType {
+		t.Errorf("Expected Content-Type %s, got %s", NodeMapsCSVMediaType, ct)
+	}
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/Defaults/NodeMaps?format=xml", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad format, got %d", w.Code)
+	}
+}
+
+func TestReadNodeMapsCSV(t *testing.T) {
+	tests := []struct {
+		csv      string
+		expected []*sm.NodeMapImportRow
+		err      bool
+	}{{
+		csv: "NID, ID, SubRole\n" +
+			"1, X0C0S0B0N0\n" +
+			"x,x0c0s0b0n1\n" +
+			"3,x0c0s0b0\n" +
+			"4,x0c0s0b0n2,foo\n",
+		expected: []*sm.NodeMapImportRow{
+			{Row: 2, ID: "x0c0s0b0n0", NID: 1},
+			{Row: 3, ID: "x0c0s0b0n1", Result: sm.NodeMapImportInvalid,
+				Error: "NID 'x' is not a number"},
+			{Row: 4, ID: "x0c0s0b0", NID: 3, Result: sm.NodeMapImportInvalid,
+				Error: "xname ID 'x0c0s0b0' is invalid or not a node"},
+			{Row: 5, ID: "x0c0s0b0n2", NID: 4, SubRole: "foo",
+				Result: sm.NodeMapImportInvalid,
+				Error:  "SubRole 'foo' is not valid."},
+		},
+	}, {
+		csv: "",
+		err: true,
+	}, {
+		csv: "xname,nid,color\n",
+		err: true,
+	}, {
+		csv: "xname,role\n",
+		err: true,
+	}, {
+		csv: "xname,nid,nid\n",
+		err: true,
+	}}
+	for i, test := range tests {
+		rows, err := readNodeMapsCSV(strings.NewReader(test.csv))
+		if test.err {
+			if err == nil {
+				t.Errorf("Test %d: expected an error", i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+			continue
+		}
+		got := []*sm.NodeMapImportRow{}
+		for _, row := range rows {
+			got = append(got, row.res)
+		}
+		if !reflect.DeepEqual(test.expected, got) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, got)
+		}
+	}
+}
+
+func TestDoNodeMapsImportPost(t *testing.T) {
+	defer resetNodeMapImportResults()
+
+	body := "xname,nid,role\n" +
+		"x0c0s0b0n0,1,Compute\n" + // Unchanged
+		"x0c0s0b0n1,20,Compute\n" + // Updated, freeing NID 2
+		"x0c0s1b0n0,2\n" + // Created with the freed NID
+		"x0c0s1b0n1,1\n" + // Same NID as row 2
+		"x0c0s1b0n0,5\n" + // Same xname as row 4
+		"x0c0s2b0n0,3\n" + // Another node's NodeMap
+		"x0c0s2b0n1,4\n" + // A component
+		"x0c0s2b0n2,6\n" + // A NID assignment
+		"x0c0s2b0n3,0\n" // Invalid
+	tests := []struct {
+		dryRun    string
+		storeErr  error
+		code      int
+		stored    []string
+		summary   map[string]int
+		conflicts map[int]string
+	}{{
+		dryRun: "true",
+		code:   http.StatusOK,
+		summary: map[string]int{
+			sm.NodeMapImportUnchanged: 1,
+			sm.NodeMapImportUpdated:   1,
+			sm.NodeMapImportCreated:   1,
+			sm.NodeMapImportConflict:  5,
+			sm.NodeMapImportInvalid:   1,
+		},
+		conflicts: map[int]string{
+			5: "NID 1 is also on row 2",
+			6: "xname x0c0s1b0n0 is also on row 4",
+			7: "NID 3 is mapped to x0c0s9b0n0",
+			8: "NID 4 is used by component x0c0s9b0n1",
+			9: "NID 6 is assigned to x0c0s9b0n2 by NID policy seq",
+		},
+	}, {
+		code:   http.StatusOK,
+		stored: []string{"x0c0s0b0n1", "x0c0s1b0n0"},
+		summary: map[string]int{
+			sm.NodeMapImportUnchanged: 1,
+			sm.NodeMapImportUpdated:   1,
+			sm.NodeMapImportCreated:   1,
+			sm.NodeMapImportConflict:  5,
+			sm.NodeMapImportInvalid:   1,
+		},
+	}, {
+		storeErr: hmsds.ErrHMSDSDuplicateKey,
+		code:     http.StatusConflict,
+		stored:   []string{"x0c0s0b0n1", "x0c0s1b0n0"},
+		summary: map[string]int{
+			sm.NodeMapImportUnchanged: 1,
+			sm.NodeMapImportFailed:    2,
+			sm.NodeMapImportConflict:  5,
+			sm.NodeMapImportInvalid:   1,
+		},
+	}}
+	for i, test := range tests {
+		resetNodeMapImportResults()
+		results.GetNodeMapsAll.Return.entry = []*sm.NodeMap{
+			{ID: "x0c0s0b0n0", NID: 1, Role: "Compute"},
+			{ID: "x0c0s0b0n1", NID: 2, Role: "Compute"},
+			{ID: "x0c0s9b0n0", NID: 3},
+		}
+		results.GetComponentsFilter.Return.ids = []*base.Component{
+			{ID: "x0c0s0b0n0", NID: json.Number("1")},
+			{ID: "x0c0s9b0n1", NID: json.Number("4")},
+		}
+		results.GetNIDAssignments.Return.as = []*sm.NIDAssignment{
+			{ID: "x0c0s9b0n2", NID: 6, Policy: "seq"},
+		}
+		results.InsertNodeMaps.Return.err = test.storeErr
+
+		url := "https://localhost/hsm/v2/Defaults/NodeMaps/Import"
+		if test.dryRun != "" {
+			url += "?dryrun=" + test.dryRun
+		}
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		var res sm.NodeMapImportResult
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(test.summary, res.Summary) {
+			t.Errorf("Test %d: expected summary %v, got %v", i, test.summary,
+				res.Summary)
+		}
+		for _, row := range res.Rows {
+			if msg, ok := test.conflicts[row.Row]; ok && msg != row.Error {
+				t.Errorf("Test %d: expected row %d error '%s', got '%s'", i,
+					row.Row, msg, row.Error)
+			}
+		}
+		stored := []string{}
+		if nnms := results.InsertNodeMaps.Input.nnms; nnms != nil {
+			for _, m := range nnms.NodeMaps {
+				stored = append(stored, m.ID)
+			}
+		}
+		if test.stored == nil {
+			test.stored = []string{}
+		}
+		if !reflect.DeepEqual(test.stored, stored) {
+			t.Errorf("Test %d: expected %v stored, got %v", i, test.stored,
+				stored)
+		}
+	}
+
+	req, _ := http.NewRequest("POST",
+		"https://localhost/hsm/v2/Defaults/NodeMaps/Import",
+		bytes.NewBufferString("xname\n"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a bad header, got %d", w.Code)
+	}
+}
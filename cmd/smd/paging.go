@@ -0,0 +1,360 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Collection paging
+//
+// The collection GETs for Components, RedfishEndpoints, ComponentEndpoints
+// and the hardware inventory take these query parameters on top of their
+// usual filters:
+//
+//     limit=N         Return at most N entries.
+//     after=<cursor>  Continue after the last entry of the previous page.
+//     sort=F1,-F2     Order by these fields, '-' for descending.
+//     fields=F1,F2    Only return these fields of each entry.
+//
+// Field names are those of the JSON entries, matched without regard to case.
+// Entries are always ordered by their ID after any sort fields, so the order
+// is stable and defaults to ID order.  Entries without a sort field come
+// first.
+//
+// When there are more entries after a page, the response has a Link header
+// with rel="next", whose URL has the cursor for the next page.  The cursor
+// is opaque, holding the sort values and ID of the last entry returned, so
+// paging carries on from the right place even if entries are added or
+// removed in between.  X-Total-Count has how many entries matched the
+// filters.
+//
+// Responses only change when one of these parameters is given.  Paged
+// component responses always use the regular JSON encoding.
+///////////////////////////////////////////////////////////////////////////////
+
+var pageParams = []string{"limit", "after", "sort", "fields"}
+
+var errPageLimit = errors.New("limit must be a positive integer")
+var errPageCursor = errors.New("invalid after cursor")
+
+type pageSortKey struct {
+	field string // JSON name
+	desc  bool
+}
+
+// The paging requested for a collection GET.
+type listPaging struct {
+	idField string        // JSON name of the entries' ID
+	limit   int           // 0 for no limit
+	sort    []pageSortKey // Before idField
+	fields  []string      // JSON names, nil for all
+	after   []interface{} // Sort values then ID of the last entry
+}
+
+// Parse the paging query parameters for a collection of entries like
+// entry, whose ID is idField.  Returns nil, nil if there are none, so the
+// collection is sent as usual.
+func parseListPaging(form url.Values, entry interface{}, idField string) (*listPaging, error) {
+	given := false
+	for _, p := range pageParams {
+		if _, ok := form[p]; ok {
+			given = true
+		}
+	}
+	if !given {
+		return nil, nil
+	}
+	names := make(map[string]string)
+	jsonFieldNames(reflect.TypeOf(entry), names)
+	field := func(f string) (string, error) {
+		name, ok := names[strings.ToLower(f)]
+		if !ok {
+			return "", fmt.Errorf("unknown field: %s", f)
+		}
+		return name, nil
+	}
+
+	pg := &listPaging{idField: idField}
+	if limit := form.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return nil, errPageLimit
+		}
+		pg.limit = n
+	}
+	for _, f := range splitPageList(form["sort"]) {
+		key := pageSortKey{}
+		if strings.HasPrefix(f, "-") {
+			key.desc = true
+			f = f[1:]
+		}
+		name, err := field(f)
+		if err != nil {
+			return nil, err
+		}
+		key.field = name
+		pg.sort = append(pg.sort, key)
+	}
+	for _, f := range splitPageList(form["fields"]) {
+		name, err := field(f)
+		if err != nil {
+			return nil, err
+		}
+		pg.fields = append(pg.fields, name)
+	}
+	if after := form.Get("after"); after != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil {
+			return nil, errPageCursor
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if dec.Decode(&pg.after) != nil || len(pg.after) != len(pg.sort)+1 {
+			return nil, errPageCursor
+		}
+	}
+	return pg, nil
+}
+
+// Comma separated and/or repeated list values.
+func splitPageList(vals []string) []string {
+	list := []string{}
+	for _, val := range vals {
+		for _, v := range strings.Split(val, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+	}
+	return list
+}
+
+// Add the JSON names of the fields of struct type t, keyed by their lower
+// case form.  Fields of embedded structs are included as encoding/json
+// promotes them.
+func jsonFieldNames(t reflect.Type, names map[string]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" {
+			jsonFieldNames(sf.Type, names)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		names[strings.ToLower(name)] = name
+	}
+}
+
+// Order of JSON values of different kinds.
+func pageValueRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case json.Number:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
+
+func comparePageValues(a, b interface{}) int {
+	ra, rb := pageValueRank(a), pageValueRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	switch av := a.(type) {
+	case bool:
+		bv := b.(bool)
+		if av == bv {
+			return 0
+		} else if !av {
+			return -1
+		}
+		return 1
+	case json.Number:
+		af, aerr := av.Float64()
+		bf, berr := b.(json.Number).Float64()
+		if aerr == nil && berr == nil && af != bf {
+			if af < bf {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(av.String(), b.(json.Number).String())
+	case string:
+		return strings.Compare(av, b.(string))
+	case nil:
+		return 0
+	}
+	// Objects and arrays, which nobody should be sorting by.
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return bytes.Compare(aj, bj)
+}
+
+// Sort values then ID of an entry.
+func (pg *listPaging) keyOf(entry map[string]interface{}) []interface{} {
+	key := make([]interface{}, 0, len(pg.sort)+1)
+	for _, sk := range pg.sort {
+		key = append(key, entry[sk.field])
+	}
+	return append(key, entry[pg.idField])
+}
+
+func (pg *listPaging) compareKeys(a, b []interface{}) int {
+	for i := range a {
+		c := comparePageValues(a[i], b[i])
+		if i < len(pg.sort) && pg.sort[i].desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Sort the entries in the slice items, then return the page of them after
+// the cursor, with only the requested fields, along with the cursor for the
+// next page ("" if this is the last) and how many entries there were.
+func (pg *listPaging) apply(items interface{}) ([]map[string]interface{}, string, int, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	entries := []map[string]interface{}{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&entries); err != nil {
+		return nil, "", 0, err
+	}
+	total := len(entries)
+	keys := make([][]interface{}, len(entries))
+	for i, e := range entries {
+		keys[i] = pg.keyOf(e)
+	}
+	sort.Sort(pageSorter{pg, entries, keys})
+
+	start := 0
+	if pg.after != nil {
+		start = sort.Search(len(entries), func(i int) bool {
+			return pg.compareKeys(keys[i], pg.after) > 0
+		})
+	}
+	end := len(entries)
+	next := ""
+	if pg.limit > 0 && start+pg.limit < end {
+		end = start + pg.limit
+		cursor, err := json.Marshal(keys[end-1])
+		if err != nil {
+			return nil, "", 0, err
+		}
+		next = base64.RawURLEncoding.EncodeToString(cursor)
+	}
+	page := entries[start:end]
+	if pg.fields != nil {
+		for i, e := range page {
+			projected := make(map[string]interface{}, len(pg.fields))
+			for _, f := range pg.fields {
+				if v, ok := e[f]; ok {
+					projected[f] = v
+				}
+			}
+			page[i] = projected
+		}
+	}
+	return page, next, total, nil
+}
+
+type pageSorter struct {
+	pg      *listPaging
+	entries []map[string]interface{}
+	keys    [][]interface{}
+}
+
+func (ps pageSorter) Len() int { return len(ps.entries) }
+func (ps pageSorter) Less(i, j int) bool {
+	return ps.pg.compareKeys(ps.keys[i], ps.keys[j]) < 0
+}
+func (ps pageSorter) Swap(i, j int) {
+	ps.entries[i], ps.entries[j] = ps.entries[j], ps.entries[i]
+	ps.keys[i], ps.keys[j] = ps.keys[j], ps.keys[i]
+}
+
+// Send a page of the entries in the slice items.  If member is set the page
+// is sent as that field of an object, like the regular response, otherwise
+// as a bare array.
+func sendListPage(w http.ResponseWriter, r *http.Request, pg *listPaging, member string, items interface{}) {
+	page, next, total, err := pg.apply(items)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to encode response.")
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if next != "" {
+		// Relative to the request URL, so it holds up behind proxies that
+		// rewrite the path.
+		q := r.URL.Query()
+		q.Set("after", next)
+		w.Header().Set("Link", "<?"+q.Encode()+">; rel=\"next\"")
+	}
+	if member == "" {
+		sendJsonObject(w, http.StatusOK, page)
+	} else {
+		sendJsonObject(w, http.StatusOK, map[string]interface{}{member: page})
+	}
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestParseListPaging(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectNil   bool
+		expectedErr string
+	}{
+		{"id=x0c0s0b0n0&type=node", true, ""},
+		{"limit=10", false, ""},
+		{"limit=0", false, errPageLimit.Error()},
+		{"limit=ten", false, errPageLimit.Error()},
+		{"sort=state,-NID&fields=id,State", false, ""},
+		{"sort=foo", false, "unknown field: foo"},
+		{"fields=ID,bar", false, "unknown field: bar"},
+		{"after=!!!", false, errPageCursor.Error()},
+		// Cursor must have a value per sort field plus the ID
+		{"sort=state&after=" + pageCursor(`["x0c0s0b0n0"]`), false, errPageCursor.Error()},
+		{"sort=state&after=" + pageCursor(`["Ready","x0c0s0b0n0"]`), false, ""},
+	}
+	for i, test := range tests {
+		form, _ := url.ParseQuery(test.query)
+		pg, err := parseListPaging(form, base.Component{}, "ID")
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("Test %v Failed: Expected error '%s'; Received '%v'",
+					i, test.expectedErr, err)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+		} else if (pg == nil) != test.expectNil {
+			t.Errorf("Test %v Failed: Expected nil paging %v; Received %+v",
+				i, test.expectNil, pg)
+		}
+	}
+
+	// Embedded struct fields are promoted
+	form, _ := url.ParseQuery("fields=id,redfishtype,odataid")
+	pg, err := parseListPaging(form, sm.ComponentEndpoint{}, "ID")
+	if err != nil {
+		t.Errorf("Unexpected error for ComponentEndpoint fields: %s", err)
+	} else if strings.Join(pg.fields, ",") != "ID,RedfishType,OdataID" {
+		t.Errorf("Unexpected ComponentEndpoint fields: %v", pg.fields)
+	}
+}
+
+func pageCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func TestDoComponentsGetPaging(t *testing.T) {
+	comps := []*base.Component{
+		{ID: "x0c0s0b0n2", State: "Ready", NID: json.Number("3")},
+		{ID: "x0c0s0b0n0", State: "Ready", NID: json.Number("1")},
+		{ID: "x0c0s0b0n1", State: "Off", NID: json.Number("2")},
+		{ID: "x0c0s0b0", State: "Ready"},
+	}
+	defer func() {
+		results.GetComponentsFilter.Return.ids = nil
+		results.GetComponentsFilter.Return.err = nil
+	}()
+	results.GetComponentsFilter.Return.ids = comps
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET",
+			"https://localhost/hsm/v2/State/Components?"+query, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Walk all the pages sorted by descending state, then ID
+	expected := []string{
+		`{"Components":[{"ID":"x0c0s0b0","State":"Ready"},{"ID":"x0c0s0b0n0","State":"Ready"}]}`,
+		`{"Components":[{"ID":"x0c0s0b0n2","State":"Ready"},{"ID":"x0c0s0b0n1","State":"Off"}]}`,
+	}
+	query := "sort=-state&fields=id,state&limit=2"
+	for i, exp := range expected {
+		w := get(query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Page %v Failed: Response code was %v; want %v", i, w.Code, http.StatusOK)
+		}
+		if strings.TrimSpace(w.Body.String()) != exp {
+			t.Errorf("Page %v Failed: Expected body is '%v'; Received '%v'", i, exp, w.Body)
+		}
+		if w.Header().Get("X-Total-Count") != "4" {
+			t.Errorf("Page %v Failed: X-Total-Count was '%s'", i, w.Header().Get("X-Total-Count"))
+		}
+		link := w.Header().Get("Link")
+		if i == len(expected)-1 {
+			if link != "" {
+				t.Errorf("Page %v Failed: Unexpected Link on last page: %s", i, link)
+			}
+			break
+		}
+		if !strings.HasPrefix(link, "<?") || !strings.HasSuffix(link, `>; rel="next"`) {
+			t.Fatalf("Page %v Failed: Bad Link header: '%s'", i, link)
+		}
+		query = strings.TrimSuffix(strings.TrimPrefix(link, "<?"), `>; rel="next"`)
+	}
+
+	// Numeric sort, and no paging parameters leaves the response alone
+	w := get("sort=nid&fields=ID")
+	exp := `{"Components":[{"ID":"x0c0s0b0"},{"ID":"x0c0s0b0n0"},{"ID":"x0c0s0b0n1"},{"ID":"x0c0s0b0n2"}]}`
+	if strings.TrimSpace(w.Body.String()) != exp {
+		t.Errorf("Expected body is '%v'; Received '%v'", exp, w.Body)
+	}
+	w = get("")
+	if w.Header().Get("X-Total-Count") != "" ||
+		!strings.HasPrefix(w.Body.String(), `{"Components":[{"ID":"x0c0s0b0n2"`) {
+		t.Errorf("Unexpected unpaged response: %v", w.Body)
+	}
+
+	w = get("sort=color")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Bad sort field: Response code was %v; want %v", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,333 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Power paths
+//
+// PowerMaps only list what directly powers each component.  These follow
+// them through the whole graph:
+//
+//     GET /sysinfo/powermaps/{xname}/Path     everything upstream of xname,
+//                                             e.g. node -> PDU outlet ->
+//                                             PDU -> breaker
+//     GET /sysinfo/powermaps/{xname}/Powered  what loses power if xname is
+//                                             turned off
+//
+// Besides the PowerMaps, a PDU outlet is always powered by the PDU it is
+// in.  A component only loses power if all of its supplies do, so ones with
+// a redundant supply are only degraded.  Cycles in the maps are reported
+// rather than followed.
+///////////////////////////////////////////////////////////////////////////////
+
+// Most paths returned by /Path, in case the maps have a lot of redundancy.
+const powerPathMaxPaths = 1000
+
+// The power graph from the PowerMaps
+type powerGraph struct {
+	suppliers map[string][]string
+	consumers map[string][]string
+}
+
+func newPowerGraph(ms []*sm.PowerMap) *powerGraph {
+	g := &powerGraph{
+		suppliers: make(map[string][]string),
+		consumers: make(map[string][]string),
+	}
+	for _, m := range ms {
+		g.suppliers[m.ID] = append(g.suppliers[m.ID], m.PoweredBy...)
+	}
+	// Add the implied edges of every xname in the maps, in the order seen.
+	known := []string{}
+	seen := map[string]bool{}
+	for _, m := range ms {
+		for _, id := range append([]string{m.ID}, m.PoweredBy...) {
+			if !seen[id] {
+				seen[id] = true
+				known = append(known, id)
+			}
+		}
+	}
+	for _, id := range known {
+		if sup := impliedSupplier(id); sup != "" && !hasString(g.suppliers[id], sup) {
+			g.suppliers[id] = append(g.suppliers[id], sup)
+		}
+	}
+	for _, id := range known {
+		for _, sup := range g.suppliers[id] {
+			g.consumers[sup] = append(g.consumers[sup], id)
+		}
+	}
+	return g
+}
+
+// What powers id regardless of the PowerMaps, if anything.
+func impliedSupplier(id string) string {
+	switch xnametypes.GetHMSType(id) {
+	case xnametypes.CabinetPDUPowerConnector, xnametypes.CabinetPDUOutlet:
+		return xnametypes.GetHMSCompParent(id)
+	}
+	return ""
+}
+
+func hasString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// If id is in the graph at all.
+func (g *powerGraph) has(id string) bool {
+	_, ok := g.suppliers[id]
+	_, ok2 := g.consumers[id]
+	return ok || ok2
+}
+
+// Whether the edge from id to sup was implied rather than mapped.
+func (g *powerGraph) implied(id, sup string) bool {
+	return impliedSupplier(id) == sup
+}
+
+// Everything reachable from id by next, nearest first, not including id.
+func reachable(id string, next map[string][]string) []string {
+	order := []string{}
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range next[cur] {
+			if !seen[n] {
+				seen[n] = true
+				order = append(order, n)
+				queue = append(queue, n)
+			}
+		}
+	}
+	return order
+}
+
+// The cycles reachable from id by next, each starting with its lowest
+// xname, which is also repeated at the end.
+func findCycles(id string, next map[string][]string) [][]string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	cycles := [][]string{}
+	found := map[string]bool{}
+	state := map[string]int{}
+	var walk func(stack []string)
+	walk = func(stack []string) {
+		cur := stack[len(stack)-1]
+		state[cur] = visiting
+		for _, n := range next[cur] {
+			switch state[n] {
+			case visiting:
+				for i, s := range stack {
+					if s == n {
+						cycle := canonicalCycle(stack[i:])
+						key := ""
+						for _, c := range cycle {
+							key += c + ","
+						}
+						if !found[key] {
+							found[key] = true
+							cycles = append(cycles, cycle)
+						}
+						break
+					}
+				}
+			case done:
+			default:
+				walk(append(stack[:len(stack):len(stack)], n))
+			}
+		}
+		state[cur] = done
+	}
+	walk([]string{id})
+	return cycles
+}
+
+// Rotate a cycle to start with its lowest xname, and close it.
+func canonicalCycle(ids []string) []string {
+	low := 0
+	for i, id := range ids {
+		if id < ids[low] {
+			low = i
+		}
+	}
+	cycle := make([]string, 0, len(ids)+1)
+	cycle = append(cycle, ids[low:]...)
+	cycle = append(cycle, ids[:low]...)
+	return append(cycle, ids[low])
+}
+
+// The upstream power path of id.
+func (g *powerGraph) path(id string) *sm.PowerPath {
+	pp := &sm.PowerPath{
+		ID:       id,
+		Supplies: reachable(id, g.suppliers),
+		Paths:    [][]string{},
+		Edges:    []sm.PowerMapEdge{},
+		Cycles:   findCycles(id, g.suppliers),
+	}
+	edgeSeen := map[[2]string]bool{}
+	var walk func(stack []string)
+	walk = func(stack []string) {
+		cur := stack[len(stack)-1]
+		extended := false
+		for _, sup := range g.suppliers[cur] {
+			if !edgeSeen[[2]string{cur, sup}] {
+				edgeSeen[[2]string{cur, sup}] = true
+				pp.Edges = append(pp.Edges, sm.PowerMapEdge{
+					ID:        cur,
+					PoweredBy: sup,
+					Implied:   g.implied(cur, sup),
+				})
+			}
+			if hasString(stack, sup) {
+				continue // A cycle
+			}
+			extended = true
+			if len(pp.Paths) >= powerPathMaxPaths {
+				pp.Truncated = true
+				return
+			}
+			walk(append(stack[:len(stack):len(stack)], sup))
+		}
+		if !extended && len(stack) > 1 && len(pp.Paths) < powerPathMaxPaths {
+			pp.Paths = append(pp.Paths, stack)
+		}
+	}
+	walk([]string{id})
+	if len(pp.Cycles) == 0 {
+		pp.Cycles = nil
+	}
+	return pp
+}
+
+// What loses power if id is turned off.  Everything downstream of id loses
+// power unless it has a supply that doesn't, which is found by dropping
+// those with such a supply until none are left to drop.
+func (g *powerGraph) impact(id string) *sm.PowerImpact {
+	pi := &sm.PowerImpact{
+		ID:         id,
+		LosesPower: []string{},
+		Degraded:   []string{},
+		Cycles:     findCycles(id, g.consumers),
+	}
+	downstream := reachable(id, g.consumers)
+	lost := map[string]bool{id: true}
+	for _, c := range downstream {
+		lost[c] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, c := range downstream {
+			if c == id || !lost[c] {
+				continue
+			}
+			for _, sup := range g.suppliers[c] {
+				if !lost[sup] {
+					lost[c] = false
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for _, c := range downstream {
+		if c == id {
+			continue
+		} else if lost[c] {
+			pi.LosesPower = append(pi.LosesPower, c)
+		} else {
+			pi.Degraded = append(pi.Degraded, c)
+		}
+	}
+	if len(pi.Cycles) == 0 {
+		pi.Cycles = nil
+	}
+	return pi
+}
+
+// Load the power graph for a request about the xname in the URL.  Returns
+// nil if a response was sent instead.
+func (s *SmD) powerGraphFor(w http.ResponseWriter, r *http.Request, name string) (*powerGraph, string) {
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !xnametypes.IsHMSCompIDValid(xname) {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname")
+		return nil, ""
+	}
+	ms, err := s.db.GetPowerMapsAll()
+	if err != nil {
+		s.reqLog(r).LogAlways("%s(): Lookup failure: %s", name, err)
+		sendJsonDBError(w, "", "", err)
+		return nil, ""
+	}
+	g := newPowerGraph(ms)
+	if !g.has(xname) && impliedSupplier(xname) == "" {
+		sendJsonError(w, http.StatusNotFound, "no such xname.")
+		return nil, ""
+	}
+	if !g.has(xname) {
+		// An outlet with nothing mapped to it
+		g.suppliers[xname] = []string{impliedSupplier(xname)}
+	}
+	return g, xname
+}
+
+// Get the upstream power path of a component.
+func (s *SmD) doPowerMapPathGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	g, xname := s.powerGraphFor(w, r, "doPowerMapPathGet")
+	if g == nil {
+		return
+	}
+	sendJsonObject(w, http.StatusOK, g.path(xname))
+}
+
+// Get what loses power if a component is turned off.
+func (s *SmD) doPowerMapPoweredGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	g, xname := s.powerGraphFor(w, r, "doPowerMapPoweredGet")
+	if g == nil {
+		return
+	}
+	sendJsonObject(w, http.StatusOK, g.impact(xname))
+}
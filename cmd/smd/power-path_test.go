@@ -0,0 +1,165 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func powerPathTestMaps() []*sm.PowerMap {
+	return []*sm.PowerMap{
+		// Redundant supplies
+		{ID: "x3000c0s19b1n0", PoweredBy: []string{"x3000m0p0v1", "x3000m0p1v1"}},
+		{ID: "x3000c0s19b2n0", PoweredBy: []string{"x3000m0p0v2"}},
+		{ID: "x3000m0p0", PoweredBy: []string{"x3000m1p0"}},
+		{ID: "x3000m0p1", PoweredBy: []string{"x3000m1p1"}},
+	}
+}
+
+func TestPowerGraphPath(t *testing.T) {
+	g := newPowerGraph(powerPathTestMaps())
+	expected := &sm.PowerPath{
+		ID: "x3000c0s19b1n0",
+		Supplies: []string{"x3000m0p0v1", "x3000m0p1v1", "x3000m0p0",
+			"x3000m0p1", "x3000m1p0", "x3000m1p1"},
+		Paths: [][]string{
+			{"x3000c0s19b1n0", "x3000m0p0v1", "x3000m0p0", "x3000m1p0"},
+			{"x3000c0s19b1n0", "x3000m0p1v1", "x3000m0p1", "x3000m1p1"},
+		},
+		Edges: []sm.PowerMapEdge{
+			{ID: "x3000c0s19b1n0", PoweredBy: "x3000m0p0v1"},
+			{ID: "x3000m0p0v1", PoweredBy: "x3000m0p0", Implied: true},
+			{ID: "x3000m0p0", PoweredBy: "x3000m1p0"},
+			{ID: "x3000c0s19b1n0", PoweredBy: "x3000m0p1v1"},
+			{ID: "x3000m0p1v1", PoweredBy: "x3000m0p1", Implied: true},
+			{ID: "x3000m0p1", PoweredBy: "x3000m1p1"},
+		},
+	}
+	if pp := g.path("x3000c0s19b1n0"); !reflect.DeepEqual(expected, pp) {
+		t.Errorf("Expected %+v, got %+v", expected, pp)
+	}
+
+	// A supply that nothing powers has no paths.
+	pp := g.path("x3000m1p0")
+	if len(pp.Paths) != 0 || len(pp.Supplies) != 0 {
+		t.Errorf("Expected no supplies, got %+v", pp)
+	}
+}
+
+func TestPowerGraphImpact(t *testing.T) {
+	g := newPowerGraph(powerPathTestMaps())
+	expected := &sm.PowerImpact{
+		ID:         "x3000m0p0",
+		LosesPower: []string{"x3000m0p0v1", "x3000m0p0v2", "x3000c0s19b2n0"},
+		Degraded:   []string{"x3000c0s19b1n0"},
+	}
+	if pi := g.impact("x3000m0p0"); !reflect.DeepEqual(expected, pi) {
+		t.Errorf("Expected %+v, got %+v", expected, pi)
+	}
+
+	expected = &sm.PowerImpact{
+		ID:         "x3000c0s19b2n0",
+		LosesPower: []string{},
+		Degraded:   []string{},
+	}
+	if pi := g.impact("x3000c0s19b2n0"); !reflect.DeepEqual(expected, pi) {
+		t.Errorf("Expected %+v, got %+v", expected, pi)
+	}
+}
+
+func TestPowerGraphCycles(t *testing.T) {
+	// x3000m0p0 -> x3000m1p0 -> x3000m0p0v1 -> x3000m0p0
+	g := newPowerGraph([]*sm.PowerMap{
+		{ID: "x3000c0s19b1n0", PoweredBy: []string{"x3000m0p0v1"}},
+		{ID: "x3000m0p0", PoweredBy: []string{"x3000m1p0"}},
+		{ID: "x3000m1p0", PoweredBy: []string{"x3000m0p0v1"}},
+	})
+	cycles := [][]string{
+		{"x3000m0p0", "x3000m1p0", "x3000m0p0v1", "x3000m0p0"},
+	}
+	pp := g.path("x3000c0s19b1n0")
+	expectedPaths := [][]string{
+		{"x3000c0s19b1n0", "x3000m0p0v1", "x3000m0p0", "x3000m1p0"},
+	}
+	if !reflect.DeepEqual(expectedPaths, pp.Paths) ||
+		!reflect.DeepEqual(cycles, pp.Cycles) {
+		t.Errorf("Expected paths %v and cycles %v, got %+v", expectedPaths,
+			cycles, pp)
+	}
+
+	expected := &sm.PowerImpact{
+		ID:         "x3000m0p0",
+		LosesPower: []string{"x3000m0p0v1", "x3000c0s19b1n0", "x3000m1p0"},
+		Degraded:   []string{},
+		Cycles: [][]string{
+			{"x3000m0p0", "x3000m0p0v1", "x3000m1p0", "x3000m0p0"},
+		},
+	}
+	if pi := g.impact("x3000m0p0"); !reflect.DeepEqual(expected, pi) {
+		t.Errorf("Expected %+v, got %+v", expected, pi)
+	}
+}
+
+func TestDoPowerMapPathGet(t *testing.T) {
+	defer func() {
+		results.GetPowerMapsAll.Return.ms = nil
+	}()
+	results.GetPowerMapsAll.Return.ms = powerPathTestMaps()
+	tests := []struct {
+		url  string
+		code int
+	}{
+		{"/hsm/v2/sysinfo/powermaps/x3000c0s19b2n0/Path", http.StatusOK},
+		{"/hsm/v2/sysinfo/powermaps/x3000m0p0/Powered", http.StatusOK},
+		// An outlet with nothing mapped to it
+		{"/hsm/v2/sysinfo/powermaps/x3000m0p0v9/Path", http.StatusOK},
+		{"/hsm/v2/sysinfo/powermaps/x9000c0s0b0n0/Path", http.StatusNotFound},
+		{"/hsm/v2/sysinfo/powermaps/foo/Powered", http.StatusBadRequest},
+	}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET", "https://localhost"+test.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+		}
+	}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/sysinfo/powermaps/x3000c0s19b2n0/Path", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var pp sm.PowerPath
+	json.Unmarshal(w.Body.Bytes(), &pp)
+	expected := []string{"x3000c0s19b2n0", "x3000m0p0v2", "x3000m0p0", "x3000m1p0"}
+	if len(pp.Paths) != 1 || !reflect.DeepEqual(expected, pp.Paths[0]) {
+		t.Errorf("Expected path %v, got %+v", expected, pp)
+	}
+}
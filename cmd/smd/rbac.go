@@ -0,0 +1,374 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Role-based access control
+//
+// With JWT authentication on (SMD_JWKS_URL), RBAC limits what each caller
+// can do with the protected routes.  The caller's roles come from a JWT
+// claim (RoleClaim, "roles" by default, a string or a list), mapped through
+// ClaimRoles if needed, and from Subjects, which gives roles to specific
+// token subjects.  Callers with no role get DefaultRole, if set, or 403.
+//
+// Each role is a list of rules, and a request is allowed if any rule of
+// any of the caller's roles has its method (or "*") and a matching route.
+// Routes are the patterns the routes are registered with, less the API
+// root, e.g. /Inventory/RedfishEndpoints/{xname}.  A trailing "*" matches
+// any route starting with the rest and "*" on its own matches every route.
+//
+// The default policy has these roles:
+//
+//     read-only  GETs, plus the POSTs that only query
+//     service    read-only, plus service reservations, component state
+//                updates and SCN subscriptions
+//     operator   read-only, plus component changes, locks, groups,
+//                partitions and discovery
+//     admin      everything
+//
+// RBAC is turned on with SMD_RBAC=true, or by loading a policy from the
+// JSON file named by SMD_RBAC_POLICY_FILE (which is then used instead of
+// the default policy).  GET /service/rbac shows the policy in use and
+// PUT /service/rbac replaces it.  Like read-only mode, a PUT is not
+// persisted and only applies to the HSM instance that receives it.
+///////////////////////////////////////////////////////////////////////////////
+
+// Default roles
+const (
+	RBACRoleReadOnly = "read-only"
+	RBACRoleService  = "service"
+	RBACRoleOperator = "operator"
+	RBACRoleAdmin    = "admin"
+)
+
+// Default claim the roles are taken from
+const rbacRoleClaimDefault = "roles"
+
+// Methods allowed by a rule on the routes matching any of its Routes.
+type RBACRule struct {
+	Methods []string `json:"Methods"`
+	Routes  []string `json:"Routes"`
+}
+
+// RBAC policy, as used by GET/PUT /service/rbac and SMD_RBAC_POLICY_FILE
+type RBACPolicy struct {
+	Enabled     bool                  `json:"Enabled"`
+	RoleClaim   string                `json:"RoleClaim,omitempty"`
+	ClaimRoles  map[string]string     `json:"ClaimRoles,omitempty"`
+	Subjects    map[string]string     `json:"Subjects,omitempty"`
+	DefaultRole string                `json:"DefaultRole,omitempty"`
+	Roles       map[string][]RBACRule `json:"Roles"`
+}
+
+// Get the default policy, disabled.
+func DefaultRBACPolicy() RBACPolicy {
+	read := []RBACRule{{
+		Methods: []string{http.MethodGet, http.MethodHead},
+		Routes:  []string{"*"},
+	}, {
+		Methods: []string{http.MethodPost},
+		Routes: []string{
+			"/State/Components/Query",
+			"/State/Components/ByNID/Query",
+			"/locks/status",
+			"/locks/service/reservations/check",
+		},
+	}}
+	service := append([]RBACRule{{
+		Methods: []string{"*"},
+		Routes:  []string{"/locks/service/*", "/Subscriptions/*"},
+	}, {
+		Methods: []string{http.MethodPatch},
+		Routes:  []string{"/State/Components*"},
+	}}, read...)
+	operator := append([]RBACRule{{
+		Methods: []string{"*"},
+		Routes: []string{
+			"/locks/*",
+			"/groups*",
+			"/partitions*",
+			"/Inventory/DiscoveryHolds*",
+		},
+	}, {
+		Methods: []string{http.MethodPost, http.MethodPut, http.MethodPatch},
+		Routes:  []string{"/State/Components*"},
+	}, {
+		Methods: []string{http.MethodPost},
+		Routes:  []string{"/Inventory/Discover"},
+	}}, read...)
+
+	return RBACPolicy{
+		RoleClaim: rbacRoleClaimDefault,
+		Roles: map[string][]RBACRule{
+			RBACRoleReadOnly: read,
+			RBACRoleService:  service,
+			RBACRoleOperator: operator,
+			RBACRoleAdmin: {{
+				Methods: []string{"*"},
+				Routes:  []string{"*"},
+			}},
+		},
+	}
+}
+
+var rbacMethods = map[string]bool{
+	"*":                true,
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// Check the policy refers only to roles it has, with valid methods and
+// routes.  Methods are normalized to upper case.
+func (p *RBACPolicy) VerifyNormalize() error {
+	if p.RoleClaim == "" {
+		p.RoleClaim = rbacRoleClaimDefault
+	}
+	for role, rules := range p.Roles {
+		if role == "" {
+			return fmt.Errorf("empty role name")
+		}
+		for i, rule := range rules {
+			if len(rule.Methods) == 0 || len(rule.Routes) == 0 {
+				return fmt.Errorf("role '%s' rule %d needs Methods and Routes",
+					role, i)
+			}
+			for j, m := range rule.Methods {
+				m = strings.ToUpper(m)
+				if !rbacMethods[m] {
+					return fmt.Errorf("role '%s' has bad method '%s'",
+						role, rule.Methods[j])
+				}
+				rule.Methods[j] = m
+			}
+			for _, rt := range rule.Routes {
+				if rt != "*" && !strings.HasPrefix(rt, "/") {
+					return fmt.Errorf("role '%s' has bad route '%s': "+
+						"must be * or start with /", role, rt)
+				}
+			}
+		}
+	}
+	for val, role := range p.ClaimRoles {
+		if _, ok := p.Roles[role]; !ok {
+			return fmt.Errorf("ClaimRoles '%s' has unknown role '%s'", val, role)
+		}
+	}
+	for sub, role := range p.Subjects {
+		if _, ok := p.Roles[role]; !ok {
+			return fmt.Errorf("Subjects '%s' has unknown role '%s'", sub, role)
+		}
+	}
+	if _, ok := p.Roles[p.DefaultRole]; p.DefaultRole != "" && !ok {
+		return fmt.Errorf("unknown DefaultRole '%s'", p.DefaultRole)
+	}
+	return nil
+}
+
+// Get the roles of the caller with the given JWT claims, sorted.
+func (p *RBACPolicy) roles(claims map[string]interface{}) []string {
+	found := make(map[string]bool)
+	add := func(val string) {
+		if role, ok := p.ClaimRoles[val]; ok {
+			found[role] = true
+		} else if _, ok := p.Roles[val]; ok {
+			found[val] = true
+		}
+	}
+	switch vals := claims[p.RoleClaim].(type) {
+	case string:
+		add(vals)
+	case []string:
+		for _, val := range vals {
+			add(val)
+		}
+	case []interface{}:
+		for _, val := range vals {
+			if str, ok := val.(string); ok {
+				add(str)
+			}
+		}
+	}
+	if sub, ok := claims["sub"].(string); ok {
+		if role, ok := p.Subjects[sub]; ok {
+			found[role] = true
+		}
+	}
+	if len(found) == 0 && p.DefaultRole != "" {
+		found[p.DefaultRole] = true
+	}
+	roles := make([]string, 0, len(found))
+	for role := range found {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// True if the route pattern, less the API root, matches the rule route.
+func rbacRouteMatch(ruleRoute, route string) bool {
+	if prefix, ok := strings.CutSuffix(ruleRoute, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return ruleRoute == route
+}
+
+// True if any of the roles may use the method on the route.
+func (p *RBACPolicy) allowed(roles []string, method, route string) bool {
+	for _, role := range roles {
+		for _, rule := range p.Roles[role] {
+			methodOK := false
+			for _, m := range rule.Methods {
+				if m == "*" || m == method {
+					methodOK = true
+					break
+				}
+			}
+			if !methodOK {
+				continue
+			}
+			for _, rt := range rule.Routes {
+				if rbacRouteMatch(rt, route) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+type RBAC struct {
+	lock   sync.RWMutex
+	policy RBACPolicy
+}
+
+// Replace the policy, if it is valid.
+func (a *RBAC) Set(p RBACPolicy) error {
+	if err := p.VerifyNormalize(); err != nil {
+		return err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.policy = p
+	return nil
+}
+
+// Get the current policy.
+func (a *RBAC) Get() RBACPolicy {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.policy
+}
+
+// Read a policy from a JSON file.
+func loadRBACPolicy(path string) (RBACPolicy, error) {
+	var p RBACPolicy
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err = json.Unmarshal(data, &p); err != nil {
+		return p, err
+	}
+	return p, p.VerifyNormalize()
+}
+
+// Wrap the handler for a protected route so that it returns 403 unless the
+// roles of the caller allow the route, if RBAC is on.
+func (s *SmD) rbacGuard(route Route, next http.Handler) http.Handler {
+	pattern := strings.TrimPrefix(route.Pattern, s.apiRootV2)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := s.rbac.Get()
+		if !p.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+		_, claims, err := jwtauth.FromContext(r.Context())
+		if err != nil {
+			claims = map[string]interface{}{}
+		}
+		roles := p.roles(claims)
+		if p.allowed(roles, route.Method, pattern) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		defer base.DrainAndCloseRequestBody(r)
+		actor := s.requestActor(r)
+		if len(roles) == 0 {
+			s.Log(LOG_INFO, "RBAC: %s has no role, denied %s %s",
+				actor, route.Method, route.Pattern)
+			sendJsonError(w, http.StatusForbidden, "no HSM role for "+actor)
+			return
+		}
+		s.Log(LOG_INFO, "RBAC: %s (%s) denied %s %s", actor,
+			strings.Join(roles, ","), route.Method, route.Pattern)
+		sendJsonError(w, http.StatusForbidden, fmt.Sprintf(
+			"role(s) %s may not %s %s", strings.Join(roles, ","),
+			route.Method, route.Pattern))
+	})
+}
+
+// Get the RBAC policy
+func (s *SmD) doRBACPolicyGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, s.rbac.Get())
+}
+
+// Replace the RBAC policy
+func (s *SmD) doRBACPolicyPut(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var p RBACPolicy
+	body, err := ioutil.ReadAll(r.Body)
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if err = s.rbac.Set(p); err != nil {
+		sendJsonError(w, http.StatusBadRequest, "bad RBAC policy: "+err.Error())
+		return
+	}
+	s.LogAlways("RBAC policy replaced by %s (enabled: %v)",
+		s.requestActor(r), p.Enabled)
+	sendJsonObject(w, http.StatusOK, s.rbac.Get())
+}
@@ -0,0 +1,313 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	jwtauth "github.com/OpenCHAMI/jwtauth/v5"
+)
+
+func TestRBACPolicyVerifyNormalize(t *testing.T) {
+	p := DefaultRBACPolicy()
+	if err := p.VerifyNormalize(); err != nil {
+		t.Errorf("Expected the default policy to be valid, got %s", err)
+	}
+
+	tests := []struct {
+		p      RBACPolicy
+		expErr string
+	}{{
+		RBACPolicy{Roles: map[string][]RBACRule{
+			"dash": {{Methods: []string{"get"}, Routes: []string{"*"}}},
+		}},
+		"",
+	}, {
+		RBACPolicy{Roles: map[string][]RBACRule{
+			"dash": {{Methods: []string{"FETCH"}, Routes: []string{"*"}}},
+		}},
+		"bad method 'FETCH'",
+	}, {
+		RBACPolicy{Roles: map[string][]RBACRule{
+			"dash": {{Methods: []string{"GET"}, Routes: []string{"groups"}}},
+		}},
+		"bad route 'groups'",
+	}, {
+		RBACPolicy{Roles: map[string][]RBACRule{
+			"dash": {{Methods: []string{"GET"}}},
+		}},
+		"needs Methods and Routes",
+	}, {
+		RBACPolicy{
+			ClaimRoles: map[string]string{"hsm-viewers": "viewer"},
+			Roles:      map[string][]RBACRule{},
+		},
+		"unknown role 'viewer'",
+	}, {
+		RBACPolicy{DefaultRole: "viewer"},
+		"unknown DefaultRole 'viewer'",
+	}}
+	for i, test := range tests {
+		err := test.p.VerifyNormalize()
+		if test.expErr == "" {
+			if err != nil {
+				t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+			} else if test.p.RoleClaim != rbacRoleClaimDefault ||
+				test.p.Roles["dash"][0].Methods[0] != "GET" {
+				t.Errorf("Test %v Failed: Policy not normalized: %+v", i, test.p)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.expErr) {
+			t.Errorf("Test %v Failed: Expected error '%s', got '%v'",
+				i, test.expErr, err)
+		}
+	}
+}
+
+func TestLoadRBACPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatalf("Can't write %s: %s", path, err)
+		}
+		return path
+	}
+	tests := []struct {
+		path   string
+		expErr bool
+	}{
+		{write("good.json", `{"Roles":{"dash":[{"Methods":["get"],"Routes":["*"]}]}}`), false},
+		{filepath.Join(dir, "missing.json"), true},
+		{write("notjson.json", `{"Roles":`), true},
+		{write("invalid.json", `{"DefaultRole":"viewer"}`), true},
+	}
+	for i, test := range tests {
+		p, err := loadRBACPolicy(test.path)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error, got %+v", i, p)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+		} else if p.Roles["dash"][0].Methods[0] != "GET" {
+			t.Errorf("Test %v Failed: Policy not normalized: %+v", i, p)
+		}
+	}
+}
+
+func TestRBACPolicyRoles(t *testing.T) {
+	p := DefaultRBACPolicy()
+	p.ClaimRoles = map[string]string{"hsm-admins": RBACRoleAdmin}
+	p.Subjects = map[string]string{"cfs": RBACRoleService}
+
+	tests := []struct {
+		claims   map[string]interface{}
+		defRole  string
+		expRoles []string
+	}{
+		{map[string]interface{}{"roles": "read-only"}, "", []string{"read-only"}},
+		{map[string]interface{}{"roles": []interface{}{"hsm-admins", "operator", 5}},
+			"", []string{"admin", "operator"}},
+		{map[string]interface{}{"roles": []string{"unknown"}}, "", []string{}},
+		{map[string]interface{}{"sub": "cfs", "roles": "read-only"}, "",
+			[]string{"read-only", "service"}},
+		{map[string]interface{}{"sub": "dash"}, "read-only", []string{"read-only"}},
+		{map[string]interface{}{"sub": "bos", "roles": "operator"}, "read-only",
+			[]string{"operator"}},
+	}
+	for i, test := range tests {
+		p.DefaultRole = test.defRole
+		roles := p.roles(test.claims)
+		if !reflect.DeepEqual(roles, test.expRoles) {
+			t.Errorf("Test %v Failed: Expected roles %v, got %v",
+				i, test.expRoles, roles)
+		}
+	}
+}
+
+func TestRBACPolicyAllowed(t *testing.T) {
+	p := DefaultRBACPolicy()
+	tests := []struct {
+		role   string
+		method string
+		route  string
+		expect bool
+	}{
+		{RBACRoleReadOnly, "GET", "/Inventory/RedfishEndpoints/{xname}", true},
+		{RBACRoleReadOnly, "DELETE", "/Inventory/RedfishEndpoints/{xname}", false},
+		{RBACRoleReadOnly, "POST", "/State/Components/Query", true},
+		{RBACRoleReadOnly, "POST", "/State/Components", false},
+		{RBACRoleService, "POST", "/locks/service/reservations", true},
+		{RBACRoleService, "POST", "/locks/lock", false},
+		{RBACRoleService, "PATCH", "/State/Components/BulkStateData", true},
+		{RBACRoleService, "POST", "/Subscriptions/SCN", true},
+		{RBACRoleOperator, "PATCH", "/State/Components/{xname}/Flag", true},
+		{RBACRoleOperator, "DELETE", "/State/Components/{xname}", false},
+		{RBACRoleOperator, "DELETE", "/groups/{group_label}", true},
+		{RBACRoleOperator, "POST", "/locks/disable", true},
+		{RBACRoleOperator, "DELETE", "/Inventory/RedfishEndpoints/{xname}", false},
+		{RBACRoleOperator, "PUT", "/service/rbac", false},
+		{RBACRoleAdmin, "DELETE", "/Inventory/RedfishEndpoints", true},
+		{RBACRoleAdmin, "PUT", "/service/rbac", true},
+		{"", "GET", "/State/Components", false},
+	}
+	for i, test := range tests {
+		if p.allowed([]string{test.role}, test.method, test.route) != test.expect {
+			t.Errorf("Test %v Failed: %s %s %s: expected allowed=%v",
+				i, test.role, test.method, test.route, test.expect)
+		}
+	}
+}
+
+func TestRBACGuard(t *testing.T) {
+	defer func() {
+		s.rbac = RBAC{}
+	}()
+	ja := jwtauth.New("HS256", []byte("0123456789abcdef0123456789abcdef"), nil)
+	route := Route{
+		"doRedfishEndpointDeleteV2",
+		"DELETE",
+		s.redfishEPBaseV2 + "/{xname}",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}
+	handler := s.rbacGuard(route, route.HandlerFunc)
+
+	tests := []struct {
+		enabled  bool
+		claims   map[string]interface{}
+		expCode  int
+		expInRsp string
+	}{{
+		false,
+		map[string]interface{}{"sub": "dash", "roles": "read-only"},
+		http.StatusNoContent,
+		"",
+	}, {
+		true,
+		map[string]interface{}{"sub": "dash", "roles": "read-only"},
+		http.StatusForbidden,
+		"role(s) read-only may not DELETE /hsm/v2/Inventory/RedfishEndpoints/{xname}",
+	}, {
+		true,
+		map[string]interface{}{"sub": "dash"},
+		http.StatusForbidden,
+		"no HSM role for dash",
+	}, {
+		true,
+		map[string]interface{}{"sub": "root", "roles": []string{"read-only", "admin"}},
+		http.StatusNoContent,
+		"",
+	}}
+	for i, test := range tests {
+		p := DefaultRBACPolicy()
+		p.Enabled = test.enabled
+		if err := s.rbac.Set(p); err != nil {
+			t.Fatalf("Set: %s", err)
+		}
+		token, _, err := ja.Encode(test.claims)
+		if err != nil {
+			t.Fatalf("Encode: %s", err)
+		}
+		req := httptest.NewRequest("DELETE",
+			"https://localhost/hsm/v2/Inventory/RedfishEndpoints/x0c0s0b0", nil)
+		req = req.WithContext(jwtauth.NewContext(req.Context(), token, nil))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v",
+				i, w.Code, test.expCode)
+		}
+		if !strings.Contains(w.Body.String(), test.expInRsp) {
+			t.Errorf("Test %v Failed: Expected '%s' in response; Received '%s'",
+				i, test.expInRsp, w.Body.String())
+		}
+	}
+}
+
+func TestDoRBACPolicy(t *testing.T) {
+	defer func() {
+		s.rbac = RBAC{}
+	}()
+	s.rbac = RBAC{}
+
+	tests := []struct {
+		reqType  string
+		reqBody  string
+		expCode  int
+		expInRsp string
+	}{{
+		"PUT",
+		`{"Enabled":true,"Roles":{"viewer":[{"Methods":["get"],"Routes":["*"]}]},"DefaultRole":"viewer"}`,
+		http.StatusOK,
+		`"RoleClaim":"roles"`,
+	}, {
+		"GET",
+		"",
+		http.StatusOK,
+		`"Enabled":true,"RoleClaim":"roles","DefaultRole":"viewer","Roles":{"viewer":[{"Methods":["GET"],"Routes":["*"]}]}`,
+	}, {
+		"PUT",
+		`{"Enabled":true,"Roles":{},"DefaultRole":"viewer"}`,
+		http.StatusBadRequest,
+		"bad RBAC policy: unknown DefaultRole 'viewer'",
+	}, {
+		"PUT",
+		`{"Enabled":"yes"}`,
+		http.StatusBadRequest,
+		"error decoding JSON",
+	}, {
+		"GET",
+		"",
+		http.StatusOK,
+		`"DefaultRole":"viewer"`,
+	}}
+	for i, test := range tests {
+		req, err := http.NewRequest(test.reqType,
+			"https://localhost/hsm/v2/service/rbac",
+			bytes.NewBufferString(test.reqBody))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v",
+				i, w.Code, test.expCode)
+		}
+		if !strings.Contains(w.Body.String(), test.expInRsp) {
+			t.Errorf("Test %v Failed: Expected '%s' in response; Received '%s'",
+				i, test.expInRsp, w.Body.String())
+		}
+	}
+}
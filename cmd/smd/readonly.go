@@ -0,0 +1,194 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Read-only mode
+//
+// While read-only, every API call that could change the database returns
+// 503 with the reason, and reads keep working.  This is meant for DB
+// migrations and failovers.  It can be turned on at startup with
+// SMD_READ_ONLY (and SMD_READ_ONLY_REASON) or at runtime with
+// PUT /service/readonly.  The current mode and counters are available via
+// GET /service/readonly and the mode is also shown by /service/ready.
+//
+// Note that this only covers the REST API.  Discovery that is already
+// running and Redfish events from the message bus are not affected.
+///////////////////////////////////////////////////////////////////////////////
+
+// Output of GET /service/readonly
+type ReadOnlyStatus struct {
+	ReadOnly         bool   `json:"ReadOnly"`
+	Reason           string `json:"Reason,omitempty"`
+	Since            string `json:"Since,omitempty"`
+	RejectedRequests uint64 `json:"RejectedRequests"`
+	Transitions      uint64 `json:"Transitions"`
+}
+
+// Input for PUT /service/readonly
+type ReadOnlyIn struct {
+	ReadOnly *bool  `json:"ReadOnly"`
+	Reason   string `json:"Reason"`
+}
+
+type ReadOnlyMode struct {
+	lock   sync.Mutex
+	status ReadOnlyStatus
+}
+
+const readOnlyDefaultReason = "HSM is in read-only mode for maintenance"
+
+// Turn read-only mode on or off.  Returns true if the mode changed.  The
+// reason is updated either way.
+func (m *ReadOnlyMode) Set(on bool, reason string, now time.Time) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if !on {
+		reason = ""
+	} else if reason == "" {
+		reason = readOnlyDefaultReason
+	}
+	m.status.Reason = reason
+	if m.status.ReadOnly == on {
+		return false
+	}
+	m.status.ReadOnly = on
+	m.status.Transitions++
+	if on {
+		m.status.Since = now.UTC().Format(time.RFC3339)
+	} else {
+		m.status.Since = ""
+	}
+	return true
+}
+
+// Get the current mode and counters.
+func (m *ReadOnlyMode) Get() ReadOnlyStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.status
+}
+
+// If read-only, count a rejected request and return the reason.
+func (m *ReadOnlyMode) reject() (string, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if !m.status.ReadOnly {
+		return "", false
+	}
+	m.status.RejectedRequests++
+	return m.status.Reason, true
+}
+
+// POST routes that only read, so they still work while read-only.
+var readOnlySafeRoutes = map[string]bool{
+	"doComponentByNIDQueryPostV2":          true,
+	"doComponentsQueryPostV2":              true,
+	"doCompLocksServiceReservationCheckV2": true,
+	"doCompLocksStatusV2":                  true,
+	"doReconcilePostV2":                    true,
+	"doNIDPolicyPreviewPostV2":             true,
+	"doXnamesValidatePostV2":               true,
+	// Telemetry is only kept in memory.
+	"doTelemetryMetricReportPostV2": true,
+	// SCN delivery queues are only kept in memory.
+	"doSCNReplayPostV2": true,
+	// The component cache is only kept in memory.
+	"doCompCacheInvalidatePostV2": true,
+	// Log levels are only kept in memory.
+	"doLogLevelPutV2": true,
+	// Always allowed so read-only mode can be turned off again.
+	"doReadOnlyPutV2": true,
+}
+
+// True if the route can change anything and must be blocked while
+// read-only.
+func routeMutates(route Route) bool {
+	switch route.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !readOnlySafeRoutes[route.Name]
+}
+
+// Wrap the handler for a route so that it returns 503 while read-only, if
+// the route can change anything.
+func (s *SmD) readOnlyGuard(route Route) http.Handler {
+	if !routeMutates(route) {
+		return route.HandlerFunc
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reason, ro := s.readOnly.reject(); ro {
+			defer base.DrainAndCloseRequestBody(r)
+			sendJsonError(w, http.StatusServiceUnavailable,
+				"HSM is read-only: "+reason)
+			return
+		}
+		route.HandlerFunc(w, r)
+	})
+}
+
+// Get the read-only mode and counters
+func (s *SmD) doReadOnlyGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, s.readOnly.Get())
+}
+
+// Turn read-only mode on or off
+func (s *SmD) doReadOnlyPut(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var roIn ReadOnlyIn
+	body, err := ioutil.ReadAll(r.Body)
+	err = json.Unmarshal(body, &roIn)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if roIn.ReadOnly == nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"ReadOnly is required in PUT body")
+		return
+	}
+	if s.readOnly.Set(*roIn.ReadOnly, roIn.Reason, time.Now()) {
+		if *roIn.ReadOnly {
+			s.LogAlways("Read-only mode ON (%s): %s", r.RemoteAddr, roIn.Reason)
+		} else {
+			s.LogAlways("Read-only mode OFF (%s)", r.RemoteAddr)
+		}
+	}
+	sendJsonObject(w, http.StatusOK, s.readOnly.Get())
+}
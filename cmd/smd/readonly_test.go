@@ -0,0 +1,148 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteMutates(t *testing.T) {
+	tests := []struct {
+		route  Route
+		expect bool
+	}{
+		{Route{Name: "doComponentGetV2", Method: "GET"}, false},
+		{Route{Name: "doComponentsPostV2", Method: "POST"}, true},
+		{Route{Name: "doComponentsQueryPostV2", Method: "POST"}, false},
+		{Route{Name: "doComponentPatchV2", Method: "PATCH"}, true},
+		{Route{Name: "doComponentDeleteV2", Method: "DELETE"}, true},
+		{Route{Name: "doReadOnlyPutV2", Method: "PUT"}, false},
+	}
+	for i, test := range tests {
+		if routeMutates(test.route) != test.expect {
+			t.Errorf("Test %v Failed: %s %s: expected mutates=%v",
+				i, test.route.Method, test.route.Name, test.expect)
+		}
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	defer func() {
+		s.readOnly = ReadOnlyMode{}
+		results.TestConnection.Return.err = nil
+	}()
+	s.readOnly = ReadOnlyMode{}
+	results.TestConnection.Return.err = nil
+
+	// Run in order, each builds on the mode set by the previous ones.
+	tests := []struct {
+		reqType  string
+		reqURI   string
+		reqBody  string
+		expCode  int
+		expInRsp string
+	}{{
+		"PUT",
+		"https://localhost/hsm/v2/service/readonly",
+		`{"Reason":"DB migration"}`,
+		http.StatusBadRequest,
+		"ReadOnly is required",
+	}, {
+		"PUT",
+		"https://localhost/hsm/v2/service/readonly",
+		`{"ReadOnly":true,"Reason":"DB migration"}`,
+		http.StatusOK,
+		`"ReadOnly":true`,
+	}, {
+		"DELETE",
+		"https://localhost/hsm/v2/State/Components/x0c0s0b0n0",
+		"",
+		http.StatusServiceUnavailable,
+		"HSM is read-only: DB migration",
+	}, {
+		"POST",
+		"https://localhost/hsm/v2/groups",
+		`{"label":"grp1"}`,
+		http.StatusServiceUnavailable,
+		"HSM is read-only: DB migration",
+	}, {
+		"GET",
+		"https://localhost/hsm/v2/service/ready",
+		"",
+		http.StatusOK,
+		"HSM is healthy but read-only: DB migration",
+	}, {
+		"GET",
+		"https://localhost/hsm/v2/service/readonly",
+		"",
+		http.StatusOK,
+		`"RejectedRequests":2`,
+	}, {
+		"PUT",
+		"https://localhost/hsm/v2/service/readonly",
+		`{"ReadOnly":false}`,
+		http.StatusOK,
+		`"ReadOnly":false`,
+	}, {
+		"GET",
+		"https://localhost/hsm/v2/service/ready",
+		"",
+		http.StatusOK,
+		`"HSM is healthy"`,
+	}}
+
+	for i, test := range tests {
+		req, err := http.NewRequest(test.reqType, test.reqURI,
+			bytes.NewBufferString(test.reqBody))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v",
+				i, w.Code, test.expCode)
+		}
+		if !strings.Contains(w.Body.String(), test.expInRsp) {
+			t.Errorf("Test %v Failed: Expected '%s' in response; Received '%s'",
+				i, test.expInRsp, w.Body.String())
+		}
+	}
+
+	var ro ReadOnlyStatus
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/service/readonly", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &ro); err != nil {
+		t.Fatalf("Bad response body: %s", err)
+	}
+	if ro.ReadOnly || ro.Transitions != 2 || ro.RejectedRequests != 2 {
+		t.Errorf("Bad final status: %+v", ro)
+	}
+}
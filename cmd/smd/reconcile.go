@@ -0,0 +1,313 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/slsapi"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Expected vs. discovered hardware reconciliation
+//
+// POST /Inventory/Reconcile takes a manifest of the hardware that should be
+// there and compares it with the discovered components, so bring-up teams
+// can check cabling and population from HSM:
+//
+//     Missing     Critical  expected but not discovered, or discovered
+//                           with State Empty (slot not populated)
+//     Unexpected  Warning   discovered but not expected
+//     Mismatched  Warning   Class differs
+//                 Info      Role or SubRole differs
+//
+// The manifest is either
+//
+//     {"Components":[{"ID":"x1000c0s0b0n0","Type":"Node","Class":"Mountain",
+//                     "Role":"Compute"}, ...],
+//      "Scope":["x1000"]}
+//
+// or the output of SLS's GET /v1/hardware as is.  Type defaults to the
+// xname's type, and Class, Role and SubRole are only compared if given.
+// Only discovered components of the types in the manifest can be
+// unexpected, and if Scope is given, only those at or below one of its
+// xnames.  Nothing is changed.
+///////////////////////////////////////////////////////////////////////////////
+
+// Reconciliation problem severities
+const (
+	ReconcileCritical = "Critical"
+	ReconcileWarning  = "Warning"
+	ReconcileInfo     = "Info"
+)
+
+// A component the manifest expects.
+type ExpectedComponent struct {
+	ID      string `json:"ID"`
+	Type    string `json:"Type,omitempty"`
+	Class   string `json:"Class,omitempty"`
+	Role    string `json:"Role,omitempty"`
+	SubRole string `json:"SubRole,omitempty"`
+}
+
+// Input for POST /Inventory/Reconcile, unless it's an SLS hardware list.
+type ReconcileManifest struct {
+	Components []ExpectedComponent `json:"Components"`
+	Scope      []string            `json:"Scope,omitempty"`
+}
+
+// One difference between the manifest and what was discovered.  Field is
+// the mismatched field, and Expected and Found its values.
+type ReconcileIssue struct {
+	ID       string `json:"ID"`
+	Type     string `json:"Type"`
+	Severity string `json:"Severity"`
+	Field    string `json:"Field,omitempty"`
+	Expected string `json:"Expected,omitempty"`
+	Found    string `json:"Found,omitempty"`
+	Detail   string `json:"Detail"`
+}
+
+// Output of POST /Inventory/Reconcile.  Summary is the number of issues of
+// each severity.
+type ReconcileReport struct {
+	Timestamp  string           `json:"Timestamp"`
+	Reconciled bool             `json:"Reconciled"`
+	Expected   int              `json:"Expected"`
+	Matched    int              `json:"Matched"`
+	Summary    map[string]int   `json:"Summary"`
+	Missing    []ReconcileIssue `json:"Missing"`
+	Unexpected []ReconcileIssue `json:"Unexpected"`
+	Mismatched []ReconcileIssue `json:"Mismatched"`
+}
+
+// Decode a manifest, either a ReconcileManifest or a list of SLS hardware,
+// and check and normalize its entries.
+func decodeReconcileManifest(body []byte) (*ReconcileManifest, error) {
+	m := new(ReconcileManifest)
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var hw []slsapi.NodeHardware
+		if err := json.Unmarshal(trimmed, &hw); err != nil {
+			return nil, err
+		}
+		for _, h := range hw {
+			m.Components = append(m.Components, ExpectedComponent{
+				ID:      h.Xname,
+				Type:    h.TypeString.String(),
+				Class:   h.Class,
+				Role:    h.ExtraProperties.Role,
+				SubRole: h.ExtraProperties.SubRole,
+			})
+		}
+	} else if err := json.Unmarshal(body, m); err != nil {
+		return nil, err
+	}
+	if len(m.Components) == 0 {
+		return nil, base.NewHMSError("sm", "no Components given")
+	}
+	for i := range m.Components {
+		ec := &m.Components[i]
+		id := xnametypes.VerifyNormalizeCompID(ec.ID)
+		if id == "" {
+			return nil, base.NewHMSError("sm", "invalid xname ID '"+ec.ID+"'")
+		}
+		ec.ID = id
+		xnameType := xnametypes.GetHMSTypeString(id)
+		if ec.Type != "" && !strings.EqualFold(ec.Type, xnameType) {
+			return nil, base.NewHMSError("sm", "Type "+ec.Type+" of "+id+
+				" does not match its xname type "+xnameType)
+		}
+		ec.Type = xnameType
+		if ec.Class != "" {
+			class := base.VerifyNormalizeClass(ec.Class)
+			if class == "" {
+				return nil, base.NewHMSError("sm", "invalid Class '"+
+					ec.Class+"' for "+id)
+			}
+			ec.Class = class
+		}
+	}
+	for i, sc := range m.Scope {
+		id := xnametypes.VerifyNormalizeCompID(sc)
+		if id == "" {
+			return nil, base.NewHMSError("sm", "invalid Scope xname '"+sc+"'")
+		}
+		m.Scope[i] = id
+	}
+	return m, nil
+}
+
+// True if id is scope or below it, e.g. x1000c0s0b0n0 is in x1000c0 but
+// not in x1000c.
+func inXnameScope(id, scope string) bool {
+	return xnameutil.Contains(scope, id)
+}
+
+// Compare a manifest with the discovered components.
+func NewReconcileReport(m *ReconcileManifest, comps []*base.Component, now time.Time) *ReconcileReport {
+	rpt := &ReconcileReport{
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Summary: map[string]int{
+			ReconcileCritical: 0,
+			ReconcileWarning:  0,
+			ReconcileInfo:     0,
+		},
+		Missing:    []ReconcileIssue{},
+		Unexpected: []ReconcileIssue{},
+		Mismatched: []ReconcileIssue{},
+	}
+	compMap := make(map[string]*base.Component, len(comps))
+	for _, comp := range comps {
+		compMap[comp.ID] = comp
+	}
+	expected := make(map[string]bool, len(m.Components))
+	types := make(map[string]bool)
+	for _, ec := range m.Components {
+		if expected[ec.ID] {
+			continue
+		}
+		expected[ec.ID] = true
+		types[ec.Type] = true
+
+		comp, ok := compMap[ec.ID]
+		if !ok {
+			rpt.Missing = append(rpt.Missing, ReconcileIssue{
+				ID:       ec.ID,
+				Type:     ec.Type,
+				Severity: ReconcileCritical,
+				Detail:   "not discovered",
+			})
+			continue
+		} else if comp.State == base.StateEmpty.String() {
+			rpt.Missing = append(rpt.Missing, ReconcileIssue{
+				ID:       ec.ID,
+				Type:     ec.Type,
+				Severity: ReconcileCritical,
+				Detail:   "discovered but not populated",
+			})
+			continue
+		}
+		matched := true
+		for _, f := range []struct {
+			name, expected, found, severity string
+		}{
+			{"Class", ec.Class, comp.Class, ReconcileWarning},
+			{"Role", ec.Role, comp.Role, ReconcileInfo},
+			{"SubRole", ec.SubRole, comp.SubRole, ReconcileInfo},
+		} {
+			if f.expected == "" || strings.EqualFold(f.expected, f.found) {
+				continue
+			}
+			matched = false
+			rpt.Mismatched = append(rpt.Mismatched, ReconcileIssue{
+				ID:       ec.ID,
+				Type:     ec.Type,
+				Severity: f.severity,
+				Field:    f.name,
+				Expected: f.expected,
+				Found:    f.found,
+				Detail:   f.name + " does not match",
+			})
+		}
+		if matched {
+			rpt.Matched++
+		}
+	}
+	rpt.Expected = len(expected)
+
+	for _, comp := range comps {
+		if expected[comp.ID] || !types[comp.Type] ||
+			comp.State == base.StateEmpty.String() {
+			continue
+		}
+		inScope := len(m.Scope) == 0
+		for _, sc := range m.Scope {
+			if inXnameScope(comp.ID, sc) {
+				inScope = true
+				break
+			}
+		}
+		if inScope {
+			rpt.Unexpected = append(rpt.Unexpected, ReconcileIssue{
+				ID:       comp.ID,
+				Type:     comp.Type,
+				Severity: ReconcileWarning,
+				Detail:   "discovered but not expected",
+			})
+		}
+	}
+
+	for _, issues := range [][]ReconcileIssue{
+		rpt.Missing,
+		rpt.Unexpected,
+		rpt.Mismatched,
+	} {
+		sort.SliceStable(issues, func(i, j int) bool {
+			return issues[i].ID < issues[j].ID
+		})
+		for _, issue := range issues {
+			rpt.Summary[issue.Severity]++
+		}
+	}
+	rpt.Reconciled = len(rpt.Missing) == 0 && len(rpt.Unexpected) == 0 &&
+		len(rpt.Mismatched) == 0
+	return rpt
+}
+
+// Compare an expected hardware manifest with the discovered components.
+func (s *SmD) doReconcilePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body: "+err.Error())
+		return
+	}
+	m, err := decodeReconcileManifest(body)
+	if err != nil {
+		if base.IsHMSError(err) {
+			sendJsonError(w, http.StatusBadRequest, err.Error())
+		} else {
+			sendJsonError(w, http.StatusBadRequest,
+				"error decoding JSON "+err.Error())
+		}
+		return
+	}
+	comps, err := s.db.GetComponentsAll()
+	if err != nil {
+		s.reqLog(r).LogAlways("doReconcilePost(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, NewReconcileReport(m, comps, time.Now()))
+}
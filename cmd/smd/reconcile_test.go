@@ -0,0 +1,238 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+func reconcileTestComps() []*base.Component {
+	return []*base.Component{
+		{ID: "x1000c0s0b0n0", Type: "Node", State: "Ready", Class: "Mountain", Role: "Compute"},
+		{ID: "x1000c0s0b0n1", Type: "Node", State: "Empty", Class: "Mountain"},
+		{ID: "x1000c0s1b0n0", Type: "Node", State: "On", Class: "River", Role: "Compute"},
+		{ID: "x1000c0s2b0n0", Type: "Node", State: "On", Class: "Mountain", Role: "Application", SubRole: "UAN"},
+		{ID: "x1000c0s3b0n0", Type: "Node", State: "Ready", Class: "Mountain"},
+		{ID: "x1000c1s0b0n0", Type: "Node", State: "Ready", Class: "Mountain"},
+		{ID: "x1000c0s0b0", Type: "NodeBMC", State: "Ready", Class: "Mountain"},
+	}
+}
+
+func TestNewReconcileReport(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	m := &ReconcileManifest{
+		Components: []ExpectedComponent{
+			{ID: "x1000c0s0b0n0", Type: "Node", Class: "Mountain", Role: "compute"},
+			{ID: "x1000c0s0b0n1", Type: "Node"},
+			{ID: "x1000c0s1b0n0", Type: "Node", Class: "Mountain"},
+			{ID: "x1000c0s2b0n0", Type: "Node", Role: "Application", SubRole: "Gateway"},
+			{ID: "x1000c0s4b0n0", Type: "Node"},
+		},
+		Scope: []string{"x1000c0"},
+	}
+	rpt := NewReconcileReport(m, reconcileTestComps(), now)
+	expected := &ReconcileReport{
+		Timestamp:  "2026-10-17T12:00:00Z",
+		Reconciled: false,
+		Expected:   5,
+		Matched:    1,
+		Summary: map[string]int{
+			ReconcileCritical: 2,
+			ReconcileWarning:  2,
+			ReconcileInfo:     1,
+		},
+		Missing: []ReconcileIssue{{
+			ID:       "x1000c0s0b0n1",
+			Type:     "Node",
+			Severity: ReconcileCritical,
+			Detail:   "discovered but not populated",
+		}, {
+			ID:       "x1000c0s4b0n0",
+			Type:     "Node",
+			Severity: ReconcileCritical,
+			Detail:   "not discovered",
+		}},
+		// The NodeBMC isn't a type in the manifest and x1000c1 is out of
+		// scope.
+		Unexpected: []ReconcileIssue{{
+			ID:       "x1000c0s3b0n0",
+			Type:     "Node",
+			Severity: ReconcileWarning,
+			Detail:   "discovered but not expected",
+		}},
+		Mismatched: []ReconcileIssue{{
+			ID:       "x1000c0s1b0n0",
+			Type:     "Node",
+			Severity: ReconcileWarning,
+			Field:    "Class",
+			Expected: "Mountain",
+			Found:    "River",
+			Detail:   "Class does not match",
+		}, {
+			ID:       "x1000c0s2b0n0",
+			Type:     "Node",
+			Severity: ReconcileInfo,
+			Field:    "SubRole",
+			Expected: "Gateway",
+			Found:    "UAN",
+			Detail:   "SubRole does not match",
+		}},
+	}
+	if !reflect.DeepEqual(expected, rpt) {
+		t.Errorf("Expected report %+v, got %+v", expected, rpt)
+	}
+
+	// Everything there, with no scope
+	m = &ReconcileManifest{Components: []ExpectedComponent{
+		{ID: "x1000c0s0b0", Type: "NodeBMC", Class: "Mountain"},
+	}}
+	rpt = NewReconcileReport(m, reconcileTestComps(), now)
+	if !rpt.Reconciled || rpt.Matched != 1 || len(rpt.Unexpected) != 0 {
+		t.Errorf("Expected a reconciled report, got %+v", rpt)
+	}
+}
+
+func TestInXnameScope(t *testing.T) {
+	tests := []struct {
+		id, scope string
+		expected  bool
+	}{
+		{"x1000c0s0b0n0", "x1000c0", true},
+		{"x1000c0", "x1000c0", true},
+		{"x1000c01s0b0n0", "x1000c0", false},
+		{"x1000c0s0b0n0", "x100", false},
+		{"x1001c0s0b0n0", "x1000", false},
+	}
+	for i, test := range tests {
+		if got := inXnameScope(test.id, test.scope); got != test.expected {
+			t.Errorf("Test %d: inXnameScope(%s, %s) expected %v, got %v", i,
+				test.id, test.scope, test.expected, got)
+		}
+	}
+}
+
+func TestDecodeReconcileManifest(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected *ReconcileManifest
+	}{{
+		body: `{"Components":[{"ID":"X1000C0S0B0N0","Type":"node","Class":"mountain"}],"Scope":["X1000"]}`,
+		expected: &ReconcileManifest{
+			Components: []ExpectedComponent{
+				{ID: "x1000c0s0b0n0", Type: "Node", Class: "Mountain"},
+			},
+			Scope: []string{"x1000"},
+		},
+	}, {
+		// SLS hardware
+		body: ` [{"Parent":"x3000c0s1b0","Xname":"x3000c0s1b0n0","Type":"comptype_node","Class":"River","TypeString":"Node","ExtraProperties":{"NID":1,"Role":"Management","SubRole":"Worker"}}]`,
+		expected: &ReconcileManifest{
+			Components: []ExpectedComponent{{
+				ID:      "x3000c0s1b0n0",
+				Type:    "Node",
+				Class:   "River",
+				Role:    "Management",
+				SubRole: "Worker",
+			}},
+		},
+	}, {
+		body: `{"Components":[]}`,
+	}, {
+		body: `{"Components":[{"ID":"foo"}]}`,
+	}, {
+		body: `{"Components":[{"ID":"x1000c0s0b0n0","Type":"NodeBMC"}]}`,
+	}, {
+		body: `{"Components":[{"ID":"x1000c0s0b0n0","Class":"Cloud"}]}`,
+	}, {
+		body: `{"Components":[{"ID":"x1000c0s0b0n0"}],"Scope":["bar"]}`,
+	}, {
+		body: `[{"Xname":1}]`,
+	}}
+	for i, test := range tests {
+		m, err := decodeReconcileManifest([]byte(test.body))
+		if test.expected == nil {
+			if err == nil {
+				t.Errorf("Test %d: expected an error, got %+v", i, m)
+			}
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, m) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, m)
+		}
+	}
+}
+
+func TestDoReconcilePost(t *testing.T) {
+	defer func() {
+		results.GetComponentsAll.Return.ids = nil
+		results.GetComponentsAll.Return.err = nil
+	}()
+	results.GetComponentsAll.Return.ids = reconcileTestComps()
+	results.GetComponentsAll.Return.err = nil
+
+	tests := []struct {
+		body     string
+		code     int
+		expected map[string]int
+	}{{
+		body:     `{"Components":[{"ID":"x1000c0s0b0n0"},{"ID":"x1000c0s9b0n0"}],"Scope":["x1000c1"]}`,
+		code:     http.StatusOK,
+		expected: map[string]int{"Critical": 1, "Warning": 1, "Info": 0},
+	}, {
+		body: `{"Components":[{"ID":"x1000c0s0b0n0","Type":"Cabinet"}]}`,
+		code: http.StatusBadRequest,
+	}, {
+		body: `{"Components":`,
+		code: http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Inventory/Reconcile",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+		var rpt ReconcileReport
+		if err := json.Unmarshal(w.Body.Bytes(), &rpt); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, rpt.Summary) {
+			t.Errorf("Test %d: expected summary %v, got %v", i, test.expected,
+				rpt.Summary)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Reservation expiry notifications
+//
+// Service reservations that aren't renewed expire, and are then released by
+// CompReservationCleanup().  So that the services holding them find out
+// before their next renew fails, SMD sends:
+//
+//     Expiring  once per reservation, SMD_RESERVATION_EXPIRY_WARN_SECS
+//               (default 60, 0 to disable) before its expiration time
+//     Expired   when an expired reservation is released
+//
+// both as an SCN, to subscriptions with Reservations set to the trigger,
+// and as a ReservationExpiring or ReservationExpired bus event.  The SCN
+// payload has the components and Reservation set to the trigger.
+//
+//     GET /locks/service/reservations/expiring?within=N
+//
+// lists the reservations expiring in the next N seconds (default 60),
+// including any that have expired but not yet been released.
+//
+// Each SMD instance checks for expiring reservations, so with more than one
+// instance a subscriber may get the same Expiring SCN from each of them,
+// unless SMD_CLUSTER_LEASE_SECS is set and only the leader checks, see
+// cluster.go.  Expired SCNs are only sent by the instance that released the
+// reservation.
+///////////////////////////////////////////////////////////////////////////////
+
+// How often to look for reservations that are about to expire.
+const resExpiryPollIntvl = 10 * time.Second
+
+// Default warning before a reservation expires, and for ?within=
+const resExpiryWarnDefault = 60 * time.Second
+
+// Tracks the reservations an Expiring notification has been sent for.
+type ReservationExpiry struct {
+	Warn   time.Duration // How long before expiration to warn, 0 disables
+	lock   sync.Mutex
+	warned map[string]string // ExpirationTime warned about, by xname
+}
+
+// Returns the reservations that haven't been warned about yet, and forgets
+// any that are no longer expiring, i.e. released or renewed.  A renewed
+// reservation is warned about again when it is next about to expire.
+func (re *ReservationExpiry) newlyExpiring(
+	res []sm.CompLockV2Expiring,
+) []sm.CompLockV2Expiring {
+	re.lock.Lock()
+	defer re.lock.Unlock()
+
+	expiring := []sm.CompLockV2Expiring{}
+	warned := make(map[string]string, len(res))
+	for _, r := range res {
+		if exp, ok := re.warned[r.ID]; !ok || exp != r.ExpirationTime {
+			expiring = append(expiring, r)
+		}
+		warned[r.ID] = r.ExpirationTime
+	}
+	re.warned = warned
+	return expiring
+}
+
+// Spin off a thread to periodically send notifications for reservations that
+// are about to expire.
+func (s *SmD) ReservationExpiryNotifier() {
+	if s.resExpiry.Warn <= 0 {
+		return
+	}
+	go func() {
+		for {
+			if s.cluster.isLeader() {
+				s.checkReservationsExpiring()
+			}
+			time.Sleep(resExpiryPollIntvl)
+		}
+	}()
+}
+
+// Send Expiring notifications for any reservations that expire within the
+// warning time and haven't been warned about yet.
+func (s *SmD) checkReservationsExpiring() {
+	res, err := s.db.GetCompReservationsExpiring(s.resExpiry.Warn)
+	if err != nil {
+		s.LogAlways("checkReservationsExpiring(): Lookup failure: %s", err)
+		return
+	}
+	expiring := s.resExpiry.newlyExpiring(res)
+	if len(expiring) > 0 {
+		s.Log(LOG_INFO, "%d component reservations expiring", len(expiring))
+		s.reservationNotify(sm.SCNReservationExpiring, expiring)
+	}
+}
+
+// Send Expired notifications for the xnames whose reservations were released
+// by CompReservationCleanup().
+func (s *SmD) reservationsExpired(xnames []string) {
+	expired := make([]sm.CompLockV2Expiring, 0, len(xnames))
+	for _, xname := range xnames {
+		expired = append(expired, sm.CompLockV2Expiring{ID: xname})
+	}
+	s.reservationNotify(sm.SCNReservationExpired, expired)
+}
+
+// Send an SCN for the reservation trigger, sm.SCNReservationExpiring or
+// sm.SCNReservationExpired, and publish it to the event bus.
+func (s *SmD) reservationNotify(trigger string, res []sm.CompLockV2Expiring) {
+	busType := sm.BusResExpiring
+	if trigger == sm.SCNReservationExpired {
+		busType = sm.BusResExpired
+	}
+	s.publishBusEvent(busType, sm.CompLockV2ExpiringArray{Reservations: res})
+
+	scn := sm.SCNPayload{
+		Components:  make([]string, 0, len(res)),
+		Reservation: trigger,
+	}
+	for _, r := range res {
+		scn.Components = append(scn.Components, r.ID)
+	}
+	payload, err := json.Marshal(scn)
+	if err != nil {
+		s.LogAlways("WARNING: SCN failed. Could not encode JSON: %v (%v)", err, scn)
+		return
+	}
+	s.queueSCN(scn, payload, SCNMAP_RESERVED, strings.ToLower(trigger))
+}
+
+// List the service reservations expiring within ?within= seconds.
+func (s *SmD) doCompLocksServiceReservationExpiringGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	within := resExpiryWarnDefault
+	if val := r.URL.Query().Get("within"); val != "" {
+		secs, err := strconv.Atoi(val)
+		if err != nil || secs < 0 {
+			sendJsonError(w, http.StatusBadRequest,
+				"bad query param: within must be 0+ seconds")
+			return
+		}
+		within = time.Duration(secs) * time.Second
+	}
+	res, err := s.db.GetCompReservationsExpiring(within)
+	if err != nil {
+		s.lg.Printf("doCompLocksServiceReservationExpiringGet(): %s Err: %s", r.RemoteAddr, err)
+		sendJsonDBError(w, "", "operation 'GET' failed during query.", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, sm.CompLockV2ExpiringArray{Reservations: res})
+}
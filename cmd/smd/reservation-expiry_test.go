@@ -0,0 +1,237 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestReservationNewlyExpiring(t *testing.T) {
+	var re ReservationExpiry
+	r1 := sm.CompLockV2Expiring{ID: "x0c0s0b0n0", ExpirationTime: "2026-10-17T10:00:00Z"}
+	r2 := sm.CompLockV2Expiring{ID: "x0c0s0b0n1", ExpirationTime: "2026-10-17T10:00:30Z"}
+
+	if got := re.newlyExpiring([]sm.CompLockV2Expiring{r1}); len(got) != 1 {
+		t.Errorf("Expected r1 to be expiring, got %v", got)
+	}
+	got := re.newlyExpiring([]sm.CompLockV2Expiring{r1, r2})
+	if !reflect.DeepEqual(got, []sm.CompLockV2Expiring{r2}) {
+		t.Errorf("Expected only r2 to be newly expiring, got %v", got)
+	}
+	// Renewed, then about to expire again.
+	r1.ExpirationTime = "2026-10-17T10:01:00Z"
+	got = re.newlyExpiring([]sm.CompLockV2Expiring{r1, r2})
+	if !reflect.DeepEqual(got, []sm.CompLockV2Expiring{r1}) {
+		t.Errorf("Expected renewed r1 to be expiring again, got %v", got)
+	}
+	// Released reservations are forgotten.
+	re.newlyExpiring([]sm.CompLockV2Expiring{})
+	if len(re.warned) != 0 {
+		t.Errorf("Expected no reservations to be tracked, got %v", re.warned)
+	}
+}
+
+func TestCheckReservationsExpiring(t *testing.T) {
+	oldDelivery := s.scnDelivery
+	oldSubMap := s.scnSubMap
+	oldSubs := s.scnSubs
+	oldWarn := s.resExpiry.Warn
+	defer func() {
+		s.scnDelivery = oldDelivery
+		s.scnSubMap = oldSubMap
+		s.scnSubs = oldSubs
+		s.resExpiry = ReservationExpiry{Warn: oldWarn}
+		s.eventBus = EventBus{}
+		results.GetCompReservationsExpiring.Return.res = nil
+	}()
+	d, ts, _ := newTestSCNDelivery(DefaultSCNDeliveryPolicy)
+	s.scnDelivery = d
+	sub := sm.SCNSubscription{ID: 1, Subscriber: "cfs@sms01",
+		Reservations: []string{sm.SCNReservationExpiring, sm.SCNReservationExpired},
+		Url:          "http://sms01/scn"}
+	s.scnSubs = sm.SCNSubscriptionArray{SubscriptionList: []sm.SCNSubscription{sub}}
+	s.scnSubMap = SCNSubMap{}
+	addSCNMapSubscription(&s.scnSubMap, &sub)
+	s.eventBus = EventBus{queue: make(chan []byte, 10)}
+	s.resExpiry = ReservationExpiry{Warn: 30 * time.Second}
+
+	results.GetCompReservationsExpiring.Return.res = []sm.CompLockV2Expiring{
+		{ID: "x0c0s0b0n0", ExpirationTime: "2026-10-17T10:00:00Z"},
+	}
+	results.GetCompReservationsExpiring.Return.err = nil
+	s.checkReservationsExpiring()
+	// Only once per reservation.
+	s.checkReservationsExpiring()
+	s.reservationsExpired([]string{"x0c0s0b0n0"})
+	waitSCNDelivery(t, d)
+
+	if results.GetCompReservationsExpiring.Input.within != 30*time.Second {
+		t.Errorf("Expected a 30s warning, got %v",
+			results.GetCompReservationsExpiring.Input.within)
+	}
+	expected := []string{
+		`{"Components":["x0c0s0b0n0"],"Reservation":"Expiring"}`,
+		`{"Components":["x0c0s0b0n0"],"Reservation":"Expired"}`,
+	}
+	if got := ts.get(sub.Url); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected SCNs %v, got %v", expected, got)
+	}
+	for _, evType := range []string{sm.BusResExpiring, sm.BusResExpired} {
+		ev := sm.BusEvent{Data: &sm.CompLockV2ExpiringArray{}}
+		if err := json.Unmarshal(<-s.eventBus.queue, &ev); err != nil {
+			t.Fatalf("Unmarshal: %s", err)
+		}
+		data := ev.Data.(*sm.CompLockV2ExpiringArray)
+		if ev.Type != evType || len(data.Reservations) != 1 ||
+			data.Reservations[0].ID != "x0c0s0b0n0" {
+			t.Errorf("Expected a %s event, got %+v", evType, ev)
+		}
+	}
+	if len(s.eventBus.queue) != 0 {
+		t.Errorf("Expected no more events, got %d", len(s.eventBus.queue))
+	}
+}
+
+func TestDoCompLocksServiceReservationExpiringGet(t *testing.T) {
+	defer func() {
+		results.GetCompReservationsExpiring.Return.res = nil
+		results.GetCompReservationsExpiring.Return.err = nil
+	}()
+	results.GetCompReservationsExpiring.Return.res = []sm.CompLockV2Expiring{{
+		ID:             "x0c0s0b0n0",
+		CreationTime:   "2026-10-17T09:59:00Z",
+		ExpirationTime: "2026-10-17T10:00:00Z",
+	}}
+
+	tests := []struct {
+		query        string
+		dbErr        error
+		expectedIn   time.Duration
+		expectedCode int
+		expectedResp string
+	}{{
+		"",
+		nil,
+		resExpiryWarnDefault,
+		http.StatusOK,
+		`{"Reservations":[{"ID":"x0c0s0b0n0","CreationTime":"2026-10-17T09:59:00Z","ExpirationTime":"2026-10-17T10:00:00Z"}]}` + "\n",
+	}, {
+		"?within=300",
+		nil,
+		300 * time.Second,
+		http.StatusOK,
+		"",
+	}, {
+		"?within=-1",
+		nil,
+		0,
+		http.StatusBadRequest,
+		"",
+	}, {
+		"?within=soon",
+		nil,
+		0,
+		http.StatusBadRequest,
+		"",
+	}, {
+		"?within=30",
+		errors.New("db down"),
+		30 * time.Second,
+		http.StatusInternalServerError,
+		"",
+	}}
+	for i, test := range tests {
+		results.GetCompReservationsExpiring.Input.within = 0
+		results.GetCompReservationsExpiring.Return.err = test.dbErr
+		req, _ := http.NewRequest("GET",
+			"http://localhost/hsm/v2/locks/service/reservations/expiring"+test.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v",
+				i, test.expectedCode, w.Code)
+		}
+		if results.GetCompReservationsExpiring.Input.within != test.expectedIn {
+			t.Errorf("Test %v Failed: Expected within %v, got %v",
+				i, test.expectedIn, results.GetCompReservationsExpiring.Input.within)
+		}
+		if test.expectedResp != "" && w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+}
+
+func TestDoPostSCNSubscriptionReservations(t *testing.T) {
+	oldSubs := s.scnSubs
+	oldSubMap := s.scnSubMap
+	defer func() {
+		s.scnSubs = oldSubs
+		s.scnSubMap = oldSubMap
+	}()
+	s.scnSubs = sm.SCNSubscriptionArray{}
+	s.scnSubMap = SCNSubMap{}
+	results.InsertSCNSubscription.Return.id = 5
+	results.InsertSCNSubscription.Return.err = nil
+
+	tests := []struct {
+		body         string
+		expectedCode int
+		expectedResp string
+	}{{
+		`{"Subscriber":"cfs@sms01","Reservations":["expiring"],"Url":"http://sms01/scn"}`,
+		http.StatusOK,
+		`{"ID":5,"Subscriber":"cfs@sms01","Reservations":["Expiring"],"Url":"http://sms01/scn"}` + "\n",
+	}, {
+		`{"Subscriber":"cfs@sms01","Reservations":["Renewed"],"Url":"http://sms01/scn"}`,
+		http.StatusBadRequest,
+		"",
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("POST", "http://localhost/hsm/v2/Subscriptions/SCN",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v",
+				i, test.expectedCode, w.Code)
+		}
+		if test.expectedResp != "" && w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+	if urls := s.scnSubMap[SCNMAP_RESERVED]["expiring"]; len(urls) != 1 ||
+		urls[0].url != "http://sms01/scn" {
+		t.Errorf("Expected the subscription in the SCN map, got %v", urls)
+	}
+}
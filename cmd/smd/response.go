@@ -32,11 +32,16 @@ import (
 )
 
 type Response struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code     int      `json:"code"`
+	Message  string   `json:"message"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 func sendJSON(w http.ResponseWriter, code int, data interface{}) {
+	if rsp, ok := data.(Response); ok {
+		rsp.Warnings = responseWarnings(w)
+		data = rsp
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
 	if data != nil && code != http.StatusNoContent {
@@ -48,14 +53,14 @@ func sendJSON(w http.ResponseWriter, code int, data interface{}) {
 
 func sendJsonError(w http.ResponseWriter, code int, msg string) {
 	if code < 400 {
-		sendJSON(w, code, Response{0, msg})
+		sendJSON(w, code, Response{Code: 0, Message: msg})
 	} else {
 		base.SendProblemDetailsGeneric(w, code, msg)
 	}
 }
 
 func sendJsonResponse(w http.ResponseWriter, code int, msg string) {
-	sendJSON(w, code, Response{code, msg})
+	sendJSON(w, code, Response{Code: code, Message: msg})
 }
 
 func sendJsonDBError(w http.ResponseWriter, prefix, internalErr string, err error) {
@@ -122,7 +127,12 @@ func sendJsonCompRsp(w http.ResponseWriter, comp *base.Component) {
 	sendJsonObject(w, http.StatusOK, comp)
 }
 
-func sendJsonCompArrayRsp(w http.ResponseWriter, comps *base.ComponentArray) {
+func sendJsonCompArrayRsp(w http.ResponseWriter, r *http.Request, comps *base.ComponentArray) {
+	w.Header().Add("Vary", "Accept")
+	if comps != nil && acceptsCompact(r) {
+		sendCompactCompArray(w, NewCompactComponentArray(comps))
+		return
+	}
 	sendJsonObject(w, http.StatusOK, comps)
 }
 
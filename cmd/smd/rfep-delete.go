@@ -0,0 +1,395 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Batch RedfishEndpoint deletion
+//
+// DELETE /Inventory/RedfishEndpoints?id=...&id=... removes the given
+// RedfishEndpoints along with everything discovered through them: their
+// ComponentEndpoints and ServiceEndpoints, the components themselves (and
+// so their group and partition memberships), the components'
+// EthernetInterfaces, and the HW inventory locations linking FRUs to them
+// and, for nodes, to their processors, memory and other subcomponents.
+// The FRUs and their history are kept.
+//
+// Since that is a lot to lose by mistake, the first request only returns a
+// preview of what would be removed, with a ConfirmationToken.  Nothing is
+// deleted until the same request is repeated with &confirm=<token> before
+// the token expires.  Tokens are single use and kept in memory.  If what
+// would be removed has changed in the meantime, e.g. because of a
+// rediscovery, the delete is refused with a new preview and token.
+//
+// The deletion is not a single transaction: if it fails part way, what
+// was already deleted stays deleted, and the error says what failed.
+///////////////////////////////////////////////////////////////////////////////
+
+// How long a ConfirmationToken is valid.
+const RFEPDeleteTokenTTL = 5 * time.Minute
+
+// A component's memberships that would be removed along with it.
+type RFEPDeleteMembership struct {
+	ID            string   `json:"ID"`
+	GroupLabels   []string `json:"GroupLabels,omitempty"`
+	PartitionName string   `json:"PartitionName,omitempty"`
+}
+
+// Output of DELETE /Inventory/RedfishEndpoints?id=... without confirm, and
+// of a refused confirmation.
+type RFEPDeletePreview struct {
+	RedfishEndpoints     []string               `json:"RedfishEndpoints"`
+	ComponentEndpoints   []string               `json:"ComponentEndpoints"`
+	ServiceEndpoints     []string               `json:"ServiceEndpoints"`
+	Components           []string               `json:"Components"`
+	EthernetInterfaces   []string               `json:"EthernetInterfaces"`
+	HWInventoryLocations []string               `json:"HWInventoryLocations"`
+	Memberships          []RFEPDeleteMembership `json:"Memberships"`
+
+	ConfirmationToken string `json:"ConfirmationToken,omitempty"`
+	ExpiresAt         string `json:"ExpiresAt,omitempty"`
+}
+
+// Hash of what a preview would remove, ignoring its token.
+func (p RFEPDeletePreview) digest() string {
+	p.ConfirmationToken = ""
+	p.ExpiresAt = ""
+	data, _ := json.Marshal(p)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type rfepDeletePending struct {
+	ids     string // Sorted, comma separated
+	digest  string
+	expires time.Time
+}
+
+// Outstanding ConfirmationTokens
+type RFEPDeleteTokens struct {
+	lock    sync.Mutex
+	pending map[string]rfepDeletePending
+}
+
+// Issue a token for deleting what preview describes.
+func (dt *RFEPDeleteTokens) issue(preview *RFEPDeletePreview, now time.Time) error {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+	expires := now.Add(RFEPDeleteTokenTTL)
+
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	if dt.pending == nil {
+		dt.pending = make(map[string]rfepDeletePending)
+	}
+	for t, p := range dt.pending {
+		if now.After(p.expires) {
+			delete(dt.pending, t)
+		}
+	}
+	dt.pending[token] = rfepDeletePending{
+		ids:     strings.Join(preview.RedfishEndpoints, ","),
+		digest:  preview.digest(),
+		expires: expires,
+	}
+	preview.ConfirmationToken = token
+	preview.ExpiresAt = expires.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// Use up a token.  Returns false if it doesn't exist, has expired, or was
+// issued for other RedfishEndpoints or a different preview.
+func (dt *RFEPDeleteTokens) redeem(token string, preview *RFEPDeletePreview, now time.Time) bool {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	p, ok := dt.pending[token]
+	if !ok {
+		return false
+	}
+	delete(dt.pending, token)
+	return now.Before(p.expires) &&
+		p.ids == strings.Join(preview.RedfishEndpoints, ",") &&
+		p.digest == preview.digest()
+}
+
+// Work out everything deleting the given RedfishEndpoints would remove.
+// Returns the IDs that don't exist as well.
+func (s *SmD) getRFEPDeletePreview(ids []string) (*RFEPDeletePreview, []string, error) {
+	preview := &RFEPDeletePreview{
+		RedfishEndpoints:     []string{},
+		ComponentEndpoints:   []string{},
+		ServiceEndpoints:     []string{},
+		Components:           []string{},
+		EthernetInterfaces:   []string{},
+		HWInventoryLocations: []string{},
+		Memberships:          []RFEPDeleteMembership{},
+	}
+	eps, err := s.db.GetRFEndpointsFilter(&hmsds.RedfishEPFilter{ID: ids})
+	if err != nil {
+		return nil, nil, err
+	}
+	found := make(map[string]bool)
+	for _, ep := range eps {
+		found[ep.ID] = true
+		preview.RedfishEndpoints = append(preview.RedfishEndpoints, ep.ID)
+	}
+	missing := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 || len(eps) == 0 {
+		return preview, missing, nil
+	}
+	epIDs := preview.RedfishEndpoints
+
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		RfEndpointID: epIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes := []string{}
+	for _, cep := range ceps {
+		preview.ComponentEndpoints = append(preview.ComponentEndpoints, cep.ID)
+		if xnametypes.GetHMSType(cep.ID) == xnametypes.Node {
+			nodes = append(nodes, cep.ID)
+		}
+	}
+	seps, err := s.db.GetServiceEndpointsFilter(&hmsds.ServiceEPFilter{
+		RfEndpointID: epIDs,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sep := range seps {
+		preview.ServiceEndpoints = append(preview.ServiceEndpoints,
+			sep.RfEndpointID+"/"+sep.RedfishType)
+	}
+
+	// Only the components discovered through the endpoints, not anything
+	// else below them, e.g. the blades in a chassis whose BMC is deleted.
+	compIDs := preview.ComponentEndpoints
+	if len(compIDs) > 0 {
+		comps, err := s.db.GetComponentsFilter(&hmsds.ComponentFilter{
+			ID: compIDs,
+		}, hmsds.FLTR_ID_ONLY)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, comp := range comps {
+			preview.Components = append(preview.Components, comp.ID)
+		}
+	}
+	if len(preview.Components) > 0 {
+		ceis, err := s.db.GetCompEthInterfaceFilter(
+			hmsds.CEI_CompIDs(preview.Components))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, cei := range ceis {
+			preview.EthernetInterfaces = append(preview.EthernetInterfaces,
+				cei.ID)
+		}
+		mships, err := s.db.GetMemberships(&hmsds.ComponentFilter{
+			ID: preview.Components,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, m := range mships {
+			if len(m.GroupLabels) == 0 && m.PartitionName == "" {
+				continue
+			}
+			preview.Memberships = append(preview.Memberships,
+				RFEPDeleteMembership{
+					ID:            m.ID,
+					GroupLabels:   m.GroupLabels,
+					PartitionName: m.PartitionName,
+				})
+		}
+	}
+	// Locations of the components themselves, plus everything in the nodes.
+	locs := make(map[string]bool)
+	if len(compIDs) > 0 {
+		hwlocs, err := s.db.GetHWInvByLocFilter(hmsds.HWInvLoc_IDs(compIDs))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, hwloc := range hwlocs {
+			locs[hwloc.ID] = true
+		}
+	}
+	if len(nodes) > 0 {
+		hwlocs, err := s.db.GetHWInvByLocFilter(hmsds.HWInvLoc_IDs(nodes),
+			hmsds.HWInvLoc_Child)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, hwloc := range hwlocs {
+			locs[hwloc.ID] = true
+		}
+	}
+	for id := range locs {
+		preview.HWInventoryLocations = append(preview.HWInventoryLocations, id)
+	}
+
+	for _, list := range [][]string{
+		preview.RedfishEndpoints,
+		preview.ComponentEndpoints,
+		preview.ServiceEndpoints,
+		preview.Components,
+		preview.EthernetInterfaces,
+		preview.HWInventoryLocations,
+	} {
+		sort.Strings(list)
+	}
+	sort.Slice(preview.Memberships, func(i, j int) bool {
+		return preview.Memberships[i].ID < preview.Memberships[j].ID
+	})
+	return preview, missing, nil
+}
+
+// Delete everything in a preview, as actor.  Children go before their
+// parents, so nothing is left pointing at something deleted if it fails
+// part way.
+func (s *SmD) deleteRFEPPreview(preview *RFEPDeletePreview, actor string) error {
+	db := s.db.WithActor(actor)
+	for _, id := range preview.EthernetInterfaces {
+		if _, err := s.db.DeleteCompEthInterfaceByID(id); err != nil {
+			return err
+		}
+	}
+	for _, id := range preview.HWInventoryLocations {
+		if _, err := s.db.DeleteHWInvByLocID(id); err != nil {
+			return err
+		}
+	}
+	affected := []string{}
+	for _, id := range preview.RedfishEndpoints {
+		_, affectedIDs, err := db.DeleteRFEndpointByIDSetEmpty(id)
+		if err != nil {
+			return err
+		}
+		affected = append(affected, affectedIDs...)
+		// Its components no longer claim any MACs.
+		s.updateMACConflicts(id, nil, nil, nil)
+		s.compCounts.Remove(id)
+	}
+	if len(affected) != 0 {
+		data := base.Component{
+			State: base.StateEmpty.String(),
+			Flag:  base.FlagOK.String(),
+		}
+		scn := NewJobSCN(affected, data, s)
+		s.wp.Queue(scn)
+	}
+	for _, id := range preview.Components {
+		if _, err := db.DeleteComponentByID(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Preview or, given a ConfirmationToken, carry out the deletion of the
+// RedfishEndpoints in the id query parameters.
+func (s *SmD) doRedfishEndpointsDeleteBatch(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	query := r.URL.Query()
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, id := range query["id"] {
+		normID := xnametypes.VerifyNormalizeCompID(id)
+		if normID == "" {
+			sendJsonError(w, http.StatusBadRequest, "invalid xname "+id)
+			return
+		}
+		if !seen[normID] {
+			seen[normID] = true
+			ids = append(ids, normID)
+		}
+	}
+	sort.Strings(ids)
+
+	preview, missing, err := s.getRFEPDeletePreview(ids)
+	if err != nil {
+		s.LogAlways("doRedfishEndpointsDeleteBatch(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
+		return
+	}
+	if len(missing) > 0 {
+		sendJsonError(w, http.StatusNotFound,
+			"no such xname: "+strings.Join(missing, ", "))
+		return
+	}
+
+	token := query.Get("confirm")
+	if token == "" {
+		if err := s.rfepDeleteTokens.issue(preview, time.Now()); err != nil {
+			s.LogAlways("doRedfishEndpointsDeleteBatch(): %s", err)
+			sendJsonError(w, http.StatusInternalServerError,
+				"failed to issue confirmation token.")
+			return
+		}
+		sendJsonObject(w, http.StatusOK, preview)
+		return
+	}
+	if !s.rfepDeleteTokens.redeem(token, preview, time.Now()) {
+		// A new token for the current state, so it can be looked at again.
+		if err := s.rfepDeleteTokens.issue(preview, time.Now()); err != nil {
+			s.LogAlways("doRedfishEndpointsDeleteBatch(): %s", err)
+		}
+		sendJsonObject(w, http.StatusConflict, preview)
+		return
+	}
+
+	s.LogAlways("doRedfishEndpointsDeleteBatch(): Deleting %v", ids)
+	if err := s.deleteRFEPPreview(preview, s.requestActor(r)); err != nil {
+		s.LogAlways("doRedfishEndpointsDeleteBatch(): Delete failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"delete failed part way: "+err.Error())
+		return
+	}
+	sendJsonObject(w, http.StatusOK, preview)
+}
@@ -0,0 +1,180 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestRFEPDeleteTokens(t *testing.T) {
+	now := time.Now()
+	var dt RFEPDeleteTokens
+	preview := &RFEPDeletePreview{RedfishEndpoints: []string{"x0c0s0b0"}}
+	if err := dt.issue(preview, now); err != nil || preview.ConfirmationToken == "" {
+		t.Fatalf("issue: %v '%s'", err, preview.ConfirmationToken)
+	}
+	token := preview.ConfirmationToken
+
+	other := &RFEPDeletePreview{RedfishEndpoints: []string{"x0c0s1b0"}}
+	changed := &RFEPDeletePreview{
+		RedfishEndpoints: []string{"x0c0s0b0"},
+		Components:       []string{"x0c0s0b0n0"},
+	}
+	same := &RFEPDeletePreview{RedfishEndpoints: []string{"x0c0s0b0"}}
+	tests := []struct {
+		name    string
+		token   string
+		preview *RFEPDeletePreview
+		now     time.Time
+		exp     bool
+	}{
+		{"unknown", "1234", same, now, false},
+		{"other endpoints", token, other, now, false},
+		{"changed", token, changed, now, false},
+		{"expired", token, same, now.Add(RFEPDeleteTokenTTL + time.Second), false},
+		{"ok", token, same, now, true},
+		{"used", token, same, now, false},
+	}
+	for _, test := range tests {
+		if test.token == token && test.name != "used" {
+			// Each redeem uses the token up, so issue it again
+			dt.pending[token] = rfepDeletePending{
+				ids:     "x0c0s0b0",
+				digest:  same.digest(),
+				expires: now.Add(RFEPDeleteTokenTTL),
+			}
+		}
+		if got := dt.redeem(test.token, test.preview, test.now); got != test.exp {
+			t.Errorf("%s: Expected %v, got %v", test.name, test.exp, got)
+		}
+	}
+}
+
+func TestDoRedfishEndpointsDeleteBatch(t *testing.T) {
+	defer func() {
+		results.GetRFEndpointsFilter.Return.entries = nil
+		results.GetCompEndpointsFilter.Return.entries = nil
+		results.GetComponentsFilter.Return.ids = nil
+		results.GetCompEthInterfaceFilter.Return.ceis = nil
+		results.GetMemberships.Return.memberships = nil
+		results.GetHWInvByLocFilter.Return.hwlocs = nil
+		results.DeleteRFEndpointByIDSetEmpty.Return.changed = false
+		results.DeleteRFEndpointByIDSetEmpty.Return.affectedIds = nil
+	}()
+	results.GetRFEndpointsFilter.Return.entries = []*sm.RedfishEndpoint{{
+		RedfishEPDescription: rf.RedfishEPDescription{ID: "x0c0s0b0"},
+	}}
+	results.GetCompEndpointsFilter.Return.entries = []*sm.ComponentEndpoint{{
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s0b0", RfEndpointID: "x0c0s0b0"},
+	}, {
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s0b0n0", RfEndpointID: "x0c0s0b0"},
+	}}
+	results.GetServiceEndpointsFilter.Return.entries = nil
+	results.GetComponentsFilter.Return.ids = []*base.Component{
+		{ID: "x0c0s0b0"}, {ID: "x0c0s0b0n0"}}
+	results.GetCompEthInterfaceFilter.Return.ceis = []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0")}
+	results.GetMemberships.Return.memberships = []*sm.Membership{
+		{ID: "x0c0s0b0", GroupLabels: []string{}},
+		{ID: "x0c0s0b0n0", GroupLabels: []string{"compute"}, PartitionName: "p1"}}
+	results.GetHWInvByLocFilter.Return.hwlocs = []*sm.HWInvByLoc{
+		{ID: "x0c0s0b0n0"}, {ID: "x0c0s0b0n0p0"}}
+	results.DeleteRFEndpointByIDSetEmpty.Return.changed = true
+	results.DeleteRFEndpointByIDSetEmpty.Return.affectedIds = []string{"x0c0s0b0n0"}
+
+	del := func(uri string, expCode int) RFEPDeletePreview {
+		req, _ := http.NewRequest("DELETE", uri, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != expCode {
+			t.Fatalf("DELETE %s: Response code was %v; want %v: %s",
+				uri, w.Code, expCode, w.Body)
+		}
+		var preview RFEPDeletePreview
+		json.Unmarshal(w.Body.Bytes(), &preview)
+		return preview
+	}
+	epURI := "https://localhost/hsm/v2/Inventory/RedfishEndpoints?id=x0c0s0b0"
+
+	results.DeleteRFEndpointByIDSetEmpty.Input.id = ""
+	preview := del(epURI, http.StatusOK)
+	exp := RFEPDeletePreview{
+		RedfishEndpoints:     []string{"x0c0s0b0"},
+		ComponentEndpoints:   []string{"x0c0s0b0", "x0c0s0b0n0"},
+		ServiceEndpoints:     []string{},
+		Components:           []string{"x0c0s0b0", "x0c0s0b0n0"},
+		EthernetInterfaces:   []string{"a4bf01000001"},
+		HWInventoryLocations: []string{"x0c0s0b0n0", "x0c0s0b0n0p0"},
+		Memberships: []RFEPDeleteMembership{{
+			ID: "x0c0s0b0n0", GroupLabels: []string{"compute"}, PartitionName: "p1"}},
+	}
+	token := preview.ConfirmationToken
+	preview.ConfirmationToken, preview.ExpiresAt = "", ""
+	if !reflect.DeepEqual(preview, exp) || token == "" {
+		t.Errorf("Expected preview %+v, got %+v", exp, preview)
+	}
+	if results.DeleteRFEndpointByIDSetEmpty.Input.id != "" {
+		t.Errorf("Preview deleted something")
+	}
+
+	// Wrong token gets a new one without deleting anything
+	if p := del(epURI+"&confirm=1234", http.StatusConflict); p.ConfirmationToken == "" {
+		t.Errorf("Expected a new token")
+	}
+	if results.DeleteRFEndpointByIDSetEmpty.Input.id != "" {
+		t.Errorf("Bad token deleted something")
+	}
+
+	// Inventory changed since the preview
+	results.GetCompEthInterfaceFilter.Return.ceis = nil
+	del(epURI+"&confirm="+token, http.StatusConflict)
+	results.GetCompEthInterfaceFilter.Return.ceis = []*sm.CompEthInterfaceV2{
+		testCEI("a4:bf:01:00:00:01", "x0c0s0b0n0")}
+
+	token = del(epURI, http.StatusOK).ConfirmationToken
+	del(epURI+"&confirm="+token, http.StatusOK)
+	if results.DeleteRFEndpointByIDSetEmpty.Input.id != "x0c0s0b0" ||
+		results.DeleteComponentByID.Input.id != "x0c0s0b0n0" ||
+		results.DeleteCompEthInterfaceByID.Input.id != "a4bf01000001" ||
+		results.DeleteHWInvByLocID.Input.id != "x0c0s0b0n0p0" {
+		t.Errorf("Not everything was deleted")
+	}
+	// Tokens are single use
+	del(epURI+"&confirm="+token, http.StatusConflict)
+
+	// Unknown and invalid endpoints
+	results.GetRFEndpointsFilter.Return.entries = nil
+	del(epURI, http.StatusNotFound)
+	del("https://localhost/hsm/v2/Inventory/RedfishEndpoints?id=foo", http.StatusBadRequest)
+}
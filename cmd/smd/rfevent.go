@@ -109,6 +109,7 @@ func (s *SmD) doHandleRFEvent(eventRaw string) error {
 		}
 		s.Log(LOG_INFO, "CHANGING STATE: %s->%s: calling doCompUpdate(%s) CompUpdateType=%s",
 			pe.RfEndppointID, pe.MessageId, update.ComponentIDs, update.UpdateType)
+		update.actor = CompHistActorRedfishEvent
 		err = s.doCompUpdate(update, "handleRFEvent")
 		if err != nil {
 			s.LogAlways("ERROR: %s->%s: calling doCompUpdate(%s): %s",
@@ -297,18 +298,48 @@ type EventActionParser func(*SmD, *processedRFEvent) (*CompUpdate, error)
 //
 //	MessageId:Registry:vers, but this time include maj version, i.e. 1.1
 var eventActionParserLookup = map[string]EventActionParser{
-	"resourcepowerstatechanged":               nil,
-	"resourcepowerstatechanged:resourceevent": ResourcePowerStateChangedParser,
-	"resourcepowerstatechanged:crayalerts":    ResourcePowerStateChangedParser,
-	"resourcepowerstatechanged:":              ResourcePowerStateChangedParser,
-	"systempoweron":                           AlertSystemPowerOnParser,
-	"systempoweroff":                          AlertSystemPowerOffParser,
-	"alert":                                   AlertSystemPowerParser,
-	"powerstatuschange":                       AlertSystemPowerParser,
-	"serverpoweredon":                         AlertSystemPowerOnParser,
-	"serverpoweredoff":                        AlertSystemPowerOffParser,
-	"dcpoweron":                               FoxconnAlertSystemPowerOnParser,
-	"dcpoweroff":                              FoxconnAlertSystemPowerOffParser,
+	"resourcepowerstatechanged":                   nil,
+	"resourcepowerstatechanged:resourceevent":     ResourcePowerStateChangedParser,
+	"resourcepowerstatechanged:crayalerts":        ResourcePowerStateChangedParser,
+	"resourcepowerstatechanged:":                  ResourcePowerStateChangedParser,
+	"systempoweron":                               AlertSystemPowerOnParser,
+	"systempoweroff":                              AlertSystemPowerOffParser,
+	"alert":                                       AlertSystemPowerParser,
+	"powerstatuschange":                           AlertSystemPowerParser,
+	"serverpoweredon":                             AlertSystemPowerOnParser,
+	"serverpoweredoff":                            AlertSystemPowerOffParser,
+	"dcpoweron":                                   FoxconnAlertSystemPowerOnParser,
+	"dcpoweroff":                                  FoxconnAlertSystemPowerOffParser,
+	"leakdetected":                                nil,
+	"leakdetected:crayalerts":                     CoolingFaultParser,
+	"leakcleared":                                 nil,
+	"leakcleared:crayalerts":                      CoolingFaultParser,
+	"coolantfault":                                nil,
+	"coolantfault:crayalerts":                     CoolingFaultParser,
+	"coolantfaultcleared":                         nil,
+	"coolantfaultcleared:crayalerts":              CoolingFaultParser,
+	"chassisintrusiondetected":                    IntrusionParser,
+	"chassisintrusioncleared":                     IntrusionParser,
+	"intrusiondetected":                           IntrusionParser,
+	"intrusioncleared":                            IntrusionParser,
+	"driveremoved":                                DriveEventParser,
+	"driveinserted":                               DriveEventParser,
+	"drivefailure":                                DriveEventParser,
+	"drivefailurecleared":                         DriveEventParser,
+	"driveoffline":                                DriveEventParser,
+	"driveofflinecleared":                         DriveEventParser,
+	"drivepredictivefailure":                      DriveEventParser,
+	"drivepredictivefailurecleared":               DriveEventParser,
+	"resourceadded":                               nil,
+	"resourceadded:resourceevent":                 ResourceAddedRemovedParser,
+	"resourceremoved":                             nil,
+	"resourceremoved:resourceevent":               ResourceAddedRemovedParser,
+	"resourcestatuschangedok":                     nil,
+	"resourcestatuschangedok:resourceevent":       ResourceStatusChangedParser,
+	"resourcestatuschangedwarning":                nil,
+	"resourcestatuschangedwarning:resourceevent":  ResourceStatusChangedParser,
+	"resourcestatuschangedcritical":               nil,
+	"resourcestatuschangedcritical:resourceevent": ResourceStatusChangedParser,
 }
 
 // Gets the EventActionParser function for the processed event or returns
@@ -753,11 +784,11 @@ func (s *SmD) getCompEPInfo(xname string) (*sm.ComponentEndpoint, *rf.RedfishEP,
 		if err != nil {
 			if strings.Contains(err.Error(), "Code: 404") {
 				// Ignore if there are no credentials in vault for the component
-				s.Log(LOG_INFO, "GetCompEPInfo(%s): No credentials in Vault - %s",
+				s.Log(LOG_INFO, "GetCompEPInfo(%s): No credentials in the credential store - %s",
 					xname, err)
 				return nil, nil, ErrSmMsgNoCreds
 			} else {
-				s.Log(LOG_INFO, "GetCompEPInfo(%s): Failed to get credentials from Vault - %s",
+				s.Log(LOG_INFO, "GetCompEPInfo(%s): Failed to get credentials from the credential store - %s",
 					xname, err)
 				return nil, nil, ErrSmMsgCredsStore
 			}
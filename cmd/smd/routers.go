@@ -47,6 +47,14 @@ type Route struct {
 
 type Routes []Route
 
+// Routes polled often enough that they are only logged when debugging.
+func isQuietRoute(route Route) bool {
+	return strings.Contains(route.Name, "doReadyGet") ||
+		strings.Contains(route.Name, "doLivenessGet") ||
+		strings.Contains(route.Name, "doDBHealthGet") ||
+		strings.Contains(route.Name, "doMetricsGet")
+}
+
 func (s *SmD) NewRouter(publicRoutes []Route, protectedRoutes []Route) *chi.Mux {
 	// create router and use recommended middleware
 	router := chi.NewRouter()
@@ -76,13 +84,13 @@ func (s *SmD) NewRouter(publicRoutes []Route, protectedRoutes []Route) *chi.Mux
 
 			// Register protected routes
 			for _, route := range protectedRoutes {
-				var handler http.Handler = route.HandlerFunc
-				if s.lgLvl >= LOG_DEBUG ||
-					(!strings.Contains(route.Name, "doReadyGet") &&
-						!strings.Contains(route.Name, "doLivenessGet")) {
+				var handler http.Handler = withWarnings(route,
+					s.rbacGuard(route, s.etagGuard(route, s.readOnlyGuard(route))))
+				if s.lgLvl >= LOG_DEBUG || !isQuietRoute(route) {
 					handler = handlers.CombinedLoggingHandler(os.Stdout, handler)
 					handler = s.Logger(handler, route.Name)
 				}
+				handler = s.instrumentRoute(route, handler)
 				r.Method(
 					route.Method,
 					route.Pattern,
@@ -94,12 +102,11 @@ func (s *SmD) NewRouter(publicRoutes []Route, protectedRoutes []Route) *chi.Mux
 		// Register public routes
 		for _, route := range publicRoutes {
 			var handler http.Handler
-			handler = route.HandlerFunc
-			if s.lgLvl >= LOG_DEBUG ||
-				(!strings.Contains(route.Name, "doReadyGet") &&
-					!strings.Contains(route.Name, "doLivenessGet")) {
+			handler = withWarnings(route, s.etagGuard(route, s.readOnlyGuard(route)))
+			if s.lgLvl >= LOG_DEBUG || !isQuietRoute(route) {
 				handler = handlers.CombinedLoggingHandler(os.Stdout, handler)
 			}
+			handler = s.instrumentRoute(route, handler)
 			router.Method(
 				route.Method,
 				route.Pattern,
@@ -111,12 +118,11 @@ func (s *SmD) NewRouter(publicRoutes []Route, protectedRoutes []Route) *chi.Mux
 		routes := append(publicRoutes, protectedRoutes...)
 		for _, route := range routes {
 			var handler http.Handler
-			handler = route.HandlerFunc
-			if s.lgLvl >= LOG_DEBUG ||
-				(!strings.Contains(route.Name, "doReadyGet") &&
-					!strings.Contains(route.Name, "doLivenessGet")) {
+			handler = withWarnings(route, s.etagGuard(route, s.readOnlyGuard(route)))
+			if s.lgLvl >= LOG_DEBUG || !isQuietRoute(route) {
 				handler = handlers.CombinedLoggingHandler(os.Stdout, handler)
 			}
+			handler = s.instrumentRoute(route, handler)
 			router.Method(
 				route.Method,
 				route.Pattern,
@@ -180,6 +186,30 @@ func (s *SmD) generatePublicRoutes() Routes {
 			s.serviceBaseV2 + "/liveness",
 			s.doLivenessGet,
 		},
+		Route{
+			"doDBHealthGetV2",
+			strings.ToUpper("Get"),
+			s.serviceBaseV2 + "/health/db",
+			s.doDBHealthGet,
+		},
+		Route{
+			"doMetricsGet",
+			strings.ToUpper("Get"),
+			"/metrics",
+			s.doMetricsGet,
+		},
+		Route{
+			"doReadOnlyGetV2",
+			strings.ToUpper("Get"),
+			s.serviceBaseV2 + "/readonly",
+			s.doReadOnlyGet,
+		},
+		Route{
+			"doLogLevelGetV2",
+			strings.ToUpper("Get"),
+			s.serviceBaseV2 + "/loglevel",
+			s.doLogLevelGet,
+		},
 		Route{
 			"doValuesGetV2",
 			strings.ToUpper("Get"),
@@ -247,24 +277,100 @@ func (s *SmD) generatePublicRoutes() Routes {
 			s.compEthIntBaseV2,
 			s.doCompEthInterfacesGetV2,
 		},
+		// Authorized by a component token, see comptokens.go
+		Route{
+			"doCompSelfReportPatchV2",
+			strings.ToUpper("Patch"),
+			s.componentsBaseV2 + "/{xname}/SelfReport",
+			s.doCompSelfReportPatch,
+		},
+		// Pushed by BMCs, which have no tokens
+		Route{
+			"doTelemetryMetricReportPostV2",
+			strings.ToUpper("Post"),
+			s.telemetryBaseV2 + "/MetricReports/{xname}",
+			s.doTelemetryMetricReportPost,
+		},
+		// Sent SCNs by other SMD instances, see comp-cache.go
+		Route{
+			"doCompCacheInvalidatePostV2",
+			strings.ToUpper("Post"),
+			s.componentsBaseV2 + "/Cache/Invalidate",
+			s.doCompCacheInvalidatePost,
+		},
+		Route{
+			"doRedfishEventPostV2",
+			strings.ToUpper("Post"),
+			s.eventsBaseV2 + "/Redfish",
+			s.doRedfishEventPost,
+		},
 	}
 }
 
 func (s *SmD) generateProtectedRoutes() Routes {
 	return Routes{
+		// HSM Service State
+		Route{
+			"doReadOnlyPutV2",
+			strings.ToUpper("Put"),
+			s.serviceBaseV2 + "/readonly",
+			s.doReadOnlyPut,
+		},
+		Route{
+			"doLogLevelPutV2",
+			strings.ToUpper("Put"),
+			s.serviceBaseV2 + "/loglevel",
+			s.doLogLevelPut,
+		},
+		Route{
+			"doClusterGetV2",
+			strings.ToUpper("Get"),
+			s.serviceBaseV2 + "/cluster",
+			s.doClusterGet,
+		},
+		Route{
+			"doRBACPolicyGetV2",
+			strings.ToUpper("Get"),
+			s.serviceBaseV2 + "/rbac",
+			s.doRBACPolicyGet,
+		},
+		Route{
+			"doRBACPolicyPutV2",
+			strings.ToUpper("Put"),
+			s.serviceBaseV2 + "/rbac",
+			s.doRBACPolicyPut,
+		},
 		// Components
+		Route{
+			"doComponentsStreamV2",
+			strings.ToUpper("Get"),
+			s.componentsBaseV2 + "/Stream",
+			s.doComponentsStream,
+		},
 		Route{
 			"doComponentGetV2",
 			strings.ToUpper("Get"),
 			s.componentsBaseV2 + "/{xname}",
 			s.doComponentGet,
 		},
+		Route{
+			"doCompHistoryGetV2",
+			strings.ToUpper("Get"),
+			s.componentsBaseV2 + "/{xname}/History",
+			s.doCompHistoryGet,
+		},
 		Route{
 			"doComponentPutV2",
 			strings.ToUpper("Put"),
 			s.componentsBaseV2 + "/{xname}",
 			s.doComponentPut,
 		},
+		Route{
+			"doComponentPatchV2",
+			strings.ToUpper("Patch"),
+			s.componentsBaseV2 + "/{xname}",
+			s.doComponentPatch,
+		},
 		Route{
 			"doComponentDeleteV2",
 			strings.ToUpper("Delete"),
@@ -277,12 +383,48 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.componentsBaseV2,
 			s.doComponentsPost,
 		},
+		Route{
+			"doComponentsPatchV2",
+			strings.ToUpper("Patch"),
+			s.componentsBaseV2,
+			s.doComponentsPatch,
+		},
 		Route{
 			"doComponentsDeleteAllV2",
 			strings.ToUpper("Delete"),
 			s.componentsBaseV2,
 			s.doComponentsDeleteAll,
 		},
+		Route{
+			"doDeletedComponentsGetV2",
+			strings.ToUpper("Get"),
+			s.deletedCompBaseV2,
+			s.doDeletedComponentsGet,
+		},
+		Route{
+			"doDeletedComponentGetV2",
+			strings.ToUpper("Get"),
+			s.deletedCompBaseV2 + "/{xname}",
+			s.doDeletedComponentGet,
+		},
+		Route{
+			"doDeletedComponentRestoreV2",
+			strings.ToUpper("Post"),
+			s.deletedCompBaseV2 + "/{xname}/Actions/Restore",
+			s.doDeletedComponentRestore,
+		},
+		Route{
+			"doCompHeartbeatsGetV2",
+			strings.ToUpper("Get"),
+			s.componentsBaseV2 + "/Heartbeats",
+			s.doCompHeartbeatsGet,
+		},
+		Route{
+			"doCompHeartbeatsPostV2",
+			strings.ToUpper("Post"),
+			s.componentsBaseV2 + "/Heartbeats",
+			s.doCompHeartbeatsPost,
+		},
 		Route{
 			"doCompBulkStateDataPatchV2",
 			"PATCH",
@@ -307,6 +449,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.componentsBaseV2 + "/{xname}/FlagOnly",
 			s.doCompFlagOnlyPatch,
 		},
+		Route{
+			"doCompTokenPostV2",
+			"POST",
+			s.componentsBaseV2 + "/{xname}/Token",
+			s.doCompTokenPost,
+		},
 		Route{
 			"doCompBulkEnabledPatchV2",
 			"PATCH",
@@ -451,6 +599,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.compEthIntBaseV2,
 			s.doCompEthInterfaceDeleteAll,
 		},
+		Route{
+			"doMACConflictsGet",
+			strings.ToUpper("Get"),
+			s.compEthIntBaseV2 + "/Conflicts",
+			s.doMACConflictsGet,
+		},
 		Route{
 			"doCompEthInterfaceGetV2",
 			strings.ToUpper("Get"),
@@ -531,6 +685,56 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.nodeMapBaseV2,
 			s.doNodeMapsDeleteAll,
 		},
+		Route{
+			"doNodeMapsImportPostV2",
+			strings.ToUpper("Post"),
+			s.nodeMapBaseV2 + "/Import",
+			s.doNodeMapsImportPost,
+		},
+
+		// NID Policies
+		Route{
+			"doNIDPoliciesGetV2",
+			strings.ToUpper("Get"),
+			s.nidPolicyBaseV2,
+			s.doNIDPoliciesGet,
+		},
+		Route{
+			"doNIDPolicyGetV2",
+			strings.ToUpper("Get"),
+			s.nidPolicyBaseV2 + "/{name}",
+			s.doNIDPolicyGet,
+		},
+		Route{
+			"doNIDPolicyPutV2",
+			strings.ToUpper("Put"),
+			s.nidPolicyBaseV2 + "/{name}",
+			s.doNIDPolicyPut,
+		},
+		Route{
+			"doNIDPolicyDeleteV2",
+			strings.ToUpper("Delete"),
+			s.nidPolicyBaseV2 + "/{name}",
+			s.doNIDPolicyDelete,
+		},
+		Route{
+			"doNIDPolicyPreviewPostV2",
+			strings.ToUpper("Post"),
+			s.nidPolicyBaseV2 + "/Actions/Preview",
+			s.doNIDPolicyPreviewPost,
+		},
+		Route{
+			"doNIDAssignmentsGetV2",
+			strings.ToUpper("Get"),
+			s.nidAssignBaseV2,
+			s.doNIDAssignmentsGet,
+		},
+		Route{
+			"doNIDAssignmentDeleteV2",
+			strings.ToUpper("Delete"),
+			s.nidAssignBaseV2 + "/{xname}",
+			s.doNIDAssignmentDelete,
+		},
 
 		// Hardware Inventory History
 		Route{
@@ -557,6 +761,18 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.hwinvByLocBaseV2 + "ByFRU/History",
 			s.doHWInvHistByFRUGetAll,
 		},
+		Route{
+			"doHWInvSightingsByFRUGetV2",
+			strings.ToUpper("Get"),
+			s.hwinvByLocBaseV2 + "History/{fruid}",
+			s.doHWInvSightingsByFRUGet,
+		},
+		Route{
+			"doHWInvSightingsGetAllV2",
+			strings.ToUpper("Get"),
+			s.hwinvByLocBaseV2 + "History",
+			s.doHWInvSightingsGetAll,
+		},
 		Route{
 			"doHWInvHistByLocationDeleteV2",
 			strings.ToUpper("Delete"),
@@ -583,6 +799,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.hwinvByLocBaseV2 + "/Query/{xname}",
 			s.doHWInvByLocationQueryGet,
 		},
+		Route{
+			"doHWInvByFRULocateGetV2",
+			strings.ToUpper("Get"),
+			s.hwinvByLocBaseV2 + "ByFRU/Locate",
+			s.doHWInvByFRULocateGet,
+		},
 		Route{
 			"doHWInvByFRUGetV2",
 			strings.ToUpper("Get"),
@@ -595,6 +817,18 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.hwinvByLocBaseV2 + "ByFRU",
 			s.doHWInvByFRUGetAll,
 		},
+		Route{
+			"doThermalSensorsGetV2",
+			strings.ToUpper("Get"),
+			s.thermalBaseV2,
+			s.doThermalSensorsGet,
+		},
+		Route{
+			"doSparePartsGetV2",
+			strings.ToUpper("Get"),
+			s.sparePartsBaseV2,
+			s.doSparePartsGet,
+		},
 		Route{
 			"doHWInvByLocationGetV2",
 			strings.ToUpper("Get"),
@@ -683,6 +917,30 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.redfishEPBaseV2,
 			s.doRedfishEndpointsDeleteAll,
 		},
+		Route{
+			"doDeletedRedfishEndpointsGetV2",
+			strings.ToUpper("Get"),
+			s.deletedRFEPBaseV2,
+			s.doDeletedRedfishEndpointsGet,
+		},
+		Route{
+			"doDeletedRedfishEndpointGetV2",
+			strings.ToUpper("Get"),
+			s.deletedRFEPBaseV2 + "/{xname}",
+			s.doDeletedRedfishEndpointGet,
+		},
+		Route{
+			"doDeletedRedfishEndpointRestoreV2",
+			strings.ToUpper("Post"),
+			s.deletedRFEPBaseV2 + "/{xname}/Actions/Restore",
+			s.doDeletedRedfishEndpointRestore,
+		},
+		Route{
+			"doRFEndpointLivenessGetV2",
+			strings.ToUpper("Get"),
+			s.redfishEPBaseV2 + "/Liveness",
+			s.doRFEndpointLivenessGet,
+		},
 		Route{
 			"doRedfishEndpointQueryGetV2",
 			strings.ToUpper("Get"),
@@ -707,6 +965,292 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.invDiscStatusBaseV2 + "/{id}",
 			s.doDiscoveryStatusGet,
 		},
+		Route{
+			"doCompCountHoldsGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscHoldBaseV2,
+			s.doCompCountHoldsGet,
+		},
+		Route{
+			"doCompCountHoldGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscHoldBaseV2 + "/{xname}",
+			s.doCompCountHoldGet,
+		},
+		Route{
+			"doCompCountHoldDeleteV2",
+			strings.ToUpper("Delete"),
+			s.invDiscHoldBaseV2 + "/{xname}",
+			s.doCompCountHoldDelete,
+		},
+		Route{
+			"doCompCountHoldConfirmV2",
+			strings.ToUpper("Post"),
+			s.invDiscHoldBaseV2 + "/{xname}/Actions/Confirm",
+			s.doCompCountHoldConfirm,
+		},
+		Route{
+			"doDiscSchedulesGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscSchedBaseV2,
+			s.doDiscSchedulesGet,
+		},
+		Route{
+			"doDiscScheduleGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscSchedBaseV2 + "/{name}",
+			s.doDiscScheduleGet,
+		},
+		Route{
+			"doDiscSchedulePutV2",
+			strings.ToUpper("Put"),
+			s.invDiscSchedBaseV2 + "/{name}",
+			s.doDiscSchedulePut,
+		},
+		Route{
+			"doDiscScheduleDeleteV2",
+			strings.ToUpper("Delete"),
+			s.invDiscSchedBaseV2 + "/{name}",
+			s.doDiscScheduleDelete,
+		},
+		Route{
+			"doDiscPlanGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscPlanBaseV2,
+			s.doDiscPlanGet,
+		},
+		Route{
+			"doDiscJobsGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscJobBaseV2,
+			s.doDiscJobsGet,
+		},
+		Route{
+			"doDiscJobGetV2",
+			strings.ToUpper("Get"),
+			s.invDiscJobBaseV2 + "/{id}",
+			s.doDiscJobGet,
+		},
+		Route{
+			"doDiscJobCancelV2",
+			strings.ToUpper("Post"),
+			s.invDiscJobBaseV2 + "/{id}/Actions/Cancel",
+			s.doDiscJobCancel,
+		},
+		Route{
+			"doHWInvExportGetV2",
+			strings.ToUpper("Get"),
+			s.invExportBaseV2 + "/{format}",
+			s.doHWInvExportGet,
+		},
+		Route{
+			"doHWInvExportResourceGetV2",
+			strings.ToUpper("Get"),
+			s.invExportBaseV2 + "/{format}/*",
+			s.doHWInvExportGet,
+		},
+		Route{
+			"doConsistencyGetV2",
+			strings.ToUpper("Get"),
+			s.invConsistBaseV2,
+			s.doConsistencyGet,
+		},
+		Route{
+			"doReconcilePostV2",
+			strings.ToUpper("Post"),
+			s.invReconcileBaseV2,
+			s.doReconcilePost,
+		},
+		Route{
+			"doSnapshotGetV2",
+			strings.ToUpper("Get"),
+			s.snapshotBaseV2,
+			s.doSnapshotGet,
+		},
+		Route{
+			"doSnapshotRestoreV2",
+			strings.ToUpper("Post"),
+			s.snapshotBaseV2 + "/Actions/Restore",
+			s.doSnapshotRestore,
+		},
+		Route{
+			"doVendorProfilesGetV2",
+			strings.ToUpper("Get"),
+			s.vendorProfBaseV2,
+			s.doVendorProfilesGet,
+		},
+		Route{
+			"doFallbackCredentialEPsGetV2",
+			strings.ToUpper("Get"),
+			s.fallbackCredBaseV2,
+			s.doFallbackCredentialEPsGet,
+		},
+
+		// Cooling faults
+		Route{
+			"doCoolingFaultsGetV2",
+			strings.ToUpper("Get"),
+			s.stateBaseV2 + "/CoolingFaults",
+			s.doCoolingFaultsGet,
+		},
+		Route{
+			"doCoolingFaultGetV2",
+			strings.ToUpper("Get"),
+			s.stateBaseV2 + "/CoolingFaults/{xname}",
+			s.doCoolingFaultGet,
+		},
+
+		// Hardware faults
+		Route{
+			"doHardwareFaultsGetV2",
+			strings.ToUpper("Get"),
+			s.stateBaseV2 + "/HardwareFaults",
+			s.doHardwareFaultsGet,
+		},
+		Route{
+			"doHardwareFaultGetV2",
+			strings.ToUpper("Get"),
+			s.stateBaseV2 + "/HardwareFaults/{xname}",
+			s.doHardwareFaultGet,
+		},
+
+		// Topology
+		Route{
+			"doTopologyGetV2",
+			strings.ToUpper("Get"),
+			s.stateBaseV2 + "/Topology/{xname}",
+			s.doTopologyGet,
+		},
+
+		// Xname geometry
+		Route{
+			"doXnamesExpandGetV2",
+			strings.ToUpper("Get"),
+			s.xnamesBaseV2 + "/Expand",
+			s.doXnamesExpandGet,
+		},
+		Route{
+			"doXnamesValidatePostV2",
+			strings.ToUpper("Post"),
+			s.xnamesBaseV2 + "/Validate",
+			s.doXnamesValidatePost,
+		},
+		Route{
+			"doXnameGetV2",
+			strings.ToUpper("Get"),
+			s.xnamesBaseV2 + "/{xname}",
+			s.doXnameGet,
+		},
+
+		// Telemetry
+		Route{
+			"doTelemetryMetricsGetV2",
+			strings.ToUpper("Get"),
+			s.telemetryBaseV2 + "/Metrics/{xname}",
+			s.doTelemetryMetricsGet,
+		},
+
+		// BMC certificates
+		Route{
+			"doCertGenerateCSRPostV2",
+			strings.ToUpper("Post"),
+			s.certBaseV2 + "/Actions/GenerateCSR",
+			s.doCertGenerateCSRPost,
+		},
+		Route{
+			"doCertReplacePostV2",
+			strings.ToUpper("Post"),
+			s.certBaseV2 + "/Actions/ReplaceCertificate",
+			s.doCertReplacePost,
+		},
+		Route{
+			"doCertStatusGetV2",
+			strings.ToUpper("Get"),
+			s.certBaseV2 + "/Status",
+			s.doCertStatusGet,
+		},
+		Route{
+			"doCertExpiryGetV2",
+			strings.ToUpper("Get"),
+			s.certBaseV2 + "/Expiry",
+			s.doCertExpiryGet,
+		},
+
+		// BMC credentials
+		Route{
+			"doCredRotatePostV2",
+			strings.ToUpper("Post"),
+			s.credBaseV2 + "/Actions/Rotate",
+			s.doCredRotatePost,
+		},
+		Route{
+			"doCredRotateStatusGetV2",
+			strings.ToUpper("Get"),
+			s.credBaseV2 + "/Status",
+			s.doCredRotateStatusGet,
+		},
+
+		// Firmware inventory
+		Route{
+			"doFirmwareInventoryGetV2",
+			strings.ToUpper("Get"),
+			s.firmwareBaseV2 + "/{xname}",
+			s.doFirmwareInventoryGet,
+		},
+		Route{
+			"doFirmwareInventoryGetAllV2",
+			strings.ToUpper("Get"),
+			s.firmwareBaseV2,
+			s.doFirmwareInventoryGetAll,
+		},
+
+		// Redfish logs
+		Route{
+			"doLogServicesGetV2",
+			strings.ToUpper("Get"),
+			s.logServiceBaseV2 + "/{xname}",
+			s.doLogServicesGet,
+		},
+		Route{
+			"doLogEntriesGetV2",
+			strings.ToUpper("Get"),
+			s.logServiceBaseV2 + "/{xname}/{logservice}/Entries",
+			s.doLogEntriesGet,
+		},
+
+		// Boot configuration
+		Route{
+			"doBootConfigGetV2",
+			strings.ToUpper("Get"),
+			s.bootConfigBaseV2 + "/{xname}",
+			s.doBootConfigGet,
+		},
+		Route{
+			"doBootConfigGetAllV2",
+			strings.ToUpper("Get"),
+			s.bootConfigBaseV2,
+			s.doBootConfigGetAll,
+		},
+
+		// Redfish event subscriptions
+		Route{
+			"doEventSubscriptionGetV2",
+			strings.ToUpper("Get"),
+			s.eventSubBaseV2 + "/{xname}",
+			s.doEventSubscriptionGet,
+		},
+		Route{
+			"doEventSubscriptionsGetV2",
+			strings.ToUpper("Get"),
+			s.eventSubBaseV2,
+			s.doEventSubscriptionsGet,
+		},
+		Route{
+			"doEventSubscriptionsActionPostV2",
+			strings.ToUpper("Post"),
+			s.eventSubBaseV2 + "/Actions/{operation}",
+			s.doEventSubscriptionsActionPost,
+		},
 
 		Route{
 			"doGetSCNSubscriptionV2",
@@ -726,6 +1270,24 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.subscriptionBaseV2 + "/SCN",
 			s.doDeleteSCNSubscriptionsAll,
 		},
+		Route{
+			"doSCNDeliveryGetV2",
+			strings.ToUpper("Get"),
+			s.subscriptionBaseV2 + "/SCN/Delivery",
+			s.doSCNDeliveryGet,
+		},
+		Route{
+			"doSCNDeadLettersGetV2",
+			strings.ToUpper("Get"),
+			s.subscriptionBaseV2 + "/SCN/Delivery/DeadLetters",
+			s.doSCNDeadLettersGet,
+		},
+		Route{
+			"doSCNReplayPostV2",
+			strings.ToUpper("Post"),
+			s.subscriptionBaseV2 + "/SCN/Delivery/Replay",
+			s.doSCNReplayPost,
+		},
 		Route{
 			"doGetSCNSubscriptionV2",
 			strings.ToUpper("Get"),
@@ -812,6 +1374,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.groupsBaseV2 + "/{group_label}/members/{xname_id}",
 			s.doGroupMemberDelete,
 		},
+		Route{
+			"doGroupHistoryGetV2",
+			strings.ToUpper("Get"),
+			s.groupsBaseV2 + "/{group_label}/history",
+			s.doGroupHistoryGet,
+		},
 
 		// Partitions
 		Route{
@@ -868,6 +1436,18 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.partitionsBaseV2 + "/{partition_name}/members/{xname_id}",
 			s.doPartitionMemberDelete,
 		},
+		Route{
+			"doPartitionsMovePostV2",
+			strings.ToUpper("Post"),
+			s.partitionsBaseV2 + "/move",
+			s.doPartitionsMovePost,
+		},
+		Route{
+			"doPartitionHistoryGetV2",
+			strings.ToUpper("Get"),
+			s.partitionsBaseV2 + "/{partition_name}/history",
+			s.doPartitionHistoryGet,
+		},
 
 		// Memberships
 		Route{
@@ -930,6 +1510,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.compLockBaseV2 + "/service/reservations/check",
 			s.doCompLocksServiceReservationCheck,
 		},
+		Route{
+			"doCompLocksServiceReservationExpiringGetV2",
+			strings.ToUpper("Get"),
+			s.compLockBaseV2 + "/service/reservations/expiring",
+			s.doCompLocksServiceReservationExpiringGet,
+		},
 
 		//Admin Locks
 		Route{
@@ -944,6 +1530,12 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.compLockBaseV2 + "/status",
 			s.doCompLocksStatusGet,
 		},
+		Route{
+			"doCompLocksAuditGetV2",
+			strings.ToUpper("Get"),
+			s.compLockBaseV2 + "/audit",
+			s.doCompLocksAuditGet,
+		},
 		Route{
 			"doCompLocksLockV2",
 			strings.ToUpper("Post"),
@@ -1006,6 +1598,18 @@ func (s *SmD) generateProtectedRoutes() Routes {
 			s.powerMapBaseV2,
 			s.doPowerMapsDeleteAll,
 		},
+		Route{
+			"doPowerMapPathGetV2",
+			strings.ToUpper("Get"),
+			s.powerMapBaseV2 + "/{xname}/Path",
+			s.doPowerMapPathGet,
+		},
+		Route{
+			"doPowerMapPoweredGetV2",
+			strings.ToUpper("Get"),
+			s.powerMapBaseV2 + "/{xname}/Powered",
+			s.doPowerMapPoweredGet,
+		},
 	}
 }
 
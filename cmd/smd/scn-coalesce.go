@@ -0,0 +1,203 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// SCN coalescing
+//
+// When a whole cabinet powers on, every node's change is its own SCN, and a
+// subscriber gets thousands of POSTs for what is one event.  With coalescing
+// on, SCNs for each subscriber URL are held for a short window, starting
+// with the first one held, and then sent as few payloads as possible: SCNs
+// with the same values (State, Flag, Enabled and so on) are merged into one
+// payload listing all of their components.
+//
+// Each component's changes are still sent in the order they were made.  A
+// component only joins an earlier payload if it isn't in any later one, so
+// e.g. On then Off for the same node is still two payloads, in that order.
+// A component that repeats the same change within the window is listed
+// once.
+//
+// If the held SCNs reach MaxComponents components before the window ends,
+// they are sent right away.  Held SCNs are not kept across restarts.
+//
+// Coalescing is off unless SMD_SCN_COALESCE_MS is set.
+///////////////////////////////////////////////////////////////////////////////
+
+// Policy controlling SCN coalescing.
+type SCNCoalescePolicy struct {
+	Window        time.Duration // How long SCNs are held, 0 for off
+	MaxComponents int           // Components held per URL before sending early
+}
+
+// Default policy, used for any values not overridden by env vars.
+var DefaultSCNCoalescePolicy = SCNCoalescePolicy{
+	Window:        0,
+	MaxComponents: 10000,
+}
+
+// Queues one SCN payload for delivery to a subscriber URL.
+type SCNQueueFunc func(url string, payload []byte)
+
+// SCNs with the same values, merged.
+type scnCoalesceBatch struct {
+	key   string
+	scn   sm.SCNPayload
+	comps map[string]bool
+}
+
+type scnCoalesceURL struct {
+	batches []*scnCoalesceBatch
+	last    map[string]int // compID -> index of the last batch it's in
+	held    int            // Components in all batches
+	timer   *time.Timer
+}
+
+type SCNCoalescer struct {
+	policy    SCNCoalescePolicy
+	queue     SCNQueueFunc
+	afterFunc func(time.Duration, func()) *time.Timer
+
+	lock sync.Mutex
+	urls map[string]*scnCoalesceURL
+}
+
+// Create a new SCN coalescer with the given policy, queueing the SCNs it
+// sends with queue.
+func NewSCNCoalescer(p SCNCoalescePolicy, queue SCNQueueFunc) *SCNCoalescer {
+	c := new(SCNCoalescer)
+	c.policy = p
+	c.queue = queue
+	c.afterFunc = time.AfterFunc
+	c.urls = make(map[string]*scnCoalesceURL)
+	return c
+}
+
+// Add an SCN for url.  payload is scn already encoded, which is queued as
+// is if coalescing is off.
+func (c *SCNCoalescer) Add(url string, scn sm.SCNPayload, payload []byte) {
+	if c.policy.Window <= 0 {
+		c.queue(url, payload)
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	cu, ok := c.urls[url]
+	if !ok {
+		cu = &scnCoalesceURL{last: make(map[string]int)}
+		c.urls[url] = cu
+	}
+	c.merge(cu, scn)
+	if c.policy.MaxComponents > 0 && cu.held >= c.policy.MaxComponents {
+		c.flushURL(url, cu)
+		return
+	}
+	if cu.timer == nil {
+		cu.timer = c.afterFunc(c.policy.Window, func() { c.Flush(url) })
+	}
+}
+
+// Merge scn into the held batches.  Should be called with lock held.
+func (c *SCNCoalescer) merge(cu *scnCoalesceURL, scn sm.SCNPayload) {
+	values := scn
+	values.Components = nil
+	raw, _ := json.Marshal(values)
+	key := string(raw)
+
+	// The last batch with the same values, which components can join if
+	// they aren't in any batch after it.
+	join := -1
+	for i := len(cu.batches) - 1; i >= 0; i-- {
+		if cu.batches[i].key == key {
+			join = i
+			break
+		}
+	}
+	var newBatch *scnCoalesceBatch
+	for _, id := range scn.Components {
+		if idx, ok := cu.last[id]; join >= 0 && (!ok || idx <= join) {
+			b := cu.batches[join]
+			if !b.comps[id] {
+				b.comps[id] = true
+				b.scn.Components = append(b.scn.Components, id)
+				cu.last[id] = join
+				cu.held++
+			}
+			continue
+		}
+		if newBatch == nil {
+			newBatch = &scnCoalesceBatch{key: key, scn: values,
+				comps: make(map[string]bool)}
+			cu.batches = append(cu.batches, newBatch)
+		}
+		if !newBatch.comps[id] {
+			newBatch.comps[id] = true
+			newBatch.scn.Components = append(newBatch.scn.Components, id)
+			cu.last[id] = len(cu.batches) - 1
+			cu.held++
+		}
+	}
+}
+
+// Send the SCNs held for url now.
+func (c *SCNCoalescer) Flush(url string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if cu, ok := c.urls[url]; ok {
+		c.flushURL(url, cu)
+	}
+}
+
+// Queue the held batches in order.  Should be called with lock held, which
+// also keeps flushes for the same URL in order.
+func (c *SCNCoalescer) flushURL(url string, cu *scnCoalesceURL) {
+	if cu.timer != nil {
+		cu.timer.Stop()
+	}
+	delete(c.urls, url)
+	for _, b := range cu.batches {
+		payload, err := json.Marshal(b.scn)
+		if err != nil {
+			continue
+		}
+		c.queue(url, payload)
+	}
+}
+
+// Queue an SCN, already encoded as payload, for delivery to url, through the
+// coalescer if there is one.
+func (s *SmD) sendSCN(url string, scn sm.SCNPayload, payload []byte) {
+	if s.scnCoalesce == nil {
+		s.scnDelivery.Queue(url, payload)
+		return
+	}
+	s.scnCoalesce.Add(url, scn, payload)
+}
@@ -0,0 +1,161 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+type testSCNQueue struct {
+	queued []sm.SCNPayload
+	urls   []string
+}
+
+func (tq *testSCNQueue) queue(url string, payload []byte) {
+	var scn sm.SCNPayload
+	json.Unmarshal(payload, &scn)
+	tq.queued = append(tq.queued, scn)
+	tq.urls = append(tq.urls, url)
+}
+
+// A coalescer whose window only ends when the returned func is called.
+func newTestSCNCoalescer(p SCNCoalescePolicy, tq *testSCNQueue) (*SCNCoalescer, func()) {
+	c := NewSCNCoalescer(p, tq.queue)
+	var fire func()
+	c.afterFunc = func(d time.Duration, f func()) *time.Timer {
+		fire = f
+		return time.NewTimer(time.Hour)
+	}
+	return c, func() {
+		if fire != nil {
+			f := fire
+			fire = nil
+			f()
+		}
+	}
+}
+
+func TestSCNCoalesceMerge(t *testing.T) {
+	tq := &testSCNQueue{}
+	c, fire := newTestSCNCoalescer(SCNCoalescePolicy{Window: time.Second,
+		MaxComponents: 100}, tq)
+	url := "http://pcs/scn"
+	on := func(ids ...string) sm.SCNPayload {
+		return sm.SCNPayload{Components: ids, State: "On"}
+	}
+	off := func(ids ...string) sm.SCNPayload {
+		return sm.SCNPayload{Components: ids, State: "Off"}
+	}
+	add := func(scn sm.SCNPayload) {
+		payload, _ := json.Marshal(scn)
+		c.Add(url, scn, payload)
+	}
+
+	// x1 and x2 go On then Off, x3 goes Off then On.  x1 repeats On.
+	add(on("x1"))
+	add(off("x3"))
+	add(on("x2", "x1"))
+	add(on("x3"))
+	add(off("x1", "x2"))
+	add(sm.SCNPayload{Components: []string{"x2"}, Flag: "Alert"})
+	if len(tq.queued) != 0 {
+		t.Fatalf("Expected nothing sent during the window, got %v", tq.queued)
+	}
+	fire()
+	// x1 and x2 going Off can join x3's earlier Off as neither has a
+	// later change in front of it.
+	expected := []sm.SCNPayload{
+		on("x1", "x2"),
+		off("x3", "x1", "x2"),
+		on("x3"),
+		{Components: []string{"x2"}, Flag: "Alert"},
+	}
+	if !reflect.DeepEqual(tq.queued, expected) {
+		t.Errorf("Expected %v, got %v", expected, tq.queued)
+	}
+	for _, u := range tq.urls {
+		if u != url {
+			t.Errorf("Expected URL %s, got %s", url, u)
+		}
+	}
+
+	// Each component's own changes are in order.
+	seen := map[string][]string{}
+	for _, scn := range tq.queued {
+		for _, id := range scn.Components {
+			seen[id] = append(seen[id], scn.State+scn.Flag)
+		}
+	}
+	expSeen := map[string][]string{
+		"x1": {"On", "Off"},
+		"x2": {"On", "Off", "Alert"},
+		"x3": {"Off", "On"},
+	}
+	if !reflect.DeepEqual(seen, expSeen) {
+		t.Errorf("Expected per-component order %v, got %v", expSeen, seen)
+	}
+
+	// A new window starts with the next SCN.
+	tq.queued = nil
+	add(on("x4"))
+	fire()
+	if !reflect.DeepEqual(tq.queued, []sm.SCNPayload{on("x4")}) {
+		t.Errorf("Expected the next window to send x4, got %v", tq.queued)
+	}
+}
+
+func TestSCNCoalesceMaxComponents(t *testing.T) {
+	tq := &testSCNQueue{}
+	c, fire := newTestSCNCoalescer(SCNCoalescePolicy{Window: time.Second,
+		MaxComponents: 3}, tq)
+	for _, id := range []string{"x1", "x2", "x3", "x4"} {
+		scn := sm.SCNPayload{Components: []string{id}, State: "Ready"}
+		payload, _ := json.Marshal(scn)
+		c.Add("http://a/scn", scn, payload)
+	}
+	if len(tq.queued) != 1 || len(tq.queued[0].Components) != 3 {
+		t.Fatalf("Expected 3 components sent early, got %v", tq.queued)
+	}
+	fire()
+	if len(tq.queued) != 2 ||
+		!reflect.DeepEqual(tq.queued[1].Components, []string{"x4"}) {
+		t.Errorf("Expected x4 sent at the end of the window, got %v", tq.queued)
+	}
+}
+
+func TestSCNCoalesceOff(t *testing.T) {
+	tq := &testSCNQueue{}
+	c := NewSCNCoalescer(SCNCoalescePolicy{}, tq.queue)
+	scn := sm.SCNPayload{Components: []string{"x1"}, State: "On"}
+	payload, _ := json.Marshal(scn)
+	c.Add("http://a/scn", scn, payload)
+	c.Add("http://a/scn", scn, payload)
+	if len(tq.queued) != 2 {
+		t.Errorf("Expected each SCN sent as is, got %v", tq.queued)
+	}
+}
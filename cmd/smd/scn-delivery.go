@@ -0,0 +1,413 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// SCN delivery
+//
+// Each subscriber URL has its own queue of SCNs, sent in order by one
+// goroutine at a time.  A failed POST is retried with backoff up to the
+// policy's Attempts; an SCN that still can't be delivered, or that doesn't
+// fit in a full queue, goes to the URL's dead-letter buffer.  When that is
+// full the oldest dead letter is dropped.
+//
+//     GET  /Subscriptions/SCN/Delivery              status per subscriber URL
+//     GET  /Subscriptions/SCN/Delivery/DeadLetters  undelivered SCNs
+//     POST /Subscriptions/SCN/Delivery/Replay       requeue dead letters
+//
+// Both GETs take ?url= to select subscribers, and Replay takes {"Url": ...}
+// or {} for all of them.  Replayed SCNs go ahead of anything queued, but are
+// sent after newer SCNs that were already delivered, so subscribers that
+// care about order should check the current state.
+//
+// Queues, status and dead letters are kept in memory by each SMD instance,
+// for the SCNs it sent.
+///////////////////////////////////////////////////////////////////////////////
+
+// Policy controlling SCN delivery.
+type SCNDeliveryPolicy struct {
+	Attempts      int           // POSTs of an SCN before it's a dead letter
+	Backoff       time.Duration // Wait after the first failure, doubled after each
+	MaxBackoff    time.Duration // Longest wait between attempts
+	QueueLen      int           // SCNs waiting per URL
+	DeadLetterLen int           // Dead letters kept per URL
+}
+
+// Default policy, used for any values not overridden by env vars.
+var DefaultSCNDeliveryPolicy = SCNDeliveryPolicy{
+	Attempts:      3,
+	Backoff:       5 * time.Second,
+	MaxBackoff:    time.Minute,
+	QueueLen:      1000,
+	DeadLetterLen: 1000,
+}
+
+// POSTs one SCN payload to a subscriber URL.
+type SCNSendFunc func(url string, payload []byte) error
+
+type scnDeliveryItem struct {
+	payload  []byte
+	queued   time.Time
+	attempts int
+	lastErr  string
+}
+
+type scnDeliveryURL struct {
+	queue       []*scnDeliveryItem
+	sending     bool
+	deadLetters []*scnDeliveryItem
+	status      sm.SCNDeliveryStatus
+}
+
+type SCNDelivery struct {
+	policy SCNDeliveryPolicy
+	send   SCNSendFunc
+	sleep  func(time.Duration)
+	now    func() time.Time
+
+	// Called with how long each delivered SCN took from being queued
+	delivered func(lag time.Duration)
+
+	lock sync.Mutex
+	urls map[string]*scnDeliveryURL
+}
+
+var errSCNDeliveryQueueFull = errors.New("delivery queue full")
+
+// Create a new SCN delivery tracker with the given policy.
+func NewSCNDelivery(p SCNDeliveryPolicy, send SCNSendFunc) *SCNDelivery {
+	d := new(SCNDelivery)
+	d.policy = p
+	d.send = send
+	d.sleep = time.Sleep
+	d.now = time.Now
+	d.urls = make(map[string]*scnDeliveryURL)
+	return d
+}
+
+// Should be called with lock held.
+func (d *SCNDelivery) getURL(url string) *scnDeliveryURL {
+	du, ok := d.urls[url]
+	if !ok {
+		du = &scnDeliveryURL{}
+		du.status.Url = url
+		d.urls[url] = du
+	}
+	return du
+}
+
+// Queue an SCN for delivery to url.
+func (d *SCNDelivery) Queue(url string, payload []byte) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	du := d.getURL(url)
+	item := &scnDeliveryItem{payload: payload, queued: d.now()}
+	if len(du.queue) >= d.policy.QueueLen {
+		item.lastErr = errSCNDeliveryQueueFull.Error()
+		d.deadLetter(du, item)
+		return
+	}
+	du.queue = append(du.queue, item)
+	d.startSending(url, du)
+}
+
+// Start a goroutine sending the queue for url, if there isn't one.  Should
+// be called with lock held.
+func (d *SCNDelivery) startSending(url string, du *scnDeliveryURL) {
+	if du.sending || len(du.queue) == 0 {
+		return
+	}
+	du.sending = true
+	go d.sendQueue(url, du)
+}
+
+// Send everything queued for url, then exit.
+func (d *SCNDelivery) sendQueue(url string, du *scnDeliveryURL) {
+	for {
+		d.lock.Lock()
+		if len(du.queue) == 0 {
+			du.sending = false
+			d.lock.Unlock()
+			return
+		}
+		item := du.queue[0]
+		du.queue = du.queue[1:]
+		d.lock.Unlock()
+
+		err := d.deliver(url, item)
+
+		d.lock.Lock()
+		if err == nil {
+			du.status.Delivered++
+			du.status.LastSuccess = d.now().UTC().Format(time.RFC3339)
+			if d.delivered != nil {
+				d.delivered(d.now().Sub(item.queued))
+			}
+		} else {
+			d.deadLetter(du, item)
+		}
+		d.lock.Unlock()
+	}
+}
+
+// Try to POST one SCN, with backoff between attempts.
+func (d *SCNDelivery) deliver(url string, item *scnDeliveryItem) error {
+	backoff := d.policy.Backoff
+	var err error
+	for try := 0; try < d.policy.Attempts; try++ {
+		if try > 0 {
+			d.sleep(backoff)
+			backoff *= 2
+			if backoff > d.policy.MaxBackoff {
+				backoff = d.policy.MaxBackoff
+			}
+		}
+		item.attempts++
+		err = d.send(url, item.payload)
+		if err == nil {
+			return nil
+		}
+		item.lastErr = err.Error()
+		d.lock.Lock()
+		d.urls[url].status.LastFailure = d.now().UTC().Format(time.RFC3339)
+		d.urls[url].status.LastError = item.lastErr
+		d.lock.Unlock()
+	}
+	return err
+}
+
+// Should be called with lock held.
+func (d *SCNDelivery) deadLetter(du *scnDeliveryURL, item *scnDeliveryItem) {
+	du.status.Failed++
+	if d.policy.DeadLetterLen <= 0 {
+		du.status.DeadLettersDropped++
+		return
+	}
+	if len(du.deadLetters) >= d.policy.DeadLetterLen {
+		du.deadLetters = du.deadLetters[1:]
+		du.status.DeadLettersDropped++
+	}
+	du.deadLetters = append(du.deadLetters, item)
+}
+
+// Requeue the dead letters for the given URLs (all if empty), ahead of
+// anything already queued.  Returns how many were requeued.
+func (d *SCNDelivery) Replay(urls []string) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if len(urls) == 0 {
+		for url := range d.urls {
+			urls = append(urls, url)
+		}
+	}
+	n := 0
+	for _, url := range urls {
+		du, ok := d.urls[url]
+		if !ok || len(du.deadLetters) == 0 {
+			continue
+		}
+		for _, item := range du.deadLetters {
+			item.attempts = 0
+		}
+		du.queue = append(du.deadLetters, du.queue...)
+		n += len(du.deadLetters)
+		du.deadLetters = nil
+		d.startSending(url, du)
+	}
+	return n
+}
+
+// Delivery status for the given URLs, or all that have been sent to.
+func (d *SCNDelivery) Status(urls []string) []sm.SCNDeliveryStatus {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if len(urls) == 0 {
+		for url := range d.urls {
+			urls = append(urls, url)
+		}
+	}
+	sort.Strings(urls)
+	out := []sm.SCNDeliveryStatus{}
+	for _, url := range urls {
+		st := sm.SCNDeliveryStatus{Url: url}
+		if du, ok := d.urls[url]; ok {
+			st = du.status
+			st.Queued = len(du.queue)
+			st.DeadLetters = len(du.deadLetters)
+		}
+		out = append(out, st)
+	}
+	return out
+}
+
+// Dead letters for the given URLs, or all of them, oldest first per URL.
+func (d *SCNDelivery) DeadLetters(urls []string) []sm.SCNDeadLetter {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if len(urls) == 0 {
+		for url := range d.urls {
+			urls = append(urls, url)
+		}
+	}
+	sort.Strings(urls)
+	out := []sm.SCNDeadLetter{}
+	for _, url := range urls {
+		du, ok := d.urls[url]
+		if !ok {
+			continue
+		}
+		for _, item := range du.deadLetters {
+			out = append(out, sm.SCNDeadLetter{
+				Url:       url,
+				Payload:   json.RawMessage(item.payload),
+				Queued:    item.queued.UTC().Format(time.RFC3339),
+				Attempts:  item.attempts,
+				LastError: item.lastErr,
+			})
+		}
+	}
+	return out
+}
+
+// POST an SCN to a subscriber.  Used as the SCNSendFunc.
+func (s *SmD) scnPost(url string, payload []byte) error {
+	req, err := retryablehttp.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	base.SetHTTPUserAgent(req.Request, serviceName)
+	req.Header.Add("Content-Type", "application/json")
+	rsp, err := s.GetHTTPClient().Do(req)
+	if err != nil {
+		base.DrainAndCloseResponseBody(rsp)
+		s.logWith(LogSubsysSCN).With("url", url).LogAlways(
+			"WARNING: SCN POST failed for %s: %v", url, err)
+		return err
+	}
+	var body []byte
+	if rsp.Body != nil {
+		body, _ = ioutil.ReadAll(rsp.Body)
+	}
+	base.DrainAndCloseResponseBody(rsp)
+	if rsp.StatusCode != http.StatusOK {
+		s.logWith(LogSubsysSCN).With("url", url).LogAlways(
+			"WARNING: An error occurred uploading SCN to %s: %s %s",
+			url, rsp.Status, string(body))
+		return fmt.Errorf("%s %s", rsp.Status, body)
+	}
+	return nil
+}
+
+// Subscriber URLs selected by the url query parameter, all if none given.
+func scnDeliveryURLs(r *http.Request) ([]string, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return r.Form["url"], nil
+}
+
+// Get the SCN delivery status of subscribers.  Subscribers with nothing sent
+// yet are included with zero counts.
+func (s *SmD) doSCNDeliveryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	urls, err := scnDeliveryURLs(r)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, "bad query param: "+err.Error())
+		return
+	}
+	subscribers := map[string][]string{}
+	s.scnSubLock.Lock()
+	for _, sub := range s.scnSubs.SubscriptionList {
+		subscribers[sub.Url] = append(subscribers[sub.Url], sub.Subscriber)
+	}
+	s.scnSubLock.Unlock()
+	if len(urls) == 0 {
+		for _, st := range s.scnDelivery.Status(nil) {
+			if _, ok := subscribers[st.Url]; !ok {
+				subscribers[st.Url] = nil
+			}
+		}
+		for url := range subscribers {
+			urls = append(urls, url)
+		}
+	}
+	out := sm.SCNDeliveryStatusArray{Subscribers: s.scnDelivery.Status(urls)}
+	for i := range out.Subscribers {
+		out.Subscribers[i].Subscribers = subscribers[out.Subscribers[i].Url]
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
+
+// Get the SCNs that could not be delivered.
+func (s *SmD) doSCNDeadLettersGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	urls, err := scnDeliveryURLs(r)
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, "bad query param: "+err.Error())
+		return
+	}
+	sendJsonObject(w, http.StatusOK,
+		sm.SCNDeadLetterArray{DeadLetters: s.scnDelivery.DeadLetters(urls)})
+}
+
+// Requeue dead letters, e.g. once a subscriber is back.
+func (s *SmD) doSCNReplayPost(w http.ResponseWriter, r *http.Request) {
+	var in sm.SCNReplayIn
+	body, err := ioutil.ReadAll(r.Body)
+	base.DrainAndCloseRequestBody(r)
+	if err == nil && len(body) != 0 {
+		err = json.Unmarshal(body, &in)
+	}
+	if err != nil {
+		s.reqLog(r).LogAlways("doSCNReplayPost(): Unmarshal body: %s", err)
+		sendJsonError(w, http.StatusBadRequest, "error decoding JSON "+err.Error())
+		return
+	}
+	urls := []string{}
+	if in.Url != "" {
+		urls = append(urls, in.Url)
+	}
+	out := sm.SCNReplayOut{Replayed: s.scnDelivery.Replay(urls)}
+	if out.Replayed != 0 {
+		s.reqLog(r).LogAlways("Replaying %d undelivered SCN(s)", out.Replayed)
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
@@ -0,0 +1,283 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+// Fake subscribers for SCNDelivery, which fail while down.
+type testSCNSubscribers struct {
+	lock     sync.Mutex
+	down     map[string]bool
+	received map[string][]string
+}
+
+func (ts *testSCNSubscribers) send(url string, payload []byte) error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if ts.down[url] {
+		return errors.New("503 Service Unavailable")
+	}
+	ts.received[url] = append(ts.received[url], string(payload))
+	return nil
+}
+
+func (ts *testSCNSubscribers) get(url string) []string {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return append([]string{}, ts.received[url]...)
+}
+
+func newTestSCNDelivery(p SCNDeliveryPolicy) (*SCNDelivery, *testSCNSubscribers, *[]time.Duration) {
+	ts := &testSCNSubscribers{
+		down:     map[string]bool{},
+		received: map[string][]string{},
+	}
+	d := NewSCNDelivery(p, ts.send)
+	sleeps := &[]time.Duration{}
+	d.sleep = func(t time.Duration) {
+		ts.lock.Lock()
+		*sleeps = append(*sleeps, t)
+		ts.lock.Unlock()
+	}
+	return d, ts, sleeps
+}
+
+// Wait for every queue to be sent.
+func waitSCNDelivery(t *testing.T, d *SCNDelivery) {
+	for i := 0; i < 500; i++ {
+		idle := true
+		d.lock.Lock()
+		for _, du := range d.urls {
+			if du.sending {
+				idle = false
+			}
+		}
+		d.lock.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for SCN delivery")
+}
+
+func TestSCNDelivery(t *testing.T) {
+	p := SCNDeliveryPolicy{
+		Attempts:      4,
+		Backoff:       time.Second,
+		MaxBackoff:    3 * time.Second,
+		QueueLen:      10,
+		DeadLetterLen: 2,
+	}
+	d, ts, sleeps := newTestSCNDelivery(p)
+	up := "http://up/scn"
+	down := "http://down/scn"
+	ts.down[down] = true
+
+	for _, msg := range []string{`{"a":1}`, `{"b":2}`, `{"c":3}`} {
+		d.Queue(up, []byte(msg))
+		d.Queue(down, []byte(msg))
+	}
+	waitSCNDelivery(t, d)
+
+	if got := ts.get(up); len(got) != 3 || got[0] != `{"a":1}` || got[2] != `{"c":3}` {
+		t.Errorf("Expected 3 SCNs in order, got %v", got)
+	}
+	// Backoff doubles up to the max, for each of the 3 undelivered SCNs.
+	if len(*sleeps) != 9 || (*sleeps)[0] != time.Second ||
+		(*sleeps)[1] != 2*time.Second || (*sleeps)[2] != 3*time.Second {
+		t.Errorf("Unexpected backoff %v", *sleeps)
+	}
+
+	st := d.Status([]string{down, up, "http://other/scn"})
+	if len(st) != 3 {
+		t.Fatalf("Expected 3 statuses, got %v", st)
+	}
+	if st[0].Url != down || st[0].Delivered != 0 || st[0].Failed != 3 ||
+		st[0].DeadLetters != 2 || st[0].DeadLettersDropped != 1 ||
+		st[0].LastError == "" || st[0].LastFailure == "" {
+		t.Errorf("Unexpected status for down subscriber: %+v", st[0])
+	}
+	if st[1].Url != "http://other/scn" || st[1].Delivered != 0 {
+		t.Errorf("Expected empty status for unknown URL, got %+v", st[1])
+	}
+	if st[2].Url != up || st[2].Delivered != 3 || st[2].Failed != 0 ||
+		st[2].LastSuccess == "" {
+		t.Errorf("Unexpected status for up subscriber: %+v", st[2])
+	}
+
+	// Only the last two are kept.
+	dls := d.DeadLetters(nil)
+	if len(dls) != 2 || string(dls[0].Payload) != `{"b":2}` ||
+		dls[0].Attempts != 4 || dls[0].Url != down {
+		t.Errorf("Unexpected dead letters %+v", dls)
+	}
+
+	// Replay once the subscriber is back.
+	ts.lock.Lock()
+	ts.down[down] = false
+	ts.lock.Unlock()
+	d.Queue(down, []byte(`{"d":4}`))
+	if n := d.Replay([]string{down}); n != 2 {
+		t.Errorf("Expected 2 replayed, got %v", n)
+	}
+	waitSCNDelivery(t, d)
+	if got := ts.get(down); len(got) != 3 || got[0] != `{"b":2}` ||
+		got[1] != `{"c":3}` || got[2] != `{"d":4}` {
+		t.Errorf("Expected replayed SCNs first, got %v", got)
+	}
+	if n := d.Replay(nil); n != 0 {
+		t.Errorf("Expected nothing left to replay, got %v", n)
+	}
+}
+
+func TestSCNDeliveryQueueFull(t *testing.T) {
+	p := DefaultSCNDeliveryPolicy
+	p.QueueLen = 1
+	d, ts, _ := newTestSCNDelivery(p)
+	url := "http://slow/scn"
+	// Hold the sender so the queue can fill.
+	ts.lock.Lock()
+	d.Queue(url, []byte(`{"a":1}`))
+	d.Queue(url, []byte(`{"b":2}`))
+	d.Queue(url, []byte(`{"c":3}`))
+	ts.lock.Unlock()
+	waitSCNDelivery(t, d)
+
+	dls := d.DeadLetters([]string{url})
+	if len(dls) == 0 || dls[len(dls)-1].LastError != errSCNDeliveryQueueFull.Error() ||
+		dls[len(dls)-1].Attempts != 0 {
+		t.Errorf("Expected a dead letter for the full queue, got %+v", dls)
+	}
+}
+
+func TestDoSCNDelivery(t *testing.T) {
+	oldDelivery := s.scnDelivery
+	oldSubs := s.scnSubs
+	defer func() {
+		s.scnDelivery = oldDelivery
+		s.scnSubs = oldSubs
+	}()
+	d, ts, _ := newTestSCNDelivery(DefaultSCNDeliveryPolicy)
+	s.scnDelivery = d
+	s.scnSubs = sm.SCNSubscriptionArray{SubscriptionList: []sm.SCNSubscription{
+		{ID: 1, Subscriber: "hmnfd@sms01", Url: "http://sms01/scn"},
+		{ID: 2, Subscriber: "cfs@sms01", Url: "http://sms02/scn"},
+	}}
+	d.policy.Attempts = 1
+	ts.down["http://sms01/scn"] = true
+	d.Queue("http://sms01/scn", []byte(`{"Components":["x0c0s0b0n0"],"State":"Ready"}`))
+	waitSCNDelivery(t, d)
+
+	tests := []struct {
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedResp string
+	}{{
+		method:       "GET",
+		path:         "/Subscriptions/SCN/Delivery?url=http://sms02/scn",
+		expectedCode: http.StatusOK,
+		expectedResp: `{"Subscribers":[{"Url":"http://sms02/scn","Subscribers":["cfs@sms01"],"Delivered":0,"Failed":0,"Queued":0,"DeadLetters":0,"DeadLettersDropped":0}]}` + "\n",
+	}, {
+		method:       "POST",
+		path:         "/Subscriptions/SCN/Delivery/Replay",
+		body:         `{"Url":"http://sms02/scn"}`,
+		expectedCode: http.StatusOK,
+		expectedResp: `{"Replayed":0}` + "\n",
+	}, {
+		method:       "POST",
+		path:         "/Subscriptions/SCN/Delivery/Replay",
+		body:         `{"Url":`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: "",
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest(test.method, "http://localhost/hsm/v2"+test.path,
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v",
+				i, test.expectedCode, w.Code)
+		}
+		if test.expectedResp != "" && w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+
+	// All subscribers, with the failure for sms01.
+	req, _ := http.NewRequest("GET", "http://localhost/hsm/v2/Subscriptions/SCN/Delivery", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var out sm.SCNDeliveryStatusArray
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if len(out.Subscribers) != 2 || out.Subscribers[0].Url != "http://sms01/scn" ||
+		out.Subscribers[0].Failed != 1 || out.Subscribers[0].DeadLetters != 1 ||
+		out.Subscribers[0].Subscribers[0] != "hmnfd@sms01" {
+		t.Errorf("Unexpected delivery status %+v", out.Subscribers)
+	}
+
+	req, _ = http.NewRequest("GET", "http://localhost/hsm/v2/Subscriptions/SCN/Delivery/DeadLetters?url=http://sms01/scn", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var dls sm.SCNDeadLetterArray
+	if err := json.Unmarshal(w.Body.Bytes(), &dls); err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if len(dls.DeadLetters) != 1 ||
+		string(dls.DeadLetters[0].Payload) != `{"Components":["x0c0s0b0n0"],"State":"Ready"}` {
+		t.Errorf("Unexpected dead letters %+v", dls.DeadLetters)
+	}
+
+	// Replay all once sms01 is back.
+	ts.lock.Lock()
+	ts.down["http://sms01/scn"] = false
+	ts.lock.Unlock()
+	req, _ = http.NewRequest("POST", "http://localhost/hsm/v2/Subscriptions/SCN/Delivery/Replay",
+		bytes.NewBufferString(""))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Body.String() != `{"Replayed":1}`+"\n" {
+		t.Errorf("Expected 1 replayed, got '%s'", w.Body.String())
+	}
+	waitSCNDelivery(t, d)
+	if got := ts.get("http://sms01/scn"); len(got) != 1 {
+		t.Errorf("Expected the replayed SCN to be delivered, got %v", got)
+	}
+}
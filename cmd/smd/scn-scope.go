@@ -0,0 +1,230 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// SCN subscription scopes
+//
+// A subscription can be limited to a set of xnames, groups and/or
+// partitions with its Xnames, Groups and Partitions lists.  These are not
+// triggers; they only narrow down which components a subscriber hears
+// about.  An xname matches itself and everything under it, so x1000c0 covers
+// every node in the chassis.  Group and partition members are looked up when
+// the SCN is sent, so membership changes apply right away.
+//
+// If a subscriber has several subscriptions for the same URL, it gets the
+// union of their scopes, and an unscoped subscription gets everything.
+///////////////////////////////////////////////////////////////////////////////
+
+// Check and normalize the scope of a subscription.
+func verifySCNScope(xnames, groups, partitions []string) error {
+	for i, xname := range xnames {
+		id := xnametypes.VerifyNormalizeCompID(xname)
+		if id == "" {
+			return errors.New("Invalid xname '" + xname + "'")
+		}
+		xnames[i] = id
+	}
+	for i, label := range groups {
+		groups[i] = sm.NormalizeGroupField(label)
+		if sm.VerifyGroupField(groups[i]) != nil {
+			return errors.New("Invalid group '" + label + "'")
+		}
+	}
+	for i, name := range partitions {
+		partitions[i] = sm.NormalizeGroupField(name)
+		if sm.VerifyGroupField(partitions[i]) != nil {
+			return errors.New("Invalid partition '" + name + "'")
+		}
+	}
+	return nil
+}
+
+// True if the subscription has any scope.
+func scnSubScoped(sub *sm.SCNSubscription) bool {
+	return len(sub.Xnames) != 0 || len(sub.Groups) != 0 ||
+		len(sub.Partitions) != 0
+}
+
+// True if the subscription is for the given (lower case) trigger.
+func scnSubHasTrigger(sub *sm.SCNSubscription, triggerType int, trigger string) bool {
+	var list []string
+	switch triggerType {
+	case SCNMAP_ENABLED:
+		return sub.Enabled != nil && *sub.Enabled
+	case SCNMAP_ROLE:
+		list = sub.Roles
+	case SCNMAP_SUBROLE:
+		list = sub.SubRoles
+	case SCNMAP_SWSTATUS:
+		list = sub.SoftwareStatus
+	case SCNMAP_STATE:
+		list = sub.States
+	case SCNMAP_RESERVED:
+		list = sub.Reservations
+	}
+	for _, t := range list {
+		if strings.ToLower(t) == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// True if id is the xname or is under it, e.g. x0c0s0b0n0 is under x0c0 but
+// x0c01 is not.
+func xnameInScope(id, xname string) bool {
+	if !strings.HasPrefix(id, xname) {
+		return false
+	}
+	if len(id) == len(xname) {
+		return true
+	}
+	c := id[len(xname)]
+	return c < '0' || c > '9'
+}
+
+// Get copies of the scoped subscriptions for the URL that are for the
+// trigger.  Returns nil if any of them is unscoped, i.e. the subscriber gets
+// every component.
+func (s *SmD) getSCNScopeSubs(url string, triggerType int, trigger string) []*sm.SCNSubscription {
+	s.scnSubLock.Lock()
+	defer s.scnSubLock.Unlock()
+	subs := []*sm.SCNSubscription{}
+	for i := range s.scnSubs.SubscriptionList {
+		sub := s.scnSubs.SubscriptionList[i]
+		if sub.Url != url || !scnSubHasTrigger(&sub, triggerType, trigger) {
+			continue
+		}
+		if !scnSubScoped(&sub) {
+			return nil
+		}
+		// The scope lists can be patched in place once unlocked.
+		sub.Xnames = append([]string{}, sub.Xnames...)
+		sub.Groups = append([]string{}, sub.Groups...)
+		sub.Partitions = append([]string{}, sub.Partitions...)
+		subs = append(subs, &sub)
+	}
+	if len(subs) == 0 {
+		// The subscription map and list are out of sync.  Don't drop the
+		// SCN because of it.
+		return nil
+	}
+	return subs
+}
+
+// Resolves the scopes of subscriptions while sending a single SCN, so each
+// group and partition is only looked up once.
+type scnScopeResolver struct {
+	s       *SmD
+	members map[string]map[string]bool
+}
+
+func newSCNScopeResolver(s *SmD) *scnScopeResolver {
+	return &scnScopeResolver{
+		s:       s,
+		members: make(map[string]map[string]bool),
+	}
+}
+
+// Get the members of a group or partition.  Unknown ones have no members.
+func (r *scnScopeResolver) getMembers(isPart bool, name string) map[string]bool {
+	key := "g:" + name
+	if isPart {
+		key = "p:" + name
+	}
+	if m, ok := r.members[key]; ok {
+		return m
+	}
+	m := make(map[string]bool)
+	var ids []string
+	if isPart {
+		part, err := r.s.db.GetPartition(name)
+		if err != nil {
+			r.s.logWith(LogSubsysSCN).LogAlways(
+				"warning: SCN scope: partition '%s': %s", name, err)
+		} else if part != nil {
+			ids = part.Members.IDs
+		}
+	} else {
+		group, err := r.s.db.GetGroup(name, "")
+		if err != nil {
+			r.s.logWith(LogSubsysSCN).LogAlways(
+				"warning: SCN scope: group '%s': %s", name, err)
+		} else if group != nil {
+			ids = group.Members.IDs
+		}
+	}
+	for _, id := range ids {
+		m[xnametypes.NormalizeHMSCompID(id)] = true
+	}
+	r.members[key] = m
+	return m
+}
+
+// True if the component is in the scope of the subscription.
+func (r *scnScopeResolver) inScope(sub *sm.SCNSubscription, id string) bool {
+	if !scnSubScoped(sub) {
+		return true
+	}
+	for _, xname := range sub.Xnames {
+		if xnameInScope(id, xname) {
+			return true
+		}
+	}
+	for _, label := range sub.Groups {
+		if r.getMembers(false, label)[id] {
+			return true
+		}
+	}
+	for _, name := range sub.Partitions {
+		if r.getMembers(true, name)[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter the components of a SCN down to the ones that any of the
+// subscriptions cover.
+func (r *scnScopeResolver) filter(subs []*sm.SCNSubscription, ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		nid := xnametypes.NormalizeHMSCompID(id)
+		for _, sub := range subs {
+			if r.inScope(sub, nid) {
+				out = append(out, id)
+				break
+			}
+		}
+	}
+	return out
+}
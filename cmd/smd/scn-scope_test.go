@@ -0,0 +1,150 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestXnameInScope(t *testing.T) {
+	tests := []struct {
+		id     string
+		xname  string
+		expect bool
+	}{
+		{"x0c0s0b0n0", "x0c0s0b0n0", true},
+		{"x0c0s0b0n0", "x0c0", true},
+		{"x0c0s0b0n0", "x0", true},
+		{"x0c01s0b0n0", "x0c0", false},
+		{"x10c0s0b0n0", "x1", false},
+		{"x0c0", "x0c0s0", false},
+	}
+	for i, test := range tests {
+		if got := xnameInScope(test.id, test.xname); got != test.expect {
+			t.Errorf("Test %v Failed: Expected %v for %s in %s, got %v",
+				i, test.expect, test.id, test.xname, got)
+		}
+	}
+}
+
+func TestSCNScopeFilter(t *testing.T) {
+	oldSubs := s.scnSubs
+	defer func() {
+		s.scnSubs = oldSubs
+		results.GetGroup.Return.group = nil
+	}()
+	results.GetGroup.Return.group = &sm.Group{
+		Label:   "compute",
+		Members: sm.Members{IDs: []string{"x1c0s0b0n0"}},
+	}
+	s.scnSubs = sm.SCNSubscriptionArray{SubscriptionList: []sm.SCNSubscription{
+		{ID: 1, Subscriber: "a@sms01", States: []string{"Ready"},
+			Xnames: []string{"x0c0s1"}, Url: "http://a/scn"},
+		{ID: 2, Subscriber: "a@sms01", States: []string{"Off"},
+			Groups: []string{"compute"}, Url: "http://a/scn"},
+		{ID: 3, Subscriber: "b@sms01", States: []string{"Ready"},
+			Url: "http://b/scn"},
+	}}
+	ids := []string{"x0c0s0b0n0", "x0c0s1b0n0", "x1c0s0b0n0"}
+
+	// Only the Ready subscription for a applies.
+	subs := s.getSCNScopeSubs("http://a/scn", SCNMAP_STATE, "ready")
+	if len(subs) != 1 || subs[0].ID != 1 {
+		t.Fatalf("Expected subscription 1, got %v", subs)
+	}
+	got := newSCNScopeResolver(s).filter(subs, ids)
+	if !reflect.DeepEqual(got, []string{"x0c0s1b0n0"}) {
+		t.Errorf("Expected only x0c0s1b0n0, got %v", got)
+	}
+
+	subs = s.getSCNScopeSubs("http://a/scn", SCNMAP_STATE, "off")
+	got = newSCNScopeResolver(s).filter(subs, ids)
+	if !reflect.DeepEqual(got, []string{"x1c0s0b0n0"}) {
+		t.Errorf("Expected only group member x1c0s0b0n0, got %v", got)
+	}
+
+	// Unscoped subscriptions get everything.
+	if subs := s.getSCNScopeSubs("http://b/scn", SCNMAP_STATE, "ready"); subs != nil {
+		t.Errorf("Expected no scope for b, got %v", subs)
+	}
+}
+
+func TestDoPostSCNSubscriptionScope(t *testing.T) {
+	oldSubs := s.scnSubs
+	oldSubMap := s.scnSubMap
+	defer func() {
+		s.scnSubs = oldSubs
+		s.scnSubMap = oldSubMap
+	}()
+	s.scnSubs = sm.SCNSubscriptionArray{}
+	s.scnSubMap = SCNSubMap{}
+	results.InsertSCNSubscription.Return.id = 4
+	results.InsertSCNSubscription.Return.err = nil
+
+	tests := []struct {
+		body         string
+		expectedCode int
+		expectedResp string
+	}{{
+		`{"Subscriber":"a@sms01","States":["Ready"],"Xnames":["X0C0S1"],"Groups":["Compute"],"Url":"http://a/scn"}`,
+		http.StatusOK,
+		`{"ID":4,"Subscriber":"a@sms01","States":["Ready"],"Xnames":["x0c0s1"],"Groups":["compute"],"Url":"http://a/scn"}` + "\n",
+	}, {
+		`{"Subscriber":"a@sms01","States":["Ready"],"Xnames":["foo"],"Url":"http://a/scn"}`,
+		http.StatusBadRequest,
+		"",
+	}, {
+		`{"Subscriber":"a@sms01","States":["Ready"],"Partitions":["p 1"],"Url":"http://a/scn"}`,
+		http.StatusBadRequest,
+		"",
+	}, {
+		// A scope is not a trigger.
+		`{"Subscriber":"a@sms01","Xnames":["x0c0"],"Url":"http://a/scn"}`,
+		http.StatusBadRequest,
+		"",
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("POST", "http://localhost/hsm/v2/Subscriptions/SCN",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v",
+				i, test.expectedCode, w.Code)
+		}
+		if test.expectedResp != "" && w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+	if sub := results.InsertSCNSubscription.Input.sub; len(sub.Xnames) != 1 ||
+		sub.Xnames[0] != "x0c0s1" {
+		t.Errorf("Expected the normalized scope to be stored, got %+v", sub)
+	}
+}
@@ -0,0 +1,310 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// SCN storm detection
+//
+// A component is "flapping" when it changes back to a state it already held
+// a short time ago, e.g. On -> Off -> On.  When enough flaps are seen under
+// the same controller (i.e. Redfish endpoint) within the policy window, we
+// schedule a rediscovery of that endpoint to verify the real state.  Until
+// the verification window ends, SCNs for components under that controller
+// are held back.  When it ends, a single SCN is sent for each component
+// whose final state differs from the last one its subscribers were sent.
+///////////////////////////////////////////////////////////////////////////////
+
+// Policy controlling SCN storm detection.
+type SCNStormPolicy struct {
+	Enabled      bool          // Detection is off unless set
+	Window       time.Duration // How far back to look when counting flaps
+	Threshold    int           // Flaps within Window under one controller
+	VerifyWindow time.Duration // How long to hold back SCNs after a storm
+	Rediscover   bool          // Rediscover the affected endpoint(s)
+}
+
+// Default policy, used for any values not overridden by env vars.
+var DefaultSCNStormPolicy = SCNStormPolicy{
+	Enabled:      false,
+	Window:       60 * time.Second,
+	Threshold:    5,
+	VerifyWindow: 5 * time.Minute,
+	Rediscover:   true,
+}
+
+// Called when a storm is detected.  rootID is the controller xname the
+// flapping components were grouped under.
+type SCNStormTriggerFunc func(rootID string, compIDs []string)
+
+// Called at the end of a verification window to send the SCNs that were
+// held back, one call per state.
+type SCNStormSendFunc func(compIDs []string, state string)
+
+type scnStormTransition struct {
+	state string
+	when  time.Time
+}
+
+type scnStormSubtree struct {
+	flaps       []time.Time
+	flapComps   map[string]bool
+	verifyUntil time.Time
+	lastSent    map[string]string // compID -> last state sent in an SCN
+	pending     map[string]string // compID -> latest state held back
+}
+
+type SCNStormDetector struct {
+	policy    SCNStormPolicy
+	onTrigger SCNStormTriggerFunc
+	send      SCNStormSendFunc
+	now       func() time.Time
+	afterFunc func(time.Duration, func()) *time.Timer
+
+	lock      sync.Mutex
+	history   map[string][]scnStormTransition
+	subtrees  map[string]*scnStormSubtree
+	lastSweep time.Time
+}
+
+// Create a new detector with the given policy.  Either callback may be nil.
+func NewSCNStormDetector(p SCNStormPolicy, onTrigger SCNStormTriggerFunc, send SCNStormSendFunc) *SCNStormDetector {
+	d := new(SCNStormDetector)
+	d.policy = p
+	d.onTrigger = onTrigger
+	d.send = send
+	d.now = time.Now
+	d.afterFunc = time.AfterFunc
+	d.history = make(map[string][]scnStormTransition)
+	d.subtrees = make(map[string]*scnStormSubtree)
+	return d
+}
+
+// Return the xname of the controller (BMC) above id, which is what gets
+// rediscovered.  If there isn't one, id itself is used.
+func scnStormRoot(id string) string {
+	for p := id; xnametypes.GetHMSType(p) != xnametypes.HMSTypeInvalid; p = xnametypes.GetHMSCompParent(p) {
+		if xnametypes.IsHMSTypeController(xnametypes.GetHMSType(p)) {
+			return p
+		}
+	}
+	return id
+}
+
+// Returns true if a storm was detected for the controller rootID and the
+// verification window has not yet ended.
+func (d *SCNStormDetector) IsVerifying(rootID string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	st, ok := d.subtrees[rootID]
+	return ok && d.now().Before(st.verifyUntil)
+}
+
+// Record a state change to 'state' for each of the components in ids, and
+// return the subset of ids that should get an SCN now.  If the policy is
+// disabled, ids is returned unchanged.
+func (d *SCNStormDetector) Filter(ids []string, state string) []string {
+	if d == nil || !d.policy.Enabled || state == "" {
+		return ids
+	}
+	type trigger struct {
+		root  string
+		comps []string
+	}
+	var triggers []trigger
+	now := d.now()
+	cutoff := now.Add(-d.policy.Window)
+	stateLower := strings.ToLower(state)
+
+	d.lock.Lock()
+	d.sweep(now, cutoff)
+	sendIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		root := scnStormRoot(id)
+		st, ok := d.subtrees[root]
+		if !ok {
+			st = &scnStormSubtree{
+				flapComps: make(map[string]bool),
+				lastSent:  make(map[string]string),
+			}
+			d.subtrees[root] = st
+		}
+		// Drop history older than the window, and see whether we've been
+		// in this state recently.
+		hist := d.history[id]
+		flap := false
+		kept := hist[:0]
+		for _, t := range hist {
+			if t.when.Before(cutoff) {
+				continue
+			}
+			if t.state == stateLower {
+				flap = true
+			}
+			kept = append(kept, t)
+		}
+		d.history[id] = append(kept, scnStormTransition{stateLower, now})
+
+		if now.Before(st.verifyUntil) {
+			st.pending[id] = state
+			continue
+		}
+		if flap {
+			flaps := st.flaps[:0]
+			for _, t := range st.flaps {
+				if !t.Before(cutoff) {
+					flaps = append(flaps, t)
+				}
+			}
+			st.flaps = append(flaps, now)
+			st.flapComps[id] = true
+			if len(st.flaps) >= d.policy.Threshold {
+				st.verifyUntil = now.Add(d.policy.VerifyWindow)
+				st.pending = make(map[string]string)
+				comps := make([]string, 0, len(st.flapComps))
+				for c := range st.flapComps {
+					comps = append(comps, c)
+				}
+				sort.Strings(comps)
+				st.flaps = nil
+				st.flapComps = make(map[string]bool)
+				triggers = append(triggers, trigger{root, comps})
+			}
+		}
+		st.lastSent[id] = state
+		sendIDs = append(sendIDs, id)
+	}
+	d.lock.Unlock()
+
+	for _, t := range triggers {
+		root := t.root
+		d.afterFunc(d.policy.VerifyWindow, func() { d.EndVerify(root) })
+		if d.onTrigger != nil {
+			go d.onTrigger(t.root, t.comps)
+		}
+	}
+	return sendIDs
+}
+
+// End the verification window for the controller rootID, sending an SCN for
+// each held-back component whose latest state differs from the last one
+// sent.  Normally called from a timer when the window expires.
+func (d *SCNStormDetector) EndVerify(rootID string) {
+	d.lock.Lock()
+	st, ok := d.subtrees[rootID]
+	if !ok || st.pending == nil {
+		d.lock.Unlock()
+		return
+	}
+	byState := make(map[string][]string)
+	for id, state := range st.pending {
+		if strings.EqualFold(st.lastSent[id], state) {
+			continue
+		}
+		byState[state] = append(byState[state], id)
+		st.lastSent[id] = state
+	}
+	st.pending = nil
+	st.verifyUntil = time.Time{}
+	d.lock.Unlock()
+
+	if d.send == nil {
+		return
+	}
+	for state, ids := range byState {
+		sort.Strings(ids)
+		d.send(ids, state)
+	}
+}
+
+// Periodically drop tracking info for components and subtrees that have
+// gone quiet so these maps don't grow without bound.  Must hold lock.
+func (d *SCNStormDetector) sweep(now, cutoff time.Time) {
+	if now.Sub(d.lastSweep) < d.policy.Window {
+		return
+	}
+	d.lastSweep = now
+	for id, hist := range d.history {
+		if len(hist) == 0 || hist[len(hist)-1].when.Before(cutoff) {
+			delete(d.history, id)
+		}
+	}
+	for root, st := range d.subtrees {
+		if st.pending != nil {
+			continue
+		}
+		if len(st.flaps) == 0 || st.flaps[len(st.flaps)-1].Before(cutoff) {
+			delete(d.subtrees, root)
+		}
+	}
+}
+
+// Rediscover the endpoint(s) behind a detected SCN storm so we can verify
+// the actual state of the flapping components.
+func (s *SmD) scnStormRediscover(rootID string, compIDs []string) {
+	s.logWith(LogSubsysSCN).With("xname", rootID).LogAlways(
+		"SCN storm detected under %s for %v, holding SCNs for %s",
+		rootID, compIDs, s.scnStormPolicy.VerifyWindow)
+	if !s.scnStormPolicy.Rediscover || s.disableDiscovery {
+		return
+	}
+	epIDs := make(map[string]bool)
+	if ep, err := s.db.GetRFEndpointByID(rootID); err == nil && ep != nil {
+		epIDs[ep.ID] = true
+	} else {
+		for _, id := range compIDs {
+			cep, err := s.db.GetCompEndpointByID(id)
+			if err != nil || cep == nil {
+				continue
+			}
+			epIDs[cep.RfEndpointID] = true
+		}
+	}
+	for epID := range epIDs {
+		ep, err := s.db.GetRFEndpointByID(epID)
+		if err != nil || ep == nil {
+			s.logWith(LogSubsysSCN).With("endpoint_id", epID).LogAlways(
+				"scnStormRediscover: can't look up %s: %v", epID, err)
+			continue
+		}
+		s.logWith(LogSubsysSCN).With("endpoint_id", epID).LogAlways(
+			"scnStormRediscover: rediscovering %s", epID)
+		go s.discoverFromEndpoint(ep, 0, false)
+	}
+}
+
+// Send the SCNs held back during an SCN storm verification window.
+func (s *SmD) scnStormSend(compIDs []string, state string) {
+	var data base.Component
+	data.State = state
+	s.wp.Queue(NewJobSCN(compIDs, data, s))
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSCNStormRoot(t *testing.T) {
+	tests := []struct {
+		id   string
+		root string
+	}{
+		{"x0c0s0b0n0", "x0c0s0b0"},
+		{"x0c0s0b0n0p1", "x0c0s0b0"},
+		{"x0c0s0b0", "x0c0s0b0"},
+		{"x0c0r1b0", "x0c0r1b0"},
+		{"x0c0s0", "x0c0s0"},
+	}
+	for i, test := range tests {
+		if root := scnStormRoot(test.id); root != test.root {
+			t.Errorf("Testcase %d (%s): FAIL: Expected %s, but got %s",
+				i, test.id, test.root, root)
+		}
+	}
+}
+
+func TestSCNStormDetector(t *testing.T) {
+	var lock sync.Mutex
+	triggered := make(chan []string, 1)
+	sent := make(map[string][]string)
+	p := SCNStormPolicy{
+		Enabled:      true,
+		Window:       time.Minute,
+		Threshold:    2,
+		VerifyWindow: time.Hour,
+		Rediscover:   true,
+	}
+	d := NewSCNStormDetector(p,
+		func(root string, comps []string) {
+			triggered <- append([]string{root}, comps...)
+		},
+		func(ids []string, state string) {
+			lock.Lock()
+			sent[state] = ids
+			lock.Unlock()
+		})
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	// The window is ended by hand below.
+	d.afterFunc = func(time.Duration, func()) *time.Timer { return nil }
+
+	node := []string{"x0c0s0b0n0"}
+	states := []string{"On", "Off", "On", "Off"}
+	for i, state := range states {
+		now = now.Add(time.Second)
+		if ids := d.Filter(node, state); !reflect.DeepEqual(ids, node) {
+			t.Errorf("Testcase %d: FAIL: Expected SCN for %v, got %v", i, node, ids)
+		}
+	}
+	select {
+	case got := <-triggered:
+		want := []string{"x0c0s0b0", "x0c0s0b0n0"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FAIL: Expected trigger %v, got %v", want, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("FAIL: storm was not detected")
+	}
+	if !d.IsVerifying("x0c0s0b0") {
+		t.Errorf("FAIL: Expected x0c0s0b0 to be verifying")
+	}
+
+	// SCNs under the same BMC are now held back, others are not.
+	now = now.Add(time.Second)
+	if ids := d.Filter([]string{"x0c0s0b0n0", "x0c0s0b0n1"}, "On"); len(ids) != 0 {
+		t.Errorf("FAIL: Expected no SCNs while verifying, got %v", ids)
+	}
+	if ids := d.Filter([]string{"x0c0s1b0n0"}, "On"); len(ids) != 1 {
+		t.Errorf("FAIL: Expected SCN for other BMC, got %v", ids)
+	}
+	now = now.Add(time.Second)
+	d.Filter(node, "Off")
+
+	// n0 ended on Off, which was the last state sent, so only n1 goes out.
+	d.EndVerify("x0c0s0b0")
+	lock.Lock()
+	defer lock.Unlock()
+	want := map[string][]string{"On": {"x0c0s0b0n1"}}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("FAIL: Expected held SCNs %v, got %v", want, sent)
+	}
+	if d.IsVerifying("x0c0s0b0") {
+		t.Errorf("FAIL: Expected x0c0s0b0 verification to be over")
+	}
+}
+
+func TestSCNStormDetectorDisabled(t *testing.T) {
+	d := NewSCNStormDetector(DefaultSCNStormPolicy, nil, nil)
+	ids := []string{"x0c0s0b0n0"}
+	for i := 0; i < 20; i++ {
+		if got := d.Filter(ids, []string{"On", "Off"}[i%2]); !reflect.DeepEqual(got, ids) {
+			t.Fatalf("FAIL: Expected %v, got %v", ids, got)
+		}
+	}
+	var nilDetector *SCNStormDetector
+	if got := nilDetector.Filter(ids, "On"); !reflect.DeepEqual(got, ids) {
+		t.Errorf("FAIL: Expected %v, got %v", ids, got)
+	}
+}
@@ -0,0 +1,449 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Startup self-test
+//
+// With SMD_SELF_TEST set, HSM discovers a synthetic NodeBMC (see
+// rf.NewSelfTestRedfishEp()) right after startup, stores it with the same
+// code and the same transaction as real discovery, reads everything back
+// and then deletes it again.  /service/ready reports not ready until this
+// has passed, so a broken schema, driver or discovery change shows up
+// before any real endpoint is touched.
+//
+// Nothing else is told about the self-test endpoint: no SCNs, no Vault
+// credentials, no telemetry subscriptions.  It lives at an otherwise
+// unused slot in cabinet x9999 (or SMD_SELF_TEST_XNAME) and its FQDN is in
+// the .invalid domain so it is easy to recognize if a crash leaves it
+// behind.  Leftovers are cleaned up by the next self-test.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	SelfTestRunning = "Running"
+	SelfTestPassed  = "Passed"
+	SelfTestFailed  = "Failed"
+	SelfTestSkipped = "Skipped"
+)
+
+// Slots tried when no xname is configured.  A self-test endpoint that
+// hasn't been touched for selfTestStaleAge is assumed to be left over from
+// an instance that died mid-test.
+const (
+	selfTestXnameFmt = "x9999c7s%db0"
+	selfTestSlots    = 8
+	selfTestStaleAge = 10 * time.Minute
+)
+
+var ErrSelfTestInUse = errors.New("xname is in use")
+
+type SelfTestState struct {
+	lock   sync.Mutex
+	status string
+	err    string
+}
+
+func (st *SelfTestState) set(status, err string) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.status = status
+	st.err = err
+}
+
+// Get the self-test status and, if it failed, the reason.  The status is
+// empty if no self-test was configured.
+func (st *SelfTestState) Get() (string, string) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	return st.status, st.err
+}
+
+// What one part of the self-test endpoint looks like in the DB, reduced
+// to the fields that must survive the round trip.
+type selfTestFingerprints struct {
+	compEPs    []string
+	serviceEPs []string
+	comps      []string
+	ceis       []string
+	hwlocs     []string
+	fruLocs    []string
+}
+
+// Mark the self-test as running.  Called before the HTTP server starts
+// so /service/ready can't report ready in between.
+func (s *SmD) SelfTestInit() {
+	if ro := s.readOnly.Get(); ro.ReadOnly {
+		s.LogAlways("Self-test skipped: HSM is read-only")
+		s.selfTest.set(SelfTestSkipped, "")
+		return
+	}
+	s.selfTest.set(SelfTestRunning, "")
+}
+
+// Run the self-test and record the result.  Intended to run in its own
+// goroutine after SelfTestInit().
+func (s *SmD) SelfTestRun() {
+	if status, _ := s.selfTest.Get(); status != SelfTestRunning {
+		return
+	}
+	start := time.Now()
+	id, err := s.selfTestDo()
+	if err != nil {
+		s.LogAlways("Self-test FAILED (%s): %s", id, err)
+		s.selfTest.set(SelfTestFailed, err.Error())
+		return
+	}
+	s.LogAlways("Self-test passed (%s) in %s", id, time.Since(start))
+	s.selfTest.set(SelfTestPassed, "")
+}
+
+// Returns the xname used, for logging.
+func (s *SmD) selfTestDo() (string, error) {
+	id, err := s.selfTestClaim(s.selfTestID)
+	if err != nil {
+		return id, err
+	}
+	defer func() {
+		if err := s.selfTestCleanup(id); err != nil {
+			s.LogAlways("Self-test: cleanup of %s failed: %s", id, err)
+		}
+	}()
+
+	rfEP, err := rf.NewSelfTestRedfishEp(id)
+	if err != nil {
+		return id, err
+	}
+	rfEP.GetRootInfo()
+	if rfEP.DiscInfo.LastStatus != rf.DiscoverOK {
+		return id, fmt.Errorf("discovery status %s", rfEP.DiscInfo.LastStatus)
+	}
+	if len(rfEP.DiscInfo.Errors) > 0 {
+		e := rfEP.DiscInfo.Errors[0]
+		return id, fmt.Errorf("discovery of %s failed: %s", e.URI, e.Error)
+	}
+	want, err := s.selfTestStore(rfEP)
+	if err != nil {
+		return id, fmt.Errorf("store failed: %s", err)
+	}
+	return id, s.selfTestVerify(id, rfEP.FQDN, want)
+}
+
+// Insert the self-test RedfishEndpoint, which reserves its xname.  With a
+// configured xname, that one must be free (or a stale self-test leftover);
+// otherwise the first free slot is used.
+func (s *SmD) selfTestClaim(id string) (string, error) {
+	ids := []string{id}
+	if id == "" {
+		ids = make([]string, 0, selfTestSlots)
+		for _, slot := range rand.Perm(selfTestSlots) {
+			ids = append(ids, fmt.Sprintf(selfTestXnameFmt, slot))
+		}
+	}
+	var err error
+	for _, id = range ids {
+		if err = s.selfTestInsert(id); err != ErrSelfTestInUse {
+			return id, err
+		}
+	}
+	if len(ids) > 1 {
+		return "", errors.New("no free slot for the self-test endpoint")
+	}
+	return id, err
+}
+
+func (s *SmD) selfTestInsert(id string) error {
+	ep := sm.NewRedfishEndpoint(&rf.RedfishEPDescription{
+		ID:       id,
+		Type:     "NodeBMC",
+		Hostname: id,
+		Domain:   rf.SelfTestDomain,
+		FQDN:     id + "." + rf.SelfTestDomain,
+		Enabled:  true,
+	})
+	ep.DiscInfo.LastStatus = rf.DiscoveryStarted
+	ep.DiscInfo.TSNow()
+
+	err := s.db.InsertRFEndpoint(ep)
+	if err != hmsds.ErrHMSDSDuplicateKey {
+		return err
+	}
+	old, err := s.db.GetRFEndpointByID(id)
+	if err != nil {
+		return err
+	} else if old == nil {
+		// Deleted in the meantime, try the next one.
+		return ErrSelfTestInUse
+	}
+	if !strings.HasSuffix(old.FQDN, "."+rf.SelfTestDomain) {
+		return ErrSelfTestInUse
+	}
+	last, err := time.Parse("2006-01-02T15:04:05.000000Z07:00",
+		old.DiscInfo.LastAttempt)
+	if err == nil && time.Since(last) < selfTestStaleAge {
+		// Another instance is running its self-test right now.
+		return ErrSelfTestInUse
+	}
+	s.LogAlways("Self-test: removing leftover self-test endpoint %s", id)
+	if err := s.selfTestCleanup(id); err != nil {
+		return err
+	}
+	err = s.db.InsertRFEndpoint(ep)
+	if err == hmsds.ErrHMSDSDuplicateKey {
+		return ErrSelfTestInUse
+	}
+	return err
+}
+
+// Store the discovered endpoint the way updateFromRfEndpoint() does, minus
+// everything that would tell the rest of the system about it.
+func (s *SmD) selfTestStore(rfEP *rf.RedfishEP) (*selfTestFingerprints, error) {
+	ep := sm.NewRedfishEndpoint(&rfEP.RedfishEPDescription)
+	want := new(selfTestFingerprints)
+	parts := rfEP.Parts()
+	next := func() (*hmsds.RFEndpointBatch, error) {
+		if len(parts) == 0 {
+			return nil, nil
+		}
+		b, err := s.discoverRFEndpointBatch(ep, parts[0])
+		parts = parts[1:]
+		if err != nil {
+			return nil, err
+		}
+		want.add(b)
+		return b, nil
+	}
+	if _, err := s.db.UpdateAllForRFEndpointBatches(ep, next); err != nil {
+		return nil, err
+	}
+	want.sort()
+	if len(want.comps) == 0 || len(want.hwlocs) == 0 {
+		return nil, fmt.Errorf("no components discovered")
+	}
+	return want, nil
+}
+
+// Read the self-test endpoint back and compare it with what was written.
+func (s *SmD) selfTestVerify(id, fqdn string, want *selfTestFingerprints) error {
+	ep, err := s.db.GetRFEndpointByID(id)
+	if err != nil {
+		return err
+	} else if ep == nil {
+		return fmt.Errorf("RedfishEndpoint %s not found", id)
+	} else if ep.FQDN != fqdn || ep.DiscInfo.LastStatus != rf.DiscoverOK {
+		return fmt.Errorf("RedfishEndpoint %s read back as %s/%s",
+			id, ep.FQDN, ep.DiscInfo.LastStatus)
+	}
+
+	got := new(selfTestFingerprints)
+	b := new(hmsds.RFEndpointBatch)
+	ceps, err := s.db.GetCompEndpointsFilter(
+		&hmsds.CompEPFilter{RfEndpointID: []string{id}})
+	if err != nil {
+		return err
+	}
+	b.CompEndpoints = &sm.ComponentEndpointArray{ComponentEndpoints: ceps}
+	seps, err := s.db.GetServiceEndpointsFilter(
+		&hmsds.ServiceEPFilter{RfEndpointID: []string{id}})
+	if err != nil {
+		return err
+	}
+	b.ServiceEndpoints = &sm.ServiceEndpointArray{ServiceEndpoints: seps}
+
+	ids := make([]string, 0, len(ceps))
+	for _, cep := range ceps {
+		ids = append(ids, cep.ID)
+	}
+	if len(ids) > 0 {
+		comps, err := s.db.GetComponentsFilter(
+			&hmsds.ComponentFilter{ID: ids}, hmsds.FLTR_DEFAULT)
+		if err != nil {
+			return err
+		}
+		b.Components = &base.ComponentArray{Components: comps}
+		b.CompEthInterfaces, err = s.db.GetCompEthInterfaceFilter(
+			hmsds.CEI_CompIDs(ids))
+		if err != nil {
+			return err
+		}
+		b.HWInvByLocs, err = s.db.GetHWInvByLocFilter(
+			hmsds.HWInvLoc_IDs(ids), hmsds.HWInvLoc_Child)
+		if err != nil {
+			return err
+		}
+	}
+	got.add(b)
+	got.sort()
+	for _, c := range []struct {
+		what      string
+		want, got []string
+	}{
+		{"ComponentEndpoints", want.compEPs, got.compEPs},
+		{"ServiceEndpoints", want.serviceEPs, got.serviceEPs},
+		{"Components", want.comps, got.comps},
+		{"EthernetInterfaces", want.ceis, got.ceis},
+		{"HWInventory", want.hwlocs, got.hwlocs},
+	} {
+		if err := selfTestCompare(c.want, c.got); err != nil {
+			return fmt.Errorf("%s: %s", c.what, err)
+		}
+	}
+
+	if len(want.fruLocs) > 0 {
+		hists, err := s.db.GetHWInvHistFilter(hmsds.HWInvHist_IDs(want.fruLocs))
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool)
+		for _, h := range hists {
+			seen[h.ID] = true
+		}
+		for _, loc := range want.fruLocs {
+			if !seen[loc] {
+				return fmt.Errorf("HWInventory history: none for %s", loc)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete everything the self-test (or an earlier, interrupted one) wrote
+// for the endpoint.  Goes by what is in the DB rather than what was
+// written, so it also works for leftovers.
+func (s *SmD) selfTestCleanup(id string) error {
+	ceps, err := s.db.GetCompEndpointsFilter(
+		&hmsds.CompEPFilter{RfEndpointID: []string{id}})
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(ceps))
+	for _, cep := range ceps {
+		ids = append(ids, cep.ID)
+	}
+	if len(ids) > 0 {
+		ceis, err := s.db.GetCompEthInterfaceFilter(hmsds.CEI_CompIDs(ids))
+		if err != nil {
+			return err
+		}
+		for _, cei := range ceis {
+			if _, err := s.db.DeleteCompEthInterfaceByID(cei.ID); err != nil {
+				return err
+			}
+		}
+		hwlocs, err := s.db.GetHWInvByLocFilter(
+			hmsds.HWInvLoc_IDs(ids), hmsds.HWInvLoc_Child)
+		if err != nil {
+			return err
+		}
+		for _, hwloc := range hwlocs {
+			if _, err := s.db.DeleteHWInvByLocID(hwloc.ID); err != nil {
+				return err
+			}
+			if _, err := s.db.DeleteHWInvHistByLocID(hwloc.ID); err != nil {
+				return err
+			}
+			if hwloc.PopulatedFRU != nil {
+				_, err := s.db.DeleteHWInvByFRUID(hwloc.PopulatedFRU.FRUID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	// Takes the component and service endpoints with it.
+	if _, err := s.db.DeleteRFEndpointByID(id); err != nil {
+		return err
+	}
+	for _, cid := range ids {
+		if _, err := s.db.DeleteComponentByID(cid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *selfTestFingerprints) add(b *hmsds.RFEndpointBatch) {
+	if b.CompEndpoints != nil {
+		for _, cep := range b.CompEndpoints.ComponentEndpoints {
+			f.compEPs = append(f.compEPs, strings.Join([]string{
+				cep.ID, cep.Type, cep.RedfishType, cep.URL}, " "))
+		}
+	}
+	if b.ServiceEndpoints != nil {
+		for _, sep := range b.ServiceEndpoints.ServiceEndpoints {
+			f.serviceEPs = append(f.serviceEPs, sep.RedfishType)
+		}
+	}
+	if b.Components != nil {
+		for _, c := range b.Components.Components {
+			f.comps = append(f.comps, strings.Join([]string{
+				c.ID, c.Type, c.State, c.Flag}, " "))
+		}
+	}
+	for _, cei := range b.CompEthInterfaces {
+		f.ceis = append(f.ceis, strings.Join([]string{
+			cei.ID, cei.CompID, cei.MACAddr}, " "))
+	}
+	for _, hwloc := range b.HWInvByLocs {
+		fruID := ""
+		if hwloc.PopulatedFRU != nil {
+			fruID = hwloc.PopulatedFRU.FRUID
+			f.fruLocs = append(f.fruLocs, hwloc.ID)
+		}
+		f.hwlocs = append(f.hwlocs, strings.Join([]string{
+			hwloc.ID, hwloc.Type, hwloc.Status, fruID}, " "))
+	}
+}
+
+func (f *selfTestFingerprints) sort() {
+	for _, l := range [][]string{f.compEPs, f.serviceEPs, f.comps,
+		f.ceis, f.hwlocs, f.fruLocs} {
+		sort.Strings(l)
+	}
+}
+
+func selfTestCompare(want, got []string) error {
+	if len(want) != len(got) {
+		return fmt.Errorf("wrote %d, read back %d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return fmt.Errorf("wrote '%s', read back '%s'", want[i], got[i])
+		}
+	}
+	return nil
+}
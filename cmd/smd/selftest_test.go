@@ -0,0 +1,171 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func selfTestReady() (int, string) {
+	req := httptest.NewRequest("GET", "https://localhost/hsm/v2/service/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code, w.Body.String()
+}
+
+func TestSelfTest(t *testing.T) {
+	const id = "x9999c7s3b0"
+	defer func() {
+		s.selfTest = SelfTestState{}
+		s.selfTestID = ""
+		results.InsertRFEndpoint.Return.err = nil
+		results.GetRFEndpointByID.Return.entry = nil
+		results.GetCompEndpointsFilter.Return.entries = nil
+		results.GetServiceEndpointsFilter.Return.entries = nil
+		results.GetComponentsFilter.Return.ids = nil
+		results.GetCompEthInterfaceFilter.Return.ceis = nil
+		results.GetHWInvByLocFilter.Return.hwlocs = nil
+		results.GetHWInvHistFilter.Return.hwhists = nil
+	}()
+	s.selfTestID = id
+	results.TestConnection.Return.err = nil
+	results.InsertRFEndpoint.Return.err = nil
+	results.GetRFEndpointByID.Return.entry = nil
+
+	// Nothing reads back, so the self-test must fail after storing.
+	s.SelfTestInit()
+	if code, _ := selfTestReady(); code != http.StatusServiceUnavailable {
+		t.Errorf("Ready while running: got %d, expected 503", code)
+	}
+	s.SelfTestRun()
+	status, reason := s.selfTest.Get()
+	if status != SelfTestFailed || !strings.Contains(reason, "not found") {
+		t.Fatalf("Expected failure on read-back, got %s: %s", status, reason)
+	}
+	if code, body := selfTestReady(); code != http.StatusServiceUnavailable ||
+		!strings.Contains(body, "self-test failed") {
+		t.Errorf("Ready after failure: got %d %s", code, body)
+	}
+	if results.InsertRFEndpoint.Input.ep.ID != id ||
+		results.InsertRFEndpoint.Input.ep.FQDN != id+"."+rf.SelfTestDomain {
+		t.Errorf("Unexpected RedfishEndpoint inserted: %+v",
+			results.InsertRFEndpoint.Input.ep)
+	}
+	if results.DeleteRFEndpointByID.Input.id != id {
+		t.Errorf("Self-test endpoint not deleted, got '%s'",
+			results.DeleteRFEndpointByID.Input.id)
+	}
+	batches := results.UpdateAllForRFEndpointBatches.Input.batches
+	if len(batches) == 0 {
+		t.Fatalf("Nothing stored")
+	}
+
+	// Read back exactly what was stored.
+	results.GetRFEndpointByID.Return.entry = &sm.RedfishEndpoint{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID:       id,
+			FQDN:     id + "." + rf.SelfTestDomain,
+			DiscInfo: rf.DiscoveryInfo{LastStatus: rf.DiscoverOK},
+		},
+	}
+	readBack(batches)
+	s.SelfTestInit()
+	s.SelfTestRun()
+	if status, reason := s.selfTest.Get(); status != SelfTestPassed {
+		t.Fatalf("Expected pass, got %s: %s", status, reason)
+	}
+	if code, body := selfTestReady(); code != http.StatusOK {
+		t.Errorf("Ready after pass: got %d %s", code, body)
+	}
+
+	// A lost MAC address must be noticed.
+	ceis := results.GetCompEthInterfaceFilter.Return.ceis
+	results.GetCompEthInterfaceFilter.Return.ceis = ceis[1:]
+	s.SelfTestInit()
+	s.SelfTestRun()
+	status, reason = s.selfTest.Get()
+	if status != SelfTestFailed || !strings.Contains(reason, "EthernetInterfaces") {
+		t.Errorf("Expected EthernetInterfaces failure, got %s: %s", status, reason)
+	}
+	results.GetCompEthInterfaceFilter.Return.ceis = ceis
+
+	// The configured xname belongs to a real endpoint.
+	results.InsertRFEndpoint.Return.err = hmsds.ErrHMSDSDuplicateKey
+	results.GetRFEndpointByID.Return.entry.FQDN = id + ".example.com"
+	results.DeleteRFEndpointByID.Input.id = ""
+	s.SelfTestInit()
+	s.SelfTestRun()
+	status, reason = s.selfTest.Get()
+	if status != SelfTestFailed || reason != ErrSelfTestInUse.Error() {
+		t.Errorf("Expected in-use failure, got %s: %s", status, reason)
+	}
+	if results.DeleteRFEndpointByID.Input.id != "" {
+		t.Errorf("Real endpoint %s was deleted", id)
+	}
+}
+
+// Have the mock DB return the contents of the given batches.
+func readBack(batches []*hmsds.RFEndpointBatch) {
+	results.GetCompEndpointsFilter.Return.entries = nil
+	results.GetServiceEndpointsFilter.Return.entries = nil
+	results.GetComponentsFilter.Return.ids = nil
+	results.GetCompEthInterfaceFilter.Return.ceis = nil
+	results.GetHWInvByLocFilter.Return.hwlocs = nil
+	results.GetHWInvHistFilter.Return.hwhists = nil
+	for _, b := range batches {
+		if b.CompEndpoints != nil {
+			results.GetCompEndpointsFilter.Return.entries = append(
+				results.GetCompEndpointsFilter.Return.entries,
+				b.CompEndpoints.ComponentEndpoints...)
+		}
+		if b.ServiceEndpoints != nil {
+			results.GetServiceEndpointsFilter.Return.entries = append(
+				results.GetServiceEndpointsFilter.Return.entries,
+				b.ServiceEndpoints.ServiceEndpoints...)
+		}
+		if b.Components != nil {
+			results.GetComponentsFilter.Return.ids = append(
+				results.GetComponentsFilter.Return.ids,
+				b.Components.Components...)
+		}
+		results.GetCompEthInterfaceFilter.Return.ceis = append(
+			results.GetCompEthInterfaceFilter.Return.ceis,
+			b.CompEthInterfaces...)
+		results.GetHWInvByLocFilter.Return.hwlocs = append(
+			results.GetHWInvByLocFilter.Return.hwlocs, b.HWInvByLocs...)
+		for _, hwloc := range b.HWInvByLocs {
+			if hwloc.PopulatedFRU != nil {
+				results.GetHWInvHistFilter.Return.hwhists = append(
+					results.GetHWInvHistFilter.Return.hwhists,
+					&sm.HWInvHist{ID: hwloc.ID, FruId: hwloc.PopulatedFRU.FRUID})
+			}
+		}
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
 	"github.com/go-chi/chi/v5"
 	"github.com/openchami/schemas/schemas"
 	redfish "github.com/openchami/schemas/schemas/csm"
@@ -72,6 +73,20 @@ type FieldFltrInForm struct {
 	NIDOnly   []string `json:"nidonly"`
 }
 
+// A single component along with the components above and/or below it,
+// as requested with ?include=ancestors,descendants.
+type CompWithRelatives struct {
+	*base.Component
+	Ancestors   *[]*base.Component `json:"Ancestors,omitempty"`
+	Descendants *[]*base.Component `json:"Descendants,omitempty"`
+}
+
+// Valid values for the 'include' parameter on component GETs
+const (
+	CompIncludeAncestors   = "ancestors"
+	CompIncludeDescendants = "descendants"
+)
+
 type HwInvIn struct {
 	Hardware []sm.HWInvByLoc `json:"Hardware"`
 }
@@ -87,6 +102,22 @@ type HwInvQueryIn struct {
 	Parents      []string `json:"parents"`
 	Partition    []string `json:"partition"`
 	Format       []string `json:"format"`
+	Columns      []string `json:"columns"`
+}
+
+type SparePartsIn struct {
+	Type         []string `json:"type"`
+	Manufacturer []string `json:"manufacturer"`
+	PartNumber   []string `json:"partnumber"`
+	StartTime    []string `json:"starttime"`
+	EndTime      []string `json:"endtime"`
+}
+
+type ThermalSensorsIn struct {
+	ID           []string `json:"id"`
+	RfEndpointID []string `json:"redfish_ep"`
+	Type         []string `json:"type"`
+	Kind         []string `json:"kind"`
 }
 
 type HwInvHistIn struct {
@@ -101,6 +132,7 @@ type GrpPartFltr struct {
 	Group     []string `json:"group"`
 	Tag       []string `json:"tag"`
 	Partition []string `json:"partition"`
+	Recursive []string `json:"recursive"`
 }
 
 type CompLockFltr struct {
@@ -290,11 +322,26 @@ func (s *SmD) doReadyGet(w http.ResponseWriter, r *http.Request) {
 	// Check that the DB connection is still available
 	err := s.db.TestConnection()
 	if err != nil {
-		s.LogAlways("doReadyGet(): Database failed health check: %s", err)
+		s.reqLog(r).LogAlways("doReadyGet(): Database failed health check: %s", err)
 		sendJsonError(w, http.StatusServiceUnavailable, "HSM's database is unhealthy: "+err.Error())
 		return
 	}
+	// Not ready until the startup self-test, if any, has passed.
+	switch status, reason := s.selfTest.Get(); status {
+	case SelfTestRunning:
+		sendJsonError(w, http.StatusServiceUnavailable,
+			"HSM self-test has not finished")
+		return
+	case SelfTestFailed:
+		sendJsonError(w, http.StatusServiceUnavailable,
+			"HSM self-test failed: "+reason)
+		return
+	}
 	// Tell them we are up and healthy
+	if ro := s.readOnly.Get(); ro.ReadOnly {
+		sendJsonError(w, http.StatusOK, "HSM is healthy but read-only: "+ro.Reason)
+		return
+	}
 	sendJsonError(w, http.StatusOK, "HSM is healthy")
 }
 
@@ -414,7 +461,7 @@ func (s *SmD) doComponentGet(w http.ResponseWriter, r *http.Request) {
 
 	cmp, err := s.db.GetComponentByID(xname)
 	if err != nil {
-		s.LogAlways("doComponentGet(): Lookup failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doComponentGet(): Lookup failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -422,15 +469,144 @@ func (s *SmD) doComponentGet(w http.ResponseWriter, r *http.Request) {
 		sendJsonError(w, http.StatusNotFound, "no such xname.")
 		return
 	}
-	// Over all summary error code needs to be computed...
-	sendJsonCompRsp(w, cmp)
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doComponentGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	ancestors, descendants, err := parseCompInclude(r.Form["include"])
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ancestors && !descendants {
+		// Over all summary error code needs to be computed...
+		etag := compETag(cmp)
+		w.Header().Set("ETag", etag)
+		if inm := r.Header.Get("If-None-Match"); inm != "" && ifNoneMatches(inm, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		sendJsonCompRsp(w, cmp)
+		return
+	}
+	out := CompWithRelatives{Component: cmp}
+	if ancestors {
+		comps, err := s.getCompAncestors([]string{cmp.ID}, hmsds.FLTR_DEFAULT)
+		if err != nil {
+			s.reqLog(r).LogAlways("doComponentGet(): Ancestor lookup failure: (%s) %s",
+				xname, err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		out.Ancestors = &comps
+	}
+	if descendants {
+		comps, err := s.getCompDescendants([]string{cmp.ID}, hmsds.FLTR_DEFAULT)
+		if err != nil {
+			s.reqLog(r).LogAlways("doComponentGet(): Descendant lookup failure: (%s) %s",
+				xname, err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		out.Descendants = &comps
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
+
+// Parse the values given for the 'include' parameter on component GETs.
+// Each may be a single value or a comma-separated list.
+func parseCompInclude(vals []string) (ancestors, descendants bool, err error) {
+	for _, val := range vals {
+		for _, inc := range strings.Split(val, ",") {
+			switch strings.ToLower(strings.TrimSpace(inc)) {
+			case CompIncludeAncestors:
+				ancestors = true
+			case CompIncludeDescendants:
+				descendants = true
+			case "":
+			default:
+				return false, false, fmt.Errorf("bad include value '%s', "+
+					"expected '%s' or '%s'", inc, CompIncludeAncestors,
+					CompIncludeDescendants)
+			}
+		}
+	}
+	return ancestors, descendants, nil
+}
+
+// Get the components that contain the given ids, e.g. the enclosure,
+// chassis and cabinet of a node.  Only those in the database are returned,
+// nearest first for each id, and without the ids themselves.
+func (s *SmD) getCompAncestors(ids []string, fieldFltr hmsds.FieldFilter) ([]*base.Component, error) {
+	seen := make(map[string]bool)
+	chain := make([]string, 0, 4)
+	for _, id := range ids {
+		for p := xnametypes.GetHMSCompParent(id); ; p = xnametypes.GetHMSCompParent(p) {
+			pType := xnametypes.GetHMSType(p)
+			if pType == xnametypes.HMSTypeInvalid || pType == xnametypes.System {
+				break
+			}
+			if !seen[p] {
+				seen[p] = true
+				chain = append(chain, p)
+			}
+		}
+	}
+	comps := make([]*base.Component, 0, len(chain))
+	if len(chain) == 0 {
+		return comps, nil
+	}
+	found, err := s.db.GetComponentsFilter(&hmsds.ComponentFilter{ID: chain}, fieldFltr)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*base.Component, len(found))
+	for _, c := range found {
+		byID[c.ID] = c
+	}
+	for _, id := range chain {
+		if c, ok := byID[id]; ok {
+			comps = append(comps, c)
+		}
+	}
+	return comps, nil
+}
+
+// Get all components contained by the given ids, e.g. everything in a
+// chassis, not including the ids themselves.
+func (s *SmD) getCompDescendants(ids []string, fieldFltr hmsds.FieldFilter) ([]*base.Component, error) {
+	// GetComponentsQuery only expands to child components when there is
+	// a type filter, so ask for every valid type.
+	f := new(hmsds.ComponentFilter)
+	for _, t := range xnametypes.GetHMSTypeList() {
+		if t != xnametypes.HMSTypeInvalid.String() {
+			f.Type = append(f.Type, t)
+		}
+	}
+	found, err := s.db.GetComponentsQuery(f, fieldFltr, ids)
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		parents[xnametypes.NormalizeHMSCompID(id)] = true
+	}
+	comps := make([]*base.Component, 0, len(found))
+	for _, c := range found {
+		if !parents[c.ID] {
+			comps = append(comps, c)
+		}
+	}
+	return comps, nil
 }
 
 // Delete single ComponentEndpoint, by its xname ID.
 func (s *SmD) doComponentDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doComponentDelete(): trying...")
+	s.reqLog(r).LogAlways("doComponentDelete(): trying...")
 
 	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
 
@@ -439,9 +615,9 @@ func (s *SmD) doComponentDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	didDelete, err := s.db.DeleteComponentByID(xname)
+	didDelete, err := s.db.WithActor(s.requestActor(r)).DeleteComponentByID(xname)
 	if err != nil {
-		s.LogAlways("doComponentDelete(): delete failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doComponentDelete(): delete failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -461,21 +637,21 @@ func (s *SmD) doComponentsGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doComponentsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doComponentsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doComponentsGet(): Marshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsGet(): Marshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	compFilter := new(hmsds.ComponentFilter)
 	if err = json.Unmarshal(formJSON, compFilter); err != nil {
-		s.lg.Printf("doComponentsGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -483,19 +659,111 @@ func (s *SmD) doComponentsGet(w http.ResponseWriter, r *http.Request) {
 	// Get the component field filter options (i.e. stateonly)
 	fieldFltrIn := new(FieldFltrInForm)
 	if err = json.Unmarshal(formJSON, fieldFltrIn); err != nil {
-		s.lg.Printf("doComponentsGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
+	ancestors, descendants, err := parseCompInclude(r.Form["include"])
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	paging, err := parseListPaging(r.Form, base.Component{}, "ID")
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	fieldFltr := getFieldFilterForm(fieldFltrIn)
+	if asOf := r.Form.Get("asof"); asOf != "" {
+		// Components as they were at some time in the past.  Relatives
+		// are looked up in the current component set, so can't be mixed in.
+		if ancestors || descendants {
+			sendJsonError(w, http.StatusBadRequest,
+				"include cannot be used with asof")
+			return
+		}
+		comps.Components, err = s.db.GetComponentsAsOf(compFilter, fieldFltr, asOf)
+		if err != nil {
+			s.reqLog(r).LogAlways("doComponentsGet(): Lookup failure: %s", err)
+			sendJsonDBError(w, "bad query param: ", "", err)
+			return
+		}
+		if paging != nil {
+			sendListPage(w, r, paging, "Components", comps.Components)
+			return
+		}
+		sendJsonCompArrayRsp(w, r, comps)
+		return
+	}
 	comps.Components, err = s.db.GetComponentsFilter(compFilter, fieldFltr)
 	if err != nil {
-		s.LogAlways("doComponentsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doComponentsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
-	sendJsonCompArrayRsp(w, comps)
+	if ancestors || descendants {
+		// Add the relatives of the matching components to the results,
+		// skipping any that are already there.
+		ids := make([]string, 0, len(comps.Components))
+		seen := make(map[string]bool, len(comps.Components))
+		for _, c := range comps.Components {
+			ids = append(ids, c.ID)
+			seen[c.ID] = true
+		}
+		var relatives []*base.Component
+		if ancestors && len(ids) > 0 {
+			found, err := s.getCompAncestors(ids, fieldFltr)
+			if err != nil {
+				s.reqLog(r).LogAlways("doComponentsGet(): Ancestor lookup failure: %s", err)
+				sendJsonDBError(w, "", "", err)
+				return
+			}
+			relatives = append(relatives, found...)
+		}
+		if descendants && len(ids) > 0 {
+			found, err := s.getCompDescendants(ids, fieldFltr)
+			if err != nil {
+				s.reqLog(r).LogAlways("doComponentsGet(): Descendant lookup failure: %s", err)
+				sendJsonDBError(w, "", "", err)
+				return
+			}
+			relatives = append(relatives, found...)
+		}
+		for _, c := range relatives {
+			if !seen[c.ID] {
+				seen[c.ID] = true
+				comps.Components = append(comps.Components, c)
+			}
+		}
+	}
+	if paging != nil {
+		sendListPage(w, r, paging, "Components", comps.Components)
+		return
+	}
+	sendJsonCompArrayRsp(w, r, comps)
+}
+
+// Warn if any of the NIDs given for comps already belong to a different
+// component.  This is not an error as NIDs are sometimes moved between
+// components on purpose, but it is usually a mistake.
+func (s *SmD) warnNIDsInUse(w http.ResponseWriter, comps []*base.Component) {
+	for _, comp := range comps {
+		if len(comp.NID) == 0 {
+			continue
+		}
+		other, err := s.db.GetComponentByNID(comp.NID.String())
+		if err != nil || other == nil || other.ID == comp.ID {
+			continue
+		}
+		if other.Enabled != nil && !*other.Enabled {
+			addWarning(w, "NID %s already in use by disabled component %s",
+				comp.NID, other.ID)
+		} else {
+			addWarning(w, "NID %s already in use by component %s",
+				comp.NID, other.ID)
+		}
+	}
 }
 
 // CREATE/Update components. If the component already exists it will not be
@@ -520,11 +788,12 @@ func (s *SmD) doComponentsPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = compsIn.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doComponentsPost(): Couldn't validate components: %s", err)
+		s.reqLog(r).LogAlways("doComponentsPost(): Couldn't validate components: %s", err)
 		sendJsonError(w, http.StatusBadRequest,
 			"couldn't validate components: "+err.Error())
 		return
 	}
+	s.warnNIDsInUse(w, compsIn.Components)
 	// Get the nid and role defaults for all node types
 	for _, comp := range compsIn.Components {
 		if comp.Type == xnametypes.Node.String() || comp.Type == xnametypes.VirtualNode.String() {
@@ -545,10 +814,10 @@ func (s *SmD) doComponentsPost(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	changeMap, err := s.db.UpsertComponents(compsIn.Components, compsIn.Force)
+	changeMap, err := s.db.WithActor(s.requestActor(r)).UpsertComponents(compsIn.Components, compsIn.Force)
 	if err != nil {
 		sendJsonDBError(w, "operation 'Post Components' failed: ", "", err)
-		s.LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	}
 
@@ -659,11 +928,11 @@ func (s *SmD) doComponentsQueryPost(w http.ResponseWriter, r *http.Request) {
 	fieldFltr := getFieldFilter(fieldFltrIn)
 	comps.Components, err = s.db.GetComponentsQuery(compFilter, fieldFltr, compQuery.ComponentIDs)
 	if err != nil {
-		s.LogAlways("doComponentsQueryPost(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryPost(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
-	sendJsonCompArrayRsp(w, comps)
+	sendJsonCompArrayRsp(w, r, comps)
 }
 
 // Get all HMS Components under a single parent component as named array
@@ -678,14 +947,14 @@ func (s *SmD) doComponentsQueryGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doComponentsQueryGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doComponentsQueryGet(): Marshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): Marshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -693,7 +962,7 @@ func (s *SmD) doComponentsQueryGet(w http.ResponseWriter, r *http.Request) {
 	// Get the query parameters
 	compFilter := new(hmsds.ComponentFilter)
 	if err = json.Unmarshal(formJSON, compFilter); err != nil {
-		s.lg.Printf("doComponentsQueryGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -701,7 +970,7 @@ func (s *SmD) doComponentsQueryGet(w http.ResponseWriter, r *http.Request) {
 	// Get the component field filter options (i.e. stateonly)
 	fieldFltrIn := new(FieldFltrInForm)
 	if err = json.Unmarshal(formJSON, fieldFltrIn); err != nil {
-		s.lg.Printf("doComponentsQueryGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -710,11 +979,11 @@ func (s *SmD) doComponentsQueryGet(w http.ResponseWriter, r *http.Request) {
 	ids = append(ids, xname)
 	comps.Components, err = s.db.GetComponentsQuery(compFilter, fieldFltr, ids)
 	if err != nil {
-		s.LogAlways("doComponentsQueryGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
-	sendJsonCompArrayRsp(w, comps)
+	sendJsonCompArrayRsp(w, r, comps)
 }
 
 // Delete entire collection of ComponentEndpoints, undoing discovery.
@@ -722,9 +991,9 @@ func (s *SmD) doComponentsDeleteAll(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
 	var err error
-	numDeleted, err := s.db.DeleteComponentsAll()
+	numDeleted, err := s.db.WithActor(s.requestActor(r)).DeleteComponentsAll()
 	if err != nil {
-		s.lg.Printf("doCompEndpointsDelete(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEndpointsDelete(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -746,7 +1015,7 @@ func (s *SmD) doComponentByNIDGet(w http.ResponseWriter, r *http.Request) {
 
 	cmp, err := s.db.GetComponentByNID(xname)
 	if err != nil {
-		s.LogAlways("doStateComponent(): Lookup failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doStateComponent(): Lookup failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -809,11 +1078,11 @@ func (s *SmD) doComponentByNIDQueryPost(w http.ResponseWriter, r *http.Request)
 	}
 	comps.Components, err = s.db.GetComponentsFilter(compFilter, fieldFltr)
 	if err != nil {
-		s.LogAlways("doComponentsQueryGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doComponentsQueryGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
-	sendJsonCompArrayRsp(w, comps)
+	sendJsonCompArrayRsp(w, r, comps)
 }
 
 // Bulk NID patch.  Unlike other patch methods, there needs to be a
@@ -836,14 +1105,19 @@ func (s *SmD) doCompBulkNIDPatch(w http.ResponseWriter, r *http.Request) {
 		sendJsonError(w, http.StatusBadRequest, "Missing Components")
 		return
 	}
+	nidComps := make([]*base.Component, 0, len(*components))
+	for i := range *components {
+		nidComps = append(nidComps, &(*components)[i])
+	}
+	s.warnNIDsInUse(w, nidComps)
 	err = s.db.BulkUpdateCompNID(components)
 	if err != nil {
 		sendJsonDBError(w, "operation 'Bulk Update NID' failed: ",
 			"", err)
-		s.LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	}
-	s.lg.Printf("succeeded: %s %s", r.RemoteAddr, string(body))
+	s.reqLog(r).LogAlways("succeeded: %s %s", r.RemoteAddr, string(body))
 
 	// Send 204 status (success, no content in response)
 	sendJsonError(w, http.StatusNoContent, "")
@@ -930,6 +1204,7 @@ func (s *SmD) compPatchHelper(
 	//
 	// Update Database
 	//
+	update.actor = s.requestActor(r)
 	err := s.doCompUpdate(update, name)
 	if err != nil {
 		op := VerifyNormalizeCompUpdateType(update.UpdateType)
@@ -1099,11 +1374,12 @@ func (s *SmD) doComponentPut(w http.ResponseWriter, r *http.Request) {
 	}
 	err = compIn.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doComponentPut(): Couldn't validate component: %s", err)
+		s.reqLog(r).LogAlways("doComponentPut(): Couldn't validate component: %s", err)
 		sendJsonError(w, http.StatusBadRequest,
 			"couldn't validate component: "+err.Error())
 		return
 	}
+	s.warnNIDsInUse(w, []*base.Component{component})
 	// Get the nid and role defaults for all node types
 	if component.Type == xnametypes.Node.String() || component.Type == xnametypes.VirtualNode.String() {
 		if len(component.Role) == 0 || len(component.NID) == 0 || len(component.Class) == 0 {
@@ -1122,10 +1398,10 @@ func (s *SmD) doComponentPut(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	changeMap, err := s.db.UpsertComponents([]*base.Component{component}, compIn.Force)
+	changeMap, err := s.db.WithActor(s.requestActor(r)).UpsertComponents([]*base.Component{component}, compIn.Force)
 	if err != nil {
 		sendJsonDBError(w, "operation 'PUT' failed: ", "", err)
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	}
 	if changes, ok := changeMap[component.ID]; ok {
@@ -1165,12 +1441,12 @@ func (s *SmD) doComponentPut(w http.ResponseWriter, r *http.Request) {
 func (s *SmD) doNodeMapGet(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doNodeMapGet(): trying...")
+	s.reqLog(r).LogAlways("doNodeMapGet(): trying...")
 
 	xname := chi.URLParam(r, "xname")
 	m, err := s.db.GetNodeMapByID(xname)
 	if err != nil {
-		s.LogAlways("doNodeMapGet(): Lookup failure: (%s) %s",
+		s.reqLog(r).LogAlways("doNodeMapGet(): Lookup failure: (%s) %s",
 			xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -1183,19 +1459,32 @@ func (s *SmD) doNodeMapGet(w http.ResponseWriter, r *http.Request) {
 }
 
 // Get all NodeMap entries in database, by doing a GET against the
-// entire collection.
+// entire collection.  With format=csv they are returned as CSV.
 func (s *SmD) doNodeMapsGet(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
 	nnms := new(sm.NodeMapArray)
 	var err error
 
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format != "" && format != "json" && format != "csv" {
+		sendJsonError(w, http.StatusBadRequest, "Invalid format")
+		return
+	}
 	nnms.NodeMaps, err = s.db.GetNodeMapsAll()
 	if err != nil {
-		s.LogAlways("doNodeMapsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doNodeMapsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", NodeMapsCSVMediaType)
+		w.WriteHeader(http.StatusOK)
+		if err := writeNodeMapsCSV(w, nnms.NodeMaps); err != nil {
+			s.reqLog(r).LogAlways("doNodeMapsGet(): Write failure: %s", err)
+		}
+		return
+	}
 	sendJsonNodeMapArrayRsp(w, nnms)
 }
 
@@ -1256,7 +1545,7 @@ func (s *SmD) doNodeMapsPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertNodeMaps(nnms)
 	if err != nil {
-		s.lg.Printf("failed: %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing xname ID that has the same NID.")
@@ -1266,7 +1555,7 @@ func (s *SmD) doNodeMapsPost(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	s.lg.Printf("succeeded: %s %s", r.RemoteAddr, string(body))
+	s.reqLog(r).LogAlways("succeeded: %s %s", r.RemoteAddr, string(body))
 
 	numStr := strconv.FormatInt(int64(len(nnms.NodeMaps)), 10)
 	sendJsonError(w, http.StatusOK, "Created or modified "+numStr+" entries")
@@ -1311,7 +1600,7 @@ func (s *SmD) doNodeMapPut(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertNodeMap(nnm)
 	if err != nil {
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing resource that has the same NID")
@@ -1330,7 +1619,7 @@ func (s *SmD) doNodeMapPut(w http.ResponseWriter, r *http.Request) {
 func (s *SmD) doNodeMapDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doNodeMapDelete(): trying...")
+	s.reqLog(r).LogAlways("doNodeMapDelete(): trying...")
 
 	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
 
@@ -1340,7 +1629,7 @@ func (s *SmD) doNodeMapDelete(w http.ResponseWriter, r *http.Request) {
 	}
 	didDelete, err := s.db.DeleteNodeMapByID(xname)
 	if err != nil {
-		s.LogAlways("doNodeMapDelete(): delete failure: (%s) %s",
+		s.reqLog(r).LogAlways("doNodeMapDelete(): delete failure: (%s) %s",
 			xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -1359,7 +1648,7 @@ func (s *SmD) doNodeMapsDeleteAll(w http.ResponseWriter, r *http.Request) {
 	var err error
 	numDeleted, err := s.db.DeleteNodeMapsAll()
 	if err != nil {
-		s.lg.Printf("doNodeMapsDelete(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doNodeMapsDelete(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -1383,7 +1672,7 @@ func (s *SmD) doHWInvByLocationGet(w http.ResponseWriter, r *http.Request) {
 
 	hl, err := s.db.GetHWInvByLocID(xname)
 	if err != nil {
-		s.LogAlways("doHWInvByLocationGet(): Lookup failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doHWInvByLocationGet(): Lookup failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -1400,33 +1689,62 @@ func (s *SmD) doHWInvByLocationGetAll(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doHWInvByLocationGetAll(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationGetAll(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	hwInvIn := new(HwInvQueryIn)
 	if err = json.Unmarshal(formJSON, hwInvIn); err != nil {
-		s.lg.Printf("doHWInvByLocationGetAll(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 
+	// Bulk export formats
+	export, err := newHWInvExport(hwInvIn.Format, hwInvIn.Columns)
+	if err != nil {
+		s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): %s: %v %v", err,
+			hwInvIn.Format, hwInvIn.Columns)
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	paging, err := parseListPaging(r.Form, sm.HWInvByLoc{}, "ID")
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if paging != nil && export != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"paging is not supported with format "+export.format)
+		return
+	}
+
 	hwInvLocFilter := []hmsds.HWInvLocFiltFunc{}
 
 	if len(hwInvIn.ID) > 0 {
 		for i, id := range hwInvIn.ID {
+			if xnameutil.IsPattern(id) {
+				// e.g. x1000c0s*b0n[0-1], matched by the database.
+				p, err := xnameutil.ParsePattern(id)
+				if err != nil {
+					s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): %s", err)
+					sendJsonError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				hwInvIn.ID[i] = p.String()
+				continue
+			}
 			normId := xnametypes.VerifyNormalizeCompID(id)
 			if normId == "" {
-				s.lg.Printf("doHWInvByLocationGetAll(): Invalid xname: %s", id)
+				s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Invalid xname: %s", id)
 				sendJsonError(w, http.StatusBadRequest, "Invalid xname")
 				return
 			}
@@ -1440,7 +1758,7 @@ func (s *SmD) doHWInvByLocationGetAll(w http.ResponseWriter, r *http.Request) {
 		for i, cType := range hwInvIn.Type {
 			normType := xnametypes.VerifyNormalizeType(cType)
 			if normType == "" {
-				s.lg.Printf("doHWInvByLocationGetAll(): Invalid HMS type: %s", cType)
+				s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Invalid HMS type: %s", cType)
 				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type")
 				return
 			}
@@ -1474,7 +1792,7 @@ func (s *SmD) doHWInvByLocationGetAll(w http.ResponseWriter, r *http.Request) {
 		for _, p := range hwInvIn.Partition {
 			normP := sm.NormalizeGroupField(p)
 			if sm.VerifyGroupField(normP) != nil {
-				s.lg.Printf("doHWInvByLocationGetAll(): Invalid partition: %s", p)
+				s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Invalid partition: %s", p)
 				sendJsonError(w, http.StatusBadRequest, "Invalid partition")
 				return
 			}
@@ -1482,12 +1800,20 @@ func (s *SmD) doHWInvByLocationGetAll(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if export != nil {
+		s.sendHWInvExport(w, export, hwInvLocFilter)
+		return
+	}
 	hwlocs, err := s.db.GetHWInvByLocFilter(hwInvLocFilter...)
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationGetAll(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Lookup failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 		return
 	}
+	if paging != nil {
+		sendListPage(w, r, paging, "", hwlocs)
+		return
+	}
 	sendJsonHWInvByLocsRsp(w, hwlocs)
 }
 
@@ -1500,7 +1826,7 @@ func (s *SmD) doHWInvByLocationPost(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &hwIn)
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationPost(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationPost(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -1512,7 +1838,7 @@ func (s *SmD) doHWInvByLocationPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertHWInvByLocs(hwlocs)
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doHWInvByLocationPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -1532,7 +1858,7 @@ func (s *SmD) doHWInvByFRUGet(w http.ResponseWriter, r *http.Request) {
 	fruID := chi.URLParam(r, "fruid")
 	hf, err := s.db.GetHWInvByFRUID(fruID)
 	if err != nil {
-		s.LogAlways("doHWInvByFRUGet(): Lookup failure: (%s) %s", fruID, err)
+		s.reqLog(r).LogAlways("doHWInvByFRUGet(): Lookup failure: (%s) %s", fruID, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -1549,25 +1875,30 @@ func (s *SmD) doHWInvByFRUGetAll(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doHWInvByFRUGetAll(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByFRUGetAll(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doHWInvByFRUGetAll(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByFRUGetAll(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	hwInvIn := new(HwInvQueryIn)
 	if err = json.Unmarshal(formJSON, hwInvIn); err != nil {
-		s.lg.Printf("doHWInvByFRUGetAll(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByFRUGetAll(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
+	paging, err := parseListPaging(r.Form, sm.HWInvByFRU{}, "FRUID")
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	hwInvLocFilter := []hmsds.HWInvLocFiltFunc{}
 
@@ -1576,7 +1907,7 @@ func (s *SmD) doHWInvByFRUGetAll(w http.ResponseWriter, r *http.Request) {
 		for i, cType := range hwInvIn.Type {
 			normType := xnametypes.VerifyNormalizeType(cType)
 			if normType == "" {
-				s.lg.Printf("doHWInvByFRUGetAll(): Invalid HMS type: %s", cType)
+				s.reqLog(r).LogAlways("doHWInvByFRUGetAll(): Invalid HMS type: %s", cType)
 				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type")
 				return
 			}
@@ -1607,13 +1938,159 @@ func (s *SmD) doHWInvByFRUGetAll(w http.ResponseWriter, r *http.Request) {
 
 	hwfrus, err := s.db.GetHWInvByFRUFilter(hwInvLocFilter...)
 	if err != nil {
-		s.lg.Printf("doHWInvByFRUGetAll(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByFRUGetAll(): Lookup failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 		return
 	}
+	if paging != nil {
+		sendListPage(w, r, paging, "", hwfrus)
+		return
+	}
 	sendJsonHWInvByFRUsRsp(w, hwfrus)
 }
 
+// Find FRUs by serial number, part number, manufacturer, FRU ID or type and
+// get the location each one is currently at along with its full details.
+func (s *SmD) doHWInvByFRULocateGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doHWInvByFRULocateGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	formJSON, err := json.Marshal(r.Form)
+	if err != nil {
+		s.reqLog(r).LogAlways("doHWInvByFRULocateGet(): Marshal form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	hwInvIn := new(HwInvQueryIn)
+	if err = json.Unmarshal(formJSON, hwInvIn); err != nil {
+		s.reqLog(r).LogAlways("doHWInvByFRULocateGet(): Unmarshal form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+
+	hwInvLocFilter := []hmsds.HWInvLocFiltFunc{}
+
+	// Validate types
+	if len(hwInvIn.Type) > 0 {
+		for i, cType := range hwInvIn.Type {
+			normType := xnametypes.VerifyNormalizeType(cType)
+			if normType == "" {
+				s.reqLog(r).LogAlways("doHWInvByFRULocateGet(): Invalid HMS type: %s", cType)
+				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type")
+				return
+			}
+			hwInvIn.Type[i] = normType
+		}
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_Types(hwInvIn.Type))
+	}
+	if len(hwInvIn.Manufacturer) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_Manufacturers(hwInvIn.Manufacturer))
+	}
+	if len(hwInvIn.PartNumber) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_PartNumbers(hwInvIn.PartNumber))
+	}
+	if len(hwInvIn.SerialNumber) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_SerialNumbers(hwInvIn.SerialNumber))
+	}
+	if len(hwInvIn.FruId) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_FruIDs(hwInvIn.FruId))
+	}
+
+	hwfrus, err := s.db.LocateHWInvByFRU(hwInvLocFilter...)
+	if err != nil {
+		s.reqLog(r).LogAlways("doHWInvByFRULocateGet(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, hwfrus)
+}
+
+// Get spare parts counts for the FRUs in the system, optionally only for the
+// given types, manufacturers and part numbers.  Removals and replacements
+// are counted from the HW inventory history, optionally only between
+// starttime and endtime.
+func (s *SmD) doSparePartsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	formJSON, err := json.Marshal(r.Form)
+	if err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): Marshal form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	sparePartsIn := new(SparePartsIn)
+	if err = json.Unmarshal(formJSON, sparePartsIn); err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): Unmarshal form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+
+	hwInvLocFilter := []hmsds.HWInvLocFiltFunc{}
+	if len(sparePartsIn.Type) > 0 {
+		for i, cType := range sparePartsIn.Type {
+			normType := xnametypes.VerifyNormalizeType(cType)
+			if normType == "" {
+				s.reqLog(r).LogAlways("doSparePartsGet(): Invalid HMS type: %s", cType)
+				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type")
+				return
+			}
+			sparePartsIn.Type[i] = normType
+		}
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_Types(sparePartsIn.Type))
+	}
+	if len(sparePartsIn.Manufacturer) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_Manufacturers(sparePartsIn.Manufacturer))
+	}
+	if len(sparePartsIn.PartNumber) > 0 {
+		hwInvLocFilter = append(hwInvLocFilter, hmsds.HWInvLoc_PartNumbers(sparePartsIn.PartNumber))
+	}
+
+	// Replacements can involve FRUs that don't match the filters, so the
+	// history is only filtered by time.
+	hwInvHistFilter := []hmsds.HWInvHistFiltFunc{}
+	if len(sparePartsIn.StartTime) > 0 {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_StartTime(sparePartsIn.StartTime[0]))
+	}
+	if len(sparePartsIn.EndTime) > 0 {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_EndTime(sparePartsIn.EndTime[0]))
+	}
+
+	hwlocs, err := s.db.GetHWInvByLocFilter(hwInvLocFilter...)
+	if err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+		return
+	}
+	hwfrus, err := s.db.GetHWInvByFRUFilter(hwInvLocFilter...)
+	if err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+		return
+	}
+	hwhists, err := s.db.GetHWInvHistFilter(hwInvHistFilter...)
+	if err != nil {
+		s.reqLog(r).LogAlways("doSparePartsGet(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, sm.NewSparePartsReport(hwlocs, hwfrus, hwhists))
+}
+
 // Provides a xthwinv-type collection of system components, sorted by type
 // and optionally nested (fully, or node subcomponents only).
 func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request) {
@@ -1629,21 +2106,21 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doHWInvByLocationQueryGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationQueryGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	hwInvIn := new(HwInvQueryIn)
 	if err = json.Unmarshal(formJSON, hwInvIn); err != nil {
-		s.lg.Printf("doHWInvByLocationQueryGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -1660,7 +2137,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 
 	// Validate xnames
 	if compType == xnametypes.HMSTypeInvalid {
-		s.lg.Printf("doHWInvByLocationQueryGet(): Invalid xname: %s", xname)
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Invalid xname: %s", xname)
 		sendJsonError(w, http.StatusBadRequest, "Invalid xname")
 		return
 	} else if compType == xnametypes.Partition {
@@ -1676,7 +2153,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 		for i, cType := range hwInvIn.Type {
 			normType := xnametypes.VerifyNormalizeType(cType)
 			if normType == "" {
-				s.lg.Printf("doHWInvByLocationQueryGet(): Invalid HMS type: %s", cType)
+				s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Invalid HMS type: %s", cType)
 				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type")
 				return
 			}
@@ -1690,7 +2167,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 		for _, p := range hwInvIn.Partition {
 			normP := sm.NormalizeGroupField(p)
 			if sm.VerifyGroupField(normP) != nil {
-				s.lg.Printf("doHWInvByLocationGetAll(): Invalid partition: %s", p)
+				s.reqLog(r).LogAlways("doHWInvByLocationGetAll(): Invalid partition: %s", p)
 				sendJsonError(w, http.StatusBadRequest, "Invalid partition")
 				return
 			}
@@ -1702,7 +2179,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 	if len(hwInvIn.Parents) > 0 {
 		parents, err := strconv.ParseBool(hwInvIn.Parents[0])
 		if err != nil {
-			s.lg.Printf("doHWInvByLocationQueryGet(): Invalid string for parents: %s", hwInvIn.Parents[0])
+			s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Invalid string for parents: %s", hwInvIn.Parents[0])
 			sendJsonError(w, http.StatusBadRequest, "Invalid boolean for parents")
 			return
 		}
@@ -1716,7 +2193,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 	if len(hwInvIn.Children) > 0 {
 		children, err := strconv.ParseBool(hwInvIn.Children[0])
 		if err != nil {
-			s.lg.Printf("doHWInvByLocationQueryGet(): Invalid string for children: %s", hwInvIn.Children[0])
+			s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Invalid string for children: %s", hwInvIn.Children[0])
 			sendJsonError(w, http.StatusBadRequest, "Invalid boolean for children")
 			return
 		}
@@ -1739,7 +2216,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 			// Not implemented yet
 			fallthrough
 		default:
-			s.lg.Printf("doHWInvByLocationQueryGet(): Invalid format: %s", hwInvIn.Format)
+			s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(): Invalid format: %s", hwInvIn.Format)
 			sendJsonError(w, http.StatusBadRequest, "Invalid format")
 			return
 		}
@@ -1748,7 +2225,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 	// Do the query
 	hwlocs, err := s.db.GetHWInvByLocQueryFilter(hwInvLocFilter...)
 	if err != nil {
-		s.LogAlways("doHWInvByLocationQueryGet(%s): Lookup failure: %s",
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(%s): Lookup failure: %s",
 			xname, err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to query DB.")
@@ -1758,7 +2235,7 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 	// Sort the results
 	hwinv, err := sm.NewSystemHWInventory(hwlocs, xname, format)
 	if err != nil {
-		s.LogAlways("doHWInvByLocationQueryGet(%s): HWInv parse: %s",
+		s.reqLog(r).LogAlways("doHWInvByLocationQueryGet(%s): HWInv parse: %s",
 			xname, err)
 		if err != base.ErrHMSTypeInvalid &&
 			err != base.ErrHMSTypeUnsupported {
@@ -1772,6 +2249,47 @@ func (s *SmD) doHWInvByLocationQueryGet(w http.ResponseWriter, r *http.Request)
 	sendJsonSystemHWInvRsp(w, hwinv)
 }
 
+// Get a resource from the HW inventory exported in another schema, e.g.
+// /Inventory/Export/Redfish/Systems/x0c0s0b0n0.  The whole tree is built
+// from the current inventory on each request.
+func (s *SmD) doHWInvExportGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	format := chi.URLParam(r, "format")
+	exporter, err := sm.GetHWInvExporter(format)
+	if err != nil {
+		sendJsonError(w, http.StatusNotFound, fmt.Sprintf(
+			"no such export format, must be one of %v",
+			sm.GetHWInvExportFormats()))
+		return
+	}
+	hwlocs, err := s.db.GetHWInvByLocAll()
+	if err != nil {
+		s.reqLog(r).LogAlways("doHWInvExportGet(): Lookup failure: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to query DB.")
+		return
+	}
+	root := s.invExportBaseV2 + "/" + format
+	resources, err := exporter.Export(hwlocs, root)
+	if err != nil {
+		s.reqLog(r).LogAlways("doHWInvExportGet(%s): %s", format, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"Couldn't format response.")
+		return
+	}
+	path := root
+	if rest := strings.Trim(chi.URLParam(r, "*"), "/"); rest != "" {
+		path += "/" + rest
+	}
+	res, ok := resources[path]
+	if !ok {
+		sendJsonError(w, http.StatusNotFound, "no such resource.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, res)
+}
+
 // Delete a single HWInvByLocation by its xname ID.
 func (s *SmD) doHWInvByLocationDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
@@ -1779,7 +2297,7 @@ func (s *SmD) doHWInvByLocationDelete(w http.ResponseWriter, r *http.Request) {
 	xname := chi.URLParam(r, "xname")
 	didDelete, err := s.db.DeleteHWInvByLocID(xname)
 	if err != nil {
-		s.LogAlways("doHWInvByLocationDelete(): delete failure: (%s) %s",
+		s.reqLog(r).LogAlways("doHWInvByLocationDelete(): delete failure: (%s) %s",
 			xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -1798,7 +2316,7 @@ func (s *SmD) doHWInvByLocationDeleteAll(w http.ResponseWriter, r *http.Request)
 	var err error
 	numDeleted, err := s.db.DeleteHWInvByLocsAll()
 	if err != nil {
-		s.lg.Printf("doHWInvByLocationDeleteAll(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByLocationDeleteAll(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -1817,7 +2335,7 @@ func (s *SmD) doHWInvByFRUDelete(w http.ResponseWriter, r *http.Request) {
 	fruID := chi.URLParam(r, "fruid")
 	didDelete, err := s.db.DeleteHWInvByFRUID(fruID)
 	if err != nil {
-		s.LogAlways("doHWInvByFRUDelete(): delete failure: (%s) %s",
+		s.reqLog(r).LogAlways("doHWInvByFRUDelete(): delete failure: (%s) %s",
 			fruID, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -1836,7 +2354,7 @@ func (s *SmD) doHWInvByFRUDeleteAll(w http.ResponseWriter, r *http.Request) {
 	var err error
 	numDeleted, err := s.db.DeleteHWInvByFRUsAll()
 	if err != nil {
-		s.lg.Printf("doHWInvByFRUDeleteAll(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doHWInvByFRUDeleteAll(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -2043,9 +2561,102 @@ func (s *SmD) hwInvHistGetAll(w http.ResponseWriter, r *http.Request, format sm.
 				sendJsonError(w, http.StatusBadRequest, "Invalid HWInvHist event type")
 				return
 			}
-			hwInvHistIn.EventType[i] = normEvType
+			hwInvHistIn.EventType[i] = normEvType
+		}
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_EventTypes(hwInvHistIn.EventType))
+	}
+
+	// Start Time
+	if len(hwInvHistIn.StartTime) > 0 {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_StartTime(hwInvHistIn.StartTime[0]))
+	}
+
+	// End Time
+	if len(hwInvHistIn.EndTime) > 0 {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_EndTime(hwInvHistIn.EndTime[0]))
+	}
+
+	hwhists, err := s.db.GetHWInvHistFilter(hwInvHistFilter...)
+	if err != nil {
+		s.lg.Printf("hwInvHistGetAll(%s): Lookup failure: %s", fmtStr, err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+		return
+	}
+	historyResp, err := sm.NewHWInvHistResp(hwhists, format)
+	if err != nil {
+		s.LogAlways("hwInvHistGetAll(%s): HWInvHist parse: %s", fmtStr, err)
+		sendJsonError(w, http.StatusInternalServerError, "Couldn't format response.")
+		return
+	}
+	sendJsonHWInvHistArrayRsp(w, historyResp)
+}
+
+// Get the sightings of a single FRU: each location it has been seen at and
+// when it was first and last seen there.
+func (s *SmD) doHWInvSightingsByFRUGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	fruid := chi.URLParam(r, "fruid")
+	if fruid == "" {
+		s.reqLog(r).LogAlways("doHWInvSightingsByFRUGet(): Invalid FRU ID: %s", fruid)
+		sendJsonError(w, http.StatusBadRequest, "Invalid FRU ID")
+		return
+	}
+	s.hwInvSightingsGet(w, r, fruid)
+}
+
+// Get the sightings of all FRUs, optionally filtered by location, FRU ID
+// and time range.
+func (s *SmD) doHWInvSightingsGetAll(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	s.hwInvSightingsGet(w, r, "")
+}
+
+// Get FRU sightings, grouped by FRU ID.  If fruid is given only that FRU's
+// are returned.
+func (s *SmD) hwInvSightingsGet(w http.ResponseWriter, r *http.Request, fruid string) {
+	if err := r.ParseForm(); err != nil {
+		s.lg.Printf("hwInvSightingsGet(%s): ParseForm: %s", fruid, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	formJSON, err := json.Marshal(r.Form)
+	if err != nil {
+		s.lg.Printf("hwInvSightingsGet(%s): Marshal form: %s", fruid, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	hwInvHistIn := new(HwInvHistIn)
+	if err = json.Unmarshal(formJSON, hwInvHistIn); err != nil {
+		s.lg.Printf("hwInvSightingsGet(%s): Unmarshal form: %s", fruid, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+
+	hwInvHistFilter := []hmsds.HWInvHistFiltFunc{}
+
+	if len(hwInvHistIn.ID) > 0 {
+		for i, id := range hwInvHistIn.ID {
+			normId := xnametypes.VerifyNormalizeCompID(id)
+			if normId == "" {
+				s.lg.Printf("hwInvSightingsGet(%s): Invalid xname: %s", fruid, id)
+				sendJsonError(w, http.StatusBadRequest, "Invalid xname")
+				return
+			}
+			hwInvHistIn.ID[i] = normId
 		}
-		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_EventTypes(hwInvHistIn.EventType))
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_IDs(hwInvHistIn.ID))
+	}
+
+	// FRU Id
+	if fruid != "" {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_FruIDs([]string{fruid}))
+	} else if len(hwInvHistIn.FruId) > 0 {
+		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_FruIDs(hwInvHistIn.FruId))
 	}
 
 	// Start Time
@@ -2058,19 +2669,16 @@ func (s *SmD) hwInvHistGetAll(w http.ResponseWriter, r *http.Request, format sm.
 		hwInvHistFilter = append(hwInvHistFilter, hmsds.HWInvHist_EndTime(hwInvHistIn.EndTime[0]))
 	}
 
-	hwhists, err := s.db.GetHWInvHistFilter(hwInvHistFilter...)
-	if err != nil {
-		s.lg.Printf("hwInvHistGetAll(%s): Lookup failure: %s", fmtStr, err)
-		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
+	sightings, err := s.db.GetHWInvSightingsFilter(hwInvHistFilter...)
+	if err == hmsds.ErrHMSDSArgBadTimeFormat {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
-	}
-	historyResp, err := sm.NewHWInvHistResp(hwhists, format)
-	if err != nil {
-		s.LogAlways("hwInvHistGetAll(%s): HWInvHist parse: %s", fmtStr, err)
-		sendJsonError(w, http.StatusInternalServerError, "Couldn't format response.")
+	} else if err != nil {
+		s.lg.Printf("hwInvSightingsGet(%s): Lookup failure: %s", fruid, err)
+		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 		return
 	}
-	sendJsonHWInvHistArrayRsp(w, historyResp)
+	sendJsonObject(w, http.StatusOK, sm.NewHWInvSightingResp(sightings))
 }
 
 // Delete the HWInvHist entries for a single HWInvByLocation by its xname ID.
@@ -2080,13 +2688,13 @@ func (s *SmD) doHWInvHistByLocationDelete(w http.ResponseWriter, r *http.Request
 	xname := chi.URLParam(r, "xname")
 	normId := xnametypes.VerifyNormalizeCompID(xname)
 	if normId == "" {
-		s.lg.Printf("doHWInvHistByLocationDelete(%s): Invalid xname: %s", xname, xname)
+		s.reqLog(r).LogAlways("doHWInvHistByLocationDelete(%s): Invalid xname: %s", xname, xname)
 		sendJsonError(w, http.StatusBadRequest, "Invalid xname")
 		return
 	}
 	numDeleted, err := s.db.DeleteHWInvHistByLocID(normId)
 	if err != nil {
-		s.LogAlways("doHWInvHistByLocationDelete(): delete failure: (%s) %s", normId, err)
+		s.reqLog(r).LogAlways("doHWInvHistByLocationDelete(): delete failure: (%s) %s", normId, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -2105,7 +2713,7 @@ func (s *SmD) doHWInvHistDeleteAll(w http.ResponseWriter, r *http.Request) {
 	var err error
 	numDeleted, err := s.db.DeleteHWInvHistAll()
 	if err != nil {
-		s.LogAlways("doHWInvHistDeleteAll(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doHWInvHistDeleteAll(): Delete failure: %s", err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -2124,7 +2732,7 @@ func (s *SmD) doHWInvHistByFRUDelete(w http.ResponseWriter, r *http.Request) {
 	fruID := chi.URLParam(r, "fruid")
 	numDeleted, err := s.db.DeleteHWInvHistByFRUID(fruID)
 	if err != nil {
-		s.LogAlways("doHWInvByFRUDelete(): Delete failure: (%s) %s", fruID, err)
+		s.reqLog(r).LogAlways("doHWInvByFRUDelete(): Delete failure: (%s) %s", fruID, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -2144,12 +2752,12 @@ func (s *SmD) doHWInvHistByFRUDelete(w http.ResponseWriter, r *http.Request) {
 func (s *SmD) doRedfishEndpointGet(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doRedfishEndpointGet(): trying...")
+	s.reqLog(r).LogAlways("doRedfishEndpointGet(): trying...")
 
 	xname := chi.URLParam(r, "xname")
 	ep, err := s.db.GetRFEndpointByID(xname)
 	if err != nil {
-		s.LogAlways("doRedfishEndpointGet(): Lookup failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doRedfishEndpointGet(): Lookup failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -2157,6 +2765,7 @@ func (s *SmD) doRedfishEndpointGet(w http.ResponseWriter, r *http.Request) {
 		sendJsonError(w, http.StatusNotFound, "no such xname.")
 		return
 	}
+	s.addRFEndpointLiveness([]*sm.RedfishEndpoint{ep})
 	sendJsonRFEndpointRsp(w, ep)
 }
 
@@ -2170,31 +2779,41 @@ func (s *SmD) doRedfishEndpointsGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doRedfishEndpointsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doRedfishEndpointsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doRedfishEndpointsGet(): Marshall form: %s", err)
+		s.reqLog(r).LogAlways("doRedfishEndpointsGet(): Marshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	rfEPFilter := new(hmsds.RedfishEPFilter)
 	if err = json.Unmarshal(formJSON, rfEPFilter); err != nil {
-		s.lg.Printf("doRedfishEndpointsGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doRedfishEndpointsGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
+	paging, err := parseListPaging(r.Form, sm.RedfishEndpoint{}, "ID")
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	eps.RedfishEndpoints, err = s.db.GetRFEndpointsFilter(rfEPFilter)
 	if err != nil {
-		s.LogAlways("doRedfishEndpointsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doRedfishEndpointsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
+	s.addRFEndpointLiveness(eps.RedfishEndpoints)
+	if paging != nil {
+		sendListPage(w, r, paging, "RedfishEndpoints", eps.RedfishEndpoints)
+		return
+	}
 	sendJsonRFEndpointArrayRsp(w, eps)
 }
 
@@ -2212,7 +2831,7 @@ func (s *SmD) doRedfishEndpointQueryGet(w http.ResponseWriter, r *http.Request)
 		var err error
 		eps.RedfishEndpoints, err = s.db.GetRFEndpointsAll()
 		if err != nil {
-			s.lg.Printf("doRedfishEndpointQueryGet(): Lookup failure: %s", err)
+			s.reqLog(r).LogAlways("doRedfishEndpointQueryGet(): Lookup failure: %s", err)
 			sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 			return
 		}
@@ -2229,12 +2848,12 @@ func (s *SmD) doRedfishEndpointQueryGet(w http.ResponseWriter, r *http.Request)
 func (s *SmD) doRedfishEndpointDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doRedfishEndpointDelete(): trying...")
+	s.reqLog(r).LogAlways("doRedfishEndpointDelete(): trying...")
 
 	xname := chi.URLParam(r, "xname")
-	didDelete, affectedIDs, err := s.db.DeleteRFEndpointByIDSetEmpty(xname)
+	didDelete, affectedIDs, err := s.db.WithActor(s.requestActor(r)).DeleteRFEndpointByIDSetEmpty(xname)
 	if err != nil {
-		s.LogAlways("doRedfishEndpointDelete(): delete failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doRedfishEndpointDelete(): delete failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -2250,17 +2869,26 @@ func (s *SmD) doRedfishEndpointDelete(w http.ResponseWriter, r *http.Request) {
 		scn := NewJobSCN(affectedIDs, data, s)
 		s.wp.Queue(scn)
 	}
+	// Its components no longer claim any MACs.
+	s.updateMACConflicts(xname, nil, nil, nil)
+	s.compCounts.Remove(xname)
 	sendJsonError(w, http.StatusOK, "deleted 1 entry")
 }
 
-// Delete collection containing all RedfishEndoint entries.
+// Delete collection containing all RedfishEndoint entries.  With id query
+// parameters, only those are deleted, along with everything discovered
+// through them, after confirmation (see rfep-delete.go).
 func (s *SmD) doRedfishEndpointsDeleteAll(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["id"]; ok {
+		s.doRedfishEndpointsDeleteBatch(w, r)
+		return
+	}
 	defer base.DrainAndCloseRequestBody(r)
 
 	var err error
-	numDeleted, affectedIDs, err := s.db.DeleteRFEndpointsAllSetEmpty()
+	numDeleted, affectedIDs, err := s.db.WithActor(s.requestActor(r)).DeleteRFEndpointsAllSetEmpty()
 	if err != nil {
-		s.lg.Printf("doRedfishEndpointsDelete(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doRedfishEndpointsDelete(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -2276,6 +2904,8 @@ func (s *SmD) doRedfishEndpointsDeleteAll(w http.ResponseWriter, r *http.Request
 		scn := NewJobSCN(affectedIDs, data, s)
 		s.wp.Queue(scn)
 	}
+	s.macIndex.reset()
+	s.compCounts.Reset()
 	numStr := strconv.FormatInt(numDeleted, 10)
 	sendJsonError(w, http.StatusOK, "deleted "+numStr+" entries")
 }
@@ -2342,7 +2972,7 @@ func (s *SmD) doRedfishEndpointPut(w http.ResponseWriter, r *http.Request) {
 
 	retEP, affectedIDs, err := s.db.UpdateRFEndpointNoDiscInfo(ep)
 	if err != nil {
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing resource that has the same FQDN")
@@ -2356,7 +2986,7 @@ func (s *SmD) doRedfishEndpointPut(w http.ResponseWriter, r *http.Request) {
 		// Create a new RedfishEndpoint in DB.
 		err = s.db.InsertRFEndpoint(ep)
 		if err != nil {
-			s.lg.Printf("failed: %s Err: %s", r.RemoteAddr, err)
+			s.reqLog(r).LogAlways("failed: %s Err: %s", r.RemoteAddr, err)
 			if err == hmsds.ErrHMSDSDuplicateKey {
 				sendJsonError(w, http.StatusConflict, "operation would conflict "+
 					"with an existing resource that has the same FQDN or xname ID.")
@@ -2408,7 +3038,7 @@ func (s *SmD) doRedfishEndpointPut(w http.ResponseWriter, r *http.Request) {
 				// since the future plan is for HSM to only read credentials
 				// from Vault. Other services like REDS should be writing the
 				// credentials to Vault.
-				s.lg.Printf("failed: %s Err: %s", r.RemoteAddr, err)
+				s.reqLog(r).LogAlways("failed: %s Err: %s", r.RemoteAddr, err)
 				sendJsonError(w, http.StatusInternalServerError,
 					"operation 'PUT' failed during secure store")
 				return
@@ -2441,7 +3071,7 @@ func (s *SmD) doRedfishEndpointPut(w http.ResponseWriter, r *http.Request) {
 	// in JSON format.
 	//
 
-	s.lg.Printf("succeeded: %s %s", r.RemoteAddr, string(body))
+	s.reqLog(r).LogAlways("succeeded: %s %s", r.RemoteAddr, string(body))
 
 	// Send 200 status (success
 	sendJsonRFEndpointRsp(w, retEP)
@@ -2488,7 +3118,7 @@ func (s *SmD) doRedfishEndpointPatch(w http.ResponseWriter, r *http.Request) {
 	}
 	retEP, affectedIDs, err := s.db.PatchRFEndpointNoDiscInfo(xname, rep)
 	if err != nil {
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing resource that has the same FQDN")
@@ -2500,7 +3130,7 @@ func (s *SmD) doRedfishEndpointPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	} else if retEP == nil {
 		// No error, but no update: Resource was not found.
-		s.lg.Printf("doRedfishEndpointPatch: No such entry %s", xname)
+		s.reqLog(r).LogAlways("doRedfishEndpointPatch: No such entry %s", xname)
 		sendJsonError(w, http.StatusNotFound, "No such entry: "+xname)
 		return
 	}
@@ -2524,7 +3154,7 @@ func (s *SmD) doRedfishEndpointPatch(w http.ResponseWriter, r *http.Request) {
 				// since the future plan is for HSM to only read credentials
 				// from Vault. Other services like REDS should be writing the
 				// credentials to Vault.
-				s.lg.Printf("failed: %s Err: %s", r.RemoteAddr, err)
+				s.reqLog(r).LogAlways("failed: %s Err: %s", r.RemoteAddr, err)
 				sendJsonError(w, http.StatusInternalServerError,
 					"operation 'PATCH' failed during secure store")
 				return
@@ -2545,7 +3175,7 @@ func (s *SmD) doRedfishEndpointPatch(w http.ResponseWriter, r *http.Request) {
 	// TODO:  Add auto-force based on time delta.
 	go s.discoverFromEndpoint(retEP, 0, false)
 
-	s.lg.Printf("succeeded: %s %s", r.RemoteAddr, string(body))
+	s.reqLog(r).LogAlways("succeeded: %s %s", r.RemoteAddr, string(body))
 
 	// Send 200 status (success
 	sendJsonRFEndpointRsp(w, retEP)
@@ -2557,6 +3187,12 @@ func (s *SmD) doRedfishEndpointPatch(w http.ResponseWriter, r *http.Request) {
 type scanableRedfishEndpoint struct {
 	*rf.RawRedfishEP
 	RedfishEndpoints *[]rf.RawRedfishEP `json:"RedfishEndpoints"`
+
+	// Vendor profile for any endpoint that doesn't set its own TemplateID
+	VendorProfile string `json:"VendorProfile"`
+
+	// Keep an event subscription to the event collector on the endpoints
+	EventSubscription bool `json:"EventSubscription"`
 }
 
 // CREATE new RedfishEndpoint or Endpoints if there is a named array provided
@@ -2575,7 +3211,18 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 			"error decoding JSON "+err.Error())
 		return
 	}
+	if scanEPs.EventSubscription && s.eventCollector == "" {
+		sendJsonError(w, http.StatusBadRequest, errEventSubNoCollector.Error())
+		return
+	}
+	credCache := make(map[string]*compcreds.CompCredentials)
 	if scanEPs.RawRedfishEP != nil {
+		err = s.applyVendorProfile(scanEPs.RawRedfishEP, scanEPs.VendorProfile, credCache)
+		if err != nil {
+			sendJsonError(w, http.StatusBadRequest,
+				"couldn't apply vendor profile: "+err.Error())
+			return
+		}
 		epd, err := rf.NewRedfishEPDescription(scanEPs.RawRedfishEP)
 		if err != nil {
 			sendJsonError(w, http.StatusBadRequest,
@@ -2601,6 +3248,13 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 			// Attempt to create a valid RedfishEndpointDescription from the
 			// raw data.  If we do not get any errors, it should be sane enough
 			// to put into the data store.
+			err = s.applyVendorProfile(&rep, scanEPs.VendorProfile, credCache)
+			if err != nil {
+				idx := strconv.Itoa(i)
+				sendJsonError(w, http.StatusBadRequest,
+					"couldn't apply vendor profile at idx "+idx+": "+err.Error())
+				return
+			}
 			epd, err := rf.NewRedfishEPDescription(&rep)
 			if err != nil {
 				idx := strconv.Itoa(i)
@@ -2626,7 +3280,7 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 
 	err = s.db.InsertRFEndpoints(eps)
 	if err != nil {
-		s.lg.Printf("failed: %s Err: %s", r.RemoteAddr, err)
+		s.reqLog(r).LogAlways("failed: %s Err: %s", r.RemoteAddr, err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing resource that has the same FQDN or xname ID.")
@@ -2651,7 +3305,7 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 					// since the future plan is for HSM to only read credentials
 					// from Vault. Other services like REDS should be writing the
 					// credentials to Vault.
-					s.lg.Printf("failed: %s Err: %s", r.RemoteAddr, err)
+					s.reqLog(r).LogAlways("failed: %s Err: %s", r.RemoteAddr, err)
 					sendJsonError(w, http.StatusInternalServerError,
 						"operation 'POST' failed during secure store. ")
 					return
@@ -2659,7 +3313,12 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
-	s.lg.Printf("succeeded: %s %s", r.RemoteAddr, string(body))
+	s.reqLog(r).LogAlways("succeeded: %s %s", r.RemoteAddr, string(body))
+
+	// Created by discovery
+	if scanEPs.EventSubscription {
+		s.addEventSubscriptions(eps.RedfishEndpoints)
+	}
 
 	// Do discovery if needed on new Endpoints.  Should never need to
 	// force this because the endpoint should always be new, else we would
@@ -2684,7 +3343,7 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if _, ok := payload["PDUInventory"]; ok {
-		s.lg.Printf("Payload contains PDUInventory key, routing to PDU parser.")
+		s.reqLog(r).LogAlways("Payload contains PDUInventory key, routing to PDU parser.")
 		err = s.parsePDUData(w, body, false)
 		if err != nil {
 			sendJsonError(w, http.StatusInternalServerError,
@@ -2692,7 +3351,7 @@ func (s *SmD) doRedfishEndpointsPost(w http.ResponseWriter, r *http.Request) {
 		}
 	} else {
 		if s.openchami {
-			s.lg.Printf("Payload does not contain PDUInventory key, routing to default V2 parser.")
+			s.reqLog(r).LogAlways("Payload does not contain PDUInventory key, routing to default V2 parser.")
 			if s.getSchemaVersion(w, body) > 0 { // Simplified from original
 				err = s.parseRedfishEndpointDataV2(w, body, false)
 				if err != nil {
@@ -3216,12 +3875,12 @@ func (s *SmD) getSchemaVersion(w http.ResponseWriter, data []byte) int {
 func (s *SmD) doComponentEndpointGet(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doComponentEndpointGet(): trying...")
+	s.reqLog(r).LogAlways("doComponentEndpointGet(): trying...")
 
 	xname := chi.URLParam(r, "xname")
 	cep, err := s.db.GetCompEndpointByID(xname)
 	if err != nil {
-		s.LogAlways("doComponentEndpointGet(): Lookup failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doComponentEndpointGet(): Lookup failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -3241,44 +3900,109 @@ func (s *SmD) doComponentEndpointsGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doComponentEndpointsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doComponentEndpointsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doComponentEndpointsGet(): Marshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentEndpointsGet(): Marshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	compEPFilter := new(hmsds.CompEPFilter)
 	if err = json.Unmarshal(formJSON, compEPFilter); err != nil {
-		s.lg.Printf("doComponentEndpointsGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doComponentEndpointsGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
+	paging, err := parseListPaging(r.Form, sm.ComponentEndpoint{}, "ID")
+	if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	ceps.ComponentEndpoints, err = s.db.GetCompEndpointsFilter(compEPFilter)
 	if err != nil {
-		s.LogAlways("doComponentEndpointsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doComponentEndpointsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
+	if paging != nil {
+		sendListPage(w, r, paging, "ComponentEndpoints", ceps.ComponentEndpoints)
+		return
+	}
 	sendJsonCompEndpointArrayRsp(w, ceps)
 }
 
+// Get the fans and temperature sensors discovered for ComponentEndpoints,
+// optionally filtered by xname, RedfishEndpoint, HMS type and sensor kind.
+// Only ComponentEndpoints with matching sensors are returned.
+func (s *SmD) doThermalSensorsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doThermalSensorsGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	formJSON, err := json.Marshal(r.Form)
+	if err != nil {
+		s.reqLog(r).LogAlways("doThermalSensorsGet(): Marshall form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	sensorsIn := new(ThermalSensorsIn)
+	if err = json.Unmarshal(formJSON, sensorsIn); err != nil {
+		s.reqLog(r).LogAlways("doThermalSensorsGet(): Unmarshall form: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	for _, kind := range sensorsIn.Kind {
+		if !strings.EqualFold(kind, rf.ThermalSensorTemperature) &&
+			!strings.EqualFold(kind, rf.ThermalSensorFan) {
+			sendJsonError(w, http.StatusBadRequest,
+				"kind must be Temperature or Fan")
+			return
+		}
+	}
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		ID:           sensorsIn.ID,
+		RfEndpointID: sensorsIn.RfEndpointID,
+		Type:         sensorsIn.Type,
+		RedfishType:  []string{rf.ChassisType, rf.ComputerSystemType},
+	})
+	if err != nil {
+		s.reqLog(r).LogAlways("doThermalSensorsGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "bad query param: ", "", err)
+		return
+	}
+	sensors := sm.ThermalSensorArray{
+		Components: []*sm.ComponentThermalSensors{},
+	}
+	for _, cep := range ceps {
+		if cts := cep.ThermalSensors(sensorsIn.Kind); cts != nil {
+			sensors.Components = append(sensors.Components, cts)
+		}
+	}
+	sendJsonObject(w, http.StatusOK, sensors)
+}
+
 // Delete single ComponentEndpoint, by its xname ID.
 func (s *SmD) doComponentEndpointDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doComponentEndpointDelete(): trying...")
+	s.reqLog(r).LogAlways("doComponentEndpointDelete(): trying...")
 
 	xname := chi.URLParam(r, "xname")
 	didDelete, affectedIDs, err := s.db.DeleteCompEndpointByIDSetEmpty(xname)
 	if err != nil {
-		s.lg.Printf("doComponentEndpointDelete(): delete failure: (%s) %s", xname, err)
+		s.reqLog(r).LogAlways("doComponentEndpointDelete(): delete failure: (%s) %s", xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -3304,7 +4028,7 @@ func (s *SmD) doComponentEndpointsDeleteAll(w http.ResponseWriter, r *http.Reque
 	var err error
 	numDeleted, affectedIDs, err := s.db.DeleteCompEndpointsAllSetEmpty()
 	if err != nil {
-		s.lg.Printf("doCompEndpointsDelete(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEndpointsDelete(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -3337,7 +4061,7 @@ func (s *SmD) doServiceEndpointGet(w http.ResponseWriter, r *http.Request) {
 	xname := chi.URLParam(r, "xname")
 	sep, err := s.db.GetServiceEndpointByID(svc, xname)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointGet(): Lookup failure: (%s,%s) %s", svc, xname, err)
+		s.reqLog(r).LogAlways("doServiceEndpointGet(): Lookup failure: (%s,%s) %s", svc, xname, err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "", err)
@@ -3359,28 +4083,28 @@ func (s *SmD) doServiceEndpointsGetAll(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doServiceEndpointsGetAll(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGetAll(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointsGetAll(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGetAll(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	serviceEPFilter := new(hmsds.ServiceEPFilter)
 	if err = json.Unmarshal(formJSON, serviceEPFilter); err != nil {
-		s.lg.Printf("doServiceEndpointsGetAll(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGetAll(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	seps.ServiceEndpoints, err = s.db.GetServiceEndpointsFilter(serviceEPFilter)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointsGetAll(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGetAll(): Lookup failure: %s", err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "", err)
@@ -3400,21 +4124,21 @@ func (s *SmD) doServiceEndpointsGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doServiceEndpointsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointsGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	serviceEPFilter := new(hmsds.ServiceEPFilter)
 	if err = json.Unmarshal(formJSON, serviceEPFilter); err != nil {
-		s.lg.Printf("doServiceEndpointsGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -3422,7 +4146,7 @@ func (s *SmD) doServiceEndpointsGet(w http.ResponseWriter, r *http.Request) {
 	serviceEPFilter.Service = []string{svc}
 	seps.ServiceEndpoints, err = s.db.GetServiceEndpointsFilter(serviceEPFilter)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsGet(): Lookup failure: %s", err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "", err)
@@ -3439,7 +4163,7 @@ func (s *SmD) doServiceEndpointDelete(w http.ResponseWriter, r *http.Request) {
 	xname := chi.URLParam(r, "xname")
 	didDelete, err := s.db.DeleteServiceEndpointByID(svc, xname)
 	if err != nil {
-		s.lg.Printf("doServiceEndpointDelete(): delete failure: (%s,%s) %s", svc, xname, err)
+		s.reqLog(r).LogAlways("doServiceEndpointDelete(): delete failure: (%s,%s) %s", svc, xname, err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "", err)
@@ -3459,7 +4183,7 @@ func (s *SmD) doServiceEndpointsDeleteAll(w http.ResponseWriter, r *http.Request
 	var err error
 	numDeleted, err := s.db.DeleteServiceEndpointsAll()
 	if err != nil {
-		s.lg.Printf("doServiceEndpointsDeleteAll(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doServiceEndpointsDeleteAll(): Delete failure: %s", err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "", err)
@@ -3484,7 +4208,7 @@ func (s *SmD) doCompEthInterfaceDeleteAll(w http.ResponseWriter, r *http.Request
 	var err error
 	numDeleted, err := s.db.DeleteCompEthInterfacesAll()
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceDeleteAll(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceDeleteAll(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
@@ -3503,19 +4227,19 @@ func (s *SmD) doCompEthInterfaceDelete(w http.ResponseWriter, r *http.Request) {
 	id := strings.ToLower(chi.URLParam(r, "id"))
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceDelete(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceDelete(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
 	}
 	didDelete, err := s.db.DeleteCompEthInterfaceByID(id)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceDelete(): delete failure: (%s) %s", id, err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceDelete(): delete failure: (%s) %s", id, err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
 	if !didDelete {
-		s.lg.Printf("doCompEthInterfaceDelete(): No such component ethernet interface, %s", id)
+		s.reqLog(r).LogAlways("doCompEthInterfaceDelete(): No such component ethernet interface, %s", id)
 		sendJsonError(w, http.StatusNotFound, "no such component ethernet interface.")
 		return
 	}
@@ -3530,21 +4254,21 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doCompEthInterfacesGetV2(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacesGetV2(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	filter := new(CompEthInterfaceFltr)
 	if err = json.Unmarshal(formJSON, filter); err != nil {
-		s.lg.Printf("doCompEthInterfacesGetV2(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -3555,7 +4279,7 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 	if len(filter.ID) > 0 {
 		for i, id := range filter.ID {
 			if len(id) == 0 {
-				s.lg.Printf("doCompEthInterfacesGetV2(): Invalid component ethernet interface ID.")
+				s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Invalid component ethernet interface ID.")
 				sendJsonError(w, http.StatusBadRequest, "Invalid component ethernet interface ID.")
 				return
 			}
@@ -3566,7 +4290,7 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 	if len(filter.MACAddr) > 0 {
 		for i, mac := range filter.MACAddr {
 			if len(mac) == 0 {
-				s.lg.Printf("doCompEthInterfacesGetV2(): Invalid component ethernet interface MAC address.")
+				s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Invalid component ethernet interface MAC address.")
 				sendJsonError(w, http.StatusBadRequest, "Invalid component ethernet interface MAC address.")
 				return
 			}
@@ -3590,7 +4314,7 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 		for i, xname := range filter.CompID {
 			xnameNorm := xnametypes.VerifyNormalizeCompID(xname)
 			if len(xnameNorm) == 0 && len(xname) != 0 {
-				s.lg.Printf("doCompEthInterfacesGetV2(): Invalid CompID.")
+				s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Invalid CompID.")
 				sendJsonError(w, http.StatusBadRequest, "Invalid CompID.")
 				return
 			}
@@ -3603,7 +4327,7 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 		for i, compType := range filter.Type {
 			compTypeNorm := xnametypes.VerifyNormalizeType(compType)
 			if len(compTypeNorm) == 0 {
-				s.lg.Printf("doCompEthInterfacesGetV2(): Invalid HMS type.")
+				s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Invalid HMS type.")
 				sendJsonError(w, http.StatusBadRequest, "Invalid HMS type.")
 				return
 			}
@@ -3613,7 +4337,7 @@ func (s *SmD) doCompEthInterfacesGetV2(w http.ResponseWriter, r *http.Request) {
 	}
 	ceis, err := s.db.GetCompEthInterfaceFilter(ceiFilter...)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacesGetV2(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacesGetV2(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -3630,7 +4354,7 @@ func (s *SmD) doCompEthInterfacePostV2(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &ceiIn)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacePostV2(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacePostV2(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -3638,7 +4362,7 @@ func (s *SmD) doCompEthInterfacePostV2(w http.ResponseWriter, r *http.Request) {
 
 	mac, err := rf.NormalizeVerifyMAC(ceiIn.MACAddr)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacePostV2(): Invalid MAC address: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacePostV2(): Invalid MAC address: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -3650,7 +4374,7 @@ func (s *SmD) doCompEthInterfacePostV2(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertCompEthInterface(cei)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacePostV2(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompEthInterfacePostV2(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing component ethernet interface that has the same MAC address.")
@@ -3676,7 +4400,7 @@ func (s *SmD) doCompEthInterfaceGetV2(w http.ResponseWriter, r *http.Request) {
 	id := strings.ToLower(chi.URLParam(r, "id"))
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceGetV2(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceGetV2(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
@@ -3684,12 +4408,12 @@ func (s *SmD) doCompEthInterfaceGetV2(w http.ResponseWriter, r *http.Request) {
 
 	ceis, err := s.db.GetCompEthInterfaceFilter(hmsds.CEI_ID(id))
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceGetV2(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceGetV2(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if len(ceis) == 0 {
-		s.lg.Printf("doCompEthInterfaceGetV2(): No such component ethernet interface, %s", id)
+		s.reqLog(r).LogAlways("doCompEthInterfaceGetV2(): No such component ethernet interface, %s", id)
 		sendJsonError(w, http.StatusNotFound, "No such component ethernet interface: "+id)
 		return
 	}
@@ -3709,7 +4433,7 @@ func (s *SmD) doCompEthInterfacePatchV2(w http.ResponseWriter, r *http.Request)
 	id := chi.URLParam(r, "id")
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfacePatchV2(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfacePatchV2(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest, "Invalid id.")
 		return
 	}
@@ -3717,23 +4441,23 @@ func (s *SmD) doCompEthInterfacePatchV2(w http.ResponseWriter, r *http.Request)
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &ceip)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacePatchV2(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacePatchV2(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	if ceip.Desc == nil && ceip.IPAddrs == nil && ceip.CompID == nil {
-		s.lg.Printf("doCompEthInterfacePatchV2(): Request must have at least one patch field.")
+		s.reqLog(r).LogAlways("doCompEthInterfacePatchV2(): Request must have at least one patch field.")
 		sendJsonError(w, http.StatusBadRequest, "Request must have at least one patch field.")
 		return
 	}
 	cei, err := s.db.UpdateCompEthInterface(id, &ceip)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfacePatchV2(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfacePatchV2(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	} else if cei == nil {
-		s.lg.Printf("doCompEthInterfacePatchV2(): no such component ethernet interface.")
+		s.reqLog(r).LogAlways("doCompEthInterfacePatchV2(): no such component ethernet interface.")
 		sendJsonError(w, http.StatusNotFound, "no such component ethernet interface.")
 		return
 	}
@@ -3752,7 +4476,7 @@ func (s *SmD) doCompEthInterfaceIPAddressesGetV2(w http.ResponseWriter, r *http.
 	id := chi.URLParam(r, "id")
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceIPAddressesGetV2(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressesGetV2(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
@@ -3762,12 +4486,12 @@ func (s *SmD) doCompEthInterfaceIPAddressesGetV2(w http.ResponseWriter, r *http.
 	// field and ignore everything else
 	ceis, err := s.db.GetCompEthInterfaceFilter(hmsds.CEI_ID(id))
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressesGetV2(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressesGetV2(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if len(ceis) == 0 {
-		s.lg.Printf("doCompEthInterfaceIPAddressesGetV2(): No such component ethernet interface, %s", id)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressesGetV2(): No such component ethernet interface, %s", id)
 		sendJsonError(w, http.StatusNotFound, "No such component ethernet interface: "+id)
 		return
 	}
@@ -3786,7 +4510,7 @@ func (s *SmD) doCompEthInterfaceIPAddressPostV2(w http.ResponseWriter, r *http.R
 	id := chi.URLParam(r, "id")
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceIPAddressesGetV2(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressesGetV2(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
@@ -3795,7 +4519,7 @@ func (s *SmD) doCompEthInterfaceIPAddressPostV2(w http.ResponseWriter, r *http.R
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &ipAddressIn)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressPostV2(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressPostV2(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -3809,7 +4533,7 @@ func (s *SmD) doCompEthInterfaceIPAddressPostV2(w http.ResponseWriter, r *http.R
 
 	ipID, err := s.db.AddCompEthInterfaceIPAddress(id, ipm)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressPostV2(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressPostV2(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSNoCompEthInterface {
 			sendJsonError(w, http.StatusNotFound, "No such component ethernet interface: "+id)
 		} else if err == hmsds.ErrHMSDSDuplicateKey {
@@ -3837,14 +4561,14 @@ func (s *SmD) doCompEthInterfaceIPAddressPatchV2(w http.ResponseWriter, r *http.
 	ipaddr := chi.URLParam(r, "ipaddr")
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
 	}
 
 	if len(ipaddr) == 0 {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): Invalid ip address.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): Invalid ip address.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid ip address.")
 		return
@@ -3853,18 +4577,18 @@ func (s *SmD) doCompEthInterfaceIPAddressPatchV2(w http.ResponseWriter, r *http.
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &ipmPatch)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressPostV2(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressPostV2(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	ipm, err := s.db.UpdateCompEthInterfaceIPAddress(id, ipaddr, &ipmPatch)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressPatchV2(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressPatchV2(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	} else if ipm == nil {
-		s.lg.Printf("doCompEthInterfaceIPAddressPatchV2(): no such IP address in component ethernet interface.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceIPAddressPatchV2(): no such IP address in component ethernet interface.")
 		sendJsonError(w, http.StatusNotFound, "no such IP address in component ethernet interface.")
 		return
 	}
@@ -3880,14 +4604,14 @@ func (s *SmD) doCompEthInterfaceIPAddressDeleteV2(w http.ResponseWriter, r *http
 	ipAddr := chi.URLParam(r, "ipaddr")
 
 	if len(id) == 0 {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): Invalid id.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): Invalid id.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid id.")
 		return
 	}
 
 	if len(ipAddr) == 0 {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): Invalid ip address.")
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): Invalid ip address.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid ip address.")
 		return
@@ -3895,7 +4619,7 @@ func (s *SmD) doCompEthInterfaceIPAddressDeleteV2(w http.ResponseWriter, r *http
 
 	didDelete, err := s.db.DeleteCompEthInterfaceIPAddress(id, ipAddr)
 	if err != nil {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): delete failure: (%s, %s) %s", id, ipAddr, err)
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): delete failure: (%s, %s) %s", id, ipAddr, err)
 		if err == hmsds.ErrHMSDSNoCompEthInterface {
 			sendJsonError(w, http.StatusNotFound, "No such component ethernet interface: "+id)
 		} else {
@@ -3905,7 +4629,7 @@ func (s *SmD) doCompEthInterfaceIPAddressDeleteV2(w http.ResponseWriter, r *http
 	}
 
 	if !didDelete {
-		s.lg.Printf("doCompEthInterfaceMembersDelete(): No such ip address, %s, in component ethernet interface, %s", ipAddr, id)
+		s.reqLog(r).LogAlways("doCompEthInterfaceMembersDelete(): No such ip address, %s, in component ethernet interface, %s", ipAddr, id)
 		sendJsonError(w, http.StatusNotFound, "component ethernet interface has no such ip address.")
 		return
 	}
@@ -3931,7 +4655,7 @@ func (s *SmD) doDiscoveryStatusGet(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		sendJsonError(w, http.StatusInternalServerError,
 			"Failed due to DB access issue.")
-		s.lg.Printf("GetDiscoveryStatusByID failed: %s: %s", r.RemoteAddr, err)
+		s.reqLog(r).LogAlways("GetDiscoveryStatusByID failed: %s: %s", r.RemoteAddr, err)
 		return
 	}
 	if stat == nil {
@@ -3948,7 +4672,7 @@ func (s *SmD) doDiscoveryStatusGetAll(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		sendJsonError(w, http.StatusInternalServerError,
 			"Failed due to DB access issue.")
-		s.lg.Printf("GetDiscoveryStatusAll failed: %s: %s", r.RemoteAddr, err)
+		s.reqLog(r).LogAlways("GetDiscoveryStatusAll failed: %s: %s", r.RemoteAddr, err)
 		return
 	}
 	sendJsonDiscoveryStatusArrayRsp(w, stats)
@@ -3960,6 +4684,7 @@ func (s *SmD) doInventoryDiscoverPost(w http.ResponseWriter, r *http.Request) {
 
 	var discIn sm.DiscoverIn
 	var id uint = 0
+	var eps []*sm.RedfishEndpoint
 
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &discIn)
@@ -3983,7 +4708,7 @@ func (s *SmD) doInventoryDiscoverPost(w http.ResponseWriter, r *http.Request) {
 			if err != nil {
 				sendJsonError(w, http.StatusInternalServerError,
 					"Failed due to DB access issue.")
-				s.lg.Printf("GetDiscoveryStatusByID failed: %s: %s",
+				s.reqLog(r).LogAlways("GetDiscoveryStatusByID failed: %s: %s",
 					r.RemoteAddr, err)
 				return
 			} else if ep == nil {
@@ -3993,14 +4718,14 @@ func (s *SmD) doInventoryDiscoverPost(w http.ResponseWriter, r *http.Request) {
 			}
 			epsTrimmed = append(epsTrimmed, ep)
 		}
-		go s.discoverFromEndpoints(epsTrimmed, id, false, discIn.Force)
+		eps = epsTrimmed
 	} else {
 		// We had no array, default to discovering all RedfishEndpoints
-		eps, err := s.db.GetRFEndpointsAll()
+		eps, err = s.db.GetRFEndpointsAll()
 		if err != nil {
 			sendJsonError(w, http.StatusInternalServerError,
 				"operation 'POST' failed due to retrieval from DB")
-			s.lg.Printf("GetRFEndpointsAll failed: %s: %s", r.RemoteAddr, err)
+			s.reqLog(r).LogAlways("GetRFEndpointsAll failed: %s: %s", r.RemoteAddr, err)
 			return
 		}
 		if len(eps) == 0 {
@@ -4008,15 +4733,25 @@ func (s *SmD) doInventoryDiscoverPost(w http.ResponseWriter, r *http.Request) {
 				"RedfishEndpoints collection is empty")
 			return
 		}
-		go s.discoverFromEndpoints(eps, id, false, discIn.Force)
 	}
+	ids := make([]string, 0, len(eps))
+	for _, ep := range eps {
+		ids = append(ids, ep.ID)
+	}
+	job := s.discJobs.New(ids)
+	go s.discoverFromEndpointsJob(eps, id, false, discIn.Force, job)
+
 	// We return a link to a set of DiscoveryStatus records.  For now,
 	// we only allow one discovery at once and the entry number is
-	// always fixed.
-	uris := make([]*sm.ResourceURI, 0, 1)
+	// always fixed.  This is followed by a link to the job tracking this
+	// discovery.
+	uris := make([]*sm.ResourceURI, 0, 2)
 	uri := new(sm.ResourceURI)
 	uri.URI = s.invDiscStatusBaseV2 + "/" + strconv.FormatUint(uint64(id), 10)
 	uris = append(uris, uri)
+	if job != nil {
+		uris = append(uris, &sm.ResourceURI{URI: s.invDiscJobBaseV2 + "/" + job.ID()})
+	}
 
 	sendJsonResourceIDArray(w, uris)
 }
@@ -4032,7 +4767,7 @@ func (s *SmD) doGetSCNSubscriptionsAll(w http.ResponseWriter, r *http.Request) {
 
 	subs, err := s.db.GetSCNSubscriptionsAll()
 	if err != nil {
-		s.lg.Printf("doGetSCNSubscriptionsAll(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGetSCNSubscriptionsAll(): Lookup failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 		return
 	}
@@ -4103,8 +4838,23 @@ func (s *SmD) doPostSCNSubscription(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if len(subIn.Reservations) != 0 {
+		foundTrigger = true
+		for i, rs := range subIn.Reservations {
+			res := sm.VerifyNormalizeSCNReservation(rs)
+			if res == "" {
+				sendJsonError(w, http.StatusBadRequest, "Invalid reservation '"+rs+"'")
+				return
+			}
+			subIn.Reservations[i] = res
+		}
+	}
+	if err := verifySCNScope(subIn.Xnames, subIn.Groups, subIn.Partitions); err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if !foundTrigger {
-		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, or State trigger.")
+		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, State, or Reservation trigger.")
 		return
 	}
 
@@ -4115,7 +4865,7 @@ func (s *SmD) doPostSCNSubscription(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.scnSubLock.Unlock()
 		sendJsonError(w, http.StatusBadRequest, "Subscribe failed")
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	}
 	newSub := sm.SCNSubscription{
@@ -4126,6 +4876,10 @@ func (s *SmD) doPostSCNSubscription(w http.ResponseWriter, r *http.Request) {
 		SubRoles:       subIn.SubRoles,
 		SoftwareStatus: subIn.SoftwareStatus,
 		States:         subIn.States,
+		Reservations:   subIn.Reservations,
+		Xnames:         subIn.Xnames,
+		Groups:         subIn.Groups,
+		Partitions:     subIn.Partitions,
 		Url:            subIn.Url,
 	}
 	// Add or update the cached subscription table.
@@ -4138,10 +4892,14 @@ func (s *SmD) doPostSCNSubscription(w http.ResponseWriter, r *http.Request) {
 			addSCNMapSubscription(&s.scnSubMap, &newSub)
 			// Update the subscription array.
 			s.scnSubs.SubscriptionList[i].States = newSub.States
+			s.scnSubs.SubscriptionList[i].Reservations = newSub.Reservations
 			s.scnSubs.SubscriptionList[i].Enabled = newSub.Enabled
 			s.scnSubs.SubscriptionList[i].Roles = newSub.Roles
 			s.scnSubs.SubscriptionList[i].SubRoles = newSub.SubRoles
 			s.scnSubs.SubscriptionList[i].SoftwareStatus = newSub.SoftwareStatus
+			s.scnSubs.SubscriptionList[i].Xnames = newSub.Xnames
+			s.scnSubs.SubscriptionList[i].Groups = newSub.Groups
+			s.scnSubs.SubscriptionList[i].Partitions = newSub.Partitions
 			found = true
 			break
 		}
@@ -4167,7 +4925,7 @@ func (s *SmD) doDeleteSCNSubscriptionsAll(w http.ResponseWriter, r *http.Request
 	if err != nil {
 		s.scnSubLock.Unlock()
 		sendJsonError(w, http.StatusBadRequest, "Unsubscribe failed")
-		s.lg.Printf("failed: %s, Err: %s", r.RemoteAddr, err)
+		s.reqLog(r).LogAlways("failed: %s, Err: %s", r.RemoteAddr, err)
 		return
 	}
 	// Delete all subscriptions from our cached subscription table.
@@ -4195,7 +4953,7 @@ func (s *SmD) doGetSCNSubscription(w http.ResponseWriter, r *http.Request) {
 
 	sub, err := s.db.GetSCNSubscription(id)
 	if err != nil {
-		s.lg.Printf("doGetSCNSubscription(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGetSCNSubscription(): Lookup failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "failed to query DB.")
 		return
 	} else if sub == nil {
@@ -4280,8 +5038,23 @@ func (s *SmD) doPutSCNSubscription(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if len(subIn.Reservations) != 0 {
+		foundTrigger = true
+		for i, rs := range subIn.Reservations {
+			res := sm.VerifyNormalizeSCNReservation(rs)
+			if res == "" {
+				sendJsonError(w, http.StatusBadRequest, "Invalid reservation '"+rs+"'")
+				return
+			}
+			subIn.Reservations[i] = res
+		}
+	}
+	if err := verifySCNScope(subIn.Xnames, subIn.Groups, subIn.Partitions); err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if !foundTrigger {
-		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, or State trigger.")
+		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, State, or Reservation trigger.")
 		return
 	}
 
@@ -4291,7 +5064,7 @@ func (s *SmD) doPutSCNSubscription(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.scnSubLock.Unlock()
 		sendJsonError(w, http.StatusBadRequest, "Subscription update failed")
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	} else if !didUpdate {
 		s.scnSubLock.Unlock()
@@ -4306,6 +5079,10 @@ func (s *SmD) doPutSCNSubscription(w http.ResponseWriter, r *http.Request) {
 		SubRoles:       subIn.SubRoles,
 		SoftwareStatus: subIn.SoftwareStatus,
 		States:         subIn.States,
+		Reservations:   subIn.Reservations,
+		Xnames:         subIn.Xnames,
+		Groups:         subIn.Groups,
+		Partitions:     subIn.Partitions,
 		Url:            subIn.Url,
 	}
 	// Add or update the cached subscription table.
@@ -4318,6 +5095,10 @@ func (s *SmD) doPutSCNSubscription(w http.ResponseWriter, r *http.Request) {
 			addSCNMapSubscription(&s.scnSubMap, &newSub)
 			// Update the subscription array.
 			s.scnSubs.SubscriptionList[i].States = newSub.States
+			s.scnSubs.SubscriptionList[i].Reservations = newSub.Reservations
+			s.scnSubs.SubscriptionList[i].Xnames = newSub.Xnames
+			s.scnSubs.SubscriptionList[i].Groups = newSub.Groups
+			s.scnSubs.SubscriptionList[i].Partitions = newSub.Partitions
 			break
 		}
 	}
@@ -4403,8 +5184,28 @@ func (s *SmD) doPatchSCNSubscription(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if len(patchIn.Reservations) != 0 {
+		foundTrigger = true
+		for i, rs := range patchIn.Reservations {
+			res := sm.VerifyNormalizeSCNReservation(rs)
+			if res == "" {
+				sendJsonError(w, http.StatusBadRequest, "Invalid reservation '"+rs+"'")
+				return
+			}
+			patchIn.Reservations[i] = res
+		}
+	}
+	if err := verifySCNScope(patchIn.Xnames, patchIn.Groups, patchIn.Partitions); err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(patchIn.Xnames) != 0 || len(patchIn.Groups) != 0 ||
+		len(patchIn.Partitions) != 0 {
+		// Only the scope is changing.
+		foundTrigger = true
+	}
 	if !foundTrigger {
-		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Subscriptions must have atleast one Enabled, Role, SubRole, SoftwareStatus, or State trigger.")
+		sendJsonError(w, http.StatusBadRequest, "Missing trigger. Subscriptions must have atleast one Enabled, Role, SubRole, SoftwareStatus, State, or Reservation trigger.")
 		return
 	}
 
@@ -4414,7 +5215,7 @@ func (s *SmD) doPatchSCNSubscription(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.scnSubLock.Unlock()
 		sendJsonError(w, http.StatusBadRequest, "Subscription patch failed")
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		return
 	} else if !didPatch {
 		s.scnSubLock.Unlock()
@@ -4496,6 +5297,24 @@ func (s *SmD) doPatchSCNSubscription(w http.ResponseWriter, r *http.Request) {
 					newSub.Enabled = patchIn.Enabled
 					s.scnSubs.SubscriptionList[i].Enabled = patchIn.Enabled
 				}
+				for _, newRes := range patchIn.Reservations {
+					match := false
+					for _, res := range sub.Reservations {
+						if res == newRes {
+							match = true
+							break
+						}
+					}
+					if !match {
+						newSub.Reservations = append(newSub.Reservations, newRes)
+						s.scnSubs.SubscriptionList[i].Reservations = append(s.scnSubs.SubscriptionList[i].Reservations, newRes)
+					}
+				}
+				// Scopes are not in the subscription map.
+				cached := &s.scnSubs.SubscriptionList[i]
+				cached.Xnames = sm.AddSCNScope(cached.Xnames, patchIn.Xnames)
+				cached.Groups = sm.AddSCNScope(cached.Groups, patchIn.Groups)
+				cached.Partitions = sm.AddSCNScope(cached.Partitions, patchIn.Partitions)
 				addSCNMapSubscription(&s.scnSubMap, &newSub)
 			case sm.PatchOpRemove:
 				// Find out which values in the request are in our
@@ -4543,6 +5362,19 @@ func (s *SmD) doPatchSCNSubscription(w http.ResponseWriter, r *http.Request) {
 					newSub.Enabled = patchIn.Enabled
 					*s.scnSubs.SubscriptionList[i].Enabled = false
 				}
+				for _, newRes := range patchIn.Reservations {
+					for _, res := range sub.Reservations {
+						if res == newRes {
+							newSub.Reservations = append(newSub.Reservations, newRes)
+							break
+						}
+					}
+				}
+				s.scnSubs.SubscriptionList[i].Reservations = sm.RemoveSCNScope(s.scnSubs.SubscriptionList[i].Reservations, newSub.Reservations)
+				cached := &s.scnSubs.SubscriptionList[i]
+				cached.Xnames = sm.RemoveSCNScope(cached.Xnames, patchIn.Xnames)
+				cached.Groups = sm.RemoveSCNScope(cached.Groups, patchIn.Groups)
+				cached.Partitions = sm.RemoveSCNScope(cached.Partitions, patchIn.Partitions)
 				removeSCNMapSubscription(&s.scnSubMap, &newSub)
 			case sm.PatchOpReplace:
 				removeSCNMapSubscription(&s.scnSubMap, &sub)
@@ -4558,6 +5390,18 @@ func (s *SmD) doPatchSCNSubscription(w http.ResponseWriter, r *http.Request) {
 				if len(patchIn.SoftwareStatus) > 0 {
 					s.scnSubs.SubscriptionList[i].SoftwareStatus = patchIn.SoftwareStatus
 				}
+				if len(patchIn.Reservations) > 0 {
+					s.scnSubs.SubscriptionList[i].Reservations = patchIn.Reservations
+				}
+				if len(patchIn.Xnames) > 0 {
+					s.scnSubs.SubscriptionList[i].Xnames = patchIn.Xnames
+				}
+				if len(patchIn.Groups) > 0 {
+					s.scnSubs.SubscriptionList[i].Groups = patchIn.Groups
+				}
+				if len(patchIn.Partitions) > 0 {
+					s.scnSubs.SubscriptionList[i].Partitions = patchIn.Partitions
+				}
 				if patchIn.Enabled != nil {
 					s.scnSubs.SubscriptionList[i].Enabled = patchIn.Enabled
 				}
@@ -4600,7 +5444,7 @@ func (s *SmD) doDeleteSCNSubscription(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		s.scnSubLock.Unlock()
 		sendJsonError(w, http.StatusBadRequest, "Unsubscribe failed")
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, idStr, err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, idStr, err)
 		return
 	}
 	// Delete the subscription from our cached subscription table.
@@ -4628,6 +5472,55 @@ func (s *SmD) doDeleteSCNSubscription(w http.ResponseWriter, r *http.Request) {
  * HSM Groups API
  */
 
+// Parse the optional recursive query parameter of the group GET calls.
+func parseGroupRecursive(f *GrpPartFltr) (bool, error) {
+	if len(f.Recursive) == 0 {
+		return false, nil
+	}
+	recursive, err := strconv.ParseBool(f.Recursive[0])
+	if err != nil {
+		return false, errors.New("Invalid recursive value, must be true or false.")
+	}
+	return recursive, nil
+}
+
+// Fill in the child groups of a group.  If recursive, the members of all of
+// its descendant groups (filtered by part, if given) are added to its own.
+func (s *SmD) getGroupTree(group *sm.Group, part string, recursive bool) error {
+	children, err := s.db.GetGroupChildren(group.Label, false)
+	if err != nil {
+		return err
+	}
+	group.Children = children
+	if !recursive || len(children) == 0 {
+		return nil
+	}
+	desc, err := s.db.GetGroupChildren(group.Label, true)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(group.Members.IDs))
+	for _, id := range group.Members.IDs {
+		seen[id] = true
+	}
+	for _, label := range desc {
+		child, err := s.db.GetGroup(label, part)
+		if err != nil {
+			return err
+		} else if child == nil {
+			// Deleted since we looked
+			continue
+		}
+		for _, id := range child.Members.IDs {
+			if !seen[id] {
+				seen[id] = true
+				group.Members.IDs = append(group.Members.IDs, id)
+			}
+		}
+	}
+	return nil
+}
+
 // Get all groups that currently exist, optionally filtering the set, returning
 // an array of groups.
 func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
@@ -4636,21 +5529,21 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 	var err error
 	groups := make([]sm.Group, 0)
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doGroupsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doGroupsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doGroupsGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupsGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	groupFilter := new(GrpPartFltr)
 	if err = json.Unmarshal(formJSON, groupFilter); err != nil {
-		s.lg.Printf("doGroupsGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupsGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -4661,17 +5554,23 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 		if part != "NULL" {
 			part = sm.NormalizeGroupField(part)
 			if sm.VerifyGroupField(part) != nil {
-				s.lg.Printf("doGroupsGet(): Invalid partition name.")
+				s.reqLog(r).LogAlways("doGroupsGet(): Invalid partition name.")
 				sendJsonError(w, http.StatusBadRequest,
 					"Invalid partition name.")
 				return
 			}
 		}
 	}
+	recursive, err := parseGroupRecursive(groupFilter)
+	if err != nil {
+		s.reqLog(r).LogAlways("doGroupsGet(): Invalid recursive value.")
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	for i, tag := range groupFilter.Tag {
 		tag = sm.NormalizeGroupField(tag)
 		if sm.VerifyGroupField(tag) != nil {
-			s.lg.Printf("doGroupsGet(): Invalid tag.")
+			s.reqLog(r).LogAlways("doGroupsGet(): Invalid tag.")
 			sendJsonError(w, http.StatusBadRequest,
 				"Invalid tag.")
 			return
@@ -4681,7 +5580,7 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 	for i, label := range groupFilter.Group {
 		label = sm.NormalizeGroupField(label)
 		if sm.VerifyGroupField(label) != nil {
-			s.lg.Printf("doGroupsGet(): Invalid group label.")
+			s.reqLog(r).LogAlways("doGroupsGet(): Invalid group label.")
 			sendJsonError(w, http.StatusBadRequest,
 				"Invalid group label.")
 			return
@@ -4691,7 +5590,7 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 	// TODO: Make this one db call. Not in the initial implementation.
 	labels, err := s.db.GetGroupLabels()
 	if err != nil {
-		s.lg.Printf("doGroupsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGroupsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -4712,7 +5611,7 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 		}
 		group, err := s.db.GetGroup(label, part)
 		if err != nil {
-			s.lg.Printf("doGroupsGet(): Lookup failure: %s", err)
+			s.reqLog(r).LogAlways("doGroupsGet(): Lookup failure: %s", err)
 			sendJsonDBError(w, "bad query param: ", "", err)
 			return
 		}
@@ -4736,6 +5635,11 @@ func (s *SmD) doGroupsGet(w http.ResponseWriter, r *http.Request) {
 		if !foundTag {
 			continue
 		}
+		if err := s.getGroupTree(group, part, recursive); err != nil {
+			s.reqLog(r).LogAlways("doGroupsGet(): Lookup failure: %s", err)
+			sendJsonDBError(w, "bad query param: ", "", err)
+			return
+		}
 		groups = append(groups, *group)
 	}
 	sendJsonGroupArrayRsp(w, &groups)
@@ -4753,7 +5657,7 @@ func (s *SmD) doGroupsPost(w http.ResponseWriter, r *http.Request) {
 	body, _ := io.ReadAll(r.Body)
 	err := json.Unmarshal(body, &groupIn)
 	if err != nil {
-		s.lg.Printf("doGroupsPost(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doGroupsPost(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -4765,20 +5669,39 @@ func (s *SmD) doGroupsPost(w http.ResponseWriter, r *http.Request) {
 		groupIn.Tags,
 		groupIn.Members.IDs)
 	if err != nil {
-		s.lg.Printf("doGroupsPost(): Couldn't validate group: %s", err)
+		s.reqLog(r).LogAlways("doGroupsPost(): Couldn't validate group: %s", err)
+		sendJsonError(w, http.StatusBadRequest,
+			"couldn't validate group: "+err.Error())
+		return
+	}
+	if group.Label != groupIn.Label {
+		addWarning(w, "group label '%s' normalized to '%s'",
+			groupIn.Label, group.Label)
+	}
+	if group.ExclusiveGroup != groupIn.ExclusiveGroup {
+		addWarning(w, "exclusiveGroup '%s' normalized to '%s'",
+			groupIn.ExclusiveGroup, group.ExclusiveGroup)
+	}
+	for _, child := range groupIn.Children {
+		group.Children = append(group.Children, sm.NormalizeGroupField(child))
+	}
+	if err := sm.VerifyGroupChildren(group.Label, group.Children); err != nil {
+		s.reqLog(r).LogAlways("doGroupsPost(): Couldn't validate group: %s", err)
 		sendJsonError(w, http.StatusBadRequest,
 			"couldn't validate group: "+err.Error())
 		return
 	}
-	label, err := s.db.InsertGroup(group)
+	label, err := s.db.WithActor(s.requestActor(r)).InsertGroup(group)
 	if err != nil {
-		s.lg.Printf("doGroupsPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doGroupsPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing group that has the same label or duplicate ids found in request.")
 		} else if err == hmsds.ErrHMSDSExclusiveGroup {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing member in another exclusive group.")
+		} else if err == hmsds.ErrHMSDSGroupCycle {
+			sendJsonError(w, http.StatusConflict, err.Error())
 		} else {
 			// Send this message as 500 or 400 plus error message if it is
 			// an HMSError and not, e.g. an internal DB error code.
@@ -4801,27 +5724,27 @@ func (s *SmD) doGroupGet(w http.ResponseWriter, r *http.Request) {
 	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupGet(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupGet(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
 	}
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doGroupGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doGroupGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doGroupGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	groupFilter := new(GrpPartFltr)
 	if err = json.Unmarshal(formJSON, groupFilter); err != nil {
-		s.lg.Printf("doGroupGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -4832,24 +5755,35 @@ func (s *SmD) doGroupGet(w http.ResponseWriter, r *http.Request) {
 		if part != "NULL" {
 			part = sm.NormalizeGroupField(part)
 			if sm.VerifyGroupField(part) != nil {
-				s.lg.Printf("doGroupGet(): Invalid partition name.")
+				s.reqLog(r).LogAlways("doGroupGet(): Invalid partition name.")
 				sendJsonError(w, http.StatusBadRequest,
 					"Invalid partition name.")
 				return
 			}
 		}
 	}
+	recursive, err := parseGroupRecursive(groupFilter)
+	if err != nil {
+		s.reqLog(r).LogAlways("doGroupGet(): Invalid recursive value.")
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	group, err := s.db.GetGroup(label, part)
 	if err != nil {
-		s.lg.Printf("doGroupGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGroupGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if group == nil {
-		s.lg.Printf("doGroupGet(): No such group, %s", label)
+		s.reqLog(r).LogAlways("doGroupGet(): No such group, %s", label)
 		sendJsonError(w, http.StatusNotFound, "No such group: "+label)
 		return
 	}
+	if err := s.getGroupTree(group, part, recursive); err != nil {
+		s.reqLog(r).LogAlways("doGroupGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "bad query param: ", "", err)
+		return
+	}
 
 	sendJsonGroupRsp(w, group)
 
@@ -4863,19 +5797,19 @@ func (s *SmD) doGroupDelete(w http.ResponseWriter, r *http.Request) {
 	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupDelete(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupDelete(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
 	}
-	didDelete, err := s.db.DeleteGroup(label)
+	didDelete, err := s.db.WithActor(s.requestActor(r)).DeleteGroup(label)
 	if err != nil {
-		s.lg.Printf("doGroupDelete(): delete failure: (%s) %s", label, err)
+		s.reqLog(r).LogAlways("doGroupDelete(): delete failure: (%s) %s", label, err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
 	if !didDelete {
-		s.lg.Printf("doGroupDelete(): No such group, %s", label)
+		s.reqLog(r).LogAlways("doGroupDelete(): No such group, %s", label)
 		sendJsonError(w, http.StatusNotFound, "no such group.")
 		return
 	}
@@ -4899,19 +5833,20 @@ func (s *SmD) doGroupPatch(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &groupPatch)
 	if err != nil {
-		s.lg.Printf("doGroupPatch(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doGroupPatch(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
-	if groupPatch.Description == nil && groupPatch.Tags == nil {
-		s.lg.Printf("doGroupPatch(): Request must have at least one patch field.")
+	if groupPatch.Description == nil && groupPatch.Tags == nil &&
+		groupPatch.Children == nil {
+		s.reqLog(r).LogAlways("doGroupPatch(): Request must have at least one patch field.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Request must have at least one patch field.")
 		return
 	}
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupPatch(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupPatch(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
@@ -4920,18 +5855,30 @@ func (s *SmD) doGroupPatch(w http.ResponseWriter, r *http.Request) {
 		for _, tag := range *groupPatch.Tags {
 			tagNorm := sm.NormalizeGroupField(tag)
 			if sm.VerifyGroupField(tagNorm) != nil {
-				s.lg.Printf("doGroupPatch(): Invalid tag.")
+				s.reqLog(r).LogAlways("doGroupPatch(): Invalid tag.")
 				sendJsonError(w, http.StatusBadRequest,
 					"Invalid tag.")
 				return
 			}
 		}
 	}
+	if groupPatch.Children != nil {
+		for _, child := range *groupPatch.Children {
+			if sm.NormalizeGroupField(child) == label {
+				s.reqLog(r).LogAlways("doGroupPatch(): Group is its own child.")
+				sendJsonError(w, http.StatusBadRequest,
+					sm.ErrGroupSelfChild.Error())
+				return
+			}
+		}
+	}
 	err = s.db.UpdateGroup(label, &groupPatch)
 	if err != nil {
-		s.lg.Printf("doGroupPatch(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGroupPatch(): Lookup failure: %s", err)
 		if err == hmsds.ErrHMSDSNoGroup {
 			sendJsonError(w, http.StatusNotFound, "no such group.")
+		} else if err == hmsds.ErrHMSDSGroupCycle {
+			sendJsonError(w, http.StatusConflict, err.Error())
 		} else {
 			sendJsonDBError(w, "bad query param: ", "", err)
 		}
@@ -4949,7 +5896,7 @@ func (s *SmD) doGroupLabelsGet(w http.ResponseWriter, r *http.Request) {
 
 	labels, err := s.db.GetGroupLabels()
 	if err != nil {
-		s.lg.Printf("doGroupLabelsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGroupLabelsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -4967,28 +5914,28 @@ func (s *SmD) doGroupMembersGet(w http.ResponseWriter, r *http.Request) {
 	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupMembersGet(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupMembersGet(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
 	}
 
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doGroupMembersGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doGroupMembersGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doGroupMembersGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupMembersGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	groupFilter := new(GrpPartFltr)
 	if err = json.Unmarshal(formJSON, groupFilter); err != nil {
-		s.lg.Printf("doGroupMembersGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doGroupMembersGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -4999,24 +5946,37 @@ func (s *SmD) doGroupMembersGet(w http.ResponseWriter, r *http.Request) {
 		if part != "NULL" {
 			part = sm.NormalizeGroupField(groupFilter.Partition[0])
 			if sm.VerifyGroupField(part) != nil {
-				s.lg.Printf("doGroupsGet(): Invalid partition name.")
+				s.reqLog(r).LogAlways("doGroupsGet(): Invalid partition name.")
 				sendJsonError(w, http.StatusBadRequest,
 					"Invalid partition name.")
 				return
 			}
 		}
 	}
+	recursive, err := parseGroupRecursive(groupFilter)
+	if err != nil {
+		s.reqLog(r).LogAlways("doGroupMembersGet(): Invalid recursive value.")
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	group, err := s.db.GetGroup(label, part)
 	if err != nil {
-		s.lg.Printf("doGroupMembersGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doGroupMembersGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if group == nil {
-		s.lg.Printf("doGroupMembersGet(): No such group, %s", label)
+		s.reqLog(r).LogAlways("doGroupMembersGet(): No such group, %s", label)
 		sendJsonError(w, http.StatusNotFound, "No such group: "+label)
 		return
 	}
+	if recursive {
+		if err := s.getGroupTree(group, part, true); err != nil {
+			s.reqLog(r).LogAlways("doGroupMembersGet(): Lookup failure: %s", err)
+			sendJsonDBError(w, "bad query param: ", "", err)
+			return
+		}
+	}
 
 	sendJsonMembersRsp(w, &group.Members)
 
@@ -5032,7 +5992,7 @@ func (s *SmD) doGroupMembersPost(w http.ResponseWriter, r *http.Request) {
 	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupMemberPost(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupMemberPost(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
@@ -5040,20 +6000,20 @@ func (s *SmD) doGroupMembersPost(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &memberIn)
 	if err != nil {
-		s.lg.Printf("doGroupMemberPost(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doGroupMemberPost(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	normID := xnametypes.NormalizeHMSCompID(memberIn.ID)
 	if !xnametypes.IsHMSCompIDValid(normID) {
-		s.lg.Printf("doGroupMemberPost(): Invalid xname ID.")
+		s.reqLog(r).LogAlways("doGroupMemberPost(): Invalid xname ID.")
 		sendJsonError(w, http.StatusBadRequest, "invalid xname ID")
 		return
 	}
-	id, err := s.db.AddGroupMember(label, normID)
+	id, err := s.db.WithActor(s.requestActor(r)).AddGroupMember(label, normID)
 	if err != nil {
-		s.lg.Printf("doGroupMemberPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doGroupMemberPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSNoGroup {
 			sendJsonError(w, http.StatusNotFound, "No such group: "+label)
 		} else if err == hmsds.ErrHMSDSExclusiveGroup {
@@ -5086,7 +6046,7 @@ func (s *SmD) doGroupMembersPut(w http.ResponseWriter, r *http.Request) {
 	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupMemberPut(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupMemberPut(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
@@ -5094,7 +6054,7 @@ func (s *SmD) doGroupMembersPut(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &membersIn)
 	if err != nil {
-		s.lg.Printf("doGroupMemberPut(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doGroupMemberPut(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -5104,7 +6064,7 @@ func (s *SmD) doGroupMembersPut(w http.ResponseWriter, r *http.Request) {
 	for _, compID := range membersIn.IDs {
 		normID := xnametypes.NormalizeHMSCompID(compID)
 		if !xnametypes.IsHMSCompIDValid(normID) {
-			s.lg.Printf("doGroupMemberPost(): Invalid xname ID: %s", compID)
+			s.reqLog(r).LogAlways("doGroupMemberPost(): Invalid xname ID: %s", compID)
 			invalidCompIDs = append(invalidCompIDs, compID)
 		} else {
 			validCompIDs = append(validCompIDs, normID)
@@ -5114,9 +6074,9 @@ func (s *SmD) doGroupMembersPut(w http.ResponseWriter, r *http.Request) {
 		sendJsonError(w, http.StatusBadRequest, fmt.Sprintf("invalid xname IDs: %v", invalidCompIDs))
 		return
 	}
-	ids, err := s.db.SetGroupMembers(label, validCompIDs)
+	ids, err := s.db.WithActor(s.requestActor(r)).SetGroupMembers(label, validCompIDs)
 	if err != nil {
-		s.lg.Printf("doGroupMemberPut(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doGroupMemberPut(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSNoGroup {
 			sendJsonError(w, http.StatusNotFound, "No such group: "+label)
 		} else if err == hmsds.ErrHMSDSExclusiveGroup {
@@ -5146,19 +6106,19 @@ func (s *SmD) doGroupMemberDelete(w http.ResponseWriter, r *http.Request) {
 	id := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname_id"))
 
 	if sm.VerifyGroupField(label) != nil {
-		s.lg.Printf("doGroupMemberDelete(): Invalid group label.")
+		s.reqLog(r).LogAlways("doGroupMemberDelete(): Invalid group label.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid group label.")
 		return
 	}
 	if !xnametypes.IsHMSCompIDValid(id) {
-		s.lg.Printf("doGroupMemberDelete(): Invalid xname ID.")
+		s.reqLog(r).LogAlways("doGroupMemberDelete(): Invalid xname ID.")
 		sendJsonError(w, http.StatusBadRequest, "invalid xname ID")
 		return
 	}
-	didDelete, err := s.db.DeleteGroupMember(label, id)
+	didDelete, err := s.db.WithActor(s.requestActor(r)).DeleteGroupMember(label, id)
 	if err != nil {
-		s.lg.Printf("doGroupMemberDelete(): delete failure: (%s, %s) %s", label, id, err)
+		s.reqLog(r).LogAlways("doGroupMemberDelete(): delete failure: (%s, %s) %s", label, id, err)
 		if err == hmsds.ErrHMSDSNoGroup {
 			sendJsonError(w, http.StatusNotFound, "No such group: "+label)
 		} else {
@@ -5167,7 +6127,7 @@ func (s *SmD) doGroupMemberDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !didDelete {
-		s.lg.Printf("doGroupMemberDelete(): No such member, %s, in group, %s", id, label)
+		s.reqLog(r).LogAlways("doGroupMemberDelete(): No such member, %s, in group, %s", id, label)
 		sendJsonError(w, http.StatusNotFound, "group has no such member.")
 		return
 	}
@@ -5175,6 +6135,46 @@ func (s *SmD) doGroupMemberDelete(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// Parse the id, starttime and endtime query parameters of a group or
+// partition history GET.
+func parseMemberHistFilter(r *http.Request) (*hmsds.MemberHistFilter, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	f := new(hmsds.MemberHistFilter)
+	f.ID = r.Form["id"]
+	f.StartTime = r.Form.Get("starttime")
+	f.EndTime = r.Form.Get("endtime")
+	return f, nil
+}
+
+// Get the membership history of a group, i.e. who added or removed which
+// members and when.  This is kept after the group is deleted.
+func (s *SmD) doGroupHistoryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	label := sm.NormalizeGroupField(chi.URLParam(r, "group_label"))
+	if sm.VerifyGroupField(label) != nil {
+		s.reqLog(r).LogAlways("doGroupHistoryGet(): Invalid group label.")
+		sendJsonError(w, http.StatusBadRequest, "Invalid group label.")
+		return
+	}
+	f, err := parseMemberHistFilter(r)
+	if err != nil {
+		s.reqLog(r).LogAlways("doGroupHistoryGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	hist, err := s.db.GetGroupHist(label, f)
+	if err != nil {
+		s.reqLog(r).LogAlways("doGroupHistoryGet(): Lookup failure: (%s) %s", label, err)
+		sendJsonDBError(w, "bad query param: ", "DB query failed.", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.GroupHistory{Label: label, History: hist})
+}
+
 /*
  * HSM Partitions API
  */
@@ -5187,21 +6187,21 @@ func (s *SmD) doPartitionsGet(w http.ResponseWriter, r *http.Request) {
 	var err error
 	partitions := make([]sm.Partition, 0)
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doPartitionsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doPartitionsGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	filter := new(GrpPartFltr)
 	if err = json.Unmarshal(formJSON, filter); err != nil {
-		s.lg.Printf("doPartitionsGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -5209,7 +6209,7 @@ func (s *SmD) doPartitionsGet(w http.ResponseWriter, r *http.Request) {
 	for i, tag := range filter.Tag {
 		tagNorm := sm.NormalizeGroupField(tag)
 		if sm.VerifyGroupField(tagNorm) != nil {
-			s.lg.Printf("doPartitionsGet(): Invalid tag.")
+			s.reqLog(r).LogAlways("doPartitionsGet(): Invalid tag.")
 			sendJsonError(w, http.StatusBadRequest,
 				"Invalid tag.")
 			return
@@ -5219,7 +6219,7 @@ func (s *SmD) doPartitionsGet(w http.ResponseWriter, r *http.Request) {
 	for i, partition := range filter.Partition {
 		partNorm := sm.NormalizeGroupField(partition)
 		if sm.VerifyGroupField(partNorm) != nil {
-			s.lg.Printf("doPartitionsGet(): Invalid partition name.")
+			s.reqLog(r).LogAlways("doPartitionsGet(): Invalid partition name.")
 			sendJsonError(w, http.StatusBadRequest,
 				"Invalid partition name.")
 			return
@@ -5229,7 +6229,7 @@ func (s *SmD) doPartitionsGet(w http.ResponseWriter, r *http.Request) {
 	// TODO: Make this one db call. Not in the initial implementation.
 	pnames, err := s.db.GetPartitionNames()
 	if err != nil {
-		s.lg.Printf("doPartitionsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -5250,7 +6250,7 @@ func (s *SmD) doPartitionsGet(w http.ResponseWriter, r *http.Request) {
 		}
 		partition, err := s.db.GetPartition(pname)
 		if err != nil {
-			s.lg.Printf("doPartitionsGet(): Lookup failure: %s", err)
+			s.reqLog(r).LogAlways("doPartitionsGet(): Lookup failure: %s", err)
 			sendJsonDBError(w, "bad query param: ", "", err)
 			return
 		}
@@ -5292,7 +6292,7 @@ func (s *SmD) doPartitionsPost(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &partIn)
 	if err != nil {
-		s.lg.Printf("doPartitionsPost(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsPost(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -5303,14 +6303,18 @@ func (s *SmD) doPartitionsPost(w http.ResponseWriter, r *http.Request) {
 		partIn.Tags,
 		partIn.Members.IDs)
 	if err != nil {
-		s.lg.Printf("doPartitionsPost(): Couldn't validate partition: %s", err)
+		s.reqLog(r).LogAlways("doPartitionsPost(): Couldn't validate partition: %s", err)
 		sendJsonError(w, http.StatusBadRequest,
 			"couldn't validate partition: "+err.Error())
 		return
 	}
-	name, err := s.db.InsertPartition(part)
+	if part.Name != partIn.Name {
+		addWarning(w, "partition name '%s' normalized to '%s'",
+			partIn.Name, part.Name)
+	}
+	name, err := s.db.WithActor(s.requestActor(r)).InsertPartition(part)
 	if err != nil {
-		s.lg.Printf("doPartitionsPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doPartitionsPost(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		if err == hmsds.ErrHMSDSDuplicateKey {
 			sendJsonError(w, http.StatusConflict, "operation would conflict "+
 				"with an existing partition that has the same name.")
@@ -5339,7 +6343,7 @@ func (s *SmD) doPartitionGet(w http.ResponseWriter, r *http.Request) {
 	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionGet(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionGet(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
@@ -5347,12 +6351,12 @@ func (s *SmD) doPartitionGet(w http.ResponseWriter, r *http.Request) {
 
 	part, err := s.db.GetPartition(name)
 	if err != nil {
-		s.lg.Printf("doPartitionGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPartitionGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if part == nil {
-		s.lg.Printf("doPartitionGet(): No such partition, %s", name)
+		s.reqLog(r).LogAlways("doPartitionGet(): No such partition, %s", name)
 		sendJsonError(w, http.StatusNotFound, "No such partition: "+name)
 		return
 	}
@@ -5369,19 +6373,19 @@ func (s *SmD) doPartitionDelete(w http.ResponseWriter, r *http.Request) {
 	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionDelete(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionDelete(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
 	}
-	didDelete, err := s.db.DeletePartition(name)
+	didDelete, err := s.db.WithActor(s.requestActor(r)).DeletePartition(name)
 	if err != nil {
-		s.lg.Printf("doPartitionDelete(): delete failure: (%s) %s", name, err)
+		s.reqLog(r).LogAlways("doPartitionDelete(): delete failure: (%s) %s", name, err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
 	if !didDelete {
-		s.lg.Printf("doPartitionDelete(): No such partition, %s", name)
+		s.reqLog(r).LogAlways("doPartitionDelete(): No such partition, %s", name)
 		sendJsonError(w, http.StatusNotFound, "no such partition.")
 		return
 	}
@@ -5403,7 +6407,7 @@ func (s *SmD) doPartitionPatch(w http.ResponseWriter, r *http.Request) {
 	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionPatch(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionPatch(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
@@ -5412,19 +6416,19 @@ func (s *SmD) doPartitionPatch(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &partPatch)
 	if err != nil {
-		s.lg.Printf("doPartitionPatch(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doPartitionPatch(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	if partPatch.Description == nil && partPatch.Tags == nil {
-		s.lg.Printf("doPartitionPatch(): Request must have at least one patch field.")
+		s.reqLog(r).LogAlways("doPartitionPatch(): Request must have at least one patch field.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Request must have at least one patch field.")
 		return
 	}
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionPatch(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionPatch(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
@@ -5433,7 +6437,7 @@ func (s *SmD) doPartitionPatch(w http.ResponseWriter, r *http.Request) {
 		for _, tag := range *partPatch.Tags {
 			tagNorm := sm.NormalizeGroupField(tag)
 			if sm.VerifyGroupField(tagNorm) != nil {
-				s.lg.Printf("doPartitionPatch(): Invalid tag.")
+				s.reqLog(r).LogAlways("doPartitionPatch(): Invalid tag.")
 				sendJsonError(w, http.StatusBadRequest,
 					"Invalid tag.")
 				return
@@ -5442,7 +6446,7 @@ func (s *SmD) doPartitionPatch(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.UpdatePartition(name, &partPatch)
 	if err != nil {
-		s.lg.Printf("doPartitionPatch(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPartitionPatch(): Lookup failure: %s", err)
 		if err == hmsds.ErrHMSDSNoPartition {
 			sendJsonError(w, http.StatusNotFound, "no such partition.")
 		} else {
@@ -5462,7 +6466,7 @@ func (s *SmD) doPartitionNamesGet(w http.ResponseWriter, r *http.Request) {
 
 	names, err := s.db.GetPartitionNames()
 	if err != nil {
-		s.lg.Printf("doPartitionNamesGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPartitionNamesGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -5480,7 +6484,7 @@ func (s *SmD) doPartitionMembersGet(w http.ResponseWriter, r *http.Request) {
 	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionMembersGet(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionMembersGet(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
@@ -5488,12 +6492,12 @@ func (s *SmD) doPartitionMembersGet(w http.ResponseWriter, r *http.Request) {
 
 	part, err := s.db.GetPartition(name)
 	if err != nil {
-		s.lg.Printf("doPartitionMembersGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPartitionMembersGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if part == nil {
-		s.lg.Printf("doPartitionMembersGet(): No such partition, %s", name)
+		s.reqLog(r).LogAlways("doPartitionMembersGet(): No such partition, %s", name)
 		sendJsonError(w, http.StatusNotFound, "No such partition: "+name)
 		return
 	}
@@ -5513,7 +6517,7 @@ func (s *SmD) doPartitionMembersPost(w http.ResponseWriter, r *http.Request) {
 	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionMembersPost(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionMembersPost(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
@@ -5521,7 +6525,7 @@ func (s *SmD) doPartitionMembersPost(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &memberIn)
 	if err != nil {
-		s.lg.Printf("doPartitionMembersPost(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doPartitionMembersPost(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
@@ -5532,14 +6536,14 @@ func (s *SmD) doPartitionMembersPost(w http.ResponseWriter, r *http.Request) {
 		// OpenCHAMI allows for any string.
 		normID = xnametypes.NormalizeHMSCompID(memberIn.ID)
 		if !xnametypes.IsHMSCompIDValid(normID) {
-			s.lg.Printf("doPartitionMembersPost(): Invalid xname ID.")
+			s.reqLog(r).LogAlways("doPartitionMembersPost(): Invalid xname ID.")
 			sendJsonError(w, http.StatusBadRequest, "invalid xname ID")
 			return
 		}
 	}
-	id, err := s.db.AddPartitionMember(name, normID)
+	id, err := s.db.WithActor(s.requestActor(r)).AddPartitionMember(name, normID)
 	if err != nil {
-		s.lg.Printf("doPartitionMembersPost(): %s %s Err: %s", r.RemoteAddr,
+		s.reqLog(r).LogAlways("doPartitionMembersPost(): %s %s Err: %s", r.RemoteAddr,
 			string(body), err)
 		if err == hmsds.ErrHMSDSNoPartition {
 			sendJsonError(w, http.StatusNotFound, "No such partition: "+name)
@@ -5570,19 +6574,19 @@ func (s *SmD) doPartitionMemberDelete(w http.ResponseWriter, r *http.Request) {
 	id := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname_id"))
 
 	if sm.VerifyGroupField(name) != nil {
-		s.lg.Printf("doPartitionMemberDelete(): Invalid partition name.")
+		s.reqLog(r).LogAlways("doPartitionMemberDelete(): Invalid partition name.")
 		sendJsonError(w, http.StatusBadRequest,
 			"Invalid partition name.")
 		return
 	}
 	if !xnametypes.IsHMSCompIDValid(id) {
-		s.lg.Printf("doPartitionMemberDelete(): Invalid xname ID.")
+		s.reqLog(r).LogAlways("doPartitionMemberDelete(): Invalid xname ID.")
 		sendJsonError(w, http.StatusBadRequest, "invalid xname ID")
 		return
 	}
-	didDelete, err := s.db.DeletePartitionMember(name, id)
+	didDelete, err := s.db.WithActor(s.requestActor(r)).DeletePartitionMember(name, id)
 	if err != nil {
-		s.lg.Printf("doPartitionMemberDelete(): delete failure: (%s, %s) %s", name, id, err)
+		s.reqLog(r).LogAlways("doPartitionMemberDelete(): delete failure: (%s, %s) %s", name, id, err)
 		if err == hmsds.ErrHMSDSNoPartition {
 			sendJsonError(w, http.StatusNotFound, "No such partition: "+name)
 		} else {
@@ -5591,7 +6595,7 @@ func (s *SmD) doPartitionMemberDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !didDelete {
-		s.lg.Printf("doPartitionMemberDelete(): No such member, %s, in partition, %s", id, name)
+		s.reqLog(r).LogAlways("doPartitionMemberDelete(): No such member, %s, in partition, %s", id, name)
 		sendJsonError(w, http.StatusNotFound, "partition has no such member.")
 		return
 	}
@@ -5599,6 +6603,79 @@ func (s *SmD) doPartitionMemberDelete(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// Move a set of members from one partition to another in a single
+// transaction, so they are never unassigned in between.
+func (s *SmD) doPartitionsMovePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	var move sm.PartitionMove
+
+	body, err := ioutil.ReadAll(r.Body)
+	err = json.Unmarshal(body, &move)
+	if err != nil {
+		s.reqLog(r).LogAlways("doPartitionsMovePost(): Unmarshal body: %s", err)
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	move.Normalize()
+	if err := move.Verify(); err != nil {
+		s.reqLog(r).LogAlways("doPartitionsMovePost(): Couldn't validate request: %s", err)
+		sendJsonError(w, http.StatusBadRequest,
+			"couldn't validate request: "+err.Error())
+		return
+	}
+	ids, err := s.db.WithActor(s.requestActor(r)).MovePartitionMembers(move.From,
+		move.To, move.IDs)
+	if err != nil {
+		s.reqLog(r).LogAlways("doPartitionsMovePost(): %s %s Err: %s", r.RemoteAddr,
+			string(body), err)
+		if err == hmsds.ErrHMSDSNoPartition {
+			sendJsonError(w, http.StatusNotFound, "No such partition: "+
+				move.From+" or "+move.To)
+		} else if err == hmsds.ErrHMSDSNoPartitionMember {
+			sendJsonError(w, http.StatusConflict, "one or more components "+
+				"are not members of partition "+move.From)
+		} else if err == hmsds.ErrHMSDSExclusivePartition ||
+			err == hmsds.ErrHMSDSDuplicateKey {
+			sendJsonError(w, http.StatusConflict, "operation would conflict "+
+				"with an existing member in another partition.")
+		} else {
+			sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
+		}
+		return
+	}
+	move.IDs = ids
+	sendJsonObject(w, http.StatusOK, &move)
+}
+
+// Get the membership history of a partition, i.e. who added or removed
+// which members and when.  This is kept after the partition is deleted.
+func (s *SmD) doPartitionHistoryGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	name := sm.NormalizeGroupField(chi.URLParam(r, "partition_name"))
+	if sm.VerifyGroupField(name) != nil {
+		s.reqLog(r).LogAlways("doPartitionHistoryGet(): Invalid partition name.")
+		sendJsonError(w, http.StatusBadRequest, "Invalid partition name.")
+		return
+	}
+	f, err := parseMemberHistFilter(r)
+	if err != nil {
+		s.reqLog(r).LogAlways("doPartitionHistoryGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	hist, err := s.db.GetPartitionHist(name, f)
+	if err != nil {
+		s.reqLog(r).LogAlways("doPartitionHistoryGet(): Lookup failure: (%s) %s", name, err)
+		sendJsonDBError(w, "bad query param: ", "DB query failed.", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.PartitionHistory{Name: name, History: hist})
+}
+
 /*
  * HSM Memberships API
  */
@@ -5610,28 +6687,28 @@ func (s *SmD) doMembershipsGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse arguments
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doMembershipsGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doMembershipsGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doMembershipsGet(): Marshal form: %s", err)
+		s.reqLog(r).LogAlways("doMembershipsGet(): Marshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	compFilter := new(hmsds.ComponentFilter)
 	if err = json.Unmarshal(formJSON, compFilter); err != nil {
-		s.lg.Printf("doMembershipsGet(): Unmarshal form: %s", err)
+		s.reqLog(r).LogAlways("doMembershipsGet(): Unmarshal form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	memberships, err := s.db.GetMemberships(compFilter)
 	if err != nil {
-		s.lg.Printf("doMembershipsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doMembershipsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
@@ -5646,18 +6723,18 @@ func (s *SmD) doMembershipGet(w http.ResponseWriter, r *http.Request) {
 	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
 
 	if !xnametypes.IsHMSCompIDValid(xname) {
-		s.lg.Printf("doMembershipGet(): Invalid xname.")
+		s.reqLog(r).LogAlways("doMembershipGet(): Invalid xname.")
 		sendJsonError(w, http.StatusBadRequest, "invalid xname")
 		return
 	}
 	membership, err := s.db.GetMembership(xname)
 	if err != nil {
-		s.lg.Printf("doMembershipGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doMembershipGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "bad query param: ", "", err)
 		return
 	}
 	if membership == nil {
-		s.lg.Printf("doMembershipGet(): No such xname, %s", xname)
+		s.reqLog(r).LogAlways("doMembershipGet(): No such xname, %s", xname)
 		sendJsonError(w, http.StatusNotFound, "No such xname: "+xname)
 		return
 	}
@@ -5686,7 +6763,7 @@ func (s *SmD) compLocksV2Helper(w http.ResponseWriter, r *http.Request, action s
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	results, err := s.db.UpdateCompLocksV2(filter, action)
+	results, err := s.db.WithActor(s.requestActor(r)).UpdateCompLocksV2(filter, action)
 	if err != nil {
 		s.lg.Printf("doCompLocksV2%s(): %s %s Err: %s", action, r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
@@ -5707,20 +6784,20 @@ func (s *SmD) doCompLocksReservationRemove(w http.ResponseWriter, r *http.Reques
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRemove(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRemove(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRemove(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRemove(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	results, err := s.db.DeleteCompReservationsForce(filter)
+	results, err := s.db.WithActor(s.requestActor(r)).DeleteCompReservationsForce(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRemove(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRemove(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5739,20 +6816,20 @@ func (s *SmD) doCompLocksReservationRelease(w http.ResponseWriter, r *http.Reque
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRelease(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRelease(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRelease(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRelease(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	results, err := s.db.DeleteCompReservations(filter)
+	results, err := s.db.WithActor(s.requestActor(r)).DeleteCompReservations(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationRelease(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksReservationRelease(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5771,21 +6848,21 @@ func (s *SmD) doCompLocksReservationCreate(w http.ResponseWriter, r *http.Reques
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationCreate(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationCreate(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationCreate(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksReservationCreate(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	filter.ReservationDuration = 0
-	results, err := s.db.InsertCompReservations(filter)
+	results, err := s.db.WithActor(s.requestActor(r)).InsertCompReservations(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksReservationCreate(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksReservationCreate(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5804,25 +6881,25 @@ func (s *SmD) doCompLocksServiceReservationRenew(w http.ResponseWriter, r *http.
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationRenew(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationRenew(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationRenew(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationRenew(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	if filter.ReservationDuration <= 0 {
-		s.lg.Printf("doCompLocksServiceReservationRenew(): ReservationDuration must be greater than 0")
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationRenew(): ReservationDuration must be greater than 0")
 		sendJsonError(w, http.StatusBadRequest, "ReservationDuration must be greater than 0")
 		return
 	}
-	results, err := s.db.UpdateCompReservations(filter)
+	results, err := s.db.WithActor(s.requestActor(r)).UpdateCompReservations(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationRenew(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationRenew(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5848,25 +6925,25 @@ func (s *SmD) doCompLocksServiceReservationCreate(w http.ResponseWriter, r *http
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCreate(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCreate(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCreate(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCreate(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	if filter.ReservationDuration <= 0 {
-		s.lg.Printf("doCompLocksServiceReservationCreate(): ReservationDuration must be greater than 0")
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCreate(): ReservationDuration must be greater than 0")
 		sendJsonError(w, http.StatusBadRequest, "ReservationDuration must be greater than 0")
 		return
 	}
-	results, err := s.db.InsertCompReservations(filter)
+	results, err := s.db.WithActor(s.requestActor(r)).InsertCompReservations(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCreate(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCreate(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5885,20 +6962,20 @@ func (s *SmD) doCompLocksServiceReservationCheck(w http.ResponseWriter, r *http.
 	body, _ := io.ReadAll(r.Body)
 	err := json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCheck(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCheck(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCheck(): Couldn't validate component reservation filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCheck(): Couldn't validate component reservation filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	results, err := s.db.GetCompReservations(filter.DeputyKeys)
 	if err != nil {
-		s.lg.Printf("doCompLocksServiceReservationCheck(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksServiceReservationCheck(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5920,20 +6997,20 @@ func (s *SmD) doCompLocksStatus(w http.ResponseWriter, r *http.Request) {
 	body, err := ioutil.ReadAll(r.Body)
 	err = json.Unmarshal(body, &filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksStatus(): Unmarshal body: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatus(): Unmarshal body: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"error decoding JSON "+err.Error())
 		return
 	}
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksStatus(): Couldn't validate component lock filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatus(): Couldn't validate component lock filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 	locks, err := s.db.GetCompLocksV2(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksStatus(): %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("doCompLocksStatus(): %s %s Err: %s", r.RemoteAddr, string(body), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'POST' failed during store.", err)
@@ -5966,20 +7043,20 @@ func (s *SmD) doCompLocksStatusGet(w http.ResponseWriter, r *http.Request) {
 
 	// Parse query parameters
 	if err := r.ParseForm(); err != nil {
-		s.lg.Printf("doCompLocksStatusGet(): ParseForm: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatusGet(): ParseForm: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	formJSON, err := json.Marshal(r.Form)
 	if err != nil {
-		s.lg.Printf("doCompLocksStatusGet(): Marshall form: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatusGet(): Marshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
 	}
 	if err = json.Unmarshal(formJSON, &inFilter); err != nil {
-		s.lg.Printf("doCompLocksStatusGet(): Unmarshall form: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatusGet(): Unmarshall form: %s", err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"failed to decode query parameters.")
 		return
@@ -5987,7 +7064,7 @@ func (s *SmD) doCompLocksStatusGet(w http.ResponseWriter, r *http.Request) {
 	filter = compGetLockFltrToCompLockV2Filter(inFilter)
 	err = filter.VerifyNormalize()
 	if err != nil {
-		s.lg.Printf("doCompLocksStatusGet(): Couldn't validate component lock filter: %s", err)
+		s.reqLog(r).LogAlways("doCompLocksStatusGet(): Couldn't validate component lock filter: %s", err)
 		sendJsonError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -5998,14 +7075,14 @@ func (s *SmD) doCompLocksStatusGet(w http.ResponseWriter, r *http.Request) {
 		_, err = strconv.ParseBool(filter.Reserved[0])
 		if err != nil {
 			reservedParamBoolErrMsg := "bad 'Reserved' query parameter: " + filter.Reserved[0]
-			s.lg.Printf("doCompLocksStatusGet(): %s", reservedParamBoolErrMsg)
+			s.reqLog(r).LogAlways("doCompLocksStatusGet(): %s", reservedParamBoolErrMsg)
 			sendJsonError(w, http.StatusBadRequest, reservedParamBoolErrMsg)
 			return
 		}
 	}
 	locks, err := s.db.GetCompLocksV2(filter)
 	if err != nil {
-		s.lg.Printf("doCompLocksStatus(): %s %s Err: %s", r.RemoteAddr, string(formJSON), err)
+		s.reqLog(r).LogAlways("doCompLocksStatus(): %s %s Err: %s", r.RemoteAddr, string(formJSON), err)
 		// Send this message as 500 or 400 plus error message if it is
 		// an HMSError and not, e.g. an internal DB error code.
 		sendJsonDBError(w, "", "operation 'GET' failed during query.", err)
@@ -6045,6 +7122,33 @@ func (s *SmD) doCompLocksDisable(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// Get the lock audit log, i.e. who locked, reserved, renewed or released
+// which components, when and why, optionally filtering by the id, action,
+// actor, starttime and endtime query parameters.
+func (s *SmD) doCompLocksAuditGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("doCompLocksAuditGet(): ParseForm: %s", err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	f := new(hmsds.CompLockAuditFilter)
+	f.ID = r.Form["id"]
+	f.Action = r.Form["action"]
+	f.Actor = r.Form.Get("actor")
+	f.StartTime = r.Form.Get("starttime")
+	f.EndTime = r.Form.Get("endtime")
+	entries, err := s.db.GetCompLockAudit(f)
+	if err != nil {
+		s.reqLog(r).LogAlways("doCompLocksAuditGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "bad query param: ", "DB query failed.", err)
+		return
+	}
+	sendJsonObject(w, http.StatusOK, &sm.CompLockV2AuditArray{Entries: entries})
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // Power Mappings
 /////////////////////////////////////////////////////////////////////////////
@@ -6053,17 +7157,17 @@ func (s *SmD) doCompLocksDisable(w http.ResponseWriter, r *http.Request) {
 func (s *SmD) doPowerMapGet(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doPowerMapGet(): trying...")
+	s.reqLog(r).LogAlways("doPowerMapGet(): trying...")
 
 	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
 	if !xnametypes.IsHMSCompIDValid(xname) {
-		s.lg.Printf("doPowerMapGet(): Invalid xname.")
+		s.reqLog(r).LogAlways("doPowerMapGet(): Invalid xname.")
 		sendJsonError(w, http.StatusBadRequest, "invalid xname")
 		return
 	}
 	m, err := s.db.GetPowerMapByID(xname)
 	if err != nil {
-		s.LogAlways("doPowerMapGet(): Lookup failure: (%s) %s",
+		s.reqLog(r).LogAlways("doPowerMapGet(): Lookup failure: (%s) %s",
 			xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -6082,7 +7186,7 @@ func (s *SmD) doPowerMapsGet(w http.ResponseWriter, r *http.Request) {
 
 	ms, err := s.db.GetPowerMapsAll()
 	if err != nil {
-		s.LogAlways("doPowerMapsGet(): Lookup failure: %s", err)
+		s.reqLog(r).LogAlways("doPowerMapsGet(): Lookup failure: %s", err)
 		sendJsonDBError(w, "", "", err)
 		return
 	}
@@ -6125,7 +7229,7 @@ func (s *SmD) doPowerMapsPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertPowerMaps(ms)
 	if err != nil {
-		s.lg.Printf("failed: %s %s Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s Err: %s", r.RemoteAddr, string(body), err)
 		sendJsonError(w, http.StatusInternalServerError,
 			"operation 'POST' failed during store. ")
 		return
@@ -6175,7 +7279,7 @@ func (s *SmD) doPowerMapPut(w http.ResponseWriter, r *http.Request) {
 	}
 	err = s.db.InsertPowerMap(m)
 	if err != nil {
-		s.lg.Printf("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
+		s.reqLog(r).LogAlways("failed: %s %s, Err: %s", r.RemoteAddr, string(body), err)
 		// Unexpected error on update
 		sendJsonError(w, http.StatusInternalServerError,
 			"operation 'PUT' failed during store")
@@ -6189,7 +7293,7 @@ func (s *SmD) doPowerMapPut(w http.ResponseWriter, r *http.Request) {
 func (s *SmD) doPowerMapDelete(w http.ResponseWriter, r *http.Request) {
 	defer base.DrainAndCloseRequestBody(r)
 
-	s.lg.Printf("doPowerMapDelete(): trying...")
+	s.reqLog(r).LogAlways("doPowerMapDelete(): trying...")
 
 	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
 
@@ -6199,7 +7303,7 @@ func (s *SmD) doPowerMapDelete(w http.ResponseWriter, r *http.Request) {
 	}
 	didDelete, err := s.db.DeletePowerMapByID(xname)
 	if err != nil {
-		s.LogAlways("doPowerMapDelete(): delete failure: (%s) %s",
+		s.reqLog(r).LogAlways("doPowerMapDelete(): delete failure: (%s) %s",
 			xname, err)
 		sendJsonDBError(w, "", "", err)
 		return
@@ -6218,7 +7322,7 @@ func (s *SmD) doPowerMapsDeleteAll(w http.ResponseWriter, r *http.Request) {
 	var err error
 	numDeleted, err := s.db.DeletePowerMapsAll()
 	if err != nil {
-		s.lg.Printf("doPowerMapsDelete(): Delete failure: %s", err)
+		s.reqLog(r).LogAlways("doPowerMapsDelete(): Delete failure: %s", err)
 		sendJsonError(w, http.StatusInternalServerError, "DB query failed.")
 		return
 	}
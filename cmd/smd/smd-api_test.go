@@ -231,6 +231,14 @@ func compareFilter(fltr1 hmsds.ComponentFilter, fltr2 hmsds.ComponentFilter) boo
 			return false
 		}
 	}
+	if len(fltr1.Filter) != len(fltr2.Filter) {
+		return false
+	}
+	for i := 0; i < len(fltr1.Filter); i++ {
+		if fltr1.Filter[i] != fltr2.Filter[i] {
+			return false
+		}
+	}
 	return true
 }
 
@@ -600,16 +608,41 @@ func TestMain(m *testing.M) {
 	s.valuesBaseV2 = s.serviceBaseV2 + "/values"
 	s.stateBaseV2 = s.apiRootV2 + "/State"
 	s.componentsBaseV2 = s.stateBaseV2 + "/Components"
+	s.deletedCompBaseV2 = s.stateBaseV2 + "/DeletedComponents"
 	s.redfishEPBaseV2 = s.apiRootV2 + "/Inventory/RedfishEndpoints"
+	s.deletedRFEPBaseV2 = s.apiRootV2 + "/Inventory/DeletedRedfishEndpoints"
 	s.nodeMapBaseV2 = s.apiRootV2 + "/Defaults/NodeMaps"
+	s.nidPolicyBaseV2 = s.apiRootV2 + "/Defaults/NIDPolicies"
+	s.nidAssignBaseV2 = s.apiRootV2 + "/Defaults/NIDAssignments"
+	s.xnamesBaseV2 = s.apiRootV2 + "/Xnames"
 	s.compEPBaseV2 = s.apiRootV2 + "/Inventory/ComponentEndpoints"
 	s.serviceEPBaseV2 = s.apiRootV2 + "/Inventory/ServiceEndpoints"
 	s.compEthIntBaseV2 = s.apiRootV2 + "/Inventory/EthernetInterfaces"
 	s.hsnIntBaseV2 = s.apiRootV2 + "/Inventory/HSNInterfaces"
 	s.hwinvByLocBaseV2 = s.apiRootV2 + "/Inventory/Hardware"
 	s.hwinvByFRUBaseV2 = s.apiRootV2 + "/Inventory/HardwareByFRU"
+	s.sparePartsBaseV2 = s.apiRootV2 + "/Inventory/SpareParts"
+	s.thermalBaseV2 = s.apiRootV2 + "/Inventory/ThermalSensors"
 	s.invDiscoverBaseV2 = s.apiRootV2 + "/Inventory/Discover"
 	s.invDiscStatusBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryStatus"
+	s.invDiscHoldBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryHolds"
+	s.invDiscSchedBaseV2 = s.apiRootV2 + "/Inventory/DiscoverySchedules"
+	s.invDiscPlanBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryPlan"
+	s.invDiscJobBaseV2 = s.apiRootV2 + "/Inventory/DiscoveryJobs"
+	s.invExportBaseV2 = s.apiRootV2 + "/Inventory/Export"
+	s.invConsistBaseV2 = s.apiRootV2 + "/Inventory/Consistency"
+	s.invReconcileBaseV2 = s.apiRootV2 + "/Inventory/Reconcile"
+	s.snapshotBaseV2 = s.apiRootV2 + "/Admin/Snapshot"
+	s.vendorProfBaseV2 = s.apiRootV2 + "/Inventory/VendorProfiles"
+	s.fallbackCredBaseV2 = s.apiRootV2 + "/Inventory/FallbackCredentialEndpoints"
+	s.telemetryBaseV2 = s.apiRootV2 + "/Telemetry"
+	s.certBaseV2 = s.apiRootV2 + "/Inventory/Certificates"
+	s.credBaseV2 = s.apiRootV2 + "/Inventory/Credentials"
+	s.firmwareBaseV2 = s.apiRootV2 + "/Inventory/FirmwareInventory"
+	s.logServiceBaseV2 = s.apiRootV2 + "/Inventory/LogServices"
+	s.bootConfigBaseV2 = s.apiRootV2 + "/Inventory/BootConfig"
+	s.eventSubBaseV2 = s.apiRootV2 + "/Inventory/EventSubscriptions"
+	s.eventsBaseV2 = s.apiRootV2 + "/Events"
 	s.subscriptionBaseV2 = s.apiRootV2 + "/Subscriptions"
 	s.groupsBaseV2 = s.apiRootV2 + "/groups"
 	s.partitionsBaseV2 = s.apiRootV2 + "/partitions"
@@ -900,6 +933,79 @@ func TestDoComponentGet(t *testing.T) {
 	}
 }
 
+func TestDoComponentGetInclude(t *testing.T) {
+	node := &base.Component{ID: "x0c0s27b0n0", Type: "Node", State: "On"}
+	bmc := &base.Component{ID: "x0c0s27b0", Type: "NodeBMC", State: "Ready"}
+	cab := &base.Component{ID: "x0", Type: "Cabinet", State: "On"}
+	proc := &base.Component{ID: "x0c0s27b0n0p0", Type: "Processor", State: "Populated"}
+	tests := []struct {
+		reqURI          string
+		filterRet       []*base.Component
+		queryRet        []*base.Component
+		expectedCode    int
+		expectedFltrIDs []string
+		expectedQryIDs  []string
+		expectedResp    []byte
+	}{{
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0?include=ancestors",
+		[]*base.Component{cab, bmc},
+		nil,
+		http.StatusOK,
+		[]string{"x0c0s27b0", "x0c0s27", "x0c0", "x0"},
+		nil,
+		json.RawMessage(`{"ID":"x0c0s27b0n0","Type":"Node","State":"On","Ancestors":[{"ID":"x0c0s27b0","Type":"NodeBMC","State":"Ready"},{"ID":"x0","Type":"Cabinet","State":"On"}]}
+`),
+	}, {
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0?include=ancestors,descendants",
+		[]*base.Component{},
+		[]*base.Component{node, proc},
+		http.StatusOK,
+		[]string{"x0c0s27b0", "x0c0s27", "x0c0", "x0"},
+		[]string{"x0c0s27b0n0"},
+		json.RawMessage(`{"ID":"x0c0s27b0n0","Type":"Node","State":"On","Ancestors":[],"Descendants":[{"ID":"x0c0s27b0n0p0","Type":"Processor","State":"Populated"}]}
+`),
+	}, {
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0?include=cousins",
+		nil,
+		nil,
+		http.StatusBadRequest,
+		nil,
+		nil,
+		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad include value 'cousins', expected 'ancestors' or 'descendants'","status":400}
+`),
+	}}
+
+	for i, test := range tests {
+		results.GetComponentByID.Return.id = node
+		results.GetComponentByID.Return.err = nil
+		results.GetComponentsFilter.Input.compFilter = hmsds.ComponentFilter{}
+		results.GetComponentsFilter.Return.ids = test.filterRet
+		results.GetComponentsFilter.Return.err = nil
+		results.GetComponentsQuery.Input.ids = nil
+		results.GetComponentsQuery.Return.ids = test.queryRet
+		results.GetComponentsQuery.Return.err = nil
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if !reflect.DeepEqual(test.expectedFltrIDs, results.GetComponentsFilter.Input.compFilter.ID) {
+			t.Errorf("Test %v Failed: Expected ancestor IDs '%v'; Received '%v'", i, test.expectedFltrIDs, results.GetComponentsFilter.Input.compFilter.ID)
+		}
+		if !reflect.DeepEqual(test.expectedQryIDs, results.GetComponentsQuery.Input.ids) {
+			t.Errorf("Test %v Failed: Expected descendant IDs '%v'; Received '%v'", i, test.expectedQryIDs, results.GetComponentsQuery.Input.ids)
+		}
+		if bytes.Compare(test.expectedResp, w.Body.Bytes()) != 0 {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'", i, string(test.expectedResp), w.Body)
+		}
+	}
+}
+
 func TestDoComponentByNIDGet(t *testing.T) {
 	enabledFlg := true
 	testComp := base.Component{
@@ -1124,7 +1230,7 @@ func TestDoComponentsGet(t *testing.T) {
 		json.RawMessage(`{"Components":[{"ID":"x0c0s14b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":448,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s15b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":480,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s18b0n0","Type":"Node","State":"Off","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":576,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s22b0n0","Type":"Node","State":"Off","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":704,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s24b0n0","Type":"Node","State":"Off","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":786,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s25b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":800,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s26b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":832,"NetType":"Sling","Arch":"X86"},{"ID":"x0c0s27b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":864,"NetType":"Sling","Arch":"X86"}]}
 `),
 	}, {
-		"PATCH",
+		"PUT",
 		"https://localhost/hsm/v2/State/Components?type=node",
 		[]*base.Component{
 			&base.Component{"x0c0s14b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Compute", "", "448", "", "Sling", "X86", "", false, false},
@@ -1141,7 +1247,7 @@ func TestDoComponentsGet(t *testing.T) {
 			Type: []string{"node"},
 		},
 		hmsds.FLTR_DEFAULT,
-		json.RawMessage(`{"type":"about:blank","title":"Method Not Allowed","detail":"allow GET,POST,DELETE","status":405}
+		json.RawMessage(`{"type":"about:blank","title":"Method Not Allowed","detail":"allow GET,POST,PATCH,DELETE","status":405}
 `),
 	}, {
 		"GET",
@@ -1182,6 +1288,30 @@ func TestDoComponentsGet(t *testing.T) {
 		},
 		hmsds.FLTR_DEFAULT,
 		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not a valid HMS Type","status":400}
+`),
+	}, {
+		"GET",
+		"https://localhost/hsm/v2/State/Components?filter=type%20eq%20Node%20and%20nid%20ge%20800",
+		[]*base.Component{
+			&base.Component{"x0c0s27b0n0", "Node", "On", "OK", &enabledFlg, "AdminStatus", "Compute", "", "864", "", "Sling", "X86", "", false, false},
+		},
+		nil,
+		hmsds.ComponentFilter{
+			Filter: []string{"type eq Node and nid ge 800"},
+		},
+		hmsds.FLTR_DEFAULT,
+		json.RawMessage(`{"Components":[{"ID":"x0c0s27b0n0","Type":"Node","State":"On","Flag":"OK","Enabled":true,"SoftwareStatus":"AdminStatus","Role":"Compute","NID":864,"NetType":"Sling","Arch":"X86"}]}
+`),
+	}, {
+		"GET",
+		"https://localhost/hsm/v2/State/Components?filter=state%20gt%20Ready",
+		nil,
+		hmsds.ErrHMSDSArgBadFilter,
+		hmsds.ComponentFilter{
+			Filter: []string{"state gt Ready"},
+		},
+		hmsds.FLTR_DEFAULT,
+		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not a valid filter expression","status":400}
 `),
 	}}
 
@@ -1215,6 +1345,70 @@ func TestDoComponentsGet(t *testing.T) {
 	}
 }
 
+func TestDoComponentsGetAsOf(t *testing.T) {
+	tests := []struct {
+		reqURI         string
+		hmsdsRespIDs   []*base.Component
+		hmsdsRespErr   error
+		expectedAsOf   string
+		expectedFilter hmsds.ComponentFilter
+		expectedCode   int
+		expectedResp   []byte
+	}{{
+		"https://localhost/hsm/v2/State/Components?type=node&state=ready&asof=2026-03-01T10:00:00Z",
+		[]*base.Component{
+			{ID: "x0c0s14b0n0", Type: "Node", State: "Ready", Flag: "OK"},
+		},
+		nil,
+		"2026-03-01T10:00:00Z",
+		hmsds.ComponentFilter{Type: []string{"node"}, State: []string{"ready"}},
+		http.StatusOK,
+		json.RawMessage(`{"Components":[{"ID":"x0c0s14b0n0","Type":"Node","State":"Ready","Flag":"OK"}]}
+`),
+	}, {
+		"https://localhost/hsm/v2/State/Components?asof=yesterday",
+		nil,
+		hmsds.ErrHMSDSArgBadTimeFormat,
+		"yesterday",
+		hmsds.ComponentFilter{},
+		http.StatusBadRequest,
+		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not in a valid RFC3339 time format","status":400}
+`),
+	}, {
+		"https://localhost/hsm/v2/State/Components?asof=2026-03-01T10:00:00Z&include=ancestors",
+		nil,
+		nil,
+		"",
+		hmsds.ComponentFilter{},
+		http.StatusBadRequest,
+		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"include cannot be used with asof","status":400}
+`),
+	}}
+
+	for i, test := range tests {
+		results.GetComponentsAsOf.Input.asOf = ""
+		results.GetComponentsAsOf.Input.compFilter = hmsds.ComponentFilter{}
+		results.GetComponentsAsOf.Return.ids = test.hmsdsRespIDs
+		results.GetComponentsAsOf.Return.err = test.hmsdsRespErr
+		req, _ := http.NewRequest("GET", test.reqURI, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if results.GetComponentsAsOf.Input.asOf != test.expectedAsOf {
+			t.Errorf("Test %v Failed: Expected asOf '%s'; Received '%s'", i, test.expectedAsOf, results.GetComponentsAsOf.Input.asOf)
+		}
+		if !compareFilter(test.expectedFilter, results.GetComponentsAsOf.Input.compFilter) {
+			t.Errorf("Test %v Failed: Expected compFilter '%v'; Received compFilter '%v'", i, test.expectedFilter, results.GetComponentsAsOf.Input.compFilter)
+		}
+		if bytes.Compare(test.expectedResp, w.Body.Bytes()) != 0 {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'", i, string(test.expectedResp), w.Body)
+		}
+	}
+}
+
 func TestDoComponentsQueryGet(t *testing.T) {
 	enabledFlg := true
 	tests := []struct {
@@ -2995,7 +3189,7 @@ func TestDoPostSCNSubscription(t *testing.T) {
 				"ready": []SCNUrl{SCNUrl{url: "https://foo2/bar", refCount: 1}},
 			},
 		},
-		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, or State trigger.","status":400}
+		json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Missing trigger. Must subscribe to atleast one Enabled, Role, SubRole, SoftwareStatus, State, or Reservation trigger.","status":400}
 `),
 	}, {
 		"POST",
@@ -4230,6 +4424,13 @@ func TestDoHWInvByLocationGetAll(t *testing.T) {
 		hmsdsRespErr:   hmsds.ErrHMSDSArgMissing, // actual error here doesn't really matter- any error.
 		expectedFilter: &hmsds.HWInvLocFilter{},
 		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}` + "\n"),
+	}, {
+		reqType:        "GET",
+		reqURI:         "https://localhost/hsm/v2/Inventory/Hardware?id=X1000C0S*B0N%5B0-1%5D&id=x1000c1s0b0n0",
+		hmsdsRespIDs:   stest.HWInvByLocArray1,
+		hmsdsRespErr:   nil,
+		expectedFilter: &hmsds.HWInvLocFilter{ID: []string{"x1000c0s*b0n[0,1]", "x1000c1s0b0n0"}},
+		expectedResp:   payload1,
 	}}
 
 	for i, test := range tests {
@@ -4328,6 +4529,172 @@ func TestDoHWInvByFRUGetAll(t *testing.T) {
 	}
 }
 
+func TestDoHWInvByFRULocateGet(t *testing.T) {
+	located := []*sm.HWInvByFRULocation{{
+		Location: "x0c0s0b0n0d3",
+		HWInvByFRU: sm.HWInvByFRU{
+			FRUID:                "Memory.Micron.PN1.SN1",
+			Type:                 "Memory",
+			HWInventoryByFRUType: sm.HWInvByFRUMemory,
+			HMSMemoryFRUInfo: &rf.MemoryFRUInfoRF{
+				Manufacturer: "Micron",
+				PartNumber:   "PN1",
+				SerialNumber: "SN1",
+			},
+		},
+	}}
+	payload1, _ := json.Marshal(located)
+
+	tests := []struct {
+		reqURI         string
+		hmsdsResp      []*sm.HWInvByFRULocation
+		hmsdsRespErr   error
+		expectedCode   int
+		expectedFilter *hmsds.HWInvLocFilter
+		expectedResp   []byte
+	}{{
+		reqURI:       "https://localhost/hsm/v2/Inventory/HardwareByFRU/Locate?serialnumber=SN1&manufacturer=micron",
+		hmsdsResp:    located,
+		expectedCode: http.StatusOK,
+		expectedFilter: &hmsds.HWInvLocFilter{
+			SerialNumber: []string{"SN1"},
+			Manufacturer: []string{"micron"},
+		},
+		expectedResp: payload1,
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareByFRU/Locate?partnumber=PN2&type=memory",
+		hmsdsResp:      []*sm.HWInvByFRULocation{},
+		expectedCode:   http.StatusOK,
+		expectedFilter: &hmsds.HWInvLocFilter{PartNumber: []string{"PN2"}, Type: []string{"Memory"}},
+		expectedResp:   json.RawMessage(`[]`),
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareByFRU/Locate?type=foo",
+		expectedCode:   http.StatusBadRequest,
+		expectedFilter: nil,
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid HMS type","status":400}`),
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareByFRU/Locate?serialnumber=SN1",
+		hmsdsRespErr:   hmsds.ErrHMSDSArgMissing,
+		expectedCode:   http.StatusInternalServerError,
+		expectedFilter: &hmsds.HWInvLocFilter{SerialNumber: []string{"SN1"}},
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}`),
+	}}
+	defer func() {
+		results.LocateHWInvByFRU.Input.f = nil
+		results.LocateHWInvByFRU.Return.hwfrus = nil
+		results.LocateHWInvByFRU.Return.err = nil
+	}()
+
+	for i, test := range tests {
+		results.LocateHWInvByFRU.Input.f = nil
+		results.LocateHWInvByFRU.Return.hwfrus = test.hmsdsResp
+		results.LocateHWInvByFRU.Return.err = test.hmsdsRespErr
+
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if f := results.LocateHWInvByFRU.Input.f; (f == nil) != (test.expectedFilter == nil) ||
+			(f != nil && !compareHWInvLocFilter(*test.expectedFilter, *f)) {
+			t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, f)
+		}
+		if strings.TrimSpace(string(test.expectedResp)) !=
+			strings.TrimSpace(string(w.Body.Bytes())) {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, string(test.expectedResp), w.Body)
+		}
+	}
+}
+
+func TestDoSparePartsGet(t *testing.T) {
+	fru := &sm.HWInvByFRU{
+		FRUID:                "Processor.Intel.PN1.SN1",
+		Type:                 "Processor",
+		HWInventoryByFRUType: sm.HWInvByFRUProcessor,
+		HMSProcessorFRUInfo: &rf.ProcessorFRUInfoRF{
+			Manufacturer: "Intel",
+			Model:        "Xeon",
+			PartNumber:   "PN1",
+		},
+	}
+	hwlocs := []*sm.HWInvByLoc{{ID: "x0c0s0b0n0p0", Type: "Processor", PopulatedFRU: fru}}
+	hwhists := []*sm.HWInvHist{{
+		ID:        "x0c0s0b0n0p0",
+		FruId:     fru.FRUID,
+		Timestamp: "2026-01-01T00:00:00Z",
+		EventType: sm.HWInvHistEventTypeRemoved,
+	}}
+
+	tests := []struct {
+		reqURI             string
+		hmsdsRespErr       error
+		expectedFilter     *hmsds.HWInvLocFilter
+		expectedHistFilter *hmsds.HWInvHistFilter
+		expectedResp       []byte
+	}{{
+		reqURI:             "https://localhost/hsm/v2/Inventory/SpareParts?type=processor&partnumber=PN1&starttime=2025-01-01T00:00:00Z",
+		expectedFilter:     &hmsds.HWInvLocFilter{Type: []string{"Processor"}, PartNumber: []string{"PN1"}},
+		expectedHistFilter: &hmsds.HWInvHistFilter{StartTime: "2025-01-01T00:00:00Z"},
+		expectedResp:       json.RawMessage(`{"Parts":[{"Type":"Processor","Manufacturer":"Intel","Model":"Xeon","PartNumber":"PN1","Installed":1,"Known":1,"Removed":1,"Replaced":0}]}`),
+	}, {
+		reqURI:       "https://localhost/hsm/v2/Inventory/SpareParts?type=foo",
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid HMS type","status":400}`),
+	}, {
+		reqURI:             "https://localhost/hsm/v2/Inventory/SpareParts",
+		hmsdsRespErr:       hmsds.ErrHMSDSArgMissing,
+		expectedFilter:     &hmsds.HWInvLocFilter{},
+		expectedHistFilter: &hmsds.HWInvHistFilter{},
+		expectedResp:       json.RawMessage(`{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}`),
+	}}
+
+	for i, test := range tests {
+		results.GetHWInvByLocFilter.Input.f = nil
+		results.GetHWInvByLocFilter.Return.hwlocs = hwlocs
+		results.GetHWInvByLocFilter.Return.err = nil
+		results.GetHWInvByFRUFilter.Input.f = nil
+		results.GetHWInvByFRUFilter.Return.hwfrus = []*sm.HWInvByFRU{fru}
+		results.GetHWInvByFRUFilter.Return.err = nil
+		results.GetHWInvHistFilter.Input.f = nil
+		results.GetHWInvHistFilter.Return.hwhists = hwhists
+		results.GetHWInvHistFilter.Return.err = test.hmsdsRespErr
+
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if test.expectedFilter != nil {
+			if !compareHWInvLocFilter(*test.expectedFilter, *results.GetHWInvByLocFilter.Input.f) {
+				t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, results.GetHWInvByLocFilter.Input.f)
+			}
+			if !compareHWInvLocFilter(*test.expectedFilter, *results.GetHWInvByFRUFilter.Input.f) {
+				t.Errorf("Test %v Failed: Expected FRU filter is '%v'; Received '%v'", i, test.expectedFilter, results.GetHWInvByFRUFilter.Input.f)
+			}
+			if !reflect.DeepEqual(test.expectedHistFilter, results.GetHWInvHistFilter.Input.f) {
+				t.Errorf("Test %v Failed: Expected history filter is '%v'; Received '%v'", i, test.expectedHistFilter, results.GetHWInvHistFilter.Input.f)
+			}
+		}
+		if strings.TrimSpace(string(test.expectedResp)) !=
+			strings.TrimSpace(string(w.Body.Bytes())) {
+
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, string(test.expectedResp), w.Body)
+		}
+	}
+	results.GetHWInvByLocFilter.Return.hwlocs = nil
+	results.GetHWInvByFRUFilter.Return.hwfrus = nil
+	results.GetHWInvHistFilter.Return.hwhists = nil
+	results.GetHWInvHistFilter.Return.err = nil
+}
+
 func TestDoHWInvByLocationPost(t *testing.T) {
 	var HWInvByLocArray1 = []sm.HWInvByLoc{
 		stest.NodeHWInvByLoc1,
@@ -4427,7 +4794,7 @@ func TestDoHWInvByLocationPost(t *testing.T) {
 }
 
 func TestDoHWInvByLocationDelete(t *testing.T) {
-	data := Response{0, "deleted 1 entry"}
+	data := Response{Code: 0, Message: "deleted 1 entry"}
 	payload1, _ := json.Marshal(data)
 
 	tests := []struct {
@@ -4502,7 +4869,7 @@ func TestDoHWInvByLocationDelete(t *testing.T) {
 }
 
 func TestDoHWInvByFRUDelete(t *testing.T) {
-	data := Response{0, "deleted 1 entry"}
+	data := Response{Code: 0, Message: "deleted 1 entry"}
 	payload1, _ := json.Marshal(data)
 
 	tests := []struct {
@@ -4578,7 +4945,7 @@ func TestDoHWInvByFRUDelete(t *testing.T) {
 }
 
 func TestDoHWInvByLocationDeleteAll(t *testing.T) {
-	data := Response{0, "deleted 2 entries"}
+	data := Response{Code: 0, Message: "deleted 2 entries"}
 	payload1, _ := json.Marshal(data)
 
 	tests := []struct {
@@ -4638,7 +5005,7 @@ func TestDoHWInvByLocationDeleteAll(t *testing.T) {
 }
 
 func TestDoHWInvByFRUDeleteAll(t *testing.T) {
-	data := Response{0, "deleted 2 entries"}
+	data := Response{Code: 0, Message: "deleted 2 entries"}
 	payload1, _ := json.Marshal(data)
 
 	tests := []struct {
@@ -4778,6 +5145,52 @@ func TestDoHWInvByLocationQueryGet(t *testing.T) {
 	}
 }
 
+func TestDoHWInvExportGet(t *testing.T) {
+	defer func() {
+		results.GetHWInvByLocAll.Return.entries = nil
+		results.GetHWInvByLocAll.Return.err = nil
+	}()
+	results.GetHWInvByLocAll.Return.entries = stest.HWInvByLocArray1
+
+	root := "https://localhost/hsm/v2/Inventory/Export/Redfish"
+	tests := []struct {
+		reqURI       string
+		hmsdsRespErr error
+		expectedCode int
+		expectedID   string
+	}{
+		{root, nil, http.StatusOK, "/hsm/v2/Inventory/Export/Redfish"},
+		{root + "/", nil, http.StatusOK, "/hsm/v2/Inventory/Export/Redfish"},
+		{root + "/Systems/x0c0s0b0n0", nil, http.StatusOK,
+			"/hsm/v2/Inventory/Export/Redfish/Systems/x0c0s0b0n0"},
+		{root + "/Systems/x0c0s0b0n0/Processors/x0c0s0b0n0p1", nil, http.StatusOK,
+			"/hsm/v2/Inventory/Export/Redfish/Systems/x0c0s0b0n0/Processors/x0c0s0b0n0p1"},
+		{root + "/Systems/x0c0s0b0n1", nil, http.StatusNotFound, ""},
+		{"https://localhost/hsm/v2/Inventory/Export/Swordfish", nil, http.StatusNotFound, ""},
+		{root, hmsds.ErrHMSDSArgMissing, http.StatusInternalServerError, ""},
+	}
+	for i, test := range tests {
+		results.GetHWInvByLocAll.Return.err = test.hmsdsRespErr
+		req, _ := http.NewRequest("GET", test.reqURI, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v",
+				i, w.Code, test.expectedCode)
+			continue
+		}
+		if test.expectedID == "" {
+			continue
+		}
+		var res map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil ||
+			res["@odata.id"] != test.expectedID {
+			t.Errorf("Test %v Failed: Expected %s; Received '%v'",
+				i, test.expectedID, w.Body)
+		}
+	}
+}
+
 //////////////////////////////////////////////////////////////////////////////
 // HW Inventory History
 //////////////////////////////////////////////////////////////////////////////
@@ -5240,6 +5653,106 @@ func TestDoHWInvHistByFRUGetAll(t *testing.T) {
 	}
 }
 
+func TestDoHWInvSightingsGet(t *testing.T) {
+	sighting1 := &sm.HWInvSighting{
+		ID:        "x5c4s3b2n1d0",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
+		FirstSeen: "2020-01-21T11:36:00Z",
+		LastSeen:  "2020-01-22T11:36:00Z",
+	}
+	sighting2 := &sm.HWInvSighting{
+		ID:        "x5c4s3b2n1d1",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
+		FirstSeen: "2020-01-23T11:36:00Z",
+		LastSeen:  "2020-01-24T11:36:00Z",
+	}
+	sighting3 := &sm.HWInvSighting{
+		ID:        "x5c4s3b2n1d0",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_2",
+		FirstSeen: "2020-01-23T11:36:00Z",
+		LastSeen:  "2020-01-24T11:36:00Z",
+	}
+	payload1, _ := json.Marshal(sm.HWInvSightingResp{FRUs: []sm.HWInvSightingArray{
+		{ID: sighting1.FruId, Sightings: []*sm.HWInvSighting{sighting1, sighting2}},
+	}})
+	payload2, _ := json.Marshal(sm.HWInvSightingResp{FRUs: []sm.HWInvSightingArray{
+		{ID: sighting1.FruId, Sightings: []*sm.HWInvSighting{sighting1}},
+		{ID: sighting3.FruId, Sightings: []*sm.HWInvSighting{sighting3}},
+	}})
+
+	tests := []struct {
+		reqURI         string
+		hmsdsResp      []*sm.HWInvSighting
+		hmsdsRespErr   error
+		expectedCode   int
+		expectedFilter *hmsds.HWInvHistFilter
+		expectedResp   []byte
+	}{{
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareHistory/" + sighting1.FruId,
+		hmsdsResp:      []*sm.HWInvSighting{sighting1, sighting2},
+		expectedCode:   http.StatusOK,
+		expectedFilter: &hmsds.HWInvHistFilter{FruId: []string{sighting1.FruId}},
+		expectedResp:   payload1,
+	}, {
+		reqURI:       "https://localhost/hsm/v2/Inventory/HardwareHistory?id=x5c4s3b2n1d0&starttime=2020-01-21T00:00:00Z&endtime=2020-01-25T00:00:00Z",
+		hmsdsResp:    []*sm.HWInvSighting{sighting1, sighting3},
+		expectedCode: http.StatusOK,
+		expectedFilter: &hmsds.HWInvHistFilter{
+			ID:        []string{"x5c4s3b2n1d0"},
+			StartTime: "2020-01-21T00:00:00Z",
+			EndTime:   "2020-01-25T00:00:00Z",
+		},
+		expectedResp: payload2,
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareHistory?id=foo",
+		expectedCode:   http.StatusBadRequest,
+		expectedFilter: nil,
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid xname","status":400}`),
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareHistory?starttime=yesterday",
+		hmsdsRespErr:   hmsds.ErrHMSDSArgBadTimeFormat,
+		expectedCode:   http.StatusBadRequest,
+		expectedFilter: &hmsds.HWInvHistFilter{StartTime: "yesterday"},
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"` + hmsds.ErrHMSDSArgBadTimeFormat.Error() + `","status":400}`),
+	}, {
+		reqURI:         "https://localhost/hsm/v2/Inventory/HardwareHistory",
+		hmsdsRespErr:   hmsds.ErrHMSDSArgMissing,
+		expectedCode:   http.StatusInternalServerError,
+		expectedFilter: &hmsds.HWInvHistFilter{},
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Internal Server Error","detail":"failed to query DB.","status":500}`),
+	}}
+	defer func() {
+		results.GetHWInvSightingsFilter.Input.f = nil
+		results.GetHWInvSightingsFilter.Return.sightings = nil
+		results.GetHWInvSightingsFilter.Return.err = nil
+	}()
+
+	for i, test := range tests {
+		results.GetHWInvSightingsFilter.Input.f = nil
+		results.GetHWInvSightingsFilter.Return.sightings = test.hmsdsResp
+		results.GetHWInvSightingsFilter.Return.err = test.hmsdsRespErr
+
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if !compareHWInvHistFilter(test.expectedFilter, results.GetHWInvSightingsFilter.Input.f) {
+			t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, results.GetHWInvSightingsFilter.Input.f)
+		}
+		if strings.TrimSpace(string(test.expectedResp)) !=
+			strings.TrimSpace(string(w.Body.Bytes())) {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, string(test.expectedResp), w.Body)
+		}
+	}
+}
+
 func TestDoHWInvHistByLocationDelete(t *testing.T) {
 	type testParams struct {
 		reqType          string
@@ -5924,7 +6437,7 @@ func TestDoRedfishEndpointPut(t *testing.T) {
 	var rawRFEUseSSDP = false
 	var rawRFEMACRequired = false
 	var rawRFERediscOnUpdate = true
-	rawRedfishEndpoint := rf.RawRedfishEP{"x0c0s14b0", "NodeBMC", "", "10.10.255.11", "local", "10.10.255.11", &rawRFEEnabled, "d4c6d22f-6983-42d8-8e6e-e1fd6d675c17", "root", "********", &rawRFEUseSSDP, &rawRFEMACRequired, "", "", &rawRFERediscOnUpdate, ""}
+	rawRedfishEndpoint := rf.RawRedfishEP{"x0c0s14b0", "NodeBMC", "", "10.10.255.11", "local", "10.10.255.11", &rawRFEEnabled, "d4c6d22f-6983-42d8-8e6e-e1fd6d675c17", "root", "********", &rawRFEUseSSDP, &rawRFEMACRequired, "", "", &rawRFERediscOnUpdate, "", ""}
 	redfishEndpointDescPtr, _ := rf.NewRedfishEPDescription(&rawRedfishEndpoint)
 	redfishEndpointPtr := sm.NewRedfishEndpoint(redfishEndpointDescPtr)
 
@@ -6267,6 +6780,83 @@ func TestDoComponentEndpointsGet(t *testing.T) {
 	}
 }
 
+func TestDoThermalSensorsGet(t *testing.T) {
+	temp := &rf.ThermalSensorInfo{Kind: rf.ThermalSensorTemperature, MemberId: "0", Name: "CPU1 Temp"}
+	fan := &rf.ThermalSensorInfo{Kind: rf.ThermalSensorFan, MemberId: "0", Name: "FAN1", ReadingUnits: "RPM"}
+	ceps := []*sm.ComponentEndpoint{{
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s0e0", Type: "NodeEnclosure", RfEndpointID: "x0c0s0b0", RedfishType: rf.ChassisType},
+		URL:                "x0c0s0b0/redfish/v1/Chassis/Self",
+		RedfishChassisInfo: &rf.ComponentChassisInfo{Sensors: []*rf.ThermalSensorInfo{temp, fan}},
+	}, {
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s0b0n0", Type: "Node", RfEndpointID: "x0c0s0b0", RedfishType: rf.ComputerSystemType},
+		URL:               "x0c0s0b0/redfish/v1/Systems/Self",
+		RedfishSystemInfo: &rf.ComponentSystemInfo{Sensors: []*rf.ThermalSensorInfo{temp}},
+	}, {
+		ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s1b0n0", Type: "Node", RfEndpointID: "x0c0s1b0", RedfishType: rf.ComputerSystemType},
+		RedfishSystemInfo: &rf.ComponentSystemInfo{},
+	}}
+
+	tests := []struct {
+		reqURI         string
+		expectedFilter *hmsds.CompEPFilter
+		expectedCode   int
+		expectedResp   []byte
+	}{{
+		reqURI: "https://localhost/hsm/v2/Inventory/ThermalSensors?type=Node&type=NodeEnclosure",
+		expectedFilter: &hmsds.CompEPFilter{
+			Type:        []string{"Node", "NodeEnclosure"},
+			RedfishType: []string{rf.ChassisType, rf.ComputerSystemType},
+		},
+		expectedCode: http.StatusOK,
+		expectedResp: json.RawMessage(`{"Components":[` +
+			`{"ID":"x0c0s0e0","Type":"NodeEnclosure","RedfishEndpointID":"x0c0s0b0","RedfishURL":"x0c0s0b0/redfish/v1/Chassis/Self","Sensors":[{"Kind":"Temperature","MemberId":"0","Name":"CPU1 Temp"},{"Kind":"Fan","MemberId":"0","Name":"FAN1","ReadingUnits":"RPM"}]},` +
+			`{"ID":"x0c0s0b0n0","Type":"Node","RedfishEndpointID":"x0c0s0b0","RedfishURL":"x0c0s0b0/redfish/v1/Systems/Self","Sensors":[{"Kind":"Temperature","MemberId":"0","Name":"CPU1 Temp"}]}]}`),
+	}, {
+		reqURI: "https://localhost/hsm/v2/Inventory/ThermalSensors?kind=fan",
+		expectedFilter: &hmsds.CompEPFilter{
+			RedfishType: []string{rf.ChassisType, rf.ComputerSystemType},
+		},
+		expectedCode: http.StatusOK,
+		expectedResp: json.RawMessage(`{"Components":[` +
+			`{"ID":"x0c0s0e0","Type":"NodeEnclosure","RedfishEndpointID":"x0c0s0b0","RedfishURL":"x0c0s0b0/redfish/v1/Chassis/Self","Sensors":[{"Kind":"Fan","MemberId":"0","Name":"FAN1","ReadingUnits":"RPM"}]}]}`),
+	}, {
+		reqURI:       "https://localhost/hsm/v2/Inventory/ThermalSensors?kind=voltage",
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"kind must be Temperature or Fan","status":400}`),
+	}}
+
+	for i, test := range tests {
+		results.GetCompEndpointsFilter.Input.f = nil
+		results.GetCompEndpointsFilter.Return.entries = ceps
+		results.GetCompEndpointsFilter.Return.err = nil
+
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if test.expectedFilter != nil &&
+			!reflect.DeepEqual(test.expectedFilter, results.GetCompEndpointsFilter.Input.f) {
+			t.Errorf("Test %v Failed: Expected filter is '%v'; Received '%v'", i, test.expectedFilter, results.GetCompEndpointsFilter.Input.f)
+		}
+		if strings.TrimSpace(string(test.expectedResp)) !=
+			strings.TrimSpace(string(w.Body.Bytes())) {
+
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'",
+				i, string(test.expectedResp), w.Body)
+		}
+	}
+	results.GetCompEndpointsFilter.Return.entries = nil
+}
+
 func TestDoComponentEndpointDelete(t *testing.T) {
 	tests := []struct {
 		reqType         string
@@ -8338,6 +8928,94 @@ func TestDoGroupGet(t *testing.T) {
 	}
 }
 
+func TestDoGroupGetRecursive(t *testing.T) {
+	defer func() {
+		results.GetGroup.Return.byLabel = nil
+		results.GetGroupChildren.Return.children = nil
+		results.GetGroupChildren.Return.err = nil
+		results.InsertGroup.Return.err = nil
+		results.UpdateGroup.Return.err = nil
+	}()
+	results.GetGroup.Return.err = nil
+	results.GetGroup.Return.byLabel = map[string]*sm.Group{
+		"rack-x1000": {
+			Label:   "rack-x1000",
+			Members: sm.Members{IDs: []string{"x1000c0s0b0n0"}},
+		},
+		"chassis-x1000c0": {
+			Label:   "chassis-x1000c0",
+			Members: sm.Members{IDs: []string{"x1000c0s0b0n0", "x1000c0s1b0n0"}},
+		},
+		"chassis-x1000c1": {
+			Label:   "chassis-x1000c1",
+			Members: sm.Members{IDs: []string{"x1000c1s0b0n0"}},
+		},
+	}
+	results.GetGroupChildren.Return.children = []string{"chassis-x1000c0", "chassis-x1000c1"}
+
+	tests := []struct {
+		method       string
+		reqURI       string
+		body         string
+		hmsdsErr     error
+		expectedCode int
+		expectedResp string
+	}{{
+		method:       "GET",
+		reqURI:       "https://localhost/hsm/v2/groups/rack-x1000",
+		expectedCode: http.StatusOK,
+		expectedResp: `{"label":"rack-x1000","description":"","children":["chassis-x1000c0","chassis-x1000c1"],"members":{"ids":["x1000c0s0b0n0"]}}` + "\n",
+	}, {
+		method:       "GET",
+		reqURI:       "https://localhost/hsm/v2/groups/rack-x1000?recursive=true",
+		expectedCode: http.StatusOK,
+		expectedResp: `{"label":"rack-x1000","description":"","children":["chassis-x1000c0","chassis-x1000c1"],"members":{"ids":["x1000c0s0b0n0","x1000c0s1b0n0","x1000c1s0b0n0"]}}` + "\n",
+	}, {
+		method:       "GET",
+		reqURI:       "https://localhost/hsm/v2/groups/rack-x1000/members?recursive=true",
+		expectedCode: http.StatusOK,
+		expectedResp: `{"ids":["x1000c0s0b0n0","x1000c0s1b0n0","x1000c1s0b0n0"]}` + "\n",
+	}, {
+		method:       "GET",
+		reqURI:       "https://localhost/hsm/v2/groups/rack-x1000?recursive=maybe",
+		expectedCode: http.StatusBadRequest,
+	}, {
+		method:       "POST",
+		reqURI:       "https://localhost/hsm/v2/groups",
+		body:         `{"label":"rack-x1000","children":["Rack-X1000"],"members":{"ids":[]}}`,
+		expectedCode: http.StatusBadRequest,
+	}, {
+		method:       "PATCH",
+		reqURI:       "https://localhost/hsm/v2/groups/chassis-x1000c0",
+		body:         `{"children":["rack-x1000"]}`,
+		hmsdsErr:     hmsds.ErrHMSDSGroupCycle,
+		expectedCode: http.StatusConflict,
+	}, {
+		method:       "PATCH",
+		reqURI:       "https://localhost/hsm/v2/groups/rack-x1000",
+		body:         `{"children":["chassis-x1000c0","chassis-x1000c1"]}`,
+		expectedCode: http.StatusNoContent,
+	}}
+	for i, test := range tests {
+		results.UpdateGroup.Return.err = test.hmsdsErr
+		req, _ := http.NewRequest(test.method, test.reqURI, bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Expected status code %v, got %v (%s)",
+				i, test.expectedCode, w.Code, w.Body.String())
+		}
+		if test.expectedResp != "" && w.Body.String() != test.expectedResp {
+			t.Errorf("Test %v Failed: Expected body '%s', got '%s'",
+				i, test.expectedResp, w.Body.String())
+		}
+	}
+	if gp := results.UpdateGroup.Input.gp; gp == nil || gp.Children == nil ||
+		len(*gp.Children) != 2 {
+		t.Errorf("Expected children to be patched, got %+v", gp)
+	}
+}
+
 func TestDoGroupDelete(t *testing.T) {
 	tests := []struct {
 		reqType       string
@@ -8871,6 +9549,93 @@ func TestDoGroupMemberDelete(t *testing.T) {
 	}
 }
 
+func TestDoGroupHistoryGet(t *testing.T) {
+	defer func() {
+		results.GetGroupHist.Return.hist = nil
+		results.GetGroupHist.Return.err = nil
+	}()
+	tests := []struct {
+		reqURI         string
+		hmsdsResp      []*sm.MemberHistEntry
+		hmsdsRespErr   error
+		expectedLabel  string
+		expectedFilter *hmsds.MemberHistFilter
+		expectedCode   int
+		expectedResp   []byte
+	}{{
+		reqURI: "https://localhost/hsm/v2/groups/My_Group/history",
+		hmsdsResp: []*sm.MemberHistEntry{
+			{ID: "x0c0s1b0n0", Action: sm.MemberHistAdded, Actor: "alice", Timestamp: "2024-01-01T00:00:00Z"},
+			{ID: "x0c0s1b0n0", Action: sm.MemberHistRemoved, Actor: "bob", Timestamp: "2024-01-02T00:00:00Z"},
+		},
+		expectedLabel:  "my_group",
+		expectedFilter: &hmsds.MemberHistFilter{},
+		expectedCode:   http.StatusOK,
+		expectedResp:   json.RawMessage(`{"label":"my_group","history":[{"id":"x0c0s1b0n0","action":"added","actor":"alice","timestamp":"2024-01-01T00:00:00Z"},{"id":"x0c0s1b0n0","action":"removed","actor":"bob","timestamp":"2024-01-02T00:00:00Z"}]}` + "\n"),
+	}, {
+		reqURI:        "https://localhost/hsm/v2/groups/my_group/history?id=x0c0s1b0n0&id=x0c0s2b0n0&starttime=2024-01-01T00:00:00Z&endtime=2024-02-01T00:00:00Z",
+		hmsdsResp:     []*sm.MemberHistEntry{},
+		expectedLabel: "my_group",
+		expectedFilter: &hmsds.MemberHistFilter{
+			ID:        []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+			StartTime: "2024-01-01T00:00:00Z",
+			EndTime:   "2024-02-01T00:00:00Z",
+		},
+		expectedCode: http.StatusOK,
+		expectedResp: json.RawMessage(`{"label":"my_group","history":[]}` + "\n"),
+	}, {
+		reqURI:        "https://localhost/hsm/v2/groups/my_group/history?starttime=yesterday",
+		hmsdsRespErr:  hmsds.ErrHMSDSArgBadTimeFormat,
+		expectedLabel: "my_group",
+		expectedFilter: &hmsds.MemberHistFilter{
+			StartTime: "yesterday",
+		},
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not in a valid RFC3339 time format","status":400}` + "\n"),
+	}, {
+		reqURI:       "https://localhost/hsm/v2/groups/$MyGroup/history",
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid group label.","status":400}` + "\n"),
+	}}
+
+	for i, test := range tests {
+		results.GetGroupHist.Return.hist = test.hmsdsResp
+		results.GetGroupHist.Return.err = test.hmsdsRespErr
+		results.GetGroupHist.Input.label = ""
+		results.GetGroupHist.Input.f = nil
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if test.expectedLabel != results.GetGroupHist.Input.label {
+			t.Errorf("Test %v Failed: Expected label is '%v'; Received '%v'", i, test.expectedLabel, results.GetGroupHist.Input.label)
+		}
+		if test.expectedFilter != nil && !reflect.DeepEqual(test.expectedFilter, results.GetGroupHist.Input.f) {
+			t.Errorf("Test %v Failed: Expected filter is '%+v'; Received '%+v'", i, test.expectedFilter, results.GetGroupHist.Input.f)
+		}
+		if bytes.Compare(test.expectedResp, w.Body.Bytes()) != 0 {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'", i, string(test.expectedResp), w.Body)
+		}
+	}
+
+	// Changes record who made them.
+	results.DeleteGroupMember.Return.didDelete = true
+	results.DeleteGroupMember.Return.err = nil
+	results.WithActor.Input.actor = ""
+	req := httptest.NewRequest("DELETE", "https://localhost/hsm/v2/groups/my_group/members/x0c0s1b0n0", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if results.WithActor.Input.actor != "10.0.0.1:5555" {
+		t.Errorf("Expected actor '10.0.0.1:5555'; Received '%v'", results.WithActor.Input.actor)
+	}
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // Partitions
 //////////////////////////////////////////////////////////////////////////////
@@ -9665,6 +10430,89 @@ func TestDoPartitionMemberDelete(t *testing.T) {
 	}
 }
 
+func TestDoPartitionsMovePost(t *testing.T) {
+	defer func() {
+		results.MovePartitionMembers.Return.ids = nil
+		results.MovePartitionMembers.Return.err = nil
+	}()
+	tests := []struct {
+		reqBody      string
+		hmsdsResp    []string
+		hmsdsRespErr error
+		expectedFrom string
+		expectedTo   string
+		expectedIDs  []string
+		expectedCode int
+		expectedResp []byte
+	}{{
+		reqBody:      `{"from":"P1","to":"p2","ids":["X0C0S1B0N0","x0c0s2b0n0","x0c0s1b0n0"]}`,
+		hmsdsResp:    []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+		expectedFrom: "p1",
+		expectedTo:   "p2",
+		expectedIDs:  []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+		expectedCode: http.StatusOK,
+		expectedResp: json.RawMessage(`{"from":"p1","to":"p2","ids":["x0c0s1b0n0","x0c0s2b0n0"]}` + "\n"),
+	}, {
+		reqBody:      `{"from":"p1","to":"p2","ids":["x0c0s1b0n0"]}`,
+		hmsdsRespErr: hmsds.ErrHMSDSNoPartitionMember,
+		expectedFrom: "p1",
+		expectedTo:   "p2",
+		expectedIDs:  []string{"x0c0s1b0n0"},
+		expectedCode: http.StatusConflict,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Conflict","detail":"one or more components are not members of partition p1","status":409}` + "\n"),
+	}, {
+		reqBody:      `{"from":"p1","to":"p3","ids":["x0c0s1b0n0"]}`,
+		hmsdsRespErr: hmsds.ErrHMSDSNoPartition,
+		expectedFrom: "p1",
+		expectedTo:   "p3",
+		expectedIDs:  []string{"x0c0s1b0n0"},
+		expectedCode: http.StatusNotFound,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Not Found","detail":"No such partition: p1 or p3","status":404}` + "\n"),
+	}, {
+		reqBody:      `{"from":"p1","to":"P1","ids":["x0c0s1b0n0"]}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"couldn't validate request: cannot move members to the partition they are already in","status":400}` + "\n"),
+	}, {
+		reqBody:      `{"from":"p1","to":"p2","ids":[]}`,
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"couldn't validate request: no members to move","status":400}` + "\n"),
+	}, {
+		reqBody:      `{"from":"p1","to":"p2","ids":["foo"]}`,
+		expectedCode: http.StatusBadRequest,
+	}}
+
+	for i, test := range tests {
+		results.MovePartitionMembers.Return.ids = test.hmsdsResp
+		results.MovePartitionMembers.Return.err = test.hmsdsRespErr
+		results.MovePartitionMembers.Input.from = ""
+		results.MovePartitionMembers.Input.to = ""
+		results.MovePartitionMembers.Input.ids = nil
+		req, err := http.NewRequest("POST", "https://localhost/hsm/v2/partitions/move",
+			bytes.NewBufferString(test.reqBody))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if test.expectedFrom != results.MovePartitionMembers.Input.from ||
+			test.expectedTo != results.MovePartitionMembers.Input.to ||
+			!reflect.DeepEqual(test.expectedIDs, results.MovePartitionMembers.Input.ids) {
+			t.Errorf("Test %v Failed: Expected move of %v from '%s' to '%s'; Received %v from '%s' to '%s'",
+				i, test.expectedIDs, test.expectedFrom, test.expectedTo,
+				results.MovePartitionMembers.Input.ids,
+				results.MovePartitionMembers.Input.from,
+				results.MovePartitionMembers.Input.to)
+		}
+		if test.expectedResp != nil && bytes.Compare(test.expectedResp, w.Body.Bytes()) != 0 {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'", i, string(test.expectedResp), w.Body)
+		}
+	}
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // Memberships
 //////////////////////////////////////////////////////////////////////////////
@@ -9894,6 +10742,39 @@ func TestDoCompLocksReservationRemove(t *testing.T) {
 		expectedFilter: sm.CompLockV2Filter{},
 		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid Processing Model","status":400}` + "\n"),
 		expectError:    true,
+	}, {
+		reqBody: json.RawMessage(`{"ComponentIDs":["x3000c0s9b0n0"],"Reason":"node hung in firmware update"}`),
+		hmsdsResp: sm.CompLockV2UpdateResult{
+			Counts: sm.CompLockV2Count{
+				Total:   1,
+				Success: 1,
+				Failure: 0,
+			},
+			Success: sm.CompLockV2SuccessArray{
+				ComponentIDs: []string{"x3000c0s9b0n0"},
+			},
+			Failure: []sm.CompLockV2Failure{},
+		},
+		hmsdsRespErr: nil,
+		expectedFilter: sm.CompLockV2Filter{
+			ID:              []string{"x3000c0s9b0n0"},
+			ProcessingModel: sm.CLProcessingModelRigid,
+			Reason:          "node hung in firmware update",
+		},
+		expectedResp: json.RawMessage(`{"Counts":{"Total":1,"Success":1,"Failure":0},"Success":{"ComponentIDs":["x3000c0s9b0n0"]},"Failure":[]}` + "\n"),
+		expectError:  false,
+	}, {
+		reqBody: json.RawMessage(`{"ComponentIDs":["x3000c0s9b0n0"],"Reason":"` + strings.Repeat("x", 256) + `"}`),
+		hmsdsResp: sm.CompLockV2UpdateResult{
+			Success: sm.CompLockV2SuccessArray{
+				ComponentIDs: []string{},
+			},
+			Failure: []sm.CompLockV2Failure{},
+		},
+		hmsdsRespErr:   sm.ErrCompLockV2BadReason,
+		expectedFilter: sm.CompLockV2Filter{},
+		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Reason must be 255 characters or less","status":400}` + "\n"),
+		expectError:    true,
 	}}
 
 	for i, test := range tests {
@@ -10610,6 +11491,29 @@ func TestDoCompLocksLock(t *testing.T) {
 		expectedFilter: sm.CompLockV2Filter{},
 		expectedResp:   json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"Invalid Processing Model","status":400}` + "\n"),
 		expectError:    true,
+	}, {
+		// Lock a blade and the nodes on it.
+		reqBody: json.RawMessage(`{"ComponentIDs":["x3000c0s9"],"IncludeDescendants":true}`),
+		hmsdsResp: sm.CompLockV2UpdateResult{
+			Counts: sm.CompLockV2Count{
+				Total:   3,
+				Success: 3,
+				Failure: 0,
+			},
+			Success: sm.CompLockV2SuccessArray{
+				ComponentIDs: []string{"x3000c0s9", "x3000c0s9b0n0", "x3000c0s9b0n1"},
+			},
+			Failure: []sm.CompLockV2Failure{},
+		},
+		hmsdsRespErr:   nil,
+		expectedAction: hmsds.CLUpdateActionLock,
+		expectedFilter: sm.CompLockV2Filter{
+			ID:                 []string{"x3000c0s9"},
+			ProcessingModel:    sm.CLProcessingModelRigid,
+			IncludeDescendants: true,
+		},
+		expectedResp: json.RawMessage(`{"Counts":{"Total":3,"Success":3,"Failure":0},"Success":{"ComponentIDs":["x3000c0s9","x3000c0s9b0n0","x3000c0s9b0n1"]},"Failure":[]}` + "\n"),
+		expectError:  false,
 	}}
 
 	for i, test := range tests {
@@ -10644,6 +11548,83 @@ func TestDoCompLocksLock(t *testing.T) {
 	}
 }
 
+func TestDoCompLocksAuditGet(t *testing.T) {
+	defer func() {
+		results.GetCompLockAudit.Return.entries = nil
+		results.GetCompLockAudit.Return.err = nil
+	}()
+	tests := []struct {
+		reqURI         string
+		hmsdsResp      []*sm.CompLockV2AuditEntry
+		hmsdsRespErr   error
+		expectedFilter *hmsds.CompLockAuditFilter
+		expectedCode   int
+		expectedResp   []byte
+	}{{
+		reqURI: "https://localhost/hsm/v2/locks/audit",
+		hmsdsResp: []*sm.CompLockV2AuditEntry{
+			{ID: "x3000c0s9b0n0", Action: sm.CLAuditReserve, Actor: "cfs", ExpirationTime: "2024-01-01T00:15:00Z", Timestamp: "2024-01-01T00:00:00Z"},
+			{ID: "x3000c0s9b0n0", Action: sm.CLAuditForceRelease, Actor: "alice", Reason: "hung", ExpirationTime: "2024-01-01T00:15:00Z", Timestamp: "2024-01-01T00:05:00Z"},
+		},
+		expectedFilter: &hmsds.CompLockAuditFilter{},
+		expectedCode:   http.StatusOK,
+		expectedResp:   json.RawMessage(`{"Entries":[{"ID":"x3000c0s9b0n0","Action":"Reserve","Actor":"cfs","ExpirationTime":"2024-01-01T00:15:00Z","Timestamp":"2024-01-01T00:00:00Z"},{"ID":"x3000c0s9b0n0","Action":"ForceRelease","Actor":"alice","Reason":"hung","ExpirationTime":"2024-01-01T00:15:00Z","Timestamp":"2024-01-01T00:05:00Z"}]}` + "\n"),
+	}, {
+		reqURI:    "https://localhost/hsm/v2/locks/audit?id=x3000c0s9b0n0&action=Lock&action=Unlock&actor=alice&starttime=2024-01-01T00:00:00Z&endtime=2024-02-01T00:00:00Z",
+		hmsdsResp: []*sm.CompLockV2AuditEntry{},
+		expectedFilter: &hmsds.CompLockAuditFilter{
+			ID:        []string{"x3000c0s9b0n0"},
+			Action:    []string{"Lock", "Unlock"},
+			Actor:     "alice",
+			StartTime: "2024-01-01T00:00:00Z",
+			EndTime:   "2024-02-01T00:00:00Z",
+		},
+		expectedCode: http.StatusOK,
+		expectedResp: json.RawMessage(`{"Entries":[]}` + "\n"),
+	}, {
+		reqURI:       "https://localhost/hsm/v2/locks/audit?id=foo",
+		hmsdsRespErr: hmsds.ErrHMSDSArgBadID,
+		expectedFilter: &hmsds.CompLockAuditFilter{
+			ID: []string{"foo"},
+		},
+		expectedCode: http.StatusBadRequest,
+		expectedResp: json.RawMessage(`{"type":"about:blank","title":"Bad Request","detail":"bad query param: Argument was not a valid xname ID","status":400}` + "\n"),
+	}}
+
+	for i, test := range tests {
+		results.GetCompLockAudit.Return.entries = test.hmsdsResp
+		results.GetCompLockAudit.Return.err = test.hmsdsRespErr
+		results.GetCompLockAudit.Input.f = nil
+		req, err := http.NewRequest("GET", test.reqURI, nil)
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+		if w.Code != test.expectedCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v", i, w.Code, test.expectedCode)
+		}
+		if !reflect.DeepEqual(test.expectedFilter, results.GetCompLockAudit.Input.f) {
+			t.Errorf("Test %v Failed: Expected filter is '%+v'; Received '%+v'", i, test.expectedFilter, results.GetCompLockAudit.Input.f)
+		}
+		if bytes.Compare(test.expectedResp, w.Body.Bytes()) != 0 {
+			t.Errorf("Test %v Failed: Expected body is '%v'; Received '%v'", i, string(test.expectedResp), w.Body)
+		}
+	}
+
+	// Lock changes record who made them.
+	results.UpdateCompLocksV2.Return.err = nil
+	results.WithActor.Input.actor = ""
+	req := httptest.NewRequest("POST", "https://localhost/hsm/v2/locks/lock",
+		bytes.NewBufferString(`{"ComponentIDs":["x3000c0s9b0n0"]}`))
+	req.RemoteAddr = "10.0.0.1:5555"
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if results.WithActor.Input.actor != "10.0.0.1:5555" {
+		t.Errorf("Expected actor '10.0.0.1:5555'; Received '%v'", results.WithActor.Input.actor)
+	}
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // Power Maps
 //////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,419 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Full state snapshots
+//
+// GET /Admin/Snapshot exports everything HSM knows as one JSON document:
+// components, RedfishEndpoints, ComponentEndpoints, ServiceEndpoints,
+// EthernetInterfaces, groups and partitions with their members (and so the
+// memberships), NodeMaps, PowerMaps and the hardware inventory by location
+// and by FRU.  RedfishEndpoint passwords are left out; credentials live in
+// the secure store and are not part of a snapshot.
+//
+// POST /Admin/Snapshot/Actions/Restore loads a snapshot into an instance
+// with no components, RedfishEndpoints, groups or partitions, e.g. to
+// recover from losing the database or to clone a system for testing.
+// Restored endpoints need their credentials to be in the secure store before
+// they can be rediscovered.
+//
+// Snapshots carry a Version, bumped whenever the format changes in a way
+// older code can't read, and a restore refuses versions it doesn't know.
+// Like the batch RedfishEndpoint delete, a restore is not one transaction:
+// if it fails part way, what was already restored stays, and the error says
+// which step failed.  Clear the instance before trying again.
+///////////////////////////////////////////////////////////////////////////////
+
+// Current snapshot format
+const SnapshotVersion = 1
+
+// Output of GET /Admin/Snapshot and input of a restore
+type Snapshot struct {
+	Version int    `json:"Version"`
+	Created string `json:"Created"`
+
+	Components            []*base.Component        `json:"Components"`
+	RedfishEndpoints      []*sm.RedfishEndpoint    `json:"RedfishEndpoints"`
+	ComponentEndpoints    []*sm.ComponentEndpoint  `json:"ComponentEndpoints"`
+	ServiceEndpoints      []*sm.ServiceEndpoint    `json:"ServiceEndpoints"`
+	EthernetInterfaces    []*sm.CompEthInterfaceV2 `json:"EthernetInterfaces"`
+	Groups                []*sm.Group              `json:"Groups"`
+	Partitions            []*sm.Partition          `json:"Partitions"`
+	NodeMaps              []*sm.NodeMap            `json:"NodeMaps"`
+	PowerMaps             []*sm.PowerMap           `json:"PowerMaps"`
+	HWInventoryByLocation []*sm.HWInvByLoc         `json:"HWInventoryByLocation"`
+	HWInventoryByFRU      []*sm.HWInvByFRU         `json:"HWInventoryByFRU"`
+}
+
+// Output of a restore: how many of each kind of thing were restored, and,
+// if it failed, at which step and, for groups, partitions and spare FRUs,
+// with which one.
+type SnapshotRestoreResult struct {
+	Restored map[string]int `json:"Restored"`
+	Failed   string         `json:"Failed,omitempty"`
+	Error    string         `json:"Error,omitempty"`
+}
+
+// Read the whole state of the system.
+func (s *SmD) getSnapshot(now time.Time) (*Snapshot, error) {
+	var err error
+	snap := &Snapshot{
+		Version: SnapshotVersion,
+		Created: now.UTC().Format(time.RFC3339),
+	}
+	if snap.Components, err = s.db.GetComponentsAll(); err != nil {
+		return nil, err
+	}
+	if snap.RedfishEndpoints, err = s.db.GetRFEndpointsAll(); err != nil {
+		return nil, err
+	}
+	for _, ep := range snap.RedfishEndpoints {
+		ep.Password = ""
+		ep.ComponentEndpoints = nil
+		ep.ServiceEndpoints = nil
+	}
+	if snap.ComponentEndpoints, err = s.db.GetCompEndpointsAll(); err != nil {
+		return nil, err
+	}
+	if snap.ServiceEndpoints, err = s.db.GetServiceEndpointsAll(); err != nil {
+		return nil, err
+	}
+	if snap.EthernetInterfaces, err = s.db.GetCompEthInterfaceFilter(); err != nil {
+		return nil, err
+	}
+
+	labels, err := s.db.GetGroupLabels()
+	if err != nil {
+		return nil, err
+	}
+	snap.Groups = make([]*sm.Group, 0, len(labels))
+	for _, label := range labels {
+		group, err := s.db.GetGroup(label, "")
+		if err != nil {
+			return nil, err
+		} else if group == nil {
+			// Deleted since we got the labels
+			continue
+		}
+		if group.Children, err = s.db.GetGroupChildren(label, false); err != nil {
+			return nil, err
+		}
+		snap.Groups = append(snap.Groups, group)
+	}
+	pnames, err := s.db.GetPartitionNames()
+	if err != nil {
+		return nil, err
+	}
+	snap.Partitions = make([]*sm.Partition, 0, len(pnames))
+	for _, pname := range pnames {
+		part, err := s.db.GetPartition(pname)
+		if err != nil {
+			return nil, err
+		} else if part == nil {
+			continue
+		}
+		snap.Partitions = append(snap.Partitions, part)
+	}
+
+	if snap.NodeMaps, err = s.db.GetNodeMapsAll(); err != nil {
+		return nil, err
+	}
+	if snap.PowerMaps, err = s.db.GetPowerMapsAll(); err != nil {
+		return nil, err
+	}
+	if snap.HWInventoryByLocation, err = s.db.GetHWInvByLocAll(); err != nil {
+		return nil, err
+	}
+	if snap.HWInventoryByFRU, err = s.db.GetHWInvByFRUAll(); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Returns a description of what the instance already has, or "" if it is
+// empty enough to restore a snapshot into.
+func (s *SmD) snapshotRestoreConflict() (string, error) {
+	counts, err := s.db.GetComponentCounts()
+	if err != nil {
+		return "", err
+	} else if len(counts) > 0 {
+		return "components", nil
+	}
+	eps, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		return "", err
+	} else if len(eps) > 0 {
+		return "RedfishEndpoints", nil
+	}
+	labels, err := s.db.GetGroupLabels()
+	if err != nil {
+		return "", err
+	} else if len(labels) > 0 {
+		return "groups", nil
+	}
+	pnames, err := s.db.GetPartitionNames()
+	if err != nil {
+		return "", err
+	} else if len(pnames) > 0 {
+		return "partitions", nil
+	}
+	return "", nil
+}
+
+// Order groups so that every group comes after its children.  Groups whose
+// children are missing or form a cycle are put last, where inserting them
+// fails with the usual error.
+func snapshotGroupOrder(groups []*sm.Group) []*sm.Group {
+	ordered := make([]*sm.Group, 0, len(groups))
+	done := make(map[string]bool)
+	left := groups
+	for len(left) > 0 {
+		next := left[:0:0]
+		for _, g := range left {
+			ready := true
+			for _, child := range g.Children {
+				if !done[sm.NormalizeGroupField(child)] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, g)
+				done[sm.NormalizeGroupField(g.Label)] = true
+			} else {
+				next = append(next, g)
+			}
+		}
+		if len(next) == len(left) {
+			return append(ordered, next...)
+		}
+		left = next
+	}
+	return ordered
+}
+
+// Load snap into the database, returning how much of each kind was
+// restored, and the step that failed if one did.
+func (s *SmD) restoreSnapshot(snap *Snapshot, actor string) (*SnapshotRestoreResult, error) {
+	db := s.db.WithActor(actor)
+	res := &SnapshotRestoreResult{Restored: make(map[string]int)}
+
+	// FRUs not in any location, i.e. spare parts, aren't inserted along
+	// with the locations.
+	populated := make(map[string]bool)
+	for _, hl := range snap.HWInventoryByLocation {
+		if hl.PopulatedFRU != nil {
+			populated[hl.PopulatedFRU.FRUID] = true
+		}
+	}
+	spares := []*sm.HWInvByFRU{}
+	for _, hf := range snap.HWInventoryByFRU {
+		if !populated[hf.FRUID] {
+			spares = append(spares, hf)
+		}
+	}
+	powerMaps := make([]sm.PowerMap, 0, len(snap.PowerMaps))
+	for _, m := range snap.PowerMaps {
+		powerMaps = append(powerMaps, *m)
+	}
+
+	steps := []struct {
+		name  string
+		count int
+		run   func() (string, error)
+	}{{
+		"RedfishEndpoints", len(snap.RedfishEndpoints), func() (string, error) {
+			return "", db.InsertRFEndpoints(&sm.RedfishEndpointArray{
+				RedfishEndpoints: snap.RedfishEndpoints,
+			})
+		},
+	}, {
+		"Components", len(snap.Components), func() (string, error) {
+			_, err := db.InsertComponents(&base.ComponentArray{
+				Components: snap.Components,
+			})
+			return "", err
+		},
+	}, {
+		"ComponentEndpoints", len(snap.ComponentEndpoints), func() (string, error) {
+			return "", db.UpsertCompEndpoints(&sm.ComponentEndpointArray{
+				ComponentEndpoints: snap.ComponentEndpoints,
+			})
+		},
+	}, {
+		"ServiceEndpoints", len(snap.ServiceEndpoints), func() (string, error) {
+			return "", db.UpsertServiceEndpoints(&sm.ServiceEndpointArray{
+				ServiceEndpoints: snap.ServiceEndpoints,
+			})
+		},
+	}, {
+		"EthernetInterfaces", len(snap.EthernetInterfaces), func() (string, error) {
+			return "", db.InsertCompEthInterfaces(snap.EthernetInterfaces)
+		},
+	}, {
+		"HWInventoryByLocation", len(snap.HWInventoryByLocation), func() (string, error) {
+			return "", db.InsertHWInvByLocs(snap.HWInventoryByLocation)
+		},
+	}, {
+		"HWInventoryByFRU", len(snap.HWInventoryByFRU), func() (string, error) {
+			for _, hf := range spares {
+				if err := db.InsertHWInvByFRU(hf); err != nil {
+					return hf.FRUID, err
+				}
+			}
+			return "", nil
+		},
+	}, {
+		"Groups", len(snap.Groups), func() (string, error) {
+			for _, g := range snapshotGroupOrder(snap.Groups) {
+				group, err := sm.NewGroup(g.Label, g.Description,
+					g.ExclusiveGroup, g.Tags, g.Members.IDs)
+				if err != nil {
+					return g.Label, err
+				}
+				group.Children = g.Children
+				if _, err := db.InsertGroup(group); err != nil {
+					return g.Label, err
+				}
+			}
+			return "", nil
+		},
+	}, {
+		"Partitions", len(snap.Partitions), func() (string, error) {
+			for _, p := range snap.Partitions {
+				part, err := sm.NewPartition(p.Name, p.Description, p.Tags,
+					p.Members.IDs)
+				if err != nil {
+					return p.Name, err
+				}
+				if _, err := db.InsertPartition(part); err != nil {
+					return p.Name, err
+				}
+			}
+			return "", nil
+		},
+	}, {
+		"NodeMaps", len(snap.NodeMaps), func() (string, error) {
+			return "", db.InsertNodeMaps(&sm.NodeMapArray{NodeMaps: snap.NodeMaps})
+		},
+	}, {
+		"PowerMaps", len(powerMaps), func() (string, error) {
+			return "", db.InsertPowerMaps(powerMaps)
+		},
+	}}
+	for _, step := range steps {
+		if step.count == 0 {
+			continue
+		}
+		if item, err := step.run(); err != nil {
+			res.Failed = step.name
+			res.Error = err.Error()
+			if item != "" {
+				res.Error = item + ": " + res.Error
+			}
+			return res, err
+		}
+		res.Restored[step.name] = step.count
+	}
+	return res, nil
+}
+
+// Export the whole state of the system
+func (s *SmD) doSnapshotGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	snap, err := s.getSnapshot(time.Now())
+	if err != nil {
+		s.reqLog(r).LogAlways("doSnapshotGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	w.Header().Set("Content-Disposition",
+		"attachment; filename=\"smd-snapshot.json\"")
+	sendJsonObject(w, http.StatusOK, snap)
+}
+
+// Load a snapshot into an empty instance
+func (s *SmD) doSnapshotRestore(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body: "+err.Error())
+		return
+	}
+	snap := new(Snapshot)
+	if err := json.Unmarshal(body, snap); err != nil {
+		sendJsonError(w, http.StatusBadRequest,
+			"error decoding JSON "+err.Error())
+		return
+	}
+	if snap.Version != SnapshotVersion {
+		sendJsonError(w, http.StatusBadRequest,
+			fmt.Sprintf("unsupported snapshot Version %d, expected %d",
+				snap.Version, SnapshotVersion))
+		return
+	}
+	what, err := s.snapshotRestoreConflict()
+	if err != nil {
+		s.reqLog(r).LogAlways("doSnapshotRestore(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	} else if what != "" {
+		sendJsonError(w, http.StatusConflict,
+			"snapshots can only be restored into an empty instance, "+
+				"but this one already has "+what)
+		return
+	}
+	res, err := s.restoreSnapshot(snap, s.requestActor(r))
+	if err != nil {
+		s.reqLog(r).LogAlways("doSnapshotRestore(): restore of %s failed: %s",
+			res.Failed, err)
+		if err == hmsds.ErrHMSDSDuplicateKey ||
+			err == hmsds.ErrHMSDSExclusiveGroup {
+			sendJsonObject(w, http.StatusConflict, res)
+		} else if base.IsHMSError(err) {
+			sendJsonObject(w, http.StatusBadRequest, res)
+		} else {
+			sendJsonObject(w, http.StatusInternalServerError, res)
+		}
+		return
+	}
+	s.reqLog(r).LogAlways("doSnapshotRestore(): restored snapshot of %s: %v",
+		snap.Created, res.Restored)
+	sendJsonObject(w, http.StatusOK, res)
+}
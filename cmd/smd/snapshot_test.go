@@ -0,0 +1,295 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+// Clear errors left behind by other tests in the results of everything
+// snapshots use.
+func resetSnapshotResultErrs() {
+	results.GetComponentsAll.Return.err = nil
+	results.GetRFEndpointsAll.Return.err = nil
+	results.GetCompEndpointsAll.Return.err = nil
+	results.GetServiceEndpointsAll.Return.err = nil
+	results.GetCompEthInterfaceFilter.Return.err = nil
+	results.GetGroupLabels.Return.err = nil
+	results.GetGroup.Return.err = nil
+	results.GetGroupChildren.Return.err = nil
+	results.GetPartitionNames.Return.err = nil
+	results.GetPartition.Return.err = nil
+	results.GetNodeMapsAll.Return.err = nil
+	results.GetPowerMapsAll.Return.err = nil
+	results.GetHWInvByLocAll.Return.err = nil
+	results.GetHWInvByFRUAll.Return.err = nil
+	results.GetComponentCounts.Return.err = nil
+	results.InsertRFEndpoints.Return.err = nil
+	results.InsertComponents.Return.err = nil
+	results.UpsertCompEndpoints.Return.err = nil
+	results.UpsertServiceEndpoints.Return.err = nil
+	results.InsertCompEthInterfaces.Return.err = nil
+	results.InsertHWInvByLocs.Return.err = nil
+	results.InsertHWInvByFRU.Return.err = nil
+	results.InsertGroup.Return.err = nil
+	results.InsertPartition.Return.err = nil
+	results.InsertNodeMaps.Return.err = nil
+	results.InsertPowerMaps.Return.err = nil
+}
+
+func TestSnapshotGroupOrder(t *testing.T) {
+	grp := func(label string, children ...string) *sm.Group {
+		return &sm.Group{Label: label, Children: children}
+	}
+	labels := func(gs []*sm.Group) []string {
+		ls := []string{}
+		for _, g := range gs {
+			ls = append(ls, g.Label)
+		}
+		return ls
+	}
+	tests := []struct {
+		groups   []*sm.Group
+		expected []string
+	}{{
+		groups:   []*sm.Group{},
+		expected: []string{},
+	}, {
+		groups: []*sm.Group{
+			grp("all", "compute", "service"),
+			grp("compute", "gpu"),
+			grp("service"),
+			grp("gpu"),
+		},
+		expected: []string{"service", "gpu", "compute", "all"},
+	}, {
+		// Missing child and a cycle go last
+		groups: []*sm.Group{
+			grp("orphan", "missing"),
+			grp("a", "b"),
+			grp("b", "a"),
+			grp("ok"),
+		},
+		expected: []string{"ok", "orphan", "a", "b"},
+	}}
+	for i, test := range tests {
+		out := labels(snapshotGroupOrder(test.groups))
+		if !reflect.DeepEqual(test.expected, out) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, out)
+		}
+	}
+}
+
+func TestDoSnapshotGet(t *testing.T) {
+	defer func() {
+		results.GetComponentsAll.Return.ids = nil
+		results.GetRFEndpointsAll.Return.entries = nil
+		results.GetGroupLabels.Return.labels = nil
+		results.GetGroup.Return.byLabel = nil
+		results.GetGroupChildren.Return.children = nil
+		results.GetPartitionNames.Return.pnames = nil
+		results.GetPartition.Return.partition = nil
+		results.GetNodeMapsAll.Return.entry = nil
+	}()
+	resetSnapshotResultErrs()
+	results.GetComponentsAll.Return.ids = []*base.Component{
+		{ID: "x0c0s0b0n0", Type: "Node", State: "Ready", NID: json.Number("1")},
+	}
+	ep := new(sm.RedfishEndpoint)
+	ep.ID = "x0c0s0b0"
+	ep.User = "root"
+	ep.Password = "secret"
+	ep.ComponentEndpoints = []*sm.ComponentEndpoint{{}}
+	results.GetRFEndpointsAll.Return.entries = []*sm.RedfishEndpoint{ep}
+	results.GetGroupLabels.Return.labels = []string{"compute"}
+	results.GetGroup.Return.byLabel = map[string]*sm.Group{
+		"compute": {Label: "compute",
+			Members: sm.Members{IDs: []string{"x0c0s0b0n0"}}},
+	}
+	results.GetGroupChildren.Return.children = []string{"gpu"}
+	results.GetPartitionNames.Return.pnames = []string{"p1"}
+	results.GetPartition.Return.partition = &sm.Partition{Name: "p1",
+		Members: sm.Members{IDs: []string{"x0c0s0b0n0"}}}
+	results.GetNodeMapsAll.Return.entry = []*sm.NodeMap{
+		{ID: "x0c0s0b0n0", NID: 1},
+	}
+
+	req, _ := http.NewRequest("GET", "https://localhost/hsm/v2/Admin/Snapshot", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("secret")) {
+		t.Errorf("Snapshot contains a password: %s", w.Body.String())
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(w.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	if snap.Version != SnapshotVersion {
+		t.Errorf("Expected Version %d, got %d", SnapshotVersion, snap.Version)
+	}
+	if _, err := time.Parse(time.RFC3339, snap.Created); err != nil {
+		t.Errorf("Bad Created '%s': %s", snap.Created, err)
+	}
+	if len(snap.Components) != 1 || snap.Components[0].ID != "x0c0s0b0n0" {
+		t.Errorf("Bad Components: %v", snap.Components)
+	}
+	if len(snap.RedfishEndpoints) != 1 ||
+		snap.RedfishEndpoints[0].User != "root" ||
+		snap.RedfishEndpoints[0].ComponentEndpoints != nil {
+		t.Errorf("Bad RedfishEndpoints: %v", snap.RedfishEndpoints)
+	}
+	if len(snap.Groups) != 1 ||
+		!reflect.DeepEqual(snap.Groups[0].Children, []string{"gpu"}) ||
+		!reflect.DeepEqual(snap.Groups[0].Members.IDs, []string{"x0c0s0b0n0"}) {
+		t.Errorf("Bad Groups: %+v", snap.Groups)
+	}
+	if len(snap.Partitions) != 1 || snap.Partitions[0].Name != "p1" {
+		t.Errorf("Bad Partitions: %+v", snap.Partitions)
+	}
+	if len(snap.NodeMaps) != 1 || snap.NodeMaps[0].NID != 1 {
+		t.Errorf("Bad NodeMaps: %+v", snap.NodeMaps)
+	}
+}
+
+func TestDoSnapshotRestore(t *testing.T) {
+	defer func() {
+		results.GetComponentCounts.Return.counts = nil
+		results.InsertComponents.Input.comps = nil
+		results.InsertRFEndpoints.Input.eps = nil
+		results.InsertHWInvByLocs.Input.hls = nil
+		results.InsertHWInvByFRU.Input.hf = nil
+		results.InsertGroup.Input.g = nil
+		results.InsertGroup.Return.err = nil
+		results.InsertPartition.Input.p = nil
+		results.InsertPowerMaps.Input.ms = nil
+	}()
+	resetSnapshotResultErrs()
+	results.GetRFEndpointsAll.Return.entries = nil
+	results.GetGroupLabels.Return.labels = nil
+	results.GetPartitionNames.Return.pnames = nil
+	ep := new(sm.RedfishEndpoint)
+	ep.ID = "x0c0s0b0"
+	snap := Snapshot{
+		Version: SnapshotVersion,
+		Created: "2026-10-01T00:00:00Z",
+		Components: []*base.Component{
+			{ID: "x0c0s0b0n0", Type: "Node", State: "Ready"},
+		},
+		RedfishEndpoints: []*sm.RedfishEndpoint{ep},
+		Groups: []*sm.Group{{Label: "compute",
+			Members: sm.Members{IDs: []string{"x0c0s0b0n0"}}}},
+		Partitions: []*sm.Partition{{Name: "p1",
+			Members: sm.Members{IDs: []string{"x0c0s0b0n0"}}}},
+		PowerMaps: []*sm.PowerMap{
+			{ID: "x0c0s0b0n0", PoweredBy: []string{"x0m0p0j1"}},
+		},
+		HWInventoryByLocation: []*sm.HWInvByLoc{{ID: "x0c0s0b0n0",
+			PopulatedFRU: &sm.HWInvByFRU{FRUID: "fru1"}}},
+		HWInventoryByFRU: []*sm.HWInvByFRU{{FRUID: "fru1"}, {FRUID: "spare"}},
+	}
+	post := func(snap interface{}) (int, *SnapshotRestoreResult) {
+		payload, _ := json.Marshal(snap)
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Admin/Snapshot/Actions/Restore",
+			bytes.NewBuffer(payload))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		res := new(SnapshotRestoreResult)
+		json.Unmarshal(w.Body.Bytes(), res)
+		return w.Code, res
+	}
+
+	// Unknown version
+	code, _ := post(map[string]int{"Version": SnapshotVersion + 1})
+	if code != http.StatusBadRequest {
+		t.Errorf("Expected %d for a bad Version, got %d",
+			http.StatusBadRequest, code)
+	}
+
+	// Not empty
+	results.GetComponentCounts.Return.counts = []*hmsds.ComponentCount{
+		{Type: "Node", State: "Ready", Count: 1},
+	}
+	code, _ = post(snap)
+	if code != http.StatusConflict || results.InsertComponents.Input.comps != nil {
+		t.Errorf("Expected %d and no inserts, got %d", http.StatusConflict, code)
+	}
+	results.GetComponentCounts.Return.counts = nil
+
+	code, res := post(snap)
+	expected := map[string]int{
+		"RedfishEndpoints":      1,
+		"Components":            1,
+		"Groups":                1,
+		"Partitions":            1,
+		"PowerMaps":             1,
+		"HWInventoryByLocation": 1,
+		"HWInventoryByFRU":      2,
+	}
+	if code != http.StatusOK || !reflect.DeepEqual(expected, res.Restored) {
+		t.Errorf("Expected %d %v, got %d %+v", http.StatusOK, expected, code, res)
+	}
+	if comps := results.InsertComponents.Input.comps; comps == nil ||
+		len(comps.Components) != 1 ||
+		comps.Components[0].ID != "x0c0s0b0n0" {
+		t.Errorf("Bad components inserted: %v", comps)
+	}
+	if eps := results.InsertRFEndpoints.Input.eps; eps == nil ||
+		len(eps.RedfishEndpoints) != 1 {
+		t.Errorf("Bad RedfishEndpoints inserted: %v", eps)
+	}
+	if hf := results.InsertHWInvByFRU.Input.hf; hf == nil || hf.FRUID != "spare" {
+		t.Errorf("Expected only the spare FRU to be inserted alone, got %v", hf)
+	}
+	if g := results.InsertGroup.Input.g; g == nil || g.Label != "compute" {
+		t.Errorf("Bad group inserted: %v", g)
+	}
+	if p := results.InsertPartition.Input.p; p == nil || p.Name != "p1" {
+		t.Errorf("Bad partition inserted: %v", p)
+	}
+	if len(results.InsertPowerMaps.Input.ms) != 1 {
+		t.Errorf("Bad PowerMaps inserted: %v", results.InsertPowerMaps.Input.ms)
+	}
+
+	// Failure part way
+	results.InsertGroup.Return.err = hmsds.ErrHMSDSExclusiveGroup
+	code, res = post(snap)
+	if code != http.StatusConflict || res.Failed != "Groups" ||
+		res.Restored["Components"] != 1 || res.Restored["Partitions"] != 0 {
+		t.Errorf("Expected %d failing at Groups, got %d %+v",
+			http.StatusConflict, code, res)
+	}
+}
@@ -123,6 +123,9 @@ type CompUpdate struct {
 	UpdateType   string          `json:"UpdateType,omitempty"`
 	Force        bool            `json:"Force,omitempty"`
 	ExtendedInfo json.RawMessage `json:"ExtendedInfo,omitempty"`
+
+	// Who is making the update, for the component state history
+	actor string
 }
 
 // Update the database based on the input fields and the selected operation.
@@ -159,6 +162,8 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 	pi.Group = append(pi.Group, u.Group...)
 	pi.Partition = append(pi.Partition, u.Partition...)
 
+	db := s.db.WithActor(u.actor)
+
 	var err error
 	switch GetCompUpdateType(u.UpdateType) {
 	case StateDataUpdate:
@@ -171,7 +176,7 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 		}
 		data.State = base.VerifyNormalizeState(u.State)
 		data.Flag = base.VerifyNormalizeFlag(nflag)
-		scnIDs, err = s.dbUpdateCompState(compIDs, u.State, nflag, u.Force, pi)
+		scnIDs, err = s.dbUpdateCompState(db, compIDs, u.State, nflag, u.Force, pi)
 		if err == nil {
 			if data.State == base.StateStandby.String() {
 				// Start State Redfish Polling jobs for any nodes
@@ -197,19 +202,19 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 			return ErrSMDNoFlag
 		}
 		data.Flag = base.VerifyNormalizeFlag(u.Flag)
-		scnIDs, err = s.dbUpdateCompFlagOnly(compIDs, u.Flag, pi)
+		scnIDs, err = s.dbUpdateCompFlagOnly(db, compIDs, u.Flag, pi)
 	case EnabledUpdate:
 		if u.Enabled == nil {
 			return ErrSMDNoEnabled
 		}
 		data.Enabled = u.Enabled
-		scnIDs, err = s.dbUpdateCompEnabled(compIDs, u.Enabled, pi)
+		scnIDs, err = s.dbUpdateCompEnabled(db, compIDs, u.Enabled, pi)
 	case SwStatusUpdate:
 		if u.SwStatus == nil {
 			return ErrSMDNoSwStatus
 		}
 		data.SwStatus = *u.SwStatus
-		scnIDs, err = s.dbUpdateCompSwStatus(compIDs, *u.SwStatus, pi)
+		scnIDs, err = s.dbUpdateCompSwStatus(db, compIDs, *u.SwStatus, pi)
 	case RoleUpdate:
 		subRole := ""
 		if u.Role == nil {
@@ -220,14 +225,14 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 			subRole = *u.SubRole
 			data.SubRole = base.VerifyNormalizeSubRole(subRole)
 		}
-		scnIDs, err = s.dbUpdateCompRole(compIDs, *u.Role, subRole, pi)
+		scnIDs, err = s.dbUpdateCompRole(db, compIDs, *u.Role, subRole, pi)
 	case SingleNIDUpdate:
 		if u.NID == nil {
 			return ErrSMDNoNID
 		}
 		// No SCN ever for NID updates (at the moment)
 		skipSCNs = true
-		err = s.dbUpdateCompSingleNID(compIDs, *u.NID, pi)
+		err = s.dbUpdateCompSingleNID(db, compIDs, *u.NID, pi)
 	default:
 		s.LogAlways("Error: %s: doCompUpdate: bad CompUpdateType: '%s'",
 			name, u.UpdateType)
@@ -236,6 +241,15 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 	if err != nil {
 		return err
 	}
+	if GetCompUpdateType(u.UpdateType) == FlagOnlyUpdate && len(scnIDs) != 0 {
+		// No SCN, but it's streamed.
+		s.compChanged(sm.SCNPayload{Components: scnIDs, Flag: data.Flag})
+	}
+	// Hold back SCNs for components under an endpoint that is being
+	// verified after an SCN storm.
+	if data.State != "" && !skipSCNs {
+		scnIDs = s.scnStorm.Filter(scnIDs, data.State)
+	}
 	// Send SCN if there were changes.
 	if len(scnIDs) != 0 && !skipSCNs {
 		scn := NewJobSCN(scnIDs, data, s)
@@ -248,31 +262,33 @@ func (s *SmD) doCompUpdate(u *CompUpdate, name string) error {
 // because we only have one target and don't need a second query to see if it
 // needs to be changed.  We can just see what happens.
 func (s *SmD) dbUpdateCompState(
+	db hmsds.HMSDB,
 	ids []string,
 	state, flag string,
 	force bool,
 	pi *hmsds.PartInfo,
 ) ([]string, error) {
-	return s.db.UpdateCompStates(ids, state, flag, force, pi)
+	return db.UpdateCompStates(ids, state, flag, force, pi)
 }
 
 // For either single or bulk Flag-only updates (state is not affected).  Single
 // updates are faster because we only have one target and don't need a second
 // query to see if it needs to be changed.  We can just see what happens.
 func (s *SmD) dbUpdateCompFlagOnly(
+	db hmsds.HMSDB,
 	ids []string,
 	flag string,
 	pi *hmsds.PartInfo,
 ) ([]string, error) {
 	if len(ids) == 1 {
-		rowsAffected, err := s.db.UpdateCompFlagOnly(ids[0], flag)
+		rowsAffected, err := db.UpdateCompFlagOnly(ids[0], flag)
 		if rowsAffected != 0 {
 			return []string{ids[0]}, err
 		} else {
 			return []string{}, err
 		}
 	} else if len(ids) > 1 {
-		return s.db.BulkUpdateCompFlagOnly(ids, flag)
+		return db.BulkUpdateCompFlagOnly(ids, flag)
 	}
 	return []string{}, ErrSMDNoIDs
 }
@@ -281,18 +297,19 @@ func (s *SmD) dbUpdateCompFlagOnly(
 // because we only have one target and don't need a second query to see if it
 // needs to be changed.  We can just see what happens.
 func (s *SmD) dbUpdateCompEnabled(
+	db hmsds.HMSDB,
 	ids []string,
 	enabled *bool,
 	pi *hmsds.PartInfo,
 ) ([]string, error) {
 	if len(ids) == 1 {
-		rowsAffected, err := s.db.UpdateCompEnabled(ids[0], *enabled)
+		rowsAffected, err := db.UpdateCompEnabled(ids[0], *enabled)
 		if rowsAffected != 0 {
 			return []string{ids[0]}, err
 		}
 		return []string{}, err
 	} else if len(ids) > 1 {
-		return s.db.BulkUpdateCompEnabled(ids, *enabled)
+		return db.BulkUpdateCompEnabled(ids, *enabled)
 	}
 	return []string{}, ErrSMDNoIDs
 }
@@ -301,18 +318,19 @@ func (s *SmD) dbUpdateCompEnabled(
 // faster because we only have one target and don't need a second query to
 // see if it needs to be changed, we can just see what happens.
 func (s *SmD) dbUpdateCompSwStatus(
+	db hmsds.HMSDB,
 	ids []string,
 	swstatus string,
 	pi *hmsds.PartInfo,
 ) ([]string, error) {
 	if len(ids) == 1 {
-		rowsAffected, err := s.db.UpdateCompSwStatus(ids[0], swstatus)
+		rowsAffected, err := db.UpdateCompSwStatus(ids[0], swstatus)
 		if rowsAffected != 0 {
 			return []string{ids[0]}, err
 		}
 		return []string{}, err
 	} else if len(ids) > 1 {
-		return s.db.BulkUpdateCompSwStatus(ids, swstatus)
+		return db.BulkUpdateCompSwStatus(ids, swstatus)
 	}
 	return []string{}, ErrSMDNoIDs
 }
@@ -321,19 +339,20 @@ func (s *SmD) dbUpdateCompSwStatus(
 // faster because we only have one target and don't need a second query to
 // see if it needs to be changed, we can just see what happens.
 func (s *SmD) dbUpdateCompRole(
+	db hmsds.HMSDB,
 	ids []string,
 	role string,
 	subRole string,
 	pi *hmsds.PartInfo,
 ) ([]string, error) {
 	if len(ids) == 1 {
-		rowsAffected, err := s.db.UpdateCompRole(ids[0], role, subRole)
+		rowsAffected, err := db.UpdateCompRole(ids[0], role, subRole)
 		if rowsAffected != 0 {
 			return []string{ids[0]}, err
 		}
 		return []string{}, err
 	} else if len(ids) > 1 {
-		return s.db.BulkUpdateCompRole(ids, role, subRole)
+		return db.BulkUpdateCompRole(ids, role, subRole)
 	}
 	return []string{}, ErrSMDNoIDs
 }
@@ -341,6 +360,7 @@ func (s *SmD) dbUpdateCompRole(
 // For single node NID updates only.  Obviously we cannot assign the
 // same NID to more than one component.
 func (s *SmD) dbUpdateCompSingleNID(
+	db hmsds.HMSDB,
 	ids []string,
 	nid int64,
 	pi *hmsds.PartInfo,
@@ -350,7 +370,7 @@ func (s *SmD) dbUpdateCompSingleNID(
 			ID:  ids[0],
 			NID: json.Number(strconv.FormatInt(nid, 10)),
 		}
-		err := s.db.UpdateCompNID(&comp)
+		err := db.UpdateCompNID(&comp)
 		return err
 	} else if len(ids) > 1 {
 		return ErrSMDTooManyIDs
@@ -452,7 +472,7 @@ func (s *SmD) doPollRFState(job *Job) {
 					// Read component endpoint credentials from the secure store.
 					cred, err := s.ccs.GetCompCred(data.CompId)
 					if err != nil {
-						s.Log(LOG_INFO, "doPollRFState(%s): Failed to get credentials from Vault - %s",
+						s.Log(LOG_INFO, "doPollRFState(%s): Failed to get credentials from the credential store - %s",
 							data.CompId, err)
 						setErrStatus = true
 						// Retry
@@ -460,7 +480,7 @@ func (s *SmD) doPollRFState(job *Job) {
 					}
 					if len(cred.Username) == 0 {
 						// Receive empty credentials from vault for the component
-						s.Log(LOG_INFO, "doPollRFState(%s): No credentials in Vault - %s",
+						s.Log(LOG_INFO, "doPollRFState(%s): No credentials in the credential store - %s",
 							data.CompId, err)
 						setErrStatus = true
 						// Retry
@@ -532,6 +552,7 @@ func (s *SmD) doPollRFState(job *Job) {
 					update.ComponentIDs = []string{data.CompId}
 					update.UpdateType = StateDataUpdate.String()
 					update.State = base.StateOff.String()
+					update.actor = CompHistActorStatePoll
 					s.doCompUpdate(update, "doPollRFState")
 					// No return here because doCompUpdate() will signal our
 					// cancelChan. We'll wait to stop that way.
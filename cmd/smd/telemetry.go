@@ -0,0 +1,332 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Redfish telemetry
+//
+// When SMD_TELEMETRY_RECEIVER_URL is set, each RedfishEndpoint with a
+// TelemetryService is subscribed (via its EventService) to the
+// MetricReports of all of its MetricReportDefinitions after it has been
+// discovered.  The endpoint POSTs them to
+//
+//     <SMD_TELEMETRY_RECEIVER_URL>/<RedfishEndpoint xname>
+//
+// which should resolve to POST /Telemetry/MetricReports/{xname}.  Power and
+// thermal samples are matched to the ComponentEndpoint whose Redfish URL
+// their MetricProperty falls under, and the latest sample of each metric is
+// kept in memory and returned by GET /Telemetry/Metrics/{xname}.  Nothing
+// is written to the database.
+//
+// BMCs can't get tokens, so the receiver is a public route.  Instead
+// SMD_TELEMETRY_CONTEXT, which must be set along with
+// SMD_TELEMETRY_RECEIVER_URL, is used as the subscription Context and
+// reports with any other Context are rejected.  Without it no reports are
+// accepted.
+///////////////////////////////////////////////////////////////////////////////
+
+// Categories of metrics that are kept.
+const (
+	TelemetryPower   = "Power"
+	TelemetryThermal = "Thermal"
+)
+
+// Latest sample of one metric of a component.
+type TelemetrySample struct {
+	Category          string `json:"Category"`
+	MetricId          string `json:"MetricId"`
+	MetricProperty    string `json:"MetricProperty"`
+	Value             string `json:"Value"`
+	Timestamp         string `json:"Timestamp"`
+	MetricReport      string `json:"MetricReport,omitempty"`
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+}
+
+// Output of GET /Telemetry/Metrics/{xname}
+type TelemetryMetrics struct {
+	ID      string            `json:"ID"`
+	Metrics []TelemetrySample `json:"Metrics"`
+}
+
+// Redfish URL of a ComponentEndpoint, for matching MetricProperties.
+type telemetryTarget struct {
+	odataID string
+	xname   string
+}
+
+type TelemetryStore struct {
+	lock    sync.Mutex
+	targets map[string][]telemetryTarget           // by RedfishEndpoint
+	samples map[string]map[string]*TelemetrySample // by xname, metric
+}
+
+// Category of a metric, or "" if it is neither power nor thermal.
+func telemetryCategory(mv *rf.MetricValue) string {
+	str := strings.ToLower(mv.MetricProperty + " " + mv.MetricId)
+	for _, kw := range []string{"thermal", "temp", "fan", "celsius"} {
+		if strings.Contains(str, kw) {
+			return TelemetryThermal
+		}
+	}
+	for _, kw := range []string{"power", "watts", "energy"} {
+		if strings.Contains(str, kw) {
+			return TelemetryPower
+		}
+	}
+	return ""
+}
+
+// Find the component a MetricProperty belongs to, i.e. the one with the
+// longest Redfish URL the property's resource falls under.  targets must
+// be sorted longest URL first.
+func telemetryXName(targets []telemetryTarget, prop string) string {
+	res := strings.SplitN(prop, "#", 2)[0]
+	res = strings.TrimSuffix(res, "/")
+	for _, t := range targets {
+		if res == t.odataID || strings.HasPrefix(res, t.odataID+"/") {
+			return t.xname
+		}
+	}
+	return ""
+}
+
+// Store the power and thermal samples of a MetricReport from the given
+// RedfishEndpoint, whose ComponentEndpoints are targets.  Returns the
+// number of samples kept.
+func (ts *TelemetryStore) ingest(epID string, targets []telemetryTarget, rpt *rf.MetricReport, now time.Time) int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.samples == nil {
+		ts.samples = make(map[string]map[string]*TelemetrySample)
+	}
+	num := 0
+	for i := range rpt.MetricValues {
+		mv := &rpt.MetricValues[i]
+		cat := telemetryCategory(mv)
+		if cat == "" {
+			continue
+		}
+		xname := telemetryXName(targets, mv.MetricProperty)
+		if xname == "" {
+			continue
+		}
+		sample := &TelemetrySample{
+			Category:          cat,
+			MetricId:          mv.MetricId,
+			MetricProperty:    mv.MetricProperty,
+			Value:             mv.MetricValue,
+			Timestamp:         mv.Timestamp,
+			MetricReport:      rpt.Id,
+			RedfishEndpointID: epID,
+		}
+		if sample.Timestamp == "" {
+			sample.Timestamp = rpt.Timestamp
+		}
+		if sample.Timestamp == "" {
+			sample.Timestamp = now.UTC().Format(time.RFC3339)
+		}
+		if _, ok := ts.samples[xname]; !ok {
+			ts.samples[xname] = make(map[string]*TelemetrySample)
+		}
+		ts.samples[xname][mv.MetricId+"|"+mv.MetricProperty] = sample
+		num++
+	}
+	return num
+}
+
+// Latest samples for xname, optionally of one category only, sorted by
+// MetricProperty.
+func (ts *TelemetryStore) latest(xname, category string) []TelemetrySample {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	samples := []TelemetrySample{}
+	for _, sample := range ts.samples[xname] {
+		if category == "" || strings.EqualFold(category, sample.Category) {
+			samples = append(samples, *sample)
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].MetricProperty != samples[j].MetricProperty {
+			return samples[i].MetricProperty < samples[j].MetricProperty
+		}
+		return samples[i].MetricId < samples[j].MetricId
+	})
+	return samples
+}
+
+// Drop the cached ComponentEndpoint URLs of a RedfishEndpoint, e.g. after
+// it has been rediscovered.
+func (ts *TelemetryStore) forget(epID string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	delete(ts.targets, epID)
+}
+
+// ComponentEndpoint URLs of a RedfishEndpoint, from the database the first
+// time they are needed.
+func (s *SmD) telemetryTargets(epID string) ([]telemetryTarget, error) {
+	s.telemetry.lock.Lock()
+	targets, ok := s.telemetry.targets[epID]
+	s.telemetry.lock.Unlock()
+	if ok {
+		return targets, nil
+	}
+	ceps, err := s.db.GetCompEndpointsFilter(&hmsds.CompEPFilter{
+		RfEndpointID: []string{epID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	targets = make([]telemetryTarget, 0, len(ceps))
+	for _, cep := range ceps {
+		if cep.OdataID != "" {
+			targets = append(targets, telemetryTarget{
+				odataID: strings.TrimSuffix(cep.OdataID, "/"),
+				xname:   cep.ID,
+			})
+		}
+	}
+	sort.SliceStable(targets, func(i, j int) bool {
+		return len(targets[i].odataID) > len(targets[j].odataID)
+	})
+	s.telemetry.lock.Lock()
+	if s.telemetry.targets == nil {
+		s.telemetry.targets = make(map[string][]telemetryTarget)
+	}
+	s.telemetry.targets[epID] = targets
+	s.telemetry.lock.Unlock()
+	return targets, nil
+}
+
+// Subscribe to the MetricReports of a newly discovered RedfishEndpoint, if
+// telemetry is enabled.
+func (s *SmD) telemetrySubscribe(rfEP *rf.RedfishEP) {
+	s.telemetry.forget(rfEP.ID)
+	if s.telemetryURL == "" || rfEP.DiscInfo.LastStatus != rf.DiscoverOK ||
+		rfEP.TelemetryService == nil {
+		return
+	}
+	dest := strings.TrimSuffix(s.telemetryURL, "/") + "/" + rfEP.ID
+	created, err := rfEP.SubscribeMetricReports(dest, s.telemetryCtx)
+	if err == rf.ErrRFNoMetricReports || err == rf.ErrRFNoEventSubscriptions {
+		s.LogAlways("Not subscribing to telemetry from %s: %s", rfEP.ID, err)
+	} else if err != nil {
+		s.LogAlways("Failed to subscribe to telemetry from %s: %s",
+			rfEP.ID, err)
+	} else if created {
+		s.LogAlways("Subscribed to telemetry from %s", rfEP.ID)
+	}
+}
+
+// Receive a MetricReport pushed by a RedfishEndpoint
+func (s *SmD) doTelemetryMetricReportPost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	if !xnametypes.IsHMSCompIDValid(xname) {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname")
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendJsonError(w, http.StatusInternalServerError,
+			"error reading request body")
+		return
+	}
+	var rpt rf.MetricReport
+	if err := json.Unmarshal(body, &rpt); err != nil {
+		if !rf.IsUnmarshalTypeError(err) {
+			s.lg.Printf("doTelemetryMetricReportPost(): Unmarshal: %s", err)
+			sendJsonError(w, http.StatusBadRequest,
+				"error decoding JSON "+err.Error())
+			return
+		}
+		s.lg.Printf("doTelemetryMetricReportPost(%s): bad field(s) skipped: %s",
+			xname, err)
+	}
+	if s.telemetryCtx == "" || subtle.ConstantTimeCompare(
+		[]byte(rpt.Context), []byte(s.telemetryCtx)) != 1 {
+		sendJsonError(w, http.StatusForbidden, "bad Context")
+		return
+	}
+	targets, err := s.telemetryTargets(xname)
+	if err != nil {
+		s.LogAlways("doTelemetryMetricReportPost(): Lookup failure: (%s) %s",
+			xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if len(targets) == 0 {
+		sendJsonError(w, http.StatusNotFound,
+			"no ComponentEndpoints for this RedfishEndpoint")
+		return
+	}
+	num := s.telemetry.ingest(xname, targets, &rpt, time.Now())
+	s.coolingFaultsFromReport(xname, targets, &rpt)
+	sendJsonResponse(w, http.StatusOK, fmt.Sprintf("%d metrics stored", num))
+}
+
+// Get the latest power and thermal samples for a component
+func (s *SmD) doTelemetryMetricsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	category := r.URL.Query().Get("category")
+	if category != "" &&
+		!strings.EqualFold(category, TelemetryPower) &&
+		!strings.EqualFold(category, TelemetryThermal) {
+		sendJsonError(w, http.StatusBadRequest,
+			"category must be Power or Thermal")
+		return
+	}
+	samples := s.telemetry.latest(xname, category)
+	if len(samples) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no metrics for this xname")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, TelemetryMetrics{
+		ID:      xname,
+		Metrics: samples,
+	})
+}
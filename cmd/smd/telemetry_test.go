@@ -0,0 +1,211 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+const testPayloadMetricReport = `{
+	"@odata.id": "/redfish/v1/TelemetryService/MetricReports/PowerMetrics",
+	"Id": "PowerMetrics",
+	"Context": "secret",
+	"Timestamp": "2026-10-17T10:00:00Z",
+	"MetricValues": [{
+		"MetricId": "PowerConsumedWatts",
+		"MetricProperty": "/redfish/v1/Chassis/Self/Power#/PowerControl/0/PowerConsumedWatts",
+		"MetricValue": "412"
+	}, {
+		"MetricId": "CPU1Temp",
+		"MetricProperty": "/redfish/v1/Systems/Self/Processors/CPU1/EnvironmentMetrics#/TemperatureCelsius/Reading",
+		"MetricValue": "61",
+		"Timestamp": "2026-10-17T09:59:58Z"
+	}, {
+		"MetricId": "MemoryUsage",
+		"MetricProperty": "/redfish/v1/Systems/Self/MemorySummary/Metrics#/BandwidthPercent",
+		"MetricValue": "12"
+	}, {
+		"MetricId": "OtherPower",
+		"MetricProperty": "/redfish/v1/Chassis/Other/Power#/PowerControl/0/PowerConsumedWatts",
+		"MetricValue": "10"
+	}]
+}`
+
+func TestTelemetryMetricReports(t *testing.T) {
+	defer func() {
+		results.GetCompEndpointsFilter.Return.entries = nil
+		results.GetCompEndpointsFilter.Return.err = nil
+		s.telemetryCtx = ""
+		s.telemetry = TelemetryStore{}
+	}()
+	s.telemetryCtx = "secret"
+	results.GetCompEndpointsFilter.Return.entries = []*sm.ComponentEndpoint{
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s27e0", OdataID: "/redfish/v1/Chassis/Self"}},
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s27b0n0", OdataID: "/redfish/v1/Systems/Self"}},
+		{ComponentDescription: rf.ComponentDescription{
+			ID: "x0c0s27b0", OdataID: "/redfish/v1/Managers/Self"}},
+	}
+	results.GetCompEndpointsFilter.Return.err = nil
+
+	req, _ := http.NewRequest("POST",
+		"https://localhost/hsm/v2/Telemetry/MetricReports/x0c0s27b0",
+		bytes.NewBufferString(testPayloadMetricReport))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST failed: %d %s", w.Code, w.Body.String())
+	}
+	if results.GetCompEndpointsFilter.Input.f.RfEndpointID[0] != "x0c0s27b0" {
+		t.Errorf("Looked up wrong RedfishEndpoint: %v",
+			results.GetCompEndpointsFilter.Input.f.RfEndpointID)
+	}
+
+	tests := []struct {
+		reqURI   string
+		expCode  int
+		expItems []TelemetrySample
+	}{{
+		"https://localhost/hsm/v2/Telemetry/Metrics/x0c0s27b0n0",
+		http.StatusOK,
+		[]TelemetrySample{{
+			Category:          TelemetryThermal,
+			MetricId:          "CPU1Temp",
+			MetricProperty:    "/redfish/v1/Systems/Self/Processors/CPU1/EnvironmentMetrics#/TemperatureCelsius/Reading",
+			Value:             "61",
+			Timestamp:         "2026-10-17T09:59:58Z",
+			MetricReport:      "PowerMetrics",
+			RedfishEndpointID: "x0c0s27b0",
+		}},
+	}, {
+		"https://localhost/hsm/v2/Telemetry/Metrics/x0c0s27e0?category=power",
+		http.StatusOK,
+		[]TelemetrySample{{
+			Category:          TelemetryPower,
+			MetricId:          "PowerConsumedWatts",
+			MetricProperty:    "/redfish/v1/Chassis/Self/Power#/PowerControl/0/PowerConsumedWatts",
+			Value:             "412",
+			Timestamp:         "2026-10-17T10:00:00Z",
+			MetricReport:      "PowerMetrics",
+			RedfishEndpointID: "x0c0s27b0",
+		}},
+	}, {
+		"https://localhost/hsm/v2/Telemetry/Metrics/x0c0s27e0?category=Thermal",
+		http.StatusNotFound,
+		nil,
+	}, {
+		"https://localhost/hsm/v2/Telemetry/Metrics/x0c0s27e0?category=Voltage",
+		http.StatusBadRequest,
+		nil,
+	}, {
+		"https://localhost/hsm/v2/Telemetry/Metrics/x0c0s27b0",
+		http.StatusNotFound,
+		nil,
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET", test.reqURI, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v",
+				i, w.Code, test.expCode)
+			continue
+		}
+		if test.expCode != http.StatusOK {
+			continue
+		}
+		var metrics TelemetryMetrics
+		if err := json.Unmarshal(w.Body.Bytes(), &metrics); err != nil {
+			t.Errorf("Test %v Failed: Bad JSON: %s", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(metrics.Metrics, test.expItems) {
+			t.Errorf("Test %v Failed: Expected %v; Received %v",
+				i, test.expItems, metrics.Metrics)
+		}
+	}
+}
+
+func TestTelemetryMetricReportContext(t *testing.T) {
+	defer func() {
+		results.GetCompEndpointsFilter.Return.entries = nil
+		s.telemetryCtx = ""
+		s.telemetry = TelemetryStore{}
+	}()
+
+	tests := []struct {
+		ctx     string
+		body    string
+		ceps    []*sm.ComponentEndpoint
+		expCode int
+	}{{
+		// No SMD_TELEMETRY_CONTEXT, so nothing is accepted
+		"",
+		`{"Id": "PowerMetrics", "Context": "", "MetricValues": []}`,
+		[]*sm.ComponentEndpoint{{}},
+		http.StatusForbidden,
+	}, {
+		"secret",
+		`{"Id": "PowerMetrics", "MetricValues": []}`,
+		[]*sm.ComponentEndpoint{{}},
+		http.StatusForbidden,
+	}, {
+		"secret",
+		`{"Id": "PowerMetrics", "Context": "wrong", "MetricValues": []}`,
+		[]*sm.ComponentEndpoint{{}},
+		http.StatusForbidden,
+	}, {
+		"secret",
+		`{"Id": "PowerMetrics", "Context": "secret", "MetricValues": []}`,
+		nil,
+		http.StatusNotFound,
+	}, {
+		"secret",
+		`{"Id": "PowerMetrics", "Context": "secret", "MetricValues": [}`,
+		nil,
+		http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		s.telemetryCtx = test.ctx
+		s.telemetry = TelemetryStore{}
+		results.GetCompEndpointsFilter.Return.entries = test.ceps
+		req, _ := http.NewRequest("POST",
+			"https://localhost/hsm/v2/Telemetry/MetricReports/x0c0s28b0",
+			bytes.NewBufferString(test.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v (%s)",
+				i, w.Code, test.expCode, w.Body.String())
+		}
+	}
+}
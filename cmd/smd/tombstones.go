@@ -0,0 +1,183 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Deleted Components and RedfishEndpoints
+//
+// Deleting a Component or RedfishEndpoint leaves a tombstone behind: a copy
+// of its database row, when it was deleted and by whom.  The tombstones are
+// written by the database itself, so every way of deleting one is covered,
+// including the bulk deletes.  They can be listed under
+// /State/DeletedComponents and /Inventory/DeletedRedfishEndpoints and the
+// most recent one for an ID restored with .../{xname}/Actions/Restore.
+//
+// Only the row itself comes back.  What went with it, like group and
+// partition memberships, ComponentEndpoints or HW inventory, is not
+// restored and has to be recreated, e.g. by rediscovering the endpoint.
+//
+// Tombstones are purged by the leader once they are older than
+// SMD_TOMBSTONE_RETENTION_DAYS, or kept forever if that is 0.
+///////////////////////////////////////////////////////////////////////////////
+
+// How long tombstones are kept by default.
+const DefaultTombstoneKeep = 30 * 24 * time.Hour
+
+// How often the leader looks for tombstones to purge.
+const tombstonePurgeInterval = time.Hour
+
+// Periodically purge tombstones older than s.tombstoneKeep.
+func (s *SmD) StartTombstonePurger() {
+	go func() {
+		for {
+			time.Sleep(tombstonePurgeInterval)
+			if !s.cluster.isLeader() {
+				continue
+			}
+			n, err := s.db.PurgeTombstones(time.Now().Add(-s.tombstoneKeep))
+			if err != nil {
+				s.LogAlways("Tombstone purge failed: %s", err)
+			} else if n > 0 {
+				s.Log(LOG_INFO, "Purged %d tombstones", n)
+			}
+		}
+	}()
+}
+
+// Send the tombstones of the given kind, all of them or only those for the
+// given ?id=... values.
+func (s *SmD) sendTombstones(w http.ResponseWriter, r *http.Request, fname, kind string) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	if err := r.ParseForm(); err != nil {
+		s.reqLog(r).LogAlways("%s(): ParseForm: %s", fname, err)
+		sendJsonError(w, http.StatusInternalServerError,
+			"failed to decode query parameters.")
+		return
+	}
+	ids := []string{""}
+	if len(r.Form["id"]) > 0 {
+		ids = ids[:0]
+		for _, id := range r.Form["id"] {
+			ids = append(ids, xnametypes.NormalizeHMSCompID(id))
+		}
+	}
+	tsa := sm.TombstoneArray{Tombstones: []*sm.Tombstone{}}
+	for _, id := range ids {
+		tss, err := s.db.GetTombstones(kind, id)
+		if err != nil {
+			s.reqLog(r).LogAlways("%s(): Lookup failure: %s", fname, err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		tsa.Tombstones = append(tsa.Tombstones, tss...)
+	}
+	sendJsonObject(w, http.StatusOK, tsa)
+}
+
+// Send the tombstones for {xname}, most recent first.
+func (s *SmD) sendTombstone(w http.ResponseWriter, r *http.Request, fname, kind string) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	tss, err := s.db.GetTombstones(kind, xname)
+	if err != nil {
+		s.reqLog(r).LogAlways("%s(): Lookup failure: (%s) %s", fname, xname, err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if len(tss) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no such xname.")
+		return
+	}
+	sendJsonObject(w, http.StatusOK, sm.TombstoneArray{Tombstones: tss})
+}
+
+// Put back the most recently deleted {xname}.
+func (s *SmD) restoreTombstone(w http.ResponseWriter, r *http.Request, fname, kind string) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.NormalizeHMSCompID(chi.URLParam(r, "xname"))
+	db := s.db.WithActor(s.requestActor(r))
+	didRestore, err := db.RestoreTombstone(kind, xname)
+	if err != nil {
+		s.reqLog(r).LogAlways("%s(): restore failed: (%s) %s", fname, xname, err)
+		if err == hmsds.ErrHMSDSDuplicateKey {
+			sendJsonError(w, http.StatusConflict,
+				"operation would conflict with an existing "+kind+".")
+			return
+		}
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if !didRestore {
+		sendJsonError(w, http.StatusNotFound, "no such xname.")
+		return
+	}
+	s.reqLog(r).LogAlways("%s(): restored %s %s", fname, kind, xname)
+	sendJsonError(w, http.StatusOK, "restored "+kind+" "+xname)
+}
+
+// Get all deleted components, or only some of them with ?id=...
+func (s *SmD) doDeletedComponentsGet(w http.ResponseWriter, r *http.Request) {
+	s.sendTombstones(w, r, "doDeletedComponentsGet", sm.TombstoneComponent)
+}
+
+// Get the tombstones of a single deleted component
+func (s *SmD) doDeletedComponentGet(w http.ResponseWriter, r *http.Request) {
+	s.sendTombstone(w, r, "doDeletedComponentGet", sm.TombstoneComponent)
+}
+
+// Restore the most recently deleted copy of a component
+func (s *SmD) doDeletedComponentRestore(w http.ResponseWriter, r *http.Request) {
+	s.restoreTombstone(w, r, "doDeletedComponentRestore", sm.TombstoneComponent)
+}
+
+// Get all deleted RedfishEndpoints, or only some of them with ?id=...
+func (s *SmD) doDeletedRedfishEndpointsGet(w http.ResponseWriter, r *http.Request) {
+	s.sendTombstones(w, r, "doDeletedRedfishEndpointsGet",
+		sm.TombstoneRedfishEndpoint)
+}
+
+// Get the tombstones of a single deleted RedfishEndpoint
+func (s *SmD) doDeletedRedfishEndpointGet(w http.ResponseWriter, r *http.Request) {
+	s.sendTombstone(w, r, "doDeletedRedfishEndpointGet",
+		sm.TombstoneRedfishEndpoint)
+}
+
+// Restore the most recently deleted copy of a RedfishEndpoint
+func (s *SmD) doDeletedRedfishEndpointRestore(w http.ResponseWriter, r *http.Request) {
+	s.restoreTombstone(w, r, "doDeletedRedfishEndpointRestore",
+		sm.TombstoneRedfishEndpoint)
+}
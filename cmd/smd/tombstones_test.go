@@ -0,0 +1,167 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestDoDeletedComponentsGet(t *testing.T) {
+	defer func() {
+		results.GetTombstones.Input.kind = ""
+		results.GetTombstones.Input.ids = nil
+		results.GetTombstones.Return.tss = nil
+		results.GetTombstones.Return.err = nil
+	}()
+	ts := &sm.Tombstone{
+		Kind:      sm.TombstoneComponent,
+		ID:        "x0c0s0b0n0",
+		DeletedBy: "admin",
+		Deleted:   "2026-10-01T12:00:00Z",
+		Data:      json.RawMessage(`{"id":"x0c0s0b0n0","state":"Ready"}`),
+	}
+	results.GetTombstones.Return.tss = []*sm.Tombstone{ts}
+
+	tests := []struct {
+		url      string
+		ids      []string
+		code     int
+		expected *sm.TombstoneArray
+	}{{
+		url:      "https://localhost/hsm/v2/State/DeletedComponents",
+		ids:      []string{""},
+		code:     http.StatusOK,
+		expected: &sm.TombstoneArray{Tombstones: []*sm.Tombstone{ts}},
+	}, {
+		url:      "https://localhost/hsm/v2/State/DeletedComponents?id=x0c0s0b0n0&id=X0C0S1B0N0",
+		ids:      []string{"x0c0s0b0n0", "x0c0s1b0n0"},
+		code:     http.StatusOK,
+		expected: &sm.TombstoneArray{Tombstones: []*sm.Tombstone{ts, ts}},
+	}, {
+		url:      "https://localhost/hsm/v2/State/DeletedComponents/X0C0S0B0N0",
+		ids:      []string{"x0c0s0b0n0"},
+		code:     http.StatusOK,
+		expected: &sm.TombstoneArray{Tombstones: []*sm.Tombstone{ts}},
+	}}
+	for i, test := range tests {
+		results.GetTombstones.Input.ids = nil
+		req, _ := http.NewRequest("GET", test.url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if results.GetTombstones.Input.kind != sm.TombstoneComponent ||
+			!reflect.DeepEqual(test.ids, results.GetTombstones.Input.ids) {
+			t.Errorf("Test %d: expected lookup of %v, got %s %v", i, test.ids,
+				results.GetTombstones.Input.kind, results.GetTombstones.Input.ids)
+		}
+		var tsa sm.TombstoneArray
+		if err := json.Unmarshal(w.Body.Bytes(), &tsa); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, &tsa) {
+			t.Errorf("Test %d: expected %+v, got %+v", i, test.expected, &tsa)
+		}
+	}
+
+	// Nothing deleted with that ID
+	results.GetTombstones.Return.tss = []*sm.Tombstone{}
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/DeletedRedfishEndpoints/x0c0s0b0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound ||
+		results.GetTombstones.Input.kind != sm.TombstoneRedfishEndpoint {
+		t.Errorf("Expected %d for %s, got %d for %s", http.StatusNotFound,
+			sm.TombstoneRedfishEndpoint, w.Code,
+			results.GetTombstones.Input.kind)
+	}
+}
+
+func TestDoDeletedRestore(t *testing.T) {
+	defer func() {
+		results.RestoreTombstone.Input.kind = ""
+		results.RestoreTombstone.Input.id = ""
+		results.RestoreTombstone.Return.restored = false
+		results.RestoreTombstone.Return.err = nil
+	}()
+	tests := []struct {
+		url      string
+		restored bool
+		err      error
+		kind     string
+		id       string
+		code     int
+	}{{
+		url:      "https://localhost/hsm/v2/State/DeletedComponents/X0C0S0B0N0/Actions/Restore",
+		restored: true,
+		kind:     sm.TombstoneComponent,
+		id:       "x0c0s0b0n0",
+		code:     http.StatusOK,
+	}, {
+		url:      "https://localhost/hsm/v2/Inventory/DeletedRedfishEndpoints/x0c0s0b0/Actions/Restore",
+		restored: true,
+		kind:     sm.TombstoneRedfishEndpoint,
+		id:       "x0c0s0b0",
+		code:     http.StatusOK,
+	}, {
+		url:      "https://localhost/hsm/v2/State/DeletedComponents/x0c0s0b0n0/Actions/Restore",
+		restored: false,
+		kind:     sm.TombstoneComponent,
+		id:       "x0c0s0b0n0",
+		code:     http.StatusNotFound,
+	}, {
+		url:  "https://localhost/hsm/v2/State/DeletedComponents/x0c0s0b0n0/Actions/Restore",
+		err:  hmsds.ErrHMSDSDuplicateKey,
+		kind: sm.TombstoneComponent,
+		id:   "x0c0s0b0n0",
+		code: http.StatusConflict,
+	}}
+	for i, test := range tests {
+		results.RestoreTombstone.Return.restored = test.restored
+		results.RestoreTombstone.Return.err = test.err
+		req, _ := http.NewRequest("POST", test.url, strings.NewReader(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+		}
+		if results.RestoreTombstone.Input.kind != test.kind ||
+			results.RestoreTombstone.Input.id != test.id {
+			t.Errorf("Test %d: expected restore of %s %s, got %s %s", i,
+				test.kind, test.id, results.RestoreTombstone.Input.kind,
+				results.RestoreTombstone.Input.id)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Topology
+//
+// GET /State/Topology/{xname} returns the components at and below a
+// cabinet, chassis, slot, etc. as a containment tree, with the state, role,
+// NID and BMC FQDN of each node, so drawing a rack takes one request.  It
+// takes one query for the components and one for the BMCs' endpoints.
+//
+// Levels that aren't in HSM, e.g. a node enclosure that was never
+// discovered, are filled in with just ID and Type so every component hangs
+// off its parent.  Children are sorted by xname, numerically, so s2 comes
+// before s10.
+///////////////////////////////////////////////////////////////////////////////
+
+// Build the tree rooted at root from comps, which are root and components
+// below it.  fqdns has the FQDNs of the BMCs' endpoints.
+func newTopology(root string, comps []*base.Component, fqdns map[string]string) *sm.Topology {
+	topo := &sm.Topology{NodeStates: map[string]int{}}
+	byID := map[string]*sm.TopologyNode{}
+
+	// Get the tree node for id, adding it and any missing levels above it.
+	var get func(id string) *sm.TopologyNode
+	get = func(id string) *sm.TopologyNode {
+		if tn, ok := byID[id]; ok {
+			return tn
+		}
+		tn := &sm.TopologyNode{ID: id, Type: xnametypes.GetHMSTypeString(id)}
+		byID[id] = tn
+		if id != root {
+			parent := get(xnametypes.GetHMSCompParent(id))
+			parent.Children = append(parent.Children, tn)
+		}
+		return tn
+	}
+	topo.Root = get(root)
+	for _, comp := range comps {
+		if !xnameutil.Contains(root, comp.ID) {
+			continue
+		}
+		tn := get(comp.ID)
+		tn.Type = comp.Type
+		tn.State = comp.State
+		tn.Flag = comp.Flag
+		tn.Enabled = comp.Enabled
+		tn.Class = comp.Class
+		tn.Role = comp.Role
+		tn.SubRole = comp.SubRole
+		if comp.Type == xnametypes.Node.String() {
+			if nid, err := comp.NID.Int64(); err == nil && nid >= 0 {
+				tn.NID = comp.NID
+			}
+			tn.BMCFQDN = fqdns[xnametypes.GetHMSCompParent(comp.ID)]
+			topo.NodeStates[comp.State]++
+		} else {
+			tn.BMCFQDN = fqdns[comp.ID]
+		}
+	}
+	sortTopology(topo.Root)
+	return topo
+}
+
+func sortTopology(tn *sm.TopologyNode) {
+	sort.Slice(tn.Children, func(i, j int) bool {
+		return xnameLess(tn.Children[i].ID, tn.Children[j].ID)
+	})
+	for _, child := range tn.Children {
+		sortTopology(child)
+	}
+}
+
+// Compare xnames part by part, with the numbers compared as numbers,
+// e.g. x1000c0s2 < x1000c0s10 < x1000c1.
+func xnameLess(a, b string) bool {
+	for a != "" && b != "" {
+		aPart, aNum, aRest := nextXnamePart(a)
+		bPart, bNum, bRest := nextXnamePart(b)
+		if aPart != bPart {
+			return aPart < bPart
+		} else if aNum != bNum {
+			return aNum < bNum
+		}
+		a, b = aRest, bRest
+	}
+	return len(a) < len(b)
+}
+
+// Split the leading letters and number off an xname.
+func nextXnamePart(xname string) (string, int, string) {
+	i := 0
+	for i < len(xname) && (xname[i] < '0' || xname[i] > '9') {
+		i++
+	}
+	j := i
+	for j < len(xname) && xname[j] >= '0' && xname[j] <= '9' {
+		j++
+	}
+	num, _ := strconv.Atoi(xname[i:j])
+	return xname[:i], num, xname[j:]
+}
+
+// Get the containment tree at and below a cabinet, chassis, slot, etc.
+func (s *SmD) doTopologyGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	xname := xnametypes.VerifyNormalizeCompID(chi.URLParam(r, "xname"))
+	if xname == "" || xname == "s0" {
+		sendJsonError(w, http.StatusBadRequest, "invalid xname")
+		return
+	}
+	// With types the query includes the components below xname.
+	f := &hmsds.ComponentFilter{Type: xnametypes.GetHMSTypeList()}
+	comps, err := s.db.GetComponentsQuery(f, hmsds.FLTR_DEFAULT,
+		[]string{xname})
+	if err != nil {
+		s.reqLog(r).LogAlways("doTopologyGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	if len(comps) == 0 {
+		sendJsonError(w, http.StatusNotFound, "no such xname.")
+		return
+	}
+
+	bmcs := map[string]bool{}
+	for _, comp := range comps {
+		if comp.Type == xnametypes.Node.String() {
+			bmcs[xnametypes.GetHMSCompParent(comp.ID)] = true
+		} else if xnametypes.IsHMSTypeController(xnametypes.HMSType(comp.Type)) {
+			bmcs[comp.ID] = true
+		}
+	}
+	fqdns := map[string]string{}
+	if len(bmcs) > 0 {
+		rf := &hmsds.RedfishEPFilter{}
+		for id := range bmcs {
+			rf.ID = append(rf.ID, id)
+		}
+		sort.Strings(rf.ID)
+		eps, err := s.db.GetRFEndpointsFilter(rf)
+		if err != nil {
+			s.reqLog(r).LogAlways("doTopologyGet(): Lookup failure: %s", err)
+			sendJsonDBError(w, "", "", err)
+			return
+		}
+		for _, ep := range eps {
+			fqdns[ep.ID] = ep.FQDN
+		}
+	}
+	sendJsonObject(w, http.StatusOK, newTopology(xname, comps, fqdns))
+}
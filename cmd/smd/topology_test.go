@@ -0,0 +1,172 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/hmsds"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestXnameLess(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"x1000c0s2", "x1000c0s10", true},
+		{"x1000c0s10", "x1000c1", true},
+		{"x1000c0s2b0", "x1000c0s2e0", true},
+		{"x1000c0s2", "x1000c0s2b0", true},
+		{"x1000c0s2b0", "x1000c0s2", false},
+		{"x1000c0", "x1000c0", false},
+	}
+	for i, test := range tests {
+		if got := xnameLess(test.a, test.b); got != test.expected {
+			t.Errorf("Test %d: xnameLess(%s, %s) expected %v, got %v", i,
+				test.a, test.b, test.expected, got)
+		}
+	}
+}
+
+func topologyTestComps() []*base.Component {
+	return []*base.Component{
+		{ID: "x1000c0", Type: "Chassis", State: "On", Class: "Mountain"},
+		{ID: "x1000c0s10b0n0", Type: "Node", State: "Ready", Flag: "OK",
+			Role: "Compute", NID: json.Number("41")},
+		{ID: "x1000c0s2b0", Type: "NodeBMC", State: "Ready"},
+		{ID: "x1000c0s2b0n0", Type: "Node", State: "Ready", Flag: "OK",
+			Role: "Compute", NID: json.Number("9")},
+		{ID: "x1000c0s2b0n1", Type: "Node", State: "Off", Flag: "OK",
+			Role: "Compute", NID: json.Number("10")},
+		{ID: "x1000c0s2", Type: "ComputeModule", State: "On"},
+	}
+}
+
+func TestNewTopology(t *testing.T) {
+	fqdns := map[string]string{"x1000c0s2b0": "x1000c0s2b0.local"}
+	topo := newTopology("x1000c0", topologyTestComps(), fqdns)
+	expected := &sm.Topology{
+		Root: &sm.TopologyNode{ID: "x1000c0", Type: "Chassis", State: "On",
+			Class: "Mountain", Children: []*sm.TopologyNode{{
+				ID: "x1000c0s2", Type: "ComputeModule", State: "On",
+				Children: []*sm.TopologyNode{{
+					ID: "x1000c0s2b0", Type: "NodeBMC", State: "Ready",
+					BMCFQDN: "x1000c0s2b0.local",
+					Children: []*sm.TopologyNode{{
+						ID: "x1000c0s2b0n0", Type: "Node", State: "Ready",
+						Flag: "OK", Role: "Compute", NID: "9",
+						BMCFQDN: "x1000c0s2b0.local",
+					}, {
+						ID: "x1000c0s2b0n1", Type: "Node", State: "Off",
+						Flag: "OK", Role: "Compute", NID: "10",
+						BMCFQDN: "x1000c0s2b0.local",
+					}},
+				}},
+			}, {
+				// Not in HSM
+				ID: "x1000c0s10", Type: "ComputeModule",
+				Children: []*sm.TopologyNode{{
+					ID: "x1000c0s10b0", Type: "NodeBMC",
+					Children: []*sm.TopologyNode{{
+						ID: "x1000c0s10b0n0", Type: "Node", State: "Ready",
+						Flag: "OK", Role: "Compute", NID: "41",
+					}},
+				}},
+			}},
+		},
+		NodeStates: map[string]int{"Ready": 2, "Off": 1},
+	}
+	if !reflect.DeepEqual(expected, topo) {
+		got, _ := json.Marshal(topo)
+		t.Errorf("Expected %+v, got %s", expected, got)
+	}
+}
+
+func TestDoTopologyGet(t *testing.T) {
+	defer func() {
+		results.GetComponentsQuery.Return.ids = nil
+		results.GetComponentsQuery.Return.err = nil
+		results.GetRFEndpointsFilter.Input.f = nil
+		results.GetRFEndpointsFilter.Return.entries = nil
+	}()
+	results.GetComponentsQuery.Return.ids = topologyTestComps()
+	results.GetComponentsQuery.Return.err = nil
+	results.GetRFEndpointsFilter.Return.err = nil
+	results.GetRFEndpointsFilter.Return.entries = []*sm.RedfishEndpoint{{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID:   "x1000c0s2b0",
+			FQDN: "x1000c0s2b0.local",
+		},
+	}}
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/State/Topology/X1000C0", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !reflect.DeepEqual([]string{"x1000c0"},
+		results.GetComponentsQuery.Input.ids) {
+		t.Errorf("Expected a query for x1000c0, got %v",
+			results.GetComponentsQuery.Input.ids)
+	}
+	expectedBMCs := &hmsds.RedfishEPFilter{
+		ID: []string{"x1000c0s10b0", "x1000c0s2b0"},
+	}
+	if !reflect.DeepEqual(expectedBMCs, results.GetRFEndpointsFilter.Input.f) {
+		t.Errorf("Expected endpoint query %+v, got %+v", expectedBMCs,
+			results.GetRFEndpointsFilter.Input.f)
+	}
+	var topo sm.Topology
+	if err := json.Unmarshal(w.Body.Bytes(), &topo); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	node := topo.Root.Children[0].Children[0].Children[0]
+	if node.ID != "x1000c0s2b0n0" || node.BMCFQDN != "x1000c0s2b0.local" {
+		t.Errorf("Unexpected first node %+v", node)
+	}
+
+	results.GetComponentsQuery.Return.ids = []*base.Component{}
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/State/Topology/x1001", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+
+	req, _ = http.NewRequest("GET",
+		"https://localhost/hsm/v2/State/Topology/foo", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
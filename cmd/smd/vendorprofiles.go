@@ -0,0 +1,259 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Vendor profiles for RedfishEndpoint creation
+//
+// Profiles are loaded from the JSON file named by SMD_VENDOR_PROFILES_FILE,
+// which contains a named array:
+//
+//   {"VendorProfiles": [{"Name": "gigabyte", "Port": 443, ...}, ...]}
+//
+// A profile is selected when creating RedfishEndpoints either per endpoint
+// with TemplateID, or for a whole POST with the top-level VendorProfile
+// field.  The profile name is kept in TemplateID so the port, auth style,
+// quirks and HTTP options can be applied each time the endpoint is
+// discovered.
+//
+// A profile can list FallbackCredentialSecrets, keys of other credentials
+// in the secure store that are tried in order during discovery when an
+// endpoint rejects its own.  Endpoints running on one of these are listed
+// by GET /Inventory/FallbackCredentialEndpoints.
+//
+// The file may also have a QuirkRules array, which is added to the
+// built-in quirk registry so that workarounds can be enabled for new
+// vendors and models by Manufacturer, Model, etc.
+///////////////////////////////////////////////////////////////////////////////
+
+// Format of the SMD_VENDOR_PROFILES_FILE and of
+// GET /Inventory/VendorProfiles
+type VendorProfileArray struct {
+	VendorProfiles []*rf.VendorProfile `json:"VendorProfiles"`
+	QuirkRules     []rf.QuirkRule      `json:"QuirkRules,omitempty"`
+}
+
+// Read and verify the vendor profiles file.  Returns the profiles by name.
+func loadVendorProfiles(path string) (map[string]*rf.VendorProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pa VendorProfileArray
+	if err := json.Unmarshal(data, &pa); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	profiles := make(map[string]*rf.VendorProfile, len(pa.VendorProfiles))
+	for _, p := range pa.VendorProfiles {
+		if p == nil {
+			continue
+		}
+		if err := p.Verify(); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+		if _, ok := profiles[p.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate vendor profile %s",
+				path, p.Name)
+		}
+		profiles[p.Name] = p
+	}
+	for _, r := range pa.QuirkRules {
+		if err := rf.RegisterQuirkRule(r); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err)
+		}
+	}
+	return profiles, nil
+}
+
+// Look up a vendor profile by name.  Returns nil if there is no such profile.
+func (s *SmD) getVendorProfile(name string) *rf.VendorProfile {
+	if name == "" || s.vendorProfiles == nil {
+		return nil
+	}
+	return s.vendorProfiles[name]
+}
+
+// Fills in a new RedfishEndpoint from its vendor profile, if it has one.
+// The endpoint's own TemplateID takes precedence over defProfile, which
+// comes from the POST as a whole.  Credentials are only filled in if the
+// endpoint didn't provide any.  credCache holds the credentials already
+// read for each profile during this request so we only look them up once.
+func (s *SmD) applyVendorProfile(
+	rep *rf.RawRedfishEP,
+	defProfile string,
+	credCache map[string]*compcreds.CompCredentials,
+) error {
+	if rep.TemplateID == "" {
+		rep.TemplateID = defProfile
+	}
+	if rep.TemplateID == "" || s.vendorProfiles == nil {
+		// TemplateID is free-form when no profiles are configured.
+		return nil
+	}
+	p := s.getVendorProfile(rep.TemplateID)
+	if p == nil {
+		return fmt.Errorf("no such vendor profile '%s'", rep.TemplateID)
+	}
+	if p.CredentialSecret == "" || rep.User != "" || rep.Password != "" {
+		return nil
+	}
+	cred, ok := credCache[p.Name]
+	if !ok {
+		if s.ccs == nil {
+			return fmt.Errorf("vendor profile %s has a CredentialSecret "+
+				"but secure storage is not enabled", p.Name)
+		}
+		c, err := s.ccs.GetCompCred(p.CredentialSecret)
+		if err != nil {
+			return fmt.Errorf("vendor profile %s: can't read "+
+				"CredentialSecret: %s", p.Name, err)
+		}
+		cred = &c
+		credCache[p.Name] = cred
+	}
+	rep.User = cred.Username
+	rep.Password = cred.Password
+	return nil
+}
+
+// Apply the endpoint's vendor profile, if any, before discovering it.
+func (s *SmD) setDiscoveryVendorProfile(rfEP *rf.RedfishEP) {
+	if rfEP.TemplateID == "" || s.vendorProfiles == nil {
+		return
+	}
+	p := s.getVendorProfile(rfEP.TemplateID)
+	if p == nil {
+		s.LogAlways("Warning: %s: vendor profile '%s' not found, using defaults",
+			rfEP.ID, rfEP.TemplateID)
+		return
+	}
+	rfEP.SetVendorProfile(p)
+}
+
+// Give the endpoint the fallback credentials of its vendor profile, if any,
+// to try during discovery if its own are rejected.
+func (s *SmD) setDiscoveryFallbackCredentials(rfEP *rf.RedfishEP) {
+	p := s.getVendorProfile(rfEP.TemplateID)
+	if p == nil || len(p.FallbackCredentialSecrets) == 0 {
+		return
+	}
+	if s.ccs == nil {
+		s.LogAlways("Warning: %s: vendor profile %s has "+
+			"FallbackCredentialSecrets but secure storage is not enabled",
+			rfEP.ID, p.Name)
+		return
+	}
+	creds := make([]rf.RedfishCredential, 0, len(p.FallbackCredentialSecrets))
+	for _, key := range p.FallbackCredentialSecrets {
+		cred, err := s.ccs.GetCompCred(key)
+		if err != nil {
+			s.LogAlways("Warning: %s: can't read fallback credentials %s: %s",
+				rfEP.ID, key, err)
+			continue
+		}
+		// Don't try empty credentials
+		if len(cred.Password) == 0 {
+			continue
+		}
+		creds = append(creds, rf.RedfishCredential{
+			Source:   key,
+			User:     cred.Username,
+			Password: cred.Password,
+		})
+	}
+	rfEP.SetFallbackCredentials(creds)
+}
+
+// A RedfishEndpoint that was last discovered with fallback credentials.
+type FallbackCredentialEP struct {
+	ID               string `json:"ID"`
+	TemplateID       string `json:"TemplateID,omitempty"`
+	CredentialSource string `json:"CredentialSource"`
+	LastAttempt      string `json:"LastDiscoveryAttempt,omitempty"`
+	LastStatus       string `json:"LastDiscoveryStatus"`
+}
+
+// Output of GET /Inventory/FallbackCredentialEndpoints
+type FallbackCredentialEPArray struct {
+	RedfishEndpoints []FallbackCredentialEP `json:"RedfishEndpoints"`
+}
+
+// Get the RedfishEndpoints that were last discovered with fallback
+// credentials, i.e. that still need moving to their standard credentials.
+func (s *SmD) doFallbackCredentialEPsGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	eps, err := s.db.GetRFEndpointsAll()
+	if err != nil {
+		s.LogAlways("doFallbackCredentialEPsGet(): Lookup failure: %s", err)
+		sendJsonDBError(w, "", "", err)
+		return
+	}
+	out := FallbackCredentialEPArray{
+		RedfishEndpoints: []FallbackCredentialEP{},
+	}
+	for _, ep := range eps {
+		if ep == nil || ep.DiscInfo.CredentialSource == "" {
+			continue
+		}
+		out.RedfishEndpoints = append(out.RedfishEndpoints,
+			FallbackCredentialEP{
+				ID:               ep.ID,
+				TemplateID:       ep.TemplateID,
+				CredentialSource: ep.DiscInfo.CredentialSource,
+				LastAttempt:      ep.DiscInfo.LastAttempt,
+				LastStatus:       ep.DiscInfo.LastStatus,
+			})
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
+
+// Get all configured vendor profiles, and all quirk rules including the
+// built-in ones.
+func (s *SmD) doVendorProfilesGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	pa := VendorProfileArray{
+		VendorProfiles: []*rf.VendorProfile{},
+		QuirkRules:     rf.GetQuirkRules(),
+	}
+	for _, p := range s.vendorProfiles {
+		pa.VendorProfiles = append(pa.VendorProfiles, p)
+	}
+	sort.Slice(pa.VendorProfiles, func(i, j int) bool {
+		return pa.VendorProfiles[i].Name < pa.VendorProfiles[j].Name
+	})
+	sendJsonObject(w, http.StatusOK, pa)
+}
@@ -0,0 +1,221 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	compcreds "github.com/Cray-HPE/hms-compcredentials"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func TestLoadVendorProfiles(t *testing.T) {
+	tests := []struct {
+		data   string
+		expErr bool
+		expLen int
+	}{{
+		data: `{"VendorProfiles": [
+			{"Name": "gigabyte", "Port": 443, "CredentialSecret": "vendor/gigabyte"},
+			{"Name": "lockout", "AuthStyle": "basic", "Quirks": ["NoGETRetries"]}
+		]}`,
+		expErr: false,
+		expLen: 2,
+	}, {
+		data:   `{"VendorProfiles": [{"Name": "a"}, {"Name": "a"}]}`,
+		expErr: true,
+	}, {
+		data:   `{"VendorProfiles": [{"Name": "a", "AuthStyle": "Digest"}]}`,
+		expErr: true,
+	}, {
+		data: `{"VendorProfiles": [
+			{"Name": "a", "FallbackCredentialSecrets": ["vendor/old", "vendor/older"]}
+		]}`,
+		expErr: false,
+		expLen: 1,
+	}, {
+		data:   `{"VendorProfiles": [{"Name": "a", "FallbackCredentialSecrets": [""]}]}`,
+		expErr: true,
+	}, {
+		data:   `{"VendorProfiles": [`,
+		expErr: true,
+	}, {
+		data: `{"VendorProfiles": [], "QuirkRules": [
+			{"Name": "test-load-rule", "Match": {"Model": "zz9000"}, "Quirks": ["NoGETRetries"]}
+		]}`,
+		expErr: false,
+		expLen: 0,
+	}, {
+		data: `{"VendorProfiles": [], "QuirkRules": [
+			{"Name": "test-bad-rule", "Match": {"Model": "zz9000"}, "Quirks": ["NoSuchQuirk"]}
+		]}`,
+		expErr: true,
+	}}
+
+	dir := t.TempDir()
+	for i, test := range tests {
+		path := filepath.Join(dir, "profiles.json")
+		if err := os.WriteFile(path, []byte(test.data), 0600); err != nil {
+			t.Fatalf("Test %v Failed: Can't write profiles: %s", i, err)
+		}
+		profiles, err := loadVendorProfiles(path)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+		} else if len(profiles) != test.expLen {
+			t.Errorf("Test %v Failed: Expected %d profiles, got %d",
+				i, test.expLen, len(profiles))
+		} else if p, ok := profiles["lockout"]; ok && p.AuthStyle != rf.AuthStyleBasic {
+			t.Errorf("Test %v Failed: AuthStyle not normalized: %s",
+				i, p.AuthStyle)
+		}
+	}
+	if _, err := loadVendorProfiles(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+	if !rf.LookupQuirks(rf.QuirkTarget{Model: "ZZ9000"}).Has(rf.QuirkNoGETRetries) {
+		t.Errorf("Quirk rule from file was not registered")
+	}
+}
+
+func TestApplyVendorProfile(t *testing.T) {
+	defer func() { s.vendorProfiles = nil }()
+	s.vendorProfiles = map[string]*rf.VendorProfile{
+		"plain":  {Name: "plain", Port: 8443, AuthStyle: rf.AuthStyleBasic},
+		"secret": {Name: "secret", AuthStyle: rf.AuthStyleBasic, CredentialSecret: "vendor/secret"},
+	}
+	credCache := map[string]*compcreds.CompCredentials{
+		"secret": {Username: "admin", Password: "cached"},
+	}
+	tests := []struct {
+		rep        rf.RawRedfishEP
+		defProfile string
+		expErr     bool
+		expTmpl    string
+		expUser    string
+		expPass    string
+	}{{
+		// No profile at all
+		rep:     rf.RawRedfishEP{ID: "x0c0s0b0", User: "root", Password: "pw"},
+		expTmpl: "",
+		expUser: "root",
+		expPass: "pw",
+	}, {
+		// POST-wide profile, no credential secret
+		rep:        rf.RawRedfishEP{ID: "x0c0s0b0"},
+		defProfile: "plain",
+		expTmpl:    "plain",
+	}, {
+		// Endpoint's own TemplateID beats the POST-wide profile
+		rep:        rf.RawRedfishEP{ID: "x0c0s0b0", TemplateID: "secret"},
+		defProfile: "plain",
+		expTmpl:    "secret",
+		expUser:    "admin",
+		expPass:    "cached",
+	}, {
+		// Explicit credentials are kept
+		rep:        rf.RawRedfishEP{ID: "x0c0s0b0", User: "root", Password: "pw"},
+		defProfile: "secret",
+		expTmpl:    "secret",
+		expUser:    "root",
+		expPass:    "pw",
+	}, {
+		rep:        rf.RawRedfishEP{ID: "x0c0s0b0"},
+		defProfile: "nosuchprofile",
+		expErr:     true,
+	}}
+
+	for i, test := range tests {
+		rep := test.rep
+		err := s.applyVendorProfile(&rep, test.defProfile, credCache)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+			continue
+		}
+		if rep.TemplateID != test.expTmpl || rep.User != test.expUser ||
+			rep.Password != test.expPass {
+			t.Errorf("Test %v Failed: Expected '%s' '%s'; Received '%s' '%s'",
+				i, test.expTmpl, test.expUser, rep.TemplateID, rep.User)
+		}
+	}
+}
+
+func TestDoFallbackCredentialEPsGet(t *testing.T) {
+	defer func() {
+		results.GetRFEndpointsAll.Return.entries = nil
+		results.GetRFEndpointsAll.Return.err = nil
+	}()
+	results.GetRFEndpointsAll.Return.err = nil
+	results.GetRFEndpointsAll.Return.entries = []*sm.RedfishEndpoint{{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID: "x0c0s0b0",
+			DiscInfo: rf.DiscoveryInfo{
+				LastStatus: rf.DiscoverOK,
+			},
+		},
+	}, {
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID:         "x0c0s1b0",
+			TemplateID: "gigabyte",
+			DiscInfo: rf.DiscoveryInfo{
+				LastStatus:       rf.DiscoverOK,
+				CredentialSource: "vendor/gigabyte-old",
+			},
+		},
+	}}
+
+	req, _ := http.NewRequest("GET",
+		"https://localhost/hsm/v2/Inventory/FallbackCredentialEndpoints", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Response code was %v; want 200", w.Code)
+	}
+	var out FallbackCredentialEPArray
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	if len(out.RedfishEndpoints) != 1 ||
+		out.RedfishEndpoints[0].ID != "x0c0s1b0" ||
+		out.RedfishEndpoints[0].CredentialSource != "vendor/gigabyte-old" ||
+		out.RedfishEndpoints[0].TemplateID != "gigabyte" {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
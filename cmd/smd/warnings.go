@@ -0,0 +1,100 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Non-fatal warnings on write responses
+//
+// Handlers that change the database can call addWarning() for things the
+// client should know about but that should not make the operation fail,
+// e.g. a NID that is already used by another component or a group label
+// that was lowercased.  Every warning is sent as a
+//
+//     Warning: 299 - "<text>"
+//
+// header (RFC 7234) so it works with any response body, and JSON message
+// bodies (Response) also list them in a "warnings" array.
+///////////////////////////////////////////////////////////////////////////////
+
+// Code used in Warning headers for miscellaneous persistent warnings.
+const warningCode = "299"
+
+// Wraps the ResponseWriter of a mutating route so handlers can attach
+// warnings.  They are written out as headers along with the status code.
+type warningWriter struct {
+	http.ResponseWriter
+	warnings    []string
+	wroteHeader bool
+}
+
+func (ww *warningWriter) WriteHeader(code int) {
+	if !ww.wroteHeader {
+		ww.wroteHeader = true
+		for _, msg := range ww.warnings {
+			ww.Header().Add("Warning", warningCode+" - "+strconv.Quote(msg))
+		}
+	}
+	ww.ResponseWriter.WriteHeader(code)
+}
+
+func (ww *warningWriter) Write(b []byte) (int, error) {
+	if !ww.wroteHeader {
+		ww.WriteHeader(http.StatusOK)
+	}
+	return ww.ResponseWriter.Write(b)
+}
+
+// Collect warnings for every route that can change anything.
+func withWarnings(route Route, h http.Handler) http.Handler {
+	if !routeMutates(route) {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(&warningWriter{ResponseWriter: w}, r)
+	})
+}
+
+// Add a non-fatal warning to the response.  Must be called before the
+// response is sent.
+func addWarning(w http.ResponseWriter, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	if ww, ok := w.(*warningWriter); ok {
+		ww.warnings = append(ww.warnings, msg)
+	} else {
+		w.Header().Add("Warning", warningCode+" - "+strconv.Quote(msg))
+	}
+}
+
+// Warnings added to the response so far, if any.
+func responseWarnings(w http.ResponseWriter) []string {
+	if ww, ok := w.(*warningWriter); ok {
+		return ww.warnings
+	}
+	return nil
+}
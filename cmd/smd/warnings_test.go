@@ -0,0 +1,137 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+func TestWriteWarnings(t *testing.T) {
+	defer func() {
+		results.GetComponentByNID.Return.id = nil
+		results.GetComponentByNID.Return.err = nil
+	}()
+	disabled := false
+	enabled := true
+
+	tests := []struct {
+		reqType     string
+		reqURI      string
+		reqBody     string
+		nidComp     *base.Component
+		groupLabel  string
+		expCode     int
+		expWarnings []string
+	}{{
+		"POST",
+		"https://localhost/hsm/v2/groups",
+		`{"label":"Grp1","members":{"ids":["x0c0s1b0n0"]}}`,
+		nil,
+		"grp1",
+		http.StatusCreated,
+		[]string{`299 - "group label 'Grp1' normalized to 'grp1'"`},
+	}, {
+		"POST",
+		"https://localhost/hsm/v2/groups",
+		`{"label":"grp1","members":{"ids":["x0c0s1b0n0"]}}`,
+		nil,
+		"grp1",
+		http.StatusCreated,
+		nil,
+	}, {
+		"PUT",
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0",
+		`{"Component":{"ID":"x0c0s27b0n0","State":"On","NID":864}}`,
+		&base.Component{ID: "x0c0s1b0n0", Enabled: &disabled},
+		"",
+		http.StatusNoContent,
+		[]string{`299 - "NID 864 already in use by disabled component x0c0s1b0n0"`},
+	}, {
+		"PUT",
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0",
+		`{"Component":{"ID":"x0c0s27b0n0","State":"On","NID":864}}`,
+		&base.Component{ID: "x0c0s1b0n0", Enabled: &enabled},
+		"",
+		http.StatusNoContent,
+		[]string{`299 - "NID 864 already in use by component x0c0s1b0n0"`},
+	}, {
+		"PUT",
+		"https://localhost/hsm/v2/State/Components/x0c0s27b0n0",
+		`{"Component":{"ID":"x0c0s27b0n0","State":"On","NID":864}}`,
+		&base.Component{ID: "x0c0s27b0n0", Enabled: &enabled},
+		"",
+		http.StatusNoContent,
+		nil,
+	}}
+
+	for i, test := range tests {
+		results.GetComponentByNID.Return.id = test.nidComp
+		results.GetComponentByNID.Return.err = nil
+		results.InsertGroup.Return.label = test.groupLabel
+		results.InsertGroup.Return.err = nil
+		results.UpsertComponents.Return.changeMap = nil
+		results.UpsertComponents.Return.err = nil
+
+		req, err := http.NewRequest(test.reqType, test.reqURI,
+			bytes.NewBufferString(test.reqBody))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected while creating request", err)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != test.expCode {
+			t.Errorf("Test %v Failed: Response code was %v; want %v (%s)",
+				i, w.Code, test.expCode, w.Body.String())
+		}
+		warnings := w.Header().Values("Warning")
+		if len(warnings) == 0 {
+			warnings = nil
+		}
+		if !reflect.DeepEqual(warnings, test.expWarnings) {
+			t.Errorf("Test %v Failed: Expected warnings %q; Received %q",
+				i, test.expWarnings, warnings)
+		}
+	}
+}
+
+func TestResponseWarnings(t *testing.T) {
+	w := httptest.NewRecorder()
+	ww := &warningWriter{ResponseWriter: w}
+	addWarning(ww, "thing %d ignored", 1)
+	sendJsonResponse(ww, http.StatusOK, "done")
+
+	expBody := `{"code":200,"message":"done","warnings":["thing 1 ignored"]}` + "\n"
+	if w.Body.String() != expBody {
+		t.Errorf("Expected body '%s'; Received '%s'", expBody, w.Body.String())
+	}
+	if w.Header().Get("Warning") != `299 - "thing 1 ignored"` {
+		t.Errorf("Bad Warning header '%s'", w.Header().Get("Warning"))
+	}
+}
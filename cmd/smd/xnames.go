@@ -0,0 +1,115 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
+	"github.com/go-chi/chi/v5"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// Xname geometry
+//
+// The xname rules HSM uses, for tools that would otherwise re-implement
+// them (see pkg/xnameutil for the same in Go):
+//
+//     GET  /Xnames/{xname}                  type, parent, ancestors, child
+//                                           types and neighbors, or why it
+//                                           is invalid
+//     POST /Xnames/Validate                 the same for many xnames
+//     GET  /Xnames/Expand?pattern=p[&limit=n]
+//                                           the xnames a pattern like
+//                                           x1000c[0-7]s[0-7]b[0-1]n[0-1]
+//                                           stands for
+//
+// None of these look at what is in HSM.
+///////////////////////////////////////////////////////////////////////////////
+
+const (
+	xnameExpandDefaultLimit = 10000
+	xnameExpandMaxLimit     = 100000
+)
+
+// A list of xnames, in and out.
+type XnameList struct {
+	Xnames []string `json:"Xnames"`
+}
+
+// Descriptions of xnames.
+type XnameInfoArray struct {
+	Xnames []*xnameutil.Info `json:"Xnames"`
+}
+
+// Describe one xname.  Invalid xnames are described as such, not errors.
+func (s *SmD) doXnameGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	sendJsonObject(w, http.StatusOK, xnameutil.Describe(chi.URLParam(r, "xname")))
+}
+
+// Describe many xnames.
+func (s *SmD) doXnamesValidatePost(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	in := new(XnameList)
+	if !s.certDecodeBody(w, r, in) {
+		return
+	}
+	out := XnameInfoArray{Xnames: make([]*xnameutil.Info, 0, len(in.Xnames))}
+	for _, xname := range in.Xnames {
+		out.Xnames = append(out.Xnames, xnameutil.Describe(xname))
+	}
+	sendJsonObject(w, http.StatusOK, out)
+}
+
+// Expand an xname pattern.
+func (s *SmD) doXnamesExpandGet(w http.ResponseWriter, r *http.Request) {
+	defer base.DrainAndCloseRequestBody(r)
+
+	q := r.URL.Query()
+	limit := xnameExpandDefaultLimit
+	if val := q.Get("limit"); val != "" {
+		var err error
+		limit, err = strconv.Atoi(val)
+		if err != nil || limit < 1 || limit > xnameExpandMaxLimit {
+			sendJsonError(w, http.StatusBadRequest, "limit must be from 1 to "+
+				strconv.Itoa(xnameExpandMaxLimit))
+			return
+		}
+	}
+	xnames, err := xnameutil.Expand(q.Get("pattern"), limit)
+	if errors.Is(err, xnameutil.ErrTooMany) {
+		sendJsonError(w, http.StatusBadRequest, err.Error()+
+			"; raise limit or narrow the pattern")
+		return
+	} else if err != nil {
+		sendJsonError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sendJsonObject(w, http.StatusOK, XnameList{Xnames: xnames})
+}
@@ -0,0 +1,131 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDoXnameGet(t *testing.T) {
+	tests := []struct {
+		xname  string
+		valid  bool
+		parent string
+	}{
+		{"X1000C0S0B0N0", true, "x1000c0s0b0"},
+		{"x1000q0", false, ""},
+	}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Xnames/"+test.xname, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var info struct {
+			Valid  bool
+			Parent string
+			Error  string
+		}
+		if w.Code != http.StatusOK {
+			t.Errorf("Test %d: expected 200, got %d", i, w.Code)
+		} else if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if info.Valid != test.valid || info.Parent != test.parent ||
+			(info.Error == "") != test.valid {
+			t.Errorf("Test %d: unexpected response %s", i, w.Body.String())
+		}
+	}
+}
+
+func TestDoXnamesValidatePost(t *testing.T) {
+	req, _ := http.NewRequest("POST",
+		"https://localhost/hsm/v2/Xnames/Validate",
+		bytes.NewBufferString(`{"Xnames":["x1000c0","x1000c9","x3000m0p0v1"]}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var out XnameInfoArray
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	} else if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("Bad response: %s", err)
+	}
+	valid := []bool{}
+	types := []string{}
+	for _, info := range out.Xnames {
+		valid = append(valid, info.Valid)
+		types = append(types, info.Type)
+	}
+	if !reflect.DeepEqual([]bool{true, false, true}, valid) ||
+		!reflect.DeepEqual([]string{"Chassis", "", "CabinetPDUPowerConnector"}, types) {
+		t.Errorf("Unexpected response %s", w.Body.String())
+	}
+}
+
+func TestDoXnamesExpandGet(t *testing.T) {
+	tests := []struct {
+		query    string
+		code     int
+		expected []string
+	}{{
+		query:    "pattern=x1000c0s[0-1]b0n[0,1]",
+		code:     http.StatusOK,
+		expected: []string{"x1000c0s0b0n0", "x1000c0s0b0n1", "x1000c0s1b0n0", "x1000c0s1b0n1"},
+	}, {
+		query: "pattern=x1000c0s[0-1]b0n[0,1]&limit=3",
+		code:  http.StatusBadRequest,
+	}, {
+		query: "pattern=x1000c0s0&limit=0",
+		code:  http.StatusBadRequest,
+	}, {
+		query: "pattern=x1000c[0-9]",
+		code:  http.StatusBadRequest,
+	}, {
+		query: "",
+		code:  http.StatusBadRequest,
+	}}
+	for i, test := range tests {
+		req, _ := http.NewRequest("GET",
+			"https://localhost/hsm/v2/Xnames/Expand?"+test.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != test.code {
+			t.Errorf("Test %d: expected %d, got %d: %s", i, test.code, w.Code,
+				w.Body.String())
+			continue
+		}
+		if test.code != http.StatusOK {
+			continue
+		}
+		var out XnameList
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Errorf("Test %d: bad response: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, out.Xnames) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected,
+				out.Xnames)
+		}
+	}
+}
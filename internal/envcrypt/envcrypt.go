@@ -0,0 +1,186 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package envcrypt does envelope encryption of sensitive values kept in the
+// database, e.g. RedfishEndpoint passwords.
+//
+// Each value is encrypted with AES-256-GCM under its own random data key,
+// which is in turn wrapped by a key encryption key from a KeyProvider: a
+// local key file, or a Vault Transit key so the key encryption key never
+// leaves Vault.  The wrapped data key is kept with the value:
+//
+//	enc:v1:<key id>:<base64 wrapped data key>:<base64 nonce+ciphertext>
+//
+// Values are bound to where they are kept (e.g. the row's ID) so they can't
+// be copied from one row to another.  Values without the prefix are taken
+// to be plaintext written before encryption was turned on, and are
+// returned as they are, so existing rows keep working until re-encrypted.
+package envcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prefix of sealed values
+const SealedPrefix = "enc:v1:"
+
+var ErrNoKey = errors.New(
+	"value is encrypted but no database encryption key is configured")
+var ErrBadSealed = errors.New("malformed encrypted value")
+var ErrUnknownKey = errors.New("value is encrypted with an unknown key")
+var ErrDecrypt = errors.New("can't decrypt value, wrong key?")
+
+// Source of the key encryption keys that wrap the data keys.
+type KeyProvider interface {
+	// ID of the key new data keys are wrapped with.  IDs may not contain
+	// ':'.
+	KeyID() string
+
+	// Wrap a data key with the current key.
+	WrapKey(dek []byte) ([]byte, error)
+
+	// Unwrap a data key wrapped with the key keyID, which may be an older
+	// key.  Returns ErrUnknownKey if there is no such key.
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Seals and opens values with data keys wrapped by a KeyProvider.  A nil
+// *Envelope is valid, and leaves values as they are, failing to open any
+// that are sealed.
+type Envelope struct {
+	kp KeyProvider
+}
+
+func NewEnvelope(kp KeyProvider) (*Envelope, error) {
+	if kp == nil {
+		return nil, errors.New("no KeyProvider")
+	}
+	if id := kp.KeyID(); id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("bad key ID '%s'", id)
+	}
+	return &Envelope{kp: kp}, nil
+}
+
+// True if val is sealed, i.e. not plaintext.
+func IsSealed(val string) bool {
+	return strings.HasPrefix(val, SealedPrefix)
+}
+
+// ID of the key val was sealed with, or "" if it isn't sealed.
+func SealedKeyID(val string) string {
+	if !IsSealed(val) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(val, SealedPrefix), ":")
+	return id
+}
+
+// True if val should be sealed again: it is plaintext, or sealed with a key
+// other than the current one.  Empty values are left empty.
+func (e *Envelope) NeedsSeal(val string) bool {
+	if e == nil || val == "" {
+		return false
+	}
+	return SealedKeyID(val) != e.kp.KeyID()
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt plain for where, e.g. "rf_endpoints.password:x0c0s0b0", under a
+// new data key.  Empty values and a nil Envelope return plain unchanged.
+func (e *Envelope) Seal(plain, where string) (string, error) {
+	if e == nil || plain == "" {
+		return plain, nil
+	}
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ct := aead.Seal(nonce, nonce, []byte(plain), []byte(where))
+	wrapped, err := e.kp.WrapKey(dek)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key: %w", err)
+	}
+	return SealedPrefix + e.kp.KeyID() + ":" +
+		base64.StdEncoding.EncodeToString(wrapped) + ":" +
+		base64.StdEncoding.EncodeToString(ct), nil
+}
+
+// Decrypt val, sealed for where.  Plaintext values are returned unchanged.
+func (e *Envelope) Open(val, where string) (string, error) {
+	if !IsSealed(val) {
+		return val, nil
+	}
+	if e == nil {
+		return "", ErrNoKey
+	}
+	parts := strings.Split(strings.TrimPrefix(val, SealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", ErrBadSealed
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrBadSealed
+	}
+	ct, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrBadSealed
+	}
+	dek, err := e.kp.UnwrapKey(parts[0], wrapped)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	if len(ct) < aead.NonceSize() {
+		return "", ErrBadSealed
+	}
+	plain, err := aead.Open(nil, ct[:aead.NonceSize()], ct[aead.NonceSize():],
+		[]byte(where))
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plain), nil
+}
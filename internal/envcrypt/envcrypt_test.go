@@ -0,0 +1,227 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package envcrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testKey1 = base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+var testKey2 = base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+
+func testEnvelope(t *testing.T, keyFile string) *Envelope {
+	kf, err := ParseKeyFile([]byte(keyFile))
+	if err != nil {
+		t.Fatalf("ParseKeyFile: %s", err)
+	}
+	e, err := NewEnvelope(kf)
+	if err != nil {
+		t.Fatalf("NewEnvelope: %s", err)
+	}
+	return e
+}
+
+func TestSealOpen(t *testing.T) {
+	e := testEnvelope(t, "# Current first\nk1 "+testKey1+"\n")
+	where := "rf_endpoints.password:x0c0s0b0"
+
+	sealed, err := e.Seal("terminal0", where)
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+	if !IsSealed(sealed) || SealedKeyID(sealed) != "k1" ||
+		strings.Contains(sealed, "terminal0") {
+		t.Errorf("Unexpected sealed value '%s'", sealed)
+	}
+	if again, _ := e.Seal("terminal0", where); again == sealed {
+		t.Errorf("Expected a new data key and nonce for each seal")
+	}
+	if plain, err := e.Open(sealed, where); err != nil || plain != "terminal0" {
+		t.Errorf("Expected 'terminal0', got '%s', %v", plain, err)
+	}
+	if _, err := e.Open(sealed, "rf_endpoints.password:x0c0s1b0"); err != ErrDecrypt {
+		t.Errorf("Expected ErrDecrypt for another row, got %v", err)
+	}
+	if _, err := e.Open(SealedPrefix+"k1:!!:!!", where); err != ErrBadSealed {
+		t.Errorf("Expected ErrBadSealed, got %v", err)
+	}
+
+	// Plaintext from before encryption was turned on
+	if plain, err := e.Open("terminal0", where); err != nil || plain != "terminal0" {
+		t.Errorf("Expected plaintext unchanged, got '%s', %v", plain, err)
+	}
+	if !e.NeedsSeal("terminal0") || e.NeedsSeal(sealed) || e.NeedsSeal("") {
+		t.Errorf("Unexpected NeedsSeal")
+	}
+	if empty, _ := e.Seal("", where); empty != "" {
+		t.Errorf("Expected empty values left empty, got '%s'", empty)
+	}
+
+	// No key configured
+	var none *Envelope
+	if plain, _ := none.Seal("terminal0", where); plain != "terminal0" {
+		t.Errorf("Expected plaintext with no key, got '%s'", plain)
+	}
+	if _, err := none.Open(sealed, where); err != ErrNoKey {
+		t.Errorf("Expected ErrNoKey, got %v", err)
+	}
+	if none.NeedsSeal("terminal0") {
+		t.Errorf("Expected nothing to seal with no key")
+	}
+
+	// Rotated: the new key first, the old one kept to open old values.
+	rotated := testEnvelope(t, "k2 "+testKey2+"\nk1 "+testKey1+"\n")
+	if plain, err := rotated.Open(sealed, where); err != nil || plain != "terminal0" {
+		t.Errorf("Expected 'terminal0' with the old key, got '%s', %v", plain, err)
+	}
+	if !rotated.NeedsSeal(sealed) {
+		t.Errorf("Expected values under the old key to need sealing")
+	}
+	newOnly := testEnvelope(t, "k2 "+testKey2+"\n")
+	if _, err := newOnly.Open(sealed, where); err != ErrUnknownKey {
+		t.Errorf("Expected ErrUnknownKey, got %v", err)
+	}
+	// Same ID, different key
+	wrongKey := testEnvelope(t, "k1 "+testKey2+"\n")
+	if _, err := wrongKey.Open(sealed, where); err != ErrDecrypt {
+		t.Errorf("Expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestParseKeyFile(t *testing.T) {
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	tests := []struct {
+		in     string
+		expErr string
+	}{
+		{"", "no keys"},
+		{"# nothing\n\n", "no keys"},
+		{"k1\n", "line 1"},
+		{"k:1 " + testKey1, "may not contain"},
+		{"k1 " + testKey1 + "\nk1 " + testKey2, "duplicate"},
+		{"k1 not-base64!", "not base64"},
+		{"\nk1 " + short, "line 2: key must be 32 bytes"},
+		{"k1 " + testKey1 + "\n  k2\t" + testKey2 + "  \n", ""},
+	}
+	for i, test := range tests {
+		kf, err := ParseKeyFile([]byte(test.in))
+		if test.expErr == "" {
+			if err != nil || kf.KeyID() != "k1" || len(kf.keys) != 2 {
+				t.Errorf("Test %d: Unexpected %+v, %v", i, kf, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.expErr) {
+			t.Errorf("Test %d: Expected error '%s', got %v", i, test.expErr, err)
+		}
+	}
+}
+
+// Just enough of Vault's Transit engine; "encrypts" by prefixing the key.
+func fakeTransit(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var in map[string]string
+		json.NewDecoder(r.Body).Decode(&in)
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/hsm":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{
+					"ciphertext": "vault:v1:" + in["plaintext"]}})
+		case "/v1/transit/decrypt/hsm":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{
+					"plaintext": strings.TrimPrefix(in["ciphertext"], "vault:v1:")}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVaultTransit(t *testing.T) {
+	ts := fakeTransit(t)
+	defer ts.Close()
+	where := "rf_endpoints.password:x0c0s0b0"
+
+	e, err := NewEnvelope(NewVaultTransit(ts.URL+"/", "/transit/", "hsm",
+		"s.token", ts.Client()))
+	if err != nil {
+		t.Fatalf("NewEnvelope: %s", err)
+	}
+	sealed, err := e.Seal("terminal0", where)
+	if err != nil {
+		t.Fatalf("Seal: %s", err)
+	}
+	if SealedKeyID(sealed) != "transit/hsm" {
+		t.Errorf("Unexpected sealed value '%s'", sealed)
+	}
+	if plain, err := e.Open(sealed, where); err != nil || plain != "terminal0" {
+		t.Errorf("Expected 'terminal0', got '%s', %v", plain, err)
+	}
+
+	e, _ = NewEnvelope(NewVaultTransit(ts.URL, "transit", "hsm", "bad",
+		ts.Client()))
+	if _, err := e.Seal("terminal0", where); err == nil ||
+		!strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a 403 error, got %v", err)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvKeyFile, "")
+	t.Setenv(EnvVaultKey, "")
+	if e, err := FromEnv(); e != nil || err != nil {
+		t.Errorf("Expected no envelope, got %v, %v", e, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys")
+	os.WriteFile(path, []byte("k1 "+testKey1+"\n"), 0600)
+	t.Setenv(EnvKeyFile, path)
+	if e, err := FromEnv(); e == nil || err != nil || e.kp.KeyID() != "k1" {
+		t.Errorf("Expected the key file, got %v, %v", e, err)
+	}
+
+	t.Setenv(EnvVaultKey, "hsm")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error with both set")
+	}
+
+	t.Setenv(EnvKeyFile, "")
+	t.Setenv(EnvVaultAddr, "")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("Expected an error with no %s", EnvVaultAddr)
+	}
+	t.Setenv(EnvVaultAddr, "http://vault:8200")
+	t.Setenv(EnvVaultMount, "")
+	if e, err := FromEnv(); err != nil || e.kp.KeyID() != "transit/hsm" {
+		t.Errorf("Expected the transit key, got %v, %v", e, err)
+	}
+}
@@ -0,0 +1,271 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package envcrypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables that configure the key provider, see FromEnv().
+// VAULT_ADDR and VAULT_TOKEN are those used by the Vault CLI.
+const (
+	EnvKeyFile    = "SMD_DB_ENCRYPT_KEY_FILE"
+	EnvVaultKey   = "SMD_DB_ENCRYPT_VAULT_KEY"
+	EnvVaultMount = "SMD_DB_ENCRYPT_VAULT_MOUNT"
+	EnvVaultAddr  = "VAULT_ADDR"
+	EnvVaultToken = "VAULT_TOKEN"
+)
+
+const vaultMountDefault = "transit"
+
+// Set up the Envelope configured by the environment: with the keys in the
+// file named by SMD_DB_ENCRYPT_KEY_FILE, or the Vault Transit key named by
+// SMD_DB_ENCRYPT_VAULT_KEY.  Returns nil if neither is set, i.e. values are
+// not encrypted.
+func FromEnv() (*Envelope, error) {
+	keyFile := os.Getenv(EnvKeyFile)
+	vaultKey := os.Getenv(EnvVaultKey)
+	if keyFile != "" && vaultKey != "" {
+		return nil, fmt.Errorf("only one of %s and %s may be set",
+			EnvKeyFile, EnvVaultKey)
+	}
+	var kp KeyProvider
+	var err error
+	if keyFile != "" {
+		kp, err = LoadKeyFile(keyFile)
+	} else if vaultKey != "" {
+		mount := os.Getenv(EnvVaultMount)
+		if mount == "" {
+			mount = vaultMountDefault
+		}
+		addr := os.Getenv(EnvVaultAddr)
+		if addr == "" {
+			return nil, fmt.Errorf("%s needs %s", EnvVaultKey, EnvVaultAddr)
+		}
+		kp = NewVaultTransit(addr, mount, vaultKey, os.Getenv(EnvVaultToken),
+			&http.Client{Timeout: 10 * time.Second})
+	} else {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvelope(kp)
+}
+
+// Key encryption keys from a local file.  Each line is a key ID and a
+// base64 32-byte key, separated by whitespace.  The first key wraps new
+// data keys, and the rest are kept to unwrap those wrapped before the keys
+// were rotated.  Blank lines and lines starting with '#' are skipped.
+type KeyFile struct {
+	current string
+	keys    map[string][]byte
+}
+
+func LoadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeyFile(data)
+}
+
+func ParseKeyFile(data []byte) (*KeyFile, error) {
+	kf := &KeyFile{keys: make(map[string][]byte)}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want '<key id> <base64 key>'", n)
+		}
+		id := fields[0]
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("line %d: key ID may not contain ':'", n)
+		}
+		if _, ok := kf.keys[id]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key ID '%s'", n, id)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: key is not base64: %s", n, err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("line %d: key must be 32 bytes, not %d",
+				n, len(key))
+		}
+		if kf.current == "" {
+			kf.current = id
+		}
+		kf.keys[id] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if kf.current == "" {
+		return nil, fmt.Errorf("no keys")
+	}
+	return kf, nil
+}
+
+func (kf *KeyFile) KeyID() string {
+	return kf.current
+}
+
+func (kf *KeyFile) WrapKey(dek []byte) ([]byte, error) {
+	aead, err := newGCM(kf.keys[kf.current])
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dek, []byte(kf.current)), nil
+}
+
+func (kf *KeyFile) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := kf.keys[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, ErrBadSealed
+	}
+	dek, err := aead.Open(nil, wrapped[:aead.NonceSize()],
+		wrapped[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return dek, nil
+}
+
+// Key encryption key kept in Vault's Transit secrets engine, which wraps
+// and unwraps the data keys so the key itself never leaves Vault.  Rotating
+// the key in Vault needs no change here, as Vault keeps the older versions
+// to decrypt with.
+type VaultTransit struct {
+	addr   string
+	mount  string
+	key    string
+	token  string
+	client *http.Client
+}
+
+func NewVaultTransit(addr, mount, key, token string, client *http.Client) *VaultTransit {
+	return &VaultTransit{
+		addr:   strings.TrimSuffix(addr, "/"),
+		mount:  strings.Trim(mount, "/"),
+		key:    key,
+		token:  token,
+		client: client,
+	}
+}
+
+func (vt *VaultTransit) KeyID() string {
+	return vt.mount + "/" + vt.key
+}
+
+// POST in to the Transit op (encrypt or decrypt) and decode the data of the
+// response into out.
+func (vt *VaultTransit) do(op string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	url := vt.addr + "/v1/" + vt.mount + "/" + op + "/" + vt.key
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if vt.token != "" {
+		req.Header.Set("X-Vault-Token", vt.token)
+	}
+	rsp, err := vt.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	rspBody, err := io.ReadAll(io.LimitReader(rsp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit %s: %d %s", op, rsp.StatusCode,
+			strings.TrimSpace(string(rspBody)))
+	}
+	wrapper := struct {
+		Data interface{} `json:"data"`
+	}{out}
+	return json.Unmarshal(rspBody, &wrapper)
+}
+
+func (vt *VaultTransit) WrapKey(dek []byte) ([]byte, error) {
+	var out struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := vt.do("encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dek),
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	if out.Ciphertext == "" {
+		return nil, fmt.Errorf("vault transit encrypt: no ciphertext")
+	}
+	return []byte(out.Ciphertext), nil
+}
+
+func (vt *VaultTransit) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != vt.KeyID() {
+		return nil, ErrUnknownKey
+	}
+	var out struct {
+		Plaintext string `json:"plaintext"`
+	}
+	err := vt.do("decrypt", map[string]string{
+		"ciphertext": string(wrapped),
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(out.Plaintext)
+}
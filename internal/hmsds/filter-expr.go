@@ -0,0 +1,380 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package hmsds
+
+import (
+	"fmt"
+	"strings"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	sq "github.com/Masterminds/squirrel"
+)
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// Component filter expressions
+//
+// ComponentFilter.Filter holds expressions like
+//
+//     type eq Node and (state ne Ready or flag eq Alert) and nid ge 1000
+//
+// that are parsed and turned into a WHERE clause.  The grammar is
+//
+//     expr       = term { "or" term }
+//     term       = factor { "and" factor }
+//     factor     = "not" factor | "(" expr ")" | comparison
+//     comparison = field op value | field "in" "(" value { "," value } ")"
+//     op         = "eq" | "ne" | "gt" | "ge" | "lt" | "le"
+//
+// Keywords and field names are not case sensitive.  Values are single words
+// or quoted with ' or ".  They are checked and normalized the same way as
+// the matching query parameter, so "state eq ready" works.  Only nid can
+// be used with gt, ge, lt and le.
+//
+///////////////////////////////////////////////////////////////////////////////
+
+// Limits to keep a single expression from turning into an enormous query.
+const (
+	compFilterMaxLen   = 4096
+	compFilterMaxDepth = 32
+)
+
+// A Components column that can be used in filter expressions, with the
+// function used to verify and normalize its values.
+type compFilterField struct {
+	col     string
+	verify  func(string) string // nil to take values as is
+	ordered bool                // Can use gt, ge, lt, le
+}
+
+var compFilterFields = map[string]compFilterField{
+	"id":                   {compIdCol, validXNameFilter, false},
+	"type":                 {compTypeCol, xnametypes.VerifyNormalizeType, false},
+	"state":                {compStateCol, base.VerifyNormalizeState, false},
+	"flag":                 {compFlagCol, base.VerifyNormalizeFlag, false},
+	"enabled":              {compEnabledCol, strToDbBool, false},
+	"softwarestatus":       {compSwStatusCol, nil, false},
+	"role":                 {compRoleCol, base.VerifyNormalizeRole, false},
+	"subrole":              {compSubRoleCol, base.VerifyNormalizeSubRole, false},
+	"nid":                  {compNIDCol, nidStrTransform, true},
+	"subtype":              {compSubTypeCol, strToAlphaNum, false},
+	"nettype":              {compNetTypeCol, base.VerifyNormalizeNetType, false},
+	"arch":                 {compArchCol, base.VerifyNormalizeArch, false},
+	"class":                {compClassCol, base.VerifyNormalizeClass, false},
+	"locked":               {compLockedCol, strToDbBool, false},
+	"reservation_disabled": {compResDisabledCol, strToDbBool, false},
+}
+
+var compFilterOps = map[string]bool{
+	"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true,
+	"in": true,
+}
+
+// Parsed filter expression.
+type compFilterNode interface {
+	// WHERE clause for Components with the given table alias
+	toSql(alias string) sq.Sqlizer
+}
+
+type compFilterAnd []compFilterNode
+type compFilterOr []compFilterNode
+
+type compFilterNot struct {
+	node compFilterNode
+}
+
+type compFilterCmp struct {
+	col  string
+	op   string
+	vals []string
+}
+
+func (n compFilterAnd) toSql(alias string) sq.Sqlizer {
+	and := sq.And{}
+	for _, sub := range n {
+		and = append(and, sub.toSql(alias))
+	}
+	return and
+}
+
+func (n compFilterOr) toSql(alias string) sq.Sqlizer {
+	or := sq.Or{}
+	for _, sub := range n {
+		or = append(or, sub.toSql(alias))
+	}
+	return or
+}
+
+func (n compFilterNot) toSql(alias string) sq.Sqlizer {
+	return compFilterNotSql{n.node.toSql(alias)}
+}
+
+// squirrel has no NOT, so wrap the subexpression.
+type compFilterNotSql struct {
+	sub sq.Sqlizer
+}
+
+func (n compFilterNotSql) ToSql() (string, []interface{}, error) {
+	sql, args, err := n.sub.ToSql()
+	return "NOT (" + sql + ")", args, err
+}
+
+func (n compFilterCmp) toSql(alias string) sq.Sqlizer {
+	col := alias + "." + n.col
+	switch n.op {
+	case "ne":
+		return sq.NotEq{col: n.vals[0]}
+	case "gt":
+		return sq.Gt{col: n.vals[0]}
+	case "ge":
+		return sq.GtOrEq{col: n.vals[0]}
+	case "lt":
+		return sq.Lt{col: n.vals[0]}
+	case "le":
+		return sq.LtOrEq{col: n.vals[0]}
+	case "in":
+		return sq.Eq{col: n.vals}
+	}
+	return sq.Eq{col: n.vals[0]}
+}
+
+// Error for a filter expression that can't be used, saying why.
+func badCompFilter(format string, a ...interface{}) error {
+	return e.NewChild(ErrHMSDSArgBadFilter.Error() + ": " +
+		fmt.Sprintf(format, a...))
+}
+
+type compFilterToken struct {
+	str    string
+	quoted bool
+}
+
+// Split a filter expression into words, quoted strings, parentheses and
+// commas.
+func tokenizeCompFilter(expr string) ([]compFilterToken, error) {
+	toks := []compFilterToken{}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			toks = append(toks, compFilterToken{str: string(c)})
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, badCompFilter("unterminated quoted value")
+			}
+			toks = append(toks, compFilterToken{
+				str:    expr[i+1 : i+1+end],
+				quoted: true,
+			})
+			i += end + 2
+		default:
+			end := strings.IndexAny(expr[i:], " \t\n\r(),'\"")
+			if end < 0 {
+				end = len(expr) - i
+			}
+			toks = append(toks, compFilterToken{str: expr[i : i+end]})
+			i += end
+		}
+	}
+	return toks, nil
+}
+
+type compFilterParser struct {
+	toks  []compFilterToken
+	pos   int
+	depth int
+}
+
+// The next token if it is the given keyword or punctuation.
+func (p *compFilterParser) accept(kw string) bool {
+	if p.pos < len(p.toks) && !p.toks[p.pos].quoted &&
+		strings.EqualFold(p.toks[p.pos].str, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *compFilterParser) next() (compFilterToken, bool) {
+	if p.pos >= len(p.toks) {
+		return compFilterToken{}, false
+	}
+	p.pos++
+	return p.toks[p.pos-1], true
+}
+
+func (p *compFilterParser) parseExpr() (compFilterNode, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > compFilterMaxDepth {
+		return nil, badCompFilter("nested too deeply")
+	}
+	or := compFilterOr{}
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, term)
+		if !p.accept("or") {
+			break
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *compFilterParser) parseTerm() (compFilterNode, error) {
+	and := compFilterAnd{}
+	for {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, factor)
+		if !p.accept("and") {
+			break
+		}
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *compFilterParser) parseFactor() (compFilterNode, error) {
+	if p.accept("not") {
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > compFilterMaxDepth {
+			return nil, badCompFilter("nested too deeply")
+		}
+		node, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return compFilterNot{node}, nil
+	}
+	if p.accept("(") {
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, badCompFilter("missing )")
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *compFilterParser) parseComparison() (compFilterNode, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, badCompFilter("expected a field at end of expression")
+	}
+	name := strings.ToLower(tok.str)
+	field, ok := compFilterFields[name]
+	if !ok || tok.quoted {
+		return nil, badCompFilter("unknown field '%s'", tok.str)
+	}
+	tok, ok = p.next()
+	op := strings.ToLower(tok.str)
+	if !ok || tok.quoted || !compFilterOps[op] {
+		return nil, badCompFilter("expected an operator after '%s'", name)
+	}
+	if !field.ordered && op != "eq" && op != "ne" && op != "in" {
+		return nil, badCompFilter("'%s' cannot be used with %s", op, name)
+	}
+	cmp := compFilterCmp{col: field.col, op: op}
+	if op == "in" {
+		if !p.accept("(") {
+			return nil, badCompFilter("expected ( after in")
+		}
+		for {
+			val, err := p.parseValue(name, field)
+			if err != nil {
+				return nil, err
+			}
+			cmp.vals = append(cmp.vals, val)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if !p.accept(")") {
+			return nil, badCompFilter("missing ) after in values")
+		}
+		return cmp, nil
+	}
+	val, err := p.parseValue(name, field)
+	if err != nil {
+		return nil, err
+	}
+	cmp.vals = []string{val}
+	return cmp, nil
+}
+
+func (p *compFilterParser) parseValue(name string, field compFilterField) (string, error) {
+	tok, ok := p.next()
+	if !ok || (!tok.quoted && strings.ContainsAny(tok.str, "(),")) {
+		return "", badCompFilter("expected a value for %s", name)
+	}
+	if field.verify == nil {
+		return tok.str, nil
+	}
+	val := field.verify(tok.str)
+	if val == "" {
+		return "", badCompFilter("'%s' is not a valid %s", tok.str, name)
+	}
+	return val, nil
+}
+
+// Parse a component filter expression.
+func parseCompFilter(expr string) (compFilterNode, error) {
+	if len(expr) > compFilterMaxLen {
+		return nil, badCompFilter("longer than %d characters", compFilterMaxLen)
+	}
+	toks, err := tokenizeCompFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, badCompFilter("empty expression")
+	}
+	p := &compFilterParser{toks: toks}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, badCompFilter("unexpected '%s'", p.toks[p.pos].str)
+	}
+	return node, nil
+}
@@ -0,0 +1,138 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package hmsds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCompFilter(t *testing.T) {
+	tests := []struct {
+		expr         string
+		expectedSql  string
+		expectedArgs []interface{}
+		expectedErr  string
+	}{{
+		expr:         "type eq node",
+		expectedSql:  "c.type = ?",
+		expectedArgs: []interface{}{"Node"},
+	}, {
+		expr:         "type eq Node and state ne ready and nid ge 1000",
+		expectedSql:  "(c.type = ? AND c.state <> ? AND c.nid >= ?)",
+		expectedArgs: []interface{}{"Node", "Ready", "1000"},
+	}, {
+		expr:         "TYPE EQ Node AND (state eq Off OR flag eq alert) and not enabled eq false",
+		expectedSql:  "(c.type = ? AND (c.state = ? OR c.flag = ?) AND NOT (c.enabled = ?))",
+		expectedArgs: []interface{}{"Node", "Off", "Alert", "0"},
+	}, {
+		expr:         "role in (compute, 'Application') or id eq \"x0c0s0b0n0\"",
+		expectedSql:  "(c.role IN (?,?) OR c.id = ?)",
+		expectedArgs: []interface{}{"Compute", "Application", "x0c0s0b0n0"},
+	}, {
+		expr:         "softwarestatus eq 'AdminDown' and nid lt 10",
+		expectedSql:  "(c.admin = ? AND c.nid < ?)",
+		expectedArgs: []interface{}{"AdminDown", "10"},
+	}, {
+		expr:        "",
+		expectedErr: "empty expression",
+	}, {
+		expr:        "color eq red",
+		expectedErr: "unknown field 'color'",
+	}, {
+		expr:        "state gt Ready",
+		expectedErr: "'gt' cannot be used with state",
+	}, {
+		expr:        "state eq Sleepy",
+		expectedErr: "'Sleepy' is not a valid state",
+	}, {
+		expr:        "nid ge ten",
+		expectedErr: "'ten' is not a valid nid",
+	}, {
+		expr:        "(type eq Node",
+		expectedErr: "missing )",
+	}, {
+		expr:        "type eq Node state eq Ready",
+		expectedErr: "unexpected 'state'",
+	}, {
+		expr:        "type eq 'Node",
+		expectedErr: "unterminated quoted value",
+	}, {
+		expr:        "type eq",
+		expectedErr: "expected a value for type",
+	}, {
+		expr:        strings.Repeat("(", 40) + "type eq Node" + strings.Repeat(")", 40),
+		expectedErr: "nested too deeply",
+	}}
+
+	for i, test := range tests {
+		node, err := parseCompFilter(test.expr)
+		if test.expectedErr != "" {
+			if err == nil || !strings.HasSuffix(err.Error(), ": "+test.expectedErr) {
+				t.Errorf("Test %v Failed: Expected error '%s'; Received '%v'",
+					i, test.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error: %s", i, err)
+			continue
+		}
+		sql, args, err := node.toSql(compTableJoinAlias).ToSql()
+		if err != nil {
+			t.Errorf("Test %v Failed: ToSql: %s", i, err)
+		} else if sql != test.expectedSql {
+			t.Errorf("Test %v Failed: Expected SQL '%s'; Received '%s'",
+				i, test.expectedSql, sql)
+		} else if !reflect.DeepEqual(args, test.expectedArgs) {
+			t.Errorf("Test %v Failed: Expected args '%v'; Received '%v'",
+				i, test.expectedArgs, args)
+		}
+	}
+}
+
+func TestSelectComponentsFilterExpr(t *testing.T) {
+	f := &ComponentFilter{
+		Type:   []string{"node"},
+		Filter: []string{"state ne Ready or nid gt 100", "flag eq OK"},
+	}
+	query, err := selectComponents(f, FLTR_ID_ONLY)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	sql, args, _ := query.ToSql()
+	if !strings.HasSuffix(sql,
+		"WHERE c.type IN (?) AND (c.state <> ? OR c.nid > ?) AND c.flag = ?") {
+		t.Errorf("Unexpected query: %s", sql)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"Node", "Ready", "100", "OK"}) {
+		t.Errorf("Unexpected args: %v", args)
+	}
+
+	f = &ComponentFilter{Filter: []string{"state eq"}}
+	if _, err := selectComponents(f, FLTR_ID_ONLY); err == nil ||
+		!strings.HasPrefix(err.Error(), ErrHMSDSArgBadFilter.Error()) {
+		t.Errorf("Expected a bad filter error, got %v", err)
+	}
+}
@@ -24,6 +24,7 @@ package hmsds
 
 import (
 	"strings"
+	"time"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
@@ -69,10 +70,15 @@ type ComponentFilter struct {
 	Partition           []string `json:"partition"`
 	Locked              []string `json:"locked"`
 	ReservationDisabled []string `json:"reservation_disabled"`
+	Filter              []string `json:"filter"` // Filter expressions, see filter-expr.go
 
 	// private options
-	writeLock bool   // default is false
-	label     string // Labels query for logging, etc.
+	writeLock   bool   // default is false
+	label       string // Labels query for logging, etc.
+	descendants bool   // ID also matches the components under each ID
+
+	// Parsed Filter expressions, all of which must match.
+	filterExprs []compFilterNode
 
 	// State OR flag subclause without ORing the whole query.  For the
 	// target state and clause, since one or the other can be right but
@@ -172,6 +178,38 @@ type HWInvHistFilter struct {
 	label string // Labels query for logging, etc.
 }
 
+// Filter for the membership history of a group or partition.  Times are
+// RFC3339 and empty fields match everything.
+type MemberHistFilter struct {
+	ID        []string `json:"id"`
+	StartTime string   `json:"starttime"`
+	EndTime   string   `json:"endtime"`
+}
+
+// Filter for the state history of a component.  Times are RFC3339 and
+// empty fields match everything.
+type CompStateHistFilter struct {
+	StartTime string `json:"starttime"`
+	EndTime   string `json:"endtime"`
+}
+
+// Filter for component heartbeats.  Empty fields match everything.
+type CompHeartbeatFilter struct {
+	ID    []string      // Only these components
+	State []string      // Only components in these states
+	Age   time.Duration // Only heartbeats at least this old
+}
+
+// Filter for the lock audit log.  Times are RFC3339 and empty fields match
+// everything.
+type CompLockAuditFilter struct {
+	ID        []string `json:"id"`
+	Action    []string `json:"action"`
+	Actor     string   `json:"actor"`
+	StartTime string   `json:"starttime"`
+	EndTime   string   `json:"endtime"`
+}
+
 type CompEthInterfaceFilter struct {
 	// User-writable options
 	ID        []string `json:"id"`
@@ -470,7 +508,7 @@ func (f *ComponentFilter) VerifyNormalize() error {
 	f.verified = true
 
 	// Verify and normalize each field.
-	err := checkFilterField(f.ID, validXNameFilter, false)
+	err := checkFilterField(f.ID, validXNameOrPatternFilter, false)
 	if err != nil {
 		return ErrHMSDSArgBadID
 	}
@@ -549,6 +587,14 @@ func (f *ComponentFilter) VerifyNormalize() error {
 	if err != nil {
 		return ErrHMSDSNoPartition
 	}
+	f.filterExprs = nil
+	for _, expr := range f.Filter {
+		node, err := parseCompFilter(expr)
+		if err != nil {
+			return err
+		}
+		f.filterExprs = append(f.filterExprs, node)
+	}
 	return nil
 }
 
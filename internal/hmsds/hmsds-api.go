@@ -23,7 +23,11 @@
 package hmsds
 
 import (
+	"database/sql"
+	"time"
+
 	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 )
 
@@ -52,6 +56,7 @@ var ErrHMSDSArgBadSubRole = e.NewChild("Argument was not a valid HMS SubRole")
 var ErrHMSDSArgBadArch = e.NewChild("Argument was not a valid HMS Arch")
 var ErrHMSDSArgBadClass = e.NewChild("Argument was not a valid HMS Class")
 var ErrHMSDSArgBadSubtype = e.NewChild("Argument was not a valid HMS Subtype")
+var ErrHMSDSArgBadFilter = e.NewChild("Argument was not a valid filter expression")
 var ErrHMSDSArgBadRedfishType = e.NewChild("Argument was not a valid Redfish type")
 var ErrHMSDSArgBadJobType = e.NewChild("Argument was not a valid job Type")
 var ErrHMSDSArgBadHWInvHistEventType = e.NewChild("Argument was not a HWInvHist event Type")
@@ -65,6 +70,9 @@ var ErrHMSDSNoGroup = e.NewChild("no such group")
 var ErrHMSDSNoPartition = e.NewChild("no such partition")
 var ErrHMSDSExclusiveGroup = e.NewChild("Would create a duplicate key in another exclusive group")
 var ErrHMSDSExclusivePartition = e.NewChild("Would create a duplicate key in another partition")
+var ErrHMSDSNoPartitionMember = e.NewChild("component is not a member of the partition")
+var ErrHMSDSNoChildGroup = e.NewChild("no such child group")
+var ErrHMSDSGroupCycle = e.NewChild("Would make a group its own descendant")
 
 var ErrHMSDSMultipleGroupAndPart = e.NewChild("group and partition cannot both have more than one value")
 var ErrHMSDSNullGroupBadPart = e.NewChild("NULL group and non-NULL partition arg not permitted")
@@ -81,6 +89,8 @@ var ErrHMSDSCompEthInterfaceMultipleIPs = e.NewChild("component ethernet interfa
 
 var ErrHMSDSNoJobData = e.NewChild("Job has no data")
 
+var ErrHMSDSNoEncryptKey = e.NewChild("no database encryption key is configured")
+
 type LogLevel int
 
 const (
@@ -124,6 +134,68 @@ type PartInfo struct {
 	Partition []string `json:"Partition"`
 }
 
+// Fields to set on each component with BulkPatchComponents.  Fields that
+// are nil are left alone.
+type CompBulkPatch struct {
+	Flag     *string `json:"Flag"`
+	Enabled  *bool   `json:"Enabled"`
+	SwStatus *string `json:"SoftwareStatus"`
+}
+
+// Part of the data discovered for a RedfishEndpoint, for storing it as it
+// is generated.  Any field may be nil.
+type RFEndpointBatch struct {
+	CompEndpoints     *sm.ComponentEndpointArray
+	HWInvByLocs       []*sm.HWInvByLoc
+	Components        *base.ComponentArray
+	ServiceEndpoints  *sm.ServiceEndpointArray
+	CompEthInterfaces []*sm.CompEthInterfaceV2
+}
+
+// Number of HMS Components with a given type and state.
+type ComponentCount struct {
+	Type  string
+	State string
+	Count int
+}
+
+// Change counters for the collections clients poll.  Each one goes up when a
+// transaction that changes the collection commits.  Groups covers groups and
+// partitions, with their members, and HWInv covers hardware inventory by
+// location and by FRU.
+type CollectionVersions struct {
+	Components int64
+	Groups     int64
+	HWInv      int64
+}
+
+// A lease, held by Holder until Expires unless renewed.
+type Lease struct {
+	Name    string
+	Holder  string
+	Expires time.Time
+}
+
+// Settings for the database connection pool.  Zero values leave the
+// implementation's defaults.
+type DBPoolConfig struct {
+	MaxOpenConns     int           // Most open connections, < 0 for no limit
+	MaxIdleConns     int           // Most idle connections kept, < 0 for none
+	ConnMaxLifetime  time.Duration // Close connections once this old
+	ConnMaxIdleTime  time.Duration // Close connections idle this long
+	StatementTimeout time.Duration // Cancel statements running this long
+	SlowTxThreshold  time.Duration // Count transactions this long as slow
+}
+
+// Transactions done since the handle was opened, timed from Begin() to
+// Commit() or Rollback().
+type DBTxStats struct {
+	Transactions     uint64
+	SlowTransactions uint64        // Took SlowTxThreshold or longer
+	SlowTxThreshold  time.Duration // 0 if slow ones aren't counted
+	MaxTxDuration    time.Duration // Longest transaction
+}
+
 type HMSDB interface {
 
 	// Return implementation name as a string
@@ -148,12 +220,34 @@ type HMSDB interface {
 	// when done).
 	Begin() (HMSDBTx, error)
 
+	// Get a handle that records actor as whoever made the changes kept in a
+	// history, e.g. group and partition membership.  It shares the
+	// connection pool with the original handle.
+	WithActor(actor string) HMSDB
+
 	// Test the database connection to make sure that it is healthy
 	TestConnection() error
 
+	// Statistics of the connection pool, zero if not open.
+	DBStats() sql.DBStats
+
+	// Configure the connection pool.  Must be done before Open().
+	SetPoolConfig(cfg DBPoolConfig)
+
+	// The connection pool configuration in use, with defaults filled in.
+	PoolConfig() DBPoolConfig
+
+	// Counts of transactions and of slow ones, see DBTxStats.
+	TxStats() DBTxStats
+
 	// Increase verbosity for debugging, etc.
 	SetLogLevel(lvl LogLevel) error
 
+	// Encrypt RedfishEndpoint passwords with env as they are written, and
+	// decrypt them as they are read.  Plaintext passwords written before
+	// are still read as they are.  A nil env turns encryption off.
+	SetFieldEncryption(env *envcrypt.Envelope)
+
 	//                                                                    //
 	//          Generic single-valed queries - get id/key, etc.           //
 	//                                                                    //
@@ -198,6 +292,25 @@ type HMSDB interface {
 	// of the non-empty strings in the filter struct.
 	GetComponentsQuery(f *ComponentFilter, fieldfltr FieldFilter, ids []string) ([]*base.Component, error)
 
+	// Get some or all HMS Components as they were at time asOf (RFC3339),
+	// from the component state history, with the same filtering options as
+	// GetComponentsFilter except for groups and partitions.
+	GetComponentsAsOf(f *ComponentFilter, fieldFltr FieldFilter, asOf string) ([]*base.Component, error)
+
+	// Get the State and Flag changes of component id, oldest first.  If
+	// f has a StartTime, the first entry is the one in effect at that time,
+	// which may be older, so the state over the whole range is known.  The
+	// history is kept after the component is deleted.
+	GetCompStateHist(id string, f *CompStateHistFilter) ([]*sm.CompStateHistEntry, error)
+
+	// Count the HMS Components in system by type and state, without
+	// reading them all.
+	GetComponentCounts() ([]*ComponentCount, error)
+
+	// Get the current change counters of the components, groups and
+	// hardware inventory collections.
+	GetCollectionVersions() (*CollectionVersions, error)
+
 	// Get a single component by its NID, if the NID exists.
 	GetComponentByNID(nid string) (*base.Component, error)
 
@@ -215,6 +328,19 @@ type HMSDB interface {
 	// this won't overwrite existing components.
 	UpsertComponents(comps []*base.Component, force bool) (map[string]map[string]bool, error)
 
+	// Overwrite the writable fields of existing component c.ID with those of
+	// c, but only if the component still matches prev, i.e. it has not been
+	// changed since prev was read.  Returns false and writes nothing if it
+	// does not match or no longer exists.  ID, Type, Locked and
+	// ReservationDisabled are never changed.
+	UpdateComponentIfUnchanged(prev, c *base.Component) (bool, error)
+
+	// Set the fields given in p on every component matching f, within a
+	// single all-or-none transaction.  Returns the fields that changed
+	// ("flag", "enabled" and/or "swStatus") for each component that
+	// changed, by ID.
+	BulkPatchComponents(f *ComponentFilter, p *CompBulkPatch) (map[string]map[string]bool, error)
+
 	// Update state and flag fields only in DB for the given IDs.  If
 	// len(ids) is > 1 a locking read will be done to ensure the list o
 	// components that was actually modified is always returned.
@@ -269,7 +395,8 @@ type HMSDB interface {
 	BulkUpdateCompNID(comps *[]base.Component) error
 
 	// Delete HMS Component with matching xname id from database, if it
-	// exists.
+	// exists.  A tombstone is kept so it can be restored, see
+	// RestoreTombstone.
 	// Return true if there was a row affected, false if there were zero.
 	DeleteComponentByID(id string) (bool, error)
 
@@ -348,6 +475,11 @@ type HMSDB interface {
 	// do not match ALL of the non-empty strings in the filter struct
 	GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByLoc, error)
 
+	// Same selection as GetHWInvByLocFilter, but instead of returning the
+	// entries fn is called with each one, in xname order, as it is read.
+	// Stops at and returns the first error, including one returned by fn.
+	ForEachHWInvByLocFilter(fn func(*sm.HWInvByLoc) error, f_opts ...HWInvLocFiltFunc) error
+
 	// Get a single Hardware inventory entry by current xname
 	// This struct includes the FRU info if the xname is currently populated.
 	GetHWInvByLocID(id string) (*sm.HWInvByLoc, error)
@@ -367,6 +499,12 @@ type HMSDB interface {
 	// do not match ALL of the non-empty strings in the filter struct
 	GetHWInvByFRUFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByFRU, error)
 
+	// Find HW-inventory-by-FRU entries and the locations they are currently
+	// at, whatever their type.  Uses the Type, Manufacturer, PartNumber,
+	// SerialNumber and FruId filter options.  FRUs that are not installed
+	// anywhere have an empty Location.
+	LocateHWInvByFRU(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByFRULocation, error)
+
 	// Get all HW-inventory-by-FRU entries.
 	GetHWInvByFRUAll() ([]*sm.HWInvByFRU, error)
 
@@ -450,6 +588,17 @@ type HMSDB interface {
 	// Returns the number of deleted rows, if error is nil.
 	DeleteHWInvHistFilter(f_opts ...HWInvHistFiltFunc) (int64, error)
 
+	// Get the FRU sightings (when each FRU was first and last seen at each
+	// location) matching the filter, ordered by FRU ID and then by when they
+	// were first seen.  Only ID, FruId, StartTime and EndTime are used; a
+	// sighting matches the time range if it overlaps it.
+	GetHWInvSightingsFilter(f_opts ...HWInvHistFiltFunc) ([]*sm.HWInvSighting, error)
+
+	// Record that the FRUs populating hwlocs were seen at those locations
+	// now.  A FRU still at the location of its latest sighting has it
+	// extended, otherwise a new sighting is started.
+	RecordHWInvSightings(hwlocs []*sm.HWInvByLoc) error
+
 	//                                                                    //
 	//    Redfish Endpoints - Redfish service roots used for discovery    //
 	//                                                                    //
@@ -515,7 +664,8 @@ type HMSDB interface {
 	UpdateRFEndpoints(eps *sm.RedfishEndpointArray) (bool, error)
 
 	// Delete RedfishEndpoint with matching xname id from database, if it
-	// exists.
+	// exists.  A tombstone is kept so it can be restored, see
+	// RestoreTombstone.
 	// Return true if there was a row affected, false if there were zero.
 	DeleteRFEndpointByID(id string) (bool, error)
 
@@ -535,6 +685,12 @@ type HMSDB interface {
 	// Also returns number of deleted rows, if error is nil.
 	DeleteRFEndpointsAllSetEmpty() (int64, []string, error)
 
+	// Re-encrypt RedfishEndpoint passwords that are plaintext, or encrypted
+	// with a key other than the current one, e.g. after encryption is
+	// turned on or the key is rotated.  Returns the number re-encrypted,
+	// or ErrHMSDSNoEncryptKey if encryption is off.
+	ReencryptRFEndpointPasswords() (int, error)
+
 	//                                                                    //
 	// ComponentEndpoints: Component info discovered from Parent          //
 	//                     RedfishEndpoint.  Management plane equivalent  //
@@ -618,6 +774,32 @@ type HMSDB interface {
 	// Also returns number of deleted rows, if error is nil.
 	DeleteServiceEndpointsAll() (int64, error)
 
+	//                                                                    //
+	// Event Subscriptions: Redfish event subscriptions kept on           //
+	//                      RedfishEndpoints for the event collector.     //
+	//                                                                    //
+
+	// Get the event subscriptions kept for the given RedfishEndpoint ids,
+	// or for all RedfishEndpoints if ids is empty.
+	GetEventSubscriptions(ids []string) ([]*sm.EventSubscription, error)
+
+	// Insert the event subscription of a RedfishEndpoint, updating it if it
+	// exists.  LastUpdated is set to the current time.
+	UpsertEventSubscription(sub *sm.EventSubscription) error
+
+	//                                                                    //
+	// RedfishEndpoint Liveness: whether RedfishEndpoints answered the    //
+	//                           BMC prober.                              //
+	//                                                                    //
+
+	// Get the liveness of the given RedfishEndpoint ids, or of all
+	// RedfishEndpoints that have been probed if ids is empty.
+	GetRFEndpointLiveness(ids []string) ([]*sm.RedfishEndpointLiveness, error)
+
+	// Insert the liveness of a RedfishEndpoint, replacing it if it exists.
+	// The RedfishEndpoint must exist.
+	UpsertRFEndpointLiveness(l *sm.RedfishEndpointLiveness) error
+
 	//                                                                    //
 	//    Component Ethernet Interfaces - MAC address to IP address       //
 	//        relations for component endpoint ethernet interfaces        //
@@ -726,6 +908,13 @@ type HMSDB interface {
 	//    The actual HWInventoryByFRU is stored using within the same
 	//    transaction.
 	// 4. Inserts or updates HMS Components entries in ComponentArray
+	// 5. Upserts ServiceEndpoints and CompEthInterfaces
+	// 6. Adds 'Detected' HWInvHist entries for populated locations whose
+	//    FRU has changed since the last recorded event.
+	//
+	// If any step fails, the whole transaction is rolled back so the
+	// endpoint's data is never left partially updated.  Callers should
+	// send SCNs, etc. only after this returns successfully.
 	//
 	UpdateAllForRFEndpoint(
 		ep *sm.RedfishEndpoint,
@@ -736,6 +925,18 @@ type HMSDB interface {
 		ceis []*sm.CompEthInterfaceV2,
 	) (*[]base.Component, error)
 
+	// Same as UpdateAllForRFEndpoint, but the endpoint's data is stored in
+	// batches, each one as soon as next() returns it, so they need not all
+	// be held in memory at once.  Each batch is committed in its own
+	// transaction and the endpoint itself is updated last, after next()
+	// returns a nil batch.  If next() or a store fails, the error is
+	// returned and the endpoint is left as it was, but any batches already
+	// stored are kept.
+	UpdateAllForRFEndpointBatches(
+		ep *sm.RedfishEndpoint,
+		next func() (*RFEndpointBatch, error),
+	) (*[]base.Component, error)
+
 	//                                                                    //
 	//           SCNSubscription: SCN subscription management             //
 	//                                                                    //
@@ -786,6 +987,11 @@ type HMSDB interface {
 	// Get list of group labels (names).
 	GetGroupLabels() ([]string, error)
 
+	// Get the labels of the child groups of the group with the given label.
+	// If recursive, all descendants are returned, nearest first.  Returns
+	// ErrHMSDSNoGroup if the group does not exist.
+	GetGroupChildren(label string, recursive bool) ([]string, error)
+
 	// Delete entire group with the given label.  If no error, bool indicates
 	// whether member was present to remove.
 	DeleteGroup(label string) (bool, error)
@@ -811,6 +1017,11 @@ type HMSDB interface {
 	// Returns the ids of the members set in the group's member list.
 	SetGroupMembers(label string, ids []string) ([]string, error)
 
+	// Get the membership history of group label, oldest change first,
+	// optionally filtering.  The history is kept after the group is
+	// deleted, so a label that never existed just has no history.
+	GetGroupHist(label string, f *MemberHistFilter) ([]*sm.MemberHistEntry, error)
+
 	//                        Partitions
 
 	// Create a partition.  Returns new name (should match one in struct,
@@ -845,6 +1056,18 @@ type HMSDB interface {
 	// whether member was present to remove.
 	DeletePartitionMember(pname, id string) (bool, error)
 
+	// Move the members ids from partition from to partition to in a single
+	// transaction, so they are never in neither.  Returns ErrHMSDSNoPartition
+	// if either partition does not exist and ErrHMSDSNoPartitionMember if
+	// any of the ids is not in from, in which case nothing is moved.
+	// Returns the normalized ids that were moved.
+	MovePartitionMembers(from, to string, ids []string) ([]string, error)
+
+	// Get the membership history of partition pname, oldest change first,
+	// optionally filtering.  Like groups, this is kept after the partition
+	// is deleted.
+	GetPartitionHist(pname string, f *MemberHistFilter) ([]*sm.MemberHistEntry, error)
+
 	//                        Memberships
 
 	// Get the memberships for a particular component xname id
@@ -878,6 +1101,10 @@ type HMSDB interface {
 	// Release all expired reservations
 	DeleteCompReservationsExpired() ([]string, error)
 
+	// Retrieve the reservations that expire within the given duration from
+	// now, sorted by expiration time.
+	GetCompReservationsExpiring(within time.Duration) ([]sm.CompLockV2Expiring, error)
+
 	// Retrieve the status of reservations. The public key and xname is
 	// required to address the reservation.
 	GetCompReservations(dkeys []sm.CompLockV2Key) (sm.CompLockV2ReservationResult, error)
@@ -899,6 +1126,9 @@ type HMSDB interface {
 	// best try.
 	UpdateCompLocksV2(f sm.CompLockV2Filter, action string) (sm.CompLockV2UpdateResult, error)
 
+	// Get the lock audit log, oldest change first, optionally filtering.
+	GetCompLockAudit(f *CompLockAuditFilter) ([]*sm.CompLockV2AuditEntry, error)
+
 	//                                                                    //
 	//                        Job Sync Management                         //
 	//                                                                    //
@@ -922,6 +1152,102 @@ type HMSDB interface {
 	// Delete the job entry with the given jobId. If no error, bool indicates
 	// whether component lock was present to remove.
 	DeleteJob(jobId string) (bool, error)
+
+	//                                                                    //
+	//                              Leases                                //
+	//                                                                    //
+
+	// Take the named lease for holder for ttl, or renew it if holder
+	// already has it.  Returns false if someone else holds it and it hasn't
+	// expired.  Expiration times are by the database's clock.
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+
+	// Give up the named lease, if held by holder.  If no error, bool
+	// indicates whether it was held.
+	ReleaseLease(name, holder string) (bool, error)
+
+	// Get the leases whose names start with prefix and that haven't
+	// expired, by name.
+	GetLeases(prefix string) ([]*Lease, error)
+
+	//                                                                    //
+	//                      Rediscovery Schedules                         //
+	//                                                                    //
+
+	// Get all rediscovery schedules, by name.
+	GetDiscoverySchedules() ([]*sm.DiscoverySchedule, error)
+
+	// Insert a rediscovery schedule, replacing any with the same name.
+	UpsertDiscoverySchedule(ds *sm.DiscoverySchedule) error
+
+	// Delete the named rediscovery schedule.  If no error, bool indicates
+	// whether it was present to remove.
+	DeleteDiscoverySchedule(name string) (bool, error)
+
+	//                                                                    //
+	//                            Tombstones                              //
+	//                                                                    //
+
+	// Get the tombstones of deleted Components or RedfishEndpoints, as
+	// given by kind (sm.TombstoneComponent, sm.TombstoneRedfishEndpoint),
+	// newest first.  If id is not empty, only those for id are returned.
+	GetTombstones(kind, id string) ([]*sm.Tombstone, error)
+
+	// Restore the most recently deleted Component or RedfishEndpoint with
+	// the given id from its tombstone, which is removed.  If no error,
+	// bool indicates whether there was a tombstone to restore.  Returns
+	// ErrHMSDSDuplicateKey if id exists again.
+	RestoreTombstone(kind, id string) (bool, error)
+
+	// Delete the tombstones of anything deleted before the given time,
+	// returning how many were deleted.
+	PurgeTombstones(before time.Time) (int64, error)
+
+	//                                                                    //
+	//                            Heartbeats                              //
+	//                                                                    //
+
+	// Record a heartbeat now for each of the given components.  IDs that
+	// are not components are ignored.  Returns the heartbeats recorded,
+	// with the components' current State and Flag.
+	UpdateCompHeartbeats(ids []string) ([]*sm.CompHeartbeat, error)
+
+	// Get the last heartbeats of the components that have reported any,
+	// oldest first, optionally filtered.
+	GetCompHeartbeats(f *CompHeartbeatFilter) ([]*sm.CompHeartbeat, error)
+
+	//                                                                    //
+	//                        NID Assignment Policies                     //
+	//                                                                    //
+
+	// Get all NID policies, in the order they are tried: by Priority,
+	// then Name.
+	GetNIDPolicies() ([]*sm.NIDPolicy, error)
+
+	// Insert a NID policy, replacing any with the same name.
+	UpsertNIDPolicy(p *sm.NIDPolicy) error
+
+	// Delete the named NID policy.  If no error, bool indicates whether it
+	// was present to remove.
+	DeleteNIDPolicy(name string) (bool, error)
+
+	// Get the NIDs assigned by policies, by xname.  If ids is empty, all
+	// of them are returned.
+	GetNIDAssignments(ids []string) ([]*sm.NIDAssignment, error)
+
+	// Record a NID assigned by a policy.  If no error, bool indicates
+	// whether it was recorded, i.e. neither the xname nor the NID had
+	// already been assigned.
+	InsertNIDAssignment(a *sm.NIDAssignment) (bool, error)
+
+	// Delete the NID assigned to xname id, so its NID can be assigned
+	// again.  If no error, bool indicates whether it was present to
+	// remove.
+	DeleteNIDAssignment(id string) (bool, error)
+
+	// Get the NIDs from start to end used by components, NodeMaps or NID
+	// assignments, with the xnames using each.
+	GetNIDsInUse(start, end int64) (map[int64][]string, error)
 }
 
 // Table identifiers for generic queries
@@ -1017,6 +1343,18 @@ type HMSDBTx interface {
 	// of the non-empty strings in the filter struct.
 	GetComponentsQueryTx(f *ComponentFilter, fieldFltr FieldFilter, ids []string) ([]*base.Component, error)
 
+	// Get some or all HMS Components as they were at time asOf (RFC3339),
+	// from the component state history (in transaction).
+	GetComponentsAsOfTx(f *ComponentFilter, fieldFltr FieldFilter, asOf string) ([]*base.Component, error)
+
+	// Count the HMS Components in system by type and state (in
+	// transaction).
+	GetComponentCountsTx() ([]*ComponentCount, error)
+
+	// Get the current change counters of the components, groups and
+	// hardware inventory collections (in transaction).
+	GetCollectionVersionsTx() (*CollectionVersions, error)
+
 	// Get a single HMS Component by its NID, if the NID exists (in transaction)
 	GetComponentByNIDTx(nid string) (*base.Component, error)
 
@@ -1096,6 +1434,12 @@ type HMSDBTx interface {
 	// effectively unsets it and suppresses its output.
 	BulkUpdateCompNIDTx(comps []base.Component) error
 
+	// Overwrite the writable fields of an existing component, i.e. all but
+	// ID, Type, Locked and ReservationDisabled.  If NID is not set or
+	// negative, it is set to -1.  (In transaction.)
+	// Returns the number of affected rows. < 0 means RowsAffected() is not supported.
+	UpdateComponentTx(c *base.Component) (int64, error)
+
 	// Delete HMS Component with matching xname id from database, if it
 	// exists (in transaction)
 	// Return true if there was a row affected, false if there were zero.
@@ -1239,6 +1583,13 @@ type HMSDBTx interface {
 	// If a duplicate is present return an error.
 	InsertHWInvHistsTx(hhs []*sm.HWInvHist) error
 
+	// Get the FRU sightings matching the filter. (in transaction)
+	GetHWInvSightingsFilterTx(f_opts ...HWInvHistFiltFunc) ([]*sm.HWInvSighting, error)
+
+	// Record that the FRUs populating hwlocs were seen at those locations
+	// now. (in transaction)
+	RecordHWInvSightingsTx(hwlocs []*sm.HWInvByLoc) error
+
 	//                                                                    //
 	//    Redfish Endpoints - Redfish service roots used for discovery    //
 	//                                                                    //
@@ -1299,6 +1650,11 @@ type HMSDBTx interface {
 	// Also returns number of deleted rows, if error is nil.
 	DeleteRFEndpointsAllTx() (int64, error)
 
+	// Re-encrypt RedfishEndpoint passwords that are plaintext, or encrypted
+	// with a key other than the current one (in transaction).  Returns the
+	// number re-encrypted.
+	ReencryptRFEndpointPasswordsTx() (int, error)
+
 	// Given the id of a RedfishEndpoint, set the states of all children
 	// with State/Components entries to state and flag, returning a list of
 	// xname IDs were at least state or flag was updated.
@@ -1502,6 +1858,15 @@ type HMSDBTx interface {
 	// of the same one).
 	GetEmptyGroupTx(label string) (uuid string, g *sm.Group, err error)
 
+	// Replace the child groups of the group with the given uuid and label.
+	// Returns ErrHMSDSNoChildGroup if a child does not exist and
+	// ErrHMSDSGroupCycle if the group would end up its own descendant.
+	SetGroupChildrenTx(uuid, label string, children []string) error
+
+	// Get the labels of the child groups of the group with the given uuid.
+	// If recursive, all descendants are returned, nearest first.
+	GetGroupChildrenTx(uuid string, recursive bool) ([]string, error)
+
 	//                         Partitions
 
 	// Creates new partition  in component groups, but adds nothing to the members
@@ -1567,6 +1932,10 @@ type HMSDBTx interface {
 	// Release all expired component reservations
 	DeleteCompReservationExpiredTx() ([]string, error)
 
+	// Retrieve the reservations that expire within the given duration from
+	// now, sorted by expiration time.
+	GetCompReservationsExpiringTx(within time.Duration) ([]sm.CompLockV2Expiring, error)
+
 	// Retrieve the status of reservations. The public key and xname is
 	// required to address the reservation unless force = true.
 	GetCompReservationsTx(dKeys []sm.CompLockV2Key, force bool) ([]sm.CompLockV2Success, string, error)
@@ -1581,6 +1950,11 @@ type HMSDBTx interface {
 	// Update component 'locked' field.
 	BulkUpdateCompResLockedTx(ids []string, locked bool) ([]string, error)
 
+	// Set the action and reason recorded in the lock audit log for the rest
+	// of the transaction.  An empty action records what was actually done,
+	// e.g. Release or Expire for removed reservations.
+	SetCompLockAuditTx(action, reason string) error
+
 	//                                                                    //
 	//                        Job Sync Management                         //
 	//                                                                    //
@@ -29,13 +29,18 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
 
@@ -44,7 +49,7 @@ import (
 )
 
 // MUST be kept in sync with schema installed via smd-init job
-const HMSDS_PG_SCHEMA = 20
+const HMSDS_PG_SCHEMA = 37
 const HMSDS_PG_SYSTEM_ID = 0
 
 type hmsdbPg struct {
@@ -55,6 +60,66 @@ type hmsdbPg struct {
 	sc        *sq.StmtCache
 	lg        *log.Logger
 	lgLvl     LogLevel
+	actor     string             // Who is making changes, see WithActor
+	fieldEnv  *envcrypt.Envelope // Encrypts passwords, see SetFieldEncryption
+	pool      DBPoolConfig       // See SetPoolConfig
+	txStats   *pgTxStats         // Shared with WithActor handles
+}
+
+// Connection pool defaults, used where DBPoolConfig fields are zero.
+const (
+	// This needs to be less than Postgres' max_connections (by default
+	// 100), leaving a little slack for other clients.  The sql package
+	// default is unlimited and this causes the server-side limit to get
+	// overwhelmed.
+	DefaultDBMaxOpenConns = 70
+	// Same as the sql package default.
+	DefaultDBMaxIdleConns = 2
+)
+
+// Running counts for TxStats().
+type pgTxStats struct {
+	count     atomic.Uint64
+	slow      atomic.Uint64
+	max       atomic.Int64 // Nanoseconds
+	threshold time.Duration
+}
+
+// Record a transaction that took dur.  Does nothing if s is nil.
+func (s *pgTxStats) done(dur time.Duration) bool {
+	if s == nil {
+		return false
+	}
+	s.count.Add(1)
+	for {
+		max := s.max.Load()
+		if int64(dur) <= max || s.max.CompareAndSwap(max, int64(dur)) {
+			break
+		}
+	}
+	if s.threshold > 0 && dur >= s.threshold {
+		s.slow.Add(1)
+		return true
+	}
+	return false
+}
+
+// Add a connection parameter to a DSN in either key=value or URL form.
+// lib/pq sends parameters it doesn't know to the server as run-time
+// parameters, e.g. statement_timeout.
+func pgDSNWithParam(dsn, key, val string) string {
+	if strings.HasPrefix(dsn, "postgres://") ||
+		strings.HasPrefix(dsn, "postgresql://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		return dsn + sep + url.QueryEscape(key) + "=" + url.QueryEscape(val)
+	}
+	if dsn == "" {
+		return key + "=" + val
+	}
+	return dsn + " " + key + "=" + val
 }
 
 // Gen DSN for MySQL/MariaDB
@@ -96,6 +161,7 @@ func NewHMSDB_PG(dsn string, l *log.Logger) HMSDB {
 	d.connected = false
 	d.lgLvl = LOG_DEFAULT
 	d.ctx = context.TODO()
+	d.txStats = new(pgTxStats)
 
 	if l == nil {
 		d.lg = log.New(os.Stdout, "", log.Lshortfile|log.LstdFlags|log.Lmicroseconds)
@@ -213,10 +279,16 @@ func (d *hmsdbPg) Open() error {
 		d.LogAlways("Warning: Open(): Already called, but no Close()")
 		return nil
 	}
+	pool := d.PoolConfig()
+	dsn := d.dsn
+	if pool.StatementTimeout > 0 {
+		dsn = pgDSNWithParam(dsn, "statement_timeout",
+			strconv.FormatInt(pool.StatementTimeout.Milliseconds(), 10))
+	}
 	// Create long-lived database handle.  This handle can manage many
 	// concurrent DB connections up to the configured limit and is
 	// safe for use by multiple Go routes.
-	d.db, err = sql.Open("postgres", d.dsn)
+	d.db, err = sql.Open("postgres", dsn)
 	if err != nil {
 		d.LogAlways("Error: Open(): sql.Open failed: %s", err)
 		return err
@@ -240,21 +312,13 @@ func (d *hmsdbPg) Open() error {
 	//
 	// Configure connection here
 	//
-
-	// This needs to be less than MariaDB's current global max_connections
-	// via a cnf file or the default of 151.  The sql package default is
-	// unlimited and this causes the server-side limit to get overwhelmed.
-	// This should be kept in sync with the configured value in the
-	// mariadb docker container, which presently has it set to 100 (likely
-	// way too low).  Note that it is a global value, however, and we should
-	// leave a little slack in any case (setting it to 100 exacly causes
-	// an occasional failure, even with no other processes connecting).
-	d.db.SetMaxOpenConns(70)
-
-	// Workaround for HMS-1080, one of these, so long as a minute is less
-	// than wait_timeout.
-	//d.db.SetMaxIdleConns(0)
-	//d.db.SetConnMaxLifetime(time.Minute)
+	d.db.SetMaxOpenConns(pool.MaxOpenConns)
+	d.db.SetMaxIdleConns(pool.MaxIdleConns)
+	d.db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	d.db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	if d.txStats != nil {
+		d.txStats.threshold = pool.SlowTxThreshold
+	}
 
 	// Mark handle as connected, as we've successfully contacted the DB
 	// and are ready to perform queries.
@@ -341,6 +405,113 @@ func (d *hmsdbPg) Begin() (HMSDBTx, error) {
 	return nil, err
 }
 
+// Get a handle that records actor as whoever made the changes kept in a
+// history, e.g. group and partition membership.  It shares the
+// connection pool with the original handle.
+func (d *hmsdbPg) WithActor(actor string) HMSDB {
+	da := *d
+	da.actor = actor
+	return &da
+}
+
+// Encrypt RedfishEndpoint passwords with env as they are written, and
+// decrypt them as they are read.  Plaintext passwords written before are
+// still read as they are.  A nil env turns encryption off.  Must be set
+// before the handle is shared, e.g. with WithActor.
+func (d *hmsdbPg) SetFieldEncryption(env *envcrypt.Envelope) {
+	d.fieldEnv = env
+}
+
+// RedfishEndpoint passwords are sealed for their row, so they can't be
+// copied to another.
+func rfEPPasswordWhere(id string) string {
+	return rfEPsTable + "." + rfEPsPasswordCol + ":" + id
+}
+
+// Encrypt the password of the RedfishEndpoint id, if encryption is on.
+func (d *hmsdbPg) sealRFEPPassword(id, password string) (string, error) {
+	sealed, err := d.fieldEnv.Seal(password, rfEPPasswordWhere(id))
+	if err != nil {
+		d.LogAlwaysParentFunc("Error: encrypt password for %s: %s", id, err)
+		return "", err
+	}
+	return sealed, nil
+}
+
+// Decrypt the password of the RedfishEndpoint id as read from the database.
+func (d *hmsdbPg) openRFEPPassword(id, password string) (string, error) {
+	plain, err := d.fieldEnv.Open(password, rfEPPasswordWhere(id))
+	if err != nil {
+		d.LogAlwaysParentFunc("Error: decrypt password for %s: %s", id, err)
+		return "", err
+	}
+	return plain, nil
+}
+
+// Execute a single statement.  If there is an actor, this needs its own
+// transaction so the history triggers can see who it is.
+func (d *hmsdbPg) execWithActor(query sq.Sqlizer) (sql.Result, error) {
+	if d.actor == "" {
+		return sq.ExecContextWith(d.ctx, d.sc, query)
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	pt := t.(*hmsdbPgTx)
+	res, err := sq.ExecContextWith(pt.ctx, pt.sc, query)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	}
+	err = t.Commit()
+	return res, err
+}
+
+// Statistics of the connection pool, zero if not open.
+func (d *hmsdbPg) DBStats() sql.DBStats {
+	if d.db == nil {
+		return sql.DBStats{}
+	}
+	return d.db.Stats()
+}
+
+// Configure the connection pool.  Must be done before Open().
+func (d *hmsdbPg) SetPoolConfig(cfg DBPoolConfig) {
+	d.pool = cfg
+}
+
+// The connection pool configuration in use, with defaults filled in.  A
+// MaxOpenConns of 0 means no limit and a MaxIdleConns of 0 means none are
+// kept, as with sql.DB.
+func (d *hmsdbPg) PoolConfig() DBPoolConfig {
+	pool := d.pool
+	if pool.MaxOpenConns == 0 {
+		pool.MaxOpenConns = DefaultDBMaxOpenConns
+	} else if pool.MaxOpenConns < 0 {
+		pool.MaxOpenConns = 0
+	}
+	if pool.MaxIdleConns == 0 {
+		pool.MaxIdleConns = DefaultDBMaxIdleConns
+	} else if pool.MaxIdleConns < 0 {
+		pool.MaxIdleConns = 0
+	}
+	return pool
+}
+
+// Counts of transactions and of slow ones, see DBTxStats.
+func (d *hmsdbPg) TxStats() DBTxStats {
+	if d.txStats == nil {
+		return DBTxStats{}
+	}
+	return DBTxStats{
+		Transactions:     d.txStats.count.Load(),
+		SlowTransactions: d.txStats.slow.Load(),
+		SlowTxThreshold:  d.txStats.threshold,
+		MaxTxDuration:    time.Duration(d.txStats.max.Load()),
+	}
+}
+
 // Test the database connection to make sure that it is healthy
 func (d *hmsdbPg) TestConnection() error {
 	if !d.connected {
@@ -491,6 +662,119 @@ func (d *hmsdbPg) GetComponentsQuery(f *ComponentFilter, fieldFltr FieldFilter,
 	return comps, err
 }
 
+// Count the HMS Components in system by type and state, without reading
+// them all.
+func (d *hmsdbPg) GetComponentCounts() ([]*ComponentCount, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := t.GetComponentCountsTx()
+	if err != nil {
+		t.Rollback()
+		return counts, err
+	}
+	err = t.Commit()
+	return counts, err
+}
+
+// Get the current change counters of the components, groups and hardware
+// inventory collections.
+func (d *hmsdbPg) GetCollectionVersions() (*CollectionVersions, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	vers, err := t.GetCollectionVersionsTx()
+	if err != nil {
+		t.Rollback()
+		return vers, err
+	}
+	err = t.Commit()
+	return vers, err
+}
+
+// Get some or all HMS Components as they were at time asOf (RFC3339),
+// from the component state history, with the same filtering options as
+// GetComponentsFilter except for groups and partitions.
+func (d *hmsdbPg) GetComponentsAsOf(f *ComponentFilter, fieldFltr FieldFilter, asOf string) ([]*base.Component, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	comps, err := t.GetComponentsAsOfTx(f, fieldFltr, asOf)
+	if err != nil {
+		t.Rollback()
+		return comps, err
+	}
+	err = t.Commit()
+	return comps, err
+}
+
+// Get the State and Flag changes of component id, oldest first.  If
+// f has a StartTime, the first entry is the one in effect at that time,
+// which may be older, so the state over the whole range is known.  The
+// history is kept after the component is deleted.
+func (d *hmsdbPg) GetCompStateHist(id string, f *CompStateHistFilter) ([]*sm.CompStateHistEntry, error) {
+	id = xnametypes.NormalizeHMSCompID(id)
+	if !xnametypes.IsHMSCompIDValid(id) {
+		return nil, ErrHMSDSArgBadID
+	}
+	query := sq.Select(compStateCol, compFlagCol, compStateHistDeletedCol,
+		compStateHistActorCol, compStateHistTimestampCol).
+		From(compStateHistTable).
+		Where(sq.Eq{compIdCol: id})
+	if f != nil && f.StartTime != "" {
+		start, err := time.Parse(time.RFC3339, f.StartTime)
+		if err != nil {
+			return nil, ErrHMSDSArgBadTimeFormat
+		}
+		// Start with the last change at or before start.
+		query = query.Where(sq.Expr(compStateHistSeqCol+" >= COALESCE("+
+			"(SELECT MAX("+compStateHistSeqCol+") FROM "+compStateHistTable+
+			" WHERE "+compIdCol+" = ? AND "+compStateHistTimestampCol+
+			" <= ?), 0)", id, start))
+	}
+	if f != nil && f.EndTime != "" {
+		end, err := time.Parse(time.RFC3339, f.EndTime)
+		if err != nil {
+			return nil, ErrHMSDSArgBadTimeFormat
+		}
+		query = query.Where(sq.LtOrEq{compStateHistTimestampCol: end})
+	}
+	query = query.OrderBy(compStateHistSeqCol).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetCompStateHist(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	// The history has every change to the component, so skip those that
+	// didn't change its State or Flag.
+	hist := []*sm.CompStateHistEntry{}
+	var last *sm.CompStateHistEntry
+	for rows.Next() {
+		var ts time.Time
+		e := new(sm.CompStateHistEntry)
+		err := rows.Scan(&e.State, &e.Flag, &e.Deleted, &e.Actor, &ts)
+		if err != nil {
+			d.LogAlways("Error: GetCompStateHist(): scan failed: %s", err)
+			return nil, err
+		}
+		if last != nil && e.State == last.State && e.Flag == last.Flag &&
+			e.Deleted == last.Deleted {
+			continue
+		}
+		e.Timestamp = ts.Format(time.RFC3339Nano)
+		hist = append(hist, e)
+		last = e
+	}
+	return hist, rows.Err()
+}
+
 // Get a single component by its NID, if one exists.
 func (d *hmsdbPg) GetComponentByNID(nid string) (*base.Component, error) {
 	t, err := d.Begin()
@@ -1068,6 +1352,141 @@ func (d *hmsdbPg) UpdateCompNID(c *base.Component) error {
 	return err
 }
 
+// Overwrite the writable fields of existing component c.ID with those of
+// c, but only if the component still matches prev.  The component is
+// locked while it is compared and updated, so a concurrent writer either
+// finishes first, making this one fail, or waits for it.
+func (d *hmsdbPg) UpdateComponentIfUnchanged(prev, c *base.Component) (bool, error) {
+	if prev == nil || c == nil {
+		d.LogAlways("Error: UpdateComponentIfUnchanged(): Component was nil.")
+		return false, ErrHMSDSArgNil
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return false, err
+	}
+	comps, err := t.GetComponentsTx(
+		IDs([]string{c.ID}),
+		WRLock,
+		From("UpdateComponentIfUnchanged"),
+	)
+	if err != nil {
+		t.Rollback()
+		return false, err
+	}
+	if len(comps) == 0 || !reflect.DeepEqual(comps[0], prev) {
+		t.Rollback()
+		return false, nil
+	}
+	if _, err := t.UpdateComponentTx(c); err != nil {
+		t.Rollback()
+		return false, err
+	}
+	if err := t.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Set the fields given in p on every component matching f, within a
+// single all-or-none transaction.  Returns the fields that changed for each
+// component that changed, by ID.
+func (d *hmsdbPg) BulkPatchComponents(f *ComponentFilter, p *CompBulkPatch) (map[string]map[string]bool, error) {
+	if p == nil || (p.Flag == nil && p.Enabled == nil && p.SwStatus == nil) {
+		d.LogAlways("Error: BulkPatchComponents(): no fields to update")
+		return nil, ErrHMSDSArgMissing
+	}
+	flag := ""
+	if p.Flag != nil {
+		flag = base.VerifyNormalizeFlag(*p.Flag)
+		if flag == "" {
+			return nil, ErrHMSDSArgBadFlag
+		}
+	}
+	if f == nil {
+		f = new(ComponentFilter)
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]map[string]bool)
+	lockFilter := *f
+	if len(f.Group) != 0 || len(f.Partition) != 0 {
+		// Group and partition filters need a join that can't be locked, so
+		// find the matching IDs first, then lock just those components,
+		// checking the rest of the filter again in case they changed.
+		matches, err := t.GetComponentsFilterTx(f, FLTR_ID_ONLY)
+		if err != nil {
+			t.Rollback()
+			return nil, err
+		}
+		if len(matches) == 0 {
+			t.Rollback()
+			return changes, nil
+		}
+		lockFilter.ID = make([]string, 0, len(matches))
+		for _, c := range matches {
+			lockFilter.ID = append(lockFilter.ID, c.ID)
+		}
+		lockFilter.Group = nil
+		lockFilter.Partition = nil
+	}
+	lockFilter.writeLock = true
+	lockFilter.label = "BulkPatchComponents"
+	comps, err := t.GetComponentsFilterTx(&lockFilter, FLTR_DEFAULT)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	}
+
+	flagIDs := []string{}
+	enabledIDs := []string{}
+	swStatusIDs := []string{}
+	changed := func(id, field string) {
+		if changes[id] == nil {
+			changes[id] = make(map[string]bool)
+		}
+		changes[id][field] = true
+	}
+	for _, c := range comps {
+		if p.Flag != nil && c.Flag != flag {
+			flagIDs = append(flagIDs, c.ID)
+			changed(c.ID, "flag")
+		}
+		if p.Enabled != nil && (c.Enabled == nil || *c.Enabled != *p.Enabled) {
+			enabledIDs = append(enabledIDs, c.ID)
+			changed(c.ID, "enabled")
+		}
+		if p.SwStatus != nil && c.SwStatus != *p.SwStatus {
+			swStatusIDs = append(swStatusIDs, c.ID)
+			changed(c.ID, "swStatus")
+		}
+	}
+	if len(flagIDs) != 0 {
+		if _, err := t.BulkUpdateCompFlagOnlyTx(flagIDs, flag); err != nil {
+			t.Rollback()
+			return nil, err
+		}
+	}
+	if len(enabledIDs) != 0 {
+		if _, err := t.BulkUpdateCompEnabledTx(enabledIDs, *p.Enabled); err != nil {
+			t.Rollback()
+			return nil, err
+		}
+	}
+	if len(swStatusIDs) != 0 {
+		if _, err := t.BulkUpdateCompSwStatusTx(swStatusIDs, *p.SwStatus); err != nil {
+			t.Rollback()
+			return nil, err
+		}
+	}
+	if err := t.Commit(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
 // Delete HMS Component with matching xname id from database, if it
 // exists.
 // Return true if there was a row affected, false if there were zero.
@@ -1362,12 +1781,9 @@ func (d *hmsdbPg) GetHWInvByLocQueryFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HW
 	return hwlocs, err
 }
 
-// Get some or all Hardware Inventory entries with filtering
-// options to possibly narrow the returned values.
-// If no filter provided, just get everything.  Otherwise use it
-// to create a custom WHERE... string that filters out entries that
-// do not match ALL of the non-empty strings in the filter struct.
-func (d *hmsdbPg) GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByLoc, error) {
+// Build the query for the HWInvByLoc entries selected by the given filter
+// options, shared by GetHWInvByLocFilter and ForEachHWInvByLocFilter.
+func hwInvByLocFilterQuery(f_opts ...HWInvLocFiltFunc) (sq.SelectBuilder, error) {
 	var queryTable string
 
 	// Parse the filter options
@@ -1398,7 +1814,7 @@ func (d *hmsdbPg) GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvBy
 		for _, t := range f.Type {
 			normType := xnametypes.VerifyNormalizeType(t)
 			if normType == "" {
-				return nil, ErrHMSDSArgBadType
+				return query, ErrHMSDSArgBadType
 			}
 			tArgs = append(tArgs, normType)
 		}
@@ -1440,9 +1856,21 @@ func (d *hmsdbPg) GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvBy
 		partCol := hwInvAlias + "." + hwInvPartPartitionCol
 		query = query.Where(sq.Eq{partCol: f.Partition})
 	}
+	return query.PlaceholderFormat(sq.Dollar), nil
+}
+
+// Get some or all Hardware Inventory entries with filtering
+// options to possibly narrow the returned values.
+// If no filter provided, just get everything.  Otherwise use it
+// to create a custom WHERE... string that filters out entries that
+// do not match ALL of the non-empty strings in the filter struct.
+func (d *hmsdbPg) GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByLoc, error) {
+	query, err := hwInvByLocFilterQuery(f_opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	// Execute
-	query = query.PlaceholderFormat(sq.Dollar)
 	qStr, qArgs, _ := query.ToSql()
 	d.Log(LOG_DEBUG, "Debug: GetHWInvByFRUFilter(): Query: %s - With args: %v", qStr, qArgs)
 	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
@@ -1468,6 +1896,41 @@ func (d *hmsdbPg) GetHWInvByLocFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvBy
 	return hwlocs, err
 }
 
+// Call fn for each Hardware Inventory entry selected by the filter options,
+// in xname order, as it is read rather than collecting them all first.
+// Scanning stops at the first error, from the query or returned by fn.
+func (d *hmsdbPg) ForEachHWInvByLocFilter(fn func(*sm.HWInvByLoc) error, f_opts ...HWInvLocFiltFunc) error {
+	query, err := hwInvByLocFilterQuery(f_opts...)
+	if err != nil {
+		return err
+	}
+	query = query.OrderBy(hwInvAlias + "." + hwInvIdCol)
+
+	// Execute
+	qStr, qArgs, _ := query.ToSql()
+	d.Log(LOG_DEBUG, "Debug: ForEachHWInvByLocFilter(): Query: %s - With args: %v", qStr, qArgs)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		hwloc, err := d.scanHwInvByLocWithFRU(rows)
+		if err != nil {
+			d.LogAlways("Error: ForEachHWInvByLocFilter(): Scan failed: %s", err)
+			return err
+		}
+		if err := fn(hwloc); err != nil {
+			return err
+		}
+		i += 1
+	}
+	d.Log(LOG_INFO, "Info: ForEachHWInvByLocFilter() read %d hwinv items.", i)
+	return rows.Err()
+}
+
 // Get a single Hardware inventory entry by current xname
 // This struct includes the FRU info if the xname is currently populated.
 func (d *hmsdbPg) GetHWInvByLocID(id string) (*sm.HWInvByLoc, error) {
@@ -1602,32 +2065,103 @@ func (d *hmsdbPg) GetHWInvByFRUFilter(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvBy
 	return hwfrus, err
 }
 
-// Get all HW-inventory-by-FRU entries.
-func (d *hmsdbPg) GetHWInvByFRUAll() ([]*sm.HWInvByFRU, error) {
-	t, err := d.Begin()
-	if err != nil {
-		return nil, err
-	}
-	hwfrus, err := t.GetHWInvByFRUAllTx()
-	if err != nil {
-		t.Rollback()
-		return hwfrus, err
+// Find FRUs and the locations they are currently at, whatever their type.
+// Uses the Type, Manufacturer, PartNumber, SerialNumber and FruId options of
+// the filter.  Part and serial numbers must match exactly and use the
+// indexed columns, manufacturers match case-insensitively on any part of
+// the name.  FRUs that are not installed anywhere have an empty Location.
+func (d *hmsdbPg) LocateHWInvByFRU(f_opts ...HWInvLocFiltFunc) ([]*sm.HWInvByFRULocation, error) {
+	// Parse the filter options
+	f := new(HWInvLocFilter)
+	for _, opts := range f_opts {
+		opts(f)
 	}
-	t.Commit()
-	return hwfrus, err
-}
 
-// Insert or update HWInventoryByLocation struct.
-// If PopulatedFRU is present, this is also added to the DB  If
-// it is not, this effectively "depopulates" the given location.
-// The actual HWInventoryByFRU is stored using within the same
-// transaction.
-func (d *hmsdbPg) InsertHWInvByLoc(hl *sm.HWInvByLoc) error {
-	t, err := d.Begin()
-	if err != nil {
-		return err
-	}
-	if hl.PopulatedFRU != nil {
+	cols := append([]string{"COALESCE(" + hwInvLocIdColAlias + ", '')"},
+		addAliasToCols(hwInvFruAlias, hwInvFruTblCols, hwInvFruTblCols)...)
+	query := sq.Select(cols...).
+		From(hwInvFruTable + " " + hwInvFruAlias).
+		LeftJoin(hwInvLocTable + " " + hwInvLocAlias + " ON " +
+			hwInvLocFruIdColAlias + " = " + hwInvFruTblIdColAlias)
+	if len(f.Type) > 0 {
+		tArgs := []string{}
+		for _, t := range f.Type {
+			normType := xnametypes.VerifyNormalizeType(t)
+			if normType == "" {
+				return nil, ErrHMSDSArgBadType
+			}
+			tArgs = append(tArgs, normType)
+		}
+		query = query.Where(sq.Eq{hwInvFruTblTypeColAlias: tArgs})
+	}
+	if len(f.Manufacturer) > 0 {
+		mOr := sq.Or{}
+		for _, m := range f.Manufacturer {
+			mOr = append(mOr, sq.ILike{hwInvFruTblManufacturerColAlias: "%" + m + "%"})
+		}
+		query = query.Where(mOr)
+	}
+	if len(f.PartNumber) > 0 {
+		query = query.Where(sq.Eq{hwInvFruTblPartColAlias: f.PartNumber})
+	}
+	if len(f.SerialNumber) > 0 {
+		query = query.Where(sq.Eq{hwInvFruTblSerialColAlias: f.SerialNumber})
+	}
+	if len(f.FruId) > 0 {
+		query = query.Where(sq.Eq{hwInvFruTblIdColAlias: f.FruId})
+	}
+	query = query.OrderBy(hwInvFruTblIdColAlias)
+
+	// Execute
+	query = query.PlaceholderFormat(sq.Dollar)
+	qStr, qArgs, _ := query.ToSql()
+	d.Log(LOG_DEBUG, "Debug: LocateHWInvByFRU(): Query: %s - With args: %v", qStr, qArgs)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hwfrus := make([]*sm.HWInvByFRULocation, 0, 1)
+	for rows.Next() {
+		hwfru, err := d.scanHwInvByFRULocation(rows)
+		if err != nil {
+			d.LogAlways("Error: LocateHWInvByFRU(): Scan failed: %s", err)
+			return hwfrus, err
+		}
+		hwfrus = append(hwfrus, hwfru)
+	}
+	err = rows.Err()
+	d.Log(LOG_INFO, "Info: LocateHWInvByFRU() returned %d hwinv items.", len(hwfrus))
+	return hwfrus, err
+}
+
+// Get all HW-inventory-by-FRU entries.
+func (d *hmsdbPg) GetHWInvByFRUAll() ([]*sm.HWInvByFRU, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	hwfrus, err := t.GetHWInvByFRUAllTx()
+	if err != nil {
+		t.Rollback()
+		return hwfrus, err
+	}
+	t.Commit()
+	return hwfrus, err
+}
+
+// Insert or update HWInventoryByLocation struct.
+// If PopulatedFRU is present, this is also added to the DB  If
+// it is not, this effectively "depopulates" the given location.
+// The actual HWInventoryByFRU is stored using within the same
+// transaction.
+func (d *hmsdbPg) InsertHWInvByLoc(hl *sm.HWInvByLoc) error {
+	t, err := d.Begin()
+	if err != nil {
+		return err
+	}
+	if hl.PopulatedFRU != nil {
 		err = t.InsertHWInvByFRUTx(hl.PopulatedFRU)
 		if err != nil {
 			t.Rollback()
@@ -1843,6 +2377,38 @@ func (d *hmsdbPg) InsertHWInvHists(hhs []*sm.HWInvHist) error {
 	return err
 }
 
+// Get the FRU sightings matching the filter, ordered by FRU ID and then by
+// when they were first seen.  Only ID, FruId, StartTime and EndTime are
+// used; a sighting matches the time range if it overlaps it.
+func (d *hmsdbPg) GetHWInvSightingsFilter(f_opts ...HWInvHistFiltFunc) ([]*sm.HWInvSighting, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	sightings, err := t.GetHWInvSightingsFilterTx(f_opts...)
+	if err != nil {
+		t.Rollback()
+		return sightings, err
+	}
+	err = t.Commit()
+	return sightings, err
+}
+
+// Record that the FRUs populating hwlocs were seen at those locations now.
+func (d *hmsdbPg) RecordHWInvSightings(hwlocs []*sm.HWInvByLoc) error {
+	t, err := d.Begin()
+	if err != nil {
+		return err
+	}
+	err = t.RecordHWInvSightingsTx(hwlocs)
+	if err != nil {
+		t.Rollback()
+		return err
+	}
+	err = t.Commit()
+	return err
+}
+
 // Delete all HWInvHist entries with matching xname id from database, if it
 // exists.
 // Returns the number of deleted rows, if error is nil.
@@ -2262,6 +2828,12 @@ func (d *hmsdbPg) PatchRFEndpointNoDiscInfo(id string, epp sm.RedfishEndpointPat
 	} else {
 		rep.TemplateID = getEP.TemplateID
 	}
+	if epp.Protocol != nil && getEP.Protocol != *epp.Protocol {
+		rep.Protocol = *epp.Protocol
+		haveUpdate = true
+	} else {
+		rep.Protocol = getEP.Protocol
+	}
 	if !haveUpdate {
 		t.Rollback()
 		return getEP, []string{}, nil
@@ -2477,6 +3049,29 @@ func (d *hmsdbPg) DeleteRFEndpointsAllSetEmpty() (int64, []string, error) {
 	return numDeleted, affectedIDs, nil
 }
 
+// Re-encrypt RedfishEndpoint passwords that are plaintext, or encrypted
+// with a key other than the current one, e.g. after encryption is turned on
+// or the key is rotated.  Returns the number re-encrypted, or
+// ErrHMSDSNoEncryptKey if encryption is off.
+func (d *hmsdbPg) ReencryptRFEndpointPasswords() (int, error) {
+	if d.fieldEnv == nil {
+		return 0, ErrHMSDSNoEncryptKey
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return 0, err
+	}
+	num, err := t.ReencryptRFEndpointPasswordsTx()
+	if err != nil {
+		t.Rollback()
+		return 0, err
+	}
+	if err := t.Commit(); err != nil {
+		return 0, err
+	}
+	return num, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Component Endpoints - Component info discovered from parent RedfishEndpoint
@@ -2800,6 +3395,177 @@ func (d *hmsdbPg) DeleteServiceEndpointsAll() (int64, error) {
 	return numDeleted, nil
 }
 
+////////////////////////////////////////////////////////////////////////////
+//
+// Event Subscriptions - Redfish event subscriptions kept on RedfishEndpoints
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get the event subscriptions kept for the given RedfishEndpoint ids,
+// or for all RedfishEndpoints if ids is empty.
+func (d *hmsdbPg) GetEventSubscriptions(ids []string) ([]*sm.EventSubscription, error) {
+	query := sq.Select(eventSubsCols...).
+		From(eventSubsTable)
+	if len(ids) > 0 {
+		normIDs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			normIDs = append(normIDs, xnametypes.NormalizeHMSCompID(id))
+		}
+		query = query.Where(sq.Eq{eventSubsRFEndpointIDCol: normIDs})
+	}
+	query = query.OrderBy(eventSubsRFEndpointIDCol)
+
+	// Execute
+	query = query.PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := make([]*sm.EventSubscription, 0, 1)
+	for rows.Next() {
+		sub := new(sm.EventSubscription)
+		err := rows.Scan(&sub.RedfishEndpointID, &sub.Destination,
+			&sub.Context, &sub.SubscriptionURI, &sub.Status, &sub.LastError,
+			&sub.LastUpdated)
+		if err != nil {
+			d.LogAlways("Error: GetEventSubscriptions(): Scan failed: %s", err)
+			return subs, err
+		}
+		subs = append(subs, sub)
+	}
+	err = rows.Err()
+	d.Log(LOG_INFO, "Info: GetEventSubscriptions() returned %d items.", len(subs))
+	return subs, err
+}
+
+// Insert the event subscription of a RedfishEndpoint, updating it if it
+// exists.  LastUpdated is set to the current time.
+func (d *hmsdbPg) UpsertEventSubscription(sub *sm.EventSubscription) error {
+	if sub == nil {
+		d.LogAlways("Error: UpsertEventSubscription(): Subscription was nil.")
+		return ErrHMSDSArgNil
+	}
+	id := xnametypes.NormalizeHMSCompID(sub.RedfishEndpointID)
+	if !xnametypes.IsHMSCompIDValid(id) {
+		return ErrHMSDSArgBadID
+	}
+	query := sq.Insert(eventSubsTable).
+		Columns(eventSubsCols...).
+		Values(id, sub.Destination, sub.Context, sub.SubscriptionURI,
+			sub.Status, sub.LastError, sq.Expr("NOW()")).
+		Suffix("ON CONFLICT(" + eventSubsRFEndpointIDCol + ") DO UPDATE SET " +
+			eventSubsDestinationCol + " = EXCLUDED." + eventSubsDestinationCol + ", " +
+			eventSubsContextCol + " = EXCLUDED." + eventSubsContextCol + ", " +
+			eventSubsSubscriptionURICol + " = EXCLUDED." + eventSubsSubscriptionURICol + ", " +
+			eventSubsStatusCol + " = EXCLUDED." + eventSubsStatusCol + ", " +
+			eventSubsLastErrorCol + " = EXCLUDED." + eventSubsLastErrorCol + ", " +
+			eventSubsLastUpdatedCol + " = EXCLUDED." + eventSubsLastUpdatedCol)
+
+	// Execute
+	query = query.PlaceholderFormat(sq.Dollar)
+	_, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: UpsertEventSubscription(%s): %s", id, err)
+		return ParsePgDBError(err)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// RedfishEndpoint Liveness - whether RedfishEndpoints answered the BMC prober
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get the liveness of the given RedfishEndpoint ids, or of all
+// RedfishEndpoints that have been probed if ids is empty.
+func (d *hmsdbPg) GetRFEndpointLiveness(ids []string) ([]*sm.RedfishEndpointLiveness, error) {
+	query := sq.Select(rfEPLivenessCols...).
+		From(rfEPLivenessTable)
+	if len(ids) > 0 {
+		normIDs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			normIDs = append(normIDs, xnametypes.NormalizeHMSCompID(id))
+		}
+		query = query.Where(sq.Eq{rfEPLivenessRFEndpointIDCol: normIDs})
+	}
+	query = query.OrderBy(rfEPLivenessRFEndpointIDCol)
+
+	// Execute
+	query = query.PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lvs := make([]*sm.RedfishEndpointLiveness, 0, 1)
+	for rows.Next() {
+		var contact sql.NullTime
+		var probe time.Time
+		l := new(sm.RedfishEndpointLiveness)
+		err := rows.Scan(&l.RedfishEndpointID, &contact, &probe,
+			&l.Failures, &l.Unreachable, &l.LastError)
+		if err != nil {
+			d.LogAlways("Error: GetRFEndpointLiveness(): Scan failed: %s", err)
+			return lvs, err
+		}
+		if contact.Valid {
+			l.LastContact = contact.Time.UTC().Format(time.RFC3339)
+		}
+		l.LastProbe = probe.UTC().Format(time.RFC3339)
+		lvs = append(lvs, l)
+	}
+	err = rows.Err()
+	d.Log(LOG_INFO, "Info: GetRFEndpointLiveness() returned %d items.", len(lvs))
+	return lvs, err
+}
+
+// Insert the liveness of a RedfishEndpoint, replacing it if it exists.
+// The RedfishEndpoint must exist.
+func (d *hmsdbPg) UpsertRFEndpointLiveness(l *sm.RedfishEndpointLiveness) error {
+	if l == nil {
+		d.LogAlways("Error: UpsertRFEndpointLiveness(): Liveness was nil.")
+		return ErrHMSDSArgNil
+	}
+	id := xnametypes.NormalizeHMSCompID(l.RedfishEndpointID)
+	if !xnametypes.IsHMSCompIDValid(id) {
+		return ErrHMSDSArgBadID
+	}
+	probe, err := time.Parse(time.RFC3339, l.LastProbe)
+	if err != nil {
+		return ErrHMSDSArgBadTimeFormat
+	}
+	var contact interface{}
+	if l.LastContact != "" {
+		t, err := time.Parse(time.RFC3339, l.LastContact)
+		if err != nil {
+			return ErrHMSDSArgBadTimeFormat
+		}
+		contact = t
+	}
+	query := sq.Insert(rfEPLivenessTable).
+		Columns(rfEPLivenessCols...).
+		Values(id, contact, probe, l.Failures, l.Unreachable, l.LastError).
+		Suffix("ON CONFLICT(" + rfEPLivenessRFEndpointIDCol + ") DO UPDATE SET " +
+			rfEPLivenessLastContactCol + " = EXCLUDED." + rfEPLivenessLastContactCol + ", " +
+			rfEPLivenessLastProbeCol + " = EXCLUDED." + rfEPLivenessLastProbeCol + ", " +
+			rfEPLivenessFailuresCol + " = EXCLUDED." + rfEPLivenessFailuresCol + ", " +
+			rfEPLivenessUnreachableCol + " = EXCLUDED." + rfEPLivenessUnreachableCol + ", " +
+			rfEPLivenessLastErrorCol + " = EXCLUDED." + rfEPLivenessLastErrorCol)
+
+	// Execute
+	query = query.PlaceholderFormat(sq.Dollar)
+	_, err = query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: UpsertRFEndpointLiveness(%s): %s", id, err)
+		return ParsePgDBError(err)
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Component Ethernet Interfaces - MAC address to IP address relations for
@@ -3359,6 +4125,11 @@ func (d *hmsdbPg) UpsertDiscoveryStatus(stat *sm.DiscoveryStatus) error {
 //     The actual HWInventoryByFRU is stored using within the same
 //     transaction.
 //  4. Inserts or updates HMS Components entries in ComponentArray
+//  5. Upserts ServiceEndpoints and CompEthInterfaces
+//  6. Adds 'Detected' HWInvHist entries for populated locations whose
+//     FRU has changed since the last recorded event.
+//
+// If any step fails, the whole transaction is rolled back.
 func (d *hmsdbPg) UpdateAllForRFEndpoint(
 	ep *sm.RedfishEndpoint,
 	ceps *sm.ComponentEndpointArray,
@@ -3368,60 +4139,165 @@ func (d *hmsdbPg) UpdateAllForRFEndpoint(
 	ceis []*sm.CompEthInterfaceV2,
 ) (*[]base.Component, error) {
 
-	discoveredIDs := make([]base.Component, 0, len(comps.Components))
+	discoveredIDs := make([]base.Component, 0, 1)
 
 	t, err := d.Begin()
 	if err != nil {
 		return nil, err
 	}
-	// Do update of RedfishEndpoint
-	didUpdate, err := t.UpdateRFEndpointTx(ep)
+	if err := updateRFEndpointForStoreTx(t, ep); err != nil {
+		t.Rollback()
+		return nil, err
+	}
+	b := &RFEndpointBatch{
+		CompEndpoints:     ceps,
+		HWInvByLocs:       hls,
+		Components:        comps,
+		ServiceEndpoints:  seps,
+		CompEthInterfaces: ceis,
+	}
+	if err := d.storeRFEndpointBatchTx(t, b, &discoveredIDs); err != nil {
+		t.Rollback()
+		return nil, err
+	}
+	if err := t.Commit(); err != nil {
+		return nil, err
+	}
+	return &discoveredIDs, nil
+}
+
+// Same as UpdateAllForRFEndpoint, but with the endpoint's data stored in
+// batches as next() returns them.  Each batch is committed in its own
+// transaction, so none is held open while next() runs.  The endpoint
+// itself is updated last, once every batch is stored.  If next() or
+// storing a batch fails, the error is returned and the endpoint is not
+// updated, but the batches already stored are kept.
+func (d *hmsdbPg) UpdateAllForRFEndpointBatches(
+	ep *sm.RedfishEndpoint,
+	next func() (*RFEndpointBatch, error),
+) (*[]base.Component, error) {
+
+	discoveredIDs := make([]base.Component, 0, 1)
+
+	// Make sure the entry exists or the batches will fail.
+	rep, err := d.GetRFEndpointByID(ep.ID)
+	if err != nil {
+		return nil, err
+	} else if rep == nil {
+		return nil, ErrHMSDSArgNoMatch
+	}
+	for {
+		b, err := next()
+		if err != nil {
+			return nil, err
+		} else if b == nil {
+			break
+		}
+		t, err := d.Begin()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.storeRFEndpointBatchTx(t, b, &discoveredIDs); err != nil {
+			t.Rollback()
+			return nil, err
+		}
+		if err := t.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	t, err := d.Begin()
 	if err != nil {
+		return nil, err
+	}
+	if err := updateRFEndpointForStoreTx(t, ep); err != nil {
 		t.Rollback()
 		return nil, err
 	}
+	if err := t.Commit(); err != nil {
+		return nil, err
+	}
+	return &discoveredIDs, nil
+}
+
+// Update the discovery-writable fields of the RedfishEndpoint before
+// storing what was discovered through it.  Returns ErrHMSDSArgNoMatch if
+// there is no such endpoint.
+func updateRFEndpointForStoreTx(t HMSDBTx, ep *sm.RedfishEndpoint) error {
+	didUpdate, err := t.UpdateRFEndpointTx(ep)
+	if err != nil {
+		return err
+	}
 	// Make sure entry exists or the other stores will fail.
 	_, err = t.GetRFEndpointByIDTx(ep.ID)
 	if err != nil {
 		if didUpdate != true {
 			// No update because there was no entry
-			t.Rollback()
-			return nil, ErrHMSDSArgNoMatch
-		} else {
-			t.Rollback()
-			return nil, err
+			return ErrHMSDSArgNoMatch
 		}
+		return err
 	}
+	return nil
+}
+
+// Store one batch of a RedfishEndpoint's discovered data.  The Components
+// that were added or changed are appended to discoveredIDs.
+func (d *hmsdbPg) storeRFEndpointBatchTx(
+	t HMSDBTx,
+	b *RFEndpointBatch,
+	discoveredIDs *[]base.Component,
+) error {
 	// Upsert ComponentEndpointArray into database
-	if ceps != nil {
-		err = t.UpsertCompEndpointsTx(ceps)
+	if b.CompEndpoints != nil {
+		err := t.UpsertCompEndpointsTx(b.CompEndpoints)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
 		}
 	}
 	// Insert FRUs first because the location info links to them.
-	if hls != nil {
+	if hls := b.HWInvByLocs; hls != nil {
 		hfs := make([]*sm.HWInvByFRU, 0, len(hls))
 		for _, hl := range hls {
 			if hl.PopulatedFRU != nil {
 				hfs = append(hfs, hl.PopulatedFRU)
 			}
 		}
-		err = t.BulkInsertHWInvByFRUTx(hfs)
+		err := t.BulkInsertHWInvByFRUTx(hfs)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
 		}
 		// Now insert HWInvByLocation so that the FRU link will exist.
 		err = t.BulkInsertHWInvByLocTx(hls)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
+		}
+		// Record any newly detected FRUs in the history.
+		locIDs := make([]string, 0, len(hfs))
+		for _, hl := range hls {
+			if hl.PopulatedFRU != nil {
+				locIDs = append(locIDs, hl.ID)
+			}
+		}
+		if len(locIDs) > 0 {
+			lhs, err := t.GetHWInvHistLastEventsTx(locIDs)
+			if err != nil {
+				return err
+			}
+			hhs := sm.NewHWInvHistsDetected(hls, lhs)
+			if len(hhs) > 0 {
+				err = t.InsertHWInvHistsTx(hhs)
+				if err != nil {
+					return err
+				}
+			}
+			// Track where each FRU has been seen.
+			err = t.RecordHWInvSightingsTx(hls)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	// Inserts or updates HMS Components entries
-	if comps != nil {
+	if comps := b.Components; comps != nil {
 		compMap := make(map[string]*base.Component)
 		nodeList := make([]string, 0, 1)
 		for _, comp := range comps.Components {
@@ -3439,8 +4315,7 @@ func (d *hmsdbPg) UpdateAllForRFEndpoint(
 		if len(nodeList) > 0 {
 			nodes, err := t.GetComponentsTx(IDs(nodeList), From("UpdateAllForRFEndpoint"))
 			if err != nil {
-				t.Rollback()
-				return nil, err
+				return err
 			}
 			for _, compOld := range nodes {
 				compNew, ok := compMap[compOld.ID]
@@ -3475,35 +4350,29 @@ func (d *hmsdbPg) UpdateAllForRFEndpoint(
 		}
 		rowsAffected, err := t.InsertComponentsTx(comps.Components)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
 		}
 		for _, id := range rowsAffected {
 			if comp, ok := compMap[id]; ok {
-				discoveredIDs = append(discoveredIDs, *comp)
+				*discoveredIDs = append(*discoveredIDs, *comp)
 			}
 		}
 	}
 	// Upsert ServiceEndpointArray into database
-	if seps != nil {
-		err = t.UpsertServiceEndpointsTx(seps)
+	if b.ServiceEndpoints != nil {
+		err := t.UpsertServiceEndpointsTx(b.ServiceEndpoints)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
 		}
 	}
 	// Insert CompEthInterfaces into the database
-	if ceis != nil {
-		err = t.InsertCompEthInterfacesCompInfoTx(ceis)
+	if b.CompEthInterfaces != nil {
+		err := t.InsertCompEthInterfacesCompInfoTx(b.CompEthInterfaces)
 		if err != nil {
-			t.Rollback()
-			return nil, err
+			return err
 		}
 	}
-	if err := t.Commit(); err != nil {
-		return nil, err
-	}
-	return &discoveredIDs, nil
+	return nil
 }
 
 ////////////////////////////////////////////////////////////////////////////
@@ -3654,6 +4523,12 @@ func (d *hmsdbPg) InsertGroup(g *sm.Group) (string, error) {
 		t.Rollback()
 		return "", err
 	}
+	if len(g.Children) != 0 {
+		if err = t.SetGroupChildrenTx(uuid, label, g.Children); err != nil {
+			t.Rollback()
+			return "", err
+		}
+	}
 	err = t.Commit()
 	return label, err
 }
@@ -3684,6 +4559,12 @@ func (d *hmsdbPg) UpdateGroup(label string, gp *sm.GroupPatch) error {
 		t.Rollback()
 		return err
 	}
+	if gp.Children != nil {
+		if err := t.SetGroupChildrenTx(uuid, g.Label, *gp.Children); err != nil {
+			t.Rollback()
+			return err
+		}
+	}
 	return t.Commit()
 }
 
@@ -3768,6 +4649,31 @@ func (d *hmsdbPg) GetGroupLabels() ([]string, error) {
 	return labels, nil
 }
 
+// Get the labels of the child groups of the group with the given label.
+// If recursive, all descendants are returned, nearest first.  Returns
+// ErrHMSDSNoGroup if the group does not exist.
+func (d *hmsdbPg) GetGroupChildren(label string, recursive bool) ([]string, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	uuid, g, err := t.GetEmptyGroupTx(label)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	} else if g == nil || uuid == "" {
+		t.Rollback()
+		return nil, ErrHMSDSNoGroup
+	}
+	children, err := t.GetGroupChildrenTx(uuid, recursive)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	}
+	err = t.Commit()
+	return children, err
+}
+
 // Delete entire group with the given label.  If no error, bool indicates
 // whether member was present to remove.
 func (d *hmsdbPg) DeleteGroup(label string) (bool, error) {
@@ -3778,7 +4684,7 @@ func (d *hmsdbPg) DeleteGroup(label string) (bool, error) {
 
 	// Execute delete query
 	query = query.PlaceholderFormat(sq.Dollar)
-	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	res, err := d.execWithActor(query)
 	if err != nil {
 		return false, err
 	}
@@ -3869,6 +4775,13 @@ func (d *hmsdbPg) DeleteGroupMember(label, id string) (bool, error) {
 	return didDelete, err
 }
 
+// Get the membership history of group label, oldest change first,
+// optionally filtering.  The history is kept after the group is
+// deleted, so a label that never existed just has no history.
+func (d *hmsdbPg) GetGroupHist(label string, f *MemberHistFilter) ([]*sm.MemberHistEntry, error) {
+	return d.getMemberHist(sm.NormalizeGroupField(label), groupNamespace, f)
+}
+
 // Sets membership list of group label to ids. If any xnames in ids already
 // exist in group, they stay in the group. If any xnames in ids do not already
 // exist in group, they are added. If any xnames that already exist in group are
@@ -4055,7 +4968,7 @@ func (d *hmsdbPg) DeletePartition(pname string) (bool, error) {
 
 	// Execute
 	query = query.PlaceholderFormat(sq.Dollar)
-	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	res, err := d.execWithActor(query)
 	if err != nil {
 		return false, err
 	}
@@ -4140,6 +5053,135 @@ func (d *hmsdbPg) DeletePartitionMember(pname, id string) (bool, error) {
 	return didDelete, err
 }
 
+// Move the members ids from partition from to partition to in a single
+// transaction, so they are never in neither.  Returns ErrHMSDSNoPartition
+// if either partition does not exist and ErrHMSDSNoPartitionMember if
+// any of the ids is not in from, in which case nothing is moved.
+// Returns the normalized ids that were moved.
+func (d *hmsdbPg) MovePartitionMembers(from, to string, ids []string) ([]string, error) {
+	// Prep ids for insertion and verify them.
+	ms := new(sm.Members)
+	ms.IDs = append(ms.IDs, ids...)
+	ms.Normalize()
+	if err := ms.Verify(); err != nil {
+		return nil, err
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	fromUuid, p, err := t.GetEmptyPartitionTx(from)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	} else if p == nil || fromUuid == "" {
+		t.Rollback()
+		return nil, ErrHMSDSNoPartition
+	}
+	toUuid, p, err := t.GetEmptyPartitionTx(to)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	} else if p == nil || toUuid == "" {
+		t.Rollback()
+		return nil, ErrHMSDSNoPartition
+	}
+	// Remove them all first, so adding them to the new partition can't
+	// clash with the old one.
+	for _, id := range ms.IDs {
+		didDelete, err := t.DeleteMemberTx(fromUuid, id)
+		if err != nil {
+			t.Rollback()
+			return nil, err
+		} else if !didDelete {
+			t.Rollback()
+			return nil, ErrHMSDSNoPartitionMember
+		}
+	}
+	err = t.InsertMembersTx(toUuid, partGroupNamespace, ms)
+	if err != nil {
+		t.Rollback()
+		return nil, err
+	}
+	err = t.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return ms.IDs, nil
+}
+
+// Get the membership history of partition pname, oldest change first,
+// optionally filtering.  Like groups, this is kept after the partition
+// is deleted.
+func (d *hmsdbPg) GetPartitionHist(pname string, f *MemberHistFilter) ([]*sm.MemberHistEntry, error) {
+	return d.getMemberHist(sm.NormalizeGroupField(pname), partNamespace, f)
+}
+
+//
+// Membership history
+//
+
+// Get the membership history of the group or partition with the given
+// name in namespace, oldest change first.
+func (d *hmsdbPg) getMemberHist(name, namespace string, f *MemberHistFilter) ([]*sm.MemberHistEntry, error) {
+	query := sq.Select(compGroupMembersHistCompIdCol,
+		compGroupMembersHistActionCol,
+		compGroupMembersHistActorCol,
+		compGroupMembersHistTimestampCol).
+		From(compGroupMembersHistTable).
+		Where(sq.Eq{compGroupMembersHistNameCol: name}).
+		Where(sq.Eq{compGroupMembersHistNamespaceCol: namespace})
+	if f != nil {
+		if len(f.ID) > 0 {
+			ids := make([]string, 0, len(f.ID))
+			for _, id := range f.ID {
+				nid := xnametypes.NormalizeHMSCompID(id)
+				if !xnametypes.IsHMSCompIDValid(nid) {
+					return nil, ErrHMSDSArgBadID
+				}
+				ids = append(ids, nid)
+			}
+			query = query.Where(sq.Eq{compGroupMembersHistCompIdCol: ids})
+		}
+		if f.StartTime != "" {
+			start, err := time.Parse(time.RFC3339, f.StartTime)
+			if err != nil {
+				return nil, ErrHMSDSArgBadTimeFormat
+			}
+			query = query.Where(sq.GtOrEq{compGroupMembersHistTimestampCol: start})
+		}
+		if f.EndTime != "" {
+			end, err := time.Parse(time.RFC3339, f.EndTime)
+			if err != nil {
+				return nil, ErrHMSDSArgBadTimeFormat
+			}
+			query = query.Where(sq.LtOrEq{compGroupMembersHistTimestampCol: end})
+		}
+	}
+	query = query.OrderBy(compGroupMembersHistSeqCol).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: getMemberHist(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	hist := []*sm.MemberHistEntry{}
+	for rows.Next() {
+		var ts time.Time
+		e := new(sm.MemberHistEntry)
+		if err := rows.Scan(&e.ID, &e.Action, &e.Actor, &ts); err != nil {
+			d.LogAlways("Error: getMemberHist(): scan failed: %s", err)
+			return nil, err
+		}
+		e.Timestamp = ts.Format(time.RFC3339Nano)
+		hist = append(hist, e)
+	}
+	return hist, rows.Err()
+}
+
 //
 // Memberships
 //
@@ -4257,6 +5299,7 @@ func compLockFilterToCompFilter(clf sm.CompLockV2Filter) (cf ComponentFilter) {
 	cf.Partition = clf.Partition
 	cf.ReservationDisabled = clf.ReservationDisabled
 	cf.Locked = clf.Locked
+	cf.descendants = clf.IncludeDescendants
 	return cf
 }
 
@@ -4371,6 +5414,12 @@ func (d *hmsdbPg) InsertCompReservations(f sm.CompLockV2Filter) (sm.CompLockV2Re
 	if err != nil {
 		return sm.CompLockV2ReservationResult{}, err
 	}
+	if f.Reason != "" {
+		if err = t.SetCompLockAuditTx("", f.Reason); err != nil {
+			t.Rollback()
+			return sm.CompLockV2ReservationResult{}, err
+		}
+	}
 
 	result, err := insertCompReservationsHelper(t, f)
 	if err != nil {
@@ -4447,6 +5496,11 @@ func (d *hmsdbPg) DeleteCompReservationsForce(f sm.CompLockV2Filter) (sm.CompLoc
 	if err != nil {
 		return sm.CompLockV2UpdateResult{}, err
 	}
+	err = t.SetCompLockAuditTx(sm.CLAuditForceRelease, f.Reason)
+	if err != nil {
+		t.Rollback()
+		return sm.CompLockV2UpdateResult{}, err
+	}
 
 	cf := compLockFilterToCompFilter(f)
 	affectedComps, err := t.GetComponentsFilterTx(&cf, FLTR_DEFAULT)
@@ -4482,6 +5536,12 @@ func (d *hmsdbPg) DeleteCompReservations(f sm.CompLockV2ReservationFilter) (sm.C
 	if err != nil {
 		return sm.CompLockV2UpdateResult{}, err
 	}
+	if f.Reason != "" {
+		if err = t.SetCompLockAuditTx("", f.Reason); err != nil {
+			t.Rollback()
+			return sm.CompLockV2UpdateResult{}, err
+		}
+	}
 
 	result, err := deleteCompReservationsHelper(t, f, false)
 	if err != nil {
@@ -4511,6 +5571,22 @@ func (d *hmsdbPg) DeleteCompReservationsExpired() ([]string, error) {
 	return xnames, err
 }
 
+// Retrieve the reservations that expire within the given duration from now,
+// sorted by expiration time.
+func (d *hmsdbPg) GetCompReservationsExpiring(within time.Duration) ([]sm.CompLockV2Expiring, error) {
+	t, err := d.Begin()
+	if err != nil {
+		return []sm.CompLockV2Expiring{}, err
+	}
+	reservations, err := t.GetCompReservationsExpiringTx(within)
+	if err != nil {
+		t.Rollback()
+		return reservations, err
+	}
+	err = t.Commit()
+	return reservations, err
+}
+
 // Retrieve the status of reservations. The public key and xname is
 // required to address the reservation.
 func (d *hmsdbPg) GetCompReservations(dkeys []sm.CompLockV2Key) (sm.CompLockV2ReservationResult, error) {
@@ -4572,6 +5648,12 @@ func (d *hmsdbPg) UpdateCompReservations(f sm.CompLockV2ReservationFilter) (sm.C
 	if err != nil {
 		return result, err
 	}
+	if f.Reason != "" {
+		if err = t.SetCompLockAuditTx("", f.Reason); err != nil {
+			t.Rollback()
+			return result, err
+		}
+	}
 
 	// Update the reservations and retrieve any v1LockIDs associated with our reservations.
 	locks, err := t.UpdateCompReservationsTx(f.ReservationKeys, f.ReservationDuration, false)
@@ -4621,6 +5703,78 @@ func (d *hmsdbPg) UpdateCompReservations(f sm.CompLockV2ReservationFilter) (sm.C
 	return result, err
 }
 
+// Get the lock audit log, oldest change first, optionally filtering.  Like
+// the membership history, this is kept after components are deleted.
+func (d *hmsdbPg) GetCompLockAudit(f *CompLockAuditFilter) ([]*sm.CompLockV2AuditEntry, error) {
+	query := sq.Select(compLockAuditCompIdCol,
+		compLockAuditActionCol,
+		compLockAuditActorCol,
+		compLockAuditReasonCol,
+		compLockAuditExpireCol,
+		compLockAuditTimestampCol).
+		From(compLockAuditTable)
+	if f != nil {
+		if len(f.ID) > 0 {
+			ids := make([]string, 0, len(f.ID))
+			for _, id := range f.ID {
+				nid := xnametypes.NormalizeHMSCompID(id)
+				if !xnametypes.IsHMSCompIDValid(nid) {
+					return nil, ErrHMSDSArgBadID
+				}
+				ids = append(ids, nid)
+			}
+			query = query.Where(sq.Eq{compLockAuditCompIdCol: ids})
+		}
+		if len(f.Action) > 0 {
+			query = query.Where(sq.Eq{compLockAuditActionCol: f.Action})
+		}
+		if f.Actor != "" {
+			query = query.Where(sq.Eq{compLockAuditActorCol: f.Actor})
+		}
+		if f.StartTime != "" {
+			start, err := time.Parse(time.RFC3339, f.StartTime)
+			if err != nil {
+				return nil, ErrHMSDSArgBadTimeFormat
+			}
+			query = query.Where(sq.GtOrEq{compLockAuditTimestampCol: start})
+		}
+		if f.EndTime != "" {
+			end, err := time.Parse(time.RFC3339, f.EndTime)
+			if err != nil {
+				return nil, ErrHMSDSArgBadTimeFormat
+			}
+			query = query.Where(sq.LtOrEq{compLockAuditTimestampCol: end})
+		}
+	}
+	query = query.OrderBy(compLockAuditSeqCol).
+		PlaceholderFormat(sq.Dollar)
+
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetCompLockAudit(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*sm.CompLockV2AuditEntry{}
+	for rows.Next() {
+		var ts time.Time
+		var exp sql.NullTime
+		e := new(sm.CompLockV2AuditEntry)
+		err := rows.Scan(&e.ID, &e.Action, &e.Actor, &e.Reason, &exp, &ts)
+		if err != nil {
+			d.LogAlways("Error: GetCompLockAudit(): scan failed: %s", err)
+			return nil, err
+		}
+		if exp.Valid {
+			e.ExpirationTime = exp.Time.Format(time.RFC3339)
+		}
+		e.Timestamp = ts.Format(time.RFC3339Nano)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
 // Retrieve component lock information.
 func (d *hmsdbPg) GetCompLocksV2(f sm.CompLockV2Filter) ([]sm.CompLockV2, error) {
 	var result []sm.CompLockV2
@@ -4727,6 +5881,18 @@ func (d *hmsdbPg) UpdateCompLocksV2(f sm.CompLockV2Filter, action string) (sm.Co
 		return result, sm.ErrCompLockV2NotFound
 	}
 
+	// Reservations released by Disable are recorded as forcibly released.
+	auditAction := ""
+	if action == CLUpdateActionDisable {
+		auditAction = sm.CLAuditForceRelease
+	}
+	if auditAction != "" || f.Reason != "" {
+		if err = t.SetCompLockAuditTx(auditAction, f.Reason); err != nil {
+			t.Rollback()
+			return result, err
+		}
+	}
+
 	switch action {
 	case CLUpdateActionDisable:
 		resFilter := sm.CompLockV2ReservationFilter{
@@ -5049,3 +6215,599 @@ func (d *hmsdbPg) DeleteJob(jobId string) (bool, error) {
 	}
 	return false, nil
 }
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Leases
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Take the named lease for holder for ttl, or renew it if holder already
+// has it.  Returns false if someone else holds it and it hasn't expired.
+// Expiration times are by the database's clock.
+func (d *hmsdbPg) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	query := sq.Insert(leaseTable).
+		Columns(leaseNameCol, leaseHolderCol, leaseExpiresCol).
+		Values(name, holder,
+			sq.Expr("now() + ? * interval '1 second'", ttl.Seconds())).
+		Suffix("ON CONFLICT(" + leaseNameCol + ") DO UPDATE SET " +
+			leaseHolderCol + " = EXCLUDED." + leaseHolderCol + ", " +
+			leaseExpiresCol + " = EXCLUDED." + leaseExpiresCol +
+			" WHERE " + leaseTable + "." + leaseHolderCol + " = EXCLUDED." + leaseHolderCol +
+			" OR " + leaseTable + "." + leaseExpiresCol + " < now()" +
+			" RETURNING " + leaseHolderCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: AcquireLease(): query failed: %s", err)
+		return false, err
+	}
+	defer rows.Close()
+	// A row is only returned if the lease was taken or renewed.
+	acquired := rows.Next()
+	return acquired, rows.Err()
+}
+
+// Give up the named lease, if held by holder.  If no error, bool indicates
+// whether it was held.
+func (d *hmsdbPg) ReleaseLease(name, holder string) (bool, error) {
+	query := sq.Delete(leaseTable).
+		Where(sq.Eq{leaseNameCol: name, leaseHolderCol: holder}).
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		return false, err
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return num > 0, nil
+}
+
+// Escapes LIKE wildcards, so a prefix matches as is.
+var leaseLikeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Get the leases whose names start with prefix and that haven't expired,
+// by name.
+func (d *hmsdbPg) GetLeases(prefix string) ([]*Lease, error) {
+	query := sq.Select(leaseNameCol, leaseHolderCol, leaseExpiresCol).
+		From(leaseTable).
+		Where(sq.Like{leaseNameCol: leaseLikeEscaper.Replace(prefix) + "%"}).
+		Where(leaseExpiresCol + " > now()").
+		OrderBy(leaseNameCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetLeases(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	leases := []*Lease{}
+	for rows.Next() {
+		l := new(Lease)
+		if err := rows.Scan(&l.Name, &l.Holder, &l.Expires); err != nil {
+			d.LogAlways("Error: GetLeases(): scan failed: %s", err)
+			return nil, err
+		}
+		leases = append(leases, l)
+	}
+	return leases, rows.Err()
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Rediscovery Schedules
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get all rediscovery schedules, by name.
+func (d *hmsdbPg) GetDiscoverySchedules() ([]*sm.DiscoverySchedule, error) {
+	query := sq.Select(discSchedScheduleCol).
+		From(discSchedTable).
+		OrderBy(discSchedNameCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetDiscoverySchedules(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	scheds := []*sm.DiscoverySchedule{}
+	for rows.Next() {
+		var schedJSON []byte
+		if err := rows.Scan(&schedJSON); err != nil {
+			d.LogAlways("Error: GetDiscoverySchedules(): scan failed: %s", err)
+			return nil, err
+		}
+		ds := new(sm.DiscoverySchedule)
+		if err := json.Unmarshal(schedJSON, ds); err != nil {
+			d.LogAlways("Error: GetDiscoverySchedules(): decode failed: %s", err)
+			return nil, err
+		}
+		scheds = append(scheds, ds)
+	}
+	return scheds, rows.Err()
+}
+
+// Insert a rediscovery schedule, replacing any with the same name.
+func (d *hmsdbPg) UpsertDiscoverySchedule(ds *sm.DiscoverySchedule) error {
+	schedJSON, err := json.Marshal(ds)
+	if err != nil {
+		return err
+	}
+	query := sq.Insert(discSchedTable).
+		Columns(discSchedNameCol, discSchedScheduleCol).
+		Values(ds.Name, schedJSON).
+		Suffix("ON CONFLICT(" + discSchedNameCol + ") DO UPDATE SET " +
+			discSchedScheduleCol + " = EXCLUDED." + discSchedScheduleCol).
+		PlaceholderFormat(sq.Dollar)
+	_, err = query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: UpsertDiscoverySchedule(): query failed: %s", err)
+	}
+	return err
+}
+
+// Delete the named rediscovery schedule.  If no error, bool indicates
+// whether it was present to remove.
+func (d *hmsdbPg) DeleteDiscoverySchedule(name string) (bool, error) {
+	query := sq.Delete(discSchedTable).
+		Where(sq.Eq{discSchedNameCol: name}).
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		return false, err
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return num > 0, nil
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// HMSDB Interface - Tombstones
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Tables of the kinds of rows that get tombstones when deleted.
+var tombstoneTables = map[string]string{
+	sm.TombstoneComponent:       compTable,
+	sm.TombstoneRedfishEndpoint: rfEPsTable,
+}
+
+// Get the tombstones of deleted Components or RedfishEndpoints, as given
+// by kind (sm.TombstoneComponent, sm.TombstoneRedfishEndpoint), newest
+// first.  If id is not empty, only those for id are returned.
+func (d *hmsdbPg) GetTombstones(kind, id string) ([]*sm.Tombstone, error) {
+	if _, ok := tombstoneTables[kind]; !ok {
+		return nil, ErrHMSDSArgBadArg
+	}
+	query := sq.Select(tombstonesKindCol, tombstonesIdCol, tombstonesActorCol,
+		tombstonesDeletedCol,
+		tombstonesDataCol+" - '"+rfEPsPasswordCol+"'").
+		From(tombstonesTable).
+		Where(sq.Eq{tombstonesKindCol: kind})
+	if id != "" {
+		query = query.Where(sq.Eq{tombstonesIdCol: id})
+	}
+	query = query.OrderBy(tombstonesSeqCol + " DESC").
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetTombstones(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tss := []*sm.Tombstone{}
+	for rows.Next() {
+		ts := new(sm.Tombstone)
+		var deleted time.Time
+		var data []byte
+		err := rows.Scan(&ts.Kind, &ts.ID, &ts.DeletedBy, &deleted, &data)
+		if err != nil {
+			d.LogAlways("Error: GetTombstones(): scan failed: %s", err)
+			return nil, err
+		}
+		ts.Deleted = deleted.Format(time.RFC3339)
+		ts.Data = data
+		tss = append(tss, ts)
+	}
+	return tss, rows.Err()
+}
+
+// Restore the most recently deleted Component or RedfishEndpoint with the
+// given id from its tombstone, which is removed.  If no error, bool
+// indicates whether there was a tombstone to restore.  Returns
+// ErrHMSDSDuplicateKey if id exists again.
+func (d *hmsdbPg) RestoreTombstone(kind, id string) (bool, error) {
+	table, ok := tombstoneTables[kind]
+	if !ok {
+		return false, ErrHMSDSArgBadArg
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return false, err
+	}
+	pt := t.(*hmsdbPgTx)
+
+	var seq int64
+	query := sq.Select(tombstonesSeqCol).
+		From(tombstonesTable).
+		Where(sq.Eq{tombstonesKindCol: kind, tombstonesIdCol: id}).
+		OrderBy(tombstonesSeqCol + " DESC").
+		Limit(1).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+	err = query.RunWith(pt.sc).QueryRowContext(pt.ctx).Scan(&seq)
+	if err == sql.ErrNoRows {
+		t.Rollback()
+		return false, nil
+	} else if err != nil {
+		t.Rollback()
+		return false, err
+	}
+	// The row is put back as it was, with the columns it had then.
+	restore := sq.Insert(table).
+		Select(sq.Select("(jsonb_populate_record(NULL::" + table + ", " +
+			tombstonesDataCol + ")).*").
+			From(tombstonesTable).
+			Where(sq.Eq{tombstonesSeqCol: seq})).
+		PlaceholderFormat(sq.Dollar)
+	_, err = restore.RunWith(pt.sc).ExecContext(pt.ctx)
+	if err != nil {
+		t.Rollback()
+		d.LogAlways("Error: RestoreTombstone(%s, %s): insert failed: %s", kind, id, err)
+		return false, ParsePgDBError(err)
+	}
+	remove := sq.Delete(tombstonesTable).
+		Where(sq.Eq{tombstonesSeqCol: seq}).
+		PlaceholderFormat(sq.Dollar)
+	_, err = remove.RunWith(pt.sc).ExecContext(pt.ctx)
+	if err != nil {
+		t.Rollback()
+		return false, err
+	}
+	if err := t.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete the tombstones of anything deleted before the given time,
+// returning how many were deleted.
+func (d *hmsdbPg) PurgeTombstones(before time.Time) (int64, error) {
+	query := sq.Delete(tombstonesTable).
+		Where(sq.Lt{tombstonesDeletedCol: before}).
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: PurgeTombstones(): query failed: %s", err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// HMSDB Interface - Heartbeats
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Select heartbeats joined with their components' State and Flag, oldest
+// first.  The age is computed by the database, so it doesn't depend on
+// the clocks of the HSM instances agreeing.
+func compHeartbeatsQuery(f *CompHeartbeatFilter) sq.SelectBuilder {
+	query := sq.Select("h."+compHeartbeatsIdCol, "c."+compStateCol,
+		"c."+compFlagCol, "h."+compHeartbeatsLastSeenCol,
+		"EXTRACT(EPOCH FROM NOW() - h."+compHeartbeatsLastSeenCol+")").
+		From(compHeartbeatsTable + " h").
+		Join(compTable + " c ON c." + compIdCol + " = h." + compHeartbeatsIdCol)
+	if f != nil {
+		if len(f.ID) > 0 {
+			query = query.Where(sq.Expr("h."+compHeartbeatsIdCol+" = ANY(?)",
+				pq.Array(f.ID)))
+		}
+		if len(f.State) > 0 {
+			query = query.Where(sq.Eq{"c." + compStateCol: f.State})
+		}
+		if f.Age > 0 {
+			query = query.Where(sq.Expr("h."+compHeartbeatsLastSeenCol+
+				" <= NOW() - make_interval(secs => ?)", f.Age.Seconds()))
+		}
+	}
+	return query.OrderBy("h." + compHeartbeatsLastSeenCol).
+		PlaceholderFormat(sq.Dollar)
+}
+
+// Scan the rows of compHeartbeatsQuery().
+func scanCompHeartbeats(rows *sql.Rows) ([]*sm.CompHeartbeat, error) {
+	hbs := []*sm.CompHeartbeat{}
+	for rows.Next() {
+		hb := new(sm.CompHeartbeat)
+		var lastSeen time.Time
+		err := rows.Scan(&hb.ID, &hb.State, &hb.Flag, &lastSeen, &hb.Age)
+		if err != nil {
+			return nil, err
+		}
+		hb.LastSeen = lastSeen.Format(time.RFC3339Nano)
+		hbs = append(hbs, hb)
+	}
+	return hbs, rows.Err()
+}
+
+// Record a heartbeat now for each of the given components.  IDs that are
+// not components are ignored.  Returns the heartbeats recorded, with the
+// components' current State and Flag.
+func (d *hmsdbPg) UpdateCompHeartbeats(ids []string) ([]*sm.CompHeartbeat, error) {
+	normIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		normIDs = append(normIDs, xnametypes.NormalizeHMSCompID(id))
+	}
+	t, err := d.Begin()
+	if err != nil {
+		return nil, err
+	}
+	pt := t.(*hmsdbPgTx)
+
+	// The IDs are passed as one array, so there's no limit on how many
+	// there can be.
+	upsert := sq.Insert(compHeartbeatsTable).
+		Columns(compHeartbeatsIdCol, compHeartbeatsLastSeenCol).
+		Select(sq.Select(compIdCol, "NOW()").
+			From(compTable).
+			Where(sq.Expr(compIdCol+" = ANY(?)", pq.Array(normIDs)))).
+		Suffix("ON CONFLICT (" + compHeartbeatsIdCol + ") DO UPDATE SET " +
+			compHeartbeatsLastSeenCol + " = EXCLUDED." +
+			compHeartbeatsLastSeenCol).
+		PlaceholderFormat(sq.Dollar)
+	_, err = upsert.RunWith(pt.sc).ExecContext(pt.ctx)
+	if err != nil {
+		t.Rollback()
+		d.LogAlways("Error: UpdateCompHeartbeats(): upsert failed: %s", err)
+		return nil, err
+	}
+	query := compHeartbeatsQuery(&CompHeartbeatFilter{ID: normIDs})
+	rows, err := query.RunWith(pt.sc).QueryContext(pt.ctx)
+	if err != nil {
+		t.Rollback()
+		d.LogAlways("Error: UpdateCompHeartbeats(): query failed: %s", err)
+		return nil, err
+	}
+	hbs, err := scanCompHeartbeats(rows)
+	rows.Close()
+	if err != nil {
+		t.Rollback()
+		d.LogAlways("Error: UpdateCompHeartbeats(): scan failed: %s", err)
+		return nil, err
+	}
+	if err := t.Commit(); err != nil {
+		return nil, err
+	}
+	return hbs, nil
+}
+
+// Get the last heartbeats of the components that have reported any, oldest
+// first, optionally filtered.
+func (d *hmsdbPg) GetCompHeartbeats(f *CompHeartbeatFilter) ([]*sm.CompHeartbeat, error) {
+	if f != nil && len(f.State) > 0 {
+		states := make([]string, 0, len(f.State))
+		for _, state := range f.State {
+			normState := base.VerifyNormalizeState(state)
+			if normState == "" {
+				return nil, ErrHMSDSArgBadState
+			}
+			states = append(states, normState)
+		}
+		f = &CompHeartbeatFilter{ID: f.ID, State: states, Age: f.Age}
+	}
+	rows, err := compHeartbeatsQuery(f).RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetCompHeartbeats(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	hbs, err := scanCompHeartbeats(rows)
+	if err != nil {
+		d.LogAlways("Error: GetCompHeartbeats(): scan failed: %s", err)
+	}
+	return hbs, err
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// HMSDB Interface - NID Assignment Policies
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Get all NID policies, in the order they are tried: by Priority, then
+// Name.
+func (d *hmsdbPg) GetNIDPolicies() ([]*sm.NIDPolicy, error) {
+	query := sq.Select(nidPoliciesPolicyCol).
+		From(nidPoliciesTable).
+		OrderBy(nidPoliciesNameCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetNIDPolicies(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []*sm.NIDPolicy{}
+	for rows.Next() {
+		var policyJSON []byte
+		if err := rows.Scan(&policyJSON); err != nil {
+			d.LogAlways("Error: GetNIDPolicies(): scan failed: %s", err)
+			return nil, err
+		}
+		p := new(sm.NIDPolicy)
+		if err := json.Unmarshal(policyJSON, p); err != nil {
+			d.LogAlways("Error: GetNIDPolicies(): decode failed: %s", err)
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Already sorted by name, so a stable sort keeps that for equal
+	// priorities.
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Priority < policies[j].Priority
+	})
+	return policies, nil
+}
+
+// Insert a NID policy, replacing any with the same name.
+func (d *hmsdbPg) UpsertNIDPolicy(p *sm.NIDPolicy) error {
+	policyJSON, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	query := sq.Insert(nidPoliciesTable).
+		Columns(nidPoliciesNameCol, nidPoliciesPolicyCol).
+		Values(p.Name, policyJSON).
+		Suffix("ON CONFLICT(" + nidPoliciesNameCol + ") DO UPDATE SET " +
+			nidPoliciesPolicyCol + " = EXCLUDED." + nidPoliciesPolicyCol).
+		PlaceholderFormat(sq.Dollar)
+	_, err = query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: UpsertNIDPolicy(): query failed: %s", err)
+	}
+	return err
+}
+
+// Delete the named NID policy.  If no error, bool indicates whether it was
+// present to remove.
+func (d *hmsdbPg) DeleteNIDPolicy(name string) (bool, error) {
+	query := sq.Delete(nidPoliciesTable).
+		Where(sq.Eq{nidPoliciesNameCol: name}).
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		return false, err
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return num > 0, nil
+}
+
+// Get the NIDs assigned by policies, by xname.  If ids is empty, all of
+// them are returned.
+func (d *hmsdbPg) GetNIDAssignments(ids []string) ([]*sm.NIDAssignment, error) {
+	query := sq.Select(nidAssignmentsIdCol, nidAssignmentsNIDCol,
+		nidAssignmentsPolicyCol, nidAssignmentsAssignedCol).
+		From(nidAssignmentsTable)
+	if len(ids) > 0 {
+		normIDs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			normIDs = append(normIDs, xnametypes.NormalizeHMSCompID(id))
+		}
+		query = query.Where(sq.Expr(nidAssignmentsIdCol+" = ANY(?)",
+			pq.Array(normIDs)))
+	}
+	query = query.OrderBy(nidAssignmentsIdCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: GetNIDAssignments(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	as := []*sm.NIDAssignment{}
+	for rows.Next() {
+		a := new(sm.NIDAssignment)
+		var assigned time.Time
+		err := rows.Scan(&a.ID, &a.NID, &a.Policy, &assigned)
+		if err != nil {
+			d.LogAlways("Error: GetNIDAssignments(): scan failed: %s", err)
+			return nil, err
+		}
+		a.Assigned = assigned.Format(time.RFC3339)
+		as = append(as, a)
+	}
+	return as, rows.Err()
+}
+
+// Record a NID assigned by a policy.  If no error, bool indicates whether
+// it was recorded, i.e. neither the xname nor the NID had already been
+// assigned.
+func (d *hmsdbPg) InsertNIDAssignment(a *sm.NIDAssignment) (bool, error) {
+	query := sq.Insert(nidAssignmentsTable).
+		Columns(nidAssignmentsIdCol, nidAssignmentsNIDCol,
+			nidAssignmentsPolicyCol).
+		Values(xnametypes.NormalizeHMSCompID(a.ID), a.NID, a.Policy).
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		d.LogAlways("Error: InsertNIDAssignment(): query failed: %s", err)
+		return false, err
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return num > 0, nil
+}
+
+// Delete the NID assigned to xname id, so its NID can be assigned again.
+// If no error, bool indicates whether it was present to remove.
+func (d *hmsdbPg) DeleteNIDAssignment(id string) (bool, error) {
+	query := sq.Delete(nidAssignmentsTable).
+		Where(sq.Eq{nidAssignmentsIdCol: xnametypes.NormalizeHMSCompID(id)}).
+		PlaceholderFormat(sq.Dollar)
+	res, err := query.RunWith(d.sc).ExecContext(d.ctx)
+	if err != nil {
+		return false, err
+	}
+	num, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return num > 0, nil
+}
+
+// Get the NIDs from start to end used by components, NodeMaps or NID
+// assignments, with the xnames using each.
+func (d *hmsdbPg) GetNIDsInUse(start, end int64) (map[int64][]string, error) {
+	inUse := make(map[int64][]string)
+	for _, table := range []string{compTable, nodeMapTableDB, nidAssignmentsTable} {
+		query := sq.Select("nid", "id").
+			From(table).
+			Where(sq.GtOrEq{"nid": start}).
+			Where(sq.LtOrEq{"nid": end}).
+			PlaceholderFormat(sq.Dollar)
+		rows, err := query.RunWith(d.sc).QueryContext(d.ctx)
+		if err != nil {
+			d.LogAlways("Error: GetNIDsInUse(): query of %s failed: %s", table, err)
+			return nil, err
+		}
+		for rows.Next() {
+			var nid int64
+			var id string
+			if err := rows.Scan(&nid, &id); err != nil {
+				rows.Close()
+				d.LogAlways("Error: GetNIDsInUse(): scan failed: %s", err)
+				return nil, err
+			}
+			inUse[nid] = append(inUse[nid], id)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inUse, nil
+}
@@ -25,6 +25,7 @@ package hmsds
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -35,6 +36,7 @@ import (
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/internal/envcrypt"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
 	stest "github.com/OpenCHAMI/smd/v2/pkg/sharedtest"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
@@ -351,6 +353,199 @@ func TestPgGetComponentsFilter(t *testing.T) {
 	}
 }
 
+func TestPgGetComponentsAsOf(t *testing.T) {
+	asOf := "2026-03-01T10:00:00Z"
+	ts, _ := time.Parse(time.RFC3339, asOf)
+	expectedPrepare := regexp.QuoteMeta("SELECT c.id AS id, c.type AS type, c.state AS state, c.flag AS flag" +
+		" FROM (SELECT DISTINCT ON (id) id, type, state, flag, enabled, admin, role, subrole, nid," +
+		" subtype, nettype, arch, class, reservation_disabled, locked, deleted FROM comp_state_hist" +
+		" WHERE timestamp <= $1 ORDER BY id, seq DESC) AS c" +
+		" WHERE c.deleted = $2 AND c.type IN ($3) AND c.state IN ($4)")
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"id", "type", "state", "flag"}).
+		AddRow("x0c0s26b0n0", "Node", "Ready", "OK")
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().
+		WithArgs(ts, false, "Node", "Ready").WillReturnRows(rows)
+	mockPG.ExpectCommit()
+
+	f := &ComponentFilter{Type: []string{"node"}, State: []string{"ready"}}
+	comps, err := dPG.GetComponentsAsOf(f, FLTR_STATEONLY, asOf)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*base.Component{
+		{ID: "x0c0s26b0n0", Type: "Node", State: "Ready", Flag: "OK"},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !stest.CompareComps(expected, comps) {
+		t.Errorf("Expected compArray '%v'; Recieved compArray '%v'", expected, comps)
+	}
+
+	// Bad times and membership filters are rejected before any query.
+	for i, test := range []struct {
+		f    *ComponentFilter
+		asOf string
+		err  error
+	}{
+		{nil, "yesterday", ErrHMSDSArgBadTimeFormat},
+		{&ComponentFilter{Group: []string{"grp1"}}, asOf, ErrHMSDSArgBadArg},
+		{&ComponentFilter{Partition: []string{"p1"}}, asOf, ErrHMSDSArgBadArg},
+	} {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		mockPG.ExpectRollback()
+		_, err := dPG.GetComponentsAsOf(test.f, FLTR_DEFAULT, test.asOf)
+		if err != test.err {
+			t.Errorf("Test %v Failed: Expected error '%v', got '%v'", i, test.err, err)
+		}
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+	}
+}
+
+func TestPgGetCompStateHist(t *testing.T) {
+	start := "2026-03-01T00:00:00Z"
+	end := "2026-03-08T00:00:00Z"
+	startTs, _ := time.Parse(time.RFC3339, start)
+	endTs, _ := time.Parse(time.RFC3339, end)
+	ts1, _ := time.Parse(time.RFC3339, "2026-02-27T00:00:00Z")
+	ts2, _ := time.Parse(time.RFC3339, "2026-03-02T00:00:00Z")
+	ts3, _ := time.Parse(time.RFC3339, "2026-03-03T00:00:00Z")
+	ts4, _ := time.Parse(time.RFC3339, "2026-03-04T00:00:00Z")
+	expectedPrepare := regexp.QuoteMeta("SELECT state, flag, deleted, actor, timestamp" +
+		" FROM comp_state_hist WHERE id = $1" +
+		" AND seq >= COALESCE((SELECT MAX(seq) FROM comp_state_hist" +
+		" WHERE id = $2 AND timestamp <= $3), 0)" +
+		" AND timestamp <= $4 ORDER BY seq")
+
+	ResetMockDB()
+	// The third row only changed something other than State or Flag.
+	rows := sqlmock.NewRows([]string{"state", "flag", "deleted", "actor", "timestamp"}).
+		AddRow("On", "OK", false, "smd:discovery", ts1).
+		AddRow("Ready", "OK", false, "smd:redfish-event", ts2).
+		AddRow("Ready", "OK", false, "admin", ts3).
+		AddRow("Ready", "OK", true, "admin", ts4)
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().
+		WithArgs("x0c0s26b0n0", "x0c0s26b0n0", startTs, endTs).
+		WillReturnRows(rows)
+
+	f := &CompStateHistFilter{StartTime: start, EndTime: end}
+	hist, err := dPG.GetCompStateHist("x0c0s26b0n00", f)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*sm.CompStateHistEntry{
+		{State: "On", Flag: "OK", Actor: "smd:discovery",
+			Timestamp: ts1.Format(time.RFC3339Nano)},
+		{State: "Ready", Flag: "OK", Actor: "smd:redfish-event",
+			Timestamp: ts2.Format(time.RFC3339Nano)},
+		{State: "Ready", Flag: "OK", Deleted: true, Actor: "admin",
+			Timestamp: ts4.Format(time.RFC3339Nano)},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, hist) {
+		t.Errorf("Expected %v, got %v", expected, hist)
+	}
+
+	// Bad IDs and times are rejected before any query.
+	for i, test := range []struct {
+		id  string
+		f   *CompStateHistFilter
+		err error
+	}{
+		{"foo", nil, ErrHMSDSArgBadID},
+		{"x0c0s26b0n0", &CompStateHistFilter{StartTime: "yesterday"}, ErrHMSDSArgBadTimeFormat},
+		{"x0c0s26b0n0", &CompStateHistFilter{EndTime: "today"}, ErrHMSDSArgBadTimeFormat},
+	} {
+		ResetMockDB()
+		_, err := dPG.GetCompStateHist(test.id, test.f)
+		if err != test.err {
+			t.Errorf("Test %v Failed: Expected error '%v', got '%v'", i, test.err, err)
+		}
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+	}
+}
+
+func TestPgGetComponentCounts(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta("SELECT type, state, COUNT(*)" +
+		" FROM components GROUP BY type, state ORDER BY type, state")
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"type", "state", "count"}).
+		AddRow("Node", "Off", 2).
+		AddRow("Node", "Ready", 1024)
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().WillReturnRows(rows)
+	mockPG.ExpectCommit()
+
+	counts, err := dPG.GetComponentCounts()
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*ComponentCount{
+		{Type: "Node", State: "Off", Count: 2},
+		{Type: "Node", State: "Ready", Count: 1024},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, counts) {
+		t.Errorf("Expected counts '%v'; Recieved counts '%v'", expected, counts)
+	}
+
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().WillReturnError(sql.ErrConnDone)
+	mockPG.ExpectRollback()
+	if _, err := dPG.GetComponentCounts(); err != sql.ErrConnDone {
+		t.Errorf("Expected error '%v', got '%v'", sql.ErrConnDone, err)
+	}
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+}
+
+func TestPgGetCollectionVersions(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`SELECT components, "groups", hwinv` +
+		` FROM collection_versions WHERE id = $1`)
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"components", "groups", "hwinv"}).
+		AddRow(12, 3, 40)
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().WithArgs(0).
+		WillReturnRows(rows)
+	mockPG.ExpectCommit()
+
+	vers, err := dPG.GetCollectionVersions()
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := &CollectionVersions{Components: 12, Groups: 3, HWInv: 40}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, vers) {
+		t.Errorf("Expected versions '%v'; Recieved versions '%v'", expected, vers)
+	}
+
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().WillReturnError(sql.ErrConnDone)
+	mockPG.ExpectRollback()
+	if _, err := dPG.GetCollectionVersions(); err != sql.ErrConnDone {
+		t.Errorf("Expected error '%v', got '%v'", sql.ErrConnDone, err)
+	}
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+}
+
 func TestPgGetComponentsQuery(t *testing.T) {
 	enabledFlg := true
 	tests := []struct {
@@ -1636,6 +1831,243 @@ func TestPgBulkUpdateCompNID(t *testing.T) {
 	}
 }
 
+func TestPgBulkPatchComponents(t *testing.T) {
+	dbColumns := []string{"id", "type", "state", "flag", "enabled", "admin", "role", "subrole", "nid", "subtype", "nettype", "arch", "class", "reservation_disabled", "locked"}
+	rows := [][]driver.Value{
+		[]driver.Value{"x0c0s26b0n0", "Node", "Off", "OK", true, "AdminStatus", "Compute", "", 832, "", "Sling", "X86", "", false, false},
+		[]driver.Value{"x0c0s27b0n0", "Node", "Off", "Warning", false, "AdminStatus", "Compute", "", 864, "", "Sling", "X86", "", false, false},
+	}
+	flag := "warning"
+	enabled := false
+	swStatus := "Maintenance"
+
+	tests := []struct {
+		f              *ComponentFilter
+		p              *CompBulkPatch
+		idQuery        string
+		idArgs         []driver.Value
+		idRows         [][]driver.Value
+		lockQuery      string
+		lockArgs       []driver.Value
+		lockRows       [][]driver.Value
+		updates        []string
+		updateArgs     [][]driver.Value
+		dbUpdateError  error
+		expectedErr    error
+		expectedChange map[string]map[string]bool
+	}{{
+		f:         &ComponentFilter{Role: []string{"compute"}, State: []string{"off"}},
+		p:         &CompBulkPatch{Flag: &flag, Enabled: &enabled},
+		lockQuery: tGetCompBaseQuery + " WHERE c.state IN ($1) AND c.role IN ($2) FOR UPDATE",
+		lockArgs:  []driver.Value{"Off", "Compute"},
+		lockRows:  rows,
+		updates: []string{
+			ToPGQueryArgs(updateCompFlagOnlyPrefix) + "WHERE (id = $2);",
+			ToPGQueryArgs(updateCompEnabledPrefix) + "WHERE (id = $2);",
+		},
+		updateArgs: [][]driver.Value{
+			{"Warning", "x0c0s26b0n0"},
+			{false, "x0c0s26b0n0"},
+		},
+		expectedChange: map[string]map[string]bool{
+			"x0c0s26b0n0": {"flag": true, "enabled": true},
+		},
+	}, {
+		f:         &ComponentFilter{Group: []string{"grp1"}},
+		p:         &CompBulkPatch{SwStatus: &swStatus},
+		idQuery:   "SELECT c.id AS id FROM components c" + tGetCompJoinGroupsQuery + " WHERE (cg.name IN ($1) AND cg.namespace = $2) GROUP BY c.id",
+		idArgs:    []driver.Value{"grp1", groupNamespace},
+		idRows:    [][]driver.Value{{"x0c0s26b0n0"}, {"x0c0s27b0n0"}},
+		lockQuery: tGetCompBaseQuery + " WHERE c.id IN ($1,$2) FOR UPDATE",
+		lockArgs:  []driver.Value{"x0c0s26b0n0", "x0c0s27b0n0"},
+		lockRows:  rows,
+		updates: []string{
+			ToPGQueryArgs(updateCompSwStatusPrefix) + "WHERE (id = $2 OR id = $3);",
+		},
+		updateArgs: [][]driver.Value{
+			{"Maintenance", "x0c0s26b0n0", "x0c0s27b0n0"},
+		},
+		expectedChange: map[string]map[string]bool{
+			"x0c0s26b0n0": {"swStatus": true},
+			"x0c0s27b0n0": {"swStatus": true},
+		},
+	}, {
+		f:              &ComponentFilter{Group: []string{"grp1"}},
+		p:              &CompBulkPatch{SwStatus: &swStatus},
+		idQuery:        "SELECT c.id AS id FROM components c" + tGetCompJoinGroupsQuery + " WHERE (cg.name IN ($1) AND cg.namespace = $2) GROUP BY c.id",
+		idArgs:         []driver.Value{"grp1", groupNamespace},
+		idRows:         [][]driver.Value{},
+		expectedChange: map[string]map[string]bool{},
+	}, {
+		f:         &ComponentFilter{Role: []string{"compute"}},
+		p:         &CompBulkPatch{Enabled: &enabled},
+		lockQuery: tGetCompBaseQuery + " WHERE c.role IN ($1) FOR UPDATE",
+		lockArgs:  []driver.Value{"Compute"},
+		lockRows:  rows,
+		updates: []string{
+			ToPGQueryArgs(updateCompEnabledPrefix) + "WHERE (id = $2);",
+		},
+		updateArgs: [][]driver.Value{
+			{false, "x0c0s26b0n0"},
+		},
+		dbUpdateError: sql.ErrNoRows,
+		expectedErr:   sql.ErrNoRows,
+	}, {
+		f:           &ComponentFilter{Role: []string{"compute"}},
+		p:           &CompBulkPatch{},
+		expectedErr: ErrHMSDSArgMissing,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		if test.expectedErr != ErrHMSDSArgMissing {
+			mockPG.ExpectBegin()
+		}
+		if test.idQuery != "" {
+			idRows := sqlmock.NewRows([]string{"id"})
+			for _, row := range test.idRows {
+				idRows.AddRow(row...)
+			}
+			mockPG.ExpectPrepare(regexp.QuoteMeta(test.idQuery)).ExpectQuery().
+				WithArgs(test.idArgs...).WillReturnRows(idRows)
+		}
+		if test.lockQuery != "" {
+			lockRows := sqlmock.NewRows(dbColumns)
+			for _, row := range test.lockRows {
+				lockRows.AddRow(row...)
+			}
+			mockPG.ExpectPrepare(regexp.QuoteMeta(test.lockQuery)).ExpectQuery().
+				WithArgs(test.lockArgs...).WillReturnRows(lockRows)
+		}
+		for j, update := range test.updates {
+			if test.dbUpdateError != nil {
+				mockPG.ExpectPrepare(regexp.QuoteMeta(update)).ExpectExec().
+					WillReturnError(test.dbUpdateError)
+				break
+			}
+			mockPG.ExpectPrepare(regexp.QuoteMeta(update)).ExpectExec().
+				WithArgs(test.updateArgs[j]...).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		if test.expectedErr == nil && test.lockQuery != "" {
+			mockPG.ExpectCommit()
+		} else if test.expectedErr != ErrHMSDSArgMissing {
+			mockPG.ExpectRollback()
+		}
+
+		changes, err := dPG.BulkPatchComponents(test.f, test.p)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Received '%v'",
+				i, test.expectedErr, err)
+		} else if err == nil && !reflect.DeepEqual(changes, test.expectedChange) {
+			t.Errorf("Test %v Failed: Expected changes '%v'; Received '%v'",
+				i, test.expectedChange, changes)
+		}
+	}
+}
+
+func TestPgUpdateComponentIfUnchanged(t *testing.T) {
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	update, _, _ := sqq.Update(compTable).
+		Set(compStateCol, "Ready").
+		Set(compFlagCol, "OK").
+		Set(compEnabledCol, true).
+		Set(compSwStatusCol, "AdminStatus").
+		Set(compRoleCol, "Application").
+		Set(compSubRoleCol, "UAN").
+		Set(compNIDCol, 832).
+		Set(compSubTypeCol, "").
+		Set(compNetTypeCol, "Sling").
+		Set(compArchCol, "X86").
+		Set(compClassCol, "").
+		Where(sq.Eq{compIdCol: "x0c0s0b0n0"}).ToSql()
+	dbColumns := []string{"id", "type", "state", "flag", "enabled", "admin", "role", "subrole", "nid", "subtype", "nettype", "arch", "class", "reservation_disabled", "locked"}
+	dbRow := []driver.Value{"x0c0s0b0n0", "Node", "Ready", "OK", true, "AdminStatus", "Compute", "", 832, "", "Sling", "X86", "", false, false}
+	enabled := true
+	prev := base.Component{
+		ID:       "x0c0s0b0n0",
+		Type:     "Node",
+		State:    "Ready",
+		Flag:     "OK",
+		Enabled:  &enabled,
+		SwStatus: "AdminStatus",
+		Role:     "Compute",
+		NID:      "832",
+		NetType:  "Sling",
+		Arch:     "X86",
+	}
+	stale := prev
+	stale.State = "On"
+	patched := prev
+	patched.Role = "Application"
+	patched.SubRole = "UAN"
+
+	tests := []struct {
+		prev           *base.Component
+		dbRows         [][]driver.Value
+		dbUpdateError  error
+		expectUpdate   bool
+		expectedResult bool
+	}{{
+		prev:           &prev,
+		dbRows:         [][]driver.Value{dbRow},
+		expectUpdate:   true,
+		expectedResult: true,
+	}, {
+		prev:           &stale,
+		dbRows:         [][]driver.Value{dbRow},
+		expectedResult: false,
+	}, {
+		prev:           &prev,
+		dbRows:         [][]driver.Value{},
+		expectedResult: false,
+	}, {
+		prev:          &prev,
+		dbRows:        [][]driver.Value{dbRow},
+		dbUpdateError: sql.ErrNoRows,
+		expectUpdate:  true,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		rows := sqlmock.NewRows(dbColumns)
+		for _, row := range test.dbRows {
+			rows.AddRow(row...)
+		}
+		mockPG.ExpectPrepare(regexp.QuoteMeta(tGetCompBaseQuery + " WHERE c.id IN ($1) FOR UPDATE")).
+			ExpectQuery().WithArgs("x0c0s0b0n0").WillReturnRows(rows)
+		if !test.expectUpdate {
+			mockPG.ExpectRollback()
+		} else if test.dbUpdateError != nil {
+			mockPG.ExpectPrepare(regexp.QuoteMeta(update)).ExpectExec().WillReturnError(test.dbUpdateError)
+			mockPG.ExpectRollback()
+		} else {
+			mockPG.ExpectPrepare(regexp.QuoteMeta(update)).ExpectExec().WithArgs(
+				"Ready", "OK", true, "AdminStatus", "Application", "UAN", 832,
+				"", "Sling", "X86", "", "x0c0s0b0n0").WillReturnResult(sqlmock.NewResult(0, 1))
+			mockPG.ExpectCommit()
+		}
+
+		ok, err := dPG.UpdateComponentIfUnchanged(test.prev, &patched)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if test.dbUpdateError != nil {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error.", i)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+		} else if ok != test.expectedResult {
+			t.Errorf("Test %v Failed: Expected result %v; Received %v",
+				i, test.expectedResult, ok)
+		}
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Hardware Inventory Tests
 ///////////////////////////////////////////////////////////////////////////////
@@ -1865,6 +2297,130 @@ func TestPgGetHWInvByLocFilter(t *testing.T) {
 	}
 }
 
+func TestXnamePatternQueries(t *testing.T) {
+	tests := []struct {
+		f            *ComponentFilter
+		expectedSql  string
+		expectedArgs []interface{}
+		expectedErr  error
+	}{{
+		f:            &ComponentFilter{ID: []string{"x1000c0s0b0n0"}},
+		expectedSql:  "WHERE c.id IN (?)",
+		expectedArgs: []interface{}{"x1000c0s0b0n0"},
+	}, {
+		f:           &ComponentFilter{ID: []string{"X1000C0S*B0N[0-1]", "x3000c0s1b0n0"}},
+		expectedSql: "WHERE (c.id IN (?) OR (c.id LIKE ? AND c.id ~ ?))",
+		expectedArgs: []interface{}{"x3000c0s1b0n0", "x1000c0s%",
+			"^x1000c0s[0-9]+b0n(0|1)$"},
+	}, {
+		f:            &ComponentFilter{ID: []string{"!x1000c*"}},
+		expectedSql:  "WHERE NOT ((c.id LIKE ? AND c.id ~ ?))",
+		expectedArgs: []interface{}{"x1000c%", "^x1000c[0-9]+$"},
+	}, {
+		f:           &ComponentFilter{ID: []string{"x1000c[0-1]"}, descendants: true},
+		expectedSql: "WHERE ((c.id LIKE ? AND c.id ~ ?))",
+		expectedArgs: []interface{}{"x1000c%",
+			"^x1000c(0|1)([[:alpha:]][[:alnum:]]*)?$"},
+	}, {
+		f:           &ComponentFilter{ID: []string{"x100*"}},
+		expectedErr: ErrHMSDSArgBadID,
+	}}
+	for i, test := range tests {
+		query, err := selectComponents(test.f, FLTR_ID_ONLY)
+		if test.expectedErr != nil {
+			if err != test.expectedErr {
+				t.Errorf("Test %d: expected %v, got %v", i, test.expectedErr, err)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+			continue
+		}
+		sql, args, _ := query.ToSql()
+		if !strings.HasSuffix(sql, test.expectedSql) {
+			t.Errorf("Test %d: unexpected query: %s", i, sql)
+		}
+		if !reflect.DeepEqual(args, test.expectedArgs) {
+			t.Errorf("Test %d: unexpected args: %v", i, args)
+		}
+	}
+
+	// Hardware inventory
+	query, err := getHWInvByLocQuery(HWInvLoc_IDs([]string{"x1000c0s0b0n0", "x1000c0s*b0n0"}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	sql, args, _ := query.ToSql()
+	if !strings.HasSuffix(sql, "WHERE (loc.id IN (?) OR (loc.id LIKE ? AND loc.id ~ ?))") ||
+		!reflect.DeepEqual(args, []interface{}{"x1000c0s0b0n0", "x1000c0s%",
+			"^x1000c0s[0-9]+b0n0$"}) {
+		t.Errorf("Unexpected query: %s %v", sql, args)
+	}
+	_, err = getHWInvByLocQuery(HWInvLoc_IDs([]string{"x1000c0s*"}),
+		HWInvLoc_Parent)
+	if err != ErrHMSDSArgBadArg {
+		t.Errorf("Expected %v with parents, got %v", ErrHMSDSArgBadArg, err)
+	}
+}
+
+func TestPgForEachHWInvByLocFilter(t *testing.T) {
+	columns := addAliasToCols(hwInvAlias, hwInvCols, hwInvCols)
+
+	node1 := stest.NodeHWInvByLoc1
+	node1LocInfo, _ := json.Marshal(node1.HMSNodeLocationInfo)
+	node1FruInfo, _ := json.Marshal(node1.PopulatedFRU.HMSNodeFRUInfo)
+	row := []driver.Value{node1.ID, node1.Type, node1.Ordinal, node1.Status, node1LocInfo, node1.PopulatedFRU.FRUID, node1.PopulatedFRU.Type, node1.PopulatedFRU.Subtype, node1FruInfo}
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, _, _ := sqq.Select(columns...).
+		From(hwInvTable + " " + hwInvAlias).
+		Where(sq.Eq{hwInvAlias + "." + hwInvTypeCol: []string{xnametypes.Node.String()}}).
+		OrderBy(hwInvAlias + "." + hwInvIdCol).ToSql()
+
+	// Each entry is handed to fn
+	ResetMockDB()
+	mockPG.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().
+		WithArgs(xnametypes.Node.String()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row...).AddRow(row...))
+	hwlocs := []*sm.HWInvByLoc{}
+	err := dPG.ForEachHWInvByLocFilter(func(hwloc *sm.HWInvByLoc) error {
+		hwlocs = append(hwlocs, hwloc)
+		return nil
+	}, HWInvLoc_Type(node1.Type))
+	if err != nil {
+		t.Errorf("Test 0 Failed: Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual([]*sm.HWInvByLoc{&node1, &node1}, hwlocs) {
+		t.Errorf("Test 0 Failed: Unexpected HWLocs '%v'", hwlocs)
+	}
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Test 0 Failed: Sql expectations were not met: %s", mock_err)
+	}
+
+	// An error from fn stops the scan
+	ResetMockDB()
+	mockPG.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().
+		WithArgs(xnametypes.Node.String()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(row...).AddRow(row...))
+	n := 0
+	errStop := fmt.Errorf("stop")
+	err = dPG.ForEachHWInvByLocFilter(func(hwloc *sm.HWInvByLoc) error {
+		n++
+		return errStop
+	}, HWInvLoc_Type(node1.Type))
+	if err != errStop || n != 1 {
+		t.Errorf("Test 1 Failed: Expected fn error after 1 entry, got '%v' after %d", err, n)
+	}
+
+	// Bad type
+	ResetMockDB()
+	err = dPG.ForEachHWInvByLocFilter(func(hwloc *sm.HWInvByLoc) error {
+		return nil
+	}, HWInvLoc_Type("foo"))
+	if err == nil {
+		t.Errorf("Test 2 Failed: Expected an error.")
+	}
+}
+
 func TestPgGetHWInvByFRUFilter(t *testing.T) {
 	columns := addAliasToCols(hwInvFruAlias, hwInvFruTblCols, hwInvFruTblCols)
 
@@ -1973,6 +2529,101 @@ func TestPgGetHWInvByFRUFilter(t *testing.T) {
 	}
 }
 
+func TestPgLocateHWInvByFRU(t *testing.T) {
+	columns := append([]string{"location"}, addAliasToCols(hwInvFruAlias, hwInvFruTblCols, hwInvFruTblCols)...)
+	node1 := stest.NodeHWInvByFRU1
+	node1FruInfo, _ := json.Marshal(node1.HMSNodeFRUInfo)
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	locate := sqq.Select(append([]string{"COALESCE(" + hwInvLocIdColAlias + ", '')"},
+		addAliasToCols(hwInvFruAlias, hwInvFruTblCols, hwInvFruTblCols)...)...).
+		From(hwInvFruTable + " " + hwInvFruAlias).
+		LeftJoin(hwInvLocTable + " " + hwInvLocAlias + " ON " +
+			hwInvLocFruIdColAlias + " = " + hwInvFruTblIdColAlias)
+	query1, _, _ := locate.
+		Where(sq.Or{sq.ILike{hwInvFruTblManufacturerColAlias: "%cray%"}}).
+		Where(sq.Eq{hwInvFruTblSerialColAlias: []string{node1.HMSNodeFRUInfo.SerialNumber}}).
+		OrderBy(hwInvFruTblIdColAlias).ToSql()
+
+	tests := []struct {
+		f_opts          []HWInvLocFiltFunc
+		dbRows          [][]driver.Value
+		dbError         error
+		expectedPrepare string
+		expectedArgs    []driver.Value
+		expectedHwFrus  []*sm.HWInvByFRULocation
+		expectedErr     error
+	}{{
+		f_opts: []HWInvLocFiltFunc{
+			HWInvLoc_Manufacturers([]string{"cray"}),
+			HWInvLoc_SerialNumbers([]string{node1.HMSNodeFRUInfo.SerialNumber}),
+		},
+		dbRows: [][]driver.Value{
+			{"x0c0s0b0n0", node1.FRUID, node1.Type, node1.Subtype, node1FruInfo},
+			{"", node1.FRUID, node1.Type, node1.Subtype, node1FruInfo},
+		},
+		expectedPrepare: regexp.QuoteMeta(query1),
+		expectedArgs:    []driver.Value{"%cray%", node1.HMSNodeFRUInfo.SerialNumber},
+		expectedHwFrus: []*sm.HWInvByFRULocation{
+			{Location: "x0c0s0b0n0", HWInvByFRU: node1},
+			{Location: "", HWInvByFRU: node1},
+		},
+	}, {
+		f_opts:      []HWInvLocFiltFunc{HWInvLoc_Types([]string{"foo"})},
+		expectedErr: ErrHMSDSArgBadType,
+	}, {
+		f_opts: []HWInvLocFiltFunc{
+			HWInvLoc_Manufacturers([]string{"cray"}),
+			HWInvLoc_SerialNumbers([]string{node1.HMSNodeFRUInfo.SerialNumber}),
+		},
+		dbError:         sql.ErrConnDone,
+		expectedPrepare: regexp.QuoteMeta(query1),
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		rows := sqlmock.NewRows(columns)
+		for _, row := range test.dbRows {
+			rows.AddRow(row...)
+		}
+		if test.dbError != nil {
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WillReturnError(test.dbError)
+		} else if test.expectedErr == nil {
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WithArgs(test.expectedArgs...).WillReturnRows(rows)
+		}
+
+		hwfrus, err := dPG.LocateHWInvByFRU(test.f_opts...)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if test.dbError == nil && test.expectedErr == nil {
+			if err != nil {
+				t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+			} else if !reflect.DeepEqual(test.expectedHwFrus, hwfrus) {
+				t.Errorf("Test %v Failed: Expected HWFrus '%v'; Recieved HWFrus '%v'", i, test.expectedHwFrus, hwfrus)
+			}
+		} else if err == nil {
+			t.Errorf("Test %v Failed: Expected an error.", i)
+		}
+	}
+}
+
+func TestDecodeFRUIdentity(t *testing.T) {
+	node1FruInfo, _ := json.Marshal(stest.NodeHWInvByFRU1.HMSNodeFRUInfo)
+	ids := decodeFRUIdentity(node1FruInfo)
+	exp := fruIdentity{
+		SerialNumber: stest.NodeHWInvByFRU1.HMSNodeFRUInfo.SerialNumber,
+		PartNumber:   stest.NodeHWInvByFRU1.HMSNodeFRUInfo.PartNumber,
+		Manufacturer: stest.NodeHWInvByFRU1.HMSNodeFRUInfo.Manufacturer,
+	}
+	if ids != exp {
+		t.Errorf("Expected %v, got %v", exp, ids)
+	}
+	if ids := decodeFRUIdentity([]byte("null")); ids != (fruIdentity{}) {
+		t.Errorf("Expected no identity for null info, got %v", ids)
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Hardware Inventory History Tests
 ///////////////////////////////////////////////////////////////////////////////
@@ -2144,68 +2795,239 @@ func TestPgGetHWInvHistFilter(t *testing.T) {
 	}
 }
 
-func TestInsertHWInvHists(t *testing.T) {
-	testHWInvHist1 := sm.HWInvHist{
-		ID:        "x5c4s3b2n1p0",
+func TestPgGetHWInvSightingsFilter(t *testing.T) {
+	columns := addAliasToCols(hwInvSightingsAlias, hwInvSightingsCols, hwInvSightingsCols)
+
+	testSighting1 := sm.HWInvSighting{
+		ID:        "x5c4s3b2n1d0",
 		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
-		Timestamp: "2020-01-21 11:36:00",
-		EventType: "Added",
+		FirstSeen: "2020-01-21T11:36:00Z",
+		LastSeen:  "2020-01-22T11:36:00Z",
 	}
-	testHWInvHist2 := sm.HWInvHist{
-		ID:        "x5c4s3b2n1p1",
-		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_2",
-		Timestamp: "2020-01-21 11:36:00",
-		EventType: "Added",
-	}
-	testHWInvHistBad := sm.HWInvHist{
-		ID:        "x5c4s3b2n1p0",
+	testSighting2 := sm.HWInvSighting{
+		ID:        "x5c4s3b2n1d1",
 		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
-		Timestamp: "2020-01-21 12:00:00",
-		EventType: "foo",
+		FirstSeen: "2020-01-23T11:36:00Z",
+		LastSeen:  "2020-01-24T11:36:00Z",
 	}
+	timeStartArg, _ := time.Parse(time.RFC3339, "2020-01-01T00:00:00Z")
+	timeEndArg, _ := time.Parse(time.RFC3339, "2020-02-01T00:00:00Z")
 
 	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query1, _, _ := sqq.Select(columns...).
+		From(hwInvSightingsTable + " " + hwInvSightingsAlias).
+		OrderBy(hwInvSightingsFruIdColAlias + ", " + hwInvSightingsFirstSeenColAlias + " ASC").ToSql()
 
-	insert1, _, _ := sqq.Insert(hwInvHistTable).
-		Columns(hwInvHistColsNoTS...).
-		Values("x0c0s0b0n0", "MFR-PARTNUMBER-SERIALNUMBER_1", "Scanned").
-		Values("x0c0s0b0n0", "MFR-PARTNUMBER-SERIALNUMBER_1", "Scanned").ToSql()
+	query2, _, _ := sqq.Select(columns...).
+		From(hwInvSightingsTable + " " + hwInvSightingsAlias).
+		Where(sq.Eq{hwInvSightingsFruIdColAlias: []string{testSighting1.FruId}}).
+		Where(sq.GtOrEq{hwInvSightingsLastSeenColAlias: timeStartArg}).
+		Where(sq.LtOrEq{hwInvSightingsFirstSeenColAlias: timeEndArg}).
+		OrderBy(hwInvSightingsFruIdColAlias + ", " + hwInvSightingsFirstSeenColAlias + " ASC").ToSql()
 
 	tests := []struct {
-		hhs             []*sm.HWInvHist
-		expectedPrepare string
-		expectedArgs    []driver.Value
-		dbError         error
+		f_opts            []HWInvHistFiltFunc
+		dbError           error
+		expectedPrepare   string
+		expectedArgs      []driver.Value
+		expectedSightings []*sm.HWInvSighting
+		expectedErr       error
 	}{{
-		hhs:             []*sm.HWInvHist{&testHWInvHist1, &testHWInvHist2},
-		expectedPrepare: regexp.QuoteMeta(insert1),
-		expectedArgs: []driver.Value{
-			testHWInvHist1.ID, testHWInvHist1.FruId, testHWInvHist1.EventType,
-			testHWInvHist2.ID, testHWInvHist2.FruId, testHWInvHist2.EventType,
+		f_opts:            []HWInvHistFiltFunc{},
+		expectedPrepare:   regexp.QuoteMeta(query1),
+		expectedSightings: []*sm.HWInvSighting{&testSighting1, &testSighting2},
+	}, {
+		f_opts: []HWInvHistFiltFunc{
+			HWInvHist_FruIDs([]string{testSighting1.FruId}),
+			HWInvHist_StartTime("2020-01-01T00:00:00Z"),
+			HWInvHist_EndTime("2020-02-01T00:00:00Z"),
 		},
-		dbError: nil,
+		expectedPrepare:   regexp.QuoteMeta(query2),
+		expectedArgs:      []driver.Value{testSighting1.FruId, timeStartArg, timeEndArg},
+		expectedSightings: []*sm.HWInvSighting{&testSighting1, &testSighting2},
 	}, {
-		hhs:             []*sm.HWInvHist{&testHWInvHistBad},
-		expectedPrepare: "",
-		expectedArgs:    []driver.Value{},
-		dbError:         ErrHMSDSArgBadHWInvHistEventType,
+		f_opts:      []HWInvHistFiltFunc{HWInvHist_StartTime("yesterday")},
+		expectedErr: ErrHMSDSArgBadTimeFormat,
 	}, {
-		hhs:             []*sm.HWInvHist{&testHWInvHist1, &testHWInvHist2},
-		expectedPrepare: regexp.QuoteMeta(insert1),
-		expectedArgs: []driver.Value{
-			testHWInvHist1.ID, testHWInvHist1.FruId, testHWInvHist1.EventType,
-			testHWInvHist2.ID, testHWInvHist2.FruId, testHWInvHist2.EventType,
-		},
-		dbError: sql.ErrNoRows,
+		f_opts:          []HWInvHistFiltFunc{},
+		dbError:         sql.ErrConnDone,
+		expectedPrepare: regexp.QuoteMeta(query1),
 	}}
 
 	for i, test := range tests {
 		ResetMockDB()
+		rows := sqlmock.NewRows(columns)
+		for _, s := range test.expectedSightings {
+			rows.AddRow(s.ID, s.FruId, s.FirstSeen, s.LastSeen)
+		}
+
 		mockPG.ExpectBegin()
-		if test.expectedPrepare == "" && test.dbError != nil {
-			mockPG.ExpectRollback()
-		} else if test.dbError != nil {
-			mockPG.ExpectPrepare(test.expectedPrepare).ExpectExec().WillReturnError(test.dbError)
+		if test.dbError != nil {
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WillReturnError(test.dbError)
+			mockPG.ExpectRollback()
+		} else if test.expectedErr == nil {
+			if len(test.expectedArgs) > 0 {
+				mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WithArgs(test.expectedArgs...).WillReturnRows(rows)
+			} else {
+				mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WillReturnRows(rows)
+			}
+			mockPG.ExpectCommit()
+		} else {
+			mockPG.ExpectRollback()
+		}
+
+		sightings, err := dPG.GetHWInvSightingsFilter(test.f_opts...)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if test.dbError == nil && test.expectedErr == nil {
+			if err != nil {
+				t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+			} else if !reflect.DeepEqual(test.expectedSightings, sightings) {
+				t.Errorf("Test %v Failed: Expected sightings '%v'; Recieved '%v'", i, test.expectedSightings, sightings)
+			}
+		} else if err == nil {
+			t.Errorf("Test %v Failed: Expected an error.", i)
+		}
+	}
+}
+
+func TestPgRecordHWInvSightings(t *testing.T) {
+	columns := addAliasToCols(hwInvSightingsAlias, hwInvSightingsCols, hwInvSightingsCols)
+	hwlocs := []*sm.HWInvByLoc{
+		{ID: "x5c4s3b2n1d0", PopulatedFRU: &sm.HWInvByFRU{FRUID: "DIMM-A"}},
+		{ID: "x5c4s3b2n1d1", PopulatedFRU: &sm.HWInvByFRU{FRUID: "DIMM-B"}},
+		{ID: "x5c4s3b2n1d2", PopulatedFRU: &sm.HWInvByFRU{FRUID: "DIMM-C"}},
+		{ID: "x5c4s3b2n1d3"},
+	}
+
+	tests := []struct {
+		hwlocs      []*sm.HWInvByLoc
+		lastRows    [][]driver.Value
+		expectQuery bool
+		update      bool
+		insertArgs  []driver.Value
+		dbError     error
+	}{{ // Test 0 nothing populated, nothing to do
+		hwlocs: []*sm.HWInvByLoc{{ID: "x5c4s3b2n1d3"}},
+	}, { // Test 1 DIMM-A stayed, DIMM-B moved, DIMM-C is new
+		hwlocs: hwlocs,
+		lastRows: [][]driver.Value{
+			{"x5c4s3b2n1d0", "DIMM-A", "2020-01-21T11:36:00Z", "2020-01-22T11:36:00Z"},
+			{"x5c4s3b2n1d0", "DIMM-B", "2020-01-20T11:36:00Z", "2020-01-21T11:00:00Z"},
+		},
+		expectQuery: true,
+		update:      true,
+		insertArgs:  []driver.Value{"x5c4s3b2n1d1", "DIMM-B", "x5c4s3b2n1d2", "DIMM-C"},
+	}, { // Test 2 lookup fails
+		hwlocs:      hwlocs,
+		expectQuery: true,
+		dbError:     sql.ErrConnDone,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		if test.expectQuery {
+			if test.dbError != nil {
+				mockPG.ExpectPrepare("FROM " + hwInvSightingsTable).ExpectQuery().
+					WillReturnError(test.dbError)
+				mockPG.ExpectRollback()
+			} else {
+				rows := sqlmock.NewRows(columns)
+				for _, row := range test.lastRows {
+					rows.AddRow(row...)
+				}
+				mockPG.ExpectPrepare("FROM "+hwInvSightingsTable).ExpectQuery().
+					WithArgs("DIMM-A", "DIMM-B", "DIMM-C").WillReturnRows(rows)
+				if test.update {
+					mockPG.ExpectPrepare("UPDATE " + hwInvSightingsTable).ExpectExec().
+						WithArgs("DIMM-A").WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+				mockPG.ExpectPrepare("INSERT INTO " + hwInvSightingsTable).ExpectExec().
+					WithArgs(test.insertArgs...).WillReturnResult(sqlmock.NewResult(0, 2))
+				mockPG.ExpectCommit()
+			}
+		} else {
+			mockPG.ExpectCommit()
+		}
+
+		err := dPG.RecordHWInvSightings(test.hwlocs)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if test.dbError != nil {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error.", i)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+		}
+	}
+}
+
+func TestInsertHWInvHists(t *testing.T) {
+	testHWInvHist1 := sm.HWInvHist{
+		ID:        "x5c4s3b2n1p0",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
+		Timestamp: "2020-01-21 11:36:00",
+		EventType: "Added",
+	}
+	testHWInvHist2 := sm.HWInvHist{
+		ID:        "x5c4s3b2n1p1",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_2",
+		Timestamp: "2020-01-21 11:36:00",
+		EventType: "Added",
+	}
+	testHWInvHistBad := sm.HWInvHist{
+		ID:        "x5c4s3b2n1p0",
+		FruId:     "MFR-PARTNUMBER-SERIALNUMBER_1",
+		Timestamp: "2020-01-21 12:00:00",
+		EventType: "foo",
+	}
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	insert1, _, _ := sqq.Insert(hwInvHistTable).
+		Columns(hwInvHistColsNoTS...).
+		Values("x0c0s0b0n0", "MFR-PARTNUMBER-SERIALNUMBER_1", "Scanned").
+		Values("x0c0s0b0n0", "MFR-PARTNUMBER-SERIALNUMBER_1", "Scanned").ToSql()
+
+	tests := []struct {
+		hhs             []*sm.HWInvHist
+		expectedPrepare string
+		expectedArgs    []driver.Value
+		dbError         error
+	}{{
+		hhs:             []*sm.HWInvHist{&testHWInvHist1, &testHWInvHist2},
+		expectedPrepare: regexp.QuoteMeta(insert1),
+		expectedArgs: []driver.Value{
+			testHWInvHist1.ID, testHWInvHist1.FruId, testHWInvHist1.EventType,
+			testHWInvHist2.ID, testHWInvHist2.FruId, testHWInvHist2.EventType,
+		},
+		dbError: nil,
+	}, {
+		hhs:             []*sm.HWInvHist{&testHWInvHistBad},
+		expectedPrepare: "",
+		expectedArgs:    []driver.Value{},
+		dbError:         ErrHMSDSArgBadHWInvHistEventType,
+	}, {
+		hhs:             []*sm.HWInvHist{&testHWInvHist1, &testHWInvHist2},
+		expectedPrepare: regexp.QuoteMeta(insert1),
+		expectedArgs: []driver.Value{
+			testHWInvHist1.ID, testHWInvHist1.FruId, testHWInvHist1.EventType,
+			testHWInvHist2.ID, testHWInvHist2.FruId, testHWInvHist2.EventType,
+		},
+		dbError: sql.ErrNoRows,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		if test.expectedPrepare == "" && test.dbError != nil {
+			mockPG.ExpectRollback()
+		} else if test.dbError != nil {
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectExec().WillReturnError(test.dbError)
 			mockPG.ExpectRollback()
 		} else {
 			mockPG.ExpectPrepare(test.expectedPrepare).ExpectExec().WithArgs(test.expectedArgs...).WillReturnResult(sqlmock.NewResult(0, 1))
@@ -2225,6 +3047,87 @@ func TestInsertHWInvHists(t *testing.T) {
 	}
 }
 
+func TestPgInsertHWInvHistsChunked(t *testing.T) {
+	// Enough entries for two full chunks and a partial one.
+	n := 2*bulkInsertMaxRows + bulkInsertMaxRows/2
+	hhs := make([]*sm.HWInvHist, 0, n)
+	for i := 0; i < n; i++ {
+		hhs = append(hhs, &sm.HWInvHist{
+			ID:        "x0c0s0b0n0",
+			FruId:     fmt.Sprintf("MFR-PARTNUMBER-SERIALNUMBER_%d", i),
+			EventType: "Scanned",
+		})
+	}
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	chunkInsert := func(rows int) string {
+		insert := sqq.Insert(hwInvHistTable).
+			Columns(hwInvHistColsNoTS...)
+		for i := 0; i < rows; i++ {
+			insert = insert.Values("x0c0s0b0n0", "MFR-PARTNUMBER-SERIALNUMBER_1", "Scanned")
+		}
+		q, _, _ := insert.ToSql()
+		return "^" + regexp.QuoteMeta(q) + "$"
+	}
+
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	// Both full chunks are the same statement, so it is only prepared once.
+	full := mockPG.ExpectPrepare(chunkInsert(bulkInsertMaxRows))
+	full.ExpectExec().WillReturnResult(sqlmock.NewResult(0, int64(bulkInsertMaxRows)))
+	full.ExpectExec().WillReturnResult(sqlmock.NewResult(0, int64(bulkInsertMaxRows)))
+	mockPG.ExpectPrepare(chunkInsert(bulkInsertMaxRows / 2)).ExpectExec().
+		WillReturnResult(sqlmock.NewResult(0, int64(bulkInsertMaxRows/2)))
+	mockPG.ExpectCommit()
+
+	err := dPG.InsertHWInvHists(hhs)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	}
+}
+
+func TestBulkInsertChunks(t *testing.T) {
+	rows := make([][]interface{}, 2*bulkInsertMaxRows+1)
+	tests := []struct {
+		rows     [][]interface{}
+		ncols    int
+		expected []int
+	}{{
+		rows:     rows[:0],
+		ncols:    3,
+		expected: []int{},
+	}, {
+		rows:     rows[:1],
+		ncols:    3,
+		expected: []int{1},
+	}, {
+		rows:     rows[:bulkInsertMaxRows],
+		ncols:    3,
+		expected: []int{bulkInsertMaxRows},
+	}, {
+		rows:     rows,
+		ncols:    3,
+		expected: []int{bulkInsertMaxRows, bulkInsertMaxRows, 1},
+	}, {
+		// Too many columns for a full chunk under the parameter limit.
+		rows:     rows[:bulkInsertMaxRows],
+		ncols:    100,
+		expected: []int{655, 345},
+	}}
+	for i, test := range tests {
+		chunks := bulkInsertChunks(test.rows, test.ncols)
+		sizes := make([]int, 0, len(chunks))
+		for _, chunk := range chunks {
+			sizes = append(sizes, len(chunk))
+		}
+		if !reflect.DeepEqual(test.expected, sizes) {
+			t.Errorf("Test %v Failed: Expected chunk sizes %v; Recieved %v", i, test.expected, sizes)
+		}
+	}
+}
+
 func TestDeleteHWInvHistByLocID(t *testing.T) {
 	testHWInvHist1 := sm.HWInvHist{
 		ID:        "x5c4s3b2n1p0",
@@ -2486,16 +3389,16 @@ func TestPgPatchRFEndpointNoDiscInfo(t *testing.T) {
 		},
 		dbErrorGet1: nil,
 		dbRowsGet1: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet1: regexp.QuoteMeta(getRFEndpointPrefix + " WHERE (id = $1);"),
 		expectedArgsGet1:    []driver.Value{"x0c0s1b1"},
 		dbError:             nil,
 		expectedPrepare:     regexp.QuoteMeta(updatePgRFEndpointNoDiscInfoQuery),
-		expectedArgs:        []driver.Value{"NodeBMC", "", "10.254.2.12", "", "10.254.2.12", true, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", true, "", "x0c0s1b1"},
+		expectedArgs:        []driver.Value{"NodeBMC", "", "10.254.2.12", "", "10.254.2.12", true, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", true, "", "", "x0c0s1b1"},
 		dbErrorGet2:         nil,
 		dbRowsGet2: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", true, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", true, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", true, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", true, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet2: regexp.QuoteMeta(getRFEndpointByIDQuery),
 		expectedArgsGet2:    []driver.Value{"x0c0s1b1"},
@@ -2528,7 +3431,7 @@ func TestPgPatchRFEndpointNoDiscInfo(t *testing.T) {
 		epp:         sm.RedfishEndpointPatch{},
 		dbErrorGet1: nil,
 		dbRowsGet1: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet1: regexp.QuoteMeta(getRFEndpointPrefix + " WHERE (id = $1);"),
 		expectedArgsGet1:    []driver.Value{"x0c0s1b1"},
@@ -2570,7 +3473,7 @@ func TestPgPatchRFEndpointNoDiscInfo(t *testing.T) {
 		},
 		dbErrorGet1: nil,
 		dbRowsGet1: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet1: regexp.QuoteMeta(getRFEndpointPrefix + " WHERE (id = $1);"),
 		expectedArgsGet1:    []driver.Value{"x0c0s1b1"},
@@ -2609,16 +3512,16 @@ func TestPgPatchRFEndpointNoDiscInfo(t *testing.T) {
 		},
 		dbErrorGet1: nil,
 		dbRowsGet1: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.12", "", "10.254.2.12", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.12", false, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet1: regexp.QuoteMeta(getRFEndpointPrefix + " WHERE (id = $1);"),
 		expectedArgsGet1:    []driver.Value{"x0c0s1b1"},
 		dbError:             nil,
 		expectedPrepare:     regexp.QuoteMeta(updatePgRFEndpointNoDiscInfoQuery),
-		expectedArgs:        []driver.Value{"NodeBMC", "", "10.254.2.13", "", "10.254.2.13", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.13", false, "", "x0c0s1b1"},
+		expectedArgs:        []driver.Value{"NodeBMC", "", "10.254.2.13", "", "10.254.2.13", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.13", false, "", "", "x0c0s1b1"},
 		dbErrorGet2:         nil,
 		dbRowsGet2: [][]driver.Value{
-			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.13", "", "10.254.2.13", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.13", false, "", json.RawMessage(`{}`)},
+			[]driver.Value{"x0c0s1b1", "NodeBMC", "", "10.254.2.13", "", "10.254.2.13", false, "da4faffe-6491-4f3f-ab54-3bf8fce57531", "root", "", false, false, "a4bf012e85b5", "10.254.2.13", false, "", "", json.RawMessage(`{}`)},
 		},
 		expectedPrepareGet2: regexp.QuoteMeta(getRFEndpointByIDQuery),
 		expectedArgsGet2:    []driver.Value{"x0c0s1b1"},
@@ -2705,6 +3608,129 @@ func TestPgPatchRFEndpointNoDiscInfo(t *testing.T) {
 	}
 }
 
+// Matches a password sealed with env for the RedfishEndpoint id.
+type sealedPassword struct {
+	env   *envcrypt.Envelope
+	id    string
+	plain string
+}
+
+func (sp sealedPassword) Match(v driver.Value) bool {
+	sealed, ok := v.(string)
+	if !ok || !envcrypt.IsSealed(sealed) {
+		return false
+	}
+	plain, err := sp.env.Open(sealed, rfEPPasswordWhere(sp.id))
+	return err == nil && plain == sp.plain
+}
+
+func TestPgRFEndpointPasswordEncryption(t *testing.T) {
+	key1 := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	key2 := base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210"))
+	kf, _ := envcrypt.ParseKeyFile([]byte("k1 " + key1))
+	oldEnv, _ := envcrypt.NewEnvelope(kf)
+	kf, _ = envcrypt.ParseKeyFile([]byte("k2 " + key2 + "\nk1 " + key1))
+	env, _ := envcrypt.NewEnvelope(kf)
+	defer dPG.SetFieldEncryption(nil)
+	dPG.SetFieldEncryption(env)
+
+	oldSealed, _ := oldEnv.Seal("pw1", rfEPPasswordWhere("x0c0s1b0"))
+	curSealed, _ := env.Seal("pw2", rfEPPasswordWhere("x0c0s2b0"))
+	row := func(id, password string) []driver.Value {
+		return []driver.Value{id, "NodeBMC", "", id, "", id, true, "", "root",
+			password, false, false, "", "", true, "", "", json.RawMessage(`{}`)}
+	}
+
+	// Written sealed
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(insertPgRFEndpointQuery))).
+		ExpectExec().WithArgs("x0c0s0b0", "NodeBMC", "", "x0c0s0b0", "",
+		"x0c0s0b0", true, "", "root",
+		sealedPassword{env, "x0c0s0b0", "pw0"},
+		false, false, "", "", true, "", "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockPG.ExpectCommit()
+	ep := &sm.RedfishEndpoint{RedfishEPDescription: rf.RedfishEPDescription{
+		ID: "x0c0s0b0", Type: "NodeBMC", Hostname: "x0c0s0b0", FQDN: "x0c0s0b0",
+		Enabled: true, User: "root", Password: "pw0", RediscOnUpdate: true}}
+	if err := dPG.InsertRFEndpoint(ep); err != nil {
+		t.Errorf("InsertRFEndpoint: %s", err)
+	}
+	if ep.Password != "pw0" {
+		t.Errorf("Expected the caller's password left alone, got '%s'", ep.Password)
+	}
+	if err := mockPG.ExpectationsWereMet(); err != nil {
+		t.Errorf("Insert: Sql expectations were not met: %s", err)
+	}
+
+	// Read decrypted, with the old key too, and plaintext as it is.  Sealed
+	// for another row fails.
+	tests := []struct {
+		id          string
+		password    string
+		expPassword string
+		expErr      bool
+	}{
+		{"x0c0s0b0", "pw0", "pw0", false},
+		{"x0c0s1b0", oldSealed, "pw1", false},
+		{"x0c0s2b0", curSealed, "pw2", false},
+		{"x0c0s3b0", curSealed, "", true},
+	}
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(getRFEndpointByIDQuery))).
+			ExpectQuery().WithArgs(test.id).
+			WillReturnRows(sqlmock.NewRows(rfEPsAllCols).AddRow(row(test.id, test.password)...))
+		if test.expErr {
+			mockPG.ExpectRollback()
+		} else {
+			mockPG.ExpectCommit()
+		}
+		ep, err := dPG.GetRFEndpointByID(test.id)
+		if err := mockPG.ExpectationsWereMet(); err != nil {
+			t.Errorf("Test %d: Sql expectations were not met: %s", i, err)
+		}
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Test %d: Expected an error", i)
+			}
+		} else if err != nil || ep.Password != test.expPassword {
+			t.Errorf("Test %d: Expected '%s', got %v, %v", i, test.expPassword, ep, err)
+		}
+	}
+
+	// Re-encrypted if plaintext or under the old key
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(regexp.QuoteMeta(
+		"SELECT id, password FROM rf_endpoints WHERE password <> $1 FOR UPDATE")).
+		ExpectQuery().WithArgs("").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password"}).
+			AddRow("x0c0s0b0", "pw0").
+			AddRow("x0c0s1b0", oldSealed).
+			AddRow("x0c0s2b0", curSealed))
+	update := mockPG.ExpectPrepare(regexp.QuoteMeta(
+		"UPDATE rf_endpoints SET password = $1 WHERE id = $2"))
+	update.ExpectExec().WithArgs(sealedPassword{env, "x0c0s0b0", "pw0"}, "x0c0s0b0").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	update.ExpectExec().WithArgs(sealedPassword{env, "x0c0s1b0", "pw1"}, "x0c0s1b0").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockPG.ExpectCommit()
+	if num, err := dPG.ReencryptRFEndpointPasswords(); err != nil || num != 2 {
+		t.Errorf("Expected 2 re-encrypted, got %d, %v", num, err)
+	}
+	if err := mockPG.ExpectationsWereMet(); err != nil {
+		t.Errorf("Re-encrypt: Sql expectations were not met: %s", err)
+	}
+
+	dPG.SetFieldEncryption(nil)
+	if _, err := dPG.ReencryptRFEndpointPasswords(); err != ErrHMSDSNoEncryptKey {
+		t.Errorf("Expected ErrHMSDSNoEncryptKey, got %v", err)
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Service Endpoint Query Tests
 ///////////////////////////////////////////////////////////////////////////////
@@ -3423,6 +4449,119 @@ func TestPgGetCompEthInterfaceFilter(t *testing.T) {
 	}
 }
 
+func TestPgEventSubscriptions(t *testing.T) {
+	sub := sm.EventSubscription{
+		RedfishEndpointID: "x3000c0s9b0",
+		Destination:       "https://collector.local/events",
+		Context:           "x3000c0s9b0",
+		SubscriptionURI:   "/redfish/v1/EventService/Subscriptions/1",
+		Status:            sm.EventSubActive,
+		LastUpdated:       "2026-10-17T12:00:00Z",
+	}
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, _, _ := sqq.Select(eventSubsCols...).
+		From(eventSubsTable).
+		Where(sq.Eq{eventSubsRFEndpointIDCol: []string{"x3000c0s9b0"}}).
+		OrderBy(eventSubsRFEndpointIDCol).ToSql()
+
+	ResetMockDB()
+	rows := sqlmock.NewRows(eventSubsCols).AddRow(sub.RedfishEndpointID,
+		sub.Destination, sub.Context, sub.SubscriptionURI, sub.Status,
+		sub.LastError, sub.LastUpdated)
+	mockPG.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().
+		WithArgs("x3000c0s9b0").WillReturnRows(rows)
+	out, err := dPG.GetEventSubscriptions([]string{"X3000c0s9b0"})
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Get: Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Get: Unexpected error received: %s", err)
+	} else if len(out) != 1 || *out[0] != sub {
+		t.Errorf("Get: Expected %+v, received %+v", sub, out)
+	}
+
+	ResetMockDB()
+	mockPG.ExpectPrepare(regexp.QuoteMeta("INSERT INTO "+eventSubsTable)).
+		ExpectExec().
+		WithArgs(sub.RedfishEndpointID, sub.Destination, sub.Context,
+			sub.SubscriptionURI, sub.Status, sub.LastError).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = dPG.UpsertEventSubscription(&sub)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Upsert: Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Upsert: Unexpected error received: %s", err)
+	}
+	if err := dPG.UpsertEventSubscription(&sm.EventSubscription{
+		RedfishEndpointID: "foo"}); err != ErrHMSDSArgBadID {
+		t.Errorf("Upsert: Expected ErrHMSDSArgBadID for a bad xname, got %v", err)
+	}
+}
+
+func TestPgRFEndpointLiveness(t *testing.T) {
+	l := sm.RedfishEndpointLiveness{
+		RedfishEndpointID: "x3000c0s9b0",
+		LastContact:       "2026-10-17T12:00:00Z",
+		LastProbe:         "2026-10-17T12:05:00Z",
+		Failures:          5,
+		Unreachable:       true,
+		LastError:         "connection refused",
+	}
+	contact, _ := time.Parse(time.RFC3339, l.LastContact)
+	probe, _ := time.Parse(time.RFC3339, l.LastProbe)
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, _, _ := sqq.Select(rfEPLivenessCols...).
+		From(rfEPLivenessTable).
+		Where(sq.Eq{rfEPLivenessRFEndpointIDCol: []string{"x3000c0s9b0", "x3000c0s10b0"}}).
+		OrderBy(rfEPLivenessRFEndpointIDCol).ToSql()
+
+	ResetMockDB()
+	rows := sqlmock.NewRows(rfEPLivenessCols).
+		AddRow(l.RedfishEndpointID, contact, probe, l.Failures,
+			l.Unreachable, l.LastError).
+		AddRow("x3000c0s10b0", nil, probe, 1, false, "timeout")
+	mockPG.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().
+		WithArgs("x3000c0s9b0", "x3000c0s10b0").WillReturnRows(rows)
+	out, err := dPG.GetRFEndpointLiveness([]string{"X3000c0s9b0", "x3000c0s10b0"})
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Get: Sql expectations were not met: %s", mock_err)
+	}
+	never := sm.RedfishEndpointLiveness{
+		RedfishEndpointID: "x3000c0s10b0",
+		LastProbe:         l.LastProbe,
+		Failures:          1,
+		LastError:         "timeout",
+	}
+	if err != nil {
+		t.Errorf("Get: Unexpected error received: %s", err)
+	} else if len(out) != 2 || *out[0] != l || *out[1] != never {
+		t.Errorf("Get: Expected %+v %+v, received %+v", l, never, out)
+	}
+
+	ResetMockDB()
+	mockPG.ExpectPrepare(regexp.QuoteMeta("INSERT INTO "+rfEPLivenessTable)).
+		ExpectExec().
+		WithArgs(l.RedfishEndpointID, contact, probe, l.Failures,
+			l.Unreachable, l.LastError).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = dPG.UpsertRFEndpointLiveness(&l)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Upsert: Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Upsert: Unexpected error received: %s", err)
+	}
+	if err := dPG.UpsertRFEndpointLiveness(&sm.RedfishEndpointLiveness{
+		RedfishEndpointID: "foo"}); err != ErrHMSDSArgBadID {
+		t.Errorf("Upsert: Expected ErrHMSDSArgBadID for a bad xname, got %v", err)
+	}
+	if err := dPG.UpsertRFEndpointLiveness(&sm.RedfishEndpointLiveness{
+		RedfishEndpointID: "x3000c0s9b0", LastProbe: "now"}); err != ErrHMSDSArgBadTimeFormat {
+		t.Errorf("Upsert: Expected ErrHMSDSArgBadTimeFormat, got %v", err)
+	}
+}
+
 func TestInsertCompEthInterfaces(t *testing.T) {
 	testCompEth1 := sm.CompEthInterfaceV2{
 		ID:      "a4bf0138ee65",
@@ -4923,6 +6062,92 @@ func TestPgUpdateGroup(t *testing.T) {
 	}
 }
 
+func TestPgUpdateGroupChildren(t *testing.T) {
+	columns := compGroupsColsSMGroup
+	dval1 := []driver.Value{uuid1, dgrp1.Label, dgrp1.Description, pq.Array(&dgrp1.Tags), dgrp1.ExclusiveGroup}
+	dval2 := []driver.Value{uuid2, dgrp2.Label, dgrp2.Description, pq.Array(&dgrp2.Tags), dgrp2.ExclusiveGroup}
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	dgrp1Query, _, _ := sqq.Select(compGroupsColsSMGroup...).
+		From(compGroupsTable).
+		Where("name = ?", dgrp1.Label).
+		Where("namespace = ?", groupNamespace).ToSql()
+	dgrp2Query, _, _ := sqq.Select(compGroupsColsSMGroup...).
+		From(compGroupsTable).
+		Where("name = ?", dgrp2.Label).
+		Where("namespace = ?", groupNamespace).ToSql()
+	descQuery := ToPGQueryArgs(getGroupDescendants)
+	delQuery, _, _ := sqq.Delete(compGroupChildrenTable).
+		Where(sq.Eq{compGroupChildrenParentCol: uuid1}).ToSql()
+	insQuery, _, _ := sqq.Insert(compGroupChildrenTable).
+		Columns(compGroupChildrenParentCol, compGroupChildrenChildCol).
+		Values(uuid1, uuid2).ToSql()
+
+	tests := []struct {
+		children    []string
+		childRows   [][]driver.Value
+		descRows    [][]driver.Value
+		expectedErr error
+	}{{
+		// grp2 becomes a child of grp1
+		children:    []string{"GRP2"},
+		childRows:   [][]driver.Value{dval2},
+		descRows:    [][]driver.Value{},
+		expectedErr: nil,
+	}, {
+		// grp1 is already under grp2
+		children:    []string{"grp2"},
+		childRows:   [][]driver.Value{dval2},
+		descRows:    [][]driver.Value{{"grp3"}, {dgrp1.Label}},
+		expectedErr: ErrHMSDSGroupCycle,
+	}, {
+		children:    []string{"grp2"},
+		childRows:   [][]driver.Value{},
+		expectedErr: ErrHMSDSNoChildGroup,
+	}}
+	for i, test := range tests {
+		ResetMockDB()
+		rows1 := sqlmock.NewRows(columns).AddRow(dval1...)
+		rows2 := sqlmock.NewRows(columns)
+		for _, row := range test.childRows {
+			rows2.AddRow(row...)
+		}
+		mockPG.ExpectBegin()
+		mockPG.ExpectPrepare(regexp.QuoteMeta(dgrp1Query)).ExpectQuery().
+			WithArgs(dgrp1.Label, groupNamespace).WillReturnRows(rows1)
+		// Same statement as for grp1, so it is already prepared.
+		mockPG.ExpectQuery(regexp.QuoteMeta(dgrp2Query)).
+			WithArgs(dgrp2.Label, groupNamespace).WillReturnRows(rows2)
+		if len(test.childRows) != 0 {
+			drows := sqlmock.NewRows([]string{"name"})
+			for _, row := range test.descRows {
+				drows.AddRow(row...)
+			}
+			mockPG.ExpectPrepare(regexp.QuoteMeta(descQuery)).ExpectQuery().
+				WithArgs(uuid2).WillReturnRows(drows)
+		}
+		if test.expectedErr == nil {
+			mockPG.ExpectPrepare(regexp.QuoteMeta(delQuery)).ExpectExec().
+				WithArgs(uuid1).WillReturnResult(sqlmock.NewResult(0, 0))
+			mockPG.ExpectPrepare(regexp.QuoteMeta(insQuery)).ExpectExec().
+				WithArgs(uuid1, uuid2).WillReturnResult(sqlmock.NewResult(0, 1))
+			mockPG.ExpectCommit()
+		} else {
+			mockPG.ExpectRollback()
+		}
+
+		children := append([]string{}, test.children...)
+		err := dPG.UpdateGroup(dgrp1.Label, &sm.GroupPatch{Children: &children})
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error %v, got %v", i, test.expectedErr, err)
+		}
+	}
+}
+
 func TestPgAddGroupMember(t *testing.T) {
 	columns := compGroupsColsSMGroup // "id", "name", "description", "tags", "exclusive_group_identifier"
 	//
@@ -5318,48 +6543,151 @@ func TestPgDeleteGroupMember(t *testing.T) {
 	}
 }
 
-//
-// Partitions
-//
-
-func TestPgGetPartition(t *testing.T) {
-	columns := compGroupsColsSMPart // "id", "name", "description", "tags"
-
-	dval5 := []driver.Value{uuid5, dgrp5p.Name, dgrp5p.Description, pq.Array(&dgrp5p.Tags)}
-	dval6 := []driver.Value{uuid6, dgrp6p.Name, dgrp6p.Description, pq.Array(&dgrp6p.Tags)}
-
-	memberCols := []string{"component_id"}
+func TestPgGetGroupHist(t *testing.T) {
+	columns := []string{compGroupMembersHistCompIdCol, compGroupMembersHistActionCol,
+		compGroupMembersHistActorCol, compGroupMembersHistTimestampCol}
+	ts1, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
+	ts2, _ := time.Parse(time.RFC3339, "2024-01-02T00:00:00Z")
+	timeStartArg, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
 
 	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-
-	dgrp5Query, _, _ := sqq.Select(compGroupsColsSMPart...).
-		From(compGroupsTable).
-		Where("name = ?", strings.ToLower(dgrp5p.Name)).
-		Where("namespace = ?", partNamespace).ToSql()
-
-	dgrp5MQuery, _, _ := sqq.Select(compGroupMembersColsUser...).
-		From(compGroupMembersTable).
-		Where("group_id = ?", uuid5).ToSql()
-
-	dgrp6Query, _, _ := sqq.Select(compGroupsColsSMPart...).
-		From(compGroupsTable).
-		Where("name = ?", strings.ToLower(dgrp6p.Name)).
-		Where("namespace = ?", partNamespace).ToSql()
-
-	dgrp6MQuery, _, _ := sqq.Select(compGroupMembersColsUser...).
-		From(compGroupMembersTable).
-		Where("group_id = ?", uuid6).ToSql()
+	base := sqq.Select(columns...).
+		From(compGroupMembersHistTable).
+		Where(sq.Eq{compGroupMembersHistNameCol: "my_group"}).
+		Where(sq.Eq{compGroupMembersHistNamespaceCol: groupNamespace})
+	query1, _, _ := base.OrderBy(compGroupMembersHistSeqCol).ToSql()
+	query2, _, _ := base.
+		Where(sq.Eq{compGroupMembersHistCompIdCol: []string{"x0c0s1b0n0"}}).
+		Where(sq.GtOrEq{compGroupMembersHistTimestampCol: timeStartArg}).
+		OrderBy(compGroupMembersHistSeqCol).ToSql()
 
 	tests := []struct {
-		name                  string
-		dbColumns             []string
-		dbRows                [][]driver.Value
-		dbQueryError          error
-		expectedQueryPrepare  string
-		expectedQueryArgs     []driver.Value
-		dbMColumns            []string
-		dbMRows               [][]driver.Value
-		dbMQueryError         error
+		label           string
+		f               *MemberHistFilter
+		dbRows          [][]driver.Value
+		expectedPrepare string
+		expectedArgs    []driver.Value
+		expectedHist    []*sm.MemberHistEntry
+		expectedErr     error
+	}{{
+		label: "My_Group",
+		f:     nil,
+		dbRows: [][]driver.Value{
+			{"x0c0s1b0n0", sm.MemberHistAdded, "alice", ts1},
+			{"x0c0s1b0n0", sm.MemberHistRemoved, "", ts2},
+		},
+		expectedPrepare: regexp.QuoteMeta(query1),
+		expectedArgs:    []driver.Value{"my_group", groupNamespace},
+		expectedHist: []*sm.MemberHistEntry{
+			{ID: "x0c0s1b0n0", Action: sm.MemberHistAdded, Actor: "alice", Timestamp: "2024-01-01T00:00:00Z"},
+			{ID: "x0c0s1b0n0", Action: sm.MemberHistRemoved, Actor: "", Timestamp: "2024-01-02T00:00:00Z"},
+		},
+	}, {
+		label: "my_group",
+		f: &MemberHistFilter{
+			ID:        []string{"X0C0S1B0N0"},
+			StartTime: "2024-01-01T00:00:00Z",
+		},
+		dbRows:          [][]driver.Value{},
+		expectedPrepare: regexp.QuoteMeta(query2),
+		expectedArgs:    []driver.Value{"my_group", groupNamespace, "x0c0s1b0n0", timeStartArg},
+		expectedHist:    []*sm.MemberHistEntry{},
+	}, {
+		label:       "my_group",
+		f:           &MemberHistFilter{ID: []string{"foo"}},
+		expectedErr: ErrHMSDSArgBadID,
+	}, {
+		label:       "my_group",
+		f:           &MemberHistFilter{EndTime: "tomorrow"},
+		expectedErr: ErrHMSDSArgBadTimeFormat,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		if test.expectedErr == nil {
+			rows := sqlmock.NewRows(columns)
+			for _, row := range test.dbRows {
+				rows.AddRow(row...)
+			}
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WithArgs(test.expectedArgs...).WillReturnRows(rows)
+		}
+
+		hist, err := dPG.GetGroupHist(test.label, test.f)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Received '%v'", i, test.expectedErr, err)
+		} else if err == nil && !reflect.DeepEqual(test.expectedHist, hist) {
+			t.Errorf("Test %v Failed: Expected history '%v'; Received '%v'", i, test.expectedHist, hist)
+		}
+	}
+}
+
+func TestPgDeleteGroupWithActor(t *testing.T) {
+	ResetMockDB()
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	query, _, _ := sqq.Delete(compGroupsTable).
+		Where("name = ?", "my_group").
+		Where("namespace = ?", groupNamespace).ToSql()
+
+	// The actor is set for the transaction the group is deleted in.
+	mockPG.ExpectBegin()
+	mockPG.ExpectExec(regexp.QuoteMeta(ToPGQueryArgs(setActor))).WithArgs("alice").WillReturnResult(sqlmock.NewResult(0, 1))
+	mockPG.ExpectPrepare(regexp.QuoteMeta(query)).ExpectExec().WithArgs("my_group", groupNamespace).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockPG.ExpectCommit()
+
+	didDelete, err := dPG.WithActor("alice").DeleteGroup("my_group")
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil || !didDelete {
+		t.Errorf("Expected the group to be deleted, got %v, %v", didDelete, err)
+	}
+}
+
+//
+// Partitions
+//
+
+func TestPgGetPartition(t *testing.T) {
+	columns := compGroupsColsSMPart // "id", "name", "description", "tags"
+
+	dval5 := []driver.Value{uuid5, dgrp5p.Name, dgrp5p.Description, pq.Array(&dgrp5p.Tags)}
+	dval6 := []driver.Value{uuid6, dgrp6p.Name, dgrp6p.Description, pq.Array(&dgrp6p.Tags)}
+
+	memberCols := []string{"component_id"}
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	dgrp5Query, _, _ := sqq.Select(compGroupsColsSMPart...).
+		From(compGroupsTable).
+		Where("name = ?", strings.ToLower(dgrp5p.Name)).
+		Where("namespace = ?", partNamespace).ToSql()
+
+	dgrp5MQuery, _, _ := sqq.Select(compGroupMembersColsUser...).
+		From(compGroupMembersTable).
+		Where("group_id = ?", uuid5).ToSql()
+
+	dgrp6Query, _, _ := sqq.Select(compGroupsColsSMPart...).
+		From(compGroupsTable).
+		Where("name = ?", strings.ToLower(dgrp6p.Name)).
+		Where("namespace = ?", partNamespace).ToSql()
+
+	dgrp6MQuery, _, _ := sqq.Select(compGroupMembersColsUser...).
+		From(compGroupMembersTable).
+		Where("group_id = ?", uuid6).ToSql()
+
+	tests := []struct {
+		name                  string
+		dbColumns             []string
+		dbRows                [][]driver.Value
+		dbQueryError          error
+		expectedQueryPrepare  string
+		expectedQueryArgs     []driver.Value
+		dbMColumns            []string
+		dbMRows               [][]driver.Value
+		dbMQueryError         error
 		expectedMQueryPrepare string
 		expectedMQueryArgs    []driver.Value
 		expPart               *sm.Partition
@@ -6020,6 +7348,81 @@ func TestPgDeletePartitionMember(t *testing.T) {
 	}
 }
 
+func TestPgMovePartitionMembers(t *testing.T) {
+	columns := compGroupsColsSMPart // "id", "name", "description", "tags"
+
+	dval5 := []driver.Value{uuid5, dgrp5p.Name, dgrp5p.Description, pq.Array(&dgrp5p.Tags)}
+	dval6 := []driver.Value{uuid6, dgrp6p.Name, dgrp6p.Description, pq.Array(&dgrp6p.Tags)}
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	partQuery, _, _ := sqq.Select(compGroupsColsSMPart...).
+		From(compGroupsTable).
+		Where("name = ?", strings.ToLower(dgrp5p.Name)).
+		Where("namespace = ?", partNamespace).ToSql()
+	delQuery, _, _ := sqq.Delete(compGroupMembersTable).
+		Where("group_id = ?", uuid5).
+		Where("component_id = ?", "x0c0s1b0n0").ToSql()
+	insQuery, _, _ := sqq.Insert(compGroupMembersTable).
+		Columns(compGroupMembersColsNoTS...).
+		Values("x0c0s1b0n0", sq.Expr("?", uuid6), partGroupNamespace).
+		Values("x0c0s2b0n0", sq.Expr("?", uuid6), partGroupNamespace).ToSql()
+
+	tests := []struct {
+		ids           []string
+		deleted       []int64
+		expectedIDs   []string
+		expectedError error
+	}{{
+		ids:         []string{"X0C0S1B0N0", "x0c0s2b0n0"},
+		deleted:     []int64{1, 1},
+		expectedIDs: []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+	}, {
+		// Nothing is moved if any of them is not in the old partition.
+		ids:           []string{"x0c0s1b0n0", "x0c0s2b0n0"},
+		deleted:       []int64{1, 0},
+		expectedError: ErrHMSDSNoPartitionMember,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		mockPG.ExpectPrepare(regexp.QuoteMeta(partQuery)).ExpectQuery().
+			WithArgs(dgrp5p.Name, partNamespace).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(dval5...))
+		mockPG.ExpectQuery(regexp.QuoteMeta(partQuery)).
+			WithArgs(dgrp6p.Name, partNamespace).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(dval6...))
+		for j, n := range test.deleted {
+			if j == 0 {
+				mockPG.ExpectPrepare(regexp.QuoteMeta(delQuery)).ExpectExec().
+					WithArgs(uuid5, strings.ToLower(test.ids[j])).WillReturnResult(sqlmock.NewResult(0, n))
+			} else {
+				mockPG.ExpectExec(regexp.QuoteMeta(delQuery)).
+					WithArgs(uuid5, strings.ToLower(test.ids[j])).WillReturnResult(sqlmock.NewResult(0, n))
+			}
+		}
+		if test.expectedError == nil {
+			mockPG.ExpectPrepare(regexp.QuoteMeta(insQuery)).ExpectExec().
+				WithArgs("x0c0s1b0n0", uuid6, partGroupNamespace,
+					"x0c0s2b0n0", uuid6, partGroupNamespace).
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mockPG.ExpectCommit()
+		} else {
+			mockPG.ExpectRollback()
+		}
+
+		ids, err := dPG.MovePartitionMembers(dgrp5p.Name, dgrp6p.Name, test.ids)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedError {
+			t.Errorf("Test %v Failed: Expected error '%v'; Received '%v'", i, test.expectedError, err)
+		} else if !reflect.DeepEqual(test.expectedIDs, ids) {
+			t.Errorf("Test %v Failed: Expected ids '%v'; Received '%v'", i, test.expectedIDs, ids)
+		}
+	}
+}
+
 // Note: GetMembership is basically a special case where f contains ID={id}
 func TestPgGetMemberships(t *testing.T) {
 	tests := []struct {
@@ -6474,6 +7877,30 @@ func TestPgInsertCompReservations(t *testing.T) {
 		expectedSuccess:         0,
 		expectedFailure:         0,
 		expectErr:               true,
+	}, {
+		// A locked node conflicts with reserving the blade it is on.
+		f: sm.CompLockV2Filter{
+			ID:                  []string{"x3000c0s9"},
+			IncludeDescendants:  true,
+			ProcessingModel:     sm.CLProcessingModelRigid,
+			ReservationDuration: 1,
+		},
+		dbGetCompIDsError:         nil,
+		expectedGetCompIDsPrepare: regexp.QuoteMeta(tGetCompBaseQuery + " WHERE (c.id SIMILAR TO $1)"),
+		expectedGetCompIDsArgs:    []driver.Value{"x3000c0s9([[:alpha:]][[:alnum:]]*)?"},
+		dbGetCompIDsReturnCols:    []string{"id", "type", "state", "flag", "enabled", "admin", "role", "subrole", "nid", "subtype", "nettype", "arch", "class", "reservation_disabled", "locked"},
+		dbGetCompIDsReturnRows: [][]driver.Value{
+			[]driver.Value{"x3000c0s9", "ComputeModule", "On", "OK", true, "", "", "", nil, "", "", "X86", "Mountain", false, false},
+			[]driver.Value{"x3000c0s9b0n0", "Node", "Ready", "OK", true, "", "Compute", "", 42, "", "Sling", "X86", "Mountain", false, true},
+		},
+		dbInsertError:           nil,
+		expectedInsertPrepare:   "",
+		expectedInsertArgs:      []driver.Value{},
+		dbInsertV2ResReturnCols: []string{},
+		dbInsertV2ResReturnRows: [][]driver.Value{},
+		expectedSuccess:         0,
+		expectedFailure:         0,
+		expectErr:               true,
 	}}
 
 	for i, test := range tests {
@@ -6569,6 +7996,7 @@ func TestPgDeleteCompReservationsForce(t *testing.T) {
 		f: sm.CompLockV2Filter{
 			ID:              []string{"x3000c0s9b0n0"},
 			ProcessingModel: sm.CLProcessingModelRigid,
+			Reason:          "node stuck in firmware update",
 		},
 		dbGetCompIDsError:         nil,
 		expectedGetCompIDsPrepare: regexp.QuoteMeta(tGetCompBaseQuery + " WHERE c.id IN ($1)"),
@@ -6621,6 +8049,9 @@ func TestPgDeleteCompReservationsForce(t *testing.T) {
 		}
 
 		mockPG.ExpectBegin()
+		mockPG.ExpectExec(regexp.QuoteMeta(ToPGQueryArgs(setLockAudit))).
+			WithArgs(sm.CLAuditForceRelease, test.f.Reason).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 		if test.expectedGetCompIDsPrepare == "" && test.dbGetCompIDsError == nil {
 			mockPG.ExpectRollback()
 		} else if test.dbGetCompIDsError != nil {
@@ -6661,6 +8092,88 @@ func TestPgDeleteCompReservationsForce(t *testing.T) {
 	}
 }
 
+func TestPgGetCompLockAudit(t *testing.T) {
+	columns := []string{compLockAuditCompIdCol, compLockAuditActionCol,
+		compLockAuditActorCol, compLockAuditReasonCol, compLockAuditExpireCol,
+		compLockAuditTimestampCol}
+	ts1, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
+	ts2, _ := time.Parse(time.RFC3339, "2024-01-01T00:05:00Z")
+	exp, _ := time.Parse(time.RFC3339, "2024-01-01T00:15:00Z")
+	timeEndArg, _ := time.Parse(time.RFC3339, "2024-01-02T00:00:00Z")
+
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	base := sqq.Select(columns...).
+		From(compLockAuditTable)
+	query1, _, _ := base.OrderBy(compLockAuditSeqCol).ToSql()
+	query2, _, _ := base.
+		Where(sq.Eq{compLockAuditCompIdCol: []string{"x0c0s1b0n0"}}).
+		Where(sq.Eq{compLockAuditActionCol: []string{sm.CLAuditForceRelease}}).
+		Where(sq.Eq{compLockAuditActorCol: "alice"}).
+		Where(sq.LtOrEq{compLockAuditTimestampCol: timeEndArg}).
+		OrderBy(compLockAuditSeqCol).ToSql()
+
+	tests := []struct {
+		f               *CompLockAuditFilter
+		dbRows          [][]driver.Value
+		expectedPrepare string
+		expectedArgs    []driver.Value
+		expectedEntries []*sm.CompLockV2AuditEntry
+		expectedErr     error
+	}{{
+		f: nil,
+		dbRows: [][]driver.Value{
+			{"x0c0s1b0n0", sm.CLAuditReserve, "cfs", "", exp, ts1},
+			{"x0c0s1b0n0", sm.CLAuditForceRelease, "alice", "hung", exp, ts2},
+			{"x0c0s1b0n0", sm.CLAuditLock, "alice", "", nil, ts2},
+		},
+		expectedPrepare: regexp.QuoteMeta(query1),
+		expectedArgs:    []driver.Value{},
+		expectedEntries: []*sm.CompLockV2AuditEntry{
+			{ID: "x0c0s1b0n0", Action: sm.CLAuditReserve, Actor: "cfs", ExpirationTime: "2024-01-01T00:15:00Z", Timestamp: "2024-01-01T00:00:00Z"},
+			{ID: "x0c0s1b0n0", Action: sm.CLAuditForceRelease, Actor: "alice", Reason: "hung", ExpirationTime: "2024-01-01T00:15:00Z", Timestamp: "2024-01-01T00:05:00Z"},
+			{ID: "x0c0s1b0n0", Action: sm.CLAuditLock, Actor: "alice", Timestamp: "2024-01-01T00:05:00Z"},
+		},
+	}, {
+		f: &CompLockAuditFilter{
+			ID:      []string{"X0C0S1B0N0"},
+			Action:  []string{sm.CLAuditForceRelease},
+			Actor:   "alice",
+			EndTime: "2024-01-02T00:00:00Z",
+		},
+		dbRows:          [][]driver.Value{},
+		expectedPrepare: regexp.QuoteMeta(query2),
+		expectedArgs:    []driver.Value{"x0c0s1b0n0", sm.CLAuditForceRelease, "alice", timeEndArg},
+		expectedEntries: []*sm.CompLockV2AuditEntry{},
+	}, {
+		f:           &CompLockAuditFilter{ID: []string{"foo"}},
+		expectedErr: ErrHMSDSArgBadID,
+	}, {
+		f:           &CompLockAuditFilter{StartTime: "yesterday"},
+		expectedErr: ErrHMSDSArgBadTimeFormat,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		if test.expectedErr == nil {
+			rows := sqlmock.NewRows(columns)
+			for _, row := range test.dbRows {
+				rows.AddRow(row...)
+			}
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().WithArgs(test.expectedArgs...).WillReturnRows(rows)
+		}
+
+		entries, err := dPG.GetCompLockAudit(test.f)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Received '%v'", i, test.expectedErr, err)
+		} else if err == nil && !reflect.DeepEqual(test.expectedEntries, entries) {
+			t.Errorf("Test %v Failed: Expected entries '%v'; Received '%v'", i, test.expectedEntries, entries)
+		}
+	}
+}
+
 func TestPgDeleteCompReservations(t *testing.T) {
 	res := compReservation{
 		component_id: "x3000c0s9b0n0",
@@ -6849,6 +8362,79 @@ func TestPgDeleteCompReservationsExpired(t *testing.T) {
 	}
 }
 
+func TestPgGetCompReservationsExpiring(t *testing.T) {
+	sqq := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	resExpiring, _, _ := sqq.Select(compResCompIdCol, compResCreatedCol, compResExpireCol).
+		From(compResTable).
+		Where(compResExpireCol+" IS NOT NULL AND "+compResExpireCol+
+			" <= NOW() + ? * INTERVAL '1 second'", 0).
+		OrderBy(compResExpireCol, compResCompIdCol).ToSql()
+	created := time.Date(2026, 10, 17, 9, 59, 0, 0, time.UTC)
+	expires := created.Add(time.Minute)
+
+	tests := []struct {
+		within       time.Duration
+		dbRows       [][]driver.Value
+		dbError      error
+		expectedArgs []driver.Value
+		expected     []sm.CompLockV2Expiring
+		expectErr    bool
+	}{{
+		within: 90 * time.Second,
+		dbRows: [][]driver.Value{
+			{"x3000c0s9b0n0", created, expires},
+		},
+		expectedArgs: []driver.Value{int64(90)},
+		expected: []sm.CompLockV2Expiring{{
+			ID:             "x3000c0s9b0n0",
+			CreationTime:   "2026-10-17T09:59:00Z",
+			ExpirationTime: "2026-10-17T10:00:00Z",
+		}},
+	}, {
+		within:       0,
+		expectedArgs: []driver.Value{int64(0)},
+		expected:     []sm.CompLockV2Expiring{},
+	}, {
+		within:       time.Minute,
+		dbError:      sql.ErrNoRows,
+		expectedArgs: []driver.Value{int64(60)},
+		expectErr:    true,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		query := mockPG.ExpectPrepare(regexp.QuoteMeta(resExpiring)).ExpectQuery().
+			WithArgs(test.expectedArgs...)
+		if test.dbError != nil {
+			query.WillReturnError(test.dbError)
+			mockPG.ExpectRollback()
+		} else {
+			rows := sqlmock.NewRows([]string{"component_id", "create_timestamp", "expiration_timestamp"})
+			for _, row := range test.dbRows {
+				rows.AddRow(row...)
+			}
+			query.WillReturnRows(rows)
+			mockPG.ExpectCommit()
+		}
+
+		res, err := dPG.GetCompReservationsExpiring(test.within)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s",
+				i, mock_err)
+		}
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error.", i)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+		} else if !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("Test %v Failed: Expected %v, got %v", i, test.expected, res)
+		}
+	}
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 // Power Map Query Tests
 ///////////////////////////////////////////////////////////////////////////////
@@ -7793,3 +9379,606 @@ func TestPgGetJobs(t *testing.T) {
 		}
 	}
 }
+
+func TestPgUpdateAllForRFEndpoint(t *testing.T) {
+	ep := &sm.RedfishEndpoint{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID:   "x0c0s1b0",
+			Type: "NodeBMC",
+		},
+	}
+	epRow := []driver.Value{"x0c0s1b0", "NodeBMC", "", "", "", "", false, "", "", "", false, false, "", "", false, "", "", json.RawMessage(`{}`)}
+	hls := []*sm.HWInvByLoc{{
+		ID:           "x0c0s1b0n0p0",
+		Type:         "Processor",
+		PopulatedFRU: &sm.HWInvByFRU{FRUID: "Processor.Intel.123.456", Type: "Processor"},
+	}}
+
+	tests := []struct {
+		hls           []*sm.HWInvByLoc
+		dbErrorUpdate error
+		dbErrorHist   error
+		expectErr     bool
+	}{{ // Test 0 Endpoint only, nothing else to store
+		hls:           nil,
+		dbErrorUpdate: nil,
+		dbErrorHist:   nil,
+		expectErr:     false,
+	}, { // Test 1 Endpoint update fails
+		hls:           nil,
+		dbErrorUpdate: sql.ErrConnDone,
+		dbErrorHist:   nil,
+		expectErr:     true,
+	}, { // Test 2 HWInv history fails, everything is rolled back
+		hls:           hls,
+		dbErrorUpdate: nil,
+		dbErrorHist:   sql.ErrConnDone,
+		expectErr:     true,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		if test.dbErrorUpdate != nil {
+			mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(updatePgRFEndpointQuery))).ExpectExec().WillReturnError(test.dbErrorUpdate)
+			mockPG.ExpectRollback()
+		} else {
+			mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(updatePgRFEndpointQuery))).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+			mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(getRFEndpointByIDQuery))).ExpectQuery().WithArgs("x0c0s1b0").WillReturnRows(sqlmock.NewRows(rfEPsAllCols).AddRow(epRow...))
+			if test.hls != nil {
+				mockPG.ExpectPrepare("INSERT INTO " + hwInvFruTable).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+				mockPG.ExpectPrepare("INSERT INTO hwinv_by_loc").ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+				mockPG.ExpectPrepare("FROM " + hwInvHistTable).ExpectQuery().WillReturnError(test.dbErrorHist)
+				mockPG.ExpectRollback()
+			} else {
+				mockPG.ExpectCommit()
+			}
+		}
+
+		discovered, err := dPG.UpdateAllForRFEndpoint(ep, nil, test.hls, nil, nil, nil)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("Test %v Failed: Expected an error.", i)
+			}
+		} else if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+		} else if discovered == nil || len(*discovered) != 0 {
+			t.Errorf("Test %v Failed: Expected empty discovered list, got %v", i, discovered)
+		}
+	}
+}
+
+func TestPgUpdateAllForRFEndpointBatches(t *testing.T) {
+	ep := &sm.RedfishEndpoint{
+		RedfishEPDescription: rf.RedfishEPDescription{
+			ID:   "x0c0s1b0",
+			Type: "NodeBMC",
+		},
+	}
+	epRow := []driver.Value{"x0c0s1b0", "NodeBMC", "", "", "", "", false, "", "", "", false, false, "", "", false, "", "", json.RawMessage(`{}`)}
+	batchErr := fmt.Errorf("bad batch")
+
+	tests := []struct {
+		numBatches int
+		failAt     int  // Batch next() fails on, -1 for none
+		noEP       bool // No such endpoint
+		expectErr  error
+	}{{ // Test 0 Several (empty) batches, each committed, then the endpoint
+		numBatches: 3,
+		failAt:     -1,
+	}, { // Test 1 Error producing a later batch, earlier ones are kept and
+		// the endpoint is not updated.
+		numBatches: 3,
+		failAt:     2,
+		expectErr:  batchErr,
+	}, { // Test 2 No such endpoint, nothing is stored
+		numBatches: 3,
+		failAt:     -1,
+		noEP:       true,
+		expectErr:  ErrHMSDSArgNoMatch,
+	}}
+
+	for i, test := range tests {
+		ResetMockDB()
+		rows := sqlmock.NewRows(rfEPsAllCols)
+		if !test.noEP {
+			rows.AddRow(epRow...)
+		}
+		mockPG.ExpectBegin()
+		mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(getRFEndpointByIDQuery))).ExpectQuery().WithArgs("x0c0s1b0").WillReturnRows(rows)
+		mockPG.ExpectCommit()
+		if !test.noEP {
+			for b := 0; b < test.numBatches; b++ {
+				if b == test.failAt {
+					break
+				}
+				mockPG.ExpectBegin()
+				mockPG.ExpectCommit()
+			}
+			if test.failAt < 0 {
+				mockPG.ExpectBegin()
+				mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(updatePgRFEndpointQuery))).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+				mockPG.ExpectPrepare(ToPGQueryArgs(regexp.QuoteMeta(getRFEndpointByIDQuery))).ExpectQuery().WithArgs("x0c0s1b0").WillReturnRows(sqlmock.NewRows(rfEPsAllCols).AddRow(epRow...))
+				mockPG.ExpectCommit()
+			}
+		}
+
+		calls := 0
+		discovered, err := dPG.UpdateAllForRFEndpointBatches(ep,
+			func() (*RFEndpointBatch, error) {
+				defer func() { calls++ }()
+				if calls == test.failAt {
+					return nil, batchErr
+				} else if calls >= test.numBatches {
+					return nil, nil
+				}
+				return &RFEndpointBatch{}, nil
+			})
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectErr {
+			t.Errorf("Test %v Failed: Expected error %v, got %v", i, test.expectErr, err)
+		} else if err == nil && (discovered == nil || len(*discovered) != 0) {
+			t.Errorf("Test %v Failed: Expected empty discovered list, got %v", i, discovered)
+		}
+	}
+}
+
+func TestPgAcquireLease(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`INSERT INTO leases (name,holder,expires)` +
+		` VALUES ($1,$2,now() + $3 * interval '1 second')` +
+		` ON CONFLICT(name) DO UPDATE SET holder = EXCLUDED.holder,` +
+		` expires = EXCLUDED.expires` +
+		` WHERE leases.holder = EXCLUDED.holder OR leases.expires < now()` +
+		` RETURNING holder`)
+	tests := []struct {
+		rows     *sqlmock.Rows
+		expected bool
+	}{
+		{sqlmock.NewRows([]string{"holder"}).AddRow("smd-1"), true},
+		{sqlmock.NewRows([]string{"holder"}), false},
+	}
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().
+			WithArgs("leader", "smd-1", float64(15)).
+			WillReturnRows(test.rows)
+
+		acquired, err := dPG.AcquireLease("leader", "smd-1", 15*time.Second)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %d Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != nil {
+			t.Errorf("Test %d Unexpected error received: %s", i, err)
+		} else if acquired != test.expected {
+			t.Errorf("Test %d Expected acquired %t, got %t", i, test.expected, acquired)
+		}
+	}
+}
+
+func TestPgGetLeases(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`SELECT name, holder, expires` +
+		` FROM leases WHERE name LIKE $1 AND expires > now() ORDER BY name`)
+	expires := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"name", "holder", "expires"}).
+		AddRow("member/smd-1", "smd-1", expires).
+		AddRow("member/smd-2", "smd-2", expires)
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().
+		WithArgs(`member/\_x%`).WillReturnRows(rows)
+
+	leases, err := dPG.GetLeases("member/_x")
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*Lease{
+		{Name: "member/smd-1", Holder: "smd-1", Expires: expires},
+		{Name: "member/smd-2", Holder: "smd-2", Expires: expires},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, leases) {
+		t.Errorf("Expected leases '%v'; Recieved leases '%v'", expected, leases)
+	}
+}
+
+func TestPgGetDiscoverySchedules(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`SELECT schedule FROM discovery_schedules ORDER BY name`)
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"schedule"}).
+		AddRow([]byte(`{"Name":"default","IntervalSeconds":86400}`)).
+		AddRow([]byte(`{"Name":"NodeBMC","IntervalSeconds":3600,"JitterSeconds":60}`))
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().WillReturnRows(rows)
+
+	scheds, err := dPG.GetDiscoverySchedules()
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*sm.DiscoverySchedule{
+		{Name: "default", IntervalSeconds: 86400},
+		{Name: "NodeBMC", IntervalSeconds: 3600, JitterSeconds: 60},
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, scheds) {
+		t.Errorf("Expected schedules '%v'; Recieved schedules '%v'", expected, scheds)
+	}
+}
+
+func TestPgDeleteDiscoverySchedule(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`DELETE FROM discovery_schedules WHERE name = $1`)
+
+	ResetMockDB()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectExec().
+		WithArgs("x3000c0s1b0").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	didDelete, err := dPG.DeleteDiscoverySchedule("x3000c0s1b0")
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !didDelete {
+		t.Errorf("Expected didDelete to be true")
+	}
+}
+
+func TestPgDSNWithParam(t *testing.T) {
+	tests := []struct {
+		dsn      string
+		expected string
+	}{{
+		dsn:      "",
+		expected: "statement_timeout=5000",
+	}, {
+		dsn:      "dbname=hmsds user=hmsdsuser",
+		expected: "dbname=hmsds user=hmsdsuser statement_timeout=5000",
+	}, {
+		dsn:      "postgres://hmsdsuser@localhost/hmsds",
+		expected: "postgres://hmsdsuser@localhost/hmsds?statement_timeout=5000",
+	}, {
+		dsn:      "postgresql://hmsdsuser@localhost/hmsds?sslmode=disable",
+		expected: "postgresql://hmsdsuser@localhost/hmsds?sslmode=disable&statement_timeout=5000",
+	}}
+	for i, test := range tests {
+		dsn := pgDSNWithParam(test.dsn, "statement_timeout", "5000")
+		if dsn != test.expected {
+			t.Errorf("Test %v Failed: Expected '%s'; Recieved '%s'", i, test.expected, dsn)
+		}
+	}
+}
+
+func TestPgPoolConfig(t *testing.T) {
+	d := NewHMSDB_PG("", nil)
+	expected := DBPoolConfig{
+		MaxOpenConns: DefaultDBMaxOpenConns,
+		MaxIdleConns: DefaultDBMaxIdleConns,
+	}
+	if cfg := d.PoolConfig(); cfg != expected {
+		t.Errorf("Expected default pool config %+v; Recieved %+v", expected, cfg)
+	}
+	d.SetPoolConfig(DBPoolConfig{
+		MaxOpenConns:     -1,
+		MaxIdleConns:     -1,
+		ConnMaxLifetime:  time.Hour,
+		StatementTimeout: time.Minute,
+	})
+	expected = DBPoolConfig{
+		ConnMaxLifetime:  time.Hour,
+		StatementTimeout: time.Minute,
+	}
+	if cfg := d.PoolConfig(); cfg != expected {
+		t.Errorf("Expected pool config %+v; Recieved %+v", expected, cfg)
+	}
+}
+
+func TestPgTxStats(t *testing.T) {
+	d := NewHMSDB_PG("", nil).(*hmsdbPg)
+	d.txStats.threshold = time.Second
+	for _, dur := range []time.Duration{
+		10 * time.Millisecond,
+		2 * time.Second,
+		time.Second,
+		500 * time.Millisecond,
+	} {
+		d.txStats.done(dur)
+	}
+	expected := DBTxStats{
+		Transactions:     4,
+		SlowTransactions: 2,
+		SlowTxThreshold:  time.Second,
+		MaxTxDuration:    2 * time.Second,
+	}
+	// Shared with handles from WithActor.
+	if stats := d.WithActor("test").TxStats(); stats != expected {
+		t.Errorf("Expected %+v; Recieved %+v", expected, stats)
+	}
+	// The mock DB handle doesn't keep any.
+	if stats := dPG.TxStats(); stats != (DBTxStats{}) {
+		t.Errorf("Expected no stats; Recieved %+v", stats)
+	}
+}
+
+func TestPgGetTombstones(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`SELECT kind, id, actor, deleted, data - 'password' FROM tombstones WHERE kind = $1 AND id = $2 ORDER BY seq DESC`)
+	deleted := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"kind", "id", "actor", "deleted", "data"}).
+		AddRow("RedfishEndpoint", "x3000c0s1b0", "admin", deleted,
+			[]byte(`{"id":"x3000c0s1b0","enabled":true}`))
+	mockPG.ExpectPrepare(expectedPrepare).ExpectQuery().
+		WithArgs("RedfishEndpoint", "x3000c0s1b0").WillReturnRows(rows)
+
+	tss, err := dPG.GetTombstones(sm.TombstoneRedfishEndpoint, "x3000c0s1b0")
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*sm.Tombstone{{
+		Kind:      "RedfishEndpoint",
+		ID:        "x3000c0s1b0",
+		DeletedBy: "admin",
+		Deleted:   "2026-10-17T12:00:00Z",
+		Data:      []byte(`{"id":"x3000c0s1b0","enabled":true}`),
+	}}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, tss) {
+		t.Errorf("Expected tombstones '%v'; Recieved tombstones '%v'", expected, tss)
+	}
+
+	if _, err := dPG.GetTombstones("Group", ""); err != ErrHMSDSArgBadArg {
+		t.Errorf("Expected ErrHMSDSArgBadArg for bad kind, got %v", err)
+	}
+}
+
+func TestPgRestoreTombstone(t *testing.T) {
+	selectPrepare := regexp.QuoteMeta(`SELECT seq FROM tombstones WHERE id = $1 AND kind = $2 ORDER BY seq DESC LIMIT 1 FOR UPDATE`)
+	restorePrepare := regexp.QuoteMeta(`INSERT INTO components SELECT (jsonb_populate_record(NULL::components, data)).* FROM tombstones WHERE seq = $1`)
+	removePrepare := regexp.QuoteMeta(`DELETE FROM tombstones WHERE seq = $1`)
+
+	tests := []struct {
+		seqs            []int64
+		dbInsertErr     error
+		expectedRestore bool
+		expectedErr     error
+	}{{
+		seqs:            []int64{7},
+		expectedRestore: true,
+	}, {
+		// No tombstone
+		seqs:            []int64{},
+		expectedRestore: false,
+	}, {
+		// Already exists again
+		seqs:        []int64{7},
+		dbInsertErr: &pq.Error{Code: "23505"},
+		expectedErr: ErrHMSDSDuplicateKey,
+	}}
+	for i, test := range tests {
+		ResetMockDB()
+		mockPG.ExpectBegin()
+		rows := sqlmock.NewRows([]string{"seq"})
+		for _, seq := range test.seqs {
+			rows.AddRow(seq)
+		}
+		mockPG.ExpectPrepare(selectPrepare).ExpectQuery().
+			WithArgs("x0c0s0b0n0", "Component").WillReturnRows(rows)
+		if len(test.seqs) == 0 {
+			mockPG.ExpectRollback()
+		} else if test.dbInsertErr != nil {
+			mockPG.ExpectPrepare(restorePrepare).ExpectExec().
+				WithArgs(test.seqs[0]).WillReturnError(test.dbInsertErr)
+			mockPG.ExpectRollback()
+		} else {
+			mockPG.ExpectPrepare(restorePrepare).ExpectExec().
+				WithArgs(test.seqs[0]).WillReturnResult(sqlmock.NewResult(0, 1))
+			mockPG.ExpectPrepare(removePrepare).ExpectExec().
+				WithArgs(test.seqs[0]).WillReturnResult(sqlmock.NewResult(0, 1))
+			mockPG.ExpectCommit()
+		}
+
+		didRestore, err := dPG.RestoreTombstone(sm.TombstoneComponent, "x0c0s0b0n0")
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Recieved '%v'", i, test.expectedErr, err)
+		} else if didRestore != test.expectedRestore {
+			t.Errorf("Test %v Failed: Expected didRestore %v", i, test.expectedRestore)
+		}
+	}
+}
+
+func TestPgPurgeTombstones(t *testing.T) {
+	expectedPrepare := regexp.QuoteMeta(`DELETE FROM tombstones WHERE deleted < $1`)
+	before := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	ResetMockDB()
+	mockPG.ExpectPrepare(expectedPrepare).ExpectExec().
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	num, err := dPG.PurgeTombstones(before)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if num != 3 {
+		t.Errorf("Expected 3 purged, got %d", num)
+	}
+}
+
+func TestPgUpdateCompHeartbeats(t *testing.T) {
+	upsertPrepare := regexp.QuoteMeta(`INSERT INTO comp_heartbeats (id,last_seen) SELECT id, NOW() FROM components WHERE id = ANY($1) ON CONFLICT (id) DO UPDATE SET last_seen = EXCLUDED.last_seen`)
+	selectPrepare := regexp.QuoteMeta(`SELECT h.id, c.state, c.flag, h.last_seen, EXTRACT(EPOCH FROM NOW() - h.last_seen) FROM comp_heartbeats h JOIN components c ON c.id = h.id WHERE h.id = ANY($1) ORDER BY h.last_seen`)
+	lastSeen := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	ids := `{"x0c0s0b0n0","x0c0s9b0n0"}`
+
+	ResetMockDB()
+	mockPG.ExpectBegin()
+	mockPG.ExpectPrepare(upsertPrepare).ExpectExec().
+		WithArgs(ids).WillReturnResult(sqlmock.NewResult(0, 1))
+	rows := sqlmock.NewRows([]string{"id", "state", "flag", "last_seen", "age"}).
+		AddRow("x0c0s0b0n0", "Standby", "Alert", lastSeen, 0.0)
+	mockPG.ExpectPrepare(selectPrepare).ExpectQuery().
+		WithArgs(ids).WillReturnRows(rows)
+	mockPG.ExpectCommit()
+
+	hbs, err := dPG.UpdateCompHeartbeats([]string{"x0c0s0b0n0", "x0c0s09b0n0"})
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := []*sm.CompHeartbeat{{
+		ID:       "x0c0s0b0n0",
+		State:    "Standby",
+		Flag:     "Alert",
+		LastSeen: "2026-10-17T12:00:00Z",
+	}}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, hbs) {
+		t.Errorf("Expected heartbeats '%v'; Recieved '%v'", expected, hbs)
+	}
+}
+
+func TestPgGetCompHeartbeats(t *testing.T) {
+	lastSeen := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		f               *CompHeartbeatFilter
+		expectedPrepare string
+		expectedArgs    []driver.Value
+		expectedErr     error
+	}{{
+		f:               nil,
+		expectedPrepare: regexp.QuoteMeta(`SELECT h.id, c.state, c.flag, h.last_seen, EXTRACT(EPOCH FROM NOW() - h.last_seen) FROM comp_heartbeats h JOIN components c ON c.id = h.id ORDER BY h.last_seen`),
+		expectedArgs:    []driver.Value{},
+	}, {
+		f: &CompHeartbeatFilter{
+			State: []string{"ready"},
+			Age:   30 * time.Second,
+		},
+		expectedPrepare: regexp.QuoteMeta(`SELECT h.id, c.state, c.flag, h.last_seen, EXTRACT(EPOCH FROM NOW() - h.last_seen) FROM comp_heartbeats h JOIN components c ON c.id = h.id WHERE c.state IN ($1) AND h.last_seen <= NOW() - make_interval(secs => $2) ORDER BY h.last_seen`),
+		expectedArgs:    []driver.Value{"Ready", 30.0},
+	}, {
+		f:           &CompHeartbeatFilter{State: []string{"foo"}},
+		expectedErr: ErrHMSDSArgBadState,
+	}}
+	for i, test := range tests {
+		ResetMockDB()
+		if test.expectedErr == nil {
+			rows := sqlmock.NewRows([]string{"id", "state", "flag", "last_seen", "age"}).
+				AddRow("x0c0s0b0n0", "Ready", "OK", lastSeen, 42.5)
+			mockPG.ExpectPrepare(test.expectedPrepare).ExpectQuery().
+				WithArgs(test.expectedArgs...).WillReturnRows(rows)
+		}
+
+		hbs, err := dPG.GetCompHeartbeats(test.f)
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != test.expectedErr {
+			t.Errorf("Test %v Failed: Expected error '%v'; Recieved '%v'", i, test.expectedErr, err)
+		} else if err == nil && (len(hbs) != 1 || hbs[0].Age != 42.5 ||
+			hbs[0].LastSeen != "2026-10-17T12:00:00Z") {
+			t.Errorf("Test %v Failed: Unexpected heartbeats '%v'", i, hbs)
+		}
+	}
+}
+
+func TestPgGetNIDPolicies(t *testing.T) {
+	ResetMockDB()
+	rows := sqlmock.NewRows([]string{"policy"}).
+		AddRow([]byte(`{"Name":"a","Type":"Geometry","Priority":2,"Start":0}`)).
+		AddRow([]byte(`{"Name":"b","Type":"Reserved","Priority":1,"Start":1,"End":9}`)).
+		AddRow([]byte(`{"Name":"c","Type":"Sequential","Priority":2,"Start":10,"End":99}`))
+	mockPG.ExpectPrepare(regexp.QuoteMeta(`SELECT policy FROM nid_policies ORDER BY name`)).
+		ExpectQuery().WillReturnRows(rows)
+
+	policies, err := dPG.GetNIDPolicies()
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error received: %s", err)
+	}
+	names := []string{}
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual([]string{"b", "a", "c"}, names) {
+		t.Errorf("Expected policies by priority then name, got %v", names)
+	}
+}
+
+func TestPgUpsertNIDPolicy(t *testing.T) {
+	p := &sm.NIDPolicy{Name: "a", Type: sm.NIDPolicySequential, Start: 1, End: 9}
+	policyJSON, _ := json.Marshal(p)
+
+	ResetMockDB()
+	mockPG.ExpectPrepare(regexp.QuoteMeta(`INSERT INTO nid_policies (name,policy) VALUES ($1,$2) ON CONFLICT(name) DO UPDATE SET policy = EXCLUDED.policy`)).
+		ExpectExec().WithArgs("a", policyJSON).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := dPG.UpsertNIDPolicy(p)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	}
+}
+
+func TestPgInsertNIDAssignment(t *testing.T) {
+	insertPrepare := regexp.QuoteMeta(`INSERT INTO nid_assignments (id,nid,policy) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)
+	for i, numRows := range []int64{1, 0} {
+		ResetMockDB()
+		mockPG.ExpectPrepare(insertPrepare).ExpectExec().
+			WithArgs("x0c0s0b0n0", int64(5), "seq").
+			WillReturnResult(sqlmock.NewResult(0, numRows))
+
+		didInsert, err := dPG.InsertNIDAssignment(&sm.NIDAssignment{
+			ID:     "x0c0s00b0n0",
+			NID:    5,
+			Policy: "seq",
+		})
+		if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+			t.Errorf("Test %v Failed: Sql expectations were not met: %s", i, mock_err)
+		}
+		if err != nil {
+			t.Errorf("Test %v Failed: Unexpected error received: %s", i, err)
+		} else if didInsert != (numRows > 0) {
+			t.Errorf("Test %v Failed: Expected didInsert %v, got %v", i,
+				numRows > 0, didInsert)
+		}
+	}
+}
+
+func TestPgGetNIDsInUse(t *testing.T) {
+	ResetMockDB()
+	for _, table := range []string{"components", "node_nid_mapping", "nid_assignments"} {
+		rows := sqlmock.NewRows([]string{"nid", "id"})
+		if table != "node_nid_mapping" {
+			rows.AddRow(int64(3), "x0c0s0b0n0")
+		}
+		mockPG.ExpectPrepare(regexp.QuoteMeta(`SELECT nid, id FROM `+table+` WHERE nid >= $1 AND nid <= $2`)).
+			ExpectQuery().WithArgs(int64(1), int64(9)).WillReturnRows(rows)
+	}
+
+	inUse, err := dPG.GetNIDsInUse(1, 9)
+	if mock_err := mockPG.ExpectationsWereMet(); mock_err != nil {
+		t.Errorf("Sql expectations were not met: %s", mock_err)
+	}
+	expected := map[int64][]string{3: {"x0c0s0b0n0", "x0c0s0b0n0"}}
+	if err != nil {
+		t.Errorf("Unexpected error received: %s", err)
+	} else if !reflect.DeepEqual(expected, inUse) {
+		t.Errorf("Expected '%v'; Recieved '%v'", expected, inUse)
+	}
+}
@@ -55,6 +55,7 @@ type hmsdbPgTx struct {
 	stmt  *sql.Stmt
 	sc    *sq.StmtCache
 	query string
+	start time.Time // For TxStats()
 }
 
 // This should only be called by hdb.Begin()
@@ -75,6 +76,14 @@ func newHMSDBPgTx(hdb *hmsdbPg) (HMSDBTx, error) {
 		return nil, err
 	}
 	t.sc = sq.NewStmtCache(t.tx)
+	t.start = time.Now()
+	if hdb.actor != "" {
+		_, err = t.tx.ExecContext(t.ctx, ToPGQueryArgs(setActor), hdb.actor)
+		if err != nil {
+			t.tx.Rollback()
+			return nil, err
+		}
+	}
 	return t, nil
 }
 
@@ -146,6 +155,86 @@ func (t *hmsdbPgTx) getRowsForQuery(qname, query string, args ...interface{}) (*
 	return rows, err
 }
 
+// Most rows put in one multi-row INSERT by bulkInsert().  Postgres allows
+// at most pgMaxBindParams arguments per statement, so large batches must be
+// split up.  Since every full chunk is the same statement, the statement
+// cache only has to prepare it once per transaction.
+const bulkInsertMaxRows = 1000
+
+// Postgres limit on the number of bind parameters in a single statement.
+const pgMaxBindParams = 65535
+
+// Split rows into chunks that each fit in one multi-row INSERT with ncols
+// columns per row.
+func bulkInsertChunks(rows [][]interface{}, ncols int) [][][]interface{} {
+	max := bulkInsertMaxRows
+	if ncols > 0 && max*ncols > pgMaxBindParams {
+		max = pgMaxBindParams / ncols
+	}
+	chunks := make([][][]interface{}, 0, (len(rows)+max-1)/max)
+	for len(rows) > max {
+		chunks = append(chunks, rows[:max])
+		rows = rows[max:]
+	}
+	if len(rows) > 0 {
+		chunks = append(chunks, rows)
+	}
+	return chunks
+}
+
+// Insert rows (each with a value for every one of cols) into table using
+// as few multi-row INSERT statements as the bind parameter limit allows.
+// suffix, e.g. an ON CONFLICT clause, is added to each statement.  If scan
+// is not nil the statements are run as queries and scan is called for each
+// row they return.  Statements go through the statement cache, so chunks
+// of the same size are only prepared once per transaction.
+func (t *hmsdbPgTx) bulkInsert(
+	fname, table string,
+	cols []string,
+	rows [][]interface{},
+	suffix string,
+	scan func(*sql.Rows) error,
+) error {
+	for _, chunk := range bulkInsertChunks(rows, len(cols)) {
+		query := sq.Insert(table).
+			Columns(cols...)
+		for _, row := range chunk {
+			query = query.Values(row...)
+		}
+		if suffix != "" {
+			query = query.Suffix(suffix)
+		}
+		query = query.PlaceholderFormat(sq.Dollar)
+		qStr, qArgs, _ := query.ToSql()
+		t.Log(LOG_DEBUG, "Debug: %s(): Query: %s - With args: %v", fname, qStr, qArgs)
+		if scan == nil {
+			res, err := query.RunWith(t.sc).ExecContext(t.ctx)
+			if err != nil {
+				t.LogAlways("Error: %s(): ExecContext: %s", fname, err)
+				return err
+			}
+			t.Log(LOG_INFO, "Info: %s() - %s", fname, res)
+			continue
+		}
+		qrows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+		if err != nil {
+			t.LogAlways("Error: %s(): QueryContext: %s", fname, err)
+			return err
+		}
+		for qrows.Next() {
+			if err := scan(qrows); err != nil {
+				qrows.Close()
+				return err
+			}
+		}
+		qrows.Close()
+		if err := qrows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //
 // Logging
 //
@@ -201,6 +290,7 @@ func (t *hmsdbPgTx) Rollback() error {
 			t.LogAlways("Warning: Rollback(): Failed to close old stmt: %s", err)
 		}
 	}
+	t.done()
 	return t.tx.Rollback()
 }
 
@@ -214,9 +304,21 @@ func (t *hmsdbPgTx) Commit() error {
 			t.LogAlways("Warning: Commit(): Failed to close old stmt: %s", err)
 		}
 	}
+	t.done()
 	return t.tx.Commit()
 }
 
+// Count the transaction for TxStats(), warning if it was slow.
+func (t *hmsdbPgTx) done() {
+	dur := time.Since(t.start)
+	if t.hdb.txStats.done(dur) {
+		// Call depth of 4 - report whoever called Commit() or Rollback().
+		t.hdb.lg.Output(4, fmt.Sprintf(
+			"Warning: Slow transaction took %s (threshold %s)",
+			dur, t.hdb.txStats.threshold))
+	}
+}
+
 // Checks to see if parent connection pool is still healthy.
 func (t *hmsdbPgTx) IsConnected() bool {
 	return t.hdb.connected
@@ -569,6 +671,80 @@ func (t *hmsdbPgTx) GetComponentsFilterTx(f *ComponentFilter, fieldFltr FieldFil
 	return comps, nil
 }
 
+// Get some or all HMS Components as they were at time asOf (RFC3339),
+// from the component state history (in transaction).
+func (t *hmsdbPgTx) GetComponentsAsOfTx(f *ComponentFilter, fieldFltr FieldFilter, asOf string) ([]*base.Component, error) {
+	label := "GetComponentsAsOfTx"
+
+	ts, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return nil, ErrHMSDSArgBadTimeFormat
+	}
+	query, err := selectComponentsAsOf(f, fieldFltr, ts)
+	if err != nil {
+		t.LogAlways("Error: %s(): selectComponentsAsOf failed: %s", label, err)
+		return nil, err
+	}
+	return t.sqQueryComponent(query, label, fieldFltr)
+}
+
+// Count the HMS Components in system by type and state (in transaction).
+func (t *hmsdbPgTx) GetComponentCountsTx() ([]*ComponentCount, error) {
+	if !t.IsConnected() {
+		return nil, ErrHMSDSPtrClosed
+	}
+	query := sq.Select(compTypeCol, compStateCol, "COUNT(*)").
+		From(compTable).
+		GroupBy(compTypeCol, compStateCol).
+		OrderBy(compTypeCol, compStateCol).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		t.LogAlways("Error: GetComponentCountsTx(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]*ComponentCount, 0, 1)
+	for rows.Next() {
+		c := new(ComponentCount)
+		if err := rows.Scan(&c.Type, &c.State, &c.Count); err != nil {
+			t.LogAlways("Error: GetComponentCountsTx(): scan failed: %s", err)
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// Get the current change counters of the components, groups and hardware
+// inventory collections (in transaction).
+func (t *hmsdbPgTx) GetCollectionVersionsTx() (*CollectionVersions, error) {
+	if !t.IsConnected() {
+		return nil, ErrHMSDSPtrClosed
+	}
+	query := sq.Select(collVersCompsCol, collVersGroupsCol, collVersHWInvCol).
+		From(collVersTable).
+		Where(sq.Eq{collVersIdCol: 0}).
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		t.LogAlways("Error: GetCollectionVersionsTx(): query failed: %s", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	vers := new(CollectionVersions)
+	if rows.Next() {
+		err := rows.Scan(&vers.Components, &vers.Groups, &vers.HWInv)
+		if err != nil {
+			t.LogAlways("Error: GetCollectionVersionsTx(): scan failed: %s", err)
+			return nil, err
+		}
+	}
+	return vers, rows.Err()
+}
+
 // Get some or all HMS Components in system (in transaction) under
 // a set of parent components, with filtering options to possibly
 // narrow the returned values. If no filter provided, just get
@@ -749,10 +925,8 @@ func (t *hmsdbPgTx) InsertComponentsTx(comps []*base.Component) ([]string, error
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(compTable).
-		Columns(compColsDefault...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(comps))
 	for _, c := range comps {
 		// Normalize key
 		var normID = xnametypes.NormalizeHMSCompID(c.ID)
@@ -779,7 +953,7 @@ func (t *hmsdbPgTx) InsertComponentsTx(comps []*base.Component) ([]string, error
 		}
 
 		// Set fields for the INSERT
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			normID,
 			c.Type,
 			c.State,
@@ -794,34 +968,29 @@ func (t *hmsdbPgTx) InsertComponentsTx(comps []*base.Component) ([]string, error
 			c.Arch,
 			c.Class,
 			c.ReservationDisabled,
-			c.Locked)
+			c.Locked})
 	}
-	query = query.Suffix("ON CONFLICT(" + compIdCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + compIdCol + ") DO UPDATE SET " +
 		compStateCol + " = EXCLUDED." + compStateCol + ", " +
 		compFlagCol + " = EXCLUDED." + compFlagCol + ", " +
 		compSubTypeCol + " = EXCLUDED." + compSubTypeCol + ", " +
 		compNetTypeCol + " = EXCLUDED." + compNetTypeCol + ", " +
 		compArchCol + " = EXCLUDED." + compArchCol + ", " +
 		compClassCol + " = EXCLUDED." + compClassCol +
-		" RETURNING " + compIdCol)
+		" RETURNING " + compIdCol
 
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: InsertComponentsTx(): Query: %s - With args: %v", qStr, qArgs)
-	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	err := t.bulkInsert("InsertComponentsTx", compTable, compColsDefault,
+		rows, suffix, func(r *sql.Rows) error {
+			var id string
+			if err := r.Scan(&id); err != nil {
+				return err
+			}
+			results = append(results, id)
+			return nil
+		})
 	if err != nil {
-		t.LogAlways("Error: InsertComponentsTx(): QueryContext: %s", err)
 		return []string{}, err
 	}
-	defer rows.Close()
-	for rows.Next() {
-		var id string
-		err := rows.Scan(&id)
-		if err != nil {
-			return []string{}, err
-		}
-		results = append(results, id)
-	}
 	return results, nil
 }
 
@@ -1462,6 +1631,60 @@ func (t *hmsdbPgTx) BulkUpdateCompNIDTx(comps []base.Component) error {
 	return nil
 }
 
+// Overwrite the writable fields of an existing component, i.e. all but
+// ID, Type, Locked and ReservationDisabled.  If NID is not set or negative,
+// it is set to -1 which effectively unsets it and suppresses its output.
+// Returns the number of affected rows. < 0 means RowsAffected() is not supported.
+func (t *hmsdbPgTx) UpdateComponentTx(c *base.Component) (int64, error) {
+	if c == nil {
+		t.LogAlways("Error: UpdateComponentTx(): Component was nil.")
+		return 0, ErrHMSDSArgNil
+	}
+	if !t.IsConnected() {
+		return 0, ErrHMSDSPtrClosed
+	}
+	var rawNID int64
+	if num, err := c.NID.Int64(); err != nil || num < -1 {
+		rawNID = -1
+	} else {
+		rawNID = num
+	}
+	// Default to enabled.
+	enabledFlg := true
+	if c.Enabled != nil {
+		enabledFlg = *c.Enabled
+	}
+	query := sq.Update(compTable).
+		Set(compStateCol, c.State).
+		Set(compFlagCol, c.Flag).
+		Set(compEnabledCol, enabledFlg).
+		Set(compSwStatusCol, c.SwStatus).
+		Set(compRoleCol, c.Role).
+		Set(compSubRoleCol, c.SubRole).
+		Set(compNIDCol, rawNID).
+		Set(compSubTypeCol, c.Subtype).
+		Set(compNetTypeCol, c.NetType).
+		Set(compArchCol, c.Arch).
+		Set(compClassCol, c.Class).
+		Where(sq.Eq{compIdCol: xnametypes.NormalizeHMSCompID(c.ID)}).
+		PlaceholderFormat(sq.Dollar)
+
+	qStr, qArgs, _ := query.ToSql()
+	t.Log(LOG_DEBUG, "Debug: UpdateComponentTx(): Query: %s - With args: %v", qStr, qArgs)
+	result, err := query.RunWith(t.sc).ExecContext(t.ctx)
+	if err != nil {
+		t.LogAlways("Error: UpdateComponentTx(): ExecContext: %s", err)
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		// This likely means that RowsAffected() is unsupported.
+		// Default to reporting that an update happened by returning non-zero.
+		return -1, nil
+	}
+	return rowsAffected, nil
+}
+
 // Delete HMS Component with matching xname id from database, if it
 // exists (in transaction)
 // Return true if there was a row affected, false if there were zero.
@@ -2088,10 +2311,8 @@ func (t *hmsdbPgTx) BulkInsertHWInvByLocTx(hls []*sm.HWInvByLoc) error {
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(hwInvLocTable).
-		Columns(hwInvLocCols...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(hls))
 	for _, hl := range hls {
 		// Normalize key
 		normID := xnametypes.NormalizeHMSCompID(hl.ID)
@@ -2139,32 +2360,24 @@ func (t *hmsdbPgTx) BulkInsertHWInvByLocTx(hls []*sm.HWInvByLoc) error {
 		}
 
 		// Set fields for the INSERT
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			normID,
 			hl.Type,
 			hl.Ordinal,
 			hl.Status,
 			pnID,
 			infoJSON,
-			fruId)
+			fruId})
 	}
-	query = query.Suffix("ON CONFLICT(" + hwInvLocIdCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + hwInvLocIdCol + ") DO UPDATE SET " +
 		hwInvLocOrdCol + " = EXCLUDED." + hwInvLocOrdCol + ", " +
 		hwInvLocStatusCol + " = EXCLUDED." + hwInvLocStatusCol + ", " +
 		hwInvLocNodeCol + " = EXCLUDED." + hwInvLocNodeCol + ", " +
 		hwInvLocLocInfoCol + " = EXCLUDED." + hwInvLocLocInfoCol + ", " +
-		hwInvLocFruIdCol + " = EXCLUDED." + hwInvLocFruIdCol)
+		hwInvLocFruIdCol + " = EXCLUDED." + hwInvLocFruIdCol
 
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: BulkInsertHWInvByLocTx(): Query: %s - With args: %v", qStr, qArgs)
-	res, err := query.RunWith(t.sc).ExecContext(t.ctx)
-	if err != nil {
-		t.LogAlways("Error: BulkInsertHWInvByLocTx(): ExecContext: %s", err)
-		return err
-	}
-	t.Log(LOG_INFO, "Info: BulkInsertHWInvByLocTx() - %s", res)
-	return nil
+	return t.bulkInsert("BulkInsertHWInvByLocTx", hwInvLocTable,
+		hwInvLocCols, rows, suffix, nil)
 }
 
 // Insert or update HWInventoryByFRU struct (in transaction)
@@ -2186,11 +2399,15 @@ func (t *hmsdbPgTx) InsertHWInvByFRUTx(hf *sm.HWInvByFRU) error {
 		t.LogAlways("Error: InsertHWInvByFRUTx(): EncodeLocationInfo: %s", err)
 		return err
 	}
+	ids := decodeFRUIdentity(infoJSON)
 	// Perform insert
 	res, err := stmt.ExecContext(t.ctx,
 		&hf.FRUID,
 		&hf.Type,
 		&hf.Subtype,
+		&ids.SerialNumber,
+		&ids.PartNumber,
+		&ids.Manufacturer,
 		&infoJSON)
 	if err != nil {
 		t.LogAlways("Error: InsertHWInvByFRUTx(): stmt.Exec: %s", err)
@@ -2200,6 +2417,22 @@ func (t *hmsdbPgTx) InsertHWInvByFRUTx(hf *sm.HWInvByFRU) error {
 	return nil
 }
 
+// The identifying fields every kind of FRU info has, which are also kept
+// in their own (indexed) columns so FRUs can be looked up by them.
+type fruIdentity struct {
+	SerialNumber string `json:"SerialNumber"`
+	PartNumber   string `json:"PartNumber"`
+	Manufacturer string `json:"Manufacturer"`
+}
+
+// Get the identifying fields from encoded FRU info.  Info without them
+// (or that isn't an object at all) gives empty strings.
+func decodeFRUIdentity(infoJSON []byte) fruIdentity {
+	var ids fruIdentity
+	json.Unmarshal(infoJSON, &ids)
+	return ids
+}
+
 // Insert or update HWInventoryByFRU structs (in transaction)
 func (t *hmsdbPgTx) BulkInsertHWInvByFRUTx(hfs []*sm.HWInvByFRU) error {
 	if len(hfs) == 0 {
@@ -2210,10 +2443,8 @@ func (t *hmsdbPgTx) BulkInsertHWInvByFRUTx(hfs []*sm.HWInvByFRU) error {
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(hwInvFruTable).
-		Columns(hwInvFruTblCols...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(hfs))
 	for _, hf := range hfs {
 		// Take out duplicates so that we don't get errors for modifying a row multiple times.
 		if _, ok := valueMap[hf.FRUID]; ok {
@@ -2228,27 +2459,27 @@ func (t *hmsdbPgTx) BulkInsertHWInvByFRUTx(hfs []*sm.HWInvByFRU) error {
 			return err
 		}
 
+		ids := decodeFRUIdentity(infoJSON)
+
 		// Set fields for the INSERT
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			hf.FRUID,
 			hf.Type,
 			hf.Subtype,
-			infoJSON)
+			ids.SerialNumber,
+			ids.PartNumber,
+			ids.Manufacturer,
+			infoJSON})
 	}
-	query = query.Suffix("ON CONFLICT(" + hwInvFruTblIdCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + hwInvFruTblIdCol + ") DO UPDATE SET " +
 		hwInvFruTblSubTypeCol + " = EXCLUDED." + hwInvFruTblSubTypeCol + ", " +
-		hwInvFruTblInfoCol + " = EXCLUDED." + hwInvFruTblInfoCol)
+		hwInvFruTblSerialCol + " = EXCLUDED." + hwInvFruTblSerialCol + ", " +
+		hwInvFruTblPartCol + " = EXCLUDED." + hwInvFruTblPartCol + ", " +
+		hwInvFruTblManufacturerCol + " = EXCLUDED." + hwInvFruTblManufacturerCol + ", " +
+		hwInvFruTblInfoCol + " = EXCLUDED." + hwInvFruTblInfoCol
 
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: BulkInsertHWInvByFRUTx(): Query: %s - With args: %v", qStr, qArgs)
-	res, err := query.RunWith(t.sc).ExecContext(t.ctx)
-	if err != nil {
-		t.LogAlways("Error: BulkInsertHWInvByFRUTx(): ExecContext: %s", err)
-		return err
-	}
-	t.Log(LOG_INFO, "Info: BulkInsertHWInvByFRUTx() - %s", res)
-	return nil
+	return t.bulkInsert("BulkInsertHWInvByFRUTx", hwInvFruTable,
+		hwInvFruTblColsAll, rows, suffix, nil)
 }
 
 // Delete HWInvByLoc entry with matching FRU ID from database, if it
@@ -2573,16 +2804,13 @@ func (t *hmsdbPgTx) InsertHWInvHistTx(hh *sm.HWInvHist) error {
 // Insert an array of HWInventoryHistory entries. (in transaction)
 // If a duplicate is present return an error.
 func (t *hmsdbPgTx) InsertHWInvHistsTx(hhs []*sm.HWInvHist) error {
-	var err error
 	if len(hhs) == 0 {
 		// Nothing to do
 		return nil
 	}
 
-	// Generate query
-	query := sq.Insert(hwInvHistTable).
-		Columns(hwInvHistColsNoTS...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(hhs))
 	for _, hh := range hhs {
 		// Normalize and verify fields (note these functions track if this
 		// has been done and only does each once.)
@@ -2597,15 +2825,163 @@ func (t *hmsdbPgTx) InsertHWInvHistsTx(hhs []*sm.HWInvHist) error {
 		if hh.FruId == "" {
 			return ErrHMSDSArgMissing
 		}
-		query = query.Values(loc, hh.FruId, eventType)
+		rows = append(rows, []interface{}{loc, hh.FruId, eventType})
 	}
 
-	// Exec with statement cache for caching prepared statements (local to tx)
+	err := t.bulkInsert("InsertHWInvHistsTx", hwInvHistTable,
+		hwInvHistColsNoTS, rows, "", nil)
+	return ParsePgDBError(err)
+}
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// HMSDBTx Interface - HWInvSightings queries
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Get the FRU sightings matching the filter, ordered by FRU ID and then by
+// when they were first seen.  Only ID, FruId, StartTime and EndTime are
+// used; a sighting matches the time range if it overlaps it.
+// (in transaction)
+func (t *hmsdbPgTx) GetHWInvSightingsFilterTx(f_opts ...HWInvHistFiltFunc) ([]*sm.HWInvSighting, error) {
+	// Parse the filter options
+	f := new(HWInvHistFilter)
+	for _, opts := range f_opts {
+		opts(f)
+	}
+
+	query := sq.Select(addAliasToCols(hwInvSightingsAlias, hwInvSightingsCols, hwInvSightingsCols)...).
+		From(hwInvSightingsTable + " " + hwInvSightingsAlias)
+	if len(f.ID) > 0 {
+		query = query.Where(sq.Eq{hwInvSightingsIdColAlias: f.ID})
+	}
+	if len(f.FruId) > 0 {
+		query = query.Where(sq.Eq{hwInvSightingsFruIdColAlias: f.FruId})
+	}
+	if f.StartTime != "" {
+		start, err := time.Parse(time.RFC3339, f.StartTime)
+		if err != nil {
+			return nil, ErrHMSDSArgBadTimeFormat
+		}
+		query = query.Where(sq.GtOrEq{hwInvSightingsLastSeenColAlias: start})
+	}
+	if f.EndTime != "" {
+		end, err := time.Parse(time.RFC3339, f.EndTime)
+		if err != nil {
+			return nil, ErrHMSDSArgBadTimeFormat
+		}
+		query = query.Where(sq.LtOrEq{hwInvSightingsFirstSeenColAlias: end})
+	}
+	query = query.OrderBy(hwInvSightingsFruIdColAlias + ", " + hwInvSightingsFirstSeenColAlias + " ASC")
+
+	// Execute
 	query = query.PlaceholderFormat(sq.Dollar)
 	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: GetHWInvHistLastEventsTx(): Query: %s - With args: %v", qStr, qArgs)
-	_, err = query.RunWith(t.sc).ExecContext(t.ctx)
-	return ParsePgDBError(err)
+	t.Log(LOG_DEBUG, "Debug: GetHWInvSightingsFilterTx(): Query: %s - With args: %v", qStr, qArgs)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sightings := make([]*sm.HWInvSighting, 0, 1)
+	for rows.Next() {
+		sighting, err := t.hdb.scanHwInvSighting(rows)
+		if err != nil {
+			t.LogAlways("Error: GetHWInvSightingsFilterTx(): Scan failed: %s", err)
+			return sightings, err
+		}
+		sightings = append(sightings, sighting)
+	}
+	err = rows.Err()
+	t.Log(LOG_INFO, "Info: GetHWInvSightingsFilterTx() returned %d sightings.", len(sightings))
+	return sightings, err
+}
+
+// Record that the FRUs populating hwlocs were seen at those locations now.
+// A FRU still at the location of its latest sighting has that sighting's
+// last seen time updated, otherwise a new sighting is started.
+// (in transaction)
+func (t *hmsdbPgTx) RecordHWInvSightingsTx(hwlocs []*sm.HWInvByLoc) error {
+	fruIDs := make([]string, 0, len(hwlocs))
+	for _, hwloc := range hwlocs {
+		if hwloc != nil && hwloc.PopulatedFRU != nil && hwloc.PopulatedFRU.FRUID != "" {
+			fruIDs = append(fruIDs, hwloc.PopulatedFRU.FRUID)
+		}
+	}
+	if len(fruIDs) == 0 {
+		// Nothing to do
+		return nil
+	}
+
+	// Get the latest sighting of each FRU
+	query := sq.Select(addAliasToCols(hwInvSightingsAlias, hwInvSightingsCols, hwInvSightingsCols)...).
+		From(hwInvSightingsTable+" "+hwInvSightingsAlias).
+		Options("DISTINCT ON (", hwInvSightingsFruIdColAlias, ")").
+		Where(sq.Eq{hwInvSightingsFruIdColAlias: fruIDs}).
+		OrderBy(hwInvSightingsFruIdColAlias + ", " + hwInvSightingsFirstSeenColAlias + " DESC").
+		PlaceholderFormat(sq.Dollar)
+	qStr, qArgs, _ := query.ToSql()
+	t.Log(LOG_DEBUG, "Debug: RecordHWInvSightingsTx(): Query: %s - With args: %v", qStr, qArgs)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		return err
+	}
+	lastSightings := make([]*sm.HWInvSighting, 0, len(fruIDs))
+	for rows.Next() {
+		sighting, err := t.hdb.scanHwInvSighting(rows)
+		if err != nil {
+			t.LogAlways("Error: RecordHWInvSightingsTx(): Scan failed: %s", err)
+			rows.Close()
+			return err
+		}
+		lastSightings = append(lastSightings, sighting)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	same, moved := sm.NewHWInvSightings(hwlocs, lastSightings)
+
+	// Still where they were last seen - extend the latest sighting.
+	if len(same) > 0 {
+		sameIDs := make([]string, 0, len(same))
+		for _, sighting := range same {
+			sameIDs = append(sameIDs, sighting.FruId)
+		}
+		update := sq.Update(hwInvSightingsTable).
+			Set(hwInvSightingsLastSeenCol, sq.Expr("NOW()")).
+			Where(sq.Eq{hwInvSightingsFruIdCol: sameIDs}).
+			Where(hwInvSightingsFirstSeenCol + " = (SELECT MAX(l." +
+				hwInvSightingsFirstSeenCol + ") FROM " + hwInvSightingsTable +
+				" l WHERE l." + hwInvSightingsFruIdCol + " = " +
+				hwInvSightingsTable + "." + hwInvSightingsFruIdCol + ")").
+			PlaceholderFormat(sq.Dollar)
+		_, err = update.RunWith(t.sc).ExecContext(t.ctx)
+		if err != nil {
+			return ParsePgDBError(err)
+		}
+	}
+	// New or moved - start a new sighting.
+	if len(moved) > 0 {
+		rows := make([][]interface{}, 0, len(moved))
+		for _, sighting := range moved {
+			rows = append(rows, []interface{}{sighting.ID, sighting.FruId,
+				sq.Expr("NOW()"), sq.Expr("NOW()")})
+		}
+		// NOW() is the start of the transaction, so a FRU seen at a second
+		// location within the same one replaces the first.
+		suffix := "ON CONFLICT (" + hwInvSightingsFruIdCol + ", " +
+			hwInvSightingsFirstSeenCol + ") DO UPDATE SET " +
+			hwInvSightingsIdCol + " = EXCLUDED." + hwInvSightingsIdCol + ", " +
+			hwInvSightingsLastSeenCol + " = EXCLUDED." + hwInvSightingsLastSeenCol
+		err = t.bulkInsert("RecordHWInvSightingsTx", hwInvSightingsTable,
+			hwInvSightingsCols, rows, suffix, nil)
+		if err != nil {
+			return ParsePgDBError(err)
+		}
+	}
+	return nil
 }
 
 /////////////////////////////////////////////////////////////////////////////
@@ -2753,6 +3129,10 @@ func (t *hmsdbPgTx) InsertRFEndpointTx(ep *sm.RedfishEndpoint) error {
 		t.LogAlways("InsertRFEndpointTx(%s): %s", ep.ID, ErrHMSDSArgBadID)
 		return ErrHMSDSArgBadID
 	}
+	password, err := t.hdb.sealRFEPPassword(normID, ep.Password)
+	if err != nil {
+		return err
+	}
 
 	// Perform insert
 	res, err := stmt.ExecContext(t.ctx,
@@ -2765,13 +3145,14 @@ func (t *hmsdbPgTx) InsertRFEndpointTx(ep *sm.RedfishEndpoint) error {
 		&ep.Enabled,
 		&ep.UUID,
 		&ep.User,
-		&ep.Password,
+		&password,
 		&ep.UseSSDP,
 		&ep.MACRequired,
 		&ep.MACAddr,
 		&ep.IPAddr,
 		&ep.RediscOnUpdate,
 		&ep.TemplateID,
+		&ep.Protocol,
 		&discInfoJSON)
 	if err != nil {
 		t.LogAlways("Error: InsertRFEndpointTx(): stmt.Exec: %s", err)
@@ -2821,6 +3202,10 @@ func (t *hmsdbPgTx) InsertRFEndpointsTx(eps []*sm.RedfishEndpoint) error {
 			t.LogAlways("InsertRFEndpointsTx(%s): %s", ep.ID, ErrHMSDSArgBadID)
 			return ErrHMSDSArgBadID
 		}
+		password, err := t.hdb.sealRFEPPassword(normID, ep.Password)
+		if err != nil {
+			return err
+		}
 
 		// Set fields for the INSERT
 		query = query.Values(
@@ -2833,13 +3218,14 @@ func (t *hmsdbPgTx) InsertRFEndpointsTx(eps []*sm.RedfishEndpoint) error {
 			ep.Enabled,
 			ep.UUID,
 			ep.User,
-			ep.Password,
+			password,
 			ep.UseSSDP,
 			ep.MACRequired,
 			ep.MACAddr,
 			ep.IPAddr,
 			ep.RediscOnUpdate,
 			ep.TemplateID,
+			ep.Protocol,
 			discInfoJSON)
 	}
 
@@ -2884,6 +3270,10 @@ func (t *hmsdbPgTx) UpdateRFEndpointTx(ep *sm.RedfishEndpoint) (bool, error) {
 	}
 	// Normalized key
 	normID := xnametypes.NormalizeHMSCompID(ep.ID)
+	password, err := t.hdb.sealRFEPPassword(normID, ep.Password)
+	if err != nil {
+		return false, err
+	}
 
 	// Perform update
 	res, err := stmt.ExecContext(t.ctx,
@@ -2895,13 +3285,14 @@ func (t *hmsdbPgTx) UpdateRFEndpointTx(ep *sm.RedfishEndpoint) (bool, error) {
 		&ep.Enabled,
 		&ep.UUID,
 		&ep.User,
-		&ep.Password,
+		&password,
 		&ep.UseSSDP,
 		&ep.MACRequired,
 		&ep.MACAddr,
 		&ep.IPAddr,
 		&ep.RediscOnUpdate,
 		&ep.TemplateID,
+		&ep.Protocol,
 		&discInfoJSON,
 		&normID) // Key
 	if err != nil {
@@ -2953,6 +3344,7 @@ func (t *hmsdbPgTx) UpdateRFEndpointsTx(eps []*sm.RedfishEndpoint) ([]*sm.Redfis
 		Set(rfEPsIPAddrCol, sq.Expr(rfEPsIPAddrColAlias)).
 		Set(rfEPsRediscOnUpdateCol, sq.Expr(rfEPsRediscOnUpdateColAlias)).
 		Set(rfEPsTemplateIDCol, sq.Expr(rfEPsTemplateIDColAlias)).
+		Set(rfEPsProtocolCol, sq.Expr(rfEPsProtocolColAlias)).
 		Set(rfEPsDiscInfoCol, sq.Expr(rfEPsDiscInfoColAlias))
 
 	// sq doesn't have a way to add a FROM statement to an UPDATE.
@@ -2975,11 +3367,15 @@ func (t *hmsdbPgTx) UpdateRFEndpointsTx(eps []*sm.RedfishEndpoint) ([]*sm.Redfis
 			// This should never fail
 			t.LogAlways("UpdateRFEndpointsTx: decode DiscoveryInfo: %s", err)
 		}
+		password, err := t.hdb.sealRFEPPassword(normID, ep.Password)
+		if err != nil {
+			return newEPs, err
+		}
 		// Add the values to our values table
 		if i == 0 {
-			valStr += "(?,?,?,?,?,?,?::BOOL,?,?,?,?::BOOL,?::BOOL,?,?,?::BOOL,?,?::JSON)"
+			valStr += "(?,?,?,?,?,?,?::BOOL,?,?,?,?::BOOL,?::BOOL,?,?,?::BOOL,?,?,?::JSON)"
 		} else {
-			valStr += ",(?,?,?,?,?,?,?::BOOL,?,?,?,?::BOOL,?::BOOL,?,?,?::BOOL,?,?::JSON)"
+			valStr += ",(?,?,?,?,?,?,?::BOOL,?,?,?,?::BOOL,?::BOOL,?,?,?::BOOL,?,?,?::JSON)"
 		}
 		args = append(args,
 			normID,
@@ -2991,13 +3387,14 @@ func (t *hmsdbPgTx) UpdateRFEndpointsTx(eps []*sm.RedfishEndpoint) ([]*sm.Redfis
 			ep.Enabled,
 			ep.UUID,
 			ep.User,
-			ep.Password,
+			password,
 			ep.UseSSDP,
 			ep.MACRequired,
 			ep.MACAddr,
 			ep.IPAddr,
 			ep.RediscOnUpdate,
 			ep.TemplateID,
+			ep.Protocol,
 			discInfoJSON)
 	}
 	// This FROM statement builds us a values table to pull update values
@@ -3048,6 +3445,10 @@ func (t *hmsdbPgTx) UpdateRFEndpointNoDiscInfoTx(ep *sm.RedfishEndpoint) (bool,
 	}
 	// Normalize key
 	normID := xnametypes.NormalizeHMSCompID(ep.ID)
+	password, err := t.hdb.sealRFEPPassword(normID, ep.Password)
+	if err != nil {
+		return false, err
+	}
 
 	// Perform update
 	res, err := stmt.ExecContext(t.ctx,
@@ -3059,13 +3460,14 @@ func (t *hmsdbPgTx) UpdateRFEndpointNoDiscInfoTx(ep *sm.RedfishEndpoint) (bool,
 		&ep.Enabled,
 		&ep.UUID,
 		&ep.User,
-		&ep.Password,
+		&password,
 		&ep.UseSSDP,
 		&ep.MACRequired,
 		&ep.MACAddr,
 		&ep.IPAddr,
 		&ep.RediscOnUpdate,
 		&ep.TemplateID,
+		&ep.Protocol,
 		&normID) // Key
 	if err != nil {
 		t.LogAlways("Error: UpdateRFEndpointNoDiscInfoTx(): stmt.Exec: %s", err)
@@ -3143,6 +3545,64 @@ func (t *hmsdbPgTx) DeleteRFEndpointsAllTx() (int64, error) {
 	return res.RowsAffected()
 }
 
+// Re-encrypt RedfishEndpoint passwords that are plaintext, or encrypted
+// with a key other than the current one (in transaction).  Returns the
+// number re-encrypted.
+func (t *hmsdbPgTx) ReencryptRFEndpointPasswordsTx() (int, error) {
+	if !t.IsConnected() {
+		return 0, ErrHMSDSPtrClosed
+	}
+	query := sq.Select(rfEPsIdCol, rfEPsPasswordCol).
+		From(rfEPsTable).
+		Where(sq.NotEq{rfEPsPasswordCol: ""}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		t.LogAlways("Error: ReencryptRFEndpointPasswordsTx(): QueryContext: %s", err)
+		return 0, err
+	}
+	// Read them all first, as the rows must be closed before updating.
+	ids := []string{}
+	passwords := []string{}
+	for rows.Next() {
+		var id, password string
+		if err := rows.Scan(&id, &password); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if t.hdb.fieldEnv.NeedsSeal(password) {
+			ids = append(ids, id)
+			passwords = append(passwords, password)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		plain, err := t.hdb.openRFEPPassword(id, passwords[i])
+		if err != nil {
+			return 0, err
+		}
+		sealed, err := t.hdb.sealRFEPPassword(id, plain)
+		if err != nil {
+			return 0, err
+		}
+		update := sq.Update(rfEPsTable).
+			Set(rfEPsPasswordCol, sealed).
+			Where(sq.Eq{rfEPsIdCol: id}).
+			PlaceholderFormat(sq.Dollar)
+		_, err = update.RunWith(t.sc).ExecContext(t.ctx)
+		if err != nil {
+			t.LogAlways("Error: ReencryptRFEndpointPasswordsTx(): ExecContext: %s", err)
+			return 0, err
+		}
+	}
+	t.Log(LOG_INFO, "Info: ReencryptRFEndpointPasswordsTx() - %d re-encrypted", len(ids))
+	return len(ids), nil
+}
+
 // Given the id of a RedfishEndpoint, set the states of all children
 // with State/Components entries to state and flag, returning a list of
 // xname IDs were at least state or flag was updated.
@@ -3397,10 +3857,8 @@ func (t *hmsdbPgTx) UpsertCompEndpointsTx(ceps *sm.ComponentEndpointArray) error
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(compEPsTable).
-		Columns(compEPsAllCols...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(ceps.ComponentEndpoints))
 	for _, cep := range ceps.ComponentEndpoints {
 		// Ensure endpoint name is normalized and valid
 		normID := xnametypes.VerifyNormalizeCompID(cep.ID)
@@ -3423,7 +3881,7 @@ func (t *hmsdbPgTx) UpsertCompEndpointsTx(ceps *sm.ComponentEndpointArray) error
 		}
 
 		// Set fields for the INSERT
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			normID,
 			cep.Type,
 			cep.Domain,
@@ -3433,9 +3891,9 @@ func (t *hmsdbPgTx) UpsertCompEndpointsTx(ceps *sm.ComponentEndpointArray) error
 			cep.MACAddr,
 			cep.UUID,
 			cep.OdataID,
-			compInfoJSON)
+			compInfoJSON})
 	}
-	query = query.Suffix("ON CONFLICT(" + compEPsIdCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + compEPsIdCol + ") DO UPDATE SET " +
 		compEPsDomainCol + " = EXCLUDED." + compEPsDomainCol + ", " +
 		compEPsRedfishTypeCol + " = EXCLUDED." + compEPsRedfishTypeCol + ", " +
 		compEPsRedfishSubtypeCol + " = EXCLUDED." + compEPsRedfishSubtypeCol + ", " +
@@ -3443,18 +3901,10 @@ func (t *hmsdbPgTx) UpsertCompEndpointsTx(ceps *sm.ComponentEndpointArray) error
 		compEPsMACCol + " = EXCLUDED." + compEPsMACCol + ", " +
 		compEPsUUIDCol + " = EXCLUDED." + compEPsUUIDCol + ", " +
 		compEPsODataIDCol + " = EXCLUDED." + compEPsODataIDCol + ", " +
-		compEPsComponentInfoCol + " = EXCLUDED." + compEPsComponentInfoCol)
+		compEPsComponentInfoCol + " = EXCLUDED." + compEPsComponentInfoCol
 
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: UpsertCompEndpointsTx(): Query: %s - With args: %v", qStr, qArgs)
-	res, err := query.RunWith(t.sc).ExecContext(t.ctx)
-	if err != nil {
-		t.LogAlways("Error: UpsertCompEndpointsTx(): ExecContext: %s", err)
-		return err
-	}
-	t.Log(LOG_INFO, "Info: UpsertCompEndpointsTx() - %s", res)
-	return nil
+	return t.bulkInsert("UpsertCompEndpointsTx", compEPsTable,
+		compEPsAllCols, rows, suffix, nil)
 }
 
 // Delete ComponentEndpoint with matching xname id from database, if it
@@ -3728,10 +4178,8 @@ func (t *hmsdbPgTx) UpsertServiceEndpointsTx(seps *sm.ServiceEndpointArray) erro
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(serviceEPsTable).
-		Columns(serviceEPsCols...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(seps.ServiceEndpoints))
 	for _, sep := range seps.ServiceEndpoints {
 		if sep == nil {
 			t.LogAlways("Error: UpsertServiceEndpointsTx(): Service Endpoint was nil.")
@@ -3747,30 +4195,22 @@ func (t *hmsdbPgTx) UpsertServiceEndpointsTx(seps *sm.ServiceEndpointArray) erro
 			valueMap[key] = true
 		}
 		// Set fields for the INSERT
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			normRFID,
 			sep.RedfishType,
 			sep.RedfishSubtype,
 			sep.UUID,
 			sep.OdataID,
-			sep.ServiceInfo)
+			sep.ServiceInfo})
 	}
-	query = query.Suffix("ON CONFLICT(" + serviceEPsRFEndpointIDCol + ", " + serviceEPsRedfishTypeCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + serviceEPsRFEndpointIDCol + ", " + serviceEPsRedfishTypeCol + ") DO UPDATE SET " +
 		serviceEPsRedfishSubtypeCol + " = EXCLUDED." + serviceEPsRedfishSubtypeCol + ", " +
 		serviceEPsUUIDCol + " = EXCLUDED." + serviceEPsUUIDCol + ", " +
 		serviceEPsODataIDCol + " = EXCLUDED." + serviceEPsODataIDCol + ", " +
-		serviceEPsServiceInfoCol + " = EXCLUDED." + serviceEPsServiceInfoCol)
+		serviceEPsServiceInfoCol + " = EXCLUDED." + serviceEPsServiceInfoCol
 
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: UpsertServiceEndpointsTx(): Query: %s - With args: %v", qStr, qArgs)
-	res, err := query.RunWith(t.sc).ExecContext(t.ctx)
-	if err != nil {
-		t.LogAlways("Error: UpsertServiceEndpointsTx(): ExecContext: %s", err)
-		return err
-	}
-	t.Log(LOG_INFO, "Info: UpsertServiceEndpointsTx() - %s", res)
-	return nil
+	return t.bulkInsert("UpsertServiceEndpointsTx", serviceEPsTable,
+		serviceEPsCols, rows, suffix, nil)
 }
 
 // Delete ServiceEndpoint with matching service type and xname id from
@@ -4051,7 +4491,6 @@ func (t *hmsdbPgTx) InsertCompEthInterfaceCompInfoTx(cei *sm.CompEthInterfaceV2)
 // and Type fields.
 // No insertion done on err != nil
 func (t *hmsdbPgTx) InsertCompEthInterfacesCompInfoTx(ceis []*sm.CompEthInterfaceV2) error {
-	var err error
 	if len(ceis) == 0 {
 		return nil
 	}
@@ -4060,10 +4499,8 @@ func (t *hmsdbPgTx) InsertCompEthInterfacesCompInfoTx(ceis []*sm.CompEthInterfac
 	}
 	valueMap := make(map[string]bool)
 
-	// Generate query
-	query := sq.Insert(compEthTable).
-		Columns(compEthCols...)
-
+	// Generate rows
+	rows := make([][]interface{}, 0, len(ceis))
 	for _, cei := range ceis {
 		cei.MACAddr = strings.ToLower(cei.MACAddr)
 		cei.ID = strings.ReplaceAll(cei.MACAddr, ":", "")
@@ -4094,24 +4531,21 @@ func (t *hmsdbPgTx) InsertCompEthInterfacesCompInfoTx(ceis []*sm.CompEthInterfac
 			// This should never fail
 			t.LogAlways("InsertCompEthInterfacesCompInfoTx: decode Details: %s", err)
 		}
-		query = query.Values(
+		rows = append(rows, []interface{}{
 			cei.ID,
 			cei.Desc,
 			cei.MACAddr,
 			"NOW()",
 			cei.CompID,
 			cei.Type,
-			ipAddrs)
+			ipAddrs})
 	}
-	query = query.Suffix("ON CONFLICT(" + compEthIdCol + ") DO UPDATE SET " +
+	suffix := "ON CONFLICT(" + compEthIdCol + ") DO UPDATE SET " +
 		compEthCompIDCol + " = EXCLUDED." + compEthCompIDCol + ", " +
-		compEthTypeCol + " = EXCLUDED." + compEthTypeCol)
+		compEthTypeCol + " = EXCLUDED." + compEthTypeCol
 
-	// Exec with statement cache for caching prepared statements (local to tx)
-	query = query.PlaceholderFormat(sq.Dollar)
-	qStr, qArgs, _ := query.ToSql()
-	t.Log(LOG_DEBUG, "Debug: InsertCompEthInterfacesCompInfoTx(): Query: %s - With args: %v", qStr, qArgs)
-	_, err = query.RunWith(t.sc).ExecContext(t.ctx)
+	err := t.bulkInsert("InsertCompEthInterfacesCompInfoTx", compEthTable,
+		compEthCols, rows, suffix, nil)
 	return ParsePgDBError(err)
 }
 
@@ -4574,6 +5008,10 @@ func (t *hmsdbPgTx) PatchSCNSubscriptionTx(id int64, op string, patch sm.SCNPatc
 				sub.SoftwareStatus = append(sub.SoftwareStatus, newSoftwareStatus)
 			}
 		}
+		sub.Reservations = sm.AddSCNScope(sub.Reservations, patch.Reservations)
+		sub.Xnames = sm.AddSCNScope(sub.Xnames, patch.Xnames)
+		sub.Groups = sm.AddSCNScope(sub.Groups, patch.Groups)
+		sub.Partitions = sm.AddSCNScope(sub.Partitions, patch.Partitions)
 		// The add patch op will only ever change the enabled field from false to true.
 		// Only show a change if our request has Enabled=true and our current subscription is enabled=false
 		if patch.Enabled != nil && *patch.Enabled &&
@@ -4615,6 +5053,10 @@ func (t *hmsdbPgTx) PatchSCNSubscriptionTx(id int64, op string, patch sm.SCNPatc
 				}
 			}
 		}
+		sub.Reservations = sm.RemoveSCNScope(sub.Reservations, patch.Reservations)
+		sub.Xnames = sm.RemoveSCNScope(sub.Xnames, patch.Xnames)
+		sub.Groups = sm.RemoveSCNScope(sub.Groups, patch.Groups)
+		sub.Partitions = sm.RemoveSCNScope(sub.Partitions, patch.Partitions)
 		// The remove patch op will only ever change the enabled field from true to false.
 		// Only show a change if our request has Enabled=true and our current subscription is Enabled=true
 		if patch.Enabled != nil && *patch.Enabled &&
@@ -4634,6 +5076,18 @@ func (t *hmsdbPgTx) PatchSCNSubscriptionTx(id int64, op string, patch sm.SCNPatc
 		if len(patch.SoftwareStatus) > 0 {
 			sub.SoftwareStatus = patch.SoftwareStatus
 		}
+		if len(patch.Reservations) > 0 {
+			sub.Reservations = patch.Reservations
+		}
+		if len(patch.Xnames) > 0 {
+			sub.Xnames = patch.Xnames
+		}
+		if len(patch.Groups) > 0 {
+			sub.Groups = patch.Groups
+		}
+		if len(patch.Partitions) > 0 {
+			sub.Partitions = patch.Partitions
+		}
 		if patch.Enabled != nil {
 			sub.Enabled = patch.Enabled
 		}
@@ -4649,6 +5103,10 @@ func (t *hmsdbPgTx) PatchSCNSubscriptionTx(id int64, op string, patch sm.SCNPatc
 		SubRoles:       sub.SubRoles,
 		SoftwareStatus: sub.SoftwareStatus,
 		States:         sub.States,
+		Reservations:   sub.Reservations,
+		Xnames:         sub.Xnames,
+		Groups:         sub.Groups,
+		Partitions:     sub.Partitions,
 		Url:            sub.Url,
 	}
 
@@ -4856,6 +5314,106 @@ func (t *hmsdbPgTx) GetEmptyGroupTx(label string) (
 	return
 }
 
+// Replace the child groups of the group with the given uuid and label (in
+// transaction).  Returns ErrHMSDSNoChildGroup if a child does not exist and
+// ErrHMSDSGroupCycle if the group would end up its own descendant.
+func (t *hmsdbPgTx) SetGroupChildrenTx(uuid, label string, children []string) error {
+	if !t.IsConnected() {
+		return ErrHMSDSPtrClosed
+	}
+	label = sm.NormalizeGroupField(label)
+	childIDs := make([]string, 0, len(children))
+	seen := make(map[string]bool)
+	for _, child := range children {
+		child = sm.NormalizeGroupField(child)
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		if child == label {
+			return ErrHMSDSGroupCycle
+		}
+		childID, g, err := t.GetEmptyGroupTx(child)
+		if err != nil {
+			return err
+		} else if g == nil || childID == "" {
+			return ErrHMSDSNoChildGroup
+		}
+		// Adding the child is a cycle if we are already under it.
+		desc, err := t.GetGroupChildrenTx(childID, true)
+		if err != nil {
+			return err
+		}
+		for _, d := range desc {
+			if d == label {
+				return ErrHMSDSGroupCycle
+			}
+		}
+		childIDs = append(childIDs, childID)
+	}
+	// Replace the old children.
+	del := sq.Delete(compGroupChildrenTable).
+		Where(sq.Eq{compGroupChildrenParentCol: uuid}).
+		PlaceholderFormat(sq.Dollar)
+	if _, err := del.RunWith(t.sc).ExecContext(t.ctx); err != nil {
+		t.LogAlways("Error: SetGroupChildrenTx(%s): delete failed: %s", label, err)
+		return err
+	}
+	if len(childIDs) == 0 {
+		return nil
+	}
+	ins := sq.Insert(compGroupChildrenTable).
+		Columns(compGroupChildrenParentCol, compGroupChildrenChildCol)
+	for _, childID := range childIDs {
+		ins = ins.Values(uuid, childID)
+	}
+	ins = ins.PlaceholderFormat(sq.Dollar)
+	_, err := ins.RunWith(t.sc).ExecContext(t.ctx)
+	return ParsePgDBError(err)
+}
+
+// Get the labels of the child groups of the group with the given uuid.
+// If recursive, all descendants are returned, nearest first.
+func (t *hmsdbPgTx) GetGroupChildrenTx(uuid string, recursive bool) ([]string, error) {
+	if !t.IsConnected() {
+		return nil, ErrHMSDSPtrClosed
+	}
+	var rows *sql.Rows
+	var err error
+	if recursive {
+		stmt, perr := t.conditionalPrepare("GetGroupChildrenTx", getGroupDescendants)
+		if perr != nil {
+			return nil, perr
+		}
+		rows, err = stmt.QueryContext(t.ctx, uuid)
+	} else {
+		query := sq.Select(compGroupsAlias+"."+compGroupNameCol).
+			From(compGroupsTable+" "+compGroupsAlias).
+			Join(compGroupChildrenTable+" c ON c."+compGroupChildrenChildCol+
+				" = "+compGroupIdColAlias).
+			Where("c."+compGroupChildrenParentCol+" = ?", uuid).
+			OrderBy(compGroupNameColAlias).
+			PlaceholderFormat(sq.Dollar)
+		rows, err = query.RunWith(t.sc).QueryContext(t.ctx)
+	}
+	if err != nil {
+		t.LogAlways("Error: GetGroupChildrenTx(%s): query failed: %s", uuid, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := []string{}
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			t.LogAlways("Error: GetGroupChildrenTx(%s): scan failed: %s", uuid, err)
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
 //
 // Partitions
 //
@@ -5337,6 +5895,53 @@ func (t *hmsdbPgTx) DeleteCompReservationExpiredTx() ([]string, error) {
 	return xnames, nil
 }
 
+// Retrieve the reservations that expire within the given duration from now,
+// including any that have already expired but not yet been released.
+// Results are sorted by expiration time.
+func (t *hmsdbPgTx) GetCompReservationsExpiringTx(within time.Duration) ([]sm.CompLockV2Expiring, error) {
+	results := make([]sm.CompLockV2Expiring, 0, 1)
+	if !t.IsConnected() {
+		return results, ErrHMSDSPtrClosed
+	}
+
+	query := sq.Select(compResCompIdCol, compResCreatedCol, compResExpireCol).
+		From(compResTable).
+		Where(compResExpireCol+" IS NOT NULL AND "+compResExpireCol+
+			" <= NOW() + ? * INTERVAL '1 second'", int64(within/time.Second)).
+		OrderBy(compResExpireCol, compResCompIdCol)
+
+	// Exec with statement cache for caching prepared statements (local to tx)
+	query = query.PlaceholderFormat(sq.Dollar)
+	rows, err := query.RunWith(t.sc).QueryContext(t.ctx)
+	if err != nil {
+		t.LogAlways("Error: GetCompReservationsExpiringTx(): query failed: %s", err)
+		return results, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cr compReservation
+		err = rows.Scan(
+			&cr.component_id,
+			&cr.create_timestamp,
+			&cr.expiration_timestamp,
+		)
+		if err != nil {
+			t.LogAlways("Error: GetCompReservationsExpiringTx(): Scan failed: %s", err)
+			return results, err
+		}
+		result := sm.CompLockV2Expiring{ID: cr.component_id}
+		if cr.create_timestamp.Valid {
+			result.CreationTime = cr.create_timestamp.Time.Format(time.RFC3339)
+		}
+		if cr.expiration_timestamp.Valid {
+			result.ExpirationTime = cr.expiration_timestamp.Time.Format(time.RFC3339)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
 // Retrieve the status of reservations. The public key and xname is
 // required to address the reservation unless force = true.
 func (t *hmsdbPgTx) GetCompReservationsTx(dKeys []sm.CompLockV2Key, force bool) ([]sm.CompLockV2Success, string, error) {
@@ -5546,6 +6151,20 @@ func (t *hmsdbPgTx) BulkUpdateCompResLockedTx(ids []string, locked bool) ([]stri
 	return results, nil
 }
 
+// Set the action and reason recorded in the lock audit log for the rest
+// of the transaction.  An empty action records what was actually done,
+// e.g. Release or Expire for removed reservations.
+func (t *hmsdbPgTx) SetCompLockAuditTx(action, reason string) error {
+	if !t.IsConnected() {
+		return ErrHMSDSPtrClosed
+	}
+	_, err := t.tx.ExecContext(t.ctx, ToPGQueryArgs(setLockAudit), action, reason)
+	if err != nil {
+		t.LogAlways("Error: SetCompLockAuditTx(): exec failed: %s", err)
+	}
+	return err
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Job Sync Management
@@ -148,10 +148,16 @@ INSERT INTO hwinv_by_fru (
     fru_id,
     type,
     subtype,
+    serial_number,
+    part_number,
+    manufacturer,
     fru_info)
-VALUES (?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(fru_id) DO UPDATE SET
     subtype = EXCLUDED.subtype,
+    serial_number = EXCLUDED.serial_number,
+    part_number = EXCLUDED.part_number,
+    manufacturer = EXCLUDED.manufacturer,
     fru_info = EXCLUDED.fru_info;`
 
 // RedfishEndpoints - Update operations
@@ -172,6 +178,7 @@ UPDATE rf_endpoints SET
     ipaddr = ?,
     rediscoveronupdate = ?,
     templateid = ?,
+    protocol = ?,
     discovery_info = ? `
 
 const updatePgRFEndpointNoDiscInfoPrefix = `
@@ -190,7 +197,8 @@ UPDATE rf_endpoints SET
     macaddr = ?,
     ipaddr = ?,
     rediscoveronupdate = ?,
-    templateid = ? `
+    templateid = ?,
+    protocol = ? `
 
 const updatePgRFEndpointQuery = updatePgRFEndpointPrefix + suffixByID
 const updatePgRFEndpointNoDiscInfoQuery = updatePgRFEndpointNoDiscInfoPrefix + suffixByID
@@ -217,8 +225,9 @@ INSERT INTO rf_endpoints (
     ipaddr,
     rediscoveronupdate,
     templateid,
+    protocol,
     discovery_info)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) `
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) `
 
 const upsertPgRFEndpointModifier = `
 ON CONFLICT(id) DO UPDATE SET
@@ -236,7 +245,8 @@ ON CONFLICT(id) DO UPDATE SET
     macaddr = EXCLUDED.macAddr,
     ipaddr = EXCLUDED.ipAddr,
     rediscoveronupdate = EXCLUDED.rediscoverOnUpdate,
-    templateid = EXCLUDED.templateID `
+    templateid = EXCLUDED.templateID,
+    protocol = EXCLUDED.protocol `
 
 const upsertPgRFEndpointPrefix = insertPgRFEndpointPrefix + upsertPgRFEndpointModifier
 
@@ -528,6 +538,28 @@ func (d *hmsdbPg) scanHwInvByFRU(rows *sql.Rows) (*sm.HWInvByFRU, error) {
 	return hwfru, nil
 }
 
+// Replaces Scan() call when expected data type is sm.HWInvByFRULocation,
+// i.e. a location (or empty string) followed by the HWInvByFRU columns.
+func (d *hmsdbPg) scanHwInvByFRULocation(rows *sql.Rows) (*sm.HWInvByFRULocation, error) {
+	var fru_info []byte
+
+	hwfru := new(sm.HWInvByFRULocation)
+	err := rows.Scan(
+		&hwfru.Location,
+		&hwfru.FRUID,
+		&hwfru.Type,
+		&hwfru.Subtype,
+		&fru_info)
+	if err != nil {
+		return nil, err
+	}
+	err = hwfru.DecodeFRUInfo(fru_info)
+	if err != nil {
+		d.LogAlways("Warning: scanHwInvByFRULocation(): DecodeFRUInfo: %s", err)
+	}
+	return hwfru, nil
+}
+
 // Replaces Scan() call when expected data type is sm.HWInvHist
 func (d *hmsdbPg) scanHwInvHist(rows *sql.Rows) (*sm.HWInvHist, error) {
 	// There should be only 1 row returned.
@@ -543,6 +575,20 @@ func (d *hmsdbPg) scanHwInvHist(rows *sql.Rows) (*sm.HWInvHist, error) {
 	return hwhist, nil
 }
 
+// This is used for all routines that read HWInvSighting structs as rows.
+func (d *hmsdbPg) scanHwInvSighting(rows *sql.Rows) (*sm.HWInvSighting, error) {
+	sighting := new(sm.HWInvSighting)
+	err := rows.Scan(
+		&sighting.ID,
+		&sighting.FruId,
+		&sighting.FirstSeen,
+		&sighting.LastSeen)
+	if err != nil {
+		return nil, err
+	}
+	return sighting, nil
+}
+
 // This is used for all routines that read RedfishEndpoint struct as rows and
 // replaces rows.Scan in normal usage.
 func (d *hmsdbPg) scanRedfishEndpoint(rows *sql.Rows) (*sm.RedfishEndpoint, error) {
@@ -566,10 +612,15 @@ func (d *hmsdbPg) scanRedfishEndpoint(rows *sql.Rows) (*sm.RedfishEndpoint, erro
 		&ep.IPAddr,
 		&ep.RediscOnUpdate,
 		&ep.TemplateID,
+		&ep.Protocol,
 		&discovery_info)
 	if err != nil {
 		return nil, err
 	}
+	ep.Password, err = d.openRFEPPassword(ep.ID, ep.Password)
+	if err != nil {
+		return nil, err
+	}
 	err = json.Unmarshal(discovery_info, &ep.DiscInfo)
 	if err != nil {
 		d.LogAlways("Warning: scanRedfishEndpoint(): Decode DiscoveryInfo: %s", err)
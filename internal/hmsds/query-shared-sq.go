@@ -29,8 +29,10 @@ package hmsds
 import (
 	"database/sql"
 	"strings"
+	"time"
 
 	"github.com/Cray-HPE/hms-xname/xnametypes"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
 
 	sq "github.com/Masterminds/squirrel"
 )
@@ -47,6 +49,68 @@ const (
 	sysSystemInfoCol    = "system_info"
 )
 
+//                                                                          //
+//                       Collection Versions Table                          //
+//                                                                          //
+
+const collVersTable = "collection_versions"
+
+const (
+	collVersIdCol     = "id"
+	collVersCompsCol  = "components"
+	collVersGroupsCol = `"groups"`
+	collVersHWInvCol  = "hwinv"
+)
+
+const leaseTable = "leases"
+
+const (
+	leaseNameCol    = "name"
+	leaseHolderCol  = "holder"
+	leaseExpiresCol = "expires"
+)
+
+const discSchedTable = "discovery_schedules"
+
+const (
+	discSchedNameCol     = "name"
+	discSchedScheduleCol = "schedule"
+)
+
+const tombstonesTable = "tombstones"
+
+const (
+	tombstonesSeqCol     = "seq"
+	tombstonesKindCol    = "kind"
+	tombstonesIdCol      = "id"
+	tombstonesDataCol    = "data"
+	tombstonesActorCol   = "actor"
+	tombstonesDeletedCol = "deleted"
+)
+
+const nidPoliciesTable = "nid_policies"
+
+const (
+	nidPoliciesNameCol   = "name"
+	nidPoliciesPolicyCol = "policy"
+)
+
+const nidAssignmentsTable = "nid_assignments"
+
+const (
+	nidAssignmentsIdCol       = "id"
+	nidAssignmentsNIDCol      = "nid"
+	nidAssignmentsPolicyCol   = "policy"
+	nidAssignmentsAssignedCol = "assigned"
+)
+
+const compHeartbeatsTable = "comp_heartbeats"
+
+const (
+	compHeartbeatsIdCol       = "id"
+	compHeartbeatsLastSeenCol = "last_seen"
+)
+
 //                                                                          //
 //                           Component structs                              //
 //                                                                          //
@@ -167,6 +231,17 @@ var compColsAllWithGroup2 []string = compGroupPartCols
 var compColsIdWithGroup1 []string = compColsIdOnly
 var compColsIdWithGroup2 []string = compGroupPartCols
 
+// Component state history.  Rows have the same columns as the components
+// table plus these.
+const compStateHistTable = `comp_state_hist`
+
+const (
+	compStateHistSeqCol       = `seq`
+	compStateHistDeletedCol   = `deleted`
+	compStateHistTimestampCol = `timestamp`
+	compStateHistActorCol     = `actor`
+)
+
 //                                                                          //
 //                        Groups and partitions                             //
 //                                                                          //
@@ -197,6 +272,21 @@ const (
 	compGroupExGrpColAlias     = compGroupsAlias + "." + compGroupExGrpCol
 )
 
+// comp_group_members_hist table, filled in by triggers on the group and
+// member tables.
+
+const compGroupMembersHistTable = `comp_group_members_hist`
+
+const (
+	compGroupMembersHistSeqCol       = `seq`
+	compGroupMembersHistNameCol      = `group_name`
+	compGroupMembersHistNamespaceCol = `namespace`
+	compGroupMembersHistCompIdCol    = `component_id`
+	compGroupMembersHistActionCol    = `action`
+	compGroupMembersHistActorCol     = `actor`
+	compGroupMembersHistTimestampCol = `timestamp`
+)
+
 // These are the namespace enums used in the DB
 const groupNamespace = `group`
 const partNamespace = `partition`
@@ -270,6 +360,15 @@ type compGroupMembersInsertNoTS struct {
 // Get only user visible columns from component_group_members
 var compGroupMembersColsUser = []string{compGroupMembersCmpIdCol}
 
+// component_group_children table - nested groups
+
+const compGroupChildrenTable = `component_group_children`
+
+const (
+	compGroupChildrenParentCol = `parent_id`
+	compGroupChildrenChildCol  = `child_id`
+)
+
 //                                                                           //
 //                            Component Locks V2                             //
 //                                                                           //
@@ -296,6 +395,21 @@ const (
 	compResRKColAlias      = compResAlias + "." + compResRKCol
 )
 
+// comp_lock_audit table, filled in by triggers on the reservations and
+// components tables.
+
+const compLockAuditTable = `comp_lock_audit`
+
+const (
+	compLockAuditSeqCol       = `seq`
+	compLockAuditCompIdCol    = `component_id`
+	compLockAuditActionCol    = `action`
+	compLockAuditActorCol     = `actor`
+	compLockAuditReasonCol    = `reason`
+	compLockAuditExpireCol    = `expiration`
+	compLockAuditTimestampCol = `timestamp`
+)
+
 // reservations table columns.
 var compResCols = []string{compResCompIdCol, compResCreatedCol,
 	compResExpireCol, compResDKCol, compResRKCol}
@@ -338,6 +452,7 @@ const (
 	rfEPsIPAddrCol         = `ipaddr`
 	rfEPsRediscOnUpdateCol = `rediscoveronupdate`
 	rfEPsTemplateIDCol     = `templateid`
+	rfEPsProtocolCol       = `protocol`
 	rfEPsDiscInfoCol       = `discovery_info`
 )
 
@@ -358,6 +473,7 @@ const (
 	rfEPsIPAddrColAlias         = rfEPsAlias + "." + rfEPsIPAddrCol
 	rfEPsRediscOnUpdateColAlias = rfEPsAlias + "." + rfEPsRediscOnUpdateCol
 	rfEPsTemplateIDColAlias     = rfEPsAlias + "." + rfEPsTemplateIDCol
+	rfEPsProtocolColAlias       = rfEPsAlias + "." + rfEPsProtocolCol
 	rfEPsDiscInfoColAlias       = rfEPsAlias + "." + rfEPsDiscInfoCol
 )
 
@@ -378,6 +494,7 @@ var rfEPsAllColsNoStatus = []string{
 	rfEPsIPAddrCol,
 	rfEPsRediscOnUpdateCol,
 	rfEPsTemplateIDCol,
+	rfEPsProtocolCol,
 }
 
 var rfEPsAllCols = append(rfEPsAllColsNoStatus, rfEPsDiscInfoCol)
@@ -462,6 +579,56 @@ var serviceEPsCols = []string{
 	serviceEPsServiceInfoCol,
 }
 
+//                                                                          //
+//                        Event subscription structs                        //
+//                                                                          //
+
+const eventSubsTable = `rf_event_subscriptions`
+
+const (
+	eventSubsRFEndpointIDCol    = `rf_endpoint_id`
+	eventSubsDestinationCol     = `destination`
+	eventSubsContextCol         = `context`
+	eventSubsSubscriptionURICol = `subscription_uri`
+	eventSubsStatusCol          = `status`
+	eventSubsLastErrorCol       = `last_error`
+	eventSubsLastUpdatedCol     = `last_updated`
+)
+
+var eventSubsCols = []string{
+	eventSubsRFEndpointIDCol,
+	eventSubsDestinationCol,
+	eventSubsContextCol,
+	eventSubsSubscriptionURICol,
+	eventSubsStatusCol,
+	eventSubsLastErrorCol,
+	eventSubsLastUpdatedCol,
+}
+
+//                                                                          //
+//                       RedfishEndpoint liveness                           //
+//                                                                          //
+
+const rfEPLivenessTable = `rf_endpoint_liveness`
+
+const (
+	rfEPLivenessRFEndpointIDCol = `rf_endpoint_id`
+	rfEPLivenessLastContactCol  = `last_contact`
+	rfEPLivenessLastProbeCol    = `last_probe`
+	rfEPLivenessFailuresCol     = `failures`
+	rfEPLivenessUnreachableCol  = `unreachable`
+	rfEPLivenessLastErrorCol    = `last_error`
+)
+
+var rfEPLivenessCols = []string{
+	rfEPLivenessRFEndpointIDCol,
+	rfEPLivenessLastContactCol,
+	rfEPLivenessLastProbeCol,
+	rfEPLivenessFailuresCol,
+	rfEPLivenessUnreachableCol,
+	rfEPLivenessLastErrorCol,
+}
+
 //                                                                          //
 //                      Component Ethernet Interfaces                       //
 //                                                                          //
@@ -681,6 +848,32 @@ var hwInvHistCols = []string{hwInvHistIdCol, hwInvHistFruIdCol,
 var hwInvHistColsNoTS = []string{hwInvHistIdCol, hwInvHistFruIdCol,
 	hwInvHistEventTypeCol}
 
+//                                                                          //
+//                        HwInv Sightings structs                           //
+//                                                                          //
+
+const hwInvSightingsTable = `hwinv_sightings`
+const hwInvSightingsAlias = `hs`
+
+const (
+	hwInvSightingsIdCol        = `id`
+	hwInvSightingsFruIdCol     = `fru_id`
+	hwInvSightingsFirstSeenCol = `first_seen`
+	hwInvSightingsLastSeenCol  = `last_seen`
+)
+
+// This adds the base table alias to each column.  it can later be appended to.
+const (
+	hwInvSightingsIdColAlias        = hwInvSightingsAlias + "." + hwInvSightingsIdCol
+	hwInvSightingsFruIdColAlias     = hwInvSightingsAlias + "." + hwInvSightingsFruIdCol
+	hwInvSightingsFirstSeenColAlias = hwInvSightingsAlias + "." + hwInvSightingsFirstSeenCol
+	hwInvSightingsLastSeenColAlias  = hwInvSightingsAlias + "." + hwInvSightingsLastSeenCol
+)
+
+// hwInvSightings table columns.
+var hwInvSightingsCols = []string{hwInvSightingsIdCol, hwInvSightingsFruIdCol,
+	hwInvSightingsFirstSeenCol, hwInvSightingsLastSeenCol}
+
 //                                                                           //
 //                                 Job Sync                                  //
 //                                                                           //
@@ -938,6 +1131,40 @@ func makeComponentQuery(alias string, f *ComponentFilter, fltr FieldFilter) (
 ) {
 	// Get the base query:
 	query := selectComponentCols(fltr, alias, compTable)
+	return filterComponentQuery(query, alias, f, fltr)
+}
+
+// Select statement for the Components as they were at time asOf, rebuilt
+// from the latest comp_state_hist entry for each one at or before that time.
+// Group and partition memberships are not kept in the history, so those
+// filters are not supported.
+func selectComponentsAsOf(f *ComponentFilter, fltr FieldFilter, asOf time.Time) (
+	sq.SelectBuilder, error,
+) {
+	alias := compTableJoinAlias
+	cols := append(append([]string{}, compColsNamesAll...), compStateHistDeletedCol)
+	snapshot := sq.Select(cols...).
+		Options("DISTINCT ON ("+compIdCol+")").
+		From(compStateHistTable).
+		Where(sq.LtOrEq{compStateHistTimestampCol: asOf}).
+		OrderBy(compIdCol, compStateHistSeqCol+" DESC")
+
+	query := selectComponentCols(fltr, alias, "").
+		FromSelect(snapshot, alias).
+		Where(sq.Eq{alias + "." + compStateHistDeletedCol: false})
+	if fltr == FLTR_ALL_W_GROUP || fltr == FLTR_ID_W_GROUP ||
+		(f != nil && (len(f.Group) > 0 || len(f.Partition) > 0)) {
+		return query, ErrHMSDSArgBadArg
+	}
+	return filterComponentQuery(query, alias, f, fltr)
+}
+
+// Add the options in the component filter to a select statement from
+// Components (or something with the same columns) with the given alias.
+// If needed, join with group/partition table for additional fields/filtering.
+func filterComponentQuery(query sq.SelectBuilder, alias string,
+	f *ComponentFilter, fltr FieldFilter,
+) (sq.SelectBuilder, error) {
 	if f != nil {
 		// Check and normalize filter inputs, skipping if this has
 		// already been done.
@@ -982,7 +1209,7 @@ func whereComponentCols(q sq.SelectBuilder, alias string, f *ComponentFilter) sq
 	if f == nil {
 		return q
 	}
-	q = whereComponentCol(q, alias+"."+compIdCol, f.ID)
+	q = whereComponentIDCol(q, alias+"."+compIdCol, f.ID, f.descendants)
 	q = whereComponentCol(q, alias+"."+compTypeCol, f.Type)
 	q = whereComponentCol(q, alias+"."+compStateCol, f.State)
 	q = whereComponentCol(q, alias+"."+compFlagCol, f.Flag)
@@ -1000,6 +1227,9 @@ func whereComponentCols(q sq.SelectBuilder, alias string, f *ComponentFilter) sq
 	// interaction between them
 	q = whereComponentNIDCol(q, alias, f)
 
+	for _, node := range f.filterExprs {
+		q = q.Where(node.toSql(alias))
+	}
 	return q
 }
 
@@ -1019,6 +1249,98 @@ func whereComponentCol(q sq.SelectBuilder, col string, args []string) sq.SelectB
 	return q
 }
 
+// Like whereComponentCol for the ID column, but each (non-negated) xname
+// also matches every component under it, e.g. x1000c0s0 matches x1000c0s0b0n0
+// but not x1000c0s01.
+func whereComponentDescendants(q sq.SelectBuilder, col string, args []string) sq.SelectBuilder {
+	if args == nil {
+		return q
+	}
+	pos, neg := splitSliceWithNegations(args)
+	if len(pos) > 0 {
+		or := sq.Or{}
+		for _, id := range pos {
+			or = append(or, sq.Expr(col+" SIMILAR TO ?",
+				id+"([[:alpha:]][[:alnum:]]*)?"))
+		}
+		q = q.Where(or)
+	}
+	if len(neg) > 0 {
+		q = q.Where(sq.NotEq{col: neg})
+	}
+	return q
+}
+
+// Like whereComponentCol for the ID column, but args may also be xname
+// patterns like x1000c0s*b0n[0-1], and if descendants is set each
+// (non-negated) xname or pattern also matches the components under it.
+func whereComponentIDCol(q sq.SelectBuilder, col string, args []string,
+	descendants bool,
+) sq.SelectBuilder {
+	pos, neg := splitSliceWithNegations(args)
+	pos, posPats := splitXnamePatterns(pos)
+	neg, negPats := splitXnamePatterns(neg)
+	if len(posPats) == 0 && len(negPats) == 0 {
+		if descendants {
+			return whereComponentDescendants(q, col, args)
+		}
+		return whereComponentCol(q, col, args)
+	}
+	suffix := ""
+	if descendants {
+		suffix = "([[:alpha:]][[:alnum:]]*)?"
+	}
+	or := sq.Or{}
+	if len(pos) > 0 {
+		if descendants {
+			for _, id := range pos {
+				or = append(or, sq.Expr(col+" SIMILAR TO ?", id+suffix))
+			}
+		} else {
+			or = append(or, sq.Eq{col: pos})
+		}
+	}
+	for _, p := range posPats {
+		or = append(or, xnamePatternExpr(col, p, suffix))
+	}
+	if len(or) > 0 {
+		q = q.Where(or)
+	}
+	if len(neg) > 0 {
+		q = q.Where(sq.NotEq{col: neg})
+	}
+	for _, p := range negPats {
+		sql, sqlArgs, _ := xnamePatternExpr(col, p, "").ToSql()
+		q = q.Where("NOT ("+sql+")", sqlArgs...)
+	}
+	return q
+}
+
+// Split xnames into plain xnames and parsed xname patterns.  The patterns
+// are assumed to have been checked already, and any that don't parse are
+// dropped.
+func splitXnamePatterns(args []string) (ids []string, pats []*xnameutil.Pattern) {
+	for _, arg := range args {
+		if !xnameutil.IsPattern(arg) {
+			ids = append(ids, arg)
+		} else if p, err := xnameutil.ParsePattern(arg); err == nil {
+			pats = append(pats, p)
+		}
+	}
+	return
+}
+
+// Match col against an xname pattern, followed by suffix, a regular
+// expression that may be empty.  The literal prefix of the pattern is
+// matched with LIKE as well, so that an index on col can narrow down the
+// rows the regular expression is tried on.
+func xnamePatternExpr(col string, p *xnameutil.Pattern, suffix string) sq.Sqlizer {
+	return sq.And{
+		sq.Like{col: p.Prefix() + "%"},
+		sq.Expr(col+" ~ ?", "^"+p.Regexp()+suffix+"$"),
+	}
+}
+
 // Split a single array with negated string arguments (if any), i.e. !ready
 // into separate pos and neg arguments with the ! negation prefix removed for
 // neg.
@@ -1287,8 +1609,13 @@ func getHWInvByLocQuery(f_opts ...HWInvLocFiltFunc) (sq.SelectBuilder, error) {
 	args := make([]interface{}, 0, 1)
 	filterQuery := ""
 	if len(f.ID) > 0 {
+		// Parents can't be found for xname patterns.
+		ids, pats := splitXnamePatterns(f.ID)
+		if len(pats) > 0 && f.Parents {
+			return query, ErrHMSDSArgBadArg
+		}
 		if hierarchy {
-			for i, id := range f.ID {
+			for i, id := range ids {
 				// Form a list of parent IDs to look for.
 				if f.Parents {
 					childId := id
@@ -1317,11 +1644,20 @@ func getHWInvByLocQuery(f_opts ...HWInvLocFiltFunc) (sq.SelectBuilder, error) {
 				arg := xnametypes.NormalizeHMSCompID(id) + "([[:alpha:]][[:alnum:]]*)?"
 				args = append(args, arg)
 			}
+			for _, p := range pats {
+				patQuery, patArgs, _ := xnamePatternExpr(idCol, p,
+					"([[:alpha:]][[:alnum:]]*)?").ToSql()
+				if len(filterQuery) > 0 {
+					filterQuery += " OR "
+				}
+				filterQuery += patQuery
+				args = append(args, patArgs...)
+			}
 		} else {
 			// If no children, the string format expected by the database
 			// is specified literally, (e.g. no component expansion). No
 			// need to use REGEXP.
-			for _, id := range f.ID {
+			for _, id := range ids {
 				// Form a list of parent IDs to look for.
 				if f.Parents {
 					childId := id
@@ -1345,7 +1681,18 @@ func getHWInvByLocQuery(f_opts ...HWInvLocFiltFunc) (sq.SelectBuilder, error) {
 				}
 				args = append(args, xnametypes.NormalizeHMSCompID(id))
 			}
-			filterQuery, args, _ = sq.Eq{idCol: args}.ToSql()
+			if len(pats) == 0 {
+				filterQuery, args, _ = sq.Eq{idCol: args}.ToSql()
+			} else {
+				or := sq.Or{}
+				if len(args) > 0 {
+					or = append(or, sq.Eq{idCol: args})
+				}
+				for _, p := range pats {
+					or = append(or, xnamePatternExpr(idCol, p, ""))
+				}
+				filterQuery, args, _ = or.ToSql()
+			}
 		}
 	}
 	// Form the type query filter only if we're not looking
@@ -27,6 +27,7 @@ import (
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
 	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+	"github.com/OpenCHAMI/smd/v2/pkg/xnameutil"
 	"strings"
 )
 
@@ -384,6 +385,7 @@ SELECT
     rf.ipAddr,
     rf.rediscoverOnUpdate,
     rf.templateID,
+    rf.protocol,
     rf.discovery_info
 FROM rf_endpoints rf`
 
@@ -522,6 +524,37 @@ DELETE FROM scn_subscriptions WHERE id = ?;`
 const deleteSCNSubscriptionsAll = `
 DELETE FROM scn_subscriptions;`
 
+// Nested groups
+
+// All descendant group labels of a group id, nearest first.  The depth limit
+// keeps this from running forever if a cycle ever gets in.
+const getGroupDescendants = `
+WITH RECURSIVE descendants(id, depth) AS (
+    SELECT child_id, 1 FROM component_group_children WHERE parent_id = ?
+  UNION
+    SELECT c.child_id, d.depth + 1
+    FROM component_group_children c
+    JOIN descendants d ON c.parent_id = d.id
+    WHERE d.depth < 64
+)
+SELECT g.name
+FROM component_groups g
+JOIN (SELECT id, MIN(depth) AS depth FROM descendants GROUP BY id) d
+    ON g.id = d.id
+ORDER BY d.depth, g.name;`
+
+// Membership history
+
+// Sets who is making changes in the current transaction.  The membership
+// history triggers record this as the actor.
+const setActor = `SELECT set_config('smd.actor', ?, true);`
+
+// Lock audit log
+
+// Sets the action and reason the lock audit triggers record for changes in
+// the current transaction.  An empty action lets the triggers work it out.
+const setLockAudit = `SELECT set_config('smd.lock_action', ?, true), set_config('smd.lock_reason', ?, true);`
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Helper functions - Query building
@@ -731,6 +764,10 @@ func (q *preparedQuery) setCompWhereQuery(f *ComponentFilter, cont bool) error {
 	if q == nil {
 		return ErrHMSDSArgNil
 	}
+	// Filter expressions are only supported by the squirrel queries.
+	if len(f.Filter) > 0 {
+		return ErrHMSDSArgBadFilter
+	}
 	//
 	// Construct 'where' arguments.
 	//
@@ -1314,6 +1351,19 @@ func validXNameFilter(xname string) string {
 	return xnametypes.VerifyNormalizeCompID(xname)
 }
 
+// Like validXNameFilter, but also accepts xname patterns like
+// x1000c0s*b0n[0-1] (see xnameutil.Pattern), returning them normalized.
+func validXNameOrPatternFilter(xname string) string {
+	if !xnameutil.IsPattern(xname) {
+		return validXNameFilter(xname)
+	}
+	p, err := xnameutil.ParsePattern(xname)
+	if err != nil {
+		return ""
+	}
+	return p.String()
+}
+
 // If group or partion name is valid, return it normalized, else return
 // empty string.
 func validGroupField(label string) string {
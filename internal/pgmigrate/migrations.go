@@ -2,13 +2,28 @@ package pgmigrate
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 
+	"github.com/OpenCHAMI/smd/v2/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/lib/pq"
 )
 
+// One migration to run, Up or down, and its DDL.
+type Step struct {
+	Version uint
+	Name    string
+	Up      bool
+	SQL     string
+}
+
 func DBConnect(dbDSN string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", dbDSN)
 	if err != nil {
@@ -22,19 +37,134 @@ func DBConnect(dbDSN string) (*sql.DB, error) {
 	return db, nil
 }
 
-func ApplyMigrations(migrations_dir string, db *sql.DB) error {
+// Open the migrations in dir, or those built in if dir is empty.
+func Source(dir string) (source.Driver, error) {
+	if dir == "" {
+		return iofs.New(migrations.Postgres, "postgres")
+	}
+	return source.Open("file://" + dir)
+}
+
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
+
+// The last migration in src.
+func Latest(src source.Driver) (uint, error) {
+	v, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(v)
+		if isNotExist(err) {
+			return v, nil
+		} else if err != nil {
+			return 0, err
+		}
+		v = next
+	}
+}
+
+// The migration the database is at, 0 if none has been applied, and
+// whether the last one failed part way through.  Unlike opening a migrate
+// driver, this doesn't create the version table if it is missing.
+func CurrentVersion(db *sql.DB) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.QueryRow(`SELECT version, dirty FROM `+
+		postgres.DefaultMigrationsTable+` LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	} else if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "42P01" {
+		// undefined_table
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	if version < 0 {
+		return 0, dirty, nil
+	}
+	return uint(version), dirty, nil
+}
+
+func readStep(r io.ReadCloser, name string, err error) (string, string, error) {
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+	ddl, err := io.ReadAll(r)
+	return string(ddl), name, err
+}
+
+// The migrations, in order, that take the database from version from (0
+// for none) to version target, up or down.
+func Plan(src source.Driver, from, target uint) ([]Step, error) {
+	steps := []Step{}
+	if target > from {
+		var v uint
+		var err error
+		if from == 0 {
+			v, err = src.First()
+		} else {
+			v, err = src.Next(from)
+		}
+		for err == nil && v <= target {
+			step := Step{Version: v, Up: true}
+			step.SQL, step.Name, err = readStep(src.ReadUp(v))
+			if err != nil {
+				return nil, fmt.Errorf("migration %d up: %w", v, err)
+			}
+			steps = append(steps, step)
+			v, err = src.Next(v)
+		}
+		if err != nil && !isNotExist(err) {
+			return nil, err
+		}
+		if len(steps) == 0 || steps[len(steps)-1].Version != target {
+			return nil, fmt.Errorf("no migration %d", target)
+		}
+		return steps, nil
+	}
+	for v := from; v > target; {
+		step := Step{Version: v}
+		var err error
+		step.SQL, step.Name, err = readStep(src.ReadDown(v))
+		if err != nil {
+			return nil, fmt.Errorf("migration %d down: %w", v, err)
+		}
+		steps = append(steps, step)
+		prev, err := src.Prev(v)
+		if isNotExist(err) {
+			prev = 0
+		} else if err != nil {
+			return nil, err
+		}
+		if prev < target {
+			return nil, fmt.Errorf("no migration %d", target)
+		}
+		v = prev
+	}
+	return steps, nil
+}
+
+// Migrate the database up or down to version target, 0 to remove
+// everything.  src is closed afterwards.
+func MigrateTo(db *sql.DB, src source.Driver, target uint) error {
 	dbDriver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
 		return err
 	}
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://"+migrations_dir,
-		"postgres", dbDriver)
+	m, err := migrate.NewWithInstance("smd", src, "postgres", dbDriver)
 	if err != nil {
 		return err
 	}
 	defer m.Close()
-	err = m.Up()
+	if target == 0 {
+		err = m.Down()
+	} else {
+		err = m.Migrate(target)
+	}
 	if err != nil && err != migrate.ErrNoChange {
 		return err
 	}
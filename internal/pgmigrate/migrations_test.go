@@ -0,0 +1,91 @@
+package pgmigrate
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+func TestPlan(t *testing.T) {
+	src, err := Source("")
+	if err != nil {
+		t.Fatalf("Can't open built-in migrations: %s", err)
+	}
+	defer src.Close()
+	latest, err := Latest(src)
+	if err != nil || latest < 34 {
+		t.Fatalf("Unexpected latest migration %d: %v", latest, err)
+	}
+
+	// Everything, in order, and back again.
+	steps, err := Plan(src, 0, latest)
+	if err != nil || uint(len(steps)) != latest {
+		t.Fatalf("Expected %d steps up, got %d: %v", latest, len(steps), err)
+	}
+	for i, step := range steps {
+		if step.Version != uint(i+1) || !step.Up || step.SQL == "" {
+			t.Errorf("Unexpected step %d: %d %s up: %t", i, step.Version,
+				step.Name, step.Up)
+		}
+	}
+	steps, err = Plan(src, latest, 0)
+	if err != nil || uint(len(steps)) != latest {
+		t.Fatalf("Expected %d steps down, got %d: %v", latest, len(steps), err)
+	}
+	for i, step := range steps {
+		if step.Version != latest-uint(i) || step.Up || step.SQL == "" {
+			t.Errorf("Unexpected step %d: %d %s up: %t", i, step.Version,
+				step.Name, step.Up)
+		}
+	}
+
+	steps, err = Plan(src, 32, 34)
+	if err != nil || len(steps) != 2 || steps[0].Version != 33 ||
+		steps[1].Name != "discovery_schedules_version32" ||
+		!strings.Contains(steps[1].SQL, "CREATE TABLE IF NOT EXISTS discovery_schedules") {
+		t.Errorf("Unexpected steps from 32 to 34: %+v, %v", steps, err)
+	}
+	steps, err = Plan(src, 34, 33)
+	if err != nil || len(steps) != 1 || steps[0].Up ||
+		!strings.Contains(steps[0].SQL, "DROP TABLE IF EXISTS discovery_schedules") {
+		t.Errorf("Unexpected steps from 34 to 33: %+v, %v", steps, err)
+	}
+	if steps, err = Plan(src, latest, latest); err != nil || len(steps) != 0 {
+		t.Errorf("Expected nothing to do, got %+v, %v", steps, err)
+	}
+	if _, err = Plan(src, 0, latest+1); err == nil {
+		t.Errorf("Expected an error migrating past the last migration")
+	}
+}
+
+func TestCurrentVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Can't create mock: %s", err)
+	}
+	defer db.Close()
+	query := regexp.QuoteMeta(`SELECT version, dirty FROM schema_migrations LIMIT 1`)
+
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"version", "dirty"}).AddRow(33, true))
+	if v, dirty, err := CurrentVersion(db); err != nil || v != 33 || !dirty {
+		t.Errorf("Expected 33, dirty, got %d, %t, %v", v, dirty, err)
+	}
+
+	// Nothing applied yet
+	mock.ExpectQuery(query).WillReturnError(&pq.Error{Code: "42P01"})
+	if v, dirty, err := CurrentVersion(db); err != nil || v != 0 || dirty {
+		t.Errorf("Expected 0, got %d, %t, %v", v, dirty, err)
+	}
+	mock.ExpectQuery(query).WillReturnRows(
+		sqlmock.NewRows([]string{"version", "dirty"}))
+	if v, _, err := CurrentVersion(db); err != nil || v != 0 {
+		t.Errorf("Expected 0, got %d, %v", v, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Sql expectations were not met: %s", err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds a history of component states so components can be queried as they
+-- were at some time in the past.
+
+BEGIN;
+
+-- Each row is a snapshot of a component after it was added or changed, or
+-- when it was deleted (deleted = TRUE).  seq orders events with the same
+-- timestamp, e.g. several in the same transaction.
+CREATE TABLE IF NOT EXISTS comp_state_hist (
+    "seq"                  BIGSERIAL    PRIMARY KEY,
+    "id"                   VARCHAR(63)  NOT NULL,
+    "type"                 VARCHAR(63)  NOT NULL,
+    "state"                VARCHAR(32)  NOT NULL,
+    "admin"                VARCHAR(32)  NOT NULL DEFAULT '',
+    "enabled"              BOOL         NOT NULL DEFAULT '1',
+    "flag"                 VARCHAR(32)  NOT NULL,
+    "role"                 VARCHAR(32)  NOT NULL,
+    "subrole"              VARCHAR(32)  NOT NULL DEFAULT '',
+    "nid"                  BIGINT       NOT NULL,
+    "subtype"              VARCHAR(64)  NOT NULL,
+    "nettype"              VARCHAR(64)  NOT NULL,
+    "arch"                 VARCHAR(64)  NOT NULL,
+    "class"                VARCHAR(32)  NOT NULL DEFAULT '',
+    "reservation_disabled" BOOL         NOT NULL DEFAULT FALSE,
+    "locked"               BOOL         NOT NULL DEFAULT FALSE,
+    "deleted"              BOOL         NOT NULL DEFAULT FALSE,
+    "timestamp"            TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comp_state_hist_id_seq_idx ON comp_state_hist(id, seq);
+
+CREATE INDEX IF NOT EXISTS comp_state_hist_timestamp_idx ON comp_state_hist(timestamp);
+
+CREATE OR REPLACE FUNCTION comp_state_hist_record()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO comp_state_hist (id, type, state, admin, enabled, flag,
+            role, subrole, nid, subtype, nettype, arch, class,
+            reservation_disabled, locked, deleted)
+        VALUES (OLD.id, OLD.type, OLD.state, OLD.admin, OLD.enabled, OLD.flag,
+            OLD.role, OLD.subrole, OLD.nid, OLD.subtype, OLD.nettype, OLD.arch,
+            OLD.class, OLD.reservation_disabled, OLD.locked, TRUE);
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD IS NOT DISTINCT FROM NEW THEN
+        RETURN NULL;
+    END IF;
+    INSERT INTO comp_state_hist (id, type, state, admin, enabled, flag,
+        role, subrole, nid, subtype, nettype, arch, class,
+        reservation_disabled, locked)
+    VALUES (NEW.id, NEW.type, NEW.state, NEW.admin, NEW.enabled, NEW.flag,
+        NEW.role, NEW.subrole, NEW.nid, NEW.subtype, NEW.nettype, NEW.arch,
+        NEW.class, NEW.reservation_disabled, NEW.locked);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comp_state_hist_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON components
+    FOR EACH ROW EXECUTE PROCEDURE comp_state_hist_record();
+
+-- Start the history with the components as they are now.
+INSERT INTO comp_state_hist (id, type, state, admin, enabled, flag,
+    role, subrole, nid, subtype, nettype, arch, class,
+    reservation_disabled, locked)
+SELECT id, type, state, admin, enabled, flag, role, subrole, nid, subtype,
+    nettype, arch, class, reservation_disabled, locked
+FROM components;
+
+-- Bump the schema version
+insert into system values(0, 21, '{}'::JSON)
+    on conflict(id) do update set schema_version=21;
+
+COMMIT;
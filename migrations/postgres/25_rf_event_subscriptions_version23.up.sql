@@ -0,0 +1,45 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds the Redfish event subscriptions SMD keeps on RedfishEndpoints,
+-- pointing at the event collector, and their state as of the last time
+-- they were created, verified or repaired.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rf_event_subscriptions (
+    "rf_endpoint_id"   VARCHAR(63)   PRIMARY KEY,
+    "destination"      VARCHAR(1024) NOT NULL,
+    "context"          VARCHAR(256)  NOT NULL DEFAULT '',
+    "subscription_uri" VARCHAR(512)  NOT NULL DEFAULT '',
+    "status"           VARCHAR(32)   NOT NULL,
+    "last_error"       TEXT          NOT NULL DEFAULT '',
+    "last_updated"     TIMESTAMPTZ   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY("rf_endpoint_id") REFERENCES rf_endpoints("id") ON DELETE CASCADE
+);
+
+-- Bump the schema version
+insert into system values(0, 23, '{}'::JSON)
+    on conflict(id) do update set schema_version=23;
+
+COMMIT;
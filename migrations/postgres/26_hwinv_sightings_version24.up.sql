@@ -0,0 +1,46 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds the hwinv_sightings table.  Each row is a stint of a FRU at one
+-- location: when discovery first and last saw it there.  A FRU that moves
+-- to another location starts a new row, so its rows ordered by first_seen
+-- give its movement over time.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS hwinv_sightings (
+    "fru_id"     VARCHAR(255) NOT NULL,
+    "id"         VARCHAR(63)  NOT NULL,
+    "first_seen" TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    "last_seen"  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY("fru_id", "first_seen")
+);
+
+CREATE INDEX IF NOT EXISTS hwinvsightings_id_idx ON hwinv_sightings(id);
+CREATE INDEX IF NOT EXISTS hwinvsightings_last_seen_idx ON hwinv_sightings(last_seen);
+
+-- Bump the schema version
+insert into system values(0, 24, '{}'::JSON)
+    on conflict(id) do update set schema_version=24;
+
+COMMIT;
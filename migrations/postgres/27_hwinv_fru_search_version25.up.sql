@@ -0,0 +1,43 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Fills in the serial_number, part_number and manufacturer columns of
+-- hwinv_by_fru from fru_info and indexes them, so FRUs can be looked up by
+-- serial or part number without scanning the JSON of every FRU.
+
+BEGIN;
+
+UPDATE hwinv_by_fru SET
+    serial_number = COALESCE(fru_info->>'SerialNumber', ''),
+    part_number   = COALESCE(fru_info->>'PartNumber', ''),
+    manufacturer  = COALESCE(fru_info->>'Manufacturer', '');
+
+CREATE INDEX IF NOT EXISTS hwinvbyfru_serial_number_idx ON hwinv_by_fru(serial_number);
+CREATE INDEX IF NOT EXISTS hwinvbyfru_part_number_idx ON hwinv_by_fru(part_number);
+CREATE INDEX IF NOT EXISTS hwinvbyloc_fru_id_idx ON hwinv_by_loc(fru_id);
+
+-- Bump the schema version
+insert into system values(0, 25, '{}'::JSON)
+    on conflict(id) do update set schema_version=25;
+
+COMMIT;
@@ -0,0 +1,44 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Lets groups contain other groups.  Each row makes child_id a child of
+-- parent_id.  Both must be groups (not partitions); this and the absence of
+-- cycles are checked by HSM when the children are set.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS component_group_children (
+    "parent_id" UUID NOT NULL,
+    "child_id"  UUID NOT NULL,
+    FOREIGN KEY ("parent_id") REFERENCES component_groups ("id") ON DELETE CASCADE,
+    FOREIGN KEY ("child_id") REFERENCES component_groups ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("parent_id", "child_id")
+);
+
+CREATE INDEX IF NOT EXISTS component_group_children_child_idx ON component_group_children(child_id);
+
+-- Bump the schema version
+insert into system values(0, 26, '{}'::JSON)
+    on conflict(id) do update set schema_version=26;
+
+COMMIT;
@@ -0,0 +1,38 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Removes the group and partition membership history.
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS comp_group_delete_hist_trigger ON component_groups;
+DROP FUNCTION IF EXISTS comp_group_delete_hist_record();
+DROP TRIGGER IF EXISTS comp_group_members_hist_trigger ON component_group_members;
+DROP FUNCTION IF EXISTS comp_group_members_hist_record();
+DROP TABLE IF EXISTS comp_group_members_hist;
+
+-- Decrease the schema version
+INSERT INTO system VALUES(0, 26, '{}'::JSON)
+    ON CONFLICT(id) DO UPDATE SET schema_version=26;
+
+COMMIT;
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds a history of group and partition membership changes, i.e. who added
+-- or removed which members and when.
+
+BEGIN;
+
+-- Each row is a member being added to or removed from a group or partition.
+-- The actor is whoever made the change, as set by HSM with the smd.actor
+-- setting for the transaction, or empty if it is not known.  seq orders
+-- changes with the same timestamp.
+CREATE TABLE IF NOT EXISTS comp_group_members_hist (
+    "seq"          BIGSERIAL    PRIMARY KEY,
+    "group_name"   VARCHAR(255) NOT NULL,
+    "namespace"    VARCHAR(255) NOT NULL,
+    "component_id" VARCHAR(63)  NOT NULL,
+    "action"       VARCHAR(16)  NOT NULL,
+    "actor"        VARCHAR(255) NOT NULL DEFAULT '',
+    "timestamp"    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comp_group_members_hist_name_seq_idx
+    ON comp_group_members_hist(group_name, namespace, seq);
+
+CREATE OR REPLACE FUNCTION comp_group_members_hist_record()
+RETURNS TRIGGER AS $$
+DECLARE
+    gname VARCHAR(255);
+    gnamespace VARCHAR(255);
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        SELECT name, namespace INTO gname, gnamespace
+        FROM component_groups WHERE id = OLD.group_id;
+        -- The group itself is being deleted; that was recorded already.
+        IF NOT FOUND THEN
+            RETURN NULL;
+        END IF;
+        INSERT INTO comp_group_members_hist (group_name, namespace,
+            component_id, action, actor)
+        VALUES (gname, gnamespace, OLD.component_id, 'removed',
+            COALESCE(current_setting('smd.actor', true), ''));
+        RETURN NULL;
+    END IF;
+    SELECT name, namespace INTO gname, gnamespace
+    FROM component_groups WHERE id = NEW.group_id;
+    INSERT INTO comp_group_members_hist (group_name, namespace,
+        component_id, action, actor)
+    VALUES (gname, gnamespace, NEW.component_id, 'added',
+        COALESCE(current_setting('smd.actor', true), ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comp_group_members_hist_trigger
+    AFTER INSERT OR DELETE ON component_group_members
+    FOR EACH ROW EXECUTE PROCEDURE comp_group_members_hist_record();
+
+-- Deleting a group or partition removes all of its members.  Record this
+-- before they are gone, since the members are deleted by cascade after the
+-- group is.
+CREATE OR REPLACE FUNCTION comp_group_delete_hist_record()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO comp_group_members_hist (group_name, namespace,
+        component_id, action, actor)
+    SELECT OLD.name, OLD.namespace, component_id, 'removed',
+        COALESCE(current_setting('smd.actor', true), '')
+    FROM component_group_members WHERE group_id = OLD.id
+    ORDER BY component_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comp_group_delete_hist_trigger
+    BEFORE DELETE ON component_groups
+    FOR EACH ROW EXECUTE PROCEDURE comp_group_delete_hist_record();
+
+-- Bump the schema version
+insert into system values(0, 27, '{}'::JSON)
+    on conflict(id) do update set schema_version=27;
+
+COMMIT;
@@ -0,0 +1,143 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds an audit log of component lock and reservation changes, i.e. who
+-- locked, reserved, renewed or released which components, when and why.
+
+BEGIN;
+
+-- Each row is one change to the lock or reservation of a component.  The
+-- actor and reason are set by HSM with the smd.actor and smd.lock_reason
+-- settings for the transaction, or are empty if not known.  smd.lock_action
+-- overrides the action recorded for released reservations, e.g. when they
+-- are forcibly removed.  seq orders changes with the same timestamp.
+CREATE TABLE IF NOT EXISTS comp_lock_audit (
+    "seq"          BIGSERIAL    PRIMARY KEY,
+    "component_id" VARCHAR(63)  NOT NULL,
+    "action"       VARCHAR(16)  NOT NULL,
+    "actor"        VARCHAR(255) NOT NULL DEFAULT '',
+    "reason"       TEXT         NOT NULL DEFAULT '',
+    "expiration"   TIMESTAMPTZ,
+    "timestamp"    TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS comp_lock_audit_component_id_seq_idx
+    ON comp_lock_audit(component_id, seq);
+CREATE INDEX IF NOT EXISTS comp_lock_audit_timestamp_idx
+    ON comp_lock_audit(timestamp);
+
+CREATE OR REPLACE FUNCTION comp_lock_audit_reservation_record()
+RETURNS TRIGGER AS $$
+DECLARE
+    act VARCHAR(16);
+    why TEXT;
+BEGIN
+    why := COALESCE(current_setting('smd.lock_reason', true), '');
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO comp_lock_audit (component_id, action, actor, reason,
+            expiration)
+        VALUES (NEW.component_id, 'Reserve',
+            COALESCE(current_setting('smd.actor', true), ''), why,
+            NEW.expiration_timestamp);
+        RETURN NULL;
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF NEW.expiration_timestamp IS NOT DISTINCT FROM
+           OLD.expiration_timestamp THEN
+            RETURN NULL;
+        END IF;
+        INSERT INTO comp_lock_audit (component_id, action, actor, reason,
+            expiration)
+        VALUES (NEW.component_id, 'Renew',
+            COALESCE(current_setting('smd.actor', true), ''), why,
+            NEW.expiration_timestamp);
+        RETURN NULL;
+    END IF;
+    act := NULLIF(COALESCE(current_setting('smd.lock_action', true), ''), '');
+    IF act IS NULL THEN
+        IF NOT EXISTS (SELECT 1 FROM components
+                       WHERE id = OLD.component_id) THEN
+            -- Deleting the component deletes its reservation.
+            act := 'ForceRelease';
+            IF why = '' THEN
+                why := 'component deleted';
+            END IF;
+        ELSIF OLD.expiration_timestamp IS NOT NULL AND
+              OLD.expiration_timestamp <= NOW() THEN
+            act := 'Expire';
+        ELSE
+            act := 'Release';
+        END IF;
+    END IF;
+    INSERT INTO comp_lock_audit (component_id, action, actor, reason,
+        expiration)
+    VALUES (OLD.component_id, act,
+        COALESCE(current_setting('smd.actor', true), ''), why,
+        OLD.expiration_timestamp);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comp_lock_audit_reservation_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON reservations
+    FOR EACH ROW EXECUTE PROCEDURE comp_lock_audit_reservation_record();
+
+CREATE OR REPLACE FUNCTION comp_lock_audit_component_record()
+RETURNS TRIGGER AS $$
+DECLARE
+    act VARCHAR(16);
+BEGIN
+    IF NEW.locked IS DISTINCT FROM OLD.locked THEN
+        IF NEW.locked THEN
+            act := 'Lock';
+        ELSE
+            act := 'Unlock';
+        END IF;
+        INSERT INTO comp_lock_audit (component_id, action, actor, reason)
+        VALUES (NEW.id, act,
+            COALESCE(current_setting('smd.actor', true), ''),
+            COALESCE(current_setting('smd.lock_reason', true), ''));
+    END IF;
+    IF NEW.reservation_disabled IS DISTINCT FROM OLD.reservation_disabled THEN
+        IF NEW.reservation_disabled THEN
+            act := 'Disable';
+        ELSE
+            act := 'Repair';
+        END IF;
+        INSERT INTO comp_lock_audit (component_id, action, actor, reason)
+        VALUES (NEW.id, act,
+            COALESCE(current_setting('smd.actor', true), ''),
+            COALESCE(current_setting('smd.lock_reason', true), ''));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comp_lock_audit_component_trigger
+    AFTER UPDATE OF locked, reservation_disabled ON components
+    FOR EACH ROW EXECUTE PROCEDURE comp_lock_audit_component_record();
+
+-- Bump the schema version
+insert into system values(0, 28, '{}'::JSON)
+    on conflict(id) do update set schema_version=28;
+
+COMMIT;
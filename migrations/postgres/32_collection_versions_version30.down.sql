@@ -0,0 +1,41 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Removes the collection change counters.
+
+BEGIN;
+
+DROP TRIGGER IF EXISTS hwinv_by_fru_version_trigger ON hwinv_by_fru;
+DROP TRIGGER IF EXISTS hwinv_by_loc_version_trigger ON hwinv_by_loc;
+DROP TRIGGER IF EXISTS component_group_children_version_trigger ON component_group_children;
+DROP TRIGGER IF EXISTS component_group_members_version_trigger ON component_group_members;
+DROP TRIGGER IF EXISTS component_groups_version_trigger ON component_groups;
+DROP TRIGGER IF EXISTS components_version_trigger ON components;
+DROP FUNCTION IF EXISTS collection_version_bump();
+DROP TABLE IF EXISTS collection_versions;
+
+-- Decrease the schema version
+INSERT INTO system VALUES(0, 29, '{}'::JSON)
+    ON CONFLICT(id) DO UPDATE SET schema_version=29;
+
+COMMIT;
@@ -0,0 +1,94 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds change counters for the collections clients poll (components, groups
+-- and partitions, hardware inventory), which HSM uses for ETags.
+
+BEGIN;
+
+-- One row, with a counter per collection.  Each transaction that changes a
+-- collection bumps its counter once, when it commits, so the row is only
+-- locked while committing and the counter changes together with the data.
+-- groups covers groups and partitions, their members and children.  hwinv
+-- covers hardware inventory by location and by FRU.
+CREATE TABLE IF NOT EXISTS collection_versions (
+    "id"         INT    PRIMARY KEY,
+    "components" BIGINT NOT NULL DEFAULT 0,
+    "groups"     BIGINT NOT NULL DEFAULT 0,
+    "hwinv"      BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO collection_versions (id) VALUES (0) ON CONFLICT DO NOTHING;
+
+-- TG_ARGV[0] is the counter.  The smd.version_bumped_<counter> setting,
+-- local to the transaction, stops it being bumped for every row changed.
+CREATE OR REPLACE FUNCTION collection_version_bump()
+RETURNS TRIGGER AS $$
+DECLARE
+    flag TEXT := 'smd.version_bumped_' || TG_ARGV[0];
+BEGIN
+    IF current_setting(flag, true) = '1' THEN
+        RETURN NULL;
+    END IF;
+    PERFORM set_config(flag, '1', true);
+    EXECUTE format('UPDATE collection_versions SET %I = %I + 1 WHERE id = 0',
+        TG_ARGV[0], TG_ARGV[0]);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER components_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON components
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('components');
+
+CREATE CONSTRAINT TRIGGER component_groups_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON component_groups
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('groups');
+
+CREATE CONSTRAINT TRIGGER component_group_members_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON component_group_members
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('groups');
+
+CREATE CONSTRAINT TRIGGER component_group_children_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON component_group_children
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('groups');
+
+CREATE CONSTRAINT TRIGGER hwinv_by_loc_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON hwinv_by_loc
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('hwinv');
+
+CREATE CONSTRAINT TRIGGER hwinv_by_fru_version_trigger
+    AFTER INSERT OR UPDATE OR DELETE ON hwinv_by_fru
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE PROCEDURE collection_version_bump('hwinv');
+
+-- Bump the schema version
+insert into system values(0, 30, '{}'::JSON)
+    on conflict(id) do update set schema_version=30;
+
+COMMIT;
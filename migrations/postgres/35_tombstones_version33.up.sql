@@ -0,0 +1,70 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds tombstones for deleted components and RedfishEndpoints, so they can
+-- be restored.
+
+BEGIN;
+
+-- Each row is a component or RedfishEndpoint as it was when deleted.  The
+-- actor is whoever deleted it, as set by HSM with the smd.actor setting for
+-- the transaction, or empty if it is not known.  seq orders deletions with
+-- the same timestamp.
+CREATE TABLE IF NOT EXISTS tombstones (
+    "seq"          BIGSERIAL    PRIMARY KEY,
+    "kind"         VARCHAR(32)  NOT NULL,
+    "id"           VARCHAR(63)  NOT NULL,
+    "data"         JSONB        NOT NULL,
+    "actor"        VARCHAR(255) NOT NULL DEFAULT '',
+    "deleted"      TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS tombstones_kind_id_seq_idx
+    ON tombstones(kind, id, seq);
+CREATE INDEX IF NOT EXISTS tombstones_deleted_idx
+    ON tombstones(deleted);
+
+-- The kind of row deleted is the trigger's argument.
+CREATE OR REPLACE FUNCTION tombstone_record()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO tombstones (kind, id, data, actor)
+    VALUES (TG_ARGV[0], OLD.id, to_jsonb(OLD),
+        COALESCE(current_setting('smd.actor', true), ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER components_tombstone_trigger
+    AFTER DELETE ON components
+    FOR EACH ROW EXECUTE PROCEDURE tombstone_record('Component');
+
+CREATE TRIGGER rf_endpoints_tombstone_trigger
+    AFTER DELETE ON rf_endpoints
+    FOR EACH ROW EXECUTE PROCEDURE tombstone_record('RedfishEndpoint');
+
+-- Bump the schema version
+insert into system values(0, 33, '{}'::JSON)
+    on conflict(id) do update set schema_version=33;
+
+COMMIT;
@@ -0,0 +1,59 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Stops recording who made each change in the component state history.
+
+BEGIN;
+
+CREATE OR REPLACE FUNCTION comp_state_hist_record()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO comp_state_hist (id, type, state, admin, enabled, flag,
+            role, subrole, nid, subtype, nettype, arch, class,
+            reservation_disabled, locked, deleted)
+        VALUES (OLD.id, OLD.type, OLD.state, OLD.admin, OLD.enabled, OLD.flag,
+            OLD.role, OLD.subrole, OLD.nid, OLD.subtype, OLD.nettype, OLD.arch,
+            OLD.class, OLD.reservation_disabled, OLD.locked, TRUE);
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND OLD IS NOT DISTINCT FROM NEW THEN
+        RETURN NULL;
+    END IF;
+    INSERT INTO comp_state_hist (id, type, state, admin, enabled, flag,
+        role, subrole, nid, subtype, nettype, arch, class,
+        reservation_disabled, locked)
+    VALUES (NEW.id, NEW.type, NEW.state, NEW.admin, NEW.enabled, NEW.flag,
+        NEW.role, NEW.subrole, NEW.nid, NEW.subtype, NEW.nettype, NEW.arch,
+        NEW.class, NEW.reservation_disabled, NEW.locked);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE comp_state_hist DROP COLUMN IF EXISTS "actor";
+
+-- Decrease the schema version
+INSERT INTO system VALUES(0, 33, '{}'::JSON)
+    ON CONFLICT(id) DO UPDATE SET schema_version=33;
+
+COMMIT;
@@ -0,0 +1,47 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds NID assignment policies and the NIDs they have assigned.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS nid_policies (
+    "name"     VARCHAR(255) PRIMARY KEY,
+    "policy"   JSON         NOT NULL
+);
+
+-- A node keeps its assigned NID, even if it is deleted and rediscovered,
+-- until the assignment is removed.  The NID is unique so HSM instances
+-- assigning NIDs at the same time can't assign the same one.
+CREATE TABLE IF NOT EXISTS nid_assignments (
+    "id"       VARCHAR(63)  PRIMARY KEY,
+    "nid"      BIGINT       NOT NULL UNIQUE,
+    "policy"   VARCHAR(255) NOT NULL,
+    "assigned" TIMESTAMPTZ  NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bump the schema version
+insert into system values(0, 36, '{}'::JSON)
+    on conflict(id) do update set schema_version=36;
+
+COMMIT;
@@ -0,0 +1,43 @@
+/*
+ * MIT License
+ *
+ * (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a
+ * copy of this software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation
+ * the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the
+ * Software is furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included
+ * in all copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+ * THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+ * OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+ * ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+ * OTHER DEALINGS IN THE SOFTWARE.
+ */
+-- Adds the liveness of each RedfishEndpoint as last probed by the BMC
+-- prober: when it was last reached, and whether it is now unreachable.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rf_endpoint_liveness (
+    "rf_endpoint_id" VARCHAR(63) PRIMARY KEY,
+    "last_contact"   TIMESTAMPTZ,
+    "last_probe"     TIMESTAMPTZ NOT NULL,
+    "failures"       INT         NOT NULL DEFAULT 0,
+    "unreachable"    BOOLEAN     NOT NULL DEFAULT FALSE,
+    "last_error"     TEXT        NOT NULL DEFAULT '',
+    FOREIGN KEY("rf_endpoint_id") REFERENCES rf_endpoints("id") ON DELETE CASCADE
+);
+
+-- Bump the schema version
+insert into system values(0, 37, '{}'::JSON)
+    on conflict(id) do update set schema_version=37;
+
+COMMIT;
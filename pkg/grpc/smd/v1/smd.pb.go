@@ -0,0 +1,1474 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// gRPC API for services that read HSM state on hot paths, e.g. PCS, without
+// the cost of the JSON REST API.  It covers reads only: Components,
+// ComponentEndpoints and hardware inventory by location, plus a stream of
+// component state changes.  Changes still go through the REST API.
+//
+// Regenerate the Go code in pkg/grpc/smd/v1 with:
+//
+//     protoc -I api/proto --go_out=pkg/grpc --go_opt=paths=source_relative \
+//         --go-grpc_out=pkg/grpc --go-grpc_opt=paths=source_relative \
+//         smd/v1/smd.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: smd/v1/smd.proto
+
+package smdv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HMS Component, as in GET /State/Components/{xname}.
+type Component struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	State               string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Flag                string                 `protobuf:"bytes,4,opt,name=flag,proto3" json:"flag,omitempty"`
+	Enabled             *bool                  `protobuf:"varint,5,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	SoftwareStatus      string                 `protobuf:"bytes,6,opt,name=software_status,json=softwareStatus,proto3" json:"software_status,omitempty"`
+	Role                string                 `protobuf:"bytes,7,opt,name=role,proto3" json:"role,omitempty"`
+	SubRole             string                 `protobuf:"bytes,8,opt,name=sub_role,json=subRole,proto3" json:"sub_role,omitempty"`
+	Nid                 *int64                 `protobuf:"varint,9,opt,name=nid,proto3,oneof" json:"nid,omitempty"`
+	Subtype             string                 `protobuf:"bytes,10,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	NetType             string                 `protobuf:"bytes,11,opt,name=net_type,json=netType,proto3" json:"net_type,omitempty"`
+	Arch                string                 `protobuf:"bytes,12,opt,name=arch,proto3" json:"arch,omitempty"`
+	Class               string                 `protobuf:"bytes,13,opt,name=class,proto3" json:"class,omitempty"`
+	ReservationDisabled bool                   `protobuf:"varint,14,opt,name=reservation_disabled,json=reservationDisabled,proto3" json:"reservation_disabled,omitempty"`
+	Locked              bool                   `protobuf:"varint,15,opt,name=locked,proto3" json:"locked,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Component) Reset() {
+	*x = Component{}
+	mi := &file_smd_v1_smd_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Component) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Component) ProtoMessage() {}
+
+func (x *Component) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Component.ProtoReflect.Descriptor instead.
+func (*Component) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Component) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Component) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Component) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Component) GetFlag() string {
+	if x != nil {
+		return x.Flag
+	}
+	return ""
+}
+
+func (x *Component) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *Component) GetSoftwareStatus() string {
+	if x != nil {
+		return x.SoftwareStatus
+	}
+	return ""
+}
+
+func (x *Component) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Component) GetSubRole() string {
+	if x != nil {
+		return x.SubRole
+	}
+	return ""
+}
+
+func (x *Component) GetNid() int64 {
+	if x != nil && x.Nid != nil {
+		return *x.Nid
+	}
+	return 0
+}
+
+func (x *Component) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *Component) GetNetType() string {
+	if x != nil {
+		return x.NetType
+	}
+	return ""
+}
+
+func (x *Component) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *Component) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *Component) GetReservationDisabled() bool {
+	if x != nil {
+		return x.ReservationDisabled
+	}
+	return false
+}
+
+func (x *Component) GetLocked() bool {
+	if x != nil {
+		return x.Locked
+	}
+	return false
+}
+
+type GetComponentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetComponentRequest) Reset() {
+	*x = GetComponentRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetComponentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetComponentRequest) ProtoMessage() {}
+
+func (x *GetComponentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetComponentRequest.ProtoReflect.Descriptor instead.
+func (*GetComponentRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetComponentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Same filters as the query parameters of GET /State/Components.
+type ListComponentsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        []string               `protobuf:"bytes,1,rep,name=id,proto3" json:"id,omitempty"`
+	Type      []string               `protobuf:"bytes,2,rep,name=type,proto3" json:"type,omitempty"`
+	State     []string               `protobuf:"bytes,3,rep,name=state,proto3" json:"state,omitempty"`
+	Flag      []string               `protobuf:"bytes,4,rep,name=flag,proto3" json:"flag,omitempty"`
+	Role      []string               `protobuf:"bytes,5,rep,name=role,proto3" json:"role,omitempty"`
+	SubRole   []string               `protobuf:"bytes,6,rep,name=sub_role,json=subRole,proto3" json:"sub_role,omitempty"`
+	Class     []string               `protobuf:"bytes,7,rep,name=class,proto3" json:"class,omitempty"`
+	Arch      []string               `protobuf:"bytes,8,rep,name=arch,proto3" json:"arch,omitempty"`
+	Group     []string               `protobuf:"bytes,9,rep,name=group,proto3" json:"group,omitempty"`
+	Partition []string               `protobuf:"bytes,10,rep,name=partition,proto3" json:"partition,omitempty"`
+	Nid       []string               `protobuf:"bytes,11,rep,name=nid,proto3" json:"nid,omitempty"`
+	// Only return the ID, Type, State and Flag of each Component.
+	StateOnly     bool `protobuf:"varint,12,opt,name=state_only,json=stateOnly,proto3" json:"state_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComponentsRequest) Reset() {
+	*x = ListComponentsRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentsRequest) ProtoMessage() {}
+
+func (x *ListComponentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentsRequest.ProtoReflect.Descriptor instead.
+func (*ListComponentsRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListComponentsRequest) GetId() []string {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetType() []string {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetState() []string {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetFlag() []string {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetRole() []string {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetSubRole() []string {
+	if x != nil {
+		return x.SubRole
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetClass() []string {
+	if x != nil {
+		return x.Class
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetArch() []string {
+	if x != nil {
+		return x.Arch
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetGroup() []string {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetPartition() []string {
+	if x != nil {
+		return x.Partition
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetNid() []string {
+	if x != nil {
+		return x.Nid
+	}
+	return nil
+}
+
+func (x *ListComponentsRequest) GetStateOnly() bool {
+	if x != nil {
+		return x.StateOnly
+	}
+	return false
+}
+
+type ListComponentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Components    []*Component           `protobuf:"bytes,1,rep,name=components,proto3" json:"components,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComponentsResponse) Reset() {
+	*x = ListComponentsResponse{}
+	mi := &file_smd_v1_smd_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentsResponse) ProtoMessage() {}
+
+func (x *ListComponentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentsResponse.ProtoReflect.Descriptor instead.
+func (*ListComponentsResponse) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListComponentsResponse) GetComponents() []*Component {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+// ComponentEndpoint, as in GET /Inventory/ComponentEndpoints/{xname}.
+type ComponentEndpoint struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                  string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Domain                string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Fqdn                  string                 `protobuf:"bytes,4,opt,name=fqdn,proto3" json:"fqdn,omitempty"`
+	RedfishType           string                 `protobuf:"bytes,5,opt,name=redfish_type,json=redfishType,proto3" json:"redfish_type,omitempty"`
+	RedfishSubtype        string                 `protobuf:"bytes,6,opt,name=redfish_subtype,json=redfishSubtype,proto3" json:"redfish_subtype,omitempty"`
+	MacAddr               string                 `protobuf:"bytes,7,opt,name=mac_addr,json=macAddr,proto3" json:"mac_addr,omitempty"`
+	Uuid                  string                 `protobuf:"bytes,8,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	OdataId               string                 `protobuf:"bytes,9,opt,name=odata_id,json=odataId,proto3" json:"odata_id,omitempty"`
+	RedfishEndpointId     string                 `protobuf:"bytes,10,opt,name=redfish_endpoint_id,json=redfishEndpointId,proto3" json:"redfish_endpoint_id,omitempty"`
+	Enabled               bool                   `protobuf:"varint,11,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	RedfishEndpointFqdn   string                 `protobuf:"bytes,12,opt,name=redfish_endpoint_fqdn,json=redfishEndpointFqdn,proto3" json:"redfish_endpoint_fqdn,omitempty"`
+	RedfishUrl            string                 `protobuf:"bytes,13,opt,name=redfish_url,json=redfishUrl,proto3" json:"redfish_url,omitempty"`
+	ComponentEndpointType string                 `protobuf:"bytes,14,opt,name=component_endpoint_type,json=componentEndpointType,proto3" json:"component_endpoint_type,omitempty"`
+	// The JSON Redfish*Info object matching component_endpoint_type, which
+	// varies too much by type to be worth a message of its own.
+	Info          []byte `protobuf:"bytes,15,opt,name=info,proto3" json:"info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentEndpoint) Reset() {
+	*x = ComponentEndpoint{}
+	mi := &file_smd_v1_smd_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentEndpoint) ProtoMessage() {}
+
+func (x *ComponentEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentEndpoint.ProtoReflect.Descriptor instead.
+func (*ComponentEndpoint) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ComponentEndpoint) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetFqdn() string {
+	if x != nil {
+		return x.Fqdn
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetRedfishType() string {
+	if x != nil {
+		return x.RedfishType
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetRedfishSubtype() string {
+	if x != nil {
+		return x.RedfishSubtype
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetMacAddr() string {
+	if x != nil {
+		return x.MacAddr
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetOdataId() string {
+	if x != nil {
+		return x.OdataId
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetRedfishEndpointId() string {
+	if x != nil {
+		return x.RedfishEndpointId
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ComponentEndpoint) GetRedfishEndpointFqdn() string {
+	if x != nil {
+		return x.RedfishEndpointFqdn
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetRedfishUrl() string {
+	if x != nil {
+		return x.RedfishUrl
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetComponentEndpointType() string {
+	if x != nil {
+		return x.ComponentEndpointType
+	}
+	return ""
+}
+
+func (x *ComponentEndpoint) GetInfo() []byte {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type GetComponentEndpointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetComponentEndpointRequest) Reset() {
+	*x = GetComponentEndpointRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetComponentEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetComponentEndpointRequest) ProtoMessage() {}
+
+func (x *GetComponentEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetComponentEndpointRequest.ProtoReflect.Descriptor instead.
+func (*GetComponentEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetComponentEndpointRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Same filters as the query parameters of GET /Inventory/ComponentEndpoints.
+type ListComponentEndpointsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []string               `protobuf:"bytes,1,rep,name=id,proto3" json:"id,omitempty"`
+	RedfishEp     []string               `protobuf:"bytes,2,rep,name=redfish_ep,json=redfishEp,proto3" json:"redfish_ep,omitempty"`
+	Type          []string               `protobuf:"bytes,3,rep,name=type,proto3" json:"type,omitempty"`
+	RedfishType   []string               `protobuf:"bytes,4,rep,name=redfish_type,json=redfishType,proto3" json:"redfish_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListComponentEndpointsRequest) Reset() {
+	*x = ListComponentEndpointsRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentEndpointsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentEndpointsRequest) ProtoMessage() {}
+
+func (x *ListComponentEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListComponentEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListComponentEndpointsRequest) GetId() []string {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ListComponentEndpointsRequest) GetRedfishEp() []string {
+	if x != nil {
+		return x.RedfishEp
+	}
+	return nil
+}
+
+func (x *ListComponentEndpointsRequest) GetType() []string {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *ListComponentEndpointsRequest) GetRedfishType() []string {
+	if x != nil {
+		return x.RedfishType
+	}
+	return nil
+}
+
+type ListComponentEndpointsResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ComponentEndpoints []*ComponentEndpoint   `protobuf:"bytes,1,rep,name=component_endpoints,json=componentEndpoints,proto3" json:"component_endpoints,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListComponentEndpointsResponse) Reset() {
+	*x = ListComponentEndpointsResponse{}
+	mi := &file_smd_v1_smd_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListComponentEndpointsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListComponentEndpointsResponse) ProtoMessage() {}
+
+func (x *ListComponentEndpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListComponentEndpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListComponentEndpointsResponse) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListComponentEndpointsResponse) GetComponentEndpoints() []*ComponentEndpoint {
+	if x != nil {
+		return x.ComponentEndpoints
+	}
+	return nil
+}
+
+// Hardware inventory of a location, as in GET /Inventory/Hardware/{xname}.
+type HWInvByLoc struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	Id                        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                      string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Ordinal                   int32                  `protobuf:"varint,3,opt,name=ordinal,proto3" json:"ordinal,omitempty"`
+	Status                    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	HwInventoryByLocationType string                 `protobuf:"bytes,5,opt,name=hw_inventory_by_location_type,json=hwInventoryByLocationType,proto3" json:"hw_inventory_by_location_type,omitempty"`
+	// The JSON *LocationInfo object for the location's type.
+	LocationInfo []byte `protobuf:"bytes,6,opt,name=location_info,json=locationInfo,proto3" json:"location_info,omitempty"`
+	// Unset if the location is empty.
+	PopulatedFru  *HWInvByFRU `protobuf:"bytes,7,opt,name=populated_fru,json=populatedFru,proto3" json:"populated_fru,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HWInvByLoc) Reset() {
+	*x = HWInvByLoc{}
+	mi := &file_smd_v1_smd_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HWInvByLoc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HWInvByLoc) ProtoMessage() {}
+
+func (x *HWInvByLoc) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HWInvByLoc.ProtoReflect.Descriptor instead.
+func (*HWInvByLoc) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HWInvByLoc) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HWInvByLoc) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HWInvByLoc) GetOrdinal() int32 {
+	if x != nil {
+		return x.Ordinal
+	}
+	return 0
+}
+
+func (x *HWInvByLoc) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HWInvByLoc) GetHwInventoryByLocationType() string {
+	if x != nil {
+		return x.HwInventoryByLocationType
+	}
+	return ""
+}
+
+func (x *HWInvByLoc) GetLocationInfo() []byte {
+	if x != nil {
+		return x.LocationInfo
+	}
+	return nil
+}
+
+func (x *HWInvByLoc) GetPopulatedFru() *HWInvByFRU {
+	if x != nil {
+		return x.PopulatedFru
+	}
+	return nil
+}
+
+// The FRU installed at a location.
+type HWInvByFRU struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	FruId                string                 `protobuf:"bytes,1,opt,name=fru_id,json=fruId,proto3" json:"fru_id,omitempty"`
+	Type                 string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Subtype              string                 `protobuf:"bytes,3,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	HwInventoryByFruType string                 `protobuf:"bytes,4,opt,name=hw_inventory_by_fru_type,json=hwInventoryByFruType,proto3" json:"hw_inventory_by_fru_type,omitempty"`
+	// The JSON *FRUInfo object for the FRU's type.
+	FruInfo       []byte `protobuf:"bytes,5,opt,name=fru_info,json=fruInfo,proto3" json:"fru_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HWInvByFRU) Reset() {
+	*x = HWInvByFRU{}
+	mi := &file_smd_v1_smd_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HWInvByFRU) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HWInvByFRU) ProtoMessage() {}
+
+func (x *HWInvByFRU) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HWInvByFRU.ProtoReflect.Descriptor instead.
+func (*HWInvByFRU) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HWInvByFRU) GetFruId() string {
+	if x != nil {
+		return x.FruId
+	}
+	return ""
+}
+
+func (x *HWInvByFRU) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HWInvByFRU) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *HWInvByFRU) GetHwInventoryByFruType() string {
+	if x != nil {
+		return x.HwInventoryByFruType
+	}
+	return ""
+}
+
+func (x *HWInvByFRU) GetFruInfo() []byte {
+	if x != nil {
+		return x.FruInfo
+	}
+	return nil
+}
+
+type GetHWInvByLocRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHWInvByLocRequest) Reset() {
+	*x = GetHWInvByLocRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHWInvByLocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHWInvByLocRequest) ProtoMessage() {}
+
+func (x *GetHWInvByLocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHWInvByLocRequest.ProtoReflect.Descriptor instead.
+func (*GetHWInvByLocRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetHWInvByLocRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Same filters as the query parameters of GET /Inventory/Hardware.
+type ListHWInvByLocRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            []string               `protobuf:"bytes,1,rep,name=id,proto3" json:"id,omitempty"`
+	Type          []string               `protobuf:"bytes,2,rep,name=type,proto3" json:"type,omitempty"`
+	Manufacturer  []string               `protobuf:"bytes,3,rep,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	PartNumber    []string               `protobuf:"bytes,4,rep,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	SerialNumber  []string               `protobuf:"bytes,5,rep,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	FruId         []string               `protobuf:"bytes,6,rep,name=fru_id,json=fruId,proto3" json:"fru_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHWInvByLocRequest) Reset() {
+	*x = ListHWInvByLocRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHWInvByLocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHWInvByLocRequest) ProtoMessage() {}
+
+func (x *ListHWInvByLocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHWInvByLocRequest.ProtoReflect.Descriptor instead.
+func (*ListHWInvByLocRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListHWInvByLocRequest) GetId() []string {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *ListHWInvByLocRequest) GetType() []string {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *ListHWInvByLocRequest) GetManufacturer() []string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return nil
+}
+
+func (x *ListHWInvByLocRequest) GetPartNumber() []string {
+	if x != nil {
+		return x.PartNumber
+	}
+	return nil
+}
+
+func (x *ListHWInvByLocRequest) GetSerialNumber() []string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return nil
+}
+
+func (x *ListHWInvByLocRequest) GetFruId() []string {
+	if x != nil {
+		return x.FruId
+	}
+	return nil
+}
+
+type ListHWInvByLocResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locations     []*HWInvByLoc          `protobuf:"bytes,1,rep,name=locations,proto3" json:"locations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHWInvByLocResponse) Reset() {
+	*x = ListHWInvByLocResponse{}
+	mi := &file_smd_v1_smd_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHWInvByLocResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHWInvByLocResponse) ProtoMessage() {}
+
+func (x *ListHWInvByLocResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHWInvByLocResponse.ProtoReflect.Descriptor instead.
+func (*ListHWInvByLocResponse) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListHWInvByLocResponse) GetLocations() []*HWInvByLoc {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+type WatchComponentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resume after the change with this event_id, as with Last-Event-ID on
+	// GET /State/Components/Stream.  Empty to only get new changes.
+	ResumeAfter   string `protobuf:"bytes,1,opt,name=resume_after,json=resumeAfter,proto3" json:"resume_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchComponentsRequest) Reset() {
+	*x = WatchComponentsRequest{}
+	mi := &file_smd_v1_smd_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchComponentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchComponentsRequest) ProtoMessage() {}
+
+func (x *WatchComponentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchComponentsRequest.ProtoReflect.Descriptor instead.
+func (*WatchComponentsRequest) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchComponentsRequest) GetResumeAfter() string {
+	if x != nil {
+		return x.ResumeAfter
+	}
+	return ""
+}
+
+// A change to one or more Components.  Only the fields that changed are set,
+// as in an SCN.
+type ComponentChange struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	EventId string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Set if changes were missed, e.g. resume_after was too old or from an
+	// earlier run of HSM, so the client should read the state again.  Only
+	// event_id is set.
+	Resync         bool     `protobuf:"varint,2,opt,name=resync,proto3" json:"resync,omitempty"`
+	Components     []string `protobuf:"bytes,3,rep,name=components,proto3" json:"components,omitempty"`
+	Enabled        *bool    `protobuf:"varint,4,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Flag           string   `protobuf:"bytes,5,opt,name=flag,proto3" json:"flag,omitempty"`
+	Role           string   `protobuf:"bytes,6,opt,name=role,proto3" json:"role,omitempty"`
+	SubRole        string   `protobuf:"bytes,7,opt,name=sub_role,json=subRole,proto3" json:"sub_role,omitempty"`
+	SoftwareStatus string   `protobuf:"bytes,8,opt,name=software_status,json=softwareStatus,proto3" json:"software_status,omitempty"`
+	State          string   `protobuf:"bytes,9,opt,name=state,proto3" json:"state,omitempty"`
+	Reservation    string   `protobuf:"bytes,10,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ComponentChange) Reset() {
+	*x = ComponentChange{}
+	mi := &file_smd_v1_smd_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentChange) ProtoMessage() {}
+
+func (x *ComponentChange) ProtoReflect() protoreflect.Message {
+	mi := &file_smd_v1_smd_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentChange.ProtoReflect.Descriptor instead.
+func (*ComponentChange) Descriptor() ([]byte, []int) {
+	return file_smd_v1_smd_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ComponentChange) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+func (x *ComponentChange) GetComponents() []string {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *ComponentChange) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *ComponentChange) GetFlag() string {
+	if x != nil {
+		return x.Flag
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetSubRole() string {
+	if x != nil {
+		return x.SubRole
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetSoftwareStatus() string {
+	if x != nil {
+		return x.SoftwareStatus
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ComponentChange) GetReservation() string {
+	if x != nil {
+		return x.Reservation
+	}
+	return ""
+}
+
+var File_smd_v1_smd_proto protoreflect.FileDescriptor
+
+const file_smd_v1_smd_proto_rawDesc = "" +
+	"\n" +
+	"\x10smd/v1/smd.proto\x12\x06smd.v1\"\xa5\x03\n" +
+	"\tComponent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\tR\x05state\x12\x12\n" +
+	"\x04flag\x18\x04 \x01(\tR\x04flag\x12\x1d\n" +
+	"\aenabled\x18\x05 \x01(\bH\x00R\aenabled\x88\x01\x01\x12'\n" +
+	"\x0fsoftware_status\x18\x06 \x01(\tR\x0esoftwareStatus\x12\x12\n" +
+	"\x04role\x18\a \x01(\tR\x04role\x12\x19\n" +
+	"\bsub_role\x18\b \x01(\tR\asubRole\x12\x15\n" +
+	"\x03nid\x18\t \x01(\x03H\x01R\x03nid\x88\x01\x01\x12\x18\n" +
+	"\asubtype\x18\n" +
+	" \x01(\tR\asubtype\x12\x19\n" +
+	"\bnet_type\x18\v \x01(\tR\anetType\x12\x12\n" +
+	"\x04arch\x18\f \x01(\tR\x04arch\x12\x14\n" +
+	"\x05class\x18\r \x01(\tR\x05class\x121\n" +
+	"\x14reservation_disabled\x18\x0e \x01(\bR\x13reservationDisabled\x12\x16\n" +
+	"\x06locked\x18\x0f \x01(\bR\x06lockedB\n" +
+	"\n" +
+	"\b_enabledB\x06\n" +
+	"\x04_nid\"%\n" +
+	"\x13GetComponentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa3\x02\n" +
+	"\x15ListComponentsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x03(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x03(\tR\x04type\x12\x14\n" +
+	"\x05state\x18\x03 \x03(\tR\x05state\x12\x12\n" +
+	"\x04flag\x18\x04 \x03(\tR\x04flag\x12\x12\n" +
+	"\x04role\x18\x05 \x03(\tR\x04role\x12\x19\n" +
+	"\bsub_role\x18\x06 \x03(\tR\asubRole\x12\x14\n" +
+	"\x05class\x18\a \x03(\tR\x05class\x12\x12\n" +
+	"\x04arch\x18\b \x03(\tR\x04arch\x12\x14\n" +
+	"\x05group\x18\t \x03(\tR\x05group\x12\x1c\n" +
+	"\tpartition\x18\n" +
+	" \x03(\tR\tpartition\x12\x10\n" +
+	"\x03nid\x18\v \x03(\tR\x03nid\x12\x1d\n" +
+	"\n" +
+	"state_only\x18\f \x01(\bR\tstateOnly\"K\n" +
+	"\x16ListComponentsResponse\x121\n" +
+	"\n" +
+	"components\x18\x01 \x03(\v2\x11.smd.v1.ComponentR\n" +
+	"components\"\xe4\x03\n" +
+	"\x11ComponentEndpoint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04fqdn\x18\x04 \x01(\tR\x04fqdn\x12!\n" +
+	"\fredfish_type\x18\x05 \x01(\tR\vredfishType\x12'\n" +
+	"\x0fredfish_subtype\x18\x06 \x01(\tR\x0eredfishSubtype\x12\x19\n" +
+	"\bmac_addr\x18\a \x01(\tR\amacAddr\x12\x12\n" +
+	"\x04uuid\x18\b \x01(\tR\x04uuid\x12\x19\n" +
+	"\bodata_id\x18\t \x01(\tR\aodataId\x12.\n" +
+	"\x13redfish_endpoint_id\x18\n" +
+	" \x01(\tR\x11redfishEndpointId\x12\x18\n" +
+	"\aenabled\x18\v \x01(\bR\aenabled\x122\n" +
+	"\x15redfish_endpoint_fqdn\x18\f \x01(\tR\x13redfishEndpointFqdn\x12\x1f\n" +
+	"\vredfish_url\x18\r \x01(\tR\n" +
+	"redfishUrl\x126\n" +
+	"\x17component_endpoint_type\x18\x0e \x01(\tR\x15componentEndpointType\x12\x12\n" +
+	"\x04info\x18\x0f \x01(\fR\x04info\"-\n" +
+	"\x1bGetComponentEndpointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x85\x01\n" +
+	"\x1dListComponentEndpointsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x03(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"redfish_ep\x18\x02 \x03(\tR\tredfishEp\x12\x12\n" +
+	"\x04type\x18\x03 \x03(\tR\x04type\x12!\n" +
+	"\fredfish_type\x18\x04 \x03(\tR\vredfishType\"l\n" +
+	"\x1eListComponentEndpointsResponse\x12J\n" +
+	"\x13component_endpoints\x18\x01 \x03(\v2\x19.smd.v1.ComponentEndpointR\x12componentEndpoints\"\x82\x02\n" +
+	"\n" +
+	"HWInvByLoc\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\aordinal\x18\x03 \x01(\x05R\aordinal\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12@\n" +
+	"\x1dhw_inventory_by_location_type\x18\x05 \x01(\tR\x19hwInventoryByLocationType\x12#\n" +
+	"\rlocation_info\x18\x06 \x01(\fR\flocationInfo\x127\n" +
+	"\rpopulated_fru\x18\a \x01(\v2\x12.smd.v1.HWInvByFRUR\fpopulatedFru\"\xa4\x01\n" +
+	"\n" +
+	"HWInvByFRU\x12\x15\n" +
+	"\x06fru_id\x18\x01 \x01(\tR\x05fruId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\asubtype\x18\x03 \x01(\tR\asubtype\x126\n" +
+	"\x18hw_inventory_by_fru_type\x18\x04 \x01(\tR\x14hwInventoryByFruType\x12\x19\n" +
+	"\bfru_info\x18\x05 \x01(\fR\afruInfo\"&\n" +
+	"\x14GetHWInvByLocRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xbc\x01\n" +
+	"\x15ListHWInvByLocRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x03(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x03(\tR\x04type\x12\"\n" +
+	"\fmanufacturer\x18\x03 \x03(\tR\fmanufacturer\x12\x1f\n" +
+	"\vpart_number\x18\x04 \x03(\tR\n" +
+	"partNumber\x12#\n" +
+	"\rserial_number\x18\x05 \x03(\tR\fserialNumber\x12\x15\n" +
+	"\x06fru_id\x18\x06 \x03(\tR\x05fruId\"J\n" +
+	"\x16ListHWInvByLocResponse\x120\n" +
+	"\tlocations\x18\x01 \x03(\v2\x12.smd.v1.HWInvByLocR\tlocations\";\n" +
+	"\x16WatchComponentsRequest\x12!\n" +
+	"\fresume_after\x18\x01 \x01(\tR\vresumeAfter\"\xb3\x02\n" +
+	"\x0fComponentChange\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x16\n" +
+	"\x06resync\x18\x02 \x01(\bR\x06resync\x12\x1e\n" +
+	"\n" +
+	"components\x18\x03 \x03(\tR\n" +
+	"components\x12\x1d\n" +
+	"\aenabled\x18\x04 \x01(\bH\x00R\aenabled\x88\x01\x01\x12\x12\n" +
+	"\x04flag\x18\x05 \x01(\tR\x04flag\x12\x12\n" +
+	"\x04role\x18\x06 \x01(\tR\x04role\x12\x19\n" +
+	"\bsub_role\x18\a \x01(\tR\asubRole\x12'\n" +
+	"\x0fsoftware_status\x18\b \x01(\tR\x0esoftwareStatus\x12\x14\n" +
+	"\x05state\x18\t \x01(\tR\x05state\x12 \n" +
+	"\vreservation\x18\n" +
+	" \x01(\tR\vreservationB\n" +
+	"\n" +
+	"\b_enabled2\xb9\x04\n" +
+	"\x03SMD\x12>\n" +
+	"\fGetComponent\x12\x1b.smd.v1.GetComponentRequest\x1a\x11.smd.v1.Component\x12O\n" +
+	"\x0eListComponents\x12\x1d.smd.v1.ListComponentsRequest\x1a\x1e.smd.v1.ListComponentsResponse\x12V\n" +
+	"\x14GetComponentEndpoint\x12#.smd.v1.GetComponentEndpointRequest\x1a\x19.smd.v1.ComponentEndpoint\x12g\n" +
+	"\x16ListComponentEndpoints\x12%.smd.v1.ListComponentEndpointsRequest\x1a&.smd.v1.ListComponentEndpointsResponse\x12A\n" +
+	"\rGetHWInvByLoc\x12\x1c.smd.v1.GetHWInvByLocRequest\x1a\x12.smd.v1.HWInvByLoc\x12O\n" +
+	"\x0eListHWInvByLoc\x12\x1d.smd.v1.ListHWInvByLocRequest\x1a\x1e.smd.v1.ListHWInvByLocResponse\x12L\n" +
+	"\x0fWatchComponents\x12\x1e.smd.v1.WatchComponentsRequest\x1a\x17.smd.v1.ComponentChange0\x01B3Z1github.com/OpenCHAMI/smd/v2/pkg/grpc/smd/v1;smdv1b\x06proto3"
+
+var (
+	file_smd_v1_smd_proto_rawDescOnce sync.Once
+	file_smd_v1_smd_proto_rawDescData []byte
+)
+
+func file_smd_v1_smd_proto_rawDescGZIP() []byte {
+	file_smd_v1_smd_proto_rawDescOnce.Do(func() {
+		file_smd_v1_smd_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_smd_v1_smd_proto_rawDesc), len(file_smd_v1_smd_proto_rawDesc)))
+	})
+	return file_smd_v1_smd_proto_rawDescData
+}
+
+var file_smd_v1_smd_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_smd_v1_smd_proto_goTypes = []any{
+	(*Component)(nil),                      // 0: smd.v1.Component
+	(*GetComponentRequest)(nil),            // 1: smd.v1.GetComponentRequest
+	(*ListComponentsRequest)(nil),          // 2: smd.v1.ListComponentsRequest
+	(*ListComponentsResponse)(nil),         // 3: smd.v1.ListComponentsResponse
+	(*ComponentEndpoint)(nil),              // 4: smd.v1.ComponentEndpoint
+	(*GetComponentEndpointRequest)(nil),    // 5: smd.v1.GetComponentEndpointRequest
+	(*ListComponentEndpointsRequest)(nil),  // 6: smd.v1.ListComponentEndpointsRequest
+	(*ListComponentEndpointsResponse)(nil), // 7: smd.v1.ListComponentEndpointsResponse
+	(*HWInvByLoc)(nil),                     // 8: smd.v1.HWInvByLoc
+	(*HWInvByFRU)(nil),                     // 9: smd.v1.HWInvByFRU
+	(*GetHWInvByLocRequest)(nil),           // 10: smd.v1.GetHWInvByLocRequest
+	(*ListHWInvByLocRequest)(nil),          // 11: smd.v1.ListHWInvByLocRequest
+	(*ListHWInvByLocResponse)(nil),         // 12: smd.v1.ListHWInvByLocResponse
+	(*WatchComponentsRequest)(nil),         // 13: smd.v1.WatchComponentsRequest
+	(*ComponentChange)(nil),                // 14: smd.v1.ComponentChange
+}
+var file_smd_v1_smd_proto_depIdxs = []int32{
+	0,  // 0: smd.v1.ListComponentsResponse.components:type_name -> smd.v1.Component
+	4,  // 1: smd.v1.ListComponentEndpointsResponse.component_endpoints:type_name -> smd.v1.ComponentEndpoint
+	9,  // 2: smd.v1.HWInvByLoc.populated_fru:type_name -> smd.v1.HWInvByFRU
+	8,  // 3: smd.v1.ListHWInvByLocResponse.locations:type_name -> smd.v1.HWInvByLoc
+	1,  // 4: smd.v1.SMD.GetComponent:input_type -> smd.v1.GetComponentRequest
+	2,  // 5: smd.v1.SMD.ListComponents:input_type -> smd.v1.ListComponentsRequest
+	5,  // 6: smd.v1.SMD.GetComponentEndpoint:input_type -> smd.v1.GetComponentEndpointRequest
+	6,  // 7: smd.v1.SMD.ListComponentEndpoints:input_type -> smd.v1.ListComponentEndpointsRequest
+	10, // 8: smd.v1.SMD.GetHWInvByLoc:input_type -> smd.v1.GetHWInvByLocRequest
+	11, // 9: smd.v1.SMD.ListHWInvByLoc:input_type -> smd.v1.ListHWInvByLocRequest
+	13, // 10: smd.v1.SMD.WatchComponents:input_type -> smd.v1.WatchComponentsRequest
+	0,  // 11: smd.v1.SMD.GetComponent:output_type -> smd.v1.Component
+	3,  // 12: smd.v1.SMD.ListComponents:output_type -> smd.v1.ListComponentsResponse
+	4,  // 13: smd.v1.SMD.GetComponentEndpoint:output_type -> smd.v1.ComponentEndpoint
+	7,  // 14: smd.v1.SMD.ListComponentEndpoints:output_type -> smd.v1.ListComponentEndpointsResponse
+	8,  // 15: smd.v1.SMD.GetHWInvByLoc:output_type -> smd.v1.HWInvByLoc
+	12, // 16: smd.v1.SMD.ListHWInvByLoc:output_type -> smd.v1.ListHWInvByLocResponse
+	14, // 17: smd.v1.SMD.WatchComponents:output_type -> smd.v1.ComponentChange
+	11, // [11:18] is the sub-list for method output_type
+	4,  // [4:11] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_smd_v1_smd_proto_init() }
+func file_smd_v1_smd_proto_init() {
+	if File_smd_v1_smd_proto != nil {
+		return
+	}
+	file_smd_v1_smd_proto_msgTypes[0].OneofWrappers = []any{}
+	file_smd_v1_smd_proto_msgTypes[14].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_smd_v1_smd_proto_rawDesc), len(file_smd_v1_smd_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_smd_v1_smd_proto_goTypes,
+		DependencyIndexes: file_smd_v1_smd_proto_depIdxs,
+		MessageInfos:      file_smd_v1_smd_proto_msgTypes,
+	}.Build()
+	File_smd_v1_smd_proto = out.File
+	file_smd_v1_smd_proto_goTypes = nil
+	file_smd_v1_smd_proto_depIdxs = nil
+}
@@ -0,0 +1,406 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// gRPC API for services that read HSM state on hot paths, e.g. PCS, without
+// the cost of the JSON REST API.  It covers reads only: Components,
+// ComponentEndpoints and hardware inventory by location, plus a stream of
+// component state changes.  Changes still go through the REST API.
+//
+// Regenerate the Go code in pkg/grpc/smd/v1 with:
+//
+//     protoc -I api/proto --go_out=pkg/grpc --go_opt=paths=source_relative \
+//         --go-grpc_out=pkg/grpc --go-grpc_opt=paths=source_relative \
+//         smd/v1/smd.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: smd/v1/smd.proto
+
+package smdv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SMD_GetComponent_FullMethodName           = "/smd.v1.SMD/GetComponent"
+	SMD_ListComponents_FullMethodName         = "/smd.v1.SMD/ListComponents"
+	SMD_GetComponentEndpoint_FullMethodName   = "/smd.v1.SMD/GetComponentEndpoint"
+	SMD_ListComponentEndpoints_FullMethodName = "/smd.v1.SMD/ListComponentEndpoints"
+	SMD_GetHWInvByLoc_FullMethodName          = "/smd.v1.SMD/GetHWInvByLoc"
+	SMD_ListHWInvByLoc_FullMethodName         = "/smd.v1.SMD/ListHWInvByLoc"
+	SMD_WatchComponents_FullMethodName        = "/smd.v1.SMD/WatchComponents"
+)
+
+// SMDClient is the client API for SMD service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SMDClient interface {
+	// Get one Component by xname.  NOT_FOUND if there is none.
+	GetComponent(ctx context.Context, in *GetComponentRequest, opts ...grpc.CallOption) (*Component, error)
+	// List the Components matching all of the non-empty filters.
+	ListComponents(ctx context.Context, in *ListComponentsRequest, opts ...grpc.CallOption) (*ListComponentsResponse, error)
+	// Get one ComponentEndpoint by xname.  NOT_FOUND if there is none.
+	GetComponentEndpoint(ctx context.Context, in *GetComponentEndpointRequest, opts ...grpc.CallOption) (*ComponentEndpoint, error)
+	// List the ComponentEndpoints matching all of the non-empty filters.
+	ListComponentEndpoints(ctx context.Context, in *ListComponentEndpointsRequest, opts ...grpc.CallOption) (*ListComponentEndpointsResponse, error)
+	// Get the hardware inventory of one location by xname.  NOT_FOUND if
+	// there is none.
+	GetHWInvByLoc(ctx context.Context, in *GetHWInvByLocRequest, opts ...grpc.CallOption) (*HWInvByLoc, error)
+	// List the hardware inventory of the locations matching all of the
+	// non-empty filters.
+	ListHWInvByLoc(ctx context.Context, in *ListHWInvByLocRequest, opts ...grpc.CallOption) (*ListHWInvByLocResponse, error)
+	// Stream Component state changes as they happen, the same ones that are
+	// sent as SCNs and on GET /State/Components/Stream.
+	WatchComponents(ctx context.Context, in *WatchComponentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ComponentChange], error)
+}
+
+type sMDClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSMDClient(cc grpc.ClientConnInterface) SMDClient {
+	return &sMDClient{cc}
+}
+
+func (c *sMDClient) GetComponent(ctx context.Context, in *GetComponentRequest, opts ...grpc.CallOption) (*Component, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Component)
+	err := c.cc.Invoke(ctx, SMD_GetComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) ListComponents(ctx context.Context, in *ListComponentsRequest, opts ...grpc.CallOption) (*ListComponentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListComponentsResponse)
+	err := c.cc.Invoke(ctx, SMD_ListComponents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) GetComponentEndpoint(ctx context.Context, in *GetComponentEndpointRequest, opts ...grpc.CallOption) (*ComponentEndpoint, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComponentEndpoint)
+	err := c.cc.Invoke(ctx, SMD_GetComponentEndpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) ListComponentEndpoints(ctx context.Context, in *ListComponentEndpointsRequest, opts ...grpc.CallOption) (*ListComponentEndpointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListComponentEndpointsResponse)
+	err := c.cc.Invoke(ctx, SMD_ListComponentEndpoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) GetHWInvByLoc(ctx context.Context, in *GetHWInvByLocRequest, opts ...grpc.CallOption) (*HWInvByLoc, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HWInvByLoc)
+	err := c.cc.Invoke(ctx, SMD_GetHWInvByLoc_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) ListHWInvByLoc(ctx context.Context, in *ListHWInvByLocRequest, opts ...grpc.CallOption) (*ListHWInvByLocResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHWInvByLocResponse)
+	err := c.cc.Invoke(ctx, SMD_ListHWInvByLoc_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sMDClient) WatchComponents(ctx context.Context, in *WatchComponentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ComponentChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SMD_ServiceDesc.Streams[0], SMD_WatchComponents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchComponentsRequest, ComponentChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SMD_WatchComponentsClient = grpc.ServerStreamingClient[ComponentChange]
+
+// SMDServer is the server API for SMD service.
+// All implementations must embed UnimplementedSMDServer
+// for forward compatibility.
+type SMDServer interface {
+	// Get one Component by xname.  NOT_FOUND if there is none.
+	GetComponent(context.Context, *GetComponentRequest) (*Component, error)
+	// List the Components matching all of the non-empty filters.
+	ListComponents(context.Context, *ListComponentsRequest) (*ListComponentsResponse, error)
+	// Get one ComponentEndpoint by xname.  NOT_FOUND if there is none.
+	GetComponentEndpoint(context.Context, *GetComponentEndpointRequest) (*ComponentEndpoint, error)
+	// List the ComponentEndpoints matching all of the non-empty filters.
+	ListComponentEndpoints(context.Context, *ListComponentEndpointsRequest) (*ListComponentEndpointsResponse, error)
+	// Get the hardware inventory of one location by xname.  NOT_FOUND if
+	// there is none.
+	GetHWInvByLoc(context.Context, *GetHWInvByLocRequest) (*HWInvByLoc, error)
+	// List the hardware inventory of the locations matching all of the
+	// non-empty filters.
+	ListHWInvByLoc(context.Context, *ListHWInvByLocRequest) (*ListHWInvByLocResponse, error)
+	// Stream Component state changes as they happen, the same ones that are
+	// sent as SCNs and on GET /State/Components/Stream.
+	WatchComponents(*WatchComponentsRequest, grpc.ServerStreamingServer[ComponentChange]) error
+	mustEmbedUnimplementedSMDServer()
+}
+
+// UnimplementedSMDServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSMDServer struct{}
+
+func (UnimplementedSMDServer) GetComponent(context.Context, *GetComponentRequest) (*Component, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComponent not implemented")
+}
+func (UnimplementedSMDServer) ListComponents(context.Context, *ListComponentsRequest) (*ListComponentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComponents not implemented")
+}
+func (UnimplementedSMDServer) GetComponentEndpoint(context.Context, *GetComponentEndpointRequest) (*ComponentEndpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComponentEndpoint not implemented")
+}
+func (UnimplementedSMDServer) ListComponentEndpoints(context.Context, *ListComponentEndpointsRequest) (*ListComponentEndpointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComponentEndpoints not implemented")
+}
+func (UnimplementedSMDServer) GetHWInvByLoc(context.Context, *GetHWInvByLocRequest) (*HWInvByLoc, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHWInvByLoc not implemented")
+}
+func (UnimplementedSMDServer) ListHWInvByLoc(context.Context, *ListHWInvByLocRequest) (*ListHWInvByLocResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHWInvByLoc not implemented")
+}
+func (UnimplementedSMDServer) WatchComponents(*WatchComponentsRequest, grpc.ServerStreamingServer[ComponentChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchComponents not implemented")
+}
+func (UnimplementedSMDServer) mustEmbedUnimplementedSMDServer() {}
+func (UnimplementedSMDServer) testEmbeddedByValue()             {}
+
+// UnsafeSMDServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SMDServer will
+// result in compilation errors.
+type UnsafeSMDServer interface {
+	mustEmbedUnimplementedSMDServer()
+}
+
+func RegisterSMDServer(s grpc.ServiceRegistrar, srv SMDServer) {
+	// If the following call pancis, it indicates UnimplementedSMDServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SMD_ServiceDesc, srv)
+}
+
+func _SMD_GetComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComponentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).GetComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_GetComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).GetComponent(ctx, req.(*GetComponentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_ListComponents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComponentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).ListComponents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_ListComponents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).ListComponents(ctx, req.(*ListComponentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_GetComponentEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComponentEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).GetComponentEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_GetComponentEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).GetComponentEndpoint(ctx, req.(*GetComponentEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_ListComponentEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComponentEndpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).ListComponentEndpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_ListComponentEndpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).ListComponentEndpoints(ctx, req.(*ListComponentEndpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_GetHWInvByLoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHWInvByLocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).GetHWInvByLoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_GetHWInvByLoc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).GetHWInvByLoc(ctx, req.(*GetHWInvByLocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_ListHWInvByLoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHWInvByLocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SMDServer).ListHWInvByLoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SMD_ListHWInvByLoc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SMDServer).ListHWInvByLoc(ctx, req.(*ListHWInvByLocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SMD_WatchComponents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchComponentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SMDServer).WatchComponents(m, &grpc.GenericServerStream[WatchComponentsRequest, ComponentChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SMD_WatchComponentsServer = grpc.ServerStreamingServer[ComponentChange]
+
+// SMD_ServiceDesc is the grpc.ServiceDesc for SMD service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SMD_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "smd.v1.SMD",
+	HandlerType: (*SMDServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetComponent",
+			Handler:    _SMD_GetComponent_Handler,
+		},
+		{
+			MethodName: "ListComponents",
+			Handler:    _SMD_ListComponents_Handler,
+		},
+		{
+			MethodName: "GetComponentEndpoint",
+			Handler:    _SMD_GetComponentEndpoint_Handler,
+		},
+		{
+			MethodName: "ListComponentEndpoints",
+			Handler:    _SMD_ListComponentEndpoints_Handler,
+		},
+		{
+			MethodName: "GetHWInvByLoc",
+			Handler:    _SMD_GetHWInvByLoc_Handler,
+		},
+		{
+			MethodName: "ListHWInvByLoc",
+			Handler:    _SMD_ListHWInvByLoc_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchComponents",
+			Handler:       _SMD_WatchComponents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "smd/v1/smd.proto",
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+// JSON decoded struct returned from Redfish "AggregationService"
+// Example: /redfish/v1/AggregationService
+type AggregationService struct {
+	OContext       string `json:"@odata.context"`
+	Oid            string `json:"@odata.id"`
+	Otype          string `json:"@odata.type"`
+	Id             string `json:"Id"`
+	Name           string `json:"Name"`
+	Description    string `json:"Description"`
+	ServiceEnabled *bool  `json:"ServiceEnabled,omitempty"`
+
+	Status StatusRF `json:"Status"`
+
+	Aggregates         ResourceID `json:"Aggregates"`
+	AggregationSources ResourceID `json:"AggregationSources"`
+	ConnectionMethods  ResourceID `json:"ConnectionMethods"`
+}
+
+// JSON decoded collection struct returned from Redfish "AggregateCollection"
+// Example: /redfish/v1/AggregationService/Aggregates
+type AggregateCollection GenericCollection
+
+// JSON decoded collection struct returned from Redfish
+// "AggregationSourceCollection"
+// Example: /redfish/v1/AggregationService/AggregationSources
+type AggregationSourceCollection GenericCollection
+
+// JSON decoded struct returned from Redfish "Aggregate", a user or vendor
+// defined grouping of aggregated resources.
+// Example: /redfish/v1/AggregationService/Aggregates/<aggregate_id>
+type Aggregate struct {
+	OContext      string       `json:"@odata.context"`
+	Oid           string       `json:"@odata.id"`
+	Otype         string       `json:"@odata.type"`
+	Id            string       `json:"Id"`
+	Name          string       `json:"Name"`
+	Elements      []ResourceID `json:"Elements"`
+	ElementsCount int          `json:"ElementsCount"`
+}
+
+// JSON decoded struct returned from Redfish "AggregationSource", i.e. one
+// of the BMCs or other services the aggregator is connected to.
+// Example: /redfish/v1/AggregationService/AggregationSources/<source_id>
+type AggregationSource struct {
+	OContext string `json:"@odata.context"`
+	Oid      string `json:"@odata.id"`
+	Otype    string `json:"@odata.type"`
+	Id       string `json:"Id"`
+	Name     string `json:"Name"`
+	HostName string `json:"HostName"`
+
+	Status StatusRF `json:"Status"`
+
+	Links AggregationSourceLinks `json:"Links"`
+}
+
+// Redfish AggregationSource - Links section
+type AggregationSourceLinks struct {
+	ConnectionMethod  ResourceID   `json:"ConnectionMethod"`
+	ResourcesAccessed []ResourceID `json:"ResourcesAccessed"`
+}
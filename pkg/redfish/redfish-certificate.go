@@ -0,0 +1,229 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+)
+
+// If true, the HTTPS certificates listed by the CertificateService are
+// fetched during discovery so their subject and expiry are kept.  Off by
+// default as it costs an extra request per certificate.
+var rfDiscoverCertificates = false
+
+// Turn discovery of HTTPS certificate details on or off.
+// NOTE: Global, to be called only once at startup.
+func SetDiscoverCertificates(on bool) {
+	rfDiscoverCertificates = on
+}
+
+// Check whether HTTPS certificate details are discovered.
+func GetDiscoverCertificates() bool {
+	return rfDiscoverCertificates
+}
+
+// JSON decoded struct returned from Redfish "CertificateService"
+// Example: /redfish/v1/CertificateService
+type CertificateService struct {
+	OContext    string `json:"@odata.context"`
+	Oid         string `json:"@odata.id"`
+	Otype       string `json:"@odata.type"`
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+
+	Actions              *CertificateServiceActions `json:"Actions,omitempty"`
+	CertificateLocations ResourceID                 `json:"CertificateLocations"`
+}
+
+// Redfish CertificateService sub-struct - Actions
+type CertificateServiceActions struct {
+	GenerateCSR        *ActionGenerateCSR        `json:"#CertificateService.GenerateCSR,omitempty"`
+	ReplaceCertificate *ActionReplaceCertificate `json:"#CertificateService.ReplaceCertificate,omitempty"`
+}
+
+// Action GenerateCSR - Found under CertificateService Actions
+type ActionGenerateCSR struct {
+	AllowableKeyPairAlgorithms []string `json:"KeyPairAlgorithm@Redfish.AllowableValues,omitempty"`
+	RFActionInfo               string   `json:"@Redfish.ActionInfo,omitempty"`
+	Target                     string   `json:"target"`
+	Title                      string   `json:"title,omitempty"`
+}
+
+// Action ReplaceCertificate - Found under CertificateService Actions
+type ActionReplaceCertificate struct {
+	AllowableValues []string `json:"CertificateType@Redfish.AllowableValues,omitempty"`
+	RFActionInfo    string   `json:"@Redfish.ActionInfo,omitempty"`
+	Target          string   `json:"target"`
+	Title           string   `json:"title,omitempty"`
+}
+
+// JSON decoded struct returned from Redfish "CertificateLocations", listing
+// every certificate on the BMC.
+// Example: /redfish/v1/CertificateService/CertificateLocations
+type CertificateLocations struct {
+	OContext string `json:"@odata.context"`
+	Oid      string `json:"@odata.id"`
+	Otype    string `json:"@odata.type"`
+	Id       string `json:"Id"`
+	Name     string `json:"Name"`
+
+	Links CertificateLocationsLinks `json:"Links"`
+}
+
+// Redfish CertificateLocations - Links section
+type CertificateLocationsLinks struct {
+	Certificates []ResourceID `json:"Certificates"`
+}
+
+// POST body of the CertificateService.ReplaceCertificate action.
+// CertificateString may hold the key after the certificate for BMCs that
+// take both at once.
+type ReplaceCertificateRequest struct {
+	CertificateString string     `json:"CertificateString"`
+	CertificateType   string     `json:"CertificateType"`
+	CertificateUri    ResourceID `json:"CertificateUri"`
+}
+
+// POST body of the CertificateService.GenerateCSR action.
+type GenerateCSRRequest struct {
+	CertificateCollection ResourceID `json:"CertificateCollection"`
+	CommonName            string     `json:"CommonName"`
+	AlternativeNames      []string   `json:"AlternativeNames,omitempty"`
+	Organization          string     `json:"Organization,omitempty"`
+	OrganizationalUnit    string     `json:"OrganizationalUnit,omitempty"`
+	City                  string     `json:"City,omitempty"`
+	State                 string     `json:"State,omitempty"`
+	Country               string     `json:"Country,omitempty"`
+	Email                 string     `json:"Email,omitempty"`
+	KeyPairAlgorithm      string     `json:"KeyPairAlgorithm,omitempty"`
+	KeyBitLength          int        `json:"KeyBitLength,omitempty"`
+}
+
+// Response to the CertificateService.GenerateCSR action.
+type GenerateCSRResponse struct {
+	CSRString             string     `json:"CSRString"`
+	CertificateCollection ResourceID `json:"CertificateCollection"`
+}
+
+// JSON decoded struct returned from Redfish "Certificate"
+// Example: /redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1
+type Certificate struct {
+	Oid             string `json:"@odata.id"`
+	Id              string `json:"Id"`
+	Name            string `json:"Name,omitempty"`
+	CertificateType string `json:"CertificateType,omitempty"`
+
+	// Not kept; only used to get the fields below if they are missing.
+	CertificateString string `json:"CertificateString,omitempty"`
+
+	Subject        CertificateIdentifier `json:"Subject"`
+	Issuer         CertificateIdentifier `json:"Issuer"`
+	ValidNotBefore string                `json:"ValidNotBefore,omitempty"`
+	ValidNotAfter  string                `json:"ValidNotAfter,omitempty"`
+	SerialNumber   string                `json:"SerialNumber,omitempty"`
+	Fingerprint    string                `json:"Fingerprint,omitempty"`
+	KeyUsage       []string              `json:"KeyUsage,omitempty"`
+}
+
+// Redfish Certificate sub-struct - Subject and Issuer
+type CertificateIdentifier struct {
+	CommonName         string `json:"CommonName,omitempty"`
+	Organization       string `json:"Organization,omitempty"`
+	OrganizationalUnit string `json:"OrganizationalUnit,omitempty"`
+	City               string `json:"City,omitempty"`
+	State              string `json:"State,omitempty"`
+	Country            string `json:"Country,omitempty"`
+	Email              string `json:"Email,omitempty"`
+}
+
+// Fill in the subject, issuer and validity from the PEM CertificateString
+// for BMCs that only give the certificate itself, then drop the string.
+func (c *Certificate) fillFromPEM() error {
+	pemStr := c.CertificateString
+	c.CertificateString = ""
+	if c.ValidNotAfter != "" && c.Subject.CommonName != "" {
+		return nil
+	}
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if c.ValidNotBefore == "" {
+		c.ValidNotBefore = cert.NotBefore.UTC().Format(time.RFC3339)
+	}
+	if c.ValidNotAfter == "" {
+		c.ValidNotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	}
+	if c.Subject.CommonName == "" {
+		c.Subject.CommonName = cert.Subject.CommonName
+	}
+	if c.Issuer.CommonName == "" {
+		c.Issuer.CommonName = cert.Issuer.CommonName
+	}
+	if c.SerialNumber == "" {
+		c.SerialNumber = cert.SerialNumber.Text(16)
+	}
+	return nil
+}
+
+// When the certificate expires, or false if that's not known.
+func (c *Certificate) Expiry() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, c.ValidNotAfter)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// What is kept as the ServiceInfo of a CertificateService ServiceEndpoint:
+// the service, with its action targets, and where its certificates are.
+// HTTPSCertificateDetails is only filled in if certificate discovery is on.
+type CertificateServiceInfo struct {
+	CertificateService
+	Certificates            []ResourceID  `json:"Certificates,omitempty"`
+	HTTPSCertificateDetails []Certificate `json:"HTTPSCertificateDetails,omitempty"`
+}
+
+// The certificates used by the BMC's web server, i.e. those in an
+// .../NetworkProtocol/HTTPS/Certificates collection.
+func (c *CertificateServiceInfo) HTTPSCertificates() []string {
+	return httpsCertificates(c.Certificates)
+}
+
+func httpsCertificates(locs []ResourceID) []string {
+	certs := []string{}
+	for _, cert := range locs {
+		if strings.Contains(cert.Oid, "/NetworkProtocol/HTTPS/Certificates/") {
+			certs = append(certs, cert.Oid)
+		}
+	}
+	return certs
+}
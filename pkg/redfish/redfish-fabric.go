@@ -0,0 +1,121 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"encoding/json"
+)
+
+// JSON decoded collection struct returned from Redfish "FabricCollection"
+// Example: /redfish/v1/Fabrics
+type FabricCollection GenericCollection
+
+// JSON decoded collection struct returned from Redfish "SwitchCollection"
+// Example: /redfish/v1/Fabrics/<fabric_id>/Switches
+type SwitchCollection GenericCollection
+
+// JSON decoded collection struct returned from Redfish "FabricAdapterCollection"
+// Example: /redfish/v1/Chassis/<chassis_id>/FabricAdapters
+type FabricAdapterCollection GenericCollection
+
+// JSON decoded collection struct returned from Redfish "PortCollection"
+// Example: /redfish/v1/Fabrics/<fabric_id>/Switches/<switch_id>/Ports
+type PortCollection GenericCollection
+
+// JSON decoded struct returned from Redfish "Fabric"
+// Example: /redfish/v1/Fabrics/<fabric_id>
+type Fabric struct {
+	OContext    string `json:"@odata.context"`
+	Oid         string `json:"@odata.id"`
+	Otype       string `json:"@odata.type"`
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	FabricType  string `json:"FabricType"`
+
+	Status StatusRF `json:"Status"`
+
+	Switches ResourceID `json:"Switches"`
+}
+
+// Redfish pass-through from Redfish "Switch" or "FabricAdapter".  Which
+// one an HSN ASIC is exposed as depends on the controller firmware, but
+// the fields HMS cares about are the same for both.
+type FabricSwitch struct {
+	OContext string `json:"@odata.context"`
+	Oid      string `json:"@odata.id"`
+	Otype    string `json:"@odata.type"`
+
+	FabricSwitchLocationInfoRF
+	FabricSwitchFRUInfoRF
+
+	SwitchType      string   `json:"SwitchType,omitempty"`
+	FirmwareVersion string   `json:"FirmwareVersion,omitempty"`
+	Status          StatusRF `json:"Status"`
+
+	Ports ResourceID `json:"Ports"`
+}
+
+// Location-specific Redfish properties to be stored in hardware inventory
+// These are only relevant to the currently installed location of the FRU
+type FabricSwitchLocationInfoRF struct {
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+}
+
+// Durable Redfish properties to be stored in hardware inventory as
+// a specific FRU, which is then link with it's current location
+// i.e. an x-name.  These properties should follow the hardware and
+// allow it to be tracked even when it is removed from the system.
+type FabricSwitchFRUInfoRF struct {
+	Manufacturer   string `json:"Manufacturer"`
+	Model          string `json:"Model"`
+	PartNumber     string `json:"PartNumber"`
+	SKU            string `json:"SKU,omitempty"`
+	SerialNumber   string `json:"SerialNumber"`
+	ASICPartNumber string `json:"ASICPartNumber,omitempty"` // FabricAdapter only
+}
+
+// JSON decoded struct returned from Redfish "Port"
+// Example: /redfish/v1/Fabrics/<fabric_id>/Switches/<switch_id>/Ports/<port_id>
+type Port struct {
+	OContext    string `json:"@odata.context"`
+	Oid         string `json:"@odata.id"`
+	Otype       string `json:"@odata.type"`
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+
+	PortId           string      `json:"PortId"`
+	PortProtocol     string      `json:"PortProtocol"`
+	PortType         string      `json:"PortType"`
+	PortMedium       string      `json:"PortMedium,omitempty"`
+	LinkState        string      `json:"LinkState,omitempty"`  // Enabled, Disabled
+	LinkStatus       string      `json:"LinkStatus,omitempty"` // LinkUp, LinkDown, etc.
+	CurrentSpeedGbps json.Number `json:"CurrentSpeedGbps,omitempty"`
+	MaxSpeedGbps     json.Number `json:"MaxSpeedGbps,omitempty"`
+	Width            json.Number `json:"Width,omitempty"`
+
+	Status StatusRF `json:"Status"`
+}
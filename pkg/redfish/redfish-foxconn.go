@@ -231,6 +231,7 @@ func discoverFoxconnENetInterfaces(s *EpSystem) {
 	if err := json.Unmarshal(jsonData, &n); err != nil {
 		errlog.Printf("Failed to decode %s: %s\n", url, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(url, 0, err)
 	}
 
 	if n.MembersOCount < 1 {
@@ -260,6 +261,7 @@ func discoverFoxconnENetInterfaces(s *EpSystem) {
 		if err := json.Unmarshal(jsonData, &nm); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			s.LastStatus = EPResponseFailedDecode
+			s.epRF.addDiscoveryError(url, 0, err)
 		}
 
 		//////////////////////////////////////////////////////
@@ -289,6 +291,7 @@ func discoverFoxconnENetInterfaces(s *EpSystem) {
 			if err := json.Unmarshal(jsonData, &p); err != nil {
 				errlog.Printf("Failed to decode %s: %s\n", url, err)
 				s.LastStatus = EPResponseFailedDecode
+				s.epRF.addDiscoveryError(url, 0, err)
 			}
 
 			//////////////////////////////////////////////////////
@@ -34,6 +34,10 @@ type HpeOemChassisLinks struct {
 	Devices ResourceID `json:"Devices"`
 }
 
+type TrustedModuleOemHpe struct {
+	VendorName string `json:"VendorName,omitempty"`
+}
+
 type HpeDeviceCollection GenericCollection
 
 // Redfish pass-through from Redfish HPE OEM Device
@@ -0,0 +1,223 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// If true, the LogServices of each System and Manager are listed during
+// discovery and kept with its ComponentEndpoint.  Off by default as it
+// costs extra requests per System and Manager.
+var rfDiscoverLogServices = false
+
+// Turn discovery of System and Manager LogServices on or off.
+// NOTE: Global, to be called only once at startup.
+func SetDiscoverLogServices(on bool) {
+	rfDiscoverLogServices = on
+}
+
+// Check whether System and Manager LogServices are discovered.
+func GetDiscoverLogServices() bool {
+	return rfDiscoverLogServices
+}
+
+// JSON decoded struct returned from Redfish "LogService"
+// Example: /redfish/v1/Systems/<system_id>/LogServices/SEL
+type LogService struct {
+	OContext           string      `json:"@odata.context"`
+	Oid                string      `json:"@odata.id"`
+	Otype              string      `json:"@odata.type"`
+	Id                 string      `json:"Id"`
+	Name               string      `json:"Name"`
+	Description        string      `json:"Description"`
+	LogEntryType       string      `json:"LogEntryType"`
+	OverWritePolicy    string      `json:"OverWritePolicy"`
+	MaxNumberOfRecords json.Number `json:"MaxNumberOfRecords"`
+	ServiceEnabled     *bool       `json:"ServiceEnabled,omitempty"`
+	Entries            ResourceID  `json:"Entries"`
+}
+
+// JSON decoded struct returned from Redfish "LogEntry"
+// Example: /redfish/v1/Systems/<system_id>/LogServices/SEL/Entries/1
+type LogEntry struct {
+	Oid          string   `json:"@odata.id"`
+	Id           string   `json:"Id"`
+	Name         string   `json:"Name,omitempty"`
+	Created      string   `json:"Created,omitempty"`
+	EntryType    string   `json:"EntryType,omitempty"`
+	Severity     string   `json:"Severity,omitempty"`
+	Message      string   `json:"Message,omitempty"`
+	MessageId    string   `json:"MessageId,omitempty"`
+	MessageArgs  []string `json:"MessageArgs,omitempty"`
+	EntryCode    string   `json:"EntryCode,omitempty"`
+	SensorType   string   `json:"SensorType,omitempty"`
+	SensorNumber *int     `json:"SensorNumber,omitempty"`
+}
+
+// JSON decoded collection struct of Redfish type "LogEntryCollection".
+// Members are usually expanded, but may only be links.
+type LogEntryCollection struct {
+	Oid             string     `json:"@odata.id"`
+	Members         []LogEntry `json:"Members"`
+	MembersNextLink string     `json:"Members@odata.nextLink,omitempty"`
+}
+
+// A LogService of a System or Manager, as kept with its ComponentEndpoint.
+type LogServiceInfo struct {
+	RedfishId          string `json:"RedfishId"`
+	Name               string `json:"Name,omitempty"`
+	LogEntryType       string `json:"LogEntryType,omitempty"`
+	MaxNumberOfRecords string `json:"MaxNumberOfRecords,omitempty"`
+	URL                string `json:"URL"`        // @odata.id
+	EntriesURL         string `json:"EntriesURL"` // @odata.id
+}
+
+// Just enough of a System or Manager to find its LogServices.
+type logServicesLink struct {
+	LogServices ResourceID `json:"LogServices"`
+}
+
+// List the LogServices in the collection at path.  Those without Entries
+// are left out, as there is nothing to read from them.
+func (ep *RedfishEP) getLogServices(path string) []*LogServiceInfo {
+	logs := []*LogServiceInfo{}
+	for _, lsJSON := range ep.getCollectionMembers(path) {
+		var ls LogService
+		if err := json.Unmarshal(lsJSON, &ls); err != nil {
+			if !IsUnmarshalTypeError(err) {
+				errlog.Printf("Bad Decode: %s: %s\n", ep.FQDN+path, err)
+				ep.addDiscoveryError(path, 0, err)
+				continue
+			}
+			errlog.Printf("bad field(s) skipped: %s: %s\n", ep.FQDN+path, err)
+		}
+		if ls.Entries.Oid == "" {
+			continue
+		}
+		logs = append(logs, &LogServiceInfo{
+			RedfishId:          ls.Id,
+			Name:               ls.Name,
+			LogEntryType:       ls.LogEntryType,
+			MaxNumberOfRecords: ls.MaxNumberOfRecords.String(),
+			URL:                ls.Oid,
+			EntriesURL:         ls.Entries.Oid,
+		})
+	}
+	return logs
+}
+
+// Get the LogServices of the System or Manager at odataID, outside of
+// discovery.
+func (ep *RedfishEP) GetLogServices(odataID string) ([]*LogServiceInfo, error) {
+	resJSON, err := ep.GETRelative(odataID)
+	if err != nil {
+		return nil, err
+	}
+	var res logServicesLink
+	if err := json.Unmarshal(resJSON, &res); err != nil {
+		return nil, err
+	}
+	if res.LogServices.Oid == "" {
+		return []*LogServiceInfo{}, nil
+	}
+	return ep.getLogServices(res.LogServices.Oid), nil
+}
+
+// Get the newest entries, up to max, of the log whose Entries collection
+// is at entriesURL, newest first.  Pages of the collection are followed
+// up to the array entry limit.  Entries that the BMC only gives as links
+// are fetched individually, but only the last max of them.
+func (ep *RedfishEP) GetLogEntries(entriesURL string, max int) ([]LogEntry, error) {
+	entries := []LogEntry{}
+	path := entriesURL
+	for path != "" && len(entries) < rfMaxArrayEntries {
+		collJSON, err := ep.GETRelative(path)
+		if err != nil {
+			return nil, err
+		}
+		var coll LogEntryCollection
+		if err := json.Unmarshal(collJSON, &coll); err != nil {
+			if !IsUnmarshalTypeError(err) {
+				return nil, err
+			}
+			errlog.Printf("bad field(s) skipped: %s: %s\n", ep.FQDN+path, err)
+		}
+		entries = append(entries, coll.Members...)
+		if coll.MembersNextLink == path {
+			break
+		}
+		path = coll.MembersNextLink
+	}
+	// Logs are usually oldest first, but not always, so sort by time if
+	// every entry has one.
+	newestFirst := make([]LogEntry, len(entries))
+	created := make([]time.Time, len(entries))
+	allTimed := true
+	for i, e := range entries {
+		j := len(entries) - 1 - i
+		newestFirst[j] = e
+		t, err := time.Parse(time.RFC3339, e.Created)
+		if err != nil {
+			allTimed = false
+		}
+		created[j] = t
+	}
+	if allTimed {
+		sort.Stable(logEntriesByTime{newestFirst, created})
+	}
+	if max > 0 && len(newestFirst) > max {
+		newestFirst = newestFirst[:max]
+	}
+	for i, e := range newestFirst {
+		if e.Id != "" || e.Created != "" || e.Message != "" || e.Oid == "" {
+			continue
+		}
+		entryJSON, err := ep.GETRelative(e.Oid)
+		if err != nil {
+			continue
+		}
+		if err := json.Unmarshal(entryJSON, &newestFirst[i]); err != nil &&
+			!IsUnmarshalTypeError(err) {
+			errlog.Printf("Bad Decode: %s: %s\n", ep.FQDN+e.Oid, err)
+		}
+	}
+	return newestFirst, nil
+}
+
+// Sorts LogEntries newest first by their parsed Created times.
+type logEntriesByTime struct {
+	entries []LogEntry
+	created []time.Time
+}
+
+func (l logEntriesByTime) Len() int { return len(l.entries) }
+func (l logEntriesByTime) Less(i, j int) bool {
+	return l.created[i].After(l.created[j])
+}
+func (l logEntriesByTime) Swap(i, j int) {
+	l.entries[i], l.entries[j] = l.entries[j], l.entries[i]
+	l.created[i], l.created[j] = l.created[j], l.created[i]
+}
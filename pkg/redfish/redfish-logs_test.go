@@ -0,0 +1,194 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+const testPayloadLogs_system = `{
+	"@odata.id": "/redfish/v1/Systems/Self",
+	"Id": "Self",
+	"LogServices": {"@odata.id": "/redfish/v1/Systems/Self/LogServices"}
+}`
+
+const testPayloadLogs_log_services = `{
+	"@odata.id": "/redfish/v1/Systems/Self/LogServices",
+	"Members": [
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL"},
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/Crash"},
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/IML"}
+	]
+}`
+
+const testPayloadLogs_log_service = `{
+	"@odata.id": "/redfish/v1/Systems/Self/LogServices/%s",
+	"Id": "%s",
+	"Name": "%s Log",
+	"LogEntryType": "%s",
+	"MaxNumberOfRecords": 1000,
+	"Entries": {"@odata.id": "/redfish/v1/Systems/Self/LogServices/%s/Entries"}
+}`
+
+// Two pages, oldest first
+const testPayloadLogs_sel_entries = `{
+	"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL/Entries",
+	"Members": [
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL/Entries/1", "Id": "1",
+		 "Created": "2026-10-01T10:00:00Z", "Message": "Power on", "Severity": "OK"},
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL/Entries/2", "Id": "2",
+		 "Created": "2026-10-02T10:00:00+02:00", "Message": "Fan 2 failed", "Severity": "Critical"}
+	],
+	"Members@odata.nextLink": "/redfish/v1/Systems/Self/LogServices/SEL/Entries?$skip=2"
+}`
+
+const testPayloadLogs_sel_entries_2 = `{
+	"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL/Entries",
+	"Members": [
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/SEL/Entries/3", "Id": "3",
+		 "Created": "2026-10-03T10:00:00Z", "Message": "Fan 2 OK", "Severity": "OK"}
+	]
+}`
+
+// Only links, oldest first
+const testPayloadLogs_iml_entries = `{
+	"@odata.id": "/redfish/v1/Systems/Self/LogServices/IML/Entries",
+	"Members": [
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/IML/Entries/1"},
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/IML/Entries/2"},
+		{"@odata.id": "/redfish/v1/Systems/Self/LogServices/IML/Entries/3"}
+	]
+}`
+
+func NewRTFuncLogs() RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1/Systems/Self":                           testPayloadLogs_system,
+		"/redfish/v1/Systems/Self/LogServices":               testPayloadLogs_log_services,
+		"/redfish/v1/Systems/Self/LogServices/SEL":           fmt.Sprintf(testPayloadLogs_log_service, "SEL", "SEL", "SEL", "SEL", "SEL"),
+		"/redfish/v1/Systems/Self/LogServices/IML":           fmt.Sprintf(testPayloadLogs_log_service, "IML", "IML", "IML", "Oem", "IML"),
+		"/redfish/v1/Systems/Self/LogServices/Crash":         `{"@odata.id": "/redfish/v1/Systems/Self/LogServices/Crash", "Id": "Crash"}`,
+		"/redfish/v1/Systems/Self/LogServices/SEL/Entries":   testPayloadLogs_sel_entries,
+		"/redfish/v1/Systems/Self/LogServices/IML/Entries":   testPayloadLogs_iml_entries,
+		"/redfish/v1/Systems/Self/LogServices/IML/Entries/2": `{"Id": "2", "Message": "POST error"}`,
+		"/redfish/v1/Systems/Self/LogServices/IML/Entries/3": `{"Id": "3", "Message": "Memory training"}`,
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		payload, ok := payloads[req.URL.Path]
+		if req.URL.RawQuery == "$skip=2" {
+			payload = testPayloadLogs_sel_entries_2
+		}
+		if !ok {
+			return &http.Response{
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestLogServices(t *testing.T) {
+	ep := TestRedfishEPInitAggregator
+	ep.client = NewTestClient(NewRTFuncLogs())
+
+	// Crash has no Entries, so is left out.
+	logs, err := ep.GetLogServices("/redfish/v1/Systems/Self")
+	if err != nil {
+		t.Fatalf("FAIL: GetLogServices: %s", err)
+	}
+	expLogs := []*LogServiceInfo{{
+		RedfishId:          "IML",
+		Name:               "IML Log",
+		LogEntryType:       "Oem",
+		MaxNumberOfRecords: "1000",
+		URL:                "/redfish/v1/Systems/Self/LogServices/IML",
+		EntriesURL:         "/redfish/v1/Systems/Self/LogServices/IML/Entries",
+	}, {
+		RedfishId:          "SEL",
+		Name:               "SEL Log",
+		LogEntryType:       "SEL",
+		MaxNumberOfRecords: "1000",
+		URL:                "/redfish/v1/Systems/Self/LogServices/SEL",
+		EntriesURL:         "/redfish/v1/Systems/Self/LogServices/SEL/Entries",
+	}}
+	if !reflect.DeepEqual(logs, expLogs) {
+		t.Errorf("FAIL: Expected LogServices %+v, got %+v", expLogs, logs)
+	}
+	if _, err := ep.GetLogServices("/redfish/v1/Systems/Other"); err == nil {
+		t.Errorf("FAIL: Expected error for missing System")
+	}
+
+	tests := []struct {
+		entriesURL string
+		max        int
+		expIDs     []string
+		expMsgs    []string
+	}{
+		// Both pages, newest first, with the offset time sorted correctly
+		{"/redfish/v1/Systems/Self/LogServices/SEL/Entries", 0,
+			[]string{"3", "2", "1"},
+			[]string{"Fan 2 OK", "Fan 2 failed", "Power on"}},
+		{"/redfish/v1/Systems/Self/LogServices/SEL/Entries", 2,
+			[]string{"3", "2"},
+			[]string{"Fan 2 OK", "Fan 2 failed"}},
+		// Links only: the last two are fetched, newest first
+		{"/redfish/v1/Systems/Self/LogServices/IML/Entries", 2,
+			[]string{"3", "2"},
+			[]string{"Memory training", "POST error"}},
+	}
+	for i, test := range tests {
+		entries, err := ep.GetLogEntries(test.entriesURL, test.max)
+		if err != nil {
+			t.Errorf("Testcase %d: FAIL: GetLogEntries: %s", i, err)
+			continue
+		}
+		ids := []string{}
+		msgs := []string{}
+		for _, e := range entries {
+			ids = append(ids, e.Id)
+			msgs = append(msgs, e.Message)
+		}
+		if !reflect.DeepEqual(ids, test.expIDs) ||
+			!reflect.DeepEqual(msgs, test.expMsgs) {
+			t.Errorf("Testcase %d: FAIL: Expected %v %v, got %v %v", i,
+				test.expIDs, test.expMsgs, ids, msgs)
+		}
+	}
+	if _, err := ep.GetLogEntries("/redfish/v1/Systems/Self/LogServices/Crash/Entries", 10); err == nil {
+		t.Errorf("FAIL: Expected error for missing Entries")
+	}
+}
@@ -127,3 +127,28 @@ type NAVirtualFunction struct {
 	MinAssignmentGroupSize int `json:"MinAssignmentGroupSize,omitempty"`
 	NetworkPortMaxCount    int `json:"NetworkPortMaxCount,omitempty"`
 }
+
+// Redfish pass-through from Redfish "NetworkDeviceFunction"
+// These are the logical functions (e.g. a PF for each port) exposed by a
+// NetworkAdapter.  HMS only cares about them for their MAC addresses.
+type NetworkDeviceFunction struct {
+	OContext string `json:"@odata.context"`
+	Oid      string `json:"@odata.id"`
+	Otype    string `json:"@odata.type"`
+
+	Id          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+
+	DeviceEnabled  *bool        `json:"DeviceEnabled,omitempty"`
+	NetDevFuncType string       `json:"NetDevFuncType,omitempty"`
+	Ethernet       *NDFEthernet `json:"Ethernet,omitempty"`
+	Status         *StatusRF    `json:"Status,omitempty"`
+}
+
+// Redfish NetworkDeviceFunction sub-struct - Ethernet
+type NDFEthernet struct {
+	MACAddress          string `json:"MACAddress,omitempty"`
+	PermanentMACAddress string `json:"PermanentMACAddress,omitempty"`
+	MTUSize             int    `json:"MTUSize,omitempty"`
+}
@@ -260,6 +260,7 @@ type OutletActions struct {
 // PowerControl - Outlet
 type ActionPowerControl struct {
 	AllowableValues []string `json:"PowerState@Redfish.AllowableValues,omitempty"`
+	RFActionInfo    string   `json:"@Redfish.ActionInfo,omitempty"`
 	Target          string   `json:"target"`
 	Title           string   `json:"title,omitempty"`
 }
@@ -368,16 +369,16 @@ type Circuit struct {
 	Links CircuitLinks `json:"Links"`
 
 	// Sensors
-	CurrentSensor           SensorExcerpt      `json:"CurrentSensor,omitempty"`
-	EnergySensor            SensorExcerpt      `json:"EnergySensor,omitempty"`
-	FrequencySensor         SensorExcerpt      `json:"FrequencySensor,omitempty"`
-	PowerSensor             SensorPowerExcerpt `json:"PowerSensor,omitempty"`
-	PolyPhaseCurrentSensors Currents           `json:"PolyPhaseCurrentSensors,omitempty"`
-	PolyPhaseEnergySensors  EnergyReadings     `json:"PolyPhaseEnergySensors,omitempty"`
-	PolyPhasePowerSensors   PowerReadings      `json:"PolyPhasePowerSensors,omitempty"`
-	PolyPhaseVoltageSensors Voltages           `json:"PolyPhaseVoltageSensors,omitempty"`
-	TemperatureSensor       SensorExcerpt      `json:"TemperatureSensor,omitempty"`
-	VoltageSensor           SensorExcerpt      `json:"VoltageSensor,omitempty"`
+	CurrentSensor           *SensorExcerpt      `json:"CurrentSensor,omitempty"`
+	EnergySensor            *SensorExcerpt      `json:"EnergySensor,omitempty"`
+	FrequencySensor         *SensorExcerpt      `json:"FrequencySensor,omitempty"`
+	PowerSensor             *SensorPowerExcerpt `json:"PowerSensor,omitempty"`
+	PolyPhaseCurrentSensors *Currents           `json:"PolyPhaseCurrentSensors,omitempty"`
+	PolyPhaseEnergySensors  *EnergyReadings     `json:"PolyPhaseEnergySensors,omitempty"`
+	PolyPhasePowerSensors   *PowerReadings      `json:"PolyPhasePowerSensors,omitempty"`
+	PolyPhaseVoltageSensors *Voltages           `json:"PolyPhaseVoltageSensors,omitempty"`
+	TemperatureSensor       *SensorExcerpt      `json:"TemperatureSensor,omitempty"`
+	VoltageSensor           *SensorExcerpt      `json:"VoltageSensor,omitempty"`
 }
 
 // Redfish Circuit Actions sub-struct
@@ -415,6 +416,7 @@ type CircuitFRUInfo struct {
 type CircuitLinks struct {
 	OEM           *json.RawMessage `json:"Oem,omitempty"`
 	BranchCircuit ResourceID       `json:"BranchCircuit"`
+	Outlets       []ResourceID     `json:"Outlets,omitempty"`
 }
 
 /////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,95 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"encoding/json"
+)
+
+// If true, the SecureBoot resource of each System is fetched during
+// discovery and its state kept with the node's ComponentEndpoint.  Off by
+// default as it costs an extra request per System.
+var rfDiscoverSecureBoot = false
+
+// Turn discovery of System SecureBoot status on or off.
+// NOTE: Global, to be called only once at startup.
+func SetDiscoverSecureBoot(on bool) {
+	rfDiscoverSecureBoot = on
+}
+
+// Check whether System SecureBoot status is discovered.
+func GetDiscoverSecureBoot() bool {
+	return rfDiscoverSecureBoot
+}
+
+// JSON decoded struct returned from Redfish "SecureBoot"
+// Example: /redfish/v1/Systems/<system_id>/SecureBoot
+type SecureBoot struct {
+	OContext              string `json:"@odata.context"`
+	Oid                   string `json:"@odata.id"`
+	Otype                 string `json:"@odata.type"`
+	Id                    string `json:"Id"`
+	Name                  string `json:"Name"`
+	SecureBootEnable      *bool  `json:"SecureBootEnable"`
+	SecureBootCurrentBoot string `json:"SecureBootCurrentBoot"`
+	SecureBootMode        string `json:"SecureBootMode"`
+}
+
+// SecureBoot status of a System, as kept with its ComponentEndpoint.
+// SecureBootEnable is whether it will be used on the next boot and
+// SecureBootCurrentBoot whether it was on the current one.
+type SecureBootInfo struct {
+	SecureBootEnable      *bool  `json:"SecureBootEnable,omitempty"`
+	SecureBootCurrentBoot string `json:"SecureBootCurrentBoot,omitempty"` // Enabled, Disabled
+	SecureBootMode        string `json:"SecureBootMode,omitempty"`        // SetupMode, UserMode, ...
+	URL                   string `json:"URL"`                             // @odata.id
+}
+
+// Fetch the SecureBoot resource of a System, if it has one, and store its
+// status.  Failures are logged but do not fail discovery of the system.
+func (s *EpSystem) discoverSecureBoot() {
+	path := s.SystemRF.SecureBoot.Oid
+	if !rfDiscoverSecureBoot || path == "" {
+		return
+	}
+	sbJSON, err := s.epRF.GETRelative(path)
+	if err != nil || sbJSON == nil {
+		errlog.Printf("%s: Could not get SecureBoot %s: %v", s.epRF.ID, path, err)
+		return
+	}
+	var sb SecureBoot
+	if err := json.Unmarshal(sbJSON, &sb); err != nil {
+		if !IsUnmarshalTypeError(err) {
+			errlog.Printf("ERROR: json decode failed: %s: %s\n", path, err)
+			s.epRF.addDiscoveryError(path, 0, err)
+			return
+		}
+		errlog.Printf("bad field(s) skipped: %s: %s\n", path, err)
+	}
+	s.SecureBoot = &SecureBootInfo{
+		SecureBootEnable:      sb.SecureBootEnable,
+		SecureBootCurrentBoot: sb.SecureBootCurrentBoot,
+		SecureBootMode:        sb.SecureBootMode,
+		URL:                   path,
+	}
+}
@@ -0,0 +1,96 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSystemSecureBoot(t *testing.T) {
+	defer SetDiscoverSecureBoot(false)
+
+	payloads := map[string]string{
+		"/redfish/v1/Systems/Node0/SecureBoot": `{
+			"@odata.id": "/redfish/v1/Systems/Node0/SecureBoot",
+			"Id": "SecureBoot",
+			"SecureBootEnable": true,
+			"SecureBootCurrentBoot": "Disabled",
+			"SecureBootMode": "UserMode"
+		}`,
+		"/redfish/v1/Systems/Node1/SecureBoot": `{"SecureBootEnable": "yes"`,
+	}
+	ep := TestRedfishEPInitAggregator
+	ep.client = NewTestClient(func(req *http.Request) *http.Response {
+		payload, ok := payloads[req.URL.Path]
+		if !ok {
+			return &http.Response{
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+			Header:     make(http.Header),
+		}
+	})
+	enabled := true
+	tests := []struct {
+		enabled   bool
+		oid       string
+		expInfo   *SecureBootInfo
+		expErrors int
+	}{
+		{false, "/redfish/v1/Systems/Node0/SecureBoot", nil, 0},
+		{true, "", nil, 0},
+		{true, "/redfish/v1/Systems/Node0/SecureBoot", &SecureBootInfo{
+			SecureBootEnable:      &enabled,
+			SecureBootCurrentBoot: "Disabled",
+			SecureBootMode:        "UserMode",
+			URL:                   "/redfish/v1/Systems/Node0/SecureBoot",
+		}, 0},
+		// Bad JSON, and missing, are recorded but leave the System alone
+		{true, "/redfish/v1/Systems/Node1/SecureBoot", nil, 1},
+		{true, "/redfish/v1/Systems/Node2/SecureBoot", nil, 1},
+	}
+	for i, test := range tests {
+		SetDiscoverSecureBoot(test.enabled)
+		ep.DiscInfo.Errors = nil
+		s := NewEpSystem(&ep, ResourceID{Oid: "/redfish/v1/Systems/Node0"}, 0)
+		s.SystemRF.SecureBoot.Oid = test.oid
+		s.discoverSecureBoot()
+		if !reflect.DeepEqual(s.SecureBoot, test.expInfo) {
+			t.Errorf("Testcase %d: FAIL: Expected %+v, got %+v", i,
+				test.expInfo, s.SecureBoot)
+		}
+		if len(ep.DiscInfo.Errors) != test.expErrors {
+			t.Errorf("Testcase %d: FAIL: Expected %d errors, got %v", i,
+				test.expErrors, ep.DiscInfo.Errors)
+		}
+	}
+}
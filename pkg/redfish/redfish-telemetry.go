@@ -0,0 +1,114 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+// JSON decoded struct returned from Redfish "TelemetryService"
+// Example: /redfish/v1/TelemetryService
+type TelemetryService struct {
+	OContext       string `json:"@odata.context"`
+	Oid            string `json:"@odata.id"`
+	Otype          string `json:"@odata.type"`
+	Id             string `json:"Id"`
+	Name           string `json:"Name"`
+	Description    string `json:"Description"`
+	ServiceEnabled *bool  `json:"ServiceEnabled,omitempty"`
+
+	Status StatusRF `json:"Status"`
+
+	MinCollectionInterval string `json:"MinCollectionInterval,omitempty"`
+
+	MetricDefinitions       ResourceID `json:"MetricDefinitions"`
+	MetricReportDefinitions ResourceID `json:"MetricReportDefinitions"`
+	MetricReports           ResourceID `json:"MetricReports"`
+}
+
+// JSON decoded collection struct returned from Redfish
+// "MetricReportDefinitionCollection"
+// Example: /redfish/v1/TelemetryService/MetricReportDefinitions
+type MetricReportDefinitionCollection GenericCollection
+
+// JSON decoded collection struct returned from Redfish
+// "MetricReportCollection"
+// Example: /redfish/v1/TelemetryService/MetricReports
+type MetricReportCollection GenericCollection
+
+// JSON decoded struct returned from Redfish "MetricReportDefinition", i.e.
+// which metrics go into a MetricReport and when it is generated.
+// Example: /redfish/v1/TelemetryService/MetricReportDefinitions/<def_id>
+type MetricReportDefinition struct {
+	OContext string `json:"@odata.context"`
+	Oid      string `json:"@odata.id"`
+	Otype    string `json:"@odata.type"`
+	Id       string `json:"Id"`
+	Name     string `json:"Name"`
+
+	MetricReportDefinitionType string   `json:"MetricReportDefinitionType"`
+	ReportActions              []string `json:"ReportActions"`
+	MetricProperties           []string `json:"MetricProperties"`
+
+	Metrics      []MetricReportDefinitionMetric `json:"Metrics"`
+	MetricReport ResourceID                     `json:"MetricReport"`
+
+	Status StatusRF `json:"Status"`
+}
+
+// Redfish MetricReportDefinition - one entry in the Metrics array
+type MetricReportDefinitionMetric struct {
+	MetricId         string   `json:"MetricId"`
+	MetricProperties []string `json:"MetricProperties"`
+}
+
+// JSON decoded struct of a Redfish "MetricReport".  This is both what is
+// under TelemetryService/MetricReports and what is POSTed to subscribers
+// whose EventFormatType is MetricReport.
+// Example: /redfish/v1/TelemetryService/MetricReports/<report_id>
+type MetricReport struct {
+	OContext  string `json:"@odata.context"`
+	Oid       string `json:"@odata.id"`
+	Otype     string `json:"@odata.type"`
+	Id        string `json:"Id"`
+	Name      string `json:"Name"`
+	Timestamp string `json:"Timestamp"`
+	Context   string `json:"Context,omitempty"`
+
+	MetricReportDefinition ResourceID    `json:"MetricReportDefinition"`
+	MetricValues           []MetricValue `json:"MetricValues"`
+}
+
+// Redfish MetricReport - one sample in the MetricValues array
+type MetricValue struct {
+	MetricId       string `json:"MetricId"`
+	MetricProperty string `json:"MetricProperty"`
+	MetricValue    string `json:"MetricValue"`
+	Timestamp      string `json:"Timestamp"`
+}
+
+// Body of a POST to EventService/Subscriptions that subscribes to
+// MetricReports.
+type MetricReportSubscription struct {
+	Destination             string       `json:"Destination"`
+	Context                 string       `json:"Context"`
+	Protocol                string       `json:"Protocol"`
+	EventFormatType         string       `json:"EventFormatType"`
+	MetricReportDefinitions []ResourceID `json:"MetricReportDefinitions,omitempty"`
+}
@@ -0,0 +1,198 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"encoding/json"
+)
+
+// Kinds of ThermalSensorInfo
+const (
+	ThermalSensorTemperature = "Temperature"
+	ThermalSensorFan         = "Fan"
+)
+
+// If true, the Thermal resource of each Chassis is fetched during discovery
+// and its fans and temperature sensors are stored with the chassis, and with
+// the node the chassis belongs to, if any.  Off by default as it costs an
+// extra request per chassis.
+var rfDiscoverThermal = false
+
+// Turn discovery of Chassis Thermal sensors on or off.
+// NOTE: Global, to be called only once at startup.
+func SetDiscoverThermal(on bool) {
+	rfDiscoverThermal = on
+}
+
+// Check whether Chassis Thermal sensors are discovered.
+func GetDiscoverThermal() bool {
+	return rfDiscoverThermal
+}
+
+// JSON decoded struct returned from Redfish "Thermal"
+// Example: /redfish/v1/Chassis/<chassis_id>/Thermal
+type Thermal struct {
+	OContext     string               `json:"@odata.context"`
+	Oid          string               `json:"@odata.id"`
+	Otype        string               `json:"@odata.type"`
+	Id           string               `json:"Id"`
+	Name         string               `json:"Name"`
+	Temperatures []ThermalTemperature `json:"Temperatures"`
+	Fans         []ThermalFan         `json:"Fans"`
+}
+
+// Redfish Thermal - one entry in the Temperatures array
+type ThermalTemperature struct {
+	Oid             string `json:"@odata.id"`
+	MemberId        string `json:"MemberId"`
+	Name            string `json:"Name"`
+	SensorNumber    *int   `json:"SensorNumber,omitempty"`
+	PhysicalContext string `json:"PhysicalContext"`
+
+	UpperThresholdNonCritical *float64 `json:"UpperThresholdNonCritical,omitempty"`
+	UpperThresholdCritical    *float64 `json:"UpperThresholdCritical,omitempty"`
+	UpperThresholdFatal       *float64 `json:"UpperThresholdFatal,omitempty"`
+	LowerThresholdNonCritical *float64 `json:"LowerThresholdNonCritical,omitempty"`
+	LowerThresholdCritical    *float64 `json:"LowerThresholdCritical,omitempty"`
+	LowerThresholdFatal       *float64 `json:"LowerThresholdFatal,omitempty"`
+	MinReadingRangeTemp       *float64 `json:"MinReadingRangeTemp,omitempty"`
+	MaxReadingRangeTemp       *float64 `json:"MaxReadingRangeTemp,omitempty"`
+
+	Status StatusRF `json:"Status"`
+}
+
+// Redfish Thermal - one entry in the Fans array
+type ThermalFan struct {
+	Oid             string `json:"@odata.id"`
+	MemberId        string `json:"MemberId"`
+	Name            string `json:"Name"`
+	FanName         string `json:"FanName"` // Older schemas
+	PhysicalContext string `json:"PhysicalContext"`
+	ReadingUnits    string `json:"ReadingUnits"`
+
+	UpperThresholdNonCritical *float64 `json:"UpperThresholdNonCritical,omitempty"`
+	UpperThresholdCritical    *float64 `json:"UpperThresholdCritical,omitempty"`
+	UpperThresholdFatal       *float64 `json:"UpperThresholdFatal,omitempty"`
+	LowerThresholdNonCritical *float64 `json:"LowerThresholdNonCritical,omitempty"`
+	LowerThresholdCritical    *float64 `json:"LowerThresholdCritical,omitempty"`
+	LowerThresholdFatal       *float64 `json:"LowerThresholdFatal,omitempty"`
+	MinReadingRange           *float64 `json:"MinReadingRange,omitempty"`
+	MaxReadingRange           *float64 `json:"MaxReadingRange,omitempty"`
+
+	Status StatusRF `json:"Status"`
+}
+
+// A fan or temperature sensor as stored with a ComponentEndpoint.  This is
+// sensor inventory, readings are not kept.
+type ThermalSensorInfo struct {
+	Kind                      string   `json:"Kind"` // Temperature or Fan
+	RedfishURL                string   `json:"RedfishURL,omitempty"`
+	MemberId                  string   `json:"MemberId"`
+	Name                      string   `json:"Name"`
+	SensorNumber              *int     `json:"SensorNumber,omitempty"`
+	PhysicalContext           string   `json:"PhysicalContext,omitempty"`
+	ReadingUnits              string   `json:"ReadingUnits,omitempty"`
+	State                     StateRF  `json:"State,omitempty"`
+	UpperThresholdNonCritical *float64 `json:"UpperThresholdNonCritical,omitempty"`
+	UpperThresholdCritical    *float64 `json:"UpperThresholdCritical,omitempty"`
+	UpperThresholdFatal       *float64 `json:"UpperThresholdFatal,omitempty"`
+	LowerThresholdNonCritical *float64 `json:"LowerThresholdNonCritical,omitempty"`
+	LowerThresholdCritical    *float64 `json:"LowerThresholdCritical,omitempty"`
+	LowerThresholdFatal       *float64 `json:"LowerThresholdFatal,omitempty"`
+}
+
+// Convert the sensors of a Thermal resource into ThermalSensorInfo,
+// temperatures first.  Absent sensors are skipped.
+func (t *Thermal) SensorInfo() []*ThermalSensorInfo {
+	sensors := make([]*ThermalSensorInfo, 0,
+		len(t.Temperatures)+len(t.Fans))
+	for _, temp := range t.Temperatures {
+		if temp.Status.State == "Absent" {
+			continue
+		}
+		sensors = append(sensors, &ThermalSensorInfo{
+			Kind:                      ThermalSensorTemperature,
+			RedfishURL:                temp.Oid,
+			MemberId:                  temp.MemberId,
+			Name:                      temp.Name,
+			SensorNumber:              temp.SensorNumber,
+			PhysicalContext:           temp.PhysicalContext,
+			ReadingUnits:              "Cel",
+			State:                     temp.Status.State,
+			UpperThresholdNonCritical: temp.UpperThresholdNonCritical,
+			UpperThresholdCritical:    temp.UpperThresholdCritical,
+			UpperThresholdFatal:       temp.UpperThresholdFatal,
+			LowerThresholdNonCritical: temp.LowerThresholdNonCritical,
+			LowerThresholdCritical:    temp.LowerThresholdCritical,
+			LowerThresholdFatal:       temp.LowerThresholdFatal,
+		})
+	}
+	for _, fan := range t.Fans {
+		if fan.Status.State == "Absent" {
+			continue
+		}
+		name := fan.Name
+		if name == "" {
+			name = fan.FanName
+		}
+		sensors = append(sensors, &ThermalSensorInfo{
+			Kind:                      ThermalSensorFan,
+			RedfishURL:                fan.Oid,
+			MemberId:                  fan.MemberId,
+			Name:                      name,
+			PhysicalContext:           fan.PhysicalContext,
+			ReadingUnits:              fan.ReadingUnits,
+			State:                     fan.Status.State,
+			UpperThresholdNonCritical: fan.UpperThresholdNonCritical,
+			UpperThresholdCritical:    fan.UpperThresholdCritical,
+			UpperThresholdFatal:       fan.UpperThresholdFatal,
+			LowerThresholdNonCritical: fan.LowerThresholdNonCritical,
+			LowerThresholdCritical:    fan.LowerThresholdCritical,
+			LowerThresholdFatal:       fan.LowerThresholdFatal,
+		})
+	}
+	return sensors
+}
+
+// Fetch the Thermal resource of a Chassis, if it has one, and store its
+// sensors.  Failures are logged but do not fail discovery of the chassis.
+func (c *EpChassis) discoverThermal() {
+	path := c.ChassisRF.Thermal.Oid
+	if !rfDiscoverThermal || path == "" {
+		return
+	}
+	thermalJSON, err := c.epRF.GETRelative(path)
+	if err != nil || thermalJSON == nil {
+		errlog.Printf("%s: Could not get Thermal %s: %v", c.epRF.ID, path, err)
+		return
+	}
+	var thermal Thermal
+	if err := json.Unmarshal(thermalJSON, &thermal); err != nil {
+		if !IsUnmarshalTypeError(err) {
+			errlog.Printf("ERROR: json decode failed: %s: %s\n", path, err)
+			return
+		}
+		errlog.Printf("bad field(s) skipped: %s: %s\n", path, err)
+	}
+	c.Sensors = thermal.SensorInfo()
+}
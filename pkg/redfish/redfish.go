@@ -132,6 +132,10 @@ type SerialInterfaceCollection GenericCollection
 // Example: /redfish/v1/Chassis/<chassis_id>/NetworkAdapters
 type NetworkAdapterCollection GenericCollection
 
+// JSON decoded collection struct returned from Redfish "NetworkDeviceFunction"
+// Example: /redfish/v1/Chassis/<chassis_id>/NetworkAdapters/<na_id>/NetworkDeviceFunctions
+type NetworkDeviceFunctionCollection GenericCollection
+
 // JSON decoded collection struct returned from Redfish "Controls"
 // Example: /redfish/v1/Chassis/<chassis_id>/Controls
 type ControlCollection GenericCollection
@@ -144,6 +148,8 @@ type ServiceRoot struct {
 	Description    string `json:"Description"`
 	RedfishVersion string `json:"RedfishVersion"`
 	UUID           string `json:"UUID"`
+	Vendor         string `json:"Vendor,omitempty"`
+	Product        string `json:"Product,omitempty"`
 
 	Systems        ResourceID `json:"Systems"`
 	Chassis        ResourceID `json:"Chassis"`
@@ -154,13 +160,22 @@ type ServiceRoot struct {
 	EventService   ResourceID `json:"EventService"`
 	UpdateService  ResourceID `json:"UpdateService"`
 	Registries     ResourceID `json:"Registries"`
+	Fabrics        ResourceID `json:"Fabrics"`
 
-	// TODO: Later stuff: StorageSystems, Fabrics, UpdateService, JsonSchemas
+	// TODO: Later stuff: StorageSystems, UpdateService, JsonSchemas
 
 	// PDU stuff
 	PowerEquipment    ResourceID `json:"PowerEquipment"`
 	PowerDistribution ResourceID `json:"PowerDistribution"`
 
+	// Aggregators
+	AggregationService ResourceID `json:"AggregationService"`
+
+	// Telemetry
+	TelemetryService ResourceID `json:"TelemetryService"`
+
+	CertificateService ResourceID `json:"CertificateService"`
+
 	Links ServiceRootLinks `json:"Links"`
 }
 
@@ -282,10 +297,12 @@ type Chassis struct {
 	ChassisLocationInfoRF
 	ChassisFRUInfoRF
 
-	PowerState string   `json:"PowerState"`
-	Status     StatusRF `json:"Status"`
+	PowerState string    `json:"PowerState"`
+	Status     StatusRF  `json:"Status"`
+	Location   *Location `json:"Location,omitempty"`
 
 	NetworkAdapters ResourceID `json:"NetworkAdapters"`
+	FabricAdapters  ResourceID `json:"FabricAdapters"`
 	Power           ResourceID `json:"Power"`
 	Assembly        ResourceID `json:"Assembly"`
 	Thermal         ResourceID `json:"Thermal"`
@@ -450,6 +467,25 @@ type ComputerSystemBoot struct {
 	BootSourceOverrideTarget     string   `json:"BootSourceOverrideTarget"`
 	AllowableValues              []string `json:"BootSourceOverrideTarget@Redfish.AllowableValues"`
 	UefiTargetBootSourceOverride string   `json:"UefiTargetBootSourceOverride"`
+
+	BootSourceOverrideMode     string      `json:"BootSourceOverrideMode,omitempty"` // UEFI or Legacy
+	ModeAllowableValues        []string    `json:"BootSourceOverrideMode@Redfish.AllowableValues,omitempty"`
+	BootNext                   string      `json:"BootNext,omitempty"`
+	BootOrder                  []string    `json:"BootOrder,omitempty"`
+	BootOrderPropertySelection string      `json:"BootOrderPropertySelection,omitempty"`
+	AliasBootOrder             []string    `json:"AliasBootOrder,omitempty"`
+	BootOptions                *ResourceID `json:"BootOptions,omitempty"`
+}
+
+// True if the System gave none of the Boot settings.
+func (b *ComputerSystemBoot) IsEmpty() bool {
+	return b.BootSourceOverrideEnabled == "" &&
+		b.BootSourceOverrideTarget == "" &&
+		b.BootSourceOverrideMode == "" &&
+		b.UefiTargetBootSourceOverride == "" &&
+		b.BootNext == "" &&
+		len(b.BootOrder) == 0 &&
+		len(b.AliasBootOrder) == 0
 }
 
 // Redfish Links struct - All those defined for ComputerSystem objects
@@ -477,6 +513,21 @@ type ComputerSystemMemorySummary struct {
 	//Status               rf.StatusRF    `json:"Status"`
 }
 
+// Redfish TrustedModules struct - Sub-struct of ComputerSystem, e.g. a TPM.
+type ComputerSystemTrustedModule struct {
+	FirmwareVersion        string            `json:"FirmwareVersion,omitempty"`
+	FirmwareVersion2       string            `json:"FirmwareVersion2,omitempty"`
+	InterfaceType          string            `json:"InterfaceType,omitempty"`
+	InterfaceTypeSelection string            `json:"InterfaceTypeSelection,omitempty"`
+	Status                 StatusRF          `json:"Status"`
+	OEM                    *TrustedModuleOEM `json:"Oem,omitempty"`
+}
+
+// Redfish TrustedModule OEM sub-struct.  HPE puts the TPM vendor here.
+type TrustedModuleOEM struct {
+	Hpe *TrustedModuleOemHpe `json:"Hpe,omitempty"`
+}
+
 // Location-specific Redfish properties to be stored in hardware inventory
 // These are only relevant to the currently installed location of the FRU
 // TODO: How to version these (as HMS structures).
@@ -490,6 +541,8 @@ type SystemLocationInfoRF struct {
 	ProcessorSummary ComputerSystemProcessorSummary `json:"ProcessorSummary"`
 
 	MemorySummary ComputerSystemMemorySummary `json:"MemorySummary"`
+
+	TrustedModules []ComputerSystemTrustedModule `json:"TrustedModules,omitempty"`
 }
 
 // Durable Redfish properties to be stored in hardware inventory as
@@ -799,6 +852,16 @@ type EventDestination struct {
 	Destination string   `json:"Destination"`
 	EventTypes  []string `json:"EventTypes"`
 	Protocol    string   `json:"Protocol"`
+
+	EventFormatType string `json:"EventFormatType,omitempty"`
+}
+
+// Body of a POST to the EventService Subscriptions collection subscribing
+// to all events, which most services do when EventTypes is left out.
+type EventSubscription struct {
+	Destination string `json:"Destination"`
+	Context     string `json:"Context"`
+	Protocol    string `json:"Protocol"`
 }
 
 // An individual event record.  Multiple EventRecords can be contained in the
@@ -974,6 +1037,40 @@ type HttpPushUriApplyTime struct {
 	MaintenanceWindowStartTime         string      `json:"MaintenanceWindowStartTime,omitempty"`
 }
 
+// Redfish SoftwareInventory, i.e. a member of the FirmwareInventory
+// collection of the UpdateService.  There is one for each updatable device
+// (BMC, BIOS, CPLD, NIC, ...), with the version it is running.
+type SoftwareInventory struct {
+	Oid                    string       `json:"@odata.id"`
+	Id                     string       `json:"Id"`
+	Name                   string       `json:"Name"`
+	Description            string       `json:"Description,omitempty"`
+	Version                string       `json:"Version"`
+	Updateable             *bool        `json:"Updateable,omitempty"`
+	SoftwareId             string       `json:"SoftwareId,omitempty"`
+	Manufacturer           string       `json:"Manufacturer,omitempty"`
+	ReleaseDate            string       `json:"ReleaseDate,omitempty"`
+	LowestSupportedVersion string       `json:"LowestSupportedVersion,omitempty"`
+	Status                 *StatusRF    `json:"Status,omitempty"`
+	RelatedItem            []ResourceID `json:"RelatedItem,omitempty"`
+}
+
+// What is kept as the ServiceInfo of an UpdateService ServiceEndpoint: the
+// service, with its update targets, and the firmware on each device in its
+// FirmwareInventory.
+type UpdateServiceInfo struct {
+	UpdateService
+	Firmware []SoftwareInventory `json:"Firmware,omitempty"`
+}
+
+// The target of the UpdateService.SimpleUpdate action, if there is one.
+func (u *UpdateServiceInfo) SimpleUpdateTarget() string {
+	if u.Actions.SimpleUpdate == nil {
+		return ""
+	}
+	return u.Actions.SimpleUpdate.Target
+}
+
 // RedfishErrorContents - Contains properties used to describe an error from a
 // Redfish Service. Code - A string indicating a specific MessageId from the
 // message registry. Message - A human-readable error message corresponding to
@@ -23,14 +23,64 @@
 package rf
 
 import (
+	"fmt"
+	"math/rand"
+	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/Cray-HPE/hms-certs/pkg/hms_certs"
+	"github.com/hashicorp/go-retryablehttp"
 )
 
 var httpRFClient *hms_certs.HTTPClientPair
 var httpClientTimeout = 30
 
+// Limits on what we will accept from a single Redfish response so that a
+// misbehaving BMC cannot drive huge allocations during discovery.
+var httpMaxResponseBytes int64 = 32 * 1024 * 1024
+var rfMaxArrayEntries = 1024
+
+// How GETRelative() retries requests that fail with a timeout or other
+// transport error, or with a 5xx/429 from a BMC that is busy.
+type RetryPolicy struct {
+	Retries    int           `json:"Retries"`    // Retries after the first try
+	Backoff    time.Duration `json:"Backoff"`    // Wait before the first retry, doubled for each after
+	MaxBackoff time.Duration `json:"MaxBackoff"` // Longest wait between retries, 0 for no limit
+	Jitter     float64       `json:"Jitter"`     // Each wait is randomly varied by up to this fraction
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	Retries:    3,
+	Backoff:    1 * time.Second,
+	MaxBackoff: 30 * time.Second,
+}
+
+var rfRetryPolicy = DefaultRetryPolicy
+
+// Tuning for the HTTP clients used to talk to endpoints, e.g. longer
+// timeouts for slow CMCs or bigger connection pools for dense chassis.
+// Zero values leave the setting at its default: the HTTP client timeout for
+// Timeout (see SetHTTPClientTimeout()), and Go's net/http defaults for the
+// rest.  Times are in seconds.
+type HTTPOptions struct {
+	Timeout             int  `json:"Timeout,omitempty"`             // Whole request, incl. reading the body
+	TLSHandshakeTimeout int  `json:"TLSHandshakeTimeout,omitempty"` // Per connection
+	IdleConnTimeout     int  `json:"IdleConnTimeout,omitempty"`     // Before an idle connection is closed
+	MaxIdleConns        int  `json:"MaxIdleConns,omitempty"`        // Over all hosts
+	MaxIdleConnsPerHost int  `json:"MaxIdleConnsPerHost,omitempty"` // Kept alive for reuse
+	MaxConnsPerHost     int  `json:"MaxConnsPerHost,omitempty"`     // Including active ones
+	DisableKeepAlives   bool `json:"DisableKeepAlives,omitempty"`   // New connection per request
+}
+
+var rfHTTPOptions HTTPOptions
+
+// HTTP clients created for non-default options, so that endpoints with the
+// same options share a connection pool.
+var httpOptClients = make(map[HTTPOptions]*hms_certs.HTTPClientPair)
+var httpOptClientsLock sync.Mutex
+
 //var httpClientProxyURL = ""
 //var httpClientInsecureSkipVerify = true
 
@@ -52,6 +102,201 @@ func GetHTTPClientTimeout() int {
 	return httpClientTimeout
 }
 
+// Set the maximum size in bytes of a single Redfish response body.  Larger
+// responses fail with ErrRFDiscResponseTooLarge.
+// NOTE: Global, to be called only once at startup.
+func SetMaxResponseBytes(max int64) {
+	if max > 0 {
+		httpMaxResponseBytes = max
+	} else {
+		errlog.Printf("SetMaxResponseBytes: bad arg '%d'", max)
+	}
+}
+
+// Get the maximum size in bytes of a single Redfish response body.
+func GetMaxResponseBytes() int64 {
+	return httpMaxResponseBytes
+}
+
+// Set the maximum number of entries kept for any one JSON array in a
+// Redfish response, e.g. Voltages.  Extra entries are dropped.  Collection
+// Members are never dropped, as the components they lead to would be lost.
+// NOTE: Global, to be called only once at startup.
+func SetMaxArrayEntries(max int) {
+	if max > 0 {
+		rfMaxArrayEntries = max
+	} else {
+		errlog.Printf("SetMaxArrayEntries: bad arg '%d'", max)
+	}
+}
+
+// Get the maximum number of entries kept for any one JSON array in a
+// Redfish response.
+func GetMaxArrayEntries() int {
+	return rfMaxArrayEntries
+}
+
+// Set the policy for retrying failed Redfish GETs.
+// NOTE: Global, to be called only once at startup.
+func SetRetryPolicy(p RetryPolicy) {
+	if p.Retries < 0 || p.Backoff < 0 || p.MaxBackoff < 0 ||
+		p.Jitter < 0 || p.Jitter > 1 {
+		errlog.Printf("SetRetryPolicy: bad arg '%+v'", p)
+		return
+	}
+	rfRetryPolicy = p
+}
+
+// Get the policy for retrying failed Redfish GETs.
+func GetRetryPolicy() RetryPolicy {
+	return rfRetryPolicy
+}
+
+// How long to wait before the given retry, counting from 0.
+func (p RetryPolicy) Wait(retry int) time.Duration {
+	wait := p.Backoff
+	for i := 0; i < retry && wait > 0 && wait < time.Hour; i++ {
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 && wait > 0 {
+		wait += time.Duration(p.Jitter * (2*rand.Float64() - 1) * float64(wait))
+	}
+	return wait
+}
+
+// Check the options for bad values.
+func (o HTTPOptions) Verify() error {
+	if o.Timeout < 0 || o.TLSHandshakeTimeout < 0 || o.IdleConnTimeout < 0 ||
+		o.MaxIdleConns < 0 || o.MaxIdleConnsPerHost < 0 ||
+		o.MaxConnsPerHost < 0 {
+		return fmt.Errorf("HTTP options must not be negative")
+	}
+	return nil
+}
+
+// Options with any non-zero values in over replacing those in o.
+func (o HTTPOptions) Merge(over *HTTPOptions) HTTPOptions {
+	if over == nil {
+		return o
+	}
+	if over.Timeout != 0 {
+		o.Timeout = over.Timeout
+	}
+	if over.TLSHandshakeTimeout != 0 {
+		o.TLSHandshakeTimeout = over.TLSHandshakeTimeout
+	}
+	if over.IdleConnTimeout != 0 {
+		o.IdleConnTimeout = over.IdleConnTimeout
+	}
+	if over.MaxIdleConns != 0 {
+		o.MaxIdleConns = over.MaxIdleConns
+	}
+	if over.MaxIdleConnsPerHost != 0 {
+		o.MaxIdleConnsPerHost = over.MaxIdleConnsPerHost
+	}
+	if over.MaxConnsPerHost != 0 {
+		o.MaxConnsPerHost = over.MaxConnsPerHost
+	}
+	if over.DisableKeepAlives {
+		o.DisableKeepAlives = true
+	}
+	return o
+}
+
+// Set the default options for the HTTP clients used during Redfish
+// interogation.  Vendor profiles may override them.
+// NOTE: Global, to be called only once at startup.
+func SetHTTPOptions(o HTTPOptions) {
+	if err := o.Verify(); err != nil {
+		errlog.Printf("SetHTTPOptions: bad arg '%+v': %s", o, err)
+		return
+	}
+	rfHTTPOptions = o
+}
+
+// Get the default options for the HTTP clients used during Redfish
+// interogation.
+func GetHTTPOptions() HTTPOptions {
+	return rfHTTPOptions
+}
+
+// Apply the connection options to a client's transport.  The hms_certs
+// clients each have their own http.Transport.
+func applyHTTPOptions(cp *hms_certs.HTTPClientPair, o HTTPOptions) {
+	for _, c := range []*retryablehttp.Client{cp.SecureClient, cp.InsecureClient} {
+		if c == nil || c.HTTPClient == nil {
+			continue
+		}
+		tr, ok := c.HTTPClient.Transport.(*http.Transport)
+		if !ok {
+			continue
+		}
+		tr.TLSHandshakeTimeout = time.Duration(o.TLSHandshakeTimeout) * time.Second
+		tr.IdleConnTimeout = time.Duration(o.IdleConnTimeout) * time.Second
+		tr.MaxIdleConns = o.MaxIdleConns
+		tr.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+		tr.MaxConnsPerHost = o.MaxConnsPerHost
+		tr.DisableKeepAlives = o.DisableKeepAlives
+	}
+}
+
+// Create an HTTP client pair, falling back to one that doesn't verify
+// certs if the CA bundle can't be used.
+func newHTTPClientPair(o HTTPOptions) *hms_certs.HTTPClientPair {
+	timeout := httpClientTimeout
+	if o.Timeout != 0 {
+		timeout = o.Timeout
+	}
+	uri := os.Getenv("SMD_CA_URI")
+	// TODO: Why CreateHTTPClientPair() instead of CreateRetryableHTTPClientPair()??
+	cp, cerr := hms_certs.CreateHTTPClientPair(uri, timeout)
+	if cerr != nil {
+		errlog.Printf("Can't create TLS cert-enabled HTTP transport, reverting to less secure transport.")
+		cp, cerr = hms_certs.CreateHTTPClientPair("", timeout)
+		if cerr != nil {
+			errlog.Printf("Can't create any HTTP transport!")
+			return nil
+		}
+	}
+	applyHTTPOptions(cp, o)
+	return cp
+}
+
+// Returns an HTTP Client for the default options with any non-zero values
+// in o replacing them.  Clients are shared by all callers with the same
+// options.
+func RfHTTPOptionsClient(o *HTTPOptions) *hms_certs.HTTPClientPair {
+	opts := rfHTTPOptions.Merge(o)
+	if opts == rfHTTPOptions {
+		return RfDefaultClient()
+	}
+	httpOptClientsLock.Lock()
+	defer httpOptClientsLock.Unlock()
+	cp := httpOptClients[opts]
+	if cp == nil {
+		cp = newHTTPClientPair(opts)
+		if cp != nil {
+			httpOptClients[opts] = cp
+		}
+	}
+	return cp
+}
+
+// Use the default HTTP options with any non-zero values in o replacing
+// them to talk to the endpoint, e.g. from its vendor profile.  nil goes
+// back to the defaults.
+func (ep *RedfishEP) SetHTTPOptions(o *HTTPOptions) {
+	if cp := RfHTTPOptionsClient(o); cp != nil {
+		ep.client = cp
+	}
+}
+
 /*
 // Set HTTP client proxy used during Redfish interogation, including port
 // and protocol (see http package: socks5, http, https).  Defaults assigned
@@ -79,20 +324,8 @@ func GetHTTPClientInsecureSkipVerify() bool {
 
 // Returns default-configuration HTTP Client
 func RfDefaultClient() *hms_certs.HTTPClientPair {
-	var cerr error
 	if httpRFClient == nil {
-		uri := os.Getenv("SMD_CA_URI")
-		// TODO: Why CreateHTTPClientPair() instead of CreateRetryableHTTPClientPair()??
-		httpRFClient, cerr = hms_certs.CreateHTTPClientPair(uri, httpClientTimeout)
-		if cerr != nil {
-			errlog.Printf("Can't create TLS cert-enabled HTTP transport, reverting to less secure transport.")
-			httpRFClient, cerr = hms_certs.CreateHTTPClientPair("", httpClientTimeout)
-			if cerr != nil {
-				errlog.Printf("Can't create any HTTP transport!")
-				httpRFClient = nil
-				return nil
-			}
-		}
+		httpRFClient = newHTTPClientPair(rfHTTPOptions)
 	}
 	return httpRFClient
 }
@@ -22,8 +22,13 @@
 package rf
 
 import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
 	"testing"
 	"time"
+
+	"github.com/Cray-HPE/hms-certs/pkg/hms_certs"
 )
 
 func TestSetHTTPClientTimeout(t *testing.T) {
@@ -62,6 +67,62 @@ func TestRfDefaultClient(t *testing.T) {
 	}
 }
 
+func TestHTTPOptions(t *testing.T) {
+	defer func(o HTTPOptions) {
+		SetHTTPOptions(o)
+		httpRFClient = nil
+	}(GetHTTPOptions())
+	httpRFClient = nil
+	SetHTTPClientTimeout(30)
+	SetHTTPOptions(HTTPOptions{MaxIdleConnsPerHost: 4, TLSHandshakeTimeout: 10})
+	SetHTTPOptions(HTTPOptions{MaxConnsPerHost: -1})
+	if o := GetHTTPOptions(); o.MaxIdleConnsPerHost != 4 || o.MaxConnsPerHost != 0 {
+		t.Errorf("Test 1: FAIL: Bad options accepted: %+v", o)
+	}
+
+	check := func(name string, cp *hms_certs.HTTPClientPair, timeout, handshake, idlePerHost int) {
+		if cp == nil {
+			t.Fatalf("%s: FAIL: No client", name)
+		}
+		c := cp.InsecureClient.HTTPClient
+		if c.Timeout != time.Duration(timeout)*time.Second {
+			t.Errorf("%s: FAIL: Expected timeout %ds, got %s", name, timeout, c.Timeout)
+		}
+		tr := c.Transport.(*http.Transport)
+		if tr.TLSHandshakeTimeout != time.Duration(handshake)*time.Second ||
+			tr.MaxIdleConnsPerHost != idlePerHost {
+			t.Errorf("%s: FAIL: Expected handshake %ds, %d idle per host, "+
+				"got %s %d", name, handshake, idlePerHost,
+				tr.TLSHandshakeTimeout, tr.MaxIdleConnsPerHost)
+		}
+	}
+	check("Test 2", RfDefaultClient(), 30, 10, 4)
+
+	// Overrides get their own client, shared with others with the same
+	slow := &HTTPOptions{Timeout: 120, MaxIdleConnsPerHost: 16}
+	cp := RfHTTPOptionsClient(slow)
+	check("Test 3", cp, 120, 10, 16)
+	if cp == RfDefaultClient() || RfHTTPOptionsClient(&HTTPOptions{Timeout: 120, MaxIdleConnsPerHost: 16}) != cp {
+		t.Errorf("Test 3: FAIL: Clients not shared by options")
+	}
+	if RfHTTPOptionsClient(nil) != RfDefaultClient() ||
+		RfHTTPOptionsClient(&HTTPOptions{MaxIdleConnsPerHost: 4}) != RfDefaultClient() {
+		t.Errorf("Test 4: FAIL: Default options didn't get default client")
+	}
+
+	// Applied from the vendor profile
+	ep, _ := NewRedfishEp(&RedfishEPDescription{
+		ID: "x0c0s0b0", Type: "NodeBMC", FQDN: "x0c0s0b0", Enabled: true})
+	ep.SetVendorProfile(&VendorProfile{Name: "slow", HTTPOptions: slow})
+	if ep.client != cp {
+		t.Errorf("Test 5: FAIL: Profile HTTPOptions not used")
+	}
+	p := VendorProfile{Name: "bad", HTTPOptions: &HTTPOptions{Timeout: -5}}
+	if err := p.Verify(); err == nil {
+		t.Errorf("Test 6: FAIL: Bad profile HTTPOptions accepted")
+	}
+}
+
 /*
 func TestRfProxyClient(t *testing.T) {
 	SetHTTPClientTimeout(66)
@@ -78,3 +139,74 @@ func TestRfProxyClient(t *testing.T) {
 	}
 }
 */
+
+func TestRetryPolicy(t *testing.T) {
+	defer SetRetryPolicy(DefaultRetryPolicy)
+
+	p := RetryPolicy{Retries: 5, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	expected := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second,
+		5 * time.Second, 5 * time.Second,
+	}
+	for i, exp := range expected {
+		if wait := p.Wait(i); wait != exp {
+			t.Errorf("Test %d: FAIL: Expected wait %s, got %s", i, exp, wait)
+		}
+	}
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if wait := p.Wait(1); wait < time.Second || wait > 3*time.Second {
+			t.Errorf("Test jitter: FAIL: wait %s out of range", wait)
+		}
+	}
+	if wait := (RetryPolicy{Retries: 3}).Wait(2); wait != 0 {
+		t.Errorf("Test no backoff: FAIL: Expected no wait, got %s", wait)
+	}
+
+	SetRetryPolicy(RetryPolicy{Retries: 1, Jitter: 2})
+	if GetRetryPolicy() != DefaultRetryPolicy {
+		t.Errorf("Test bad policy: FAIL: policy was changed")
+	}
+}
+
+func TestGETRelativeRetry(t *testing.T) {
+	defer SetRetryPolicy(DefaultRetryPolicy)
+	SetRetryPolicy(RetryPolicy{Retries: 2})
+
+	tests := []struct {
+		statuses []int // returned in turn, the last one repeated
+		expErr   bool
+		expTries int
+	}{
+		{[]int{200}, false, 1},
+		{[]int{503, 502, 200}, false, 3},
+		{[]int{503}, true, 3},
+		{[]int{404}, true, 1},
+		{[]int{401}, true, 1},
+	}
+	for i, test := range tests {
+		tries := 0
+		ep := &RedfishEP{client: NewTestClient(func(req *http.Request) *http.Response {
+			status := test.statuses[len(test.statuses)-1]
+			if tries < len(test.statuses) {
+				status = test.statuses[tries]
+			}
+			tries++
+			return &http.Response{
+				StatusCode: status,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				Header:     make(http.Header),
+			}
+		})}
+		ep.FQDN = "x0c0s0b0"
+		_, err := ep.GETRelative("/redfish/v1")
+		if (err != nil) != test.expErr {
+			t.Errorf("Test %d: FAIL: Expected error %t, got %v",
+				i, test.expErr, err)
+		}
+		if tries != test.expTries {
+			t.Errorf("Test %d: FAIL: Expected %d tries, got %d",
+				i, test.expTries, tries)
+		}
+	}
+}
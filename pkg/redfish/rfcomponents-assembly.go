@@ -99,6 +99,7 @@ func (a *EpAssembly) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			a.LastStatus = EPResponseFailedDecode
+			a.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
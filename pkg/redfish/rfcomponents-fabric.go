@@ -0,0 +1,556 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+// Fabrics - HSN ASICs
+/////////////////////////////////////////////////////////////////////////////
+
+// Set of EpHSNAsic, each representing a switch ASIC on a router blade.
+// These come from /redfish/v1/Fabrics/<id>/Switches or, if the controller
+// doesn't list any there, from the router chassis' FabricAdapters.
+type EpHSNAsics struct {
+	Num  int                   `json:"num"`
+	OIDs map[string]*EpHSNAsic `json:"oids"`
+}
+
+// This is one of possibly several HSN ASICs under a RouterBMC.
+type EpHSNAsic struct {
+	// Embedded struct: id, type, odataID and associated RfEndpointID.
+	ComponentDescription
+
+	BaseOdataID string `json:"BaseOdataID"`
+
+	// Embedded struct - Locational/FRU, state, and status info
+	InventoryData
+
+	HSNAsicURL string `json:"hsnAsicURL"` // Full URL to this RF Switch obj
+	ParentOID  string `json:"parentOID"`  // odata.id for parent
+	ParentType string `json:"parentType"` // Fabric or Chassis
+	LastStatus string `json:"LastStatus"`
+
+	HSNAsicRF  FabricSwitch `json:"HSNAsicRF"`
+	hsnAsicRaw *json.RawMessage
+
+	Ports EpHSNPorts `json:"Ports"`
+
+	epRF *RedfishEP // Backpointer to RF EP, for connection details, etc.
+}
+
+// Initializes EpHSNAsic struct with minimal information needed to
+// discover it, i.e. endpoint info and the odataID of the Switch or
+// FabricAdapter to look at.  rfType should be SwitchType or
+// FabricAdapterType.
+func NewEpHSNAsic(ep *RedfishEP, pOID, pType, rfType string, odataID ResourceID, rawOrdinal int) *EpHSNAsic {
+	a := new(EpHSNAsic)
+	a.OdataID = odataID.Oid
+	a.Type = xnametypes.HSNAsic.String()
+	a.BaseOdataID = odataID.Basename()
+	a.RedfishType = rfType
+	a.RfEndpointID = ep.ID
+
+	a.HSNAsicURL = ep.FQDN + odataID.Oid
+	a.ParentOID = pOID
+	a.ParentType = pType
+
+	a.Ordinal = -1
+	a.RawOrdinal = rawOrdinal
+
+	a.LastStatus = NotYetQueried
+	a.epRF = ep
+
+	return a
+}
+
+// Makes contact with redfish endpoint to discover information about
+// all HSN ASICs for a given RouterBMC.  EpHSNAsic entries should be
+// created with the appropriate constructor first.
+func (as *EpHSNAsics) discoverRemotePhase1() {
+	for _, a := range as.OIDs {
+		a.discoverRemotePhase1()
+	}
+}
+
+// Makes contact with redfish endpoint to discover information about
+// a particular HSN ASIC and its Ports.  Note that the EpHSNAsic should
+// be created with the appropriate constructor first.
+func (a *EpHSNAsic) discoverRemotePhase1() {
+	rpath := a.OdataID
+	url := a.epRF.FQDN + rpath
+	urlJSON, err := a.epRF.GETRelative(rpath)
+	if err != nil || urlJSON == nil {
+		if err == ErrRFDiscURLNotFound {
+			errlog.Printf("%s: Redfish bug! Link %s was dead (404).  "+
+				"Will try to continue.  No component will be created.",
+				a.epRF.ID, rpath)
+			a.LastStatus = RedfishSubtypeNoSupport
+			a.RedfishSubtype = RFSubtypeUnknown
+		} else {
+			a.LastStatus = HTTPsGetFailed
+		}
+		return
+	}
+	a.hsnAsicRaw = &urlJSON
+	a.LastStatus = HTTPsGetOk
+
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", url, urlJSON)
+	}
+	if err := json.Unmarshal(urlJSON, &a.HSNAsicRF); err != nil {
+		if IsUnmarshalTypeError(err) {
+			errlog.Printf("bad field(s) skipped: %s: %s\n", url, err)
+		} else {
+			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
+			a.LastStatus = EPResponseFailedDecode
+			a.epRF.addDiscoveryError(url, 0, err)
+			return
+		}
+	}
+	a.RedfishSubtype = a.HSNAsicRF.SwitchType
+	if a.RedfishSubtype == "" {
+		a.RedfishSubtype = a.RedfishType
+	}
+
+	// Get the Ports under this ASIC.  An ASIC without them is still
+	// worth reporting, so don't fail if they can't be retrieved.
+	a.Ports.Num = 0
+	a.Ports.OIDs = make(map[string]*EpHSNPort)
+	if a.HSNAsicRF.Ports.Oid != "" {
+		ppath := a.HSNAsicRF.Ports.Oid
+		portsJSON, err := a.epRF.GETRelative(ppath)
+		if err != nil || portsJSON == nil {
+			errlog.Printf("%s: Ports GET failed, skipping: %v\n",
+				a.epRF.FQDN+ppath, err)
+		} else {
+			if rfDebug > 0 {
+				errlog.Printf("%s: %s\n", a.epRF.FQDN+ppath, portsJSON)
+			}
+			var portInfo PortCollection
+			if err := json.Unmarshal(portsJSON, &portInfo); err != nil {
+				errlog.Printf("Failed to decode %s: %s\n",
+					a.epRF.FQDN+ppath, err)
+			} else {
+				sort.Sort(ResourceIDSlice(portInfo.Members))
+				for i, pOID := range portInfo.Members {
+					a.Ports.OIDs[pOID.Basename()] = NewEpHSNPort(a, pOID, i)
+				}
+				a.Ports.Num = len(a.Ports.OIDs)
+				a.Ports.discoverRemotePhase1()
+			}
+		}
+	}
+
+	if rfVerbose > 0 {
+		jout, _ := json.MarshalIndent(a, "", "   ")
+		errlog.Printf("%s: %s\n", url, jout)
+	}
+	a.LastStatus = VerifyingData
+}
+
+// This is the second discovery phase, after all information from
+// the parent endpoint has been gathered.
+func (as *EpHSNAsics) discoverLocalPhase2() error {
+	var savedError error
+	for i, a := range as.OIDs {
+		a.discoverLocalPhase2()
+		if a.LastStatus == RedfishSubtypeNoSupport {
+			errlog.Printf("Key %s: RF HSN ASIC type not supported: %s",
+				i, a.RedfishSubtype)
+		} else if a.LastStatus != DiscoverOK {
+			err := fmt.Errorf("Key %s: %s", i, a.LastStatus)
+			errlog.Printf("HSNAsics discoverLocalPhase2: saw error: %s", err)
+			savedError = err
+		}
+	}
+	return savedError
+}
+
+// Phase2 discovery for an individual HSN ASIC.  Now that all information
+// has been gathered, we can set the remaining fields needed to provide
+// HMS with information about where the ASIC is located.  Its Ports are
+// done here too since their xnames are based on the ASIC's.
+func (a *EpHSNAsic) discoverLocalPhase2() {
+	// Should never happen
+	if a.epRF == nil {
+		errlog.Printf("Error: RedfishEP == nil for odataID: %s\n",
+			a.OdataID)
+		a.LastStatus = EndpointInvalid
+		return
+	}
+	if a.LastStatus != VerifyingData {
+		return
+	}
+	a.Ordinal = a.epRF.getHSNAsicOrdinal(a)
+	a.ID = a.epRF.getHSNAsicHMSID(a, a.Ordinal)
+	a.discoverComponentState()
+
+	// Check if we have something valid to insert into the data store
+	if xnametypes.GetHMSType(a.ID) != xnametypes.HSNAsic ||
+		a.Type != xnametypes.HSNAsic.String() {
+		errlog.Printf("Error: Bad xname ID ('%s') or Type ('%s') for: %s\n",
+			a.ID, a.Type, a.HSNAsicURL)
+		a.LastStatus = VerificationFailed
+		return
+	}
+	if err := a.Ports.discoverLocalPhase2(); err != nil {
+		errlog.Printf("HSN ASIC %s: Ports verification failed: %s",
+			a.ID, err)
+	}
+	if rfVerbose > 0 {
+		jout, _ := json.MarshalIndent(a, "", "   ")
+		errlog.Printf("%s\n", jout)
+		errlog.Printf("HSN ASIC ID: %s\n", a.ID)
+		errlog.Printf("HSN ASIC FRUID: %s\n", a.FRUID)
+	}
+	a.LastStatus = DiscoverOK
+}
+
+// Sets up HMS state fields for HSN ASICs using Status/State/Health info
+// from Redfish
+func (a *EpHSNAsic) discoverComponentState() {
+	if a.HSNAsicRF.Status.State == "Absent" {
+		a.Status = "Empty"
+		a.State = base.StateEmpty.String()
+		a.Flag = base.FlagOK.String()
+		return
+	}
+	a.Status = "Populated"
+	a.State = getFabricStatusState(a.HSNAsicRF.Status)
+	a.Flag = getFabricStatusFlag(a.HSNAsicRF.Status)
+	generatedFRUID, err := GetHSNAsicFRUID(a)
+	if err != nil {
+		errlog.Printf("FRUID Error: %s\n", err.Error())
+		errlog.Printf("Using untrackable FRUID: %s\n", generatedFRUID)
+	}
+	a.FRUID = generatedFRUID
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Fabrics - HSN ASIC Ports
+/////////////////////////////////////////////////////////////////////////////
+
+// Set of EpHSNPort, each representing a Redfish "Port" under an HSN ASIC.
+type EpHSNPorts struct {
+	Num  int                   `json:"num"`
+	OIDs map[string]*EpHSNPort `json:"oids"`
+}
+
+// This is one of possibly several Ports on an HSN ASIC.
+type EpHSNPort struct {
+	// Embedded struct: id, type, odataID and associated RfEndpointID.
+	ComponentDescription
+
+	BaseOdataID string `json:"BaseOdataID"`
+
+	// Embedded struct - Locational/FRU, state, and status info
+	InventoryData
+
+	PortURL    string `json:"portURL"`    // Full URL to this RF Port obj
+	ParentOID  string `json:"parentOID"`  // odata.id for parent
+	ParentType string `json:"parentType"` // Switch or FabricAdapter
+	LastStatus string `json:"LastStatus"`
+
+	PortRF  Port `json:"PortRF"`
+	portRaw *json.RawMessage
+
+	epRF   *RedfishEP // Backpointer to RF EP, for connection details, etc.
+	asicRF *EpHSNAsic // Backpointer to parent ASIC
+}
+
+// Initializes EpHSNPort struct with minimal information needed to
+// discover it, i.e. endpoint info and the odataID of the Port to look at.
+func NewEpHSNPort(a *EpHSNAsic, odataID ResourceID, rawOrdinal int) *EpHSNPort {
+	p := new(EpHSNPort)
+	p.OdataID = odataID.Oid
+	p.Type = xnametypes.HSNLink.String()
+	p.BaseOdataID = odataID.Basename()
+	p.RedfishType = PortType
+	p.RfEndpointID = a.epRF.ID
+
+	p.PortURL = a.epRF.FQDN + odataID.Oid
+	p.ParentOID = a.OdataID
+	p.ParentType = a.RedfishType
+
+	p.Ordinal = -1
+	p.RawOrdinal = rawOrdinal
+
+	p.LastStatus = NotYetQueried
+	p.epRF = a.epRF
+	p.asicRF = a
+
+	return p
+}
+
+// Makes contact with redfish endpoint to discover information about
+// all Ports for a given HSN ASIC.
+func (ps *EpHSNPorts) discoverRemotePhase1() {
+	for _, p := range ps.OIDs {
+		p.discoverRemotePhase1()
+	}
+}
+
+// Makes contact with redfish endpoint to discover information about
+// a particular Port on an HSN ASIC.
+func (p *EpHSNPort) discoverRemotePhase1() {
+	rpath := p.OdataID
+	url := p.epRF.FQDN + rpath
+	urlJSON, err := p.epRF.GETRelative(rpath)
+	if err != nil || urlJSON == nil {
+		if err == ErrRFDiscURLNotFound {
+			errlog.Printf("%s: Redfish bug! Link %s was dead (404).  "+
+				"Will try to continue.  No component will be created.",
+				p.epRF.ID, rpath)
+			p.LastStatus = RedfishSubtypeNoSupport
+			p.RedfishSubtype = RFSubtypeUnknown
+		} else {
+			p.LastStatus = HTTPsGetFailed
+		}
+		return
+	}
+	p.portRaw = &urlJSON
+	p.LastStatus = HTTPsGetOk
+
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", url, urlJSON)
+	}
+	if err := json.Unmarshal(urlJSON, &p.PortRF); err != nil {
+		if IsUnmarshalTypeError(err) {
+			errlog.Printf("bad field(s) skipped: %s: %s\n", url, err)
+		} else {
+			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
+			p.LastStatus = EPResponseFailedDecode
+			p.epRF.addDiscoveryError(url, 0, err)
+			return
+		}
+	}
+	p.RedfishSubtype = p.PortRF.PortProtocol
+
+	if rfVerbose > 0 {
+		jout, _ := json.MarshalIndent(p, "", "   ")
+		errlog.Printf("%s: %s\n", url, jout)
+	}
+	p.LastStatus = VerifyingData
+}
+
+// This is the second discovery phase, after all information from
+// the parent ASIC has been gathered.
+func (ps *EpHSNPorts) discoverLocalPhase2() error {
+	var savedError error
+	for i, p := range ps.OIDs {
+		p.discoverLocalPhase2()
+		if p.LastStatus == RedfishSubtypeNoSupport {
+			errlog.Printf("Key %s: RF Port type not supported: %s",
+				i, p.RedfishSubtype)
+		} else if p.LastStatus != DiscoverOK {
+			err := fmt.Errorf("Key %s: %s", i, p.LastStatus)
+			errlog.Printf("HSNPorts discoverLocalPhase2: saw error: %s", err)
+			savedError = err
+		}
+	}
+	return savedError
+}
+
+// Phase2 discovery for an individual Port.  The parent ASIC must have
+// already been given its xname.
+func (p *EpHSNPort) discoverLocalPhase2() {
+	// Should never happen
+	if p.epRF == nil || p.asicRF == nil {
+		errlog.Printf("Error: RedfishEP or parent == nil for odataID: %s\n",
+			p.OdataID)
+		p.LastStatus = EndpointInvalid
+		return
+	}
+	if p.LastStatus != VerifyingData {
+		return
+	}
+	p.Ordinal = p.epRF.getHSNPortOrdinal(p)
+	p.ID = p.asicRF.ID + "l" + strconv.Itoa(p.Ordinal)
+	p.discoverComponentState()
+
+	// Check if we have something valid to insert into the data store
+	if xnametypes.GetHMSType(p.ID) != xnametypes.HSNLink ||
+		p.Type != xnametypes.HSNLink.String() {
+		errlog.Printf("Error: Bad xname ID ('%s') or Type ('%s') for: %s\n",
+			p.ID, p.Type, p.PortURL)
+		p.LastStatus = VerificationFailed
+		return
+	}
+	if rfVerbose > 0 {
+		jout, _ := json.MarshalIndent(p, "", "   ")
+		errlog.Printf("%s\n", jout)
+		errlog.Printf("HSN Port ID: %s\n", p.ID)
+	}
+	p.LastStatus = DiscoverOK
+}
+
+// Sets up HMS state fields for Ports.  The link status is what matters
+// here: a port is On if its link is up, Off if it is down.
+func (p *EpHSNPort) discoverComponentState() {
+	if p.PortRF.Status.State == "Absent" {
+		p.Status = "Empty"
+		p.State = base.StateEmpty.String()
+		p.Flag = base.FlagOK.String()
+		return
+	}
+	p.Status = "Populated"
+	switch p.PortRF.LinkStatus {
+	case "LinkUp":
+		p.State = base.StateOn.String()
+	case "LinkDown", "NoLink", "Starting", "Training":
+		p.State = base.StateOff.String()
+	default:
+		if p.PortRF.LinkState == "Disabled" {
+			p.State = base.StateOff.String()
+		} else {
+			p.State = getFabricStatusState(p.PortRF.Status)
+		}
+	}
+	p.Flag = getFabricStatusFlag(p.PortRF.Status)
+	// Ports aren't FRUs; they go wherever their ASIC goes.
+	if p.asicRF.FRUID != "" {
+		p.FRUID = p.Type + "." + strconv.Itoa(p.Ordinal) + "." + p.asicRF.FRUID
+	} else {
+		p.FRUID = "FRUIDfor" + p.ID
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Fabrics - discovery from the RedfishEP
+/////////////////////////////////////////////////////////////////////////////
+
+// Find the HSN ASICs under a RouterBMC, first by crawling the Fabrics
+// collection for Switches and, if none are found there, via the
+// FabricAdapters of the endpoint's Chassis.  Failures are logged, but
+// are not fatal to the discovery of the rest of the endpoint.
+func (ep *RedfishEP) discoverHSNAsicsPhase1() {
+	ep.HSNAsics.Num = 0
+	ep.HSNAsics.OIDs = make(map[string]*EpHSNAsic)
+
+	if ep.ServiceRootRF.Fabrics.Oid != "" {
+		var fabrics FabricCollection
+		if ep.getFabricObject(ep.ServiceRootRF.Fabrics.Oid, &fabrics) {
+			sort.Sort(ResourceIDSlice(fabrics.Members))
+			for _, fOID := range fabrics.Members {
+				var fabric Fabric
+				if !ep.getFabricObject(fOID.Oid, &fabric) ||
+					fabric.Switches.Oid == "" {
+					continue
+				}
+				var switches SwitchCollection
+				if !ep.getFabricObject(fabric.Switches.Oid, &switches) {
+					continue
+				}
+				ep.addHSNAsics(fOID.Oid, FabricType, SwitchType, switches.Members)
+			}
+		}
+	}
+	if len(ep.HSNAsics.OIDs) == 0 {
+		chassisIDs := make([]string, 0, len(ep.Chassis.OIDs))
+		for id := range ep.Chassis.OIDs {
+			chassisIDs = append(chassisIDs, id)
+		}
+		sort.Strings(chassisIDs)
+		for _, id := range chassisIDs {
+			c := ep.Chassis.OIDs[id]
+			if c.ChassisRF.FabricAdapters.Oid == "" {
+				continue
+			}
+			var adapters FabricAdapterCollection
+			if !ep.getFabricObject(c.ChassisRF.FabricAdapters.Oid, &adapters) {
+				continue
+			}
+			ep.addHSNAsics(c.OdataID, ChassisType, FabricAdapterType, adapters.Members)
+		}
+	}
+	ep.HSNAsics.Num = len(ep.HSNAsics.OIDs)
+	ep.HSNAsics.discoverRemotePhase1()
+}
+
+// Add an EpHSNAsic for each member.  Raw ordinals continue on from any
+// ASICs already found so they stay unique across multiple Fabrics.
+func (ep *RedfishEP) addHSNAsics(pOID, pType, rfType string, members []ResourceID) {
+	sort.Sort(ResourceIDSlice(members))
+	for _, aOID := range members {
+		if _, ok := ep.HSNAsics.OIDs[aOID.Oid]; ok {
+			continue
+		}
+		ep.HSNAsics.OIDs[aOID.Oid] = NewEpHSNAsic(ep, pOID, pType, rfType,
+			aOID, len(ep.HSNAsics.OIDs))
+	}
+}
+
+// GET and decode one of the Fabric-related objects into out.  Returns
+// false, after logging, if this couldn't be done.
+func (ep *RedfishEP) getFabricObject(rpath string, out interface{}) bool {
+	url := ep.FQDN + rpath
+	urlJSON, err := ep.GETRelative(rpath)
+	if err != nil || urlJSON == nil {
+		errlog.Printf("%s: GET failed, skipping: %v\n", url, err)
+		return false
+	}
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", url, urlJSON)
+	}
+	if err := json.Unmarshal(urlJSON, out); err != nil {
+		if IsUnmarshalTypeError(err) {
+			errlog.Printf("bad field(s) skipped: %s: %s\n", url, err)
+		} else {
+			errlog.Printf("Failed to decode %s: %s\n", url, err)
+			return false
+		}
+	}
+	return true
+}
+
+// Map Redfish Status to an HMS state for Fabric components that don't
+// have a PowerState.
+func getFabricStatusState(st StatusRF) string {
+	switch st.State {
+	case "Enabled":
+		return base.StateOn.String()
+	case "Disabled", "StandbyOffline", "UnavailableOffline":
+		return base.StateOff.String()
+	}
+	return base.StatePopulated.String()
+}
+
+// Map Redfish Status Health to an HMS flag.
+func getFabricStatusFlag(st StatusRF) string {
+	switch st.Health {
+	case "Warning":
+		return base.FlagWarning.String()
+	case "Critical":
+		return base.FlagAlert.String()
+	}
+	return base.FlagOK.String()
+}
@@ -130,6 +130,7 @@ func (d *EpHpeDevice) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			d.LastStatus = EPResponseFailedDecode
+			d.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -194,6 +195,15 @@ func (d *EpHpeDevice) discoverLocalPhase2() {
 	if strings.ToLower(d.RedfishSubtype) == "gpu" &&
 	   !strings.Contains(strings.ToLower(d.DeviceRF.Name), "switch") &&
 	   !strings.Contains(strings.ToLower(d.DeviceRF.Location), "baseboard") {
+		// Some BMCs list the same GPU under both the System's Processors
+		// and the Chassis Devices. The Processor entry wins.
+		if p := d.getDuplicateAccelProcessor(); p != nil {
+			errlog.Printf("HPE Device %s duplicates GPU processor %s, skipping\n",
+				d.DeviceURL, p.ProcessorURL)
+			d.Type = xnametypes.HMSTypeInvalid.String()
+			d.LastStatus = RedfishSubtypeNoSupport
+			return
+		}
 		d.ID = d.systemRF.ID + "a" + strconv.Itoa(d.Ordinal)
 		d.Type = xnametypes.NodeAccel.String()
 	} else if strings.Contains(strings.ToLower(d.RedfishSubtype), "nic") &&
@@ -277,9 +287,41 @@ func (ep *RedfishEP) getHpeDeviceOrdinal(d *EpHpeDevice) int {
 			}
 		}
 	}
+	// GPUs may also be listed as Processors with ProcessorType GPU or
+	// Accelerator. Those get the first NodeAccel ordinals so number the
+	// HPE device GPUs after them.
+	if strings.ToLower(d.RedfishSubtype) == "gpu" {
+		for _, p := range d.systemRF.Processors.OIDs {
+			if IsAccelProcessorType(p.ProcessorRF.ProcessorType) {
+				ordinal++
+			}
+		}
+		for _, device := range d.systemRF.HpeDevices.OIDs {
+			if device.BaseOdataID < d.BaseOdataID &&
+				strings.ToLower(device.RedfishSubtype) == "gpu" &&
+				device.getDuplicateAccelProcessor() != nil {
+				ordinal--
+			}
+		}
+	}
 	return ordinal
 }
 
+// Returns the GPU/Accelerator processor under the same system that has the
+// same serial number as this HPE device, or nil if there is none.
+func (d *EpHpeDevice) getDuplicateAccelProcessor() *EpProcessor {
+	if d.DeviceRF.SerialNumber == "" {
+		return nil
+	}
+	for _, p := range d.systemRF.Processors.OIDs {
+		if IsAccelProcessorType(p.ProcessorRF.ProcessorType) &&
+			p.ProcessorRF.SerialNumber == d.DeviceRF.SerialNumber {
+			return p
+		}
+	}
+	return nil
+}
+
 // Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
 // else return an error.
 func GetHpeDeviceFRUID(d *EpHpeDevice) (fruid string, err error) {
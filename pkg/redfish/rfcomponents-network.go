@@ -25,6 +25,7 @@ package rf
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
@@ -60,6 +61,9 @@ type EpNetworkAdapter struct {
 	NetworkAdapterRF  *NetworkAdapter `json:"NetworkAdapterRF"`
 	networkAdapterRaw *json.RawMessage
 
+	// Functions with Ethernet MACs, which may not show up anywhere else.
+	NetDevFuncsRF []*NetworkDeviceFunction `json:"NetworkDeviceFunctionsRF,omitempty"`
+
 	epRF     *RedfishEP // Backpointer to RF EP, for connection details, etc.
 	systemRF *EpSystem  // Backpointer to the associated system.
 }
@@ -125,11 +129,15 @@ func (na *EpNetworkAdapter) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			na.LastStatus = EPResponseFailedDecode
+			na.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
 	na.RedfishSubtype = NetworkAdapterType
 
+	// Failing to get these doesn't affect the adapter itself.
+	na.discoverNetworkDeviceFunctions()
+
 	if rfVerbose > 0 {
 		jout, _ := json.MarshalIndent(na, "", "   ")
 		errlog.Printf("%s: %s\n", url, jout)
@@ -138,6 +146,54 @@ func (na *EpNetworkAdapter) discoverRemotePhase1() {
 	na.LastStatus = VerifyingData
 }
 
+// Get the NetworkDeviceFunctions for the NetworkAdapter so their MAC
+// addresses can be reported with the System's EthernetInterfaces.  Errors
+// are logged and the offending function skipped.
+func (na *EpNetworkAdapter) discoverNetworkDeviceFunctions() {
+	na.NetDevFuncsRF = make([]*NetworkDeviceFunction, 0, 1)
+	if na.NetworkAdapterRF == nil || na.NetworkAdapterRF.NetworkDeviceFunctions.Oid == "" {
+		return
+	}
+	rpath := na.NetworkAdapterRF.NetworkDeviceFunctions.Oid
+	url := na.epRF.FQDN + rpath
+	ndfsJSON, err := na.epRF.GETRelative(rpath)
+	if err != nil || ndfsJSON == nil {
+		errlog.Printf("%s: NetworkDeviceFunctions GET failed, skipping: %v\n",
+			url, err)
+		return
+	}
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", url, ndfsJSON)
+	}
+	var ndfInfo NetworkDeviceFunctionCollection
+	if err := json.Unmarshal(ndfsJSON, &ndfInfo); err != nil {
+		errlog.Printf("Failed to decode %s: %s\n", url, err)
+		return
+	}
+	sort.Sort(ResourceIDSlice(ndfInfo.Members))
+	for _, ndfOID := range ndfInfo.Members {
+		ndfURL := na.epRF.FQDN + ndfOID.Oid
+		ndfJSON, err := na.epRF.GETRelative(ndfOID.Oid)
+		if err != nil || ndfJSON == nil {
+			errlog.Printf("%s: GET failed, skipping: %v\n", ndfURL, err)
+			continue
+		}
+		if rfDebug > 0 {
+			errlog.Printf("%s: %s\n", ndfURL, ndfJSON)
+		}
+		ndf := new(NetworkDeviceFunction)
+		if err := json.Unmarshal(ndfJSON, ndf); err != nil {
+			if IsUnmarshalTypeError(err) {
+				errlog.Printf("bad field(s) skipped: %s: %s\n", ndfURL, err)
+			} else {
+				errlog.Printf("ERROR: json decode failed: %s: %s\n", ndfURL, err)
+				continue
+			}
+		}
+		na.NetDevFuncsRF = append(na.NetDevFuncsRF, ndf)
+	}
+}
+
 // This is the second discovery phase, after all information from
 // the parent system has been gathered.  This is not intended to
 // be run as a separate step; it is separate because certain discovery
@@ -64,6 +64,9 @@ type EpPDU struct {
 	Outlets EpOutlets `json:"outlets"`
 	//Circuits EpCircuits `json:"circuits"`
 
+	// Vendor workarounds for this PDU and its Outlets, see Quirks()
+	quirks *QuirkSet
+
 	epRF *RedfishEP // Backpointer, for connection details, etc.
 }
 
@@ -133,6 +136,7 @@ func (pdu *EpPDU) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			pdu.LastStatus = EPResponseFailedDecode
+			pdu.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -167,6 +171,7 @@ func (pdu *EpPDU) discoverRemotePhase1() {
 		if err := json.Unmarshal(outsJSON, &outInfo); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			pdu.LastStatus = EPResponseFailedDecode
+			pdu.epRF.addDiscoveryError(url, 0, err)
 		}
 
 		// HPE PDUs use Outlets instead of Members, so copy to Members
@@ -187,6 +192,12 @@ func (pdu *EpPDU) discoverRemotePhase1() {
 		// Sort in lexical order, so the ordinal values will keep the same
 		// ordering.
 		sort.Sort(ResourceIDSlice(outInfo.Members))
+		if pdu.Quirks().Has(QuirkOutletNumericOrder) {
+			sort.SliceStable(outInfo.Members, func(i, j int) bool {
+				return outletIDLess(outInfo.Members[i].Basename(),
+					outInfo.Members[j].Basename())
+			})
+		}
 		for i, outOID := range outInfo.Members {
 			outID := outOID.Basename()
 			pdu.Outlets.OIDs[outID] = NewEpOutlet(pdu, outOID, i)
@@ -194,6 +205,13 @@ func (pdu *EpPDU) discoverRemotePhase1() {
 		pdu.Outlets.discoverRemotePhase1()
 	}
 
+	// Circuits and sensors only add metering info, so a PDU without them,
+	// or whose circuits can't be read, is still discovered.
+	pdu.Mains = pdu.discoverCircuits(pdu.PowerDistributionRF.Mains.Oid)
+	pdu.Branches = pdu.discoverCircuits(pdu.PowerDistributionRF.Branches.Oid)
+	pdu.SensorsURL = pdu.PowerDistributionRF.Sensors.Oid
+	pdu.MetricsURL = pdu.PowerDistributionRF.Metrics.Oid
+
 	if rfVerbose > 0 {
 		jout, _ := json.MarshalIndent(pdu, "", "   ")
 		errlog.Printf("%s: %s\n", topURL, jout)
@@ -402,18 +420,26 @@ func (out *EpOutlet) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			out.LastStatus = EPResponseFailedDecode
+			out.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
 	out.RedfishSubtype = out.OutletRF.OutletType
 	if out.OutletRF.Actions != nil {
 		out.Actions = out.OutletRF.Actions
+		// Raritan gives the PowerStates in an ActionInfo
+		if out.Actions.PowerControl != nil &&
+			out.Actions.PowerControl.RFActionInfo != "" {
+			out.getPowerControlActionInfo()
+		}
 		// HPE PDUs do not supply Allowable Values, so add them
 		if out.Actions.PowerControl != nil && len(out.Actions.PowerControl.AllowableValues) == 0 {
 			out.Actions.PowerControl.AllowableValues = append(out.Actions.PowerControl.AllowableValues, "On", "Off")
 		}
 	}
 	out.Name = out.OutletRF.Name
+	out.BranchCircuit = out.OutletRF.Links.BranchCircuit.Oid
+	out.Sensors = out.OutletRF.meteringSensors()
 	if rfVerbose > 0 {
 		jout, _ := json.MarshalIndent(out, "", "   ")
 		errlog.Printf("%s: %s\n", topURL, jout)
@@ -421,6 +447,60 @@ func (out *EpOutlet) discoverRemotePhase1() {
 	out.LastStatus = VerifyingData
 }
 
+// Get the PowerStates for the Outlet's PowerControl action from its
+// @Redfish.ActionInfo.  The inline AllowableValues, if any, are kept if the
+// ActionInfo can't be read.
+func (out *EpOutlet) getPowerControlActionInfo() {
+	pc := out.Actions.PowerControl
+	url := out.epRF.FQDN + pc.RFActionInfo
+	actionInfoJSON, err := out.epRF.GETRelative(pc.RFActionInfo)
+	if err != nil || actionInfoJSON == nil {
+		errlog.Printf("%s: Could not get %s, using inline PowerStates\n",
+			out.OutletURL, pc.RFActionInfo)
+		return
+	}
+	var actionInfo ResetActionInfo
+	if err := json.Unmarshal(actionInfoJSON, &actionInfo); err != nil {
+		errlog.Printf("Failed to decode %s: %s\n", url, err)
+		out.epRF.addDiscoveryError(url, 0, err)
+		return
+	}
+	for _, p := range actionInfo.RAParameters {
+		if p.Name == "PowerState" && len(p.AllowableValues) > 0 {
+			pc.AllowableValues = p.AllowableValues
+		}
+	}
+}
+
+// Order for Outlet Ids with QuirkOutletNumericOrder: by the part before
+// the trailing number, then by the number, e.g. AA2 < AA10 < AB1.  Ids
+// without a trailing number sort lexically before those with one.
+func outletIDLess(a, b string) bool {
+	aPrefix, aNum := splitOutletID(a)
+	bPrefix, bNum := splitOutletID(b)
+	if aPrefix != bPrefix {
+		return aPrefix < bPrefix
+	}
+	if aNum != bNum {
+		return aNum < bNum
+	}
+	return a < b
+}
+
+// Split an Outlet Id into the part before its trailing number and the
+// number, or -1 if there is none.
+func splitOutletID(id string) (string, int) {
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	num, err := strconv.Atoi(id[i:])
+	if err != nil {
+		return id, -1
+	}
+	return id[:i], num
+}
+
 // Outlets: This is the second discovery phase, after all information from
 // the parent endpoint has been gathered.  This is not really intended to
 // be run as a separate step; it is separate because certain discovery
@@ -522,6 +602,167 @@ func (out *EpOutlet) discoverComponentState() {
 	}
 }
 
+/////////////////////////////////////////////////////////////////////////////
+// Redfish Circuits - Mains and branch circuits of PDUs
+/////////////////////////////////////////////////////////////////////////////
+
+// Fetches the CircuitCollection at path, e.g. a PDU's Mains or Branches,
+// and each Circuit in it.  Circuits that can't be read are skipped.
+func (pdu *EpPDU) discoverCircuits(path string) []*CircuitInfo {
+	if path == "" {
+		return nil
+	}
+	url := pdu.epRF.FQDN + path
+	collJSON, err := pdu.epRF.GETRelative(path)
+	if err != nil || collJSON == nil {
+		return nil
+	}
+	var coll CircuitCollection
+	if err := json.Unmarshal(collJSON, &coll); err != nil {
+		errlog.Printf("Failed to decode %s: %s\n", url, err)
+		pdu.epRF.addDiscoveryError(url, 0, err)
+		return nil
+	}
+	sort.Sort(ResourceIDSlice(coll.Members))
+
+	circuits := make([]*CircuitInfo, 0, len(coll.Members))
+	for _, oid := range coll.Members {
+		url = pdu.epRF.FQDN + oid.Oid
+		cJSON, err := pdu.epRF.GETRelative(oid.Oid)
+		if err != nil || cJSON == nil {
+			continue
+		}
+		var c Circuit
+		if err := json.Unmarshal(cJSON, &c); err != nil {
+			if IsUnmarshalTypeError(err) {
+				errlog.Printf("bad field(s) skipped: %s: %s\n", url, err)
+			} else {
+				errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
+				pdu.epRF.addDiscoveryError(url, 0, err)
+				continue
+			}
+		}
+		info := &CircuitInfo{
+			RedfishId:        c.Id,
+			Oid:              oid.Oid,
+			Name:             c.Name,
+			CircuitType:      c.CircuitType,
+			PhaseWiringType:  c.PhaseWiringType,
+			NominalVoltage:   c.NominalVoltage,
+			RatedCurrentAmps: c.RatedCurrentAmps,
+			Sensors:          c.meteringSensors(),
+		}
+		if info.RedfishId == "" {
+			info.RedfishId = oid.Basename()
+		}
+		// Older payloads put Outlets at the top level instead of in Links
+		outlets := c.Links.Outlets
+		if len(outlets) == 0 {
+			outlets = c.Outlets
+		}
+		for _, out := range outlets {
+			info.Outlets = append(info.Outlets, out.Oid)
+		}
+		circuits = append(circuits, info)
+	}
+	return circuits
+}
+
+// Sensor excerpts of an outlet or circuit, as references to the Sensor
+// resources holding the readings.
+func (out *Outlet) meteringSensors() []*MeteringSensor {
+	var ms meteringSensorList
+	ms.addExcerpt("CurrentSensor", out.CurrentSensor)
+	ms.addExcerpt("EnergySensor", out.EnergySensor)
+	ms.addExcerpt("FrequencySensor", out.FrequencySensor)
+	ms.addPowerExcerpt("PowerSensor", out.PowerSensor)
+	ms.addExcerpt("TemperatureSensor", out.TemperatureSensor)
+	ms.addExcerpt("VoltageSensor", out.VoltageSensor)
+	ms.addPolyPhase(out.PolyPhaseCurrentSensors, out.PolyPhaseEnergySensors,
+		out.PolyPhasePowerSensors, out.PolyPhaseVoltageSensors)
+	return ms
+}
+
+func (c *Circuit) meteringSensors() []*MeteringSensor {
+	var ms meteringSensorList
+	ms.addExcerpt("CurrentSensor", c.CurrentSensor)
+	ms.addExcerpt("EnergySensor", c.EnergySensor)
+	ms.addExcerpt("FrequencySensor", c.FrequencySensor)
+	ms.addPowerExcerpt("PowerSensor", c.PowerSensor)
+	ms.addExcerpt("TemperatureSensor", c.TemperatureSensor)
+	ms.addExcerpt("VoltageSensor", c.VoltageSensor)
+	ms.addPolyPhase(c.PolyPhaseCurrentSensors, c.PolyPhaseEnergySensors,
+		c.PolyPhasePowerSensors, c.PolyPhaseVoltageSensors)
+	return ms
+}
+
+type meteringSensorList []*MeteringSensor
+
+// Excerpts without a DataSourceUri give nothing to read later, so are
+// skipped.
+func (ms *meteringSensorList) add(prop, uri, units string) {
+	if uri != "" {
+		*ms = append(*ms, &MeteringSensor{
+			Property:      prop,
+			DataSourceUri: uri,
+			ReadingUnits:  units,
+		})
+	}
+}
+
+func (ms *meteringSensorList) addExcerpt(prop string, s *SensorExcerpt) {
+	if s != nil {
+		ms.add(prop, s.DataSourceUri, s.ReadingUnits)
+	}
+}
+
+func (ms *meteringSensorList) addPowerExcerpt(prop string, s *SensorPowerExcerpt) {
+	if s != nil {
+		ms.add(prop, s.DataSourceUri, s.ReadingUnits)
+	}
+}
+
+func (ms *meteringSensorList) addPolyPhase(
+	cur *Currents,
+	energy *EnergyReadings,
+	power *PowerReadings,
+	volts *Voltages,
+) {
+	if cur != nil {
+		ms.addExcerpt("PolyPhaseCurrentSensors.Line1", cur.Line1)
+		ms.addExcerpt("PolyPhaseCurrentSensors.Line2", cur.Line2)
+		ms.addExcerpt("PolyPhaseCurrentSensors.Line3", cur.Line3)
+		ms.addExcerpt("PolyPhaseCurrentSensors.Neutral", cur.Neutral)
+	}
+	if energy != nil {
+		p := "PolyPhaseEnergySensors."
+		ms.addExcerpt(p+"Line1ToLine2", energy.Line1ToLine2)
+		ms.addExcerpt(p+"Line1ToNeutral", energy.Line1ToNeutral)
+		ms.addExcerpt(p+"Line2ToLine3", energy.Line2ToLine3)
+		ms.addExcerpt(p+"Line2ToNeutral", energy.Line2ToNeutral)
+		ms.addExcerpt(p+"Line3ToLine1", energy.Line3ToLine1)
+		ms.addExcerpt(p+"Line3ToNeutral", energy.Line3ToNeutral)
+	}
+	if power != nil {
+		p := "PolyPhasePowerSensors."
+		ms.addPowerExcerpt(p+"Line1ToLine2", power.Line1ToLine2)
+		ms.addPowerExcerpt(p+"Line1ToNeutral", power.Line1ToNeutral)
+		ms.addPowerExcerpt(p+"Line2ToLine3", power.Line2ToLine3)
+		ms.addPowerExcerpt(p+"Line2ToNeutral", power.Line2ToNeutral)
+		ms.addPowerExcerpt(p+"Line3ToLine1", power.Line3ToLine1)
+		ms.addPowerExcerpt(p+"Line3ToNeutral", power.Line3ToNeutral)
+	}
+	if volts != nil {
+		p := "PolyPhaseVoltageSensors."
+		ms.addExcerpt(p+"Line1ToLine2", volts.Line1ToLine2)
+		ms.addExcerpt(p+"Line1ToNeutral", volts.Line1ToNeutral)
+		ms.addExcerpt(p+"Line2ToLine3", volts.Line2ToLine3)
+		ms.addExcerpt(p+"Line2ToNeutral", volts.Line2ToNeutral)
+		ms.addExcerpt(p+"Line3ToLine1", volts.Line3ToLine1)
+		ms.addExcerpt(p+"Line3ToNeutral", volts.Line3ToNeutral)
+	}
+}
+
 //////////////////////////////////////////////////////////////////////////
 //
 // Power field discovery
@@ -693,6 +934,7 @@ func (p *EpPower) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			p.LastStatus = EPResponseFailedDecode
+			p.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
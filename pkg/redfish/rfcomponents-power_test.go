@@ -0,0 +1,291 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//
+// Mock ServerTech PRO3X and Raritan PX3 PDUs.  Only the RackPDU and its
+// Outlets differ from the RTS mock, which serves everything else.
+//
+
+const testPathPDU1 = "/redfish/v1/PowerEquipment/RackPDUs/1"
+
+func NewRTFuncVendorPDU(pdu string, outletIDs []string, outlet func(id string) string, extra map[string]string) RTFunc {
+	rts := NewRTFuncRtsPDU1()
+	members := make([]string, 0, len(outletIDs))
+	payloads := map[string]string{testPathPDU1: pdu}
+	for _, id := range outletIDs {
+		path := testPathPDU1 + "/Outlets/" + id
+		members = append(members, `{"@odata.id": "`+path+`"}`)
+		payloads[path] = outlet(id)
+	}
+	payloads[testPathPDU1+"/Outlets"] = `{
+        "@odata.id": "` + testPathPDU1 + `/Outlets",
+        "@odata.type": "#OutletCollection.OutletCollection",
+        "Members": [` + strings.Join(members, ",") + `],
+        "Members@odata.count": ` + fmt.Sprint(len(members)) + `,
+        "Name": "Outlet Collection"
+}`
+	for path, payload := range extra {
+		payloads[path] = payload
+	}
+	return func(req *http.Request) *http.Response {
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		if strings.HasPrefix(req.URL.Path, testPathPDU1+"/") {
+			return &http.Response{
+				StatusCode: 404,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				Header:     make(http.Header),
+			}
+		}
+		return rts(req)
+	}
+}
+
+// Tower A, infeed A, outlets 1-12
+var testServerTechOutletIDs = []string{
+	"AA1", "AA2", "AA3", "AA4", "AA5", "AA6",
+	"AA7", "AA8", "AA9", "AA10", "AA11", "AA12",
+}
+
+const testPayloadServerTechPDU = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1",
+        "@odata.type": "#PowerDistribution.v1_2_1.PowerDistribution",
+        "EquipmentType": "RackPDU",
+        "FirmwareVersion": "8.0p",
+        "Id": "1",
+        "Manufacturer": "Server Technology",
+        "Model": "C2WG36MS-DQME2M66",
+        "Name": "Master",
+        "Outlets": {
+                "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets"
+        },
+        "PartNumber": "C2WG36MS-DQME2M66",
+        "SerialNumber": "ABEF0001234",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+
+func testPayloadServerTechOutlet(id string) string {
+	return `{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/` + id + `",
+        "@odata.type": "#Outlet.v1_2_0.Outlet",
+        "Actions": {
+                "#Outlet.PowerControl": {
+                        "PowerState@Redfish.AllowableValues": ["On", "Off", "PowerCycle"],
+                        "target": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/` + id + `/Actions/Outlet.PowerControl"
+                }
+        },
+        "Id": "` + id + `",
+        "Name": "Master_Outlet_` + strings.TrimPrefix(id, "AA") + `",
+        "OutletType": "IEC_60320_C13",
+        "PowerState": "On",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+}
+
+var testRaritanOutletIDs = []string{
+	"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12",
+}
+
+const testPayloadRaritanPDU = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1",
+        "@odata.type": "#PowerDistribution.v1_0_0.PowerDistribution",
+        "EquipmentType": "RackPDU",
+        "FirmwareVersion": "4.0.20.5-48194",
+        "Id": "1",
+        "Manufacturer": "Legrand",
+        "Model": "PX3-5145R",
+        "Name": "PDU",
+        "Outlets": {
+                "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets"
+        },
+        "SerialNumber": "QIN7A00012",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+
+// Outlet 12 is switched off and its ActionInfo can't be read.
+func testPayloadRaritanOutlet(id string) string {
+	state := "On"
+	if id == "12" {
+		state = "Off"
+	}
+	return `{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/` + id + `",
+        "@odata.type": "#Outlet.v1_0_0.Outlet",
+        "Actions": {
+                "#Outlet.PowerControl": {
+                        "@Redfish.ActionInfo": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/` + id + `/PowerControlActionInfo",
+                        "target": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/` + id + `/Outlet.PowerControl"
+                }
+        },
+        "Id": "` + id + `",
+        "Name": "Outlet ` + id + `",
+        "OutletType": "IEC_60320_C13",
+        "PowerState": "` + state + `",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+}
+
+func testRaritanActionInfo() map[string]string {
+	infos := make(map[string]string)
+	for _, id := range testRaritanOutletIDs[:11] {
+		path := testPathPDU1 + "/Outlets/" + id + "/PowerControlActionInfo"
+		infos[path] = `{
+        "@odata.id": "` + path + `",
+        "@odata.type": "#ActionInfo.v1_1_2.ActionInfo",
+        "Id": "PowerControlActionInfo",
+        "Name": "Power Control Action Info",
+        "Parameters": [
+                {
+                        "AllowableValues": ["On", "Off", "PowerCycle"],
+                        "DataType": "String",
+                        "Name": "PowerState",
+                        "Required": true
+                }
+        ]
+}`
+	}
+	return infos
+}
+
+func TestVendorPDUDiscovery(t *testing.T) {
+	tests := []struct {
+		name      string
+		rt        RTFunc
+		outletIDs []string
+		expStates map[string][]string // By outlet Id, if not On/Off/PowerCycle
+		expOff    string
+	}{{
+		name: "ServerTech",
+		rt: NewRTFuncVendorPDU(testPayloadServerTechPDU, testServerTechOutletIDs,
+			testPayloadServerTechOutlet, nil),
+		outletIDs: testServerTechOutletIDs,
+	}, {
+		name: "Raritan",
+		rt: NewRTFuncVendorPDU(testPayloadRaritanPDU, testRaritanOutletIDs,
+			testPayloadRaritanOutlet, testRaritanActionInfo()),
+		outletIDs: testRaritanOutletIDs,
+		expStates: map[string][]string{"12": {"On", "Off"}},
+		expOff:    "12",
+	}}
+
+	for _, test := range tests {
+		ep := TestRedfishEPInitRtsCabPDUController
+		ep.client = NewTestClient(test.rt)
+		ep.GetRootInfo()
+		if ep.DiscInfo.LastStatus != DiscoverOK {
+			t.Errorf("%s: FAILED discovery, LastStatus: %s",
+				test.name, ep.DiscInfo.LastStatus)
+			continue
+		}
+		pdu, ok := ep.RackPDUs.OIDs["1"]
+		if !ok {
+			t.Errorf("%s: PDU 1 not discovered", test.name)
+			continue
+		}
+		if pdu.ID != "x0m0p0" || pdu.Type != "CabinetPDU" ||
+			pdu.LastStatus != DiscoverOK {
+			t.Errorf("%s: bad PDU %s/%s/%s", test.name,
+				pdu.ID, pdu.Type, pdu.LastStatus)
+		}
+		if len(pdu.Outlets.OIDs) != len(test.outletIDs) {
+			t.Errorf("%s: expected %d outlets, got %d", test.name,
+				len(test.outletIDs), len(pdu.Outlets.OIDs))
+		}
+		for i, id := range test.outletIDs {
+			out, ok := pdu.Outlets.OIDs[id]
+			if !ok {
+				t.Errorf("%s: outlet %s not discovered", test.name, id)
+				continue
+			}
+			expID := fmt.Sprintf("x0m0p0v%d", i+1)
+			if out.ID != expID || out.Type != "CabinetPDUPowerConnector" ||
+				out.LastStatus != DiscoverOK {
+				t.Errorf("%s: outlet %s: expected %s CabinetPDUPowerConnector, "+
+					"got %s %s (%s)", test.name, id, expID, out.ID,
+					out.Type, out.LastStatus)
+			}
+			expState := "On"
+			if id == test.expOff {
+				expState = "Off"
+			}
+			if out.State != expState {
+				t.Errorf("%s: outlet %s: expected State %s, got %s",
+					test.name, id, expState, out.State)
+			}
+			expStates, ok := test.expStates[id]
+			if !ok {
+				expStates = []string{"On", "Off", "PowerCycle"}
+			}
+			if out.Actions == nil || out.Actions.PowerControl == nil {
+				t.Errorf("%s: outlet %s: no PowerControl", test.name, id)
+			} else if pc := out.Actions.PowerControl; !reflect.DeepEqual(
+				pc.AllowableValues, expStates) || !strings.HasPrefix(
+				pc.Target, testPathPDU1+"/Outlets/"+id+"/") {
+				t.Errorf("%s: outlet %s: bad PowerControl %+v",
+					test.name, id, pc)
+			}
+		}
+	}
+}
+
+func TestOutletIDLess(t *testing.T) {
+	ids := []string{"AA10", "AB1", "AA2", "10", "2", "X", "AA1", "A"}
+	exp := []string{"2", "10", "A", "AA1", "AA2", "AA10", "AB1", "X"}
+	sort.Slice(ids, func(i, j int) bool { return outletIDLess(ids[i], ids[j]) })
+	if !reflect.DeepEqual(ids, exp) {
+		t.Errorf("Expected %v, got %v", exp, ids)
+	}
+}
@@ -179,6 +179,7 @@ func (c *EpStorageCollection) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			c.LastStatus = EPResponseFailedDecode
+			c.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -186,6 +187,13 @@ func (c *EpStorageCollection) discoverRemotePhase1() {
 	//onto the parent system's Drives collection (c.sysRF.Drives.OIDs)
 	sort.Sort(ResourceIDSlice(c.StorageCollectionRF.Drives))
 	for i, dOID := range c.StorageCollectionRF.Drives {
+		// The same drive can be reachable through more than one storage
+		// controller.  Only the first one gets a component.
+		if _, ok := c.sysRF.Drives.OIDs[dOID.Oid]; ok {
+			errlog.Printf("%s: Drive %s already seen under another "+
+				"storage collection, skipping.", url, dOID.Oid)
+			continue
+		}
 		c.sysRF.Drives.OIDs[dOID.Oid] = NewEpDrive(c, dOID, i)
 		c.sysRF.Drives.Num = c.sysRF.Drives.Num + 1
 	}
@@ -303,10 +311,16 @@ func (d *EpDrive) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			d.LastStatus = EPResponseFailedDecode
+			d.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
+	// MediaType (HDD, SSD) is the most useful subtype, but is optional.
+	// NVMe drives in particular may only report their Protocol.
 	d.RedfishSubtype = d.DriveRF.MediaType
+	if d.RedfishSubtype == "" {
+		d.RedfishSubtype = d.DriveRF.Protocol
+	}
 
 	if rfVerbose > 0 {
 		jout, _ := json.MarshalIndent(d, "", "   ")
@@ -73,8 +73,9 @@ type InventoryData struct {
 
 // Type specific info for Redfish Chassis components
 type ComponentChassisInfo struct {
-	Name    string          `json:"Name,omitempty"`
-	Actions *ChassisActions `json:"Actions,omitempty"`
+	Name    string               `json:"Name,omitempty"`
+	Actions *ChassisActions      `json:"Actions,omitempty"`
+	Sensors []*ThermalSensorInfo `json:"Sensors,omitempty"`
 }
 
 // Type specific info for Redfish ComputerSystem components
@@ -83,23 +84,58 @@ type ComponentSystemInfo struct {
 	Actions    *ComputerSystemActions `json:"Actions,omitempty"`
 	EthNICInfo []*EthernetNICInfo     `json:"EthernetNICInfo,omitempty"`
 	PowerCtlInfo
-	Controls   []*Control             `json:"Controls,omitempty"`
+	Controls    []*Control           `json:"Controls,omitempty"`
+	Sensors     []*ThermalSensorInfo `json:"Sensors,omitempty"`
+	LogServices []*LogServiceInfo    `json:"LogServices,omitempty"`
+	Boot        *ComputerSystemBoot  `json:"Boot,omitempty"`
+	SecureBoot  *SecureBootInfo      `json:"SecureBoot,omitempty"`
 }
 
 type ComponentManagerInfo struct {
-	Name       string             `json:"Name,omitempty"`
-	Actions    *ManagerActions    `json:"Actions,omitempty"`
-	EthNICInfo []*EthernetNICInfo `json:"EthernetNICInfo,omitempty"`
+	Name        string             `json:"Name,omitempty"`
+	Actions     *ManagerActions    `json:"Actions,omitempty"`
+	EthNICInfo  []*EthernetNICInfo `json:"EthernetNICInfo,omitempty"`
+	LogServices []*LogServiceInfo  `json:"LogServices,omitempty"`
 }
 
 type ComponentPDUInfo struct {
-	Name    string                    `json:"Name,omitempty"`
-	Actions *PowerDistributionActions `json:"Actions,omitempty"`
+	Name       string                    `json:"Name,omitempty"`
+	Actions    *PowerDistributionActions `json:"Actions,omitempty"`
+	Mains      []*CircuitInfo            `json:"Mains,omitempty"`
+	Branches   []*CircuitInfo            `json:"Branches,omitempty"`
+	SensorsURL string                    `json:"SensorsURL,omitempty"`
+	MetricsURL string                    `json:"MetricsURL,omitempty"`
 }
 
 type ComponentOutletInfo struct {
-	Name    string         `json:"Name,omitempty"`
-	Actions *OutletActions `json:"Actions,omitempty"`
+	Name          string            `json:"Name,omitempty"`
+	Actions       *OutletActions    `json:"Actions,omitempty"`
+	BranchCircuit string            `json:"BranchCircuit,omitempty"` // @odata.id
+	Sensors       []*MeteringSensor `json:"Sensors,omitempty"`
+}
+
+// A mains or branch circuit of a PDU.  Circuits don't get xnames, so they
+// are kept with the PDU along with where their metering data can be read.
+type CircuitInfo struct {
+	RedfishId        string            `json:"RedfishId"`
+	Oid              string            `json:"@odata.id"`
+	Name             string            `json:"Name,omitempty"`
+	CircuitType      string            `json:"CircuitType,omitempty"`
+	PhaseWiringType  string            `json:"PhaseWiringType,omitempty"`
+	NominalVoltage   string            `json:"NominalVoltage,omitempty"`
+	RatedCurrentAmps json.Number       `json:"RatedCurrentAmps,omitempty"`
+	Outlets          []string          `json:"Outlets,omitempty"` // @odata.ids
+	Sensors          []*MeteringSensor `json:"Sensors,omitempty"`
+}
+
+// Reference to a metering reading of an outlet or circuit, taken from the
+// sensor excerpts in its Redfish payload.  Property is where the excerpt
+// was found, e.g. PowerSensor or PolyPhaseVoltageSensors.Line1ToNeutral,
+// and DataSourceUri the Sensor resource with the full reading.
+type MeteringSensor struct {
+	Property      string `json:"Property"`
+	DataSourceUri string `json:"DataSourceUri"`
+	ReadingUnits  string `json:"ReadingUnits,omitempty"`
 }
 
 type EthernetNICInfo struct {
@@ -124,13 +160,23 @@ type PowerControl struct {
 	Name               string        `json:"Name,omitempty"`
 	PowerCapacityWatts int           `json:"PowerCapacityWatts,omitempty"`
 	PowerConsumedWatts interface{}   `json:"PowerConsumedWatts,omitempty"`	// May come in an int or float, but need an int
+	PowerLimit         *PowerLimitRF `json:"PowerLimit,omitempty"`
 	OEM                *PwrCtlOEM    `json:"OEM,omitempty"`
 	RelatedItem        []*ResourceID `json:"RelatedItem,omitempty"`
 }
 
+// Standard Redfish PowerLimit.  Vendors without OEM power capping info,
+// e.g. SuperMicro, only give the current limit here.
+type PowerLimitRF struct {
+	LimitInWatts   *int   `json:"LimitInWatts,omitempty"`
+	LimitException string `json:"LimitException,omitempty"`
+	CorrectionInMs int    `json:"CorrectionInMs,omitempty"`
+}
+
 type PwrCtlOEM struct {
-	Cray *PwrCtlOEMCray `json:"Cray,omitempty"`
-	HPE  *PwrCtlOEMHPE  `json:"HPE,omitempty"`
+	Cray   *PwrCtlOEMCray   `json:"Cray,omitempty"`
+	HPE    *PwrCtlOEMHPE    `json:"HPE,omitempty"`
+	Lenovo *PwrCtlOEMLenovo `json:"Lenovo,omitempty"`
 }
 
 type PwrCtlOEMCray struct {
@@ -151,6 +197,37 @@ type PwrCtlOEMHPE struct {
 	Target     string           `json:"Target"`
 }
 
+// Lenovo XCC puts the power capping range under PowerUtilization, for both
+// AC and DC.  PowerLimit is filled in from whichever one LimitMode says is
+// in use, so it can be read the same way as the Cray one.
+type PwrCtlOEMLenovo struct {
+	PowerUtilization *LenovoPowerUtilization `json:"PowerUtilization,omitempty"`
+	PowerLimit       *CrayPwrLimit           `json:"PowerLimit,omitempty"`
+}
+
+type LenovoPowerUtilization struct {
+	EnablePowerCapping bool   `json:"EnablePowerCapping"`
+	LimitMode          string `json:"LimitMode,omitempty"` // AC or DC
+	CapacityMinAC      int    `json:"CapacityMinAC,omitempty"`
+	CapacityMaxAC      int    `json:"CapacityMaxAC,omitempty"`
+	CapacityMinDC      int    `json:"CapacityMinDC,omitempty"`
+	CapacityMaxDC      int    `json:"CapacityMaxDC,omitempty"`
+}
+
+// Set PowerLimit from the AC or DC capacity range, per LimitMode.
+// AC is the default.
+func (l *PwrCtlOEMLenovo) setPowerLimit() {
+	pu := l.PowerUtilization
+	if pu == nil {
+		return
+	}
+	if strings.EqualFold(pu.LimitMode, "DC") {
+		l.PowerLimit = &CrayPwrLimit{Min: pu.CapacityMinDC, Max: pu.CapacityMaxDC}
+	} else {
+		l.PowerLimit = &CrayPwrLimit{Min: pu.CapacityMinAC, Max: pu.CapacityMaxAC}
+	}
+}
+
 type PwrCtlRelatedItem struct {
 	Oid string `json:"@odata.id"`
 }
@@ -206,6 +283,9 @@ type EpChassis struct {
 	ChassisRF     Chassis          `json:"chassisRF"`
 	chassisURLRaw *json.RawMessage //`json:"chassisURLRaw"`
 
+	// Vendor workarounds for this Chassis, see Quirks()
+	quirks *QuirkSet
+
 	Power         *EpPower        `json:"Power"`
 	PowerSupplies EpPowerSupplies `json:"PowerSupplies"`
 
@@ -315,6 +395,7 @@ func (c *EpChassis) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			c.LastStatus = EPResponseFailedDecode
+			c.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -373,6 +454,9 @@ func (c *EpChassis) discoverRemotePhase1() {
 
 	}
 
+	// Fans and temperature sensors, if enabled
+	c.discoverThermal()
+
 	c.LastStatus = VerifyingData
 	if rfVerbose > 0 {
 		jout, _ := json.MarshalIndent(c, "", "   ")
@@ -557,6 +641,9 @@ type EpManager struct {
 	ManagerRF     Manager          `json:"managerRF"`
 	managerURLRaw *json.RawMessage //`json:"managerURLRaw"`
 
+	// Vendor workarounds for this Manager, see Quirks()
+	quirks *QuirkSet
+
 	// Ethernet Interfaces are children of Managers or Systems, not top
 	// level components like Chassis or Managers.  Therefore we put the
 	// local collection here.  Chassis or Managers are consolodated at the
@@ -638,6 +725,7 @@ func (m *EpManager) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			m.LastStatus = EPResponseFailedDecode
+			m.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -647,27 +735,44 @@ func (m *EpManager) discoverRemotePhase1() {
 	m.ManagerInChassis = m.ManagerRF.Links.ManagerInChassis
 	m.ManagedChassis = m.ManagerRF.Links.ManagerForChassis
 	m.ManagedSystems = m.ManagerRF.Links.ManagerForServers
+	if rfDiscoverLogServices {
+		m.LogServices = m.epRF.getLogServices(m.ManagerRF.LogServices.Oid)
+	}
 	if m.ManagerRF.Actions != nil {
 		m.Actions = m.ManagerRF.Actions
 		mr := m.Actions.ManagerReset
 		if mr.RFActionInfo != "" {
 			actionInfoJSON, err := m.epRF.GETRelative(mr.RFActionInfo)
 			if err != nil || actionInfoJSON == nil {
-				m.LastStatus = HTTPsGetFailed
-				return
-			}
-			var actionInfo ResetActionInfo
-			err = json.Unmarshal(actionInfoJSON, &actionInfo)
-			if err != nil {
-				errlog.Printf("Failed to decode %s: %s\n", url, err)
-				m.LastStatus = EPResponseFailedDecode
-			}
-			for _, p := range actionInfo.RAParameters {
-				if p.Name == "ResetType" {
-					m.Actions.ManagerReset.AllowableValues = p.AllowableValues
+				if !m.Quirks().Has(QuirkOptionalActionInfo) {
+					m.LastStatus = HTTPsGetFailed
+					return
+				}
+				errlog.Printf("%s: Could not get %s, using inline ResetTypes\n",
+					topURL, mr.RFActionInfo)
+			} else {
+				var actionInfo ResetActionInfo
+				err = json.Unmarshal(actionInfoJSON, &actionInfo)
+				if err != nil {
+					errlog.Printf("Failed to decode %s: %s\n", url, err)
+					m.LastStatus = EPResponseFailedDecode
+					m.epRF.addDiscoveryError(url, 0, err)
+				}
+				for _, p := range actionInfo.RAParameters {
+					// Some BMCs leave these empty and only give the
+					// inline ResetType@Redfish.AllowableValues.
+					if p.Name == "ResetType" && len(p.AllowableValues) > 0 {
+						m.Actions.ManagerReset.AllowableValues = p.AllowableValues
+					}
 				}
 			}
 		}
+		// Manager.Reset doesn't always take a ResetType.
+		if len(m.Actions.ManagerReset.AllowableValues) == 0 &&
+			m.Actions.ManagerReset.Target != "" &&
+			m.Quirks().Has(QuirkDefaultManagerResetTypes) {
+			m.Actions.ManagerReset.AllowableValues = []string{"ForceRestart"}
+		}
 	}
 
 	// Get link to Manager's ethernet interfaces
@@ -692,6 +797,7 @@ func (m *EpManager) discoverRemotePhase1() {
 		if err := json.Unmarshal(ethIfacesJSON, &ethInfo); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			m.LastStatus = EPResponseFailedDecode
+			m.epRF.addDiscoveryError(url, 0, err)
 		}
 		if ethInfo.MembersOCount > 0 && ethInfo.MembersOCount != len(ethInfo.Members) {
 			errlog.Printf("%s: Member@odata.count != Member array len\n", url)
@@ -967,6 +1073,9 @@ type EpSystem struct {
 	SystemRF  ComputerSystem   `json:"ComputerSystemRF"`
 	sysURLRaw *json.RawMessage //`json:"sysURLRaw"`
 
+	// Vendor workarounds for this System, see Quirks()
+	quirks *QuirkSet
+
 	// Ethernet Interfaces are children of Managers or Systems, not top
 	// level components like Chassis or Managers.  Therefore we put the
 	// local collection here.  Chassis or Managers are consolodated at the
@@ -997,9 +1106,6 @@ type EpSystem struct {
 	Processors EpProcessors `json:"processors"`
 	MemoryMods EpMemoryMods `json:"memoryMods"`
 
-	cpuCount   int
-	accelCount int
-
 	StorageGroups EpStorageCollections `json:"storageGroups"`
 	Drives        EpDrives             `json:"drives"`
 
@@ -1027,8 +1133,6 @@ func NewEpSystem(epRF *RedfishEP, odataID ResourceID, rawOrdinal int) *EpSystem
 	s.Ordinal = -1
 	s.RawOrdinal = rawOrdinal
 	s.epRF = epRF
-	s.cpuCount = 0
-	s.accelCount = 0
 	return s
 }
 
@@ -1083,6 +1187,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 			} else {
 				errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 				s.LastStatus = EPResponseFailedDecode
+				s.epRF.addDiscoveryError(url, 0, err)
 				return
 			}
 		}
@@ -1108,6 +1213,15 @@ func (s *EpSystem) discoverRemotePhase1() {
 	s.UUID = s.SystemRF.UUID
 	s.ManagedBy = s.SystemRF.Links.ManagedBy
 	s.ChassisForSys = s.SystemRF.Links.Chassis
+	if rfDiscoverLogServices {
+		s.LogServices = s.epRF.getLogServices(s.SystemRF.LogServices.Oid)
+	}
+	// Keep the boot order and override settings, e.g. to check for UEFI
+	if !s.SystemRF.Boot.IsEmpty() {
+		boot := s.SystemRF.Boot
+		s.Boot = &boot
+	}
+	s.discoverSecureBoot()
 	// The format of the Actions field of the ComputerSystem Redfish response
 	// has changed in the AMI Redfish implementation. Both the Mountain and
 	// Gigabyte nodes use this new Action field.
@@ -1161,18 +1275,28 @@ func (s *EpSystem) discoverRemotePhase1() {
 		if csr.RFActionInfo != "" {
 			actionInfoJSON, err := s.epRF.GETRelative(csr.RFActionInfo)
 			if err != nil || actionInfoJSON == nil {
-				s.LastStatus = HTTPsGetFailed
-				return
-			}
-			var actionInfo ResetActionInfo
-			err = json.Unmarshal(actionInfoJSON, &actionInfo)
-			if err != nil {
-				errlog.Printf("Failed to decode %s: %s\n", url, err)
-				s.LastStatus = EPResponseFailedDecode
-			}
-			for _, p := range actionInfo.RAParameters {
-				if p.Name == "ResetType" {
-					s.Actions.ComputerSystemReset.AllowableValues = p.AllowableValues
+				if !s.Quirks().Has(QuirkOptionalActionInfo) {
+					s.LastStatus = HTTPsGetFailed
+					return
+				}
+				// Keep the target and any inline AllowableValues
+				// rather than losing the whole System.
+				errlog.Printf("%s: Could not get %s, using inline ResetTypes\n",
+					topURL, csr.RFActionInfo)
+			} else {
+				var actionInfo ResetActionInfo
+				err = json.Unmarshal(actionInfoJSON, &actionInfo)
+				if err != nil {
+					errlog.Printf("Failed to decode %s: %s\n", url, err)
+					s.LastStatus = EPResponseFailedDecode
+					s.epRF.addDiscoveryError(url, 0, err)
+				}
+				for _, p := range actionInfo.RAParameters {
+					// Some BMCs leave these empty and only give the
+					// inline ResetType@Redfish.AllowableValues.
+					if p.Name == "ResetType" && len(p.AllowableValues) > 0 {
+						s.Actions.ComputerSystemReset.AllowableValues = p.AllowableValues
+					}
 				}
 			}
 		}
@@ -1188,7 +1312,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 	isFoxconnPowerOnEventDiscovery := false
 	nodeChassis, ok := s.epRF.Chassis.OIDs[s.SystemRF.Id]
 	if !ok {
-		if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 {
+		if s.Quirks().Has(QuirkProcessorModuleChassis) {
 			// Foxconn Paradise uses the ProcessorModule_0 chassis to find the 
 			// Power endpoint for power capping.
 			nodeChassis, ok = s.epRF.Chassis.OIDs["ProcessorModule_0"]
@@ -1249,13 +1373,16 @@ func (s *EpSystem) discoverRemotePhase1() {
 	}
 
 	if ok {
+		// Fans and temperature sensors of the node, if discovered
+		s.Sensors = nodeChassis.Sensors
+
 		//
 		// Get PowerControl Info if it exists
 		//
 		// Note: Foxconn Paradise boards have a Controls entry but it is used for
 		// something entirely different so skip it here for Foxconn
 		//
-		if (nodeChassis.ChassisRF.Controls.Oid != "") && (IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) != 1) {
+		if (nodeChassis.ChassisRF.Controls.Oid != "") && !s.Quirks().Has(QuirkSkipChassisControls) {
 			path = nodeChassis.ChassisRF.Controls.Oid
 			ctlURLJSON, err := s.epRF.GETRelative(path)
 			if err != nil || ctlURLJSON == nil {
@@ -1272,6 +1399,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 				} else {
 					errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 					s.LastStatus = EPResponseFailedDecode
+					s.epRF.addDiscoveryError(url, 0, err)
 					return
 				}
 			}
@@ -1309,7 +1437,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 			path = nodeChassis.ChassisRF.Power.Oid
 			pwrCtlURLJSON, err := s.epRF.GETRelative(path, maxPowerRetries)
 			if err != nil || pwrCtlURLJSON == nil {
-				if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 {
+				if s.Quirks().Has(QuirkDelayedPowerData) {
 					// When the node power is off, the Power endpoint for the ProcessorModule_0
 					// has been observed with earlier versions of BMC fw to not available and
 					// thus the s.epRF.GetRelative() call will time out. We cannot treat this
@@ -1345,6 +1473,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 				} else {
 					errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 					s.LastStatus = EPResponseFailedDecode
+					s.epRF.addDiscoveryError(url, 0, err)
 					return
 				}
 			}
@@ -1363,7 +1492,10 @@ func (s *EpSystem) discoverRemotePhase1() {
 						errlog.Printf("ERROR: unexpected type/value '%T'/'%v' detected for PowerConsumedWatts, setting to 0\n", pwrCtl.PowerConsumedWatts, pwrCtl.PowerConsumedWatts)
 					}
 				}
-				if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 {
+				if pwrCtl.OEM != nil && pwrCtl.OEM.Lenovo != nil {
+					pwrCtl.OEM.Lenovo.setPowerLimit()
+				}
+				if s.Quirks().Has(QuirkDelayedPowerData) {
 					// Even though we've successfully read the /Power endpoint, we have
 					// observed that the Paradise BMC fw may not have populated it with
 					// all of the data that we depend on if the node was just powered on.
@@ -1455,7 +1587,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 		//
 		// Get Chassis assembly (NodeAccelRiser) info if it exists
 		//
-		if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 {
+		if s.Quirks().Has(QuirkBaseboard0Assemblies) {
 			// Assemblies are in Baseboard_0 for Foxconn Paradise
 			nodeChassis, ok = s.epRF.Chassis.OIDs["Baseboard_0"]
 			if !ok {
@@ -1527,6 +1659,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 			if err := json.Unmarshal(devicesJSON, &deviceInfo); err != nil {
 				errlog.Printf("Failed to decode %s: %s\n", url, err)
 				s.LastStatus = EPResponseFailedDecode
+				s.epRF.addDiscoveryError(url, 0, err)
 			}
 
 			s.HpeDevices.Num = len(deviceInfo.Members)
@@ -1542,7 +1675,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 			s.HpeDevices.Num = 0
 			s.HpeDevices.OIDs = make(map[string]*EpHpeDevice)
 
-			if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 {
+			if s.Quirks().Has(QuirkBaseboard0Assemblies) {
 				// NetworkAdapters are in Baseboard_0 for Foxconn Paradise. nodeChassis
 				// should still be Baseboard_0 after discovering assemblies but let's
 				// play it safe in case of future code changes and set it again here
@@ -1574,6 +1707,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 				if err := json.Unmarshal(naJSON, &naInfo); err != nil {
 					errlog.Printf("Failed to decode %s: %s\n", url, err)
 					s.LastStatus = EPResponseFailedDecode
+					s.epRF.addDiscoveryError(url, 0, err)
 				}
 
 				s.NetworkAdapters.Num = len(naInfo.Members)
@@ -1597,7 +1731,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 		s.ENetInterfaces.Num = 0
 		s.ENetInterfaces.OIDs = make(map[string]*EpEthInterface)
 
-		if IsManufacturer(s.SystemRF.Manufacturer, FoxconnMfr) == 1 &&
+		if s.Quirks().Has(QuirkInsydeNcsiEthInterfaces) &&
 			s.SystemRF.OEM != nil && s.SystemRF.OEM.InsydeNcsi != nil {
 			// Foxconn uses an entirely different hierarchy
 			discoverFoxconnENetInterfaces(s)
@@ -1622,6 +1756,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 		if err := json.Unmarshal(ethIfacesJSON, &ethInfo); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			s.LastStatus = EPResponseFailedDecode
+			s.epRF.addDiscoveryError(url, 0, err)
 		}
 
 		// The count is typically given as "Members@odata.count", but
@@ -1667,6 +1802,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 		if err := json.Unmarshal(processorsJSON, &procInfo); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			s.LastStatus = EPResponseFailedDecode
+			s.epRF.addDiscoveryError(url, 0, err)
 		}
 
 		// The count is typically given as "Members@odata.count", but
@@ -1715,6 +1851,7 @@ func (s *EpSystem) discoverRemotePhase1() {
 		if err := json.Unmarshal(memoryModsJSON, &memInfo); err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", url, err)
 			s.LastStatus = EPResponseFailedDecode
+			s.epRF.addDiscoveryError(url, 0, err)
 		}
 
 		// The count is typically given as "Members@odata.count", but
@@ -1748,42 +1885,48 @@ func (s *EpSystem) discoverRemotePhase1() {
 		url = s.epRF.FQDN + path
 		storageJSON, err := s.epRF.GETRelative(path)
 		if err != nil || storageJSON == nil {
-			s.LastStatus = HTTPsGetFailed
-			return
-		}
-		if rfDebug > 0 {
-			errlog.Printf("%s: %s\n", url, storageJSON)
-		}
-		s.LastStatus = HTTPsGetOk
+			// Drives are optional, so don't fail discovery of the whole
+			// system just because they can't be retrieved.
+			errlog.Printf("%s: StorageCollection GET failed, skipping "+
+				"drives: %v\n", url, err)
+			s.Drives.Num = 0
+			s.Drives.OIDs = make(map[string]*EpDrive)
+		} else {
+			if rfDebug > 0 {
+				errlog.Printf("%s: %s\n", url, storageJSON)
+			}
+			s.LastStatus = HTTPsGetOk
 
-		var storageInfo Storage
-		if err := json.Unmarshal(storageJSON, &storageInfo); err != nil {
-			errlog.Printf("Failed to decode %s: %s\n", url, err)
-			s.LastStatus = EPResponseFailedDecode
-		}
+			var storageInfo Storage
+			if err := json.Unmarshal(storageJSON, &storageInfo); err != nil {
+				errlog.Printf("Failed to decode %s: %s\n", url, err)
+				s.LastStatus = EPResponseFailedDecode
+				s.epRF.addDiscoveryError(url, 0, err)
+			}
 
-		// The count is typically given as "Members@odata.count", but
-		// older versions drop the "Members" identifier
-		if storageInfo.MembersOCount > 0 && storageInfo.MembersOCount != len(storageInfo.Members) {
-			errlog.Printf("%s: Member@odata.count != Member array len\n", url)
-		} else if storageInfo.OCount > 0 && storageInfo.OCount != len(storageInfo.Members) {
-			errlog.Printf("%s: odata.count != Member array len\n", url)
-		}
-		//iterate across storageInfo.members and create a new EpStorageCollection
-		//for each, and push it into the StorageGroups OIDs
-		var epStorage = NewEpStorage(s, ResourceID{storageInfo.Oid})
-		s.StorageGroups.Num = len(storageInfo.Members)
-		s.StorageGroups.OIDs = make(map[string]*EpStorageCollection)
-		sort.Sort(ResourceIDSlice(storageInfo.Members))
-		for i, gOID := range storageInfo.Members {
-			gID := gOID.Basename()
-			s.StorageGroups.OIDs[gID] = NewEpStorageCollection(epStorage, gOID, i)
+			// The count is typically given as "Members@odata.count", but
+			// older versions drop the "Members" identifier
+			if storageInfo.MembersOCount > 0 && storageInfo.MembersOCount != len(storageInfo.Members) {
+				errlog.Printf("%s: Member@odata.count != Member array len\n", url)
+			} else if storageInfo.OCount > 0 && storageInfo.OCount != len(storageInfo.Members) {
+				errlog.Printf("%s: odata.count != Member array len\n", url)
+			}
+			//iterate across storageInfo.members and create a new EpStorageCollection
+			//for each, and push it into the StorageGroups OIDs
+			var epStorage = NewEpStorage(s, ResourceID{storageInfo.Oid})
+			s.StorageGroups.Num = len(storageInfo.Members)
+			s.StorageGroups.OIDs = make(map[string]*EpStorageCollection)
+			sort.Sort(ResourceIDSlice(storageInfo.Members))
+			for i, gOID := range storageInfo.Members {
+				gID := gOID.Basename()
+				s.StorageGroups.OIDs[gID] = NewEpStorageCollection(epStorage, gOID, i)
+			}
+			s.Drives.Num = 0
+			s.Drives.OIDs = make(map[string]*EpDrive)
+			//the s.Drives collection will also be populated after this call
+			s.StorageGroups.discoverRemotePhase1()
+			s.Drives.discoverRemotePhase1()
 		}
-		s.Drives.Num = 0
-		s.Drives.OIDs = make(map[string]*EpDrive)
-		//the s.Drives collection will also be populated after this call
-		s.StorageGroups.discoverRemotePhase1()
-		s.Drives.discoverRemotePhase1()
 	}
 
 	if rfVerbose > 0 {
@@ -1940,26 +2083,9 @@ func (s *EpSystem) discoverComponentEPEthInterfaces() {
 		s.EthNICInfo = append(s.EthNICInfo, ethIDAddr)
 	}
 	// No EthernetInterface objects found.  Uh oh.
-	// See if we can apply a workaround for Intel s2600 boards
+	// See if we can apply a workaround, e.g. for Intel s2600 boards
 	// or gigabyte nodes with R05 bios.
-	intelMACWorkaround := false
-	gigayteMACWorkaround := false
 	if len(s.ENetInterfaces.OIDs) == 0 {
-		// Intel Buchanan Pass, Wolf Pass, etc.
-		if strings.Contains(strings.ToLower(s.SystemRF.Model), "s2600") ||
-			strings.Contains(strings.ToLower(s.SystemRF.Name), "s2600") ||
-			strings.HasPrefix(strings.ToLower(s.SystemRF.Id), "qs") {
-
-			intelMACWorkaround = true
-		} else if strings.Contains(strings.ToLower(s.SystemRF.Model), "r272-z30-00") {
-			// Gigabyte nodes
-			gigayteMACWorkaround = true
-		}
-	}
-	// Use s2600 workaround as we seem to have this type of board and got
-	// zero node/system ethernet interfaces.  We need to get the lowest
-	// MAC address amongst the BMC MAC addresses and compute an offset.
-	if intelMACWorkaround || gigayteMACWorkaround {
 		var mgr *EpManager = nil
 		var ok bool = false
 		// Get the first manager linked to in the system object
@@ -1977,12 +2103,12 @@ func (s *EpSystem) discoverComponentEPEthInterfaces() {
 				break
 			}
 		}
-		// Found a manager, now look at its interfaces
+		// Found a manager, now look at its interfaces.  Some rules only
+		// apply to known problem BMC firmware so include its version.
 		if mgr != nil {
-			// Don't apply workaround on gigabyte nodes if they
-			// aren't the known problem firmware
-			if intelMACWorkaround ||
-				strings.HasPrefix(strings.ToLower(mgr.ManagerRF.FirmwareVersion), "12") {
+			target := s.quirkTarget()
+			target.FirmwareVersion = mgr.ManagerRF.FirmwareVersion
+			if s.epRF.lookupQuirks(target).Has(QuirkMACFromBMCOffset) {
 				minMAC := ""
 				for _, eth := range mgr.ENetInterfaces.OIDs {
 					thisMAC := NormalizeMAC(eth.EtherIfaceRF.MACAddress)
@@ -2027,6 +2153,59 @@ func (s *EpSystem) discoverComponentEPEthInterfaces() {
 			}
 		}
 	}
+	s.discoverNetworkAdapterEthInterfaces()
+}
+
+// Add EthernetNICInfo entries for the MACs of any NetworkAdapter
+// NetworkDeviceFunctions that weren't already listed under the System's
+// EthernetInterfaces.  These are never used for the main MAC address.
+func (s *EpSystem) discoverNetworkAdapterEthInterfaces() {
+	seen := make(map[string]bool)
+	for _, ei := range s.EthNICInfo {
+		if ei.MACAddress != "" {
+			seen[ei.MACAddress] = true
+		}
+		if ei.PermanentMACAddress != "" {
+			seen[ei.PermanentMACAddress] = true
+		}
+	}
+	naIDs := make([]string, 0, len(s.NetworkAdapters.OIDs))
+	for naID := range s.NetworkAdapters.OIDs {
+		naIDs = append(naIDs, naID)
+	}
+	sort.Strings(naIDs)
+	for _, naID := range naIDs {
+		na := s.NetworkAdapters.OIDs[naID]
+		for _, ndf := range na.NetDevFuncsRF {
+			if ndf.Ethernet == nil {
+				continue
+			}
+			mac := NormalizeMACIfValid(ndf.Ethernet.MACAddress)
+			pmac := NormalizeMACIfValid(ndf.Ethernet.PermanentMACAddress)
+			if (mac == "" && pmac == "") || seen[mac] || seen[pmac] {
+				continue
+			}
+			ethIDAddr := new(EthernetNICInfo)
+			ethIDAddr.RedfishId = ndf.Id
+			ethIDAddr.Oid = ndf.Oid
+			ethIDAddr.Description = ndf.Description
+			if ethIDAddr.Description == "" {
+				ethIDAddr.Description = fmt.Sprintf(
+					"NetworkAdapter %s function %s", naID, ndf.Id,
+				)
+			}
+			ethIDAddr.InterfaceEnabled = ndf.DeviceEnabled
+			ethIDAddr.MACAddress = mac
+			ethIDAddr.PermanentMACAddress = pmac
+			if mac != "" {
+				seen[mac] = true
+			}
+			if pmac != "" {
+				seen[pmac] = true
+			}
+			s.EthNICInfo = append(s.EthNICInfo, ethIDAddr)
+		}
+	}
 }
 
 // Sets up HMS state fields using Status/State/Health info from Redfish
@@ -2165,6 +2344,7 @@ func (ei *EpEthInterface) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			ei.LastStatus = EPResponseFailedDecode
+			ei.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -2279,6 +2459,7 @@ func (p *EpProcessor) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			p.LastStatus = EPResponseFailedDecode
+			p.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -2330,7 +2511,7 @@ func (p *EpProcessor) discoverLocalPhase2() {
 
 	// CPUs and GPUs are both under processors
 	p.Ordinal = p.epRF.getProcessorOrdinal(p)
-	if strings.ToLower(p.RedfishSubtype) == "gpu" {
+	if IsAccelProcessorType(p.RedfishSubtype) {
 		p.ID = p.sysRF.ID + "a" + strconv.Itoa(p.Ordinal)
 		p.Type = xnametypes.NodeAccel.String()
 	} else {
@@ -2486,6 +2667,7 @@ func (m *EpMemory) discoverRemotePhase1() {
 		} else {
 			errlog.Printf("ERROR: json decode failed: %s: %s\n", url, err)
 			m.LastStatus = EPResponseFailedDecode
+			m.epRF.addDiscoveryError(url, 0, err)
 			return
 		}
 	}
@@ -30,6 +30,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
 	base "github.com/Cray-HPE/hms-base/v2"
@@ -583,6 +585,127 @@ var PRLTVerifyInfo = RedfishEPVerifyInfo{
 	SystemActionTargets: []string{"/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
 }
 
+// Lenovo ThinkSystem XCC dummy endpoint 1
+var TestRedfishEPInitLenovo = RedfishEP{
+	RedfishEPDescription: RedfishEPDescription{
+		ID:             testXName,
+		Type:           "NodeBMC",
+		Hostname:       "x3000c0s9b0",
+		Domain:         testDomain,
+		FQDN:           testFQDN,
+		Enabled:        true,
+		User:           "root",
+		Password:       "********",
+		UseSSDP:        false,
+		MACRequired:    false,
+		RediscOnUpdate: false,
+		DiscInfo: DiscoveryInfo{
+			LastStatus: NotYetQueried,
+		},
+	},
+	ServiceRootURL: testFQDN + "/redfish/v1",
+	RedfishType:    "ServiceRoot",
+	OdataID:        "/redfish/v1",
+	NumSystems:     0,
+}
+
+// Verification data for Lenovo XCC dummy endpoint 1
+var LenovoVerifyInfo = RedfishEPVerifyInfo{
+	SystemIds:             []string{"1"},
+	SystemActionCount:     7,
+	SystemActionTargets:   []string{"/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
+	SystemExpectPowerInfo: true,
+	SystemPowerControl: []*PowerControl{{
+		Name:               "Server Power Control",
+		PowerCapacityWatts: 1100,
+		OEM: &PwrCtlOEM{Lenovo: &PwrCtlOEMLenovo{
+			PowerLimit: &CrayPwrLimit{Min: 402, Max: 1040},
+		}},
+		RelatedItem: []*ResourceID{
+			{Oid: "/redfish/v1/Systems/1"},
+			{Oid: "/redfish/v1/Chassis/1"},
+		},
+	}},
+	ManagerId:                 "1",
+	ManagerType:               "NodeBMC",
+	ManagerActionCount:        2,
+	ManagerActionTarget:       "/redfish/v1/Managers/1/Actions/Manager.Reset",
+	NodeEnclosureId:           "1",
+	NodeEnclosureActionCount:  -1,
+	NodeEnclosureActionTarget: "",
+}
+
+// Aggregator dummy endpoint 1
+var TestRedfishEPInitAggregator = RedfishEP{
+	RedfishEPDescription: RedfishEPDescription{
+		ID:             testXName,
+		Type:           "NodeBMC",
+		Hostname:       testXName,
+		Domain:         testDomain,
+		FQDN:           testFQDN,
+		Enabled:        true,
+		User:           "root",
+		Password:       "********",
+		UseSSDP:        false,
+		MACRequired:    false,
+		RediscOnUpdate: false,
+		DiscInfo: DiscoveryInfo{
+			LastStatus: NotYetQueried,
+		},
+	},
+	ServiceRootURL: testFQDN + "/redfish/v1",
+	RedfishType:    "ServiceRoot",
+	OdataID:        "/redfish/v1",
+	NumSystems:     0,
+}
+
+// SuperMicro X12 dummy endpoint 1
+var TestRedfishEPInitSupermicro = RedfishEP{
+	RedfishEPDescription: RedfishEPDescription{
+		ID:             testXName,
+		Type:           "NodeBMC",
+		Hostname:       "x3000c0s11b0",
+		Domain:         testDomain,
+		FQDN:           testFQDN,
+		Enabled:        true,
+		User:           "root",
+		Password:       "********",
+		UseSSDP:        false,
+		MACRequired:    false,
+		RediscOnUpdate: false,
+		DiscInfo: DiscoveryInfo{
+			LastStatus: NotYetQueried,
+		},
+	},
+	ServiceRootURL: testFQDN + "/redfish/v1",
+	RedfishType:    "ServiceRoot",
+	OdataID:        "/redfish/v1",
+	NumSystems:     0,
+}
+
+// Verification data for SuperMicro X12 dummy endpoint 1
+var SupermicroVerifyInfo = RedfishEPVerifyInfo{
+	SystemIds:             []string{"1"},
+	SystemActionCount:     7,
+	SystemActionTargets:   []string{"/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
+	SystemExpectPowerInfo: true,
+	SystemPowerControl: []*PowerControl{{
+		Name:               "System Power Control",
+		PowerCapacityWatts: 1600,
+		OEM:                &PwrCtlOEM{},
+		RelatedItem: []*ResourceID{
+			{Oid: "/redfish/v1/Systems/1"},
+		},
+	}},
+	ManagerId:                 "1",
+	ManagerType:               "NodeBMC",
+	ManagerActionCount:        1,
+	ManagerActionTarget:       "/redfish/v1/Managers/1/Actions/Manager.Reset",
+	NodeEnclosureId:           "1",
+	NodeEnclosureActionCount:  -1,
+	NodeEnclosureActionTarget: "",
+}
+
 // Do a mock discovery of the two main HW types we're seen so far,
 // which demonstrate all of the existing workarounds needed to discover
 // them.  This should touch just about all tv.ManagerIdhe code in rfcomponents.
@@ -758,6 +881,438 @@ func TestGetRootInfo(t *testing.T) {
 			t.Logf("Testcase 9: (PRLT): PASSED verification")
 		}
 	}
+
+	// Lenovo XCC Endpoint
+	clientLenovo1 := NewTestClient(NewRTFuncLenovo1())
+	lenovoEP1 := TestRedfishEPInitLenovo
+	lenovoEP1.client = clientLenovo1
+	lenovoEP1.GetRootInfo()
+
+	if lenovoEP1.DiscInfo.LastStatus != DiscoverOK {
+		t.Errorf("Testcase 10: (Lenovo): FAILED discovery, LastStatus: %s",
+			lenovoEP1.DiscInfo.LastStatus)
+	} else {
+		t.Logf("Testcase 10: (Lenovo): PASSED discovery, LastStatus: %s",
+			lenovoEP1.DiscInfo.LastStatus)
+		if err := VerifyGetRootInfo(&lenovoEP1, LenovoVerifyInfo); err != nil {
+			t.Errorf("Testcase 10: (Lenovo): FAILED verfication: %s", err)
+		} else {
+			t.Logf("Testcase 10: (Lenovo): PASSED verification")
+		}
+	}
+
+	// SuperMicro X12 Endpoint
+	clientSMC1 := NewTestClient(NewRTFuncSupermicro1())
+	smcEP1 := TestRedfishEPInitSupermicro
+	smcEP1.client = clientSMC1
+	smcEP1.GetRootInfo()
+
+	if smcEP1.DiscInfo.LastStatus != DiscoverOK {
+		t.Errorf("Testcase 11: (Supermicro): FAILED discovery, LastStatus: %s",
+			smcEP1.DiscInfo.LastStatus)
+	} else {
+		t.Logf("Testcase 11: (Supermicro): PASSED discovery, LastStatus: %s",
+			smcEP1.DiscInfo.LastStatus)
+		if err := VerifyGetRootInfo(&smcEP1, SupermicroVerifyInfo); err != nil {
+			t.Errorf("Testcase 11: (Supermicro): FAILED verfication: %s", err)
+		} else {
+			t.Logf("Testcase 11: (Supermicro): PASSED verification")
+		}
+	}
+}
+
+// SuperMicro puts storage enclosures (backplanes) under /Chassis as
+// separate RackMount chassis with no system.  Make sure these don't turn into
+// NodeEnclosures, and that processors, memory and the standard PowerLimit
+// are picked up for the node.
+func TestSupermicroDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncSupermicro1())
+	ep := TestRedfishEPInitSupermicro
+	ep.client = client
+	ep.GetRootInfo()
+
+	if ep.DiscInfo.LastStatus != DiscoverOK {
+		t.Fatalf("FAIL: bad LastStatus: %s", ep.DiscInfo.LastStatus)
+	}
+	c, ok := ep.Chassis.OIDs["HA-RAID.0.StorageEnclosure.0"]
+	if !ok {
+		t.Fatalf("FAIL: storage enclosure Chassis not discovered")
+	}
+	if c.Type != xnametypes.HMSTypeInvalid.String() {
+		t.Errorf("FAIL: storage enclosure has type %s, expected %s",
+			c.Type, xnametypes.HMSTypeInvalid)
+	}
+	s, ok := ep.Systems.OIDs["1"]
+	if !ok {
+		t.Fatalf("FAIL: System 1 not discovered")
+	}
+	if len(s.Processors.OIDs) != 2 {
+		t.Errorf("FAIL: Expected 2 processors, got %d", len(s.Processors.OIDs))
+	}
+	if len(s.MemoryMods.OIDs) != 2 {
+		t.Fatalf("FAIL: Expected 2 memory modules, got %d",
+			len(s.MemoryMods.OIDs))
+	}
+	tests := []struct {
+		oid   string
+		state string
+	}{
+		{"1", base.StatePopulated.String()},
+		{"2", base.StateEmpty.String()},
+	}
+	for i, test := range tests {
+		m, ok := s.MemoryMods.OIDs[test.oid]
+		if !ok {
+			t.Errorf("Testcase %d: FAIL: Memory %s not found", i, test.oid)
+			continue
+		}
+		if m.State != test.state {
+			t.Errorf("Testcase %d: FAIL: Expected State %s, got %s",
+				i, test.state, m.State)
+		}
+	}
+	if len(s.PowerCtl) != 1 || s.PowerCtl[0].PowerLimit == nil ||
+		s.PowerCtl[0].PowerLimit.LimitInWatts == nil ||
+		*s.PowerCtl[0].PowerLimit.LimitInWatts != 800 {
+		t.Errorf("FAIL: Expected PowerLimit of 800W")
+	}
+	if s.Boot == nil || s.Boot.BootSourceOverrideMode != "UEFI" ||
+		!reflect.DeepEqual(s.Boot.BootOrder, []string{"Boot0003", "Boot0001"}) {
+		t.Errorf("FAIL: Expected UEFI boot settings, got %+v", s.Boot)
+	}
+}
+
+// Aggregator with four BMCs behind an AggregationService, one of which is
+// missing from the Systems collection.  All four should become nodes, in
+// AggregationSource order.
+func TestAggregatorDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncAggregator1())
+	ep := TestRedfishEPInitAggregator
+	ep.client = client
+	ep.GetRootInfo()
+
+	if ep.DiscInfo.LastStatus != DiscoverOK {
+		t.Fatalf("FAIL: bad LastStatus: %s", ep.DiscInfo.LastStatus)
+	}
+	if ep.NumSystems != 4 {
+		t.Errorf("FAIL: Expected 4 systems, got %d", ep.NumSystems)
+	}
+	tests := []struct {
+		oid string
+		id  string
+	}{
+		{"AF32C1_Self", "x0c0s16b0n0"},
+		{"0D8B4E_Self", "x0c0s16b0n1"},
+		{"71C0DE_Self", "x0c0s16b0n2"},
+		{"E4A912_Self", "x0c0s16b0n3"},
+	}
+	for i, test := range tests {
+		s, ok := ep.Systems.OIDs[test.oid]
+		if !ok {
+			t.Errorf("Testcase %d: FAIL: System %s not discovered",
+				i, test.oid)
+			continue
+		}
+		if s.LastStatus != DiscoverOK {
+			t.Errorf("Testcase %d: FAIL: bad LastStatus: %s",
+				i, s.LastStatus)
+		}
+		if s.ID != test.id {
+			t.Errorf("Testcase %d: FAIL: Expected ID %s, got %s",
+				i, test.id, s.ID)
+		}
+	}
+}
+
+// Make sure the Drives listed under each of the Intel node's Storage
+// collections are discovered as Drive components under the node.
+func TestDriveDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncIntel1())
+	ep := TestRedfishEPInitIntel
+	ep.client = client
+	ep.GetRootInfo()
+
+	s, ok := ep.Systems.OIDs["QSBP74304715"]
+	if !ok {
+		t.Fatalf("FAIL: System QSBP74304715 not discovered")
+	}
+	if s.Drives.Num != 16 || len(s.Drives.OIDs) != 16 {
+		t.Fatalf("FAIL: Expected 16 drives, got %d (%d)",
+			s.Drives.Num, len(s.Drives.OIDs))
+	}
+	tests := []struct {
+		oid     string
+		id      string
+		fruid   string
+		subtype string
+	}{{
+		"/redfish/v1/Systems/QSBP74304715/Storage/1/Drives/HDD1",
+		"x0c0s16b0n0g1k0",
+		"Drive.SAMSUNGMZ7LH3T8HMLT00005.S456NY0M400230HDD1",
+		"SSD",
+	}, {
+		"/redfish/v1/Systems/QSBP74304715/Storage/2/Drives/HDD8",
+		"x0c0s16b0n0g2k7",
+		"Drive.SAMSUNGMZ7LH3T8HMLT00005.S456NY0M4002382HDD8",
+		"",
+	}}
+	for i, test := range tests {
+		d, ok := s.Drives.OIDs[test.oid]
+		if !ok {
+			t.Errorf("Testcase %d: FAIL: Drive %s not found", i, test.oid)
+			continue
+		}
+		if d.LastStatus != DiscoverOK {
+			t.Errorf("Testcase %d: FAIL: Expected %s, got %s",
+				i, DiscoverOK, d.LastStatus)
+		}
+		if d.ID != test.id {
+			t.Errorf("Testcase %d: FAIL: Expected ID %s, got %s",
+				i, test.id, d.ID)
+		}
+		if d.FRUID != test.fruid {
+			t.Errorf("Testcase %d: FAIL: Expected FRUID %s, got %s",
+				i, test.fruid, d.FRUID)
+		}
+		if d.RedfishSubtype != test.subtype {
+			t.Errorf("Testcase %d: FAIL: Expected subtype '%s', got '%s'",
+				i, test.subtype, d.RedfishSubtype)
+		}
+		if d.DriveRF.CapacityBytes.String() != "4027323514880" {
+			t.Errorf("Testcase %d: FAIL: Bad CapacityBytes %s",
+				i, d.DriveRF.CapacityBytes)
+		}
+	}
+}
+
+func TestHSNAsicDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncCrayRCFabric())
+	ep := TestRedfishEPInitCrayRC
+	ep.client = client
+	ep.GetRootInfo()
+
+	if ep.DiscInfo.LastStatus != DiscoverOK {
+		t.Fatalf("FAIL: Bad LastStatus: %s", ep.DiscInfo.LastStatus)
+	}
+	a, ok := ep.HSNAsics.OIDs["/redfish/v1/Fabrics/HSN/Switches/Switch0"]
+	if !ok || ep.HSNAsics.Num != 1 {
+		t.Fatalf("FAIL: Expected 1 HSN ASIC, got %d", ep.HSNAsics.Num)
+	}
+	if a.LastStatus != DiscoverOK {
+		t.Errorf("FAIL: Expected %s, got %s", DiscoverOK, a.LastStatus)
+	}
+	if a.ID != "x0c0r16a0" || a.Type != xnametypes.HSNAsic.String() {
+		t.Errorf("FAIL: Bad ASIC ID/Type: %s/%s", a.ID, a.Type)
+	}
+	if a.FRUID != "HSNAsic.Cray.102325100.HA19350066" {
+		t.Errorf("FAIL: Bad ASIC FRUID: %s", a.FRUID)
+	}
+	if a.Ports.Num != 3 {
+		t.Fatalf("FAIL: Expected 3 ports, got %d", a.Ports.Num)
+	}
+	tests := []struct {
+		key   string
+		id    string
+		state string
+		flag  string
+	}{{
+		"0",
+		"x0c0r16a0l0",
+		base.StateOn.String(),
+		base.FlagOK.String(),
+	}, {
+		"1",
+		"x0c0r16a0l1",
+		base.StateOff.String(),
+		base.FlagOK.String(),
+	}, {
+		"17",
+		"x0c0r16a0l17",
+		base.StateOn.String(),
+		base.FlagWarning.String(),
+	}}
+	for i, test := range tests {
+		p, ok := a.Ports.OIDs[test.key]
+		if !ok {
+			t.Errorf("Testcase %d: FAIL: Port %s not found", i, test.key)
+			continue
+		}
+		if p.LastStatus != DiscoverOK {
+			t.Errorf("Testcase %d: FAIL: Expected %s, got %s",
+				i, DiscoverOK, p.LastStatus)
+		}
+		if p.ID != test.id || p.Type != xnametypes.HSNLink.String() {
+			t.Errorf("Testcase %d: FAIL: Bad ID/Type: %s/%s",
+				i, p.ID, p.Type)
+		}
+		if p.State != test.state || p.Flag != test.flag {
+			t.Errorf("Testcase %d: FAIL: Expected %s/%s, got %s/%s",
+				i, test.state, test.flag, p.State, p.Flag)
+		}
+	}
+}
+
+func TestNetworkDeviceFunctionDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncGBTNetworkAdapter())
+	ep := TestRedfishEPInitGBT
+	ep.client = client
+	ep.GetRootInfo()
+
+	s, ok := ep.Systems.OIDs["Self"]
+	if !ok {
+		t.Fatalf("FAIL: System Self not discovered")
+	}
+	na, ok := s.NetworkAdapters.OIDs["NIC0"]
+	if !ok {
+		t.Fatalf("FAIL: NetworkAdapter NIC0 not discovered")
+	}
+	if len(na.NetDevFuncsRF) != 3 {
+		t.Errorf("FAIL: Expected 3 NetworkDeviceFunctions, got %d",
+			len(na.NetDevFuncsRF))
+	}
+	// The first function duplicates Lan1 under EthernetInterfaces and the
+	// third has no MAC, so only the second should be added.
+	tests := []struct {
+		mac   string
+		count int
+	}{
+		{"b4:2e:99:b5:d7:11", 1},
+		{"0c:42:a1:5b:3e:21", 1},
+	}
+	for i, test := range tests {
+		count := 0
+		for _, ei := range s.EthNICInfo {
+			if ei.MACAddress == test.mac {
+				count++
+			}
+		}
+		if count != test.count {
+			t.Errorf("Testcase %d: FAIL: Expected %d EthNICInfo with %s, got %d",
+				i, test.count, test.mac, count)
+		}
+	}
+	if s.MACAddr == "0c:42:a1:5b:3e:21" {
+		t.Errorf("FAIL: NetworkDeviceFunction MAC used as main MAC")
+	}
+}
+
+func TestTrustedModuleDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncPRLT1())
+	ep := TestRedfishEPInitPRLT
+	ep.client = client
+	ep.GetRootInfo()
+
+	s, ok := ep.Systems.OIDs["1"]
+	if !ok {
+		t.Fatalf("FAIL: System 1 not discovered")
+	}
+	tms := s.SystemRF.TrustedModules
+	if len(tms) != 1 {
+		t.Fatalf("FAIL: Expected 1 TrustedModule, got %d", len(tms))
+	}
+	if tms[0].InterfaceType != "TPM2_0" || tms[0].FirmwareVersion != "73.64" {
+		t.Errorf("FAIL: Bad InterfaceType/FirmwareVersion: %s/%s",
+			tms[0].InterfaceType, tms[0].FirmwareVersion)
+	}
+	if tms[0].OEM == nil || tms[0].OEM.Hpe == nil ||
+		tms[0].OEM.Hpe.VendorName != "STMicro" {
+		t.Errorf("FAIL: Expected Hpe VendorName STMicro")
+	}
+	if tms[0].Status.State != "Enabled" {
+		t.Errorf("FAIL: Bad Status.State: %s", tms[0].Status.State)
+	}
+}
+
+func TestPDUCircuitDiscovery(t *testing.T) {
+	client := NewTestClient(NewRTFuncRtsPDU1())
+	ep := TestRedfishEPInitRtsCabPDUController
+	ep.client = client
+	ep.GetRootInfo()
+
+	pdu, ok := ep.RackPDUs.OIDs["1"]
+	if !ok {
+		t.Fatalf("FAIL: PDU 1 not discovered")
+	}
+	if pdu.LastStatus != DiscoverOK {
+		t.Errorf("FAIL: Bad PDU LastStatus: %s", pdu.LastStatus)
+	}
+	sensors := "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors"
+	if pdu.SensorsURL != sensors ||
+		pdu.MetricsURL != "/redfish/v1/PowerEquipment/RackPDUs/1/Metrics" {
+		t.Errorf("FAIL: Bad SensorsURL/MetricsURL: %s/%s",
+			pdu.SensorsURL, pdu.MetricsURL)
+	}
+	if len(pdu.Mains) != 1 || pdu.Mains[0].RedfishId != "AC1" ||
+		pdu.Mains[0].CircuitType != "Mains" ||
+		pdu.Mains[0].RatedCurrentAmps != "30" {
+		t.Fatalf("FAIL: Bad Mains: %+v", pdu.Mains)
+	}
+	expMains := []string{
+		"EnergySensor=" + sensors + "/EnergyMains",
+		"FrequencySensor=" + sensors + "/FrequencyMains",
+		"PowerSensor=" + sensors + "/PowerMains",
+		"PolyPhaseCurrentSensors.Line1=" + sensors + "/CurrentMainsL1",
+		"PolyPhaseCurrentSensors.Line2=" + sensors + "/CurrentMainsL2",
+	}
+	sensorList := func(ms []*MeteringSensor) []string {
+		refs := []string{}
+		for _, m := range ms {
+			refs = append(refs, m.Property+"="+m.DataSourceUri)
+		}
+		return refs
+	}
+	if got := sensorList(pdu.Mains[0].Sensors); !reflect.DeepEqual(got, expMains) {
+		t.Errorf("FAIL: Bad Mains sensors: %v", got)
+	}
+
+	// Branch C is listed but can't be read, so is skipped
+	if len(pdu.Branches) != 2 || pdu.Branches[0].RedfishId != "A" ||
+		pdu.Branches[1].RedfishId != "B" {
+		t.Fatalf("FAIL: Bad Branches: %+v", pdu.Branches)
+	}
+	outlets := "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets"
+	if !reflect.DeepEqual(pdu.Branches[0].Outlets,
+		[]string{outlets + "/A1", outlets + "/A2"}) ||
+		!reflect.DeepEqual(pdu.Branches[1].Outlets, []string{outlets + "/B1"}) {
+		t.Errorf("FAIL: Bad Branch outlets: %v %v",
+			pdu.Branches[0].Outlets, pdu.Branches[1].Outlets)
+	}
+	expBranchA := []string{
+		"CurrentSensor=" + sensors + "/CurrentA",
+		"PowerSensor=" + sensors + "/PowerA",
+		"PolyPhaseVoltageSensors.Line1ToNeutral=" + sensors + "/VoltageAL1N",
+	}
+	if got := sensorList(pdu.Branches[0].Sensors); !reflect.DeepEqual(got, expBranchA) {
+		t.Errorf("FAIL: Bad Branch A sensors: %v", got)
+	}
+
+	out, ok := pdu.Outlets.OIDs["B1"]
+	if !ok {
+		t.Fatalf("FAIL: Outlet B1 not discovered")
+	}
+	if out.BranchCircuit != "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/B" {
+		t.Errorf("FAIL: Bad BranchCircuit: %s", out.BranchCircuit)
+	}
+	expB1 := []string{
+		"CurrentSensor=" + sensors + "/CurrentB1",
+		"EnergySensor=" + sensors + "/EnergyB1",
+		"PowerSensor=" + sensors + "/PowerB1",
+		"VoltageSensor=" + sensors + "/VoltageB1",
+		"PolyPhaseCurrentSensors.Line1=" + sensors + "/CurrentB1",
+		"PolyPhasePowerSensors.Line2ToNeutral=" + sensors + "/PowerB1",
+		"PolyPhaseVoltageSensors.Line2ToNeutral=" + sensors + "/VoltageB1",
+	}
+	if got := sensorList(out.Sensors); !reflect.DeepEqual(got, expB1) {
+		t.Errorf("FAIL: Bad Outlet B1 sensors: %v", got)
+	}
+	if out.Sensors[2].ReadingUnits != "W" {
+		t.Errorf("FAIL: Bad PowerSensor ReadingUnits: %s",
+			out.Sensors[2].ReadingUnits)
+	}
+	// No sensor excerpts, nothing to meter
+	if a5 := pdu.Outlets.OIDs["A5"]; a5 == nil || len(a5.Sensors) != 0 {
+		t.Errorf("FAIL: Expected no sensors for Outlet A5")
+	}
 }
 
 // Check System, Manager, and Chassis.  Make sure status is OK, actions are
@@ -828,6 +1383,17 @@ func VerifyGetRootInfo(e *RedfishEP, v RedfishEPVerifyInfo) error {
 								}
 							}
 						}
+						if (pCtl.OEM.Lenovo == nil) != (vPCtl.OEM.Lenovo == nil) {
+							return fmt.Errorf("%s: Bad powerControl OEM Lenovo nil struct for '%s'",
+								sysId, s.ID)
+						}
+						if pCtl.OEM.Lenovo != nil && vPCtl.OEM.Lenovo.PowerLimit != nil {
+							if pCtl.OEM.Lenovo.PowerLimit == nil ||
+								*pCtl.OEM.Lenovo.PowerLimit != *vPCtl.OEM.Lenovo.PowerLimit {
+								return fmt.Errorf("%s: Bad powerControl OEM Lenovo PowerLimit %v != %v for '%s'",
+									sysId, pCtl.OEM.Lenovo.PowerLimit, vPCtl.OEM.Lenovo.PowerLimit, s.ID)
+							}
+						}
 					}
 					if len(pCtl.RelatedItem) != len(vPCtl.RelatedItem) {
 						return fmt.Errorf("%s: Bad powerControl RelatedItem mismatched array length '%s'",
@@ -7541,6 +8107,8 @@ const testPayloadIntelSystems_QSBP74304715_Storage_1_Drives_1 = `
       "Health" : "OK"
    },
    "CapacityBytes" : 4027323514880,
+   "Protocol" : "SATA",
+   "MediaType" : "SSD",
    "Model" : "SAMSUNG MZ7LH3T8HMLT-00005",
    "SerialNumber" : "S456NY0M400230-HDD1",
    "Oem" : {
@@ -15036,6 +15604,41 @@ func NewRTFuncRtsPDU1() RTFunc {
 				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Outlets_A3)),
 				Header: make(http.Header),
 			}
+		case "https://" + testFQDN + testPathRtsPDUPowerEquipment_RackPDUs_1_Mains:
+			return &http.Response{
+				StatusCode: 200,
+				// Send mock response for rpath
+				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Mains)),
+				Header: make(http.Header),
+			}
+		case "https://" + testFQDN + testPathRtsPDUPowerEquipment_RackPDUs_1_Mains_AC1:
+			return &http.Response{
+				StatusCode: 200,
+				// Send mock response for rpath
+				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Mains_AC1)),
+				Header: make(http.Header),
+			}
+		case "https://" + testFQDN + testPathRtsPDUPowerEquipment_RackPDUs_1_Branches:
+			return &http.Response{
+				StatusCode: 200,
+				// Send mock response for rpath
+				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches)),
+				Header: make(http.Header),
+			}
+		case "https://" + testFQDN + testPathRtsPDUPowerEquipment_RackPDUs_1_Branches_A:
+			return &http.Response{
+				StatusCode: 200,
+				// Send mock response for rpath
+				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches_A)),
+				Header: make(http.Header),
+			}
+		case "https://" + testFQDN + testPathRtsPDUPowerEquipment_RackPDUs_1_Branches_B:
+			return &http.Response{
+				StatusCode: 200,
+				// Send mock response for rpath
+				Body:   ioutil.NopCloser(bytes.NewBufferString(testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches_B)),
+				Header: make(http.Header),
+			}
 		default:
 			return &http.Response{
 				StatusCode: 404,
@@ -16498,3 +17101,1955 @@ const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Outlets_A3 = `
         },
         "VoltageType": "AC"
 }`
+
+const testPathRtsPDUPowerEquipment_RackPDUs_1_Mains = "/redfish/v1/PowerEquipment/RackPDUs/1/Mains"
+
+const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Mains = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Mains",
+        "@odata.type": "#CircuitCollection.CircuitCollection",
+        "Members": [
+                {
+                        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Mains/AC1"
+                }
+        ],
+        "Members@odata.count": 1,
+        "Name": "Power Inputs"
+}`
+
+const testPathRtsPDUPowerEquipment_RackPDUs_1_Mains_AC1 = "/redfish/v1/PowerEquipment/RackPDUs/1/Mains/AC1"
+
+const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Mains_AC1 = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Mains/AC1",
+        "@odata.type": "#Circuit.v1_0_0.Circuit",
+        "CircuitType": "Mains",
+        "EnergySensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/EnergyMains",
+                "Name": "Mains Energy",
+                "Reading": 325675,
+                "ReadingUnits": "kW.h"
+        },
+        "FrequencySensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/FrequencyMains",
+                "Name": "Mains Frequency",
+                "Reading": 60.0,
+                "ReadingUnits": "Hz"
+        },
+        "Id": "AC1",
+        "Name": "Mains Input Circuit",
+        "NominalVoltage": "AC200To240V",
+        "PhaseWiringType": "ThreePhase5Wire",
+        "PlugType": "NEMA_L21_30P",
+        "PolyPhaseCurrentSensors": {
+                "Line1": {
+                        "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/CurrentMainsL1",
+                        "Name": "Mains L1 Current",
+                        "Reading": 5.19,
+                        "ReadingUnits": "A"
+                },
+                "Line2": {
+                        "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/CurrentMainsL2",
+                        "Name": "Mains L2 Current",
+                        "Reading": 4.3,
+                        "ReadingUnits": "A"
+                }
+        },
+        "PowerSensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/PowerMains",
+                "Name": "Mains Power",
+                "Reading": 937.4,
+                "ReadingUnits": "W"
+        },
+        "RatedCurrentAmps": 30,
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        },
+        "VoltageType": "AC"
+}`
+
+const testPathRtsPDUPowerEquipment_RackPDUs_1_Branches = "/redfish/v1/PowerEquipment/RackPDUs/1/Branches"
+
+const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches",
+        "@odata.type": "#CircuitCollection.CircuitCollection",
+        "Members": [
+                {
+                        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/B"
+                },
+                {
+                        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/A"
+                },
+                {
+                        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/C"
+                }
+        ],
+        "Members@odata.count": 3,
+        "Name": "Branch Circuits"
+}`
+
+const testPathRtsPDUPowerEquipment_RackPDUs_1_Branches_A = "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/A"
+
+const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches_A = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/A",
+        "@odata.type": "#Circuit.v1_0_0.Circuit",
+        "CircuitType": "Branch",
+        "CurrentSensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/CurrentA",
+                "Name": "Branch A Current",
+                "Reading": 5.19,
+                "ReadingUnits": "A"
+        },
+        "Id": "A",
+        "Links": {
+                "Outlets": [
+                        {
+                                "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/A1"
+                        },
+                        {
+                                "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/A2"
+                        }
+                ]
+        },
+        "Name": "Branch Circuit A",
+        "NominalVoltage": "AC120V",
+        "PhaseWiringType": "TwoPhase3Wire",
+        "PolyPhaseVoltageSensors": {
+                "Line1ToNeutral": {
+                        "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/VoltageAL1N",
+                        "Name": "Branch A Voltage L1N",
+                        "Reading": 118.2,
+                        "ReadingUnits": "V"
+                }
+        },
+        "PowerSensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/PowerA",
+                "Name": "Branch A Power",
+                "Reading": 937.4,
+                "ReadingUnits": "W"
+        },
+        "RatedCurrentAmps": 16,
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        },
+        "VoltageType": "AC"
+}`
+
+const testPathRtsPDUPowerEquipment_RackPDUs_1_Branches_B = "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/B"
+
+const testPayloadRtsPDUPowerEquipment_RackPDUs_1_Branches_B = `
+{
+        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Branches/B",
+        "@odata.type": "#Circuit.v1_0_0.Circuit",
+        "CircuitType": "Branch",
+        "Id": "B",
+        "Name": "Branch Circuit B",
+        "Outlets": [
+                {
+                        "@odata.id": "/redfish/v1/PowerEquipment/RackPDUs/1/Outlets/B1"
+                }
+        ],
+        "PowerSensor": {
+                "DataSourceUri": "/redfish/v1/PowerEquipment/RackPDUs/1/Sensors/PowerB",
+                "Name": "Branch B Power",
+                "Reading": 228.3,
+                "ReadingUnits": "W"
+        },
+        "RatedCurrentAmps": 16,
+        "VoltageType": "AC"
+}`
+
+//
+// Mock Fabrics tree for CrayRC.  Anything not part of the HSN fabric is
+// passed on to the regular CrayRC1 mock.
+//
+
+func NewRTFuncCrayRCFabric() RTFunc {
+	crayRC1 := NewRTFuncCrayRC1()
+	payloads := map[string]string{
+		testPathCrayRC_redfish_v1: strings.Replace(testPayloadCrayRC_redfish_v1,
+			`"Registries": {`,
+			`"Fabrics": {
+                "@odata.id": "/redfish/v1/Fabrics"
+        },
+        "Registries": {`, 1),
+		"/redfish/v1/Fabrics":                               testPayloadCrayRCFabrics,
+		"/redfish/v1/Fabrics/HSN":                           testPayloadCrayRCFabricHSN,
+		"/redfish/v1/Fabrics/HSN/Switches":                  testPayloadCrayRCSwitches,
+		"/redfish/v1/Fabrics/HSN/Switches/Switch0":          testPayloadCrayRCSwitch0,
+		"/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports":    testPayloadCrayRCPorts,
+		"/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/0":  testPayloadCrayRCPort0,
+		"/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/1":  testPayloadCrayRCPort1,
+		"/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/17": testPayloadCrayRCPort17,
+	}
+	return func(req *http.Request) *http.Response {
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return crayRC1(req)
+	}
+}
+
+const testPayloadCrayRCFabrics = `
+{
+        "@odata.id": "/redfish/v1/Fabrics",
+        "@odata.type": "#FabricCollection.FabricCollection",
+        "Members": [
+                {
+                        "@odata.id": "/redfish/v1/Fabrics/HSN"
+                }
+        ],
+        "Members@odata.count": 1,
+        "Name": "Fabric Collection"
+}`
+
+const testPayloadCrayRCFabricHSN = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN",
+        "@odata.type": "#Fabric.v1_0_4.Fabric",
+        "FabricType": "Ethernet",
+        "Id": "HSN",
+        "Name": "HSN Fabric",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        },
+        "Switches": {
+                "@odata.id": "/redfish/v1/Fabrics/HSN/Switches"
+        }
+}`
+
+const testPayloadCrayRCSwitches = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches",
+        "@odata.type": "#SwitchCollection.SwitchCollection",
+        "Members": [
+                {
+                        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0"
+                }
+        ],
+        "Members@odata.count": 1,
+        "Name": "Switch Collection"
+}`
+
+const testPayloadCrayRCSwitch0 = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0",
+        "@odata.type": "#Switch.v1_2_0.Switch",
+        "Id": "Switch0",
+        "Manufacturer": "Cray",
+        "Model": "Rosetta",
+        "Name": "Switch0",
+        "PartNumber": "102325100",
+        "Ports": {
+                "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports"
+        },
+        "SerialNumber": "HA19350066",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        },
+        "SwitchType": "Ethernet"
+}`
+
+const testPayloadCrayRCPorts = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports",
+        "@odata.type": "#PortCollection.PortCollection",
+        "Members": [
+                {
+                        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/0"
+                },
+                {
+                        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/1"
+                },
+                {
+                        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/17"
+                }
+        ],
+        "Members@odata.count": 3,
+        "Name": "Port Collection"
+}`
+
+const testPayloadCrayRCPort0 = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/0",
+        "@odata.type": "#Port.v1_4_0.Port",
+        "CurrentSpeedGbps": 200,
+        "Id": "0",
+        "LinkState": "Enabled",
+        "LinkStatus": "LinkUp",
+        "Name": "Port 0",
+        "PortId": "0",
+        "PortProtocol": "Ethernet",
+        "PortType": "InterswitchPort",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+
+const testPayloadCrayRCPort1 = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/1",
+        "@odata.type": "#Port.v1_4_0.Port",
+        "CurrentSpeedGbps": 0,
+        "Id": "1",
+        "LinkState": "Enabled",
+        "LinkStatus": "LinkDown",
+        "Name": "Port 1",
+        "PortId": "1",
+        "PortProtocol": "Ethernet",
+        "PortType": "InterswitchPort",
+        "Status": {
+                "Health": "OK",
+                "State": "Enabled"
+        }
+}`
+
+const testPayloadCrayRCPort17 = `
+{
+        "@odata.id": "/redfish/v1/Fabrics/HSN/Switches/Switch0/Ports/17",
+        "@odata.type": "#Port.v1_4_0.Port",
+        "CurrentSpeedGbps": 100,
+        "Id": "17",
+        "LinkState": "Enabled",
+        "LinkStatus": "LinkUp",
+        "Name": "Port 17",
+        "PortId": "17",
+        "PortProtocol": "Ethernet",
+        "PortType": "DownstreamPort",
+        "Status": {
+                "Health": "Warning",
+                "State": "Enabled"
+        }
+}`
+
+//
+// Mock GBT NetworkAdapter with NetworkDeviceFunctions.  Anything else is
+// passed on to the regular GBT1 mock.
+//
+
+func NewRTFuncGBTNetworkAdapter() RTFunc {
+	gbt1 := NewRTFuncGBT1()
+	payloads := map[string]string{
+		testPathGBT_chassis_self_network_adapters:              testPayloadGBTNetworkAdapters,
+		"/redfish/v1/Chassis/Self/NetworkAdapters/NIC0":        testPayloadGBTNetworkAdapterNIC0,
+		"/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs":   testPayloadGBTNIC0NDFs,
+		"/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/0": testPayloadGBTNIC0NDF0,
+		"/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/1": testPayloadGBTNIC0NDF1,
+		"/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/2": testPayloadGBTNIC0NDF2,
+	}
+	return func(req *http.Request) *http.Response {
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return gbt1(req)
+	}
+}
+
+const testPayloadGBTNetworkAdapters = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters",
+  "@odata.type": "#NetworkAdapterCollection.NetworkAdapterCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "NetworkAdapter Collection"
+}`
+
+const testPayloadGBTNetworkAdapterNIC0 = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0",
+  "@odata.type": "#NetworkAdapter.v1_3_0.NetworkAdapter",
+  "Id": "NIC0",
+  "Manufacturer": "Mellanox Technologies",
+  "Model": "ConnectX-6 Dx",
+  "Name": "NIC0",
+  "NetworkDeviceFunctions": {
+    "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs"
+  },
+  "PartNumber": "MCX623106AN-CDAT",
+  "SerialNumber": "MT2104X08374"
+}`
+
+const testPayloadGBTNIC0NDFs = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs",
+  "@odata.type": "#NetworkDeviceFunctionCollection.NetworkDeviceFunctionCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/0"
+    },
+    {
+      "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/1"
+    },
+    {
+      "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/2"
+    }
+  ],
+  "Members@odata.count": 3,
+  "Name": "NetworkDeviceFunction Collection"
+}`
+
+const testPayloadGBTNIC0NDF0 = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/0",
+  "@odata.type": "#NetworkDeviceFunction.v1_3_3.NetworkDeviceFunction",
+  "Ethernet": {
+    "MACAddress": "B4:2E:99:B5:D7:11",
+    "PermanentMACAddress": "B4:2E:99:B5:D7:11"
+  },
+  "Id": "0",
+  "Name": "NetworkDeviceFunction 0",
+  "NetDevFuncType": "Ethernet"
+}`
+
+const testPayloadGBTNIC0NDF1 = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/1",
+  "@odata.type": "#NetworkDeviceFunction.v1_3_3.NetworkDeviceFunction",
+  "DeviceEnabled": true,
+  "Ethernet": {
+    "MACAddress": "0C:42:A1:5B:3E:21",
+    "PermanentMACAddress": "0C:42:A1:5B:3E:21"
+  },
+  "Id": "1",
+  "Name": "NetworkDeviceFunction 1",
+  "NetDevFuncType": "Ethernet"
+}`
+
+const testPayloadGBTNIC0NDF2 = `
+{
+  "@odata.id": "/redfish/v1/Chassis/Self/NetworkAdapters/NIC0/NDFs/2",
+  "@odata.type": "#NetworkDeviceFunction.v1_3_3.NetworkDeviceFunction",
+  "Id": "2",
+  "Name": "NetworkDeviceFunction 2",
+  "NetDevFuncType": "FibreChannel"
+}`
+
+//
+// Mock Lenovo ThinkSystem XCC.  The System's ResetActionInfo is missing, as
+// seen on some XCC firmware, so the inline ResetTypes must be used.
+//
+
+func NewRTFuncLenovo1() RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1":                                   testPayloadLenovo_redfish_v1,
+		"/redfish/v1/Systems":                           testPayloadLenovo_systems,
+		"/redfish/v1/Systems/1":                         testPayloadLenovo_systems_1,
+		"/redfish/v1/Systems/1/EthernetInterfaces":      testPayloadLenovo_systems_1_ethernet_interfaces,
+		"/redfish/v1/Systems/1/EthernetInterfaces/NIC1": testPayloadLenovo_systems_1_ethernet_interfaces_nic1,
+		"/redfish/v1/Systems/1/Processors":              testPayloadLenovo_systems_1_processors,
+		"/redfish/v1/Systems/1/Processors/1":            testPayloadLenovo_systems_1_processors_1,
+		"/redfish/v1/Systems/1/Memory":                  testPayloadLenovo_systems_1_memory,
+		"/redfish/v1/Systems/1/Memory/1":                testPayloadLenovo_systems_1_memory_1,
+		"/redfish/v1/Managers":                          testPayloadLenovo_managers,
+		"/redfish/v1/Managers/1":                        testPayloadLenovo_managers_1,
+		"/redfish/v1/Managers/1/ResetActionInfo":        testPayloadLenovo_managers_1_reset_action_info,
+		"/redfish/v1/Managers/1/EthernetInterfaces":     testPayloadLenovo_managers_1_ethernet_interfaces,
+		"/redfish/v1/Managers/1/EthernetInterfaces/NIC": testPayloadLenovo_managers_1_ethernet_interfaces_nic,
+		"/redfish/v1/Chassis":                           testPayloadLenovo_chassis,
+		"/redfish/v1/Chassis/1":                         testPayloadLenovo_chassis_1,
+		"/redfish/v1/Chassis/1/Power":                   testPayloadLenovo_chassis_1_power,
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+const testPayloadLenovo_redfish_v1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ServiceRoot.ServiceRoot",
+  "@odata.id": "/redfish/v1/",
+  "@odata.type": "#ServiceRoot.v1_5_0.ServiceRoot",
+  "AccountService": {
+    "@odata.id": "/redfish/v1/AccountService"
+  },
+  "Chassis": {
+    "@odata.id": "/redfish/v1/Chassis"
+  },
+  "EventService": {
+    "@odata.id": "/redfish/v1/EventService"
+  },
+  "Id": "RootService",
+  "Links": {
+    "Sessions": {
+      "@odata.id": "/redfish/v1/SessionService/Sessions"
+    }
+  },
+  "Managers": {
+    "@odata.id": "/redfish/v1/Managers"
+  },
+  "Name": "Root Service",
+  "Product": "Lenovo XClarity Controller",
+  "RedfishVersion": "1.6.0",
+  "SessionService": {
+    "@odata.id": "/redfish/v1/SessionService"
+  },
+  "Systems": {
+    "@odata.id": "/redfish/v1/Systems"
+  },
+  "UUID": "3f5b8e2a-5d62-11e9-8647-d663bd873d93",
+  "UpdateService": {
+    "@odata.id": "/redfish/v1/UpdateService"
+  },
+  "Vendor": "Lenovo"
+}`
+
+const testPayloadLenovo_systems = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ComputerSystemCollection.ComputerSystemCollection",
+  "@odata.id": "/redfish/v1/Systems",
+  "@odata.type": "#ComputerSystemCollection.ComputerSystemCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "ComputerSystemCollection"
+}`
+
+const testPayloadLenovo_systems_1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ComputerSystem.ComputerSystem",
+  "@odata.id": "/redfish/v1/Systems/1",
+  "@odata.type": "#ComputerSystem.v1_7_0.ComputerSystem",
+  "Actions": {
+    "#ComputerSystem.Reset": {
+      "@Redfish.ActionInfo": "/redfish/v1/Systems/1/ResetActionInfo",
+      "ResetType@Redfish.AllowableValues": [
+        "On",
+        "Nmi",
+        "GracefulShutdown",
+        "GracefulRestart",
+        "ForceOn",
+        "ForceOff",
+        "ForceRestart"
+      ],
+      "target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset",
+      "title": "Reset"
+    }
+  },
+  "AssetTag": "",
+  "BiosVersion": "IVE160I-3.22",
+  "Boot": {
+    "BootSourceOverrideEnabled": "Disabled",
+    "BootSourceOverrideTarget": "None"
+  },
+  "Description": "This resource is used to represent a computer system for a Redfish implementation.",
+  "EthernetInterfaces": {
+    "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces"
+  },
+  "HostName": "sr650-node1",
+  "Id": "1",
+  "IndicatorLED": "Off",
+  "Links": {
+    "Chassis": [
+      {
+        "@odata.id": "/redfish/v1/Chassis/1"
+      }
+    ],
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/1"
+      }
+    ]
+  },
+  "Manufacturer": "Lenovo",
+  "Memory": {
+    "@odata.id": "/redfish/v1/Systems/1/Memory"
+  },
+  "MemorySummary": {
+    "Status": {
+      "Health": "OK",
+      "HealthRollup": "OK",
+      "State": "Enabled"
+    },
+    "TotalSystemMemoryGiB": 32
+  },
+  "Model": "7X06CTO1WW",
+  "Name": "ComputerSystem",
+  "PartNumber": "SB27A20107",
+  "PowerState": "On",
+  "ProcessorSummary": {
+    "Count": 1,
+    "Model": "Intel(R) Xeon(R) Gold 6130 CPU @ 2.10GHz",
+    "Status": {
+      "Health": "OK",
+      "HealthRollup": "OK",
+      "State": "Enabled"
+    }
+  },
+  "Processors": {
+    "@odata.id": "/redfish/v1/Systems/1/Processors"
+  },
+  "SKU": "7X06CTO1WW",
+  "SerialNumber": "J30012AB",
+  "Status": {
+    "Health": "OK",
+    "HealthRollup": "OK",
+    "State": "Enabled"
+  },
+  "SystemType": "Physical",
+  "UUID": "A9F3AB2E-5C3D-11E9-8B2B-7ED30A5F0C4A"
+}`
+
+const testPayloadLenovo_systems_1_ethernet_interfaces = `
+{
+  "@odata.context": "/redfish/v1/$metadata#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces",
+  "@odata.type": "#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/NIC1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "EthernetInterfaceCollection"
+}`
+
+const testPayloadLenovo_systems_1_ethernet_interfaces_nic1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#EthernetInterface.EthernetInterface",
+  "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/NIC1",
+  "@odata.type": "#EthernetInterface.v1_4_1.EthernetInterface",
+  "Description": "Onboard 1Gb Ethernet port 1",
+  "Id": "NIC1",
+  "InterfaceEnabled": true,
+  "MACAddress": "08:94:ef:4a:7c:1e",
+  "Name": "NIC1",
+  "PermanentMACAddress": "08:94:ef:4a:7c:1e",
+  "Status": {
+    "Health": "OK",
+    "State": "Enabled"
+  }
+}`
+
+const testPayloadLenovo_systems_1_processors = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ProcessorCollection.ProcessorCollection",
+  "@odata.id": "/redfish/v1/Systems/1/Processors",
+  "@odata.type": "#ProcessorCollection.ProcessorCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Processors/1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "ProcessorCollection"
+}`
+
+const testPayloadLenovo_systems_1_processors_1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#Processor.Processor",
+  "@odata.id": "/redfish/v1/Systems/1/Processors/1",
+  "@odata.type": "#Processor.v1_3_1.Processor",
+  "Id": "1",
+  "InstructionSet": "x86-64",
+  "Manufacturer": "Intel(R) Corporation",
+  "MaxSpeedMHz": 4000,
+  "Model": "Intel(R) Xeon(R) Gold 6130 CPU @ 2.10GHz",
+  "Name": "Processor 1",
+  "ProcessorArchitecture": "x86",
+  "ProcessorId": {
+    "EffectiveFamily": "0xb3",
+    "EffectiveModel": "0x55",
+    "IdentificationRegisters": "0x00050654bfebfbff",
+    "Step": "0x4",
+    "VendorId": "GenuineIntel"
+  },
+  "ProcessorType": "CPU",
+  "Socket": "CPU 1",
+  "Status": {
+    "Health": "OK",
+    "State": "Enabled"
+  },
+  "TotalCores": 16,
+  "TotalThreads": 32
+}`
+
+const testPayloadLenovo_systems_1_memory = `
+{
+  "@odata.context": "/redfish/v1/$metadata#MemoryCollection.MemoryCollection",
+  "@odata.id": "/redfish/v1/Systems/1/Memory",
+  "@odata.type": "#MemoryCollection.MemoryCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Memory/1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "MemoryCollection"
+}`
+
+const testPayloadLenovo_systems_1_memory_1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#Memory.Memory",
+  "@odata.id": "/redfish/v1/Systems/1/Memory/1",
+  "@odata.type": "#Memory.v1_6_0.Memory",
+  "BaseModuleType": "RDIMM",
+  "CapacityMiB": 32768,
+  "DataWidthBits": 64,
+  "DeviceLocator": "DIMM 1",
+  "Id": "1",
+  "Manufacturer": "Samsung",
+  "MemoryDeviceType": "DDR4",
+  "MemoryLocation": {
+    "Channel": 0,
+    "MemoryController": 0,
+    "Slot": 1,
+    "Socket": 1
+  },
+  "Name": "DIMM 1",
+  "OperatingSpeedMhz": 2666,
+  "PartNumber": "M393A4K40CB2-CTD",
+  "SerialNumber": "3B1F2C4D",
+  "Status": {
+    "Health": "OK",
+    "State": "Enabled"
+  }
+}`
+
+const testPayloadLenovo_managers = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ManagerCollection.ManagerCollection",
+  "@odata.id": "/redfish/v1/Managers",
+  "@odata.type": "#ManagerCollection.ManagerCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Managers/1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "ManagerCollection"
+}`
+
+const testPayloadLenovo_managers_1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#Manager.Manager",
+  "@odata.id": "/redfish/v1/Managers/1",
+  "@odata.type": "#Manager.v1_5_0.Manager",
+  "Actions": {
+    "#Manager.Reset": {
+      "@Redfish.ActionInfo": "/redfish/v1/Managers/1/ResetActionInfo",
+      "target": "/redfish/v1/Managers/1/Actions/Manager.Reset",
+      "title": "Reset"
+    }
+  },
+  "DateTime": "2024-03-11T09:21:07+00:00",
+  "DateTimeLocalOffset": "+00:00",
+  "Description": "This resource is used to represent a management subsystem for a Redfish implementation.",
+  "EthernetInterfaces": {
+    "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces"
+  },
+  "FirmwareVersion": "CDI3B6I 6.20",
+  "Id": "1",
+  "Links": {
+    "ManagerForChassis": [
+      {
+        "@odata.id": "/redfish/v1/Chassis/1"
+      }
+    ],
+    "ManagerForServers": [
+      {
+        "@odata.id": "/redfish/v1/Systems/1"
+      }
+    ]
+  },
+  "ManagerType": "BMC",
+  "Model": "Lenovo XClarity Controller",
+  "Name": "XCC",
+  "PowerState": "On",
+  "Status": {
+    "Health": "OK",
+    "State": "Enabled"
+  },
+  "UUID": "3F5B8E2A-5D62-11E9-8647-D663BD873D93"
+}`
+
+const testPayloadLenovo_managers_1_reset_action_info = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ActionInfo.ActionInfo",
+  "@odata.id": "/redfish/v1/Managers/1/ResetActionInfo",
+  "@odata.type": "#ActionInfo.v1_1_0.ActionInfo",
+  "Id": "ResetActionInfo",
+  "Name": "Reset Action Info",
+  "Parameters": [
+    {
+      "AllowableValues": [
+        "GracefulRestart",
+        "ForceRestart"
+      ],
+      "DataType": "String",
+      "Name": "ResetType",
+      "Required": true
+    }
+  ]
+}`
+
+const testPayloadLenovo_managers_1_ethernet_interfaces = `
+{
+  "@odata.context": "/redfish/v1/$metadata#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces",
+  "@odata.type": "#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces/NIC"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "EthernetInterfaceCollection"
+}`
+
+const testPayloadLenovo_managers_1_ethernet_interfaces_nic = `
+{
+  "@odata.context": "/redfish/v1/$metadata#EthernetInterface.EthernetInterface",
+  "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces/NIC",
+  "@odata.type": "#EthernetInterface.v1_4_1.EthernetInterface",
+  "Description": "Management Network Interface",
+  "HostName": "XCC-7X06-J30012AB",
+  "Id": "NIC",
+  "InterfaceEnabled": true,
+  "MACAddress": "08:94:ef:4a:7c:21",
+  "Name": "Manager Ethernet Interface",
+  "PermanentMACAddress": "08:94:ef:4a:7c:21",
+  "Status": {
+    "Health": "OK",
+    "State": "Enabled"
+  }
+}`
+
+const testPayloadLenovo_chassis = `
+{
+  "@odata.context": "/redfish/v1/$metadata#ChassisCollection.ChassisCollection",
+  "@odata.id": "/redfish/v1/Chassis",
+  "@odata.type": "#ChassisCollection.ChassisCollection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/1"
+    }
+  ],
+  "Members@odata.count": 1,
+  "Name": "ChassisCollection"
+}`
+
+const testPayloadLenovo_chassis_1 = `
+{
+  "@odata.context": "/redfish/v1/$metadata#Chassis.Chassis",
+  "@odata.id": "/redfish/v1/Chassis/1",
+  "@odata.type": "#Chassis.v1_10_0.Chassis",
+  "AssetTag": "",
+  "ChassisType": "RackMount",
+  "Id": "1",
+  "IndicatorLED": "Off",
+  "Links": {
+    "ComputerSystems": [
+      {
+        "@odata.id": "/redfish/v1/Systems/1"
+      }
+    ],
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/1"
+      }
+    ]
+  },
+  "Manufacturer": "Lenovo",
+  "Model": "7X06CTO1WW",
+  "Name": "Chassis",
+  "PartNumber": "SB27A20107",
+  "Power": {
+    "@odata.id": "/redfish/v1/Chassis/1/Power"
+  },
+  "PowerState": "On",
+  "SKU": "7X06CTO1WW",
+  "SerialNumber": "J30012AB",
+  "Status": {
+    "Health": "OK",
+    "HealthRollup": "OK",
+    "State": "Enabled"
+  }
+}`
+
+const testPayloadLenovo_chassis_1_power = `
+{
+  "@odata.context": "/redfish/v1/$metadata#Power.Power",
+  "@odata.id": "/redfish/v1/Chassis/1/Power",
+  "@odata.type": "#Power.v1_5_0.Power",
+  "Id": "Power",
+  "Name": "Power",
+  "PowerControl": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/1/Power#/PowerControl/0",
+      "MemberId": "0",
+      "Name": "Server Power Control",
+      "Oem": {
+        "Lenovo": {
+          "@odata.type": "#LenovoPower.v1_0_0.PowerControl",
+          "PowerUtilization": {
+            "CapacityMaxAC": 1040,
+            "CapacityMaxDC": 958,
+            "CapacityMinAC": 402,
+            "CapacityMinDC": 371,
+            "EnablePowerCapping": false,
+            "LimitMode": "AC"
+          }
+        }
+      },
+      "PowerCapacityWatts": 1100,
+      "PowerConsumedWatts": 187,
+      "PowerLimit": {
+        "LimitException": "NoAction",
+        "LimitInWatts": null
+      },
+      "RelatedItem": [
+        {
+          "@odata.id": "/redfish/v1/Systems/1"
+        },
+        {
+          "@odata.id": "/redfish/v1/Chassis/1"
+        }
+      ]
+    }
+  ]
+}`
+
+//
+// Mock SuperMicro X12.  The System's ResetActionInfo has no AllowableValues
+// so the inline ones must be kept, and Manager.Reset has no ResetType.
+// The storage enclosure Chassis must not become a second NodeEnclosure.
+//
+
+func NewRTFuncSupermicro1() RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1":                                      testPayloadSMC_redfish_v1,
+		"/redfish/v1/Systems":                              testPayloadSMC_systems,
+		"/redfish/v1/Systems/1":                            testPayloadSMC_systems_1,
+		"/redfish/v1/Systems/1/ResetActionInfo":            testPayloadSMC_systems_1_reset_action_info,
+		"/redfish/v1/Systems/1/EthernetInterfaces":         testPayloadSMC_systems_1_ethernet_interfaces,
+		"/redfish/v1/Systems/1/EthernetInterfaces/1":       testPayloadSMC_systems_1_ethernet_interfaces_1,
+		"/redfish/v1/Systems/1/Processors":                 testPayloadSMC_systems_1_processors,
+		"/redfish/v1/Systems/1/Processors/1":               testPayloadSMC_systems_1_processors_1,
+		"/redfish/v1/Systems/1/Processors/2":               testPayloadSMC_systems_1_processors_2,
+		"/redfish/v1/Systems/1/Memory":                     testPayloadSMC_systems_1_memory,
+		"/redfish/v1/Systems/1/Memory/1":                   testPayloadSMC_systems_1_memory_1,
+		"/redfish/v1/Systems/1/Memory/2":                   testPayloadSMC_systems_1_memory_2,
+		"/redfish/v1/Managers":                             testPayloadSMC_managers,
+		"/redfish/v1/Managers/1":                           testPayloadSMC_managers_1,
+		"/redfish/v1/Managers/1/EthernetInterfaces":        testPayloadSMC_managers_1_ethernet_interfaces,
+		"/redfish/v1/Managers/1/EthernetInterfaces/1":      testPayloadSMC_managers_1_ethernet_interfaces_1,
+		"/redfish/v1/Chassis":                              testPayloadSMC_chassis,
+		"/redfish/v1/Chassis/1":                            testPayloadSMC_chassis_1,
+		"/redfish/v1/Chassis/1/Power":                      testPayloadSMC_chassis_1_power,
+		"/redfish/v1/Chassis/HA-RAID.0.StorageEnclosure.0": testPayloadSMC_chassis_storage_enclosure,
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+const testPayloadSMC_redfish_v1 = `
+{
+  "@odata.type": "#ServiceRoot.v1_5_2.ServiceRoot",
+  "@odata.id": "/redfish/v1",
+  "Id": "ServiceRoot",
+  "Name": "Root Service",
+  "RedfishVersion": "1.11.0",
+  "UUID": "00000000-0000-0000-0000-3cecefc8a1b2",
+  "Systems": {
+    "@odata.id": "/redfish/v1/Systems"
+  },
+  "Chassis": {
+    "@odata.id": "/redfish/v1/Chassis"
+  },
+  "Managers": {
+    "@odata.id": "/redfish/v1/Managers"
+  },
+  "Tasks": {
+    "@odata.id": "/redfish/v1/TaskService"
+  },
+  "SessionService": {
+    "@odata.id": "/redfish/v1/SessionService"
+  },
+  "AccountService": {
+    "@odata.id": "/redfish/v1/AccountService"
+  },
+  "EventService": {
+    "@odata.id": "/redfish/v1/EventService"
+  },
+  "UpdateService": {
+    "@odata.id": "/redfish/v1/UpdateService"
+  },
+  "Links": {
+    "Sessions": {
+      "@odata.id": "/redfish/v1/SessionService/Sessions"
+    }
+  },
+  "Oem": {},
+  "Product": "SYS-620U-TNR",
+  "Vendor": "Supermicro"
+}`
+
+const testPayloadSMC_systems = `
+{
+  "@odata.type": "#ComputerSystemCollection.ComputerSystemCollection",
+  "@odata.id": "/redfish/v1/Systems",
+  "Name": "Computer System Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadSMC_systems_1 = `
+{
+  "@odata.type": "#ComputerSystem.v1_13_0.ComputerSystem",
+  "@odata.id": "/redfish/v1/Systems/1",
+  "Id": "1",
+  "Name": "System",
+  "Description": "Description of server",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "SerialNumber": "S452719X1A05123",
+  "PartNumber": "SYS-620U-TNR",
+  "SystemType": "Physical",
+  "BiosVersion": "1.4a",
+  "Manufacturer": "Supermicro",
+  "Model": "SYS-620U-TNR",
+  "SKU": "To be filled by O.E.M.",
+  "UUID": "C4D5E6F7-1A2B-11EC-8000-3CECEFC8A1B4",
+  "ProcessorSummary": {
+    "Count": 2,
+    "Model": "Intel(R) Xeon(R) processor",
+    "Status": {
+      "State": "Enabled",
+      "Health": "OK",
+      "HealthRollup": "OK"
+    }
+  },
+  "MemorySummary": {
+    "TotalSystemMemoryGiB": 32,
+    "MemoryMirroring": "System",
+    "Status": {
+      "State": "Enabled",
+      "Health": "OK",
+      "HealthRollup": "OK"
+    }
+  },
+  "IndicatorLED": "Off",
+  "PowerState": "On",
+  "Boot": {
+    "BootSourceOverrideEnabled": "Disabled",
+    "BootSourceOverrideMode": "UEFI",
+    "BootSourceOverrideTarget": "None",
+    "BootOrder": [
+      "Boot0003",
+      "Boot0001"
+    ]
+  },
+  "Processors": {
+    "@odata.id": "/redfish/v1/Systems/1/Processors"
+  },
+  "Memory": {
+    "@odata.id": "/redfish/v1/Systems/1/Memory"
+  },
+  "EthernetInterfaces": {
+    "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces"
+  },
+  "Links": {
+    "Chassis": [
+      {
+        "@odata.id": "/redfish/v1/Chassis/1"
+      }
+    ],
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/1"
+      }
+    ]
+  },
+  "Actions": {
+    "#ComputerSystem.Reset": {
+      "target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset",
+      "@Redfish.ActionInfo": "/redfish/v1/Systems/1/ResetActionInfo",
+      "ResetType@Redfish.AllowableValues": [
+        "On",
+        "ForceOff",
+        "GracefulShutdown",
+        "GracefulRestart",
+        "ForceRestart",
+        "Nmi",
+        "ForceOn"
+      ]
+    }
+  },
+  "Oem": {
+    "Supermicro": {
+      "@odata.type": "#SmcSystemExtensions.v1_0_0.System"
+    }
+  }
+}`
+
+const testPayloadSMC_systems_1_reset_action_info = `
+{
+  "@odata.type": "#ActionInfo.v1_1_0.ActionInfo",
+  "@odata.id": "/redfish/v1/Systems/1/ResetActionInfo",
+  "Id": "ResetActionInfo",
+  "Name": "Reset Action Info",
+  "Parameters": [
+    {
+      "Name": "ResetType",
+      "Required": true,
+      "DataType": "String"
+    }
+  ]
+}`
+
+const testPayloadSMC_systems_1_ethernet_interfaces = `
+{
+  "@odata.type": "#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces",
+  "Name": "Ethernet Interface Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/1"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadSMC_systems_1_ethernet_interfaces_1 = `
+{
+  "@odata.type": "#EthernetInterface.v1_8_0.EthernetInterface",
+  "@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/1",
+  "Id": "1",
+  "Name": "Ethernet Interface",
+  "Description": "Ethernet Interface Port 1",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "InterfaceEnabled": true,
+  "PermanentMACAddress": "3c:ec:ef:c8:a1:b0",
+  "MACAddress": "3c:ec:ef:c8:a1:b0",
+  "SpeedMbps": 10000,
+  "FullDuplex": true
+}`
+
+const testPayloadSMC_systems_1_processors = `
+{
+  "@odata.type": "#ProcessorCollection.ProcessorCollection",
+  "@odata.id": "/redfish/v1/Systems/1/Processors",
+  "Name": "Processor Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Processors/1"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Processors/2"
+    }
+  ],
+  "Members@odata.count": 2
+}`
+
+const testPayloadSMC_systems_1_processors_1 = `
+{
+  "@odata.type": "#Processor.v1_11_0.Processor",
+  "@odata.id": "/redfish/v1/Systems/1/Processors/1",
+  "Id": "1",
+  "Name": "Processor",
+  "Socket": "CPU1",
+  "Manufacturer": "Intel(R) Corporation",
+  "Model": "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz",
+  "ProcessorType": "CPU",
+  "ProcessorArchitecture": "x86",
+  "InstructionSet": "x86-64",
+  "MaxSpeedMHz": 4000,
+  "TotalCores": 32,
+  "TotalThreads": 64,
+  "ProcessorId": {
+    "VendorId": "GenuineIntel",
+    "IdentificationRegisters": "0x000606A6",
+    "EffectiveFamily": "0x6",
+    "EffectiveModel": "0x6A",
+    "Step": "0x6"
+  },
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  }
+}`
+
+const testPayloadSMC_systems_1_processors_2 = `
+{
+  "@odata.type": "#Processor.v1_11_0.Processor",
+  "@odata.id": "/redfish/v1/Systems/1/Processors/2",
+  "Id": "2",
+  "Name": "Processor",
+  "Socket": "CPU2",
+  "Manufacturer": "Intel(R) Corporation",
+  "Model": "Intel(R) Xeon(R) Gold 6338 CPU @ 2.00GHz",
+  "ProcessorType": "CPU",
+  "ProcessorArchitecture": "x86",
+  "InstructionSet": "x86-64",
+  "MaxSpeedMHz": 4000,
+  "TotalCores": 32,
+  "TotalThreads": 64,
+  "ProcessorId": {
+    "VendorId": "GenuineIntel",
+    "IdentificationRegisters": "0x000606A6",
+    "EffectiveFamily": "0x6",
+    "EffectiveModel": "0x6A",
+    "Step": "0x6"
+  },
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  }
+}`
+
+const testPayloadSMC_systems_1_memory = `
+{
+  "@odata.type": "#MemoryCollection.MemoryCollection",
+  "@odata.id": "/redfish/v1/Systems/1/Memory",
+  "Name": "Memory Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Memory/1"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/1/Memory/2"
+    }
+  ],
+  "Members@odata.count": 2
+}`
+
+const testPayloadSMC_systems_1_memory_1 = `
+{
+  "@odata.type": "#Memory.v1_10_0.Memory",
+  "@odata.id": "/redfish/v1/Systems/1/Memory/1",
+  "Id": "1",
+  "Name": "Memory",
+  "Description": "DIMM Object",
+  "MemoryDeviceType": "DDR4",
+  "BaseModuleType": "RDIMM",
+  "CapacityMiB": 32768,
+  "DataWidthBits": 64,
+  "BusWidthBits": 72,
+  "Manufacturer": "Micron",
+  "SerialNumber": "E2A3B4C5",
+  "PartNumber": "36ASF4G72PZ-3G2E1",
+  "OperatingSpeedMhz": 3200,
+  "DeviceLocator": "P1-DIMMA1",
+  "MemoryLocation": {
+    "Socket": 1,
+    "MemoryController": 1,
+    "Channel": 1,
+    "Slot": 1
+  },
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  }
+}`
+
+const testPayloadSMC_systems_1_memory_2 = `
+{
+  "@odata.type": "#Memory.v1_10_0.Memory",
+  "@odata.id": "/redfish/v1/Systems/1/Memory/2",
+  "Id": "2",
+  "Name": "Memory",
+  "Description": "DIMM Object",
+  "DeviceLocator": "P1-DIMMB1",
+  "MemoryLocation": {
+    "Socket": 1,
+    "MemoryController": 1,
+    "Channel": 2,
+    "Slot": 1
+  },
+  "Status": {
+    "State": "Absent"
+  }
+}`
+
+const testPayloadSMC_managers = `
+{
+  "@odata.type": "#ManagerCollection.ManagerCollection",
+  "@odata.id": "/redfish/v1/Managers",
+  "Name": "Manager Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Managers/1"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadSMC_managers_1 = `
+{
+  "@odata.type": "#Manager.v1_11_0.Manager",
+  "@odata.id": "/redfish/v1/Managers/1",
+  "Id": "1",
+  "Name": "Manager",
+  "Description": "BMC",
+  "ManagerType": "BMC",
+  "UUID": "00000000-0000-0000-0000-3CECEFC8A1B2",
+  "Model": "ASPEED",
+  "FirmwareVersion": "01.01.14",
+  "DateTime": "2023-06-14T17:23:01Z",
+  "DateTimeLocalOffset": "+00:00",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "EthernetInterfaces": {
+    "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces"
+  },
+  "Links": {
+    "ManagerForServers": [
+      {
+        "@odata.id": "/redfish/v1/Systems/1"
+      }
+    ],
+    "ManagerForChassis": [
+      {
+        "@odata.id": "/redfish/v1/Chassis/1"
+      }
+    ]
+  },
+  "Actions": {
+    "#Manager.Reset": {
+      "target": "/redfish/v1/Managers/1/Actions/Manager.Reset"
+    }
+  }
+}`
+
+const testPayloadSMC_managers_1_ethernet_interfaces = `
+{
+  "@odata.type": "#EthernetInterfaceCollection.EthernetInterfaceCollection",
+  "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces",
+  "Name": "Ethernet Network Interface Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces/1"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadSMC_managers_1_ethernet_interfaces_1 = `
+{
+  "@odata.type": "#EthernetInterface.v1_8_0.EthernetInterface",
+  "@odata.id": "/redfish/v1/Managers/1/EthernetInterfaces/1",
+  "Id": "1",
+  "Name": "Manager Ethernet Interface",
+  "Description": "BMC Network Interface",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "InterfaceEnabled": true,
+  "PermanentMACAddress": "3c:ec:ef:c8:a1:b2",
+  "MACAddress": "3c:ec:ef:c8:a1:b2",
+  "HostName": "bmc-s452719x1a05123"
+}`
+
+const testPayloadSMC_chassis = `
+{
+  "@odata.type": "#ChassisCollection.ChassisCollection",
+  "@odata.id": "/redfish/v1/Chassis",
+  "Name": "Chassis Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/1"
+    },
+    {
+      "@odata.id": "/redfish/v1/Chassis/HA-RAID.0.StorageEnclosure.0"
+    }
+  ],
+  "Members@odata.count": 2
+}`
+
+const testPayloadSMC_chassis_1 = `
+{
+  "@odata.type": "#Chassis.v1_14_0.Chassis",
+  "@odata.id": "/redfish/v1/Chassis/1",
+  "Id": "1",
+  "Name": "Computer System Chassis",
+  "ChassisType": "RackMount",
+  "Manufacturer": "Supermicro",
+  "Model": "CSE-829UTS-R1K62P-T",
+  "SerialNumber": "C8290LK31AB0123",
+  "PartNumber": "CSE-829UTS-R1K62P-T",
+  "PowerState": "On",
+  "IndicatorLED": "Off",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK",
+    "HealthRollup": "OK"
+  },
+  "Power": {
+    "@odata.id": "/redfish/v1/Chassis/1/Power"
+  },
+  "Links": {
+    "ComputerSystems": [
+      {
+        "@odata.id": "/redfish/v1/Systems/1"
+      }
+    ],
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/1"
+      }
+    ]
+  }
+}`
+
+const testPayloadSMC_chassis_1_power = `
+{
+  "@odata.type": "#Power.v1_7_0.Power",
+  "@odata.id": "/redfish/v1/Chassis/1/Power",
+  "Id": "Power",
+  "Name": "Power",
+  "PowerControl": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/1/Power#/PowerControl/0",
+      "MemberId": "0",
+      "Name": "System Power Control",
+      "PowerConsumedWatts": 241,
+      "PowerCapacityWatts": 1600,
+      "PowerLimit": {
+        "LimitInWatts": 800,
+        "LimitException": "LogEventOnly",
+        "CorrectionInMs": 50
+      },
+      "RelatedItem": [
+        {
+          "@odata.id": "/redfish/v1/Systems/1"
+        }
+      ],
+      "Status": {
+        "State": "Enabled",
+        "Health": "OK"
+      },
+      "Oem": {
+        "Supermicro": {
+          "@odata.type": "#SmcPowerControlExtensions.v1_0_0.PowerControl"
+        }
+      }
+    }
+  ]
+}`
+
+const testPayloadSMC_chassis_storage_enclosure = `
+{
+  "@odata.type": "#Chassis.v1_14_0.Chassis",
+  "@odata.id": "/redfish/v1/Chassis/HA-RAID.0.StorageEnclosure.0",
+  "Id": "HA-RAID.0.StorageEnclosure.0",
+  "Name": "Internal Enclosure 0",
+  "ChassisType": "RackMount",
+  "Manufacturer": "Supermicro",
+  "Model": "BPN-SAS3-826EL1",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "Links": {
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/1"
+      }
+    ]
+  }
+}`
+
+//
+// Mock aggregator (e.g. an enclosure manager) that exposes the
+// ComputerSystems of four BMCs through an AggregationService.  The Systems
+// collection is missing 71C0DE_Self, which is only linked from its
+// AggregationSource.
+//
+
+func NewRTFuncAggregator1() RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1":                                              testPayloadAgg_redfish_v1,
+		"/redfish/v1/Systems":                                      testPayloadAgg_systems,
+		"/redfish/v1/Chassis":                                      testPayloadAgg_chassis,
+		"/redfish/v1/Chassis/Enclosure":                            testPayloadAgg_chassis_enclosure,
+		"/redfish/v1/Managers":                                     testPayloadAgg_managers,
+		"/redfish/v1/Managers/Self":                                testPayloadAgg_managers_self,
+		"/redfish/v1/AggregationService":                           testPayloadAgg_aggregation_service,
+		"/redfish/v1/AggregationService/Aggregates":                testPayloadAgg_aggregates,
+		"/redfish/v1/AggregationService/Aggregates/1":              testPayloadAgg_aggregates_1,
+		"/redfish/v1/AggregationService/AggregationSources":        testPayloadAgg_sources,
+		"/redfish/v1/AggregationService/AggregationSources/1":      fmt.Sprintf(testPayloadAgg_source, "1", "11", "AF32C1"),
+		"/redfish/v1/AggregationService/AggregationSources/2":      fmt.Sprintf(testPayloadAgg_source, "2", "12", "0D8B4E"),
+		"/redfish/v1/AggregationService/AggregationSources/3":      fmt.Sprintf(testPayloadAgg_source, "3", "13", "71C0DE"),
+		"/redfish/v1/AggregationService/AggregationSources/4":      fmt.Sprintf(testPayloadAgg_source, "4", "14", "E4A912"),
+		"/redfish/v1/AggregationService/ConnectionMethods":         testPayloadAgg_connection_methods,
+		"/redfish/v1/AggregationService/ConnectionMethods/Redfish": testPayloadAgg_connection_methods_redfish,
+	}
+	for _, id := range []string{"AF32C1", "0D8B4E", "71C0DE", "E4A912"} {
+		payloads["/redfish/v1/Systems/"+id+"_Self"] =
+			fmt.Sprintf(testPayloadAgg_system, id)
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+const testPayloadAgg_redfish_v1 = `
+{
+  "@odata.type": "#ServiceRoot.v1_9_0.ServiceRoot",
+  "@odata.id": "/redfish/v1",
+  "Id": "RootService",
+  "Name": "Root Service",
+  "RedfishVersion": "1.15.0",
+  "UUID": "92384634-2938-2342-8820-489239905423",
+  "Systems": {
+    "@odata.id": "/redfish/v1/Systems"
+  },
+  "Chassis": {
+    "@odata.id": "/redfish/v1/Chassis"
+  },
+  "Managers": {
+    "@odata.id": "/redfish/v1/Managers"
+  },
+  "AggregationService": {
+    "@odata.id": "/redfish/v1/AggregationService"
+  }
+}`
+
+const testPayloadAgg_systems = `
+{
+  "@odata.type": "#ComputerSystemCollection.ComputerSystemCollection",
+  "@odata.id": "/redfish/v1/Systems",
+  "Name": "Computer System Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Systems/AF32C1_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/0D8B4E_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/E4A912_Self"
+    }
+  ],
+  "Members@odata.count": 3
+}`
+
+const testPayloadAgg_system = `
+{
+  "@odata.type": "#ComputerSystem.v1_16_0.ComputerSystem",
+  "@odata.id": "/redfish/v1/Systems/%[1]s_Self",
+  "Id": "%[1]s_Self",
+  "Name": "System %[1]s",
+  "SystemType": "Physical",
+  "Manufacturer": "Contoso",
+  "Model": "3500",
+  "SerialNumber": "%[1]s",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "PowerState": "On",
+  "ProcessorSummary": {
+    "Count": 2,
+    "Model": "Contoso C-2400"
+  },
+  "MemorySummary": {
+    "TotalSystemMemoryGiB": 256
+  },
+  "Actions": {
+    "#ComputerSystem.Reset": {
+      "target": "/redfish/v1/Systems/%[1]s_Self/Actions/ComputerSystem.Reset",
+      "ResetType@Redfish.AllowableValues": [
+        "On",
+        "ForceOff",
+        "GracefulShutdown",
+        "ForceRestart"
+      ]
+    }
+  },
+  "Links": {
+    "Chassis": [
+      {
+        "@odata.id": "/redfish/v1/Chassis/Enclosure"
+      }
+    ],
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/Self"
+      }
+    ]
+  }
+}`
+
+const testPayloadAgg_chassis = `
+{
+  "@odata.type": "#ChassisCollection.ChassisCollection",
+  "@odata.id": "/redfish/v1/Chassis",
+  "Name": "Chassis Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Chassis/Enclosure"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadAgg_chassis_enclosure = `
+{
+  "@odata.type": "#Chassis.v1_21_0.Chassis",
+  "@odata.id": "/redfish/v1/Chassis/Enclosure",
+  "Id": "Enclosure",
+  "Name": "Enclosure",
+  "ChassisType": "RackMount",
+  "Manufacturer": "Contoso",
+  "Model": "3500-E",
+  "SerialNumber": "E3500A00042",
+  "PowerState": "On",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "Links": {
+    "ManagedBy": [
+      {
+        "@odata.id": "/redfish/v1/Managers/Self"
+      }
+    ]
+  }
+}`
+
+const testPayloadAgg_managers = `
+{
+  "@odata.type": "#ManagerCollection.ManagerCollection",
+  "@odata.id": "/redfish/v1/Managers",
+  "Name": "Manager Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/Managers/Self"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadAgg_managers_self = `
+{
+  "@odata.type": "#Manager.v1_15_0.Manager",
+  "@odata.id": "/redfish/v1/Managers/Self",
+  "Id": "Self",
+  "Name": "Enclosure Manager",
+  "ManagerType": "BMC",
+  "Model": "Contoso EM",
+  "FirmwareVersion": "2.1.0",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "Actions": {
+    "#Manager.Reset": {
+      "target": "/redfish/v1/Managers/Self/Actions/Manager.Reset",
+      "ResetType@Redfish.AllowableValues": [
+        "ForceRestart"
+      ]
+    }
+  }
+}`
+
+const testPayloadAgg_aggregation_service = `
+{
+  "@odata.type": "#AggregationService.v1_0_1.AggregationService",
+  "@odata.id": "/redfish/v1/AggregationService",
+  "Id": "AggregationService",
+  "Name": "Aggregation Service",
+  "ServiceEnabled": true,
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "Aggregates": {
+    "@odata.id": "/redfish/v1/AggregationService/Aggregates"
+  },
+  "AggregationSources": {
+    "@odata.id": "/redfish/v1/AggregationService/AggregationSources"
+  },
+  "ConnectionMethods": {
+    "@odata.id": "/redfish/v1/AggregationService/ConnectionMethods"
+  }
+}`
+
+const testPayloadAgg_aggregates = `
+{
+  "@odata.type": "#AggregateCollection.AggregateCollection",
+  "@odata.id": "/redfish/v1/AggregationService/Aggregates",
+  "Name": "Aggregate Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/AggregationService/Aggregates/1"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadAgg_aggregates_1 = `
+{
+  "@odata.type": "#Aggregate.v1_0_1.Aggregate",
+  "@odata.id": "/redfish/v1/AggregationService/Aggregates/1",
+  "Id": "1",
+  "Name": "Enclosure Systems",
+  "ElementsCount": 5,
+  "Elements": [
+    {
+      "@odata.id": "/redfish/v1/Systems/E4A912_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/AF32C1_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/0D8B4E_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Systems/71C0DE_Self"
+    },
+    {
+      "@odata.id": "/redfish/v1/Chassis/Enclosure"
+    }
+  ]
+}`
+
+const testPayloadAgg_sources = `
+{
+  "@odata.type": "#AggregationSourceCollection.AggregationSourceCollection",
+  "@odata.id": "/redfish/v1/AggregationService/AggregationSources",
+  "Name": "Aggregation Source Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/AggregationService/AggregationSources/4"
+    },
+    {
+      "@odata.id": "/redfish/v1/AggregationService/AggregationSources/1"
+    },
+    {
+      "@odata.id": "/redfish/v1/AggregationService/AggregationSources/3"
+    },
+    {
+      "@odata.id": "/redfish/v1/AggregationService/AggregationSources/2"
+    }
+  ],
+  "Members@odata.count": 4
+}`
+
+const testPayloadAgg_source = `
+{
+  "@odata.type": "#AggregationSource.v1_2_0.AggregationSource",
+  "@odata.id": "/redfish/v1/AggregationService/AggregationSources/%[1]s",
+  "Id": "%[1]s",
+  "Name": "Aggregation Source %[1]s",
+  "HostName": "https://10.1.0.%[2]s",
+  "Status": {
+    "State": "Enabled",
+    "Health": "OK"
+  },
+  "Links": {
+    "ConnectionMethod": {
+      "@odata.id": "/redfish/v1/AggregationService/ConnectionMethods/Redfish"
+    },
+    "ResourcesAccessed": [
+      {
+        "@odata.id": "/redfish/v1/Systems/%[3]s_Self"
+      }
+    ]
+  }
+}`
+
+const testPayloadAgg_connection_methods = `
+{
+  "@odata.type": "#ConnectionMethodCollection.ConnectionMethodCollection",
+  "@odata.id": "/redfish/v1/AggregationService/ConnectionMethods",
+  "Name": "Connection Method Collection",
+  "Members": [
+    {
+      "@odata.id": "/redfish/v1/AggregationService/ConnectionMethods/Redfish"
+    }
+  ],
+  "Members@odata.count": 1
+}`
+
+const testPayloadAgg_connection_methods_redfish = `
+{
+  "@odata.type": "#ConnectionMethod.v1_0_0.ConnectionMethod",
+  "@odata.id": "/redfish/v1/AggregationService/ConnectionMethods/Redfish",
+  "Id": "Redfish",
+  "Name": "Redfish Connection Method",
+  "ConnectionMethodType": "Redfish",
+  "ConnectionMethodVariant": "Contoso"
+}`
+
+const testPayloadThermal = `{
+	"@odata.id": "/redfish/v1/Chassis/Self/Thermal",
+	"Id": "Thermal",
+	"Temperatures": [{
+		"@odata.id": "/redfish/v1/Chassis/Self/Thermal#/Temperatures/0",
+		"MemberId": "0",
+		"Name": "CPU1 Temp",
+		"SensorNumber": 12,
+		"PhysicalContext": "CPU",
+		"ReadingCelsius": 45,
+		"UpperThresholdCritical": 95,
+		"UpperThresholdFatal": 100,
+		"Status": {"State": "Enabled", "Health": "OK"}
+	}, {
+		"@odata.id": "/redfish/v1/Chassis/Self/Thermal#/Temperatures/1",
+		"MemberId": "1",
+		"Name": "CPU2 Temp",
+		"ReadingCelsius": null,
+		"Status": {"State": "Absent"}
+	}],
+	"Fans": [{
+		"@odata.id": "/redfish/v1/Chassis/Self/Thermal#/Fans/0",
+		"MemberId": "0",
+		"FanName": "FAN1",
+		"PhysicalContext": "SystemBoard",
+		"Reading": 5400,
+		"ReadingUnits": "RPM",
+		"LowerThresholdCritical": 600,
+		"Status": {"State": "Enabled", "Health": "OK"}
+	}]
+}`
+
+func TestChassisThermal(t *testing.T) {
+	defer SetDiscoverThermal(false)
+
+	ep := TestRedfishEPInitAggregator
+	ep.client = NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.String() == "https://"+testFQDN+"/redfish/v1/Chassis/Self/Thermal" {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(testPayloadThermal)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	})
+	sensorNum := 12
+	critical, fatal, fanCritical := 95.0, 100.0, 600.0
+	expected := []*ThermalSensorInfo{{
+		Kind:                   ThermalSensorTemperature,
+		RedfishURL:             "/redfish/v1/Chassis/Self/Thermal#/Temperatures/0",
+		MemberId:               "0",
+		Name:                   "CPU1 Temp",
+		SensorNumber:           &sensorNum,
+		PhysicalContext:        "CPU",
+		ReadingUnits:           "Cel",
+		State:                  "Enabled",
+		UpperThresholdCritical: &critical,
+		UpperThresholdFatal:    &fatal,
+	}, {
+		Kind:                   ThermalSensorFan,
+		RedfishURL:             "/redfish/v1/Chassis/Self/Thermal#/Fans/0",
+		MemberId:               "0",
+		Name:                   "FAN1",
+		PhysicalContext:        "SystemBoard",
+		ReadingUnits:           "RPM",
+		State:                  "Enabled",
+		LowerThresholdCritical: &fanCritical,
+	}}
+
+	tests := []struct {
+		enabled    bool
+		thermalOID string
+		expSensors []*ThermalSensorInfo
+	}{
+		{false, "/redfish/v1/Chassis/Self/Thermal", nil},
+		{true, "", nil},
+		{true, "/redfish/v1/Chassis/Other/Thermal", nil},
+		{true, "/redfish/v1/Chassis/Self/Thermal", expected},
+	}
+	for i, test := range tests {
+		SetDiscoverThermal(test.enabled)
+		c := NewEpChassis(&ep, ResourceID{"/redfish/v1/Chassis/Self"}, 0)
+		c.ChassisRF.Thermal.Oid = test.thermalOID
+		c.discoverThermal()
+		if !reflect.DeepEqual(c.Sensors, test.expSensors) {
+			t.Errorf("Testcase %d: FAIL: Expected sensors %v, got %v",
+				i, test.expSensors, c.Sensors)
+		}
+	}
+}
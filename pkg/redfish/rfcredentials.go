@@ -0,0 +1,70 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Fallback credentials
+//
+// During credential standardization not every BMC has been moved to the
+// new credentials yet, so an endpoint can be given a prioritized list of
+// other credentials to try when its own are rejected with a 401.  The
+// ones that worked become the endpoint's credentials for the rest of
+// discovery, and their Source is recorded in DiscoveryInfo so endpoints
+// still running on fallback credentials can be found.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+type RedfishCredential struct {
+	Source   string // Where they came from, e.g. a secure store key
+	User     string
+	Password string
+}
+
+// Set the credentials to try, in order, if the endpoint's own are rejected.
+// Once fallback credentials have worked they are usually stored as the
+// endpoint's own, so the endpoint still counts as using a fallback as long
+// as its credentials match one of these.
+func (ep *RedfishEP) SetFallbackCredentials(creds []RedfishCredential) {
+	ep.fallbackCreds = creds
+}
+
+// True if the endpoint rejected the credentials used for the last
+// discovery attempt.
+func (ep *RedfishEP) AuthFailed() bool {
+	return ep.authFailed
+}
+
+// Source of the fallback credentials the endpoint is using, or "" if it is
+// using its own.
+func (ep *RedfishEP) fallbackSource() string {
+	if ep.authFailed {
+		return ""
+	}
+	for _, cred := range ep.fallbackCreds {
+		if cred.User == ep.User && cred.Password == ep.Password {
+			return cred.Source
+		}
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestFallbackCredentials(t *testing.T) {
+	fallbacks := []RedfishCredential{
+		{Source: "old-default", User: "root", Password: "initial0"},
+		{Source: "new-default", User: "admin", Password: "standard"},
+	}
+	tests := []struct {
+		user       string // endpoint's own credentials
+		password   string
+		goodPass   string // password the BMC accepts
+		expUser    string
+		expSource  string
+		expFailed  bool
+		expNumAuth int // number of ServiceRoot GETs, i.e. attempts
+	}{
+		{"root", "secret", "secret", "root", "", false, 1},
+		{"root", "secret", "standard", "admin", "new-default", false, 3},
+		{"root", "secret", "nothing", "root", "", true, 3},
+		// Running on fallback credentials that were stored as its own
+		{"admin", "standard", "standard", "admin", "new-default", false, 1},
+		// Own credentials equal a rejected fallback - not tried twice
+		{"root", "initial0", "standard", "admin", "new-default", false, 2},
+	}
+	for i, test := range tests {
+		numRoot := 0
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			body := `{"Members": []}`
+			if req.URL.Path == "/redfish/v1" {
+				numRoot++
+				body = `{"Chassis": {"@odata.id": "/redfish/v1/Chassis"}}`
+			} else if _, pw, _ := req.BasicAuth(); pw != test.goodPass {
+				return &http.Response{
+					StatusCode: 401,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+					Header:     make(http.Header),
+				}
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+				Header:     make(http.Header),
+			}
+		})
+		ep, err := NewRedfishEp(&RedfishEPDescription{
+			ID:       "x0c0s0b0",
+			Type:     "NodeBMC",
+			FQDN:     "x0c0s0b0",
+			Enabled:  true,
+			User:     test.user,
+			Password: test.password,
+		})
+		if err != nil {
+			t.Fatalf("Testcase %d: FAIL: NewRedfishEp: %s", i, err)
+		}
+		ep.client = client
+		ep.SetFallbackCredentials(fallbacks)
+		ep.GetRootInfo()
+		if ep.AuthFailed() != test.expFailed {
+			t.Errorf("Testcase %d: FAIL: Expected AuthFailed %t",
+				i, test.expFailed)
+		}
+		if ep.User != test.expUser {
+			t.Errorf("Testcase %d: FAIL: Expected User %s, got %s",
+				i, test.expUser, ep.User)
+		}
+		if ep.DiscInfo.CredentialSource != test.expSource {
+			t.Errorf("Testcase %d: FAIL: Expected CredentialSource '%s', got '%s'",
+				i, test.expSource, ep.DiscInfo.CredentialSource)
+		}
+		if numRoot != test.expNumAuth {
+			t.Errorf("Testcase %d: FAIL: Expected %d attempts, got %d",
+				i, test.expNumAuth, numRoot)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Discovery drivers
+//
+// Endpoints are normally discovered over Redfish by GetRootInfo().  A
+// DiscoveryDriver is another way of filling in the same RedfishEP for
+// devices without Redfish, e.g. PDUs that only speak SNMP, so everything
+// after discovery (components, inventory, etc.) works the same for them.
+//
+// Drivers are registered by name.  An endpoint registered with a driver's
+// name as its Protocol is always discovered with that driver.  Otherwise
+// drivers are used only if the endpoint's VendorProfile lists them in
+// FallbackDrivers, and only if its Redfish ServiceRoot can't be reached at
+// all.  Fallbacks are tried in order and the first that succeeds wins.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+type DiscoveryDriver interface {
+	// Contact the device and fill in ep's components as Redfish phase 1
+	// discovery would, i.e. with a LastStatus of VerifyingData.  Phase 2
+	// is then run as usual.  An error means the device couldn't be
+	// discovered this way.
+	Discover(ep *RedfishEP) error
+}
+
+// Protocol of endpoints discovered over Redfish, the default.
+const ProtocolRedfish = "Redfish"
+
+var discoveryDrivers = struct {
+	sync.RWMutex
+	drivers map[string]DiscoveryDriver
+}{drivers: map[string]DiscoveryDriver{
+	SNMPDriverName: &SNMPDriver{},
+	IPMIDriverName: &IPMIDriver{},
+}}
+
+// Add a DiscoveryDriver under the given name.  Names are
+// case-insensitive.
+func RegisterDiscoveryDriver(name string, d DiscoveryDriver) error {
+	if name == "" || d == nil {
+		return fmt.Errorf("discovery driver needs a name and an implementation")
+	}
+	discoveryDrivers.Lock()
+	defer discoveryDrivers.Unlock()
+	if strings.EqualFold(name, ProtocolRedfish) {
+		return fmt.Errorf("discovery driver can't be named %s", name)
+	}
+	for n := range discoveryDrivers.drivers {
+		if strings.EqualFold(n, name) {
+			return fmt.Errorf("duplicate discovery driver %s", name)
+		}
+	}
+	discoveryDrivers.drivers[name] = d
+	return nil
+}
+
+// Get the DiscoveryDriver with the given name, nil if there is none.
+func GetDiscoveryDriver(name string) DiscoveryDriver {
+	_, d := lookupDiscoveryDriver(name)
+	return d
+}
+
+// Get the DiscoveryDriver with the given name and the name it was
+// registered under.
+func lookupDiscoveryDriver(name string) (string, DiscoveryDriver) {
+	discoveryDrivers.RLock()
+	defer discoveryDrivers.RUnlock()
+	for n, d := range discoveryDrivers.drivers {
+		if strings.EqualFold(n, name) {
+			return n, d
+		}
+	}
+	return "", nil
+}
+
+// Fill in the endpoint with the named driver, starting from no
+// components.  The error is recorded in DiscInfo.
+func (ep *RedfishEP) runDiscoveryDriver(name string) error {
+	d := GetDiscoveryDriver(name)
+	if d == nil {
+		errlog.Printf("%s: unknown discovery driver %s", ep.ID, name)
+		return fmt.Errorf("unknown discovery driver %s", name)
+	}
+	ep.Chassis = EpChassisSet{}
+	ep.Managers = EpManagers{}
+	ep.Systems = EpSystems{}
+	ep.RackPDUs = EpPDUs{}
+	ep.NumChassis, ep.NumManagers = 0, 0
+	ep.NumSystems, ep.NumRackPDUs = 0, 0
+	if err := d.Discover(ep); err != nil {
+		errlog.Printf("%s: %s discovery failed: %s", ep.ID, name, err)
+		ep.addDiscoveryError(name, 0, err)
+		return err
+	}
+	return nil
+}
+
+// Discover an endpoint that was registered with a Protocol other than
+// Redfish.  A failure is reported as HTTPsGetFailed, like an unreachable
+// Redfish service.
+func (ep *RedfishEP) discoverWithProtocolDriver() {
+	if err := ep.runDiscoveryDriver(ep.Protocol); err != nil {
+		ep.DiscInfo.UpdateLastStatusWithTS(HTTPsGetFailed)
+		return
+	}
+	ep.DiscInfo.Driver = ep.Protocol
+	ep.discoverLocalPhase2()
+}
+
+// Try the endpoint's fallback drivers after its Redfish ServiceRoot
+// couldn't be read.  LastStatus stays HTTPsGetFailed if none of them work.
+func (ep *RedfishEP) discoverWithFallbackDrivers() {
+	for _, name := range ep.fallbackDrivers {
+		errlog.Printf("%s: no Redfish service, trying %s", ep.ID, name)
+		if err := ep.runDiscoveryDriver(name); err != nil {
+			continue
+		}
+		// The failed Redfish GET is expected for these.
+		ep.DiscInfo.Errors = nil
+		ep.DiscInfo.Driver = name
+		ep.discoverLocalPhase2()
+		return
+	}
+	ep.DiscInfo.UpdateLastStatusWithTS(HTTPsGetFailed)
+}
@@ -27,10 +27,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"maps"
 	"net/http"
 	"path"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -65,34 +68,41 @@ const (
 
 	StoreFailed             = "StoreFailed"
 	UnexpectedErrorPreStore = "UnexpectedErrorPreStore"
+	DiscoveryHeld           = "DiscoveryHeld" // Not stored, pending confirmation
 )
 
 // These are types of structures in rfendpoints that are built upon
 // the underlying Redfish type of the same name.
 const (
-	ServiceRootType       = "ServiceRoot"
-	ChassisType           = "Chassis"
-	ComputerSystemType    = "ComputerSystem"
-	EthernetInterfaceType = "EthernetInterface"
-	ManagerType           = "Manager"
-	MemoryType            = "Memory"
-	ProcessorType         = "Processor"
-	DriveType             = "Drive"
-	StorageGroupType      = "StorageGroup"
-	PowerSupplyType       = "PowerSupply"
-	PowerType             = "Power"
-	NodeAccelRiserType    = "GPUSubsystem"
-	AssemblyType          = "Assembly"
-	HpeDeviceType         = "HpeDevice"
-	OutletType            = "Outlet"
-	PDUType               = "PowerDistribution"
-	NetworkAdapterType    = "NetworkAdapter"
-	AccountServiceType    = "AccountService"
-	EventServiceType      = "EventService"
-	LogServiceType        = "LogService"
-	SessionServiceType    = "SessionService"
-	TaskServiceType       = "TaskService"
-	UpdateServiceType     = "UpdateService"
+	ServiceRootType        = "ServiceRoot"
+	ChassisType            = "Chassis"
+	ComputerSystemType     = "ComputerSystem"
+	EthernetInterfaceType  = "EthernetInterface"
+	ManagerType            = "Manager"
+	MemoryType             = "Memory"
+	ProcessorType          = "Processor"
+	DriveType              = "Drive"
+	StorageGroupType       = "StorageGroup"
+	PowerSupplyType        = "PowerSupply"
+	PowerType              = "Power"
+	NodeAccelRiserType     = "GPUSubsystem"
+	AssemblyType           = "Assembly"
+	HpeDeviceType          = "HpeDevice"
+	OutletType             = "Outlet"
+	PDUType                = "PowerDistribution"
+	NetworkAdapterType     = "NetworkAdapter"
+	FabricType             = "Fabric"
+	SwitchType             = "Switch"
+	FabricAdapterType      = "FabricAdapter"
+	PortType               = "Port"
+	AccountServiceType     = "AccountService"
+	EventServiceType       = "EventService"
+	LogServiceType         = "LogService"
+	SessionServiceType     = "SessionService"
+	TaskServiceType        = "TaskService"
+	UpdateServiceType      = "UpdateService"
+	TelemetryServiceType   = "TelemetryService"
+	CertificateServiceType = "CertificateService"
 )
 
 // Redfish object subtypes, i.e. {type-name}Type,
@@ -159,6 +169,14 @@ const MaxFanout int = 1000
 var ErrRFDiscFQDNMissing = errors.New("FQDN unexpectedly empty string")
 var ErrRFDiscURLNotFound = errors.New("URL request returned 404: Not Found")
 var ErrRFDiscILOLicenseReq = errors.New("iLO License Required")
+var ErrRFDiscResponseTooLarge = errors.New("response body exceeds maximum size")
+var ErrRFDiscUnauthorized = errors.New("URL request returned 401: Unauthorized")
+var ErrRFNoMetricReports = errors.New("no MetricReportDefinitions")
+var ErrRFNoEventSubscriptions = errors.New("no EventService Subscriptions")
+var ErrRFEventSubscriptionURI = errors.New("no URI for new event subscription")
+var ErrRFNoCertificateAction = errors.New("no CertificateService action target")
+var ErrRFNoAccountService = errors.New("no AccountService")
+var ErrRFNoAccount = errors.New("no AccountService account for the user")
 
 /////////////////////////////////////////////////////////////////////////////
 //
@@ -198,6 +216,7 @@ type RawRedfishEP struct {
 	IPAddr         string `json:"IPAddress"`
 	RediscOnUpdate *bool  `json:"RediscoverOnUpdate"`
 	TemplateID     string `json:"TemplateID"`
+	Protocol       string `json:"Protocol"`
 }
 
 // String function to redact passwords from any kind of output
@@ -237,6 +256,7 @@ func (rrep RawRedfishEP) String() string {
 		fmt.Fprintf(buf, "RediscOnUpdate: %t, ", *rrep.RediscOnUpdate)
 	}
 	fmt.Fprintf(buf, "TemplateID: %s, ", rrep.TemplateID)
+	fmt.Fprintf(buf, "Protocol: %s, ", rrep.Protocol)
 	fmt.Fprintf(buf, "}")
 	return buf.String()
 }
@@ -266,16 +286,29 @@ func NewRedfishEPDescription(rep *RawRedfishEP) (*RedfishEPDescription, error) {
 	// Figure out the FQDN to contact the endpoint at.
 	// Use Hostname and Domain fields first.
 	//
+	// Addresses are canonicalized by NormalizeHostPort.  A port may
+	// be given with the Hostname or FQDN, and is kept only in the FQDN.
+	//
 	if rep.Domain != "" {
-		ep.Domain = strings.Trim(rep.Domain, "./ ")
+		ep.Domain = strings.ToLower(strings.Trim(rep.Domain, "./ "))
+		if _, port, err := NormalizeHostPort(ep.Domain); err != nil ||
+			port != "" || GetIPAddressString(ep.Domain) != "" {
+			err := fmt.Errorf("Domain is not a valid domain name: '%s'",
+				rep.Domain)
+			return nil, err
+		}
 	}
 	hostIsIP := false
+	port := ""
 	if rep.Hostname != "" {
-		ep.Hostname = strings.Trim(rep.Hostname, "./ ")
-		ipHost := GetIPAddressString(ep.Hostname)
-		if ipHost != "" {
+		host, hostPort, err := NormalizeHostPort(strings.Trim(rep.Hostname, "./ "))
+		if err != nil {
+			return nil, fmt.Errorf("Hostname is not valid: %s", err)
+		}
+		ep.Hostname = host
+		port = hostPort
+		if GetIPAddressString(host) != "" {
 			hostIsIP = true
-			ep.Hostname = ipHost
 		} else {
 			splitHost := strings.SplitN(ep.Hostname, ".", 2)
 			if len(splitHost) > 1 {
@@ -286,31 +319,44 @@ func NewRedfishEPDescription(rep *RawRedfishEP) (*RedfishEPDescription, error) {
 	}
 	// If a FQDN was given instead, use those to fill in the hostname
 	// or domain if either was missing.  But we must not have a mismatch.
+	repFQDN := ""
 	if rep.FQDN != "" {
-		// If the FQDN is an IP address, don't treat it as host.domain.
-		// Just treat it as a single hostname.  We don't really want to
-		// use IP addresses, but if that's all we have, it will work as
-		// long as ID is set to an xname.
-		fqdnIP := GetIPAddressString(rep.FQDN)
-		if fqdnIP != "" && ep.Hostname == "" {
-			// Just use the IP as the Hostname/FQDN with no domain.
-			ep.Hostname = fqdnIP
-			hostIsIP = true
-		}
-		// Assume FQDN is in normal host.domain1.domain2.etc format.
-		fqdnHost := ""
-		fqdnDomain := ""
-		splitFQDN := strings.SplitN(rep.FQDN, ".", 2)
-		fqdnHost = strings.Trim(splitFQDN[0], "./ ")
-		if len(splitFQDN) > 1 {
-			fqdnDomain = strings.Trim(splitFQDN[1], "./ ")
+		fqdn, fqdnPort, err := NormalizeHostPort(strings.Trim(rep.FQDN, "./ "))
+		if err != nil {
+			return nil, fmt.Errorf("FQDN is not valid: %s", err)
 		}
-		if ep.Hostname == "" {
-			ep.Hostname = fqdnHost
+		if port != "" && fqdnPort != port {
+			err := fmt.Errorf("Hostname port conflicts with FQDN: '%s' != '%s'",
+				port, fqdnPort)
+			return nil, err
 		}
-		if ep.Domain == "" {
-			if ep.Hostname == fqdnHost {
-				ep.Domain = fqdnDomain
+		repFQDN = fqdn
+		port = fqdnPort
+		if GetIPAddressString(fqdn) != "" {
+			// If the FQDN is an IP address, don't treat it as host.domain.
+			// Just treat it as a single hostname.  We don't really want to
+			// use IP addresses, but if that's all we have, it will work as
+			// long as ID is set to an xname.
+			if ep.Hostname == "" {
+				// Just use the IP as the Hostname/FQDN with no domain.
+				ep.Hostname = fqdn
+				hostIsIP = true
+			}
+		} else {
+			// FQDN is in normal host.domain1.domain2.etc format.
+			fqdnHost := fqdn
+			fqdnDomain := ""
+			splitFQDN := strings.SplitN(fqdn, ".", 2)
+			if len(splitFQDN) > 1 {
+				fqdnHost, fqdnDomain = splitFQDN[0], splitFQDN[1]
+			}
+			if ep.Hostname == "" {
+				ep.Hostname = fqdnHost
+			}
+			if ep.Domain == "" {
+				if ep.Hostname == fqdnHost {
+					ep.Domain = fqdnDomain
+				}
 			}
 		}
 	}
@@ -379,19 +425,16 @@ func NewRedfishEPDescription(rep *RawRedfishEP) (*RedfishEPDescription, error) {
 	}
 	// If these don't match, host/id + domain given but it doesn't agree
 	// with non-empty FQDN
-	repFQDN := GetIPAddressString(rep.FQDN)
-	if repFQDN == "" {
-		repFQDN = rep.FQDN
-	}
-	if rep.FQDN != "" && ep.FQDN != repFQDN {
+	if repFQDN != "" && ep.FQDN != repFQDN {
 		err := fmt.Errorf("host/domain conflicts with FQDN: '%s' != '%s'",
 			ep.FQDN, repFQDN)
 		return nil, err
 	}
+	ep.FQDN += port
 	// Validate given IP address that is not in the 'Hostname' or 'FQDN' fields
 	if rep.IPAddr != "" {
-		repIP := GetIPAddressString(rep.IPAddr)
-		if repIP == "" {
+		repIP, repIPPort, err := NormalizeHostPort(rep.IPAddr)
+		if err != nil || repIPPort != "" || GetIPAddressString(repIP) == "" {
 			err := fmt.Errorf("IPAddress is not a valid IP address: '%s'", rep.IPAddr)
 			return nil, err
 		}
@@ -430,6 +473,16 @@ func NewRedfishEPDescription(rep *RawRedfishEP) (*RedfishEPDescription, error) {
 	} else {
 		ep.RediscOnUpdate = RediscOnUpdateDefault
 	}
+	ep.TemplateID = rep.TemplateID
+	// Anything but Redfish needs a DiscoveryDriver to be discovered with.
+	if rep.Protocol != "" && !strings.EqualFold(rep.Protocol, ProtocolRedfish) {
+		name, d := lookupDiscoveryDriver(rep.Protocol)
+		if d == nil {
+			err := fmt.Errorf("Protocol is not supported: '%s'", rep.Protocol)
+			return nil, err
+		}
+		ep.Protocol = name
+	}
 	ep.DiscInfo.LastStatus = NotYetQueried
 	return ep, nil
 }
@@ -457,6 +510,7 @@ type RedfishEPDescription struct {
 	IPAddr         string        `json:"IPAddress,omitempty"`
 	RediscOnUpdate bool          `json:"RediscoverOnUpdate"`
 	TemplateID     string        `json:"TemplateID,omitempty"`
+	Protocol       string        `json:"Protocol,omitempty"` // Empty for Redfish
 	DiscInfo       DiscoveryInfo `json:"DiscoveryInfo"`
 }
 
@@ -481,6 +535,7 @@ func (red RedfishEPDescription) String() string {
 	fmt.Fprintf(buf, "IPAddress: %s, ", red.IPAddr)
 	fmt.Fprintf(buf, "RediscOnUpdate: %t, ", red.RediscOnUpdate)
 	fmt.Fprintf(buf, "TemplateID: %s, ", red.TemplateID)
+	fmt.Fprintf(buf, "Protocol: %s, ", red.Protocol)
 	fmt.Fprintf(buf, "DiscInfo: %+v", red.DiscInfo)
 	fmt.Fprintf(buf, "}")
 	return buf.String()
@@ -490,8 +545,39 @@ type DiscoveryInfo struct {
 	LastAttempt    string `json:"LastDiscoveryAttempt,omitempty"`
 	LastStatus     string `json:"LastDiscoveryStatus"`
 	RedfishVersion string `json:"RedfishVersion,omitempty"`
+
+	// Source of the fallback credentials the endpoint was last discovered
+	// with, empty if it was its own.
+	CredentialSource string `json:"CredentialSource,omitempty"`
+
+	// Subresources that could not be retrieved or decoded during the last
+	// discovery.  If LastStatus is DiscoverOK but this is not empty, the
+	// discovery was only partial.
+	Errors []DiscoveryError `json:"DiscoveryErrors,omitempty"`
+
+	// Resources from the last discovery that don't match their schemas.
+	// Only checked if schema validation is on.
+	SchemaViolations []SchemaViolation `json:"SchemaViolations,omitempty"`
+
+	// DiscoveryDriver the endpoint was last discovered with, either its
+	// Protocol or a fallback driver.  Empty for Redfish.
+	Driver string `json:"Driver,omitempty"`
+}
+
+// A Redfish resource that failed during discovery.
+type DiscoveryError struct {
+	URI        string `json:"URI"`
+	HTTPStatus int    `json:"HTTPStatus,omitempty"` // 0 if no response
+	Error      string `json:"Error"`
 }
 
+// Most DiscoveryErrors kept for an endpoint.  A BMC that is badly broken
+// could otherwise fail hundreds of GETs.
+const MaxDiscoveryErrors = 100
+
+// Protects DiscInfo.Errors and SchemaViolations, as subresources are discovered in parallel.
+var discErrLock sync.Mutex
+
 // Update Status and set timestamp to now.
 func (d *DiscoveryInfo) UpdateLastStatusWithTS(status string) {
 	d.LastAttempt = time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00")
@@ -554,10 +640,15 @@ type RedfishEP struct {
 	EventService   *EpEventService   `json:"eventService"`
 	TaskService    *EpTaskService    `json:"taskService"`
 	UpdateService  *EpUpdateService  `json:"updateService"`
+
+	TelemetryService   *EpTelemetryService   `json:"telemetryService"`
+	CertificateService *EpCertificateService `json:"certificateService"`
+
 	Chassis        EpChassisSet      `json:"chassis"`
 	Managers       EpManagers        `json:"managers"`
 	Systems        EpSystems         `json:"systems"`
 	RackPDUs       EpPDUs            `json:"rackpdus"`
+	HSNAsics       EpHSNAsics        `json:"hsnAsics"`
 
 	rootSvcRaw  *json.RawMessage //`json:"rootSvcRaw"`
 	chassisRaw  *json.RawMessage //`json:"chassisRaw"`
@@ -567,6 +658,24 @@ type RedfishEP struct {
 	// Contains various PowerEquipment links; we only care about PDUs for now
 	powerEquipment *PowerEquipment
 
+	// Set from the endpoint's VendorProfile, if any.
+	port            int
+	authStyle       string
+	quirks          map[string]bool
+	fallbackDrivers []string
+
+	// Tried in order if the endpoint's own credentials are rejected.
+	fallbackCreds []RedfishCredential
+	authFailed    bool
+
+	// Session used during discovery instead of basic auth, if any.
+	session *rfSession
+
+	// Node ordinals and sled Chassis (to their enclosure) of multi-host
+	// sleds, keyed by OdataID.  See resolveNodeSleds().
+	nodeSlots map[string]int
+	nodeSleds map[string]string
+
 	client *hms_certs.HTTPClientPair
 }
 
@@ -585,7 +694,7 @@ func NewRedfishEp(rep *RedfishEPDescription) (*RedfishEP, error) {
 	}
 	ep.RedfishEPDescription = *rep
 
-	ep.ServiceRootURL = ep.FQDN + "/redfish/v1"
+	ep.ServiceRootURL = ep.hostPort() + "/redfish/v1"
 	ep.OdataID = "/redfish/v1"
 	ep.NumSystems = 0
 	// Add client handle.  Allow for proxy if configured.
@@ -644,7 +753,9 @@ func NewRedfishEps(epds *RedfishEPDescriptions) (*RedfishEPs, error) {
 // odata.id always includes this).
 //
 // There is an optional argument to provide the retry count.  If not given,
-// the default is 3.  This is the number of times to retry the GET if it fails.
+// the Retries of the RetryPolicy are used (default 3).  This is the number
+// of times to retry the GET if it fails or the BMC answers with a 5xx/429,
+// backing off as set in the RetryPolicy.
 //
 // If no error results, result should be the raw body (i.e. Redfish JSON).
 // returned.
@@ -653,13 +764,15 @@ func NewRedfishEps(epds *RedfishEPDescriptions) (*RedfishEPs, error) {
 // interface{} map.
 func (ep *RedfishEP) GETRelative(rpath string, optionalArgs ...int) (json.RawMessage, error) {
 	var rsp *http.Response
-	var path string = "https://" + ep.FQDN + strings.Replace(rpath, "#", "%23", -1)
+	var path string = "https://" + ep.hostPort() + strings.Replace(rpath, "#", "%23", -1)
 	var body []byte
 
 	// Process optional timeout argument
-	retryCount := 3
+	retryCount := rfRetryPolicy.Retries
 	if len(optionalArgs) > 0 {
 		retryCount = optionalArgs[0]
+	} else if ep.HasQuirk(QuirkNoGETRetries) {
+		retryCount = 0
 	}
 
 	// In case we don't catch this...
@@ -672,7 +785,7 @@ func (ep *RedfishEP) GETRelative(rpath string, optionalArgs ...int) (json.RawMes
 		errlog.Printf("Error forming new request for (%s) %s", path, err)
 		return nil, err
 	}
-	req.SetBasicAuth(ep.User, ep.Password)
+	usedToken := ep.setAuth(req)
 	req.Header.Set("Accept", "*/*")
 	req.Close = true
 
@@ -685,36 +798,72 @@ func (ep *RedfishEP) GETRelative(rpath string, optionalArgs ...int) (json.RawMes
 	//     o If there have been > 1 failovers with successful Do() calls, then set
 	//       ep.client.SecureClient = InsecureClient
 
-	// Do retries on errors. They could be temporary interuptions in service.
-	sleepTime := 1
-	for retry := 0; retry <= retryCount; retry++ {
+	// Do retries on errors and on responses from a BMC that is too busy to
+	// answer.  They could be temporary interuptions in service.
+	for retry := 0; ; retry++ {
 		rsp, err = ep.client.Do(req)
-		if err != nil {
-			base.DrainAndCloseResponseBody(rsp)
-			if retry == retryCount {
+		if err == nil && !isRetryableStatus(rsp.StatusCode) {
+			break
+		}
+		if retry >= retryCount {
+			if err != nil {
+				base.DrainAndCloseResponseBody(rsp)
 				errlog.Printf("GETRelative (%s) ERROR: %s, Failing after %d retries", path, err, retry)
+				ep.addDiscoveryError(rpath, 0, err)
 				return nil, err
-			} else {
-				errlog.Printf("GETRelative (%s) ERROR: %s, Retry %d after %d seconds...", path, err, retry + 1, sleepTime)
-				time.Sleep(time.Duration(sleepTime) * time.Second)
-				sleepTime += (retry + 1) * 10
-				continue
 			}
+			// Give up, the bad status is handled below.
+			break
+		}
+		wait := rfRetryPolicy.Wait(retry)
+		if err == nil {
+			err = fmt.Errorf("%s", http.StatusText(rsp.StatusCode))
+		}
+		base.DrainAndCloseResponseBody(rsp)
+		errlog.Printf("GETRelative (%s) ERROR: %s, Retry %d after %s...", path, err, retry+1, wait)
+		time.Sleep(wait)
+	}
+
+	// Successful responses are decoded as they are read, trimming any
+	// oversized arrays (e.g. thousands of Voltages) so that they never have
+	// to be held in memory.  Other responses are only read for their error.
+	var decodeErr error
+	if rsp.StatusCode == http.StatusOK && rsp.Body != nil {
+		body, decodeErr = readCappedJSON(path, rsp.Body,
+			httpMaxResponseBytes, rfMaxArrayEntries)
+	} else if rsp.Body != nil {
+		// Read one byte past the limit so we can tell if it was exceeded.
+		body, _ = ioutil.ReadAll(io.LimitReader(rsp.Body, httpMaxResponseBytes+1))
+		if int64(len(body)) > httpMaxResponseBytes {
+			decodeErr = ErrRFDiscResponseTooLarge
 		}
-		break
-	}
-
-	if rsp.Body != nil {
-		body, _ = ioutil.ReadAll(rsp.Body)
 	}
 	base.DrainAndCloseResponseBody(rsp)
+	if decodeErr == ErrRFDiscResponseTooLarge {
+		errlog.Printf("GETRelative (%s) ERROR: response larger than %d bytes",
+			path, httpMaxResponseBytes)
+		ep.addDiscoveryError(rpath, rsp.StatusCode, ErrRFDiscResponseTooLarge)
+		return nil, ErrRFDiscResponseTooLarge
+	}
 
+	if rsp.StatusCode == http.StatusUnauthorized && usedToken &&
+		ep.session.drop() {
+		// The session may have timed out.  Go back to basic auth.
+		errlog.Printf("GETRelative (%s) session token rejected, "+
+			"using basic auth", path)
+		return ep.GETRelative(rpath, optionalArgs...)
+	}
 	if rsp.StatusCode != http.StatusOK {
 		rerr := fmt.Errorf("%s", http.StatusText(rsp.StatusCode))
 		errlog.Printf("GETRelative (%s) Bad rsp: %s", path, rerr)
+		ep.addDiscoveryError(rpath, rsp.StatusCode, rerr)
 		if rsp.StatusCode == http.StatusNotFound {
 			// Return a named error so we can take special action
 			return nil, ErrRFDiscURLNotFound
+		} else if rsp.StatusCode == http.StatusUnauthorized {
+			// Noted so discovery can be retried with other credentials.
+			ep.authFailed = true
+			return nil, ErrRFDiscUnauthorized
 		} else {
 			var compErr RedfishError
 			if err := json.Unmarshal(json.RawMessage(body), &compErr); err != nil {
@@ -733,29 +882,154 @@ func (ep *RedfishEP) GETRelative(rpath string, optionalArgs ...int) (json.RawMes
 	}
 
 	// We want to return the raw JSON output.  It unmarshals just as
-	// well if it's indented, so we do that here to verify that it is
-	// valid JSON. This lets us defer to the caller how to unmarshall it,
-	// and puts it in a more (human) readable format.
+	// well if it's indented, so we do that here.  It was already checked
+	// to be valid JSON when it was read.  This lets us defer to the caller
+	// how to unmarshall it, and puts it in a more (human) readable format.
+	err = decodeErr
 	var out bytes.Buffer
-	err = json.Indent(&out, body, "", "\t")
+	if err == nil {
+		err = json.Indent(&out, body, "", "\t")
+	}
 	if err != nil {
 		errlog.Printf("Error decoding %s: %s", path, err)
+		ep.addDiscoveryError(rpath, rsp.StatusCode, err)
 		return nil, err
 	}
+	if rfSchemaValidation {
+		ep.checkSchema(rpath, body)
+	}
 	// Dump response and path in a unit-test friendly format for regression
 	// test auto-generation.
 	if genTestingPayloadsTitle != "" {
 		if genTestingPayloadsDumpEpID == ep.ID {
+			payload := out.Bytes()
+			if genTestingPayloadsMinimize {
+				if minPayload, err := MinimizeTestingPayload(payload); err == nil {
+					payload = minPayload
+				} else {
+					errlog.Printf("%s: %s", path, err)
+				}
+			}
 			GenTestingPayloads(genTestingPayloadsOutfile,
 				genTestingPayloadsTitle,
 				rpath,
-				out.Bytes())
+				payload)
 		}
 	}
 	jsonBody := json.RawMessage(out.Bytes())
 	return jsonBody, nil
 }
 
+// Note a resource that failed during discovery in DiscInfo.Errors.  uri may
+// be given with or without the endpoint's FQDN in front.
+func (ep *RedfishEP) addDiscoveryError(uri string, httpStatus int, err error) {
+	if ep == nil {
+		return
+	}
+	derr := DiscoveryError{URI: ep.trimURI(uri), HTTPStatus: httpStatus}
+	if err != nil {
+		derr.Error = err.Error()
+	}
+
+	discErrLock.Lock()
+	defer discErrLock.Unlock()
+	if len(ep.DiscInfo.Errors) < MaxDiscoveryErrors {
+		ep.DiscInfo.Errors = append(ep.DiscInfo.Errors, derr)
+	}
+}
+
+// Strip the scheme and the endpoint's FQDN, if any, from the front of uri.
+func (ep *RedfishEP) trimURI(uri string) string {
+	uri = strings.TrimPrefix(uri, "https://")
+	uri = strings.TrimPrefix(uri, ep.hostPort())
+	return strings.TrimPrefix(uri, ep.FQDN)
+}
+
+// True for HTTP statuses a busy or restarting BMC may give, which are
+// worth retrying.
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// POST body to the given rpath relative to the redfish hostname of the
+// given endpoint, e.g. to create an EventService subscription.  Unlike
+// GETRelative() this is not retried, since the POST may not be idempotent.
+// Returns the response body, if any.
+func (ep *RedfishEP) POSTRelative(rpath string, body []byte) (json.RawMessage, error) {
+	return ep.sendRelative("POST", rpath, body)
+}
+
+// DELETE the resource at the given rpath relative to the redfish hostname
+// of the given endpoint, e.g. an EventService subscription.
+func (ep *RedfishEP) DELETERelative(rpath string) error {
+	_, err := ep.sendRelative("DELETE", rpath, nil)
+	return err
+}
+
+// PATCH body to the given rpath relative to the redfish hostname of the
+// given endpoint, e.g. to change the password of an account.  Not retried.
+func (ep *RedfishEP) PATCHRelative(rpath string, body []byte) (json.RawMessage, error) {
+	return ep.sendRelative("PATCH", rpath, body)
+}
+
+// Send a single, non-retried request with the given method and (JSON) body
+// to rpath, returning the response body, if any.
+func (ep *RedfishEP) sendRelative(method, rpath string, body []byte) (json.RawMessage, error) {
+	var path string = "https://" + ep.hostPort() + rpath
+
+	if ep.FQDN == "" {
+		errlog.Printf("Can't HTTP %s (%s): FQDN is empty", method, path)
+		return nil, ErrRFDiscFQDNMissing
+	}
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, path, reqBody)
+	if err != nil {
+		errlog.Printf("Error forming new request for (%s) %s", path, err)
+		return nil, err
+	}
+	usedToken := ep.setAuth(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "*/*")
+	req.Close = true
+
+	rsp, err := ep.client.Do(req)
+	if err != nil {
+		base.DrainAndCloseResponseBody(rsp)
+		errlog.Printf("%s (%s) ERROR: %s", method, path, err)
+		return nil, err
+	}
+	var rspBody []byte
+	if rsp.Body != nil {
+		rspBody, _ = ioutil.ReadAll(io.LimitReader(rsp.Body, httpMaxResponseBytes))
+	}
+	base.DrainAndCloseResponseBody(rsp)
+	if rsp.StatusCode == http.StatusUnauthorized && usedToken &&
+		ep.session.drop() {
+		errlog.Printf("%s (%s) session token rejected, "+
+			"using basic auth", method, path)
+		return ep.sendRelative(method, rpath, body)
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		rerr := fmt.Errorf("%s", http.StatusText(rsp.StatusCode))
+		errlog.Printf("%s (%s) Bad rsp: %s", method, path, rerr)
+		return nil, rerr
+	}
+	return json.RawMessage(rspBody), nil
+}
+
 // Loop through all endpoints to get top-level information, i.e.
 // how many systems, etc.  and initalize these structures so they
 // can be discovered in more detail.
@@ -791,7 +1065,36 @@ func (eps *RedfishEPs) GetAllRootInfo() {
 // For a given Redfish endpoint, get top-level information, i.e.
 // how many systems, chassis, managers, etc. and initalize these structures
 // so can be discovered in more detail.
+//
+// If the endpoint rejects its credentials, discovery is repeated with each
+// of its fallback credentials in turn, see SetFallbackCredentials().
 func (ep *RedfishEP) GetRootInfo() {
+	user, password := ep.User, ep.Password
+	ep.getRootInfo()
+	for _, cred := range ep.fallbackCreds {
+		if !ep.authFailed {
+			break
+		}
+		if cred.User == user && cred.Password == password {
+			continue
+		}
+		errlog.Printf("%s: credentials rejected, trying fallback %s",
+			ep.ID, cred.Source)
+		ep.User, ep.Password = cred.User, cred.Password
+		ep.getRootInfo()
+	}
+	if ep.authFailed {
+		ep.User, ep.Password = user, password
+	}
+	ep.DiscInfo.CredentialSource = ep.fallbackSource()
+}
+
+// Does the actual work for GetRootInfo() with the current credentials.
+func (ep *RedfishEP) getRootInfo() {
+	ep.authFailed = false
+	ep.DiscInfo.Errors = nil
+	ep.DiscInfo.SchemaViolations = nil
+	ep.DiscInfo.Driver = ""
 	ep.DiscInfo.TSNow()
 	err := ep.CheckPrePhase1()
 	if err != nil {
@@ -806,11 +1109,20 @@ func (ep *RedfishEP) GetRootInfo() {
 		ep.DiscInfo.UpdateLastStatusWithTS(EndpointNotEnabled)
 		return
 	}
+	// Endpoints with another Protocol have no Redfish service at all.
+	if ep.Protocol != "" {
+		ep.discoverWithProtocolDriver()
+		return
+	}
 	// Get ServiceRoot for endpoint
 	path := ep.OdataID
 	rootSvcJSON, err := ep.GETRelative(path)
 	if err != nil || rootSvcJSON == nil {
 		ep.DiscInfo.UpdateLastStatusWithTS(HTTPsGetFailed)
+		// No Redfish at all, as opposed to Redfish we can't log in to
+		if !ep.authFailed && len(ep.fallbackDrivers) > 0 {
+			ep.discoverWithFallbackDrivers()
+		}
 		return
 	}
 	if rfDebug > 0 {
@@ -824,11 +1136,17 @@ func (ep *RedfishEP) GetRootInfo() {
 	if err != nil {
 		errlog.Printf("Failed to decode %s: %s\n", path, err)
 		ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+		ep.addDiscoveryError(path, 0, err)
 	}
 	ep.RedfishType = ServiceRootType
 	ep.DiscInfo.RedfishVersion = ep.ServiceRootRF.RedfishVersion
 	ep.UUID = ep.ServiceRootRF.UUID
 
+	// Log in once for the rest of discovery rather than sending basic
+	// auth on every request.
+	ep.openSession()
+	defer ep.closeSession()
+
 	//
 	// Now create structs for each of the services in the
 	// SystemRoot, then discover them, so that we can interact
@@ -872,6 +1190,17 @@ func (ep *RedfishEP) GetRootInfo() {
 	} else {
 		errlog.Printf("%s: No UpdateService entry found!\n", ep.FQDN)
 	}
+	// Optional, so not finding it is not worth logging.
+	if ep.ServiceRootRF.TelemetryService.Oid != "" {
+		oid := ep.ServiceRootRF.TelemetryService.Oid
+		ep.TelemetryService = NewEpTelemetryService(ep, oid)
+		ep.TelemetryService.discoverRemotePhase1()
+	}
+	if ep.ServiceRootRF.CertificateService.Oid != "" {
+		oid := ep.ServiceRootRF.CertificateService.Oid
+		ep.CertificateService = NewEpCertificateService(ep, oid)
+		ep.CertificateService.discoverRemotePhase1()
+	}
 	//
 	// We now take each set of root level Redfish component objects in
 	// turn so we can dive deeper and collect info on those we need for
@@ -910,6 +1239,7 @@ func (ep *RedfishEP) GetRootInfo() {
 		if err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", path, err)
 			ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+			ep.addDiscoveryError(path, 0, err)
 			return
 		}
 
@@ -959,6 +1289,7 @@ func (ep *RedfishEP) GetRootInfo() {
 	if err != nil {
 		errlog.Printf("Failed to decode %s: %s\n", path, err)
 		ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+		ep.addDiscoveryError(path, 0, err)
 		return
 	}
 	ep.NumManagers = len(manInfo.Members)
@@ -1012,6 +1343,7 @@ func (ep *RedfishEP) GetRootInfo() {
 		if err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", path, err)
 			ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+			ep.addDiscoveryError(path, 0, err)
 			return
 		}
 		ep.powerEquipment = &powerInfo
@@ -1034,6 +1366,7 @@ func (ep *RedfishEP) GetRootInfo() {
 			if err != nil {
 				errlog.Printf("Failed to decode %s: %s\n", path, err)
 				ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+				ep.addDiscoveryError(path, 0, err)
 				return
 			}
 			ep.NumRackPDUs = len(pduInfo.Members)
@@ -1049,15 +1382,29 @@ func (ep *RedfishEP) GetRootInfo() {
 		}
 	}
 
+	//
+	// Next, the HSN switch ASICs and their ports, for router blades.
+	//
+	if xnametypes.GetHMSType(ep.ID) == xnametypes.RouterBMC {
+		ep.discoverHSNAsicsPhase1()
+	}
+
 	//
 	// Phase 2 - remote queries are done for entire root.  Now use this
 	// info to tie the Redfish properties to HMS ones, like HMS Type and
 	// location, so they can be organized into a larger system that contains
 	// the discovered hardware for all of the system's endpoints.
 	//
+	ep.discoverLocalPhase2()
+}
+
+// Phase 2 of discovery for the whole endpoint, once its components have
+// been fetched, either over Redfish or by a DiscoveryDriver.
+func (ep *RedfishEP) discoverLocalPhase2() {
 	ep.DiscInfo.UpdateLastStatusWithTS(VerifyingData)
 
 	var childStatus string = DiscoverOK
+	ep.resolveNodeSleds()
 	if err := ep.Chassis.discoverLocalPhase2(); err != nil {
 		errlog.Printf("ERROR: Chassis verification failed: %s", err)
 		childStatus = ChildVerificationFailed
@@ -1070,6 +1417,10 @@ func (ep *RedfishEP) GetRootInfo() {
 		errlog.Printf("ERROR: RackPDUs verification failed: %s", err)
 		childStatus = ChildVerificationFailed
 	}
+	if err := ep.HSNAsics.discoverLocalPhase2(); err != nil {
+		errlog.Printf("ERROR: HSNAsics verification failed: %s", err)
+		childStatus = ChildVerificationFailed
+	}
 	// Note we need to do systems last because they are the most likely
 	// to need info from the other objects.
 	if err := ep.VerifySystems(); err != nil {
@@ -1079,6 +1430,73 @@ func (ep *RedfishEP) GetRootInfo() {
 	ep.DiscInfo.UpdateLastStatusWithTS(childStatus)
 }
 
+// Split a discovered endpoint into parts that can be converted and stored
+// one after another once discovery is complete, so that the HMS-level data
+// for a controller with many components does not all have to be in memory
+// at once.  The crawl itself is not split, as the second discovery phase
+// needs the whole endpoint.  Each part is a shallow copy of the endpoint
+// with one Chassis, Manager, RackPDU or HSN ASIC, except that all Systems
+// are in a single part, as node FRUIDs are made unique across them.  The
+// services (AccountService, etc.) go with the first part.  Parts are always
+// returned in the same order, and there is always at least one.
+func (ep *RedfishEP) Parts() []*RedfishEP {
+	var parts []*RedfishEP
+	newPart := func() *RedfishEP {
+		part := *ep
+		part.Chassis = EpChassisSet{OIDs: make(map[string]*EpChassis)}
+		part.Systems = EpSystems{OIDs: make(map[string]*EpSystem)}
+		part.Managers = EpManagers{OIDs: make(map[string]*EpManager)}
+		part.RackPDUs = EpPDUs{OIDs: make(map[string]*EpPDU)}
+		part.HSNAsics = EpHSNAsics{OIDs: make(map[string]*EpHSNAsic)}
+		part.NumChassis, part.NumSystems = 0, 0
+		part.NumManagers, part.NumRackPDUs = 0, 0
+		if len(parts) > 0 {
+			part.AccountService = nil
+			part.SessionService = nil
+			part.EventService = nil
+			part.TaskService = nil
+			part.UpdateService = nil
+			part.TelemetryService = nil
+			part.CertificateService = nil
+		}
+		parts = append(parts, &part)
+		return &part
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(ep.Chassis.OIDs)) {
+		part := newPart()
+		part.Chassis.OIDs[id] = ep.Chassis.OIDs[id]
+		part.Chassis.Num, part.NumChassis = 1, 1
+	}
+	if len(ep.Systems.OIDs) > 0 {
+		part := newPart()
+		for id, sys := range ep.Systems.OIDs {
+			part.Systems.OIDs[id] = sys
+		}
+		part.Systems.Num = len(ep.Systems.OIDs)
+		part.NumSystems = part.Systems.Num
+	}
+	for _, id := range slices.Sorted(maps.Keys(ep.Managers.OIDs)) {
+		part := newPart()
+		part.Managers.OIDs[id] = ep.Managers.OIDs[id]
+		part.Managers.Num, part.NumManagers = 1, 1
+	}
+	for _, id := range slices.Sorted(maps.Keys(ep.RackPDUs.OIDs)) {
+		part := newPart()
+		part.RackPDUs.OIDs[id] = ep.RackPDUs.OIDs[id]
+		part.RackPDUs.Num, part.NumRackPDUs = 1, 1
+	}
+	for _, id := range slices.Sorted(maps.Keys(ep.HSNAsics.OIDs)) {
+		part := newPart()
+		part.HSNAsics.OIDs[id] = ep.HSNAsics.OIDs[id]
+		part.HSNAsics.Num = 1
+	}
+	if len(parts) == 0 {
+		newPart()
+	}
+	return parts
+}
+
 func (ep *RedfishEP) GetSystems() string {
 	var path string
 
@@ -1118,6 +1536,7 @@ func (ep *RedfishEP) GetSystems() string {
 		if err != nil {
 			errlog.Printf("Failed to decode %s: %s\n", path, err)
 			ep.DiscInfo.UpdateLastStatusWithTS(EPResponseFailedDecode)
+			ep.addDiscoveryError(path, 0, err)
 			return EPResponseFailedDecode
 		}
 		ep.NumSystems = len(sysInfo.Members)
@@ -1126,9 +1545,12 @@ func (ep *RedfishEP) GetSystems() string {
 		} else if sysInfo.OCount > 0 && sysInfo.OCount != ep.NumSystems {
 			errlog.Printf("%s: odata.count != Member array len\n", ep.FQDN+path)
 		}
+		sort.Sort(ResourceIDSlice(sysInfo.Members))
+		sysInfo.Members = ep.addAggregatedSystems(path, sysInfo.Members)
+		ep.NumSystems = len(sysInfo.Members)
+
 		ep.Systems.OIDs = make(map[string]*EpSystem)
 		ep.Systems.Num = ep.NumSystems
-		sort.Sort(ResourceIDSlice(sysInfo.Members))
 		for i, sysOID := range sysInfo.Members {
 			sID := sysOID.Basename()
 			ep.Systems.OIDs[sID] = NewEpSystem(ep, sysOID, i)
@@ -1138,6 +1560,101 @@ func (ep *RedfishEP) GetSystems() string {
 	return HTTPsGetOk
 }
 
+// Aggregators (e.g. enclosure or rack managers) can expose the
+// ComputerSystems of all of the BMCs they are connected to via an
+// AggregationService.  Order the Systems so the node ordinals follow the
+// AggregationSources, i.e. the aggregator's own systems first, then the
+// systems of each source in turn, and add any aggregated systems that are
+// missing from the Systems collection.  Anything else in an Aggregate comes
+// last.  Without an AggregationService members is returned as is.
+func (ep *RedfishEP) addAggregatedSystems(sysPath string, members []ResourceID) []ResourceID {
+	aggPath := ep.ServiceRootRF.AggregationService.Oid
+	if aggPath == "" {
+		return members
+	}
+	aggJSON, err := ep.GETRelative(aggPath)
+	if err != nil || aggJSON == nil {
+		errlog.Printf("%s: Couldn't get AggregationService, skipping it.\n",
+			ep.FQDN+aggPath)
+		return members
+	}
+	var aggSvc AggregationService
+	if err := json.Unmarshal(aggJSON, &aggSvc); err != nil {
+		errlog.Printf("Failed to decode %s: %s\n", aggPath, err)
+		return members
+	}
+	if aggSvc.ServiceEnabled != nil && !*aggSvc.ServiceEnabled {
+		return members
+	}
+	sysPath = strings.TrimSuffix(sysPath, "/")
+	seen := make(map[string]bool)
+	aggregated := []ResourceID{}
+	addSystem := func(r ResourceID) {
+		oid := strings.TrimSuffix(r.Oid, "/")
+		if path.Dir(oid) == sysPath && !seen[oid] {
+			seen[oid] = true
+			aggregated = append(aggregated, ResourceID{oid})
+		}
+	}
+	for _, raw := range ep.getCollectionMembers(aggSvc.AggregationSources.Oid) {
+		var src AggregationSource
+		if err := json.Unmarshal(raw, &src); err != nil {
+			errlog.Printf("Failed to decode AggregationSource: %s\n", err)
+			continue
+		}
+		for _, r := range src.Links.ResourcesAccessed {
+			addSystem(r)
+		}
+	}
+	for _, raw := range ep.getCollectionMembers(aggSvc.Aggregates.Oid) {
+		var agg Aggregate
+		if err := json.Unmarshal(raw, &agg); err != nil {
+			errlog.Printf("Failed to decode Aggregate: %s\n", err)
+			continue
+		}
+		for _, r := range agg.Elements {
+			addSystem(r)
+		}
+	}
+	if len(aggregated) == 0 {
+		return members
+	}
+	ordered := make([]ResourceID, 0, len(members)+len(aggregated))
+	for _, m := range members {
+		if !seen[strings.TrimSuffix(m.Oid, "/")] {
+			ordered = append(ordered, m)
+		}
+	}
+	return append(ordered, aggregated...)
+}
+
+// GET each member of the collection at rpath, in sorted order.  Members
+// that can't be retrieved are skipped.
+func (ep *RedfishEP) getCollectionMembers(rpath string) []json.RawMessage {
+	members := []json.RawMessage{}
+	if rpath == "" {
+		return members
+	}
+	collJSON, err := ep.GETRelative(rpath)
+	if err != nil || collJSON == nil {
+		return members
+	}
+	var coll GenericCollection
+	if err := json.Unmarshal(collJSON, &coll); err != nil {
+		errlog.Printf("Failed to decode %s: %s\n", rpath, err)
+		return members
+	}
+	sort.Sort(ResourceIDSlice(coll.Members))
+	for _, m := range coll.Members {
+		mJSON, err := ep.GETRelative(m.Oid)
+		if err != nil || mJSON == nil {
+			continue
+		}
+		members = append(members, mJSON)
+	}
+	return members
+}
+
 func (ep *RedfishEP) VerifySystems() error {
 	return ep.Systems.discoverLocalPhase2()
 }
@@ -1281,10 +1798,20 @@ func (ep *RedfishEP) getChassisHMSType(c *EpChassis) string {
 		// NodeEnclosures may be RackMount, Enclosure.
 		fallthrough
 	case RFSubtypeRackMount:
+		if ep.isNodeSled(c) {
+			// Sled of a multi-host enclosure, the enclosure is the
+			// NodeEnclosure.
+			return xnametypes.HMSTypeInvalid.String()
+		}
 		if isFoxconnChassis(c) {
 			// Foxconn Paradise has a bunch of RackMount chassis we can ignore
 			return xnametypes.HMSTypeInvalid.String()
 		}
+		if c.Quirks().Has(QuirkNodeEnclosureNeedsSystem) &&
+			len(c.ChassisRF.Links.ComputerSystems) == 0 {
+			// e.g. SuperMicro storage enclosures
+			return xnametypes.HMSTypeInvalid.String()
+		}
 		if ep.NumSystems > 0 {
 			// Does the endpoint contain nodes?
 			// For now assume NodeEnclosure.
@@ -1405,11 +1932,13 @@ func (ep *RedfishEP) getSystemHMSID(s *EpSystem, hmsType string, ordinal int) st
 
 // Determines based on discovered info and original list order what the
 // node ordinal is, i.e. the n[0-n] in the xname, along with the HMS Type.
+// Nodes in multi-host sleds use the order of their sleds instead, see
+// resolveNodeSleds().
 // Note: Only use physical systems for now (or systems with no type, provided
 // there are no physical systems)
 // Return -1 if invalid (bad input or unsupported RF SystemType).
 func (ep *RedfishEP) getSystemOrdinalAndType(s *EpSystem) (int, string) {
-	// Always use the order in the System collection for now.
+	// Use the order in the System collection unless set by sled.
 	ordinal := 0
 	hmsType := ""
 
@@ -1438,6 +1967,10 @@ func (ep *RedfishEP) getSystemOrdinalAndType(s *EpSystem) (int, string) {
 			}
 		}
 	}
+	// Nodes in multi-host sleds are numbered by sled instead.
+	if slot, ok := ep.nodeSlots[s.OdataID]; ok {
+		return slot, hmsType
+	}
 	// Something went wrong or bad input.
 	if ordinal > s.RawOrdinal {
 		errlog.Printf("BUG: Bad ordinal.")
@@ -1456,10 +1989,9 @@ func (ep *RedfishEP) getNodeSvcNetDomain(s *EpSystem) string {
 // For nodes with multiple ethernet interfaces, return the Redfish ID
 // of the one that will be plugged into the management network.
 func (ep *RedfishEP) getNodeSvcNetEthIfaceId(s *EpSystem) string {
-	// TODO: Next discovery phase.  Hardcoded for now, only valid for
-	//       the Dell boxes we currently have, but that is the only
-	//       multi-interface scenario we don't handle another way.
-	return "NIC.Integrated.1-3-1"
+	// Only set for the Dell boxes we currently have, but that is the only
+	// multi-interface scenario we don't handle another way.
+	return s.Quirks().Param(QuirkParamMgmtEthIfaceID)
 }
 
 //
@@ -1469,20 +2001,33 @@ func (ep *RedfishEP) getNodeSvcNetEthIfaceId(s *EpSystem) string {
 // Determines based on discovered info and original list order what the
 // processor ordinal is, i.e. the n0p[0-n] in the xname.
 func (ep *RedfishEP) getProcessorOrdinal(p *EpProcessor) int {
-	// Always use the order in the System's ProcessorCollection for now.
-	//look at the EpProcessor's ProcessorRF.ProcessorType field
-	//to determine processor type
+	// CPUs and accelerators (GPUs) share the System's ProcessorCollection
+	// but are numbered separately.  The ordinal is the position among the
+	// siblings of the same kind, in collection order, so that it does not
+	// depend on the order in which the processors are visited.
+	isAccel := IsAccelProcessorType(p.ProcessorRF.ProcessorType)
 	ordinal := 0
-	if p.ProcessorRF.ProcessorType == "GPU" || p.ProcessorRF.ProcessorType == "Accelerator" {
-		ordinal = p.sysRF.accelCount
-		p.sysRF.accelCount = p.sysRF.accelCount + 1
-	} else {
-		ordinal = p.sysRF.cpuCount
-		p.sysRF.cpuCount = p.sysRF.cpuCount + 1
+	for _, sib := range p.sysRF.Processors.OIDs {
+		if sib == p || IsAccelProcessorType(sib.ProcessorRF.ProcessorType) != isAccel {
+			continue
+		}
+		if sib.RawOrdinal < p.RawOrdinal {
+			ordinal++
+		}
 	}
 	return ordinal
 }
 
+// Returns true if the Redfish ProcessorType is one that HMS models as a
+// NodeAccel (GPU) rather than a Processor (CPU).
+func IsAccelProcessorType(procType string) bool {
+	switch strings.ToLower(procType) {
+	case "gpu", "accelerator":
+		return true
+	}
+	return false
+}
+
 // Determines based on discovered info and original list order what the
 // Memory module ordinal is, i.e. the n0d[0-n] in the xname.
 func (ep *RedfishEP) getMemoryOrdinal(m *EpMemory) int {
@@ -1652,12 +2197,44 @@ func (ep *RedfishEP) getNetworkAdapterOrdinal(na *EpNetworkAdapter) int {
 	return na.RawOrdinal
 }
 
+// Determines the HSN ASIC ordinal, i.e. the a[0-n] in the xname.  Just use
+// the sorted order they were found in.
+func (ep *RedfishEP) getHSNAsicOrdinal(a *EpHSNAsic) int {
+	return a.RawOrdinal
+}
+
+// Determines the xname of an HSN ASIC.  This is the RouterModule the
+// RouterBMC is on, plus the ASIC's ordinal.
+func (ep *RedfishEP) getHSNAsicHMSID(a *EpHSNAsic, ordinal int) string {
+	if ordinal < 0 || xnametypes.GetHMSType(ep.ID) != xnametypes.RouterBMC {
+		return ""
+	}
+	return xnametypes.GetHMSCompParent(ep.ID) + "a" + strconv.Itoa(ordinal)
+}
+
+// Determines the Port ordinal, i.e. the l[0-n] in the xname.  Ports are
+// usually numbered, so use that number if we can, otherwise fall back on
+// the sorted order.
+func (ep *RedfishEP) getHSNPortOrdinal(p *EpHSNPort) int {
+	ordinal, err := strconv.Atoi(path.Base(p.OdataID))
+	if err != nil || ordinal < 0 {
+		ordinal = p.RawOrdinal
+	}
+	return ordinal
+}
+
 // Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
 // else return an error.
 func GetNetworkAdapterFRUID(na *EpNetworkAdapter) (fruid string, err error) {
 	return getStandardFRUID(na.Type, na.ID, na.NetworkAdapterRF.Manufacturer, na.NetworkAdapterRF.PartNumber, na.NetworkAdapterRF.SerialNumber)
 }
 
+// Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
+// else return an error.
+func GetHSNAsicFRUID(a *EpHSNAsic) (fruid string, err error) {
+	return getStandardFRUID(a.Type, a.ID, a.HSNAsicRF.Manufacturer, a.HSNAsicRF.PartNumber, a.HSNAsicRF.SerialNumber)
+}
+
 // Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
 // else return an error.
 func GetNodeAccelRiserFRUID(r *EpNodeAccelRiser) (fruid string, err error) {
@@ -1672,9 +2249,14 @@ func GetPowerSupplyFRUID(p *EpPowerSupply) (fruid string, err error) {
 }
 
 // Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
-// else return an error.
+// else return an error.  Many drives (NVMe especially) report a Model but
+// no PartNumber, so the Model is used in its place when needed.
 func GetDriveFRUID(d *EpDrive) (fruid string, err error) {
-	return getStandardFRUID(d.Type, d.ID, d.DriveRF.Manufacturer, d.DriveRF.PartNumber, d.DriveRF.SerialNumber)
+	partNumber := d.DriveRF.PartNumber
+	if partNumber == "" {
+		partNumber = d.DriveRF.Model
+	}
+	return getStandardFRUID(d.Type, d.ID, d.DriveRF.Manufacturer, partNumber, d.DriveRF.SerialNumber)
 }
 
 // Build FRUID using standard fields: <Type>.<Manufacturer>.<PartNumber>.<SerialNumber>
@@ -1872,11 +2454,15 @@ func (ep *RedfishEP) getManagerOrdinal(m *EpManager) int {
 
 // Parsing manufacturer string
 const (
-	CrayMfr     = "Cray"
-	IntelMfr    = "Intel"
-	DellMfr     = "Dell"
-	GigabyteMfr = "Gigabyte"
-	FoxconnMfr  = "Foxconn"
+	CrayMfr       = "Cray"
+	IntelMfr      = "Intel"
+	DellMfr       = "Dell"
+	GigabyteMfr   = "Gigabyte"
+	FoxconnMfr    = "Foxconn"
+	LenovoMfr     = "Lenovo"
+	SupermicroMfr = "Supermicro"
+	ServerTechMfr = "ServerTech"
+	RaritanMfr    = "Raritan"
 )
 
 // This should only return 1 if the RF manufacturer string (mfrCheckStr) is mfr
@@ -1915,6 +2501,24 @@ func IsManufacturer(mfrCheckStr, mfr string) int {
 				if s == "foxconn" {
 					return 1
 				}
+			case LenovoMfr:
+				if s == "lenovo" {
+					return 1
+				}
+			case SupermicroMfr:
+				if s == "supermicro" || s == "smci" {
+					return 1
+				}
+			case ServerTechMfr:
+				// Usually "Server Technology"
+				if s == "servertech" ||
+					(s == "server" && strings.Contains(lower, "server technology")) {
+					return 1
+				}
+			case RaritanMfr:
+				if s == "raritan" {
+					return 1
+				}
 			}
 		}
 		return 0
@@ -23,7 +23,11 @@
 package rf
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
 	"strings"
 	"testing"
 )
@@ -150,6 +154,18 @@ var goodRawEPs = RawRedfishEPs{
 			Domain:   "",
 			User:     "root",
 			Password: "calvin",
+		}, {
+			ID:       "x0c0s39b0",
+			FQDN:     "fe80::240:a6ff:fe82:f7c2%eth0",
+			User:     "root",
+			Password: "calvin",
+		}, {
+			ID:       "x0c0s40b0",
+			Hostname: "x0c0s40b0:8443",
+			Domain:   "crush.next.cray.com",
+			FQDN:     "X0C0S40B0.crush.next.cray.com:8443",
+			User:     "root",
+			Password: "calvin",
 		},
 	},
 }
@@ -184,6 +200,28 @@ var badRawEPs = RawRedfishEPs{
 			Domain:   "next.cray.com",
 			User:     "root",
 			Password: "calvin",
+		}, {
+			ID:       "x0c0s26b0",
+			FQDN:     "x0c0s26b0.crush.next.cray.com:99999", // Bad port
+			User:     "root",
+			Password: "calvin",
+		}, {
+			ID:       "x0c0s27b0",
+			FQDN:     "[fd40::zz]", // Malformed IPv6 address
+			User:     "root",
+			Password: "calvin",
+		}, {
+			ID:       "x0c0s28b0",
+			Hostname: "x0c0s28b0:8443", // Port conflicts with FQDN's
+			FQDN:     "x0c0s28b0.crush.next.cray.com:9443",
+			User:     "root",
+			Password: "calvin",
+		}, {
+			ID:       "x0c0s29b0",
+			Hostname: "10.100.16.22",
+			IPAddr:   "10.100.16.22:8443", // IP address with a port
+			User:     "root",
+			Password: "calvin",
 		},
 	},
 }
@@ -232,6 +270,65 @@ func TestNewRedfishEPDescription(t *testing.T) {
 	}
 }
 
+// Addresses should come out canonicalized, with the URL for an IPv6 zone
+// escaped.
+func TestNewRedfishEPDescriptionAddrs(t *testing.T) {
+	tests := []struct {
+		rep        RawRedfishEP
+		expFQDN    string
+		expIPAddr  string
+		expRootURL string
+	}{{
+		RawRedfishEP{ID: "x0c0s1b0", FQDN: "FE80::0240:A6FF:FE82:F7C2%eth0"},
+		"[fe80::240:a6ff:fe82:f7c2%eth0]",
+		"[fe80::240:a6ff:fe82:f7c2%eth0]",
+		"[fe80::240:a6ff:fe82:f7c2%25eth0]/redfish/v1",
+	}, {
+		RawRedfishEP{ID: "x0c0s1b0", Hostname: "[fd40::1]:8443"},
+		"[fd40::1]:8443",
+		"[fd40::1]",
+		"[fd40::1]:8443/redfish/v1",
+	}, {
+		RawRedfishEP{ID: "x0c0s1b0", Hostname: "fd40::1", IPAddr: "[fd40::0001]"},
+		"[fd40::1]",
+		"[fd40::1]",
+		"[fd40::1]/redfish/v1",
+	}, {
+		RawRedfishEP{Hostname: "x0c0s1b0:8443", Domain: "Local."},
+		"x0c0s1b0.local:8443",
+		"",
+		"x0c0s1b0.local:8443/redfish/v1",
+	}, {
+		RawRedfishEP{FQDN: "x0c0s1b0.local:443"},
+		"x0c0s1b0.local",
+		"",
+		"x0c0s1b0.local/redfish/v1",
+	}}
+	for i, test := range tests {
+		epd, err := NewRedfishEPDescription(&test.rep)
+		if err != nil {
+			t.Errorf("Testcase %d: Got unexpected error: %s", i, err)
+			continue
+		}
+		if epd.ID != "x0c0s1b0" || epd.FQDN != test.expFQDN ||
+			epd.IPAddr != test.expIPAddr {
+			t.Errorf("Testcase %d: Expected ID x0c0s1b0, FQDN '%s', IPAddr '%s', "+
+				"got '%s' '%s' '%s'", i, test.expFQDN, test.expIPAddr,
+				epd.ID, epd.FQDN, epd.IPAddr)
+		}
+		ep, err := NewRedfishEp(epd)
+		if err != nil {
+			t.Errorf("Testcase %d: NewRedfishEp: %s", i, err)
+		} else if ep.ServiceRootURL != test.expRootURL {
+			t.Errorf("Testcase %d: Expected ServiceRootURL '%s', got '%s'",
+				i, test.expRootURL, ep.ServiceRootURL)
+		} else if _, err := http.NewRequest("GET",
+			"https://"+ep.ServiceRootURL, nil); err != nil {
+			t.Errorf("Testcase %d: Bad ServiceRootURL: %s", i, err)
+		}
+	}
+}
+
 // This is the bulk-create version of the former
 func TestNewRedfishEPDescriptions(t *testing.T) {
 	epds, err := NewRedfishEPDescriptions(nil)
@@ -305,6 +402,129 @@ func TestNewRedfishEp(t *testing.T) {
 	}
 }
 
+// Failed subresources should be listed in DiscInfo.Errors
+func TestDiscoveryErrors(t *testing.T) {
+	payloads := map[string]string{
+		"/redfish/v1": `{
+			"Chassis": {"@odata.id": "/redfish/v1/Chassis"},
+			"Managers": {"@odata.id": "/redfish/v1/Managers"},
+			"Systems": {"@odata.id": "/redfish/v1/Systems"}}`,
+		"/redfish/v1/Chassis": `{"Members": [
+			{"@odata.id": "/redfish/v1/Chassis/Enclosure"},
+			{"@odata.id": "/redfish/v1/Chassis/Self"}]}`,
+		"/redfish/v1/Chassis/Enclosure": `{"Id": "Enclosure"`,
+		"/redfish/v1/Managers":          `{"Members": []}`,
+		"/redfish/v1/Systems":           `{"Members": []}`,
+	}
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		code := http.StatusOK
+		body, ok := payloads[req.URL.Path]
+		if !ok {
+			code = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: code,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	ep, err := NewRedfishEp(&RedfishEPDescription{
+		ID:      "x0c0s0b0",
+		Type:    "NodeBMC",
+		FQDN:    "x0c0s0b0",
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatalf("NewRedfishEp: %s", err)
+	}
+	ep.client = client
+	ep.DiscInfo.Errors = []DiscoveryError{{URI: "/stale"}}
+	ep.GetRootInfo()
+
+	expected := map[string]int{
+		"/redfish/v1/Chassis/Enclosure": http.StatusOK, // Bad JSON
+		"/redfish/v1/Chassis/Self":      http.StatusNotFound,
+	}
+	if len(ep.DiscInfo.Errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), ep.DiscInfo.Errors)
+	}
+	for _, derr := range ep.DiscInfo.Errors {
+		if code, ok := expected[derr.URI]; !ok || code != derr.HTTPStatus ||
+			derr.Error == "" {
+			t.Errorf("Unexpected error %+v", derr)
+		}
+	}
+
+	// Capped, and without the FQDN
+	ep.DiscInfo.Errors = nil
+	for i := 0; i < MaxDiscoveryErrors+5; i++ {
+		ep.addDiscoveryError("x0c0s0b0/redfish/v1/Chassis/Self", 0, nil)
+	}
+	if len(ep.DiscInfo.Errors) != MaxDiscoveryErrors {
+		t.Errorf("Expected %d errors, got %d", MaxDiscoveryErrors,
+			len(ep.DiscInfo.Errors))
+	} else if uri := ep.DiscInfo.Errors[0].URI; uri != "/redfish/v1/Chassis/Self" {
+		t.Errorf("Expected FQDN to be trimmed, got %s", uri)
+	}
+}
+
+func TestRedfishEPParts(t *testing.T) {
+	ep := &RedfishEP{
+		AccountService: &EpAccountService{},
+		Chassis: EpChassisSet{Num: 2, OIDs: map[string]*EpChassis{
+			"Perif1":    {ComponentDescription: ComponentDescription{ID: "x0c0s1"}},
+			"Enclosure": {ComponentDescription: ComponentDescription{ID: "x0c0"}},
+		}},
+		Systems: EpSystems{Num: 2, OIDs: map[string]*EpSystem{
+			"Node0": {ComponentDescription: ComponentDescription{ID: "x0c0s1b0n0"}},
+			"Node1": {ComponentDescription: ComponentDescription{ID: "x0c0s1b0n1"}},
+		}},
+		Managers: EpManagers{Num: 1, OIDs: map[string]*EpManager{
+			"BMC": {ComponentDescription: ComponentDescription{ID: "x0c0s1b0"}},
+		}},
+	}
+	ep.ID = "x0c0s1b0"
+
+	parts := ep.Parts()
+	expected := [][]string{{"x0c0"}, {"x0c0s1"}, {"x0c0s1b0n0", "x0c0s1b0n1"}, {"x0c0s1b0"}}
+	if len(parts) != len(expected) {
+		t.Fatalf("Expected %d parts, got %d", len(expected), len(parts))
+	}
+	for i, part := range parts {
+		var ids []string
+		for _, id := range []string{"Enclosure", "Perif1"} {
+			if ch, ok := part.Chassis.OIDs[id]; ok {
+				ids = append(ids, ch.ID)
+			}
+		}
+		for _, id := range []string{"Node0", "Node1"} {
+			if sys, ok := part.Systems.OIDs[id]; ok {
+				ids = append(ids, sys.ID)
+			}
+		}
+		for _, m := range part.Managers.OIDs {
+			ids = append(ids, m.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(expected[i], ",") {
+			t.Errorf("Part %d: Expected %v, got %v", i, expected[i], ids)
+		}
+		if part.ID != ep.ID {
+			t.Errorf("Part %d: Expected ID %s, got %s", i, ep.ID, part.ID)
+		}
+		if (part.AccountService != nil) != (i == 0) {
+			t.Errorf("Part %d: Services should only be in the first part", i)
+		}
+	}
+	if len(ep.Chassis.OIDs) != 2 || len(ep.Systems.OIDs) != 2 {
+		t.Errorf("Endpoint itself was changed")
+	}
+
+	// Nothing discovered, still one part
+	if parts := (&RedfishEP{}).Parts(); len(parts) != 1 {
+		t.Errorf("Expected 1 part for an empty endpoint, got %d", len(parts))
+	}
+}
+
 // Bulk/collection version of NewRedfishEp
 func TestNewRedfishEps(t *testing.T) {
 	eps, err := NewRedfishEps(nil)
@@ -846,3 +1066,39 @@ func TestGetSystemArch(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProcessorOrdinal(t *testing.T) {
+	ep := new(RedfishEP)
+	ep.ID = "x0c0s0b0"
+	s := &EpSystem{epRF: ep}
+	s.ID = "x0c0s0b0n0"
+	procs := []struct {
+		oid      string
+		procType string
+		wantID   string
+	}{
+		{"/redfish/v1/Systems/1/Processors/1", "CPU", "x0c0s0b0n0p0"},
+		{"/redfish/v1/Systems/1/Processors/2", "GPU", "x0c0s0b0n0a0"},
+		{"/redfish/v1/Systems/1/Processors/3", "CPU", "x0c0s0b0n0p1"},
+		{"/redfish/v1/Systems/1/Processors/4", "Accelerator", "x0c0s0b0n0a1"},
+		{"/redfish/v1/Systems/1/Processors/5", "GPU", "x0c0s0b0n0a2"},
+	}
+	s.Processors.OIDs = make(map[string]*EpProcessor)
+	for i, proc := range procs {
+		oid := ResourceID{Oid: proc.oid}
+		p := NewEpProcessor(s, oid, i)
+		p.ProcessorRF.ProcessorType = proc.procType
+		p.RedfishSubtype = proc.procType
+		p.LastStatus = VerifyingData
+		s.Processors.OIDs[oid.Basename()] = p
+	}
+	if err := s.Processors.discoverLocalPhase2(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for i, proc := range procs {
+		p := s.Processors.OIDs[path.Base(proc.oid)]
+		if p.ID != proc.wantID {
+			t.Errorf("Test %d Failed: Expected ID '%s'; Received '%s'", i, proc.wantID, p.ID)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+// Minimized test fixtures
+//
+// With GEN_TEST_PAYLOADS every Redfish payload of one endpoint is dumped as
+// a mock for the unit tests.  Those dumps are large and full of serial
+// numbers, so for a new BMC model it is more useful to dump just the parts
+// discovery actually looks at.  When minimizing is turned on, each payload
+// is reduced to the properties that map onto one of the structs we decode
+// Redfish into (plus @odata.id/type and the like), and serial numbers and
+// UUIDs are replaced with stable synthetic values.  What is left is the
+// structure that sets the model apart, which can be shared and checked in
+// without access to the hardware.
+/////////////////////////////////////////////////////////////////////////////
+
+var genTestingPayloadsMinimize = false
+
+// Minimize payloads dumped by GEN_TEST_PAYLOADS.  See
+// MinimizeTestingPayload().
+//
+// NOTE: Not thread safe.  Set once per process before invoking client
+// commands.
+func SetGenTestingPayloadsMinimize(on bool) {
+	genTestingPayloadsMinimize = on
+}
+
+// Properties that are replaced with synthetic values so fixtures don't
+// identify the hardware they came from.
+var fixtureRedactedProps = map[string]bool{
+	"SerialNumber": true,
+	"UUID":         true,
+}
+
+// Types we decode Redfish payloads into, other than the ones reachable
+// from RedfishEP.
+var fixtureRootTypes = []interface{}{
+	RedfishEP{},
+	GenericCollection{},
+	ResetActionInfo{},
+	RFControl{},
+	HPEAccPowerService{},
+	HPEPowerLimit{},
+	Storage{},
+	NetworkDeviceFunction{},
+	InsydeOemNcsiCollection{},
+	InsydeOemNcsiMember{},
+	InsydeOemPackage{},
+	AggregationService{},
+	Aggregate{},
+	AggregationSource{},
+	Thermal{},
+}
+
+var fixtureProps struct {
+	once   sync.Once
+	known  map[string]bool // Properties discovery decodes
+	opaque map[string]bool // ...that are kept as-is, e.g. json.RawMessage
+}
+
+var jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// Collect the JSON names of the fields of t and of every type it contains.
+func addFixtureProps(t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice ||
+		t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			addFixtureProps(f.Type, seen)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fixtureProps.known[name] = true
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft == jsonRawMessageType || ft.Kind() == reflect.Interface {
+			fixtureProps.opaque[name] = true
+		}
+		addFixtureProps(f.Type, seen)
+	}
+}
+
+func initFixtureProps() {
+	fixtureProps.known = make(map[string]bool)
+	fixtureProps.opaque = make(map[string]bool)
+	seen := make(map[reflect.Type]bool)
+	for _, v := range fixtureRootTypes {
+		addFixtureProps(reflect.TypeOf(v), seen)
+	}
+}
+
+// Stable synthetic value for a redacted property, in the same format.
+func fixtureRedact(prop, val string) string {
+	if val == "" {
+		return val
+	}
+	sum := sha1.Sum([]byte(prop + ":" + val))
+	h := hex.EncodeToString(sum[:])
+	if prop == "UUID" {
+		return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+	}
+	return "FIXTURE" + strings.ToUpper(h[0:10])
+}
+
+func minimizeFixtureValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, sub := range val {
+			if !fixtureProps.known[k] {
+				delete(val, k)
+			} else if fixtureRedactedProps[k] {
+				if s, ok := sub.(string); ok {
+					val[k] = fixtureRedact(k, s)
+				}
+			} else if !fixtureProps.opaque[k] {
+				val[k] = minimizeFixtureValue(sub)
+			}
+		}
+	case []interface{}:
+		for i, sub := range val {
+			val[i] = minimizeFixtureValue(sub)
+		}
+	}
+	return v
+}
+
+// Reduce a Redfish payload to the properties discovery decodes, replacing
+// serial numbers and UUIDs with synthetic ones.  The result is indented
+// the same way as in GETRelative().
+func MinimizeTestingPayload(payload []byte) ([]byte, error) {
+	fixtureProps.once.Do(initFixtureProps)
+
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, fmt.Errorf("can't minimize payload: %s", err)
+	}
+	var out bytes.Buffer
+	e := json.NewEncoder(&out)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "\t")
+	if err := e.Encode(minimizeFixtureValue(v)); err != nil {
+		return nil, fmt.Errorf("can't minimize payload: %s", err)
+	}
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
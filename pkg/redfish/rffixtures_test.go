@@ -0,0 +1,121 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMinimizeTestingPayload(t *testing.T) {
+	payload := `{
+		"@odata.id": "/redfish/v1/Systems/1",
+		"Id": "1",
+		"SerialNumber": "S452719X1A05123",
+		"UUID": "C4D5E6F7-1A2B-11EC-8000-3CECEFC8A1B4",
+		"NoSuchProperty": {"Id": "2"},
+		"Actions": {
+			"#ComputerSystem.Reset": {
+				"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset",
+				"ResetType@Redfish.AllowableValues": ["On", "ForceOff"],
+				"NoSuchProperty": 1
+			}
+		},
+		"Links": {
+			"Chassis": [{"@odata.id": "/redfish/v1/Chassis/1", "NoSuchProperty": 1}]
+		}
+	}`
+	minPayload, err := MinimizeTestingPayload([]byte(payload))
+	if err != nil {
+		t.Fatalf("FAIL: Unexpected error: %s", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(minPayload, &got); err != nil {
+		t.Fatalf("FAIL: Bad output JSON: %s", err)
+	}
+	sn := fixtureRedact("SerialNumber", "S452719X1A05123")
+	uuid := fixtureRedact("UUID", "C4D5E6F7-1A2B-11EC-8000-3CECEFC8A1B4")
+	exp := map[string]interface{}{
+		"@odata.id":    "/redfish/v1/Systems/1",
+		"Id":           "1",
+		"SerialNumber": sn,
+		"UUID":         uuid,
+		"Actions": map[string]interface{}{
+			"#ComputerSystem.Reset": map[string]interface{}{
+				"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset",
+				"ResetType@Redfish.AllowableValues": []interface{}{
+					"On", "ForceOff",
+				},
+			},
+		},
+		"Links": map[string]interface{}{
+			"Chassis": []interface{}{
+				map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/1"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("FAIL: Expected %v, got %v", exp, got)
+	}
+	if len(uuid) != 36 || sn == "S452719X1A05123" {
+		t.Errorf("FAIL: Bad synthetic values '%s' '%s'", sn, uuid)
+	}
+	if _, err := MinimizeTestingPayload([]byte("{")); err == nil {
+		t.Errorf("FAIL: Expected an error for bad JSON")
+	}
+}
+
+// Minimized fixtures must still discover the same way as the originals.
+func TestMinimizedFixtureDiscovery(t *testing.T) {
+	rt := NewRTFuncSupermicro1()
+	minRT := func(req *http.Request) *http.Response {
+		rsp := rt(req)
+		if rsp.StatusCode != http.StatusOK {
+			return rsp
+		}
+		body, _ := ioutil.ReadAll(rsp.Body)
+		minPayload, err := MinimizeTestingPayload(body)
+		if err != nil {
+			t.Fatalf("FAIL: %s: %s", req.URL, err)
+		}
+		if len(minPayload) >= len(body) {
+			t.Errorf("FAIL: %s: payload was not minimized", req.URL)
+		}
+		rsp.Body = ioutil.NopCloser(bytes.NewBuffer(minPayload))
+		return rsp
+	}
+	ep := TestRedfishEPInitSupermicro
+	ep.client = NewTestClient(minRT)
+	ep.GetRootInfo()
+
+	if ep.DiscInfo.LastStatus != DiscoverOK {
+		t.Fatalf("FAIL: bad LastStatus: %s", ep.DiscInfo.LastStatus)
+	}
+	if err := VerifyGetRootInfo(&ep, SupermicroVerifyInfo); err != nil {
+		t.Errorf("FAIL: verification: %s", err)
+	}
+}
@@ -0,0 +1,689 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// IPMI discovery driver
+//
+// Discovers legacy BMCs without Redfish over IPMI v1.5 LAN sessions, for
+// endpoints registered with Protocol=IPMI.  Only enough is read to track
+// the node and its BMC: the BMC's device ID and firmware version, the
+// system GUID and power state, the board/product FRU fields of FRU device
+// 0 and the number of processors from the SDR repository.  The result is
+// one NodeBMC Manager and one Node System, as if read over Redfish.
+//
+// Sessions are authenticated with MD5 or, if that's all the BMC offers, a
+// straight password, using the endpoint's User and Password at User
+// privilege.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Name the IPMI driver is registered under.
+const IPMIDriverName = "IPMI"
+
+var ErrIPMITimeout = errors.New("IPMI request timed out")
+var ErrIPMINoAuthType = errors.New("BMC supports no usable IPMI authentication type")
+
+// Upper limits on what is read from FRU and SDR.
+const (
+	ipmiMaxFRUSize    = 4096
+	ipmiMaxSDRRecords = 1024
+)
+
+// Network functions and commands
+const (
+	ipmiNetFnChassis = 0x00
+	ipmiNetFnApp     = 0x06
+	ipmiNetFnStorage = 0x0a
+
+	ipmiCmdGetChassisStatus = 0x01
+
+	ipmiCmdGetDeviceID          = 0x01
+	ipmiCmdGetSystemGUID        = 0x37
+	ipmiCmdGetChannelAuthCaps   = 0x38
+	ipmiCmdGetSessionChallenge  = 0x39
+	ipmiCmdActivateSession      = 0x3a
+	ipmiCmdCloseSession         = 0x3c
+	ipmiCmdGetFRUInventoryInfo  = 0x10
+	ipmiCmdReadFRUData          = 0x11
+	ipmiCmdReserveSDRRepository = 0x22
+	ipmiCmdGetSDR               = 0x23
+)
+
+// Authentication types
+const (
+	ipmiAuthNone     = 0x00
+	ipmiAuthMD5      = 0x02
+	ipmiAuthPassword = 0x04
+)
+
+const (
+	ipmiPrivUser   = 0x02
+	ipmiBMCAddr    = 0x20
+	ipmiRemoteSWID = 0x81
+)
+
+// SDR record types and entity IDs used for the processor count.
+const (
+	ipmiSDRFullSensor    = 0x01
+	ipmiSDRCompactSensor = 0x02
+	ipmiEntityProcessor  = 0x03
+)
+
+// Manufacturers of some common BMCs, by IANA enterprise number.
+var ipmiManufacturers = map[uint32]string{
+	2:     "IBM",
+	11:    "HPE",
+	343:   IntelMfr,
+	674:   DellMfr,
+	10876: SupermicroMfr,
+	15370: GigabyteMfr,
+	19046: LenovoMfr,
+	20974: "American Megatrends",
+}
+
+// DiscoveryDriver for legacy BMCs over IPMI v1.5 LAN.
+type IPMIDriver struct {
+	Port    int           // Default 623
+	Timeout time.Duration // Per try, default 2s
+	Retries int           // Default 2
+}
+
+// Discover the node and BMC of the endpoint over IPMI.
+func (d *IPMIDriver) Discover(ep *RedfishEP) error {
+	host := ep.FQDN
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return ErrRFDiscFQDNMissing
+	}
+	port := d.Port
+	if port <= 0 {
+		port = 623
+	}
+	c, err := newIPMIClient(net.JoinHostPort(host, strconv.Itoa(port)),
+		d.Timeout, d.Retries)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+	if err := c.openSession(ep.User, ep.Password); err != nil {
+		return err
+	}
+	defer c.closeSession()
+
+	devID, err := c.command(ipmiNetFnApp, ipmiCmdGetDeviceID)
+	if err != nil {
+		return err
+	}
+	if len(devID) < 11 {
+		return fmt.Errorf("short Get Device ID response")
+	}
+	mgr := NewEpManager(ep, ResourceID{Oid: "/ipmi/Managers/BMC"}, 0)
+	mgr.ManagerURL = ep.FQDN + mgr.OdataID
+	mgrRF := &mgr.ManagerRF
+	mgrRF.Oid = mgr.OdataID
+	mgrRF.Id = "BMC"
+	mgrRF.Name = "BMC"
+	mgrRF.ManagerType = "BMC"
+	// Major in binary, minor in BCD
+	mgrRF.FirmwareVersion = fmt.Sprintf("%d.%02x", devID[2]&0x7f, devID[3])
+	mfrID := uint32(devID[6]) | uint32(devID[7])<<8 | uint32(devID[8]&0x0f)<<16
+	if mfr, ok := ipmiManufacturers[mfrID]; ok {
+		mgrRF.Manufacturer = mfr
+	} else {
+		mgrRF.Manufacturer = fmt.Sprintf("IANA %d", mfrID)
+	}
+	mgrRF.Model = fmt.Sprintf("0x%04x", binary.LittleEndian.Uint16(devID[9:11]))
+	mgrRF.Status = StatusRF{State: "Enabled", Health: "OK"}
+
+	sys := NewEpSystem(ep, ResourceID{Oid: "/ipmi/Systems/Self"}, 0)
+	sys.SystemURL = ep.FQDN + sys.OdataID
+	sysRF := &sys.SystemRF
+	sysRF.Oid = sys.OdataID
+	sysRF.Id = "Self"
+	sysRF.Name = "System"
+	sysRF.SystemType = RFSubtypePhysical
+	sysRF.Status = StatusRF{State: "Enabled", Health: "OK"}
+	sys.ManagedBy = []ResourceID{{Oid: mgr.OdataID}}
+	sys.ManagerOID = mgr.OdataID
+	sys.ManagerType = mgrRF.ManagerType
+	mgr.ManagedSystems = []ResourceID{{Oid: sys.OdataID}}
+
+	// The rest is optional.  Failures are recorded but don't fail
+	// discovery, as with Redfish subresources.
+	status, err := c.command(ipmiNetFnChassis, ipmiCmdGetChassisStatus)
+	if err == nil && len(status) > 0 {
+		if status[0]&0x01 != 0 {
+			sysRF.PowerState = POWER_STATE_ON
+		} else {
+			sysRF.PowerState = POWER_STATE_OFF
+		}
+	} else {
+		ep.addDiscoveryError("ipmi:ChassisStatus", 0, err)
+	}
+	guid, err := c.command(ipmiNetFnApp, ipmiCmdGetSystemGUID)
+	if err == nil && len(guid) >= 16 {
+		sysRF.UUID = ipmiGUIDString(guid[:16])
+	} else if err != nil {
+		ep.addDiscoveryError("ipmi:SystemGUID", 0, err)
+	}
+	if fru, err := c.readFRU(0); err == nil {
+		sysRF.Manufacturer = fru.firstOf("ProductManufacturer", "BoardManufacturer")
+		sysRF.Model = fru.firstOf("ProductName", "BoardProductName")
+		sysRF.PartNumber = fru.firstOf("ProductPartNumber", "BoardPartNumber", "ChassisPartNumber")
+		sysRF.SerialNumber = fru.firstOf("ProductSerialNumber", "BoardSerialNumber", "ChassisSerialNumber")
+		sysRF.AssetTag = fru["ProductAssetTag"]
+		if sysRF.Model != "" {
+			sysRF.Name = sysRF.Model
+		}
+	} else {
+		ep.addDiscoveryError("ipmi:FRU/0", 0, err)
+	}
+	if n, err := c.countProcessors(); err == nil {
+		sysRF.ProcessorSummary.Count = json.Number(strconv.Itoa(n))
+	} else {
+		ep.addDiscoveryError("ipmi:SDR", 0, err)
+	}
+
+	mgr.LastStatus = VerifyingData
+	sys.LastStatus = VerifyingData
+	ep.Managers = EpManagers{Num: 1,
+		OIDs: map[string]*EpManager{mgr.BaseOdataID: mgr}}
+	ep.Systems = EpSystems{Num: 1,
+		OIDs: map[string]*EpSystem{sys.BaseOdataID: sys}}
+	ep.NumManagers, ep.NumSystems = 1, 1
+	return nil
+}
+
+// IPMI GUIDs are sent least significant byte first.
+func ipmiGUIDString(b []byte) string {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[i] = b[len(b)-1-i]
+	}
+	s := hex.EncodeToString(r)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// FRU and SDR
+/////////////////////////////////////////////////////////////////////////////
+
+// Fields from the chassis, board and product areas of a FRU, e.g.
+// "BoardSerialNumber".
+type ipmiFRUInfo map[string]string
+
+// Value of the first of the given fields that is set.
+func (f ipmiFRUInfo) firstOf(names ...string) string {
+	for _, name := range names {
+		if v := f[name]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Read and decode the given FRU device.
+func (c *ipmiClient) readFRU(id byte) (ipmiFRUInfo, error) {
+	info, err := c.command(ipmiNetFnStorage, ipmiCmdGetFRUInventoryInfo, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(info) < 3 {
+		return nil, fmt.Errorf("short FRU inventory area info")
+	}
+	size := int(binary.LittleEndian.Uint16(info[0:2]))
+	if size > ipmiMaxFRUSize {
+		size = ipmiMaxFRUSize
+	}
+	words := info[2]&0x01 != 0
+	var data []byte
+	for off := 0; off < size; {
+		n := min(size-off, 16)
+		reqOff, reqN := off, n
+		if words {
+			reqOff, reqN = off/2, (n+1)/2
+		}
+		resp, err := c.command(ipmiNetFnStorage, ipmiCmdReadFRUData, id,
+			byte(reqOff), byte(reqOff>>8), byte(reqN))
+		if err != nil {
+			return nil, err
+		}
+		if len(resp) < 2 || resp[0] == 0 {
+			break
+		}
+		chunk := resp[1:]
+		if int(resp[0]) < len(chunk) && !words {
+			chunk = chunk[:resp[0]]
+		}
+		data = append(data, chunk...)
+		off += len(chunk)
+	}
+	return parseIPMIFRU(data)
+}
+
+// Decode the chassis, board and product info areas of raw FRU data.
+func parseIPMIFRU(data []byte) (ipmiFRUInfo, error) {
+	if len(data) < 8 || data[0] != 0x01 || ipmiChecksum(data[:8]) != 0 {
+		return nil, fmt.Errorf("bad FRU common header")
+	}
+	fru := make(ipmiFRUInfo)
+	areas := []struct {
+		offset int
+		skip   int // Bytes before the first field
+		prefix string
+		names  []string
+	}{
+		{int(data[2]) * 8, 3, "Chassis", []string{"PartNumber", "SerialNumber"}},
+		{int(data[3]) * 8, 6, "Board", []string{"Manufacturer", "ProductName",
+			"SerialNumber", "PartNumber"}},
+		{int(data[4]) * 8, 3, "Product", []string{"Manufacturer", "Name",
+			"PartNumber", "Version", "SerialNumber", "AssetTag"}},
+	}
+	for _, area := range areas {
+		if area.offset == 0 || area.offset+2 > len(data) {
+			continue
+		}
+		end := area.offset + int(data[area.offset+1])*8
+		if end > len(data) || end <= area.offset+area.skip {
+			continue
+		}
+		fields := data[area.offset+area.skip : end]
+		for _, name := range area.names {
+			if len(fields) == 0 || fields[0] == 0xc1 {
+				break
+			}
+			n := int(fields[0] & 0x3f)
+			if 1+n > len(fields) {
+				break
+			}
+			if v := decodeIPMIField(fields[0]>>6, fields[1:1+n]); v != "" {
+				fru[area.prefix+name] = v
+			}
+			fields = fields[1+n:]
+		}
+	}
+	return fru, nil
+}
+
+// Decode a FRU field of the given type code.
+func decodeIPMIField(typ byte, b []byte) string {
+	switch typ {
+	case 0: // Binary
+		return hex.EncodeToString(b)
+	case 1: // BCD plus
+		const digits = "0123456789 -.:,_"
+		var s strings.Builder
+		for _, c := range b {
+			s.WriteByte(digits[c>>4])
+			s.WriteByte(digits[c&0x0f])
+		}
+		return strings.TrimSpace(s.String())
+	case 2: // 6-bit packed ASCII
+		var s strings.Builder
+		var acc, nbits uint
+		for _, c := range b {
+			acc |= uint(c) << nbits
+			nbits += 8
+			for nbits >= 6 {
+				s.WriteByte(byte(acc&0x3f) + 0x20)
+				acc >>= 6
+				nbits -= 6
+			}
+		}
+		return strings.TrimSpace(s.String())
+	}
+	return strings.TrimSpace(string(bytes.TrimRight(b, "\x00")))
+}
+
+// Count the processors with sensors in the SDR repository, i.e. the
+// distinct instances of the processor entity.
+func (c *ipmiClient) countProcessors() (int, error) {
+	resv, err := c.command(ipmiNetFnStorage, ipmiCmdReserveSDRRepository)
+	if err != nil {
+		return 0, err
+	}
+	if len(resv) < 2 {
+		return 0, fmt.Errorf("short SDR reservation")
+	}
+	procs := make(map[byte]bool)
+	id := [2]byte{0, 0}
+	for i := 0; i < ipmiMaxSDRRecords; i++ {
+		hdr, err := c.command(ipmiNetFnStorage, ipmiCmdGetSDR,
+			resv[0], resv[1], id[0], id[1], 0, 5)
+		if err != nil {
+			return 0, err
+		}
+		if len(hdr) < 7 {
+			return 0, fmt.Errorf("short SDR record header")
+		}
+		next := [2]byte{hdr[0], hdr[1]}
+		if typ := hdr[5]; typ == ipmiSDRFullSensor || typ == ipmiSDRCompactSensor {
+			// Key and the start of the body: owner, LUN, number,
+			// entity ID, entity instance, ...
+			body, err := c.command(ipmiNetFnStorage, ipmiCmdGetSDR,
+				resv[0], resv[1], id[0], id[1], 5, 5)
+			if err != nil {
+				return 0, err
+			}
+			if len(body) >= 7 && body[5] == ipmiEntityProcessor {
+				procs[body[6]&0x7f] = true
+			}
+		}
+		if next == [2]byte{0xff, 0xff} {
+			break
+		}
+		id = next
+	}
+	return len(procs), nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Minimal IPMI v1.5 LAN client
+/////////////////////////////////////////////////////////////////////////////
+
+type ipmiClient struct {
+	conn    net.Conn
+	timeout time.Duration
+	retries int
+
+	authType  byte
+	password  [16]byte
+	sessionID uint32
+	seq       uint32 // Session sequence number, 0 outside of a session
+	rqSeq     byte   // Request sequence number, 6 bits
+}
+
+func newIPMIClient(addr string, timeout time.Duration, retries int) (*ipmiClient, error) {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	if retries <= 0 {
+		retries = 2
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &ipmiClient{conn: conn, timeout: timeout, retries: retries,
+		rqSeq: byte(rand.Intn(64))}, nil
+}
+
+func (c *ipmiClient) close() {
+	c.conn.Close()
+}
+
+// Log in at User privilege.
+func (c *ipmiClient) openSession(user, password string) error {
+	if len(user) > 16 || len(password) > 16 {
+		return fmt.Errorf("IPMI v1.5 user names and passwords are at most 16 bytes")
+	}
+	copy(c.password[:], password)
+
+	caps, err := c.command(ipmiNetFnApp, ipmiCmdGetChannelAuthCaps,
+		0x0e, ipmiPrivUser)
+	if err != nil {
+		return err
+	}
+	if len(caps) < 2 {
+		return fmt.Errorf("short channel authentication capabilities")
+	}
+	switch {
+	case caps[1]&(1<<ipmiAuthMD5) != 0:
+		c.authType = ipmiAuthMD5
+	case caps[1]&(1<<ipmiAuthPassword) != 0:
+		c.authType = ipmiAuthPassword
+	case caps[1]&(1<<ipmiAuthNone) != 0:
+		c.authType = ipmiAuthNone
+	default:
+		return ErrIPMINoAuthType
+	}
+
+	var userName [16]byte
+	copy(userName[:], user)
+	chal, err := c.command(ipmiNetFnApp, ipmiCmdGetSessionChallenge,
+		append([]byte{c.authType}, userName[:]...)...)
+	if err != nil {
+		return err
+	}
+	if len(chal) < 20 {
+		return fmt.Errorf("short session challenge")
+	}
+	c.sessionID = binary.LittleEndian.Uint32(chal[0:4])
+
+	// The challenge is authenticated, but with no sequence number yet.
+	outSeq := rand.Uint32() | 1
+	req := []byte{c.authType, ipmiPrivUser}
+	req = append(req, chal[4:20]...)
+	req = binary.LittleEndian.AppendUint32(req, outSeq)
+	act, err := c.command(ipmiNetFnApp, ipmiCmdActivateSession, req...)
+	if err != nil {
+		c.sessionID = 0
+		return err
+	}
+	if len(act) < 9 {
+		c.sessionID = 0
+		return fmt.Errorf("short activate session response")
+	}
+	c.sessionID = binary.LittleEndian.Uint32(act[1:5])
+	c.seq = binary.LittleEndian.Uint32(act[5:9])
+	if c.seq == 0 {
+		c.seq = 1
+	}
+	return nil
+}
+
+// Log out, if logged in.
+func (c *ipmiClient) closeSession() {
+	if c.seq == 0 {
+		return
+	}
+	id := binary.LittleEndian.AppendUint32(nil, c.sessionID)
+	c.command(ipmiNetFnApp, ipmiCmdCloseSession, id...)
+	c.seq = 0
+	c.sessionID = 0
+}
+
+// Send a command to the BMC, retrying on timeouts, and return the response
+// data after the completion code.  A non-zero completion code is an error.
+func (c *ipmiClient) command(netFn, cmd byte, data ...byte) ([]byte, error) {
+	c.rqSeq = (c.rqSeq + 1) & 0x3f
+	msg := encodeIPMIRequest(netFn, cmd, c.rqSeq, data)
+	authType := c.authType
+	if c.sessionID == 0 {
+		authType = ipmiAuthNone // Still setting up the session
+	}
+	pkt := encodeIPMIPacket(authType, c.seq, c.sessionID, c.password, msg)
+	if c.seq != 0 {
+		c.seq++
+		if c.seq == 0 {
+			c.seq = 1
+		}
+	}
+
+	buf := make([]byte, 1024)
+	for try := 0; try <= c.retries; try++ {
+		if _, err := c.conn.Write(pkt); err != nil {
+			return nil, err
+		}
+		c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+		for {
+			n, err := c.conn.Read(buf)
+			if err != nil {
+				var nerr net.Error
+				if errors.As(err, &nerr) && nerr.Timeout() {
+					break
+				}
+				return nil, err
+			}
+			rsp, err := decodeIPMIResponse(buf[:n])
+			if err != nil || rsp.netFn != netFn|1 || rsp.cmd != cmd ||
+				rsp.rqSeq != c.rqSeq {
+				continue // Not ours, or garbage
+			}
+			if rsp.ccode != 0 {
+				return nil, fmt.Errorf("IPMI netfn 0x%02x command 0x%02x: "+
+					"completion code 0x%02x", netFn, cmd, rsp.ccode)
+			}
+			return rsp.data, nil
+		}
+	}
+	return nil, ErrIPMITimeout
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Encoding
+/////////////////////////////////////////////////////////////////////////////
+
+// RMCP header for IPMI messages, no acknowledgement wanted.
+var ipmiRMCPHeader = []byte{0x06, 0x00, 0xff, 0x07}
+
+type ipmiMessage struct {
+	authType  byte
+	seq       uint32
+	sessionID uint32
+	netFn     byte
+	rqSeq     byte
+	cmd       byte
+	ccode     byte   // Responses only
+	data      []byte // After the completion code for responses
+}
+
+func ipmiChecksum(b []byte) byte {
+	var sum byte
+	for _, c := range b {
+		sum += c
+	}
+	return -sum
+}
+
+// IPMI message from remote software to the BMC.
+func encodeIPMIRequest(netFn, cmd, rqSeq byte, data []byte) []byte {
+	msg := []byte{ipmiBMCAddr, netFn << 2}
+	msg = append(msg, ipmiChecksum(msg))
+	body := append([]byte{ipmiRemoteSWID, rqSeq << 2, cmd}, data...)
+	msg = append(msg, body...)
+	return append(msg, ipmiChecksum(body))
+}
+
+// IPMI message from the BMC to remote software.
+func encodeIPMIResponse(netFn, cmd, rqSeq, ccode byte, data []byte) []byte {
+	msg := []byte{ipmiRemoteSWID, netFn << 2}
+	msg = append(msg, ipmiChecksum(msg))
+	body := append([]byte{ipmiBMCAddr, rqSeq << 2, cmd, ccode}, data...)
+	msg = append(msg, body...)
+	return append(msg, ipmiChecksum(body))
+}
+
+// Authentication code of a v1.5 session message.
+func ipmiAuthCode(authType byte, seq, sessionID uint32, password [16]byte,
+	msg []byte) []byte {
+
+	switch authType {
+	case ipmiAuthPassword:
+		return password[:]
+	case ipmiAuthMD5:
+		h := md5.New()
+		h.Write(password[:])
+		h.Write(binary.LittleEndian.AppendUint32(nil, sessionID))
+		h.Write(msg)
+		h.Write(binary.LittleEndian.AppendUint32(nil, seq))
+		h.Write(password[:])
+		return h.Sum(nil)
+	}
+	return nil
+}
+
+// Wrap an IPMI message in the RMCP and v1.5 session headers.
+func encodeIPMIPacket(authType byte, seq, sessionID uint32, password [16]byte,
+	msg []byte) []byte {
+
+	pkt := append([]byte{}, ipmiRMCPHeader...)
+	pkt = append(pkt, authType)
+	pkt = binary.LittleEndian.AppendUint32(pkt, seq)
+	pkt = binary.LittleEndian.AppendUint32(pkt, sessionID)
+	pkt = append(pkt, ipmiAuthCode(authType, seq, sessionID, password, msg)...)
+	pkt = append(pkt, byte(len(msg)))
+	return append(pkt, msg...)
+}
+
+// Unwrap an IPMI message.  Checksums are checked but the session's
+// authentication code isn't.  The data of requests starts at ccode.
+func decodeIPMIPacket(b []byte) (*ipmiMessage, error) {
+	if len(b) < 14 || !bytes.Equal(b[:4], ipmiRMCPHeader) {
+		return nil, errors.New("IPMI: not an RMCP IPMI message")
+	}
+	m := &ipmiMessage{authType: b[4],
+		seq:       binary.LittleEndian.Uint32(b[5:9]),
+		sessionID: binary.LittleEndian.Uint32(b[9:13])}
+	b = b[13:]
+	if m.authType != ipmiAuthNone {
+		if len(b) < 16 {
+			return nil, errors.New("IPMI: truncated")
+		}
+		b = b[16:]
+	}
+	if len(b) < 1 || len(b) < 1+int(b[0]) {
+		return nil, errors.New("IPMI: truncated")
+	}
+	msg := b[1 : 1+int(b[0])]
+	if len(msg) < 7 || ipmiChecksum(msg[:2]) != msg[2] ||
+		ipmiChecksum(msg[3:len(msg)-1]) != msg[len(msg)-1] {
+		return nil, errors.New("IPMI: bad message checksum")
+	}
+	m.netFn = msg[1] >> 2
+	m.rqSeq = msg[4] >> 2
+	m.cmd = msg[5]
+	m.data = msg[6 : len(msg)-1]
+	return m, nil
+}
+
+func decodeIPMIResponse(b []byte) (*ipmiMessage, error) {
+	m, err := decodeIPMIPacket(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.data) < 1 {
+		return nil, errors.New("IPMI: no completion code")
+	}
+	m.ccode, m.data = m.data[0], m.data[1:]
+	return m, nil
+}
@@ -0,0 +1,303 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Fake IPMI v1.5 BMC on localhost with one user.  Messages with a bad
+// authentication code are dropped, as real BMCs do.
+type testIPMIBMC struct {
+	conn     net.PacketConn
+	user     string
+	password [16]byte
+	fru      []byte
+	sdrs     [][]byte // Record n has ID n
+
+	sessionID uint32
+	challenge []byte
+	active    atomic.Bool
+}
+
+func newTestIPMIBMC(t *testing.T, user, password string) *testIPMIBMC {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Can't listen: %s", err)
+	}
+	b := &testIPMIBMC{conn: conn, user: user, sessionID: 0x11223344,
+		challenge: bytes.Repeat([]byte{0x5a}, 16)}
+	copy(b.password[:], password)
+	b.fru = testIPMIFRU()
+	b.sdrs = [][]byte{
+		testIPMISDR(0, ipmiSDRFullSensor, ipmiEntityProcessor, 1),
+		testIPMISDR(1, ipmiSDRCompactSensor, ipmiEntityProcessor, 2),
+		testIPMISDR(2, ipmiSDRCompactSensor, ipmiEntityProcessor, 1),
+		testIPMISDR(3, ipmiSDRFullSensor, 0x07, 1), // System board
+		{4, 0, 0x51, 0x12, 0}, // MC device locator, no sensor
+	}
+	go b.serve()
+	t.Cleanup(func() { conn.Close() })
+	return b
+}
+
+func (b *testIPMIBMC) port() int {
+	return b.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (b *testIPMIBMC) serve() {
+	buf := make([]byte, 1024)
+	for {
+		n, from, err := b.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		req, err := decodeIPMIPacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		if req.authType != ipmiAuthNone {
+			msg := buf[30:n]
+			code := ipmiAuthCode(req.authType, req.seq, req.sessionID,
+				b.password, msg)
+			if !bytes.Equal(code, buf[13:29]) {
+				continue
+			}
+		} else if req.sessionID != 0 {
+			continue
+		}
+		ccode, data := b.handle(req)
+		rsp := encodeIPMIResponse(req.netFn|1, req.cmd, req.rqSeq, ccode, data)
+		b.conn.WriteTo(encodeIPMIPacket(ipmiAuthNone, 0, 0, [16]byte{}, rsp), from)
+	}
+}
+
+func (b *testIPMIBMC) handle(req *ipmiMessage) (byte, []byte) {
+	le32 := func(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+	d := req.data
+	if req.netFn == ipmiNetFnApp {
+		switch req.cmd {
+		case ipmiCmdGetChannelAuthCaps:
+			return 0, []byte{1, 1<<ipmiAuthMD5 | 1<<ipmiAuthPassword, 0x04, 0, 0, 0, 0, 0}
+		case ipmiCmdGetSessionChallenge:
+			if string(bytes.TrimRight(d[1:17], "\x00")) != b.user {
+				return 0x81, nil // Invalid user name
+			}
+			return 0, append(le32(0x0bad0bad), b.challenge...)
+		case ipmiCmdActivateSession:
+			if req.sessionID != 0x0bad0bad || !bytes.Equal(d[2:18], b.challenge) {
+				return 0x85, nil
+			}
+			b.active.Store(true)
+			return 0, append(append([]byte{d[0]}, le32(b.sessionID)...),
+				append(le32(1000), ipmiPrivUser)...)
+		case ipmiCmdCloseSession:
+			b.active.Store(false)
+			return 0, nil
+		}
+	}
+	if !b.active.Load() || req.sessionID != b.sessionID {
+		return 0xd4, nil // Insufficient privilege
+	}
+	switch {
+	case req.netFn == ipmiNetFnApp && req.cmd == ipmiCmdGetDeviceID:
+		// Firmware 2.41 from Intel (343), product 0x000b
+		return 0, []byte{0x20, 0x01, 0x02, 0x41, 0x51, 0xbf, 0x57, 0x01, 0x00, 0x0b, 0x00}
+	case req.netFn == ipmiNetFnApp && req.cmd == ipmiCmdGetSystemGUID:
+		return 0, []byte{0x0f, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a, 0x09, 0x08,
+			0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0x00}
+	case req.netFn == ipmiNetFnChassis && req.cmd == ipmiCmdGetChassisStatus:
+		return 0, []byte{0x01, 0x00, 0x00}
+	case req.netFn == ipmiNetFnStorage && req.cmd == ipmiCmdGetFRUInventoryInfo:
+		return 0, []byte{byte(len(b.fru)), byte(len(b.fru) >> 8), 0}
+	case req.netFn == ipmiNetFnStorage && req.cmd == ipmiCmdReadFRUData:
+		off := int(d[1]) | int(d[2])<<8
+		end := min(off+int(d[3]), len(b.fru))
+		return 0, append([]byte{byte(end - off)}, b.fru[off:end]...)
+	case req.netFn == ipmiNetFnStorage && req.cmd == ipmiCmdReserveSDRRepository:
+		return 0, []byte{0x34, 0x12}
+	case req.netFn == ipmiNetFnStorage && req.cmd == ipmiCmdGetSDR:
+		id := int(d[2]) | int(d[3])<<8
+		if d[0] != 0x34 || d[1] != 0x12 {
+			return 0xc5, nil // Reservation canceled
+		}
+		if id >= len(b.sdrs) {
+			return 0xcb, nil // Not present
+		}
+		next := []byte{byte(id + 1), 0}
+		if id == len(b.sdrs)-1 {
+			next = []byte{0xff, 0xff}
+		}
+		rec := b.sdrs[id]
+		end := min(int(d[4])+int(d[5]), len(rec))
+		return 0, append(next, rec[d[4]:end]...)
+	}
+	return 0xc1, nil // Invalid command
+}
+
+// Start of a sensor SDR, up to the sensor type.
+func testIPMISDR(id, typ, entity, instance byte) []byte {
+	return []byte{id, 0, 0x51, typ, 40, ipmiBMCAddr, 0, id, entity, instance,
+		0, 0, 0x07}
+}
+
+// FRU area with the given header, fields and checksum, padded to 8 bytes.
+func testIPMIFRUArea(hdr []byte, fields ...[]byte) []byte {
+	area := append([]byte{}, hdr...)
+	for _, f := range fields {
+		area = append(area, f...)
+	}
+	area = append(area, 0xc1)
+	for (len(area)+1)%8 != 0 {
+		area = append(area, 0)
+	}
+	area[1] = byte((len(area) + 1) / 8)
+	return append(area, ipmiChecksum(area))
+}
+
+func testIPMIASCII(s string) []byte {
+	return append([]byte{0xc0 | byte(len(s))}, s...)
+}
+
+// 6-bit packed ASCII, for a multiple of 4 characters.
+func testIPMISixBit(s string) []byte {
+	b := []byte{0x80 | byte(len(s)*3/4)}
+	for i := 0; i < len(s); i += 4 {
+		var v uint32
+		for j := 0; j < 4; j++ {
+			v |= uint32(s[i+j]-0x20) << (6 * j)
+		}
+		b = append(b, byte(v), byte(v>>8), byte(v>>16))
+	}
+	return b
+}
+
+// Board area with everything, product area without a serial number.
+func testIPMIFRU() []byte {
+	board := testIPMIFRUArea([]byte{1, 0, 0, 0, 0, 0},
+		testIPMIASCII("Intel Corporation"), testIPMIASCII("S2600WT2R"),
+		testIPMISixBit("BQWL12345678"), testIPMIASCII("H48104-850"))
+	product := testIPMIFRUArea([]byte{1, 0, 0},
+		testIPMIASCII("Intel Corporation"), testIPMIASCII("R1208WT2GSR"),
+		testIPMIASCII("R1208WT2GSR"), testIPMIASCII("1.0"),
+		[]byte{0xc0}, testIPMIASCII("ASSET-1"))
+	hdr := []byte{1, 0, 0, 1, byte(1 + len(board)/8), 0, 0}
+	hdr = append(hdr, ipmiChecksum(hdr))
+	return append(append(hdr, board...), product...)
+}
+
+func TestIPMIDriverDiscovery(t *testing.T) {
+	bmc := newTestIPMIBMC(t, "root", "initial0")
+	if GetDiscoveryDriver("IPMI-test") == nil {
+		d := &IPMIDriver{Port: bmc.port(), Timeout: 200 * time.Millisecond,
+			Retries: 1}
+		if err := RegisterDiscoveryDriver("IPMI-test", d); err != nil {
+			t.Fatalf("Can't register driver: %s", err)
+		}
+	}
+	if err := RegisterDiscoveryDriver("redfish", &IPMIDriver{}); err == nil {
+		t.Errorf("Expected error registering a driver named Redfish")
+	}
+
+	tests := []struct {
+		protocol    string
+		password    string
+		expProtocol string
+		expErr      bool
+		expStatus   string
+	}{
+		{"Telnet", "initial0", "", true, ""},
+		{"redfish", "initial0", "", false, ""},
+		{"ipmi-test", "wrong", "IPMI-test", false, HTTPsGetFailed},
+		{"ipmi-test", "initial0", "IPMI-test", false, DiscoverOK},
+	}
+	for i, test := range tests {
+		epd, err := NewRedfishEPDescription(&RawRedfishEP{ID: "x0c0s0b0",
+			FQDN: "127.0.0.1", User: "root", Password: test.password,
+			Protocol: test.protocol})
+		if (err != nil) != test.expErr {
+			t.Errorf("Testcase %d: unexpected error result: %v", i, err)
+			continue
+		} else if err != nil {
+			continue
+		}
+		if epd.Protocol != test.expProtocol {
+			t.Errorf("Testcase %d: expected Protocol '%s', got '%s'", i,
+				test.expProtocol, epd.Protocol)
+		}
+		if test.expStatus == "" {
+			continue
+		}
+		ep, err := NewRedfishEp(epd)
+		if err != nil {
+			t.Fatalf("Testcase %d: NewRedfishEp: %s", i, err)
+		}
+		ep.GetRootInfo()
+		if ep.DiscInfo.LastStatus != test.expStatus {
+			t.Errorf("Testcase %d: expected %s, got %s", i, test.expStatus,
+				ep.DiscInfo.LastStatus)
+			continue
+		}
+		if test.expStatus != DiscoverOK {
+			if len(ep.DiscInfo.Errors) == 0 {
+				t.Errorf("Testcase %d: driver failure not recorded", i)
+			}
+			continue
+		}
+		if ep.DiscInfo.Driver != "IPMI-test" || len(ep.DiscInfo.Errors) != 0 {
+			t.Errorf("Testcase %d: expected driver IPMI-test and no errors, "+
+				"got '%s', %v", i, ep.DiscInfo.Driver, ep.DiscInfo.Errors)
+		}
+		mgr, ok := ep.Managers.OIDs["BMC"]
+		if !ok || mgr.ID != "x0c0s0b0" || mgr.Type != "NodeBMC" ||
+			mgr.LastStatus != DiscoverOK ||
+			mgr.ManagerRF.FirmwareVersion != "2.41" ||
+			mgr.ManagerRF.Manufacturer != IntelMfr {
+			t.Errorf("Testcase %d: bad manager: %+v", i, mgr)
+		}
+		sys, ok := ep.Systems.OIDs["Self"]
+		if !ok {
+			t.Errorf("Testcase %d: no system discovered", i)
+			continue
+		}
+		rf := sys.SystemRF
+		if sys.ID != "x0c0s0b0n0" || sys.Type != "Node" ||
+			sys.LastStatus != DiscoverOK || sys.State != "On" ||
+			rf.Manufacturer != "Intel Corporation" ||
+			rf.Model != "R1208WT2GSR" || rf.PartNumber != "R1208WT2GSR" ||
+			rf.SerialNumber != "BQWL12345678" || rf.AssetTag != "ASSET-1" ||
+			rf.UUID != "00010203-0405-0607-0809-0a0b0c0d0e0f" ||
+			rf.ProcessorSummary.Count != "2" {
+			t.Errorf("Testcase %d: bad system %s %s %s %s: %+v", i, sys.ID,
+				sys.Type, sys.LastStatus, sys.State, rf)
+		}
+		if bmc.active.Load() {
+			t.Errorf("Testcase %d: session not closed", i)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Vendor profiles
+//
+// A vendor profile holds the connection details that are identical for
+// every BMC of a given make, e.g. all Gigabyte nodes, so they don't need
+// to be repeated for each RedfishEndpoint.  The profile name is stored in
+// the RedfishEndpoint's TemplateID so that it can be applied again each
+// time the endpoint is discovered.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Supported values for VendorProfile.AuthStyle
+const (
+	AuthStyleBasic = "Basic" // Session or HTTP basic auth (default)
+	AuthStyleNone  = "None"  // No credentials sent
+)
+
+// Default Redfish (HTTPS) port.
+const RedfishDefaultPort = 443
+
+type VendorProfile struct {
+	Name string `json:"Name"`
+
+	// Redfish port, if not the default of 443.
+	Port int `json:"Port,omitempty"`
+
+	// One of the AuthStyle* values.  Defaults to Basic if empty.
+	AuthStyle string `json:"AuthStyle,omitempty"`
+
+	// Key of the initial credentials in the secure store, used for any
+	// endpoint created with this profile that doesn't provide its own
+	// User and Password.
+	CredentialSecret string `json:"CredentialSecret,omitempty"`
+
+	// Keys of other credentials in the secure store, tried in order during
+	// discovery if an endpoint with this profile rejects its own.
+	FallbackCredentialSecrets []string `json:"FallbackCredentialSecrets,omitempty"`
+
+	// Set of Quirk* values to enable for the whole endpoint, in addition
+	// to any from the quirk registry.
+	Quirks []string `json:"Quirks,omitempty"`
+
+	// Overrides of the default HTTP client options for endpoints with
+	// this profile, e.g. longer timeouts for slow controllers.
+	HTTPOptions *HTTPOptions `json:"HTTPOptions,omitempty"`
+
+	// Names of DiscoveryDrivers to try, in order, if an endpoint with
+	// this profile has no Redfish service, e.g. "SNMP" for older PDUs.
+	FallbackDrivers []string `json:"FallbackDrivers,omitempty"`
+}
+
+// Check a profile for bad values, normalizing case where needed.
+func (p *VendorProfile) Verify() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return fmt.Errorf("vendor profile has no Name")
+	}
+	if p.Port < 0 || p.Port > 65535 {
+		return fmt.Errorf("vendor profile %s: bad Port %d", p.Name, p.Port)
+	}
+	switch strings.ToLower(p.AuthStyle) {
+	case "", strings.ToLower(AuthStyleBasic):
+		p.AuthStyle = AuthStyleBasic
+	case strings.ToLower(AuthStyleNone):
+		p.AuthStyle = AuthStyleNone
+	default:
+		return fmt.Errorf("vendor profile %s: bad AuthStyle '%s'",
+			p.Name, p.AuthStyle)
+	}
+	for i, key := range p.FallbackCredentialSecrets {
+		p.FallbackCredentialSecrets[i] = strings.TrimSpace(key)
+		if p.FallbackCredentialSecrets[i] == "" {
+			return fmt.Errorf("vendor profile %s: empty "+
+				"FallbackCredentialSecrets entry", p.Name)
+		}
+	}
+	for _, q := range p.Quirks {
+		if !IsKnownQuirk(q) {
+			return fmt.Errorf("vendor profile %s: unknown quirk '%s'",
+				p.Name, q)
+		}
+	}
+	if p.HTTPOptions != nil {
+		if err := p.HTTPOptions.Verify(); err != nil {
+			return fmt.Errorf("vendor profile %s: %s", p.Name, err)
+		}
+	}
+	for _, name := range p.FallbackDrivers {
+		if GetDiscoveryDriver(name) == nil {
+			return fmt.Errorf("vendor profile %s: unknown discovery "+
+				"driver '%s'", p.Name, name)
+		}
+	}
+	return nil
+}
+
+// Apply a vendor profile's connection settings to the endpoint prior
+// to discovery.  Credentials are not touched.
+func (ep *RedfishEP) SetVendorProfile(p *VendorProfile) {
+	if p == nil {
+		return
+	}
+	if p.Port != 0 && p.Port != RedfishDefaultPort {
+		ep.port = p.Port
+	} else {
+		ep.port = 0
+	}
+	ep.ServiceRootURL = ep.hostPort() + "/redfish/v1"
+	ep.authStyle = p.AuthStyle
+	ep.quirks = make(map[string]bool, len(p.Quirks))
+	for _, q := range p.Quirks {
+		ep.quirks[q] = true
+	}
+	if p.HTTPOptions != nil {
+		ep.SetHTTPOptions(p.HTTPOptions)
+	}
+	ep.fallbackDrivers = p.FallbackDrivers
+}
+
+// True if the given Quirk* is enabled for the endpoint.
+func (ep *RedfishEP) HasQuirk(quirk string) bool {
+	return ep.quirks[quirk]
+}
+
+// host[:port] used to contact the endpoint, as it goes in a URL.  A port
+// given explicitly in the FQDN wins over the profile's.
+func (ep *RedfishEP) hostPort() string {
+	hostPort := ep.FQDN
+	if ep.port != 0 {
+		if _, _, err := net.SplitHostPort(ep.FQDN); err != nil {
+			host := strings.TrimSuffix(strings.TrimPrefix(ep.FQDN, "["), "]")
+			hostPort = net.JoinHostPort(host, strconv.Itoa(ep.port))
+		}
+	}
+	// The '%' before an IPv6 zone ID must be escaped in URLs.
+	if !strings.Contains(hostPort, "%25") {
+		hostPort = strings.Replace(hostPort, "%", "%25", 1)
+	}
+	return hostPort
+}
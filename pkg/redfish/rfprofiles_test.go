@@ -0,0 +1,138 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestVendorProfileVerify(t *testing.T) {
+	tests := []struct {
+		in           VendorProfile
+		expErr       bool
+		expAuthStyle string
+	}{
+		{VendorProfile{Name: "gigabyte"}, false, AuthStyleBasic},
+		{VendorProfile{Name: "gb", Port: 8443, AuthStyle: "none"}, false, AuthStyleNone},
+		{VendorProfile{Name: "gb", Quirks: []string{QuirkNoGETRetries}}, false, AuthStyleBasic},
+		{VendorProfile{Name: " "}, true, ""},
+		{VendorProfile{Name: "gb", Port: 70000}, true, ""},
+		{VendorProfile{Name: "gb", AuthStyle: "Digest"}, true, ""},
+		{VendorProfile{Name: "gb", Quirks: []string{"NoSuchQuirk"}}, true, ""},
+		{VendorProfile{Name: "gb", FallbackCredentialSecrets: []string{"old"}}, false, AuthStyleBasic},
+		{VendorProfile{Name: "gb", FallbackCredentialSecrets: []string{"old", " "}}, true, ""},
+		{VendorProfile{Name: "pdu", FallbackDrivers: []string{"snmp"}}, false, AuthStyleBasic},
+		{VendorProfile{Name: "pdu", FallbackDrivers: []string{"Telnet"}}, true, ""},
+	}
+	for i, test := range tests {
+		err := test.in.Verify()
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Testcase %d: FAIL: Expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Testcase %d: FAIL: Unexpected error: %s", i, err)
+		} else if test.in.AuthStyle != test.expAuthStyle {
+			t.Errorf("Testcase %d: FAIL: Expected AuthStyle %s, got %s",
+				i, test.expAuthStyle, test.in.AuthStyle)
+		}
+	}
+}
+
+func TestVendorProfileGET(t *testing.T) {
+	tests := []struct {
+		fqdn     string
+		profile  VendorProfile
+		expURL   string
+		expAuth  bool
+		expQuirk bool
+	}{{
+		fqdn:    "x0c0s0b0",
+		profile: VendorProfile{Name: "default", AuthStyle: AuthStyleBasic},
+		expURL:  "https://x0c0s0b0/redfish/v1",
+		expAuth: true,
+	}, {
+		fqdn:    "x0c0s0b0",
+		profile: VendorProfile{Name: "gb", Port: 443, AuthStyle: AuthStyleBasic},
+		expURL:  "https://x0c0s0b0/redfish/v1",
+		expAuth: true,
+	}, {
+		fqdn:    "x0c0s0b0",
+		profile: VendorProfile{Name: "gb", Port: 8443, AuthStyle: AuthStyleNone},
+		expURL:  "https://x0c0s0b0:8443/redfish/v1",
+		expAuth: false,
+	}, {
+		fqdn:    testFQDN,
+		profile: VendorProfile{Name: "v6", Port: 8443, AuthStyle: AuthStyleBasic},
+		expURL:  "https://" + testFQDN + ":8443/redfish/v1",
+		expAuth: true,
+	}, {
+		fqdn:    "x0c0s0b0.local:9443",
+		profile: VendorProfile{Name: "gb", Port: 8443, AuthStyle: AuthStyleBasic},
+		expURL:  "https://x0c0s0b0.local:9443/redfish/v1",
+		expAuth: true,
+	}, {
+		fqdn: "x0c0s0b0",
+		profile: VendorProfile{Name: "lockout", AuthStyle: AuthStyleBasic,
+			Quirks: []string{QuirkNoGETRetries}},
+		expURL:   "https://x0c0s0b0/redfish/v1",
+		expAuth:  true,
+		expQuirk: true,
+	}}
+
+	for i, test := range tests {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.String() != test.expURL {
+				t.Errorf("Testcase %d: FAIL: Expected URL %s, got %s",
+					i, test.expURL, req.URL.String())
+			}
+			_, _, hasAuth := req.BasicAuth()
+			if hasAuth != test.expAuth {
+				t.Errorf("Testcase %d: FAIL: Expected basic auth %t",
+					i, test.expAuth)
+			}
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("{}")),
+				Header:     make(http.Header),
+			}
+		})
+		ep := &RedfishEP{client: client}
+		ep.FQDN = test.fqdn
+		ep.User = "root"
+		ep.Password = "secret"
+		ep.SetVendorProfile(&test.profile)
+		if _, err := ep.GETRelative("/redfish/v1"); err != nil {
+			t.Errorf("Testcase %d: FAIL: Unexpected error: %s", i, err)
+		}
+		if ep.HasQuirk(QuirkNoGETRetries) != test.expQuirk {
+			t.Errorf("Testcase %d: FAIL: Expected %s %t",
+				i, QuirkNoGETRetries, test.expQuirk)
+		}
+	}
+}
@@ -0,0 +1,450 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Vendor quirks
+//
+// Workarounds for vendor-specific Redfish behavior are named quirks.  Which
+// quirks apply to a given System is decided by a registry of QuirkRules,
+// each of which matches on Manufacturer, Model, etc. and turns on one or
+// more quirks.  Rules compose: every matching rule contributes its quirks
+// and parameters, so a new vendor or model can usually be supported by
+// adding a rule rather than another special case in discovery.
+//
+// Endpoints can also turn on quirks directly via their VendorProfile.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Known quirks
+const (
+	// Don't retry failed GETs.  Some BMCs lock out the account after a
+	// few failed logins, and retries just make that happen sooner.
+	QuirkNoGETRetries = "NoGETRetries"
+
+	// Use basic auth for all of discovery rather than a Redfish session,
+	// e.g. if sessions can be created but their tokens aren't accepted.
+	QuirkNoSessions = "NoSessions"
+
+	// System has no EthernetInterfaces, so compute the node MACs from the
+	// lowest BMC MAC (Intel s2600, some Gigabyte BIOS/BMC versions).
+	QuirkMACFromBMCOffset = "MACFromBMCOffset"
+
+	// Power for the System is under the ProcessorModule_0 Chassis rather
+	// than the Chassis with the System's Id, and may need to be
+	// rediscovered after the node powers on.
+	QuirkProcessorModuleChassis = "ProcessorModuleChassis"
+
+	// The System's Chassis has Controls, but they aren't PowerControls.
+	QuirkSkipChassisControls = "SkipChassisControls"
+
+	// Chassis Power may be unavailable or only partially populated for a
+	// while after the node powers on, so wait and retry.
+	QuirkDelayedPowerData = "DelayedPowerData"
+
+	// Assemblies and NetworkAdapters are under the Baseboard_0 Chassis.
+	QuirkBaseboard0Assemblies = "Baseboard0Assemblies"
+
+	// System EthernetInterfaces are only available via the InsydeNcsi OEM
+	// hierarchy.
+	QuirkInsydeNcsiEthInterfaces = "InsydeNcsiEthInterfaces"
+
+	// The @Redfish.ActionInfo for a Reset action can't always be fetched,
+	// so fall back to the inline ResetType@Redfish.AllowableValues rather
+	// than failing the System or Manager.
+	QuirkOptionalActionInfo = "OptionalActionInfo"
+
+	// Manager.Reset has no ResetType, so assume ForceRestart.
+	QuirkDefaultManagerResetTypes = "DefaultManagerResetTypes"
+
+	// Only RackMount/Enclosure Chassis that link to a ComputerSystem are
+	// NodeEnclosures.  Others are e.g. storage enclosures.
+	QuirkNodeEnclosureNeedsSystem = "NodeEnclosureNeedsSystem"
+
+	// PDU Outlet Ids end in a number that isn't zero-padded (ServerTech
+	// AA1-AA24, Raritan 1-36), so order the Outlets by that number rather
+	// than lexically, where e.g. AA10 would come before AA2.
+	QuirkOutletNumericOrder = "OutletNumericOrder"
+)
+
+var knownQuirks = map[string]bool{
+	QuirkNoGETRetries:             true,
+	QuirkNoSessions:               true,
+	QuirkMACFromBMCOffset:         true,
+	QuirkProcessorModuleChassis:   true,
+	QuirkSkipChassisControls:      true,
+	QuirkDelayedPowerData:         true,
+	QuirkBaseboard0Assemblies:     true,
+	QuirkInsydeNcsiEthInterfaces:  true,
+	QuirkOptionalActionInfo:       true,
+	QuirkDefaultManagerResetTypes: true,
+	QuirkNodeEnclosureNeedsSystem: true,
+	QuirkOutletNumericOrder:       true,
+}
+
+// Known quirk parameters
+const (
+	// Redfish Id of the System EthernetInterface that is plugged into the
+	// management network, when there is more than one.
+	QuirkParamMgmtEthIfaceID = "MgmtEthInterfaceID"
+)
+
+var knownQuirkParams = map[string]bool{
+	QuirkParamMgmtEthIfaceID: true,
+}
+
+// True if quirk is one of the Quirk* values.
+func IsKnownQuirk(quirk string) bool {
+	return knownQuirks[quirk]
+}
+
+// What a QuirkRule is matched against.  Fields that are unknown at the
+// time of the lookup are left empty.
+type QuirkTarget struct {
+	Manufacturer    string
+	Model           string
+	Name            string
+	ID              string // Redfish Id
+	RedfishVersion  string // From the ServiceRoot
+	FirmwareVersion string // Of the BMC
+}
+
+// Conditions for a QuirkRule.  Empty fields match anything, non-empty
+// fields must all match.  Manufacturer uses IsManufacturer() for the
+// known *Mfr values and is otherwise a case-insensitive substring, as is
+// Model and Name.  The others are case-insensitive prefixes.
+type QuirkMatch struct {
+	Manufacturer    string `json:"Manufacturer,omitempty"`
+	Model           string `json:"Model,omitempty"`
+	Name            string `json:"Name,omitempty"`
+	IDPrefix        string `json:"IDPrefix,omitempty"`
+	RedfishVersion  string `json:"RedfishVersion,omitempty"`
+	FirmwareVersion string `json:"FirmwareVersion,omitempty"`
+}
+
+type QuirkRule struct {
+	Name   string            `json:"Name"`
+	Match  QuirkMatch        `json:"Match"`
+	Quirks []string          `json:"Quirks,omitempty"`
+	Params map[string]string `json:"Params,omitempty"`
+}
+
+// Check that a rule only uses known quirks and parameters.
+func (r *QuirkRule) Verify() error {
+	if r.Name == "" {
+		return fmt.Errorf("quirk rule has no Name")
+	}
+	if len(r.Quirks) == 0 && len(r.Params) == 0 {
+		return fmt.Errorf("quirk rule %s has no Quirks or Params", r.Name)
+	}
+	for _, q := range r.Quirks {
+		if !IsKnownQuirk(q) {
+			return fmt.Errorf("quirk rule %s: unknown quirk '%s'", r.Name, q)
+		}
+	}
+	for p := range r.Params {
+		if !knownQuirkParams[p] {
+			return fmt.Errorf("quirk rule %s: unknown param '%s'", r.Name, p)
+		}
+	}
+	return nil
+}
+
+func (m *QuirkMatch) matches(t *QuirkTarget) bool {
+	if m.Manufacturer != "" &&
+		IsManufacturer(t.Manufacturer, m.Manufacturer) != 1 &&
+		!containsFold(t.Manufacturer, m.Manufacturer) {
+		return false
+	}
+	if m.Model != "" && !containsFold(t.Model, m.Model) {
+		return false
+	}
+	if m.Name != "" && !containsFold(t.Name, m.Name) {
+		return false
+	}
+	if m.IDPrefix != "" && !hasPrefixFold(t.ID, m.IDPrefix) {
+		return false
+	}
+	if m.RedfishVersion != "" && !hasPrefixFold(t.RedfishVersion, m.RedfishVersion) {
+		return false
+	}
+	if m.FirmwareVersion != "" && !hasPrefixFold(t.FirmwareVersion, m.FirmwareVersion) {
+		return false
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
+
+// Built-in rules.  These replace what used to be hard-coded checks in
+// discovery.
+var defaultQuirkRules = []QuirkRule{
+	// Intel Buchanan Pass, Wolf Pass, etc.
+	{
+		Name:   "intel-s2600-model",
+		Match:  QuirkMatch{Model: "s2600"},
+		Quirks: []string{QuirkMACFromBMCOffset},
+	}, {
+		Name:   "intel-s2600-name",
+		Match:  QuirkMatch{Name: "s2600"},
+		Quirks: []string{QuirkMACFromBMCOffset},
+	}, {
+		Name:   "intel-qs",
+		Match:  QuirkMatch{IDPrefix: "qs"},
+		Quirks: []string{QuirkMACFromBMCOffset},
+	},
+	// Gigabyte nodes with R05 BIOS
+	{
+		Name:   "gigabyte-r272-z30-bmc12",
+		Match:  QuirkMatch{Model: "r272-z30-00", FirmwareVersion: "12"},
+		Quirks: []string{QuirkMACFromBMCOffset},
+	},
+	// Foxconn Paradise
+	{
+		Name:  "foxconn",
+		Match: QuirkMatch{Manufacturer: FoxconnMfr},
+		Quirks: []string{
+			QuirkProcessorModuleChassis,
+			QuirkSkipChassisControls,
+			QuirkDelayedPowerData,
+			QuirkBaseboard0Assemblies,
+			QuirkInsydeNcsiEthInterfaces,
+		},
+	},
+	// Dell boxes with multiple NICs
+	{
+		Name:   "dell",
+		Match:  QuirkMatch{Manufacturer: DellMfr},
+		Params: map[string]string{QuirkParamMgmtEthIfaceID: "NIC.Integrated.1-3-1"},
+	},
+	// Lenovo ThinkSystem XCC.  The XCC Manager doesn't always give a
+	// Manufacturer, so match its Model too.
+	{
+		Name:   "lenovo",
+		Match:  QuirkMatch{Manufacturer: LenovoMfr},
+		Quirks: []string{QuirkOptionalActionInfo},
+	}, {
+		Name:   "lenovo-xcc",
+		Match:  QuirkMatch{Model: "XClarity"},
+		Quirks: []string{QuirkOptionalActionInfo},
+	},
+	// SuperMicro X11/X12
+	{
+		Name:  "supermicro",
+		Match: QuirkMatch{Manufacturer: SupermicroMfr},
+		Quirks: []string{
+			QuirkOptionalActionInfo,
+			QuirkDefaultManagerResetTypes,
+			QuirkNodeEnclosureNeedsSystem,
+		},
+	},
+	// ServerTech PRO2/PRO3X and Raritan PX3 PDUs.  Newer Raritan firmware
+	// reports Legrand as the Manufacturer, so match the PX3 Model too.
+	{
+		Name:   "servertech",
+		Match:  QuirkMatch{Manufacturer: ServerTechMfr},
+		Quirks: []string{QuirkOutletNumericOrder},
+	}, {
+		Name:   "raritan",
+		Match:  QuirkMatch{Manufacturer: RaritanMfr},
+		Quirks: []string{QuirkOutletNumericOrder},
+	}, {
+		Name:   "raritan-px3",
+		Match:  QuirkMatch{Model: "PX3-"},
+		Quirks: []string{QuirkOutletNumericOrder},
+	},
+}
+
+var quirkRegistry = struct {
+	sync.RWMutex
+	rules []QuirkRule
+}{rules: defaultQuirkRules}
+
+// Add a rule to the quirk registry.  Later rules override the Params of
+// earlier ones.
+func RegisterQuirkRule(r QuirkRule) error {
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	quirkRegistry.Lock()
+	defer quirkRegistry.Unlock()
+	for _, rule := range quirkRegistry.rules {
+		if rule.Name == r.Name {
+			return fmt.Errorf("duplicate quirk rule %s", r.Name)
+		}
+	}
+	quirkRegistry.rules = append(quirkRegistry.rules, r)
+	return nil
+}
+
+// Get a copy of all registered rules, in order.
+func GetQuirkRules() []QuirkRule {
+	quirkRegistry.RLock()
+	defer quirkRegistry.RUnlock()
+	rules := make([]QuirkRule, len(quirkRegistry.rules))
+	copy(rules, quirkRegistry.rules)
+	return rules
+}
+
+// The quirks and params that apply to something, as decided by the quirk
+// registry.  A nil *QuirkSet has no quirks.
+type QuirkSet struct {
+	quirks map[string]bool
+	params map[string]string
+	rules  []string
+}
+
+// Look up the quirks for t in the registry.
+func LookupQuirks(t QuirkTarget) *QuirkSet {
+	qs := &QuirkSet{
+		quirks: make(map[string]bool),
+		params: make(map[string]string),
+	}
+	quirkRegistry.RLock()
+	defer quirkRegistry.RUnlock()
+	for i := range quirkRegistry.rules {
+		r := &quirkRegistry.rules[i]
+		if !r.Match.matches(&t) {
+			continue
+		}
+		for _, q := range r.Quirks {
+			qs.quirks[q] = true
+		}
+		for k, v := range r.Params {
+			qs.params[k] = v
+		}
+		qs.rules = append(qs.rules, r.Name)
+	}
+	return qs
+}
+
+// True if the given Quirk* is on.
+func (qs *QuirkSet) Has(quirk string) bool {
+	if qs == nil {
+		return false
+	}
+	return qs.quirks[quirk]
+}
+
+// Value of the given QuirkParam*, or "" if not set.
+func (qs *QuirkSet) Param(param string) string {
+	if qs == nil {
+		return ""
+	}
+	return qs.params[param]
+}
+
+// Names of the rules that matched, in registry order.
+func (qs *QuirkSet) Rules() []string {
+	if qs == nil {
+		return nil
+	}
+	return qs.rules
+}
+
+// Look up quirks for t, adding any quirks turned on for the whole endpoint
+// by its VendorProfile.  If t has no Manufacturer, e.g. for most Managers,
+// the ServiceRoot's Vendor is used.
+func (ep *RedfishEP) lookupQuirks(t QuirkTarget) *QuirkSet {
+	t.RedfishVersion = ep.ServiceRootRF.RedfishVersion
+	if t.Manufacturer == "" {
+		t.Manufacturer = ep.ServiceRootRF.Vendor
+	}
+	qs := LookupQuirks(t)
+	for q := range ep.quirks {
+		qs.quirks[q] = true
+	}
+	return qs
+}
+
+// Quirks for the System, looked up once SystemRF is available.
+func (s *EpSystem) Quirks() *QuirkSet {
+	if s.quirks == nil {
+		s.quirks = s.epRF.lookupQuirks(s.quirkTarget())
+	}
+	return s.quirks
+}
+
+func (s *EpSystem) quirkTarget() QuirkTarget {
+	return QuirkTarget{
+		Manufacturer: s.SystemRF.Manufacturer,
+		Model:        s.SystemRF.Model,
+		Name:         s.SystemRF.Name,
+		ID:           s.SystemRF.Id,
+	}
+}
+
+// Quirks for the Manager, looked up once ManagerRF is available.
+func (m *EpManager) Quirks() *QuirkSet {
+	if m.quirks == nil {
+		m.quirks = m.epRF.lookupQuirks(QuirkTarget{
+			Manufacturer:    m.ManagerRF.Manufacturer,
+			Model:           m.ManagerRF.Model,
+			Name:            m.ManagerRF.Name,
+			ID:              m.ManagerRF.Id,
+			FirmwareVersion: m.ManagerRF.FirmwareVersion,
+		})
+	}
+	return m.quirks
+}
+
+// Quirks for the Chassis, looked up once ChassisRF is available.
+func (c *EpChassis) Quirks() *QuirkSet {
+	if c.quirks == nil {
+		c.quirks = c.epRF.lookupQuirks(QuirkTarget{
+			Manufacturer: c.ChassisRF.Manufacturer,
+			Model:        c.ChassisRF.Model,
+			Name:         c.ChassisRF.Name,
+			ID:           c.ChassisRF.Id,
+		})
+	}
+	return c.quirks
+}
+
+// Quirks for the PDU, looked up once PowerDistributionRF is available.
+// Its Outlets use the same set.
+func (pdu *EpPDU) Quirks() *QuirkSet {
+	if pdu.quirks == nil {
+		pdu.quirks = pdu.epRF.lookupQuirks(QuirkTarget{
+			Manufacturer:    pdu.PowerDistributionRF.Manufacturer,
+			Model:           pdu.PowerDistributionRF.Model,
+			Name:            pdu.PowerDistributionRF.Name,
+			ID:              pdu.PowerDistributionRF.Id,
+			FirmwareVersion: pdu.PowerDistributionRF.FirmwareVersion,
+		})
+	}
+	return pdu.quirks
+}
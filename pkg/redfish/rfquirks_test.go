@@ -0,0 +1,177 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"testing"
+)
+
+func TestLookupQuirks(t *testing.T) {
+	tests := []struct {
+		target    QuirkTarget
+		expQuirks []string
+		expNot    []string
+		expMgmtID string
+	}{{
+		target:    QuirkTarget{Manufacturer: "Intel Corporation", Model: "S2600WFT"},
+		expQuirks: []string{QuirkMACFromBMCOffset},
+		expNot:    []string{QuirkProcessorModuleChassis},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Intel", Name: "s2600bpb", ID: "QSBP82704374"},
+		expQuirks: []string{QuirkMACFromBMCOffset},
+	}, {
+		// Gigabyte R272-Z30-00 needs the known bad BMC firmware
+		target: QuirkTarget{Manufacturer: "GIGABYTE", Model: "R272-Z30-00"},
+		expNot: []string{QuirkMACFromBMCOffset},
+	}, {
+		target: QuirkTarget{Manufacturer: "GIGABYTE", Model: "R272-Z30-00",
+			FirmwareVersion: "12.61.17"},
+		expQuirks: []string{QuirkMACFromBMCOffset},
+	}, {
+		target: QuirkTarget{Manufacturer: "Foxconn", Model: "HPE Cray Supercomputing XD224"},
+		expQuirks: []string{
+			QuirkProcessorModuleChassis,
+			QuirkSkipChassisControls,
+			QuirkDelayedPowerData,
+			QuirkBaseboard0Assemblies,
+			QuirkInsydeNcsiEthInterfaces,
+		},
+		expNot: []string{QuirkMACFromBMCOffset},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Dell Inc.", Model: "PowerEdge R640"},
+		expNot:    []string{QuirkMACFromBMCOffset, QuirkSkipChassisControls},
+		expMgmtID: "NIC.Integrated.1-3-1",
+	}, {
+		target: QuirkTarget{Manufacturer: "Cray Inc.", Model: "WindomNodeCard", ID: "Node0"},
+		expNot: []string{QuirkMACFromBMCOffset, QuirkSkipChassisControls, QuirkOptionalActionInfo},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Lenovo", Model: "7X06CTO1WW", ID: "1"},
+		expQuirks: []string{QuirkOptionalActionInfo},
+		expNot:    []string{QuirkMACFromBMCOffset},
+	}, {
+		// XCC Manager, no Manufacturer
+		target:    QuirkTarget{Model: "Lenovo XClarity Controller", Name: "XCC", ID: "1"},
+		expQuirks: []string{QuirkOptionalActionInfo},
+	}, {
+		target: QuirkTarget{Manufacturer: "Supermicro", Model: "SYS-620U-TNR", ID: "1"},
+		expQuirks: []string{
+			QuirkOptionalActionInfo,
+			QuirkDefaultManagerResetTypes,
+			QuirkNodeEnclosureNeedsSystem,
+		},
+		expNot: []string{QuirkMACFromBMCOffset},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Dell Inc.", Model: "PowerEdge R640"},
+		expNot:    []string{QuirkDefaultManagerResetTypes, QuirkNodeEnclosureNeedsSystem},
+		expMgmtID: "NIC.Integrated.1-3-1",
+	}, {
+		target:    QuirkTarget{Manufacturer: "Server Technology", Model: "C2WG36MS", ID: "1"},
+		expQuirks: []string{QuirkOutletNumericOrder},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Raritan", Model: "PX3-5190R", ID: "1"},
+		expQuirks: []string{QuirkOutletNumericOrder},
+	}, {
+		target:    QuirkTarget{Manufacturer: "Legrand", Model: "PX3-5145R", ID: "1"},
+		expQuirks: []string{QuirkOutletNumericOrder},
+	}, {
+		// RTS PDU
+		target: QuirkTarget{Manufacturer: "Contoso", Model: "ZAP4000", ID: "1"},
+		expNot: []string{QuirkOutletNumericOrder},
+	}}
+
+	for i, test := range tests {
+		qs := LookupQuirks(test.target)
+		for _, q := range test.expQuirks {
+			if !qs.Has(q) {
+				t.Errorf("Testcase %d: FAIL: Expected quirk %s (rules %v)",
+					i, q, qs.Rules())
+			}
+		}
+		for _, q := range test.expNot {
+			if qs.Has(q) {
+				t.Errorf("Testcase %d: FAIL: Unexpected quirk %s (rules %v)",
+					i, q, qs.Rules())
+			}
+		}
+		if qs.Param(QuirkParamMgmtEthIfaceID) != test.expMgmtID {
+			t.Errorf("Testcase %d: FAIL: Expected %s '%s', got '%s'",
+				i, QuirkParamMgmtEthIfaceID, test.expMgmtID,
+				qs.Param(QuirkParamMgmtEthIfaceID))
+		}
+	}
+
+	var nilQS *QuirkSet
+	if nilQS.Has(QuirkMACFromBMCOffset) || nilQS.Param(QuirkParamMgmtEthIfaceID) != "" {
+		t.Errorf("FAIL: nil QuirkSet should have no quirks")
+	}
+}
+
+func TestRegisterQuirkRule(t *testing.T) {
+	good := QuirkRule{
+		Name:   "test-acme-rf1",
+		Match:  QuirkMatch{Manufacturer: "Acme", RedfishVersion: "1.0"},
+		Quirks: []string{QuirkMACFromBMCOffset},
+	}
+	bad := []QuirkRule{
+		{Match: QuirkMatch{Model: "x"}, Quirks: []string{QuirkMACFromBMCOffset}},
+		{Name: "test-noquirks", Match: QuirkMatch{Model: "x"}},
+		{Name: "test-badquirk", Quirks: []string{"NoSuchQuirk"}},
+		{Name: "test-badparam", Params: map[string]string{"NoSuchParam": "x"}},
+		good,
+	}
+	if err := RegisterQuirkRule(good); err != nil {
+		t.Fatalf("FAIL: Unexpected error: %s", err)
+	}
+	for i, r := range bad {
+		if err := RegisterQuirkRule(r); err == nil {
+			t.Errorf("Testcase %d: FAIL: Expected an error", i)
+		}
+	}
+
+	target := QuirkTarget{Manufacturer: "ACME Computers", RedfishVersion: "1.0.6"}
+	if !LookupQuirks(target).Has(QuirkMACFromBMCOffset) {
+		t.Errorf("FAIL: Registered rule did not match")
+	}
+	target.RedfishVersion = "1.11.0"
+	if LookupQuirks(target).Has(QuirkMACFromBMCOffset) {
+		t.Errorf("FAIL: Registered rule matched wrong RedfishVersion")
+	}
+
+	// Endpoint-wide quirks from a VendorProfile are added to the registry's.
+	ep := &RedfishEP{}
+	ep.SetVendorProfile(&VendorProfile{Name: "p", Quirks: []string{QuirkNoGETRetries}})
+	qs := ep.lookupQuirks(QuirkTarget{Manufacturer: "Foxconn"})
+	if !qs.Has(QuirkNoGETRetries) || !qs.Has(QuirkSkipChassisControls) {
+		t.Errorf("FAIL: Expected endpoint and registry quirks, got rules %v",
+			qs.Rules())
+	}
+
+	// Managers with no Manufacturer fall back to the ServiceRoot Vendor.
+	ep = &RedfishEP{}
+	ep.ServiceRootRF.Vendor = "Supermicro"
+	qs = ep.lookupQuirks(QuirkTarget{Name: "Manager", ID: "1"})
+	if !qs.Has(QuirkDefaultManagerResetTypes) {
+		t.Errorf("FAIL: Expected quirks from ServiceRoot Vendor, got rules %v",
+			qs.Rules())
+	}
+}
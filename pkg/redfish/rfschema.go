@@ -0,0 +1,447 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Schema validation
+//
+// In strict mode, every resource fetched during discovery is checked
+// against the DMTF JSON schema for its @odata.type, and any violations are
+// recorded in the endpoint's DiscoveryInfo so that noncompliant firmware
+// can be found without external tooling.  It does not change what is
+// discovered.
+//
+// A trimmed set of the DMTF schemas covering the resources and properties
+// discovery uses is built in (see schemas/).  Since they are partial,
+// they don't flag unknown properties.  Full DMTF schema files, e.g. from
+// DSP8010, can be loaded from a directory with LoadSchemaDir(), and take
+// precedence over the built-in ones.  Only the JSON schema keywords the
+// DMTF schemas use for validation are supported.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// If true, resources fetched during discovery are validated against their
+// schemas.
+var rfSchemaValidation = false
+
+// Turn schema validation of discovered resources on or off.
+// NOTE: Global, to be called only once at startup.
+func SetSchemaValidation(on bool) {
+	rfSchemaValidation = on
+}
+
+// Check whether discovered resources are validated against their schemas.
+func GetSchemaValidation() bool {
+	return rfSchemaValidation
+}
+
+// A property of a discovered resource that doesn't match its schema.
+type SchemaViolation struct {
+	URI       string `json:"URI"`
+	ODataType string `json:"ODataType"`
+	Property  string `json:"Property,omitempty"` // e.g. Status.Health, empty for the resource
+	Error     string `json:"Error"`
+}
+
+// Most SchemaViolations kept for an endpoint.
+const MaxSchemaViolations = 100
+
+//go:embed schemas/*.json
+var builtinSchemas embed.FS
+
+// Schema documents by file name, e.g. ComputerSystem.v1_5_0.json
+type schemaRegistry struct {
+	lock sync.Mutex
+	docs map[string]map[string]interface{}
+	res  map[string]*regexp.Regexp // Compiled patterns
+}
+
+var rfSchemas = &schemaRegistry{}
+var rfSchemasOnce sync.Once
+
+// Registry with the built-in schemas loaded.
+func getSchemas() *schemaRegistry {
+	rfSchemasOnce.Do(func() {
+		rfSchemas.lock.Lock()
+		defer rfSchemas.lock.Unlock()
+		if rfSchemas.docs == nil {
+			rfSchemas.docs = make(map[string]map[string]interface{})
+			rfSchemas.res = make(map[string]*regexp.Regexp)
+		}
+		entries, _ := builtinSchemas.ReadDir("schemas")
+		for _, e := range entries {
+			data, err := builtinSchemas.ReadFile("schemas/" + e.Name())
+			if err != nil {
+				continue
+			}
+			var doc map[string]interface{}
+			if err := json.Unmarshal(data, &doc); err != nil {
+				errlog.Printf("Bad built-in schema %s: %s", e.Name(), err)
+				continue
+			}
+			if _, ok := rfSchemas.docs[e.Name()]; !ok {
+				rfSchemas.docs[e.Name()] = doc
+			}
+		}
+	})
+	return rfSchemas
+}
+
+// Load the *.json schema files in dir, replacing any built-in schemas of
+// the same name.
+// NOTE: Global, to be called only once at startup.
+func LoadSchemaDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no schema files in %s", dir)
+	}
+	docs := make(map[string]map[string]interface{}, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("%s: %s", f, err)
+		}
+		docs[filepath.Base(f)] = doc
+	}
+	reg := getSchemas()
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	for name, doc := range docs {
+		reg.docs[name] = doc
+	}
+	return nil
+}
+
+// Find the definition a $ref points to, e.g.
+// http://redfish.dmtf.org/schemas/v1/Resource.json#/definitions/Status, or
+// #/definitions/Health within doc.  Returns the document it is in too.  nil
+// if the schema isn't available, in which case anything is allowed.
+func (reg *schemaRegistry) resolve(doc map[string]interface{}, ref string) (map[string]interface{}, map[string]interface{}) {
+	file, ptr, _ := strings.Cut(ref, "#")
+	if file != "" {
+		doc = reg.docs[path.Base(file)]
+	}
+	if doc == nil {
+		return nil, nil
+	}
+	var cur interface{} = doc
+	for _, key := range strings.Split(strings.Trim(ptr, "/"), "/") {
+		if key == "" {
+			continue
+		}
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		cur = m[key]
+	}
+	def, _ := cur.(map[string]interface{})
+	return def, doc
+}
+
+// Schema definition for an @odata.type, e.g.
+// #ComputerSystem.v1_5_0.ComputerSystem.  The versioned schema file is used
+// if it is available, otherwise the unversioned one.
+func (reg *schemaRegistry) lookup(odataType string) (map[string]interface{}, map[string]interface{}) {
+	t := strings.TrimPrefix(odataType, "#")
+	dot := strings.LastIndex(t, ".")
+	if dot <= 0 {
+		return nil, nil
+	}
+	ns, name := t[:dot], t[dot+1:]
+	files := []string{ns + ".json"}
+	if i := strings.Index(ns, "."); i > 0 {
+		files = append(files, ns[:i]+".json")
+	}
+	for _, f := range files {
+		if def, doc := reg.resolve(nil, f+"#/definitions/"+name); def != nil {
+			return def, doc
+		}
+	}
+	return nil, nil
+}
+
+func (reg *schemaRegistry) regexp(pattern string) *regexp.Regexp {
+	if re, ok := reg.res[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		// Not RE2 compatible.  Don't check it rather than fail.
+		errlog.Printf("Unsupported schema pattern '%s': %s", pattern, err)
+		re = nil
+	}
+	reg.res[pattern] = re
+	return re
+}
+
+// JSON schema type of a decoded value
+func jsonType(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
+
+// Errors found checking val at prop against the schema definition def from
+// doc.  depth guards against $ref loops.
+func (reg *schemaRegistry) validate(
+	doc, def map[string]interface{},
+	val interface{},
+	prop string,
+	depth int,
+) []SchemaViolation {
+	if def == nil || depth > 32 {
+		return nil
+	}
+	fail := func(format string, a ...interface{}) []SchemaViolation {
+		return []SchemaViolation{{Property: prop, Error: fmt.Sprintf(format, a...)}}
+	}
+
+	if ref, ok := def["$ref"].(string); ok {
+		rdef, rdoc := reg.resolve(doc, ref)
+		return reg.validate(rdoc, rdef, val, prop, depth+1)
+	}
+	for _, kw := range []string{"anyOf", "oneOf"} {
+		alts, ok := def[kw].([]interface{})
+		if !ok || len(alts) == 0 {
+			continue
+		}
+		var first []SchemaViolation
+		for i, alt := range alts {
+			adef, _ := alt.(map[string]interface{})
+			errs := reg.validate(doc, adef, val, prop, depth+1)
+			if len(errs) == 0 {
+				first = nil
+				break
+			} else if i == 0 {
+				first = errs
+			}
+		}
+		if first != nil {
+			return first
+		}
+	}
+
+	vt := jsonType(val)
+	switch t := def["type"].(type) {
+	case string:
+		if !typeMatches(t, vt) {
+			return fail("expected %s, got %s", t, vt)
+		}
+	case []interface{}:
+		ok := false
+		names := make([]string, 0, len(t))
+		for _, tt := range t {
+			s, _ := tt.(string)
+			names = append(names, s)
+			ok = ok || typeMatches(s, vt)
+		}
+		if !ok {
+			return fail("expected %s, got %s", strings.Join(names, " or "), vt)
+		}
+	}
+	if enum, ok := def["enum"].([]interface{}); ok && val != nil {
+		found := false
+		names := make([]string, 0, len(enum))
+		for _, e := range enum {
+			names = append(names, fmt.Sprint(e))
+			found = found || e == val
+		}
+		if !found {
+			return fail("'%v' is not one of %s", val, strings.Join(names, ", "))
+		}
+	}
+
+	var errs []SchemaViolation
+	switch x := val.(type) {
+	case string:
+		if p, ok := def["pattern"].(string); ok {
+			if re := reg.regexp(p); re != nil && !re.MatchString(x) {
+				return fail("'%s' does not match %s", x, p)
+			}
+		}
+	case float64:
+		if min, ok := def["minimum"].(float64); ok && x < min {
+			return fail("%v is less than %v", x, min)
+		}
+		if max, ok := def["maximum"].(float64); ok && x > max {
+			return fail("%v is more than %v", x, max)
+		}
+	case []interface{}:
+		if idef, ok := def["items"].(map[string]interface{}); ok {
+			for i, item := range x {
+				errs = append(errs, reg.validate(doc, idef, item,
+					fmt.Sprintf("%s[%d]", prop, i), depth+1)...)
+			}
+		}
+	case map[string]interface{}:
+		if req, ok := def["required"].([]interface{}); ok {
+			for _, r := range req {
+				name, _ := r.(string)
+				if _, ok := x[name]; !ok {
+					errs = append(errs, SchemaViolation{
+						Property: joinProp(prop, name),
+						Error:    "required property missing",
+					})
+				}
+			}
+		}
+		props, _ := def["properties"].(map[string]interface{})
+		pprops, _ := def["patternProperties"].(map[string]interface{})
+		addl, _ := def["additionalProperties"].(bool)
+		_, hasAddl := def["additionalProperties"]
+		names := make([]string, 0, len(x))
+		for name := range x {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p := joinProp(prop, name)
+			if pdef, ok := props[name].(map[string]interface{}); ok {
+				errs = append(errs, reg.validate(doc, pdef, x[name], p, depth+1)...)
+				continue
+			}
+			matched := false
+			for pat, pp := range pprops {
+				if re := reg.regexp(pat); re != nil && re.MatchString(name) {
+					matched = true
+					pdef, _ := pp.(map[string]interface{})
+					errs = append(errs, reg.validate(doc, pdef, x[name], p, depth+1)...)
+				}
+			}
+			if !matched && hasAddl && !addl {
+				errs = append(errs, SchemaViolation{
+					Property: p,
+					Error:    "property not defined by schema",
+				})
+			}
+		}
+	}
+	return errs
+}
+
+func typeMatches(schemaType, valType string) bool {
+	return schemaType == valType ||
+		(schemaType == "number" && valType == "integer")
+}
+
+func joinProp(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Validate a resource against the schema for its @odata.type.  Returns
+// nothing if there is no schema for it.
+func ValidateResource(body []byte) []SchemaViolation {
+	var val map[string]interface{}
+	if err := json.Unmarshal(body, &val); err != nil {
+		return []SchemaViolation{{Error: err.Error()}}
+	}
+	odataType, _ := val["@odata.type"].(string)
+	if odataType == "" {
+		return nil
+	}
+	reg := getSchemas()
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+	def, doc := reg.lookup(odataType)
+	if def == nil {
+		return nil
+	}
+	errs := reg.validate(doc, def, val, "", 0)
+	for i := range errs {
+		errs[i].ODataType = odataType
+	}
+	return errs
+}
+
+// Validate a resource fetched from rpath and note any violations in
+// DiscInfo.SchemaViolations.
+func (ep *RedfishEP) checkSchema(rpath string, body []byte) {
+	errs := ValidateResource(body)
+	if len(errs) == 0 {
+		return
+	}
+	errlog.Printf("%s: %s: %d schema violation(s)", ep.ID, rpath, len(errs))
+
+	uri := ep.trimURI(rpath)
+	discErrLock.Lock()
+	defer discErrLock.Unlock()
+	for _, e := range errs {
+		if len(ep.DiscInfo.SchemaViolations) >= MaxSchemaViolations {
+			break
+		}
+		// Some resources are fetched more than once, e.g. while waiting
+		// for a system to settle.
+		dup := false
+		for _, old := range ep.DiscInfo.SchemaViolations {
+			if old.URI == uri && old.Property == e.Property {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			e.URI = uri
+			ep.DiscInfo.SchemaViolations = append(ep.DiscInfo.SchemaViolations, e)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateResource(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		props []string // Properties with violations, in order
+	}{
+		{"valid", `{
+			"@odata.id": "/redfish/v1/Systems/Self",
+			"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+			"Id": "Self", "Name": "System",
+			"PowerState": "On",
+			"ProcessorSummary": {"Count": 2},
+			"Status": {"Health": "OK", "State": "Enabled"},
+			"Oem": {"Vendor": {"Anything": 1}}}`, nil},
+		{"nulls", `{
+			"@odata.id": "/redfish/v1/Systems/Self",
+			"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+			"Id": "Self", "Name": "System",
+			"PowerState": null, "Manufacturer": null,
+			"Status": {"Health": null}}`, nil},
+		{"bad values", `{
+			"@odata.id": "/redfish/v1/Systems/Self",
+			"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+			"Id": "Self", "Name": "System",
+			"PowerState": "Up",
+			"ProcessorSummary": {"Count": "2"},
+			"Status": {"Health": "Fine"}}`,
+			[]string{"PowerState", "ProcessorSummary.Count", "Status.Health"}},
+		{"missing required", `{
+			"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+			"Id": "Self"}`, []string{"@odata.id", "Name"}},
+		{"fractional count", `{
+			"@odata.id": "/redfish/v1/Systems/Self",
+			"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+			"Id": "Self", "Name": "System",
+			"ProcessorSummary": {"Count": 1.5}}`, []string{"ProcessorSummary.Count"}},
+		{"unknown type", `{"@odata.type": "#Foo.v1_0_0.Foo", "Id": 5}`, nil},
+		{"no type", `{"Id": 5}`, nil},
+		{"bad JSON", `{"Id": `, []string{""}},
+	}
+	for _, test := range tests {
+		errs := ValidateResource([]byte(test.body))
+		if len(errs) != len(test.props) {
+			t.Errorf("%s: Expected %d violations, got %+v", test.name,
+				len(test.props), errs)
+			continue
+		}
+		for i, e := range errs {
+			if e.Property != test.props[i] || e.Error == "" {
+				t.Errorf("%s: Expected violation of '%s', got %+v", test.name,
+					test.props[i], e)
+			}
+		}
+	}
+}
+
+func TestLoadSchemaDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadSchemaDir(dir); err == nil {
+		t.Errorf("Expected error for empty directory")
+	}
+	schema := `{"definitions": {"Widget": {
+		"type": "object",
+		"properties": {"Size": {"type": "integer", "maximum": 10}},
+		"additionalProperties": false}}}`
+	err := os.WriteFile(filepath.Join(dir, "Widget.v1_0_0.json"), []byte(schema), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadSchemaDir(dir); err != nil {
+		t.Fatalf("LoadSchemaDir: %s", err)
+	}
+	errs := ValidateResource([]byte(`{
+		"@odata.type": "#Widget.v1_0_0.Widget", "Size": 11, "Color": "red"}`))
+	if len(errs) != 3 {
+		t.Errorf("Expected 3 violations, got %+v", errs)
+	}
+	// Other versions fall back to the unversioned schema, which isn't there
+	errs = ValidateResource([]byte(`{
+		"@odata.type": "#Widget.v1_1_0.Widget", "Size": 11}`))
+	if len(errs) != 0 {
+		t.Errorf("Expected no violations, got %+v", errs)
+	}
+}
+
+// Violations should be listed in DiscInfo.SchemaViolations only if schema
+// validation is on.
+func TestSchemaViolations(t *testing.T) {
+	defer SetSchemaValidation(false)
+
+	payloads := map[string]string{
+		"/redfish/v1": `{
+			"@odata.id": "/redfish/v1",
+			"@odata.type": "#ServiceRoot.v1_5_0.ServiceRoot",
+			"Id": "RootService", "Name": "Root Service",
+			"RedfishVersion": "1.5",
+			"Links": {"Sessions": {"@odata.id": "/redfish/v1/SessionService/Sessions"}},
+			"Chassis": {"@odata.id": "/redfish/v1/Chassis"},
+			"Managers": {"@odata.id": "/redfish/v1/Managers"},
+			"Systems": {"@odata.id": "/redfish/v1/Systems"}}`,
+		"/redfish/v1/Chassis":  `{"Members": []}`,
+		"/redfish/v1/Managers": `{"Members": []}`,
+		"/redfish/v1/Systems":  `{"Members": []}`,
+	}
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		code := http.StatusOK
+		body, ok := payloads[req.URL.Path]
+		if !ok {
+			code = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: code,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+	for _, on := range []bool{false, true} {
+		SetSchemaValidation(on)
+		ep, err := NewRedfishEp(&RedfishEPDescription{
+			ID:      "x0c0s0b0",
+			Type:    "NodeBMC",
+			FQDN:    "x0c0s0b0",
+			Enabled: true,
+		})
+		if err != nil {
+			t.Fatalf("NewRedfishEp: %s", err)
+		}
+		ep.client = client
+		ep.DiscInfo.SchemaViolations = []SchemaViolation{{URI: "/stale"}}
+		ep.GetRootInfo()
+
+		if !on {
+			if len(ep.DiscInfo.SchemaViolations) != 0 {
+				t.Errorf("Unexpected violations with validation off: %+v",
+					ep.DiscInfo.SchemaViolations)
+			}
+			continue
+		}
+		v := ep.DiscInfo.SchemaViolations
+		if len(v) != 1 || v[0].URI != "/redfish/v1" ||
+			v[0].Property != "RedfishVersion" ||
+			v[0].ODataType != "#ServiceRoot.v1_5_0.ServiceRoot" {
+			t.Errorf("Unexpected violations: %+v", v)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Cray-HPE/hms-certs/pkg/hms_certs"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+// Self-test endpoint
+//
+// A synthetic NodeBMC with one node, two processors and two DIMMs (one of
+// them empty), served from memory instead of over the network.  It lets
+// HSM run the whole discovery pipeline at startup without any hardware.
+// The payloads were minimized from a real BMC with
+// MinimizeTestingPayload(), and the MAC addresses replaced with locally
+// administered ones so they can't collide with real interfaces.
+/////////////////////////////////////////////////////////////////////////////
+
+//go:embed selftest/nodebmc.json
+var selfTestPayloadsJSON []byte
+
+// Hostname domain of self-test endpoints.  .invalid can never resolve, so
+// the endpoint is unusable if it ever escapes into real discovery.
+const SelfTestDomain = "selftest.invalid"
+
+// Serves the self-test payloads by path, 404 for everything else.
+type selfTestTransport map[string][]byte
+
+func (t selfTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rsp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Request:    req,
+	}
+	body, ok := t[req.URL.Path]
+	if !ok || req.Method != http.MethodGet {
+		rsp.StatusCode = http.StatusNotFound
+		body = []byte{}
+	}
+	rsp.Header.Set("Content-Type", "application/json")
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return rsp, nil
+}
+
+// Create a RedfishEP for the self-test endpoint with the given NodeBMC
+// xname.  GetRootInfo() discovers it like any other endpoint.
+func NewSelfTestRedfishEp(id string) (*RedfishEP, error) {
+	var payloads map[string]json.RawMessage
+	if err := json.Unmarshal(selfTestPayloadsJSON, &payloads); err != nil {
+		return nil, fmt.Errorf("bad self-test payloads: %s", err)
+	}
+	t := make(selfTestTransport)
+	for path, payload := range payloads {
+		t[path] = payload
+	}
+	ep, err := NewRedfishEp(&RedfishEPDescription{
+		ID:       id,
+		Type:     "NodeBMC",
+		Hostname: id,
+		Domain:   SelfTestDomain,
+		FQDN:     id + "." + SelfTestDomain,
+		Enabled:  true,
+		DiscInfo: DiscoveryInfo{LastStatus: NotYetQueried},
+	})
+	if err != nil {
+		return nil, err
+	}
+	cp, err := hms_certs.CreateHTTPClientPair("", 5)
+	if err != nil {
+		return nil, err
+	}
+	cp.InsecureClient.HTTPClient.Transport = t
+	ep.client = cp
+	return ep, nil
+}
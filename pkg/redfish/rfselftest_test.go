@@ -0,0 +1,64 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"testing"
+
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+// The self-test endpoint must discover cleanly, without any discovery
+// errors, or the startup self-test would fail on every HSM.
+func TestSelfTestRedfishEp(t *testing.T) {
+	ep, err := NewSelfTestRedfishEp("x9999c7s0b0")
+	if err != nil {
+		t.Fatalf("NewSelfTestRedfishEp: %s", err)
+	}
+	ep.GetRootInfo()
+	if ep.DiscInfo.LastStatus != DiscoverOK || len(ep.DiscInfo.Errors) != 0 {
+		t.Fatalf("Bad discovery: %s %+v", ep.DiscInfo.LastStatus,
+			ep.DiscInfo.Errors)
+	}
+	if ep.FQDN != "x9999c7s0b0."+SelfTestDomain {
+		t.Errorf("Bad FQDN: %s", ep.FQDN)
+	}
+	s, ok := ep.Systems.OIDs["1"]
+	if !ok {
+		t.Fatalf("System 1 not discovered")
+	}
+	if s.ID != "x9999c7s0b0n0" || s.Type != xnametypes.Node.String() {
+		t.Errorf("Bad node: %s %s", s.ID, s.Type)
+	}
+	if len(s.Processors.OIDs) != 2 || len(s.MemoryMods.OIDs) != 2 {
+		t.Errorf("Expected 2 processors and 2 DIMMs, got %d and %d",
+			len(s.Processors.OIDs), len(s.MemoryMods.OIDs))
+	}
+	m, ok := ep.Managers.OIDs["1"]
+	if !ok || m.ID != "x9999c7s0b0" {
+		t.Fatalf("Manager not discovered")
+	}
+	if mac := ep.MACAddr; mac != "" && mac[:2] != "02" {
+		t.Errorf("Expected a locally administered MAC, got %s", mac)
+	}
+}
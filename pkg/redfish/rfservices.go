@@ -25,10 +25,14 @@ package rf
 import (
 	//"bytes"
 	"encoding/json"
+	"fmt"
 	//"io/ioutil"
+	"net/http"
 	//"path"
-	//"strings"
+	"strings"
 	//"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
 )
 
 /////////////////////////////////////////////////////////////////////////////
@@ -107,10 +111,99 @@ func (s *EpAccountService) discoverRemotePhase1() {
 	if err := json.Unmarshal(svcURLJSON, &s.AccountServiceRF); err != nil {
 		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
 		return
 	}
 }
 
+// Find the account in the endpoint's AccountService with the given
+// UserName, and return its URI.
+func (ep *RedfishEP) FindAccount(user string) (string, error) {
+	svcPath := ep.ServiceRootRF.AccountService.Oid
+	if svcPath == "" {
+		// Not discovered this time around, e.g. outside of discovery
+		rootJSON, err := ep.GETRelative(ep.OdataID)
+		if err != nil {
+			return "", err
+		}
+		var root ServiceRoot
+		if err := json.Unmarshal(rootJSON, &root); err != nil &&
+			!IsUnmarshalTypeError(err) {
+			return "", err
+		}
+		svcPath = root.AccountService.Oid
+	}
+	if svcPath == "" {
+		return "", ErrRFNoAccountService
+	}
+	svcJSON, err := ep.GETRelative(svcPath)
+	if err != nil {
+		return "", err
+	}
+	var svc AccountService
+	if err := json.Unmarshal(svcJSON, &svc); err != nil &&
+		!IsUnmarshalTypeError(err) {
+		return "", err
+	}
+	acctsPath := svc.Accounts.Oid
+	if acctsPath == "" {
+		acctsPath = strings.TrimSuffix(svcPath, "/") + "/Accounts"
+	}
+	for _, acctJSON := range ep.getCollectionMembers(acctsPath) {
+		var acct ManagerAccount
+		if err := json.Unmarshal(acctJSON, &acct); err != nil &&
+			!IsUnmarshalTypeError(err) {
+			continue
+		}
+		if acct.UserName == user && acct.Oid != "" {
+			return acct.Oid, nil
+		}
+	}
+	return "", ErrRFNoAccount
+}
+
+// Change the password of the account at acctURI, e.g. as returned by
+// FindAccount().  The endpoint's own credentials are left as they are, so
+// if it is the account being used, they must be updated after.
+func (ep *RedfishEP) SetAccountPassword(acctURI, password string) error {
+	body, err := json.Marshal(map[string]string{"Password": password})
+	if err != nil {
+		return err
+	}
+	_, err = ep.PATCHRelative(acctURI, body)
+	return err
+}
+
+// Check that the endpoint accepts the given credentials by GETting rpath
+// with them, without changing the endpoint's own.  Returns
+// ErrRFDiscUnauthorized if they are rejected.
+func (ep *RedfishEP) CheckLogin(rpath, user, password string) error {
+	path := "https://" + ep.hostPort() + rpath
+	if ep.FQDN == "" {
+		errlog.Printf("Can't HTTP GET (%s): FQDN is empty", path)
+		return ErrRFDiscFQDNMissing
+	}
+	req, err := http.NewRequest("GET", path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(user, password)
+	req.Header.Set("Accept", "*/*")
+	req.Close = true
+
+	rsp, err := ep.client.Do(req)
+	base.DrainAndCloseResponseBody(rsp)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode == http.StatusUnauthorized {
+		return ErrRFDiscUnauthorized
+	} else if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s", http.StatusText(rsp.StatusCode))
+	}
+	return nil
+}
+
 // This is the SessionService for the corresponding RedfishEP
 type EpSessionService struct {
 	// Embedded struct: id, type, odataID and associated RfEndpointID.
@@ -171,6 +264,7 @@ func (s *EpSessionService) discoverRemotePhase1() {
 	if err := json.Unmarshal(svcURLJSON, &s.SessionServiceRF); err != nil {
 		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
 		return
 	}
 }
@@ -235,6 +329,7 @@ func (s *EpEventService) discoverRemotePhase1() {
 	if err := json.Unmarshal(svcURLJSON, &s.EventServiceRF); err != nil {
 		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
 		return
 	}
 }
@@ -299,6 +394,7 @@ func (s *EpTaskService) discoverRemotePhase1() {
 	if err := json.Unmarshal(svcURLJSON, &s.TaskServiceRF); err != nil {
 		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
 		return
 	}
 }
@@ -315,8 +411,9 @@ type EpUpdateService struct {
 
 	LastStatus string `json:"lastStatus"`
 
-	UpdateServiceRF     UpdateService    `json:"updateServiceRF"`
-	updateServiceURLRaw *json.RawMessage // `json:"eventServiceURLRaw"`
+	UpdateServiceRF     UpdateService       `json:"updateServiceRF"`
+	Firmware            []SoftwareInventory `json:"firmware"`
+	updateServiceURLRaw *json.RawMessage    // `json:"eventServiceURLRaw"`
 
 	epRF *RedfishEP // Backpointer, for connection details, etc.
 }
@@ -332,7 +429,8 @@ func NewEpUpdateService(epRF *RedfishEP, odataID string) *EpUpdateService {
 	return s
 }
 
-// Contact RedfishEP and discover properties of the UpdateService
+// Contact RedfishEP and discover properties of the UpdateService and the
+// firmware versions in its FirmwareInventory.
 func (s *EpUpdateService) discoverRemotePhase1() {
 	// Should never happen
 	if s.epRF == nil {
@@ -363,6 +461,382 @@ func (s *EpUpdateService) discoverRemotePhase1() {
 	if err := json.Unmarshal(svcURLJSON, &s.UpdateServiceRF); err != nil {
 		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
 		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
+		return
+	}
+	s.Firmware = []SoftwareInventory{}
+	if s.UpdateServiceRF.FirmwareInventory == nil {
+		return
+	}
+	path = s.UpdateServiceRF.FirmwareInventory.Oid
+	for _, fwJSON := range s.epRF.getCollectionMembers(path) {
+		var fw SoftwareInventory
+		if err := json.Unmarshal(fwJSON, &fw); err != nil {
+			if !IsUnmarshalTypeError(err) {
+				errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+				s.epRF.addDiscoveryError(path, 0, err)
+				continue
+			}
+			errlog.Printf("bad field(s) skipped: %s: %s\n", s.RootFQDN+path, err)
+		}
+		s.Firmware = append(s.Firmware, fw)
+	}
+}
+
+// What to keep as the ServiceInfo of the UpdateService.
+func (s *EpUpdateService) Info() *UpdateServiceInfo {
+	return &UpdateServiceInfo{
+		UpdateService: s.UpdateServiceRF,
+		Firmware:      s.Firmware,
+	}
+}
+
+// This is the TelemetryService for the corresponding RedfishEP
+type EpTelemetryService struct {
+	// Embedded struct: id, type, odataID and associated RfEndpointID.
+	ServiceDescription
+
+	TelemetryServiceURL string `json:"telemetryServiceURL"` // Full URL to this svc
+	RootFQDN            string `json:"rootFQDN"`            // i.e. for epRF
+	RootHostname        string `json:"rootHostname"`
+	RootDomain          string `json:"rootDomain"`
+
+	LastStatus string `json:"lastStatus"`
+
+	TelemetryServiceRF     TelemetryService         `json:"telemetryServiceRF"`
+	MetricReportDefs       []MetricReportDefinition `json:"metricReportDefinitions"`
+	telemetryServiceURLRaw *json.RawMessage         // `json:"telemetryServiceURLRaw"`
+
+	epRF *RedfishEP // Backpointer, for connection details, etc.
+}
+
+// Create new struct to discover the TelemetryService for this RedfishEP
+func NewEpTelemetryService(epRF *RedfishEP, odataID string) *EpTelemetryService {
+	s := new(EpTelemetryService)
+	s.OdataID = odataID
+	s.RfEndpointID = epRF.ID
+	s.RedfishType = TelemetryServiceType
+	s.LastStatus = NotYetQueried
+	s.epRF = epRF
+	return s
+}
+
+// Contact RedfishEP and discover properties of the TelemetryService and
+// the MetricReportDefinitions it offers.
+func (s *EpTelemetryService) discoverRemotePhase1() {
+	// Should never happen
+	if s.epRF == nil {
+		errlog.Printf("Error: RedfishEP == nil for TelemetryService odataID: %s\n",
+			s.OdataID)
+		s.LastStatus = EndpointInvalid
+		return
+	}
+	s.TelemetryServiceURL = s.epRF.FQDN + s.OdataID
+	s.RootFQDN = s.epRF.FQDN
+	s.RootHostname = s.epRF.Hostname
+	s.RootDomain = s.epRF.Domain
+
+	path := s.OdataID
+	svcURLJSON, err := s.epRF.GETRelative(path)
+	if err != nil || svcURLJSON == nil {
+		errlog.Println(err)
+		s.LastStatus = HTTPsGetFailed
+		return
+	}
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", s.epRF.FQDN+path, svcURLJSON)
+	}
+	s.telemetryServiceURLRaw = &svcURLJSON
+	s.LastStatus = HTTPsGetOk
+
+	// Decode Raw JSON into TelemetryService Go struct
+	if err := json.Unmarshal(svcURLJSON, &s.TelemetryServiceRF); err != nil {
+		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
+		return
+	}
+	s.MetricReportDefs = []MetricReportDefinition{}
+	path = s.TelemetryServiceRF.MetricReportDefinitions.Oid
+	for _, defJSON := range s.epRF.getCollectionMembers(path) {
+		var def MetricReportDefinition
+		if err := json.Unmarshal(defJSON, &def); err != nil {
+			if !IsUnmarshalTypeError(err) {
+				errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+				continue
+			}
+			errlog.Printf("bad field(s) skipped: %s: %s\n", s.RootFQDN+path, err)
+		}
+		s.MetricReportDefs = append(s.MetricReportDefs, def)
+	}
+}
+
+// Subscribe to the MetricReports of every MetricReportDefinition of the
+// endpoint, to be POSTed to dest with the given Context.  Nothing is done
+// if there already is a subscription for dest.  Returns true if a new
+// subscription was created.
+func (ep *RedfishEP) SubscribeMetricReports(dest, context string) (bool, error) {
+	if ep.TelemetryService == nil || len(ep.TelemetryService.MetricReportDefs) == 0 {
+		return false, ErrRFNoMetricReports
+	}
+	if ep.EventService == nil ||
+		ep.EventService.EventServiceRF.Subscriptions.Oid == "" {
+		return false, ErrRFNoEventSubscriptions
+	}
+	subPath := ep.EventService.EventServiceRF.Subscriptions.Oid
+	for _, subJSON := range ep.getCollectionMembers(subPath) {
+		var sub EventDestination
+		if err := json.Unmarshal(subJSON, &sub); err != nil {
+			errlog.Printf("Failed to decode %s member: %s\n", subPath, err)
+			continue
+		}
+		if sub.Destination == dest {
+			return false, nil
+		}
+	}
+	sub := MetricReportSubscription{
+		Destination:     dest,
+		Context:         context,
+		Protocol:        "Redfish",
+		EventFormatType: "MetricReport",
+	}
+	for _, def := range ep.TelemetryService.MetricReportDefs {
+		sub.MetricReportDefinitions = append(sub.MetricReportDefinitions,
+			ResourceID{def.Oid})
+	}
+	body, err := json.Marshal(sub)
+	if err != nil {
+		return false, err
+	}
+	if _, err := ep.POSTRelative(subPath, body); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get the event subscriptions in the Subscriptions collection subPath that
+// POST to dest.  Unlike discovery, failing to read the collection is an
+// error, so a missing subscription can be told from an unreachable one.
+func (ep *RedfishEP) GetEventSubscriptions(subPath, dest string) ([]EventDestination, error) {
+	collJSON, err := ep.GETRelative(subPath)
+	if err != nil {
+		return nil, err
+	}
+	var coll EventDestinationCollection
+	if err := json.Unmarshal(collJSON, &coll); err != nil {
+		return nil, err
+	}
+	subs := []EventDestination{}
+	for _, m := range coll.Members {
+		subJSON, err := ep.GETRelative(m.Oid)
+		if err != nil {
+			return nil, err
+		}
+		var sub EventDestination
+		if err := json.Unmarshal(subJSON, &sub); err != nil {
+			errlog.Printf("Failed to decode %s: %s\n", m.Oid, err)
+			continue
+		}
+		if sub.Destination == dest {
+			if sub.Oid == "" {
+				sub.Oid = m.Oid
+			}
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+// Create a subscription in the Subscriptions collection subPath for all
+// events to be POSTed to dest with the given Context.  Returns the URI of
+// the new subscription.
+func (ep *RedfishEP) CreateEventSubscription(subPath, dest, context string) (string, error) {
+	body, err := json.Marshal(EventSubscription{
+		Destination: dest,
+		Context:     context,
+		Protocol:    "Redfish",
+	})
+	if err != nil {
+		return "", err
+	}
+	rspJSON, err := ep.POSTRelative(subPath, body)
+	if err != nil {
+		return "", err
+	}
+	var sub EventDestination
+	if len(rspJSON) > 0 && json.Unmarshal(rspJSON, &sub) == nil && sub.Oid != "" {
+		return sub.Oid, nil
+	}
+	// Not every service returns the new subscription, so look for it.
+	subs, err := ep.GetEventSubscriptions(subPath, dest)
+	if err != nil {
+		return "", err
+	}
+	for _, sub := range subs {
+		if sub.Context == context {
+			return sub.Oid, nil
+		}
+	}
+	return "", ErrRFEventSubscriptionURI
+}
+
+// Remove the event subscription at uri.
+func (ep *RedfishEP) DeleteEventSubscription(uri string) error {
+	return ep.DELETERelative(uri)
+}
+
+// This is the CertificateService for the corresponding RedfishEP
+type EpCertificateService struct {
+	// Embedded struct: id, type, odataID and associated RfEndpointID.
+	ServiceDescription
+
+	CertificateServiceURL string `json:"certificateServiceURL"` // Full URL to this svc
+	RootFQDN              string `json:"rootFQDN"`              // i.e. for epRF
+	RootHostname          string `json:"rootHostname"`
+	RootDomain            string `json:"rootDomain"`
+
+	LastStatus string `json:"lastStatus"`
+
+	CertificateServiceRF CertificateService `json:"certificateServiceRF"`
+	Certificates         []ResourceID       `json:"certificates"`
+	HTTPSCertificates    []Certificate      `json:"httpsCertificates"`
+
+	epRF *RedfishEP // Backpointer, for connection details, etc.
+}
+
+// Create new struct to discover the CertificateService for this RedfishEP
+func NewEpCertificateService(epRF *RedfishEP, odataID string) *EpCertificateService {
+	s := new(EpCertificateService)
+	s.OdataID = odataID
+	s.RfEndpointID = epRF.ID
+	s.RedfishType = CertificateServiceType
+	s.LastStatus = NotYetQueried
+	s.epRF = epRF
+	return s
+}
+
+// Contact RedfishEP and discover properties of the CertificateService and
+// the locations of the certificates it manages.
+func (s *EpCertificateService) discoverRemotePhase1() {
+	// Should never happen
+	if s.epRF == nil {
+		errlog.Printf("Error: RedfishEP == nil for CertificateService odataID: %s\n",
+			s.OdataID)
+		s.LastStatus = EndpointInvalid
+		return
+	}
+	s.CertificateServiceURL = s.epRF.FQDN + s.OdataID
+	s.RootFQDN = s.epRF.FQDN
+	s.RootHostname = s.epRF.Hostname
+	s.RootDomain = s.epRF.Domain
+
+	path := s.OdataID
+	svcURLJSON, err := s.epRF.GETRelative(path)
+	if err != nil || svcURLJSON == nil {
+		errlog.Println(err)
+		s.LastStatus = HTTPsGetFailed
+		return
+	}
+	if rfDebug > 0 {
+		errlog.Printf("%s: %s\n", s.epRF.FQDN+path, svcURLJSON)
+	}
+	s.LastStatus = HTTPsGetOk
+
+	// Decode Raw JSON into CertificateService Go struct
+	if err := json.Unmarshal(svcURLJSON, &s.CertificateServiceRF); err != nil {
+		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+		s.LastStatus = EPResponseFailedDecode
+		s.epRF.addDiscoveryError(path, 0, err)
+		return
+	}
+	path = s.CertificateServiceRF.CertificateLocations.Oid
+	if path == "" {
 		return
 	}
+	locJSON, err := s.epRF.GETRelative(path)
+	if err != nil || locJSON == nil {
+		errlog.Println(err)
+		return
+	}
+	var locs CertificateLocations
+	if err := json.Unmarshal(locJSON, &locs); err != nil {
+		errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+		s.epRF.addDiscoveryError(path, 0, err)
+		return
+	}
+	s.Certificates = locs.Links.Certificates
+	if rfDiscoverCertificates {
+		s.discoverHTTPSCertificates()
+	}
+}
+
+// Fetch the subject and validity of each HTTPS certificate.
+func (s *EpCertificateService) discoverHTTPSCertificates() {
+	for _, path := range httpsCertificates(s.Certificates) {
+		certJSON, err := s.epRF.GETRelative(path)
+		if err != nil || certJSON == nil {
+			errlog.Println(err)
+			continue
+		}
+		var cert Certificate
+		if err := json.Unmarshal(certJSON, &cert); err != nil {
+			errlog.Printf("Bad Decode: %s: %s\n", s.RootFQDN+path, err)
+			s.epRF.addDiscoveryError(path, 0, err)
+			continue
+		}
+		if err := cert.fillFromPEM(); err != nil {
+			errlog.Printf("Bad CertificateString: %s: %s\n", s.RootFQDN+path, err)
+			s.epRF.addDiscoveryError(path, 0, err)
+		}
+		s.HTTPSCertificates = append(s.HTTPSCertificates, cert)
+	}
+}
+
+// What to keep as the ServiceInfo of the CertificateService.
+func (s *EpCertificateService) Info() *CertificateServiceInfo {
+	return &CertificateServiceInfo{
+		CertificateService:      s.CertificateServiceRF,
+		Certificates:            s.Certificates,
+		HTTPSCertificateDetails: s.HTTPSCertificates,
+	}
+}
+
+// Replace a certificate on the endpoint by POSTing to the target of its
+// CertificateService.ReplaceCertificate action.
+func (ep *RedfishEP) ReplaceCertificate(target string, req *ReplaceCertificateRequest) error {
+	if target == "" {
+		return ErrRFNoCertificateAction
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = ep.POSTRelative(target, body)
+	return err
+}
+
+// Have the endpoint generate a new key pair and a certificate signing
+// request for it, by POSTing to the target of its
+// CertificateService.GenerateCSR action.  Once signed, the certificate is
+// installed with ReplaceCertificate().
+func (ep *RedfishEP) GenerateCSR(target string, req *GenerateCSRRequest) (*GenerateCSRResponse, error) {
+	if target == "" {
+		return nil, ErrRFNoCertificateAction
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	rspJSON, err := ep.POSTRelative(target, body)
+	if err != nil {
+		return nil, err
+	}
+	rsp := new(GenerateCSRResponse)
+	if err := json.Unmarshal(rspJSON, rsp); err != nil {
+		return nil, err
+	}
+	if rsp.CSRString == "" {
+		return nil, fmt.Errorf("no CSRString in response")
+	}
+	return rsp, nil
 }
@@ -0,0 +1,637 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+const testPayloadTelemetry_event_service = `{
+	"@odata.id": "/redfish/v1/EventService",
+	"Id": "EventService",
+	"ServiceEnabled": true,
+	"Subscriptions": {"@odata.id": "/redfish/v1/EventService/Subscriptions"}
+}`
+
+const testPayloadTelemetry_telemetry_service = `{
+	"@odata.id": "/redfish/v1/TelemetryService",
+	"Id": "TelemetryService",
+	"ServiceEnabled": true,
+	"MetricReportDefinitions": {"@odata.id": "/redfish/v1/TelemetryService/MetricReportDefinitions"},
+	"MetricReports": {"@odata.id": "/redfish/v1/TelemetryService/MetricReports"}
+}`
+
+const testPayloadTelemetry_mrds = `{
+	"@odata.id": "/redfish/v1/TelemetryService/MetricReportDefinitions",
+	"Members": [
+		{"@odata.id": "/redfish/v1/TelemetryService/MetricReportDefinitions/ThermalMetrics"},
+		{"@odata.id": "/redfish/v1/TelemetryService/MetricReportDefinitions/PowerMetrics"}
+	],
+	"Members@odata.count": 2
+}`
+
+const testPayloadTelemetry_mrd = `{
+	"@odata.id": "/redfish/v1/TelemetryService/MetricReportDefinitions/%s",
+	"Id": "%s",
+	"MetricReportDefinitionType": "Periodic",
+	"MetricReport": {"@odata.id": "/redfish/v1/TelemetryService/MetricReports/%s"}
+}`
+
+const testPayloadTelemetry_subs = `{
+	"@odata.id": "/redfish/v1/EventService/Subscriptions",
+	"Members": [
+		{"@odata.id": "/redfish/v1/EventService/Subscriptions/1"}
+	],
+	"Members@odata.count": 1
+}`
+
+const testPayloadTelemetry_sub = `{
+	"@odata.id": "/redfish/v1/EventService/Subscriptions/1",
+	"Id": "1",
+	"Destination": "%s",
+	"Protocol": "Redfish",
+	"EventFormatType": "MetricReport"
+}`
+
+// Mock of a BMC with a TelemetryService and one existing subscription
+// for existingDest.  POSTs to the subscriptions are passed to post.
+func NewRTFuncTelemetry(existingDest string, post func([]byte)) RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1/EventService":                                            testPayloadTelemetry_event_service,
+		"/redfish/v1/EventService/Subscriptions":                              testPayloadTelemetry_subs,
+		"/redfish/v1/EventService/Subscriptions/1":                            fmt.Sprintf(testPayloadTelemetry_sub, existingDest),
+		"/redfish/v1/TelemetryService":                                        testPayloadTelemetry_telemetry_service,
+		"/redfish/v1/TelemetryService/MetricReportDefinitions":                testPayloadTelemetry_mrds,
+		"/redfish/v1/TelemetryService/MetricReportDefinitions/PowerMetrics":   fmt.Sprintf(testPayloadTelemetry_mrd, "PowerMetrics", "PowerMetrics", "PowerMetrics"),
+		"/redfish/v1/TelemetryService/MetricReportDefinitions/ThermalMetrics": fmt.Sprintf(testPayloadTelemetry_mrd, "ThermalMetrics", "ThermalMetrics", "ThermalMetrics"),
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		if req.Method == "POST" &&
+			req.URL.String() == "https://"+testFQDN+"/redfish/v1/EventService/Subscriptions" {
+			body, _ := ioutil.ReadAll(req.Body)
+			post(body)
+			return &http.Response{
+				StatusCode: 201,
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				Header:     make(http.Header),
+			}
+		}
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestSubscribeMetricReports(t *testing.T) {
+	dest := "https://smd.local/hsm/v2/Telemetry/MetricReports/" + testXName
+	tests := []struct {
+		existingDest string
+		noDefs       bool
+		expCreated   bool
+		expErr       error
+		expPost      *MetricReportSubscription
+	}{{
+		"https://collector.local/events",
+		false,
+		true,
+		nil,
+		&MetricReportSubscription{
+			Destination:     dest,
+			Context:         "ctx",
+			Protocol:        "Redfish",
+			EventFormatType: "MetricReport",
+			MetricReportDefinitions: []ResourceID{
+				{"/redfish/v1/TelemetryService/MetricReportDefinitions/PowerMetrics"},
+				{"/redfish/v1/TelemetryService/MetricReportDefinitions/ThermalMetrics"},
+			},
+		},
+	}, {
+		dest,
+		false,
+		false,
+		nil,
+		nil,
+	}, {
+		"https://collector.local/events",
+		true,
+		false,
+		ErrRFNoMetricReports,
+		nil,
+	}}
+	for i, test := range tests {
+		var post *MetricReportSubscription
+		rt := NewRTFuncTelemetry(test.existingDest, func(body []byte) {
+			post = new(MetricReportSubscription)
+			if err := json.Unmarshal(body, post); err != nil {
+				t.Errorf("Testcase %d: FAIL: Bad POST body: %s", i, err)
+			}
+		})
+		ep := TestRedfishEPInitAggregator
+		ep.client = NewTestClient(rt)
+		ep.EventService = NewEpEventService(&ep, "/redfish/v1/EventService")
+		ep.EventService.discoverRemotePhase1()
+		ep.TelemetryService = NewEpTelemetryService(&ep, "/redfish/v1/TelemetryService")
+		ep.TelemetryService.discoverRemotePhase1()
+		if test.noDefs {
+			ep.TelemetryService.MetricReportDefs = nil
+		} else if len(ep.TelemetryService.MetricReportDefs) != 2 {
+			t.Fatalf("Testcase %d: FAIL: Expected 2 MetricReportDefinitions, got %d",
+				i, len(ep.TelemetryService.MetricReportDefs))
+		}
+
+		created, err := ep.SubscribeMetricReports(dest, "ctx")
+		if err != test.expErr {
+			t.Errorf("Testcase %d: FAIL: Expected error %v, got %v",
+				i, test.expErr, err)
+		}
+		if created != test.expCreated {
+			t.Errorf("Testcase %d: FAIL: Expected created %v, got %v",
+				i, test.expCreated, created)
+		}
+		if !reflect.DeepEqual(post, test.expPost) {
+			t.Errorf("Testcase %d: FAIL: Expected POST %+v, got %+v",
+				i, test.expPost, post)
+		}
+	}
+}
+
+const testPayloadCert_certificate_service = `{
+	"@odata.id": "/redfish/v1/CertificateService",
+	"Id": "CertificateService",
+	"Name": "Certificate Service",
+	"Actions": {
+		"#CertificateService.GenerateCSR": {
+			"target": "/redfish/v1/CertificateService/Actions/CertificateService.GenerateCSR"
+		},
+		"#CertificateService.ReplaceCertificate": {
+			"CertificateType@Redfish.AllowableValues": ["PEM"],
+			"target": "/redfish/v1/CertificateService/Actions/CertificateService.ReplaceCertificate"
+		}
+	},
+	"CertificateLocations": {"@odata.id": "/redfish/v1/CertificateService/CertificateLocations"}
+}`
+
+const testPayloadCert_certificate_locations = `{
+	"@odata.id": "/redfish/v1/CertificateService/CertificateLocations",
+	"Id": "CertificateLocations",
+	"Links": {
+		"Certificates": [
+			{"@odata.id": "/redfish/v1/AccountService/LDAP/Certificates/1"},
+			{"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1"}
+		]
+	}
+}`
+
+// Mock of a BMC with a CertificateService.  POSTs to its actions are
+// passed to post, which returns the response body.
+func NewRTFuncCertificate(post func(path string, body []byte) string) RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1/CertificateService":                      testPayloadCert_certificate_service,
+		"/redfish/v1/CertificateService/CertificateLocations": testPayloadCert_certificate_locations,
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		if req.Method == "POST" {
+			body, _ := ioutil.ReadAll(req.Body)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(post(req.URL.Path, body))),
+				Header:     make(http.Header),
+			}
+		}
+		if payload, ok := payloads[req.URL.Path]; ok {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestCertificateService(t *testing.T) {
+	posts := map[string][]byte{}
+	rt := NewRTFuncCertificate(func(path string, body []byte) string {
+		posts[path] = body
+		if strings.HasSuffix(path, "GenerateCSR") {
+			return `{"CSRString": "-----BEGIN CERTIFICATE REQUEST-----",` +
+				`"CertificateCollection": {"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates"}}`
+		}
+		return ""
+	})
+	ep := TestRedfishEPInitAggregator
+	ep.client = NewTestClient(rt)
+	ep.CertificateService = NewEpCertificateService(&ep, "/redfish/v1/CertificateService")
+	ep.CertificateService.discoverRemotePhase1()
+	if ep.CertificateService.LastStatus != HTTPsGetOk {
+		t.Fatalf("FAIL: Expected LastStatus %s, got %s", HTTPsGetOk,
+			ep.CertificateService.LastStatus)
+	}
+
+	// What is stored as the ServiceInfo
+	infoJSON, _ := json.Marshal(ep.CertificateService.Info())
+	info := new(CertificateServiceInfo)
+	if err := json.Unmarshal(infoJSON, info); err != nil {
+		t.Fatalf("FAIL: Bad ServiceInfo: %s", err)
+	}
+	expCerts := []string{"/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1"}
+	if certs := info.HTTPSCertificates(); !reflect.DeepEqual(certs, expCerts) {
+		t.Errorf("FAIL: Expected HTTPS certificates %v, got %v", expCerts, certs)
+	}
+	if info.Actions == nil || info.Actions.ReplaceCertificate == nil ||
+		info.Actions.GenerateCSR == nil {
+		t.Fatalf("FAIL: Missing actions in ServiceInfo: %s", infoJSON)
+	}
+
+	replaceTarget := info.Actions.ReplaceCertificate.Target
+	replace := &ReplaceCertificateRequest{
+		CertificateString: "-----BEGIN CERTIFICATE-----",
+		CertificateType:   "PEM",
+		CertificateUri:    ResourceID{expCerts[0]},
+	}
+	if err := ep.ReplaceCertificate(replaceTarget, replace); err != nil {
+		t.Errorf("FAIL: ReplaceCertificate: %s", err)
+	}
+	var gotReplace ReplaceCertificateRequest
+	json.Unmarshal(posts[replaceTarget], &gotReplace)
+	if !reflect.DeepEqual(&gotReplace, replace) {
+		t.Errorf("FAIL: Expected ReplaceCertificate POST %+v, got %+v",
+			replace, gotReplace)
+	}
+
+	csrTarget := info.Actions.GenerateCSR.Target
+	rsp, err := ep.GenerateCSR(csrTarget, &GenerateCSRRequest{
+		CertificateCollection: ResourceID{"/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates"},
+		CommonName:            testFQDN,
+	})
+	if err != nil {
+		t.Errorf("FAIL: GenerateCSR: %s", err)
+	} else if rsp.CSRString != "-----BEGIN CERTIFICATE REQUEST-----" {
+		t.Errorf("FAIL: Unexpected CSRString '%s'", rsp.CSRString)
+	}
+	if _, ok := posts[csrTarget]; !ok {
+		t.Errorf("FAIL: Expected a POST to %s", csrTarget)
+	}
+
+	if err := ep.ReplaceCertificate("", replace); err != ErrRFNoCertificateAction {
+		t.Errorf("FAIL: Expected %v with no target, got %v",
+			ErrRFNoCertificateAction, err)
+	}
+}
+
+const testPayloadUpdate_update_service = `{
+	"@odata.id": "/redfish/v1/UpdateService",
+	"Id": "UpdateService",
+	"ServiceEnabled": true,
+	"FirmwareInventory": {"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory"},
+	"Actions": {
+		"#UpdateService.SimpleUpdate": {
+			"target": "/redfish/v1/UpdateService/Actions/SimpleUpdate"
+		}
+	}
+}`
+
+const testPayloadUpdate_firmware_inventory = `{
+	"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory",
+	"Members": [
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BMC"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BIOS"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/Gone"}
+	],
+	"Members@odata.count": 3
+}`
+
+const testPayloadUpdate_firmware = `{
+	"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/%s",
+	"Id": "%s",
+	"Version": "%s",
+	"Updateable": true,
+	"RelatedItem": [{"@odata.id": "/redfish/v1/Managers/BMC"}]
+}`
+
+func TestUpdateServiceFirmwareInventory(t *testing.T) {
+	payloads := map[string]string{
+		"/redfish/v1/UpdateService":                        testPayloadUpdate_update_service,
+		"/redfish/v1/UpdateService/FirmwareInventory":      testPayloadUpdate_firmware_inventory,
+		"/redfish/v1/UpdateService/FirmwareInventory/BMC":  fmt.Sprintf(testPayloadUpdate_firmware, "BMC", "BMC", "1.45.2"),
+		"/redfish/v1/UpdateService/FirmwareInventory/BIOS": fmt.Sprintf(testPayloadUpdate_firmware, "BIOS", "BIOS", "2.18"),
+	}
+	rt := func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		if payload, ok := payloads[req.URL.Path]; ok {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+	ep := TestRedfishEPInitAggregator
+	ep.client = NewTestClient(rt)
+	ep.UpdateService = NewEpUpdateService(&ep, "/redfish/v1/UpdateService")
+	ep.UpdateService.discoverRemotePhase1()
+	if ep.UpdateService.LastStatus != HTTPsGetOk {
+		t.Fatalf("FAIL: Expected LastStatus %s, got %s", HTTPsGetOk,
+			ep.UpdateService.LastStatus)
+	}
+
+	// What is stored as the ServiceInfo; the member that couldn't be
+	// fetched is left out.
+	infoJSON, _ := json.Marshal(ep.UpdateService.Info())
+	info := new(UpdateServiceInfo)
+	if err := json.Unmarshal(infoJSON, info); err != nil {
+		t.Fatalf("FAIL: Bad ServiceInfo: %s", err)
+	}
+	if target := info.SimpleUpdateTarget(); target != "/redfish/v1/UpdateService/Actions/SimpleUpdate" {
+		t.Errorf("FAIL: Unexpected SimpleUpdate target '%s'", target)
+	}
+	versions := map[string]string{}
+	for _, fw := range info.Firmware {
+		versions[fw.Id] = fw.Version
+		if fw.Updateable == nil || !*fw.Updateable || len(fw.RelatedItem) != 1 {
+			t.Errorf("FAIL: Incomplete firmware entry %+v", fw)
+		}
+	}
+	expVersions := map[string]string{"BMC": "1.45.2", "BIOS": "2.18"}
+	if !reflect.DeepEqual(versions, expVersions) {
+		t.Errorf("FAIL: Expected firmware %v, got %v", expVersions, versions)
+	}
+
+	// No FirmwareInventory at all
+	payloads["/redfish/v1/UpdateService"] = `{"@odata.id": "/redfish/v1/UpdateService"}`
+	ep.UpdateService = NewEpUpdateService(&ep, "/redfish/v1/UpdateService")
+	ep.UpdateService.discoverRemotePhase1()
+	info = ep.UpdateService.Info()
+	if info.SimpleUpdateTarget() != "" || len(info.Firmware) != 0 {
+		t.Errorf("FAIL: Expected no target or firmware, got %+v", info)
+	}
+}
+
+const testPayloadCert_https_certificate = `{
+	"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1",
+	"Id": "1",
+	"CertificateType": "PEM",
+	"Subject": {"CommonName": "x0c0s0b0", "Organization": "OpenCHAMI"},
+	"Issuer": {"CommonName": "BMC CA"},
+	"ValidNotBefore": "2026-01-01T00:00:00Z",
+	"ValidNotAfter": "2027-01-01T00:00:00Z"
+}`
+
+// Self-signed certificate with only the PEM string, as some BMCs give.
+func testCertPEMOnly(t *testing.T, cn string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Can't generate key: %s", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x1234),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Can't create certificate: %s", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certJSON, _ := json.Marshal(map[string]string{
+		"@odata.id":         "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/2",
+		"Id":                "2",
+		"CertificateString": string(certPEM),
+	})
+	return string(certJSON)
+}
+
+func TestCertificateDiscovery(t *testing.T) {
+	notAfter := time.Date(2027, 3, 1, 12, 0, 0, 0, time.UTC)
+	payloads := map[string]string{
+		"/redfish/v1/CertificateService": testPayloadCert_certificate_service,
+		"/redfish/v1/CertificateService/CertificateLocations": `{
+			"@odata.id": "/redfish/v1/CertificateService/CertificateLocations",
+			"Links": {"Certificates": [
+				{"@odata.id": "/redfish/v1/AccountService/LDAP/Certificates/1"},
+				{"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1"},
+				{"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/2"},
+				{"@odata.id": "/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/3"}
+			]}
+		}`,
+		"/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/1": testPayloadCert_https_certificate,
+		"/redfish/v1/Managers/BMC/NetworkProtocol/HTTPS/Certificates/2": testCertPEMOnly(t, "bmc.local", notAfter),
+	}
+	rt := func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		if payload, ok := payloads[req.URL.Path]; ok {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+	defer SetDiscoverCertificates(GetDiscoverCertificates())
+
+	for _, on := range []bool{false, true} {
+		SetDiscoverCertificates(on)
+		ep := TestRedfishEPInitAggregator
+		ep.client = NewTestClient(rt)
+		ep.CertificateService = NewEpCertificateService(&ep, "/redfish/v1/CertificateService")
+		ep.CertificateService.discoverRemotePhase1()
+		info := ep.CertificateService.Info()
+		if !on {
+			if len(info.HTTPSCertificateDetails) != 0 {
+				t.Errorf("FAIL: Certificates fetched while off: %+v",
+					info.HTTPSCertificateDetails)
+			}
+			continue
+		}
+		// The LDAP certificate isn't fetched, and the missing one is skipped.
+		certs := info.HTTPSCertificateDetails
+		if len(certs) != 2 {
+			t.Fatalf("FAIL: Expected 2 HTTPS certificates, got %+v", certs)
+		}
+		if certs[0].Subject.CommonName != "x0c0s0b0" ||
+			certs[0].Issuer.CommonName != "BMC CA" ||
+			certs[0].ValidNotAfter != "2027-01-01T00:00:00Z" {
+			t.Errorf("FAIL: Unexpected certificate %+v", certs[0])
+		}
+		if certs[1].Subject.CommonName != "bmc.local" ||
+			certs[1].Issuer.CommonName != "bmc.local" ||
+			certs[1].ValidNotAfter != "2027-03-01T12:00:00Z" ||
+			certs[1].SerialNumber != "1234" || certs[1].CertificateString != "" {
+			t.Errorf("FAIL: Certificate not filled in from PEM: %+v", certs[1])
+		}
+		if expiry, ok := certs[1].Expiry(); !ok || !expiry.Equal(notAfter) {
+			t.Errorf("FAIL: Expected expiry %s, got %s", notAfter, expiry)
+		}
+		if len(ep.DiscInfo.Errors) != 1 || !strings.HasSuffix(
+			ep.DiscInfo.Errors[0].URI, "/Certificates/3") {
+			t.Errorf("FAIL: Expected an error for the missing certificate, "+
+				"got %v", ep.DiscInfo.Errors)
+		}
+	}
+}
+
+const testPayloadAccount_account = `{
+	"@odata.id": "/redfish/v1/AccountService/Accounts/%s",
+	"Id": "%s",
+	"UserName": "%s",
+	"Password": null,
+	"RoleId": "Administrator"
+}`
+
+// BMC with accounts 1 (root) and 2 (admin) that checks the password.
+func NewRTFuncAccounts(passwords map[string]string) RTFunc {
+	payloads := map[string]string{
+		"/redfish/v1": `{"AccountService": {"@odata.id": "/redfish/v1/AccountService"}}`,
+		"/redfish/v1/AccountService": `{"@odata.id": "/redfish/v1/AccountService",
+			"Accounts": {"@odata.id": "/redfish/v1/AccountService/Accounts"}}`,
+		"/redfish/v1/AccountService/Accounts": `{"Members": [
+			{"@odata.id": "/redfish/v1/AccountService/Accounts/2"},
+			{"@odata.id": "/redfish/v1/AccountService/Accounts/1"}]}`,
+		"/redfish/v1/AccountService/Accounts/1": fmt.Sprintf(testPayloadAccount_account, "1", "1", "root"),
+		"/redfish/v1/AccountService/Accounts/2": fmt.Sprintf(testPayloadAccount_account, "2", "2", "admin"),
+	}
+	users := map[string]string{"1": "root", "2": "admin"}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		rsp := func(code int, body string) *http.Response {
+			return &http.Response{
+				StatusCode: code,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+				Header:     make(http.Header),
+			}
+		}
+		user, pw, _ := req.BasicAuth()
+		if passwords[user] == "" || passwords[user] != pw {
+			return rsp(401, "")
+		}
+		payload, ok := payloads[req.URL.Path]
+		if !ok {
+			return rsp(404, "")
+		}
+		if req.Method == "PATCH" {
+			var patch map[string]string
+			body, _ := ioutil.ReadAll(req.Body)
+			if err := json.Unmarshal(body, &patch); err != nil {
+				return rsp(400, "")
+			}
+			passwords[users[strings.TrimPrefix(req.URL.Path,
+				"/redfish/v1/AccountService/Accounts/")]] = patch["Password"]
+			return rsp(204, "")
+		}
+		return rsp(200, payload)
+	}
+}
+
+func TestAccountPassword(t *testing.T) {
+	passwords := map[string]string{"root": "initial0", "admin": "other"}
+	ep, err := NewRedfishEp(&RedfishEPDescription{
+		ID:       "x0c0s0b0",
+		Type:     "NodeBMC",
+		FQDN:     "x0c0s0b0",
+		Enabled:  true,
+		User:     "root",
+		Password: "initial0",
+	})
+	if err != nil {
+		t.Fatalf("FAIL: NewRedfishEp: %s", err)
+	}
+	ep.client = NewTestClient(NewRTFuncAccounts(passwords))
+
+	uri, err := ep.FindAccount("root")
+	if err != nil || uri != "/redfish/v1/AccountService/Accounts/1" {
+		t.Fatalf("FAIL: Expected account 1, got '%s' %v", uri, err)
+	}
+	if _, err = ep.FindAccount("nobody"); err != ErrRFNoAccount {
+		t.Errorf("FAIL: Expected %v, got %v", ErrRFNoAccount, err)
+	}
+	if err = ep.SetAccountPassword(uri, "changed1"); err != nil {
+		t.Fatalf("FAIL: SetAccountPassword: %s", err)
+	}
+	if passwords["root"] != "changed1" || passwords["admin"] != "other" {
+		t.Errorf("FAIL: Unexpected passwords %v", passwords)
+	}
+	if err = ep.CheckLogin(uri, "root", "changed1"); err != nil {
+		t.Errorf("FAIL: CheckLogin with the new password: %s", err)
+	}
+	if err = ep.CheckLogin(uri, "root", "initial0"); err != ErrRFDiscUnauthorized {
+		t.Errorf("FAIL: Expected %v with the old password, got %v",
+			ErrRFDiscUnauthorized, err)
+	}
+	// The endpoint's own credentials are the caller's to change.
+	if ep.Password != "initial0" {
+		t.Errorf("FAIL: Endpoint password changed to '%s'", ep.Password)
+	}
+	if err = ep.SetAccountPassword(uri, "changed2"); err == nil {
+		t.Errorf("FAIL: Expected an error with the old credentials")
+	}
+}
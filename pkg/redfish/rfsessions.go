@@ -0,0 +1,206 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// Session auth
+//
+// Rather than sending basic auth with every request, discovery logs in once
+// through the endpoint's SessionService and sends the X-Auth-Token it gets
+// back with the rest of its requests, then logs out again.  Some BMCs rate
+// limit basic auth, and others (e.g. iLO) are slow with it since every
+// request is a new login.  If a session can't be created, or the endpoint
+// stops accepting its token, requests go back to using basic auth.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// If true, discovery uses a Redfish session for endpoints with credentials,
+// unless they have QuirkNoSessions.
+var rfSessionAuth = true
+
+// Turn the use of Redfish sessions during discovery on or off.
+// NOTE: Global, to be called only once at startup.
+func SetSessionAuth(on bool) {
+	rfSessionAuth = on
+}
+
+// Check whether Redfish sessions are used during discovery.
+func GetSessionAuth() bool {
+	return rfSessionAuth
+}
+
+// Header a Redfish session's token is sent and received in.
+const SessionTokenHeader = "X-Auth-Token"
+
+// POST body to create a session.
+type SessionLogin struct {
+	UserName string `json:"UserName"`
+	Password string `json:"Password"`
+}
+
+// An open session.  This is shared by the parts of a RedfishEP, so it is
+// kept by pointer.
+type rfSession struct {
+	lock  sync.Mutex
+	token string
+	uri   string // To delete it with when done
+}
+
+// Token to send, or "" if the session was dropped.
+func (s *rfSession) getToken() string {
+	if s == nil {
+		return ""
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.token
+}
+
+// Stop using the session.  Returns true if it was still in use.
+func (s *rfSession) drop() bool {
+	if s == nil {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	inUse := s.token != ""
+	s.token = ""
+	return inUse
+}
+
+// Add the endpoint's credentials to req: its session token if it has one,
+// otherwise basic auth.  Returns true if the session token was used.
+func (ep *RedfishEP) setAuth(req *http.Request) bool {
+	if ep.authStyle == AuthStyleNone {
+		return false
+	}
+	if token := ep.session.getToken(); token != "" {
+		req.Header.Set(SessionTokenHeader, token)
+		return true
+	}
+	req.SetBasicAuth(ep.User, ep.Password)
+	return false
+}
+
+// Where to POST to create a session, or "" if the endpoint has no
+// SessionService.
+func (ep *RedfishEP) sessionsPath() string {
+	if ep.ServiceRootRF.Links.Sessions.Oid != "" {
+		return ep.ServiceRootRF.Links.Sessions.Oid
+	}
+	if ep.ServiceRootRF.SessionService.Oid != "" {
+		return strings.TrimSuffix(ep.ServiceRootRF.SessionService.Oid, "/") +
+			"/Sessions"
+	}
+	return ""
+}
+
+// Log in to the endpoint so the rest of discovery can use a session token.
+// The ServiceRoot must have been read.  Failures are only logged, leaving
+// the endpoint on basic auth.
+func (ep *RedfishEP) openSession() {
+	ep.session = nil
+	if !rfSessionAuth || ep.authStyle == AuthStyleNone ||
+		ep.HasQuirk(QuirkNoSessions) || ep.User == "" {
+		return
+	}
+	rpath := ep.sessionsPath()
+	if rpath == "" {
+		return
+	}
+	path := "https://" + ep.hostPort() + rpath
+	body, _ := json.Marshal(SessionLogin{UserName: ep.User, Password: ep.Password})
+	req, err := http.NewRequest("POST", path, bytes.NewReader(body))
+	if err != nil {
+		errlog.Printf("Error forming new request for (%s) %s", path, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "*/*")
+	req.Close = true
+
+	rsp, err := ep.client.Do(req)
+	if err != nil {
+		base.DrainAndCloseResponseBody(rsp)
+		errlog.Printf("%s: session login failed, using basic auth: %s",
+			ep.ID, err)
+		return
+	}
+	base.DrainAndCloseResponseBody(rsp)
+	token := rsp.Header.Get(SessionTokenHeader)
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 || token == "" {
+		errlog.Printf("%s: session login failed, using basic auth: %d %s",
+			ep.ID, rsp.StatusCode, http.StatusText(rsp.StatusCode))
+		return
+	}
+	// The Location is usually relative, but may be a full URL.
+	uri := rsp.Header.Get("Location")
+	if u, err := url.Parse(uri); err == nil && u.Path != "" {
+		uri = u.Path
+	}
+	ep.session = &rfSession{token: token, uri: uri}
+}
+
+// Log out of the session opened by openSession(), if any.  Any requests
+// made afterwards use basic auth.
+func (ep *RedfishEP) closeSession() {
+	s := ep.session
+	ep.session = nil
+	if s == nil {
+		return
+	}
+	token := s.getToken()
+	if !s.drop() || s.uri == "" {
+		return
+	}
+	path := "https://" + ep.hostPort() + s.uri
+	req, err := http.NewRequest("DELETE", path, nil)
+	if err != nil {
+		errlog.Printf("Error forming new request for (%s) %s", path, err)
+		return
+	}
+	req.Header.Set(SessionTokenHeader, token)
+	req.Header.Set("Accept", "*/*")
+	req.Close = true
+
+	rsp, err := ep.client.Do(req)
+	base.DrainAndCloseResponseBody(rsp)
+	if err != nil {
+		errlog.Printf("%s: session logout failed: %s", ep.ID, err)
+	} else if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		errlog.Printf("%s: session logout failed: %d %s", ep.ID,
+			rsp.StatusCode, http.StatusText(rsp.StatusCode))
+	}
+}
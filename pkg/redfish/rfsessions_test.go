@@ -0,0 +1,132 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestSessionAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		sessionAuth  bool
+		quirks       []string
+		loginStatus  int
+		tokenUses    int // GETs the token is good for, -1 for all
+		expLogins    int
+		expTokenGETs int
+		expBasicGETs int
+		expLogouts   int
+	}{
+		// Discovery GETs SessionService, Chassis, Managers and Systems
+		{"session", true, nil, 201, -1, 1, 4, 0, 1},
+		{"login fails", true, nil, 405, -1, 1, 0, 4, 0},
+		// The rejected GET is repeated with basic auth
+		{"token expires", true, nil, 201, 1, 1, 2, 3, 0},
+		{"disabled", false, nil, 201, -1, 0, 0, 4, 0},
+		{"quirk", true, []string{QuirkNoSessions}, 201, -1, 0, 0, 4, 0},
+	}
+	defer SetSessionAuth(GetSessionAuth())
+	for _, test := range tests {
+		var logins, tokenGETs, basicGETs, logouts int
+		tokenUses := test.tokenUses
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			rsp := &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{"Members": []}`)),
+				Header:     make(http.Header),
+			}
+			switch {
+			case req.URL.Path == "/redfish/v1":
+				rsp.Body = ioutil.NopCloser(bytes.NewBufferString(`{
+					"Chassis": {"@odata.id": "/redfish/v1/Chassis"},
+					"SessionService": {"@odata.id": "/redfish/v1/SessionService"},
+					"Links": {"Sessions": {"@odata.id": "/redfish/v1/SessionService/Sessions"}}
+				}`))
+			case req.Method == "POST" && req.URL.Path == "/redfish/v1/SessionService/Sessions":
+				logins++
+				var login SessionLogin
+				body, _ := ioutil.ReadAll(req.Body)
+				if err := json.Unmarshal(body, &login); err != nil ||
+					login.UserName != "root" || login.Password != "secret" {
+					t.Errorf("%s: FAIL: Bad login '%s'", test.name, body)
+				}
+				rsp.StatusCode = test.loginStatus
+				rsp.Header.Set(SessionTokenHeader, "token1")
+				rsp.Header.Set("Location",
+					"https://x0c0s0b0/redfish/v1/SessionService/Sessions/1")
+			case req.Method == "DELETE":
+				logouts++
+				if req.URL.Path != "/redfish/v1/SessionService/Sessions/1" ||
+					req.Header.Get(SessionTokenHeader) != "token1" {
+					t.Errorf("%s: FAIL: Bad logout %s", test.name, req.URL)
+				}
+			case req.Header.Get(SessionTokenHeader) != "":
+				tokenGETs++
+				if tokenUses == 0 {
+					rsp.StatusCode = 401
+				}
+				tokenUses--
+			default:
+				if _, pw, ok := req.BasicAuth(); !ok || pw != "secret" {
+					rsp.StatusCode = 401
+				}
+				basicGETs++
+			}
+			return rsp
+		})
+		SetSessionAuth(test.sessionAuth)
+		ep, err := NewRedfishEp(&RedfishEPDescription{
+			ID:       "x0c0s0b0",
+			Type:     "NodeBMC",
+			FQDN:     "x0c0s0b0",
+			Enabled:  true,
+			User:     "root",
+			Password: "secret",
+		})
+		if err != nil {
+			t.Fatalf("%s: FAIL: NewRedfishEp: %s", test.name, err)
+		}
+		ep.client = client
+		ep.SetVendorProfile(&VendorProfile{Name: "test", Quirks: test.quirks})
+		ep.GetRootInfo()
+
+		if ep.AuthFailed() {
+			t.Errorf("%s: FAIL: Unexpected auth failure", test.name)
+		}
+		if logins != test.expLogins || tokenGETs != test.expTokenGETs ||
+			basicGETs != test.expBasicGETs || logouts != test.expLogouts {
+			t.Errorf("%s: FAIL: Expected %d logins, %d token GETs, %d basic "+
+				"GETs, %d logouts, got %d %d %d %d", test.name,
+				test.expLogins, test.expTokenGETs, test.expBasicGETs,
+				test.expLogouts, logins, tokenGETs, basicGETs, logouts)
+		}
+		if ep.session != nil {
+			t.Errorf("%s: FAIL: Session still open after discovery", test.name)
+		}
+	}
+}
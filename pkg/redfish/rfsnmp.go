@@ -0,0 +1,655 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// SNMP discovery driver
+//
+// Discovers PDUs that only speak SNMP (v2c) using the vendors' outlet MIBs.
+// Each PDU found becomes a RackPDU with its outlets, named and numbered in
+// the order the agent returns them, so they end up as CabinetPDUs and
+// CabinetPDUPowerConnectors like ones discovered over Redfish.
+//
+// The community string is the endpoint's Password if it has one, otherwise
+// the global default (see SetSNMPCommunity).
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// Name the SNMP driver is registered under.
+const SNMPDriverName = "SNMP"
+
+// Default SNMP community, if the endpoint doesn't give one.
+var rfSNMPCommunity = "public"
+
+// Set the SNMP community used for endpoints without a Password.
+// NOTE: Global, to be called only once at startup.
+func SetSNMPCommunity(community string) {
+	if community != "" {
+		rfSNMPCommunity = community
+	} else {
+		errlog.Printf("SetSNMPCommunity: empty community")
+	}
+}
+
+// Get the SNMP community used for endpoints without a Password.
+func GetSNMPCommunity() string {
+	return rfSNMPCommunity
+}
+
+var ErrSNMPTimeout = errors.New("SNMP request timed out")
+var ErrSNMPNoOutlets = errors.New("no supported PDU outlet MIB found")
+
+// Upper limit on the rows read from any one SNMP table.
+const snmpMaxRows = 1024
+
+// System group OIDs, the same for every agent.
+const (
+	snmpSysDescr    = "1.3.6.1.2.1.1.1.0"
+	snmpSysObjectID = "1.3.6.1.2.1.1.2.0"
+	snmpSysName     = "1.3.6.1.2.1.1.5.0"
+)
+
+// Outlet table of a vendor PDU MIB.  Tables are indexed by the PDU's
+// index (PDUArcs arcs, none if the agent is just one PDU) followed by the
+// outlet's.
+type snmpOutletMIB struct {
+	Manufacturer string
+	Enterprise   string // sysObjectID prefix of the vendor's agents
+	NameOID      string // Outlet name column
+	StateOID     string // Outlet state column
+	PDUArcs      int
+	States       map[int64]string // State column value -> PowerState
+
+	// Outlet Id from its full index.  The index without the PDU arcs,
+	// joined with ".", if nil.
+	OutletID func(idx []int) string
+}
+
+var snmpOutletMIBs = []*snmpOutletMIB{
+	// PowerNet-MIB rPDU2OutletSwitchStatusTable
+	{
+		Manufacturer: "APC",
+		Enterprise:   "1.3.6.1.4.1.318",
+		NameOID:      "1.3.6.1.4.1.318.1.1.26.9.2.3.1.3",
+		StateOID:     "1.3.6.1.4.1.318.1.1.26.9.2.3.1.5",
+		States:       map[int64]string{1: POWER_STATE_OFF, 2: POWER_STATE_ON},
+	},
+	// PDU2-MIB outletConfigurationTable and outletSwitchControlTable
+	{
+		Manufacturer: RaritanMfr,
+		Enterprise:   "1.3.6.1.4.1.13742.6",
+		NameOID:      "1.3.6.1.4.1.13742.6.3.5.3.1.3",
+		StateOID:     "1.3.6.1.4.1.13742.6.4.1.2.1.3",
+		PDUArcs:      1,
+		States:       map[int64]string{7: POWER_STATE_ON, 8: POWER_STATE_OFF},
+	},
+	// Sentry3-MIB outletTable, indexed by tower, infeed, outlet
+	{
+		Manufacturer: "Server Technology",
+		Enterprise:   "1.3.6.1.4.1.1718.3",
+		NameOID:      "1.3.6.1.4.1.1718.3.2.3.1.3",
+		StateOID:     "1.3.6.1.4.1.1718.3.2.3.1.5",
+		PDUArcs:      1,
+		States: map[int64]string{
+			0: POWER_STATE_OFF, 1: POWER_STATE_ON,
+			4: POWER_STATE_OFF, 5: POWER_STATE_ON, // off/onError
+			8: POWER_STATE_OFF, 9: POWER_STATE_ON, // off/onFuse
+		},
+		OutletID: sentryOutletID,
+	},
+}
+
+// Sentry outlets are named like their Redfish Ids, e.g. AA1 for tower A,
+// infeed A, outlet 1.
+func sentryOutletID(idx []int) string {
+	if len(idx) != 3 || idx[0] < 1 || idx[0] > 26 || idx[1] < 1 || idx[1] > 26 {
+		return joinOID(idx[1:])
+	}
+	return string(rune('A'+idx[0]-1)) + string(rune('A'+idx[1]-1)) +
+		strconv.Itoa(idx[2])
+}
+
+// DiscoveryDriver for PDUs over SNMP v2c.
+type SNMPDriver struct {
+	Port    int           // Default 161
+	Timeout time.Duration // Per try, default 2s
+	Retries int           // Default 2
+}
+
+// Discover the PDUs and outlets of the endpoint over SNMP.
+func (d *SNMPDriver) Discover(ep *RedfishEP) error {
+	host := ep.FQDN
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		return ErrRFDiscFQDNMissing
+	}
+	port := d.Port
+	if port <= 0 {
+		port = 161
+	}
+	community := ep.Password
+	if community == "" {
+		community = rfSNMPCommunity
+	}
+	c, err := newSNMPClient(net.JoinHostPort(host, strconv.Itoa(port)),
+		community, d.Timeout, d.Retries)
+	if err != nil {
+		return err
+	}
+	defer c.close()
+
+	sys, err := c.get(snmpSysDescr, snmpSysObjectID, snmpSysName)
+	if err != nil {
+		return err
+	}
+	sysDescr, sysObjectID, sysName := sys[0].str(), sys[1].str(), sys[2].str()
+
+	// Try the MIB for the agent's vendor first, then the rest.
+	mibs := make([]*snmpOutletMIB, 0, len(snmpOutletMIBs))
+	for _, mib := range snmpOutletMIBs {
+		if strings.HasPrefix(sysObjectID+".", mib.Enterprise+".") {
+			mibs = append([]*snmpOutletMIB{mib}, mibs...)
+		} else {
+			mibs = append(mibs, mib)
+		}
+	}
+	for _, mib := range mibs {
+		names, err := c.walk(mib.NameOID)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			continue
+		}
+		states, err := c.walk(mib.StateOID)
+		if err != nil {
+			return err
+		}
+		ep.addSNMPPDUs(mib, names, states, sysName, sysDescr)
+		return nil
+	}
+	return ErrSNMPNoOutlets
+}
+
+// Fill in ep.RackPDUs from the walked outlet name and state columns.
+func (ep *RedfishEP) addSNMPPDUs(mib *snmpOutletMIB, names, states []snmpVarBind,
+	sysName, sysDescr string) {
+
+	stateByIdx := make(map[string]string, len(states))
+	for _, vb := range states {
+		idx := strings.TrimPrefix(vb.oid, mib.StateOID+".")
+		stateByIdx[idx] = mib.States[vb.int()]
+	}
+	ep.RackPDUs.OIDs = make(map[string]*EpPDU)
+	for _, vb := range names {
+		idx, err := parseOID(strings.TrimPrefix(vb.oid, mib.NameOID+"."))
+		if err != nil || len(idx) <= mib.PDUArcs {
+			continue
+		}
+		pduID := "1"
+		if mib.PDUArcs > 0 {
+			pduID = joinOID(idx[:mib.PDUArcs])
+		}
+		pdu, ok := ep.RackPDUs.OIDs[pduID]
+		if !ok {
+			pdu = NewEpPDU(ep, ResourceID{Oid: "/snmp/RackPDUs/" + pduID},
+				len(ep.RackPDUs.OIDs))
+			pdu.PDUURL = ep.FQDN + pdu.OdataID
+			pduRF := &pdu.PowerDistributionRF
+			pduRF.Oid = pdu.OdataID
+			pduRF.Id = pduID
+			pduRF.Name = sysName
+			pduRF.EquipmentType = "RackPDU"
+			pduRF.Manufacturer = mib.Manufacturer
+			pduRF.Model = sysDescr
+			pduRF.Status = StatusRF{State: "Enabled", Health: "OK"}
+			pdu.RedfishSubtype = pduRF.EquipmentType
+			pdu.Name = pduRF.Name
+			pdu.Outlets.OIDs = make(map[string]*EpOutlet)
+			pdu.LastStatus = VerifyingData
+			ep.RackPDUs.OIDs[pduID] = pdu
+		}
+		outID := joinOID(idx[mib.PDUArcs:])
+		if mib.OutletID != nil {
+			outID = mib.OutletID(idx)
+		}
+		out := NewEpOutlet(pdu, ResourceID{Oid: pdu.OdataID + "/Outlets/" + outID},
+			len(pdu.Outlets.OIDs))
+		out.OutletURL = ep.FQDN + out.OdataID
+		out.OutletRF.Oid = out.OdataID
+		out.OutletRF.Id = outID
+		out.OutletRF.Name = vb.str()
+		out.OutletRF.PowerState = stateByIdx[joinOID(idx)]
+		out.OutletRF.Status = StatusRF{State: "Enabled", Health: "OK"}
+		out.Name = out.OutletRF.Name
+		out.LastStatus = VerifyingData
+		pdu.Outlets.OIDs[outID] = out
+		pdu.Outlets.Num = len(pdu.Outlets.OIDs)
+	}
+	ep.NumRackPDUs = len(ep.RackPDUs.OIDs)
+	ep.RackPDUs.Num = ep.NumRackPDUs
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// Minimal SNMP v2c client, just enough for Get and walking tables.
+/////////////////////////////////////////////////////////////////////////////
+
+// BER tags
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berNull        = 0x05
+	berOID         = 0x06
+	berSequence    = 0x30
+
+	snmpCounter32      = 0x41
+	snmpGauge32        = 0x42
+	snmpTimeTicks      = 0x43
+	snmpCounter64      = 0x46
+	snmpNoSuchObject   = 0x80
+	snmpNoSuchInstance = 0x81
+	snmpEndOfMibView   = 0x82
+
+	snmpGetRequest     = 0xa0
+	snmpGetNextRequest = 0xa1
+	snmpGetResponse    = 0xa2
+)
+
+const snmpVersion2c = 1
+
+type snmpVarBind struct {
+	oid   string
+	tag   byte
+	value []byte // Undecoded contents
+}
+
+// Value as a string, e.g. for OCTET STRING and OID values.
+func (vb snmpVarBind) str() string {
+	switch vb.tag {
+	case berOctetString:
+		return string(vb.value)
+	case berOID:
+		return decodeOID(vb.value)
+	case berInteger, snmpCounter32, snmpGauge32, snmpTimeTicks, snmpCounter64:
+		return strconv.FormatInt(vb.int(), 10)
+	}
+	return ""
+}
+
+// Value as an integer, 0 if it isn't one.
+func (vb snmpVarBind) int() int64 {
+	switch vb.tag {
+	case berInteger:
+		return decodeInt(vb.value)
+	case snmpCounter32, snmpGauge32, snmpTimeTicks, snmpCounter64:
+		var v int64
+		for _, b := range vb.value {
+			v = v<<8 | int64(b)
+		}
+		return v
+	}
+	return 0
+}
+
+// True if there is no such OID, i.e. one of the v2c exceptions.
+func (vb snmpVarBind) missing() bool {
+	return vb.tag == snmpNoSuchObject || vb.tag == snmpNoSuchInstance ||
+		vb.tag == snmpEndOfMibView
+}
+
+type snmpPDU struct {
+	tag       byte
+	requestID int32
+	errStatus int64
+	errIndex  int64
+	community string
+	varBinds  []snmpVarBind
+}
+
+type snmpClient struct {
+	conn      net.Conn
+	community string
+	timeout   time.Duration
+	retries   int
+}
+
+func newSNMPClient(addr, community string, timeout time.Duration,
+	retries int) (*snmpClient, error) {
+
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	if retries <= 0 {
+		retries = 2
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &snmpClient{conn, community, timeout, retries}, nil
+}
+
+func (c *snmpClient) close() {
+	c.conn.Close()
+}
+
+// Send the request, retrying on timeouts, and return the matching response.
+func (c *snmpClient) request(tag byte, oids ...string) ([]snmpVarBind, error) {
+	req := snmpPDU{tag: tag, requestID: rand.Int31(), community: c.community}
+	for _, oid := range oids {
+		req.varBinds = append(req.varBinds, snmpVarBind{oid: oid, tag: berNull})
+	}
+	msg, err := encodeSNMP(&req)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 65535)
+	for try := 0; try <= c.retries; try++ {
+		if _, err := c.conn.Write(msg); err != nil {
+			return nil, err
+		}
+		c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+		for {
+			n, err := c.conn.Read(buf)
+			if err != nil {
+				var nerr net.Error
+				if errors.As(err, &nerr) && nerr.Timeout() {
+					break
+				}
+				return nil, err
+			}
+			resp, err := decodeSNMP(buf[:n])
+			if err != nil || resp.tag != snmpGetResponse ||
+				resp.requestID != req.requestID {
+				continue // Not ours, or garbage
+			}
+			if resp.errStatus != 0 {
+				return nil, fmt.Errorf("SNMP error-status %d at %d",
+					resp.errStatus, resp.errIndex)
+			}
+			if len(resp.varBinds) != len(oids) {
+				return nil, fmt.Errorf("SNMP response has %d varbinds, "+
+					"expected %d", len(resp.varBinds), len(oids))
+			}
+			return resp.varBinds, nil
+		}
+	}
+	return nil, ErrSNMPTimeout
+}
+
+// Get the given OIDs.  Any that don't exist are an error.
+func (c *snmpClient) get(oids ...string) ([]snmpVarBind, error) {
+	vbs, err := c.request(snmpGetRequest, oids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, vb := range vbs {
+		if vb.missing() {
+			return nil, fmt.Errorf("no such OID %s", vb.oid)
+		}
+	}
+	return vbs, nil
+}
+
+// Get everything under root, in order.  Empty if there is nothing.
+func (c *snmpClient) walk(root string) ([]snmpVarBind, error) {
+	var vbs []snmpVarBind
+	oid := root
+	for len(vbs) < snmpMaxRows {
+		next, err := c.request(snmpGetNextRequest, oid)
+		if err != nil {
+			return nil, err
+		}
+		vb := next[0]
+		if vb.missing() || !strings.HasPrefix(vb.oid, root+".") {
+			break
+		}
+		vbs = append(vbs, vb)
+		oid = vb.oid
+	}
+	return vbs, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// BER encoding
+/////////////////////////////////////////////////////////////////////////////
+
+func encodeSNMP(p *snmpPDU) ([]byte, error) {
+	var vbs []byte
+	for _, vb := range p.varBinds {
+		oid, err := encodeOID(vb.oid)
+		if err != nil {
+			return nil, err
+		}
+		vbs = append(vbs, berTLV(berSequence,
+			append(berTLV(berOID, oid), berTLV(vb.tag, vb.value)...))...)
+	}
+	var pdu []byte
+	pdu = append(pdu, berTLV(berInteger, encodeInt(int64(p.requestID)))...)
+	pdu = append(pdu, berTLV(berInteger, encodeInt(p.errStatus))...)
+	pdu = append(pdu, berTLV(berInteger, encodeInt(p.errIndex))...)
+	pdu = append(pdu, berTLV(berSequence, vbs)...)
+
+	var msg []byte
+	msg = append(msg, berTLV(berInteger, encodeInt(snmpVersion2c))...)
+	msg = append(msg, berTLV(berOctetString, []byte(p.community))...)
+	msg = append(msg, berTLV(p.tag, pdu)...)
+	return berTLV(berSequence, msg), nil
+}
+
+func decodeSNMP(b []byte) (*snmpPDU, error) {
+	msg, err := berExpect(&b, berSequence)
+	if err != nil {
+		return nil, err
+	}
+	version, err := berExpect(&msg, berInteger)
+	if err != nil {
+		return nil, err
+	}
+	if decodeInt(version) != snmpVersion2c {
+		return nil, fmt.Errorf("unsupported SNMP version %d", decodeInt(version))
+	}
+	p := new(snmpPDU)
+	community, err := berExpect(&msg, berOctetString)
+	if err != nil {
+		return nil, err
+	}
+	p.community = string(community)
+	var pdu []byte
+	if p.tag, pdu, err = berNext(&msg); err != nil {
+		return nil, err
+	}
+	var ints [3]int64
+	for i := range ints {
+		v, err := berExpect(&pdu, berInteger)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = decodeInt(v)
+	}
+	p.requestID, p.errStatus, p.errIndex = int32(ints[0]), ints[1], ints[2]
+	vbs, err := berExpect(&pdu, berSequence)
+	if err != nil {
+		return nil, err
+	}
+	for len(vbs) > 0 {
+		vb, err := berExpect(&vbs, berSequence)
+		if err != nil {
+			return nil, err
+		}
+		oid, err := berExpect(&vb, berOID)
+		if err != nil {
+			return nil, err
+		}
+		tag, value, err := berNext(&vb)
+		if err != nil {
+			return nil, err
+		}
+		p.varBinds = append(p.varBinds,
+			snmpVarBind{oid: decodeOID(oid), tag: tag, value: value})
+	}
+	return p, nil
+}
+
+func berTLV(tag byte, value []byte) []byte {
+	b := []byte{tag}
+	switch n := len(value); {
+	case n < 0x80:
+		b = append(b, byte(n))
+	case n <= 0xff:
+		b = append(b, 0x81, byte(n))
+	default:
+		b = append(b, 0x82, byte(n>>8), byte(n))
+	}
+	return append(b, value...)
+}
+
+// Take the next TLV off the front of b.
+func berNext(b *[]byte) (byte, []byte, error) {
+	buf := *b
+	if len(buf) < 2 {
+		return 0, nil, errors.New("BER: truncated")
+	}
+	tag, n, hdr := buf[0], int(buf[1]), 2
+	if n&0x80 != 0 {
+		nlen := n & 0x7f
+		if nlen == 0 || nlen > 2 || len(buf) < 2+nlen {
+			return 0, nil, errors.New("BER: bad length")
+		}
+		n = 0
+		for _, c := range buf[2 : 2+nlen] {
+			n = n<<8 | int(c)
+		}
+		hdr += nlen
+	}
+	if len(buf) < hdr+n {
+		return 0, nil, errors.New("BER: truncated")
+	}
+	*b = buf[hdr+n:]
+	return tag, buf[hdr : hdr+n], nil
+}
+
+func berExpect(b *[]byte, tag byte) ([]byte, error) {
+	t, v, err := berNext(b)
+	if err != nil {
+		return nil, err
+	}
+	if t != tag {
+		return nil, fmt.Errorf("BER: expected tag 0x%02x, got 0x%02x", tag, t)
+	}
+	return v, nil
+}
+
+func encodeInt(v int64) []byte {
+	b := []byte{byte(v)}
+	for v >>= 8; ; v >>= 8 {
+		// Stop once the remaining bytes are just sign extension.
+		if (v == 0 && b[0]&0x80 == 0) || (v == -1 && b[0]&0x80 != 0) {
+			return b
+		}
+		b = append([]byte{byte(v)}, b...)
+	}
+}
+
+func decodeInt(b []byte) int64 {
+	var v int64
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v = -1
+	}
+	for _, c := range b {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+func parseOID(s string) ([]int, error) {
+	parts := strings.Split(s, ".")
+	arcs := make([]int, len(parts))
+	for i, p := range parts {
+		arc, err := strconv.Atoi(p)
+		if err != nil || arc < 0 {
+			return nil, fmt.Errorf("bad OID '%s'", s)
+		}
+		arcs[i] = arc
+	}
+	return arcs, nil
+}
+
+func joinOID(arcs []int) string {
+	parts := make([]string, len(arcs))
+	for i, arc := range arcs {
+		parts[i] = strconv.Itoa(arc)
+	}
+	return strings.Join(parts, ".")
+}
+
+func encodeOID(s string) ([]byte, error) {
+	arcs, err := parseOID(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(arcs) < 2 || arcs[0] > 2 {
+		return nil, fmt.Errorf("bad OID '%s'", s)
+	}
+	arcs = append([]int{arcs[0]*40 + arcs[1]}, arcs[2:]...)
+	var b []byte
+	for _, arc := range arcs {
+		enc := []byte{byte(arc & 0x7f)}
+		for arc >>= 7; arc > 0; arc >>= 7 {
+			enc = append([]byte{byte(arc&0x7f) | 0x80}, enc...)
+		}
+		b = append(b, enc...)
+	}
+	return b, nil
+}
+
+func decodeOID(b []byte) string {
+	var arcs []int
+	arc := 0
+	for _, c := range b {
+		arc = arc<<7 | int(c&0x7f)
+		if c&0x80 == 0 {
+			if len(arcs) == 0 {
+				first := min(arc/40, 2)
+				arcs = append(arcs, first, arc-first*40)
+			} else {
+				arcs = append(arcs, arc)
+			}
+			arc = 0
+		}
+	}
+	return joinOID(arcs)
+}
@@ -0,0 +1,271 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Fake SNMP v2c agent serving a fixed set of OIDs on localhost.
+type testSNMPAgent struct {
+	conn      net.PacketConn
+	community string
+	oids      map[string]snmpVarBind
+	sorted    [][]int
+}
+
+func newTestSNMPAgent(t *testing.T, community string, vbs []snmpVarBind) *testSNMPAgent {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Can't listen: %s", err)
+	}
+	a := &testSNMPAgent{conn: conn, community: community,
+		oids: make(map[string]snmpVarBind)}
+	for _, vb := range vbs {
+		a.oids[vb.oid] = vb
+		arcs, _ := parseOID(vb.oid)
+		a.sorted = append(a.sorted, arcs)
+	}
+	slices.SortFunc(a.sorted, slices.Compare)
+	go a.serve()
+	t.Cleanup(func() { conn.Close() })
+	return a
+}
+
+func (a *testSNMPAgent) port() int {
+	return a.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (a *testSNMPAgent) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, from, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		req, err := decodeSNMP(buf[:n])
+		if err != nil || req.community != a.community {
+			continue
+		}
+		resp := snmpPDU{tag: snmpGetResponse, requestID: req.requestID,
+			community: req.community}
+		for _, vb := range req.varBinds {
+			resp.varBinds = append(resp.varBinds, a.lookup(req.tag, vb.oid))
+		}
+		msg, _ := encodeSNMP(&resp)
+		a.conn.WriteTo(msg, from)
+	}
+}
+
+func (a *testSNMPAgent) lookup(tag byte, oid string) snmpVarBind {
+	if tag == snmpGetRequest {
+		if vb, ok := a.oids[oid]; ok {
+			return vb
+		}
+		return snmpVarBind{oid: oid, tag: snmpNoSuchObject}
+	}
+	arcs, _ := parseOID(oid)
+	for _, next := range a.sorted {
+		if slices.Compare(next, arcs) > 0 {
+			return a.oids[joinOID(next)]
+		}
+	}
+	return snmpVarBind{oid: oid, tag: snmpEndOfMibView}
+}
+
+func testSNMPStr(oid, s string) snmpVarBind {
+	return snmpVarBind{oid: oid, tag: berOctetString, value: []byte(s)}
+}
+
+func testSNMPInt(oid string, v int64) snmpVarBind {
+	return snmpVarBind{oid: oid, tag: berInteger, value: encodeInt(v)}
+}
+
+// Agent of two daisy-chained Sentry towers, with outlet AA3 switched off.
+func testSNMPSentryOIDs() []snmpVarBind {
+	sysObjectID, _ := encodeOID("1.3.6.1.4.1.1718.3")
+	vbs := []snmpVarBind{
+		testSNMPStr(snmpSysDescr, "Sentry Switched CDU"),
+		{oid: snmpSysObjectID, tag: berOID, value: sysObjectID},
+		testSNMPStr(snmpSysName, "x0m0"),
+		// Something else from the same enterprise, that's not outlets
+		testSNMPInt("1.3.6.1.4.1.1718.3.1.1.0", 3),
+	}
+	for _, tower := range []string{"1", "2"} {
+		for _, outlet := range []string{"1", "2", "3", "4"} {
+			idx := tower + ".1." + outlet
+			name := "Tower" + tower + "_Outlet" + outlet
+			state := int64(1)
+			if idx == "1.1.3" {
+				state = 0
+			}
+			vbs = append(vbs,
+				testSNMPStr("1.3.6.1.4.1.1718.3.2.3.1.3."+idx, name),
+				testSNMPInt("1.3.6.1.4.1.1718.3.2.3.1.5."+idx, state))
+		}
+	}
+	return vbs
+}
+
+// Nothing answers over Redfish.
+func testRTFuncNoRedfish(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode: 404,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		Header:     make(http.Header),
+	}
+}
+
+func TestSNMPDriverDiscovery(t *testing.T) {
+	agent := newTestSNMPAgent(t, "pdus", testSNMPSentryOIDs())
+	empty := newTestSNMPAgent(t, "pdus", testSNMPSentryOIDs()[:3])
+	drivers := map[string]int{"SNMP-test": agent.port(), "SNMP-empty": empty.port()}
+	for name, port := range drivers {
+		if GetDiscoveryDriver(name) != nil {
+			continue
+		}
+		d := &SNMPDriver{Port: port, Timeout: 200 * time.Millisecond, Retries: 1}
+		if err := RegisterDiscoveryDriver(name, d); err != nil {
+			t.Fatalf("Can't register %s: %s", name, err)
+		}
+	}
+	if err := RegisterDiscoveryDriver("snmp", &SNMPDriver{}); err == nil {
+		t.Errorf("Expected error registering duplicate driver")
+	}
+
+	tests := []struct {
+		drivers   []string
+		community string
+		expStatus string
+		expDriver string
+	}{
+		{nil, "pdus", HTTPsGetFailed, ""},
+		{[]string{"SNMP-test"}, "wrong", HTTPsGetFailed, ""},
+		{[]string{"SNMP-empty"}, "pdus", HTTPsGetFailed, ""},
+		{[]string{"SNMP-empty", "SNMP-test"}, "pdus", DiscoverOK, "SNMP-test"},
+	}
+	for i, test := range tests {
+		ep := TestRedfishEPInitRtsCabPDUController
+		ep.FQDN = "127.0.0.1"
+		ep.Password = test.community
+		ep.client = NewTestClient(testRTFuncNoRedfish)
+		ep.SetVendorProfile(&VendorProfile{Name: "pdu", FallbackDrivers: test.drivers})
+		ep.GetRootInfo()
+		if ep.DiscInfo.LastStatus != test.expStatus ||
+			ep.DiscInfo.Driver != test.expDriver {
+			t.Errorf("Testcase %d: expected %s with '%s', got %s with '%s'",
+				i, test.expStatus, test.expDriver, ep.DiscInfo.LastStatus,
+				ep.DiscInfo.Driver)
+			continue
+		}
+		if test.expStatus != DiscoverOK {
+			if len(test.drivers) > 0 && len(ep.DiscInfo.Errors) == 0 {
+				t.Errorf("Testcase %d: driver failure not recorded", i)
+			}
+			continue
+		}
+		if len(ep.DiscInfo.Errors) != 0 {
+			t.Errorf("Testcase %d: unexpected errors %v", i, ep.DiscInfo.Errors)
+		}
+		for p, pduID := range []string{"1", "2"} {
+			pdu, ok := ep.RackPDUs.OIDs[pduID]
+			if !ok {
+				t.Errorf("Testcase %d: PDU %s not discovered", i, pduID)
+				continue
+			}
+			expID := "x0m0p" + []string{"0", "1"}[p]
+			if pdu.ID != expID || pdu.Type != "CabinetPDU" ||
+				pdu.LastStatus != DiscoverOK ||
+				pdu.PowerDistributionRF.Manufacturer != "Server Technology" {
+				t.Errorf("Testcase %d: bad PDU %s: %s/%s/%s/%s", i, pduID,
+					pdu.ID, pdu.Type, pdu.LastStatus,
+					pdu.PowerDistributionRF.Manufacturer)
+			}
+			if len(pdu.Outlets.OIDs) != 4 {
+				t.Errorf("Testcase %d: PDU %s: expected 4 outlets, got %d",
+					i, pduID, len(pdu.Outlets.OIDs))
+			}
+			tower := []string{"A", "B"}[p]
+			for o, num := range []string{"1", "2", "3", "4"} {
+				outID := tower + "A" + num
+				out, ok := pdu.Outlets.OIDs[outID]
+				if !ok {
+					t.Errorf("Testcase %d: outlet %s not discovered", i, outID)
+					continue
+				}
+				expState := "On"
+				if outID == "AA3" {
+					expState = "Off"
+				}
+				expID := expID + "v" + []string{"1", "2", "3", "4"}[o]
+				if out.ID != expID || out.Type != "CabinetPDUPowerConnector" ||
+					out.LastStatus != DiscoverOK || out.State != expState ||
+					out.Name != "Tower"+pduID+"_Outlet"+num {
+					t.Errorf("Testcase %d: outlet %s: expected %s %s, got "+
+						"%s %s %s %s (%s)", i, outID, expID, expState,
+						out.ID, out.Type, out.State, out.Name, out.LastStatus)
+				}
+			}
+		}
+	}
+}
+
+func TestSNMPBER(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129,
+		2147483647, -2147483648} {
+		if got := decodeInt(encodeInt(v)); got != v {
+			t.Errorf("Integer %d: got %d back", v, got)
+		}
+	}
+	for _, oid := range []string{"1.3.6.1.2.1.1.1.0",
+		"1.3.6.1.4.1.13742.6.3.5.3.1.3.1.24", "2.999.3", "0.0"} {
+		b, err := encodeOID(oid)
+		if err != nil {
+			t.Errorf("OID %s: %s", oid, err)
+		} else if got := decodeOID(b); got != oid {
+			t.Errorf("OID %s: got %s back", oid, got)
+		}
+	}
+	// Long enough for a multi-byte length
+	p := snmpPDU{tag: snmpGetResponse, requestID: 42, community: "public",
+		varBinds: []snmpVarBind{testSNMPStr(snmpSysDescr,
+			string(bytes.Repeat([]byte("x"), 300)))}}
+	msg, _ := encodeSNMP(&p)
+	got, err := decodeSNMP(msg)
+	if err != nil {
+		t.Fatalf("Decode failed: %s", err)
+	}
+	if got.requestID != 42 || got.community != "public" ||
+		len(got.varBinds) != 1 || len(got.varBinds[0].str()) != 300 {
+		t.Errorf("Bad round trip: %+v", got)
+	}
+	if _, err := decodeSNMP(msg[:len(msg)-1]); err == nil {
+		t.Errorf("Expected error for truncated message")
+	}
+}
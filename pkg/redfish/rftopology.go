@@ -0,0 +1,216 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"sort"
+	"strconv"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+// Multi-host sleds
+//
+// Dense servers (e.g. four nodes in 2U) can have a single BMC for all of
+// their nodes, listing them all in its Systems collection.  The System Ids
+// don't always say which sled a node is in (they may be serial numbers or
+// just numbered in boot order), but the Chassis links usually do: each sled
+// is a Chassis of its own, linking to its ComputerSystem and ContainedBy
+// the enclosure.  If every node can be placed in a sled of its own in the
+// same enclosure this way, the node ordinals follow the sleds rather than
+// the Systems collection, and the sleds are not made NodeEnclosures of
+// their own.
+//
+// Endpoints with one Chassis for several nodes, e.g. Cray dual-node cards,
+// or without Chassis links, are not affected.
+/////////////////////////////////////////////////////////////////////////////
+
+// Work out the sled of each physical node from the Chassis links and, if
+// each node has its own sled in a common enclosure, set the node ordinals
+// by sled.  Called at the start of phase 2, once all Chassis and Systems
+// have been fetched.
+func (ep *RedfishEP) resolveNodeSleds() {
+	ep.nodeSlots = nil
+	ep.nodeSleds = nil
+
+	// Aggregators already order their nodes by AggregationSource.
+	if ep.ServiceRootRF.AggregationService.Oid != "" {
+		return
+	}
+	systems := ep.physicalSystems()
+	if len(systems) < 2 {
+		return
+	}
+	chassis := make(map[string]*EpChassis, len(ep.Chassis.OIDs))
+	for _, c := range ep.Chassis.OIDs {
+		chassis[c.OdataID] = c
+	}
+	sledOf := make(map[string]*EpChassis, len(systems))
+	bySled := make(map[string]*EpChassis, len(systems))
+	enclosure := ""
+	for _, s := range systems {
+		sled := ep.getNodeSled(s, chassis)
+		if sled == nil || bySled[sled.OdataID] != nil {
+			return
+		}
+		parent := ep.getParentChassisOID(sled)
+		if chassis[parent] == nil || (enclosure != "" && parent != enclosure) {
+			return
+		}
+		enclosure = parent
+		sledOf[s.OdataID] = sled
+		bySled[sled.OdataID] = sled
+	}
+
+	// Order the sleds by their slot numbers if they all give one, otherwise
+	// by Id, e.g. Sled2 before Sled10.
+	order := make([]ResourceID, 0, len(bySled))
+	for oid := range bySled {
+		order = append(order, ResourceID{Oid: oid})
+	}
+	if slots, ok := sledSlots(bySled); ok {
+		sort.Slice(order, func(i, j int) bool {
+			return slots[order[i].Oid] < slots[order[j].Oid]
+		})
+	} else {
+		sort.Sort(ResourceIDSlice(order))
+	}
+	rank := make(map[string]int, len(order))
+	for i, oid := range order {
+		rank[oid.Oid] = i
+	}
+	ep.nodeSlots = make(map[string]int, len(systems))
+	ep.nodeSleds = make(map[string]string, len(bySled))
+	for _, s := range systems {
+		sled := sledOf[s.OdataID]
+		ep.nodeSlots[s.OdataID] = rank[sled.OdataID]
+		ep.nodeSleds[sled.OdataID] = enclosure
+	}
+}
+
+// The Systems that get node ordinals, as in getSystemOrdinalAndType: the
+// Physical ones, or those with no SystemType if there are none.
+func (ep *RedfishEP) physicalSystems() []*EpSystem {
+	var physical, untyped []*EpSystem
+	for _, s := range ep.Systems.OIDs {
+		switch s.SystemRF.SystemType {
+		case RFSubtypePhysical:
+			physical = append(physical, s)
+		case "":
+			untyped = append(untyped, s)
+		}
+	}
+	if len(physical) > 0 {
+		return physical
+	}
+	return untyped
+}
+
+// The innermost Chassis holding System s, i.e. the one linking to it with
+// the fewest ComputerSystems, or if none links to it, the one of the
+// System's own Chassis links that doesn't contain the others.
+func (ep *RedfishEP) getNodeSled(s *EpSystem, chassis map[string]*EpChassis) *EpChassis {
+	var sled *EpChassis
+	for _, c := range chassis {
+		for _, sys := range c.ChildSystems {
+			if sys.Oid != s.OdataID {
+				continue
+			}
+			if sled == nil || len(c.ChildSystems) < len(sled.ChildSystems) ||
+				(len(c.ChildSystems) == len(sled.ChildSystems) &&
+					c.RawOrdinal < sled.RawOrdinal) {
+				sled = c
+			}
+		}
+	}
+	if sled != nil {
+		return sled
+	}
+	linked := make(map[string]*EpChassis)
+	for _, link := range s.SystemRF.Links.Chassis {
+		if c, ok := chassis[link.Oid]; ok {
+			linked[c.OdataID] = c
+		}
+	}
+	for _, c := range linked {
+		delete(linked, ep.getParentChassisOID(c))
+	}
+	if len(linked) != 1 {
+		return nil
+	}
+	for _, c := range linked {
+		sled = c
+	}
+	return sled
+}
+
+// The OdataID of the Chassis containing c, from its ContainedBy link or
+// else the Contains links of the other Chassis.
+func (ep *RedfishEP) getParentChassisOID(c *EpChassis) string {
+	if c.PChassisOID != "" {
+		return c.PChassisOID
+	}
+	for _, p := range ep.Chassis.OIDs {
+		for _, child := range p.ChildChassis {
+			if child.Oid == c.OdataID {
+				return p.OdataID
+			}
+		}
+	}
+	return ""
+}
+
+// The slot number (Location.PartLocation.LocationOrdinalValue) of each
+// sled, if they all have a different one.
+func sledSlots(sleds map[string]*EpChassis) (map[string]int, bool) {
+	slots := make(map[string]int, len(sleds))
+	seen := make(map[int]bool, len(sleds))
+	for oid, c := range sleds {
+		loc := c.ChassisRF.Location
+		if loc == nil || loc.PartLocation == nil {
+			return nil, false
+		}
+		n, err := strconv.Atoi(loc.PartLocation.LocationOrdinalValue.String())
+		if err != nil || seen[n] {
+			return nil, false
+		}
+		seen[n] = true
+		slots[oid] = n
+	}
+	return slots, true
+}
+
+// True if c is the sled of a node in a multi-host enclosure that is
+// itself a NodeEnclosure candidate, so c should not be one too.
+func (ep *RedfishEP) isNodeSled(c *EpChassis) bool {
+	enclosure, ok := ep.nodeSleds[c.OdataID]
+	if !ok {
+		return false
+	}
+	for _, p := range ep.Chassis.OIDs {
+		if p.OdataID == enclosure {
+			return p.RedfishSubtype == RFSubtypeEnclosure ||
+				p.RedfishSubtype == RFSubtypeRackMount
+		}
+	}
+	return false
+}
@@ -0,0 +1,214 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package rf
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+// Four node, single BMC 2U enclosure whose System Ids are serial numbers,
+// so the Systems collection isn't in sled order.
+var testSledSystems = map[string]string{
+	"Sled1": "S4B2",
+	"Sled2": "S3C7",
+	"Sled3": "S1A9",
+	"Sled4": "S2D1",
+}
+
+// How the sleds are linked to the enclosure and their systems.
+type testSledLinks struct {
+	sledLinksSystem     bool   // Sled Chassis have ComputerSystems
+	systemLinksSled     bool   // Systems have Chassis links to their sled
+	containment         string // "ContainedBy" on the sleds, "Contains" on the enclosure, or none
+	enclosureHasSystems bool   // Enclosure has ComputerSystems for all nodes
+	slots               map[string]int
+}
+
+func NewRTFuncMultiHost(links testSledLinks) RTFunc {
+	sleds := []string{"Sled1", "Sled2", "Sled3", "Sled4"}
+	members := func(path string, ids []string) string {
+		m := []string{}
+		for _, id := range ids {
+			m = append(m, fmt.Sprintf(`{"@odata.id": "%s/%s"}`, path, id))
+		}
+		return "[" + strings.Join(m, ",") + "]"
+	}
+	systemIds := []string{}
+	for _, sled := range sleds {
+		systemIds = append(systemIds, testSledSystems[sled])
+	}
+	encLinks := `"ManagedBy": [{"@odata.id": "/redfish/v1/Managers/BMC"}]`
+	if links.containment == "Contains" {
+		encLinks += `, "Contains": ` + members("/redfish/v1/Chassis", sleds)
+	}
+	if links.enclosureHasSystems {
+		encLinks += `, "ComputerSystems": ` +
+			members("/redfish/v1/Systems", systemIds)
+	}
+	payloads := map[string]string{
+		"/redfish/v1": `{"@odata.id": "/redfish/v1", "RedfishVersion": "1.11.0",
+			"Systems": {"@odata.id": "/redfish/v1/Systems"},
+			"Chassis": {"@odata.id": "/redfish/v1/Chassis"},
+			"Managers": {"@odata.id": "/redfish/v1/Managers"}}`,
+		"/redfish/v1/Systems": `{"Members": ` +
+			members("/redfish/v1/Systems", systemIds) + `}`,
+		"/redfish/v1/Chassis": `{"Members": ` +
+			members("/redfish/v1/Chassis", append([]string{"Enclosure"}, sleds...)) + `}`,
+		"/redfish/v1/Chassis/Enclosure": `{"@odata.id": "/redfish/v1/Chassis/Enclosure",
+			"Id": "Enclosure", "ChassisType": "RackMount", "Manufacturer": "Contoso",
+			"Model": "H4-2U", "SerialNumber": "ENC0001",
+			"Status": {"State": "Enabled", "Health": "OK"},
+			"Links": {` + encLinks + `}}`,
+		"/redfish/v1/Managers": `{"Members": [{"@odata.id": "/redfish/v1/Managers/BMC"}]}`,
+		"/redfish/v1/Managers/BMC": `{"@odata.id": "/redfish/v1/Managers/BMC",
+			"Id": "BMC", "ManagerType": "BMC",
+			"Status": {"State": "Enabled", "Health": "OK"}}`,
+	}
+	for _, sled := range sleds {
+		sys := testSledSystems[sled]
+		sledLinks := `"ManagedBy": [{"@odata.id": "/redfish/v1/Managers/BMC"}]`
+		if links.sledLinksSystem {
+			sledLinks += `, "ComputerSystems": [{"@odata.id": "/redfish/v1/Systems/` +
+				sys + `"}]`
+		}
+		if links.containment == "ContainedBy" {
+			sledLinks += `, "ContainedBy": {"@odata.id": "/redfish/v1/Chassis/Enclosure"}`
+		}
+		location := ""
+		if slot, ok := links.slots[sled]; ok {
+			location = fmt.Sprintf(`"Location": {"PartLocation": {
+				"LocationType": "Slot", "LocationOrdinalValue": %d}},`, slot)
+		}
+		payloads["/redfish/v1/Chassis/"+sled] = `{"@odata.id": "/redfish/v1/Chassis/` +
+			sled + `", "Id": "` + sled + `", "ChassisType": "RackMount",` + location +
+			`"Manufacturer": "Contoso", "Model": "H4-N", "SerialNumber": "` + sled + `",
+			"Status": {"State": "Enabled", "Health": "OK"},
+			"Links": {` + sledLinks + `}}`
+		sysLinks := `"ManagedBy": [{"@odata.id": "/redfish/v1/Managers/BMC"}]`
+		if links.systemLinksSled {
+			sysLinks += `, "Chassis": [{"@odata.id": "/redfish/v1/Chassis/` + sled +
+				`"}, {"@odata.id": "/redfish/v1/Chassis/Enclosure"}]`
+		}
+		payloads["/redfish/v1/Systems/"+sys] = `{"@odata.id": "/redfish/v1/Systems/` +
+			sys + `", "Id": "` + sys + `", "SystemType": "Physical",
+			"Manufacturer": "Contoso", "Model": "H4-N", "SerialNumber": "` + sys + `",
+			"PowerState": "On", "Status": {"State": "Enabled", "Health": "OK"},
+			"ProcessorSummary": {"Count": 2},
+			"MemorySummary": {"TotalSystemMemoryGiB": 256},
+			"Links": {` + sysLinks + `}}`
+	}
+	return func(req *http.Request) *http.Response {
+		defer base.DrainAndCloseRequestBody(req)
+
+		for rpath, payload := range payloads {
+			if req.URL.String() == "https://"+testFQDN+rpath {
+				return &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(payload)),
+					Header:     make(http.Header),
+				}
+			}
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+			Header:     make(http.Header),
+		}
+	}
+}
+
+func TestMultiHostSledDiscovery(t *testing.T) {
+	inSledOrder := []string{"S4B2", "S3C7", "S1A9", "S2D1"}
+	inSystemsOrder := []string{"S1A9", "S2D1", "S3C7", "S4B2"}
+	tests := []struct {
+		links       testSledLinks
+		expOrder    []string // Systems for n0, n1, ...
+		expSledType string   // Type of the Sled1 Chassis
+	}{{
+		// Links both ways
+		testSledLinks{sledLinksSystem: true, systemLinksSled: true,
+			containment: "ContainedBy"},
+		inSledOrder, xnametypes.HMSTypeInvalid.String(),
+	}, {
+		// Only the enclosure's Contains and the Systems' Chassis links,
+		// ordered by slot rather than Id.
+		testSledLinks{systemLinksSled: true, containment: "Contains",
+			slots: map[string]int{
+				"Sled1": 4, "Sled2": 3, "Sled3": 2, "Sled4": 1}},
+		[]string{"S2D1", "S1A9", "S3C7", "S4B2"},
+		xnametypes.HMSTypeInvalid.String(),
+	}, {
+		// Duplicate slots are ignored in favor of the Ids
+		testSledLinks{sledLinksSystem: true, containment: "ContainedBy",
+			enclosureHasSystems: true, slots: map[string]int{
+				"Sled1": 1, "Sled2": 1, "Sled3": 2, "Sled4": 3}},
+		inSledOrder, xnametypes.HMSTypeInvalid.String(),
+	}, {
+		// Sleds that can't be placed in the enclosure
+		testSledLinks{sledLinksSystem: true},
+		inSystemsOrder, xnametypes.NodeEnclosure.String(),
+	}, {
+		// No way to tell which sled a node is in
+		testSledLinks{enclosureHasSystems: true, containment: "ContainedBy"},
+		inSystemsOrder, xnametypes.NodeEnclosure.String(),
+	}}
+	for i, test := range tests {
+		ep := TestRedfishEPInitAggregator
+		ep.client = NewTestClient(NewRTFuncMultiHost(test.links))
+		ep.GetRootInfo()
+		if ep.DiscInfo.LastStatus != DiscoverOK {
+			t.Errorf("Testcase %d: FAIL: bad LastStatus: %s", i,
+				ep.DiscInfo.LastStatus)
+			continue
+		}
+		for n, sys := range test.expOrder {
+			s, ok := ep.Systems.OIDs[sys]
+			if !ok {
+				t.Errorf("Testcase %d: FAIL: System %s not discovered", i, sys)
+				continue
+			}
+			expID := fmt.Sprintf("%sn%d", testXName, n)
+			if s.ID != expID {
+				t.Errorf("Testcase %d: FAIL: Expected %s to be %s, got %s",
+					i, sys, expID, s.ID)
+			}
+		}
+		if enc := ep.Chassis.OIDs["Enclosure"]; enc == nil ||
+			enc.Type != xnametypes.NodeEnclosure.String() {
+			t.Errorf("Testcase %d: FAIL: Enclosure is not a NodeEnclosure", i)
+		}
+		if sled := ep.Chassis.OIDs["Sled1"]; sled == nil ||
+			sled.Type != test.expSledType {
+			t.Errorf("Testcase %d: FAIL: Expected Sled1 of type %s", i,
+				test.expSledType)
+		}
+	}
+}
@@ -22,12 +22,16 @@
 package rf
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -352,3 +356,256 @@ func SplitAddrZone(ipaddr string) (addr, zone string) {
 	addr, zone = StringSplitLast(ipaddr, '%')
 	return
 }
+
+// Canonicalize an endpoint address, i.e. a hostname, FQDN, or IPv4 or
+// IPv6 address, each optionally followed by a port, and validate it.  The
+// canonical host is lower-cased, and IPv6 addresses are in net.ParseIP
+// form in brackets with any zone ID after a '%' (a URL-escaped "%25" is
+// accepted too).  IPv6 addresses need brackets to be followed by a port,
+// i.e. bare ones never have one.  port is empty or ":<port>", and the
+// default https port 443 is dropped.
+func NormalizeHostPort(hostport string) (host, port string, err error) {
+	s := strings.ToLower(strings.TrimSpace(hostport))
+	s = strings.Replace(s, "%25", "%", 1)
+	if s == "" {
+		return "", "", fmt.Errorf("empty address")
+	}
+	bracketed := strings.HasPrefix(s, "[")
+	if bracketed && !strings.HasSuffix(s, "]") {
+		if host, port, err = net.SplitHostPort(s); err != nil {
+			return "", "", fmt.Errorf("malformed address '%s'", hostport)
+		}
+	} else if !bracketed && strings.Count(s, ":") == 1 {
+		if host, port, err = net.SplitHostPort(s); err != nil {
+			return "", "", fmt.Errorf("malformed address '%s'", hostport)
+		}
+	} else {
+		host = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	}
+	if port != "" {
+		num, err := strconv.Atoi(port)
+		if err != nil || num < 1 || num > 65535 {
+			return "", "", fmt.Errorf("bad port '%s' in '%s'", port, hostport)
+		}
+		if num == 443 {
+			port = ""
+		} else {
+			port = ":" + strconv.Itoa(num)
+		}
+	}
+
+	if strings.ContainsAny(host, ":%") {
+		// IPv6
+		addr, zone := SplitAddrZone(host)
+		ip := net.ParseIP(addr)
+		if ip == nil || !strings.Contains(addr, ":") {
+			return "", "", fmt.Errorf("bad IPv6 address '%s'", hostport)
+		}
+		if strings.Contains(host, "%") {
+			if zone == "" || strings.Trim(zone, hostChars+".") != "" {
+				return "", "", fmt.Errorf("bad IPv6 zone in '%s'", hostport)
+			}
+			zone = "%" + zone
+		}
+		return "[" + ip.String() + zone + "]", port, nil
+	}
+	if bracketed {
+		return "", "", fmt.Errorf("brackets around non-IPv6 address '%s'",
+			hostport)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), port, nil
+	}
+	if len(host) > 253 {
+		return "", "", fmt.Errorf("hostname too long in '%s'", hostport)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 ||
+			strings.Trim(label, hostChars) != "" ||
+			strings.HasPrefix(label, "-") {
+			return "", "", fmt.Errorf("bad hostname '%s'", hostport)
+		}
+	}
+	return host, port, nil
+}
+
+// Characters allowed in hostname labels and (with '.') IPv6 zones.
+// Underscores are not valid DNS, but are seen in the wild.
+const hostChars = "abcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// Arrays that are never cut down by readCappedJSON.  Dropping collection
+// Members would silently drop the components they lead to.
+var rfUncappedArrays = map[string]bool{
+	"Members": true,
+}
+
+// Reads a Redfish JSON payload from r and re-encodes it with every array,
+// at any depth, cut down to at most max entries, except rfUncappedArrays.
+// The payload is walked with a streaming decoder, so neither the raw
+// payload nor dropped entries are ever held in memory; only the capped
+// output is.  The number of dropped entries per field is logged.  Fails
+// with ErrRFDiscResponseTooLarge if r holds more than maxBytes, or with
+// the decoding error if it is not a single JSON value.
+func readCappedJSON(path string, r io.Reader, maxBytes int64, max int) ([]byte, error) {
+	mr := &maxBytesReader{r: r, n: maxBytes}
+	jc := &jsonCapper{
+		dec:       json.NewDecoder(mr),
+		max:       max,
+		truncated: make(map[string]int),
+	}
+	jc.dec.UseNumber()
+	jc.enc = json.NewEncoder(&jc.out)
+	jc.enc.SetEscapeHTML(false)
+
+	err := jc.copyValue("")
+	if err == nil {
+		// Only whitespace may follow the value.
+		if _, err = jc.dec.Token(); err == io.EOF {
+			err = nil
+		} else if err == nil {
+			err = errors.New("invalid data after top-level value")
+		}
+	}
+	if mr.exceeded {
+		return nil, ErrRFDiscResponseTooLarge
+	} else if err != nil {
+		return nil, err
+	}
+	if len(jc.truncated) != 0 {
+		fields := make([]string, 0, len(jc.truncated))
+		for field, n := range jc.truncated {
+			fields = append(fields, fmt.Sprintf("%s(%d)", field, n))
+		}
+		sort.Strings(fields)
+		errlog.Printf("%s: arrays exceeded %d entries, dropped: %s",
+			path, max, strings.Join(fields, ", "))
+	}
+	return jc.out.Bytes(), nil
+}
+
+// Reader that fails with ErrRFDiscResponseTooLarge once more than n bytes
+// are read from r.
+type maxBytesReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (mr *maxBytesReader) Read(p []byte) (int, error) {
+	if mr.n <= 0 {
+		// Only an error if there is more.
+		var b [1]byte
+		if n, _ := io.ReadAtLeast(mr.r, b[:], 1); n > 0 {
+			mr.exceeded = true
+			return 0, ErrRFDiscResponseTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > mr.n {
+		p = p[:mr.n]
+	}
+	n, err := mr.r.Read(p)
+	mr.n -= int64(n)
+	return n, err
+}
+
+// State of readCappedJSON.
+type jsonCapper struct {
+	dec       *json.Decoder
+	enc       *json.Encoder // Writes tokens to out
+	out       bytes.Buffer
+	max       int
+	truncated map[string]int // Entries dropped per field
+}
+
+// Copy the next JSON value from dec to out, cutting down arrays.  field is
+// the name of the object key the value is stored under.
+func (jc *jsonCapper) copyValue(field string) error {
+	tok, err := jc.dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return jc.writeToken(tok)
+	}
+	switch delim {
+	case '{':
+		jc.out.WriteByte('{')
+		for i := 0; jc.dec.More(); i++ {
+			keyTok, err := jc.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			if i > 0 {
+				jc.out.WriteByte(',')
+			}
+			if err := jc.writeToken(key); err != nil {
+				return err
+			}
+			jc.out.WriteByte(':')
+			if err := jc.copyValue(key); err != nil {
+				return err
+			}
+		}
+		jc.out.WriteByte('}')
+	case '[':
+		jc.out.WriteByte('[')
+		n := 0
+		for ; jc.dec.More(); n++ {
+			if n >= jc.max && !rfUncappedArrays[field] {
+				if err := jc.skipValue(); err != nil {
+					return err
+				}
+				continue
+			}
+			if n > 0 {
+				jc.out.WriteByte(',')
+			}
+			if err := jc.copyValue(field); err != nil {
+				return err
+			}
+		}
+		jc.out.WriteByte(']')
+		if n > jc.max && !rfUncappedArrays[field] {
+			jc.truncated[field] += n - jc.max
+		}
+	}
+	// Consume the closing delimiter.
+	_, err = jc.dec.Token()
+	return err
+}
+
+// Read past the next JSON value without keeping any of it.
+func (jc *jsonCapper) skipValue() error {
+	depth := 0
+	for {
+		tok, err := jc.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// Write a JSON string, number, bool or null to out, as it was in the
+// payload (e.g. without escaping '<').
+func (jc *jsonCapper) writeToken(tok json.Token) error {
+	if err := jc.enc.Encode(tok); err != nil {
+		return err
+	}
+	// Drop the newline Encode adds.
+	jc.out.Truncate(jc.out.Len() - 1)
+	return nil
+}
@@ -377,3 +377,132 @@ func TestGetIPAddressString(t *testing.T) {
 		}
 	}
 }
+
+func TestNormalizeHostPort(t *testing.T) {
+	tests := []struct {
+		in      string
+		expHost string
+		expPort string
+		expErr  bool
+	}{
+		{"x0c0s0b0", "x0c0s0b0", "", false},
+		{" X0C0S0B0.Local ", "x0c0s0b0.local", "", false},
+		{"x0c0s0b0.local:8443", "x0c0s0b0.local", ":8443", false},
+		{"x0c0s0b0.local:443", "x0c0s0b0.local", "", false},
+		{"x0c0s0b0.local:0", "", "", true},
+		{"x0c0s0b0.local:70000", "", "", true},
+		{"x0c0s0b0.local:https", "", "", true},
+		{"x0c0s0b0..local", "", "", true},
+		{"-x0c0s0b0.local", "", "", true},
+		{"x0c0s0b0/redfish", "", "", true},
+		{"[x0c0s0b0.local]", "", "", true},
+		{"10.1.2.3:8443", "10.1.2.3", ":8443", false},
+		{"fd40::0001", "[fd40::1]", "", false},
+		{"[FD40::1]", "[fd40::1]", "", false},
+		{"[fd40::1]:443", "[fd40::1]", "", false},
+		{"[fd40::1]:8443", "[fd40::1]", ":8443", false},
+		{"fe80::1%eth0", "[fe80::1%eth0]", "", false},
+		{"[fe80::1%25eth0.100]:8443", "[fe80::1%eth0.100]", ":8443", false},
+		{"fe80::1%", "", "", true},
+		{"fe80::1%eth/0", "", "", true},
+		{"[fd40::1]8443", "", "", true},
+		{"[fd40::1", "", "", true},
+		{"fd40::zz", "", "", true},
+		{"10.1.2.3%eth0", "", "", true},
+		{"", "", "", true},
+	}
+	for i, test := range tests {
+		host, port, err := NormalizeHostPort(test.in)
+		if test.expErr {
+			if err == nil {
+				t.Errorf("Testcase %d: FAIL: expected error for '%s', got '%s' '%s'",
+					i, test.in, host, port)
+			}
+		} else if err != nil {
+			t.Errorf("Testcase %d: FAIL: unexpected error for '%s': %s",
+				i, test.in, err)
+		} else if host != test.expHost || port != test.expPort {
+			t.Errorf("Testcase %d: FAIL: expected '%s' '%s' for '%s', got '%s' '%s'",
+				i, test.expHost, test.expPort, test.in, host, port)
+		}
+	}
+}
+
+func TestReadCappedJSON(t *testing.T) {
+	tests := []struct {
+		in       string
+		maxBytes int64
+		max      int
+		out      string
+		err      error // nil if no error is expected
+		anyErr   bool  // true if some other error is expected
+	}{{
+		in:       `{"Name": "Power", "Voltages": [1, 2]}`,
+		maxBytes: 100,
+		max:      100,
+		out:      `{"Name":"Power","Voltages":[1,2]}`,
+	}, {
+		in:       `{"Name":"Power","Voltages":[{"Id":"0"},{"Id":"1"},{"Id":"2"},{"Id":"3"}],"Count":4}`,
+		maxBytes: 1000,
+		max:      2,
+		out:      `{"Name":"Power","Voltages":[{"Id":"0"},{"Id":"1"}],"Count":4}`,
+	}, {
+		// Collection Members are kept whole, arrays in them are not.
+		in:       `{"Members":[{"@odata.id":"/a","Ids":[1,2,3,4]},{"@odata.id":"/b"},{"@odata.id":"/c"}]}`,
+		maxBytes: 1000,
+		max:      2,
+		out:      `{"Members":[{"@odata.id":"/a","Ids":[1,2]},{"@odata.id":"/b"},{"@odata.id":"/c"}]}`,
+	}, {
+		// Dropped entries are skipped whatever they hold.
+		in:       `{"Temps":[1,{"a":[{"b":{}}]},[3,[4]],"<x>",5.50,null,true]}`,
+		maxBytes: 1000,
+		max:      1,
+		out:      `{"Temps":[1]}`,
+	}, {
+		// Values are written as they were.
+		in:       `{"Name":"<A&B>","Big":12345678901234567890,"Ok":false,"X":null}`,
+		maxBytes: 1000,
+		max:      1,
+		out:      `{"Name":"<A&B>","Big":12345678901234567890,"Ok":false,"X":null}`,
+	}, {
+		in:       `{"Members":[1,2,3,4,5,6,7,8,9,10]}`,
+		maxBytes: 20,
+		max:      100,
+		err:      ErrRFDiscResponseTooLarge,
+	}, {
+		// Exactly the limit is fine.
+		in:       `{"Members":[1,2,3]}`,
+		maxBytes: 19,
+		max:      100,
+		out:      `{"Members":[1,2,3]}`,
+	}, {
+		in:       `{"Members":[1,2,3,4,5,6,7,8,9,10,11`,
+		maxBytes: 1000,
+		max:      2,
+		anyErr:   true,
+	}, {
+		in:       `{"Members":[]} {}`,
+		maxBytes: 1000,
+		max:      2,
+		anyErr:   true,
+	}, {
+		in:       ``,
+		maxBytes: 1000,
+		max:      2,
+		anyErr:   true,
+	}}
+	for i, test := range tests {
+		out, err := readCappedJSON("/test", strings.NewReader(test.in),
+			test.maxBytes, test.max)
+		if test.err != nil || test.anyErr {
+			if err == nil || (test.err != nil && err != test.err) {
+				t.Errorf("Testcase %d: FAIL: expected error %v, got %v", i,
+					test.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("Testcase %d: FAIL: unexpected error %s", i, err)
+		} else if string(out) != test.out {
+			t.Errorf("Testcase %d: FAIL: expected '%s', got '%s'", i, test.out, out)
+		}
+	}
+}
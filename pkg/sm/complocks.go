@@ -59,6 +59,11 @@ var ErrCompLockV2RKey = base.NewHMSError("sm",
 	"Reservation Key required for operation")
 var ErrCompLockV2DKey = base.NewHMSError("sm",
 	"Deputy Key required for operation")
+var ErrCompLockV2BadReason = base.NewHMSError("sm",
+	"Reason must be 255 characters or less")
+
+// Longest Reason kept in the lock audit log.
+const CLReasonMaxLen = 255
 
 const (
 	CLProcessingModelRigid = "rigid"
@@ -93,6 +98,19 @@ const (
 	CLResultServerError = "ServerError"
 )
 
+// Lock audit log actions
+const (
+	CLAuditLock         = "Lock"
+	CLAuditUnlock       = "Unlock"
+	CLAuditDisable      = "Disable"
+	CLAuditRepair       = "Repair"
+	CLAuditReserve      = "Reserve"
+	CLAuditRenew        = "Renew"
+	CLAuditRelease      = "Release"
+	CLAuditForceRelease = "ForceRelease"
+	CLAuditExpire       = "Expire"
+)
+
 //////////////////////////////////////////////
 // Responses
 //////////////////////////////////////////////
@@ -143,9 +161,31 @@ type CompLockV2Status struct {
 	NotFound   []string     `json:"NotFound,omitempty"`
 }
 
-//////////////////////////////////////////////
-// Payloads
-//////////////////////////////////////////////
+// Expiring ServRes
+type CompLockV2Expiring struct {
+	ID             string `json:"ID"`
+	CreationTime   string `json:"CreationTime,omitempty"`
+	ExpirationTime string `json:"ExpirationTime,omitempty"`
+}
+type CompLockV2ExpiringArray struct {
+	Reservations []CompLockV2Expiring `json:"Reservations"`
+}
+
+// Lock audit log entry, one change to the lock or reservation of a
+// component.  Actor is whoever made the change and Reason why, if known.
+// ExpirationTime is that of the reservation, if it has one.
+type CompLockV2AuditEntry struct {
+	ID             string `json:"ID"`
+	Action         string `json:"Action"`
+	Actor          string `json:"Actor"`
+	Reason         string `json:"Reason,omitempty"`
+	ExpirationTime string `json:"ExpirationTime,omitempty"`
+	Timestamp      string `json:"Timestamp"`
+}
+type CompLockV2AuditArray struct {
+	Entries []*CompLockV2AuditEntry `json:"Entries"`
+}
+
 
 // Create/Remove Res, Create ServRes, Check/Lock/Unlock/Repair/Disable Lock
 type CompLockV2Filter struct {
@@ -168,6 +208,13 @@ type CompLockV2Filter struct {
 	Locked              []string `json:"Locked"`
 	Reserved            []string `json:"Reserved"`
 	ReservationDisabled []string `json:"ReservationDisabled"`
+
+	// Also select every component under each of the ComponentIDs, e.g. all
+	// of the nodes in a chassis or on a blade.
+	IncludeDescendants bool `json:"IncludeDescendants,omitempty"`
+
+	// Why, for the lock audit log.
+	Reason string `json:"Reason,omitempty"`
 }
 
 // Release Res, Release/Renew ServRes
@@ -180,6 +227,7 @@ type CompLockV2ReservationFilter struct {
 	ReservationKeys     []CompLockV2Key `json:"ReservationKeys"`
 	ProcessingModel     string          `json:"ProcessingModel"`
 	ReservationDuration int             `json:"ReservationDuration"`
+	Reason              string          `json:"Reason,omitempty"`
 }
 
 // Check ServRes
@@ -195,6 +243,9 @@ func (cl *CompLockV2Filter) VerifyNormalize() error {
 	if cl.ReservationDuration > 15 {
 		return ErrCompLockV2BadDuration
 	}
+	if len(cl.Reason) > CLReasonMaxLen {
+		return ErrCompLockV2BadReason
+	}
 	return nil
 }
 
@@ -215,6 +266,9 @@ func (clr *CompLockV2ReservationFilter) VerifyNormalize() error {
 	if clr.ReservationDuration > 15 {
 		return ErrCompLockV2BadDuration
 	}
+	if len(clr.Reason) > CLReasonMaxLen {
+		return ErrCompLockV2BadReason
+	}
 	for i, key := range clr.ReservationKeys {
 		err := key.VerifyNormalize()
 		if err != nil {
@@ -26,6 +26,7 @@ package sm
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	base "github.com/Cray-HPE/hms-base/v2"
@@ -87,6 +88,16 @@ func TestVerifyNormalizeCompLockV2Filter(t *testing.T) {
 			ReservationDuration: 16,
 		},
 		err: ErrCompLockV2BadDuration,
+	}, {
+		in: &CompLockV2Filter{
+			ProcessingModel: CLProcessingModelRigid,
+			Reason:          strings.Repeat("x", CLReasonMaxLen+1),
+		},
+		out: &CompLockV2Filter{
+			ProcessingModel: CLProcessingModelRigid,
+			Reason:          strings.Repeat("x", CLReasonMaxLen+1),
+		},
+		err: ErrCompLockV2BadReason,
 	}}
 	for i, test := range tests {
 		err := test.in.VerifyNormalize()
@@ -195,6 +206,16 @@ func TestVerifyNormalizeCompLockV2ReservationFilter(t *testing.T) {
 			ReservationDuration: 16,
 		},
 		err: ErrCompLockV2BadDuration,
+	}, {
+		in: &CompLockV2ReservationFilter{
+			ProcessingModel: CLProcessingModelRigid,
+			Reason:          strings.Repeat("x", CLReasonMaxLen+1),
+		},
+		out: &CompLockV2ReservationFilter{
+			ProcessingModel: CLProcessingModelRigid,
+			Reason:          strings.Repeat("x", CLReasonMaxLen+1),
+		},
+		err: ErrCompLockV2BadReason,
 	}}
 	for i, test := range tests {
 		err := test.in.VerifyNormalize()
@@ -315,3 +315,69 @@ func (cp *ComponentPut) VerifyNormalize() error {
 	}
 	return nil
 }
+
+// A change to the State or Flag of a component, or its deletion, as kept in
+// the component state history.  Actor is who made the change, if known:
+// the API caller, or smd:discovery, smd:redfish-event, etc. for changes
+// HSM made itself.
+type CompStateHistEntry struct {
+	State     string `json:"State"`
+	Flag      string `json:"Flag"`
+	Deleted   bool   `json:"Deleted,omitempty"`
+	Actor     string `json:"Actor"`
+	Timestamp string `json:"Timestamp"`
+}
+
+// Time a component spent in each State (or Flag) in a time range, in
+// seconds.  Time before it was added or while it was deleted is not counted.
+type CompStateTimes struct {
+	By        string             `json:"By"`
+	StartTime string             `json:"StartTime"`
+	EndTime   string             `json:"EndTime"`
+	Seconds   map[string]float64 `json:"Seconds"`
+}
+
+// State history of a component, oldest change first.
+type CompStateHistory struct {
+	ID      string                `json:"ID"`
+	History []*CompStateHistEntry `json:"History"`
+	Times   *CompStateTimes       `json:"Times,omitempty"`
+}
+
+// Heartbeat Status values.  A heartbeat with no Status is the same as OK.
+const (
+	HeartbeatOK   = "OK"
+	HeartbeatHalt = "Halt" // The node's OS is shutting down or panicked
+)
+
+// A heartbeat from a component, as reported to HSM.
+type Heartbeat struct {
+	ID     string `json:"ID"`
+	Status string `json:"Status,omitempty"`
+}
+
+// Heartbeats from many components at once.
+type HeartbeatArray struct {
+	Heartbeats []Heartbeat `json:"Heartbeats"`
+}
+
+// Result of reporting heartbeats.  Unknown are the IDs that are not
+// components, whose heartbeats were ignored.
+type HeartbeatResult struct {
+	Accepted int      `json:"Accepted"`
+	Unknown  []string `json:"Unknown"`
+}
+
+// The last heartbeat of a component and its current State and Flag.
+// LastSeen is in RFC3339 format and Age is the seconds since then.
+type CompHeartbeat struct {
+	ID       string  `json:"ID"`
+	State    string  `json:"State"`
+	Flag     string  `json:"Flag"`
+	LastSeen string  `json:"LastSeen"`
+	Age      float64 `json:"Age"`
+}
+
+type CompHeartbeatArray struct {
+	Heartbeats []*CompHeartbeat `json:"Heartbeats"`
+}
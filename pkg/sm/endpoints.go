@@ -24,6 +24,7 @@ package sm
 
 import (
 	"encoding/json"
+	"strings"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
@@ -49,6 +50,9 @@ type RedfishEndpoint struct {
 
 	ComponentEndpoints []*ComponentEndpoint `json:"ComponentEndpoints,omitempty"`
 	ServiceEndpoints   []*ServiceEndpoint   `json:"ServiceEndpoints,omitempty"`
+
+	// Filled in on GET when the BMC prober is on.  Ignored on input.
+	Liveness *RedfishEndpointLiveness `json:"Liveness,omitempty"`
 }
 
 // RedfishEndpointPatch is just rf.RedfishEPDescription but everything is a pointer.
@@ -69,6 +73,7 @@ type RedfishEndpointPatch struct {
 	IPAddr         *string `json:"IPAddress"`
 	RediscOnUpdate *bool   `json:"RediscoverOnUpdate"`
 	TemplateID     *string `json:"TemplateID"`
+	Protocol       *string `json:"Protocol"`
 }
 
 // A collection of 0-n RedfishEndpoints.  It could just be an ordinary
@@ -222,6 +227,55 @@ func (cep *ComponentEndpoint) EncodeComponentInfo() ([]byte, error) {
 	return infoJSON, err
 }
 
+// Fans and temperature sensors discovered for one ComponentEndpoint.
+type ComponentThermalSensors struct {
+	ID                string                  `json:"ID"`
+	Type              string                  `json:"Type"`
+	RedfishEndpointID string                  `json:"RedfishEndpointID"`
+	RedfishURL        string                  `json:"RedfishURL"`
+	Sensors           []*rf.ThermalSensorInfo `json:"Sensors"`
+}
+
+// Output of a thermal sensor inventory query.
+type ThermalSensorArray struct {
+	Components []*ComponentThermalSensors `json:"Components"`
+}
+
+// Fans and temperature sensors of a ComponentEndpoint, if any, optionally
+// only those of the given kinds (Temperature/Fan, case-insensitive).
+// Returns nil if there are none.
+func (cep *ComponentEndpoint) ThermalSensors(kinds []string) *ComponentThermalSensors {
+	var sensors []*rf.ThermalSensorInfo
+	if cep.RedfishChassisInfo != nil {
+		sensors = cep.RedfishChassisInfo.Sensors
+	} else if cep.RedfishSystemInfo != nil {
+		sensors = cep.RedfishSystemInfo.Sensors
+	}
+	cts := &ComponentThermalSensors{
+		ID:                cep.ID,
+		Type:              cep.Type,
+		RedfishEndpointID: cep.RfEndpointID,
+		RedfishURL:        cep.URL,
+		Sensors:           []*rf.ThermalSensorInfo{},
+	}
+	for _, sensor := range sensors {
+		if len(kinds) == 0 {
+			cts.Sensors = append(cts.Sensors, sensor)
+			continue
+		}
+		for _, kind := range kinds {
+			if strings.EqualFold(kind, sensor.Kind) {
+				cts.Sensors = append(cts.Sensors, sensor)
+				break
+			}
+		}
+	}
+	if len(cts.Sensors) == 0 {
+		return nil
+	}
+	return cts
+}
+
 type ServiceEndpoint struct {
 	// Embedded struct
 	rf.ServiceDescription
@@ -243,3 +297,44 @@ type ServiceEndpoint struct {
 type ServiceEndpointArray struct {
 	ServiceEndpoints []*ServiceEndpoint `json:"ServiceEndpoints"`
 }
+
+// Values for EventSubscription.Status
+const (
+	EventSubPending = "Pending" // Not yet created, e.g. before discovery
+	EventSubActive  = "Active"  // Last seen present on the endpoint
+	EventSubMissing = "Missing" // Last verify found no matching subscription
+	EventSubFailed  = "Failed"  // Last operation couldn't reach the endpoint
+)
+
+// The Redfish event subscription SMD keeps on a RedfishEndpoint, pointing
+// at the event collector, and its state as of the last operation on it.
+type EventSubscription struct {
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+	Destination       string `json:"Destination"`
+	Context           string `json:"Context,omitempty"`
+	SubscriptionURI   string `json:"SubscriptionURI,omitempty"`
+	Status            string `json:"Status"`
+	LastError         string `json:"LastError,omitempty"`
+	LastUpdated       string `json:"LastUpdated,omitempty"`
+}
+
+// A collection of 0-n EventSubscriptions.
+type EventSubscriptionArray struct {
+	EventSubscriptions []*EventSubscription `json:"EventSubscriptions"`
+}
+
+// Whether a RedfishEndpoint answered the BMC prober.  LastContact is empty
+// if it never has, and Failures counts the probes since it last did.
+type RedfishEndpointLiveness struct {
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+	LastContact       string `json:"LastContact,omitempty"`
+	LastProbe         string `json:"LastProbe"`
+	Failures          int    `json:"Failures"`
+	Unreachable       bool   `json:"Unreachable"`
+	LastError         string `json:"LastError,omitempty"`
+}
+
+// A collection of 0-n RedfishEndpointLiveness.
+type RedfishEndpointLivenessArray struct {
+	Liveness []*RedfishEndpointLiveness `json:"Liveness"`
+}
@@ -75,3 +75,37 @@ type SMEventArray struct {
 	Timestamp string     `json:"Timestamp"`
 	Events    []*SMEvent `json:"Events"`
 }
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Message bus events
+//
+////////////////////////////////////////////////////////////////////////////
+
+// Version of the BusEvent schema.  The major version changes only if fields
+// are removed or change meaning; new fields and event types are minor.
+const BusEventVersion = "1.0.0"
+
+// BusEvent types and the type of their Data
+const (
+	BusCompChange   = "ComponentChange"     // SCNPayload
+	BusDiscComplete = "DiscoveryComplete"   // BusDiscoveryComplete
+	BusHWInvChange  = "HWInventoryChange"   // DiscoveryChange
+	BusResExpiring  = "ReservationExpiring" // CompLockV2ExpiringArray
+	BusResExpired   = "ReservationExpired"  // CompLockV2ExpiringArray
+)
+
+// One change published to the message bus.
+type BusEvent struct {
+	Version   string      `json:"Version"`
+	Type      string      `json:"Type"`
+	Source    string      `json:"Source"`
+	Timestamp string      `json:"Timestamp"`
+	Data      interface{} `json:"Data"`
+}
+
+// BusEvent Data for a completed discovery.
+type BusDiscoveryComplete struct {
+	DiscoveryID        uint     `json:"DiscoveryID"`
+	RedfishEndpointIDs []string `json:"RedfishEndpointIDs"`
+}
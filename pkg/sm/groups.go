@@ -41,6 +41,12 @@ var ErrGroupBadField = base.NewHMSError("sm",
 	"group or partition field has invalid characters")
 var ErrPartBadName = base.NewHMSError("sm",
 	"Bad partition name. Must be p# or p#.#")
+var ErrGroupSelfChild = base.NewHMSError("sm",
+	"group cannot be a child of itself")
+var ErrPartMoveSame = base.NewHMSError("sm",
+	"cannot move members to the partition they are already in")
+var ErrPartMoveNoIDs = base.NewHMSError("sm",
+	"no members to move")
 
 // Normalize group field by lowercasing
 func NormalizeGroupField(f string) string {
@@ -130,7 +136,8 @@ type Group struct {
 	Description    string   `json:"description"`
 	ExclusiveGroup string   `json:"exclusiveGroup,omitempty"`
 	Tags           []string `json:"tags,omitempty"`
-	Members        Members  `json:"members"` // List of xnames, required.
+	Children       []string `json:"children,omitempty"` // Child group labels
+	Members        Members  `json:"members"`            // List of xnames, required.
 
 	// Private
 	normalized bool
@@ -164,6 +171,9 @@ func (g *Group) Normalize() {
 	for i, f := range g.Tags {
 		g.Tags[i] = strings.ToLower(f)
 	}
+	for i, f := range g.Children {
+		g.Children[i] = strings.ToLower(f)
+	}
 	g.Members.Normalize()
 }
 
@@ -188,36 +198,65 @@ func (g *Group) Verify() error {
 			return err
 		}
 	}
+	if err := VerifyGroupChildren(g.Label, g.Children); err != nil {
+		return err
+	}
 	if err := g.Members.Verify(); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Patchable fields if included in payload.
+// Verify child group labels.  Cycles through other groups can only be found
+// by the database.
+func VerifyGroupChildren(label string, children []string) error {
+	for _, f := range children {
+		if err := VerifyGroupField(f); err != nil {
+			return err
+		}
+		if f == label {
+			return ErrGroupSelfChild
+		}
+	}
+	return nil
+}
+
+// Patchable fields if included in payload.  Children replaces the list of
+// child groups; an empty list removes them all.
 type GroupPatch struct {
 	Description *string   `json:"description"`
 	Tags        *[]string `json:"tags"`
+	Children    *[]string `json:"children"`
 }
 
 // Normalize groupPatch (just lower case tags, basically, but keeping same
 // interface as others.
 func (gp *GroupPatch) Normalize() {
-	if gp.Tags == nil {
-		return
+	if gp.Tags != nil {
+		for i, f := range *gp.Tags {
+			(*gp.Tags)[i] = strings.ToLower(f)
+		}
 	}
-	for i, f := range *gp.Tags {
-		(*gp.Tags)[i] = strings.ToLower(f)
+	if gp.Children != nil {
+		for i, f := range *gp.Children {
+			(*gp.Children)[i] = strings.ToLower(f)
+		}
 	}
 }
 
-// Analgous Verify call for GroupPatch objects.
+// Analgous Verify call for GroupPatch objects.  The label of the group being
+// patched isn't known here, so a group listing itself as a child is caught by
+// the database.
 func (gp *GroupPatch) Verify() error {
-	if gp.Tags == nil {
-		return nil
+	if gp.Tags != nil {
+		for _, f := range *gp.Tags {
+			if err := VerifyGroupField(f); err != nil {
+				return err
+			}
+		}
 	}
-	for _, f := range *gp.Tags {
-		if err := VerifyGroupField(f); err != nil {
+	if gp.Children != nil {
+		if err := VerifyGroupChildren("", *gp.Children); err != nil {
 			return err
 		}
 	}
@@ -323,6 +362,52 @@ func (pp *PartitionPatch) Verify() error {
 	return nil
 }
 
+// For POST to the partitions move endpoint, to move members from one
+// partition to another without them being in neither in between.
+type PartitionMove struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	IDs  []string `json:"ids"`
+}
+
+// Lowercase partition names and normalize xnames, dropping duplicates.
+func (pm *PartitionMove) Normalize() {
+	pm.From = NormalizeGroupField(pm.From)
+	pm.To = NormalizeGroupField(pm.To)
+	seen := make(map[string]bool, len(pm.IDs))
+	ids := make([]string, 0, len(pm.IDs))
+	for _, id := range pm.IDs {
+		id = xnametypes.NormalizeHMSCompID(id)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	pm.IDs = ids
+}
+
+// Check the fields of a PartitionMove.
+func (pm *PartitionMove) Verify() error {
+	if err := VerifyGroupField(pm.From); err != nil {
+		return err
+	}
+	if err := VerifyGroupField(pm.To); err != nil {
+		return err
+	}
+	if pm.From == pm.To {
+		return ErrPartMoveSame
+	}
+	if len(pm.IDs) == 0 {
+		return ErrPartMoveNoIDs
+	}
+	for _, id := range pm.IDs {
+		if !xnametypes.IsHMSCompIDValid(id) {
+			return base.ErrHMSTypeInvalid
+		}
+	}
+	return nil
+}
+
 ///////////////////////////////////////////////////////////////////////////
 //
 // Membership - Reverse lookup of group and partition info by component id
@@ -334,3 +419,36 @@ type Membership struct {
 	GroupLabels   []string `json:"groupLabels"`
 	PartitionName string   `json:"partitionName"`
 }
+
+///////////////////////////////////////////////////////////////////////////
+//
+// Membership history - Who added or removed group and partition members
+//
+///////////////////////////////////////////////////////////////////////////
+
+// Actions in the membership history
+const (
+	MemberHistAdded   = "added"
+	MemberHistRemoved = "removed"
+)
+
+// A single member being added to or removed from a group or partition.
+// Actor is whoever made the change, if known.
+type MemberHistEntry struct {
+	ID        string `json:"id"`
+	Action    string `json:"action"`
+	Actor     string `json:"actor"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Membership history of a group, oldest change first.
+type GroupHistory struct {
+	Label   string             `json:"label"`
+	History []*MemberHistEntry `json:"history"`
+}
+
+// Membership history of a partition, oldest change first.
+type PartitionHistory struct {
+	Name    string             `json:"name"`
+	History []*MemberHistEntry `json:"history"`
+}
@@ -1416,6 +1416,13 @@ type HWInvByFRU struct {
 	// TODO: Remaining types in hmsTypeArray
 }
 
+// A HWInvByFRU along with the location (xname) it is currently at, if it
+// is installed anywhere.
+type HWInvByFRULocation struct {
+	Location string `json:"Location"` // Empty if not at any location
+	HWInvByFRU
+}
+
 // HWInventoryByFRUType properties.  Used to select proper subtype in
 // api schema.
 // TODO: Remaining types
@@ -0,0 +1,539 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package sm
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	base "github.com/Cray-HPE/hms-base/v2"
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+)
+
+////////////////////////////////////////////////////////////////////////////
+//
+// HW Inventory export
+//
+// Exporters represent hardware inventory in some other, standard schema so
+// that tools which understand it can consume HSM's aggregated view.  Each
+// is registered under a format name, and turns the inventory into a tree of
+// read-only resources keyed by their path below a root path chosen by the
+// caller.
+//
+////////////////////////////////////////////////////////////////////////////
+
+var ErrHWInvExportFmt = base.NewHMSError("sm", "No such HW Inventory export format")
+
+// Serializes HW inventory into a tree of resources.
+type HWInvExporter interface {
+	// Resources representing hwlocs, keyed by their path, each of which
+	// starts with root.  root itself must be one of them.
+	Export(hwlocs []*HWInvByLoc, root string) (map[string]interface{}, error)
+}
+
+// Export formats
+const (
+	HWInvExportRedfish = "Redfish"
+)
+
+var hwInvExporters = map[string]HWInvExporter{
+	HWInvExportRedfish: RedfishExporter{},
+}
+var hwInvExportersLock sync.RWMutex
+
+// Add an exporter under the given format name, replacing any existing one.
+func RegisterHWInvExporter(format string, e HWInvExporter) {
+	hwInvExportersLock.Lock()
+	defer hwInvExportersLock.Unlock()
+	hwInvExporters[format] = e
+}
+
+// Get the exporter for a format (case-insensitive), or ErrHWInvExportFmt.
+func GetHWInvExporter(format string) (HWInvExporter, error) {
+	hwInvExportersLock.RLock()
+	defer hwInvExportersLock.RUnlock()
+	for name, e := range hwInvExporters {
+		if strings.EqualFold(name, format) {
+			return e, nil
+		}
+	}
+	return nil, ErrHWInvExportFmt
+}
+
+// Names of the registered export formats, sorted.
+func GetHWInvExportFormats() []string {
+	hwInvExportersLock.RLock()
+	defer hwInvExportersLock.RUnlock()
+	formats := make([]string, 0, len(hwInvExporters))
+	for name := range hwInvExporters {
+		formats = append(formats, name)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+////////////////////////////////////////////////////////////////////////////
+//
+// Redfish export
+//
+// The inventory as a DMTF Redfish service: root is the ServiceRoot, nodes
+// are ComputerSystems with their Processors (including accelerators) and
+// Memory, enclosures from cabinets down to blades and switches are Chassis,
+// and BMCs are Managers.  Resources are named by xname, and linked by the
+// xname hierarchy, e.g. a node's Chassis is its nearest enclosing one in
+// the inventory.  Only what HW inventory knows is included, so there is no
+// state, and nothing can be changed.
+//
+////////////////////////////////////////////////////////////////////////////
+
+type RedfishExporter struct{}
+
+// A Redfish resource
+type rfResource map[string]interface{}
+
+// HMS types exported as Chassis, and the ChassisType to use if their FRU
+// doesn't say.
+var rfExportChassisTypes = map[xnametypes.HMSType]string{
+	xnametypes.Cabinet:       "Rack",
+	xnametypes.Chassis:       "Enclosure",
+	xnametypes.ComputeModule: "Blade",
+	xnametypes.RouterModule:  "Blade",
+	xnametypes.NodeEnclosure: "Enclosure",
+	xnametypes.HSNBoard:      "Module",
+	xnametypes.MgmtSwitch:    "RackMount",
+	xnametypes.MgmtHLSwitch:  "RackMount",
+	xnametypes.CDUMgmtSwitch: "RackMount",
+}
+
+func rfOdataID(path string) rfResource {
+	return rfResource{"@odata.id": path}
+}
+
+func rfOdataIDs(paths []string) []rfResource {
+	refs := make([]rfResource, 0, len(paths))
+	sort.Strings(paths)
+	for _, p := range paths {
+		refs = append(refs, rfOdataID(p))
+	}
+	return refs
+}
+
+func rfCollection(path, odataType, name string, members []string) rfResource {
+	return rfResource{
+		"@odata.id":           path,
+		"@odata.type":         "#" + odataType + "." + odataType,
+		"Name":                name,
+		"Members":             rfOdataIDs(members),
+		"Members@odata.count": len(members),
+	}
+}
+
+// Status of a location.  HW inventory only knows whether it is populated.
+func rfExportStatus(hwloc *HWInvByLoc) rfResource {
+	if hwloc.Status == "Empty" {
+		return rfResource{"State": "Absent"}
+	}
+	return rfResource{"State": "Enabled"}
+}
+
+// Set the properties that have values.
+func (r rfResource) setStrings(props map[string]string) {
+	for k, v := range props {
+		if v != "" {
+			r[k] = v
+		}
+	}
+}
+
+// Set the integer properties that have values.
+func (r rfResource) setInts(props map[string]json.Number) {
+	for k, v := range props {
+		if n, err := v.Int64(); err == nil {
+			r[k] = n
+		}
+	}
+}
+
+func (RedfishExporter) Export(hwlocs []*HWInvByLoc, root string) (map[string]interface{}, error) {
+	root = strings.TrimSuffix(root, "/")
+	res := make(map[string]interface{})
+
+	systemsPath := root + "/Systems"
+	chassisPath := root + "/Chassis"
+	managersPath := root + "/Managers"
+	sessionsPath := root + "/SessionService/Sessions"
+
+	byID := make(map[string]*HWInvByLoc, len(hwlocs))
+	for _, hwloc := range hwlocs {
+		byID[hwloc.ID] = hwloc
+	}
+	// Nearest ancestor of id that is exported as a Chassis
+	chassisOf := func(id string) string {
+		for p := xnametypes.GetHMSCompParent(id); p != "" && p != "s0"; p = xnametypes.GetHMSCompParent(p) {
+			if hwloc, ok := byID[p]; ok {
+				if _, ok := rfExportChassisTypes[xnametypes.GetHMSType(hwloc.ID)]; ok {
+					return p
+				}
+			}
+		}
+		return ""
+	}
+
+	systems := []string{}
+	chassis := []string{}
+	managers := []string{}
+	procs := make(map[string][]string)    // Processor paths by node
+	mems := make(map[string][]string)     // Memory paths by node
+	contains := make(map[string][]string) // Chassis paths by parent chassis
+	chSystems := make(map[string][]string)
+	chManagers := make(map[string][]string)
+	managed := make(map[string][]string) // System paths by manager
+
+	for _, hwloc := range hwlocs {
+		hmsType := xnametypes.GetHMSType(hwloc.ID)
+		var fru *HWInvByFRU
+		if hwloc.Status != "Empty" {
+			fru = hwloc.PopulatedFRU
+		}
+		switch {
+		case hmsType == xnametypes.Node:
+			path := systemsPath + "/" + hwloc.ID
+			systems = append(systems, path)
+			r := rfResource{
+				"@odata.id":   path,
+				"@odata.type": "#ComputerSystem.v1_5_0.ComputerSystem",
+				"Id":          hwloc.ID,
+				"Name":        hwloc.ID,
+				"Status":      rfExportStatus(hwloc),
+				"Processors":  rfOdataID(path + "/Processors"),
+				"Memory":      rfOdataID(path + "/Memory"),
+			}
+			links := rfResource{}
+			if ch := chassisOf(hwloc.ID); ch != "" {
+				links["Chassis"] = rfOdataIDs([]string{chassisPath + "/" + ch})
+				chSystems[ch] = append(chSystems[ch], path)
+			}
+			if bmc := xnametypes.GetHMSCompParent(hwloc.ID); byID[bmc] != nil {
+				links["ManagedBy"] = rfOdataIDs([]string{managersPath + "/" + bmc})
+				managed[bmc] = append(managed[bmc], path)
+			}
+			r["Links"] = links
+			if li := hwloc.HMSNodeLocationInfo; li != nil {
+				r.setStrings(map[string]string{
+					"Name":        li.Name,
+					"Description": li.Description,
+					"HostName":    li.Hostname,
+				})
+				if n, err := li.ProcessorSummary.Count.Int64(); err == nil {
+					summary := rfResource{"Count": n}
+					summary.setStrings(map[string]string{
+						"Model": li.ProcessorSummary.Model,
+					})
+					r["ProcessorSummary"] = summary
+				}
+				if gib, err := li.MemorySummary.TotalSystemMemoryGiB.Float64(); err == nil {
+					r["MemorySummary"] = rfResource{"TotalSystemMemoryGiB": gib}
+				}
+			}
+			if fru != nil && fru.HMSNodeFRUInfo != nil {
+				fi := fru.HMSNodeFRUInfo
+				r.setStrings(map[string]string{
+					"AssetTag":     fi.AssetTag,
+					"BiosVersion":  fi.BiosVersion,
+					"Model":        fi.Model,
+					"Manufacturer": fi.Manufacturer,
+					"PartNumber":   fi.PartNumber,
+					"SerialNumber": fi.SerialNumber,
+					"SKU":          fi.SKU,
+					"SystemType":   fi.SystemType,
+					"UUID":         fi.UUID,
+				})
+			}
+			res[path] = r
+
+		case hmsType == xnametypes.Processor || hmsType == xnametypes.NodeAccel:
+			node := xnametypes.GetHMSCompParent(hwloc.ID)
+			path := systemsPath + "/" + node + "/Processors/" + hwloc.ID
+			procs[node] = append(procs[node], path)
+			r := rfResource{
+				"@odata.id":   path,
+				"@odata.type": "#Processor.v1_3_0.Processor",
+				"Id":          hwloc.ID,
+				"Name":        hwloc.ID,
+				"Status":      rfExportStatus(hwloc),
+			}
+			li := hwloc.HMSProcessorLocationInfo
+			if li == nil {
+				li = hwloc.HMSNodeAccelLocationInfo
+			}
+			if li != nil {
+				r.setStrings(map[string]string{
+					"Name":        li.Name,
+					"Description": li.Description,
+					"Socket":      li.Socket,
+				})
+			}
+			var fi *rf.ProcessorFRUInfoRF
+			if fru != nil {
+				fi = fru.HMSProcessorFRUInfo
+				if fi == nil {
+					fi = fru.HMSNodeAccelFRUInfo
+				}
+			}
+			if fi != nil {
+				r.setStrings(map[string]string{
+					"InstructionSet":        fi.InstructionSet,
+					"Manufacturer":          fi.Manufacturer,
+					"Model":                 fi.Model,
+					"SerialNumber":          fi.SerialNumber,
+					"PartNumber":            fi.PartNumber,
+					"ProcessorArchitecture": fi.ProcessorArchitecture,
+					"ProcessorType":         fi.ProcessorType,
+				})
+				r.setInts(map[string]json.Number{
+					"MaxSpeedMHz":  fi.MaxSpeedMHz,
+					"TotalCores":   fi.TotalCores,
+					"TotalThreads": fi.TotalThreads,
+				})
+			}
+			res[path] = r
+
+		case hmsType == xnametypes.Memory:
+			node := xnametypes.GetHMSCompParent(hwloc.ID)
+			path := systemsPath + "/" + node + "/Memory/" + hwloc.ID
+			mems[node] = append(mems[node], path)
+			r := rfResource{
+				"@odata.id":   path,
+				"@odata.type": "#Memory.v1_7_0.Memory",
+				"Id":          hwloc.ID,
+				"Name":        hwloc.ID,
+				"Status":      rfExportStatus(hwloc),
+			}
+			if li := hwloc.HMSMemoryLocationInfo; li != nil {
+				r.setStrings(map[string]string{
+					"Name":        li.Name,
+					"Description": li.Description,
+				})
+			}
+			if fru != nil && fru.HMSMemoryFRUInfo != nil {
+				fi := fru.HMSMemoryFRUInfo
+				r.setStrings(map[string]string{
+					"BaseModuleType":   fi.BaseModuleType,
+					"ErrorCorrection":  fi.ErrorCorrection,
+					"Manufacturer":     fi.Manufacturer,
+					"MemoryType":       fi.MemoryType,
+					"MemoryDeviceType": fi.MemoryDeviceType,
+					"PartNumber":       fi.PartNumber,
+					"SerialNumber":     fi.SerialNumber,
+				})
+				r.setInts(map[string]json.Number{
+					"CapacityMiB":       fi.CapacityMiB,
+					"OperatingSpeedMhz": fi.OperatingSpeedMhz,
+					"RankCount":         fi.RankCount,
+				})
+			}
+			res[path] = r
+
+		case hmsType == xnametypes.NodeBMC || hmsType == xnametypes.RouterBMC:
+			path := managersPath + "/" + hwloc.ID
+			managers = append(managers, path)
+			r := rfResource{
+				"@odata.id":   path,
+				"@odata.type": "#Manager.v1_5_0.Manager",
+				"Id":          hwloc.ID,
+				"Name":        hwloc.ID,
+				"ManagerType": "BMC",
+				"Status":      rfExportStatus(hwloc),
+			}
+			li := hwloc.HMSNodeBMCLocationInfo
+			if li == nil {
+				li = hwloc.HMSRouterBMCLocationInfo
+			}
+			if li != nil {
+				r.setStrings(map[string]string{
+					"Name":            li.Name,
+					"Description":     li.Description,
+					"FirmwareVersion": li.FirmwareVersion,
+				})
+			}
+			var fi *rf.ManagerFRUInfoRF
+			if fru != nil {
+				fi = fru.HMSNodeBMCFRUInfo
+				if fi == nil {
+					fi = fru.HMSRouterBMCFRUInfo
+				}
+			}
+			if fi != nil {
+				r.setStrings(map[string]string{
+					"ManagerType":  fi.ManagerType,
+					"Model":        fi.Model,
+					"Manufacturer": fi.Manufacturer,
+					"PartNumber":   fi.PartNumber,
+					"SerialNumber": fi.SerialNumber,
+				})
+			}
+			if ch := chassisOf(hwloc.ID); ch != "" {
+				chManagers[ch] = append(chManagers[ch], path)
+			}
+			res[path] = r
+
+		case rfExportChassisTypes[hmsType] != "":
+			path := chassisPath + "/" + hwloc.ID
+			chassis = append(chassis, path)
+			r := rfResource{
+				"@odata.id":   path,
+				"@odata.type": "#Chassis.v1_9_0.Chassis",
+				"Id":          hwloc.ID,
+				"Name":        hwloc.ID,
+				"ChassisType": rfExportChassisTypes[hmsType],
+				"Status":      rfExportStatus(hwloc),
+			}
+			if li := hwloc.chassisLocationInfo(); li != nil {
+				r.setStrings(map[string]string{
+					"Name":        li.Name,
+					"Description": li.Description,
+				})
+			}
+			if fi := fru.chassisFRUInfo(); fi != nil {
+				r.setStrings(map[string]string{
+					"AssetTag":     fi.AssetTag,
+					"ChassisType":  fi.ChassisType,
+					"Model":        fi.Model,
+					"Manufacturer": fi.Manufacturer,
+					"PartNumber":   fi.PartNumber,
+					"SerialNumber": fi.SerialNumber,
+					"SKU":          fi.SKU,
+				})
+			}
+			if ch := chassisOf(hwloc.ID); ch != "" {
+				contains[ch] = append(contains[ch], path)
+			}
+			res[path] = r
+		}
+	}
+
+	// Links that need everything else to be known first
+	for _, path := range chassis {
+		r := res[path].(rfResource)
+		id := r["Id"].(string)
+		links := rfResource{}
+		if ch := chassisOf(id); ch != "" {
+			links["ContainedBy"] = rfOdataID(chassisPath + "/" + ch)
+		}
+		if c := contains[id]; len(c) > 0 {
+			links["Contains"] = rfOdataIDs(c)
+		}
+		if c := chSystems[id]; len(c) > 0 {
+			links["ComputerSystems"] = rfOdataIDs(c)
+		}
+		if c := chManagers[id]; len(c) > 0 {
+			links["ManagedBy"] = rfOdataIDs(c)
+		}
+		r["Links"] = links
+	}
+	for _, path := range managers {
+		r := res[path].(rfResource)
+		links := rfResource{}
+		if c := managed[r["Id"].(string)]; len(c) > 0 {
+			links["ManagerForServers"] = rfOdataIDs(c)
+		}
+		r["Links"] = links
+	}
+	for _, path := range systems {
+		id := res[path].(rfResource)["Id"].(string)
+		res[path+"/Processors"] = rfCollection(path+"/Processors",
+			"ProcessorCollection", "Processors Collection", procs[id])
+		res[path+"/Memory"] = rfCollection(path+"/Memory",
+			"MemoryCollection", "Memory Collection", mems[id])
+	}
+
+	res[systemsPath] = rfCollection(systemsPath,
+		"ComputerSystemCollection", "Computer System Collection", systems)
+	res[chassisPath] = rfCollection(chassisPath,
+		"ChassisCollection", "Chassis Collection", chassis)
+	res[managersPath] = rfCollection(managersPath,
+		"ManagerCollection", "Manager Collection", managers)
+	// Required by the ServiceRoot schema.  There are never any sessions.
+	res[sessionsPath] = rfCollection(sessionsPath,
+		"SessionCollection", "Session Collection", nil)
+	res[root] = rfResource{
+		"@odata.id":      root,
+		"@odata.type":    "#ServiceRoot.v1_5_0.ServiceRoot",
+		"Id":             "RootService",
+		"Name":           "HSM Hardware Inventory",
+		"RedfishVersion": "1.6.0",
+		"Systems":        rfOdataID(systemsPath),
+		"Chassis":        rfOdataID(chassisPath),
+		"Managers":       rfOdataID(managersPath),
+		"Links": rfResource{
+			"Sessions": rfOdataID(sessionsPath),
+		},
+	}
+	return res, nil
+}
+
+// The location info of an entry exported as a Chassis
+func (hw *HWInvByLoc) chassisLocationInfo() *rf.ChassisLocationInfoRF {
+	for _, li := range []*rf.ChassisLocationInfoRF{
+		hw.HMSCabinetLocationInfo,
+		hw.HMSChassisLocationInfo,
+		hw.HMSComputeModuleLocationInfo,
+		hw.HMSRouterModuleLocationInfo,
+		hw.HMSNodeEnclosureLocationInfo,
+		hw.HMSHSNBoardLocationInfo,
+		hw.HMSMgmtSwitchLocationInfo,
+		hw.HMSMgmtHLSwitchLocationInfo,
+		hw.HMSCDUMgmtSwitchLocationInfo,
+	} {
+		if li != nil {
+			return li
+		}
+	}
+	return nil
+}
+
+// The FRU info of an entry exported as a Chassis.  hf may be nil.
+func (hf *HWInvByFRU) chassisFRUInfo() *rf.ChassisFRUInfoRF {
+	if hf == nil {
+		return nil
+	}
+	for _, fi := range []*rf.ChassisFRUInfoRF{
+		hf.HMSCabinetFRUInfo,
+		hf.HMSChassisFRUInfo,
+		hf.HMSComputeModuleFRUInfo,
+		hf.HMSRouterModuleFRUInfo,
+		hf.HMSNodeEnclosureFRUInfo,
+		hf.HMSHSNBoardFRUInfo,
+		hf.HMSMgmtSwitchFRUInfo,
+		hf.HMSMgmtHLSwitchFRUInfo,
+		hf.HMSCDUMgmtSwitchFRUInfo,
+	} {
+		if fi != nil {
+			return fi
+		}
+	}
+	return nil
+}
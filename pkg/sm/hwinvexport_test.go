@@ -0,0 +1,223 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package sm_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func testExportHWLocs() []*sm.HWInvByLoc {
+	return []*sm.HWInvByLoc{{
+		ID:     "x1000",
+		Type:   "Cabinet",
+		Status: "Populated",
+		HMSCabinetLocationInfo: &rf.ChassisLocationInfoRF{
+			Id: "Cabinet", Name: "Cabinet"},
+	}, {
+		ID:     "x1000c0",
+		Type:   "Chassis",
+		Status: "Populated",
+	}, {
+		ID:     "x1000c0s0",
+		Type:   "ComputeModule",
+		Status: "Populated",
+		PopulatedFRU: &sm.HWInvByFRU{
+			FRUID: "Blade.1234",
+			HMSComputeModuleFRUInfo: &rf.ChassisFRUInfoRF{
+				ChassisType:  "Blade",
+				Manufacturer: "HPE",
+				SerialNumber: "1234",
+			},
+		},
+	}, {
+		ID:     "x1000c0s1",
+		Type:   "ComputeModule",
+		Status: "Empty",
+	}, {
+		ID:     "x1000c0s0b0",
+		Type:   "NodeBMC",
+		Status: "Populated",
+		HMSNodeBMCLocationInfo: &rf.ManagerLocationInfoRF{
+			Id: "BMC", Name: "BMC", FirmwareVersion: "1.2.3"},
+	}, {
+		ID:     "x1000c0s0b0n0",
+		Type:   "Node",
+		Status: "Populated",
+		HMSNodeLocationInfo: &rf.SystemLocationInfoRF{
+			Id: "Node0", Name: "Node0",
+			ProcessorSummary: rf.ComputerSystemProcessorSummary{
+				Count: "2", Model: "EPYC"},
+			MemorySummary: rf.ComputerSystemMemorySummary{
+				TotalSystemMemoryGiB: "512"},
+		},
+		PopulatedFRU: &sm.HWInvByFRU{
+			FRUID: "Node.5678",
+			HMSNodeFRUInfo: &rf.SystemFRUInfoRF{
+				Manufacturer: "HPE",
+				SerialNumber: "5678",
+				SystemType:   "Physical",
+			},
+		},
+	}, {
+		ID:     "x1000c0s0b0n0p0",
+		Type:   "Processor",
+		Status: "Populated",
+		HMSProcessorLocationInfo: &rf.ProcessorLocationInfoRF{
+			Id: "CPU0", Name: "CPU0", Socket: "P0"},
+		PopulatedFRU: &sm.HWInvByFRU{
+			FRUID: "Proc.1",
+			HMSProcessorFRUInfo: &rf.ProcessorFRUInfoRF{
+				Model: "EPYC", TotalCores: "64", MaxSpeedMHz: ""},
+		},
+	}, {
+		ID:     "x1000c0s0b0n0d0",
+		Type:   "Memory",
+		Status: "Populated",
+		PopulatedFRU: &sm.HWInvByFRU{
+			FRUID: "Mem.1",
+			HMSMemoryFRUInfo: &rf.MemoryFRUInfoRF{
+				CapacityMiB: "32768", SerialNumber: "M1"},
+		},
+	}}
+}
+
+func TestRedfishExport(t *testing.T) {
+	exporter, err := sm.GetHWInvExporter("redfish")
+	if err != nil {
+		t.Fatalf("GetHWInvExporter: %s", err)
+	}
+	if _, err := sm.GetHWInvExporter("Swordfish"); err != sm.ErrHWInvExportFmt {
+		t.Errorf("Expected ErrHWInvExportFmt, got %v", err)
+	}
+	root := "/hsm/v2/Inventory/Export/Redfish"
+	res, err := exporter.Export(testExportHWLocs(), root+"/")
+	if err != nil {
+		t.Fatalf("Export: %s", err)
+	}
+
+	// Everything should be a valid Redfish resource at its @odata.id
+	get := func(path string) map[string]interface{} {
+		data, err := json.Marshal(res[path])
+		if err != nil || res[path] == nil {
+			t.Fatalf("Bad or missing resource %s: %v", path, err)
+		}
+		var r map[string]interface{}
+		json.Unmarshal(data, &r)
+		return r
+	}
+	for path := range res {
+		r := get(path)
+		if r["@odata.id"] != path {
+			t.Errorf("%s has @odata.id %v", path, r["@odata.id"])
+		}
+		data, _ := json.Marshal(res[path])
+		if errs := rf.ValidateResource(data); len(errs) != 0 {
+			t.Errorf("%s: Schema violations: %+v", path, errs)
+		}
+	}
+
+	members := func(path string) []string {
+		ids := []string{}
+		for _, m := range get(path)["Members"].([]interface{}) {
+			ids = append(ids, m.(map[string]interface{})["@odata.id"].(string))
+		}
+		return ids
+	}
+	links := func(path, link string) interface{} {
+		return get(path)["Links"].(map[string]interface{})[link]
+	}
+	ref := func(paths ...string) interface{} {
+		refs := []interface{}{}
+		for _, p := range paths {
+			refs = append(refs, map[string]interface{}{"@odata.id": p})
+		}
+		return refs
+	}
+
+	tests := []struct {
+		got, exp interface{}
+	}{
+		{members(root + "/Systems"), []string{root + "/Systems/x1000c0s0b0n0"}},
+		{members(root + "/Managers"), []string{root + "/Managers/x1000c0s0b0"}},
+		{members(root + "/Chassis"), []string{
+			root + "/Chassis/x1000", root + "/Chassis/x1000c0",
+			root + "/Chassis/x1000c0s0", root + "/Chassis/x1000c0s1"}},
+		{members(root + "/Systems/x1000c0s0b0n0/Processors"),
+			[]string{root + "/Systems/x1000c0s0b0n0/Processors/x1000c0s0b0n0p0"}},
+		{members(root + "/Systems/x1000c0s0b0n0/Memory"),
+			[]string{root + "/Systems/x1000c0s0b0n0/Memory/x1000c0s0b0n0d0"}},
+		{links(root+"/Systems/x1000c0s0b0n0", "Chassis"),
+			ref(root + "/Chassis/x1000c0s0")},
+		{links(root+"/Systems/x1000c0s0b0n0", "ManagedBy"),
+			ref(root + "/Managers/x1000c0s0b0")},
+		{links(root+"/Chassis/x1000c0", "ContainedBy"),
+			map[string]interface{}{"@odata.id": root + "/Chassis/x1000"}},
+		{links(root+"/Chassis/x1000c0", "Contains"),
+			ref(root+"/Chassis/x1000c0s0", root+"/Chassis/x1000c0s1")},
+		{links(root+"/Chassis/x1000c0s0", "ComputerSystems"),
+			ref(root + "/Systems/x1000c0s0b0n0")},
+		{links(root+"/Managers/x1000c0s0b0", "ManagerForServers"),
+			ref(root + "/Systems/x1000c0s0b0n0")},
+		{get(root + "/Chassis/x1000c0s1")["Status"],
+			map[string]interface{}{"State": "Absent"}},
+		{get(root + "/Chassis/x1000")["ChassisType"], "Rack"},
+		{get(root + "/Chassis/x1000c0s0")["SerialNumber"], "1234"},
+		{get(root + "/Managers/x1000c0s0b0")["FirmwareVersion"], "1.2.3"},
+		{get(root + "/Systems/x1000c0s0b0n0")["ProcessorSummary"],
+			map[string]interface{}{"Count": 2.0, "Model": "EPYC"}},
+		{get(root + "/Systems/x1000c0s0b0n0/Processors/x1000c0s0b0n0p0")["TotalCores"], 64.0},
+		{get(root + "/Systems/x1000c0s0b0n0/Processors/x1000c0s0b0n0p0")["MaxSpeedMHz"], nil},
+		{get(root + "/Systems/x1000c0s0b0n0/Memory/x1000c0s0b0n0d0")["CapacityMiB"], 32768.0},
+	}
+	for i, test := range tests {
+		if !reflect.DeepEqual(test.got, test.exp) {
+			t.Errorf("Testcase %d: Expected %v, got %v", i, test.exp, test.got)
+		}
+	}
+}
+
+type testExporter struct{}
+
+func (testExporter) Export(hwlocs []*sm.HWInvByLoc, root string) (map[string]interface{}, error) {
+	return map[string]interface{}{root: len(hwlocs)}, nil
+}
+
+func TestRegisterHWInvExporter(t *testing.T) {
+	sm.RegisterHWInvExporter("Count", testExporter{})
+	if formats := sm.GetHWInvExportFormats(); !reflect.DeepEqual(formats,
+		[]string{"Count", sm.HWInvExportRedfish}) {
+		t.Errorf("Unexpected formats %v", formats)
+	}
+	e, err := sm.GetHWInvExporter("count")
+	if err != nil {
+		t.Fatalf("GetHWInvExporter: %s", err)
+	}
+	if res, _ := e.Export(testExportHWLocs(), "/"); res["/"] != 8 {
+		t.Errorf("Wrong exporter: %v", res)
+	}
+}
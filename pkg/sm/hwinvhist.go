@@ -110,3 +110,103 @@ func VerifyNormalizeHWInvHistEventType(eventType string) string {
 		return value
 	}
 }
+
+// Create 'Detected' HWInvHist entries for each populated location in hwlocs.
+// lastHists should hold the most recent event for those locations; no entry
+// is created for a location whose last event was already a 'Detected' event
+// for the same FRU.
+func NewHWInvHistsDetected(hwlocs []*HWInvByLoc, lastHists []*HWInvHist) []*HWInvHist {
+	hwhists := make([]*HWInvHist, 0, 1)
+	lhsMap := make(map[string]*HWInvHist, len(lastHists))
+	for _, lh := range lastHists {
+		lhsMap[lh.ID] = lh
+	}
+	for _, hwloc := range hwlocs {
+		// Skip hwlocs that have no FRU
+		if hwloc == nil || hwloc.PopulatedFRU == nil {
+			continue
+		}
+		if lastHist, ok := lhsMap[hwloc.ID]; !ok ||
+			lastHist.FruId != hwloc.PopulatedFRU.FRUID ||
+			lastHist.EventType != HWInvHistEventTypeDetected {
+			hwhists = append(hwhists, &HWInvHist{
+				ID:        hwloc.ID,
+				FruId:     hwloc.PopulatedFRU.FRUID,
+				EventType: HWInvHistEventTypeDetected,
+			})
+		}
+	}
+	return hwhists
+}
+
+// A stint of a FRU at one location: when it was first and last seen there.
+// A FRU that moves gets a new one at each location it is seen at, so its
+// sightings ordered by FirstSeen give its movement over time.
+type HWInvSighting struct {
+	ID        string `json:"ID"`        // xname location the FRU was seen at
+	FruId     string `json:"FRUID"`     // FRU ID of the FRU
+	FirstSeen string `json:"FirstSeen"` // Timestamp it was first seen there
+	LastSeen  string `json:"LastSeen"`  // Timestamp it was last seen there
+}
+
+type HWInvSightingArray struct {
+	ID        string           `json:"ID"` // FruId
+	Sightings []*HWInvSighting `json:"Sightings"`
+}
+
+type HWInvSightingResp struct {
+	FRUs []HWInvSightingArray `json:"FRUs"`
+}
+
+// Create formatted HWInvSightingResp from HWInvSighting entries, grouped
+// by FRU ID in the order each FRU is first found.
+func NewHWInvSightingResp(sightings []*HWInvSighting) *HWInvSightingResp {
+	resp := &HWInvSightingResp{FRUs: []HWInvSightingArray{}}
+	idxMap := make(map[string]int)
+	for _, sighting := range sightings {
+		if idx, ok := idxMap[sighting.FruId]; ok {
+			resp.FRUs[idx].Sightings = append(resp.FRUs[idx].Sightings, sighting)
+			continue
+		}
+		idxMap[sighting.FruId] = len(resp.FRUs)
+		resp.FRUs = append(resp.FRUs, HWInvSightingArray{
+			ID:        sighting.FruId,
+			Sightings: []*HWInvSighting{sighting},
+		})
+	}
+	return resp
+}
+
+// Split the FRUs populating hwlocs into those still at the location of
+// their latest sighting and those seen somewhere new (or for the first
+// time).  lastSightings should hold the most recent sighting of those FRUs.
+// A FRU that appears more than once is taken at its first location.
+func NewHWInvSightings(hwlocs []*HWInvByLoc, lastSightings []*HWInvSighting) (same, moved []*HWInvSighting) {
+	same = make([]*HWInvSighting, 0, 1)
+	moved = make([]*HWInvSighting, 0, 1)
+	lsMap := make(map[string]*HWInvSighting, len(lastSightings))
+	for _, ls := range lastSightings {
+		lsMap[ls.FruId] = ls
+	}
+	seen := make(map[string]bool)
+	for _, hwloc := range hwlocs {
+		// Skip hwlocs that have no FRU
+		if hwloc == nil || hwloc.PopulatedFRU == nil ||
+			hwloc.PopulatedFRU.FRUID == "" {
+			continue
+		}
+		fruID := hwloc.PopulatedFRU.FRUID
+		if seen[fruID] {
+			continue
+		}
+		seen[fruID] = true
+		sighting := &HWInvSighting{ID: hwloc.ID, FruId: fruID}
+		if ls, ok := lsMap[fruID]; ok && ls.ID == hwloc.ID {
+			sighting.FirstSeen = ls.FirstSeen
+			same = append(same, sighting)
+		} else {
+			moved = append(moved, sighting)
+		}
+	}
+	return same, moved
+}
@@ -157,3 +157,88 @@ func TestVerifyNormalizeHWInvHistEventType(t *testing.T) {
 		}
 	}
 }
+
+func TestNewHWInvHistsDetected(t *testing.T) {
+	hwlocs := []*HWInvByLoc{
+		{ID: "x0c0s0b0n0", PopulatedFRU: &HWInvByFRU{FRUID: "fru0"}},
+		{ID: "x0c0s0b0n0p0", PopulatedFRU: &HWInvByFRU{FRUID: "fru1"}},
+		{ID: "x0c0s0b0n0p1", PopulatedFRU: &HWInvByFRU{FRUID: "fru2"}},
+		{ID: "x0c0s0b0n0p2", PopulatedFRU: &HWInvByFRU{FRUID: "fru3"}},
+		{ID: "x0c0s0b0n0d0"},
+		nil,
+	}
+	tests := []struct {
+		lastHists   []*HWInvHist
+		expectedOut []*HWInvHist
+	}{{
+		lastHists: []*HWInvHist{},
+		expectedOut: []*HWInvHist{
+			{ID: "x0c0s0b0n0", FruId: "fru0", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p0", FruId: "fru1", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p1", FruId: "fru2", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p2", FruId: "fru3", EventType: HWInvHistEventTypeDetected},
+		},
+	}, {
+		lastHists: []*HWInvHist{
+			{ID: "x0c0s0b0n0", FruId: "fru0", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p0", FruId: "fruX", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p1", FruId: "fru2", EventType: HWInvHistEventTypeRemoved},
+		},
+		expectedOut: []*HWInvHist{
+			{ID: "x0c0s0b0n0p0", FruId: "fru1", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p1", FruId: "fru2", EventType: HWInvHistEventTypeDetected},
+			{ID: "x0c0s0b0n0p2", FruId: "fru3", EventType: HWInvHistEventTypeDetected},
+		},
+	}}
+	for i, test := range tests {
+		out := NewHWInvHistsDetected(hwlocs, test.lastHists)
+		if !reflect.DeepEqual(test.expectedOut, out) {
+			t.Errorf("Test %v Failed: Expected '%v'; Received '%v'", i, test.expectedOut, out)
+		}
+	}
+}
+
+func TestNewHWInvSightings(t *testing.T) {
+	hwlocs := []*HWInvByLoc{
+		{ID: "x0c0s0b0n0", PopulatedFRU: &HWInvByFRU{FRUID: "fru0"}},
+		{ID: "x0c0s0b0n0d0", PopulatedFRU: &HWInvByFRU{FRUID: "fru1"}},
+		{ID: "x0c0s0b0n0d1", PopulatedFRU: &HWInvByFRU{FRUID: "fru2"}},
+		{ID: "x0c0s0b0n0d2", PopulatedFRU: &HWInvByFRU{FRUID: "fru2"}},
+		{ID: "x0c0s0b0n0d3"},
+		nil,
+	}
+	lastSightings := []*HWInvSighting{
+		{ID: "x0c0s0b0n0", FruId: "fru0", FirstSeen: "2026-01-01T00:00:00Z"},
+		{ID: "x0c0s0b0n0d1", FruId: "fru1", FirstSeen: "2026-01-02T00:00:00Z"},
+	}
+	expSame := []*HWInvSighting{
+		{ID: "x0c0s0b0n0", FruId: "fru0", FirstSeen: "2026-01-01T00:00:00Z"},
+	}
+	expMoved := []*HWInvSighting{
+		{ID: "x0c0s0b0n0d0", FruId: "fru1"},
+		{ID: "x0c0s0b0n0d1", FruId: "fru2"},
+	}
+	same, moved := NewHWInvSightings(hwlocs, lastSightings)
+	if !reflect.DeepEqual(expSame, same) {
+		t.Errorf("Expected same '%v'; Received '%v'", expSame, same)
+	}
+	if !reflect.DeepEqual(expMoved, moved) {
+		t.Errorf("Expected moved '%v'; Received '%v'", expMoved, moved)
+	}
+}
+
+func TestNewHWInvSightingResp(t *testing.T) {
+	s1 := &HWInvSighting{ID: "x0c0s0b0n0d0", FruId: "fru1", FirstSeen: "t1", LastSeen: "t2"}
+	s2 := &HWInvSighting{ID: "x0c0s1b0n0d0", FruId: "fru1", FirstSeen: "t3", LastSeen: "t4"}
+	s3 := &HWInvSighting{ID: "x0c0s0b0n0d1", FruId: "fru2", FirstSeen: "t1", LastSeen: "t4"}
+	exp := &HWInvSightingResp{FRUs: []HWInvSightingArray{
+		{ID: "fru1", Sightings: []*HWInvSighting{s1, s2}},
+		{ID: "fru2", Sightings: []*HWInvSighting{s3}},
+	}}
+	if out := NewHWInvSightingResp([]*HWInvSighting{s1, s3, s2}); !reflect.DeepEqual(exp, out) {
+		t.Errorf("Expected '%v'; Received '%v'", exp, out)
+	}
+	if out := NewHWInvSightingResp(nil); out.FRUs == nil || len(out.FRUs) != 0 {
+		t.Errorf("Expected empty FRUs; Received '%v'", out)
+	}
+}
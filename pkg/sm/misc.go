@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
 )
 
@@ -74,12 +75,131 @@ type DiscoveryStatus struct {
 	Details    *json.RawMessage `json:"Details,omitempty"`
 }
 
+// Valid values for the DiscoveryChange Change field below.
+const (
+	DiscChangeAdded   = "Added"
+	DiscChangeRemoved = "Removed"
+	DiscChangeMoved   = "Moved"
+	DiscChangeChanged = "Changed"
+)
+
+// Valid values for the DiscoveryChange Kind field below.
+const (
+	DiscChangeKindComponent = "Component"
+	DiscChangeKindFRU       = "FRU"
+)
+
+// A difference between what a discovery found through a RedfishEndpoint
+// and what was stored for it beforehand.  ID is the xname of the
+// ComponentEndpoint or HWInventory location.  A FRU that moved is reported
+// at its new location, with its old one in From.
+type DiscoveryChange struct {
+	RedfishEndpointID string   `json:"RedfishEndpointID"`
+	Kind              string   `json:"Kind"`
+	ID                string   `json:"ID"`
+	Change            string   `json:"Change"`
+	FRUID             string   `json:"FRUID,omitempty"`
+	From              string   `json:"From,omitempty"`
+	Fields            []string `json:"Fields,omitempty"`
+}
+
+// DiscoveryStatus Details once a discovery has completed.
+type DiscoveryDetails struct {
+	Changes []DiscoveryChange `json:"Changes"`
+}
+
 // POST object to kick of discovery
 type DiscoverIn struct {
 	XNames []string `json:"xnames"`
 	Force  bool     `json:"force"`
 }
 
+// Name of the DiscoverySchedule used for endpoints with no other schedule.
+const DiscoveryScheduleDefault = "default"
+
+// A time of day, in UTC, during which scheduled rediscoveries may start.
+// Start and End are HH:MM, and an End before Start is the next day.  Days
+// are Sun, Mon, ... Sat, and if empty the window is every day.
+type DiscoveryWindow struct {
+	Days  []string `json:"Days,omitempty"`
+	Start string   `json:"Start"`
+	End   string   `json:"End"`
+}
+
+// A rediscovery schedule, for the RedfishEndpoint or endpoint Type (e.g.
+// NodeBMC) given by Name, or for all others if Name is "default".  An
+// endpoint is rediscovered IntervalSeconds after its last discovery attempt,
+// plus a fixed offset of up to JitterSeconds for each endpoint so they
+// don't all go at once, but only starting within one of the Windows, if
+// any.  An IntervalSeconds of 0 means never.
+type DiscoverySchedule struct {
+	Name            string            `json:"Name"`
+	IntervalSeconds int               `json:"IntervalSeconds"`
+	JitterSeconds   int               `json:"JitterSeconds,omitempty"`
+	Windows         []DiscoveryWindow `json:"Windows,omitempty"`
+}
+
+type DiscoveryScheduleArray struct {
+	Schedules []*DiscoverySchedule `json:"Schedules"`
+}
+
+// Days of the week for DiscoveryWindow, by time.Weekday.
+var DiscoveryWindowDays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// Parse a DiscoveryWindow HH:MM time, returning minutes after midnight.
+func ParseDiscoveryWindowTime(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, fmt.Errorf("bad time '%s', must be HH:MM", hhmm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Check the schedule's values, other than Name, are valid.
+func (ds *DiscoverySchedule) Verify() error {
+	if ds.IntervalSeconds < 0 {
+		return fmt.Errorf("IntervalSeconds must be 0 or more")
+	}
+	if ds.JitterSeconds < 0 || ds.JitterSeconds > ds.IntervalSeconds {
+		return fmt.Errorf("JitterSeconds must be 0 up to IntervalSeconds")
+	}
+	for _, w := range ds.Windows {
+		if _, err := ParseDiscoveryWindowTime(w.Start); err != nil {
+			return fmt.Errorf("window Start: %s", err)
+		}
+		if _, err := ParseDiscoveryWindowTime(w.End); err != nil {
+			return fmt.Errorf("window End: %s", err)
+		}
+		for _, day := range w.Days {
+			ok := false
+			for _, d := range DiscoveryWindowDays {
+				if day == d {
+					ok = true
+				}
+			}
+			if !ok {
+				return fmt.Errorf("bad window day '%s', must be one of %v",
+					day, DiscoveryWindowDays)
+			}
+		}
+	}
+	return nil
+}
+
+// When a RedfishEndpoint is next due to be rediscovered.  Next is empty if
+// it has no schedule, or its interval is 0.
+type DiscoveryPlanEntry struct {
+	RedfishEndpointID string `json:"RedfishEndpointID"`
+	Schedule          string `json:"Schedule,omitempty"`
+	LastAttempt       string `json:"LastDiscoveryAttempt,omitempty"`
+	Next              string `json:"NextDiscovery,omitempty"`
+	Owner             string `json:"Owner,omitempty"`
+}
+
+type DiscoveryPlan struct {
+	Endpoints []*DiscoveryPlanEntry `json:"Endpoints"`
+}
+
 ////////////////////////////////////////////////////////////////////////////
 //
 // Job Sync
@@ -163,3 +283,25 @@ func NewStateRFPollJob(xname string, delay, poll, lifetime, keepAlive int) (*Job
 
 	return job, nil
 }
+
+// Kinds of Tombstone
+const (
+	TombstoneComponent       = "Component"
+	TombstoneRedfishEndpoint = "RedfishEndpoint"
+)
+
+// A Component or RedfishEndpoint that was deleted, kept so it can be
+// restored until it is purged.  Data is its database row as it was, minus
+// any password.  DeletedBy is who deleted it, if known, and Deleted is
+// when, in RFC3339 format.
+type Tombstone struct {
+	Kind      string          `json:"Kind"`
+	ID        string          `json:"ID"`
+	DeletedBy string          `json:"DeletedBy"`
+	Deleted   string          `json:"Deleted"`
+	Data      json.RawMessage `json:"Data"`
+}
+
+type TombstoneArray struct {
+	Tombstones []*Tombstone `json:"Tombstones"`
+}
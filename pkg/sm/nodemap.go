@@ -25,6 +25,7 @@ package sm
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	base "github.com/Cray-HPE/hms-base/v2"
 	"github.com/Cray-HPE/hms-xname/xnametypes"
@@ -84,3 +85,141 @@ func NewNodeMap(id, role, subRole string, nid int, nodeInfo *json.RawMessage) (*
 	}
 	return m, nil
 }
+
+// Results of importing a NodeMap.  In a dry run they are what would have
+// happened.
+const (
+	NodeMapImportCreated   = "Created"
+	NodeMapImportUpdated   = "Updated"
+	NodeMapImportUnchanged = "Unchanged"
+	NodeMapImportInvalid   = "Invalid"  // Bad xname, NID, Role or SubRole
+	NodeMapImportConflict  = "Conflict" // NID or xname is already taken
+	NodeMapImportFailed    = "Failed"   // Could not be stored
+)
+
+// The result of importing one row of a NodeMap import.  Row is the line it
+// is on, with the header on line 1.
+type NodeMapImportRow struct {
+	Row     int    `json:"Row"`
+	ID      string `json:"ID,omitempty"`
+	NID     int    `json:"NID,omitempty"`
+	Role    string `json:"Role,omitempty"`
+	SubRole string `json:"SubRole,omitempty"`
+	Result  string `json:"Result"`
+	Error   string `json:"Error,omitempty"`
+}
+
+// The results of a NodeMap import, with the number of rows with each
+// result.
+type NodeMapImportResult struct {
+	DryRun  bool                `json:"DryRun"`
+	Summary map[string]int      `json:"Summary"`
+	Rows    []*NodeMapImportRow `json:"Rows"`
+}
+
+// Types of NIDPolicy
+const (
+	NIDPolicySequential = "Sequential" // Lowest free NID from Start to End
+	NIDPolicyGeometry   = "Geometry"   // Derived from the xname, plus Start
+	NIDPolicyReserved   = "Reserved"   // Start to End are never assigned
+)
+
+// A rule for assigning NIDs to nodes that get none from SLS or the
+// NodeMaps.  The policy applies to nodes at or below Scope, e.g. a cabinet,
+// or all nodes if Scope is empty.  Sequential policies assign the lowest
+// free NID from Start to End, Geometry policies derive the NID from the
+// node's xname, offset by Start and no higher than End if End is set, and
+// Reserved ranges are never assigned by the others.  Policies are tried
+// in order of Priority, lowest first.
+type NIDPolicy struct {
+	Name     string `json:"Name"`
+	Type     string `json:"Type"`
+	Scope    string `json:"Scope,omitempty"`
+	Priority int    `json:"Priority"`
+	Start    int64  `json:"Start"`
+	End      int64  `json:"End,omitempty"`
+}
+
+type NIDPolicyArray struct {
+	Policies []*NIDPolicy `json:"Policies"`
+}
+
+// Check the policy's values, other than Name, are valid and normalize
+// Type and Scope.
+func (p *NIDPolicy) Verify() error {
+	switch strings.ToLower(p.Type) {
+	case strings.ToLower(NIDPolicySequential):
+		p.Type = NIDPolicySequential
+	case strings.ToLower(NIDPolicyGeometry):
+		p.Type = NIDPolicyGeometry
+	case strings.ToLower(NIDPolicyReserved):
+		p.Type = NIDPolicyReserved
+	default:
+		return fmt.Errorf("Type must be %s, %s or %s", NIDPolicySequential,
+			NIDPolicyGeometry, NIDPolicyReserved)
+	}
+	if p.Scope != "" {
+		scope := xnametypes.VerifyNormalizeCompID(p.Scope)
+		if scope == "" {
+			return fmt.Errorf("Scope '%s' is not a valid xname", p.Scope)
+		}
+		p.Scope = scope
+	}
+	if p.Type == NIDPolicyGeometry {
+		if p.Start < 0 {
+			return fmt.Errorf("Start must be 0 or more")
+		}
+		if p.End != 0 && p.End < p.Start {
+			return fmt.Errorf("End must be 0 or at least Start")
+		}
+		return nil
+	}
+	if p.Start < 1 {
+		return fmt.Errorf("Start must be 1 or more")
+	}
+	if p.End < p.Start {
+		return fmt.Errorf("End must be at least Start")
+	}
+	return nil
+}
+
+// A NID assigned to a node by a NIDPolicy.  Assigned is in RFC3339 format.
+type NIDAssignment struct {
+	ID       string `json:"ID"`
+	NID      int64  `json:"NID"`
+	Policy   string `json:"Policy"`
+	Assigned string `json:"Assigned,omitempty"`
+}
+
+type NIDAssignmentArray struct {
+	Assignments []*NIDAssignment `json:"Assignments"`
+}
+
+// Where the NID of a node comes from
+const (
+	NIDSourceComponent  = "Component"  // It already has one
+	NIDSourceSLS        = "SLS"        // SLS has one for it
+	NIDSourceNodeMap    = "NodeMap"    // From its NodeMap
+	NIDSourceAssignment = "Assignment" // Previously assigned by a policy
+	NIDSourcePolicy     = "Policy"     // Newly assigned by a policy
+	NIDSourceDefault    = "Default"    // Derived from the xname
+)
+
+// The NID a node has or would get, where it comes from, and why any
+// policies that applied could not assign one.
+type NIDPreview struct {
+	ID        string   `json:"ID"`
+	NID       int64    `json:"NID"`
+	Source    string   `json:"Source"`
+	Policy    string   `json:"Policy,omitempty"`
+	Conflicts []string `json:"Conflicts,omitempty"`
+}
+
+// Input for previewing NIDs, the node xnames.
+type NIDPreviewIn struct {
+	IDs []string `json:"IDs"`
+}
+
+type NIDPreviewArray struct {
+	NIDs []*NIDPreview `json:"NIDs"`
+}
@@ -55,3 +55,34 @@ func NewPowerMap(id string, poweredBy []string) (*PowerMap, error) {
 	}
 	return m, nil
 }
+
+// An edge of the power graph: ID is powered by PoweredBy.  Implied edges
+// aren't in the PowerMaps but follow from the xnames, e.g. a PDU outlet is
+// powered by its PDU.
+type PowerMapEdge struct {
+	ID        string `json:"id"`
+	PoweredBy string `json:"poweredBy"`
+	Implied   bool   `json:"implied,omitempty"`
+}
+
+// The upstream power path of a component.  Supplies has everything that
+// powers it, directly or not, nearest first.  Each of Paths runs from the
+// component to a supply that nothing powers, or to the last supply before
+// a cycle.  Cycles are listed with the first xname repeated at the end.
+type PowerPath struct {
+	ID        string         `json:"id"`
+	Supplies  []string       `json:"supplies"`
+	Paths     [][]string     `json:"paths"`
+	Edges     []PowerMapEdge `json:"edges"`
+	Cycles    [][]string     `json:"cycles,omitempty"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+// What turning a component off affects.  LosesPower has the components
+// with no supply left, and Degraded those that still have another.
+type PowerImpact struct {
+	ID         string     `json:"id"`
+	LosesPower []string   `json:"losesPower"`
+	Degraded   []string   `json:"degraded"`
+	Cycles     [][]string `json:"cycles,omitempty"`
+}
@@ -0,0 +1,165 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package sm
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// Counts for one kind of FRU, i.e. one part number of one model, across the
+// system.  Used to decide how many spares to stock.
+type SparePart struct {
+	Type         string `json:"Type"`
+	Manufacturer string `json:"Manufacturer"`
+	Model        string `json:"Model"`
+	PartNumber   string `json:"PartNumber"`
+
+	Installed int `json:"Installed"` // FRUs currently at a location
+	Known     int `json:"Known"`     // FRUs in the inventory, installed or not
+	Removed   int `json:"Removed"`   // 'Removed' history events
+	Replaced  int `json:"Replaced"`  // times a different FRU took its place
+}
+
+type SparePartsReport struct {
+	Parts []SparePart `json:"Parts"`
+}
+
+// Manufacturer, model and part number of a FRU, whatever its type.
+func (hf *HWInvByFRU) PartInfo() (manufacturer, model, partNumber string) {
+	fruInfoJSON, err := hf.EncodeFRUInfo()
+	if err != nil {
+		return "", "", ""
+	}
+	var info struct {
+		Manufacturer string `json:"Manufacturer"`
+		Model        string `json:"Model"`
+		PartNumber   string `json:"PartNumber"`
+	}
+	json.Unmarshal(fruInfoJSON, &info)
+	return info.Manufacturer, info.Model, info.PartNumber
+}
+
+// Build a spare parts report from the locations in hwlocs (for installed
+// counts), the FRUs in hwfrus (all FRUs of interest, installed or not) and
+// the history in hwhists (for removal and replacement counts).  History of
+// FRUs that are not in hwfrus is only used to spot replacements of ones
+// that are.  FRUs with neither a model nor a part number are left out, as
+// there is nothing to order them by.  Parts are sorted by type, manufacturer,
+// model and part number.
+func NewSparePartsReport(hwlocs []*HWInvByLoc, hwfrus []*HWInvByFRU, hwhists []*HWInvHist) *SparePartsReport {
+	parts := make(map[SparePart]*SparePart)
+	partOf := make(map[string]*SparePart) // by FRU ID
+
+	addFRU := func(hf *HWInvByFRU) *SparePart {
+		if p, ok := partOf[hf.FRUID]; ok {
+			return p
+		}
+		manufacturer, model, partNumber := hf.PartInfo()
+		if model == "" && partNumber == "" {
+			return nil
+		}
+		key := SparePart{
+			Type:         hf.Type,
+			Manufacturer: manufacturer,
+			Model:        model,
+			PartNumber:   partNumber,
+		}
+		p, ok := parts[key]
+		if !ok {
+			p = &key
+			parts[key] = p
+		}
+		p.Known++
+		partOf[hf.FRUID] = p
+		return p
+	}
+	for _, hf := range hwfrus {
+		if hf != nil {
+			addFRU(hf)
+		}
+	}
+	for _, hwloc := range hwlocs {
+		if hwloc == nil || hwloc.PopulatedFRU == nil {
+			continue
+		}
+		if hwloc.PopulatedFRU.Type == "" {
+			hwloc.PopulatedFRU.Type = hwloc.Type
+		}
+		if p := addFRU(hwloc.PopulatedFRU); p != nil {
+			p.Installed++
+		}
+	}
+
+	// Walk the history of each location oldest first.  A FRU is replaced
+	// when the next FRU seen at its location is a different one.
+	hists := make([]*HWInvHist, 0, len(hwhists))
+	for _, hh := range hwhists {
+		if hh != nil {
+			hists = append(hists, hh)
+		}
+	}
+	sort.SliceStable(hists, func(i, j int) bool {
+		if hists[i].ID != hists[j].ID {
+			return hists[i].ID < hists[j].ID
+		}
+		return hists[i].Timestamp < hists[j].Timestamp
+	})
+	lastFRU := ""
+	for i, hh := range hists {
+		if i == 0 || hh.ID != hists[i-1].ID {
+			lastFRU = ""
+		}
+		if hh.EventType == HWInvHistEventTypeRemoved {
+			if p, ok := partOf[hh.FruId]; ok {
+				p.Removed++
+			}
+		}
+		if lastFRU != "" && hh.FruId != lastFRU {
+			if p, ok := partOf[lastFRU]; ok {
+				p.Replaced++
+			}
+		}
+		lastFRU = hh.FruId
+	}
+
+	report := new(SparePartsReport)
+	report.Parts = make([]SparePart, 0, len(parts))
+	for _, p := range parts {
+		report.Parts = append(report.Parts, *p)
+	}
+	sort.Slice(report.Parts, func(i, j int) bool {
+		a, b := &report.Parts[i], &report.Parts[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Manufacturer != b.Manufacturer {
+			return a.Manufacturer < b.Manufacturer
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.PartNumber < b.PartNumber
+	})
+	return report
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package sm_test
+
+import (
+	"reflect"
+	"testing"
+
+	rf "github.com/OpenCHAMI/smd/v2/pkg/redfish"
+	"github.com/OpenCHAMI/smd/v2/pkg/sm"
+)
+
+func testProcFRU(fruID, model, partNumber string) *sm.HWInvByFRU {
+	return &sm.HWInvByFRU{
+		FRUID:                fruID,
+		Type:                 "Processor",
+		HWInventoryByFRUType: sm.HWInvByFRUProcessor,
+		HMSProcessorFRUInfo: &rf.ProcessorFRUInfoRF{
+			Manufacturer: "Intel",
+			Model:        model,
+			PartNumber:   partNumber,
+		},
+	}
+}
+
+func TestNewSparePartsReport(t *testing.T) {
+	p1 := testProcFRU("Processor.Intel.PN1.SN1", "Xeon 8360Y", "PN1")
+	p2 := testProcFRU("Processor.Intel.PN1.SN2", "Xeon 8360Y", "PN1")
+	p3 := testProcFRU("Processor.Intel.PN1.SN3", "Xeon 8360Y", "PN1")
+	p4 := testProcFRU("Processor.Intel.PN2.SN4", "Xeon 6338", "PN2")
+	noPart := testProcFRU("Processor.Intel.SN5", "", "")
+	mem := &sm.HWInvByFRU{
+		FRUID:                "Memory.Samsung.M1.SN6",
+		Type:                 "Memory",
+		HWInventoryByFRUType: sm.HWInvByFRUMemory,
+		HMSMemoryFRUInfo: &rf.MemoryFRUInfoRF{
+			Manufacturer: "Samsung",
+			PartNumber:   "M1",
+		},
+	}
+
+	hwlocs := []*sm.HWInvByLoc{
+		{ID: "x0c0s0b0n0p0", Type: "Processor", PopulatedFRU: p2},
+		{ID: "x0c0s0b0n0p1", Type: "Processor", PopulatedFRU: p3},
+		{ID: "x0c0s1b0n0p0", Type: "Processor", PopulatedFRU: p4},
+		{ID: "x0c0s1b0n0p1", Type: "Processor", PopulatedFRU: noPart},
+		{ID: "x0c0s1b0n0d0", Type: "Memory", PopulatedFRU: mem},
+		{ID: "x0c0s1b0n0d1", Type: "Memory"},
+	}
+	hwfrus := []*sm.HWInvByFRU{p1, p2, p3, p4, noPart, mem}
+	hwhists := []*sm.HWInvHist{
+		// p1 failed and was replaced by p2
+		{ID: "x0c0s0b0n0p0", FruId: p1.FRUID, Timestamp: "2026-01-01T00:00:00Z", EventType: sm.HWInvHistEventTypeAdded},
+		{ID: "x0c0s0b0n0p0", FruId: p2.FRUID, Timestamp: "2026-03-01T00:00:00Z", EventType: sm.HWInvHistEventTypeAdded},
+		{ID: "x0c0s0b0n0p0", FruId: p1.FRUID, Timestamp: "2026-02-28T00:00:00Z", EventType: sm.HWInvHistEventTypeRemoved},
+		// p3 was reseated
+		{ID: "x0c0s0b0n0p1", FruId: p3.FRUID, Timestamp: "2026-01-01T00:00:00Z", EventType: sm.HWInvHistEventTypeAdded},
+		{ID: "x0c0s0b0n0p1", FruId: p3.FRUID, Timestamp: "2026-02-01T00:00:00Z", EventType: sm.HWInvHistEventTypeRemoved},
+		{ID: "x0c0s0b0n0p1", FruId: p3.FRUID, Timestamp: "2026-02-02T00:00:00Z", EventType: sm.HWInvHistEventTypeAdded},
+		// An unknown FRU was swapped for p4
+		{ID: "x0c0s1b0n0p0", FruId: "Processor.Other", Timestamp: "2026-01-01T00:00:00Z", EventType: sm.HWInvHistEventTypeDetected},
+		{ID: "x0c0s1b0n0p0", FruId: p4.FRUID, Timestamp: "2026-01-05T00:00:00Z", EventType: sm.HWInvHistEventTypeDetected},
+	}
+
+	expected := []sm.SparePart{{
+		Type:         "Memory",
+		Manufacturer: "Samsung",
+		PartNumber:   "M1",
+		Installed:    1,
+		Known:        1,
+	}, {
+		Type:         "Processor",
+		Manufacturer: "Intel",
+		Model:        "Xeon 6338",
+		PartNumber:   "PN2",
+		Installed:    1,
+		Known:        1,
+	}, {
+		Type:         "Processor",
+		Manufacturer: "Intel",
+		Model:        "Xeon 8360Y",
+		PartNumber:   "PN1",
+		Installed:    2,
+		Known:        3,
+		Removed:      2,
+		Replaced:     1,
+	}}
+	report := sm.NewSparePartsReport(hwlocs, hwfrus, hwhists)
+	if !reflect.DeepEqual(report.Parts, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, report.Parts)
+	}
+
+	// Empty input
+	report = sm.NewSparePartsReport(nil, nil, nil)
+	if report.Parts == nil || len(report.Parts) != 0 {
+		t.Errorf("Expected empty parts, got %+v", report.Parts)
+	}
+}
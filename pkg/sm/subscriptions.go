@@ -23,6 +23,7 @@
 package sm
 
 import (
+	"encoding/json"
 	"strings"
 )
 
@@ -48,6 +49,10 @@ type SCNPostSubscription struct {
 	SubRoles       []string `json:"SubRoles,omitempty"`
 	SoftwareStatus []string `json:"SoftwareStatus,omitempty"`
 	States         []string `json:"States,omitempty"`
+	Reservations   []string `json:"Reservations,omitempty"`
+	Xnames         []string `json:"Xnames,omitempty"`
+	Groups         []string `json:"Groups,omitempty"`
+	Partitions     []string `json:"Partitions,omitempty"`
 	Url            string   `json:"Url"`
 }
 
@@ -59,6 +64,10 @@ type SCNSubscription struct {
 	SubRoles       []string `json:"SubRoles,omitempty"`
 	SoftwareStatus []string `json:"SoftwareStatus,omitempty"`
 	States         []string `json:"States,omitempty"`
+	Reservations   []string `json:"Reservations,omitempty"`
+	Xnames         []string `json:"Xnames,omitempty"`
+	Groups         []string `json:"Groups,omitempty"`
+	Partitions     []string `json:"Partitions,omitempty"`
 	Url            string   `json:"Url"`
 }
 
@@ -69,6 +78,10 @@ type SCNPatchSubscription struct {
 	SubRoles       []string `json:"SubRoles,omitempty"`
 	SoftwareStatus []string `json:"SoftwareStatus,omitempty"`
 	States         []string `json:"States,omitempty"`
+	Reservations   []string `json:"Reservations,omitempty"`
+	Xnames         []string `json:"Xnames,omitempty"`
+	Groups         []string `json:"Groups,omitempty"`
+	Partitions     []string `json:"Partitions,omitempty"`
 }
 
 type SCNSubscriptionArray struct {
@@ -83,6 +96,25 @@ type SCNPayload struct {
 	SubRole        string   `json:"SubRole,omitempty"`
 	SoftwareStatus string   `json:"SoftwareStatus,omitempty"`
 	State          string   `json:"State,omitempty"`
+	Reservation    string   `json:"Reservation,omitempty"`
+}
+
+// Reservation SCN triggers, for service reservations that are about to
+// expire or have expired.
+const (
+	SCNReservationExpiring = "Expiring"
+	SCNReservationExpired  = "Expired"
+)
+
+var scnReservationMap = map[string]string{
+	"expiring": SCNReservationExpiring,
+	"expired":  SCNReservationExpired,
+}
+
+// Returns the normalized reservation trigger, or the empty string if it is
+// not valid.
+func VerifyNormalizeSCNReservation(r string) string {
+	return scnReservationMap[strings.ToLower(r)]
 }
 
 func GetPatchOp(op string) SMPatchOp {
@@ -92,3 +124,75 @@ func GetPatchOp(op string) SMPatchOp {
 	}
 	return opInt
 }
+
+// Add the Xnames, Groups or Partitions scope values that are not already in
+// the list, for the add patch op.  These are normalized to lower case so they
+// are compared as-is.
+func AddSCNScope(list, add []string) []string {
+	for _, a := range add {
+		match := false
+		for _, v := range list {
+			if v == a {
+				match = true
+				break
+			}
+		}
+		if !match {
+			list = append(list, a)
+		}
+	}
+	return list
+}
+
+// Remove the given scope values from the list, for the remove patch op.
+func RemoveSCNScope(list, remove []string) []string {
+	for _, r := range remove {
+		for j, v := range list {
+			if v == r {
+				list = append(list[:j], list[j+1:]...)
+				break
+			}
+		}
+	}
+	return list
+}
+
+// Delivery status of the SCNs sent to one subscriber URL.
+type SCNDeliveryStatus struct {
+	Url                string   `json:"Url"`
+	Subscribers        []string `json:"Subscribers,omitempty"`
+	Delivered          uint64   `json:"Delivered"`
+	Failed             uint64   `json:"Failed"`
+	Queued             int      `json:"Queued"`
+	DeadLetters        int      `json:"DeadLetters"`
+	DeadLettersDropped uint64   `json:"DeadLettersDropped"`
+	LastSuccess        string   `json:"LastSuccess,omitempty"`
+	LastFailure        string   `json:"LastFailure,omitempty"`
+	LastError          string   `json:"LastError,omitempty"`
+}
+
+type SCNDeliveryStatusArray struct {
+	Subscribers []SCNDeliveryStatus `json:"Subscribers"`
+}
+
+// An SCN that could not be delivered.
+type SCNDeadLetter struct {
+	Url       string          `json:"Url"`
+	Payload   json.RawMessage `json:"Payload"`
+	Queued    string          `json:"Queued"`
+	Attempts  int             `json:"Attempts"`
+	LastError string          `json:"LastError,omitempty"`
+}
+
+type SCNDeadLetterArray struct {
+	DeadLetters []SCNDeadLetter `json:"DeadLetters"`
+}
+
+// Replay the dead letters for Url, or for all subscribers if it's empty.
+type SCNReplayIn struct {
+	Url string `json:"Url,omitempty"`
+}
+
+type SCNReplayOut struct {
+	Replayed int `json:"Replayed"`
+}
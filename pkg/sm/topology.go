@@ -0,0 +1,52 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package sm
+
+import (
+	"encoding/json"
+)
+
+// A component in a topology tree, with the fields needed to draw it.
+// Components that aren't in HSM but are between the root and ones that
+// are have only ID and Type.  BMCFQDN is the FQDN of the RedfishEndpoint
+// of a BMC, or of a node's BMC.
+type TopologyNode struct {
+	ID       string          `json:"ID"`
+	Type     string          `json:"Type"`
+	State    string          `json:"State,omitempty"`
+	Flag     string          `json:"Flag,omitempty"`
+	Enabled  *bool           `json:"Enabled,omitempty"`
+	Class    string          `json:"Class,omitempty"`
+	Role     string          `json:"Role,omitempty"`
+	SubRole  string          `json:"SubRole,omitempty"`
+	NID      json.Number     `json:"NID,omitempty"`
+	BMCFQDN  string          `json:"BMCFQDN,omitempty"`
+	Children []*TopologyNode `json:"Children,omitempty"`
+}
+
+// The containment tree of components at and below a cabinet, chassis,
+// slot, etc., with the number of nodes in each state.
+type Topology struct {
+	Root       *TopologyNode  `json:"Root"`
+	NodeStates map[string]int `json:"NodeStates"`
+}
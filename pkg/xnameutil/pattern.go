@@ -0,0 +1,171 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package xnameutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+// The most numbers one pair of brackets in a Pattern can stand for.
+const PatternMaxAlternatives = 1000
+
+// An xname with wildcards in place of some of its numbers, for matching
+// rather than expanding.  "*" matches any number and brackets match the
+// numbers in them, e.g. x1000c0s*b0n[0-1] matches both nodes of b0 in
+// every slot of x1000c0.  Wildcards stand for whole numbers, so x100*
+// and x1000c[0-1]0 are not patterns.
+type Pattern struct {
+	parts  []patternPart
+	re     *regexp.Regexp
+	str    string
+	prefix string
+}
+
+// A literal, "*" (any true) or a list of numbers.
+type patternPart struct {
+	literal string
+	any     bool
+	nums    []string
+}
+
+// Matches runs of leading zeros in the numbers of a literal.
+var patternZeros = regexp.MustCompile(`([a-z])0+([0-9])`)
+
+// True if s has wildcards, i.e. should be parsed with ParsePattern rather
+// than as an xname.
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "*[]")
+}
+
+// Parse and normalize a pattern.  Replacing every wildcard with 0 must
+// give a valid xname.
+func ParsePattern(pattern string) (*Pattern, error) {
+	p := new(Pattern)
+	sample := ""
+	rest := strings.ToLower(strings.TrimSpace(pattern))
+	for rest != "" {
+		i := strings.IndexAny(rest, "*[]")
+		if i < 0 {
+			i = len(rest)
+		}
+		if i > 0 {
+			lit := patternZeros.ReplaceAllString(rest[:i], "$1$2")
+			p.parts = append(p.parts, patternPart{literal: lit})
+			sample += lit
+			rest = rest[i:]
+			continue
+		}
+		// A wildcard must follow a letter and be followed by a letter or
+		// the end, so that it stands for a whole number.
+		if sample == "" || !isLetter(sample[len(sample)-1]) {
+			return nil, fmt.Errorf("%w: wildcard must follow a letter",
+				ErrBadPattern)
+		}
+		part := patternPart{}
+		switch rest[0] {
+		case '*':
+			part.any = true
+			rest = rest[1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unmatched '['", ErrBadPattern)
+			}
+			nums, err := expandBrackets(rest[1:end], PatternMaxAlternatives)
+			if err != nil {
+				return nil, err
+			}
+			part.nums = nums
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w: unmatched ']'", ErrBadPattern)
+		}
+		if rest != "" && !isLetter(rest[0]) {
+			return nil, fmt.Errorf("%w: wildcard must be followed by a letter",
+				ErrBadPattern)
+		}
+		p.parts = append(p.parts, part)
+		sample += "0"
+	}
+	if sample == "" {
+		return nil, fmt.Errorf("%w: empty", ErrBadPattern)
+	}
+	if xnametypes.VerifyNormalizeCompID(sample) == "" {
+		return nil, fmt.Errorf("%w: '%s'", ErrBadPattern, pattern)
+	}
+
+	// Patterns start with a letter, so the first part is a literal.
+	p.prefix = p.parts[0].literal
+	var str, re strings.Builder
+	for _, part := range p.parts {
+		switch {
+		case part.any:
+			str.WriteString("*")
+			re.WriteString("[0-9]+")
+		case part.nums != nil:
+			str.WriteString("[" + strings.Join(part.nums, ",") + "]")
+			re.WriteString("(" + strings.Join(part.nums, "|") + ")")
+		default:
+			str.WriteString(part.literal)
+			re.WriteString(part.literal)
+		}
+	}
+	p.str = str.String()
+	p.re = regexp.MustCompile("^" + re.String() + "$")
+	return p, nil
+}
+
+// The normalized pattern, with brackets listing every number.
+func (p *Pattern) String() string {
+	return p.str
+}
+
+// The literal text before the first wildcard, so every match starts with
+// it, e.g. x1000c0s for x1000c0s*b0n[0-1].
+func (p *Pattern) Prefix() string {
+	return p.prefix
+}
+
+// The pattern as a POSIX extended regular expression without anchors,
+// which both Go and Postgres accept.  Matches only normalized xnames.
+func (p *Pattern) Regexp() string {
+	s := p.re.String()
+	return s[1 : len(s)-1]
+}
+
+// True if xname, once normalized, matches the pattern.
+func (p *Pattern) Match(xname string) bool {
+	norm, err := Normalize(xname)
+	if err != nil {
+		return false
+	}
+	return p.re.MatchString(norm)
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
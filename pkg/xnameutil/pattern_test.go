@@ -0,0 +1,127 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package xnameutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		str     string
+		prefix  string
+		regexp  string
+		err     error
+	}{{
+		pattern: "X1000C0S*B0N[0-1]",
+		str:     "x1000c0s*b0n[0,1]",
+		prefix:  "x1000c0s",
+		regexp:  "x1000c0s[0-9]+b0n(0|1)",
+	}, {
+		pattern: "x01000c[3,1-2]",
+		str:     "x1000c[3,1,2]",
+		prefix:  "x1000c",
+		regexp:  "x1000c(3|1|2)",
+	}, {
+		pattern: "x*c0",
+		str:     "x*c0",
+		prefix:  "x",
+		regexp:  "x[0-9]+c0",
+	}, {
+		pattern: "x1000c0s0",
+		str:     "x1000c0s0",
+		prefix:  "x1000c0s0",
+		regexp:  "x1000c0s0",
+	}, {
+		// Partial numbers
+		pattern: "x100*",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000c[0-1]0",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "*c0",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000q*",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000c[0-1",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000c0]",
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x[0-5000]",
+		err:     ErrTooMany,
+	}, {
+		pattern: "",
+		err:     ErrBadPattern,
+	}}
+	for i, test := range tests {
+		p, err := ParsePattern(test.pattern)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("Test %d: expected %v, got %v", i, test.err, err)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+			continue
+		}
+		if p.String() != test.str || p.Prefix() != test.prefix ||
+			p.Regexp() != test.regexp {
+			t.Errorf("Test %d: expected %s %s %s, got %s %s %s", i,
+				test.str, test.prefix, test.regexp,
+				p.String(), p.Prefix(), p.Regexp())
+		}
+	}
+}
+
+func TestPatternMatch(t *testing.T) {
+	p, err := ParsePattern("x1000c0s*b0n[0-1]")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	matches := map[string]bool{
+		"x1000c0s0b0n0":  true,
+		"X1000C0S17B0N1": true,
+		"x1000c0s07b0n1": true,
+		"x1000c0s7b0n2":  false,
+		"x1000c0s7b1n0":  false,
+		"x1000c0sb0n0":   false,
+		"x1000c0s7b0n0x": false,
+		"x1000c1s7b0n0":  false,
+	}
+	for xname, expected := range matches {
+		if p.Match(xname) != expected {
+			t.Errorf("Expected Match(%s) to be %v", xname, expected)
+		}
+	}
+	if !IsPattern("x1000c*") || !IsPattern("x1000c[0-1]") ||
+		IsPattern("x1000c0") {
+		t.Errorf("IsPattern is wrong")
+	}
+}
@@ -0,0 +1,275 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+// Package xnameutil derives xnames from other xnames the way HSM does:
+// parents, children and neighbors, and expanding patterns like
+// x1000c[0-7]s[0-7]b[0-1]n[0-1] into the xnames they stand for.  It uses
+// the geometry rules of hms-xname, so tools using it agree with HSM about
+// which xnames are valid.
+package xnameutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+var (
+	ErrInvalid    = errors.New("invalid xname")
+	ErrBadPattern = errors.New("invalid xname pattern")
+	ErrTooMany    = errors.New("xname pattern expands to too many xnames")
+)
+
+// What HSM makes of an xname.  Parent is s0 for cabinets.  ChildTypes are
+// the types of components directly inside it, and Neighbors are the valid
+// xnames of the same type and parent numbered one less and one more.
+type Info struct {
+	ID         string   `json:"ID"`
+	Valid      bool     `json:"Valid"`
+	Type       string   `json:"Type,omitempty"`
+	Parent     string   `json:"Parent,omitempty"`
+	Ancestors  []string `json:"Ancestors,omitempty"`
+	ChildTypes []string `json:"ChildTypes,omitempty"`
+	Neighbors  []string `json:"Neighbors,omitempty"`
+	Error      string   `json:"Error,omitempty"`
+}
+
+// Normalize and validate xname, returning the normalized form.
+func Normalize(xname string) (string, error) {
+	norm := xnametypes.VerifyNormalizeCompID(xname)
+	if norm == "" {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalid, xname)
+	}
+	return norm, nil
+}
+
+// Get the xname that directly contains xname, e.g. x1000c0s0b0 for
+// x1000c0s0b0n0, or s0 for a cabinet.
+func Parent(xname string) (string, error) {
+	norm, err := Normalize(xname)
+	if err != nil {
+		return "", err
+	}
+	if xnametypes.GetHMSType(norm) == xnametypes.System {
+		return "", nil
+	}
+	return xnametypes.GetHMSCompParent(norm), nil
+}
+
+// Get the xnames containing xname, nearest first, not including s0.
+func Ancestors(xname string) ([]string, error) {
+	norm, err := Normalize(xname)
+	if err != nil {
+		return nil, err
+	}
+	ancestors := []string{}
+	for p := xnametypes.GetHMSCompParent(norm); xnametypes.IsHMSCompIDValid(p) &&
+		xnametypes.GetHMSType(p) != xnametypes.System; p = xnametypes.GetHMSCompParent(p) {
+		ancestors = append(ancestors, p)
+	}
+	return ancestors, nil
+}
+
+// If xname is ancestor or inside it.  Both must already be normalized.
+// Everything is inside s0.
+func Contains(ancestor, xname string) bool {
+	if ancestor == "s0" {
+		return true
+	}
+	if !strings.HasPrefix(xname, ancestor) {
+		return false
+	}
+	// x1000c1 is not inside x1000c, nor x10001 inside x1000.
+	rest := xname[len(ancestor):]
+	return rest == "" || rest[0] < '0' || rest[0] > '9'
+}
+
+// Get the types of components directly inside a component of type t,
+// sorted by name.
+func ChildTypes(t xnametypes.HMSType) []xnametypes.HMSType {
+	types := []xnametypes.HMSType{}
+	if t == xnametypes.HMSTypeInvalid {
+		return types
+	}
+	for _, entry := range xnametypes.GetHMSCompRecognitionTable() {
+		if entry.ParentType == t && entry.Type != t {
+			types = append(types, entry.Type)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// Get the valid xnames of the same type and parent as xname whose last
+// number is one less and one more, e.g. x1000c0s2 and x1000c0s4 for
+// x1000c0s3.
+func Neighbors(xname string) ([]string, error) {
+	norm, err := Normalize(xname)
+	if err != nil {
+		return nil, err
+	}
+	htype := xnametypes.GetHMSType(norm)
+	i := len(norm)
+	for i > 0 && norm[i-1] >= '0' && norm[i-1] <= '9' {
+		i--
+	}
+	num, err := strconv.Atoi(norm[i:])
+	if err != nil {
+		return []string{}, nil
+	}
+	neighbors := []string{}
+	for _, n := range []int{num - 1, num + 1} {
+		if n < 0 {
+			continue
+		}
+		id := norm[:i] + strconv.Itoa(n)
+		if xnametypes.GetHMSType(id) == htype {
+			neighbors = append(neighbors, id)
+		}
+	}
+	return neighbors, nil
+}
+
+// Get everything HSM knows about xname from the xname alone.  Invalid
+// xnames get Valid false and the Error.
+func Describe(xname string) *Info {
+	info := &Info{ID: xname}
+	norm, err := Normalize(xname)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.ID = norm
+	info.Valid = true
+	htype := xnametypes.GetHMSType(norm)
+	info.Type = htype.String()
+	info.Parent, _ = Parent(norm)
+	info.Ancestors, _ = Ancestors(norm)
+	for _, t := range ChildTypes(htype) {
+		info.ChildTypes = append(info.ChildTypes, t.String())
+	}
+	info.Neighbors, _ = Neighbors(norm)
+	return info
+}
+
+// Expand a pattern into the xnames it stands for, in order.  Numbers in
+// brackets are alternatives: ranges like [0-7], lists like [0,2,4], or
+// both, e.g. x1000c[0-1]s[0,3-4]b0n[0-1].  Every xname must be valid, and
+// there can be at most limit of them.
+func Expand(pattern string, limit int) ([]string, error) {
+	// Split into literal parts and alternatives: each part is either one
+	// literal string or the numbers it can be.
+	parts := [][]string{}
+	total := 1
+	for rest := strings.TrimSpace(pattern); rest != ""; {
+		open := strings.IndexByte(rest, '[')
+		if open < 0 {
+			open = len(rest)
+		}
+		if strings.IndexByte(rest[:open], ']') >= 0 {
+			return nil, fmt.Errorf("%w: unmatched ']'", ErrBadPattern)
+		}
+		if open == len(rest) {
+			parts = append(parts, []string{rest})
+			break
+		}
+		if open > 0 {
+			parts = append(parts, []string{rest[:open]})
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unmatched '['", ErrBadPattern)
+		}
+		alts, err := expandBrackets(rest[open+1:end], limit)
+		if err != nil {
+			return nil, err
+		}
+		total *= len(alts)
+		if total > limit {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooMany, limit)
+		}
+		parts = append(parts, alts)
+		rest = rest[end+1:]
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrBadPattern)
+	}
+
+	xnames := make([]string, 0, total)
+	seen := map[string]bool{}
+	idx := make([]int, len(parts))
+	for {
+		var sb strings.Builder
+		for i, p := range parts {
+			sb.WriteString(p[idx[i]])
+		}
+		norm, err := Normalize(sb.String())
+		if err != nil {
+			return nil, err
+		}
+		if !seen[norm] {
+			seen[norm] = true
+			xnames = append(xnames, norm)
+		}
+		// Next combination, the last part changing fastest.
+		i := len(parts) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(parts[i]) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			break
+		}
+	}
+	return xnames, nil
+}
+
+// Expand the inside of brackets, e.g. "0-3,7", into at most limit numbers.
+func expandBrackets(s string, limit int) ([]string, error) {
+	nums := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		lo, hi := item, item
+		if dash := strings.IndexByte(item, '-'); dash >= 0 {
+			lo, hi = item[:dash], item[dash+1:]
+		}
+		from, err1 := strconv.Atoi(lo)
+		to, err2 := strconv.Atoi(hi)
+		if err1 != nil || err2 != nil || from < 0 || to < from {
+			return nil, fmt.Errorf("%w: bad range '%s'", ErrBadPattern, item)
+		}
+		if to-from >= limit-len(nums) {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooMany, limit)
+		}
+		for n := from; n <= to; n++ {
+			nums = append(nums, strconv.Itoa(n))
+		}
+	}
+	return nums, nil
+}
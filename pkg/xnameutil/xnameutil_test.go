@@ -0,0 +1,205 @@
+// MIT License
+//
+// (C) Copyright [2026] Hewlett Packard Enterprise Development LP
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL
+// THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+
+package xnameutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Cray-HPE/hms-xname/xnametypes"
+)
+
+func TestParentAncestors(t *testing.T) {
+	tests := []struct {
+		xname     string
+		parent    string
+		ancestors []string
+		err       bool
+	}{
+		{"X1000C0S0B0N0", "x1000c0s0b0", []string{"x1000c0s0b0", "x1000c0s0", "x1000c0", "x1000"}, false},
+		{"x1000", "s0", []string{}, false},
+		{"x3000m0p0v1", "x3000m0p0", []string{"x3000m0p0", "x3000m0", "x3000"}, false},
+		{"s0", "", []string{}, false},
+		{"foo", "", nil, true},
+	}
+	for i, test := range tests {
+		parent, err := Parent(test.xname)
+		ancestors, err2 := Ancestors(test.xname)
+		if test.err {
+			if !errors.Is(err, ErrInvalid) || !errors.Is(err2, ErrInvalid) {
+				t.Errorf("Test %d: expected ErrInvalid, got %v, %v", i, err, err2)
+			}
+			continue
+		}
+		if err != nil || err2 != nil {
+			t.Errorf("Test %d: unexpected error: %v, %v", i, err, err2)
+		} else if parent != test.parent {
+			t.Errorf("Test %d: expected parent %s, got %s", i, test.parent, parent)
+		} else if !reflect.DeepEqual(test.ancestors, ancestors) {
+			t.Errorf("Test %d: expected ancestors %v, got %v", i,
+				test.ancestors, ancestors)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		ancestor, xname string
+		expected        bool
+	}{
+		{"x1000c0", "x1000c0s0b0n0", true},
+		{"x1000c0", "x1000c0", true},
+		{"x1000c1", "x1000c10s0", false},
+		{"x100", "x1000c0", false},
+		{"x1000c0s0b0n0", "x1000c0", false},
+		{"s0", "x1000c0", true},
+	}
+	for i, test := range tests {
+		if got := Contains(test.ancestor, test.xname); got != test.expected {
+			t.Errorf("Test %d: Contains(%s, %s) expected %v, got %v", i,
+				test.ancestor, test.xname, test.expected, got)
+		}
+	}
+}
+
+func TestChildTypes(t *testing.T) {
+	types := ChildTypes(xnametypes.NodeBMC)
+	expected := []xnametypes.HMSType{xnametypes.Node, xnametypes.NodeBMCNic}
+	for _, e := range expected {
+		found := false
+		for _, t := range types {
+			if t == e {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected %s in NodeBMC child types %v", e, types)
+		}
+	}
+	if len(ChildTypes(xnametypes.HMSTypeInvalid)) != 0 {
+		t.Errorf("Expected no child types for an invalid type")
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		xname    string
+		expected []string
+	}{
+		{"x1000c0s3", []string{"x1000c0s2", "x1000c0s4"}},
+		{"x1000c0s0", []string{"x1000c0s1"}},
+		// Chassis only go to 7
+		{"x1000c7", []string{"x1000c6"}},
+		{"x1000c0s0b0n1", []string{"x1000c0s0b0n0", "x1000c0s0b0n2"}},
+	}
+	for i, test := range tests {
+		got, err := Neighbors(test.xname)
+		if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, got) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, got)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	info := Describe("x1000c0s0b0")
+	if !info.Valid || info.Type != "NodeBMC" || info.Parent != "x1000c0s0" ||
+		len(info.ChildTypes) == 0 || len(info.Neighbors) != 1 {
+		t.Errorf("Unexpected info %+v", info)
+	}
+	info = Describe("x1000q0")
+	if info.Valid || info.Error == "" || info.ID != "x1000q0" {
+		t.Errorf("Expected an invalid xname, got %+v", info)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		limit    int
+		expected []string
+		err      error
+	}{{
+		pattern: "x1000c[0-1]s0b0n[0-1]",
+		limit:   10,
+		expected: []string{"x1000c0s0b0n0", "x1000c0s0b0n1",
+			"x1000c1s0b0n0", "x1000c1s0b0n1"},
+	}, {
+		pattern:  "x1000c0s[0,3-4,3]",
+		limit:    10,
+		expected: []string{"x1000c0s0", "x1000c0s3", "x1000c0s4"},
+	}, {
+		pattern:  "X1000C0",
+		limit:    1,
+		expected: []string{"x1000c0"},
+	}, {
+		pattern: "x1000c[0-7]s[0-7]b[0-1]n[0-1]",
+		limit:   255,
+		err:     ErrTooMany,
+	}, {
+		pattern: "x1000c[0-999999999]",
+		limit:   100,
+		err:     ErrTooMany,
+	}, {
+		// Chassis only go to 7
+		pattern: "x1000c[0-8]",
+		limit:   10,
+		err:     ErrInvalid,
+	}, {
+		pattern: "x1000c[0-1",
+		limit:   10,
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000c0]",
+		limit:   10,
+		err:     ErrBadPattern,
+	}, {
+		pattern: "x1000c[1-0]",
+		limit:   10,
+		err:     ErrBadPattern,
+	}, {
+		pattern: "",
+		limit:   10,
+		err:     ErrBadPattern,
+	}}
+	for i, test := range tests {
+		got, err := Expand(test.pattern, test.limit)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("Test %d: expected %v, got %v", i, test.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error: %s", i, err)
+		} else if !reflect.DeepEqual(test.expected, got) {
+			t.Errorf("Test %d: expected %v, got %v", i, test.expected, got)
+		}
+	}
+
+	// The example from the docs
+	got, err := Expand("x1000c[0-7]s[0-7]b[0-1]n[0-1]", 256)
+	if err != nil || len(got) != 256 || got[255] != "x1000c7s7b1n1" {
+		t.Errorf("Expected 256 xnames, got %d: %v", len(got), err)
+	}
+}